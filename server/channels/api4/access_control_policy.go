@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitAccessControlPolicy() {
+	api.BaseRoutes.Channel.Handle("/access_control_policy", api.APISessionRequired(getAccessControlPolicy)).Methods(http.MethodGet)
+	api.BaseRoutes.Channel.Handle("/access_control_policy", api.APISessionRequired(saveAccessControlPolicy)).Methods(http.MethodPut)
+	api.BaseRoutes.Channel.Handle("/access_control_policy", api.APISessionRequired(deleteAccessControlPolicy)).Methods(http.MethodDelete)
+	api.BaseRoutes.Channel.Handle("/access_control_policy/simulate", api.APISessionRequired(simulateAccessControlPolicy)).Methods(http.MethodPost)
+}
+
+// checkAccessControlPolicyPermission checks that attribute-based access control is enabled
+// and that the session has the given system console permission over channels.
+func checkAccessControlPolicyPermission(c *Context, permission *model.Permission) {
+	if !*c.App.Config().AccessControlSettings.EnableAttributeBasedAccessControl {
+		c.Err = model.NewAppError("checkAccessControlPolicyPermission", "api.access_control_policy.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), permission) {
+		c.SetPermissionError(permission)
+	}
+}
+
+func getAccessControlPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	if checkAccessControlPolicyPermission(c, model.PermissionSysconsoleReadUserManagementChannels); c.Err != nil {
+		return
+	}
+
+	policy, appErr := c.App.GetAccessControlPolicy(c.Params.ChannelId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(policy); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func saveAccessControlPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	var policy *model.AccessControlPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil || policy == nil {
+		c.SetInvalidParamWithErr("access_control_policy", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("saveAccessControlPolicy", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "channel_id", c.Params.ChannelId)
+	audit.AddEventParameter(auditRec, "expression", policy.Expression)
+
+	if checkAccessControlPolicyPermission(c, model.PermissionSysconsoleWriteUserManagementChannels); c.Err != nil {
+		return
+	}
+
+	policy.ChannelId = c.Params.ChannelId
+	policy.CreatorId = c.AppContext.Session().UserId
+	policy.CreateAt = 0
+	saved, appErr := c.App.SaveAccessControlPolicy(c.AppContext, policy)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(saved)
+	auditRec.AddEventObjectType("access_control_policy")
+
+	if err := json.NewEncoder(w).Encode(saved); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteAccessControlPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteAccessControlPolicy", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "channel_id", c.Params.ChannelId)
+
+	if checkAccessControlPolicyPermission(c, model.PermissionSysconsoleWriteUserManagementChannels); c.Err != nil {
+		return
+	}
+
+	if appErr := c.App.DeleteAccessControlPolicy(c.Params.ChannelId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+func simulateAccessControlPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	var policy *model.AccessControlPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil || policy == nil {
+		c.SetInvalidParamWithErr("access_control_policy", err)
+		return
+	}
+
+	if checkAccessControlPolicyPermission(c, model.PermissionSysconsoleReadUserManagementChannels); c.Err != nil {
+		return
+	}
+
+	simulation, appErr := c.App.SimulateAccessControlPolicy(c.AppContext, c.Params.ChannelId, policy.Expression)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(simulation); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestAccessControlPolicy(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	channel := th.CreatePrivateChannel()
+	const expression = `"system_user" in user.roles`
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.SaveAccessControlPolicy(context.Background(), channel.Id, expression)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.AccessControlSettings.EnableAttributeBasedAccessControl = true })
+
+	t.Run("permissions", func(t *testing.T) {
+		_, resp, err := th.Client.SaveAccessControlPolicy(context.Background(), channel.Id, expression)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.Client.SimulateAccessControlPolicy(context.Background(), channel.Id, expression)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("simulate", func(t *testing.T) {
+		simulation, _, err := th.SystemAdminClient.SimulateAccessControlPolicy(context.Background(), channel.Id, expression)
+		require.NoError(t, err)
+		assert.Equal(t, 1, simulation.MemberCount)
+		assert.Equal(t, 1, simulation.MatchingCount)
+		assert.Empty(t, simulation.RemovedUserIds)
+
+		_, resp, err := th.SystemAdminClient.SimulateAccessControlPolicy(context.Background(), channel.Id, `user.department == "Sales"`)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("save, get and delete", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.GetAccessControlPolicy(context.Background(), channel.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		policy, _, err := th.SystemAdminClient.SaveAccessControlPolicy(context.Background(), channel.Id, expression)
+		require.NoError(t, err)
+		assert.Equal(t, channel.Id, policy.ChannelId)
+		assert.Equal(t, th.SystemAdminUser.Id, policy.CreatorId)
+
+		fetched, _, err := th.SystemAdminClient.GetAccessControlPolicy(context.Background(), channel.Id)
+		require.NoError(t, err)
+		assert.Equal(t, policy, fetched)
+
+		_, err = th.SystemAdminClient.DeleteAccessControlPolicy(context.Background(), channel.Id)
+		require.NoError(t, err)
+
+		resp, err = th.SystemAdminClient.DeleteAccessControlPolicy(context.Background(), channel.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("add member", func(t *testing.T) {
+		_, _, err := th.SystemAdminClient.SaveAccessControlPolicy(context.Background(), channel.Id, `user.attributes.department == "Engineering"`)
+		require.NoError(t, err)
+
+		_, resp, err := th.SystemAdminClient.AddChannelMember(context.Background(), channel.Id, th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+		CheckErrorID(t, err, "api.channel.add_user_to_channel.access_control.app_error")
+	})
+}
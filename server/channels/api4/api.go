@@ -336,6 +336,13 @@ func Init(srv *app.Server) (*API, error) {
 	api.InitLimits()
 	api.InitOutgoingOAuthConnection()
 	api.InitClientPerformanceMetrics()
+	api.InitNotificationRule()
+	api.InitEmailDigest()
+	api.InitChannelEmailAddress()
+	api.InitAccessControlPolicy()
+	api.InitCustomProfileAttribute()
+	api.InitWebAuthn()
+	api.InitAuditRecord()
 
 	srv.Router.Handle("/api/v4/{anything:.*}", http.HandlerFunc(api.Handle404))
 
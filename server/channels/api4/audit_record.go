@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitAuditRecord() {
+	api.BaseRoutes.APIRoot.Handle("/audits/search", api.APISessionRequired(searchAuditRecords)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/audits/export", api.APISessionRequired(exportAuditRecords)).Methods(http.MethodGet)
+}
+
+// auditRecordSearchOptionsFromQuery parses the filters of the audit records from the query
+// of the request. The time range is in milliseconds.
+func auditRecordSearchOptionsFromQuery(c *Context, r *http.Request) model.AuditRecordSearchOptions {
+	query := r.URL.Query()
+	opts := model.AuditRecordSearchOptions{
+		UserId:     query.Get("user_id"),
+		EventName:  query.Get("event_name"),
+		IpAddress:  query.Get("ip_address"),
+		ObjectType: query.Get("object_type"),
+		Status:     query.Get("status"),
+		Limit:      c.Params.PerPage,
+	}
+
+	if opts.UserId != "" && !model.IsValidId(opts.UserId) {
+		c.SetInvalidURLParam("user_id")
+		return opts
+	}
+
+	for param, value := range map[string]*int64{"since": &opts.StartTime, "until": &opts.EndTime} {
+		if s := query.Get(param); s != "" {
+			parsed, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || parsed < 0 {
+				c.SetInvalidURLParam(param)
+				return opts
+			}
+			*value = parsed
+		}
+	}
+
+	cursor, err := model.DecodeAuditRecordCursor(query.Get("cursor"))
+	if err != nil {
+		c.SetInvalidURLParam("cursor")
+		return opts
+	}
+	opts.Cursor = cursor
+
+	return opts
+}
+
+func addAuditRecordSearchParameters(auditRec *audit.Record, opts model.AuditRecordSearchOptions) {
+	audit.AddEventParameter(auditRec, "user_id", opts.UserId)
+	audit.AddEventParameter(auditRec, "event_name", opts.EventName)
+	audit.AddEventParameter(auditRec, "ip_address", opts.IpAddress)
+	audit.AddEventParameter(auditRec, "object_type", opts.ObjectType)
+	audit.AddEventParameter(auditRec, "status", opts.Status)
+	audit.AddEventParameter(auditRec, "since", opts.StartTime)
+	audit.AddEventParameter(auditRec, "until", opts.EndTime)
+}
+
+func searchAuditRecords(c *Context, w http.ResponseWriter, r *http.Request) {
+	opts := auditRecordSearchOptionsFromQuery(c, r)
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("searchAuditRecords", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	addAuditRecordSearchParameters(auditRec, opts)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionReadAudits) {
+		c.SetPermissionError(model.PermissionReadAudits)
+		return
+	}
+
+	list, appErr := c.App.SearchAuditRecords(opts)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func exportAuditRecords(c *Context, w http.ResponseWriter, r *http.Request) {
+	opts := auditRecordSearchOptionsFromQuery(c, r)
+	if c.Err != nil {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = model.AuditRecordExportFormatJSONL
+	}
+
+	auditRec := c.MakeAuditRecord("exportAuditRecords", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	addAuditRecordSearchParameters(auditRec, opts)
+	audit.AddEventParameter(auditRec, "format", format)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionReadAudits) {
+		c.SetPermissionError(model.PermissionReadAudits)
+		return
+	}
+
+	var contentType string
+	switch format {
+	case model.AuditRecordExportFormatCSV:
+		contentType = "text/csv"
+	case model.AuditRecordExportFormatJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		c.SetInvalidURLParam("format")
+		return
+	}
+
+	exportWriter := &auditRecordExportWriter{ResponseWriter: w, contentType: contentType, filename: "audit_records." + format}
+	if appErr := c.App.ExportAuditRecords(opts, format, exportWriter); appErr != nil {
+		// Once the records are streaming, the response can no longer report the error.
+		if exportWriter.started {
+			c.Logger.Error("Failed to export the audit records", mlog.Err(appErr))
+			return
+		}
+		c.Err = appErr
+		return
+	}
+	// An export without records may not have written anything.
+	exportWriter.start()
+
+	auditRec.Success()
+}
+
+// auditRecordExportWriter sets the headers of an audit record export when it starts
+// writing it, so that an error occurring before can still be reported as usual.
+type auditRecordExportWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *auditRecordExportWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.Header().Set("Content-Type", w.contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+w.filename+"\"")
+}
+
+func (w *auditRecordExportWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSearchAuditRecords(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.SearchAuditRecords(context.Background(), model.AuditRecordSearchOptions{})
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ExperimentalAuditSettings.EnableDatabaseStorage = true })
+
+	chainID := model.NewId()
+	var saved []*model.AuditRecord
+	for i, eventName := range []string{"login", "updateUser", "login"} {
+		record, err := th.App.Srv().Store().AuditRecord().Save(&model.AuditRecord{
+			ChainId:    chainID,
+			Sequence:   int64(i + 1),
+			CreateAt:   1000 + int64(i),
+			EventName:  eventName,
+			Status:     "success",
+			UserId:     th.BasicUser.Id,
+			IpAddress:  "10.0.0.1",
+			ObjectType: "user",
+			Hash:       strings.Repeat("a", 64),
+			Data:       json.RawMessage(`{"event_name":"` + eventName + `"}`),
+		})
+		require.NoError(t, err)
+		saved = append(saved, record)
+	}
+	opts := model.AuditRecordSearchOptions{UserId: th.BasicUser.Id, StartTime: 1000, EndTime: 2000}
+
+	t.Run("without permission", func(t *testing.T) {
+		_, resp, err := th.Client.SearchAuditRecords(context.Background(), opts)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		var buf bytes.Buffer
+		_, resp, err = th.Client.ExportAuditRecords(context.Background(), opts, model.AuditRecordExportFormatCSV, &buf)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("search with filters", func(t *testing.T) {
+		filtered := opts
+		filtered.EventName = "login"
+		list, _, err := th.SystemAdminClient.SearchAuditRecords(context.Background(), filtered)
+		require.NoError(t, err)
+		require.Len(t, list.Records, 2)
+		assert.Equal(t, saved[2].Id, list.Records[0].Id)
+		assert.Equal(t, saved[0].Id, list.Records[1].Id)
+		assert.JSONEq(t, `{"event_name":"login"}`, string(list.Records[0].Data))
+		assert.Empty(t, list.NextCursor)
+	})
+
+	t.Run("search pages", func(t *testing.T) {
+		paged := opts
+		paged.Limit = 2
+		list, _, err := th.SystemAdminClient.SearchAuditRecords(context.Background(), paged)
+		require.NoError(t, err)
+		require.Len(t, list.Records, 2)
+		require.NotEmpty(t, list.NextCursor)
+
+		paged.Cursor, err = model.DecodeAuditRecordCursor(list.NextCursor)
+		require.NoError(t, err)
+		list, _, err = th.SystemAdminClient.SearchAuditRecords(context.Background(), paged)
+		require.NoError(t, err)
+		require.Len(t, list.Records, 1)
+		assert.Equal(t, saved[0].Id, list.Records[0].Id)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.SearchAuditRecords(context.Background(), model.AuditRecordSearchOptions{UserId: "invalid"})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		var buf bytes.Buffer
+		_, resp, err = th.SystemAdminClient.ExportAuditRecords(context.Background(), opts, "xml", &buf)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("export csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, _, err := th.SystemAdminClient.ExportAuditRecords(context.Background(), opts, model.AuditRecordExportFormatCSV, &buf)
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		assert.Equal(t, model.AuditRecordCSVHeader, rows[0])
+		assert.Equal(t, saved[2].CSVRow(), rows[1])
+	})
+
+	t.Run("export jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, _, err := th.SystemAdminClient.ExportAuditRecords(context.Background(), opts, model.AuditRecordExportFormatJSONL, &buf)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+		var record model.AuditRecord
+		require.NoError(t, json.Unmarshal([]byte(lines[2]), &record))
+		assert.Equal(t, saved[0].Id, record.Id)
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitChannelEmailAddress() {
+	api.BaseRoutes.Channel.Handle("/email_address", api.APISessionRequired(getChannelEmailAddress)).Methods(http.MethodGet)
+	api.BaseRoutes.Channel.Handle("/email_address", api.APISessionRequired(createChannelEmailAddress)).Methods(http.MethodPost)
+	api.BaseRoutes.Channel.Handle("/email_address", api.APISessionRequired(deleteChannelEmailAddress)).Methods(http.MethodDelete)
+	api.BaseRoutes.Channel.Handle("/email_address/patch", api.APISessionRequired(patchChannelEmailAddress)).Methods(http.MethodPut)
+	api.BaseRoutes.Channel.Handle("/email_address/regenerate", api.APISessionRequired(regenerateChannelEmailAddress)).Methods(http.MethodPost)
+}
+
+// checkChannelEmailAddressPermission returns the channel when the session can manage
+// its properties, since the address of a channel is one of them.
+func checkChannelEmailAddressPermission(c *Context) *model.Channel {
+	channel, appErr := c.App.GetChannel(c.AppContext, c.Params.ChannelId)
+	if appErr != nil {
+		c.Err = appErr
+		return nil
+	}
+
+	switch channel.Type {
+	case model.ChannelTypeOpen:
+		if !c.App.SessionHasPermissionToChannel(c.AppContext, *c.AppContext.Session(), channel.Id, model.PermissionManagePublicChannelProperties) {
+			c.SetPermissionError(model.PermissionManagePublicChannelProperties)
+			return nil
+		}
+	case model.ChannelTypePrivate:
+		if !c.App.SessionHasPermissionToChannel(c.AppContext, *c.AppContext.Session(), channel.Id, model.PermissionManagePrivateChannelProperties) {
+			c.SetPermissionError(model.PermissionManagePrivateChannelProperties)
+			return nil
+		}
+	default:
+		c.Err = model.NewAppError("checkChannelEmailAddressPermission", "api.channel_email_address.channel_type.app_error", nil, "", http.StatusBadRequest)
+		return nil
+	}
+
+	return channel
+}
+
+func getChannelEmailAddress(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	if checkChannelEmailAddressPermission(c); c.Err != nil {
+		return
+	}
+
+	address, appErr := c.App.GetChannelEmailAddress(c.Params.ChannelId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(address); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func createChannelEmailAddress(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch *model.ChannelEmailAddressPatch
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			c.SetInvalidParamWithErr("channel_email_address", err)
+			return
+		}
+	}
+
+	auditRec := c.MakeAuditRecord("createChannelEmailAddress", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "channel_id", c.Params.ChannelId)
+
+	channel := checkChannelEmailAddressPermission(c)
+	if c.Err != nil {
+		return
+	}
+
+	address, appErr := c.App.CreateChannelEmailAddress(c.AppContext, channel, c.AppContext.Session().UserId, patch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(address)
+	auditRec.AddEventObjectType("channel_email_address")
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(address); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchChannelEmailAddress(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch *model.ChannelEmailAddressPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		c.SetInvalidParamWithErr("channel_email_address", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchChannelEmailAddress", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "channel_id", c.Params.ChannelId)
+
+	if checkChannelEmailAddressPermission(c); c.Err != nil {
+		return
+	}
+
+	address, appErr := c.App.PatchChannelEmailAddress(c.Params.ChannelId, patch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(address)
+	auditRec.AddEventObjectType("channel_email_address")
+
+	if err := json.NewEncoder(w).Encode(address); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func regenerateChannelEmailAddress(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("regenerateChannelEmailAddress", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "channel_id", c.Params.ChannelId)
+
+	if checkChannelEmailAddressPermission(c); c.Err != nil {
+		return
+	}
+
+	address, appErr := c.App.RegenerateChannelEmailAddress(c.Params.ChannelId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(address)
+	auditRec.AddEventObjectType("channel_email_address")
+
+	if err := json.NewEncoder(w).Encode(address); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteChannelEmailAddress(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteChannelEmailAddress", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "channel_id", c.Params.ChannelId)
+
+	if checkChannelEmailAddressPermission(c); c.Err != nil {
+		return
+	}
+
+	if appErr := c.App.DeleteChannelEmailAddress(c.AppContext, c.Params.ChannelId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestChannelEmailAddress(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := client.CreateChannelEmailAddress(context.Background(), th.BasicChannel.Id, nil)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.EmailSettings.EnablePostByEmail = true
+		*cfg.EmailSettings.PostByEmailAddress = "channel@example.com"
+		*cfg.EmailSettings.InboundSMTPListenAddress = "127.0.0.1:0"
+		*cfg.ServiceSettings.EnableIncomingWebhooks = true
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, resp, err := client.GetChannelEmailAddress(context.Background(), th.BasicChannel.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	var address *model.ChannelEmailAddress
+	t.Run("create", func(t *testing.T) {
+		var resp *model.Response
+		var err error
+		address, resp, err = client.CreateChannelEmailAddress(context.Background(), th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"@example.com"}})
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		assert.Equal(t, "channel+"+address.Token+"@example.com", address.Address)
+		assert.Equal(t, model.StringArray{"@example.com"}, address.AllowedSenders)
+		assert.Equal(t, th.BasicUser.Id, address.CreatorId)
+
+		_, resp, err = client.CreateChannelEmailAddress(context.Background(), th.BasicChannel.Id, nil)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		_, resp, err = client.CreateChannelEmailAddress(context.Background(), th.BasicChannel2.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"not an email"}})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		got, _, err := client.GetChannelEmailAddress(context.Background(), th.BasicChannel.Id)
+		require.NoError(t, err)
+		assert.Equal(t, address.Address, got.Address)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		patched, _, err := client.PatchChannelEmailAddress(context.Background(), th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"jane@example.org"}})
+		require.NoError(t, err)
+		assert.Equal(t, model.StringArray{"jane@example.org"}, patched.AllowedSenders)
+		assert.Equal(t, address.Token, patched.Token)
+	})
+
+	t.Run("regenerate", func(t *testing.T) {
+		regenerated, _, err := client.RegenerateChannelEmailAddress(context.Background(), th.BasicChannel.Id)
+		require.NoError(t, err)
+		assert.NotEqual(t, address.Token, regenerated.Token)
+	})
+
+	t.Run("no permission", func(t *testing.T) {
+		private := th.CreatePrivateChannel()
+		_, _, err := client.CreateChannelEmailAddress(context.Background(), private.Id, nil)
+		require.NoError(t, err)
+
+		client2 := th.CreateClient()
+		_, _, err = client2.Login(context.Background(), th.BasicUser2.Email, th.BasicUser2.Password)
+		require.NoError(t, err)
+
+		_, resp, err := client2.GetChannelEmailAddress(context.Background(), private.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = client2.DeleteChannelEmailAddress(context.Background(), private.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("direct channel", func(t *testing.T) {
+		dm := th.CreateDmChannel(th.BasicUser2)
+		_, resp, err := client.CreateChannelEmailAddress(context.Background(), dm.Id, nil)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteChannelEmailAddress(context.Background(), th.BasicChannel.Id)
+		require.NoError(t, err)
+
+		_, resp, err := client.GetChannelEmailAddress(context.Background(), th.BasicChannel.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+}
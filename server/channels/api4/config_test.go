@@ -867,3 +867,53 @@ func TestMigrateConfig(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestConfigHistory(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+	client := th.Client
+
+	t.Run("as system user", func(t *testing.T) {
+		_, resp, err := client.GetConfigHistory(context.Background(), 0, 10)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = client.GetConfigVersionDiff(context.Background(), model.NewId(), "")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = client.RollbackConfig(context.Background(), model.NewId())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	th.TestForSystemAdminAndLocal(t, func(t *testing.T, client *model.Client4) {
+		// The test server keeps its configuration in memory, which has no history.
+		_, resp, err := client.GetConfigHistory(context.Background(), 0, 10)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+
+		_, resp, err = client.GetConfigVersionDiff(context.Background(), model.NewId(), "")
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+
+		resp, err = client.RollbackConfig(context.Background(), model.NewId())
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	}, "as system admin and local mode without a database store")
+
+	t.Run("invalid version id", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.GetConfigVersionDiff(context.Background(), model.NewId(), "invalid")
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("as restricted system admin", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ExperimentalSettings.RestrictSystemAdmin = true })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ExperimentalSettings.RestrictSystemAdmin = false })
+
+		resp, err := th.SystemAdminClient.RollbackConfig(context.Background(), model.NewId())
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+}
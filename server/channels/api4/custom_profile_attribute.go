@@ -0,0 +1,197 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitCustomProfileAttribute() {
+	api.BaseRoutes.APIRoot.Handle("/custom_profile_attributes/fields", api.APISessionRequired(getCustomProfileFields)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/custom_profile_attributes/fields", api.APISessionRequired(createCustomProfileField)).Methods(http.MethodPost)
+	api.BaseRoutes.APIRoot.Handle("/custom_profile_attributes/fields/{field_id:[A-Za-z0-9]+}", api.APISessionRequired(patchCustomProfileField)).Methods(http.MethodPatch)
+	api.BaseRoutes.APIRoot.Handle("/custom_profile_attributes/fields/{field_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteCustomProfileField)).Methods(http.MethodDelete)
+
+	api.BaseRoutes.User.Handle("/custom_profile_attributes", api.APISessionRequired(getCustomProfileValues)).Methods(http.MethodGet)
+	api.BaseRoutes.User.Handle("/custom_profile_attributes", api.APISessionRequired(patchCustomProfileValues)).Methods(http.MethodPut)
+}
+
+func getCustomProfileFields(c *Context, w http.ResponseWriter, r *http.Request) {
+	fields, appErr := c.App.GetCustomProfileFields()
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	// The fields only visible to admins are hidden from everyone else.
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleReadUserManagementUsers) {
+		visible := []*model.CustomProfileField{}
+		for _, field := range fields {
+			if field.Visibility != model.CustomProfileFieldVisibilityAdmin {
+				visible = append(visible, field)
+			}
+		}
+		fields = visible
+	}
+
+	if err := json.NewEncoder(w).Encode(fields); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func createCustomProfileField(c *Context, w http.ResponseWriter, r *http.Request) {
+	var field *model.CustomProfileField
+	if err := json.NewDecoder(r.Body).Decode(&field); err != nil || field == nil {
+		c.SetInvalidParamWithErr("custom_profile_field", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createCustomProfileField", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "name", field.Name)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleWriteUserManagementUsers) {
+		c.SetPermissionError(model.PermissionSysconsoleWriteUserManagementUsers)
+		return
+	}
+
+	created, appErr := c.App.CreateCustomProfileField(field)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(created)
+	auditRec.AddEventObjectType("custom_profile_field")
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchCustomProfileField(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireCustomProfileFieldId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch *model.CustomProfileFieldPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		c.SetInvalidParamWithErr("custom_profile_field_patch", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchCustomProfileField", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "field_id", c.Params.CustomProfileFieldId)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleWriteUserManagementUsers) {
+		c.SetPermissionError(model.PermissionSysconsoleWriteUserManagementUsers)
+		return
+	}
+
+	patched, appErr := c.App.PatchCustomProfileField(c.AppContext, c.Params.CustomProfileFieldId, patch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(patched)
+	auditRec.AddEventObjectType("custom_profile_field")
+
+	if err := json.NewEncoder(w).Encode(patched); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteCustomProfileField(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireCustomProfileFieldId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteCustomProfileField", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "field_id", c.Params.CustomProfileFieldId)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleWriteUserManagementUsers) {
+		c.SetPermissionError(model.PermissionSysconsoleWriteUserManagementUsers)
+		return
+	}
+
+	if appErr := c.App.DeleteCustomProfileField(c.AppContext, c.Params.CustomProfileFieldId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+func getCustomProfileValues(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	canSee, appErr := c.App.UserCanSeeOtherUser(c.AppContext, c.AppContext.Session().UserId, c.Params.UserId)
+	if appErr != nil || !canSee {
+		c.SetPermissionError(model.PermissionViewMembers)
+		return
+	}
+
+	asAdmin := c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleReadUserManagementUsers)
+	values, appErr := c.App.GetCustomProfileValues(c.Params.UserId, c.AppContext.Session().UserId, asAdmin)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(values); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchCustomProfileValues(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		c.SetInvalidParamWithErr("custom_profile_attributes", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchCustomProfileValues", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
+
+	asAdmin := c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleWriteUserManagementUsers)
+	if !asAdmin && c.AppContext.Session().UserId != c.Params.UserId {
+		c.SetPermissionError(model.PermissionSysconsoleWriteUserManagementUsers)
+		return
+	}
+
+	values, appErr := c.App.PatchCustomProfileValues(c.AppContext, c.Params.UserId, patch, asAdmin)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(values); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCustomProfileAttributes(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	location := &model.CustomProfileField{Name: "location", DisplayName: "Location", Type: model.CustomProfileFieldTypeText}
+
+	t.Run("create field permissions", func(t *testing.T) {
+		_, resp, err := th.Client.CreateCustomProfileField(context.Background(), location)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	location, resp, err := th.SystemAdminClient.CreateCustomProfileField(context.Background(), location)
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+
+	salary, _, err := th.SystemAdminClient.CreateCustomProfileField(context.Background(), &model.CustomProfileField{
+		Name:        "salary_band",
+		DisplayName: "Salary band",
+		Type:        model.CustomProfileFieldTypeSelect,
+		Options:     model.StringArray{"A", "B"},
+		Visibility:  model.CustomProfileFieldVisibilityAdmin,
+	})
+	require.NoError(t, err)
+
+	t.Run("get fields", func(t *testing.T) {
+		fields, _, err := th.Client.GetCustomProfileFields(context.Background())
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.Equal(t, location.Id, fields[0].Id)
+
+		fields, _, err = th.SystemAdminClient.GetCustomProfileFields(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, fields, 2)
+	})
+
+	t.Run("patch field", func(t *testing.T) {
+		displayName := "Office"
+		_, resp, err := th.Client.PatchCustomProfileField(context.Background(), location.Id, &model.CustomProfileFieldPatch{DisplayName: &displayName})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		patched, _, err := th.SystemAdminClient.PatchCustomProfileField(context.Background(), location.Id, &model.CustomProfileFieldPatch{DisplayName: &displayName})
+		require.NoError(t, err)
+		assert.Equal(t, displayName, patched.DisplayName)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		values, _, err := th.Client.PatchCustomProfileAttributes(context.Background(), th.BasicUser.Id, map[string]string{location.Id: "Paris"})
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+		assert.Equal(t, "Paris", values[0].Value)
+
+		_, resp, err := th.Client.PatchCustomProfileAttributes(context.Background(), th.BasicUser.Id, map[string]string{salary.Id: "A"})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.Client.PatchCustomProfileAttributes(context.Background(), th.BasicUser2.Id, map[string]string{location.Id: "Paris"})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, _, err = th.SystemAdminClient.PatchCustomProfileAttributes(context.Background(), th.BasicUser.Id, map[string]string{salary.Id: "B"})
+		require.NoError(t, err)
+
+		values, _, err = th.Client.GetCustomProfileAttributes(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+		assert.Equal(t, location.Id, values[0].FieldId)
+
+		values, _, err = th.SystemAdminClient.GetCustomProfileAttributes(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.Len(t, values, 2)
+
+		users, _, err := th.Client.SearchUsers(context.Background(), &model.UserSearch{Term: "Paris", TeamId: th.BasicTeam.Id})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, th.BasicUser.Id, users[0].Id)
+	})
+
+	t.Run("delete field", func(t *testing.T) {
+		resp, err := th.Client.DeleteCustomProfileField(context.Background(), location.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, err = th.SystemAdminClient.DeleteCustomProfileField(context.Background(), location.Id)
+		require.NoError(t, err)
+
+		resp, err = th.SystemAdminClient.DeleteCustomProfileField(context.Background(), location.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		values, _, err := th.Client.GetCustomProfileAttributes(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+)
+
+func (api *API) InitEmailDigest() {
+	// The unsubscribe link is opened from the digest email, without a session. Opening it only
+	// asks for a confirmation, as mail link scanners follow the links of the emails they receive.
+	api.BaseRoutes.User.Handle("/email_digest/unsubscribe", api.APIHandler(confirmEmailDigestUnsubscribe)).Methods(http.MethodGet)
+	// Both the confirmation and the one-click unsubscribe of the mail clients (RFC 8058) post to
+	// the link. Its signature protects it from forged requests, a CSRF token isn't required.
+	api.BaseRoutes.User.Handle("/email_digest/unsubscribe", api.APIHandlerTrustRequester(unsubscribeFromEmailDigest)).Methods(http.MethodPost)
+}
+
+func confirmEmailDigestUnsubscribe(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if appErr := c.App.CheckEmailDigestUnsubscribeSignature(c.Params.UserId, r.URL.Query().Get("signature")); appErr != nil {
+		c.Err = appErr
+		utils.RenderWebAppError(c.App.Config(), w, r, c.Err, c.App.AsymmetricSigningKey())
+		return
+	}
+
+	// The form posts to the link itself, signature included.
+	utils.RenderMobileMessage(w, `
+		<h2> `+template.HTMLEscapeString(c.AppContext.T("api.email_digest.unsubscribe.confirm_title"))+` </h2>
+		<p> `+template.HTMLEscapeString(c.AppContext.T("api.email_digest.unsubscribe.confirm_info"))+` </p>
+		<form method="post">
+			<button type="submit">`+template.HTMLEscapeString(c.AppContext.T("api.email_digest.unsubscribe.confirm_button"))+`</button>
+		</form>
+	`)
+}
+
+func unsubscribeFromEmailDigest(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("unsubscribeFromEmailDigest", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
+
+	if appErr := c.App.UnsubscribeFromEmailDigest(c.AppContext, c.Params.UserId, r.URL.Query().Get("signature")); appErr != nil {
+		c.Err = appErr
+		utils.RenderWebAppError(c.App.Config(), w, r, c.Err, c.App.AsymmetricSigningKey())
+		return
+	}
+
+	auditRec.Success()
+
+	utils.RenderMobileMessage(w, `
+		<h2> `+template.HTMLEscapeString(c.AppContext.T("api.email_digest.unsubscribe.title"))+` </h2>
+		<p> `+template.HTMLEscapeString(c.AppContext.T("api.email_digest.unsubscribe.info"))+` </p>
+		<a href="`+template.HTMLEscapeString(c.App.GetSiteURL())+`">
+			`+template.HTMLEscapeString(c.AppContext.T("api.back_to_app", map[string]any{"SiteName": *c.App.Config().TeamSettings.SiteName}))+`
+		</a>
+	`)
+}
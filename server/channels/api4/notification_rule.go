@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitNotificationRule() {
+	api.BaseRoutes.User.Handle("/notification_rules", api.APISessionRequired(getNotificationRules)).Methods(http.MethodGet)
+	api.BaseRoutes.User.Handle("/notification_rules", api.APISessionRequired(createNotificationRule)).Methods(http.MethodPost)
+	api.BaseRoutes.User.Handle("/notification_rules/{rule_id:[A-Za-z0-9]+}", api.APISessionRequired(updateNotificationRule)).Methods(http.MethodPut)
+	api.BaseRoutes.User.Handle("/notification_rules/{rule_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteNotificationRule)).Methods(http.MethodDelete)
+}
+
+func getNotificationRules(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	rules, appErr := c.App.GetNotificationRules(c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(rules); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func createNotificationRule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	var rule *model.NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil || rule == nil {
+		c.SetInvalidParamWithErr("notification_rule", err)
+		return
+	}
+	rule.UserId = c.Params.UserId
+
+	auditRec := c.MakeAuditRecord("createNotificationRule", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "notification_rule", rule)
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	created, appErr := c.App.CreateNotificationRule(rule)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(created)
+	auditRec.AddEventObjectType("notification_rule")
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func updateNotificationRule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireNotificationRuleId()
+	if c.Err != nil {
+		return
+	}
+
+	var rule *model.NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil || rule == nil {
+		c.SetInvalidParamWithErr("notification_rule", err)
+		return
+	}
+
+	// The rule should have the same id as the one specified in the URL
+	if rule.Id != c.Params.NotificationRuleId {
+		c.SetInvalidParam("id")
+		return
+	}
+	rule.UserId = c.Params.UserId
+
+	auditRec := c.MakeAuditRecord("updateNotificationRule", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "notification_rule", rule)
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	updated, appErr := c.App.UpdateNotificationRule(rule)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(updated)
+	auditRec.AddEventObjectType("notification_rule")
+
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteNotificationRule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireNotificationRuleId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteNotificationRule", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
+	audit.AddEventParameter(auditRec, "rule_id", c.Params.NotificationRuleId)
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	deleted, appErr := c.App.DeleteNotificationRule(c.Params.UserId, c.Params.NotificationRuleId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventPriorState(deleted)
+	auditRec.AddEventObjectType("notification_rule")
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestNotificationRules(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	newRule := func() *model.NotificationRule {
+		return &model.NotificationRule{
+			Name:     "Deploys",
+			Action:   model.NotificationRuleActionNotify,
+			Keywords: model.StringArray{"deploy"},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := client.GetNotificationRules(context.Background(), th.BasicUser.Id)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableNotificationRules = true })
+
+	var rule *model.NotificationRule
+	t.Run("create", func(t *testing.T) {
+		var resp *model.Response
+		var err error
+		rule, resp, err = client.CreateNotificationRule(context.Background(), th.BasicUser.Id, newRule())
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		assert.NotEmpty(t, rule.Id)
+		assert.Equal(t, th.BasicUser.Id, rule.UserId)
+
+		invalid := newRule()
+		invalid.Patterns = model.StringArray{"a("}
+		_, resp, err = client.CreateNotificationRule(context.Background(), th.BasicUser.Id, invalid)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		rules, _, err := client.GetNotificationRules(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		assert.Equal(t, rule.Id, rules[0].Id)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		rule.Action = model.NotificationRuleActionMute
+		rule.Schedule = &model.NotificationRuleSchedule{StartTime: "22:00", EndTime: "07:00"}
+		updated, _, err := client.UpdateNotificationRule(context.Background(), th.BasicUser.Id, rule)
+		require.NoError(t, err)
+		assert.Equal(t, model.NotificationRuleActionMute, updated.Action)
+		assert.Equal(t, rule.Schedule, updated.Schedule)
+		assert.Equal(t, rule.CreateAt, updated.CreateAt)
+	})
+
+	t.Run("other users", func(t *testing.T) {
+		_, resp, err := client.GetNotificationRules(context.Background(), th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = client.CreateNotificationRule(context.Background(), th.BasicUser2.Id, newRule())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		// The rule of another user can't be reached through one's own route.
+		otherRule, _, err := th.SystemAdminClient.CreateNotificationRule(context.Background(), th.BasicUser2.Id, newRule())
+		require.NoError(t, err)
+		resp, err = client.DeleteNotificationRule(context.Background(), th.BasicUser.Id, otherRule.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteNotificationRule(context.Background(), th.BasicUser.Id, rule.Id)
+		require.NoError(t, err)
+
+		rules, _, err := client.GetNotificationRules(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+}
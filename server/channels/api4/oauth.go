@@ -20,6 +20,7 @@ func (api *API) InitOAuth() {
 	api.BaseRoutes.OAuthApp.Handle("/info", api.APISessionRequired(getOAuthAppInfo)).Methods(http.MethodGet)
 	api.BaseRoutes.OAuthApp.Handle("", api.APISessionRequired(deleteOAuthApp)).Methods(http.MethodDelete)
 	api.BaseRoutes.OAuthApp.Handle("/regen_secret", api.APISessionRequired(regenerateOAuthAppSecret)).Methods(http.MethodPost)
+	api.BaseRoutes.OAuth.Handle("/device/app", api.APISessionRequired(getOAuthAppForDeviceCode)).Methods(http.MethodGet)
 
 	api.BaseRoutes.User.Handle("/oauth/apps/authorized", api.APISessionRequired(getAuthorizedOAuthApps)).Methods(http.MethodGet)
 }
@@ -47,6 +48,14 @@ func createOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	oauthApp.CreatorId = c.AppContext.Session().UserId
 
+	// The client credentials grant issues tokens for the bot, so only its managers can bind an app to it.
+	if oauthApp.BotUserId != "" {
+		if err := c.App.SessionHasPermissionToManageBot(c.AppContext, *c.AppContext.Session(), oauthApp.BotUserId); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
 	rapp, err := c.App.CreateOAuthApp(&oauthApp)
 	if err != nil {
 		c.Err = err
@@ -109,6 +118,13 @@ func updateOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 		oauthApp.IsTrusted = oldOAuthApp.IsTrusted
 	}
 
+	if oauthApp.BotUserId != "" && oauthApp.BotUserId != oldOAuthApp.BotUserId {
+		if err := c.App.SessionHasPermissionToManageBot(c.AppContext, *c.AppContext.Session(), oauthApp.BotUserId); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
 	updatedOAuthApp, err := c.App.UpdateOAuthApp(oldOAuthApp, &oauthApp)
 	if err != nil {
 		c.Err = err
@@ -201,6 +217,25 @@ func getOAuthAppInfo(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getOAuthAppForDeviceCode(c *Context, w http.ResponseWriter, r *http.Request) {
+	userCode := r.URL.Query().Get("user_code")
+	if userCode == "" {
+		c.SetInvalidURLParam("user_code")
+		return
+	}
+
+	oauthApp, err := c.App.GetOAuthAppForDeviceCode(userCode)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	oauthApp.Sanitize()
+	if err := json.NewEncoder(w).Encode(oauthApp); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
 func deleteOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireAppId()
 	if c.Err != nil {
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -830,6 +831,11 @@ func searchPosts(c *Context, w http.ResponseWriter, r *http.Request, teamId stri
 		return
 	}
 
+	if err = filterPostSearchResultsByTokenScope(c, results); err != nil {
+		c.Err = err
+		return
+	}
+
 	clientPostList := c.App.PreparePostListForClient(c.AppContext, results.PostList)
 	clientPostList, err = c.App.SanitizePostListMetadataForUser(c.AppContext, clientPostList, c.AppContext.Session().UserId)
 	if err != nil {
@@ -845,6 +851,43 @@ func searchPosts(c *Context, w http.ResponseWriter, r *http.Request, teamId stri
 	}
 }
 
+// filterPostSearchResultsByTokenScope removes the posts of the channels outside of the
+// scope of the user access token of the session, if it is restricted to some teams or
+// channels.
+func filterPostSearchResultsByTokenScope(c *Context, results *model.PostSearchResults) *model.AppError {
+	scope := c.AppContext.Session().GetUserAccessTokenScope()
+	if scope == nil || !scope.RestrictsChannels() || len(results.Order) == 0 {
+		return nil
+	}
+
+	channelIds := []string{}
+	for _, post := range results.Posts {
+		if !slices.Contains(channelIds, post.ChannelId) {
+			channelIds = append(channelIds, post.ChannelId)
+		}
+	}
+	channels, err := c.App.GetChannels(c.AppContext, channelIds)
+	if err != nil {
+		return err
+	}
+	allowed := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		allowed[channel.Id] = scope.AllowsChannel(channel.Id, channel.TeamId)
+	}
+
+	order := make([]string, 0, len(results.Order))
+	for _, postID := range results.Order {
+		if post, ok := results.Posts[postID]; ok && allowed[post.ChannelId] {
+			order = append(order, postID)
+			continue
+		}
+		delete(results.Posts, postID)
+		delete(results.Matches, postID)
+	}
+	results.Order = order
+	return nil
+}
+
 func updatePost(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequirePostId()
 	if c.Err != nil {
@@ -3159,6 +3159,54 @@ func TestSearchPosts(t *testing.T) {
 	CheckUnauthorizedStatus(t, resp)
 }
 
+func TestSearchPostsWithScopedUserAccessToken(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableUserAccessTokens = true })
+
+	otherChannel := th.CreatePublicChannel()
+	post := th.CreateMessagePost("scoped search post")
+	otherPost := th.CreateMessagePostWithClient(th.Client, otherChannel, "scoped search post")
+	_, err := th.Client.UpdatePreferences(context.Background(), th.BasicUser.Id, model.Preferences{
+		{UserId: th.BasicUser.Id, Category: model.PreferenceCategoryFlaggedPost, Name: post.Id, Value: "true"},
+		{UserId: th.BasicUser.Id, Category: model.PreferenceCategoryFlaggedPost, Name: otherPost.Id, Value: "true"},
+	})
+	require.NoError(t, err)
+
+	token, _, err := th.SystemAdminClient.CreateScopedUserAccessToken(context.Background(), th.BasicUser.Id, &model.UserAccessToken{
+		Description: "scoped token",
+		Scope:       &model.UserAccessTokenScope{ChannelIds: []string{th.BasicChannel.Id}},
+	})
+	require.NoError(t, err)
+	client := th.CreateClient()
+	client.AuthToken = token.Token
+
+	t.Run("search in all teams only returns the posts of the scoped channels", func(t *testing.T) {
+		posts, _, err := client.SearchPosts(context.Background(), "", "scoped", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{post.Id}, posts.Order)
+		require.NotContains(t, posts.Posts, otherPost.Id)
+	})
+
+	t.Run("search in a team only returns the posts of the scoped channels", func(t *testing.T) {
+		posts, _, err := client.SearchPosts(context.Background(), th.BasicTeam.Id, "scoped", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{post.Id}, posts.Order)
+	})
+
+	t.Run("flagged posts cannot be listed", func(t *testing.T) {
+		_, resp, err := client.GetFlaggedPostsForUser(context.Background(), th.BasicUser.Id, 0, 10)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("account settings cannot be changed", func(t *testing.T) {
+		_, resp, err := client.PatchUser(context.Background(), th.BasicUser.Id, &model.UserPatch{Nickname: model.NewPointer("scoped")})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
 func TestSearchHashtagPosts(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
@@ -16,6 +16,8 @@ func (api *API) InitUsage() {
 	api.BaseRoutes.Usage.Handle("/posts", api.APISessionRequired(getPostsUsage)).Methods(http.MethodGet)
 	// GET /api/v4/usage/storage
 	api.BaseRoutes.Usage.Handle("/storage", api.APISessionRequired(getStorageUsage)).Methods(http.MethodGet)
+	// GET /api/v4/usage/storage/tiers
+	api.BaseRoutes.Usage.Handle("/storage/tiers", api.APISessionRequired(getStorageUsageByTier)).Methods(http.MethodGet)
 	// GET /api/v4/usage/teams
 	api.BaseRoutes.Usage.Handle("/teams", api.APISessionRequired(getTeamsUsage)).Methods(http.MethodGet)
 }
@@ -53,6 +55,27 @@ func getStorageUsage(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write(json)
 }
 
+func getStorageUsageByTier(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleReadEnvironmentFileStorage) {
+		c.SetPermissionError(model.PermissionSysconsoleReadEnvironmentFileStorage)
+		return
+	}
+
+	usage, appErr := c.App.GetStorageUsageByTier()
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	json, err := json.Marshal(usage)
+	if err != nil {
+		c.Err = model.NewAppError("Api4.getStorageUsageByTier", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	w.Write(json)
+}
+
 func getTeamsUsage(c *Context, w http.ResponseWriter, r *http.Request) {
 	teamsUsage, appErr := c.App.GetTeamsUsage()
 	if appErr != nil {
@@ -61,6 +61,9 @@ func (api *API) InitUser() {
 
 	api.BaseRoutes.User.Handle("/mfa", api.APISessionRequiredMfa(updateUserMfa)).Methods(http.MethodPut)
 	api.BaseRoutes.User.Handle("/mfa/generate", api.APISessionRequiredMfa(generateMfaSecret)).Methods(http.MethodPost)
+	// Activating MFA doesn't return the recovery codes, they are generated with their own endpoint.
+	api.BaseRoutes.User.Handle("/mfa/recovery_codes", api.APISessionRequired(getMfaRecoveryCodesStatus)).Methods(http.MethodGet)
+	api.BaseRoutes.User.Handle("/mfa/recovery_codes", api.APISessionRequired(regenerateMfaRecoveryCodes)).Methods(http.MethodPost)
 
 	api.BaseRoutes.Users.Handle("/login", api.APIHandler(login)).Methods(http.MethodPost)
 	api.BaseRoutes.Users.Handle("/login/desktop_token", api.RateLimitedHandler(api.APIHandler(loginWithDesktopToken), model.RateLimitSettings{PerSec: model.NewPointer(2), MaxBurst: model.NewPointer(1)})).Methods(http.MethodPost)
@@ -72,6 +75,7 @@ func (api *API) InitUser() {
 	api.BaseRoutes.UserByEmail.Handle("", api.APISessionRequired(getUserByEmail)).Methods(http.MethodGet)
 
 	api.BaseRoutes.User.Handle("/sessions", api.APISessionRequired(getSessions)).Methods(http.MethodGet)
+	api.BaseRoutes.User.Handle("/sessions/active", api.APISessionRequired(getActiveSessions)).Methods(http.MethodGet)
 	api.BaseRoutes.User.Handle("/sessions/revoke", api.APISessionRequired(revokeSession)).Methods(http.MethodPost)
 	api.BaseRoutes.User.Handle("/sessions/revoke/all", api.APISessionRequired(revokeAllSessionsForUser)).Methods(http.MethodPost)
 	api.BaseRoutes.Users.Handle("/sessions/revoke/all", api.APISessionRequired(revokeAllSessionsAllUsers)).Methods(http.MethodPost)
@@ -1694,6 +1698,72 @@ func generateMfaSecret(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getMfaRecoveryCodesStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	status, err := c.App.GetMfaRecoveryCodesStatus(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func regenerateMfaRecoveryCodes(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("regenerateMfaRecoveryCodes", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	// The codes let their holder log in as the user, only the user can generate them.
+	if c.AppContext.Session().UserId != c.Params.UserId {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	props := model.MapFromJSON(r.Body)
+	code := props["code"]
+	if code == "" {
+		c.SetInvalidParam("code")
+		return
+	}
+
+	codes, err := c.App.RegenerateMfaRecoveryCodes(c.AppContext, c.Params.UserId, code)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	c.LogAudit("success - mfa recovery codes regenerated")
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(codes); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
 func updatePassword(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUserId()
 	if c.Err != nil {
@@ -1857,8 +1927,12 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 		openidEnabled := *config.OpenIdSettings.Enable
 		googleEnabled := *config.GoogleSettings.Enable
 		office365Enabled := *config.Office365Settings.Enable
+		openIdConnectEnabled := false
+		for _, provider := range config.OpenIdConnectSettings.Providers {
+			openIdConnectEnabled = openIdConnectEnabled || *provider.Enable
+		}
 
-		if samlEnabled || gitlabEnabled || googleEnabled || office365Enabled || openidEnabled {
+		if samlEnabled || gitlabEnabled || googleEnabled || office365Enabled || openidEnabled || openIdConnectEnabled {
 			c.Err = model.NewAppError("login", "api.user.login.invalid_credentials_sso", nil, "", http.StatusUnauthorized)
 			return
 		}
@@ -1883,6 +1957,14 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 	mfaToken := props["token"]
 	deviceId := props["device_id"]
 	ldapOnly := props["ldap_only"] == "true"
+	trustDevice := props["trust_device"] == "true"
+
+	// A trusted device stands for the second factor when no MFA token is given.
+	if mfaToken == "" {
+		if cookie, err := r.Cookie(model.MfaTrustedDeviceCookie); err == nil {
+			mfaToken = cookie.Value
+		}
+	}
 
 	if *c.App.Config().ExperimentalSettings.ClientSideCertEnable {
 		if license := c.App.Channels().License(); license == nil || !*license.Features.FutureFeatures {
@@ -1950,6 +2032,11 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 		c.App.AttachSessionCookies(c.AppContext, w, r)
 	}
 
+	if err = c.App.AttachMfaTrustedDevice(c.AppContext, w, r, user, mfaToken, trustDevice); err != nil {
+		c.Err = err
+		return
+	}
+
 	userTermsOfService, err := c.App.GetUserTermsOfService(user.Id)
 	if err != nil && err.StatusCode != http.StatusNotFound {
 		c.Err = err
@@ -2118,6 +2205,32 @@ func getSessions(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write(js)
 }
 
+func getActiveSessions(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	sessions, appErr := c.App.GetActiveSessions(c.AppContext, c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(sessions)
+	if err != nil {
+		c.Err = model.NewAppError("getActiveSessions", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	w.Write(js)
+}
+
 func revokeSession(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUserId()
 	if c.Err != nil {
@@ -2159,6 +2272,12 @@ func revokeSession(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Revoking a session of a trusted device revokes the trust of the device.
+	if err := c.App.RevokeMfaTrustedDeviceForSession(c.AppContext, session); err != nil {
+		c.Err = err
+		return
+	}
+
 	auditRec.Success()
 	c.LogAudit("")
 
@@ -2468,6 +2587,13 @@ func createUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A scoped token must not be able to create a token with a wider scope.
+	if c.AppContext.Session().GetUserAccessTokenScope() != nil {
+		c.SetPermissionError(model.PermissionCreateUserAccessToken)
+		c.Err.DetailedError += ", attempted access by scoped user access token"
+		return
+	}
+
 	var accessToken model.UserAccessToken
 	if jsonErr := json.NewDecoder(r.Body).Decode(&accessToken); jsonErr != nil {
 		c.SetInvalidParamWithErr("user_access_token", jsonErr)
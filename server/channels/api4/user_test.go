@@ -3279,6 +3279,88 @@ func TestUserLoginMFAFlow(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, user)
 	})
+
+	t.Run("WithTrustedDevice", func(t *testing.T) {
+		th.App.UpdateConfig(func(c *model.Config) { *c.ServiceSettings.MfaTrustedDeviceDays = 30 })
+		defer th.App.UpdateConfig(func(c *model.Config) { *c.ServiceSettings.MfaTrustedDeviceDays = 0 })
+
+		secret, appErr := th.App.GenerateMfaSecret(th.BasicUser.Id)
+		assert.Nil(t, appErr)
+
+		err := th.Server.Store().User().UpdateMfaActive(th.BasicUser.Id, true)
+		require.NoError(t, err)
+
+		err = th.Server.Store().User().UpdateMfaSecret(th.BasicUser.Id, secret.Secret)
+		require.NoError(t, err)
+
+		code := dgoogauth.ComputeCode(secret.Secret, time.Now().UTC().Unix()/30)
+
+		client := th.CreateClient()
+		_, resp, err := client.LoginWithMFATrustingDevice(context.Background(), th.BasicUser.Email, th.BasicUser.Password, fmt.Sprintf("%06d", code))
+		require.NoError(t, err)
+		token := resp.Header.Get(model.HeaderMfaTrustedDevice)
+		require.NotEmpty(t, token)
+
+		// The device skips MFA on the next logins.
+		client = th.CreateClient()
+		user, _, err := client.LoginWithMFA(context.Background(), th.BasicUser.Email, th.BasicUser.Password, token)
+		require.NoError(t, err)
+		assert.NotNil(t, user)
+
+		// Revoking a session of the device revokes the trust.
+		session, appErr := th.App.GetSession(client.AuthToken)
+		require.Nil(t, appErr)
+		_, err = client.RevokeSession(context.Background(), th.BasicUser.Id, session.Id)
+		require.NoError(t, err)
+
+		_, _, err = th.CreateClient().LoginWithMFA(context.Background(), th.BasicUser.Email, th.BasicUser.Password, token)
+		CheckErrorID(t, err, "mfa.validate_token.authenticate.app_error")
+	})
+}
+
+func TestMfaRecoveryCodes(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMultifactorAuthentication = true })
+
+	_, resp, err := th.Client.RegenerateMfaRecoveryCodes(context.Background(), th.BasicUser.Id, "aaaaa-aaaaa")
+	require.Error(t, err)
+	CheckBadRequestStatus(t, resp)
+
+	err = th.Server.Store().User().UpdateMfaActive(th.BasicUser.Id, true)
+	require.NoError(t, err)
+	th.App.InvalidateCacheForUser(th.BasicUser.Id)
+
+	codes, resp, err := th.Client.RegenerateMfaRecoveryCodes(context.Background(), th.BasicUser.Id, "")
+	require.Error(t, err)
+	CheckUnauthorizedStatus(t, resp)
+	assert.Nil(t, codes)
+
+	secret, appErr := th.App.GenerateMfaSecret(th.BasicUser.Id)
+	require.Nil(t, appErr)
+	code := dgoogauth.ComputeCode(secret.Secret, time.Now().UTC().Unix()/30)
+
+	codes, _, err = th.Client.RegenerateMfaRecoveryCodes(context.Background(), th.BasicUser.Id, fmt.Sprintf("%06d", code))
+	require.NoError(t, err)
+	require.Len(t, codes.RecoveryCodes, model.MfaRecoveryCodeCount)
+
+	status, _, err := th.Client.GetMfaRecoveryCodesStatus(context.Background(), th.BasicUser.Id)
+	require.NoError(t, err)
+	assert.Equal(t, model.MfaRecoveryCodeCount, status.Remaining)
+	assert.Empty(t, status.RecoveryCodes)
+
+	_, resp, err = th.Client.GetMfaRecoveryCodesStatus(context.Background(), th.BasicUser2.Id)
+	require.Error(t, err)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp, err = th.SystemAdminClient.RegenerateMfaRecoveryCodes(context.Background(), th.BasicUser.Id, codes.RecoveryCodes[0])
+	require.Error(t, err)
+	CheckForbiddenStatus(t, resp)
+
+	status, _, err = th.SystemAdminClient.GetMfaRecoveryCodesStatus(context.Background(), th.BasicUser.Id)
+	require.NoError(t, err)
+	assert.Equal(t, model.MfaRecoveryCodeCount, status.Remaining)
 }
 
 func TestGenerateMfaSecret(t *testing.T) {
@@ -3531,6 +3613,43 @@ func TestGetSessions(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGetActiveSessions(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	user := th.BasicUser
+
+	_, _, err := th.Client.Login(context.Background(), user.Email, user.Password)
+	require.NoError(t, err)
+
+	sessions, _, err := th.Client.GetActiveSessions(context.Background(), user.Id)
+	require.NoError(t, err)
+	require.NotEmpty(t, sessions)
+
+	var current *model.ActiveSession
+	for _, session := range sessions {
+		if session.IsCurrent {
+			current = session
+		}
+	}
+	require.NotNil(t, current)
+	assert.Equal(t, model.SessionClientTypeWeb, current.ClientType)
+	assert.NotEmpty(t, current.Name)
+	assert.NotZero(t, current.LastActivityAt)
+
+	_, resp, err := th.Client.GetActiveSessions(context.Background(), th.BasicUser2.Id)
+	require.Error(t, err)
+	CheckForbiddenStatus(t, resp)
+
+	_, _, err = th.SystemAdminClient.GetActiveSessions(context.Background(), user.Id)
+	require.NoError(t, err)
+
+	th.Client.Logout(context.Background())
+	_, resp, err = th.Client.GetActiveSessions(context.Background(), user.Id)
+	require.Error(t, err)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestRevokeSessions(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
@@ -0,0 +1,244 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+)
+
+func (api *API) InitWebAuthn() {
+	// Registering an authenticator is allowed before completing MFA, for users who have to
+	// set up MFA before using the server.
+	api.BaseRoutes.User.Handle("/webauthn/registration/options", api.APISessionRequiredMfa(generateWebAuthnRegistrationOptions)).Methods(http.MethodPost)
+	api.BaseRoutes.User.Handle("/webauthn/registration", api.APISessionRequiredMfa(registerWebAuthnCredential)).Methods(http.MethodPost)
+	api.BaseRoutes.User.Handle("/webauthn/credentials", api.APISessionRequiredMfa(getWebAuthnCredentials)).Methods(http.MethodGet)
+	api.BaseRoutes.User.Handle("/webauthn/credentials/{credential_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteWebAuthnCredential)).Methods(http.MethodDelete)
+
+	api.BaseRoutes.Users.Handle("/login/webauthn/options", api.APIHandler(generateWebAuthnLoginOptions)).Methods(http.MethodPost)
+	api.BaseRoutes.Users.Handle("/login/webauthn", api.APIHandler(loginWithWebAuthn)).Methods(http.MethodPost)
+}
+
+func generateWebAuthnRegistrationOptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	// Authenticators are bound to their user, they cannot be registered by an admin.
+	if c.AppContext.Session().UserId != c.Params.UserId {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	options, appErr := c.App.GenerateWebAuthnRegistrationOptions(c.AppContext, c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(options); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func registerWebAuthnCredential(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	var registration *model.WebAuthnRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&registration); err != nil || registration == nil || registration.Credential == nil {
+		c.SetInvalidParamWithErr("registration", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("registerWebAuthnCredential", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
+	audit.AddEventParameter(auditRec, "name", registration.Name)
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	if c.AppContext.Session().UserId != c.Params.UserId {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	result, appErr := c.App.RegisterWebAuthnCredential(c.AppContext, c.Params.UserId, registration)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(result.Credential)
+	auditRec.AddEventObjectType("webauthn_credential")
+	auditRec.AddMeta("recovery_codes_generated", len(result.RecoveryCodes) > 0)
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getWebAuthnCredentials(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	credentials, appErr := c.App.GetWebAuthnCredentials(c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(credentials); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteWebAuthnCredential(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireWebAuthnCredentialId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteWebAuthnCredential", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
+	audit.AddEventParameter(auditRec, "credential_id", c.Params.WebAuthnCredentialId)
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	if appErr := c.App.DeleteWebAuthnCredential(c.AppContext, c.Params.UserId, c.Params.WebAuthnCredentialId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("webauthn_credential")
+
+	ReturnStatusOK(w)
+}
+
+func generateWebAuthnLoginOptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	var request model.WebAuthnLoginOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		c.SetInvalidParamWithErr("login_id", err)
+		return
+	}
+
+	options, appErr := c.App.GenerateWebAuthnLoginOptions(c.AppContext, request.LoginId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(options); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func loginWithWebAuthn(c *Context, w http.ResponseWriter, r *http.Request) {
+	var request *model.WebAuthnLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request == nil || request.Credential == nil {
+		c.SetInvalidParamWithErr("credential", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("loginWithWebAuthn", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "device_id", request.DeviceId)
+
+	user, appErr := c.App.AuthenticateUserForWebAuthnLogin(c.AppContext, request.Credential)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+	auditRec.AddEventResultState(user)
+
+	if user.IsGuest() {
+		if c.App.Channels().License() == nil {
+			c.Err = model.NewAppError("loginWithWebAuthn", "api.user.login.guest_accounts.license.error", nil, "", http.StatusUnauthorized)
+			return
+		}
+		if !*c.App.Config().GuestAccountsSettings.Enable {
+			c.Err = model.NewAppError("loginWithWebAuthn", "api.user.login.guest_accounts.disabled.error", nil, "", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if user.IsRemote() {
+		c.Err = model.NewAppError("loginWithWebAuthn", "api.user.login.remote_users.login.error", nil, "", http.StatusUnauthorized)
+		return
+	}
+
+	c.LogAuditWithUserId(user.Id, "authenticated with webauthn")
+
+	session, appErr := c.App.DoLogin(c.AppContext, w, r, user, request.DeviceId, utils.IsMobileRequest(r), false, false)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+	c.AppContext = c.AppContext.WithSession(session)
+
+	c.LogAuditWithUserId(user.Id, "success")
+
+	if r.Header.Get(model.HeaderRequestedWith) == model.HeaderRequestedWithXML {
+		c.App.AttachSessionCookies(c.AppContext, w, r)
+	}
+
+	userTermsOfService, appErr := c.App.GetUserTermsOfService(user.Id)
+	if appErr != nil && appErr.StatusCode != http.StatusNotFound {
+		c.Err = appErr
+		return
+	}
+
+	if userTermsOfService != nil {
+		user.TermsOfServiceId = userTermsOfService.TermsOfServiceId
+		user.TermsOfServiceCreateAt = userTermsOfService.CreateAt
+	}
+
+	user.Sanitize(map[string]bool{})
+
+	auditRec.Success()
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
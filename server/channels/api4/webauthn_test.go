@@ -0,0 +1,124 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestWebAuthn(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := client.GenerateWebAuthnRegistrationOptions(context.Background(), th.BasicUser.Id)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+
+		_, resp, err = client.GenerateWebAuthnLoginOptions(context.Background(), th.BasicUser.Email)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.SiteURL = "https://chat.example.com"
+		*cfg.ServiceSettings.EnableMultifactorAuthentication = true
+		*cfg.ServiceSettings.EnableWebAuthn = true
+	})
+
+	t.Run("registration options", func(t *testing.T) {
+		options, _, err := client.GenerateWebAuthnRegistrationOptions(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.NotEmpty(t, options.Challenge)
+		assert.Equal(t, "chat.example.com", options.RP.Id)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString([]byte(th.BasicUser.Id)), options.User.Id)
+		assert.NotEmpty(t, options.PubKeyCredParams)
+		assert.Empty(t, options.ExcludeCredentials)
+	})
+
+	t.Run("registration for another user", func(t *testing.T) {
+		_, resp, err := client.GenerateWebAuthnRegistrationOptions(context.Background(), th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.GenerateWebAuthnRegistrationOptions(context.Background(), th.BasicUser.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("invalid registration", func(t *testing.T) {
+		_, resp, err := client.RegisterWebAuthnCredential(context.Background(), th.BasicUser.Id, &model.WebAuthnRegistrationRequest{
+			Name: "Security key",
+			Credential: &model.WebAuthnRegistrationCredential{
+				Id:    "AAAA",
+				RawId: "AAAA",
+				Type:  model.WebAuthnCredentialTypePublicKey,
+			},
+		})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("get credentials", func(t *testing.T) {
+		credentials, _, err := client.GetWebAuthnCredentials(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.Empty(t, credentials)
+
+		_, resp, err := client.GetWebAuthnCredentials(context.Background(), th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, _, err = th.SystemAdminClient.GetWebAuthnCredentials(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete unknown credential", func(t *testing.T) {
+		resp, err := client.DeleteWebAuthnCredential(context.Background(), th.BasicUser.Id, model.NewId())
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("login options", func(t *testing.T) {
+		options, _, err := th.CreateClient().GenerateWebAuthnLoginOptions(context.Background(), th.BasicUser.Email)
+		require.NoError(t, err)
+		assert.NotEmpty(t, options.Challenge)
+		assert.Equal(t, "preferred", options.UserVerification)
+
+		// Unknown users get options all the same.
+		options, _, err = th.CreateClient().GenerateWebAuthnLoginOptions(context.Background(), "unknown@example.com")
+		require.NoError(t, err)
+		assert.NotEmpty(t, options.Challenge)
+		assert.Empty(t, options.AllowCredentials)
+
+		options, _, err = th.CreateClient().GenerateWebAuthnLoginOptions(context.Background(), "")
+		require.NoError(t, err)
+		assert.Equal(t, "required", options.UserVerification)
+	})
+
+	t.Run("passwordless login", func(t *testing.T) {
+		assertion := &model.WebAuthnAssertionCredential{
+			Id:    "AAAA",
+			RawId: "AAAA",
+			Type:  model.WebAuthnCredentialTypePublicKey,
+		}
+
+		_, resp, err := th.CreateClient().LoginWithWebAuthn(context.Background(), assertion, "")
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableWebAuthnPasswordlessLogin = true })
+
+		_, resp, err = th.CreateClient().LoginWithWebAuthn(context.Background(), assertion, "")
+		require.Error(t, err)
+		CheckUnauthorizedStatus(t, resp)
+	})
+}
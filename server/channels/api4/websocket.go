@@ -5,6 +5,8 @@ package api4
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/websocket"
 
@@ -18,11 +20,22 @@ const (
 	connectionIDParam   = "connection_id"
 	sequenceNumberParam = "sequence_number"
 	postedAckParam      = "posted_ack"
+	logSequenceParam    = "log_seq"
+	timeoutParam        = "timeout"
+
+	lastEventIDHeader = "Last-Event-ID"
+
+	longPollDefaultTimeout = 30 * time.Second
+	longPollMaxTimeout     = 60 * time.Second
 )
 
 func (api *API) InitWebSocket() {
 	// Optionally supports a trailing slash
 	api.BaseRoutes.APIRoot.Handle("/{websocket:websocket(?:\\/)?}", api.APIHandlerTrustRequester(connectWebSocket)).Methods(http.MethodGet)
+
+	// Fallbacks for the clients behind proxies which don't support WebSockets.
+	api.BaseRoutes.APIRoot.Handle("/websocket/events", api.APISessionRequiredTrustRequester(streamWebSocketEvents)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/websocket/poll", api.APISessionRequiredTrustRequester(pollWebSocketEvents)).Methods(http.MethodGet)
 }
 
 func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -41,10 +54,88 @@ func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cfg, err := newWebConnConfig(c, r, r.URL.Query().Get(connectionIDParam), r.URL.Query().Get(sequenceNumberParam))
+	if err != nil {
+		c.Logger.Warn("Error while populating webconn config", mlog.String("id", r.URL.Query().Get(connectionIDParam)), mlog.Err(err))
+		ws.Close()
+		return
+	}
+	cfg.WebSocket = ws
+
+	wc := c.App.Srv().Platform().NewWebConn(cfg, c.App, c.App.Srv().Channels())
+	if c.AppContext.Session().UserId != "" {
+		c.App.Srv().Platform().HubRegister(wc)
+	}
+
+	wc.Pump()
+}
+
+// streamWebSocketEvents serves the events of the WebSocket as Server-Sent Events,
+// for the clients which can't open a WebSocket.
+func streamWebSocketEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	connectionID := r.URL.Query().Get(connectionIDParam)
+	sequenceNumber := r.URL.Query().Get(sequenceNumberParam)
+	if connectionID == "" {
+		// EventSource clients resume by sending the id of the last event they received.
+		connectionID, sequenceNumber = platform.ParseLastEventID(r.Header.Get(lastEventIDHeader))
+	}
+
+	cfg, err := newWebConnConfig(c, r, connectionID, sequenceNumber)
+	if err != nil {
+		c.Err = model.NewAppError("streamWebSocketEvents", "api.web_socket.connect.resume.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		return
+	}
+
+	stream, err := platform.NewSSEStream(w, r)
+	if err != nil {
+		c.Logger.Debug("Failed to start the event stream", mlog.Err(err))
+		return
+	}
+	cfg.Stream = stream
+
+	wc := c.App.Srv().Platform().NewWebConn(cfg, c.App, c.App.Srv().Channels())
+	c.App.Srv().Platform().HubRegister(wc)
+	wc.Pump()
+}
+
+// pollWebSocketEvents returns the events of the WebSocket as soon as some are
+// available, or an empty list after the timeout. Each request resumes the
+// connection of the previous one, so that no event is lost between requests.
+func pollWebSocketEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	timeout := longPollDefaultTimeout
+	if s := r.URL.Query().Get(timeoutParam); s != "" {
+		seconds, err := strconv.Atoi(s)
+		if err != nil || seconds <= 0 {
+			c.SetInvalidURLParam(timeoutParam)
+			return
+		}
+		timeout = min(time.Duration(seconds)*time.Second, longPollMaxTimeout)
+	}
+
+	cfg, err := newWebConnConfig(c, r, r.URL.Query().Get(connectionIDParam), r.URL.Query().Get(sequenceNumberParam))
+	if err != nil {
+		c.Err = model.NewAppError("pollWebSocketEvents", "api.web_socket.connect.resume.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		return
+	}
+
+	stream := platform.NewLongPollStream(r.Context(), timeout)
+	cfg.Stream = stream
+
+	wc := c.App.Srv().Platform().NewWebConn(cfg, c.App, c.App.Srv().Channels())
+	c.App.Srv().Platform().HubRegister(wc)
+	wc.Pump()
+
+	if err := stream.WriteResponse(w); err != nil {
+		c.Logger.Debug("Failed to write the polled events", mlog.Err(err))
+	}
+}
+
+// newWebConnConfig initializes the configuration of a WebConn, resuming the
+// given connection if it's still known to the hub.
+func newWebConnConfig(c *Context, r *http.Request, connectionID, sequenceNumber string) (*platform.WebConnConfig, error) {
 	// We initialize webconn with all the necessary data.
 	// If the queues are empty, they are initialized in the constructor.
 	cfg := &platform.WebConnConfig{
-		WebSocket:     ws,
 		Session:       *c.AppContext.Session(),
 		TFunc:         c.AppContext.T,
 		Locale:        "",
@@ -61,25 +152,20 @@ func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
 		cfg.OriginClient = string(web.GetOriginClient(r))
 	}
 
-	cfg.ConnectionID = r.URL.Query().Get(connectionIDParam)
+	// The event log position is only a hint to replay missed events, an
+	// invalid one is the same as a fresh connection.
+	if logSeq, err := strconv.ParseInt(r.URL.Query().Get(logSequenceParam), 10, 64); err == nil && logSeq > 0 {
+		cfg.LogSequence = logSeq
+	}
+
+	cfg.ConnectionID = connectionID
 	if cfg.ConnectionID == "" || c.AppContext.Session().UserId == "" {
 		// If not present, we assume client is not capable yet, or it's a fresh connection.
 		// We just create a new ID.
 		cfg.ConnectionID = model.NewId()
 		// In case of fresh connection id, sequence number is already zero.
-	} else {
-		cfg, err = c.App.Srv().Platform().PopulateWebConnConfig(c.AppContext.Session(), cfg, r.URL.Query().Get(sequenceNumberParam))
-		if err != nil {
-			c.Logger.Warn("Error while populating webconn config", mlog.String("id", r.URL.Query().Get(connectionIDParam)), mlog.Err(err))
-			ws.Close()
-			return
-		}
+		return cfg, nil
 	}
 
-	wc := c.App.Srv().Platform().NewWebConn(cfg, c.App, c.App.Srv().Channels())
-	if c.AppContext.Session().UserId != "" {
-		c.App.Srv().Platform().HubRegister(wc)
-	}
-
-	wc.Pump()
+	return c.App.Srv().Platform().PopulateWebConnConfig(c.AppContext.Session(), cfg, sequenceNumber)
 }
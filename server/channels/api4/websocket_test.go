@@ -4,7 +4,10 @@
 package api4
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -467,3 +470,191 @@ func TestWebSocketUpgrade(t *testing.T) {
 	require.NoError(t, th.TestLogger.Flush())
 	testlib.AssertLog(t, buffer, mlog.LvlDebug.Name, "URL Blocked because of CORS. Url: ")
 }
+
+func TestWebSocketLongPoll(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	poll := func(t *testing.T, query string) []*model.WebSocketEvent {
+		t.Helper()
+		resp, err := th.Client.DoAPIGet(context.Background(), "/websocket/poll?"+query, "")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var raw []json.RawMessage
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+		events := make([]*model.WebSocketEvent, 0, len(raw))
+		for _, r := range raw {
+			evt, err := model.WebSocketEventFromJSON(bytes.NewReader(r))
+			require.NoError(t, err)
+			events = append(events, evt)
+		}
+		return events
+	}
+
+	events := poll(t, "timeout=5")
+	require.NotEmpty(t, events)
+	require.Equal(t, model.WebsocketEventHello, events[0].EventType())
+	require.EqualValues(t, 0, events[0].GetSequence())
+	connID := events[0].GetData()["connection_id"].(string)
+	require.NotEmpty(t, connID)
+
+	// The events published between two requests are returned by the next one.
+	evt := model.NewWebSocketEvent(model.WebsocketEventTyping, "", th.BasicChannel.Id, "", nil, "")
+	evt.Add("user_id", "somerandomid")
+	th.App.Publish(evt)
+	time.Sleep(300 * time.Millisecond)
+
+	events = poll(t, fmt.Sprintf("timeout=5&connection_id=%s&sequence_number=1", connID))
+	require.Len(t, events, 1)
+	require.Equal(t, model.WebsocketEventTyping, events[0].EventType())
+	require.EqualValues(t, 1, events[0].GetSequence())
+
+	// A request resuming from an older sequence gets the events again.
+	events = poll(t, fmt.Sprintf("timeout=5&connection_id=%s&sequence_number=1", connID))
+	require.Len(t, events, 1)
+	require.Equal(t, model.WebsocketEventTyping, events[0].EventType())
+
+	t.Run("times out without events", func(t *testing.T) {
+		events := poll(t, fmt.Sprintf("timeout=1&connection_id=%s&sequence_number=2", connID))
+		require.Empty(t, events)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		resp, err := th.Client.DoAPIGet(context.Background(), "/websocket/poll?timeout=abc", "")
+		require.Error(t, err)
+		CheckBadRequestStatus(t, model.BuildResponse(resp))
+
+		resp, err = th.Client.DoAPIGet(context.Background(), "/websocket/poll?connection_id="+connID, "")
+		require.Error(t, err)
+		CheckBadRequestStatus(t, model.BuildResponse(resp))
+	})
+
+	t.Run("requires a session", func(t *testing.T) {
+		client := th.CreateClient()
+		resp, err := client.DoAPIGet(context.Background(), "/websocket/poll", "")
+		require.Error(t, err)
+		CheckUnauthorizedStatus(t, model.BuildResponse(resp))
+	})
+}
+
+func TestWebSocketEventStream(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	readEvents := func(t *testing.T, header http.Header, count int) ([]*model.WebSocketEvent, []string) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, th.Client.APIURL+"/websocket/events", nil)
+		require.NoError(t, err)
+		req.Header = header
+		req.Header.Set(model.HeaderAuth, model.HeaderBearer+" "+th.Client.AuthToken)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		var events []*model.WebSocketEvent
+		var ids []string
+		scanner := bufio.NewScanner(resp.Body)
+		for len(events) < count && scanner.Scan() {
+			line := scanner.Text()
+			if id, ok := strings.CutPrefix(line, "id: "); ok {
+				ids = append(ids, id)
+			} else if data, ok := strings.CutPrefix(line, "data: "); ok {
+				evt, err := model.WebSocketEventFromJSON(strings.NewReader(data))
+				require.NoError(t, err)
+				events = append(events, evt)
+			}
+		}
+		require.Len(t, events, count)
+		return events, ids
+	}
+
+	events, ids := readEvents(t, http.Header{}, 1)
+	require.Equal(t, model.WebsocketEventHello, events[0].EventType())
+	connID := events[0].GetData()["connection_id"].(string)
+	require.Equal(t, []string{connID + ":0"}, ids)
+
+	evt := model.NewWebSocketEvent(model.WebsocketEventTyping, "", th.BasicChannel.Id, "", nil, "")
+	evt.Add("user_id", "somerandomid")
+	th.App.Publish(evt)
+	time.Sleep(300 * time.Millisecond)
+
+	// Reconnecting clients resume after the last event they received.
+	events, ids = readEvents(t, http.Header{"Last-Event-Id": []string{connID + ":0"}}, 1)
+	require.Equal(t, model.WebsocketEventTyping, events[0].EventType())
+	require.Equal(t, []string{connID + ":1"}, ids)
+}
+
+func TestWebSocketEventLogReplay(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableWebSocketEventLog = true
+	})
+
+	poll := func(t *testing.T, query string) []*model.WebSocketEvent {
+		t.Helper()
+		resp, err := th.Client.DoAPIGet(context.Background(), "/websocket/poll?"+query, "")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var raw []json.RawMessage
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+		events := make([]*model.WebSocketEvent, 0, len(raw))
+		for _, r := range raw {
+			evt, err := model.WebSocketEventFromJSON(bytes.NewReader(r))
+			require.NoError(t, err)
+			events = append(events, evt)
+		}
+		return events
+	}
+
+	// A fresh connection learns the current position in the log.
+	events := poll(t, "timeout=5")
+	require.NotEmpty(t, events)
+	require.Equal(t, model.WebsocketEventHello, events[0].EventType())
+	logSeq := int64(events[0].GetData()["log_seq"].(float64))
+
+	evt := model.NewWebSocketEvent(model.WebsocketEventPreferencesChanged, "", "", th.BasicUser.Id, nil, "")
+	evt.Add("preferences", "[]")
+	th.App.Publish(evt)
+	th.App.Publish(model.NewWebSocketEvent(model.WebsocketEventPreferencesChanged, "", "", th.BasicUser2.Id, nil, ""))
+
+	// The events are written to the log in the background.
+	require.Eventually(t, func() bool {
+		_, latest, err := th.App.Srv().Store().WebSocketEvent().GetSeqRange()
+		return err == nil && latest >= logSeq+2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	t.Run("replays the missed events to a new connection", func(t *testing.T) {
+		events := poll(t, fmt.Sprintf("timeout=5&log_seq=%d", logSeq))
+		require.NotEmpty(t, events)
+		require.Equal(t, model.WebsocketEventHello, events[0].EventType())
+		require.EqualValues(t, logSeq, events[0].GetData()["log_seq"])
+
+		// Events slightly older than the position are replayed as well, in
+		// case they were logged out of order.
+		var missed []*model.WebSocketEvent
+		for _, evt := range events[1:] {
+			if evt.GetLogSequence() > logSeq {
+				missed = append(missed, evt)
+			}
+		}
+		require.Len(t, missed, 1)
+		require.Equal(t, model.WebsocketEventPreferencesChanged, missed[0].EventType())
+		require.Equal(t, "[]", missed[0].GetData()["preferences"])
+	})
+
+	t.Run("requires a resync from an unknown position", func(t *testing.T) {
+		events := poll(t, fmt.Sprintf("timeout=5&log_seq=%d", logSeq+1000))
+		require.Len(t, events, 2)
+		require.Equal(t, model.WebsocketEventHello, events[0].EventType())
+		require.Equal(t, model.WebsocketEventResyncRequired, events[1].EventType())
+	})
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const (
+	accessControlPoliciesPerPage = 100
+	accessControlMembersPerPage  = 200
+)
+
+func (a *App) GetAccessControlPolicy(channelID string) (*model.AccessControlPolicy, *model.AppError) {
+	policy, err := a.Srv().Store().AccessControlPolicy().Get(channelID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("GetAccessControlPolicy", "app.access_control_policy.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return nil, model.NewAppError("GetAccessControlPolicy", "app.access_control_policy.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return policy, nil
+}
+
+// SaveAccessControlPolicy creates or replaces the policy of a channel, then removes the
+// members who do not match it in the background.
+func (a *App) SaveAccessControlPolicy(rctx request.CTX, policy *model.AccessControlPolicy) (*model.AccessControlPolicy, *model.AppError) {
+	channel, appErr := a.GetChannel(rctx, policy.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if appErr := checkAccessControlPolicyChannel(channel); appErr != nil {
+		return nil, appErr
+	}
+
+	saved, err := a.Srv().Store().AccessControlPolicy().Save(policy)
+	if err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, model.NewAppError("SaveAccessControlPolicy", "app.access_control_policy.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.Srv().Go(func() {
+		if err := a.enforceAccessControlPolicy(rctx, saved, channel); err != nil {
+			rctx.Logger().Warn("Failed to enforce the access control policy", mlog.String("channel_id", channel.Id), mlog.Err(err))
+		}
+	})
+
+	return saved, nil
+}
+
+func (a *App) DeleteAccessControlPolicy(channelID string) *model.AppError {
+	if err := a.Srv().Store().AccessControlPolicy().Delete(channelID); err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return model.NewAppError("DeleteAccessControlPolicy", "app.access_control_policy.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return model.NewAppError("DeleteAccessControlPolicy", "app.access_control_policy.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
+
+// SimulateAccessControlPolicy returns how many of the current members of a channel match
+// an expression, and which ones would be removed if it was the channel's policy.
+func (a *App) SimulateAccessControlPolicy(rctx request.CTX, channelID, expression string) (*model.AccessControlPolicySimulation, *model.AppError) {
+	parsed, appErr := model.ParseAccessControlExpression(expression)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	channel, appErr := a.GetChannel(rctx, channelID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if appErr := checkAccessControlPolicyChannel(channel); appErr != nil {
+		return nil, appErr
+	}
+
+	simulation, err := a.evaluateAccessControlPolicy(rctx, channelID, parsed)
+	if err != nil {
+		return nil, model.NewAppError("SimulateAccessControlPolicy", "app.access_control_policy.simulate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return simulation, nil
+}
+
+// EnforceAccessControlPolicies removes the members of the channels with an access control
+// policy who no longer match it.
+func (a *App) EnforceAccessControlPolicies(rctx request.CTX) error {
+	if !*a.Config().AccessControlSettings.EnableAttributeBasedAccessControl {
+		return nil
+	}
+
+	var multiErr *multierror.Error
+	afterChannelID := ""
+	for {
+		policies, err := a.Srv().Store().AccessControlPolicy().GetAll(afterChannelID, accessControlPoliciesPerPage)
+		if err != nil {
+			return fmt.Errorf("failed to get the access control policies: %w", err)
+		}
+
+		for _, policy := range policies {
+			channel, appErr := a.GetChannel(rctx, policy.ChannelId)
+			if appErr != nil {
+				multiErr = multierror.Append(multiErr, fmt.Errorf("failed to get the channel of the access control policy %s: %w", policy.ChannelId, appErr))
+				continue
+			}
+
+			if err := a.enforceAccessControlPolicy(rctx, policy, channel); err != nil {
+				multiErr = multierror.Append(multiErr, err)
+			}
+		}
+
+		if len(policies) < accessControlPoliciesPerPage {
+			break
+		}
+		afterChannelID = policies[len(policies)-1].ChannelId
+	}
+
+	return multiErr.ErrorOrNil()
+}
+
+func (a *App) enforceAccessControlPolicy(rctx request.CTX, policy *model.AccessControlPolicy, channel *model.Channel) error {
+	if channel.DeleteAt != 0 {
+		return nil
+	}
+
+	parsed, appErr := model.ParseAccessControlExpression(policy.Expression)
+	if appErr != nil {
+		return fmt.Errorf("invalid access control policy for channel %s: %w", channel.Id, appErr)
+	}
+
+	simulation, err := a.evaluateAccessControlPolicy(rctx, channel.Id, parsed)
+	if err != nil {
+		return err
+	}
+
+	var multiErr *multierror.Error
+	for _, userID := range simulation.RemovedUserIds {
+		if appErr := a.RemoveUserFromChannel(rctx, userID, "", channel); appErr != nil {
+			multiErr = multierror.Append(multiErr, fmt.Errorf("failed to remove channel member not matching the access control policy: %w", appErr))
+			continue
+		}
+
+		rctx.Logger().Info("Removed channel member not matching the access control policy", mlog.String("user_id", userID), mlog.String("channel_id", channel.Id))
+	}
+
+	return multiErr.ErrorOrNil()
+}
+
+// evaluateAccessControlPolicy evaluates an expression against the members of a channel.
+// Bots and deactivated users are ignored.
+func (a *App) evaluateAccessControlPolicy(rctx request.CTX, channelID string, expression *model.AccessControlExpression) (*model.AccessControlPolicySimulation, error) {
+	// The fields are loaded once, and the attributes of the members once per page.
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	simulation := &model.AccessControlPolicySimulation{RemovedUserIds: []string{}}
+	for page := 0; ; page++ {
+		members, err := a.Srv().Store().Channel().GetMembers(channelID, page*accessControlMembersPerPage, accessControlMembersPerPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the members of channel %s: %w", channelID, err)
+		}
+
+		userIDs := make([]string, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserId)
+		}
+		users, err := a.Srv().Store().User().GetProfileByIds(context.Background(), userIDs, nil, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the members of channel %s: %w", channelID, err)
+		}
+
+		activeUsers := make([]*model.User, 0, len(users))
+		for _, user := range users {
+			if !user.IsBot && user.DeleteAt == 0 {
+				activeUsers = append(activeUsers, user)
+			}
+		}
+
+		subjects, appErr := a.getAccessControlSubjects(activeUsers, fields)
+		if appErr != nil {
+			return nil, appErr
+		}
+
+		for _, user := range activeUsers {
+			simulation.MemberCount++
+			if expression.Evaluate(subjects[user.Id]) {
+				simulation.MatchingCount++
+			} else {
+				simulation.RemovedUserIds = append(simulation.RemovedUserIds, user.Id)
+			}
+		}
+
+		if len(members) < accessControlMembersPerPage {
+			break
+		}
+	}
+
+	return simulation, nil
+}
+
+// checkAccessControlPolicy returns an error when the channel has an access control policy
+// the user does not match.
+func (a *App) checkAccessControlPolicy(user *model.User, channel *model.Channel) *model.AppError {
+	if !*a.Config().AccessControlSettings.EnableAttributeBasedAccessControl || user.IsBot {
+		return nil
+	}
+
+	policy, err := a.Srv().Store().AccessControlPolicy().Get(channel.Id)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil
+		}
+		return model.NewAppError("checkAccessControlPolicy", "app.access_control_policy.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	parsed, appErr := model.ParseAccessControlExpression(policy.Expression)
+	if appErr != nil {
+		return appErr
+	}
+
+	subject, appErr := a.getAccessControlSubject(user)
+	if appErr != nil {
+		return appErr
+	}
+
+	if !parsed.Evaluate(subject) {
+		return model.NewAppError("checkAccessControlPolicy", "api.channel.add_user_to_channel.access_control.app_error", nil, "user_id="+user.Id+", channel_id="+channel.Id, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+func (a *App) getAccessControlSubject(user *model.User) (*model.AccessControlSubject, *model.AppError) {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	subjects, appErr := a.getAccessControlSubjects([]*model.User{user}, fields)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return subjects[user.Id], nil
+}
+
+// getAccessControlSubjects returns the attributes of several users by user ID, loading
+// their groups and custom profile attributes at once.
+func (a *App) getAccessControlSubjects(users []*model.User, fields []*model.CustomProfileField) (map[string]*model.AccessControlSubject, *model.AppError) {
+	subjects := make(map[string]*model.AccessControlSubject, len(users))
+	if len(users) == 0 {
+		return subjects, nil
+	}
+
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.Id)
+	}
+
+	groups, err := a.Srv().Store().Group().GetByUsers(userIDs)
+	if err != nil {
+		return nil, model.NewAppError("getAccessControlSubjects", "app.select_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	values, err := a.Srv().Store().CustomProfileAttribute().GetValuesForUsers(userIDs)
+	if err != nil {
+		return nil, model.NewAppError("getAccessControlSubjects", "app.custom_profile_attribute.get_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	valuesByUser := make(map[string][]*model.CustomProfileValue, len(users))
+	for _, value := range values {
+		valuesByUser[value.UserId] = append(valuesByUser[value.UserId], value)
+	}
+
+	for _, user := range users {
+		subject := model.NewAccessControlSubject(user, groups[user.Id])
+		subject.AddCustomProfileAttributes(fields, valuesByUser[user.Id])
+		subjects[user.Id] = subject
+	}
+	return subjects, nil
+}
+
+func checkAccessControlPolicyChannel(channel *model.Channel) *model.AppError {
+	if (channel.Type != model.ChannelTypeOpen && channel.Type != model.ChannelTypePrivate) || channel.Name == model.DefaultChannelName {
+		return model.NewAppError("checkAccessControlPolicyChannel", "app.access_control_policy.channel_type.app_error", nil, "channel_id="+channel.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestAccessControlPolicy(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.AccessControlSettings.EnableAttributeBasedAccessControl = true })
+
+	group := th.CreateGroup()
+	setGroupMember := func(t *testing.T, user *model.User, isGroupMember bool) {
+		t.Helper()
+
+		var appErr *model.AppError
+		if isGroupMember {
+			_, appErr = th.App.UpsertGroupMember(group.Id, user.Id)
+		} else {
+			_, appErr = th.App.DeleteGroupMember(group.Id, user.Id)
+		}
+		require.Nil(t, appErr)
+	}
+
+	isMember := func(user *model.User, channel *model.Channel) bool {
+		_, appErr := th.App.GetChannelMember(th.Context, channel.Id, user.Id)
+		return appErr == nil
+	}
+
+	channel := th.CreateChannel(th.Context, th.BasicTeam)
+	th.AddUserToChannel(th.BasicUser2, channel)
+	setGroupMember(t, th.BasicUser2, true)
+	expression := `"` + group.DisplayName + `" in user.groups`
+
+	// Neither the attributes users edit themselves nor the custom groups they join can
+	// satisfy a policy.
+	th.BasicUser.Position = "Engineer"
+	_, appErr := th.App.UpdateUser(th.Context, th.BasicUser, false)
+	require.Nil(t, appErr)
+	customGroup, appErr := th.App.CreateGroup(&model.Group{
+		DisplayName:    group.DisplayName,
+		Name:           model.NewPointer("custom" + model.NewId()),
+		Source:         model.GroupSourceCustom,
+		AllowReference: true,
+	})
+	require.Nil(t, appErr)
+	_, appErr = th.App.UpsertGroupMember(customGroup.Id, th.BasicUser.Id)
+	require.Nil(t, appErr)
+
+	t.Run("simulate", func(t *testing.T) {
+		simulation, appErr := th.App.SimulateAccessControlPolicy(th.Context, channel.Id, expression)
+		require.Nil(t, appErr)
+		assert.Equal(t, 2, simulation.MemberCount)
+		assert.Equal(t, 1, simulation.MatchingCount)
+		assert.Equal(t, []string{th.BasicUser.Id}, simulation.RemovedUserIds)
+
+		_, appErr = th.App.SimulateAccessControlPolicy(th.Context, channel.Id, `user.position == "Engineer"`)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.access_control_policy.expression.app_error", appErr.Id)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		defaultChannel, appErr := th.App.GetChannelByName(th.Context, model.DefaultChannelName, th.BasicTeam.Id, false)
+		require.Nil(t, appErr)
+		_, appErr = th.App.SaveAccessControlPolicy(th.Context, &model.AccessControlPolicy{ChannelId: defaultChannel.Id, CreatorId: th.SystemAdminUser.Id, Expression: expression})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.access_control_policy.channel_type.app_error", appErr.Id)
+
+		policy, appErr := th.App.SaveAccessControlPolicy(th.Context, &model.AccessControlPolicy{ChannelId: channel.Id, CreatorId: th.SystemAdminUser.Id, Expression: expression})
+		require.Nil(t, appErr)
+		assert.Equal(t, expression, policy.Expression)
+
+		require.Eventually(t, func() bool { return !isMember(th.BasicUser, channel) }, 5*time.Second, 100*time.Millisecond)
+		assert.True(t, isMember(th.BasicUser2, channel))
+	})
+
+	t.Run("add members", func(t *testing.T) {
+		_, appErr := th.App.AddUserToChannel(th.Context, th.BasicUser, channel, false)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.channel.add_user_to_channel.access_control.app_error", appErr.Id)
+
+		appErr = th.App.JoinChannel(th.Context, channel, th.BasicUser.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.channel.add_user_to_channel.access_control.app_error", appErr.Id)
+
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.AccessControlSettings.EnableAttributeBasedAccessControl = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.AccessControlSettings.EnableAttributeBasedAccessControl = true })
+		_, appErr = th.App.AddUserToChannel(th.Context, th.BasicUser, channel, false)
+		require.Nil(t, appErr)
+	})
+
+	t.Run("enforce", func(t *testing.T) {
+		require.True(t, isMember(th.BasicUser, channel))
+		setGroupMember(t, th.BasicUser2, false)
+
+		require.NoError(t, th.App.EnforceAccessControlPolicies(th.Context))
+		assert.False(t, isMember(th.BasicUser, channel))
+		assert.False(t, isMember(th.BasicUser2, channel))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.Nil(t, th.App.DeleteAccessControlPolicy(channel.Id))
+
+		_, appErr := th.App.GetAccessControlPolicy(channel.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+		_, appErr = th.App.AddUserToChannel(th.Context, th.BasicUser, channel, false)
+		require.Nil(t, appErr)
+	})
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/app/platform"
 	"github.com/mattermost/mattermost/server/v8/channels/audit"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/config"
 	"github.com/mattermost/mattermost/server/v8/einterfaces"
 	"github.com/mattermost/mattermost/server/v8/platform/services/imageproxy"
 	"github.com/mattermost/mattermost/server/v8/platform/services/remotecluster"
@@ -54,6 +55,42 @@ type AppIface interface {
 	AddPublicKey(name string, key io.Reader) *model.AppError
 	// AddUserToChannel adds a user to a given channel.
 	AddUserToChannel(c request.CTX, user *model.User, channel *model.Channel, skipTeamMemberIntegrityCheck bool) (*model.ChannelMember, *model.AppError)
+	// AttachMfaTrustedDevice binds the session of a login to a trusted device. When the user
+	// completed MFA with the token of a trusted device, the session joins the sessions of the
+	// device. Otherwise, when the user chose to trust the device, a new trusted device is
+	// created and its token is set in a cookie.
+	AttachMfaTrustedDevice(rctx request.CTX, w http.ResponseWriter, r *http.Request, user *model.User, mfaToken string, trustDevice bool) *model.AppError
+	// AuthenticateUserForWebAuthnLogin authenticates a user with a passkey, without password.
+	// The authenticator must have verified the user, e.g. with a PIN or biometrics, which
+	// makes it a multi-factor authentication by itself.
+	AuthenticateUserForWebAuthnLogin(rctx request.CTX, assertion *model.WebAuthnAssertionCredential) (user *model.User, appErr *model.AppError)
+	// AuthorizeOAuthDeviceCode records whether the user allowed or denied the device identified by
+	// the user code. The device gets the token, or the denial, the next time it polls.
+	AuthorizeOAuthDeviceCode(c request.CTX, userID, userCode string, allow bool) *model.AppError
+	// CheckEmailDigestUnsubscribeSignature checks the signature of the unsubscribe
+	// link sent with the digest of the user.
+	CheckEmailDigestUnsubscribeSignature(userID, signature string) *model.AppError
+	// CreateScimGroup provisions a group from its SCIM representation. A deleted group with
+	// the same external id is restored rather than duplicated.
+	CreateScimGroup(c request.CTX, sg *model.ScimGroup) (*model.ScimGroup, *model.AppError)
+	// CreateScimUser provisions a user from its SCIM representation. Users are bound to the
+	// configured authentication service when there is one, and get the given or a random
+	// password otherwise.
+	CreateScimUser(c request.CTX, su *model.ScimUser) (*model.ScimUser, *model.AppError)
+	// DeleteCustomProfileField deletes a field along with the values of all the users.
+	DeleteCustomProfileField(rctx request.CTX, fieldID string) *model.AppError
+	// DeleteScimGroup deletes a group provisioned through SCIM and removes its members from
+	// the group-constrained teams and channels it granted access to.
+	DeleteScimGroup(c request.CTX, groupID string) *model.AppError
+	// DeleteScimUser deprovisions a user. The user is deactivated rather than deleted, so
+	// their content is kept and they remain visible to the identity provider as inactive.
+	DeleteScimUser(c request.CTX, userID string) *model.AppError
+	// DeleteWebAuthnCredential revokes an authenticator of the user. Revoking the last MFA
+	// method of the user deactivates MFA.
+	DeleteWebAuthnCredential(rctx request.CTX, userID, credentialID string) *model.AppError
+	// EnforceAccessControlPolicies removes the members of the channels with an access control
+	// policy who no longer match it.
+	EnforceAccessControlPolicies(rctx request.CTX) error
 	// Caller must close the first return value
 	ExportFileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
 	// Caller must close the first return value
@@ -87,11 +124,72 @@ type AppIface interface {
 	ConvertBotToUser(c request.CTX, bot *model.Bot, userPatch *model.UserPatch, sysadmin bool) (*model.User, *model.AppError)
 	// ConvertUserToBot converts a user to bot.
 	ConvertUserToBot(rctx request.CTX, user *model.User) (*model.Bot, *model.AppError)
+	// GenerateWebAuthnLoginOptions starts an authentication ceremony, to log in without a
+	// password or to complete a login as the second factor. When a login id is given, the
+	// ceremony is restricted to the authenticators of that user.
+	GenerateWebAuthnLoginOptions(rctx request.CTX, loginID string) (*model.WebAuthnRequestOptions, *model.AppError)
+	// GenerateWebAuthnRegistrationOptions starts the registration of a new authenticator
+	// for the user.
+	GenerateWebAuthnRegistrationOptions(rctx request.CTX, userID string) (*model.WebAuthnCreationOptions, *model.AppError)
+	// GetActiveSessions returns the descriptions of the sessions of a user for them to review
+	// the devices they are logged in on, the most recently active first.
+	GetActiveSessions(c request.CTX, userID string) ([]*model.ActiveSession, *model.AppError)
+	// GetConfigVersion returns the configuration persisted with the given version id.
+	GetConfigVersion(versionID string) (*model.Config, *model.AppError)
+	// GetCustomProfileValues returns the values of a user that the viewer is allowed to see
+	// given the visibility of the fields. Admins see every value.
+	GetCustomProfileValues(userID, viewerID string, asAdmin bool) ([]*model.CustomProfileValue, *model.AppError)
+	// GetMfaRecoveryCodesStatus returns the number of unused recovery codes of the user.
+	GetMfaRecoveryCodesStatus(userID string) (*model.MfaRecoveryCodes, *model.AppError)
+	// GetScimGroup returns the SCIM representation of a group provisioned through SCIM.
+	GetScimGroup(groupID string, excludeMembers bool) (*model.ScimGroup, *model.AppError)
+	// GetScimUser returns the SCIM representation of a user. Bots and remote users are not
+	// provisioned through SCIM and are reported as missing.
+	GetScimUser(userID string) (*model.ScimUser, *model.AppError)
+	// PatchCustomProfileField updates a field. The name and the type of a field cannot be
+	// changed since they define the meaning of the existing values.
+	PatchCustomProfileField(rctx request.CTX, fieldID string, patch *model.CustomProfileFieldPatch) (*model.CustomProfileField, *model.AppError)
+	// PatchCustomProfileValues sets the values of a user by field id. Empty values unset the
+	// fields. Only admins can set the values of the admin managed fields and of the fields
+	// only visible to admins.
+	PatchCustomProfileValues(rctx request.CTX, userID string, patch map[string]string, asAdmin bool) ([]*model.CustomProfileValue, *model.AppError)
+	// PatchScimGroup applies the operations of a SCIM PATCH request to a group.
+	PatchScimGroup(c request.CTX, groupID string, operations []*model.ScimPatchOperation) (*model.ScimGroup, *model.AppError)
+	// PatchScimUser applies the operations of a SCIM PATCH request to a user.
+	PatchScimUser(c request.CTX, userID string, operations []*model.ScimPatchOperation) (*model.ScimUser, *model.AppError)
+	// RegenerateMfaRecoveryCodes generates the recovery codes of the user, e.g. once MFA is
+	// activated, replacing the previous ones, after checking the given MFA token, and returns
+	// the new codes.
+	RegenerateMfaRecoveryCodes(rctx request.CTX, userID, token string) (*model.MfaRecoveryCodes, *model.AppError)
+	// RegisterWebAuthnCredential completes the registration of an authenticator. Registering
+	// the first MFA method of the user activates MFA, and generates their recovery codes.
+	RegisterWebAuthnCredential(rctx request.CTX, userID string, registration *model.WebAuthnRegistrationRequest) (*model.WebAuthnRegistrationResult, *model.AppError)
+	// ReplaceScimGroup replaces the attributes and members of a group with its SCIM
+	// representation.
+	ReplaceScimGroup(c request.CTX, groupID string, sg *model.ScimGroup) (*model.ScimGroup, *model.AppError)
+	// ReplaceScimUser replaces the attributes of a user with its SCIM representation.
+	ReplaceScimUser(c request.CTX, userID string, su *model.ScimUser) (*model.ScimUser, *model.AppError)
+	// RevokeMfaTrustedDeviceForSession revokes the trust of the device a session was created
+	// on, so that the next logins on the device require MFA again.
+	RevokeMfaTrustedDeviceForSession(rctx request.CTX, session *model.Session) *model.AppError
+	// SaveAccessControlPolicy creates or replaces the policy of a channel, then removes the
+	// members who do not match it in the background.
+	SaveAccessControlPolicy(rctx request.CTX, policy *model.AccessControlPolicy) (*model.AccessControlPolicy, *model.AppError)
+	// SearchScimGroups returns the page of groups provisioned through SCIM matching a filter,
+	// starting at the 1-based startIndex. An empty filter matches every group.
+	SearchScimGroups(filter string, startIndex, count int, excludeMembers bool) (*model.ScimListResponse, *model.AppError)
+	// SearchScimUsers returns the page of users matching a SCIM filter, starting at the
+	// 1-based startIndex. An empty filter matches every user.
+	SearchScimUsers(filter string, startIndex, count int) (*model.ScimListResponse, *model.AppError)
 	// Create/ Update a subscription history event
 	// This function is run daily to record the number of activated users in the system for Cloud workspaces
 	SendSubscriptionHistoryEvent(userID string) (*model.SubscriptionHistory, error)
 	// CreateBot creates the given bot and corresponding user.
 	CreateBot(rctx request.CTX, bot *model.Bot) (*model.Bot, *model.AppError)
+	// CreateChannelEmailAddress gives the channel an inbound email address. When incoming webhooks
+	// are enabled, the emails of the senders that are not users of the server are posted through
+	// an incoming webhook created for the address.
+	CreateChannelEmailAddress(c request.CTX, channel *model.Channel, creatorID string, patch *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.AppError)
 	// CreateChannelScheme creates a new Scheme of scope channel and assigns it to the channel.
 	CreateChannelScheme(c request.CTX, channel *model.Channel) (*model.Scheme, *model.AppError)
 	// CreateDefaultMemberships adds users to teams and channels based on their group memberships and how those groups
@@ -102,6 +200,9 @@ type AppIface interface {
 	// CreateGuest creates a guest and sets several fields of the returned User struct to
 	// their zero values.
 	CreateGuest(c request.CTX, user *model.User) (*model.User, *model.AppError)
+	// CreateOAuthDeviceCode starts the device authorization grant of RFC 8628 for devices without a
+	// browser, returning the codes the device shows to the user and then polls the token endpoint with.
+	CreateOAuthDeviceCode(c request.CTX, clientId, secret, scope string) (*model.OAuthDeviceAuthorizationResponse, *model.AppError)
 	// CreateUser creates a user and sets several fields of the returned User struct to
 	// their zero values.
 	CreateUser(c request.CTX, user *model.User) (*model.User, *model.AppError)
@@ -118,6 +219,8 @@ type AppIface interface {
 	//
 	//	['town-square', 'game-of-thrones', 'wow']
 	DefaultChannelNames(c request.CTX) []string
+	// DeleteChannelEmailAddress removes the address of the channel along with its incoming webhook.
+	DeleteChannelEmailAddress(c request.CTX, channelID string) *model.AppError
 	// DeleteChannelScheme deletes a channels scheme and sets its SchemeId to nil.
 	DeleteChannelScheme(c request.CTX, channel *model.Channel) (*model.Channel, *model.AppError)
 	// DeleteGroupConstrainedMemberships deletes team and channel memberships of users who aren't members of the allowed
@@ -149,6 +252,9 @@ type AppIface interface {
 	// attributes of the attachment structure. The Slack attachment structure is
 	// documented here: https://api.slack.com/docs/attachments
 	ProcessSlackAttachments(attachments []*model.SlackAttachment) []*model.SlackAttachment
+	// ExportAuditRecords writes all the audit records matching the options to w, most recent
+	// first, as CSV or JSON Lines. The limit of the options is ignored.
+	ExportAuditRecords(opts model.AuditRecordSearchOptions, format string, w io.Writer) *model.AppError
 	// ExtendSessionExpiryIfNeeded extends Session.ExpiresAt based on session lengths in config.
 	// A new ExpiresAt is only written if enough time has elapsed since last update.
 	// Returns true only if the session was extended.
@@ -179,6 +285,11 @@ type AppIface interface {
 	GetClusterPluginStatuses() (model.PluginStatuses, *model.AppError)
 	// GetConfigFile proxies access to the given configuration file to the underlying config store.
 	GetConfigFile(name string) ([]byte, error)
+	// GetConfigHistory returns a page of the past configurations, newest first.
+	GetConfigHistory(page, perPage int) ([]*model.ConfigVersion, *model.AppError)
+	// GetConfigVersionDiff compares two past configurations, without any secrets. An empty
+	// toID compares against the active configuration.
+	GetConfigVersionDiff(fromID, toID string) (config.ConfigDiffs, *model.AppError)
 	// GetEmojiStaticURL returns a relative static URL for system default emojis,
 	// and the API route for custom ones. Errors if not found or if custom and deleted.
 	GetEmojiStaticURL(c request.CTX, emojiName string) (string, *model.AppError)
@@ -207,6 +318,16 @@ type AppIface interface {
 	// GetMarketplacePlugins returns a list of plugins from the marketplace-server,
 	// and plugins that are installed locally.
 	GetMarketplacePlugins(rctx request.CTX, filter *model.MarketplacePluginFilter) ([]*model.MarketplacePlugin, *model.AppError)
+	// GetOAuthAccessTokenForClientCredentials issues a token for the bot user of the app, for
+	// integrations acting on their own behalf rather than on behalf of a user.
+	GetOAuthAccessTokenForClientCredentials(c request.CTX, clientId, secret, scope string) (*model.AccessResponse, *model.AppError)
+	// GetOAuthAccessTokenForDeviceCode is polled by the device until the user allows or denies the
+	// device authorization. The errors of the grant are identified by their id, see
+	// OAuthDeviceCodeErrorCode.
+	GetOAuthAccessTokenForDeviceCode(c request.CTX, clientId, secret, code string) (*model.AccessResponse, *model.AppError)
+	// GetOAuthAppForDeviceCode returns the app requesting access with the user code, so that the
+	// user can check it before approving the request.
+	GetOAuthAppForDeviceCode(userCode string) (*model.OAuthApp, *model.AppError)
 	// GetPluginStatus returns the status for a plugin installed on this server.
 	GetPluginStatus(id string) (*model.PluginStatus, *model.AppError)
 	// GetPluginStatuses returns the status for plugins installed on this server.
@@ -237,6 +358,8 @@ type AppIface interface {
 	GetSessionLengthInMillis(session *model.Session) int64
 	// GetStorageUsage returns the sum of files' sizes stored on this instance
 	GetStorageUsage() (int64, *model.AppError)
+	// GetStorageUsageByTier returns the number and total size of the files stored in each storage tier
+	GetStorageUsageByTier() ([]*model.FileTierUsage, *model.AppError)
 	// GetSuggestions returns suggestions for user input.
 	GetSuggestions(c request.CTX, commandArgs *model.CommandArgs, commands []*model.Command, roleID string) []model.AutocompleteSuggestion
 	// GetTeamGroupUsers returns the users who are associated to the team via GroupTeams and GroupMembers.
@@ -308,6 +431,18 @@ type AppIface interface {
 	PromoteGuestToUser(c request.CTX, user *model.User, requestorId string) *model.AppError
 	// ReattachPlugin allows the server to bind to an existing plugin instance launched elsewhere.
 	ReattachPlugin(manifest *model.Manifest, pluginReattachConfig *model.PluginReattachConfig) *model.AppError
+	// ReceiveInboundEmail posts the email received by the inbound email server for the recipient.
+	ReceiveInboundEmail(c request.CTX, recipient string, data []byte) (*model.Post, *model.AppError)
+	// ReceivePostByEmail posts the email sent to the address of a channel. The post is made by
+	// the sender when their verified email belongs to a user allowed to post to the channel, and
+	// through the incoming webhook of the address otherwise.
+	ReceivePostByEmail(c request.CTX, recipient string, data []byte) (*model.Post, *model.AppError)
+	// ReceiveReplyByEmail posts the reply received by email to the thread of the post
+	// the email notification was sent for, as the user the notification was sent to.
+	ReceiveReplyByEmail(c request.CTX, recipient string, data []byte) (*model.Post, *model.AppError)
+	// RegenerateChannelEmailAddress replaces the address of the channel, so that the
+	// emails sent to the previous one are rejected.
+	RegenerateChannelEmailAddress(channelID string) (*model.ChannelEmailAddress, *model.AppError)
 	// Removes a listener function by the unique ID returned when AddConfigListener was called
 	RemoveConfigListener(id string)
 	// RenameChannel is used to rename the channel Name and the DisplayName fields
@@ -322,10 +457,18 @@ type AppIface interface {
 	RevokeSessionsFromAllUsers() *model.AppError
 	// SaveConfig replaces the active configuration, optionally notifying cluster peers.
 	SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bool) (*model.Config, *model.Config, *model.AppError)
+	// SaveConfigWithAuthor replaces the active configuration, recording the user who made the change.
+	SaveConfigWithAuthor(newCfg *model.Config, sendConfigChangeClusterMessage bool, authorID string) (*model.Config, *model.Config, *model.AppError)
 	// SearchAllChannels returns a list of channels, the total count of the results of the search (if the paginate search option is true), and an error.
 	SearchAllChannels(c request.CTX, term string, opts model.ChannelSearchOpts) (model.ChannelListWithTeamData, int64, *model.AppError)
 	// SearchAllTeams returns a team list and the total count of the results
 	SearchAllTeams(searchOpts *model.TeamSearch) ([]*model.Team, int64, *model.AppError)
+	// SearchAuditRecords returns a page of the audit records persisted to the database, most
+	// recent first, with the cursor of the next page.
+	SearchAuditRecords(opts model.AuditRecordSearchOptions) (*model.AuditRecordList, *model.AppError)
+	// SendEmailDigests sends their digest to the users who subscribed to one and for
+	// whom it is due.
+	SendEmailDigests() error
 	// SessionHasPermissionToChannels returns true only if user has access to all channels.
 	SessionHasPermissionToChannels(c request.CTX, session model.Session, channelIDs []string, permission *model.Permission) bool
 	// SessionHasPermissionToManageBot returns nil if the session has access to manage the given bot.
@@ -347,16 +490,30 @@ type AppIface interface {
 	// status to away if needed. Used by the WS to set status to away if an 'online' device disconnects
 	// while an 'away' device is still connected
 	SetStatusLastActivityAt(userID string, activityAt int64)
+	// SimulateAccessControlPolicy returns how many of the current members of a channel match
+	// an expression, and which ones would be removed if it was the channel's policy.
+	SimulateAccessControlPolicy(rctx request.CTX, channelID, expression string) (*model.AccessControlPolicySimulation, *model.AppError)
+	// SyncCustomProfileAttributes sets the values of the fields synchronized from an identity
+	// provider, either LDAP or SAML, from the attributes of the user in that provider. Values
+	// that are not valid for their field are skipped.
+	SyncCustomProfileAttributes(rctx request.CTX, userID, authService string, attributes map[string]string) *model.AppError
 	// SyncLdap starts an LDAP sync job.
 	// If includeRemovedMembers is true, then members who left or were removed from a team/channel will
 	// be re-added; otherwise, they will not be re-added.
 	SyncLdap(c request.CTX, includeRemovedMembers bool)
+	// SyncLdapCustomProfileAttributes synchronizes the custom profile fields mapped to LDAP
+	// attributes for every active LDAP user. It runs after each LDAP sync job, so that the
+	// values follow the directory for users who don't sign in with their password.
+	SyncLdapCustomProfileAttributes(rctx request.CTX) *model.AppError
 	// SyncPlugins synchronizes the plugins installed locally
 	// with the plugin bundles available in the file store.
 	SyncPlugins() *model.AppError
 	// SyncRolesAndMembership updates the SchemeAdmin status and membership of all of the members of the given
 	// syncable.
 	SyncRolesAndMembership(rctx request.CTX, syncableID string, syncableType model.GroupSyncableType, includeRemovedMembers bool)
+	// SyncSamlCustomProfileAttributes synchronizes the custom profile fields mapped to SAML
+	// attributes from the assertion a user signed in with.
+	SyncSamlCustomProfileAttributes(rctx request.CTX, user *model.User, encodedXML string) *model.AppError
 	// SyncSharedChannel forces a shared channel to send any changed content to all remote clusters.
 	SyncSharedChannel(channelID string) error
 	// SyncSyncableRoles updates the SchemeAdmin field value of the given syncable's members based on the configuration of
@@ -383,6 +540,9 @@ type AppIface interface {
 	CreateZipFileAndAddFiles(fileBackend filestore.FileBackend, fileDatas []model.FileData, zipFileName, directory string) error
 	// This to be used for places we check the users password when they are already logged in
 	DoubleCheckPassword(rctx request.CTX, user *model.User, password string) *model.AppError
+	// UnsubscribeFromEmailDigest turns the email digest of the user off, given the
+	// signature of the unsubscribe link sent with the digest.
+	UnsubscribeFromEmailDigest(c request.CTX, userID, signature string) *model.AppError
 	// UpdateBotActive marks a bot as active or inactive, along with its corresponding user.
 	UpdateBotActive(rctx request.CTX, botUserId string, active bool) (*model.Bot, *model.AppError)
 	// UpdateBotOwner changes a bot's owner to the given value.
@@ -520,12 +680,14 @@ type AppIface interface {
 	CreateChannelWithUser(c request.CTX, channel *model.Channel, userID string) (*model.Channel, *model.AppError)
 	CreateCommand(cmd *model.Command) (*model.Command, *model.AppError)
 	CreateCommandWebhook(commandID string, args *model.CommandArgs) (*model.CommandWebhook, *model.AppError)
+	CreateCustomProfileField(field *model.CustomProfileField) (*model.CustomProfileField, *model.AppError)
 	CreateEmoji(c request.CTX, sessionUserId string, emoji *model.Emoji, multiPartImageData *multipart.Form) (*model.Emoji, *model.AppError)
 	CreateGroup(group *model.Group) (*model.Group, *model.AppError)
 	CreateGroupChannel(c request.CTX, userIDs []string, creatorId string) (*model.Channel, *model.AppError)
 	CreateGroupWithUserIds(group *model.GroupWithUserIds) (*model.Group, *model.AppError)
 	CreateIncomingWebhookForChannel(creatorId string, channel *model.Channel, hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError)
 	CreateJob(c request.CTX, job *model.Job) (*model.Job, *model.AppError)
+	CreateNotificationRule(rule *model.NotificationRule) (*model.NotificationRule, *model.AppError)
 	CreateOAuthApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError)
 	CreateOAuthStateToken(extra string) (*model.Token, *model.AppError)
 	CreateOAuthUser(c request.CTX, service string, userData io.Reader, teamID string, tokenUser *model.User) (*model.User, *model.AppError)
@@ -558,6 +720,7 @@ type AppIface interface {
 	DeactivateMfa(userID string) *model.AppError
 	DeauthorizeOAuthAppForUser(c request.CTX, userID, appID string) *model.AppError
 	DecryptRemoteClusterInvite(inviteCode, password string) (*model.RemoteClusterInvite, *model.AppError)
+	DeleteAccessControlPolicy(channelID string) *model.AppError
 	DeleteAcknowledgementForPost(c request.CTX, postID, userID string) *model.AppError
 	DeleteAllExpiredPluginKeys() *model.AppError
 	DeleteAllKeysForPlugin(pluginID string) *model.AppError
@@ -574,6 +737,7 @@ type AppIface interface {
 	DeleteGroupMembers(groupID string, userIDs []string) ([]*model.GroupMember, *model.AppError)
 	DeleteGroupSyncable(groupID string, syncableID string, syncableType model.GroupSyncableType) (*model.GroupSyncable, *model.AppError)
 	DeleteIncomingWebhook(hookID string) *model.AppError
+	DeleteNotificationRule(userID, ruleID string) (*model.NotificationRule, *model.AppError)
 	DeleteOAuthApp(rctx request.CTX, appID string) *model.AppError
 	DeleteOutgoingWebhook(hookID string) *model.AppError
 	DeletePluginKey(pluginID string, key string) *model.AppError
@@ -622,6 +786,7 @@ type AppIface interface {
 	GeneratePresignURLForExport(name string) (*model.PresignURLResponse, *model.AppError)
 	GeneratePublicLink(siteURL string, info *model.FileInfo) string
 	GenerateSupportPacket(c request.CTX, options *model.SupportPacketOptions) []model.FileData
+	GetAccessControlPolicy(channelID string) (*model.AccessControlPolicy, *model.AppError)
 	GetAcknowledgementsForPost(postID string) ([]*model.PostAcknowledgement, *model.AppError)
 	GetAcknowledgementsForPostList(postList *model.PostList) (map[string][]*model.PostAcknowledgement, *model.AppError)
 	GetActivePluginManifests() ([]*model.Manifest, *model.AppError)
@@ -649,6 +814,7 @@ type AppIface interface {
 	GetChannelByName(c request.CTX, channelName, teamID string, includeDeleted bool) (*model.Channel, *model.AppError)
 	GetChannelByNameForTeamName(c request.CTX, channelName, teamName string, includeDeleted bool) (*model.Channel, *model.AppError)
 	GetChannelCounts(c request.CTX, teamID string, userID string) (*model.ChannelCounts, *model.AppError)
+	GetChannelEmailAddress(channelID string) (*model.ChannelEmailAddress, *model.AppError)
 	GetChannelFileCount(c request.CTX, channelID string) (int64, *model.AppError)
 	GetChannelGuestCount(c request.CTX, channelID string) (int64, *model.AppError)
 	GetChannelMember(c request.CTX, channelID string, userID string) (*model.ChannelMember, *model.AppError)
@@ -680,6 +846,8 @@ type AppIface interface {
 	GetComplianceReport(reportId string) (*model.Compliance, *model.AppError)
 	GetComplianceReports(page, perPage int) (model.Compliances, *model.AppError)
 	GetCookieDomain() string
+	GetCustomProfileField(fieldID string) (*model.CustomProfileField, *model.AppError)
+	GetCustomProfileFields() ([]*model.CustomProfileField, *model.AppError)
 	GetCustomStatus(userID string) (*model.CustomStatus, *model.AppError)
 	GetDefaultProfileImage(user *model.User) ([]byte, *model.AppError)
 	GetDeletedChannels(c request.CTX, teamID string, offset int, limit int, userID string) (model.ChannelList, *model.AppError)
@@ -738,8 +906,10 @@ type AppIface interface {
 	GetNewUsersForTeamPage(rctx request.CTX, teamID string, page, perPage int, asAdmin bool, viewRestrictions *model.ViewUsersRestrictions) ([]*model.User, *model.AppError)
 	GetNextPostIdFromPostList(postList *model.PostList, collapsedThreads bool) string
 	GetNotificationNameFormat(user *model.User) string
+	GetNotificationRule(ruleID string) (*model.NotificationRule, *model.AppError)
+	GetNotificationRules(userID string) ([]*model.NotificationRule, *model.AppError)
 	GetNumberOfChannelsOnTeam(c request.CTX, teamID string) (int, *model.AppError)
-	GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType, redirectURI, code, secret, refreshToken string) (*model.AccessResponse, *model.AppError)
+	GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType, redirectURI, code, secret, refreshToken, codeVerifier string) (*model.AccessResponse, *model.AppError)
 	GetOAuthAccessTokenForImplicitFlow(c request.CTX, userID string, authRequest *model.AuthorizeRequest) (*model.Session, *model.AppError)
 	GetOAuthApp(appID string) (*model.OAuthApp, *model.AppError)
 	GetOAuthApps(page, perPage int) ([]*model.OAuthApp, *model.AppError)
@@ -899,6 +1069,7 @@ type AppIface interface {
 	GetUsersWithoutTeamPage(options *model.UserGetOptions, asAdmin bool) ([]*model.User, *model.AppError)
 	GetVerifyEmailToken(token string) (*model.Token, *model.AppError)
 	GetViewUsersRestrictions(c request.CTX, userID string) (*model.ViewUsersRestrictions, *model.AppError)
+	GetWebAuthnCredentials(userID string) ([]*model.WebAuthnCredential, *model.AppError)
 	HTTPService() httpservice.HTTPService
 	HandleCommandResponse(c request.CTX, command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool) (*model.CommandResponse, *model.AppError)
 	HandleCommandResponsePost(c request.CTX, command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool) (*model.Post, *model.AppError)
@@ -974,6 +1145,7 @@ type AppIface interface {
 	OriginChecker() func(*http.Request) bool
 	OutgoingOAuthConnections() einterfaces.OutgoingOAuthConnectionInterface
 	PatchChannel(c request.CTX, channel *model.Channel, patch *model.ChannelPatch, userID string) (*model.Channel, *model.AppError)
+	PatchChannelEmailAddress(channelID string, patch *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.AppError)
 	PatchChannelMembersNotifyProps(c request.CTX, members []*model.ChannelMemberIdentifier, notifyProps map[string]string) ([]*model.ChannelMember, *model.AppError)
 	PatchPost(c request.CTX, postID string, patch *model.PostPatch) (*model.Post, *model.AppError)
 	PatchRemoteCluster(rcId string, patch *model.RemoteClusterPatch) (*model.RemoteCluster, *model.AppError)
@@ -1186,6 +1358,7 @@ type AppIface interface {
 	UpdateJobStatus(c request.CTX, job *model.Job, newStatus string) *model.AppError
 	UpdateMfa(c request.CTX, activate bool, userID, token string) *model.AppError
 	UpdateMobileAppBadge(userID string)
+	UpdateNotificationRule(rule *model.NotificationRule) (*model.NotificationRule, *model.AppError)
 	UpdateOAuthApp(oldApp, updatedApp *model.OAuthApp) (*model.OAuthApp, *model.AppError)
 	UpdateOAuthUserAttrs(c request.CTX, userData io.Reader, user *model.User, provider einterfaces.OAuthProvider, service string, tokenUser *model.User) *model.AppError
 	UpdateOutgoingWebhook(c request.CTX, oldHook, updatedHook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.AppError)
@@ -4,6 +4,7 @@
 package app
 
 import (
+	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -145,7 +146,31 @@ func (s *Server) configureAudit(adt *audit.Audit, bAllowAdvancedLogging bool) er
 		cfg.Append(cfgAdditional)
 	}
 
-	return adt.Configure(cfg)
+	if err := adt.Configure(cfg); err != nil {
+		return err
+	}
+
+	auditSettings := s.platform.Config().ExperimentalAuditSettings
+	if auditSettings.IsHashChainEnabled() {
+		if *auditSettings.EnableDatabaseStorage {
+			s.auditRecordPersister = newAuditRecordPersister(s.Store().AuditRecord(), s.Log())
+			adt.OnChainedRecord = s.auditRecordPersister.persist
+		}
+		// The checkpoints are signed with a key held outside of the database and the
+		// configuration, rather than with the signing key of the server stored in the
+		// database, so that whoever can write to them cannot sign a rewritten chain.
+		var signingKey *ecdsa.PrivateKey
+		if keyFile := *auditSettings.CheckpointSigningKeyFile; keyFile != "" {
+			if signingKey, err = audit.LoadSigningKey(keyFile); err != nil {
+				return fmt.Errorf("invalid audit checkpoint signing key, %w", err)
+			}
+		} else {
+			s.Log().Warn("No audit checkpoint signing key configured, the audit checkpoints are not signed.")
+		}
+		adt.SetChain(audit.NewChain(*auditSettings.CheckpointInterval, func() *ecdsa.PrivateKey { return signingKey }))
+	}
+
+	return nil
 }
 
 func (s *Server) onAuditTargetQueueFull(qname string, maxQSize int) bool {
@@ -0,0 +1,179 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// auditRecordPersister saves the hash chained audit records to the database in the
+// background, so that logging an audit record does not wait for the database.
+type auditRecordPersister struct {
+	store   store.AuditRecordStore
+	logger  mlog.LoggerIFace
+	records chan *model.AuditRecord
+	done    chan struct{}
+}
+
+func newAuditRecordPersister(auditRecordStore store.AuditRecordStore, logger mlog.LoggerIFace) *auditRecordPersister {
+	p := &auditRecordPersister{
+		store:   auditRecordStore,
+		logger:  logger,
+		records: make(chan *model.AuditRecord, audit.DefMaxQueueSize),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// persist queues the record to be saved. It is dropped from the database, but still
+// logged, when the queue is full.
+func (p *auditRecordPersister) persist(rec *audit.ChainedRecord) {
+	select {
+	case p.records <- newAuditRecord(rec):
+	default:
+		p.logger.Error("Audit record queue full, dropping record from the database.", mlog.String("chain_id", rec.ChainID), mlog.Int("seq", rec.Sequence))
+	}
+}
+
+// stop saves the queued records. No record can be persisted afterwards.
+func (p *auditRecordPersister) stop() {
+	close(p.records)
+	<-p.done
+}
+
+func (p *auditRecordPersister) run() {
+	defer close(p.done)
+	for record := range p.records {
+		if _, err := p.store.Save(record); err != nil {
+			p.logger.Error("Failed to save audit record", mlog.String("chain_id", record.ChainId), mlog.Int("seq", record.Sequence), mlog.Err(err))
+		}
+	}
+}
+
+func newAuditRecord(rec *audit.ChainedRecord) *model.AuditRecord {
+	return &model.AuditRecord{
+		ChainId:    rec.ChainID,
+		Sequence:   rec.Sequence,
+		CreateAt:   rec.CreateAt,
+		EventName:  rec.EventName,
+		Status:     rec.Status,
+		UserId:     rec.Actor.UserId,
+		SessionId:  rec.Actor.SessionId,
+		IpAddress:  rec.Actor.IpAddress,
+		ObjectType: rec.EventData.ObjectType,
+		PrevHash:   rec.PrevHash,
+		Hash:       rec.Hash,
+		Signature:  rec.Signature,
+		Data:       rec.Payload,
+	}
+}
+
+func (a *App) checkAuditRecordStorageEnabled(where string) *model.AppError {
+	if !*a.Config().ExperimentalAuditSettings.EnableDatabaseStorage {
+		return model.NewAppError(where, "app.audit_record.storage_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// SearchAuditRecords returns a page of the audit records persisted to the database, most
+// recent first, with the cursor of the next page.
+func (a *App) SearchAuditRecords(opts model.AuditRecordSearchOptions) (*model.AuditRecordList, *model.AppError) {
+	if appErr := a.checkAuditRecordStorageEnabled("SearchAuditRecords"); appErr != nil {
+		return nil, appErr
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = model.AuditRecordSearchDefaultLimit
+	}
+	opts.Limit = min(opts.Limit, model.AuditRecordSearchMaxLimit)
+
+	records, err := a.Srv().Store().AuditRecord().Search(opts)
+	if err != nil {
+		return nil, model.NewAppError("SearchAuditRecords", "app.audit_record.search.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	list := &model.AuditRecordList{Records: records}
+	if len(records) == opts.Limit {
+		last := records[len(records)-1]
+		list.NextCursor = model.AuditRecordCursor{CreateAt: last.CreateAt, Id: last.Id}.Encode()
+	}
+	return list, nil
+}
+
+// ExportAuditRecords writes all the audit records matching the options to w, most recent
+// first, as CSV or JSON Lines. The limit of the options is ignored.
+func (a *App) ExportAuditRecords(opts model.AuditRecordSearchOptions, format string, w io.Writer) *model.AppError {
+	if appErr := a.checkAuditRecordStorageEnabled("ExportAuditRecords"); appErr != nil {
+		return appErr
+	}
+
+	var write func(record *model.AuditRecord) error
+	var flush func() error
+	switch format {
+	case model.AuditRecordExportFormatCSV:
+		// The header is written with the first page, so that nothing is written when the
+		// records cannot be retrieved, and the response can still report the error.
+		csvWriter := csv.NewWriter(w)
+		headerWritten := false
+		writeHeader := func() error {
+			if headerWritten {
+				return nil
+			}
+			headerWritten = true
+			return csvWriter.Write(model.AuditRecordCSVHeader)
+		}
+		write = func(record *model.AuditRecord) error {
+			if err := writeHeader(); err != nil {
+				return err
+			}
+			return csvWriter.Write(record.CSVRow())
+		}
+		flush = func() error {
+			// An export without records still has the header.
+			if err := writeHeader(); err != nil {
+				return err
+			}
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	case model.AuditRecordExportFormatJSONL:
+		encoder := json.NewEncoder(w)
+		write = func(record *model.AuditRecord) error { return encoder.Encode(record) }
+		flush = func() error { return nil }
+	default:
+		return model.NewAppError("ExportAuditRecords", "app.audit_record.export.format.app_error", map[string]any{"Format": format}, "", http.StatusBadRequest)
+	}
+
+	opts.Limit = model.AuditRecordSearchMaxLimit
+	for {
+		records, err := a.Srv().Store().AuditRecord().Search(opts)
+		if err != nil {
+			return model.NewAppError("ExportAuditRecords", "app.audit_record.search.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		for _, record := range records {
+			if err := write(record); err != nil {
+				return model.NewAppError("ExportAuditRecords", "app.audit_record.export.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+			}
+		}
+		if err := flush(); err != nil {
+			return model.NewAppError("ExportAuditRecords", "app.audit_record.export.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		if len(records) < opts.Limit {
+			return nil
+		}
+		last := records[len(records)-1]
+		opts.Cursor = model.AuditRecordCursor{CreateAt: last.CreateAt, Id: last.Id}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+)
+
+func TestAuditRecordPersister(t *testing.T) {
+	auditRecordStore := &mocks.AuditRecordStore{}
+	var saved []*model.AuditRecord
+	auditRecordStore.On("Save", mock.AnythingOfType("*model.AuditRecord")).Return(func(record *model.AuditRecord) (*model.AuditRecord, error) {
+		saved = append(saved, record)
+		return record, nil
+	})
+
+	persister := newAuditRecordPersister(auditRecordStore, mlog.CreateConsoleTestLogger(t))
+	chain := audit.NewChain(1, nil)
+
+	rec := audit.Record{
+		EventName: "updateUser",
+		Status:    audit.Success,
+		Actor:     audit.EventActor{UserId: model.NewId(), SessionId: model.NewId(), IpAddress: "10.0.0.1"},
+		EventData: audit.EventData{ObjectType: "user"},
+	}
+	chained, err := chain.Link(mlog.LvlAuditAPI, rec)
+	require.NoError(t, err)
+	persister.persist(chained)
+
+	checkpoint, _, err := chain.Checkpoint()
+	require.NoError(t, err)
+	persister.persist(checkpoint)
+
+	persister.stop()
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, &model.AuditRecord{
+		ChainId:    chain.ID(),
+		Sequence:   1,
+		CreateAt:   chained.CreateAt,
+		EventName:  "updateUser",
+		Status:     audit.Success,
+		UserId:     rec.Actor.UserId,
+		SessionId:  rec.Actor.SessionId,
+		IpAddress:  "10.0.0.1",
+		ObjectType: "user",
+		Hash:       chained.Hash,
+		Data:       chained.Payload,
+	}, saved[0])
+	assert.Equal(t, audit.EventNameCheckpoint, saved[1].EventName)
+	assert.Equal(t, chained.Hash, saved[1].PrevHash)
+}
@@ -60,6 +60,12 @@ func (a *App) IsPasswordValid(rctx request.CTX, password string) *model.AppError
 }
 
 func (a *App) CheckPasswordAndAllCriteria(rctx request.CTX, user *model.User, password string, mfaToken string) *model.AppError {
+	return a.checkPasswordAndAllCriteria(rctx, user, password, mfaToken, a.CheckUserMfa)
+}
+
+// checkPasswordAndAllCriteria checks the password of a user and the other criteria to
+// authenticate them, with checkMfa checking the MFA token.
+func (a *App) checkPasswordAndAllCriteria(rctx request.CTX, user *model.User, password string, mfaToken string, checkMfa func(request.CTX, *model.User, string) *model.AppError) *model.AppError {
 	if err := a.CheckUserPreflightAuthenticationCriteria(rctx, user, mfaToken); err != nil {
 		return err
 	}
@@ -80,7 +86,7 @@ func (a *App) CheckPasswordAndAllCriteria(rctx request.CTX, user *model.User, pa
 		}
 	}
 
-	if err := a.CheckUserMfa(rctx, user, mfaToken); err != nil {
+	if err := checkMfa(rctx, user, mfaToken); err != nil {
 		// If the mfaToken is not set, we assume the client used this as a pre-flight request to query the server
 		// about the MFA state of the user in question
 		if mfaToken != "" {
@@ -155,7 +161,7 @@ func (a *App) checkLdapUserPasswordAndAllCriteria(rctx request.CTX, ldapId *stri
 		return nil, err
 	}
 
-	if err := a.CheckUserMfa(rctx, ldapUser, mfaToken); err != nil {
+	if err := a.checkUserLoginMfa(rctx, ldapUser, mfaToken); err != nil {
 		return nil, err
 	}
 
@@ -163,6 +169,10 @@ func (a *App) checkLdapUserPasswordAndAllCriteria(rctx request.CTX, ldapId *stri
 		return nil, err
 	}
 
+	if err := a.syncLdapCustomProfileAttributes(rctx, ldapUser); err != nil {
+		rctx.Logger().Warn("Failed to synchronize the custom profile attributes from LDAP", mlog.String("user_id", ldapUser.Id), mlog.Err(err))
+	}
+
 	// user successfully authenticated
 	return ldapUser, nil
 }
@@ -212,6 +222,22 @@ func (a *App) CheckUserMfa(rctx request.CTX, user *model.User, token string) *mo
 		return model.NewAppError("CheckUserMfa", "mfa.mfa_disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
 
+	// The token is either a WebAuthn assertion, a recovery code, or a one-time password of
+	// the TOTP authenticator of the user. The token of a trusted device only stands for
+	// MFA when logging in, see checkUserLoginMfa.
+	switch {
+	case token == "":
+		return model.NewAppError("CheckUserMfa", "mfa.validate_token.authenticate.app_error", nil, "", http.StatusBadRequest)
+	case model.IsMfaTrustedDeviceToken(token):
+		return model.NewAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+	case model.IsWebAuthnAssertion(token):
+		return a.checkWebAuthnMfa(rctx, user, token)
+	case model.IsMfaRecoveryCode(token):
+		return a.checkMfaRecoveryCode(rctx, user, token)
+	case user.MfaSecret == "":
+		return model.NewAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+	}
+
 	ok, err := mfa.New(a.Srv().Store().User()).ValidateToken(user.MfaSecret, token)
 	if err != nil {
 		return model.NewAppError("CheckUserMfa", "mfa.validate_token.authenticate.app_error", nil, "", http.StatusBadRequest).Wrap(err)
@@ -224,6 +250,16 @@ func (a *App) CheckUserMfa(rctx request.CTX, user *model.User, token string) *mo
 	return nil
 }
 
+// checkUserLoginMfa checks the MFA token of a user logging in, which may also be the
+// token of a device they trusted.
+func (a *App) checkUserLoginMfa(rctx request.CTX, user *model.User, token string) *model.AppError {
+	if user.MfaActive && *a.Config().ServiceSettings.EnableMultifactorAuthentication && model.IsMfaTrustedDeviceToken(token) {
+		return a.checkMfaTrustedDevice(rctx, user, token)
+	}
+
+	return a.CheckUserMfa(rctx, user, token)
+}
+
 func checkUserLoginAttempts(user *model.User, max int) *model.AppError {
 	if user.FailedAttempts >= max {
 		return model.NewAppError("checkUserLoginAttempts", "api.user.check_user_login_attempts.too_many.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
@@ -275,7 +311,7 @@ func (a *App) authenticateUser(rctx request.CTX, user *model.User, password, mfa
 		return user, err
 	}
 
-	if err := a.CheckPasswordAndAllCriteria(rctx, user, password, mfaToken); err != nil {
+	if err := a.checkPasswordAndAllCriteria(rctx, user, password, mfaToken, a.checkUserLoginMfa); err != nil {
 		if err.Id == "api.user.check_user_password.invalid.app_error" {
 			rctx.Logger().LogM(mlog.MlvlLDAPInfo, "A user tried to sign in, which matched a Mattermost account, but the password was incorrect.", mlog.String("username", user.Username))
 		}
@@ -284,6 +320,13 @@ func (a *App) authenticateUser(rctx request.CTX, user *model.User, password, mfa
 		return user, err
 	}
 
+	a.upgradePasswordHash(rctx, user, password)
+
+	// An expired password is only good for resetting it.
+	if a.isPasswordExpired(user) {
+		return user, model.NewAppError("login", "api.user.check_user_password.expired.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
+	}
+
 	return user, nil
 }
 
@@ -18,6 +18,9 @@ func (a *App) SessionHasPermissionTo(session model.Session, permission *model.Pe
 	if session.IsUnrestricted() {
 		return true
 	}
+	if scope := session.GetUserAccessTokenScope(); scope != nil && !scope.AllowsSystemPermission(permission.Id) {
+		return false
+	}
 	return a.RolesGrantPermission(session.GetUserRoles(), permission.Id)
 }
 
@@ -37,6 +40,9 @@ func (a *App) SessionHasPermissionToTeam(session model.Session, teamID string, p
 	if session.IsUnrestricted() {
 		return true
 	}
+	if scope := session.GetUserAccessTokenScope(); scope != nil && (!scope.AllowsPermission(permission.Id) || !scope.AllowsTeam(teamID)) {
+		return false
+	}
 
 	teamMember := session.GetTeamByTeamId(teamID)
 	if teamMember != nil {
@@ -60,8 +66,20 @@ func (a *App) SessionHasPermissionToTeams(c request.CTX, session model.Session,
 		}
 	}
 
-	// Check session permission, if it allows access, no need to check teams.
-	if a.SessionHasPermissionTo(session, permission) {
+	if scope := session.GetUserAccessTokenScope(); scope != nil {
+		if !scope.AllowsPermission(permission.Id) {
+			return false
+		}
+		for _, teamID := range teamIDs {
+			if !scope.AllowsTeam(teamID) {
+				return false
+			}
+		}
+	}
+
+	// Check session permission, if it allows access, no need to check teams. The scope
+	// of the session was checked for the teams above.
+	if session.IsUnrestricted() || a.RolesGrantPermission(session.GetUserRoles(), permission.Id) {
 		return true
 	}
 	for _, teamID := range teamIDs {
@@ -82,6 +100,10 @@ func (a *App) SessionHasPermissionToChannel(c request.CTX, session model.Session
 		return false
 	}
 
+	if !a.sessionScopeAllowsChannel(c, session, channelID, permission) {
+		return false
+	}
+
 	ids, err := a.Srv().Store().Channel().GetAllChannelMembersForUser(c, session.UserId, true, true)
 	var channelRoles []string
 	if err == nil {
@@ -106,7 +128,8 @@ func (a *App) SessionHasPermissionToChannel(c request.CTX, session model.Session
 		return a.SessionHasPermissionToTeam(session, channel.TeamId, permission)
 	}
 
-	return a.SessionHasPermissionTo(session, permission)
+	// The scope of the session was checked for the channel above.
+	return a.RolesGrantPermission(session.GetUserRoles(), permission.Id)
 }
 
 // SessionHasPermissionToChannels returns true only if user has access to all channels.
@@ -119,11 +142,14 @@ func (a *App) SessionHasPermissionToChannels(c request.CTX, session model.Sessio
 		if channelID == "" {
 			return false
 		}
+		if !a.sessionScopeAllowsChannel(c, session, channelID, permission) {
+			return false
+		}
 	}
 
 	// if System Roles (ie. Admin, TeamAdmin) allow permissions
 	// if so, no reason to check team
-	if a.SessionHasPermissionTo(session, permission) {
+	if session.IsUnrestricted() || a.RolesGrantPermission(session.GetUserRoles(), permission.Id) {
 		// make sure all channels exist, otherwise return false.
 		for _, channelID := range channelIDs {
 			_, appErr := a.GetChannel(c, channelID)
@@ -151,7 +177,29 @@ func (a *App) SessionHasPermissionToChannels(c request.CTX, session model.Sessio
 	return true
 }
 
+// sessionScopeAllowsChannel returns whether the scope of the user access token of a
+// session, if any, allows a permission in a channel.
+func (a *App) sessionScopeAllowsChannel(c request.CTX, session model.Session, channelID string, permission *model.Permission) bool {
+	scope := session.GetUserAccessTokenScope()
+	if scope == nil {
+		return true
+	}
+	if !scope.AllowsPermission(permission.Id) {
+		return false
+	}
+
+	channel, appErr := a.GetChannel(c, channelID)
+	if appErr != nil {
+		return false
+	}
+	return scope.AllowsChannel(channel.Id, channel.TeamId)
+}
+
 func (a *App) SessionHasPermissionToGroup(session model.Session, groupID string, permission *model.Permission) bool {
+	if scope := session.GetUserAccessTokenScope(); scope != nil && !scope.AllowsPermission(permission.Id) {
+		return false
+	}
+
 	groupMember, err := a.Srv().Store().Group().GetMember(groupID, session.UserId)
 	// don't reject immediately on ErrNoRows error because there's further authz logic below for non-groupmembers
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -175,6 +223,13 @@ func (a *App) SessionHasPermissionToChannelByPost(session model.Session, postID
 		return false
 	}
 
+	if scope := session.GetUserAccessTokenScope(); scope != nil {
+		channel, err := a.Srv().Store().Channel().GetForPost(postID)
+		if err != nil || !scope.AllowsPermission(permission.Id) || !scope.AllowsChannel(channel.Id, channel.TeamId) {
+			return false
+		}
+	}
+
 	if channelMember, err := a.Srv().Store().Channel().GetMemberForPost(postID, session.UserId, *a.Config().TeamSettings.ExperimentalViewArchivedChannels); err == nil {
 		if a.RolesGrantPermission(channelMember.GetRoles(), permission.Id) {
 			return true
@@ -194,6 +249,9 @@ func (a *App) SessionHasPermissionToCategory(c request.CTX, session model.Sessio
 	if a.SessionHasPermissionTo(session, model.PermissionEditOtherUsers) {
 		return true
 	}
+	if scope := session.GetUserAccessTokenScope(); scope != nil && (!scope.AllowsUserAccount() || !scope.AllowsTeam(teamID)) {
+		return false
+	}
 	category, err := a.GetSidebarCategory(c, categoryId)
 	return err == nil && category != nil && category.UserId == session.UserId && category.UserId == userID && category.TeamId == teamID
 }
@@ -207,7 +265,8 @@ func (a *App) SessionHasPermissionToUser(session model.Session, userID string) b
 	}
 
 	if session.UserId == userID {
-		return true
+		scope := session.GetUserAccessTokenScope()
+		return scope == nil || scope.AllowsUserAccount()
 	}
 
 	if a.SessionHasPermissionTo(session, model.PermissionEditOtherUsers) {
@@ -375,6 +434,9 @@ func (a *App) SessionHasPermissionToReadChannel(c request.CTX, session model.Ses
 	if session.IsUnrestricted() {
 		return true
 	}
+	if scope := session.GetUserAccessTokenScope(); scope != nil && (!scope.AllowsPermission(model.PermissionReadChannelContent.Id) || !scope.AllowsChannel(channel.Id, channel.TeamId)) {
+		return false
+	}
 
 	return a.HasPermissionToReadChannel(c, session.UserId, channel)
 }
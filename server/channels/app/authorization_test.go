@@ -6,6 +6,7 @@ package app
 import (
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -771,3 +772,78 @@ func TestHasPermissionToChannelByPost(t *testing.T) {
 		require.Equal(t, true, th.App.HasPermissionToChannelByPost(th.Context, th.SystemAdminUser.Id, post.Id, model.PermissionReadChannel))
 	})
 }
+
+func TestSessionHasPermissionWithUserAccessTokenScope(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	otherChannel := th.CreateChannel(th.Context, th.BasicTeam)
+	otherTeam := th.CreateTeam()
+
+	newSession := func(scope *model.UserAccessTokenScope) model.Session {
+		session := model.Session{
+			UserId: th.BasicUser.Id,
+			Roles:  model.SystemUserRoleId,
+			TeamMembers: []*model.TeamMember{
+				{TeamId: th.BasicTeam.Id, Roles: model.TeamUserRoleId, SchemeUser: true},
+			},
+		}
+		session.AddProp(model.SessionPropType, model.SessionTypeUserAccessToken)
+		if scope != nil {
+			buf, err := json.Marshal(scope)
+			require.NoError(t, err)
+			session.AddProp(model.SessionPropUserAccessTokenScope, string(buf))
+		}
+		return session
+	}
+
+	t.Run("unscoped token has the permissions of the user", func(t *testing.T) {
+		session := newSession(nil)
+		assert.True(t, th.App.SessionHasPermissionToChannel(th.Context, session, th.BasicChannel.Id, model.PermissionCreatePost))
+		assert.True(t, th.App.SessionHasPermissionToChannel(th.Context, session, otherChannel.Id, model.PermissionCreatePost))
+		assert.True(t, th.App.SessionHasPermissionToUser(session, th.BasicUser.Id))
+	})
+
+	t.Run("token restricted to a permission in a channel", func(t *testing.T) {
+		session := newSession(&model.UserAccessTokenScope{
+			Permissions: []string{model.PermissionCreatePost.Id},
+			ChannelIds:  []string{th.BasicChannel.Id},
+		})
+		assert.True(t, th.App.SessionHasPermissionToChannel(th.Context, session, th.BasicChannel.Id, model.PermissionCreatePost))
+		assert.False(t, th.App.SessionHasPermissionToChannel(th.Context, session, th.BasicChannel.Id, model.PermissionAddReaction))
+		assert.False(t, th.App.SessionHasPermissionToChannel(th.Context, session, otherChannel.Id, model.PermissionCreatePost))
+		assert.False(t, th.App.SessionHasPermissionToChannels(th.Context, session, []string{th.BasicChannel.Id, otherChannel.Id}, model.PermissionCreatePost))
+		assert.True(t, th.App.SessionHasPermissionToChannelByPost(session, th.BasicPost.Id, model.PermissionCreatePost))
+		assert.False(t, th.App.SessionHasPermissionToUser(session, th.BasicUser.Id))
+		assert.False(t, th.App.SessionHasPermissionTo(session, model.PermissionCreateTeam))
+	})
+
+	t.Run("token restricted to a team", func(t *testing.T) {
+		session := newSession(&model.UserAccessTokenScope{
+			TeamIds: []string{th.BasicTeam.Id},
+		})
+		assert.True(t, th.App.SessionHasPermissionToTeam(session, th.BasicTeam.Id, model.PermissionViewTeam))
+		assert.False(t, th.App.SessionHasPermissionToTeam(session, otherTeam.Id, model.PermissionViewTeam))
+		assert.False(t, th.App.SessionHasPermissionToTeams(th.Context, session, []string{th.BasicTeam.Id, otherTeam.Id}, model.PermissionViewTeam))
+		assert.True(t, th.App.SessionHasPermissionToChannel(th.Context, session, otherChannel.Id, model.PermissionCreatePost))
+		assert.True(t, th.App.SessionHasPermissionToReadChannel(th.Context, session, th.BasicChannel))
+		assert.False(t, th.App.SessionHasPermissionToUser(session, th.BasicUser.Id))
+	})
+
+	t.Run("token of an admin restricted to a channel", func(t *testing.T) {
+		session := newSession(&model.UserAccessTokenScope{
+			ChannelIds: []string{th.BasicChannel.Id},
+		})
+		session.Roles = model.SystemAdminRoleId + " " + model.SystemUserRoleId
+		assert.True(t, th.App.SessionHasPermissionToChannel(th.Context, session, th.BasicChannel.Id, model.PermissionCreatePost))
+		assert.False(t, th.App.SessionHasPermissionTo(session, model.PermissionManageSystem))
+		assert.False(t, th.App.SessionHasPermissionToUser(session, th.BasicUser.Id))
+
+		session = newSession(&model.UserAccessTokenScope{
+			Permissions: []string{model.PermissionManageSystem.Id},
+			ChannelIds:  []string{th.BasicChannel.Id},
+		})
+		session.Roles = model.SystemAdminRoleId + " " + model.SystemUserRoleId
+		assert.True(t, th.App.SessionHasPermissionTo(session, model.PermissionManageSystem))
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/services/tracing"
 )
 
 const (
@@ -523,6 +524,7 @@ func (a *App) DoCommandRequest(rctx request.CTX, cmd *model.Command, p url.Value
 	}
 
 	req.Header.Set("Accept", "application/json")
+	tracing.InjectHTTPHeaders(rctx.Context(), req.Header)
 	if cmd.Token != "" {
 		req.Header.Set("Authorization", "Token "+cmd.Token)
 	}
@@ -7,6 +7,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/json"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/config"
 	"github.com/mattermost/mattermost/server/v8/platform/shared/mail"
 )
 
@@ -230,6 +232,53 @@ func (a *App) SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bo
 	return a.Srv().platform.SaveConfig(newCfg, sendConfigChangeClusterMessage)
 }
 
+// SaveConfigWithAuthor replaces the active configuration, recording the user who made the change.
+func (a *App) SaveConfigWithAuthor(newCfg *model.Config, sendConfigChangeClusterMessage bool, authorID string) (*model.Config, *model.Config, *model.AppError) {
+	return a.Srv().platform.SaveConfigWithAuthor(newCfg, sendConfigChangeClusterMessage, authorID)
+}
+
+func configHistoryAppError(where string, err error) *model.AppError {
+	switch {
+	case errors.Is(err, config.ErrConfigHistoryUnsupported):
+		return model.NewAppError(where, "app.config.history.unsupported.app_error", nil, "", http.StatusNotImplemented).Wrap(err)
+	case errors.Is(err, config.ErrConfigVersionNotFound):
+		return model.NewAppError(where, "app.config.history.version_not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+	default:
+		return model.NewAppError(where, "app.config.history.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+}
+
+// GetConfigHistory returns a page of the past configurations, newest first.
+func (a *App) GetConfigHistory(page, perPage int) ([]*model.ConfigVersion, *model.AppError) {
+	versions, err := a.Srv().platform.GetConfigHistory(page*perPage, perPage)
+	if err != nil {
+		return nil, configHistoryAppError("GetConfigHistory", err)
+	}
+
+	return versions, nil
+}
+
+// GetConfigVersionDiff compares two past configurations, without any secrets. An empty
+// toID compares against the active configuration.
+func (a *App) GetConfigVersionDiff(fromID, toID string) (config.ConfigDiffs, *model.AppError) {
+	diffs, err := a.Srv().platform.DiffConfigVersions(fromID, toID)
+	if err != nil {
+		return nil, configHistoryAppError("GetConfigVersionDiff", err)
+	}
+
+	return diffs.Sanitize(), nil
+}
+
+// GetConfigVersion returns the configuration persisted with the given version id.
+func (a *App) GetConfigVersion(versionID string) (*model.Config, *model.AppError) {
+	cfg, err := a.Srv().platform.GetConfigVersion(versionID)
+	if err != nil {
+		return nil, configHistoryAppError("GetConfigVersion", err)
+	}
+
+	return cfg, nil
+}
+
 func (a *App) HandleMessageExportConfig(cfg *model.Config, appCfg *model.Config) {
 	// If the Message Export feature has been toggled in the System Console, rewrite the ExportFromTimestamp field to an
 	// appropriate value. The rewriting occurs here to ensure it doesn't affect values written to the config file
@@ -0,0 +1,341 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func (a *App) GetCustomProfileFields() ([]*model.CustomProfileField, *model.AppError) {
+	fields, err := a.Srv().Store().CustomProfileAttribute().GetFields(false)
+	if err != nil {
+		return nil, model.NewAppError("GetCustomProfileFields", "app.custom_profile_attribute.get_fields.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return fields, nil
+}
+
+func (a *App) GetCustomProfileField(fieldID string) (*model.CustomProfileField, *model.AppError) {
+	field, err := a.Srv().Store().CustomProfileAttribute().GetField(fieldID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("GetCustomProfileField", "app.custom_profile_attribute.get_field.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return nil, model.NewAppError("GetCustomProfileField", "app.custom_profile_attribute.get_fields.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if field.DeleteAt != 0 {
+		return nil, model.NewAppError("GetCustomProfileField", "app.custom_profile_attribute.get_field.not_found.app_error", nil, "id="+fieldID, http.StatusNotFound)
+	}
+
+	return field, nil
+}
+
+func (a *App) CreateCustomProfileField(field *model.CustomProfileField) (*model.CustomProfileField, *model.AppError) {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return nil, appErr
+	}
+	if len(fields) >= model.CustomProfileFieldsMax {
+		return nil, model.NewAppError("CreateCustomProfileField", "app.custom_profile_attribute.create_field.limit.app_error", map[string]any{"Max": model.CustomProfileFieldsMax}, "", http.StatusBadRequest)
+	}
+
+	field.Id = ""
+	field.DeleteAt = 0
+	if field.IsSynced() {
+		field.AdminManaged = true
+	}
+
+	saved, err := a.Srv().Store().CustomProfileAttribute().SaveField(field)
+	if err != nil {
+		var appErr *model.AppError
+		var conflictErr *store.ErrConflict
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &conflictErr):
+			return nil, model.NewAppError("CreateCustomProfileField", "app.custom_profile_attribute.create_field.exists.app_error", nil, "", http.StatusConflict).Wrap(err)
+		default:
+			return nil, model.NewAppError("CreateCustomProfileField", "app.custom_profile_attribute.save_field.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return saved, nil
+}
+
+// PatchCustomProfileField updates a field. The name and the type of a field cannot be
+// changed since they define the meaning of the existing values.
+func (a *App) PatchCustomProfileField(rctx request.CTX, fieldID string, patch *model.CustomProfileFieldPatch) (*model.CustomProfileField, *model.AppError) {
+	field, appErr := a.GetCustomProfileField(fieldID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	field.Patch(patch)
+	if field.IsSynced() {
+		field.AdminManaged = true
+	}
+
+	updated, err := a.Srv().Store().CustomProfileAttribute().UpdateField(rctx, field)
+	if err != nil {
+		var appErr *model.AppError
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("PatchCustomProfileField", "app.custom_profile_attribute.get_field.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("PatchCustomProfileField", "app.custom_profile_attribute.save_field.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return updated, nil
+}
+
+// DeleteCustomProfileField deletes a field along with the values of all the users.
+func (a *App) DeleteCustomProfileField(rctx request.CTX, fieldID string) *model.AppError {
+	if err := a.Srv().Store().CustomProfileAttribute().DeleteField(rctx, fieldID, model.GetMillis()); err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return model.NewAppError("DeleteCustomProfileField", "app.custom_profile_attribute.get_field.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return model.NewAppError("DeleteCustomProfileField", "app.custom_profile_attribute.delete_field.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
+
+// GetCustomProfileValues returns the values of a user that the viewer is allowed to see
+// given the visibility of the fields. Admins see every value.
+func (a *App) GetCustomProfileValues(userID, viewerID string, asAdmin bool) ([]*model.CustomProfileValue, *model.AppError) {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	values, err := a.Srv().Store().CustomProfileAttribute().GetValuesForUsers([]string{userID})
+	if err != nil {
+		return nil, model.NewAppError("GetCustomProfileValues", "app.custom_profile_attribute.get_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	fieldsByID := make(map[string]*model.CustomProfileField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.Id] = field
+	}
+
+	visible := []*model.CustomProfileValue{}
+	for _, value := range values {
+		if field, ok := fieldsByID[value.FieldId]; ok && canViewCustomProfileField(field, userID, viewerID, asAdmin) {
+			visible = append(visible, value)
+		}
+	}
+
+	return visible, nil
+}
+
+// PatchCustomProfileValues sets the values of a user by field id. Empty values unset the
+// fields. Only admins can set the values of the admin managed fields and of the fields
+// only visible to admins.
+func (a *App) PatchCustomProfileValues(rctx request.CTX, userID string, patch map[string]string, asAdmin bool) ([]*model.CustomProfileValue, *model.AppError) {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	fieldsByID := make(map[string]*model.CustomProfileField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.Id] = field
+	}
+
+	values := make([]*model.CustomProfileValue, 0, len(patch))
+	for fieldID, value := range patch {
+		field, ok := fieldsByID[fieldID]
+		if !ok {
+			return nil, model.NewAppError("PatchCustomProfileValues", "app.custom_profile_attribute.get_field.not_found.app_error", nil, "id="+fieldID, http.StatusNotFound)
+		}
+		if (field.AdminManaged || field.Visibility == model.CustomProfileFieldVisibilityAdmin) && !asAdmin {
+			return nil, model.NewAppError("PatchCustomProfileValues", "app.custom_profile_attribute.patch_values.admin_managed.app_error", map[string]any{"DisplayName": field.DisplayName}, "id="+fieldID, http.StatusForbidden)
+		}
+		if appErr := field.IsValidValue(value); appErr != nil {
+			return nil, appErr
+		}
+
+		values = append(values, &model.CustomProfileValue{FieldId: fieldID, Value: value})
+	}
+
+	if err := a.Srv().Store().CustomProfileAttribute().SaveValues(rctx, userID, values); err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, model.NewAppError("PatchCustomProfileValues", "app.custom_profile_attribute.save_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return a.GetCustomProfileValues(userID, userID, asAdmin)
+}
+
+// SyncCustomProfileAttributes sets the values of the fields synchronized from an identity
+// provider, either LDAP or SAML, from the attributes of the user in that provider. Values
+// that are not valid for their field are skipped.
+func (a *App) SyncCustomProfileAttributes(rctx request.CTX, userID, authService string, attributes map[string]string) *model.AppError {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return appErr
+	}
+
+	values := []*model.CustomProfileValue{}
+	for _, field := range fields {
+		attribute := customProfileFieldAttribute(field, authService)
+		if attribute == "" {
+			continue
+		}
+
+		value := attributes[attribute]
+		if field.Type == model.CustomProfileFieldTypeMultiselect && value != "" {
+			b, err := json.Marshal([]string{value})
+			if err != nil {
+				continue
+			}
+			value = string(b)
+		}
+		if appErr := field.IsValidValue(value); appErr != nil {
+			rctx.Logger().Warn("Skipping invalid synchronized custom profile attribute", mlog.String("user_id", userID), mlog.String("field", field.Name), mlog.String("auth_service", authService))
+			continue
+		}
+
+		values = append(values, &model.CustomProfileValue{FieldId: field.Id, Value: value})
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if err := a.Srv().Store().CustomProfileAttribute().SaveValues(rctx, userID, values); err != nil {
+		return model.NewAppError("SyncCustomProfileAttributes", "app.custom_profile_attribute.save_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
+
+// syncLdapCustomProfileAttributes synchronizes the custom profile fields mapped to LDAP
+// attributes for a user who signed in with LDAP.
+func (a *App) syncLdapCustomProfileAttributes(rctx request.CTX, user *model.User) *model.AppError {
+	if a.Ldap() == nil || user.AuthData == nil {
+		return nil
+	}
+
+	attributes, appErr := a.customProfileFieldAttributes(model.UserAuthServiceLdap)
+	if appErr != nil || len(attributes) == 0 {
+		return appErr
+	}
+
+	ldapAttributes, appErr := a.Ldap().GetUserAttributes(rctx, *user.AuthData, attributes)
+	if appErr != nil {
+		return appErr
+	}
+
+	return a.SyncCustomProfileAttributes(rctx, user.Id, model.UserAuthServiceLdap, ldapAttributes)
+}
+
+// SyncLdapCustomProfileAttributes synchronizes the custom profile fields mapped to LDAP
+// attributes for every active LDAP user. It runs after each LDAP sync job, so that the
+// values follow the directory for users who don't sign in with their password.
+func (a *App) SyncLdapCustomProfileAttributes(rctx request.CTX) *model.AppError {
+	if a.Ldap() == nil {
+		return nil
+	}
+
+	attributes, appErr := a.customProfileFieldAttributes(model.UserAuthServiceLdap)
+	if appErr != nil || len(attributes) == 0 {
+		return appErr
+	}
+
+	users, err := a.Srv().Store().User().GetAllUsingAuthService(model.UserAuthServiceLdap)
+	if err != nil {
+		return model.NewAppError("SyncLdapCustomProfileAttributes", "app.user.get_by_auth.other.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	for _, user := range users {
+		if user.DeleteAt != 0 || user.AuthData == nil {
+			continue
+		}
+
+		ldapAttributes, appErr := a.Ldap().GetUserAttributes(rctx, *user.AuthData, attributes)
+		if appErr == nil {
+			appErr = a.SyncCustomProfileAttributes(rctx, user.Id, model.UserAuthServiceLdap, ldapAttributes)
+		}
+		if appErr != nil {
+			rctx.Logger().Warn("Failed to synchronize the custom profile attributes from LDAP", mlog.String("user_id", user.Id), mlog.Err(appErr))
+		}
+	}
+
+	return nil
+}
+
+// SyncSamlCustomProfileAttributes synchronizes the custom profile fields mapped to SAML
+// attributes from the assertion a user signed in with.
+func (a *App) SyncSamlCustomProfileAttributes(rctx request.CTX, user *model.User, encodedXML string) *model.AppError {
+	if a.Saml() == nil {
+		return nil
+	}
+
+	attributes, appErr := a.customProfileFieldAttributes(model.UserAuthServiceSaml)
+	if appErr != nil || len(attributes) == 0 {
+		return appErr
+	}
+
+	// The response was validated when logging in, so its assertion can be read as is.
+	samlAttributes, err := a.samlAssertionAttributes(encodedXML)
+	if err != nil {
+		return model.NewAppError("SyncSamlCustomProfileAttributes", "app.custom_profile_attribute.saml_attributes.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	return a.SyncCustomProfileAttributes(rctx, user.Id, model.UserAuthServiceSaml, samlAttributes)
+}
+
+// customProfileFieldAttributes returns the attributes of an identity provider the custom
+// profile fields are synchronized from.
+func (a *App) customProfileFieldAttributes(authService string) ([]string, *model.AppError) {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	attributes := []string{}
+	for _, field := range fields {
+		if attribute := customProfileFieldAttribute(field, authService); attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes, nil
+}
+
+func customProfileFieldAttribute(field *model.CustomProfileField, authService string) string {
+	switch authService {
+	case model.UserAuthServiceLdap:
+		return field.LdapAttribute
+	case model.UserAuthServiceSaml:
+		return field.SamlAttribute
+	}
+	return ""
+}
+
+func canViewCustomProfileField(field *model.CustomProfileField, userID, viewerID string, asAdmin bool) bool {
+	switch field.Visibility {
+	case model.CustomProfileFieldVisibilityAll:
+		return true
+	case model.CustomProfileFieldVisibilitySelf:
+		return asAdmin || userID == viewerID
+	default:
+		return asAdmin
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	emocks "github.com/mattermost/mattermost/server/v8/einterfaces/mocks"
+)
+
+func TestCustomProfileFields(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	field, appErr := th.App.CreateCustomProfileField(&model.CustomProfileField{
+		Name:        "department",
+		DisplayName: "Department",
+		Type:        model.CustomProfileFieldTypeSelect,
+		Options:     model.StringArray{"Sales", "Engineering"},
+	})
+	require.Nil(t, appErr)
+	assert.Equal(t, model.CustomProfileFieldVisibilityAll, field.Visibility)
+	assert.False(t, field.AdminManaged)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, appErr := th.App.CreateCustomProfileField(&model.CustomProfileField{Name: "department", DisplayName: "Department", Type: model.CustomProfileFieldTypeText})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		options := []string{"Sales", "Engineering", "Marketing"}
+		ldapAttribute := "departmentNumber"
+		patched, appErr := th.App.PatchCustomProfileField(th.Context, field.Id, &model.CustomProfileFieldPatch{Options: &options, LdapAttribute: &ldapAttribute})
+		require.Nil(t, appErr)
+		assert.Equal(t, model.StringArray(options), patched.Options)
+		assert.True(t, patched.AdminManaged, "synced fields are admin managed")
+		assert.Equal(t, "department", patched.Name)
+
+		_, appErr = th.App.PatchCustomProfileField(th.Context, model.NewId(), &model.CustomProfileFieldPatch{Options: &options})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		other, appErr := th.App.CreateCustomProfileField(&model.CustomProfileField{Name: "location", DisplayName: "Location", Type: model.CustomProfileFieldTypeText})
+		require.Nil(t, appErr)
+
+		require.Nil(t, th.App.DeleteCustomProfileField(th.Context, other.Id))
+
+		fields, appErr := th.App.GetCustomProfileFields()
+		require.Nil(t, appErr)
+		require.Len(t, fields, 1)
+		assert.Equal(t, field.Id, fields[0].Id)
+
+		appErr = th.App.DeleteCustomProfileField(th.Context, other.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+}
+
+func TestCustomProfileValues(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	createField := func(t *testing.T, field *model.CustomProfileField) *model.CustomProfileField {
+		t.Helper()
+
+		created, appErr := th.App.CreateCustomProfileField(field)
+		require.Nil(t, appErr)
+		return created
+	}
+
+	skills := createField(t, &model.CustomProfileField{Name: "skills", DisplayName: "Skills", Type: model.CustomProfileFieldTypeMultiselect, Options: model.StringArray{"Go", "SQL"}})
+	phone := createField(t, &model.CustomProfileField{Name: "phone", DisplayName: "Phone", Type: model.CustomProfileFieldTypeText, Visibility: model.CustomProfileFieldVisibilitySelf})
+	badge := createField(t, &model.CustomProfileField{Name: "badge", DisplayName: "Badge", Type: model.CustomProfileFieldTypeText, AdminManaged: true})
+	department := createField(t, &model.CustomProfileField{Name: "department", DisplayName: "Department", Type: model.CustomProfileFieldTypeText, LdapAttribute: "departmentNumber", SamlAttribute: "Department"})
+
+	userID := th.BasicUser.Id
+
+	t.Run("patch as user", func(t *testing.T) {
+		values, appErr := th.App.PatchCustomProfileValues(th.Context, userID, map[string]string{skills.Id: `["Go"]`, phone.Id: "555-0100"}, false)
+		require.Nil(t, appErr)
+		assert.Len(t, values, 2)
+
+		_, appErr = th.App.PatchCustomProfileValues(th.Context, userID, map[string]string{skills.Id: `["Rust"]`}, false)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.custom_profile_field.is_valid_value.app_error", appErr.Id)
+
+		_, appErr = th.App.PatchCustomProfileValues(th.Context, userID, map[string]string{badge.Id: "1234"}, false)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+
+		_, appErr = th.App.PatchCustomProfileValues(th.Context, userID, map[string]string{department.Id: "Sales"}, false)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+
+		_, appErr = th.App.PatchCustomProfileValues(th.Context, userID, map[string]string{badge.Id: "1234"}, true)
+		require.Nil(t, appErr)
+	})
+
+	t.Run("visibility", func(t *testing.T) {
+		values, appErr := th.App.GetCustomProfileValues(userID, th.BasicUser2.Id, false)
+		require.Nil(t, appErr)
+		fieldIDs := []string{}
+		for _, value := range values {
+			fieldIDs = append(fieldIDs, value.FieldId)
+		}
+		assert.ElementsMatch(t, []string{skills.Id, badge.Id}, fieldIDs)
+
+		values, appErr = th.App.GetCustomProfileValues(userID, userID, false)
+		require.Nil(t, appErr)
+		assert.Len(t, values, 3)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		users, appErr := th.App.SearchUsersInTeam(th.Context, th.BasicTeam.Id, "1234", &model.UserSearchOptions{AllowFullNames: true, Limit: model.UserSearchDefaultLimit})
+		require.Nil(t, appErr)
+		require.Len(t, users, 1)
+		assert.Equal(t, userID, users[0].Id)
+
+		users, appErr = th.App.SearchUsersInTeam(th.Context, th.BasicTeam.Id, "555-0100", &model.UserSearchOptions{AllowFullNames: true, Limit: model.UserSearchDefaultLimit})
+		require.Nil(t, appErr)
+		assert.Empty(t, users, "values of fields not visible to everyone are not searchable")
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		appErr := th.App.SyncCustomProfileAttributes(th.Context, userID, model.UserAuthServiceSaml, map[string]string{"Department": "Sales", "departmentNumber": "42"})
+		require.Nil(t, appErr)
+
+		departmentValue := func(t *testing.T, userID string) string {
+			t.Helper()
+
+			values, appErr := th.App.GetCustomProfileValues(userID, userID, false)
+			require.Nil(t, appErr)
+			for _, value := range values {
+				if value.FieldId == department.Id {
+					return value.Value
+				}
+			}
+			return ""
+		}
+		assert.Equal(t, "Sales", departmentValue(t, userID))
+
+		th.App.Channels().Saml = &emocks.SamlInterface{}
+		defer func() { th.App.Channels().Saml = nil }()
+
+		response := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol"><saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"><saml:AttributeStatement>` +
+			`<saml:Attribute Name="Department"><saml:AttributeValue>Marketing</saml:AttributeValue></saml:Attribute>` +
+			`</saml:AttributeStatement></saml:Assertion></samlp:Response>`
+		appErr = th.App.SyncSamlCustomProfileAttributes(th.Context, &model.User{Id: userID}, base64.StdEncoding.EncodeToString([]byte(response)))
+		require.Nil(t, appErr)
+		assert.Equal(t, "Marketing", departmentValue(t, userID))
+
+		ldapMock := &emocks.LdapInterface{}
+		ldapMock.On("GetUserAttributes", mock.Anything, "ldap-id", []string{"departmentNumber"}).Return(map[string]string{"departmentNumber": "Engineering"}, nil)
+		th.App.Channels().Ldap = ldapMock
+		defer func() { th.App.Channels().Ldap = nil }()
+
+		authData := "ldap-id"
+		appErr = th.App.syncLdapCustomProfileAttributes(th.Context, &model.User{Id: userID, AuthData: &authData})
+		require.Nil(t, appErr)
+		assert.Equal(t, "Engineering", departmentValue(t, userID))
+
+		t.Run("ldap sync job", func(t *testing.T) {
+			ldapUser, err := th.App.Srv().Store().User().Save(th.Context, &model.User{
+				Email:       th.MakeEmail(),
+				Username:    "ldap" + model.NewId(),
+				AuthService: model.UserAuthServiceLdap,
+				AuthData:    model.NewPointer("ldap-" + model.NewId()),
+			})
+			require.NoError(t, err)
+			ldapMock.On("GetUserAttributes", mock.Anything, *ldapUser.AuthData, []string{"departmentNumber"}).Return(map[string]string{"departmentNumber": "Research"}, nil)
+			ldapMock.On("GetUserAttributes", mock.Anything, mock.Anything, []string{"departmentNumber"}).Return(map[string]string{}, nil)
+
+			require.Nil(t, th.App.SyncLdapCustomProfileAttributes(th.Context))
+			assert.Equal(t, "Research", departmentValue(t, ldapUser.Id))
+		})
+	})
+
+	t.Run("access control", func(t *testing.T) {
+		subject, appErr := th.App.getAccessControlSubject(th.BasicUser)
+		require.Nil(t, appErr)
+
+		expression, appErr := model.ParseAccessControlExpression(`user.attributes.department == "Engineering"`)
+		require.Nil(t, appErr)
+		assert.True(t, expression.Evaluate(subject))
+
+		// Users set their own skills, which policies can't rely on.
+		expression, appErr = model.ParseAccessControlExpression(`"go" in user.attributes.skills`)
+		require.Nil(t, appErr)
+		assert.False(t, expression.Evaluate(subject))
+	})
+
+	t.Run("permanent delete", func(t *testing.T) {
+		user := th.CreateUser()
+		_, appErr := th.App.PatchCustomProfileValues(th.Context, user.Id, map[string]string{skills.Id: `["SQL"]`}, false)
+		require.Nil(t, appErr)
+
+		require.Nil(t, th.App.PermanentDeleteUser(th.Context, user))
+
+		values, err := th.App.Srv().Store().CustomProfileAttribute().GetValuesForUsers([]string{user.Id})
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+}
@@ -330,6 +330,35 @@ func (es *Service) SendUserAccessTokenAddedEmail(email, locale, siteURL string)
 	return nil
 }
 
+func (es *Service) SendNewLoginEmail(email, deviceName, country, ipAddress, locale, siteURL string) error {
+	T := i18n.GetUserTranslations(locale)
+
+	subject := T("api.templates.new_login_subject",
+		map[string]any{"SiteName": es.config().TeamSettings.SiteName})
+
+	if country == "" {
+		country = T("api.templates.new_login_body.unknown_country")
+	}
+
+	data := es.NewEmailTemplateData(locale)
+	data.Props["SiteURL"] = siteURL
+	data.Props["Title"] = T("api.templates.new_login_body.title")
+	data.Props["Info"] = T("api.templates.new_login_body.info",
+		map[string]any{"SiteURL": siteURL, "DeviceName": deviceName, "Country": country, "IPAddress": ipAddress})
+	data.Props["Warning"] = T("api.templates.email_warning")
+
+	body, err := es.templatesContainer.RenderToString("password_change_body", data)
+	if err != nil {
+		return err
+	}
+
+	if err := es.sendMail(email, subject, body, "NewLoginEmail"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (es *Service) SendPasswordResetEmail(email string, token *model.Token, locale, siteURL string) (bool, error) {
 	T := i18n.GetUserTranslations(locale)
 
@@ -832,24 +861,28 @@ func (es *Service) sendMailWithCC(to, subject, htmlBody, ccMail, category string
 	return mail.SendMailUsingConfig(to, subject, htmlBody, mailConfig, license != nil && *license.Features.Compliance, "", "", "", ccMail, category)
 }
 
-func (es *Service) SendMailWithEmbeddedFilesAndCustomReplyTo(to, subject, htmlBody, replyToAddress string, embeddedFiles map[string]io.Reader, category string) error {
+func (es *Service) sendMailWithHeaders(to, subject, htmlBody string, headers map[string]string, category string) error {
 	license := es.license()
-	mailConfig := es.mailServiceConfig(replyToAddress)
+	mailConfig := es.mailServiceConfig("")
 
 	category = getSendGridCategory(category, license.IsCloud())
 
-	return mail.SendMailWithEmbeddedFilesUsingConfig(to, subject, htmlBody, embeddedFiles, mailConfig, license != nil && *license.Features.Compliance, "", "", "", "", category)
+	return mail.SendMailWithHeadersUsingConfig(to, subject, htmlBody, headers, mailConfig, category)
 }
 
-func (es *Service) SendMailWithEmbeddedFiles(to, subject, htmlBody string, embeddedFiles map[string]io.Reader, messageID string, inReplyTo string, references string, category string) error {
+func (es *Service) SendMailWithEmbeddedFilesAndCustomReplyTo(to, subject, htmlBody, replyToAddress string, embeddedFiles map[string]io.Reader, messageID string, inReplyTo string, references string, category string) error {
 	license := es.license()
-	mailConfig := es.mailServiceConfig("")
+	mailConfig := es.mailServiceConfig(replyToAddress)
 
 	category = getSendGridCategory(category, license.IsCloud())
 
 	return mail.SendMailWithEmbeddedFilesUsingConfig(to, subject, htmlBody, embeddedFiles, mailConfig, license != nil && *license.Features.Compliance, messageID, inReplyTo, references, "", category)
 }
 
+func (es *Service) SendMailWithEmbeddedFiles(to, subject, htmlBody string, embeddedFiles map[string]io.Reader, messageID string, inReplyTo string, references string, category string) error {
+	return es.SendMailWithEmbeddedFilesAndCustomReplyTo(to, subject, htmlBody, "", embeddedFiles, messageID, inReplyTo, references, category)
+}
+
 func (es *Service) InvalidateVerifyEmailTokensForUser(userID string) *model.AppError {
 	tokens, err := es.store.Token().GetAllTokensByType(TokenTypeVerifyEmail)
 	if err != nil {
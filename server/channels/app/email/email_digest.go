@@ -0,0 +1,278 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package email
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	emailDigestMaxItems          = 10
+	emailDigestMembersPerPage    = 200
+	emailDigestMessageMaxRunes   = 100
+	emailDigestNewChannelsMaxAge = 7 * 24 * 60 * 60 * 1000
+)
+
+type digestItem struct {
+	Title string
+	Info  string
+	URL   string
+}
+
+type digestSection struct {
+	Title string
+	Items []*digestItem
+}
+
+// SendEmailDigest compiles the unread activity of the user since the given time
+// and sends it by email. It returns false when there was nothing to send.
+func (es *Service) SendEmailDigest(user *model.User, frequency string, since int64, unsubscribeURL string) (bool, error) {
+	T := i18n.GetUserTranslations(user.Locale)
+	siteURL := *es.config().ServiceSettings.SiteURL
+
+	sections, err := es.getEmailDigestSections(user, since, siteURL, T)
+	if err != nil {
+		return false, err
+	}
+	if len(sections) == 0 {
+		return false, nil
+	}
+
+	subject := T("app.email_digest."+frequency+".subject", map[string]any{"SiteName": es.config().TeamSettings.SiteName})
+
+	data := es.NewEmailTemplateData(user.Locale)
+	data.Props["SiteURL"] = siteURL
+	data.Props["Title"] = T("app.email_digest.title")
+	data.Props["SubTitle"] = T("app.email_digest."+frequency+".subTitle", map[string]any{"SiteName": es.config().TeamSettings.SiteName})
+	data.Props["Button"] = T("app.email_digest.button")
+	data.Props["ButtonURL"] = siteURL
+	data.Props["Sections"] = sections
+	data.Props["UnsubscribeInfo"] = T("app.email_digest.unsubscribe_info")
+	data.Props["Unsubscribe"] = T("app.email_digest.unsubscribe")
+	data.Props["UnsubscribeURL"] = unsubscribeURL
+
+	body, err := es.templatesContainer.RenderToString("email_digest", data)
+	if err != nil {
+		return false, err
+	}
+
+	// Lets the mail clients unsubscribe in one click, see RFC 8058.
+	headers := map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	if err := es.sendMailWithHeaders(user.Email, subject, body, headers, "EmailDigest"); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (es *Service) getEmailDigestSections(user *model.User, since int64, siteURL string, T i18n.TranslateFunc) ([]*digestSection, error) {
+	fullContents := true
+	if license := es.license(); license != nil && *license.Features.EmailNotificationContents {
+		fullContents = *es.config().EmailSettings.EmailNotificationContentsType == model.EmailNotificationContentsFull
+	}
+
+	members, err := es.getChannelMembersForDigest(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaultTeamName string
+	var mentioned []*model.ChannelMemberWithTeamData
+	for i := range members {
+		member := &members[i]
+		if defaultTeamName == "" && member.TeamName != "" {
+			defaultTeamName = member.TeamName
+		}
+		if member.MentionCount > 0 && !member.IsChannelMuted() && len(mentioned) < emailDigestMaxItems {
+			mentioned = append(mentioned, member)
+		}
+	}
+
+	threads, err := es.store.Thread().GetThreadsForUser(user.Id, "", model.GetUserThreadsOpts{
+		PageSize:    emailDigestMaxItems,
+		Unread:      true,
+		ThreadsOnly: true,
+		Since:       uint64(since),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the followed threads")
+	}
+
+	reacted, err := es.store.Post().GetTopReactedForUserSince(user.Id, since, emailDigestMaxItems)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the top reacted posts")
+	}
+
+	// Channels created before the last digest were already listed in it.
+	newChannelsSince := max(since, model.GetMillis()-emailDigestNewChannelsMaxAge)
+	newChannels, err := es.store.Channel().GetNewPublicChannelsForUser(user.Id, newChannelsSince, emailDigestMaxItems)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the new channels")
+	}
+
+	channelIDs := make([]string, 0, len(mentioned)+len(threads)+len(reacted))
+	for _, member := range mentioned {
+		channelIDs = append(channelIDs, member.ChannelId)
+	}
+	for _, thread := range threads {
+		if thread.Post != nil {
+			channelIDs = append(channelIDs, thread.Post.ChannelId)
+		}
+	}
+	for _, post := range reacted {
+		channelIDs = append(channelIDs, post.Post.ChannelId)
+	}
+
+	channels := map[string]*model.Channel{}
+	if len(channelIDs) > 0 {
+		list, err := es.store.Channel().GetChannelsByIds(model.RemoveDuplicateStrings(channelIDs), false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the channels")
+		}
+		for _, channel := range list {
+			channels[channel.Id] = channel
+		}
+	}
+
+	var sections []*digestSection
+
+	if len(mentioned) > 0 {
+		section := &digestSection{Title: T("app.email_digest.mentions.title")}
+		for _, member := range mentioned {
+			channel := channels[member.ChannelId]
+			if channel == nil {
+				continue
+			}
+			section.Items = append(section.Items, &digestItem{
+				Title: es.getChannelNameForDigest(user, channel),
+				Info:  T("app.email_digest.mentions.info", member.MentionCount, map[string]any{"Count": member.MentionCount}),
+				URL:   es.getChannelURLForDigest(user, channel, member.TeamName, defaultTeamName, siteURL),
+			})
+		}
+		sections = appendDigestSection(sections, section)
+	}
+
+	if len(threads) > 0 {
+		section := &digestSection{Title: T("app.email_digest.threads.title")}
+		for _, thread := range threads {
+			if thread.Post == nil {
+				continue
+			}
+			channel := channels[thread.Post.ChannelId]
+			if channel == nil {
+				continue
+			}
+			section.Items = append(section.Items, &digestItem{
+				Title: es.getPostTitleForDigest(user, thread.Post, channel, fullContents, T),
+				Info:  T("app.email_digest.threads.info", thread.UnreadReplies, map[string]any{"Count": thread.UnreadReplies}),
+				URL:   siteURL + "/_redirect/pl/" + thread.PostId,
+			})
+		}
+		sections = appendDigestSection(sections, section)
+	}
+
+	if len(reacted) > 0 {
+		section := &digestSection{Title: T("app.email_digest.reactions.title")}
+		for _, post := range reacted {
+			channel := channels[post.Post.ChannelId]
+			if channel == nil {
+				continue
+			}
+			section.Items = append(section.Items, &digestItem{
+				Title: es.getPostTitleForDigest(user, post.Post, channel, fullContents, T),
+				Info:  T("app.email_digest.reactions.info", post.ReactionCount, map[string]any{"Count": post.ReactionCount}),
+				URL:   siteURL + "/_redirect/pl/" + post.Post.Id,
+			})
+		}
+		sections = appendDigestSection(sections, section)
+	}
+
+	if len(newChannels) > 0 {
+		section := &digestSection{Title: T("app.email_digest.channels.title")}
+		for _, channel := range newChannels {
+			section.Items = append(section.Items, &digestItem{
+				Title: channel.DisplayName,
+				Info:  T("app.email_digest.channels.info", map[string]any{"TeamName": channel.TeamDisplayName}),
+				URL:   siteURL + "/" + channel.TeamName + "/channels/" + channel.Name,
+			})
+		}
+		sections = appendDigestSection(sections, section)
+	}
+
+	return sections, nil
+}
+
+func appendDigestSection(sections []*digestSection, section *digestSection) []*digestSection {
+	if len(section.Items) == 0 {
+		return sections
+	}
+	return append(sections, section)
+}
+
+func (es *Service) getChannelMembersForDigest(userID string) (model.ChannelMembersWithTeamData, error) {
+	var members model.ChannelMembersWithTeamData
+	for page := 0; ; page++ {
+		list, err := es.store.Channel().GetMembersForUserWithPagination(userID, page, emailDigestMembersPerPage)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the channel members")
+		}
+		members = append(members, list...)
+		if len(list) < emailDigestMembersPerPage {
+			return members, nil
+		}
+	}
+}
+
+func (es *Service) getChannelNameForDigest(user *model.User, channel *model.Channel) string {
+	if channel.Type != model.ChannelTypeDirect {
+		return channel.DisplayName
+	}
+
+	otherUser, err := es.userService.GetUser(channel.GetOtherUserIdForDM(user.Id))
+	if err != nil {
+		mlog.Warn("Unable to find the other user of a direct channel for the email digest", mlog.String("channel_id", channel.Id), mlog.Err(err))
+		return channel.DisplayName
+	}
+	return otherUser.GetDisplayName(*es.config().TeamSettings.TeammateNameDisplay)
+}
+
+func (es *Service) getChannelURLForDigest(user *model.User, channel *model.Channel, teamName, defaultTeamName, siteURL string) string {
+	switch {
+	case teamName != "":
+		return siteURL + "/" + teamName + "/channels/" + channel.Name
+	case defaultTeamName == "":
+		return siteURL
+	case channel.Type == model.ChannelTypeDirect:
+		otherUser, err := es.userService.GetUser(channel.GetOtherUserIdForDM(user.Id))
+		if err != nil {
+			return siteURL
+		}
+		return siteURL + "/" + defaultTeamName + "/messages/@" + otherUser.Username
+	default:
+		return siteURL + "/" + defaultTeamName + "/messages/" + channel.Name
+	}
+}
+
+func (es *Service) getPostTitleForDigest(user *model.User, post *model.Post, channel *model.Channel, fullContents bool, T i18n.TranslateFunc) string {
+	channelName := es.getChannelNameForDigest(user, channel)
+
+	message := strings.Join(strings.Fields(post.Message), " ")
+	if !fullContents || message == "" {
+		return T("app.email_digest.post_in_channel", map[string]any{"ChannelName": channelName})
+	}
+
+	if runes := []rune(message); len(runes) > emailDigestMessageMaxRunes {
+		message = string(runes[:emailDigestMessageMaxRunes]) + "…"
+	}
+	return channelName + ": " + message
+}
@@ -254,6 +254,34 @@ func (_m *ServiceInterface) SendEmailChangeVerifyEmail(newUserEmail string, loca
 	return r0
 }
 
+// SendEmailDigest provides a mock function with given fields: user, frequency, since, unsubscribeURL
+func (_m *ServiceInterface) SendEmailDigest(user *model.User, frequency string, since int64, unsubscribeURL string) (bool, error) {
+	ret := _m.Called(user, frequency, since, unsubscribeURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmailDigest")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.User, string, int64, string) (bool, error)); ok {
+		return rf(user, frequency, since, unsubscribeURL)
+	}
+	if rf, ok := ret.Get(0).(func(*model.User, string, int64, string) bool); ok {
+		r0 = rf(user, frequency, since, unsubscribeURL)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.User, string, int64, string) error); ok {
+		r1 = rf(user, frequency, since, unsubscribeURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendGuestInviteEmails provides a mock function with given fields: team, channels, senderName, senderUserId, senderProfileImage, invites, siteURL, message, errorWhenNotSent, isSystemAdmin, isFirstAdmin
 func (_m *ServiceInterface) SendGuestInviteEmails(team *model.Team, channels []*model.Channel, senderName string, senderUserId string, senderProfileImage []byte, invites []string, siteURL string, message string, errorWhenNotSent bool, isSystemAdmin bool, isFirstAdmin bool) error {
 	ret := _m.Called(team, channels, senderName, senderUserId, senderProfileImage, invites, siteURL, message, errorWhenNotSent, isSystemAdmin, isFirstAdmin)
@@ -374,6 +402,24 @@ func (_m *ServiceInterface) SendMailWithEmbeddedFiles(to string, subject string,
 	return r0
 }
 
+// SendMailWithEmbeddedFilesAndCustomReplyTo provides a mock function with given fields: to, subject, htmlBody, replyToAddress, embeddedFiles, messageID, inReplyTo, references, category
+func (_m *ServiceInterface) SendMailWithEmbeddedFilesAndCustomReplyTo(to string, subject string, htmlBody string, replyToAddress string, embeddedFiles map[string]io.Reader, messageID string, inReplyTo string, references string, category string) error {
+	ret := _m.Called(to, subject, htmlBody, replyToAddress, embeddedFiles, messageID, inReplyTo, references, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMailWithEmbeddedFilesAndCustomReplyTo")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, map[string]io.Reader, string, string, string, string) error); ok {
+		r0 = rf(to, subject, htmlBody, replyToAddress, embeddedFiles, messageID, inReplyTo, references, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendMfaChangeEmail provides a mock function with given fields: _a0, activated, locale, siteURL
 func (_m *ServiceInterface) SendMfaChangeEmail(_a0 string, activated bool, locale string, siteURL string) error {
 	ret := _m.Called(_a0, activated, locale, siteURL)
@@ -392,6 +438,24 @@ func (_m *ServiceInterface) SendMfaChangeEmail(_a0 string, activated bool, local
 	return r0
 }
 
+// SendNewLoginEmail provides a mock function with given fields: _a0, deviceName, country, ipAddress, locale, siteURL
+func (_m *ServiceInterface) SendNewLoginEmail(_a0 string, deviceName string, country string, ipAddress string, locale string, siteURL string) error {
+	ret := _m.Called(_a0, deviceName, country, ipAddress, locale, siteURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendNewLoginEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string) error); ok {
+		r0 = rf(_a0, deviceName, country, ipAddress, locale, siteURL)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendNotificationMail provides a mock function with given fields: to, subject, htmlBody
 func (_m *ServiceInterface) SendNotificationMail(to string, subject string, htmlBody string) error {
 	ret := _m.Called(to, subject, htmlBody)
@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	emailDigestDefaultTime = "08:00"
+	emailDigestDefaultDay  = time.Monday
+
+	// emailDigestCatchUpWindow is how late a digest is still sent, e.g. after a downtime.
+	emailDigestCatchUpWindow = 6 * time.Hour
+
+	emailDigestUnsubscribeAction = "email_digest_unsubscribe"
+)
+
+// SendEmailDigests sends their digest to the users who subscribed to one and for
+// whom it is due.
+func (a *App) SendEmailDigests() error {
+	if !*a.Config().EmailSettings.EnableEmailDigests || !*a.Config().EmailSettings.SendEmailNotifications {
+		return nil
+	}
+
+	subscriptions, err := a.Srv().Store().Preference().GetCategoryAndName(model.PreferenceCategoryNotifications, model.PreferenceNameEmailDigest)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the email digest subscriptions")
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if subscription.Value != model.PreferenceEmailDigestDaily && subscription.Value != model.PreferenceEmailDigestWeekly {
+			continue
+		}
+
+		if err := a.sendEmailDigest(subscription.UserId, subscription.Value, now); err != nil {
+			a.Log().Warn("Failed to send the email digest", mlog.String("user_id", subscription.UserId), mlog.Err(err))
+		}
+	}
+
+	return nil
+}
+
+func (a *App) sendEmailDigest(userID, frequency string, now time.Time) error {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return appErr
+	}
+	if user.DeleteAt != 0 || user.IsBot {
+		return nil
+	}
+
+	preferences, err := a.Srv().Store().Preference().GetCategory(userID, model.PreferenceCategoryNotifications)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the notification preferences")
+	}
+
+	digestTime, digestDay := emailDigestDefaultTime, strconv.Itoa(int(emailDigestDefaultDay))
+	var lastSent int64
+	for _, preference := range preferences {
+		switch preference.Name {
+		case model.PreferenceNameEmailDigestTime:
+			digestTime = preference.Value
+		case model.PreferenceNameEmailDigestDay:
+			digestDay = preference.Value
+		case model.PreferenceNameEmailDigestLastSent:
+			lastSent, _ = strconv.ParseInt(preference.Value, 10, 64)
+		}
+	}
+
+	since, due := getEmailDigestPeriod(frequency, digestTime, digestDay, lastSent, now.In(user.GetTimezoneLocation()))
+	if !due {
+		return nil
+	}
+
+	sent, err := a.Srv().EmailService.SendEmailDigest(user, frequency, since, a.getEmailDigestUnsubscribeURL(userID))
+	if err != nil {
+		return err
+	}
+
+	// An empty digest is marked as sent as well, so that the next one only covers its own period.
+	if err := a.Srv().Store().Preference().Save(model.Preferences{{
+		UserId:   userID,
+		Category: model.PreferenceCategoryNotifications,
+		Name:     model.PreferenceNameEmailDigestLastSent,
+		Value:    strconv.FormatInt(model.GetMillisForTime(now), 10),
+	}}); err != nil {
+		return errors.Wrap(err, "failed to save the time of the email digest")
+	}
+
+	if sent {
+		a.Log().Debug("Sent the email digest", mlog.String("user_id", userID), mlog.String("frequency", frequency))
+	}
+	return nil
+}
+
+// getEmailDigestPeriod returns the start of the period covered by the digest and
+// whether it is due at the given time, in the timezone of the user. A digest is
+// due once it was scheduled and until it is sent, within the catch up window.
+func getEmailDigestPeriod(frequency, digestTime, digestDay string, lastSent int64, now time.Time) (since int64, due bool) {
+	at, err := time.Parse("15:04", digestTime)
+	if err != nil {
+		at, _ = time.Parse("15:04", emailDigestDefaultTime)
+	}
+
+	day := emailDigestDefaultDay
+	if d, err := strconv.Atoi(digestDay); err == nil && d >= int(time.Sunday) && d <= int(time.Saturday) {
+		day = time.Weekday(d)
+	}
+
+	days := 1
+	scheduledAt := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	if frequency == model.PreferenceEmailDigestWeekly {
+		days = 7
+		scheduledAt = scheduledAt.AddDate(0, 0, -int((now.Weekday()-day+7)%7))
+	}
+	if scheduledAt.After(now) {
+		scheduledAt = scheduledAt.AddDate(0, 0, -days)
+	}
+
+	if lastSent >= model.GetMillisForTime(scheduledAt) || now.Sub(scheduledAt) > emailDigestCatchUpWindow {
+		return 0, false
+	}
+
+	return max(lastSent, model.GetMillisForTime(scheduledAt.AddDate(0, 0, -days))), true
+}
+
+func (a *App) getEmailDigestUnsubscribeSignature(userID string) string {
+	mac := hmac.New(sha256.New, a.PostActionCookieSecret())
+	mac.Write([]byte(emailDigestUnsubscribeAction + ":" + userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (a *App) getEmailDigestUnsubscribeURL(userID string) string {
+	return a.GetSiteURL() + model.APIURLSuffix + "/users/" + userID + "/email_digest/unsubscribe?signature=" + url.QueryEscape(a.getEmailDigestUnsubscribeSignature(userID))
+}
+
+// CheckEmailDigestUnsubscribeSignature checks the signature of the unsubscribe
+// link sent with the digest of the user.
+func (a *App) CheckEmailDigestUnsubscribeSignature(userID, signature string) *model.AppError {
+	if !hmac.Equal([]byte(signature), []byte(a.getEmailDigestUnsubscribeSignature(userID))) {
+		return model.NewAppError("CheckEmailDigestUnsubscribeSignature", "app.email_digest.unsubscribe.invalid_signature.app_error", nil, "", http.StatusForbidden)
+	}
+	return nil
+}
+
+// UnsubscribeFromEmailDigest turns the email digest of the user off, given the
+// signature of the unsubscribe link sent with the digest.
+func (a *App) UnsubscribeFromEmailDigest(c request.CTX, userID, signature string) *model.AppError {
+	if appErr := a.CheckEmailDigestUnsubscribeSignature(userID, signature); appErr != nil {
+		return appErr
+	}
+
+	return a.UpdatePreferences(c, userID, model.Preferences{{
+		UserId:   userID,
+		Category: model.PreferenceCategoryNotifications,
+		Name:     model.PreferenceNameEmailDigest,
+		Value:    model.PreferenceEmailDigestOff,
+	}})
+}
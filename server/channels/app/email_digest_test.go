@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestGetEmailDigestPeriod(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone data is not available")
+	}
+
+	millis := func(tm time.Time) int64 { return model.GetMillisForTime(tm) }
+
+	// January 1st, 2024 is a Monday.
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 1, day, hour, minute, 0, 0, newYork) }
+
+	for name, tc := range map[string]struct {
+		frequency string
+		time      string
+		day       string
+		lastSent  int64
+		now       time.Time
+		due       bool
+		since     int64
+	}{
+		"daily, before the time":               {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(3, 7, 59), lastSent: millis(at(2, 8, 0))},
+		"daily, at the time":                   {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(3, 8, 0), lastSent: millis(at(2, 8, 5)), due: true, since: millis(at(2, 8, 5))},
+		"daily, already sent":                  {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(3, 8, 15), lastSent: millis(at(3, 8, 0))},
+		"daily, catching up":                   {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(3, 13, 0), lastSent: millis(at(2, 8, 0)), due: true, since: millis(at(2, 8, 0))},
+		"daily, too late":                      {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(3, 15, 0), lastSent: millis(at(2, 8, 0))},
+		"daily, first digest":                  {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(3, 8, 10), due: true, since: millis(at(2, 8, 0))},
+		"daily, after missed days":             {frequency: model.PreferenceEmailDigestDaily, time: "08:00", now: at(10, 8, 10), lastSent: millis(at(3, 8, 0)), due: true, since: millis(at(9, 8, 0))},
+		"daily, across midnight":               {frequency: model.PreferenceEmailDigestDaily, time: "23:30", now: at(4, 1, 0), lastSent: millis(at(2, 23, 30)), due: true, since: millis(at(2, 23, 30))},
+		"daily, invalid time uses the default": {frequency: model.PreferenceEmailDigestDaily, time: "noon", now: at(3, 8, 10), due: true, since: millis(at(2, 8, 0))},
+		"weekly, another day":                  {frequency: model.PreferenceEmailDigestWeekly, time: "08:00", day: "1", now: at(3, 8, 10), lastSent: millis(at(1, 8, 0))},
+		"weekly, on the day":                   {frequency: model.PreferenceEmailDigestWeekly, time: "08:00", day: "3", now: at(3, 8, 10), due: true, since: millis(at(-4, 8, 0))},
+		"weekly, invalid day uses the default": {frequency: model.PreferenceEmailDigestWeekly, time: "08:00", day: "9", now: at(8, 8, 10), lastSent: millis(at(1, 8, 0)), due: true, since: millis(at(1, 8, 0))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			since, due := getEmailDigestPeriod(tc.frequency, tc.time, tc.day, tc.lastSent, tc.now)
+			assert.Equal(t, tc.due, due)
+			assert.Equal(t, tc.since, since)
+		})
+	}
+}
+
+func TestUnsubscribeFromEmailDigest(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	appErr := th.App.UpdatePreferences(th.Context, th.BasicUser.Id, model.Preferences{{
+		UserId:   th.BasicUser.Id,
+		Category: model.PreferenceCategoryNotifications,
+		Name:     model.PreferenceNameEmailDigest,
+		Value:    model.PreferenceEmailDigestDaily,
+	}})
+	require.Nil(t, appErr)
+
+	getDigest := func() string {
+		preference, appErr := th.App.GetPreferenceByCategoryAndNameForUser(th.Context, th.BasicUser.Id, model.PreferenceCategoryNotifications, model.PreferenceNameEmailDigest)
+		require.Nil(t, appErr)
+		return preference.Value
+	}
+
+	t.Run("rejects the signature of another user", func(t *testing.T) {
+		appErr := th.App.UnsubscribeFromEmailDigest(th.Context, th.BasicUser.Id, th.App.getEmailDigestUnsubscribeSignature(th.BasicUser2.Id))
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+		assert.Equal(t, model.PreferenceEmailDigestDaily, getDigest())
+	})
+
+	t.Run("turns the digest off", func(t *testing.T) {
+		unsubscribeURL, err := url.Parse(th.App.getEmailDigestUnsubscribeURL(th.BasicUser.Id))
+		require.NoError(t, err)
+
+		appErr := th.App.UnsubscribeFromEmailDigest(th.Context, th.BasicUser.Id, unsubscribeURL.Query().Get("signature"))
+		require.Nil(t, appErr)
+		assert.Equal(t, model.PreferenceEmailDigestOff, getDigest())
+	})
+}
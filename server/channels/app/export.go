@@ -399,6 +399,12 @@ func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer,
 	afterId := strings.Repeat("0", 26)
 	cnt := 0
 	profilePictures := []string{}
+
+	customProfileFields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return profilePictures, appErr
+	}
+
 	for {
 		users, err := a.Srv().Store().User().GetAllAfter(1000, afterId)
 
@@ -412,6 +418,11 @@ func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer,
 		cnt += len(users)
 		updateJobProgress(ctx.Logger(), a.Srv().Store(), job, "users_exported", cnt)
 
+		customProfileAttributes, appErr := a.buildUserCustomProfileAttributes(users, customProfileFields)
+		if appErr != nil {
+			return profilePictures, appErr
+		}
+
 		for _, user := range users {
 			afterId = user.Id
 
@@ -474,6 +485,10 @@ func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer,
 				userLine.User.CustomStatus = cs
 			}
 
+			if attributes, ok := customProfileAttributes[user.Id]; ok {
+				userLine.User.CustomProfileAttributes = &attributes
+			}
+
 			// Do the Team Memberships.
 			members, err := a.buildUserTeamAndChannelMemberships(ctx, user.Id, includeArchivedChannels)
 			if err != nil {
@@ -491,6 +506,43 @@ func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer,
 	return profilePictures, nil
 }
 
+// buildUserCustomProfileAttributes returns the custom profile attributes of the users by
+// user id, keyed by field name as in the import format.
+func (a *App) buildUserCustomProfileAttributes(users []*model.User, fields []*model.CustomProfileField) (map[string]map[string]string, *model.AppError) {
+	attributes := map[string]map[string]string{}
+	if len(fields) == 0 {
+		return attributes, nil
+	}
+
+	fieldsByID := make(map[string]*model.CustomProfileField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.Id] = field
+	}
+
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.Id)
+	}
+
+	values, err := a.Srv().Store().CustomProfileAttribute().GetValuesForUsers(userIDs)
+	if err != nil {
+		return nil, model.NewAppError("buildUserCustomProfileAttributes", "app.custom_profile_attribute.get_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	for _, value := range values {
+		field, ok := fieldsByID[value.FieldId]
+		if !ok {
+			continue
+		}
+		if attributes[value.UserId] == nil {
+			attributes[value.UserId] = map[string]string{}
+		}
+		attributes[value.UserId][field.Name] = value.Value
+	}
+
+	return attributes, nil
+}
+
 func (a *App) buildUserTeamAndChannelMemberships(c request.CTX, userID string, includeArchivedChannels bool) (*[]imports.UserTeamImportData, *model.AppError) {
 	var memberships []imports.UserTeamImportData
 
@@ -77,6 +77,14 @@ func connectionTestErrorToAppError(connTestErr error) *model.AppError {
 		return model.NewAppError("TestConnection", "api.file.test_connection_s3_auth.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	case *filestore.S3FileBackendNoBucketError:
 		return model.NewAppError("TestConnection", "api.file.test_connection_s3_bucket_does_not_exist.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	case *filestore.AzureFileBackendAuthError:
+		return model.NewAppError("TestConnection", "api.file.test_connection_azure_auth.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	case *filestore.AzureFileBackendNoContainerError:
+		return model.NewAppError("TestConnection", "api.file.test_connection_azure_container_does_not_exist.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	case *filestore.GCSFileBackendAuthError:
+		return model.NewAppError("TestConnection", "api.file.test_connection_gcs_auth.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	case *filestore.GCSFileBackendNoBucketError:
+		return model.NewAppError("TestConnection", "api.file.test_connection_gcs_bucket_does_not_exist.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	default:
 		return model.NewAppError("TestConnection", "api.file.test_connection.app_error", nil, "", http.StatusInternalServerError).Wrap(connTestErr)
 	}
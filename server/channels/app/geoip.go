@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// geoIPLookup finds the country of IP addresses in a local GeoIP database in the MaxMind
+// DB format, such as GeoLite2 Country or City. The database is loaded again whenever its
+// file, or the path configured for it, changes.
+type geoIPLookup struct {
+	mut     sync.Mutex
+	path    string
+	modTime time.Time
+	reader  *maxminddb.Reader
+}
+
+// geoIPRecord holds the fields of a GeoIP database record the lookup needs. The registered
+// country is the fallback for networks without a country, e.g. anycast ones.
+type geoIPRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+func (g *geoIPLookup) country(path string, addr netip.Addr) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the GeoIP database file")
+	}
+
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	if g.path != path || !g.modTime.Equal(info.ModTime()) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read the GeoIP database file")
+		}
+		reader, err := maxminddb.FromBytes(data)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to load the GeoIP database")
+		}
+		g.path = path
+		g.modTime = info.ModTime()
+		g.reader = reader
+	}
+
+	var record geoIPRecord
+	if err := g.reader.Lookup(net.IP(addr.Unmap().AsSlice()), &record); err != nil {
+		return "", errors.Wrap(err, "failed to look the address up in the GeoIP database")
+	}
+
+	if record.Country.ISOCode != "" {
+		return record.Country.ISOCode, nil
+	}
+	return record.RegisteredCountry.ISOCode, nil
+}
+
+// lookupCountry returns the country an IP address belongs to according to the configured
+// GeoIP database, or an empty string when it is unknown.
+func (a *App) lookupCountry(ipAddress string) string {
+	path := *a.Config().ServiceSettings.GeoIPDatabaseFile
+	if path == "" {
+		return ""
+	}
+
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return ""
+	}
+
+	country, err := a.Srv().geoIP.country(path, addr)
+	if err != nil {
+		a.Log().Warn("Unable to look up the country of an IP address", mlog.Err(err))
+		return ""
+	}
+	return country
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testGeoIPNode struct {
+	children [2]*testGeoIPNode
+	// data is the offset of the record of a leaf in the data section, or -1 for the other
+	// nodes.
+	data int
+}
+
+// writeTestGeoIPDatabase writes a database in the MaxMind DB format mapping networks to
+// countries. A network nested in another one overrides it.
+func writeTestGeoIPDatabase(t *testing.T, path string, countries map[string]string) {
+	t.Helper()
+
+	var data bytes.Buffer
+	writeString := func(value string) {
+		data.WriteByte(2<<5 | byte(len(value)))
+		data.WriteString(value)
+	}
+	writeUint := func(kind byte, value uint32, size int) {
+		data.WriteByte(kind<<5 | byte(size))
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], value)
+		data.Write(buf[4-size:])
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(countries))
+	for network := range countries {
+		prefixes = append(prefixes, netip.MustParsePrefix(network))
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return prefixes[i].Bits() < prefixes[j].Bits()
+	})
+
+	root := &testGeoIPNode{data: -1}
+	for _, prefix := range prefixes {
+		offset := data.Len()
+		data.WriteByte(7<<5 | 1)
+		writeString("country")
+		data.WriteByte(7<<5 | 1)
+		writeString("iso_code")
+		writeString(countries[prefix.String()])
+
+		// IPv4 networks are stored in the ::/96 subtree.
+		addr, bits := prefix.Addr().As16(), prefix.Bits()
+		if prefix.Addr().Is4() {
+			addr = [16]byte{}
+			copy(addr[12:], prefix.Addr().AsSlice())
+			bits += 96
+		}
+
+		node := root
+		for depth := range bits {
+			bit := addr[depth/8] >> (7 - depth%8) & 1
+			if depth == bits-1 {
+				node.children[bit] = &testGeoIPNode{data: offset}
+				break
+			}
+			child := node.children[bit]
+			if child == nil {
+				child = &testGeoIPNode{data: -1}
+			} else if child.data >= 0 {
+				child = &testGeoIPNode{children: [2]*testGeoIPNode{{data: child.data}, {data: child.data}}, data: -1}
+			}
+			node.children[bit] = child
+			node = child
+		}
+	}
+
+	nodes := []*testGeoIPNode{root}
+	index := map[*testGeoIPNode]int{root: 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			if child != nil && child.data < 0 {
+				index[child] = len(nodes)
+				nodes = append(nodes, child)
+			}
+		}
+	}
+
+	var file bytes.Buffer
+	for _, node := range nodes {
+		for _, child := range node.children {
+			record := len(nodes)
+			if child != nil && child.data >= 0 {
+				record = len(nodes) + 16 + child.data
+			} else if child != nil {
+				record = index[child]
+			}
+			file.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+	file.Write(make([]byte, 16))
+	file.Write(data.Bytes())
+
+	data.Reset()
+	data.WriteByte(7<<5 | 5)
+	writeString("node_count")
+	writeUint(6, uint32(len(nodes)), 4)
+	writeString("record_size")
+	writeUint(5, 24, 2)
+	writeString("ip_version")
+	writeUint(5, 6, 2)
+	writeString("binary_format_major_version")
+	writeUint(5, 2, 2)
+	writeString("database_type")
+	writeString("Test-Country")
+	file.WriteString("\xAB\xCD\xEFMaxMind.com")
+	file.Write(data.Bytes())
+
+	require.NoError(t, os.WriteFile(path, file.Bytes(), 0600))
+}
+
+func TestGeoIPLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geoip.mmdb")
+	writeTestGeoIPDatabase(t, path, map[string]string{
+		"192.0.2.0/24":    "FR",
+		"198.51.100.0/25": "DE",
+		"203.0.112.0/23":  "US",
+		"203.0.113.0/26":  "CA",
+		"2001:db8::/32":   "JP",
+	})
+
+	var lookup geoIPLookup
+	for ip, expected := range map[string]string{
+		"192.0.2.0":          "FR",
+		"192.0.2.255":        "FR",
+		"::ffff:192.0.2.10":  "FR",
+		"198.51.100.127":     "DE",
+		"198.51.100.128":     "",
+		"203.0.112.1":        "US",
+		"203.0.113.1":        "CA",
+		"203.0.113.64":       "US",
+		"2001:db8:ffff::1":   "JP",
+		"2001:db9::1":        "",
+		"10.0.0.1":           "",
+		"0.0.0.0":            "",
+		"ffff:ffff:ffff::ff": "",
+	} {
+		country, err := lookup.country(path, netip.MustParseAddr(ip))
+		require.NoError(t, err)
+		assert.Equal(t, expected, country, ip)
+	}
+
+	t.Run("reloads the database when the file changes", func(t *testing.T) {
+		writeTestGeoIPDatabase(t, path, map[string]string{"10.0.0.0/8": "GB"})
+		modTime := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+		country, err := lookup.country(path, netip.MustParseAddr("10.1.2.3"))
+		require.NoError(t, err)
+		assert.Equal(t, "GB", country)
+
+		country, err = lookup.country(path, netip.MustParseAddr("192.0.2.1"))
+		require.NoError(t, err)
+		assert.Equal(t, "", country)
+	})
+
+	t.Run("invalid database", func(t *testing.T) {
+		invalidPath := filepath.Join(t.TempDir(), "invalid.mmdb")
+		require.NoError(t, os.WriteFile(invalidPath, []byte("192.0.2.0/24,FR\n"), 0600))
+		_, err := lookup.country(invalidPath, netip.MustParseAddr("192.0.2.1"))
+		require.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := lookup.country(filepath.Join(t.TempDir(), "missing.mmdb"), netip.MustParseAddr("10.1.2.3"))
+		require.Error(t, err)
+	})
+}
@@ -793,9 +793,45 @@ func (a *App) importUser(rctx request.CTX, data *imports.UserImportData, dryRun
 		}
 	}
 
+	if data.CustomProfileAttributes != nil {
+		if err := a.importUserCustomProfileAttributes(rctx, savedUser, *data.CustomProfileAttributes); err != nil {
+			return err
+		}
+	}
+
 	return a.importUserTeams(rctx, savedUser, data.Teams)
 }
 
+func (a *App) importUserCustomProfileAttributes(rctx request.CTX, user *model.User, attributes map[string]string) *model.AppError {
+	fields, appErr := a.GetCustomProfileFields()
+	if appErr != nil {
+		return appErr
+	}
+
+	fieldsByName := make(map[string]*model.CustomProfileField, len(fields))
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	values := make([]*model.CustomProfileValue, 0, len(attributes))
+	for name, value := range attributes {
+		field, ok := fieldsByName[name]
+		if !ok {
+			return model.NewAppError("BulkImport", "app.import.import_user.custom_profile_attribute_not_found.error", map[string]any{"Name": name}, "", http.StatusBadRequest)
+		}
+		if appErr := field.IsValidValue(value); appErr != nil {
+			return appErr
+		}
+		values = append(values, &model.CustomProfileValue{FieldId: field.Id, Value: value})
+	}
+
+	if err := a.Srv().Store().CustomProfileAttribute().SaveValues(rctx, user.Id, values); err != nil {
+		return model.NewAppError("BulkImport", "app.custom_profile_attribute.save_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
+
 func (a *App) importUserTeams(rctx request.CTX, user *model.User, data *[]imports.UserTeamImportData) *model.AppError {
 	if data == nil {
 		return nil
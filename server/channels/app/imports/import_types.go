@@ -87,6 +87,9 @@ type UserImportData struct {
 
 	NotifyProps  *UserNotifyPropsImportData `json:"notify_props,omitempty"`
 	CustomStatus *model.CustomStatus        `json:"custom_status,omitempty"`
+
+	// CustomProfileAttributes are the values of the custom profile fields by field name.
+	CustomProfileAttributes *map[string]string `json:"custom_profile_attributes,omitempty"`
 }
 
 type UserNotifyPropsImportData struct {
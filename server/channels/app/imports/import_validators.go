@@ -305,6 +305,14 @@ func ValidateUserImportData(data *UserImportData) *model.AppError {
 		return model.NewAppError("BulkImport", "app.import.validate_user_import_data.advanced_props_email_interval.error", nil, "", http.StatusBadRequest)
 	}
 
+	if data.CustomProfileAttributes != nil {
+		for name, value := range *data.CustomProfileAttributes {
+			if !model.IsValidCustomProfileFieldName(name) || utf8.RuneCountInString(value) > model.CustomProfileValueMaxRunes {
+				return model.NewAppError("BulkImport", "app.import.validate_user_import_data.custom_profile_attribute_invalid.error", map[string]any{"Name": name}, "", http.StatusBadRequest)
+			}
+		}
+	}
+
 	if data.Teams != nil {
 		return ValidateUserTeamsImportData(data.Teams)
 	}
@@ -633,6 +633,18 @@ func TestImportValidateUserImportData(t *testing.T) {
 
 	data.EmailInterval = model.NewPointer("")
 	checkError(t, ValidateUserImportData(&data))
+
+	data.EmailInterval = nil
+
+	// Custom profile attributes
+	data.CustomProfileAttributes = &map[string]string{"department": "Sales", "skills": `["Go","SQL"]`}
+	checkNoError(t, ValidateUserImportData(&data))
+
+	data.CustomProfileAttributes = &map[string]string{"Department": "Sales"}
+	checkError(t, ValidateUserImportData(&data))
+
+	data.CustomProfileAttributes = &map[string]string{"department": strings.Repeat("a", model.CustomProfileValueMaxRunes+1)}
+	checkError(t, ValidateUserImportData(&data))
 }
 
 func TestImportValidateUserAuth(t *testing.T) {
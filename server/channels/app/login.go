@@ -206,6 +206,13 @@ func (a *App) DoLogin(c request.CTX, w http.ResponseWriter, r *http.Request, use
 	} else {
 		session.AddProp(model.SessionPropIsGuest, "false")
 	}
+	a.addLoginDeviceProps(c, session)
+
+	// The limit is enforced first, so that no session is left behind when it fails.
+	if err := a.limitNumberOfSessionsForClientType(c, session); err != nil {
+		err.StatusCode = http.StatusInternalServerError
+		return nil, err
+	}
 
 	var err *model.AppError
 	if session, err = a.CreateSession(c, session); err != nil {
@@ -213,6 +220,8 @@ func (a *App) DoLogin(c request.CTX, w http.ResponseWriter, r *http.Request, use
 		return nil, err
 	}
 
+	a.recordLoginDevice(c, user, session)
+
 	if updateErr := a.Srv().Store().User().UpdateLastLogin(user.Id, session.CreateAt); updateErr != nil {
 		return nil, model.NewAppError("DoLogin", "app.login.doLogin.updateLastLogin.error", nil, "", http.StatusInternalServerError).Wrap(updateErr)
 	}
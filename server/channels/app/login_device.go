@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// addLoginDeviceProps records where a new session of a user logged in from, i.e. the IP
+// address, the country it belongs to and the fingerprint of the device.
+func (a *App) addLoginDeviceProps(c request.CTX, session *model.Session) {
+	ipAddress := c.IPAddress()
+	if ipAddress != "" {
+		session.AddProp(model.SessionPropIPAddress, ipAddress)
+	}
+	if country := a.lookupCountry(ipAddress); country != "" {
+		session.AddProp(model.SessionPropCountry, country)
+	}
+	session.AddProp(model.SessionPropDeviceFingerprint, model.NewLoginDeviceFingerprint(session))
+}
+
+// recordLoginDevice remembers the device and the country of a new session of a user, and
+// alerts them when it is the first login from the device or from the country. The very
+// first login of a user is not reported.
+func (a *App) recordLoginDevice(c request.CTX, user *model.User, session *model.Session) {
+	fingerprint := session.Props[model.SessionPropDeviceFingerprint]
+	country := session.Props[model.SessionPropCountry]
+	if fingerprint == "" {
+		return
+	}
+
+	devices, err := a.Srv().Store().LoginDevice().GetForUser(user.Id, model.LoginDeviceMaxPerUser)
+	if err != nil {
+		c.Logger().Warn("Unable to get the login devices of the user", mlog.String("user_id", user.Id), mlog.Err(err))
+		return
+	}
+
+	var knownDevice, knownCountry bool
+	for _, device := range devices {
+		if device.Fingerprint == fingerprint && device.Country == country {
+			if err := a.Srv().Store().LoginDevice().UpdateLastSeenAt(device.Id, session.CreateAt); err != nil {
+				c.Logger().Warn("Unable to update the login device", mlog.String("user_id", user.Id), mlog.Err(err))
+			}
+			return
+		}
+		knownDevice = knownDevice || device.Fingerprint == fingerprint
+		knownCountry = knownCountry || device.Country == country
+	}
+
+	_, err = a.Srv().Store().LoginDevice().Save(&model.LoginDevice{
+		UserId:      user.Id,
+		Fingerprint: fingerprint,
+		Name:        session.GetDeviceName(),
+		Country:     country,
+		LastSeenAt:  session.CreateAt,
+	})
+	var conflictErr *store.ErrConflict
+	if errors.As(err, &conflictErr) {
+		// Another login from the same device recorded it first.
+		return
+	} else if err != nil {
+		c.Logger().Warn("Unable to save the login device", mlog.String("user_id", user.Id), mlog.Err(err))
+		return
+	}
+
+	if err := a.Srv().Store().LoginDevice().PruneForUser(user.Id, model.LoginDeviceMaxPerUser); err != nil {
+		c.Logger().Warn("Unable to prune the login devices of the user", mlog.String("user_id", user.Id), mlog.Err(err))
+	}
+
+	if len(devices) == 0 || !*a.Config().ServiceSettings.EnableLoginAlerts || user.IsBot {
+		return
+	}
+	if knownDevice && (country == "" || knownCountry) {
+		return
+	}
+
+	deviceName := session.GetDeviceName()
+	ipAddress := session.Props[model.SessionPropIPAddress]
+	a.Srv().Go(func() {
+		if err := a.Srv().EmailService.SendNewLoginEmail(user.Email, deviceName, country, ipAddress, user.Locale, a.GetSiteURL()); err != nil {
+			c.Logger().Error("Unable to send new login email", mlog.String("user_id", user.Id), mlog.Err(err))
+		}
+	})
+}
+
+// limitNumberOfSessionsForClientType revokes the oldest sessions of a user created with the
+// same kind of client as a new session, to keep them within the limit configured for it.
+// It runs before the new session is created, which it leaves room for.
+func (a *App) limitNumberOfSessionsForClientType(c request.CTX, session *model.Session) *model.AppError {
+	var limit int
+	clientType := session.GetClientType()
+	switch clientType {
+	case model.SessionClientTypeWeb:
+		limit = *a.Config().ServiceSettings.MaximumWebSessionsPerUser
+	case model.SessionClientTypeDesktop:
+		limit = *a.Config().ServiceSettings.MaximumDesktopSessionsPerUser
+	case model.SessionClientTypeMobile:
+		limit = *a.Config().ServiceSettings.MaximumMobileSessionsPerUser
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	sessions, appErr := a.GetSessions(c, session.UserId)
+	if appErr != nil {
+		return appErr
+	}
+
+	others := make([]*model.Session, 0, len(sessions))
+	for _, other := range sessions {
+		if other.Id != session.Id && !other.IsExpired() && other.GetClientType() == clientType {
+			others = append(others, other)
+		}
+	}
+	if len(others) < limit {
+		return nil
+	}
+
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].CreateAt > others[j].CreateAt
+	})
+	for _, other := range others[limit-1:] {
+		if err := a.RevokeSession(c, other); err != nil {
+			return model.NewAppError("limitNumberOfSessionsForClientType", "app.session.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		c.Logger().Debug("Session revoked; user's number of sessions were over the limit of their client type",
+			mlog.String("user_id", session.UserId),
+			mlog.String("session_id", other.Id),
+			mlog.String("client_type", clientType))
+	}
+
+	return nil
+}
+
+// GetActiveSessions returns the descriptions of the sessions of a user for them to review
+// the devices they are logged in on, the most recently active first.
+func (a *App) GetActiveSessions(c request.CTX, userID string) ([]*model.ActiveSession, *model.AppError) {
+	sessions, appErr := a.GetSessions(c, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	activeSessions := make([]*model.ActiveSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsExpired() || session.IsIntegration() {
+			continue
+		}
+		activeSessions = append(activeSessions, session.ToActiveSession(c.Session().Id))
+	}
+
+	sort.Slice(activeSessions, func(i, j int) bool {
+		return activeSessions[i].LastActivityAt > activeSessions[j].LastActivityAt
+	})
+	return activeSessions, nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	emailmocks "github.com/mattermost/mattermost/server/v8/channels/app/email/mocks"
+)
+
+func TestRecordLoginDevice(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableLoginAlerts = true })
+
+	alerts := make(chan string, 10)
+	emailServiceMock := emailmocks.ServiceInterface{}
+	emailServiceMock.On("SendNewLoginEmail", th.BasicUser.Email, mock.Anything, mock.Anything, "192.0.2.1", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { alerts <- args.String(1) + "/" + args.String(2) }).
+		Return(nil)
+	emailServiceMock.On("Stop").Return()
+	th.App.Srv().EmailService = &emailServiceMock
+
+	newSession := func(browser, country string) *model.Session {
+		session := &model.Session{UserId: th.BasicUser.Id, CreateAt: model.GetMillis(), Props: model.StringMap{
+			model.SessionPropPlatform:  "Windows",
+			model.SessionPropOs:        "Windows 10",
+			model.SessionPropBrowser:   browser,
+			model.SessionPropIPAddress: "192.0.2.1",
+		}}
+		if country != "" {
+			session.AddProp(model.SessionPropCountry, country)
+		}
+		session.AddProp(model.SessionPropDeviceFingerprint, model.NewLoginDeviceFingerprint(session))
+		return session
+	}
+
+	requireNoAlert := func(t *testing.T) {
+		select {
+		case alert := <-alerts:
+			require.Fail(t, "unexpected login alert", alert)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	requireAlert := func(t *testing.T, expected string) {
+		select {
+		case alert := <-alerts:
+			require.Equal(t, expected, alert)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "missing login alert")
+		}
+	}
+
+	t.Run("first login is not reported", func(t *testing.T) {
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Chrome/120.0", "FR"))
+		requireNoAlert(t)
+	})
+
+	t.Run("login from a known device and country is not reported", func(t *testing.T) {
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Chrome/121.0", "FR"))
+		requireNoAlert(t)
+	})
+
+	t.Run("login from a new device is reported", func(t *testing.T) {
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Firefox/120.0", "FR"))
+		requireAlert(t, "Firefox on Windows 10/FR")
+	})
+
+	t.Run("login from a new country is reported", func(t *testing.T) {
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Chrome/121.0", "DE"))
+		requireAlert(t, "Chrome on Windows 10/DE")
+	})
+
+	t.Run("login from a known device in a known country is not reported", func(t *testing.T) {
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Firefox/120.0", "DE"))
+		requireNoAlert(t)
+	})
+
+	t.Run("login from an unknown country is not reported for a known device", func(t *testing.T) {
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Firefox/120.0", ""))
+		requireNoAlert(t)
+	})
+
+	t.Run("login is not reported when alerts are disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableLoginAlerts = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableLoginAlerts = true })
+
+		th.App.recordLoginDevice(th.Context, th.BasicUser, newSession("Safari/17.0", "FR"))
+		requireNoAlert(t)
+	})
+
+	devices, err := th.App.Srv().Store().LoginDevice().GetForUser(th.BasicUser.Id, model.LoginDeviceMaxPerUser)
+	require.NoError(t, err)
+	assert.Len(t, devices, 6)
+}
+
+func TestLimitNumberOfSessionsForClientType(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MaximumWebSessionsPerUser = 2 })
+
+	userID := model.NewId()
+	createSession := func(browser string) *model.Session {
+		// Sessions are evicted by creation time, in milliseconds.
+		time.Sleep(2 * time.Millisecond)
+		session, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: userID, Props: model.StringMap{
+			model.SessionPropBrowser: browser,
+		}})
+		require.Nil(t, appErr)
+		return session
+	}
+
+	oldestWeb := createSession("Chrome/120.0")
+	desktop := createSession("Desktop App/5.6.0")
+	web := createSession("Firefox/120.0")
+
+	// The limit is enforced before the new session is created.
+	newWeb := &model.Session{UserId: userID, Props: model.StringMap{model.SessionPropBrowser: "Safari/17.0"}}
+	require.Nil(t, th.App.limitNumberOfSessionsForClientType(th.Context, newWeb))
+
+	sessions, appErr := th.App.GetSessions(th.Context, userID)
+	require.Nil(t, appErr)
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.Id)
+	}
+	assert.ElementsMatch(t, []string{desktop.Id, web.Id}, ids)
+	assert.NotContains(t, ids, oldestWeb.Id)
+
+	t.Run("no limit", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MaximumWebSessionsPerUser = 0 })
+
+		require.Nil(t, th.App.limitNumberOfSessionsForClientType(th.Context, createSession("Chrome/120.0")))
+
+		sessions, appErr := th.App.GetSessions(th.Context, userID)
+		require.Nil(t, appErr)
+		assert.Len(t, sessions, 3)
+	})
+}
+
+func TestGetActiveSessions(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	session, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: th.BasicUser.Id, Props: model.StringMap{
+		model.SessionPropBrowser: "Chrome/120.0",
+		model.SessionPropOs:      "Windows 10",
+		model.SessionPropCountry: "FR",
+	}})
+	require.Nil(t, appErr)
+
+	token := &model.Session{UserId: th.BasicUser.Id}
+	token.AddProp(model.SessionPropType, model.SessionTypeUserAccessToken)
+	_, appErr = th.App.CreateSession(th.Context, token)
+	require.Nil(t, appErr)
+
+	activeSessions, appErr := th.App.GetActiveSessions(th.Context.WithSession(session), th.BasicUser.Id)
+	require.Nil(t, appErr)
+
+	var current *model.ActiveSession
+	for _, activeSession := range activeSessions {
+		assert.NotEqual(t, token.Id, activeSession.Id)
+		if activeSession.IsCurrent {
+			current = activeSession
+		}
+	}
+	require.NotNil(t, current)
+	assert.Equal(t, session.Id, current.Id)
+	assert.Equal(t, "Chrome on Windows 10", current.Name)
+	assert.Equal(t, "FR", current.Country)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+)
+
+const mfaTrustedDeviceSignatureAction = "mfa_trusted_device"
+
+// makeMfaAuditRecord creates an audit record for a use of an MFA method by the user,
+// which happens before the user has a session.
+func (a *App) makeMfaAuditRecord(rctx request.CTX, event string, user *model.User) *audit.Record {
+	rec := a.MakeAuditRecord(rctx, event, audit.Fail)
+	rec.Actor.UserId = user.Id
+	rec.Actor.IpAddress = rctx.IPAddress()
+	rec.Actor.XForwardedFor = rctx.XForwardedFor()
+	rec.Actor.Client = rctx.UserAgent()
+	audit.AddEventParameter(rec, "user_id", user.Id)
+	return rec
+}
+
+// replaceMfaRecoveryCodes generates new recovery codes for the user, invalidating the
+// previous ones, and returns them.
+func (a *App) replaceMfaRecoveryCodes(userID string) ([]string, *model.AppError) {
+	codes, hashed := model.NewMfaRecoveryCodes(userID)
+	if err := a.Srv().Store().MfaRecoveryCode().Replace(userID, hashed); err != nil {
+		return nil, model.NewAppError("replaceMfaRecoveryCodes", "app.mfa_recovery_code.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return codes, nil
+}
+
+// checkMfaRecoveryCode consumes a recovery code of the user.
+func (a *App) checkMfaRecoveryCode(rctx request.CTX, user *model.User, code string) (appErr *model.AppError) {
+	auditRec := a.makeMfaAuditRecord(rctx, "useMfaRecoveryCode", user)
+	defer func() { a.LogAuditRec(rctx, auditRec, appErr) }()
+
+	codes, err := a.Srv().Store().MfaRecoveryCode().GetUnusedForUser(user.Id)
+	if err != nil {
+		return model.NewAppError("checkMfaRecoveryCode", "app.mfa_recovery_code.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	hash := model.HashMfaRecoveryCode(code)
+	for _, recoveryCode := range codes {
+		if recoveryCode.CodeHash != hash {
+			continue
+		}
+
+		if err := a.Srv().Store().MfaRecoveryCode().MarkUsed(recoveryCode.Id, model.GetMillis()); err != nil {
+			var nfErr *store.ErrNotFound
+			if errors.As(err, &nfErr) {
+				break
+			}
+			return model.NewAppError("checkMfaRecoveryCode", "app.mfa_recovery_code.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		rctx.Logger().Info("MFA recovery code used", mlog.String("user_id", user.Id), mlog.Int("remaining", len(codes)-1))
+		auditRec.Success()
+		auditRec.AddMeta("remaining", len(codes)-1)
+		return nil
+	}
+
+	return model.NewAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+}
+
+// GetMfaRecoveryCodesStatus returns the number of unused recovery codes of the user.
+func (a *App) GetMfaRecoveryCodesStatus(userID string) (*model.MfaRecoveryCodes, *model.AppError) {
+	codes, err := a.Srv().Store().MfaRecoveryCode().GetUnusedForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetMfaRecoveryCodesStatus", "app.mfa_recovery_code.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &model.MfaRecoveryCodes{Remaining: len(codes)}, nil
+}
+
+// RegenerateMfaRecoveryCodes generates the recovery codes of the user, e.g. once MFA is
+// activated, replacing the previous ones, after checking the given MFA token, and returns
+// the new codes.
+func (a *App) RegenerateMfaRecoveryCodes(rctx request.CTX, userID, token string) (*model.MfaRecoveryCodes, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !*a.Config().ServiceSettings.EnableMultifactorAuthentication {
+		return nil, model.NewAppError("RegenerateMfaRecoveryCodes", "mfa.mfa_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if !user.MfaActive {
+		return nil, model.NewAppError("RegenerateMfaRecoveryCodes", "app.mfa_recovery_code.mfa_inactive.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if token == "" {
+		return nil, model.NewAppError("RegenerateMfaRecoveryCodes", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	if appErr = a.CheckUserMfa(rctx, user, token); appErr != nil {
+		return nil, appErr
+	}
+
+	codes, appErr := a.replaceMfaRecoveryCodes(user.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return &model.MfaRecoveryCodes{RecoveryCodes: codes, Remaining: len(codes)}, nil
+}
+
+func (a *App) isMfaTrustedDeviceEnabled() bool {
+	return *a.Config().ServiceSettings.EnableMultifactorAuthentication && *a.Config().ServiceSettings.MfaTrustedDeviceDays > 0
+}
+
+func (a *App) getMfaTrustedDeviceSignature(deviceID, userID string) string {
+	mac := hmac.New(sha256.New, a.PostActionCookieSecret())
+	mac.Write([]byte(mfaTrustedDeviceSignatureAction + ":" + deviceID + ":" + userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// checkMfaTrustedDevice checks the token of a device the user trusted, which stands for
+// the second factor until the trust expires or the sessions of the device are revoked.
+func (a *App) checkMfaTrustedDevice(rctx request.CTX, user *model.User, token string) (appErr *model.AppError) {
+	// An untrusted device has to complete the login with another method.
+	invalidErr := model.NewAppError("checkMfaTrustedDevice", "mfa.validate_token.authenticate.app_error", nil, "", http.StatusBadRequest)
+
+	if !a.isMfaTrustedDeviceEnabled() {
+		return invalidErr
+	}
+
+	deviceID, signature, ok := model.ParseMfaTrustedDeviceToken(token)
+	if !ok || !hmac.Equal([]byte(signature), []byte(a.getMfaTrustedDeviceSignature(deviceID, user.Id))) {
+		return invalidErr
+	}
+
+	auditRec := a.makeMfaAuditRecord(rctx, "useMfaTrustedDevice", user)
+	defer func() { a.LogAuditRec(rctx, auditRec, appErr) }()
+	audit.AddEventParameter(auditRec, "trusted_device_id", deviceID)
+
+	device, err := a.Srv().Store().MfaTrustedDevice().Get(deviceID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return invalidErr
+		}
+		return model.NewAppError("checkMfaTrustedDevice", "app.mfa_trusted_device.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if device.UserId != user.Id || device.ExpiresAt < model.GetMillis() {
+		return invalidErr
+	}
+
+	if err := a.Srv().Store().MfaTrustedDevice().UpdateLastUsedAt(device.Id, model.GetMillis()); err != nil {
+		rctx.Logger().Warn("Failed to update the last use of a trusted device", mlog.String("trusted_device_id", device.Id), mlog.Err(err))
+	}
+
+	auditRec.Success()
+	return nil
+}
+
+// AttachMfaTrustedDevice binds the session of a login to a trusted device. When the user
+// completed MFA with the token of a trusted device, the session joins the sessions of the
+// device. Otherwise, when the user chose to trust the device, a new trusted device is
+// created and its token is set in a cookie.
+func (a *App) AttachMfaTrustedDevice(rctx request.CTX, w http.ResponseWriter, r *http.Request, user *model.User, mfaToken string, trustDevice bool) *model.AppError {
+	if !user.MfaActive || !a.isMfaTrustedDeviceEnabled() {
+		return nil
+	}
+
+	session := rctx.Session()
+	if deviceID, _, ok := model.ParseMfaTrustedDeviceToken(mfaToken); ok {
+		return a.SetExtraSessionProps(session, map[string]string{model.SessionPropMfaTrustedDeviceId: deviceID})
+	}
+
+	if !trustDevice {
+		return nil
+	}
+
+	days := *a.Config().ServiceSettings.MfaTrustedDeviceDays
+	device, err := a.Srv().Store().MfaTrustedDevice().Save(&model.MfaTrustedDevice{
+		UserId:    user.Id,
+		ExpiresAt: model.GetMillisForTime(time.Now().AddDate(0, 0, days)),
+	})
+	if err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		return model.NewAppError("AttachMfaTrustedDevice", "app.mfa_trusted_device.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	auditRec := a.makeMfaAuditRecord(rctx, "trustMfaDevice", user)
+	defer a.LogAuditRec(rctx, auditRec, nil)
+	auditRec.Actor.SessionId = session.Id
+	auditRec.AddEventResultState(device)
+	auditRec.AddEventObjectType("mfa_trusted_device")
+
+	if appErr := a.SetExtraSessionProps(session, map[string]string{model.SessionPropMfaTrustedDeviceId: device.Id}); appErr != nil {
+		return appErr
+	}
+
+	subpath, _ := utils.GetSubpathFromConfig(a.Config())
+	cookie := &http.Cookie{
+		Name:     model.MfaTrustedDeviceCookie,
+		Value:    model.NewMfaTrustedDeviceToken(device.Id, a.getMfaTrustedDeviceSignature(device.Id, user.Id)),
+		Path:     subpath,
+		MaxAge:   days * 24 * 60 * 60,
+		Expires:  time.UnixMilli(device.ExpiresAt),
+		HttpOnly: true,
+		Domain:   a.GetCookieDomain(),
+		Secure:   GetProtocol(r) == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+	http.SetCookie(w, cookie)
+
+	// The token is also returned to the clients not keeping cookies, e.g. the mobile
+	// apps, which send it back as the MFA token when logging in.
+	w.Header().Set(model.HeaderMfaTrustedDevice, cookie.Value)
+
+	auditRec.Success()
+	return nil
+}
+
+// RevokeMfaTrustedDeviceForSession revokes the trust of the device a session was created
+// on, so that the next logins on the device require MFA again.
+func (a *App) RevokeMfaTrustedDeviceForSession(rctx request.CTX, session *model.Session) *model.AppError {
+	deviceID := session.Props[model.SessionPropMfaTrustedDeviceId]
+	if deviceID == "" {
+		return nil
+	}
+
+	if err := a.Srv().Store().MfaTrustedDevice().Delete(deviceID); err != nil {
+		return model.NewAppError("RevokeMfaTrustedDeviceForSession", "app.mfa_trusted_device.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Debug("MFA trusted device revoked", mlog.String("user_id", session.UserId), mlog.String("trusted_device_id", deviceID))
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCheckMfaRecoveryCode(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMultifactorAuthentication = true })
+
+	require.NoError(t, th.App.Srv().Store().User().UpdateMfaActive(th.BasicUser.Id, true))
+	th.App.InvalidateCacheForUser(th.BasicUser.Id)
+	user, appErr := th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, appErr)
+
+	codes, appErr := th.App.replaceMfaRecoveryCodes(user.Id)
+	require.Nil(t, appErr)
+	require.Len(t, codes, model.MfaRecoveryCodeCount)
+
+	t.Run("unknown code", func(t *testing.T) {
+		appErr := th.App.CheckUserMfa(th.Context, user, "aaaaa-aaaaa")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.user.check_user_mfa.bad_code.app_error", appErr.Id)
+	})
+
+	t.Run("codes are single use", func(t *testing.T) {
+		require.Nil(t, th.App.CheckUserMfa(th.Context, user, codes[0]))
+
+		appErr := th.App.CheckUserMfa(th.Context, user, codes[0])
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.user.check_user_mfa.bad_code.app_error", appErr.Id)
+	})
+
+	t.Run("codes are invalidated when replaced", func(t *testing.T) {
+		_, appErr := th.App.replaceMfaRecoveryCodes(user.Id)
+		require.Nil(t, appErr)
+
+		require.NotNil(t, th.App.CheckUserMfa(th.Context, user, codes[1]))
+	})
+}
+
+func TestRegenerateMfaRecoveryCodes(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMultifactorAuthentication = true })
+
+	t.Run("mfa inactive", func(t *testing.T) {
+		_, appErr := th.App.RegenerateMfaRecoveryCodes(th.Context, th.BasicUser.Id, "aaaaa-aaaaa")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.mfa_recovery_code.mfa_inactive.app_error", appErr.Id)
+	})
+
+	require.NoError(t, th.App.Srv().Store().User().UpdateMfaActive(th.BasicUser.Id, true))
+	th.App.InvalidateCacheForUser(th.BasicUser.Id)
+	codes, appErr := th.App.replaceMfaRecoveryCodes(th.BasicUser.Id)
+	require.Nil(t, appErr)
+
+	t.Run("wrong code", func(t *testing.T) {
+		_, appErr := th.App.RegenerateMfaRecoveryCodes(th.Context, th.BasicUser.Id, "aaaaa-aaaaa")
+		require.NotNil(t, appErr)
+
+		status, appErr := th.App.GetMfaRecoveryCodesStatus(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.MfaRecoveryCodeCount, status.Remaining)
+	})
+
+	t.Run("recovery code", func(t *testing.T) {
+		result, appErr := th.App.RegenerateMfaRecoveryCodes(th.Context, th.BasicUser.Id, codes[0])
+		require.Nil(t, appErr)
+		require.Len(t, result.RecoveryCodes, model.MfaRecoveryCodeCount)
+		assert.Equal(t, model.MfaRecoveryCodeCount, result.Remaining)
+
+		status, appErr := th.App.GetMfaRecoveryCodesStatus(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.MfaRecoveryCodeCount, status.Remaining)
+		assert.Empty(t, status.RecoveryCodes)
+	})
+}
+
+func TestMfaTrustedDevice(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableMultifactorAuthentication = true
+		*cfg.ServiceSettings.MfaTrustedDeviceDays = 30
+	})
+
+	require.NoError(t, th.App.Srv().Store().User().UpdateMfaActive(th.BasicUser.Id, true))
+	th.App.InvalidateCacheForUser(th.BasicUser.Id)
+	user, appErr := th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, appErr)
+
+	trustDevice := func(t *testing.T) (*model.Session, string) {
+		t.Helper()
+
+		session, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: user.Id})
+		require.Nil(t, appErr)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/api/v4/users/login", nil)
+		require.Nil(t, th.App.AttachMfaTrustedDevice(th.Context.WithSession(session), w, r, user, "123456", true))
+
+		token := w.Header().Get(model.HeaderMfaTrustedDevice)
+		require.True(t, model.IsMfaTrustedDeviceToken(token))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, model.MfaTrustedDeviceCookie, cookies[0].Name)
+		assert.Equal(t, token, cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+
+		session, appErr = th.App.GetSessionById(th.Context, session.Id)
+		require.Nil(t, appErr)
+		require.NotEmpty(t, session.Props[model.SessionPropMfaTrustedDeviceId])
+
+		return session, token
+	}
+
+	t.Run("token stands for mfa when logging in", func(t *testing.T) {
+		_, token := trustDevice(t)
+
+		require.Nil(t, th.App.checkUserLoginMfa(th.Context, user, token))
+
+		appErr := th.App.CheckUserMfa(th.Context, user, token)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.user.check_user_mfa.bad_code.app_error", appErr.Id)
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		_, token := trustDevice(t)
+
+		otherUser := *th.BasicUser2
+		otherUser.MfaActive = true
+		appErr := th.App.checkUserLoginMfa(th.Context, &otherUser, token)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "mfa.validate_token.authenticate.app_error", appErr.Id)
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		_, token := trustDevice(t)
+
+		deviceID, _, ok := model.ParseMfaTrustedDeviceToken(token)
+		require.True(t, ok)
+		appErr := th.App.checkUserLoginMfa(th.Context, user, model.NewMfaTrustedDeviceToken(deviceID, "signature"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "mfa.validate_token.authenticate.app_error", appErr.Id)
+	})
+
+	t.Run("revoking a session revokes the trust", func(t *testing.T) {
+		session, token := trustDevice(t)
+
+		require.Nil(t, th.App.RevokeMfaTrustedDeviceForSession(th.Context, session))
+
+		appErr := th.App.checkUserLoginMfa(th.Context, user, token)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "mfa.validate_token.authenticate.app_error", appErr.Id)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, token := trustDevice(t)
+
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MfaTrustedDeviceDays = 0 })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MfaTrustedDeviceDays = 30 })
+
+		require.NotNil(t, th.App.checkUserLoginMfa(th.Context, user, token))
+	})
+
+	t.Run("deactivating mfa revokes the trust", func(t *testing.T) {
+		_, token := trustDevice(t)
+
+		require.Nil(t, th.App.DeactivateMfa(user.Id))
+		defer func() {
+			require.NoError(t, th.App.Srv().Store().User().UpdateMfaActive(user.Id, true))
+			th.App.InvalidateCacheForUser(user.Id)
+		}()
+
+		deviceID, _, _ := model.ParseMfaTrustedDeviceToken(token)
+		_, err := th.App.Srv().Store().MfaTrustedDevice().Get(deviceID)
+		require.Error(t, err)
+	})
+}
@@ -95,6 +95,16 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 		}()
 	}
 
+	var rchan chan store.StoreResult[[]*model.NotificationRule]
+	if *a.Config().ServiceSettings.EnableNotificationRules {
+		rchan = make(chan store.StoreResult[[]*model.NotificationRule], 1)
+		go func() {
+			rules, err := a.Srv().Store().NotificationRule().GetForChannelMembers(channel.Id)
+			rchan <- store.StoreResult[[]*model.NotificationRule]{Data: rules, NErr: err}
+			close(rchan)
+		}()
+	}
+
 	pResult := <-pchan
 	if pResult.NErr != nil {
 		a.CountNotificationReason(model.NotificationStatusError, model.NotificationTypeAll, model.NotificationReasonFetchError, model.NotificationNoPlatform)
@@ -166,6 +176,15 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 
 	mentions, keywords := a.getExplicitMentionsAndKeywords(c, post, channel, profileMap, groups, channelMemberNotifyPropsMap, parentPostList)
 
+	mutedByRule := make(model.StringSet)
+	if rchan != nil {
+		if rResult := <-rchan; rResult.NErr != nil {
+			c.Logger().Warn("Failed to get the notification rules of the channel members", mlog.String("post_id", post.Id), mlog.String("channel_id", channel.Id), mlog.Err(rResult.NErr))
+		} else {
+			mutedByRule = a.applyNotificationRules(rResult.Data, post, channel, profileMap, mentions)
+		}
+	}
+
 	var allActivityPushUserIds []string
 	if channel.Type != model.ChannelTypeDirect {
 		// Iterate through all groups that were mentioned and insert group members into the list of mentions or potential mentions
@@ -394,6 +413,10 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 				continue
 			}
 
+			if mutedByRule.Has(id) {
+				continue
+			}
+
 			//If email verification is required and user email is not verified don't send email.
 			if *a.Config().EmailSettings.RequireEmailVerification && !profileMap[id].EmailVerified {
 				a.CountNotificationReason(model.NotificationStatusNotSent, model.NotificationTypeEmail, model.NotificationReasonEmailNotVerified, model.NotificationNoPlatform)
@@ -526,6 +549,10 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 				continue
 			}
 
+			if mutedByRule.Has(id) {
+				continue
+			}
+
 			var status *model.Status
 			var err *model.AppError
 			if status, err = a.GetStatus(id); err != nil {
@@ -578,6 +605,10 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 				continue
 			}
 
+			if mutedByRule.Has(id) {
+				continue
+			}
+
 			if _, ok := mentions.Mentions[id]; !ok {
 				var status *model.Status
 				var err *model.AppError
@@ -612,6 +643,10 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 				continue
 			}
 
+			if mutedByRule.Has(id) {
+				continue
+			}
+
 			var status *model.Status
 			var err *model.AppError
 			if status, err = a.GetStatus(id); err != nil {
@@ -689,6 +724,10 @@ func (a *App) SendNotifications(c request.CTX, post *model.Post, team *model.Tea
 		useAddFollowersHook(message, notificationsForCRT.Desktop)
 	}
 
+	if len(mutedByRule) > 0 {
+		useMuteNotificationsHook(message, mutedByRule.Val())
+	}
+
 	// Collect user IDs of whom we want to acknowledge the websocket event for notification metrics
 	usersToAck := []string{}
 	for id, profile := range profileMap {
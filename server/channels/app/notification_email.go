@@ -128,8 +128,10 @@ func (a *App) sendNotificationEmail(c request.CTX, notification *PostNotificatio
 		references = referencesVal
 	}
 
+	replyToAddress := a.getReplyByEmailAddress(user.Id, post.Id)
+
 	a.Srv().Go(func() {
-		if nErr := a.Srv().EmailService.SendMailWithEmbeddedFiles(user.Email, html.UnescapeString(subjectText), bodyText, embeddedFiles, messageID, inReplyTo, references, "Notification"); nErr != nil {
+		if nErr := a.Srv().EmailService.SendMailWithEmbeddedFilesAndCustomReplyTo(user.Email, html.UnescapeString(subjectText), bodyText, replyToAddress, embeddedFiles, messageID, inReplyTo, references, "Notification"); nErr != nil {
 			c.Logger().Error("Error while sending the email", mlog.String("user_email", user.Email), mlog.Err(nErr))
 		}
 	})
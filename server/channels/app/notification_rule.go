@@ -0,0 +1,184 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func (a *App) checkNotificationRulesEnabled(where string) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableNotificationRules {
+		return model.NewAppError(where, "app.notification_rule.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+func (a *App) GetNotificationRules(userID string) ([]*model.NotificationRule, *model.AppError) {
+	if appErr := a.checkNotificationRulesEnabled("GetNotificationRules"); appErr != nil {
+		return nil, appErr
+	}
+
+	rules, err := a.Srv().Store().NotificationRule().GetForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetNotificationRules", "app.notification_rule.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return rules, nil
+}
+
+func (a *App) GetNotificationRule(ruleID string) (*model.NotificationRule, *model.AppError) {
+	if appErr := a.checkNotificationRulesEnabled("GetNotificationRule"); appErr != nil {
+		return nil, appErr
+	}
+
+	rule, err := a.Srv().Store().NotificationRule().Get(ruleID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("GetNotificationRule", "app.notification_rule.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return nil, model.NewAppError("GetNotificationRule", "app.notification_rule.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return rule, nil
+}
+
+func (a *App) CreateNotificationRule(rule *model.NotificationRule) (*model.NotificationRule, *model.AppError) {
+	if appErr := a.checkNotificationRulesEnabled("CreateNotificationRule"); appErr != nil {
+		return nil, appErr
+	}
+
+	rules, err := a.Srv().Store().NotificationRule().GetForUser(rule.UserId)
+	if err != nil {
+		return nil, model.NewAppError("CreateNotificationRule", "app.notification_rule.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(rules) >= model.MaxNotificationRulesPerUser {
+		return nil, model.NewAppError("CreateNotificationRule", "app.notification_rule.save.limit.app_error", map[string]any{"Max": model.MaxNotificationRulesPerUser}, "", http.StatusBadRequest)
+	}
+
+	rule.Id = ""
+	saved, err := a.Srv().Store().NotificationRule().Save(rule)
+	if err != nil {
+		var appErr *model.AppError
+		var invErr *store.ErrInvalidInput
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &invErr):
+			return nil, model.NewAppError("CreateNotificationRule", "app.notification_rule.save.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		default:
+			return nil, model.NewAppError("CreateNotificationRule", "app.notification_rule.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return saved, nil
+}
+
+func (a *App) UpdateNotificationRule(rule *model.NotificationRule) (*model.NotificationRule, *model.AppError) {
+	existing, appErr := a.GetNotificationRule(rule.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if existing.UserId != rule.UserId {
+		return nil, model.NewAppError("UpdateNotificationRule", "app.notification_rule.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	rule.CreateAt = existing.CreateAt
+	updated, err := a.Srv().Store().NotificationRule().Update(rule)
+	if err != nil {
+		var appErr *model.AppError
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("UpdateNotificationRule", "app.notification_rule.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("UpdateNotificationRule", "app.notification_rule.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return updated, nil
+}
+
+func (a *App) DeleteNotificationRule(userID, ruleID string) (*model.NotificationRule, *model.AppError) {
+	rule, appErr := a.GetNotificationRule(ruleID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if rule.UserId != userID {
+		return nil, model.NewAppError("DeleteNotificationRule", "app.notification_rule.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if err := a.Srv().Store().NotificationRule().Delete(ruleID); err != nil {
+		return nil, model.NewAppError("DeleteNotificationRule", "app.notification_rule.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return rule, nil
+}
+
+// applyNotificationRules adds the users notified by their rules to the mentions
+// and returns the users whose notifications are muted by their rules.
+func (a *App) applyNotificationRules(rules []*model.NotificationRule, post *model.Post, channel *model.Channel, profileMap map[string]*model.User, mentions *MentionResults) model.StringSet {
+	notified, muted := evaluateNotificationRules(rules, post, channel, profileMap, time.Now())
+
+	for id := range notified {
+		mentions.addMention(id, KeywordMention)
+	}
+
+	for id := range muted {
+		a.CountNotificationReason(model.NotificationStatusNotSent, model.NotificationTypeAll, model.NotificationReasonMutedByRule, model.NotificationNoPlatform)
+		a.NotificationsLog().Debug("Notification muted by rule",
+			mlog.String("type", model.NotificationTypeAll),
+			mlog.String("post_id", post.Id),
+			mlog.String("status", model.NotificationStatusNotSent),
+			mlog.String("reason", model.NotificationReasonMutedByRule),
+			mlog.String("sender_id", post.UserId),
+			mlog.String("receiver_id", id),
+		)
+	}
+
+	return muted
+}
+
+// evaluateNotificationRules returns the users to notify and the users to mute
+// according to the rules matching the post. A user matched by both kinds of
+// rules is muted.
+func evaluateNotificationRules(rules []*model.NotificationRule, post *model.Post, channel *model.Channel, profileMap map[string]*model.User, now time.Time) (notified model.StringSet, muted model.StringSet) {
+	notified = make(model.StringSet)
+	muted = make(model.StringSet)
+
+	if post.IsSystemMessage() {
+		return notified, muted
+	}
+
+	for _, rule := range rules {
+		profile := profileMap[rule.UserId]
+		if profile == nil || (rule.UserId == post.UserId && post.GetProp("from_webhook") != "true") {
+			continue
+		}
+
+		if rule.Action == model.NotificationRuleActionMute && rule.BypassUrgent && post.IsUrgent() {
+			continue
+		}
+
+		if !rule.AppliesToChannel(channel) || !rule.IsActiveAt(now.In(profile.GetTimezoneLocation())) || !rule.MatchesText(post.Message) {
+			continue
+		}
+
+		switch rule.Action {
+		case model.NotificationRuleActionNotify:
+			notified.Add(rule.UserId)
+		case model.NotificationRuleActionMute:
+			muted.Add(rule.UserId)
+		}
+	}
+
+	for id := range muted {
+		delete(notified, id)
+	}
+
+	return notified, muted
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestEvaluateNotificationRules(t *testing.T) {
+	sender := &model.User{Id: model.NewId()}
+	user1 := &model.User{Id: model.NewId()}
+	user2 := &model.User{Id: model.NewId(), Timezone: model.StringMap{"useAutomaticTimezone": "false", "manualTimezone": "America/New_York"}}
+	profileMap := map[string]*model.User{sender.Id: sender, user1.Id: user1, user2.Id: user2}
+
+	channel := &model.Channel{Id: model.NewId(), TeamId: model.NewId(), Type: model.ChannelTypeOpen}
+	post := &model.Post{Id: model.NewId(), UserId: sender.Id, ChannelId: channel.Id, Message: "The deploy of v2 failed"}
+
+	// A Monday, at 8:00 in UTC and 3:00 in New York.
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	newRule := func(userID, action string, keywords ...string) *model.NotificationRule {
+		return &model.NotificationRule{Id: model.NewId(), UserId: userID, Action: action, Keywords: keywords}
+	}
+
+	t.Run("notify and mute on keywords", func(t *testing.T) {
+		rules := []*model.NotificationRule{
+			newRule(user1.Id, model.NotificationRuleActionNotify, "deploy"),
+			newRule(user2.Id, model.NotificationRuleActionMute, "v2"),
+			newRule(user2.Id, model.NotificationRuleActionNotify, "rollback"),
+		}
+
+		notified, muted := evaluateNotificationRules(rules, post, channel, profileMap, now)
+		assert.ElementsMatch(t, []string{user1.Id}, notified.Val())
+		assert.ElementsMatch(t, []string{user2.Id}, muted.Val())
+	})
+
+	t.Run("mute wins over notify", func(t *testing.T) {
+		rules := []*model.NotificationRule{
+			newRule(user1.Id, model.NotificationRuleActionNotify, "deploy"),
+			newRule(user1.Id, model.NotificationRuleActionMute),
+		}
+
+		notified, muted := evaluateNotificationRules(rules, post, channel, profileMap, now)
+		assert.Empty(t, notified)
+		assert.ElementsMatch(t, []string{user1.Id}, muted.Val())
+	})
+
+	t.Run("ignores the rules of the sender and of non members", func(t *testing.T) {
+		rules := []*model.NotificationRule{
+			newRule(sender.Id, model.NotificationRuleActionNotify, "deploy"),
+			newRule(model.NewId(), model.NotificationRuleActionNotify, "deploy"),
+		}
+
+		notified, muted := evaluateNotificationRules(rules, post, channel, profileMap, now)
+		assert.Empty(t, notified)
+		assert.Empty(t, muted)
+	})
+
+	t.Run("ignores system messages", func(t *testing.T) {
+		systemPost := post.Clone()
+		systemPost.Type = model.PostTypeJoinChannel
+
+		notified, _ := evaluateNotificationRules([]*model.NotificationRule{newRule(user1.Id, model.NotificationRuleActionNotify)}, systemPost, channel, profileMap, now)
+		assert.Empty(t, notified)
+	})
+
+	t.Run("applies the rules scoped to the channel or its team", func(t *testing.T) {
+		otherChannel := newRule(user1.Id, model.NotificationRuleActionNotify, "deploy")
+		otherChannel.ChannelIds = model.StringArray{model.NewId()}
+		team := newRule(user2.Id, model.NotificationRuleActionNotify, "deploy")
+		team.TeamIds = model.StringArray{channel.TeamId}
+
+		notified, _ := evaluateNotificationRules([]*model.NotificationRule{otherChannel, team}, post, channel, profileMap, now)
+		assert.ElementsMatch(t, []string{user2.Id}, notified.Val())
+	})
+
+	t.Run("uses the timezone of the user for quiet hours", func(t *testing.T) {
+		quietHours := &model.NotificationRuleSchedule{StartTime: "22:00", EndTime: "07:00"}
+		rule1 := newRule(user1.Id, model.NotificationRuleActionMute)
+		rule1.Schedule = quietHours
+		rule2 := newRule(user2.Id, model.NotificationRuleActionMute)
+		rule2.Schedule = quietHours
+
+		_, muted := evaluateNotificationRules([]*model.NotificationRule{rule1, rule2}, post, channel, profileMap, now)
+		assert.ElementsMatch(t, []string{user2.Id}, muted.Val())
+	})
+
+	t.Run("lets urgent posts through when asked to", func(t *testing.T) {
+		urgentPost := post.Clone()
+		urgentPost.Metadata = &model.PostMetadata{Priority: &model.PostPriority{Priority: model.NewPointer(model.PostPriorityUrgent)}}
+
+		bypass := newRule(user1.Id, model.NotificationRuleActionMute)
+		bypass.BypassUrgent = true
+		noBypass := newRule(user2.Id, model.NotificationRuleActionMute)
+
+		_, muted := evaluateNotificationRules([]*model.NotificationRule{bypass, noBypass}, urgentPost, channel, profileMap, now)
+		assert.ElementsMatch(t, []string{user2.Id}, muted.Val())
+
+		_, muted = evaluateNotificationRules([]*model.NotificationRule{bypass, noBypass}, post, channel, profileMap, now)
+		assert.ElementsMatch(t, []string{user1.Id, user2.Id}, muted.Val())
+	})
+}
@@ -6,7 +6,10 @@ package app
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	b64 "encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -39,7 +42,10 @@ func (a *App) CreateOAuthApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppEr
 		return nil, model.NewAppError("CreateOAuthApp", "api.oauth.register_oauth_app.turn_off.app_error", nil, "", http.StatusNotImplemented)
 	}
 
-	app.ClientSecret = model.NewId()
+	app.ClientSecret = ""
+	if !app.IsPublic {
+		app.ClientSecret = model.NewId()
+	}
 
 	oauthApp, err := a.Srv().Store().OAuth().SaveApp(app)
 	if err != nil {
@@ -87,6 +93,13 @@ func (a *App) UpdateOAuthApp(oldApp, updatedApp *model.OAuthApp) (*model.OAuthAp
 	updatedApp.CreateAt = oldApp.CreateAt
 	updatedApp.ClientSecret = oldApp.ClientSecret
 
+	// Public apps can't keep a secret, so one is only issued when the app becomes confidential.
+	if updatedApp.IsPublic {
+		updatedApp.ClientSecret = ""
+	} else if updatedApp.ClientSecret == "" {
+		updatedApp.ClientSecret = model.NewId()
+	}
+
 	oauthApp, err := a.Srv().Store().OAuth().UpdateApp(updatedApp)
 	if err != nil {
 		var appErr *model.AppError
@@ -163,7 +176,7 @@ func (a *App) GetOAuthImplicitRedirect(c request.CTX, userID string, authRequest
 }
 
 func (a *App) GetOAuthCodeRedirect(userID string, authRequest *model.AuthorizeRequest) (string, *model.AppError) {
-	authData := &model.AuthData{UserId: userID, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectURI, State: authRequest.State, Scope: authRequest.Scope}
+	authData := &model.AuthData{UserId: userID, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectURI, State: authRequest.State, Scope: authRequest.Scope, CodeChallenge: authRequest.CodeChallenge, CodeChallengeMethod: authRequest.CodeChallengeMethod}
 	authData.Code = model.NewId() + model.NewId()
 
 	// parse authRequest.RedirectURI to handle query parameters see: https://mattermost.atlassian.net/browse/MM-46216
@@ -208,6 +221,12 @@ func (a *App) AllowOAuthAppAccessToUser(c request.CTX, userID string, authReques
 		return "", model.NewAppError("AllowOAuthAppAccessToUser", "api.oauth.allow_oauth.redirect_callback.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	// The plain method would let anyone intercepting the authorization request redeem the
+	// code, which the secret of confidential apps prevents.
+	if oauthApp.IsPublic && (authRequest.ResponseType != model.AuthCodeResponseType || authRequest.CodeChallenge == "" || authRequest.CodeChallengeMethod != model.PKCEMethodS256) {
+		return "", model.NewAppError("AllowOAuthAppAccessToUser", "api.oauth.allow_oauth.pkce_required.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	var redirectURI string
 	var err *model.AppError
 	switch authRequest.ResponseType {
@@ -269,7 +288,9 @@ func (a *App) GetOAuthAccessTokenForImplicitFlow(c request.CTX, userID string, a
 	return session, nil
 }
 
-func (a *App) GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType, redirectURI, code, secret, refreshToken string) (*model.AccessResponse, *model.AppError) {
+// authenticateOAuthClient returns the app with the given client id if the secret matches. Public
+// apps have no secret, so the client id is enough to identify them.
+func (a *App) authenticateOAuthClient(clientId, secret string) (*model.OAuthApp, *model.AppError) {
 	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
@@ -279,16 +300,32 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType,
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusNotFound)
 	}
 
-	if oauthApp.ClientSecret != secret {
+	if oauthApp.IsPublic {
+		if secret != "" {
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusForbidden)
+		}
+		return oauthApp, nil
+	}
+
+	if secret == "" {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.bad_client_secret.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(oauthApp.ClientSecret), []byte(secret)) != 1 {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusForbidden)
 	}
 
-	var accessData *model.AccessData
-	var accessRsp *model.AccessResponse
-	var user *model.User
+	return oauthApp, nil
+}
+
+func (a *App) GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType, redirectURI, code, secret, refreshToken, codeVerifier string) (*model.AccessResponse, *model.AppError) {
+	oauthApp, err := a.authenticateOAuthClient(clientId, secret)
+	if err != nil {
+		return nil, err
+	}
+
 	if grantType == model.AccessTokenGrantType {
-		var authData *model.AuthData
-		authData, nErr = a.Srv().Store().OAuth().GetAuthData(code)
+		authData, nErr := a.Srv().Store().OAuth().GetAuthData(code)
 		if nErr != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusBadRequest)
 		}
@@ -300,11 +337,19 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType,
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusForbidden)
 		}
 
+		if authData.ClientId != clientId {
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusBadRequest)
+		}
+
 		if authData.RedirectUri != redirectURI {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.redirect_uri.app_error", nil, "", http.StatusBadRequest)
 		}
 
-		user, nErr = a.Srv().Store().User().Get(context.Background(), authData.UserId)
+		if !authData.VerifyCodeVerifier(codeVerifier) || (oauthApp.IsPublic && authData.CodeChallengeMethod != model.PKCEMethodS256) {
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.code_verifier.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		user, nErr := a.Srv().Store().User().Get(context.Background(), authData.UserId)
 		if nErr != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
 		}
@@ -313,73 +358,104 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(c request.CTX, clientId, grantType,
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusForbidden)
 		}
 
-		accessData, nErr = a.Srv().Store().OAuth().GetPreviousAccessData(user.Id, clientId)
-		if nErr != nil {
-			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal.app_error", nil, "", http.StatusBadRequest)
+		accessRsp, err := a.issueOAuthAccessToken(c, oauthApp, user, model.AccessTokenGrantType, redirectURI, authData.Scope)
+		if err != nil {
+			return nil, err
 		}
 
-		if accessData != nil {
-			if accessData.IsExpired() {
-				var access *model.AccessResponse
-				access, err := a.newSessionUpdateToken(c, oauthApp, accessData, user)
-				if err != nil {
-					return nil, err
-				}
-				accessRsp = access
-			} else {
-				// Return the same token and no need to create a new session
-				accessRsp = &model.AccessResponse{
-					AccessToken:      accessData.Token,
-					TokenType:        model.AccessTokenType,
-					RefreshToken:     accessData.RefreshToken,
-					ExpiresInSeconds: int32((accessData.ExpiresAt - model.GetMillis()) / 1000),
-				}
-			}
-		} else {
-			var session *model.Session
-			// Create a new session and return new access token
-			session, err := a.newSession(c, oauthApp, user)
-			if err != nil {
-				return nil, err
-			}
+		if nErr = a.Srv().Store().OAuth().RemoveAuthData(authData.Code); nErr != nil {
+			c.Logger().Warn("unable to remove auth data", mlog.Err(nErr))
+		}
 
-			accessData = &model.AccessData{ClientId: clientId, UserId: user.Id, Token: session.Token, RefreshToken: model.NewId(), RedirectUri: redirectURI, ExpiresAt: session.ExpiresAt, Scope: authData.Scope}
+		return accessRsp, nil
+	}
 
-			if _, nErr = a.Srv().Store().OAuth().SaveAccessData(accessData); nErr != nil {
-				return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_saving.app_error", nil, "", http.StatusInternalServerError)
-			}
+	// When grantType is refresh_token
+	accessData, nErr := a.Srv().Store().OAuth().GetAccessDataByRefreshToken(refreshToken)
+	if nErr != nil || accessData.ClientId != clientId {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.refresh_token.app_error", nil, "", http.StatusNotFound)
+	}
 
-			accessRsp = &model.AccessResponse{
-				AccessToken:      session.Token,
-				TokenType:        model.AccessTokenType,
-				RefreshToken:     accessData.RefreshToken,
-				ExpiresInSeconds: int32(*a.Config().ServiceSettings.SessionLengthSSOInHours * 60 * 60),
-			}
-		}
+	user, nErr := a.Srv().Store().User().Get(context.Background(), accessData.UserId)
+	if nErr != nil {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
+	}
 
-		if nErr = a.Srv().Store().OAuth().RemoveAuthData(authData.Code); nErr != nil {
-			c.Logger().Warn("unable to remove auth data", mlog.Err(nErr))
-		}
-	} else {
-		// When grantType is refresh_token
-		accessData, nErr = a.Srv().Store().OAuth().GetAccessDataByRefreshToken(refreshToken)
-		if nErr != nil {
-			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.refresh_token.app_error", nil, "", http.StatusNotFound)
-		}
+	return a.newSessionUpdateToken(c, oauthApp, accessData, user)
+}
 
-		user, nErr := a.Srv().Store().User().Get(context.Background(), accessData.UserId)
-		if nErr != nil {
-			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
-		}
+// GetOAuthAccessTokenForClientCredentials issues a token for the bot user of the app, for
+// integrations acting on their own behalf rather than on behalf of a user.
+func (a *App) GetOAuthAccessTokenForClientCredentials(c request.CTX, clientId, secret, scope string) (*model.AccessResponse, *model.AppError) {
+	oauthApp, err := a.authenticateOAuthClient(clientId, secret)
+	if err != nil {
+		return nil, err
+	}
 
-		access, err := a.newSessionUpdateToken(c, oauthApp, accessData, user)
-		if err != nil {
-			return nil, err
+	if oauthApp.IsPublic || oauthApp.BotUserId == "" {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.client_credentials.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	user, nErr := a.Srv().Store().User().Get(context.Background(), oauthApp.BotUserId)
+	if nErr != nil {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound).Wrap(nErr)
+	}
+
+	if !user.IsBot || user.DeleteAt != 0 {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.client_credentials.app_error", nil, "bot_user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	if scope == "" {
+		scope = model.DefaultScope
+	}
+
+	return a.issueOAuthAccessToken(c, oauthApp, user, model.ClientCredentialsGrantType, "", scope)
+}
+
+// issueOAuthAccessToken returns the access token of the user for the app, creating a new session
+// if the user has none or if it has expired.
+func (a *App) issueOAuthAccessToken(c request.CTX, oauthApp *model.OAuthApp, user *model.User, grantType, redirectURI, scope string) (*model.AccessResponse, *model.AppError) {
+	accessData, nErr := a.Srv().Store().OAuth().GetPreviousAccessData(user.Id, oauthApp.Id)
+	if nErr != nil {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if accessData != nil {
+		if accessData.IsExpired() {
+			return a.newSessionUpdateToken(c, oauthApp, accessData, user)
 		}
-		accessRsp = access
+
+		// Return the same token and no need to create a new session
+		return &model.AccessResponse{
+			AccessToken:      accessData.Token,
+			TokenType:        model.AccessTokenType,
+			RefreshToken:     accessData.RefreshToken,
+			ExpiresInSeconds: int32((accessData.ExpiresAt - model.GetMillis()) / 1000),
+		}, nil
 	}
 
-	return accessRsp, nil
+	// Create a new session and return new access token
+	session, err := a.newSession(c, oauthApp, user)
+	if err != nil {
+		return nil, err
+	}
+
+	accessData = &model.AccessData{ClientId: oauthApp.Id, UserId: user.Id, Token: session.Token, RedirectUri: redirectURI, ExpiresAt: session.ExpiresAt, Scope: scope, GrantType: grantType}
+	// The client credentials grant has no refresh token, the client requests a new token instead.
+	if grantType != model.ClientCredentialsGrantType {
+		accessData.RefreshToken = model.NewId()
+	}
+
+	if _, nErr = a.Srv().Store().OAuth().SaveAccessData(accessData); nErr != nil {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_saving.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	return &model.AccessResponse{
+		AccessToken:      session.Token,
+		TokenType:        model.AccessTokenType,
+		RefreshToken:     accessData.RefreshToken,
+		ExpiresInSeconds: int32(*a.Config().ServiceSettings.SessionLengthSSOInHours * 60 * 60),
+	}, nil
 }
 
 func (a *App) newSession(c request.CTX, app *model.OAuthApp, user *model.User) (*model.Session, *model.AppError) {
@@ -420,7 +496,9 @@ func (a *App) newSessionUpdateToken(c request.CTX, app *model.OAuthApp, accessDa
 	}
 
 	accessData.Token = session.Token
-	accessData.RefreshToken = model.NewId()
+	if accessData.GrantType != model.ClientCredentialsGrantType {
+		accessData.RefreshToken = model.NewId()
+	}
 	accessData.ExpiresAt = session.ExpiresAt
 
 	if _, err := a.Srv().Store().OAuth().UpdateAccessData(accessData); err != nil {
@@ -536,6 +614,10 @@ func (a *App) RegenerateOAuthAppSecret(app *model.OAuthApp) (*model.OAuthApp, *m
 		return nil, model.NewAppError("RegenerateOAuthAppSecret", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
 	}
 
+	if app.IsPublic {
+		return nil, model.NewAppError("RegenerateOAuthAppSecret", "api.oauth.regenerate_secret.public_app.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	app.ClientSecret = model.NewId()
 	if _, err := a.Srv().Store().OAuth().UpdateApp(app); err != nil {
 		var appErr *model.AppError
@@ -571,20 +653,115 @@ func (a *App) RevokeAccessToken(c request.CTX, token string) *model.AppError {
 func (a *App) CompleteOAuth(c request.CTX, service string, body io.ReadCloser, teamID string, props map[string]string, tokenUser *model.User) (*model.User, *model.AppError) {
 	defer body.Close()
 
+	buf := bytes.Buffer{}
+	if _, err := buf.ReadFrom(body); err != nil {
+		return nil, model.NewAppError("CompleteOAuth", "api.user.login_by_oauth.parse.app_error",
+			map[string]any{"Service": service}, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	var user *model.User
+	var appErr *model.AppError
 	action := props["action"]
 
 	switch action {
 	case model.OAuthActionSignup:
-		return a.CreateOAuthUser(c, service, body, teamID, tokenUser)
+		user, appErr = a.CreateOAuthUser(c, service, bytes.NewReader(buf.Bytes()), teamID, tokenUser)
 	case model.OAuthActionLogin:
-		return a.LoginByOAuth(c, service, body, teamID, tokenUser)
+		user, appErr = a.LoginByOAuth(c, service, bytes.NewReader(buf.Bytes()), teamID, tokenUser)
 	case model.OAuthActionEmailToSSO:
-		return a.CompleteSwitchWithOAuth(c, service, body, props["email"], tokenUser)
+		user, appErr = a.CompleteSwitchWithOAuth(c, service, bytes.NewReader(buf.Bytes()), props["email"], tokenUser)
 	case model.OAuthActionSSOToEmail:
-		return a.LoginByOAuth(c, service, body, teamID, tokenUser)
+		user, appErr = a.LoginByOAuth(c, service, bytes.NewReader(buf.Bytes()), teamID, tokenUser)
 	default:
-		return a.LoginByOAuth(c, service, body, teamID, tokenUser)
+		user, appErr = a.LoginByOAuth(c, service, bytes.NewReader(buf.Bytes()), teamID, tokenUser)
+	}
+	if appErr != nil {
+		return nil, appErr
 	}
+
+	a.syncOAuthGroups(c, service, bytes.NewReader(buf.Bytes()), user, tokenUser)
+
+	return user, nil
+}
+
+// syncOAuthGroups updates the user's memberships in the groups of an OAuth
+// service whose provider reports group membership, creating groups seen for
+// the first time. Failures are logged rather than failing the login.
+func (a *App) syncOAuthGroups(c request.CTX, service string, userData io.Reader, user *model.User, tokenUser *model.User) {
+	provider, appErr := a.getSSOProvider(service)
+	if appErr != nil {
+		return
+	}
+	groupsProvider, ok := provider.(einterfaces.OAuthGroupsProvider)
+	if !ok {
+		return
+	}
+
+	names, err := groupsProvider.GetGroupsFromJSON(c, userData, tokenUser)
+	if err != nil {
+		c.Logger().Warn("Failed to read groups from OAuth user data", mlog.String("service", service), mlog.Err(err))
+		return
+	}
+
+	wanted := make(map[string]string, len(names))
+	for _, name := range names {
+		wanted[oauthGroupRemoteID(service, name)] = name
+	}
+
+	current, appErr := a.GetGroupsByUserId(user.Id)
+	if appErr != nil {
+		c.Logger().Warn("Failed to get groups of OAuth user", mlog.String("user_id", user.Id), mlog.Err(appErr))
+		return
+	}
+
+	prefix := service + ":"
+	for _, group := range current {
+		if group.Source != model.GroupSourceOpenId || !strings.HasPrefix(group.GetRemoteId(), prefix) {
+			continue
+		}
+		if _, ok := wanted[group.GetRemoteId()]; ok {
+			delete(wanted, group.GetRemoteId())
+			continue
+		}
+		if _, appErr := a.DeleteGroupMember(group.Id, user.Id); appErr != nil {
+			c.Logger().Warn("Failed to remove OAuth user from group", mlog.String("user_id", user.Id), mlog.String("group_id", group.Id), mlog.Err(appErr))
+		}
+	}
+
+	for remoteID, name := range wanted {
+		group, appErr := a.GetGroupByRemoteID(remoteID, model.GroupSourceOpenId)
+		if appErr != nil && appErr.StatusCode == http.StatusNotFound {
+			displayName := name
+			if len(displayName) > model.GroupDisplayNameMaxLength {
+				displayName = displayName[:model.GroupDisplayNameMaxLength]
+			}
+			group, appErr = a.CreateGroup(&model.Group{
+				DisplayName: displayName,
+				Source:      model.GroupSourceOpenId,
+				RemoteId:    model.NewPointer(remoteID),
+			})
+		}
+		if appErr != nil {
+			c.Logger().Warn("Failed to get group for OAuth user", mlog.String("remote_id", remoteID), mlog.Err(appErr))
+			continue
+		}
+
+		if _, appErr := a.UpsertGroupMember(group.Id, user.Id); appErr != nil {
+			c.Logger().Warn("Failed to add OAuth user to group", mlog.String("user_id", user.Id), mlog.String("group_id", group.Id), mlog.Err(appErr))
+		}
+	}
+}
+
+// oauthGroupRemoteID identifies a group reported by an OAuth service. Values
+// too long to be stored as they are get replaced by a hash.
+func oauthGroupRemoteID(service, name string) string {
+	remoteID := service + ":" + name
+	if len(remoteID) <= model.GroupRemoteIDMaxLength {
+		return remoteID
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	return (service + ":" + hex.EncodeToString(hash[:]))[:model.GroupRemoteIDMaxLength]
 }
 
 func (a *App) getSSOProvider(service string) (einterfaces.OAuthProvider, *model.AppError) {
@@ -593,7 +770,9 @@ func (a *App) getSSOProvider(service string) (einterfaces.OAuthProvider, *model.
 		return nil, model.NewAppError("getSSOProvider", "api.user.authorize_oauth_user.unsupported.app_error", nil, "service="+service, http.StatusNotImplemented)
 	}
 	providerType := service
-	if strings.Contains(*sso.Scope, OpenIDScope) {
+	if model.IsOpenIdProviderService(service) {
+		providerType = model.OpenIdProviderServicePrefix
+	} else if strings.Contains(*sso.Scope, OpenIDScope) {
 		providerType = model.ServiceOpenid
 	}
 	provider := einterfaces.GetOAuthProvider(providerType)
@@ -0,0 +1,229 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// OAuthDeviceVerificationPath is where the user enters the user code shown by the device.
+const OAuthDeviceVerificationPath = "/oauth/device"
+
+// CreateOAuthDeviceCode starts the device authorization grant of RFC 8628 for devices without a
+// browser, returning the codes the device shows to the user and then polls the token endpoint with.
+func (a *App) CreateOAuthDeviceCode(c request.CTX, clientId, secret, scope string) (*model.OAuthDeviceAuthorizationResponse, *model.AppError) {
+	if _, err := a.authenticateOAuthClient(clientId, secret); err != nil {
+		return nil, err
+	}
+
+	if err := a.Srv().Store().OAuth().RemoveExpiredDeviceCodes(model.GetMillis()); err != nil {
+		c.Logger().Warn("Unable to remove the expired OAuth device codes", mlog.Err(err))
+	}
+
+	deviceCode, nErr := a.Srv().Store().OAuth().SaveDeviceCode(&model.OAuthDeviceCode{ClientId: clientId, Scope: scope})
+	if nErr != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(nErr, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("CreateOAuthDeviceCode", "app.oauth.save_device_code.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+		}
+	}
+
+	verificationURI := a.GetSiteURL() + OAuthDeviceVerificationPath
+	return &model.OAuthDeviceAuthorizationResponse{
+		DeviceCode:              deviceCode.DeviceCode,
+		UserCode:                deviceCode.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(deviceCode.UserCode),
+		ExpiresIn:               model.OAuthDeviceCodeExpireTime,
+		Interval:                model.OAuthDeviceCodePollInterval,
+	}, nil
+}
+
+// getPendingOAuthDeviceCode returns the pending device code matching the user code typed by the user.
+func (a *App) getPendingOAuthDeviceCode(userCode string) (*model.OAuthDeviceCode, *model.AppError) {
+	normalized := model.NormalizeOAuthUserCode(userCode)
+	if normalized == "" {
+		return nil, model.NewAppError("getPendingOAuthDeviceCode", "api.oauth.device.invalid_user_code.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	deviceCode, err := a.Srv().Store().OAuth().GetDeviceCodeByUserCode(normalized)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("getPendingOAuthDeviceCode", "api.oauth.device.invalid_user_code.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("getPendingOAuthDeviceCode", "app.oauth.get_device_code.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	if deviceCode.Status != model.OAuthDeviceCodeStatusPending || deviceCode.IsExpired() {
+		return nil, model.NewAppError("getPendingOAuthDeviceCode", "api.oauth.device.invalid_user_code.app_error", nil, "", http.StatusNotFound)
+	}
+
+	return deviceCode, nil
+}
+
+// GetOAuthAppForDeviceCode returns the app requesting access with the user code, so that the
+// user can check it before approving the request.
+func (a *App) GetOAuthAppForDeviceCode(userCode string) (*model.OAuthApp, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return nil, model.NewAppError("GetOAuthAppForDeviceCode", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	deviceCode, err := a.getPendingOAuthDeviceCode(userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.GetOAuthApp(deviceCode.ClientId)
+}
+
+// AuthorizeOAuthDeviceCode records whether the user allowed or denied the device identified by
+// the user code. The device gets the token, or the denial, the next time it polls.
+func (a *App) AuthorizeOAuthDeviceCode(c request.CTX, userID, userCode string, allow bool) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return model.NewAppError("AuthorizeOAuthDeviceCode", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	deviceCode, err := a.getPendingOAuthDeviceCode(userCode)
+	if err != nil {
+		return err
+	}
+
+	deviceCode.UserId = userID
+	deviceCode.Status = model.OAuthDeviceCodeStatusDenied
+	if allow {
+		deviceCode.Status = model.OAuthDeviceCodeStatusApproved
+	}
+
+	updated, nErr := a.Srv().Store().OAuth().UpdateDeviceCodeOptimistically(deviceCode, model.OAuthDeviceCodeStatusPending)
+	if nErr != nil {
+		return model.NewAppError("AuthorizeOAuthDeviceCode", "app.oauth.update_device_code.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+	}
+	if !updated {
+		return model.NewAppError("AuthorizeOAuthDeviceCode", "api.oauth.device.invalid_user_code.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if !allow {
+		return nil
+	}
+
+	// This saves the OAuth2 app as authorized, as for the authorization code grant
+	authorizedApp := model.Preference{
+		UserId:   userID,
+		Category: model.PreferenceCategoryAuthorizedOAuthApp,
+		Name:     deviceCode.ClientId,
+		Value:    deviceCode.Scope,
+	}
+
+	if nErr := a.Srv().Store().Preference().Save(model.Preferences{authorizedApp}); nErr != nil {
+		c.Logger().Warn("error saving store preference", mlog.Err(nErr))
+	}
+
+	return nil
+}
+
+// GetOAuthAccessTokenForDeviceCode is polled by the device until the user allows or denies the
+// device authorization. The errors of the grant are identified by their id, see
+// OAuthDeviceCodeErrorCode.
+func (a *App) GetOAuthAccessTokenForDeviceCode(c request.CTX, clientId, secret, code string) (*model.AccessResponse, *model.AppError) {
+	oauthApp, err := a.authenticateOAuthClient(clientId, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCode, nErr := a.Srv().Store().OAuth().GetDeviceCode(code)
+	if nErr != nil || deviceCode.ClientId != clientId {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.invalid_grant.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if deviceCode.IsExpired() {
+		if nErr = a.Srv().Store().OAuth().RemoveDeviceCode(deviceCode.DeviceCode); nErr != nil {
+			c.Logger().Warn("Unable to remove the OAuth device code", mlog.Err(nErr))
+		}
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.expired_token.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	switch deviceCode.Status {
+	case model.OAuthDeviceCodeStatusPending:
+		now := model.GetMillis()
+		tooFast := now-deviceCode.LastPollAt < model.OAuthDeviceCodePollInterval*1000
+		deviceCode.LastPollAt = now
+		if _, nErr = a.Srv().Store().OAuth().UpdateDeviceCodeOptimistically(deviceCode, model.OAuthDeviceCodeStatusPending); nErr != nil {
+			c.Logger().Warn("Unable to update the OAuth device code", mlog.Err(nErr))
+		}
+
+		if tooFast {
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.slow_down.app_error", nil, "", http.StatusBadRequest)
+		}
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.authorization_pending.app_error", nil, "", http.StatusBadRequest)
+	case model.OAuthDeviceCodeStatusDenied:
+		if nErr = a.Srv().Store().OAuth().RemoveDeviceCode(deviceCode.DeviceCode); nErr != nil {
+			c.Logger().Warn("Unable to remove the OAuth device code", mlog.Err(nErr))
+		}
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.access_denied.app_error", nil, "", http.StatusBadRequest)
+	case model.OAuthDeviceCodeStatusApproved:
+	default:
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.invalid_grant.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	// Mark the device code as used first so that concurrent polls can't get a token each.
+	deviceCode.Status = model.OAuthDeviceCodeStatusUsed
+	updated, nErr := a.Srv().Store().OAuth().UpdateDeviceCodeOptimistically(deviceCode, model.OAuthDeviceCodeStatusApproved)
+	if nErr != nil {
+		return nil, model.NewAppError("GetOAuthAccessToken", "app.oauth.update_device_code.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+	}
+	if !updated {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.invalid_grant.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	defer func() {
+		if nErr := a.Srv().Store().OAuth().RemoveDeviceCode(deviceCode.DeviceCode); nErr != nil {
+			c.Logger().Warn("Unable to remove the OAuth device code", mlog.Err(nErr))
+		}
+	}()
+
+	user, nErr := a.Srv().Store().User().Get(context.Background(), deviceCode.UserId)
+	if nErr != nil {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if user.DeleteAt != 0 {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.device_code.access_denied.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return a.issueOAuthAccessToken(c, oauthApp, user, model.DeviceCodeGrantType, "", deviceCode.Scope)
+}
+
+// OAuthDeviceCodeErrorCode returns the error code of RFC 8628 matching the error returned by
+// GetOAuthAccessTokenForDeviceCode, or an empty string for other errors.
+func OAuthDeviceCodeErrorCode(err *model.AppError) string {
+	switch err.Id {
+	case "api.oauth.get_access_token.device_code.authorization_pending.app_error":
+		return model.OAuthErrorAuthorizationPending
+	case "api.oauth.get_access_token.device_code.slow_down.app_error":
+		return model.OAuthErrorSlowDown
+	case "api.oauth.get_access_token.device_code.access_denied.app_error":
+		return model.OAuthErrorAccessDenied
+	case "api.oauth.get_access_token.device_code.expired_token.app_error":
+		return model.OAuthErrorExpiredToken
+	case "api.oauth.get_access_token.device_code.invalid_grant.app_error":
+		return model.OAuthErrorInvalidGrant
+	default:
+		return ""
+	}
+}
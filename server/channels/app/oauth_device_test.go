@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestOAuthDeviceCodeFlow(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	oapp, appErr := th.App.CreateOAuthApp(&model.OAuthApp{
+		Name:         "fakeoauthapp" + model.NewRandomString(10),
+		CreatorId:    th.BasicUser2.Id,
+		Homepage:     "https://nowhere.com",
+		Description:  "test",
+		CallbackUrls: []string{"https://nowhere.com"},
+		IsPublic:     true,
+	})
+	require.Nil(t, appErr)
+
+	pollErrorCode := func(t *testing.T, deviceCode string) string {
+		t.Helper()
+		_, appErr := th.App.GetOAuthAccessTokenForDeviceCode(th.Context, oapp.Id, "", deviceCode)
+		require.NotNil(t, appErr)
+		return OAuthDeviceCodeErrorCode(appErr)
+	}
+
+	t.Run("unknown app", func(t *testing.T) {
+		_, appErr := th.App.CreateOAuthDeviceCode(th.Context, model.NewId(), "", "")
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("invalid user code", func(t *testing.T) {
+		appErr := th.App.AuthorizeOAuthDeviceCode(th.Context, th.BasicUser.Id, "AEIO-UAEI", true)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.oauth.device.invalid_user_code.app_error", appErr.Id)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		deviceRsp, appErr := th.App.CreateOAuthDeviceCode(th.Context, oapp.Id, "", "")
+		require.Nil(t, appErr)
+		assert.Equal(t, model.OAuthDeviceCodePollInterval, deviceRsp.Interval)
+		assert.Contains(t, deviceRsp.VerificationURIComplete, deviceRsp.UserCode)
+
+		assert.Equal(t, model.OAuthErrorAuthorizationPending, pollErrorCode(t, deviceRsp.DeviceCode))
+		assert.Equal(t, model.OAuthErrorSlowDown, pollErrorCode(t, deviceRsp.DeviceCode))
+
+		app, appErr := th.App.GetOAuthAppForDeviceCode(deviceRsp.UserCode)
+		require.Nil(t, appErr)
+		assert.Equal(t, oapp.Id, app.Id)
+
+		// The user code is accepted whatever its case and separators.
+		userCode := deviceRsp.UserCode[:4] + deviceRsp.UserCode[5:]
+		require.Nil(t, th.App.AuthorizeOAuthDeviceCode(th.Context, th.BasicUser.Id, userCode, true))
+
+		appErr = th.App.AuthorizeOAuthDeviceCode(th.Context, th.BasicUser.Id, deviceRsp.UserCode, true)
+		require.NotNil(t, appErr, "the user code can only be used once")
+
+		rsp, appErr := th.App.GetOAuthAccessTokenForDeviceCode(th.Context, oapp.Id, "", deviceRsp.DeviceCode)
+		require.Nil(t, appErr)
+		assert.NotEmpty(t, rsp.AccessToken)
+
+		session, appErr := th.App.GetSession(rsp.AccessToken)
+		require.Nil(t, appErr)
+		assert.Equal(t, th.BasicUser.Id, session.UserId)
+
+		assert.Equal(t, model.OAuthErrorInvalidGrant, pollErrorCode(t, deviceRsp.DeviceCode))
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		deviceRsp, appErr := th.App.CreateOAuthDeviceCode(th.Context, oapp.Id, "", "")
+		require.Nil(t, appErr)
+
+		require.Nil(t, th.App.AuthorizeOAuthDeviceCode(th.Context, th.BasicUser.Id, deviceRsp.UserCode, false))
+		assert.Equal(t, model.OAuthErrorAccessDenied, pollErrorCode(t, deviceRsp.DeviceCode))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		deviceCode, err := th.App.Srv().Store().OAuth().SaveDeviceCode(&model.OAuthDeviceCode{
+			ClientId:  oapp.Id,
+			CreateAt:  model.GetMillis() - 2000,
+			ExpiresAt: model.GetMillis() - 1000,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.OAuthErrorExpiredToken, pollErrorCode(t, deviceCode.DeviceCode))
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/einterfaces"
 	"github.com/mattermost/mattermost/server/v8/einterfaces/mocks"
@@ -671,9 +673,219 @@ func TestDeactivatedUserOAuthApp(t *testing.T) {
 	_, appErr := th.App.UpdateActive(th.Context, th.BasicUser, false)
 	require.Nil(t, appErr)
 
-	resp, accErr := th.App.GetOAuthAccessTokenForCodeFlow(th.Context, oapp.Id, model.AccessTokenGrantType, oapp.CallbackUrls[0], code, oapp.ClientSecret, "")
+	resp, accErr := th.App.GetOAuthAccessTokenForCodeFlow(th.Context, oapp.Id, model.AccessTokenGrantType, oapp.CallbackUrls[0], code, oapp.ClientSecret, "", "")
 	assert.Nil(t, resp)
 	require.NotNil(t, accErr, "Should not get access token")
 	require.Equal(t, http.StatusBadRequest, accErr.StatusCode)
 	assert.Equal(t, "api.oauth.get_access_token.expired_code.app_error", accErr.Id)
 }
+
+func TestGetOAuthAccessTokenForCodeFlowWithPKCE(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	oapp, appErr := th.App.CreateOAuthApp(&model.OAuthApp{
+		Name:         "fakeoauthapp" + model.NewRandomString(10),
+		CreatorId:    th.BasicUser2.Id,
+		Homepage:     "https://nowhere.com",
+		Description:  "test",
+		CallbackUrls: []string{"https://nowhere.com"},
+		IsPublic:     true,
+	})
+	require.Nil(t, appErr)
+	require.Empty(t, oapp.ClientSecret)
+
+	// Example from RFC 7636, appendix B.
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	authRequest := &model.AuthorizeRequest{
+		ResponseType: model.AuthCodeResponseType,
+		ClientId:     oapp.Id,
+		RedirectURI:  oapp.CallbackUrls[0],
+		State:        "123",
+	}
+
+	t.Run("public app requires a code challenge", func(t *testing.T) {
+		_, appErr := th.App.AllowOAuthAppAccessToUser(th.Context, th.BasicUser.Id, authRequest)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.oauth.allow_oauth.pkce_required.app_error", appErr.Id)
+	})
+
+	t.Run("public app requires the S256 method", func(t *testing.T) {
+		plainRequest := *authRequest
+		plainRequest.CodeChallenge = verifier
+		for _, method := range []string{"", model.PKCEMethodPlain} {
+			plainRequest.CodeChallengeMethod = method
+			_, appErr := th.App.AllowOAuthAppAccessToUser(th.Context, th.BasicUser.Id, &plainRequest)
+			require.NotNil(t, appErr, method)
+			assert.Equal(t, "api.oauth.allow_oauth.pkce_required.app_error", appErr.Id)
+		}
+	})
+
+	authRequest.CodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	authRequest.CodeChallengeMethod = model.PKCEMethodS256
+
+	getCode := func(t *testing.T) string {
+		redirectURL, appErr := th.App.AllowOAuthAppAccessToUser(th.Context, th.BasicUser.Id, authRequest)
+		require.Nil(t, appErr)
+		uri, err := url.Parse(redirectURL)
+		require.NoError(t, err)
+		return uri.Query().Get("code")
+	}
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		_, appErr := th.App.GetOAuthAccessTokenForCodeFlow(th.Context, oapp.Id, model.AccessTokenGrantType, oapp.CallbackUrls[0], getCode(t), "", "", model.NewRandomString(43))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.oauth.get_access_token.code_verifier.app_error", appErr.Id)
+	})
+
+	t.Run("secret sent by a public app", func(t *testing.T) {
+		_, appErr := th.App.GetOAuthAccessTokenForCodeFlow(th.Context, oapp.Id, model.AccessTokenGrantType, oapp.CallbackUrls[0], getCode(t), model.NewId(), "", verifier)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("valid code verifier", func(t *testing.T) {
+		rsp, appErr := th.App.GetOAuthAccessTokenForCodeFlow(th.Context, oapp.Id, model.AccessTokenGrantType, oapp.CallbackUrls[0], getCode(t), "", "", verifier)
+		require.Nil(t, appErr)
+		assert.NotEmpty(t, rsp.AccessToken)
+		assert.NotEmpty(t, rsp.RefreshToken)
+
+		accessData, err := th.App.Srv().Store().OAuth().GetAccessData(rsp.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, model.AccessTokenGrantType, accessData.GrantType)
+	})
+}
+
+func TestGetOAuthAccessTokenForClientCredentials(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	bot, appErr := th.App.CreateBot(th.Context, &model.Bot{
+		Username: "bot" + model.NewRandomString(10),
+		OwnerId:  th.BasicUser.Id,
+	})
+	require.Nil(t, appErr)
+
+	oapp, appErr := th.App.CreateOAuthApp(&model.OAuthApp{
+		Name:         "fakeoauthapp" + model.NewRandomString(10),
+		CreatorId:    th.BasicUser.Id,
+		Homepage:     "https://nowhere.com",
+		Description:  "test",
+		CallbackUrls: []string{"https://nowhere.com"},
+	})
+	require.Nil(t, appErr)
+
+	t.Run("app without a bot user", func(t *testing.T) {
+		_, appErr := th.App.GetOAuthAccessTokenForClientCredentials(th.Context, oapp.Id, oapp.ClientSecret, "")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.oauth.get_access_token.client_credentials.app_error", appErr.Id)
+	})
+
+	updatedApp := *oapp
+	updatedApp.BotUserId = bot.UserId
+	oapp, appErr = th.App.UpdateOAuthApp(oapp, &updatedApp)
+	require.Nil(t, appErr)
+
+	t.Run("bad secret", func(t *testing.T) {
+		_, appErr := th.App.GetOAuthAccessTokenForClientCredentials(th.Context, oapp.Id, model.NewId(), "")
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("issues a token for the bot", func(t *testing.T) {
+		rsp, appErr := th.App.GetOAuthAccessTokenForClientCredentials(th.Context, oapp.Id, oapp.ClientSecret, "")
+		require.Nil(t, appErr)
+		assert.NotEmpty(t, rsp.AccessToken)
+		assert.Empty(t, rsp.RefreshToken)
+
+		session, appErr := th.App.GetSession(rsp.AccessToken)
+		require.Nil(t, appErr)
+		assert.Equal(t, bot.UserId, session.UserId)
+		assert.True(t, session.IsOAuth)
+
+		// The token is reused until it expires.
+		rsp2, appErr := th.App.GetOAuthAccessTokenForClientCredentials(th.Context, oapp.Id, oapp.ClientSecret, "")
+		require.Nil(t, appErr)
+		assert.Equal(t, rsp.AccessToken, rsp2.AccessToken)
+	})
+}
+
+// oauthGroupsProviderMock adds group reporting to the generated provider mock.
+type oauthGroupsProviderMock struct {
+	*mocks.OAuthProvider
+	groups []string
+}
+
+func (p *oauthGroupsProviderMock) GetGroupsFromJSON(_ request.CTX, _ io.Reader, _ *model.User) ([]string, error) {
+	return p.groups, nil
+}
+
+func TestSyncOAuthGroups(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		provider := &model.OpenIdProviderSettings{
+			Id:                model.NewPointer("mock"),
+			Enable:            model.NewPointer(true),
+			ClientId:          model.NewPointer("client"),
+			DiscoveryEndpoint: model.NewPointer("https://idp.example.com/.well-known/openid-configuration"),
+		}
+		provider.SetDefaults()
+		cfg.OpenIdConnectSettings.Providers = []*model.OpenIdProviderSettings{provider}
+	})
+
+	provider := &oauthGroupsProviderMock{OAuthProvider: &mocks.OAuthProvider{}}
+	einterfaces.RegisterOAuthProvider(model.OpenIdProviderServicePrefix, provider)
+	defer einterfaces.RegisterOAuthProvider(model.OpenIdProviderServicePrefix, nil)
+
+	groupIDsOf := func(t *testing.T, userID string) map[string]string {
+		groups, appErr := th.App.GetGroupsByUserId(userID)
+		require.Nil(t, appErr)
+		ids := map[string]string{}
+		for _, group := range groups {
+			if group.Source == model.GroupSourceOpenId {
+				ids[group.DisplayName] = group.Id
+			}
+		}
+		return ids
+	}
+
+	provider.groups = []string{"admins", "developers"}
+	th.App.syncOAuthGroups(th.Context, "oidcmock", strings.NewReader("{}"), th.BasicUser, nil)
+
+	groups := groupIDsOf(t, th.BasicUser.Id)
+	require.Len(t, groups, 2)
+	group, appErr := th.App.GetGroupByRemoteID("oidcmock:admins", model.GroupSourceOpenId)
+	require.Nil(t, appErr)
+	assert.Equal(t, groups["admins"], group.Id)
+
+	t.Run("existing groups are reused and stale memberships removed", func(t *testing.T) {
+		provider.groups = []string{"developers", "ops"}
+		th.App.syncOAuthGroups(th.Context, "oidcmock", strings.NewReader("{}"), th.BasicUser, nil)
+
+		updated := groupIDsOf(t, th.BasicUser.Id)
+		require.Len(t, updated, 2)
+		assert.Equal(t, groups["developers"], updated["developers"])
+		assert.NotContains(t, updated, "admins")
+		assert.Contains(t, updated, "ops")
+	})
+
+	t.Run("other users share the groups", func(t *testing.T) {
+		provider.groups = []string{"ops"}
+		th.App.syncOAuthGroups(th.Context, "oidcmock", strings.NewReader("{}"), th.BasicUser2, nil)
+
+		assert.Equal(t, groupIDsOf(t, th.BasicUser.Id)["ops"], groupIDsOf(t, th.BasicUser2.Id)["ops"])
+	})
+
+	t.Run("long group names", func(t *testing.T) {
+		remoteID := oauthGroupRemoteID("oidcmock", strings.Repeat("a", 100))
+		assert.Len(t, remoteID, model.GroupRemoteIDMaxLength)
+		assert.True(t, strings.HasPrefix(remoteID, "oidcmock:"))
+		assert.NotEqual(t, remoteID, oauthGroupRemoteID("oidcmock", strings.Repeat("b", 100)))
+	})
+}
@@ -0,0 +1,469 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthopenid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+)
+
+const (
+	// discoveryCacheTTL is how long a provider's discovery document is
+	// reused before it is fetched again.
+	discoveryCacheTTL = time.Hour
+
+	// jwksCacheTTL is how long a provider's signing keys are reused before
+	// they are fetched again.
+	jwksCacheTTL = time.Hour
+
+	// jwksMinRefreshInterval throttles refetching the signing keys when a
+	// token references a key id that isn't cached.
+	jwksMinRefreshInterval = time.Minute
+
+	httpRequestTimeout = 30 * time.Second
+	maxResponseSize    = 1024 * 1024
+)
+
+// OpenIdProvider implements einterfaces.OAuthProvider for every identity
+// provider configured in OpenIdConnectSettings. Each one is exposed as its own
+// login service, see model.OpenIdProviderSettings.Service.
+type OpenIdProvider struct {
+	httpClient             *http.Client
+	jwksMinRefreshInterval time.Duration
+
+	mut     sync.Mutex
+	issuers map[string]*issuer
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// issuer holds what has been learned about a single configured identity
+// provider: its settings, discovery document and signing keys.
+type issuer struct {
+	service   string
+	settings  model.OpenIdProviderSettings
+	discovery *discoveryDocument
+	fetchedAt time.Time
+
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+func init() {
+	einterfaces.RegisterOAuthProvider(model.OpenIdProviderServicePrefix, New())
+}
+
+func New() *OpenIdProvider {
+	return &OpenIdProvider{
+		httpClient:             &http.Client{Timeout: httpRequestTimeout},
+		jwksMinRefreshInterval: jwksMinRefreshInterval,
+		issuers:                make(map[string]*issuer),
+	}
+}
+
+func (p *OpenIdProvider) GetSSOSettings(_ request.CTX, config *model.Config, service string) (*model.SSOSettings, error) {
+	settings := config.OpenIdConnectSettings.GetProvider(service)
+	if settings == nil {
+		return nil, fmt.Errorf("no OpenID Connect provider configured for service %q", service)
+	}
+
+	iss, err := p.getIssuer(service, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	ssoSettings := settings.SSOSettings()
+	ssoSettings.AuthEndpoint = model.NewPointer(iss.discovery.AuthorizationEndpoint)
+	ssoSettings.TokenEndpoint = model.NewPointer(iss.discovery.TokenEndpoint)
+	ssoSettings.UserAPIEndpoint = model.NewPointer(iss.discovery.UserinfoEndpoint)
+	return ssoSettings, nil
+}
+
+// GetUserFromIdToken verifies the ID token against the signing keys of the
+// provider that issued it and maps its claims onto a user.
+func (p *OpenIdProvider) GetUserFromIdToken(c request.CTX, idToken string) (*model.User, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(idToken, jwt.MapClaims{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse ID token")
+	}
+
+	issuerURL, err := unverified.Claims.GetIssuer()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ID token issuer")
+	}
+	audience, err := unverified.Claims.GetAudience()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ID token audience")
+	}
+
+	iss := p.issuerForToken(issuerURL, audience)
+	if iss == nil {
+		return nil, fmt.Errorf("ID token issued by unknown issuer %q", issuerURL)
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512"}),
+		jwt.WithIssuer(iss.discovery.Issuer),
+		jwt.WithAudience(*iss.settings.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+
+	claims := jwt.MapClaims{}
+	if _, err = parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getSigningKey(iss, kid)
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to verify ID token")
+	}
+
+	user := userFromClaims(c.Logger(), &iss.settings, claims)
+	user.AuthService = iss.service
+	if user.AuthData == nil || *user.AuthData == "" {
+		return nil, errors.New("ID token is missing the sub claim")
+	}
+
+	return user, nil
+}
+
+// GetUserFromJSON maps the userinfo response onto a user. The generic
+// provider requires an ID token, so tokenUser identifies which provider the
+// response came from and any claim missing from the response is taken from it.
+func (p *OpenIdProvider) GetUserFromJSON(c request.CTX, data io.Reader, tokenUser *model.User) (*model.User, error) {
+	if tokenUser == nil || tokenUser.AuthData == nil {
+		return nil, errors.New("an ID token is required to log in with OpenID Connect")
+	}
+
+	iss := p.issuerForService(tokenUser.AuthService)
+	if iss == nil {
+		return nil, fmt.Errorf("no OpenID Connect provider configured for service %q", tokenUser.AuthService)
+	}
+
+	claims, err := claimsFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The userinfo response must be about the user the ID token was issued to.
+	// See https://openid.net/specs/openid-connect-core-1_0.html#UserInfoResponse
+	if sub, ok := claims["sub"].(string); !ok || sub != *tokenUser.AuthData {
+		return nil, errors.New("userinfo subject does not match the ID token subject")
+	}
+
+	user := userFromClaims(c.Logger(), &iss.settings, claims)
+	user.AuthService = iss.service
+	if user.Username == "" {
+		user.Username = tokenUser.Username
+	}
+	if user.Email == "" {
+		user.Email = tokenUser.Email
+	}
+	// The email_verified claim may only be part of the ID token.
+	if user.Email == tokenUser.Email && tokenUser.EmailVerified {
+		user.EmailVerified = true
+	}
+	if user.FirstName == "" && user.LastName == "" {
+		user.FirstName = tokenUser.FirstName
+		user.LastName = tokenUser.LastName
+	}
+
+	if user.Email == "" {
+		return nil, errors.New("user e-mail should not be empty")
+	}
+	if user.Username == "" {
+		user.Username = model.CleanUsername(c.Logger(), strings.Split(user.Email, "@")[0])
+	}
+
+	return user, nil
+}
+
+// GetGroupsFromJSON returns the values of the configured groups claim in the
+// userinfo response, or nil when no groups claim is configured.
+func (p *OpenIdProvider) GetGroupsFromJSON(_ request.CTX, data io.Reader, tokenUser *model.User) ([]string, error) {
+	if tokenUser == nil {
+		return nil, errors.New("an ID token is required to log in with OpenID Connect")
+	}
+
+	iss := p.issuerForService(tokenUser.AuthService)
+	if iss == nil {
+		return nil, fmt.Errorf("no OpenID Connect provider configured for service %q", tokenUser.AuthService)
+	}
+
+	if *iss.settings.GroupsClaim == "" {
+		return nil, nil
+	}
+
+	claims, err := claimsFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return stringsClaim(claims, *iss.settings.GroupsClaim), nil
+}
+
+func (p *OpenIdProvider) IsSameUser(_ request.CTX, dbUser, oauthUser *model.User) bool {
+	return dbUser.AuthData != nil && oauthUser.AuthData != nil && *dbUser.AuthData == *oauthUser.AuthData
+}
+
+// getIssuer returns the cached issuer for the service, fetching the
+// discovery document when it is missing, stale or the settings changed.
+func (p *OpenIdProvider) getIssuer(service string, settings *model.OpenIdProviderSettings) (*issuer, error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	// Issuers are replaced rather than updated so that the settings and
+	// discovery document can be read without holding the lock.
+	iss := p.issuers[service]
+	if iss != nil && *iss.settings.DiscoveryEndpoint == *settings.DiscoveryEndpoint && time.Since(iss.fetchedAt) < discoveryCacheTTL {
+		if iss.settings != *settings {
+			iss = &issuer{
+				service:       service,
+				settings:      *settings,
+				discovery:     iss.discovery,
+				fetchedAt:     iss.fetchedAt,
+				keys:          iss.keys,
+				keysFetchedAt: iss.keysFetchedAt,
+			}
+			p.issuers[service] = iss
+		}
+		return iss, nil
+	}
+
+	discovery, err := p.fetchDiscovery(*settings.DiscoveryEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	newIssuer := &issuer{
+		service:   service,
+		settings:  *settings,
+		discovery: discovery,
+		fetchedAt: time.Now(),
+	}
+	// Keep the signing keys around if the provider is still using the same ones.
+	if iss != nil && iss.discovery.JwksURI == discovery.JwksURI {
+		newIssuer.keys = iss.keys
+		newIssuer.keysFetchedAt = iss.keysFetchedAt
+	}
+	p.issuers[service] = newIssuer
+
+	return newIssuer, nil
+}
+
+func (p *OpenIdProvider) issuerForService(service string) *issuer {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	return p.issuers[service]
+}
+
+// issuerForToken finds the issuer matching a token's iss and aud claims.
+// Several providers may share an issuer URL with different client ids.
+func (p *OpenIdProvider) issuerForToken(issuerURL string, audience []string) *issuer {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	for _, iss := range p.issuers {
+		if iss.discovery.Issuer != issuerURL {
+			continue
+		}
+		for _, aud := range audience {
+			if aud == *iss.settings.ClientId {
+				return iss
+			}
+		}
+	}
+
+	return nil
+}
+
+// getSigningKey returns the key with the given id, refetching the key set
+// when it is stale or doesn't contain the key, e.g. after a key rotation.
+func (p *OpenIdProvider) getSigningKey(iss *issuer, kid string) (any, error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	key, ok := lookupKey(iss.keys, kid)
+	stale := time.Since(iss.keysFetchedAt) >= jwksCacheTTL
+	if ok && !stale {
+		return key, nil
+	}
+
+	if !stale && time.Since(iss.keysFetchedAt) < p.jwksMinRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := p.fetchKeys(iss.discovery.JwksURI)
+	if err != nil {
+		return nil, err
+	}
+	iss.keys = keys
+	iss.keysFetchedAt = time.Now()
+
+	key, ok = lookupKey(keys, kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+// lookupKey returns the key with the given id. Tokens without a key id may
+// only be used with providers publishing a single key.
+func lookupKey(keys map[string]any, kid string) (any, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+	return key, ok
+}
+
+func (p *OpenIdProvider) fetchDiscovery(discoveryEndpoint string) (*discoveryDocument, error) {
+	var discovery discoveryDocument
+	if err := p.getJSON(discoveryEndpoint, &discovery); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch OpenID Connect discovery document")
+	}
+
+	if discovery.Issuer == "" || discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" || discovery.JwksURI == "" {
+		return nil, errors.New("OpenID Connect discovery document is missing required endpoints")
+	}
+
+	return &discovery, nil
+}
+
+func (p *OpenIdProvider) fetchKeys(jwksURI string) (map[string]any, error) {
+	var set jsonWebKeySet
+	if err := p.getJSON(jwksURI, &set); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch OpenID Connect signing keys")
+	}
+
+	return set.signingKeys(), nil
+}
+
+func (p *OpenIdProvider) getJSON(url string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
+
+func claimsFromJSON(data io.Reader) (map[string]any, error) {
+	var claims map[string]any
+	if err := json.NewDecoder(data).Decode(&claims); err != nil {
+		return nil, errors.Wrap(err, "failed to decode OpenID Connect claims")
+	}
+
+	return claims, nil
+}
+
+func userFromClaims(logger mlog.LoggerIFace, settings *model.OpenIdProviderSettings, claims map[string]any) *model.User {
+	user := &model.User{}
+	if sub := stringClaim(claims, "sub"); sub != "" {
+		user.AuthData = model.NewPointer(sub)
+	}
+	if username := stringClaim(claims, *settings.UsernameClaim); username != "" {
+		user.Username = model.CleanUsername(logger, username)
+	}
+	user.Email = strings.ToLower(stringClaim(claims, *settings.EmailClaim))
+	user.EmailVerified = user.Email != "" && (*settings.AssumeEmailVerified || boolClaim(claims, "email_verified"))
+	user.FirstName = stringClaim(claims, *settings.FirstNameClaim)
+	user.LastName = stringClaim(claims, *settings.LastNameClaim)
+
+	return user
+}
+
+// lookupClaim returns the value of a claim. Nested claims, such as Keycloak's
+// realm_access.roles, are addressed with dots.
+func lookupClaim(claims map[string]any, name string) any {
+	if name == "" {
+		return nil
+	}
+
+	if value, ok := claims[name]; ok {
+		return value
+	}
+
+	parent, child, found := strings.Cut(name, ".")
+	if !found {
+		return nil
+	}
+	nested, ok := claims[parent].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return lookupClaim(nested, child)
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	value, _ := lookupClaim(claims, name).(string)
+	return value
+}
+
+// boolClaim returns a boolean claim, which some providers send as a string.
+func boolClaim(claims map[string]any, name string) bool {
+	switch value := lookupClaim(claims, name).(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(value, "true")
+	}
+	return false
+}
+
+// stringsClaim returns a claim that may either be a single string or a list.
+func stringsClaim(claims map[string]any, name string) []string {
+	switch value := lookupClaim(claims, name).(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthopenid
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// mockIssuer is an in-process OpenID Connect provider serving a discovery
+// document and a key set, and signing ID tokens with its current key.
+type mockIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	kid           string
+	key           *rsa.PrivateKey
+	discoveryHits atomic.Int32
+	jwksHits      atomic.Int32
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	m := &mockIssuer{t: t}
+	m.rotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		m.discoveryHits.Add(1)
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"userinfo_endpoint":      m.server.URL + "/userinfo",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.jwksHits.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"use": "sig",
+				"kid": m.kid,
+				"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			}},
+		})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+func (m *mockIssuer) rotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(m.t, err)
+	m.key = key
+	m.kid = model.NewId()
+}
+
+func (m *mockIssuer) idToken(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	signed, err := token.SignedString(m.key)
+	require.NoError(m.t, err)
+	return signed
+}
+
+func (m *mockIssuer) claims(clientID string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                m.server.URL,
+		"aud":                clientID,
+		"sub":                "user-1234",
+		"iat":                time.Now().Unix(),
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "Jane.Doe",
+		"email":              "Jane@Example.com",
+		"given_name":         "Jane",
+		"family_name":        "Doe",
+	}
+}
+
+func (m *mockIssuer) config(id, clientID string) *model.Config {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	provider := &model.OpenIdProviderSettings{
+		Id:                model.NewPointer(id),
+		Enable:            model.NewPointer(true),
+		ClientId:          model.NewPointer(clientID),
+		ClientSecret:      model.NewPointer("secret"),
+		DiscoveryEndpoint: model.NewPointer(m.server.URL + "/.well-known/openid-configuration"),
+		GroupsClaim:       model.NewPointer("realm_access.roles"),
+	}
+	provider.SetDefaults()
+	cfg.OpenIdConnectSettings.Providers = []*model.OpenIdProviderSettings{provider}
+	return cfg
+}
+
+func TestGetSSOSettings(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := New()
+	c := request.TestContext(t)
+
+	sso, err := p.GetSSOSettings(c, issuer.config("mock", "client"), "oidcmock")
+	require.NoError(t, err)
+	assert.Equal(t, "client", *sso.Id)
+	assert.Equal(t, "secret", *sso.Secret)
+	assert.Equal(t, issuer.server.URL+"/authorize", *sso.AuthEndpoint)
+	assert.Equal(t, issuer.server.URL+"/token", *sso.TokenEndpoint)
+	assert.Equal(t, issuer.server.URL+"/userinfo", *sso.UserAPIEndpoint)
+
+	t.Run("discovery document is cached", func(t *testing.T) {
+		_, err := p.GetSSOSettings(c, issuer.config("mock", "client"), "oidcmock")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), issuer.discoveryHits.Load())
+	})
+
+	t.Run("unknown service", func(t *testing.T) {
+		_, err := p.GetSSOSettings(c, issuer.config("mock", "client"), "oidcother")
+		require.Error(t, err)
+	})
+
+	t.Run("discovery failure", func(t *testing.T) {
+		cfg := issuer.config("broken", "client")
+		*cfg.OpenIdConnectSettings.Providers[0].DiscoveryEndpoint = issuer.server.URL + "/missing"
+		_, err := p.GetSSOSettings(c, cfg, "oidcbroken")
+		require.Error(t, err)
+	})
+}
+
+func TestGetUserFromIdToken(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := New()
+	c := request.TestContext(t)
+
+	_, err := p.GetSSOSettings(c, issuer.config("mock", "client"), "oidcmock")
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		user, err := p.GetUserFromIdToken(c, issuer.idToken(issuer.claims("client")))
+		require.NoError(t, err)
+		assert.Equal(t, "oidcmock", user.AuthService)
+		assert.Equal(t, "user-1234", *user.AuthData)
+		assert.Equal(t, "jane.doe", user.Username)
+		assert.Equal(t, "jane@example.com", user.Email)
+		assert.Equal(t, "Jane", user.FirstName)
+		assert.Equal(t, "Doe", user.LastName)
+		assert.False(t, user.EmailVerified, "the email is not verified without the email_verified claim")
+	})
+
+	t.Run("verified email", func(t *testing.T) {
+		for _, verified := range []any{true, "true"} {
+			claims := issuer.claims("client")
+			claims["email_verified"] = verified
+			user, err := p.GetUserFromIdToken(c, issuer.idToken(claims))
+			require.NoError(t, err)
+			assert.True(t, user.EmailVerified)
+		}
+
+		claims := issuer.claims("client")
+		claims["email_verified"] = false
+		user, err := p.GetUserFromIdToken(c, issuer.idToken(claims))
+		require.NoError(t, err)
+		assert.False(t, user.EmailVerified)
+	})
+
+	t.Run("signing keys are cached", func(t *testing.T) {
+		_, err := p.GetUserFromIdToken(c, issuer.idToken(issuer.claims("client")))
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), issuer.jwksHits.Load())
+	})
+
+	t.Run("rotated key is fetched", func(t *testing.T) {
+		p.jwksMinRefreshInterval = 0
+		defer func() { p.jwksMinRefreshInterval = jwksMinRefreshInterval }()
+
+		issuer.rotateKey()
+		_, err := p.GetUserFromIdToken(c, issuer.idToken(issuer.claims("client")))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), issuer.jwksHits.Load())
+	})
+
+	t.Run("unknown key is not refetched within the refresh interval", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.claims("client"))
+		token.Header["kid"] = "unknown"
+		signed, err := token.SignedString(issuer.key)
+		require.NoError(t, err)
+
+		_, err = p.GetUserFromIdToken(c, signed)
+		require.Error(t, err)
+		assert.Equal(t, int32(2), issuer.jwksHits.Load())
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		_, err := p.GetUserFromIdToken(c, issuer.idToken(issuer.claims("other-client")))
+		require.Error(t, err)
+	})
+
+	t.Run("unknown issuer", func(t *testing.T) {
+		claims := issuer.claims("client")
+		claims["iss"] = "https://unknown.example.com"
+		_, err := p.GetUserFromIdToken(c, issuer.idToken(claims))
+		require.Error(t, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := issuer.claims("client")
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := p.GetUserFromIdToken(c, issuer.idToken(claims))
+		require.Error(t, err)
+	})
+
+	t.Run("forged signature", func(t *testing.T) {
+		forger, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.claims("client"))
+		token.Header["kid"] = issuer.kid
+		signed, err := token.SignedString(forger)
+		require.NoError(t, err)
+
+		_, err = p.GetUserFromIdToken(c, signed)
+		require.Error(t, err)
+	})
+
+	t.Run("unsigned token", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, issuer.claims("client"))
+		signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = p.GetUserFromIdToken(c, signed)
+		require.Error(t, err)
+	})
+}
+
+func TestMultipleProviders(t *testing.T) {
+	first := newMockIssuer(t)
+	second := newMockIssuer(t)
+	p := New()
+	c := request.TestContext(t)
+
+	_, err := p.GetSSOSettings(c, first.config("first", "client"), "oidcfirst")
+	require.NoError(t, err)
+	_, err = p.GetSSOSettings(c, second.config("second", "client"), "oidcsecond")
+	require.NoError(t, err)
+
+	user, err := p.GetUserFromIdToken(c, first.idToken(first.claims("client")))
+	require.NoError(t, err)
+	assert.Equal(t, "oidcfirst", user.AuthService)
+
+	user, err = p.GetUserFromIdToken(c, second.idToken(second.claims("client")))
+	require.NoError(t, err)
+	assert.Equal(t, "oidcsecond", user.AuthService)
+
+	// A token signed by one provider must not be accepted as the other's.
+	claims := first.claims("client")
+	claims["iss"] = second.server.URL
+	_, err = p.GetUserFromIdToken(c, first.idToken(claims))
+	require.Error(t, err)
+}
+
+func TestGetUserFromJSON(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := New()
+	c := request.TestContext(t)
+
+	cfg := issuer.config("mock", "client")
+	*cfg.OpenIdConnectSettings.Providers[0].UsernameClaim = "nickname"
+	_, err := p.GetSSOSettings(c, cfg, "oidcmock")
+	require.NoError(t, err)
+
+	tokenUser, err := p.GetUserFromIdToken(c, issuer.idToken(issuer.claims("client")))
+	require.NoError(t, err)
+
+	t.Run("claims are merged with the ID token", func(t *testing.T) {
+		user, err := p.GetUserFromJSON(c, strings.NewReader(`{"sub": "user-1234", "nickname": "jdoe", "given_name": "Janet", "family_name": "Doe"}`), tokenUser)
+		require.NoError(t, err)
+		assert.Equal(t, "oidcmock", user.AuthService)
+		assert.Equal(t, "user-1234", *user.AuthData)
+		assert.Equal(t, "jdoe", user.Username)
+		assert.Equal(t, "jane@example.com", user.Email)
+		assert.Equal(t, "Janet", user.FirstName)
+		assert.False(t, user.EmailVerified)
+		assert.True(t, p.IsSameUser(c, tokenUser, user))
+	})
+
+	t.Run("email verified in the ID token", func(t *testing.T) {
+		claims := issuer.claims("client")
+		claims["email_verified"] = true
+		verifiedTokenUser, err := p.GetUserFromIdToken(c, issuer.idToken(claims))
+		require.NoError(t, err)
+
+		user, err := p.GetUserFromJSON(c, strings.NewReader(`{"sub": "user-1234", "email": "jane@example.com"}`), verifiedTokenUser)
+		require.NoError(t, err)
+		assert.True(t, user.EmailVerified)
+
+		user, err = p.GetUserFromJSON(c, strings.NewReader(`{"sub": "user-1234", "email": "other@example.com"}`), verifiedTokenUser)
+		require.NoError(t, err)
+		assert.False(t, user.EmailVerified, "another email address is not verified by the ID token")
+	})
+
+	t.Run("email assumed verified", func(t *testing.T) {
+		cfg := issuer.config("assumed", "assumed-client")
+		*cfg.OpenIdConnectSettings.Providers[0].AssumeEmailVerified = true
+		_, err := p.GetSSOSettings(c, cfg, "oidcassumed")
+		require.NoError(t, err)
+
+		user, err := p.GetUserFromIdToken(c, issuer.idToken(issuer.claims("assumed-client")))
+		require.NoError(t, err)
+		assert.True(t, user.EmailVerified)
+	})
+
+	t.Run("subject mismatch", func(t *testing.T) {
+		_, err := p.GetUserFromJSON(c, strings.NewReader(`{"sub": "someone-else", "email": "other@example.com"}`), tokenUser)
+		require.Error(t, err)
+	})
+
+	t.Run("ID token required", func(t *testing.T) {
+		_, err := p.GetUserFromJSON(c, strings.NewReader(`{"sub": "user-1234"}`), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		groups, err := p.GetGroupsFromJSON(c, strings.NewReader(`{"sub": "user-1234", "realm_access": {"roles": ["admins", "developers"]}}`), tokenUser)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"admins", "developers"}, groups)
+
+		groups, err = p.GetGroupsFromJSON(c, strings.NewReader(`{"sub": "user-1234"}`), tokenUser)
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/app/users"
+)
+
+// upgradePasswordHash hashes the password of the user again when its hash was computed
+// with another algorithm or other parameters than the configured ones. It is called once
+// the password was checked, being the only time the password is known.
+func (a *App) upgradePasswordHash(rctx request.CTX, user *model.User, password string) {
+	settings := &a.Config().PasswordSettings
+	if !model.PasswordHashNeedsUpgrade(user.Password, settings) {
+		return
+	}
+
+	hash, err := model.HashPasswordWithSettings(password, settings)
+	if err != nil {
+		rctx.Logger().Warn("Failed to upgrade the password hash", mlog.String("user_id", user.Id), mlog.Err(err))
+		return
+	}
+
+	// The password was changed concurrently when the hash is not the checked one anymore.
+	if err := a.Srv().Store().User().UpdatePasswordHash(user.Id, user.Password, hash); err != nil {
+		rctx.Logger().Warn("Failed to upgrade the password hash", mlog.String("user_id", user.Id), mlog.Err(err))
+		return
+	}
+
+	rctx.Logger().Debug("Password hash upgraded", mlog.String("user_id", user.Id), mlog.String("algorithm", *settings.HashAlgorithm))
+	user.Password = hash
+	a.InvalidateCacheForUser(user.Id)
+}
+
+// isPasswordExpired returns whether the password of the user is older than the
+// configured number of days.
+func (a *App) isPasswordExpired(user *model.User) bool {
+	days := *a.Config().PasswordSettings.ExpiryDays
+	if days == 0 || user.AuthService != "" {
+		return false
+	}
+
+	lastUpdate := user.LastPasswordUpdate
+	if lastUpdate == 0 {
+		lastUpdate = user.CreateAt
+	}
+
+	return time.UnixMilli(lastUpdate).AddDate(0, 0, days).Before(time.Now())
+}
+
+// checkPasswordHistory prevents the user from reusing their current password, or one of
+// their previous passwords still in the history.
+func (a *App) checkPasswordHistory(user *model.User, newPassword string) *model.AppError {
+	count := *a.Config().PasswordSettings.HistoryCount
+	if count == 0 {
+		return nil
+	}
+
+	hashes := []string{}
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+
+	if count > 1 {
+		entries, err := a.Srv().Store().PasswordHistory().GetForUser(user.Id, count-1)
+		if err != nil {
+			return model.NewAppError("checkPasswordHistory", "app.password_history.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		for _, entry := range entries {
+			hashes = append(hashes, entry.PasswordHash)
+		}
+	}
+
+	for _, hash := range hashes {
+		if users.ComparePassword(hash, newPassword) == nil {
+			return model.NewAppError("checkPasswordHistory", "api.user.update_password.reused.app_error", map[string]any{"Count": count}, "user_id="+user.Id, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// recordPasswordHistory keeps the hash of the password the user is replacing, and forgets
+// the ones beyond the configured history.
+func (a *App) recordPasswordHistory(rctx request.CTX, user *model.User) {
+	count := *a.Config().PasswordSettings.HistoryCount
+	if count <= 1 {
+		// The current password is checked from the user itself.
+		if err := a.Srv().Store().PasswordHistory().PermanentDeleteByUser(user.Id); err != nil {
+			rctx.Logger().Warn("Failed to clear the password history", mlog.String("user_id", user.Id), mlog.Err(err))
+		}
+		return
+	}
+
+	if user.Password == "" {
+		return
+	}
+
+	if _, err := a.Srv().Store().PasswordHistory().Save(&model.PasswordHistoryEntry{
+		UserId:       user.Id,
+		PasswordHash: user.Password,
+	}); err != nil {
+		rctx.Logger().Warn("Failed to save the password history", mlog.String("user_id", user.Id), mlog.Err(err))
+		return
+	}
+
+	if err := a.Srv().Store().PasswordHistory().PruneForUser(user.Id, count-1); err != nil {
+		rctx.Logger().Warn("Failed to prune the password history", mlog.String("user_id", user.Id), mlog.Err(err))
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestUpgradePasswordHash(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	user, appErr := th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, appErr)
+	require.True(t, strings.HasPrefix(user.Password, "$2a$"))
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.PasswordSettings.HashAlgorithm = model.PasswordHashAlgorithmArgon2id
+		*cfg.PasswordSettings.Argon2idMemoryKiB = model.PasswordHashArgon2idMinMemoryKiB
+	})
+
+	t.Run("wrong password keeps the hash", func(t *testing.T) {
+		_, appErr := th.App.AuthenticateUserForLogin(th.Context, "", th.BasicUser.Username, "wrong", "", "", false)
+		require.NotNil(t, appErr)
+
+		user, appErr := th.App.GetUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		assert.True(t, strings.HasPrefix(user.Password, "$2a$"))
+	})
+
+	t.Run("login upgrades the hash", func(t *testing.T) {
+		_, appErr := th.App.AuthenticateUserForLogin(th.Context, "", th.BasicUser.Username, "Password1", "", "", false)
+		require.Nil(t, appErr)
+
+		upgraded, appErr := th.App.GetUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		assert.True(t, strings.HasPrefix(upgraded.Password, "$argon2id$v=19$m=8192,t=3,p=2$"), upgraded.Password)
+		assert.Equal(t, user.LastPasswordUpdate, upgraded.LastPasswordUpdate)
+
+		_, appErr = th.App.AuthenticateUserForLogin(th.Context, "", th.BasicUser.Username, "Password1", "", "", false)
+		require.Nil(t, appErr)
+	})
+
+	t.Run("password changes use the configured algorithm", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PasswordSettings.HashAlgorithm = model.PasswordHashAlgorithmScrypt })
+
+		require.Nil(t, th.App.UpdatePassword(th.Context, th.BasicUser2, "new-password"))
+
+		user, appErr := th.App.GetUser(th.BasicUser2.Id)
+		require.Nil(t, appErr)
+		assert.True(t, strings.HasPrefix(user.Password, "$scrypt$"), user.Password)
+
+		_, appErr = th.App.AuthenticateUserForLogin(th.Context, "", th.BasicUser2.Username, "new-password", "", "", false)
+		require.Nil(t, appErr)
+	})
+}
+
+func TestPasswordHistory(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PasswordSettings.HistoryCount = 3 })
+
+	updatePassword := func(password string) *model.AppError {
+		user, appErr := th.App.GetUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		return th.App.UpdatePassword(th.Context, user, password)
+	}
+
+	appErr := updatePassword("Password1")
+	require.NotNil(t, appErr)
+	assert.Equal(t, "api.user.update_password.reused.app_error", appErr.Id)
+
+	require.Nil(t, updatePassword("password-1"))
+	require.Nil(t, updatePassword("password-2"))
+
+	appErr = updatePassword("Password1")
+	require.NotNil(t, appErr)
+	assert.Equal(t, "api.user.update_password.reused.app_error", appErr.Id)
+
+	require.Nil(t, updatePassword("password-3"))
+
+	// The original password is beyond the history now.
+	require.Nil(t, updatePassword("Password1"))
+
+	entries, err := th.App.Srv().Store().PasswordHistory().GetForUser(th.BasicUser.Id, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PasswordSettings.HistoryCount = 0 })
+	require.Nil(t, updatePassword("Password1"))
+}
+
+func TestPasswordExpiry(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PasswordSettings.ExpiryDays = 90 })
+
+	_, appErr := th.App.AuthenticateUserForLogin(th.Context, "", th.BasicUser.Username, "Password1", "", "", false)
+	require.Nil(t, appErr)
+
+	user, appErr := th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, appErr)
+	user.LastPasswordUpdate = model.GetMillisForTime(time.Now().AddDate(0, 0, -91))
+	assert.True(t, th.App.isPasswordExpired(user))
+	user.LastPasswordUpdate = model.GetMillisForTime(time.Now().AddDate(0, 0, -89))
+	assert.False(t, th.App.isPasswordExpired(user))
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PasswordSettings.ExpiryDays = 0 })
+	user.LastPasswordUpdate = 1
+	assert.False(t, th.App.isPasswordExpired(user))
+}
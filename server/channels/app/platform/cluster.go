@@ -176,6 +176,21 @@ func (ps *PlatformService) Publish(message *model.WebSocketEvent) {
 		ps.metricsIFace.IncrementWebsocketEvent(message.EventType())
 	}
 
+	// The event is logged once by the node publishing it, the other nodes
+	// receive its log sequence along with it.
+	if ps.isWebSocketEventLogEnabled() {
+		if writer := ps.eventLogWriter.Load(); writer != nil {
+			writer.Publish(message)
+			return
+		}
+		message = ps.logWebSocketEvents([]*model.WebSocketEvent{message})[0]
+	}
+
+	ps.publish(message)
+}
+
+// publish broadcasts the event to the connections of every node.
+func (ps *PlatformService) publish(message *model.WebSocketEvent) {
 	ps.PublishSkipClusterSend(message)
 
 	if ps.clusterIFace != nil {
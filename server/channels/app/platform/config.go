@@ -71,6 +71,12 @@ func (ps *PlatformService) IsConfigReadOnly() bool {
 // SaveConfig replaces the active configuration, optionally notifying cluster peers.
 // It returns both the previous and current configs.
 func (ps *PlatformService) SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bool) (*model.Config, *model.Config, *model.AppError) {
+	return ps.SaveConfigWithAuthor(newCfg, sendConfigChangeClusterMessage, "")
+}
+
+// SaveConfigWithAuthor behaves like SaveConfig, additionally recording the user who
+// made the change in the configuration history.
+func (ps *PlatformService) SaveConfigWithAuthor(newCfg *model.Config, sendConfigChangeClusterMessage bool, authorID string) (*model.Config, *model.Config, *model.AppError) {
 	if ps.pluginEnv != nil {
 		var hookErr error
 		ps.pluginEnv.RunMultiHook(func(hooks plugin.Hooks) bool {
@@ -89,8 +95,11 @@ func (ps *PlatformService) SaveConfig(newCfg *model.Config, sendConfigChangeClus
 		}
 	}
 
-	oldCfg, newCfg, err := ps.configStore.Set(newCfg)
-	if errors.Is(err, config.ErrReadOnlyConfiguration) {
+	oldCfg, newCfg, err := ps.configStore.SetWithAuthor(newCfg, authorID)
+	var managedErr *config.ManagedSettingError
+	if errors.As(err, &managedErr) {
+		return nil, nil, model.NewAppError("saveConfig", "app.save_config.managed_setting.app_error", map[string]any{"Setting": managedErr.Path}, "", http.StatusForbidden).Wrap(err)
+	} else if errors.Is(err, config.ErrReadOnlyConfiguration) {
 		return nil, nil, model.NewAppError("saveConfig", "ent.cluster.save_config.error", nil, "", http.StatusForbidden).Wrap(err)
 	} else if err != nil {
 		return nil, nil, model.NewAppError("saveConfig", "app.save_config.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
@@ -107,6 +116,22 @@ func (ps *PlatformService) SaveConfig(newCfg *model.Config, sendConfigChangeClus
 	return oldCfg, newCfg, nil
 }
 
+// GetConfigHistory returns up to limit past configurations, newest first.
+func (ps *PlatformService) GetConfigHistory(offset, limit int) ([]*model.ConfigVersion, error) {
+	return ps.configStore.GetHistory(offset, limit)
+}
+
+// GetConfigVersion returns the configuration persisted with the given version id.
+func (ps *PlatformService) GetConfigVersion(id string) (*model.Config, error) {
+	return ps.configStore.GetVersion(id)
+}
+
+// DiffConfigVersions compares two persisted configurations. An empty toID compares
+// against the active configuration.
+func (ps *PlatformService) DiffConfigVersions(fromID, toID string) (config.ConfigDiffs, error) {
+	return ps.configStore.DiffVersions(fromID, toID)
+}
+
 func (ps *PlatformService) ReloadConfig() error {
 	if err := ps.configStore.Load(); err != nil {
 		return err
@@ -157,7 +182,7 @@ func (ps *PlatformService) ConfigureLogger(name string, logger *mlog.Logger, log
 	}
 
 	// this will remove any existing targets and replace with those defined in cfg.
-	if err := logger.ConfigureTargets(cfg, nil); err != nil {
+	if err := logger.ConfigureTargets(cfg, ps.logTargetFactories()); err != nil {
 		return fmt.Errorf("invalid config for %s, %w", name, err)
 	}
 
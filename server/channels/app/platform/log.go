@@ -102,6 +102,16 @@ func (ps *PlatformService) EnableLoggingMetrics() {
 	mlog.Debug("Logging metrics enabled")
 }
 
+// logTargetFactories returns the factories of the log targets that are not built into logr,
+// reporting their metrics when logging metrics are enabled.
+func (ps *PlatformService) logTargetFactories() *mlog.Factories {
+	var collector mlog.MetricsCollector
+	if ps.metrics != nil && ps.metricsIFace != nil {
+		collector = ps.metricsIFace.GetLoggerMetricsCollector()
+	}
+	return mlog.NewFactories(collector)
+}
+
 // RemoveUnlicensedLogTargets removes any unlicensed log target types.
 func (ps *PlatformService) RemoveUnlicensedLogTargets(license *model.License) {
 	if license != nil && *license.Features.AdvancedLogging {
@@ -93,6 +93,8 @@ type PlatformService struct {
 
 	hubs     []*Hub
 	hashSeed maphash.Seed
+	// eventLogWriter publishes the events logged to the WebSocket event log.
+	eventLogWriter atomic.Pointer[eventLogWriter]
 
 	goroutineCount      int32
 	goroutineExitSignal chan struct{}
@@ -271,6 +273,15 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 			return nil, fmt.Errorf("failed to initialize filebackend: %w", err2)
 		}
 
+		if *ps.Config().FileSettings.EnableColdStorage {
+			mlog.Info("Setting up cold storage filestore", mlog.String("driver_name", *ps.Config().FileSettings.ColdDriverName))
+			coldBackend, errCold := filestore.NewFileBackend(filestore.NewColdFileBackendSettingsFromConfig(&ps.Config().FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure))
+			if errCold != nil {
+				return nil, fmt.Errorf("failed to initialize cold storage filebackend: %w", errCold)
+			}
+			backend = filestore.NewTieredFileBackend(backend, coldBackend)
+		}
+
 		ps.filestore = backend
 	}
 
@@ -61,7 +61,9 @@ type pluginWSPostedHook struct {
 }
 
 type WebConnConfig struct {
-	WebSocket     *websocket.Conn
+	WebSocket *websocket.Conn
+	// Stream replaces the WebSocket for the clients connected through SSE or long polling.
+	Stream        WebConnStream
 	Session       model.Session
 	TFunc         i18n.TranslateFunc
 	Locale        string
@@ -72,6 +74,9 @@ type WebConnConfig struct {
 	PostedAck     bool
 	RemoteAddress string
 	XForwardedFor string
+	// LogSequence is the position in the WebSocket event log of the last event
+	// received by a reconnecting client, used to replay the events it missed.
+	LogSequence int64
 
 	// These aren't necessary to be exported to api layer.
 	sequence         int
@@ -95,6 +100,21 @@ type WebConn struct {
 	UserId           string
 	PostedAck        bool
 
+	// stream is set instead of WebSocket when the client can't use WebSockets.
+	stream WebConnStream
+
+	// logSequence is the position in the event log the client resumes from.
+	logSequence int64
+	// replayFromLog indicates whether the events missed by the client must be
+	// replayed from the event log, once the connection is registered.
+	replayFromLog bool
+	// The events broadcast while the missed ones are being replayed are held
+	// back, to be queued after them. Only accessed by the hub goroutine.
+	replayingEventLog   bool
+	heldEvents          []model.WebSocketMessage
+	heldEventsOverflow  bool
+	heldEventsLatestSeq int64
+
 	allChannelMembers         map[string]string
 	lastAllChannelMembersTime int64
 	lastUserActivityAt        int64
@@ -203,22 +223,24 @@ func (ps *PlatformService) NewWebConn(cfg *WebConnConfig, suite SuiteIFace, runn
 		})
 	}
 
-	// Disable TCP_NO_DELAY for higher throughput
-	var tcpConn *net.TCPConn
-	switch conn := cfg.WebSocket.UnderlyingConn().(type) {
-	case *net.TCPConn:
-		tcpConn = conn
-	case *tls.Conn:
-		newConn, ok := conn.NetConn().(*net.TCPConn)
-		if ok {
-			tcpConn = newConn
+	if cfg.WebSocket != nil {
+		// Disable TCP_NO_DELAY for higher throughput
+		var tcpConn *net.TCPConn
+		switch conn := cfg.WebSocket.UnderlyingConn().(type) {
+		case *net.TCPConn:
+			tcpConn = conn
+		case *tls.Conn:
+			newConn, ok := conn.NetConn().(*net.TCPConn)
+			if ok {
+				tcpConn = newConn
+			}
 		}
-	}
 
-	if tcpConn != nil {
-		err := tcpConn.SetNoDelay(false)
-		if err != nil {
-			mlog.Warn("Error in setting NoDelay socket opts", mlog.Err(err))
+		if tcpConn != nil {
+			err := tcpConn.SetNoDelay(false)
+			if err != nil {
+				mlog.Warn("Error in setting NoDelay socket opts", mlog.Err(err))
+			}
 		}
 	}
 
@@ -239,6 +261,7 @@ func (ps *PlatformService) NewWebConn(cfg *WebConnConfig, suite SuiteIFace, runn
 		deadQueuePointer:   cfg.deadQueuePointer,
 		Sequence:           int64(cfg.sequence),
 		WebSocket:          cfg.WebSocket,
+		stream:             cfg.Stream,
 		lastUserActivityAt: model.GetMillis(),
 		UserId:             cfg.Session.UserId,
 		T:                  cfg.TFunc,
@@ -253,9 +276,22 @@ func (ps *PlatformService) NewWebConn(cfg *WebConnConfig, suite SuiteIFace, runn
 		originClient:       cfg.OriginClient,
 		remoteAddress:      cfg.RemoteAddress,
 		xForwardedFor:      cfg.XForwardedFor,
+		logSequence:        cfg.LogSequence,
 	}
 	wc.Active.Store(cfg.Active)
 
+	// The event log is only needed when the events can't be recovered from
+	// the dead queue, i.e. on a different node, after a restart or when the
+	// connection has been away for too long.
+	if cfg.LogSequence > 0 && *ps.Config().ServiceSettings.EnableWebSocketEventLog {
+		if wc.reuseCount == 0 {
+			wc.replayFromLog = true
+		} else if wc.Sequence != 0 {
+			found, _ := wc.isInDeadQueue(wc.Sequence)
+			wc.replayFromLog = !found && wc.hasMsgLoss()
+		}
+	}
+
 	wc.SetSession(&cfg.Session)
 	wc.SetSessionToken(cfg.Session.Token)
 	wc.SetSessionExpiresAt(cfg.Session.ExpiresAt)
@@ -267,12 +303,16 @@ func (ps *PlatformService) NewWebConn(cfg *WebConnConfig, suite SuiteIFace, runn
 	wc.SetActiveRHSThreadChannelID(UnsetPresenceIndicator)
 	wc.SetActiveThreadViewThreadChannelID(UnsetPresenceIndicator)
 
-	ps.Go(func() {
-		runner.RunMultiHook(func(hooks plugin.Hooks) bool {
-			hooks.OnWebSocketConnect(wc.GetConnectionID(), userID)
-			return true
-		}, plugin.OnWebSocketConnectID)
-	})
+	// Every request of a long-poll client reuses the connection, which is
+	// only connected once.
+	if !wc.isLongPoll() || wc.reuseCount == 0 {
+		ps.Go(func() {
+			runner.RunMultiHook(func(hooks plugin.Hooks) bool {
+				hooks.OnWebSocketConnect(wc.GetConnectionID(), userID)
+				return true
+			}, plugin.OnWebSocketConnectID)
+		})
+	}
 
 	return wc
 }
@@ -290,10 +330,26 @@ func (wc *WebConn) pluginPostedConsumer(wg *sync.WaitGroup) {
 
 // Close closes the WebConn.
 func (wc *WebConn) Close() {
-	wc.WebSocket.Close()
+	wc.closeTransport()
 	<-wc.pumpFinished
 }
 
+// closeTransport closes the WebSocket or the stream of the connection.
+func (wc *WebConn) closeTransport() {
+	if wc.stream != nil {
+		wc.stream.close()
+		return
+	}
+	wc.WebSocket.Close()
+}
+
+// isLongPoll returns whether the client polls for events rather than keeping
+// the connection open.
+func (wc *WebConn) isLongPoll() bool {
+	_, ok := wc.stream.(*LongPollStream)
+	return ok
+}
+
 // GetSessionExpiresAt returns the time at which the session expires.
 func (wc *WebConn) GetSessionExpiresAt() int64 {
 	return atomic.LoadInt64(&wc.sessionExpiresAt)
@@ -415,6 +471,13 @@ func (wc *WebConn) Pump() {
 	wc.Platform.HubUnregister(wc)
 	close(wc.pumpFinished)
 
+	// Long-poll clients are disconnected once their connection expires in the hub.
+	if !wc.isLongPoll() {
+		wc.runDisconnectHook()
+	}
+}
+
+func (wc *WebConn) runDisconnectHook() {
 	userID := wc.UserId
 	wc.Platform.Go(func() {
 		wc.HookRunner.RunMultiHook(func(hooks plugin.Hooks) bool {
@@ -429,12 +492,18 @@ func (wc *WebConn) readPump() {
 		if metrics := wc.Platform.metricsIFace; metrics != nil {
 			metrics.DecrementHTTPWebSockets(wc.originClient)
 		}
-		wc.WebSocket.Close()
+		wc.closeTransport()
 	}()
 	if metrics := wc.Platform.metricsIFace; metrics != nil {
 		metrics.IncrementHTTPWebSockets(wc.originClient)
 	}
 
+	if wc.stream != nil {
+		// Nothing is read from a stream, its client only receives events.
+		<-wc.stream.done()
+		return
+	}
+
 	wc.WebSocket.SetReadLimit(model.SocketMaxMessageSizeKb)
 	wc.WebSocket.SetReadDeadline(time.Now().Add(pongWaitTime))
 	wc.WebSocket.SetPongHandler(func(string) error {
@@ -504,7 +573,7 @@ func (wc *WebConn) writePump() {
 	defer func() {
 		ticker.Stop()
 		authTicker.Stop()
-		wc.WebSocket.Close()
+		wc.closeTransport()
 	}()
 
 	if wc.Sequence != 0 {
@@ -558,7 +627,9 @@ func (wc *WebConn) writePump() {
 
 			buf.Reset()
 			var err error
+			seq := int64(-1)
 			if evtOk {
+				seq = wc.Sequence
 				evt = evt.SetSequence(wc.Sequence)
 				err = evt.Encode(enc, &buf)
 				wc.Sequence++
@@ -589,7 +660,7 @@ func (wc *WebConn) writePump() {
 				wc.addToDeadQueue(evt)
 			}
 
-			if err := wc.writeMessageBuf(websocket.TextMessage, buf.Bytes()); err != nil {
+			if err := wc.writeEventBuf(seq, buf.Bytes()); err != nil {
 				wc.logSocketErr("websocket.send", err)
 				return
 			}
@@ -608,7 +679,7 @@ func (wc *WebConn) writePump() {
 
 		case <-authTicker.C:
 			if wc.GetSessionToken() == "" {
-				mlog.Debug("websocket.authTicker: did not authenticate", mlog.Stringer("ip_address", wc.WebSocket.RemoteAddr()))
+				mlog.Debug("websocket.authTicker: did not authenticate", mlog.String("ip_address", wc.remoteAddress))
 				return
 			}
 			authTicker.Stop()
@@ -619,10 +690,29 @@ func (wc *WebConn) writePump() {
 // writeMessageBuf is a helper utility that wraps the write to the socket
 // along with setting the write deadline.
 func (wc *WebConn) writeMessageBuf(msgType int, data []byte) error {
+	if wc.stream != nil {
+		switch msgType {
+		case websocket.PingMessage:
+			return wc.stream.keepAlive()
+		case websocket.CloseMessage:
+			return nil
+		}
+		return wc.stream.writeEvent(wc.GetConnectionID(), -1, data)
+	}
+
 	wc.WebSocket.SetWriteDeadline(time.Now().Add(writeWaitTime))
 	return wc.WebSocket.WriteMessage(msgType, data)
 }
 
+// writeEventBuf writes an encoded message, along with its sequence number
+// for the streams which need it.
+func (wc *WebConn) writeEventBuf(seq int64, data []byte) error {
+	if wc.stream != nil {
+		return wc.stream.writeEvent(wc.GetConnectionID(), seq, data)
+	}
+	return wc.writeMessageBuf(websocket.TextMessage, data)
+}
+
 func (wc *WebConn) writeMessage(msg *model.WebSocketEvent) error {
 	// We don't use the encoder from the write pump because it's unwieldy to pass encoders
 	// around, and this is only called during initialization of the webConn.
@@ -634,7 +724,7 @@ func (wc *WebConn) writeMessage(msg *model.WebSocketEvent) error {
 	}
 	wc.Sequence++
 
-	return wc.writeMessageBuf(websocket.TextMessage, buf.Bytes())
+	return wc.writeEventBuf(msg.GetSequence(), buf.Bytes())
 }
 
 // addToDeadQueue appends a message to the dead queue.
@@ -778,6 +868,9 @@ func (wc *WebConn) createHelloMessage() *model.WebSocketEvent {
 		wc.Platform.ClientConfigHash(),
 		ee))
 	msg.Add("connection_id", wc.connectionID.Load())
+	if logSeq, ok := wc.helloLogSequence(); ok {
+		msg.Add("log_seq", logSeq)
+	}
 	return msg
 }
 
@@ -841,6 +934,14 @@ func (wc *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 		}
 	}
 
+	// Connections authenticated with a user access token restricted to teams or channels
+	// only receive the events of these.
+	if session := wc.GetSession(); session != nil {
+		if scope := session.GetUserAccessTokenScope(); scope != nil && !wc.scopeAllowsEvent(scope, msg) {
+			return false
+		}
+	}
+
 	// There are two checks here which differentiates between what to send to an admin user and what to send to a normal user.
 	// For websocket events containing sensitive data, we split that to create two events:
 	// 1. We sanitize all fields, and set ContainsSanitizedData to true. This goes to normal users.
@@ -942,6 +1043,41 @@ func (wc *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 	return true
 }
 
+// scopeAllowsEvent returns whether the scope of a user access token allows an event, by
+// the channel or team the event is broadcast to or is about.
+func (wc *WebConn) scopeAllowsEvent(scope *model.UserAccessTokenScope, msg *model.WebSocketEvent) bool {
+	if !scope.RestrictsChannels() {
+		return true
+	}
+
+	channelID, teamID := msg.GetBroadcast().ChannelId, msg.GetBroadcast().TeamId
+	if channelID == "" {
+		channelID, _ = msg.GetData()["channel_id"].(string)
+	}
+	if teamID == "" {
+		teamID, _ = msg.GetData()["team_id"].(string)
+	}
+
+	if channelID != "" {
+		if len(scope.ChannelIds) > 0 {
+			return scope.AllowsChannel(channelID, "")
+		}
+
+		channel, err := wc.Platform.Store.Channel().Get(channelID, true)
+		if err != nil {
+			mlog.Debug("webhub.scopeAllowsEvent: could not get the channel of the event", mlog.String("channel_id", channelID), mlog.Err(err))
+			return false
+		}
+		return scope.AllowsChannel(channel.Id, channel.TeamId)
+	}
+
+	if teamID != "" {
+		return scope.AllowsTeam(teamID)
+	}
+
+	return true
+}
+
 func (wc *WebConn) notInChannel(val string) bool {
 	return (wc.isSet(wc.GetActiveChannelID()) && val != wc.GetActiveChannelID())
 }
@@ -975,7 +1111,7 @@ func (wc *WebConn) isMemberOfTeam(teamID string) bool {
 
 func (wc *WebConn) logSocketErr(source string, err error) {
 	// browsers will appear as CloseNoStatusReceived
-	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) || errors.Is(err, errStreamClosed) {
 		mlog.Debug(source+": client side closed socket",
 			mlog.String("user_id", wc.UserId),
 			mlog.String("conn_id", wc.GetConnectionID()),
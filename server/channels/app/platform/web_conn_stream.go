@@ -0,0 +1,221 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// longPollBatchWait is how long a long-poll request waits for more events
+	// after receiving the first one, so that bursts are returned together.
+	longPollBatchWait = 100 * time.Millisecond
+	// sseRetryInterval is the reconnection delay suggested to SSE clients, in milliseconds.
+	sseRetryInterval = 3000
+)
+
+// errStreamClosed is returned when writing to a stream whose request is over.
+var errStreamClosed = errors.New("stream closed")
+
+// WebConnStream delivers the events of a WebConn over a plain HTTP response,
+// for the clients which can't open a WebSocket.
+type WebConnStream interface {
+	// writeEvent writes an encoded message. seq is negative for the messages
+	// which aren't events.
+	writeEvent(connectionID string, seq int64, data []byte) error
+	// keepAlive prevents proxies from closing an idle stream.
+	keepAlive() error
+	// done is closed once no more messages can be written.
+	done() <-chan struct{}
+	close()
+}
+
+// SSEStream streams the events as Server-Sent Events. Each event id is made of
+// the connection id and the sequence number of the event, so that the
+// Last-Event-ID header sent by reconnecting clients is enough to resume.
+type SSEStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+
+	writeLock sync.Mutex
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSSEStream starts a Server-Sent Events response.
+func NewSSEStream(w http.ResponseWriter, r *http.Request) (*SSEStream, error) {
+	s := &SSEStream{
+		w:      w,
+		rc:     http.NewResponseController(w),
+		doneCh: make(chan struct{}),
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Prevents nginx from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := s.write(fmt.Appendf(nil, "retry: %d\n\n", sseRetryInterval)); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-r.Context().Done():
+			s.close()
+		case <-s.doneCh:
+		}
+	}()
+
+	return s, nil
+}
+
+func (s *SSEStream) write(data []byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	select {
+	case <-s.doneCh:
+		return errStreamClosed
+	default:
+	}
+
+	// The deadline is extended on every write, the server write timeout
+	// would end the stream otherwise.
+	if err := s.rc.SetWriteDeadline(time.Now().Add(writeWaitTime)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *SSEStream) writeEvent(connectionID string, seq int64, data []byte) error {
+	var buf bytes.Buffer
+	if seq >= 0 {
+		fmt.Fprintf(&buf, "id: %s:%d\n", connectionID, seq)
+	}
+	buf.WriteString("data: ")
+	buf.Write(bytes.TrimRight(data, "\n"))
+	buf.WriteString("\n\n")
+	return s.write(buf.Bytes())
+}
+
+func (s *SSEStream) keepAlive() error {
+	return s.write([]byte(": ping\n\n"))
+}
+
+func (s *SSEStream) done() <-chan struct{} {
+	return s.doneCh
+}
+
+func (s *SSEStream) close() {
+	s.closeOnce.Do(func() {
+		s.writeLock.Lock()
+		defer s.writeLock.Unlock()
+		close(s.doneCh)
+	})
+}
+
+// ParseLastEventID returns the connection id and the sequence number the client
+// expects next from the Last-Event-ID header of a reconnecting SSE client.
+func ParseLastEventID(lastEventID string) (string, string) {
+	connectionID, seq, ok := strings.Cut(lastEventID, ":")
+	if !ok {
+		return "", ""
+	}
+	n, err := strconv.ParseInt(seq, 10, 64)
+	if err != nil || n < 0 {
+		return "", ""
+	}
+	return connectionID, strconv.FormatInt(n+1, 10)
+}
+
+// LongPollStream collects the events to return to a long-poll request. The
+// request completes as soon as events are available, or after the timeout.
+type LongPollStream struct {
+	eventsLock sync.Mutex
+	events     []json.RawMessage
+
+	doneCh    chan struct{}
+	closeOnce sync.Once
+	stop      func() bool
+	timer     *time.Timer
+}
+
+// NewLongPollStream creates the stream of a long-poll request waiting up to timeout for events.
+func NewLongPollStream(ctx context.Context, timeout time.Duration) *LongPollStream {
+	s := &LongPollStream{
+		doneCh: make(chan struct{}),
+	}
+	// close waits for the lock, should the context be done already.
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+	s.timer = time.AfterFunc(timeout, s.close)
+	s.stop = context.AfterFunc(ctx, s.close)
+	return s
+}
+
+func (s *LongPollStream) writeEvent(_ string, _ int64, data []byte) error {
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	select {
+	case <-s.doneCh:
+		return errStreamClosed
+	default:
+	}
+
+	// The buffer is reused by the write pump.
+	s.events = append(s.events, json.RawMessage(bytes.TrimRight(bytes.Clone(data), "\n")))
+	if len(s.events) == 1 {
+		s.timer.Reset(longPollBatchWait)
+	}
+	if len(s.events) >= sendQueueSize {
+		go s.close()
+	}
+	return nil
+}
+
+func (s *LongPollStream) keepAlive() error {
+	return nil
+}
+
+func (s *LongPollStream) done() <-chan struct{} {
+	return s.doneCh
+}
+
+func (s *LongPollStream) close() {
+	s.closeOnce.Do(func() {
+		s.eventsLock.Lock()
+		defer s.eventsLock.Unlock()
+		s.timer.Stop()
+		s.stop()
+		close(s.doneCh)
+	})
+}
+
+// WriteResponse writes the collected events as a JSON array. It must only be
+// called once the WebConn using the stream has stopped.
+func (s *LongPollStream) WriteResponse(w http.ResponseWriter) error {
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	events := s.events
+	if events == nil {
+		events = []json.RawMessage{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(events)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/api/v4/websocket/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	stream, err := NewSSEStream(w, r)
+	require.NoError(t, err)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	require.NoError(t, stream.writeEvent("conn1", 3, []byte(`{"event":"hello"}`+"\n")))
+	require.NoError(t, stream.writeEvent("conn1", -1, []byte(`{"status":"OK"}`)))
+	require.NoError(t, stream.keepAlive())
+	assert.Equal(t, "retry: 3000\n\nid: conn1:3\ndata: {\"event\":\"hello\"}\n\ndata: {\"status\":\"OK\"}\n\n: ping\n\n", w.Body.String())
+
+	cancel()
+	select {
+	case <-stream.done():
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "stream should be done once the request is canceled")
+	}
+	require.ErrorIs(t, stream.writeEvent("conn1", 4, []byte(`{}`)), errStreamClosed)
+}
+
+func TestParseLastEventID(t *testing.T) {
+	connectionID, seq := ParseLastEventID("conn1:41")
+	assert.Equal(t, "conn1", connectionID)
+	assert.Equal(t, "42", seq)
+
+	for _, id := range []string{"", "conn1", "conn1:", "conn1:-1", "conn1:abc"} {
+		connectionID, seq = ParseLastEventID(id)
+		assert.Empty(t, connectionID, id)
+		assert.Empty(t, seq, id)
+	}
+}
+
+func TestLongPollStream(t *testing.T) {
+	t.Run("returns the events received", func(t *testing.T) {
+		stream := NewLongPollStream(context.Background(), time.Minute)
+		require.NoError(t, stream.writeEvent("conn1", 0, []byte(`{"seq":0}`+"\n")))
+		require.NoError(t, stream.writeEvent("conn1", 1, []byte(`{"seq":1}`+"\n")))
+
+		select {
+		case <-stream.done():
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "stream should be done shortly after receiving events")
+		}
+		require.ErrorIs(t, stream.writeEvent("conn1", 2, []byte(`{"seq":2}`)), errStreamClosed)
+
+		w := httptest.NewRecorder()
+		require.NoError(t, stream.WriteResponse(w))
+		assert.JSONEq(t, `[{"seq":0},{"seq":1}]`, w.Body.String())
+	})
+
+	t.Run("returns an empty list after the timeout", func(t *testing.T) {
+		stream := NewLongPollStream(context.Background(), 10*time.Millisecond)
+		select {
+		case <-stream.done():
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "stream should be done after the timeout")
+		}
+
+		w := httptest.NewRecorder()
+		require.NoError(t, stream.WriteResponse(w))
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+
+	t.Run("stops with the request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		stream := NewLongPollStream(ctx, time.Minute)
+		select {
+		case <-stream.done():
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "stream should be done once the request is canceled")
+		}
+	})
+}
@@ -0,0 +1,441 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store/sqlstore"
+)
+
+const (
+	// eventLogReplayLimit is the maximum number of events replayed to a
+	// reconnecting client. It must leave room in the send queue, since the
+	// events are queued by the hub. Clients which missed more have to resync.
+	eventLogReplayLimit = sendQueueSize / 2
+
+	// eventLogReplayPageSize is the number of logged events read at once for a
+	// reconnecting client. Most of them may be broadcast to channels and teams
+	// the user is not a member of.
+	eventLogReplayPageSize = eventLogReplayLimit + 1
+
+	// eventLogReplayScanLimit is the maximum number of logged events read for a
+	// reconnecting client. Clients which missed more have to resync.
+	eventLogReplayScanLimit = 50 * eventLogReplayPageSize
+
+	// eventLogReplayOverlap is how long, in milliseconds, before the last event
+	// received by a client the events are replayed again. Events may be
+	// committed in a different order than their sequence numbers, so the client
+	// may have received an event before an earlier one was visible in the log.
+	eventLogReplayOverlap = 5000
+
+	// eventLogBatchSize is the maximum number of events saved to the event log
+	// at once.
+	eventLogBatchSize = 100
+)
+
+func (ps *PlatformService) isWebSocketEventLogEnabled() bool {
+	return *ps.Config().ServiceSettings.EnableWebSocketEventLog
+}
+
+func shouldLogWebSocketEvent(msg *model.WebSocketEvent) bool {
+	switch msg.EventType() {
+	case model.WebsocketEventTyping,
+		model.WebsocketEventHello,
+		model.WebsocketEventResyncRequired:
+		return false
+	}
+
+	// Events sent to a single connection are lost along with it.
+	if msg.GetBroadcast() == nil || msg.GetBroadcast().ConnectionId != "" {
+		return false
+	}
+
+	return msg.GetLogSequence() == 0
+}
+
+// logWebSocketEvents saves the events to the WebSocket event log, so that they
+// can be replayed to the clients reconnecting to any node. It returns the events
+// with their position in the log.
+func (ps *PlatformService) logWebSocketEvents(msgs []*model.WebSocketEvent) []*model.WebSocketEvent {
+	if !ps.isWebSocketEventLogEnabled() {
+		return msgs
+	}
+
+	var indexes []int
+	var entries []*model.WebSocketEventLogEntry
+	for i, msg := range msgs {
+		if !shouldLogWebSocketEvent(msg) {
+			continue
+		}
+
+		data, err := msg.ToJSON()
+		if err != nil {
+			ps.logger.Warn("Failed to encode WebSocket event for the event log", mlog.String("type", string(msg.EventType())), mlog.Err(err))
+			continue
+		}
+		indexes = append(indexes, i)
+		entries = append(entries, &model.WebSocketEventLogEntry{
+			CreateAt: model.GetMillis(),
+			UserId:   msg.GetBroadcast().UserId,
+			Event:    data,
+		})
+	}
+	if len(entries) == 0 {
+		return msgs
+	}
+
+	saved, err := ps.Store.WebSocketEvent().SaveMultiple(entries)
+	if err != nil {
+		ps.logger.Warn("Failed to save WebSocket events to the event log", mlog.Int("count", len(entries)), mlog.Err(err))
+		return msgs
+	}
+
+	logged := make([]*model.WebSocketEvent, len(msgs))
+	copy(logged, msgs)
+	for i, entry := range saved {
+		logged[indexes[i]] = msgs[indexes[i]].SetLogSequence(entry.Seq)
+	}
+	return logged
+}
+
+// eventLogWriter saves the published events to the event log in batches, off
+// the publish path, and publishes them once they have their log sequence. The
+// events keep the order they were published in.
+type eventLogWriter struct {
+	ps      *PlatformService
+	queue   chan *model.WebSocketEvent
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newEventLogWriter(ps *PlatformService) *eventLogWriter {
+	return &eventLogWriter{
+		ps:      ps,
+		queue:   make(chan *model.WebSocketEvent, broadcastQueueSize),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (w *eventLogWriter) Start() {
+	go func() {
+		defer close(w.stopped)
+
+		for {
+			select {
+			case msg := <-w.queue:
+				w.write(msg)
+			case <-w.stop:
+				// Publish the events queued before stopping.
+				for {
+					select {
+					case msg := <-w.queue:
+						w.write(msg)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+func (w *eventLogWriter) Stop() {
+	close(w.stop)
+	<-w.stopped
+}
+
+func (w *eventLogWriter) Publish(msg *model.WebSocketEvent) {
+	select {
+	case w.queue <- msg:
+	case <-w.stop:
+	}
+}
+
+// write logs and publishes the given event along with the ones queued after it.
+func (w *eventLogWriter) write(msg *model.WebSocketEvent) {
+	batch := []*model.WebSocketEvent{msg}
+	for len(batch) < eventLogBatchSize {
+		select {
+		case msg := <-w.queue:
+			batch = append(batch, msg)
+			continue
+		default:
+		}
+		break
+	}
+
+	for _, msg := range w.ps.logWebSocketEvents(batch) {
+		w.ps.publish(msg)
+	}
+}
+
+// helloLogSequence returns the position in the event log to send in the hello
+// message. A resuming client keeps its own position, since the events it
+// missed are replayed after the hello message.
+func (wc *WebConn) helloLogSequence() (int64, bool) {
+	if !wc.Platform.isWebSocketEventLogEnabled() {
+		return 0, false
+	}
+
+	if wc.replayFromLog {
+		return wc.logSequence, true
+	}
+
+	_, latest, err := wc.Platform.Store.WebSocketEvent().GetSeqRange()
+	if err != nil {
+		mlog.Warn("Failed to get the WebSocket event log position", mlog.String("user_id", wc.UserId), mlog.Err(err))
+		return 0, false
+	}
+	return latest, true
+}
+
+// eventLogReplay holds the logged events a reconnecting client missed.
+type eventLogReplay struct {
+	webConn *WebConn
+	events  []*model.WebSocketEvent
+	// resyncSeq is the latest position in the event log when the client has
+	// to resync instead.
+	resyncSeq int64
+	resync    bool
+}
+
+// loadEventLogReplay reads the logged events a reconnecting client missed. It
+// runs off the hub goroutine, since it queries the database.
+func (h *Hub) loadEventLogReplay(webConn *WebConn) *eventLogReplay {
+	since := webConn.logSequence
+	replay := &eventLogReplay{webConn: webConn}
+
+	oldest, latest, err := h.platform.Store.WebSocketEvent().GetSeqRange()
+	if err != nil {
+		mlog.Warn("Failed to get the WebSocket event log range", mlog.String("user_id", webConn.UserId), mlog.Err(err))
+		replay.resync = true
+		return replay
+	}
+
+	// The client comes from a reset log, or the events it missed are gone.
+	if since > latest || oldest > since+1 {
+		replay.resync, replay.resyncSeq = true, latest
+		return replay
+	}
+
+	// The events are read by pages, so that only the events the user may receive
+	// count towards the replay limit.
+	filter := &eventLogReplayFilter{platform: h.platform, userID: webConn.UserId}
+	from, overlap := since, int64(eventLogReplayOverlap)
+	for scanned := 0; ; {
+		entries, err := h.platform.Store.WebSocketEvent().GetSince(webConn.UserId, from, overlap, eventLogReplayPageSize)
+		if err != nil {
+			mlog.Warn("Failed to get the WebSocket events to replay", mlog.String("user_id", webConn.UserId), mlog.Err(err))
+			replay.resync, replay.resyncSeq = true, latest
+			return replay
+		}
+
+		for _, entry := range entries {
+			msg, err := model.WebSocketEventFromJSON(bytes.NewReader(entry.Event))
+			if err != nil {
+				mlog.Warn("Failed to decode logged WebSocket event", mlog.Int("log_seq", entry.Seq), mlog.Err(err))
+				continue
+			}
+
+			allowed, err := filter.allows(msg)
+			if err != nil {
+				mlog.Warn("Failed to get the memberships of the user to replay the WebSocket events", mlog.String("user_id", webConn.UserId), mlog.Err(err))
+				replay.resync, replay.resyncSeq = true, latest
+				return replay
+			}
+			if !allowed {
+				continue
+			}
+
+			replay.events = append(replay.events, msg.SetLogSequence(entry.Seq))
+			if len(replay.events) > eventLogReplayLimit {
+				replay.events, replay.resync, replay.resyncSeq = nil, true, latest
+				return replay
+			}
+		}
+
+		scanned += len(entries)
+		if len(entries) < eventLogReplayPageSize {
+			return replay
+		}
+		if scanned >= eventLogReplayScanLimit {
+			replay.events, replay.resync, replay.resyncSeq = nil, true, latest
+			return replay
+		}
+		from, overlap = entries[len(entries)-1].Seq, 0
+	}
+}
+
+// eventLogReplayFilter leaves out the logged events broadcast to other users, or to
+// channels and teams the user is not a member of. ShouldSendEvent still decides
+// which of the remaining ones are sent, once they are delivered.
+type eventLogReplayFilter struct {
+	platform *PlatformService
+	userID   string
+
+	// The memberships are loaded the first time an event broadcast to a channel or
+	// team is found.
+	loaded     bool
+	channelIDs map[string]string
+	teamIDs    map[string]bool
+}
+
+func (f *eventLogReplayFilter) allows(msg *model.WebSocketEvent) (bool, error) {
+	broadcast := msg.GetBroadcast()
+	if broadcast == nil {
+		return true, nil
+	}
+	if broadcast.UserId != "" {
+		return broadcast.UserId == f.userID, nil
+	}
+	if _, ok := broadcast.OmitUsers[f.userID]; ok {
+		return false, nil
+	}
+	if broadcast.ChannelId == "" && broadcast.TeamId == "" {
+		return true, nil
+	}
+
+	if !f.loaded {
+		if err := f.loadMemberships(); err != nil {
+			return false, err
+		}
+	}
+	if broadcast.ChannelId != "" {
+		_, ok := f.channelIDs[broadcast.ChannelId]
+		return ok, nil
+	}
+	return f.teamIDs[broadcast.TeamId], nil
+}
+
+func (f *eventLogReplayFilter) loadMemberships() error {
+	channelIDs, err := f.platform.Store.Channel().GetAllChannelMembersForUser(
+		sqlstore.RequestContextWithMaster(request.EmptyContext(f.platform.logger)),
+		f.userID,
+		false,
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get the channels of user %s: %w", f.userID, err)
+	}
+
+	teamIDs, err := f.platform.Store.Team().GetUserTeamIds(f.userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to get the teams of user %s: %w", f.userID, err)
+	}
+
+	f.channelIDs = channelIDs
+	f.teamIDs = make(map[string]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		f.teamIDs[teamID] = true
+	}
+	f.loaded = true
+	return nil
+}
+
+// replayEventLog loads the logged events a reconnecting client missed. The
+// events broadcast to the connection meanwhile are held back, and queued after
+// the replayed ones by deliverEventLogReplay.
+func (h *Hub) replayEventLog(webConn *WebConn) {
+	webConn.replayingEventLog = true
+	h.platform.Go(func() {
+		replay := h.loadEventLogReplay(webConn)
+		select {
+		case h.eventLogReplayed <- replay:
+		case <-h.stop:
+		}
+	})
+}
+
+// deliverEventLogReplay queues the hello message and the replayed events,
+// followed by the ones held back during the replay. It runs on the hub goroutine. Replayed events may have
+// been received already and are to be ignored by the client, based on their
+// log sequence.
+func (h *Hub) deliverEventLogReplay(connIndex *hubConnectionIndex, replay *eventLogReplay) {
+	webConn := replay.webConn
+	held, heldOverflow, heldLatestSeq := webConn.heldEvents, webConn.heldEventsOverflow, webConn.heldEventsLatestSeq
+	webConn.replayingEventLog = false
+	webConn.heldEvents, webConn.heldEventsOverflow, webConn.heldEventsLatestSeq = nil, false, 0
+
+	if !connIndex.Has(webConn) {
+		return
+	}
+
+	send := func(msg model.WebSocketMessage) bool {
+		select {
+		case webConn.send <- msg:
+			return true
+		default:
+			if webConn.Active.Load() {
+				mlog.Error("webhub.broadcast: cannot send, closing websocket for user",
+					mlog.String("user_id", webConn.UserId),
+					mlog.String("conn_id", webConn.GetConnectionID()))
+			}
+			close(webConn.send)
+			connIndex.Remove(webConn)
+			return false
+		}
+	}
+
+	if webConn.reuseCount == 0 && !send(webConn.createHelloMessage()) {
+		return
+	}
+
+	// Too many events were broadcast during the replay to hold them back.
+	if heldOverflow {
+		replay.resync, replay.resyncSeq = true, max(replay.resyncSeq, heldLatestSeq)
+	}
+
+	if replay.resync {
+		msg := model.NewWebSocketEvent(model.WebsocketEventResyncRequired, "", "", webConn.UserId, nil, "")
+		msg.Add("log_seq", replay.resyncSeq)
+		if !send(msg) || heldOverflow {
+			return
+		}
+	}
+
+	replayed := make(map[int64]bool, len(replay.events))
+	for _, msg := range replay.events {
+		replayed[msg.GetLogSequence()] = true
+
+		msg, broadcastHooks, broadcastHookArgs := msg.WithoutBroadcastHooks()
+		if !webConn.ShouldSendEvent(msg) {
+			continue
+		}
+		if !send(h.runBroadcastHooks(msg, webConn, broadcastHooks, broadcastHookArgs)) {
+			return
+		}
+	}
+
+	for _, msg := range held {
+		if ev, ok := msg.(*model.WebSocketEvent); ok && replayed[ev.GetLogSequence()] {
+			continue
+		}
+		if !send(msg) {
+			return
+		}
+	}
+}
+
+// holdEvent keeps an event broadcast to a connection while the events it missed
+// are being replayed. Once too many events were held back, they are dropped and
+// the client has to resync.
+func (wc *WebConn) holdEvent(msg model.WebSocketMessage) {
+	if ev, ok := msg.(*model.WebSocketEvent); ok {
+		wc.heldEventsLatestSeq = max(wc.heldEventsLatestSeq, ev.GetLogSequence())
+	}
+	if wc.heldEventsOverflow {
+		return
+	}
+	if len(wc.heldEvents) >= sendQueueSize {
+		wc.heldEvents, wc.heldEventsOverflow = nil, true
+		return
+	}
+	wc.heldEvents = append(wc.heldEvents, msg)
+}
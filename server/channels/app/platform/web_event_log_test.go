@@ -0,0 +1,313 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+)
+
+func setupWebSocketEventStoreMock(th *TestHelper) *mocks.WebSocketEventStore {
+	mockStore := th.Service.Store.(*mocks.Store)
+
+	// Needed to update the config.
+	mockUserStore := mocks.UserStore{}
+	mockUserStore.On("Count", mock.Anything).Return(int64(10), nil)
+	mockPostStore := mocks.PostStore{}
+	mockPostStore.On("GetMaxPostSize").Return(65535, nil)
+	mockSystemStore := mocks.SystemStore{}
+	mockSystemStore.On("GetByName", "UpgradedFromTE").Return(&model.System{Name: "UpgradedFromTE", Value: "false"}, nil)
+	mockSystemStore.On("GetByName", "InstallationDate").Return(&model.System{Name: "InstallationDate", Value: "10"}, nil)
+	mockSystemStore.On("GetByName", "FirstServerRunTimestamp").Return(&model.System{Name: "FirstServerRunTimestamp", Value: "10"}, nil)
+	mockStore.On("User").Return(&mockUserStore)
+	mockStore.On("Post").Return(&mockPostStore)
+	mockStore.On("System").Return(&mockSystemStore)
+	mockStore.On("GetDBSchemaVersion").Return(1, nil)
+
+	mockEventStore := &mocks.WebSocketEventStore{}
+	mockStore.On("WebSocketEvent").Return(mockEventStore)
+	return mockEventStore
+}
+
+func TestShouldLogWebSocketEvent(t *testing.T) {
+	assert.True(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventPosted, "", "channelID", "", nil, "")))
+	assert.True(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventPreferencesChanged, "", "", "userID", nil, "")))
+
+	assert.False(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventTyping, "", "channelID", "", nil, "")))
+	assert.False(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventHello, "", "", "userID", nil, "")))
+	assert.False(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventResyncRequired, "", "", "userID", nil, "")))
+	assert.False(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventPosted, "", "", "", nil, "").SetBroadcast(&model.WebsocketBroadcast{ConnectionId: "connectionID"})))
+	assert.False(t, shouldLogWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventPosted, "", "channelID", "", nil, "").SetLogSequence(4)))
+}
+
+func TestLogWebSocketEvents(t *testing.T) {
+	th := SetupWithStoreMock(t)
+	defer th.TearDown()
+
+	mockEventStore := setupWebSocketEventStoreMock(th)
+	var savedEntries []*model.WebSocketEventLogEntry
+	mockEventStore.On("SaveMultiple", mock.MatchedBy(func(entries []*model.WebSocketEventLogEntry) bool {
+		return len(entries) == 2 && entries[0].UserId == "userID"
+	})).Return(func(entries []*model.WebSocketEventLogEntry) ([]*model.WebSocketEventLogEntry, error) {
+		savedEntries = entries
+		saved := []*model.WebSocketEventLogEntry{}
+		for i, entry := range entries {
+			copied := *entry
+			copied.Seq = int64(42 + i)
+			saved = append(saved, &copied)
+		}
+		return saved, nil
+	})
+	// The config change itself is published.
+	mockEventStore.On("SaveMultiple", mock.Anything).Return([]*model.WebSocketEventLogEntry{{Seq: 1}}, nil)
+
+	msgs := []*model.WebSocketEvent{
+		model.NewWebSocketEvent(model.WebsocketEventPreferencesChanged, "", "", "userID", nil, ""),
+		model.NewWebSocketEvent(model.WebsocketEventTyping, "", "channelID", "", nil, ""),
+		model.NewWebSocketEvent(model.WebsocketEventPosted, "", "channelID", "", nil, ""),
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		for _, logged := range th.Service.logWebSocketEvents(msgs) {
+			assert.Zero(t, logged.GetLogSequence())
+		}
+		mockEventStore.AssertNotCalled(t, "SaveMultiple", mock.Anything)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		th.Service.UpdateConfig(func(cfg *model.Config) {
+			*cfg.ServiceSettings.EnableWebSocketEventLog = true
+		})
+
+		logged := th.Service.logWebSocketEvents(msgs)
+		require.Len(t, logged, 3)
+		assert.Equal(t, int64(42), logged[0].GetLogSequence())
+		assert.Zero(t, logged[1].GetLogSequence(), "typing events are not logged")
+		assert.Equal(t, int64(43), logged[2].GetLogSequence())
+		assert.Zero(t, msgs[0].GetLogSequence())
+
+		require.Len(t, savedEntries, 2)
+		saved, err := model.WebSocketEventFromJSON(bytes.NewReader(savedEntries[0].Event))
+		require.NoError(t, err)
+		assert.Equal(t, msgs[0].EventType(), saved.EventType())
+	})
+}
+
+func TestHubReplayEventLog(t *testing.T) {
+	th := SetupWithStoreMock(t)
+	defer th.TearDown()
+
+	mockEventStore := setupWebSocketEventStoreMock(th)
+	mockEventStore.On("SaveMultiple", mock.Anything).Return([]*model.WebSocketEventLogEntry{{Seq: 1}}, nil)
+
+	th.Service.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableWebSocketEventLog = true
+	})
+
+	hub := newWebHub(th.Service)
+
+	newEvent := func(userID string) *model.WebSocketEvent {
+		return model.NewWebSocketEvent(model.WebsocketEventPreferencesChanged, "", "", userID, nil, "")
+	}
+
+	newLogEntry := func(seq int64, userID string) *model.WebSocketEventLogEntry {
+		data, err := newEvent(userID).ToJSON()
+		require.NoError(t, err)
+		return &model.WebSocketEventLogEntry{Seq: seq, UserId: userID, Event: data}
+	}
+
+	newChannelLogEntry := func(seq int64, channelID string) *model.WebSocketEventLogEntry {
+		data, err := model.NewWebSocketEvent(model.WebsocketEventPosted, "", channelID, "", nil, "").ToJSON()
+		require.NoError(t, err)
+		return &model.WebSocketEventLogEntry{Seq: seq, Event: data}
+	}
+
+	mockStore := th.Service.Store.(*mocks.Store)
+	mockChannelStore := mocks.ChannelStore{}
+	mockChannelStore.On("GetAllChannelMembersForUser", mock.Anything, "user1", false, false).Return(map[string]string{"channel1": ""}, nil)
+	mockTeamStore := mocks.TeamStore{}
+	mockTeamStore.On("GetUserTeamIds", "user1", false).Return([]string{"team1"}, nil)
+	mockStore.On("Channel").Return(&mockChannelStore)
+	mockStore.On("Team").Return(&mockTeamStore)
+
+	newWebConn := func(logSeq int64) (*WebConn, *hubConnectionIndex) {
+		wc := &WebConn{
+			Platform:      th.Service,
+			Suite:         th.Suite,
+			UserId:        "user1",
+			send:          make(chan model.WebSocketMessage, sendQueueSize),
+			logSequence:   logSeq,
+			replayFromLog: true,
+		}
+		wc.SetSession(&model.Session{UserId: "user1", Token: "token"})
+		wc.SetSessionToken("token")
+		wc.SetSessionExpiresAt(model.GetMillis() + 300000)
+		wc.SetConnectionID(model.NewId())
+		wc.Active.Store(true)
+
+		connIndex := newHubConnectionIndex(time.Second)
+		connIndex.Add(wc)
+		return wc, connIndex
+	}
+
+	replay := func(wc *WebConn, connIndex *hubConnectionIndex) {
+		hub.deliverEventLogReplay(connIndex, hub.loadEventLogReplay(wc))
+	}
+
+	// receive returns the events queued after the hello message.
+	receive := func(t *testing.T, wc *WebConn) []*model.WebSocketEvent {
+		var events []*model.WebSocketEvent
+		for len(wc.send) > 0 {
+			events = append(events, (<-wc.send).(*model.WebSocketEvent))
+		}
+		require.NotEmpty(t, events)
+		require.Equal(t, model.WebsocketEventHello, events[0].EventType())
+		return events[1:]
+	}
+
+	t.Run("replays the missed events", func(t *testing.T) {
+		mockEventStore.On("GetSeqRange").Return(int64(1), int64(12), nil).Once()
+		mockEventStore.On("GetSince", "user1", int64(10), int64(eventLogReplayOverlap), eventLogReplayPageSize).Return([]*model.WebSocketEventLogEntry{
+			newLogEntry(11, "user1"),
+			newLogEntry(12, "user2"),
+		}, nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.WebsocketEventPreferencesChanged, events[0].EventType())
+		assert.Equal(t, int64(11), events[0].GetLogSequence())
+	})
+
+	t.Run("leaves the events of other channels out of the replay limit", func(t *testing.T) {
+		entries := make([]*model.WebSocketEventLogEntry, eventLogReplayPageSize)
+		for i := range entries {
+			entries[i] = newChannelLogEntry(int64(11+i), "channel2")
+		}
+		last := entries[len(entries)-1].Seq
+		mockEventStore.On("GetSeqRange").Return(int64(1), last+2, nil).Once()
+		mockEventStore.On("GetSince", "user1", int64(10), int64(eventLogReplayOverlap), eventLogReplayPageSize).Return(entries, nil).Once()
+		mockEventStore.On("GetSince", "user1", last, int64(0), eventLogReplayPageSize).Return([]*model.WebSocketEventLogEntry{
+			newChannelLogEntry(last+1, "channel1"),
+			newLogEntry(last+2, "user1"),
+		}, nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.WebsocketEventPosted, events[0].EventType())
+		assert.Equal(t, last+1, events[0].GetLogSequence())
+		assert.Equal(t, last+2, events[1].GetLogSequence())
+	})
+
+	t.Run("requires a resync when too many events of other channels were missed", func(t *testing.T) {
+		entries := make([]*model.WebSocketEventLogEntry, eventLogReplayPageSize)
+		for i := range entries {
+			entries[i] = newChannelLogEntry(int64(11+i), "channel2")
+		}
+		mockEventStore.On("GetSeqRange").Return(int64(1), int64(10+eventLogReplayScanLimit), nil).Once()
+		mockEventStore.On("GetSince", "user1", mock.Anything, mock.Anything, eventLogReplayPageSize).Return(entries, nil).Times(eventLogReplayScanLimit / eventLogReplayPageSize)
+
+		wc, connIndex := newWebConn(10)
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.WebsocketEventResyncRequired, events[0].EventType())
+		assert.Equal(t, int64(10+eventLogReplayScanLimit), events[0].GetData()["log_seq"])
+	})
+
+	t.Run("queues the events held back during the replay after the replayed ones", func(t *testing.T) {
+		mockEventStore.On("GetSeqRange").Return(int64(1), int64(12), nil).Once()
+		mockEventStore.On("GetSince", "user1", int64(10), int64(eventLogReplayOverlap), eventLogReplayPageSize).Return([]*model.WebSocketEventLogEntry{
+			newLogEntry(11, "user1"),
+			newLogEntry(12, "user1"),
+		}, nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		wc.replayingEventLog = true
+		wc.holdEvent(newEvent("user1").SetLogSequence(12))
+		wc.holdEvent(newEvent("user1").SetLogSequence(13))
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 3)
+		assert.Equal(t, int64(11), events[0].GetLogSequence())
+		assert.Equal(t, int64(12), events[1].GetLogSequence())
+		assert.Equal(t, int64(13), events[2].GetLogSequence())
+		assert.False(t, wc.replayingEventLog)
+		assert.Empty(t, wc.heldEvents)
+	})
+
+	t.Run("requires a resync when too many events are held back", func(t *testing.T) {
+		mockEventStore.On("GetSeqRange").Return(int64(1), int64(10), nil).Once()
+		mockEventStore.On("GetSince", "user1", int64(10), int64(eventLogReplayOverlap), eventLogReplayPageSize).Return([]*model.WebSocketEventLogEntry{}, nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		wc.replayingEventLog = true
+		for i := 0; i <= sendQueueSize; i++ {
+			wc.holdEvent(newEvent("user1").SetLogSequence(int64(11 + i)))
+		}
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.WebsocketEventResyncRequired, events[0].EventType())
+		assert.Equal(t, int64(11+sendQueueSize), events[0].GetData()["log_seq"])
+	})
+
+	t.Run("requires a resync when the events are gone", func(t *testing.T) {
+		mockEventStore.On("GetSeqRange").Return(int64(20), int64(30), nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.WebsocketEventResyncRequired, events[0].EventType())
+		assert.Equal(t, int64(30), events[0].GetData()["log_seq"])
+	})
+
+	t.Run("requires a resync when the client is ahead of the log", func(t *testing.T) {
+		mockEventStore.On("GetSeqRange").Return(int64(1), int64(5), nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.WebsocketEventResyncRequired, events[0].EventType())
+		assert.Equal(t, int64(5), events[0].GetData()["log_seq"])
+	})
+
+	t.Run("requires a resync when too many events were missed", func(t *testing.T) {
+		entries := make([]*model.WebSocketEventLogEntry, eventLogReplayLimit+1)
+		for i := range entries {
+			entries[i] = newLogEntry(int64(11+i), "user1")
+		}
+		latest := int64(10 + len(entries))
+		mockEventStore.On("GetSeqRange").Return(int64(1), latest, nil).Once()
+		mockEventStore.On("GetSince", "user1", int64(10), int64(eventLogReplayOverlap), eventLogReplayPageSize).Return(entries, nil).Once()
+
+		wc, connIndex := newWebConn(10)
+		replay(wc, connIndex)
+
+		events := receive(t, wc)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.WebsocketEventResyncRequired, events[0].EventType())
+		assert.Equal(t, latest, events[0].GetData()["log_seq"])
+	})
+}
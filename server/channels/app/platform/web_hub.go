@@ -77,23 +77,26 @@ type Hub struct {
 	checkConn       chan *webConnCheckMessage
 	connCount       chan *webConnCountMessage
 	broadcastHooks  map[string]BroadcastHook
+	// eventLogReplayed receives the events to replay to reconnecting clients.
+	eventLogReplayed chan *eventLogReplay
 }
 
 // newWebHub creates a new Hub.
 func newWebHub(ps *PlatformService) *Hub {
 	return &Hub{
-		platform:        ps,
-		register:        make(chan *WebConn),
-		unregister:      make(chan *WebConn),
-		broadcast:       make(chan *model.WebSocketEvent, broadcastQueueSize),
-		stop:            make(chan struct{}),
-		didStop:         make(chan struct{}),
-		invalidateUser:  make(chan string),
-		activity:        make(chan *webConnActivityMessage),
-		directMsg:       make(chan *webConnDirectMessage),
-		checkRegistered: make(chan *webConnSessionMessage),
-		checkConn:       make(chan *webConnCheckMessage),
-		connCount:       make(chan *webConnCountMessage),
+		platform:         ps,
+		register:         make(chan *WebConn),
+		unregister:       make(chan *WebConn),
+		broadcast:        make(chan *model.WebSocketEvent, broadcastQueueSize),
+		stop:             make(chan struct{}),
+		didStop:          make(chan struct{}),
+		invalidateUser:   make(chan string),
+		activity:         make(chan *webConnActivityMessage),
+		directMsg:        make(chan *webConnDirectMessage),
+		checkRegistered:  make(chan *webConnSessionMessage),
+		checkConn:        make(chan *webConnCheckMessage),
+		connCount:        make(chan *webConnCountMessage),
+		eventLogReplayed: make(chan *eventLogReplay),
 	}
 }
 
@@ -116,6 +119,10 @@ func (ps *PlatformService) hubStart(broadcastHooks map[string]BroadcastHook) {
 	// Assigning to the hubs slice without any mutex is fine because it is only assigned once
 	// during the start of the program and always read from after that.
 	ps.hubs = hubs
+
+	writer := newEventLogWriter(ps)
+	writer.Start()
+	ps.eventLogWriter.Store(writer)
 }
 
 func (ps *PlatformService) InvalidateCacheForWebhook(webhookID string) {
@@ -126,6 +133,11 @@ func (ps *PlatformService) InvalidateCacheForWebhook(webhookID string) {
 func (ps *PlatformService) HubStop() {
 	ps.logger.Info("stopping websocket hub connections")
 
+	// The queued events are published before the hubs stop.
+	if writer := ps.eventLogWriter.Swap(nil); writer != nil {
+		writer.Stop()
+	}
+
 	for _, hub := range ps.hubs {
 		hub.Stop()
 	}
@@ -421,7 +433,17 @@ func (h *Hub) Start() {
 			case req := <-h.connCount:
 				req.result <- connIndex.ForUserActiveCount(req.userID)
 			case <-ticker.C:
-				connIndex.RemoveInactiveConnections()
+				for _, webConn := range connIndex.RemoveInactiveConnections() {
+					// Long-poll clients are inactive between requests, they are only
+					// disconnected once they stopped polling.
+					if !webConn.isLongPoll() || webConn.UserId == "" {
+						continue
+					}
+					webConn.runDisconnectHook()
+					if conns := connIndex.ForUser(webConn.UserId); len(conns) == 0 || areAllInactive(conns) {
+						h.setOfflineIfDisconnected(webConn.UserId)
+					}
+				}
 			case webConn := <-h.register:
 				// Mark the current one as active.
 				// There is no need to check if it was inactive or not,
@@ -431,13 +453,18 @@ func (h *Hub) Start() {
 				connIndex.Add(webConn)
 				atomic.StoreInt64(&h.connectionCount, int64(connIndex.AllActive()))
 
-				if webConn.IsAuthenticated() && webConn.reuseCount == 0 {
+				if webConn.IsAuthenticated() && webConn.replayFromLog {
+					// The hello message is sent along with the replayed events.
+					h.replayEventLog(webConn)
+				} else if webConn.IsAuthenticated() && webConn.reuseCount == 0 {
 					// The hello message should only be sent when the reuseCount is 0.
 					// i.e in server restart, or long timeout, or fresh connection case.
 					// In case of seq number not found in dead queue, it is handled by
 					// the webconn write pump.
 					webConn.send <- webConn.createHelloMessage()
 				}
+			case replay := <-h.eventLogReplayed:
+				h.deliverEventLogReplay(connIndex, replay)
 			case webConn := <-h.unregister:
 				// If already removed (via queue full), then removing again becomes a noop.
 				// But if not removed, mark inactive.
@@ -451,29 +478,10 @@ func (h *Hub) Start() {
 
 				conns := connIndex.ForUser(webConn.UserId)
 				if len(conns) == 0 || areAllInactive(conns) {
-					userID := webConn.UserId
-					h.platform.Go(func() {
-						// If this is an HA setup, get count for this user
-						// from other nodes.
-						var clusterCnt int
-						var appErr *model.AppError
-						if h.platform.Cluster() != nil {
-							clusterCnt, appErr = h.platform.Cluster().WebConnCountForUser(userID)
-						}
-						if appErr != nil {
-							mlog.Error("Error in trying to get the webconn count from cluster", mlog.Err(appErr))
-							// We take a conservative approach
-							// and do not set status to offline in case
-							// there's an error, rather than potentially
-							// incorrectly setting status to offline.
-							return
-						}
-						// Only set to offline if there are no
-						// active connections in other nodes as well.
-						if clusterCnt == 0 {
-							h.platform.SetStatusOffline(userID, false)
-						}
-					})
+					// The status of long-poll clients is updated once they stopped polling.
+					if !webConn.isLongPoll() {
+						h.setOfflineIfDisconnected(webConn.UserId)
+					}
 					continue
 				}
 				var latestActivity int64
@@ -536,6 +544,10 @@ func (h *Hub) Start() {
 						return
 					}
 					if webConn.ShouldSendEvent(msg) {
+						if webConn.replayingEventLog {
+							webConn.holdEvent(h.runBroadcastHooks(msg, webConn, broadcastHooks, broadcastHookArgs))
+							return
+						}
 						select {
 						case webConn.send <- h.runBroadcastHooks(msg, webConn, broadcastHooks, broadcastHookArgs):
 						default:
@@ -604,6 +616,32 @@ func (h *Hub) Start() {
 	go doRecoverableStart()
 }
 
+// setOfflineIfDisconnected sets the user offline, unless connected to another node.
+func (h *Hub) setOfflineIfDisconnected(userID string) {
+	h.platform.Go(func() {
+		// If this is an HA setup, get count for this user
+		// from other nodes.
+		var clusterCnt int
+		var appErr *model.AppError
+		if h.platform.Cluster() != nil {
+			clusterCnt, appErr = h.platform.Cluster().WebConnCountForUser(userID)
+		}
+		if appErr != nil {
+			mlog.Error("Error in trying to get the webconn count from cluster", mlog.Err(appErr))
+			// We take a conservative approach
+			// and do not set status to offline in case
+			// there's an error, rather than potentially
+			// incorrectly setting status to offline.
+			return
+		}
+		// Only set to offline if there are no
+		// active connections in other nodes as well.
+		if clusterCnt == 0 {
+			h.platform.SetStatusOffline(userID, false)
+		}
+	})
+}
+
 // areAllInactive returns whether all of the connections
 // are inactive or not.
 func areAllInactive(conns []*WebConn) bool {
@@ -730,14 +768,17 @@ func (i *hubConnectionIndex) RemoveInactiveByConnectionID(userID, connectionID s
 }
 
 // RemoveInactiveConnections removes all inactive connections whose lastUserActivityAt
-// exceeded staleThreshold.
-func (i *hubConnectionIndex) RemoveInactiveConnections() {
+// exceeded staleThreshold, and returns them.
+func (i *hubConnectionIndex) RemoveInactiveConnections() []*WebConn {
+	var removed []*WebConn
 	now := model.GetMillis()
 	for conn := range i.byConnection {
 		if !conn.Active.Load() && now-conn.lastUserActivityAt > i.staleThreshold.Milliseconds() {
 			i.Remove(conn)
+			removed = append(removed, conn)
 		}
 	}
+	return removed
 }
 
 // AllActive returns the number of active connections.
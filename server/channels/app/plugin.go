@@ -204,13 +204,21 @@ func (ch *Channels) initPlugins(c request.CTX, pluginDir, webappPluginDir string
 		return New(ServerConnector(ch)).NewPluginAPI(c, manifest)
 	}
 
+	var metrics pluginMetricsInterface
+	if m := ch.srv.GetMetrics(); m != nil {
+		metrics = m
+	}
+	if *ch.cfgSvc.Config().ServiceSettings.EnableOpenTracing {
+		metrics = &pluginTracingMetrics{metrics: metrics}
+	}
+
 	env, err := plugin.NewEnvironment(
 		newAPIFunc,
 		NewDriverImpl(ch.srv),
 		pluginDir,
 		webappPluginDir,
 		ch.srv.Log(),
-		ch.srv.GetMetrics(),
+		metrics,
 	)
 	if err != nil {
 		ch.srv.Log().Error("Failed to start up plugins", mlog.Err(err))
@@ -12,11 +12,14 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/platform/services/tracing"
 )
 
 func (ch *Channels) ServePluginRequest(w http.ResponseWriter, r *http.Request) {
@@ -70,8 +73,9 @@ func (a *App) ServeInterPluginRequest(w http.ResponseWriter, r *http.Request, so
 	}
 
 	context := &plugin.Context{
-		RequestId: model.NewId(),
-		UserAgent: r.UserAgent(),
+		RequestId:   model.NewId(),
+		UserAgent:   r.UserAgent(),
+		TraceParent: tracing.TraceParent(tracing.ExtractHTTPHeaders(r.Context(), r.Header)),
 	}
 
 	r.Header.Set("Mattermost-Plugin-ID", sourcePluginId)
@@ -226,5 +230,19 @@ func (ch *Channels) servePluginRequest(w http.ResponseWriter, r *http.Request, h
 	r.URL.RawQuery = newQuery.Encode()
 	r.URL.Path = strings.TrimPrefix(r.URL.Path, path.Join(subpath, "plugins", params["plugin_id"]))
 
+	if *ch.cfgSvc.Config().ServiceSettings.EnableOpenTracing {
+		// The plugin continues the trace from the span of the request propagated in the headers.
+		span, ctx := tracing.StartRootSpanByContext(tracing.ExtractHTTPHeaders(r.Context(), r.Header), "plugin:ServeHTTP", trace.SpanKindServer)
+		span.SetAttributes(
+			attribute.String("plugin_id", params["plugin_id"]),
+			attribute.String("request_id", context.RequestId),
+		)
+		defer span.End()
+
+		tracing.InjectHTTPHeaders(ctx, r.Header)
+		r = r.WithContext(ctx)
+		context.TraceParent = tracing.TraceParent(ctx)
+	}
+
 	handler(context, w, r)
 }
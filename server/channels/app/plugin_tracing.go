@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/v8/platform/services/tracing"
+)
+
+// pluginMetricsInterface is the interface used by the plugin environment to observe the
+// duration of the hooks and API calls of the plugins.
+type pluginMetricsInterface interface {
+	ObservePluginHookDuration(pluginID, hookName string, success bool, elapsed float64)
+	ObservePluginMultiHookIterationDuration(pluginID string, elapsed float64)
+	ObservePluginMultiHookDuration(elapsed float64)
+	ObservePluginAPIDuration(pluginID, apiName string, success bool, elapsed float64)
+}
+
+// pluginTracingMetrics records a span for each hook called over RPC and each API call of the
+// plugins, in addition to observing their durations with the wrapped metrics, if any.
+//
+// The spans of the hooks called with a plugin context are children of the span of the
+// request, propagated in the context. The API calls carry no context over RPC, so their
+// spans are root spans.
+type pluginTracingMetrics struct {
+	metrics pluginMetricsInterface
+}
+
+func (m *pluginTracingMetrics) ObservePluginHookDuration(pluginID, hookName string, success bool, elapsed float64) {
+	recordPluginSpan(context.Background(), "plugin.hook."+hookName, trace.SpanKindClient, pluginID, success, elapsed)
+	if m.metrics != nil {
+		m.metrics.ObservePluginHookDuration(pluginID, hookName, success, elapsed)
+	}
+}
+
+func (m *pluginTracingMetrics) ObservePluginHookDurationWithContext(c *plugin.Context, pluginID, hookName string, success bool, elapsed float64) {
+	ctx := tracing.ContextWithTraceParent(context.Background(), c.TraceParent)
+	recordPluginSpan(ctx, "plugin.hook."+hookName, trace.SpanKindClient, pluginID, success, elapsed)
+	if m.metrics != nil {
+		m.metrics.ObservePluginHookDuration(pluginID, hookName, success, elapsed)
+	}
+}
+
+func (m *pluginTracingMetrics) ObservePluginMultiHookIterationDuration(pluginID string, elapsed float64) {
+	if m.metrics != nil {
+		m.metrics.ObservePluginMultiHookIterationDuration(pluginID, elapsed)
+	}
+}
+
+func (m *pluginTracingMetrics) ObservePluginMultiHookDuration(elapsed float64) {
+	if m.metrics != nil {
+		m.metrics.ObservePluginMultiHookDuration(elapsed)
+	}
+}
+
+func (m *pluginTracingMetrics) ObservePluginAPIDuration(pluginID, apiName string, success bool, elapsed float64) {
+	recordPluginSpan(context.Background(), "plugin.api."+apiName, trace.SpanKindServer, pluginID, success, elapsed)
+	if m.metrics != nil {
+		m.metrics.ObservePluginAPIDuration(pluginID, apiName, success, elapsed)
+	}
+}
+
+// recordPluginSpan records the span of a call that just completed, since the plugin
+// environment only reports the calls once they are done. The span is a child of the span of
+// the context, if any.
+func recordPluginSpan(ctx context.Context, name string, kind trace.SpanKind, pluginID string, success bool, elapsed float64) {
+	end := time.Now()
+	start := end.Add(-time.Duration(elapsed * float64(time.Second)))
+
+	span, _ := tracing.StartRootSpanByContext(ctx, name, kind, trace.WithTimestamp(start))
+	span.SetAttributes(attribute.String("plugin_id", pluginID))
+	if !success {
+		span.SetStatus(codes.Error, "")
+	}
+	span.End(trace.WithTimestamp(end))
+}
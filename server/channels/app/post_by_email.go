@@ -0,0 +1,399 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/mail"
+)
+
+const (
+	// inboundEmailMaxAttachments is the number of files that fit in a post.
+	inboundEmailMaxAttachments = 10
+	inboundEmailDKIMTimeout    = 10 * time.Second
+)
+
+// verifyInboundEmailDKIM returns the domains of the valid DKIM signatures of an email.
+// It is replaced by the tests, which can't publish DKIM keys.
+var verifyInboundEmailDKIM = func(ctx context.Context, data []byte) ([]string, error) {
+	return mail.VerifyDKIM(ctx, data, net.DefaultResolver.LookupTXT)
+}
+
+// getPostByEmailAddress returns the sub-address of the configured address used
+// to post to a channel, e.g. channel+<token>@example.com.
+func getPostByEmailAddress(address, token string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return ""
+	}
+	return address[:at] + "+" + token + address[at:]
+}
+
+// parsePostByEmailAddress returns the token of a channel address, if the recipient
+// is a sub-address of the configured address.
+func parsePostByEmailAddress(address, recipient string) (token string, ok bool) {
+	at := strings.LastIndex(address, "@")
+	recipientAt := strings.LastIndex(recipient, "@")
+	if at < 0 || recipientAt < 0 || !strings.EqualFold(address[at:], recipient[recipientAt:]) {
+		return "", false
+	}
+
+	prefix := address[:at] + "+"
+	local := recipient[:recipientAt]
+	if len(local) < len(prefix) || !strings.EqualFold(local[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	// Some servers change the case of the address.
+	token = strings.ToLower(local[len(prefix):])
+	if !model.IsValidId(token) {
+		return "", false
+	}
+
+	return token, true
+}
+
+func (a *App) isPostByEmailRecipient(recipient string) bool {
+	_, ok := parsePostByEmailAddress(*a.Config().EmailSettings.PostByEmailAddress, recipient)
+	return ok
+}
+
+// isInboundEmailRecipient reports whether the inbound email server accepts emails
+// for the recipient, either replies to notifications or posts to a channel.
+func (a *App) isInboundEmailRecipient(recipient string) bool {
+	if *a.Config().EmailSettings.EnableReplyByEmail && a.isReplyByEmailRecipient(recipient) {
+		return true
+	}
+	return *a.Config().EmailSettings.EnablePostByEmail && a.isPostByEmailRecipient(recipient)
+}
+
+// ReceiveInboundEmail posts the email received by the inbound email server for the recipient.
+func (a *App) ReceiveInboundEmail(c request.CTX, recipient string, data []byte) (*model.Post, *model.AppError) {
+	if a.isReplyByEmailRecipient(recipient) {
+		return a.ReceiveReplyByEmail(c, recipient, data)
+	}
+	return a.ReceivePostByEmail(c, recipient, data)
+}
+
+func (a *App) fillChannelEmailAddress(address *model.ChannelEmailAddress) *model.ChannelEmailAddress {
+	address.Address = getPostByEmailAddress(*a.Config().EmailSettings.PostByEmailAddress, address.Token)
+	return address
+}
+
+func (a *App) GetChannelEmailAddress(channelID string) (*model.ChannelEmailAddress, *model.AppError) {
+	address, err := a.Srv().Store().ChannelEmailAddress().Get(channelID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("GetChannelEmailAddress", "app.channel_email_address.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("GetChannelEmailAddress", "app.channel_email_address.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return a.fillChannelEmailAddress(address), nil
+}
+
+// CreateChannelEmailAddress gives the channel an inbound email address. When incoming webhooks
+// are enabled, the emails of the senders that are not users of the server are posted through
+// an incoming webhook created for the address.
+func (a *App) CreateChannelEmailAddress(c request.CTX, channel *model.Channel, creatorID string, patch *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.AppError) {
+	if !*a.Config().EmailSettings.EnablePostByEmail {
+		return nil, model.NewAppError("CreateChannelEmailAddress", "app.channel_email_address.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if channel.DeleteAt != 0 {
+		return nil, model.NewAppError("CreateChannelEmailAddress", "app.channel_email_address.channel_archived.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	address := &model.ChannelEmailAddress{
+		ChannelId: channel.Id,
+		CreatorId: creatorID,
+	}
+	if patch != nil {
+		address.Patch(patch)
+	}
+
+	if *a.Config().ServiceSettings.EnableIncomingWebhooks {
+		hook, appErr := a.CreateIncomingWebhookForChannel(creatorID, channel, &model.IncomingWebhook{
+			ChannelId:     channel.Id,
+			DisplayName:   "Post by email",
+			Description:   "Posts the emails sent to the channel address.",
+			Username:      "email",
+			ChannelLocked: true,
+		})
+		if appErr != nil {
+			return nil, appErr
+		}
+		address.WebhookId = hook.Id
+	}
+
+	saved, err := a.Srv().Store().ChannelEmailAddress().Save(address)
+	if err != nil {
+		if address.WebhookId != "" {
+			if appErr := a.DeleteIncomingWebhook(address.WebhookId); appErr != nil {
+				c.Logger().Warn("Failed to delete the incoming webhook of a channel email address", mlog.String("webhook_id", address.WebhookId), mlog.Err(appErr))
+			}
+		}
+
+		var appErr *model.AppError
+		var conflictErr *store.ErrConflict
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &conflictErr):
+			return nil, model.NewAppError("CreateChannelEmailAddress", "app.channel_email_address.save.exists.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		default:
+			return nil, model.NewAppError("CreateChannelEmailAddress", "app.channel_email_address.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return a.fillChannelEmailAddress(saved), nil
+}
+
+func (a *App) PatchChannelEmailAddress(channelID string, patch *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.AppError) {
+	address, appErr := a.GetChannelEmailAddress(channelID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	address.Patch(patch)
+	return a.updateChannelEmailAddress(address)
+}
+
+// RegenerateChannelEmailAddress replaces the address of the channel, so that the
+// emails sent to the previous one are rejected.
+func (a *App) RegenerateChannelEmailAddress(channelID string) (*model.ChannelEmailAddress, *model.AppError) {
+	address, appErr := a.GetChannelEmailAddress(channelID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	address.Token = model.NewId()
+	return a.updateChannelEmailAddress(address)
+}
+
+func (a *App) updateChannelEmailAddress(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, *model.AppError) {
+	updated, err := a.Srv().Store().ChannelEmailAddress().Update(address)
+	if err != nil {
+		var appErr *model.AppError
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("updateChannelEmailAddress", "app.channel_email_address.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("updateChannelEmailAddress", "app.channel_email_address.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return a.fillChannelEmailAddress(updated), nil
+}
+
+// DeleteChannelEmailAddress removes the address of the channel along with its incoming webhook.
+func (a *App) DeleteChannelEmailAddress(c request.CTX, channelID string) *model.AppError {
+	address, appErr := a.GetChannelEmailAddress(channelID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := a.Srv().Store().ChannelEmailAddress().Delete(channelID); err != nil {
+		return model.NewAppError("DeleteChannelEmailAddress", "app.channel_email_address.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if address.WebhookId != "" && *a.Config().ServiceSettings.EnableIncomingWebhooks {
+		if appErr := a.DeleteIncomingWebhook(address.WebhookId); appErr != nil {
+			c.Logger().Warn("Failed to delete the incoming webhook of a channel email address", mlog.String("webhook_id", address.WebhookId), mlog.Err(appErr))
+		}
+	}
+
+	return nil
+}
+
+// isInboundEmailSenderAuthenticated returns whether a valid DKIM signature of the domain
+// of the sender authenticates the email. The From header is set freely by the senders
+// otherwise.
+func (a *App) isInboundEmailSenderAuthenticated(c request.CTX, data []byte, from string) bool {
+	ctx, cancel := context.WithTimeout(c.Context(), inboundEmailDKIMTimeout)
+	defer cancel()
+
+	domains, err := verifyInboundEmailDKIM(ctx, data)
+	if err != nil {
+		c.Logger().Debug("Failed to verify the DKIM signatures of an inbound email", mlog.String("from", from), mlog.Err(err))
+	}
+
+	return slices.ContainsFunc(domains, func(domain string) bool {
+		return mail.IsDKIMAligned(from, domain)
+	})
+}
+
+// ReceivePostByEmail posts the email sent to the address of a channel. The post is made by
+// the sender when the email is authenticated by a DKIM signature of their domain and their
+// verified email belongs to a user allowed to post to the channel, and through the incoming
+// webhook of the address otherwise.
+func (a *App) ReceivePostByEmail(c request.CTX, recipient string, data []byte) (*model.Post, *model.AppError) {
+	if !*a.Config().EmailSettings.EnablePostByEmail {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.channel_email_address.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	token, ok := parsePostByEmailAddress(*a.Config().EmailSettings.PostByEmailAddress, recipient)
+	if !ok {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.invalid_recipient.app_error", nil, "recipient="+recipient, http.StatusBadRequest)
+	}
+
+	address, err := a.Srv().Store().ChannelEmailAddress().GetByToken(token)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.invalid_recipient.app_error", nil, "recipient="+recipient, http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("ReceivePostByEmail", "app.channel_email_address.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	email, err := mail.ParseInboundEmail(data)
+	if err != nil {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.parse.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	if email.AutoSubmitted {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.auto_submitted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	// Only the authenticated senders can be allowed, anyone can send an email from
+	// an allowed address otherwise.
+	authenticated := a.isInboundEmailSenderAuthenticated(c, data, email.From.Address)
+	if !address.IsSenderAllowed(email.From.Address) || (len(address.AllowedSenders) > 0 && !authenticated) {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.sender_not_allowed.app_error", nil, "", http.StatusForbidden)
+	}
+
+	channel, appErr := a.GetChannel(c, address.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if channel.DeleteAt != 0 {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.channel_email_address.channel_archived.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	message := mail.StripReplyQuote(email.Text)
+	if subject := strings.TrimSpace(email.Subject); subject != "" {
+		message = strings.TrimSpace("**" + subject + "**\n\n" + message)
+	}
+	if maxPostSize := a.MaxPostSize(); utf8.RuneCountInString(message) > maxPostSize {
+		message = string([]rune(message)[:maxPostSize])
+	}
+
+	if !authenticated {
+		c.Logger().Debug("Posting an unauthenticated inbound email through the webhook", mlog.String("channel_id", channel.Id))
+	} else if user, appErr := a.GetUserByEmail(email.From.Address); appErr == nil && user.EmailVerified && user.DeleteAt == 0 && !user.IsBot &&
+		a.HasPermissionToChannel(c, user.Id, channel.Id, model.PermissionCreatePost) {
+		post := &model.Post{
+			UserId:    user.Id,
+			ChannelId: channel.Id,
+			Message:   message,
+			FileIds:   a.uploadInboundEmailAttachments(c, channel.Id, user.Id, email.Attachments),
+		}
+		if post.Message == "" && len(post.FileIds) == 0 {
+			return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.empty.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		return a.CreatePostAsUser(c, post, "", false)
+	}
+
+	if address.WebhookId == "" || !*a.Config().ServiceSettings.EnableIncomingWebhooks {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.invalid_sender.app_error", nil, "", http.StatusForbidden)
+	}
+
+	hook, appErr := a.GetIncomingWebhook(address.WebhookId)
+	if appErr != nil {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.invalid_sender.app_error", nil, "", http.StatusForbidden).Wrap(appErr)
+	}
+
+	post := &model.Post{
+		UserId:    hook.UserId,
+		ChannelId: channel.Id,
+		Message:   message,
+		FileIds:   a.uploadInboundEmailAttachments(c, channel.Id, hook.UserId, email.Attachments),
+	}
+	if post.Message == "" && len(post.FileIds) == 0 {
+		return nil, model.NewAppError("ReceivePostByEmail", "app.post_by_email.empty.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	post.AddProp("from_webhook", "true")
+	post.AddProp("webhook_display_name", hook.DisplayName)
+	post.AddProp("from_email", email.From.Address)
+	if *a.Config().ServiceSettings.EnablePostUsernameOverride {
+		if email.From.Name != "" {
+			post.AddProp("override_username", email.From.Name)
+		} else {
+			post.AddProp("override_username", email.From.Address)
+		}
+	}
+
+	if metrics := a.Metrics(); metrics != nil {
+		metrics.IncrementWebhookPost()
+	}
+
+	return a.CreatePost(c, post, channel, false, false)
+}
+
+// uploadInboundEmailAttachments uploads the attachments of an email received by the inbound
+// email server to the channel, skipping the ones that cannot be uploaded.
+func (a *App) uploadInboundEmailAttachments(c request.CTX, channelID, userID string, attachments []*mail.InboundAttachment) []string {
+	if len(attachments) == 0 || !*a.Config().FileSettings.EnableFileAttachments {
+		return nil
+	}
+
+	var fileIDs []string
+	for _, attachment := range attachments {
+		if len(fileIDs) >= inboundEmailMaxAttachments {
+			break
+		}
+
+		size := int64(len(attachment.Data))
+		if size == 0 || size > *a.Config().FileSettings.MaxFileSize {
+			c.Logger().Warn("Skipped the attachment of an inbound email", mlog.String("filename", attachment.Filename), mlog.Int("size", size))
+			continue
+		}
+
+		us, appErr := a.CreateUploadSession(c, &model.UploadSession{
+			Id:        model.NewId(),
+			Type:      model.UploadTypeAttachment,
+			UserId:    userID,
+			ChannelId: channelID,
+			Filename:  attachment.Filename,
+			FileSize:  size,
+		})
+		if appErr != nil {
+			c.Logger().Warn("Failed to upload the attachment of an inbound email", mlog.String("filename", attachment.Filename), mlog.Err(appErr))
+			continue
+		}
+
+		info, appErr := a.UploadData(c, us, bytes.NewReader(attachment.Data))
+		if appErr != nil || info == nil {
+			c.Logger().Warn("Failed to upload the attachment of an inbound email", mlog.String("filename", attachment.Filename), mlog.Err(appErr))
+			continue
+		}
+		fileIDs = append(fileIDs, info.Id)
+	}
+
+	return fileIDs
+}
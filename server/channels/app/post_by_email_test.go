@@ -0,0 +1,240 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestPostByEmailAddress(t *testing.T) {
+	token := model.NewId()
+	address := getPostByEmailAddress("channel@example.com", token)
+	assert.Equal(t, "channel+"+token+"@example.com", address)
+
+	for name, tc := range map[string]struct {
+		recipient string
+		ok        bool
+	}{
+		"channel address":    {recipient: address, ok: true},
+		"upper case":         {recipient: strings.ToUpper(address), ok: true},
+		"configured address": {recipient: "channel@example.com"},
+		"other domain":       {recipient: strings.Replace(address, "example.com", "example.org", 1)},
+		"other local part":   {recipient: strings.Replace(address, "channel+", "other+", 1)},
+		"invalid token":      {recipient: "channel+" + token[1:] + "@example.com"},
+		"reply address":      {recipient: "channel+" + token + strings.Repeat("a", 16) + "@example.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			parsedToken, ok := parsePostByEmailAddress("channel@example.com", tc.recipient)
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, token, parsedToken)
+			}
+		})
+	}
+}
+
+func TestChannelEmailAddress(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, appErr := th.App.CreateChannelEmailAddress(th.Context, th.BasicChannel, th.BasicUser.Id, nil)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotImplemented, appErr.StatusCode)
+	})
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.EmailSettings.EnablePostByEmail = true
+		*cfg.EmailSettings.PostByEmailAddress = "channel@example.com"
+		*cfg.EmailSettings.InboundSMTPListenAddress = "127.0.0.1:0"
+		*cfg.ServiceSettings.EnableIncomingWebhooks = true
+	})
+
+	address, appErr := th.App.CreateChannelEmailAddress(th.Context, th.BasicChannel, th.BasicUser.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"@Example.com "}})
+	require.Nil(t, appErr)
+	assert.Equal(t, "channel+"+address.Token+"@example.com", address.Address)
+	assert.Equal(t, model.StringArray{"@example.com"}, address.AllowedSenders)
+	require.NotEmpty(t, address.WebhookId)
+
+	hook, appErr := th.App.GetIncomingWebhook(address.WebhookId)
+	require.Nil(t, appErr)
+	assert.Equal(t, th.BasicChannel.Id, hook.ChannelId)
+	assert.True(t, hook.ChannelLocked)
+
+	_, appErr = th.App.CreateChannelEmailAddress(th.Context, th.BasicChannel, th.BasicUser.Id, nil)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "app.channel_email_address.save.exists.app_error", appErr.Id)
+
+	patched, appErr := th.App.PatchChannelEmailAddress(th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{}})
+	require.Nil(t, appErr)
+	assert.Empty(t, patched.AllowedSenders)
+	assert.Equal(t, address.Token, patched.Token)
+
+	regenerated, appErr := th.App.RegenerateChannelEmailAddress(th.BasicChannel.Id)
+	require.Nil(t, appErr)
+	assert.NotEqual(t, address.Token, regenerated.Token)
+	assert.NotEqual(t, address.Address, regenerated.Address)
+
+	require.Nil(t, th.App.DeleteChannelEmailAddress(th.Context, th.BasicChannel.Id))
+	_, appErr = th.App.GetChannelEmailAddress(th.BasicChannel.Id)
+	require.NotNil(t, appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	_, appErr = th.App.GetIncomingWebhook(address.WebhookId)
+	require.NotNil(t, appErr)
+}
+
+func TestReceivePostByEmail(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.EmailSettings.EnablePostByEmail = true
+		*cfg.EmailSettings.PostByEmailAddress = "channel@example.com"
+		*cfg.EmailSettings.InboundSMTPListenAddress = "127.0.0.1:0"
+		*cfg.ServiceSettings.EnableIncomingWebhooks = true
+		*cfg.ServiceSettings.EnablePostUsernameOverride = true
+	})
+
+	address, appErr := th.App.CreateChannelEmailAddress(th.Context, th.BasicChannel, th.BasicUser.Id, nil)
+	require.Nil(t, appErr)
+
+	email := func(from, headers, body string) []byte {
+		return []byte("From: " + from + "\r\nTo: " + address.Address + "\r\nSubject: Weekly report\r\n" + headers + "\r\n" + body)
+	}
+
+	// The tests can't publish DKIM keys, a fake signature header stands for a valid signature of its domain.
+	dkimSignature := func(domain string) string {
+		return "DKIM-Signature: d=" + domain + "\r\n"
+	}
+	verifyDKIM := verifyInboundEmailDKIM
+	verifyInboundEmailDKIM = func(_ context.Context, data []byte) ([]string, error) {
+		var domains []string
+		for _, match := range regexp.MustCompile(`DKIM-Signature: d=(\S+)`).FindAllSubmatch(data, -1) {
+			domains = append(domains, string(match[1]))
+		}
+		return domains, nil
+	}
+	defer func() { verifyInboundEmailDKIM = verifyDKIM }()
+
+	userDomain := th.BasicUser.Email[strings.LastIndex(th.BasicUser.Email, "@")+1:]
+
+	t.Run("posts as the user with the verified email", func(t *testing.T) {
+		require.Nil(t, th.App.VerifyUserEmail(th.BasicUser.Id, th.BasicUser.Email))
+
+		post, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, email(th.BasicUser.Email, dkimSignature(userDomain), "All good."))
+		require.Nil(t, appErr)
+		assert.Equal(t, th.BasicUser.Id, post.UserId)
+		assert.Equal(t, th.BasicChannel.Id, post.ChannelId)
+		assert.Equal(t, "**Weekly report**\n\nAll good.", post.Message)
+		assert.Nil(t, post.GetProp("from_webhook"))
+	})
+
+	t.Run("posts through the webhook for an unauthenticated user", func(t *testing.T) {
+		for name, headers := range map[string]string{
+			"unsigned":          "",
+			"signed by another": dkimSignature("example.org"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				post, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, email(th.BasicUser.Email, headers, "All good."))
+				require.Nil(t, appErr)
+				assert.Equal(t, "true", post.GetProp("from_webhook"))
+				assert.Equal(t, th.BasicUser.Email, post.GetProp("from_email"))
+			})
+		}
+	})
+
+	t.Run("posts through the webhook for an unknown sender", func(t *testing.T) {
+		post, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, email(`"Jane Doe" <jane@example.org>`, "", "Hello"))
+		require.Nil(t, appErr)
+		assert.Equal(t, th.BasicUser.Id, post.UserId)
+		assert.Equal(t, "true", post.GetProp("from_webhook"))
+		assert.Equal(t, "jane@example.org", post.GetProp("from_email"))
+		assert.Equal(t, "Jane Doe", post.GetProp("override_username"))
+	})
+
+	t.Run("uploads the attachments", func(t *testing.T) {
+		body := strings.Join([]string{
+			`Content-Type: multipart/mixed; boundary="boundary"`,
+			"",
+			"--boundary",
+			"Content-Type: text/plain",
+			"",
+			"See attached.",
+			"--boundary",
+			`Content-Type: text/plain; name="report.txt"`,
+			`Content-Disposition: attachment; filename="report.txt"`,
+			"",
+			"Numbers",
+			"--boundary--",
+			"",
+		}, "\r\n")
+		post, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, []byte("From: jane@example.org\r\n"+body))
+		require.Nil(t, appErr)
+
+		assert.Equal(t, "See attached.", post.Message)
+		require.Len(t, post.FileIds, 1)
+		info, appErr := th.App.GetFileInfo(th.Context, post.FileIds[0])
+		require.Nil(t, appErr)
+		assert.Equal(t, "report.txt", info.Name)
+		assert.Equal(t, th.BasicChannel.Id, info.ChannelId)
+	})
+
+	t.Run("rejects a sender that is not allowed", func(t *testing.T) {
+		_, appErr := th.App.PatchChannelEmailAddress(th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"@example.com"}})
+		require.Nil(t, appErr)
+		defer th.App.PatchChannelEmailAddress(th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{}})
+
+		_, appErr = th.App.ReceivePostByEmail(th.Context, address.Address, email("jane@example.org", dkimSignature("example.org"), "Hello"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post_by_email.sender_not_allowed.app_error", appErr.Id)
+	})
+
+	t.Run("rejects an unauthenticated sender when the senders are restricted", func(t *testing.T) {
+		_, appErr := th.App.PatchChannelEmailAddress(th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"@example.org"}})
+		require.Nil(t, appErr)
+		defer th.App.PatchChannelEmailAddress(th.BasicChannel.Id, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{}})
+
+		_, appErr = th.App.ReceivePostByEmail(th.Context, address.Address, email("jane@example.org", "", "Hello"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post_by_email.sender_not_allowed.app_error", appErr.Id)
+
+		post, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, email("jane@example.org", dkimSignature("example.org"), "Hello"))
+		require.Nil(t, appErr)
+		assert.Equal(t, "jane@example.org", post.GetProp("from_email"))
+	})
+
+	t.Run("rejects automatic replies", func(t *testing.T) {
+		_, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, email("jane@example.org", "Precedence: bulk\r\n", "Newsletter"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post_by_email.auto_submitted.app_error", appErr.Id)
+	})
+
+	t.Run("rejects an unknown address", func(t *testing.T) {
+		_, appErr := th.App.ReceivePostByEmail(th.Context, getPostByEmailAddress("channel@example.com", model.NewId()), email("jane@example.org", "", "Hello"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("rejects an unknown sender without webhook", func(t *testing.T) {
+		th.UpdateConfig(func(cfg *model.Config) {
+			*cfg.ServiceSettings.EnableIncomingWebhooks = false
+		})
+		defer th.UpdateConfig(func(cfg *model.Config) {
+			*cfg.ServiceSettings.EnableIncomingWebhooks = true
+		})
+
+		_, appErr := th.App.ReceivePostByEmail(th.Context, address.Address, email("jane@example.org", "", "Hello"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post_by_email.invalid_sender.app_error", appErr.Id)
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/mail"
+)
+
+const (
+	replyByEmailAction = "reply_by_email"
+
+	// replyByEmailSignatureSize keeps the reply address within the 64 characters
+	// allowed for the local part of an email address.
+	replyByEmailSignatureSize = 10
+)
+
+var replyByEmailEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// getReplyByEmailSignature signs the post for the user, so that a reply address
+// only lets the user it was sent to reply to the post.
+func getReplyByEmailSignature(secret []byte, userID, postID string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(replyByEmailAction + ":" + userID + ":" + postID))
+	return replyByEmailEncoding.EncodeToString(mac.Sum(nil)[:replyByEmailSignatureSize])
+}
+
+// getReplyByEmailAddress returns the sub-address of the configured address used
+// to reply to the post, e.g. reply+<post id><signature>@example.com.
+func getReplyByEmailAddress(address, postID, signature string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return ""
+	}
+	return address[:at] + "+" + postID + signature + address[at:]
+}
+
+// parseReplyByEmailAddress returns the post and the signature of a reply address,
+// if the recipient is a sub-address of the configured address.
+func parseReplyByEmailAddress(address, recipient string) (postID, signature string, ok bool) {
+	at := strings.LastIndex(address, "@")
+	recipientAt := strings.LastIndex(recipient, "@")
+	if at < 0 || recipientAt < 0 || !strings.EqualFold(address[at:], recipient[recipientAt:]) {
+		return "", "", false
+	}
+
+	prefix := address[:at] + "+"
+	local := recipient[:recipientAt]
+	if len(local) < len(prefix) || !strings.EqualFold(local[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	// Some servers change the case of the address.
+	token := strings.ToLower(local[len(prefix):])
+	if len(token) != 26+replyByEmailEncoding.EncodedLen(replyByEmailSignatureSize) || !model.IsValidId(token[:26]) {
+		return "", "", false
+	}
+
+	return token[:26], token[26:], true
+}
+
+// getReplyByEmailAddress returns the address the user replies to in order to
+// reply to the post by email, or an empty string when it is disabled.
+func (a *App) getReplyByEmailAddress(userID, postID string) string {
+	if !*a.Config().EmailSettings.EnableReplyByEmail {
+		return ""
+	}
+
+	return getReplyByEmailAddress(*a.Config().EmailSettings.ReplyByEmailAddress, postID, getReplyByEmailSignature(a.PostActionCookieSecret(), userID, postID))
+}
+
+func (a *App) isReplyByEmailRecipient(recipient string) bool {
+	_, _, ok := parseReplyByEmailAddress(*a.Config().EmailSettings.ReplyByEmailAddress, recipient)
+	return ok
+}
+
+// ReceiveReplyByEmail posts the reply received by email to the thread of the post
+// the email notification was sent for, as the user the notification was sent to.
+func (a *App) ReceiveReplyByEmail(c request.CTX, recipient string, data []byte) (*model.Post, *model.AppError) {
+	if !*a.Config().EmailSettings.EnableReplyByEmail {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	postID, signature, ok := parseReplyByEmailAddress(*a.Config().EmailSettings.ReplyByEmailAddress, recipient)
+	if !ok {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.invalid_recipient.app_error", nil, "recipient="+recipient, http.StatusBadRequest)
+	}
+
+	email, err := mail.ParseInboundEmail(data)
+	if err != nil {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.parse.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	if email.AutoSubmitted {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.auto_submitted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	user, appErr := a.GetUserByEmail(email.From.Address)
+	if appErr != nil {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.invalid_sender.app_error", nil, "", http.StatusForbidden).Wrap(appErr)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(getReplyByEmailSignature(a.PostActionCookieSecret(), user.Id, postID))) {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.invalid_sender.app_error", nil, "", http.StatusForbidden)
+	}
+
+	if user.DeleteAt != 0 || user.IsBot {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.invalid_sender.app_error", nil, "", http.StatusForbidden)
+	}
+
+	post, appErr := a.GetSinglePost(c, postID, false)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	channel, appErr := a.GetChannel(c, post.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !a.HasPermissionToChannel(c, user.Id, channel.Id, model.PermissionCreatePost) {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.permission.app_error", nil, "", http.StatusForbidden)
+	}
+
+	reply := &model.Post{
+		UserId:    user.Id,
+		ChannelId: channel.Id,
+		RootId:    post.Id,
+		Message:   mail.StripReplyQuote(email.Text),
+	}
+	if post.RootId != "" {
+		reply.RootId = post.RootId
+	}
+
+	reply.FileIds = a.uploadInboundEmailAttachments(c, channel.Id, user.Id, email.Attachments)
+
+	if reply.Message == "" && len(reply.FileIds) == 0 {
+		return nil, model.NewAppError("ReceiveReplyByEmail", "app.reply_by_email.empty.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return a.CreatePostAsUser(c, reply, "", false)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/mail"
+)
+
+func TestReplyByEmailAddress(t *testing.T) {
+	secret := []byte("secret")
+	postID := model.NewId()
+	userID := model.NewId()
+	signature := getReplyByEmailSignature(secret, userID, postID)
+
+	address := getReplyByEmailAddress("reply@example.com", postID, signature)
+	local, _, _ := strings.Cut(address, "@")
+	assert.LessOrEqual(t, len(local), 64)
+	assert.NotEqual(t, signature, getReplyByEmailSignature(secret, model.NewId(), postID))
+	assert.NotEqual(t, signature, getReplyByEmailSignature([]byte("other"), userID, postID))
+
+	for name, tc := range map[string]struct {
+		recipient string
+		ok        bool
+	}{
+		"reply address":       {recipient: address, ok: true},
+		"upper case":          {recipient: strings.ToUpper(address), ok: true},
+		"configured address":  {recipient: "reply@example.com"},
+		"other domain":        {recipient: strings.Replace(address, "example.com", "example.org", 1)},
+		"other local part":    {recipient: strings.Replace(address, "reply+", "noreply+", 1)},
+		"truncated signature": {recipient: strings.Replace(address, signature, signature[1:], 1)},
+		"invalid post":        {recipient: "reply+" + strings.Repeat("-", 26) + signature + "@example.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			parsedPostID, parsedSignature, ok := parseReplyByEmailAddress("reply@example.com", tc.recipient)
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, postID, parsedPostID)
+				assert.Equal(t, signature, parsedSignature)
+			}
+		})
+	}
+}
+
+func TestReceiveReplyByEmail(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.EmailSettings.EnableReplyByEmail = true
+		*cfg.EmailSettings.ReplyByEmailAddress = "reply@example.com"
+		*cfg.EmailSettings.InboundSMTPListenAddress = "127.0.0.1:0"
+	})
+
+	root, appErr := th.App.CreatePost(th.Context, &model.Post{
+		UserId:    th.BasicUser2.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "Are you coming?",
+	}, th.BasicChannel, false, false)
+	require.Nil(t, appErr)
+
+	recipient := th.App.getReplyByEmailAddress(th.BasicUser.Id, root.Id)
+	require.NotEmpty(t, recipient)
+
+	email := func(from, headers, body string) []byte {
+		return []byte("From: " + from + "\r\nTo: " + recipient + "\r\nSubject: Re: Are you coming?\r\n" + headers + "\r\n" + body)
+	}
+
+	getReplies := func() []*model.Post {
+		thread, appErr := th.App.GetPostThread(root.Id, model.GetPostsOptions{}, th.BasicUser.Id)
+		require.Nil(t, appErr)
+
+		var replies []*model.Post
+		for _, post := range thread.ToSlice() {
+			if post.Id != root.Id {
+				replies = append(replies, post)
+			}
+		}
+		return replies
+	}
+
+	t.Run("posts the reply without the quote", func(t *testing.T) {
+		reply, appErr := th.App.ReceiveReplyByEmail(th.Context, recipient, email(th.BasicUser.Email, "", "Yes!\r\n\r\nOn Mon, Jan 1, 2024 someone wrote:\r\n> Are you coming?\r\n"))
+		require.Nil(t, appErr)
+
+		assert.Equal(t, th.BasicUser.Id, reply.UserId)
+		assert.Equal(t, root.Id, reply.RootId)
+		assert.Equal(t, "Yes!", reply.Message)
+		assert.Len(t, getReplies(), 1)
+	})
+
+	t.Run("replies to the thread of a reply", func(t *testing.T) {
+		replies := getReplies()
+		require.NotEmpty(t, replies)
+
+		reply, appErr := th.App.ReceiveReplyByEmail(th.Context, th.App.getReplyByEmailAddress(th.BasicUser.Id, replies[0].Id), email(th.BasicUser.Email, "", "Great"))
+		require.Nil(t, appErr)
+		assert.Equal(t, root.Id, reply.RootId)
+	})
+
+	t.Run("uploads the attachments", func(t *testing.T) {
+		body := strings.Join([]string{
+			`Content-Type: multipart/mixed; boundary="boundary"`,
+			"",
+			"--boundary",
+			"Content-Type: text/plain",
+			"",
+			"Here is the agenda.",
+			"--boundary",
+			`Content-Type: text/plain; name="agenda.txt"`,
+			`Content-Disposition: attachment; filename="agenda.txt"`,
+			"",
+			"1. Coffee",
+			"--boundary--",
+			"",
+		}, "\r\n")
+		reply, appErr := th.App.ReceiveReplyByEmail(th.Context, recipient, []byte("From: "+th.BasicUser.Email+"\r\n"+body))
+		require.Nil(t, appErr)
+
+		assert.Equal(t, "Here is the agenda.", reply.Message)
+		require.Len(t, reply.FileIds, 1)
+		info, appErr := th.App.GetFileInfo(th.Context, reply.FileIds[0])
+		require.Nil(t, appErr)
+		assert.Equal(t, "agenda.txt", info.Name)
+	})
+
+	t.Run("rejects another sender", func(t *testing.T) {
+		_, appErr := th.App.ReceiveReplyByEmail(th.Context, recipient, email(th.BasicUser2.Email, "", "I am not the recipient"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("rejects an unknown sender", func(t *testing.T) {
+		_, appErr := th.App.ReceiveReplyByEmail(th.Context, recipient, email("someone@example.org", "", "Hello"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("rejects automatic replies", func(t *testing.T) {
+		_, appErr := th.App.ReceiveReplyByEmail(th.Context, recipient, email(th.BasicUser.Email, "Auto-Submitted: auto-replied\r\n", "I am out of office"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.reply_by_email.auto_submitted.app_error", appErr.Id)
+	})
+
+	t.Run("rejects an empty reply", func(t *testing.T) {
+		_, appErr := th.App.ReceiveReplyByEmail(th.Context, recipient, email(th.BasicUser.Email, "", "> Are you coming?"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.reply_by_email.empty.app_error", appErr.Id)
+	})
+
+	t.Run("rejects a user who is not a member of the channel", func(t *testing.T) {
+		private := th.CreatePrivateChannel(th.Context, th.BasicTeam)
+		post := th.CreatePost(private)
+
+		_, appErr := th.App.ReceiveReplyByEmail(th.Context, th.App.getReplyByEmailAddress(th.BasicUser2.Id, post.Id), email(th.BasicUser2.Email, "", "Hello"))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.reply_by_email.permission.app_error", appErr.Id)
+	})
+
+	t.Run("replies to an email notification", func(t *testing.T) {
+		th.UpdateConfig(func(cfg *model.Config) {
+			*cfg.EmailSettings.SendEmailNotifications = true
+		})
+
+		require.NoError(t, th.Server.startInboundEmailServer())
+		defer th.Server.stopInboundEmailServer()
+
+		mail.DeleteMailBox(th.BasicUser.Email)
+		err := th.App.Srv().EmailService.SendMailWithEmbeddedFilesAndCustomReplyTo(th.BasicUser.Email, "Are you coming?", "<p>Are you coming?</p>", recipient, nil, "", "", "", "Notification")
+		require.NoError(t, err)
+
+		var mailbox mail.JSONMessageHeaderInbucket
+		err = mail.RetryInbucket(5, func() error {
+			var err error
+			mailbox, err = mail.GetMailBox(th.BasicUser.Email)
+			return err
+		})
+		if err != nil {
+			t.Skipf("No email was received, maybe due load on the server: %v", err)
+		}
+		require.Len(t, mailbox, 1)
+
+		source, err := mail.GetMessageSourceFromMailbox(th.BasicUser.Email, mailbox[0].ID)
+		require.NoError(t, err)
+		notification, err := netmail.ReadMessage(strings.NewReader(string(source)))
+		require.NoError(t, err)
+		replyTo, err := netmail.ParseAddress(notification.Header.Get("Reply-To"))
+		require.NoError(t, err)
+		assert.Equal(t, recipient, replyTo.Address)
+
+		err = smtp.SendMail(th.Server.inboundEmailServer.ListenAddr().String(), nil, th.BasicUser.Email, []string{replyTo.Address}, email(th.BasicUser.Email, "", "Count me in.\r\n\r\n> Are you coming?\r\n"))
+		require.NoError(t, err)
+
+		var messages []string
+		for _, reply := range getReplies() {
+			messages = append(messages, reply.Message)
+		}
+		assert.Contains(t, messages, "Count me in.")
+	})
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	// Register the hashes used to decrypt the data keys of encrypted assertions.
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+const samlAssertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+
+type samlAssertion struct {
+	Attributes []struct {
+		Name         string   `xml:"Name,attr"`
+		FriendlyName string   `xml:"FriendlyName,attr"`
+		Values       []string `xml:"AttributeValue"`
+	} `xml:"AttributeStatement>Attribute"`
+}
+
+type samlEncryptionMethod struct {
+	Algorithm    string `xml:"Algorithm,attr"`
+	DigestMethod struct {
+		Algorithm string `xml:"Algorithm,attr"`
+	} `xml:"DigestMethod"`
+	MGF struct {
+		Algorithm string `xml:"Algorithm,attr"`
+	} `xml:"MGF"`
+}
+
+type samlEncryptedKey struct {
+	EncryptionMethod samlEncryptionMethod `xml:"EncryptionMethod"`
+	CipherValue      string               `xml:"CipherData>CipherValue"`
+}
+
+type samlEncryptedAssertion struct {
+	EncryptedData struct {
+		EncryptionMethod samlEncryptionMethod `xml:"EncryptionMethod"`
+		EncryptedKey     *samlEncryptedKey    `xml:"KeyInfo>EncryptedKey"`
+		CipherValue      string               `xml:"CipherData>CipherValue"`
+	} `xml:"EncryptedData"`
+	// Some identity providers put the encrypted key next to the encrypted data.
+	EncryptedKey *samlEncryptedKey `xml:"EncryptedKey"`
+}
+
+// samlAssertionAttributes returns the first value of each attribute of the assertion of
+// an encoded SAML response, by name and by friendly name. Encrypted assertions are
+// decrypted with the private key of the service provider.
+//
+// The response is not validated, it must have been by logging in with it. To make sure
+// that the attributes come from the assertion which was, the response must hold exactly
+// one assertion.
+func (a *App) samlAssertionAttributes(encodedXML string) (map[string]string, error) {
+	data, err := base64.StdEncoding.DecodeString(encodedXML)
+	if err != nil {
+		return nil, fmt.Errorf("SAML response is not base64 encoded: %w", err)
+	}
+
+	return parseSamlAssertionAttributes(data, func() (*rsa.PrivateKey, error) {
+		keyFile := *a.Config().SamlSettings.PrivateKeyFile
+		if keyFile == "" {
+			return nil, errors.New("no SAML private key is configured")
+		}
+		keyData, err := a.Srv().platform.GetConfigFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the SAML private key: %w", err)
+		}
+		return parseSamlPrivateKey(keyData)
+	})
+}
+
+func parseSamlAssertionAttributes(data []byte, privateKey func() (*rsa.PrivateKey, error)) (map[string]string, error) {
+	var assertion *samlAssertion
+	var encrypted *samlEncryptedAssertion
+	count := 0
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot parse the SAML response: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != samlAssertionNamespace {
+			continue
+		}
+		switch start.Name.Local {
+		case "Assertion":
+			assertion = &samlAssertion{}
+			err = decoder.DecodeElement(assertion, &start)
+		case "EncryptedAssertion":
+			encrypted = &samlEncryptedAssertion{}
+			err = decoder.DecodeElement(encrypted, &start)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the SAML assertion: %w", err)
+		}
+		count++
+	}
+	if count != 1 {
+		return nil, fmt.Errorf("SAML response holds %d assertions instead of one", count)
+	}
+
+	if encrypted != nil {
+		key, err := privateKey()
+		if err != nil {
+			return nil, err
+		}
+		decrypted, err := decryptSamlAssertion(encrypted, key)
+		if err != nil {
+			return nil, err
+		}
+		return parseSamlAssertionAttributes(decrypted, nil)
+	}
+
+	attributes := make(map[string]string, len(assertion.Attributes))
+	for _, attribute := range assertion.Attributes {
+		if len(attribute.Values) == 0 {
+			continue
+		}
+		value := strings.TrimSpace(attribute.Values[0])
+		if attribute.Name != "" {
+			attributes[attribute.Name] = value
+		}
+		if attribute.FriendlyName != "" {
+			attributes[attribute.FriendlyName] = value
+		}
+	}
+	return attributes, nil
+}
+
+// decryptSamlAssertion decrypts an assertion encrypted as per XML Encryption, with a
+// data key encrypted with the public key of the service provider.
+func decryptSamlAssertion(encrypted *samlEncryptedAssertion, privateKey *rsa.PrivateKey) ([]byte, error) {
+	encryptedKey := encrypted.EncryptedData.EncryptedKey
+	if encryptedKey == nil {
+		encryptedKey = encrypted.EncryptedKey
+	}
+	if encryptedKey == nil {
+		return nil, errors.New("SAML assertion has no encrypted key")
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encryptedKey.CipherValue))
+	if err != nil {
+		return nil, fmt.Errorf("encrypted key of the SAML assertion is not base64 encoded: %w", err)
+	}
+	var dataKey []byte
+	switch method := encryptedKey.EncryptionMethod; method.Algorithm {
+	case "http://www.w3.org/2001/04/xmlenc#rsa-oaep-mgf1p", "http://www.w3.org/2009/xmlenc11#rsa-oaep":
+		hash, err := samlDigestHash(method.DigestMethod.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		mgfHash := crypto.SHA1
+		if method.MGF.Algorithm != "" {
+			if mgfHash, err = samlMGFHash(method.MGF.Algorithm); err != nil {
+				return nil, err
+			}
+		}
+		dataKey, err = privateKey.Decrypt(rand.Reader, wrappedKey, &rsa.OAEPOptions{Hash: hash, MGFHash: mgfHash})
+		if err != nil {
+			return nil, fmt.Errorf("cannot decrypt the key of the SAML assertion: %w", err)
+		}
+	case "http://www.w3.org/2001/04/xmlenc#rsa-1_5":
+		dataKey, err = rsa.DecryptPKCS1v15(rand.Reader, privateKey, wrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decrypt the key of the SAML assertion: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported SAML key encryption algorithm %q", method.Algorithm)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted.EncryptedData.CipherValue))
+	if err != nil {
+		return nil, fmt.Errorf("SAML assertion is not base64 encoded: %w", err)
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key of the SAML assertion: %w", err)
+	}
+
+	switch algorithm := encrypted.EncryptedData.EncryptionMethod.Algorithm; algorithm {
+	case "http://www.w3.org/2001/04/xmlenc#aes128-cbc", "http://www.w3.org/2001/04/xmlenc#aes192-cbc", "http://www.w3.org/2001/04/xmlenc#aes256-cbc":
+		if len(ciphertext) < 2*aes.BlockSize || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, errors.New("encrypted SAML assertion has an invalid length")
+		}
+		plaintext := make([]byte, len(ciphertext)-aes.BlockSize)
+		cipher.NewCBCDecrypter(block, ciphertext[:aes.BlockSize]).CryptBlocks(plaintext, ciphertext[aes.BlockSize:])
+		// The last byte is the length of the padding, whose other bytes are arbitrary.
+		padding := int(plaintext[len(plaintext)-1])
+		if padding < 1 || padding > aes.BlockSize {
+			return nil, errors.New("encrypted SAML assertion has an invalid padding")
+		}
+		return plaintext[:len(plaintext)-padding], nil
+	case "http://www.w3.org/2009/xmlenc11#aes128-gcm", "http://www.w3.org/2009/xmlenc11#aes192-gcm", "http://www.w3.org/2009/xmlenc11#aes256-gcm":
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		if len(ciphertext) < gcm.NonceSize() {
+			return nil, errors.New("encrypted SAML assertion has an invalid length")
+		}
+		plaintext, err := gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decrypt the SAML assertion: %w", err)
+		}
+		return plaintext, nil
+	default:
+		return nil, fmt.Errorf("unsupported SAML assertion encryption algorithm %q", algorithm)
+	}
+}
+
+func samlDigestHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case "", "http://www.w3.org/2000/09/xmldsig#sha1":
+		return crypto.SHA1, nil
+	case "http://www.w3.org/2001/04/xmlenc#sha256":
+		return crypto.SHA256, nil
+	case "http://www.w3.org/2001/04/xmlenc#sha512":
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("unsupported SAML key digest algorithm %q", algorithm)
+}
+
+func samlMGFHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case "http://www.w3.org/2009/xmlenc11#mgf1sha1":
+		return crypto.SHA1, nil
+	case "http://www.w3.org/2009/xmlenc11#mgf1sha256":
+		return crypto.SHA256, nil
+	case "http://www.w3.org/2009/xmlenc11#mgf1sha512":
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("unsupported SAML mask generation function %q", algorithm)
+}
+
+// parseSamlPrivateKey parses the PEM encoded private key of the service provider, either
+// in PKCS #1 or PKCS #8 form.
+func parseSamlPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("SAML private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the SAML private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("SAML private key is not an RSA key")
+	}
+	return key, nil
+}
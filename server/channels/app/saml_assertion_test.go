@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSamlAssertionAttributes(t *testing.T) {
+	const assertion = `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"><saml:AttributeStatement>` +
+		`<saml:Attribute Name="urn:oid:2.5.4.11" FriendlyName="ou"><saml:AttributeValue>Engineering</saml:AttributeValue><saml:AttributeValue>Sales</saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute Name="Department"><saml:AttributeValue> Marketing </saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute Name="Empty"/>` +
+		`</saml:AttributeStatement></saml:Assertion>`
+	response := func(assertions ...string) []byte {
+		body := ""
+		for _, assertion := range assertions {
+			body += assertion
+		}
+		return []byte(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol">` + body + `</samlp:Response>`)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := func() (*rsa.PrivateKey, error) { return key, nil }
+
+	encrypt := func(t *testing.T, plaintext string) string {
+		t.Helper()
+
+		dataKey := make([]byte, 32)
+		_, err := rand.Read(dataKey)
+		require.NoError(t, err)
+		block, err := aes.NewCipher(dataKey)
+		require.NoError(t, err)
+		gcm, err := cipher.NewGCM(block)
+		require.NoError(t, err)
+		nonce := make([]byte, gcm.NonceSize())
+		_, err = rand.Read(nonce)
+		require.NoError(t, err)
+		ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+		wrappedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, &key.PublicKey, dataKey, nil)
+		require.NoError(t, err)
+
+		return fmt.Sprintf(`<saml:EncryptedAssertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"><xenc:EncryptedData xmlns:xenc="http://www.w3.org/2001/04/xmlenc#">`+
+			`<xenc:EncryptionMethod Algorithm="http://www.w3.org/2009/xmlenc11#aes256-gcm"/>`+
+			`<ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><xenc:EncryptedKey><xenc:EncryptionMethod Algorithm="http://www.w3.org/2001/04/xmlenc#rsa-oaep-mgf1p"/>`+
+			`<xenc:CipherData><xenc:CipherValue>%s</xenc:CipherValue></xenc:CipherData></xenc:EncryptedKey></ds:KeyInfo>`+
+			`<xenc:CipherData><xenc:CipherValue>%s</xenc:CipherValue></xenc:CipherData></xenc:EncryptedData></saml:EncryptedAssertion>`,
+			base64.StdEncoding.EncodeToString(wrappedKey), base64.StdEncoding.EncodeToString(ciphertext))
+	}
+
+	expected := map[string]string{"urn:oid:2.5.4.11": "Engineering", "ou": "Engineering", "Department": "Marketing"}
+
+	t.Run("plain assertion", func(t *testing.T) {
+		attributes, err := parseSamlAssertionAttributes(response(assertion), privateKey)
+		require.NoError(t, err)
+		assert.Equal(t, expected, attributes)
+	})
+
+	t.Run("encrypted assertion", func(t *testing.T) {
+		attributes, err := parseSamlAssertionAttributes(response(encrypt(t, assertion)), privateKey)
+		require.NoError(t, err)
+		assert.Equal(t, expected, attributes)
+	})
+
+	t.Run("encrypted assertion with another key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = parseSamlAssertionAttributes(response(encrypt(t, assertion)), func() (*rsa.PrivateKey, error) { return otherKey, nil })
+		require.Error(t, err)
+	})
+
+	t.Run("several assertions", func(t *testing.T) {
+		// Only one assertion can have been validated, so it is unknown which one to trust.
+		_, err := parseSamlAssertionAttributes(response(assertion, assertion), privateKey)
+		require.Error(t, err)
+
+		_, err = parseSamlAssertionAttributes(response(assertion, encrypt(t, assertion)), privateKey)
+		require.Error(t, err)
+	})
+
+	t.Run("no assertion", func(t *testing.T) {
+		_, err := parseSamlAssertionAttributes(response(), privateKey)
+		require.Error(t, err)
+	})
+}
+
+func TestParseSamlPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	parsed, err := parseSamlPrivateKey(pkcs1)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	parsed, err = parseSamlPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = parseSamlPrivateKey([]byte("not a key"))
+	require.Error(t, err)
+}
@@ -0,0 +1,633 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// GetScimUser returns the SCIM representation of a user. Bots and remote users are not
+// provisioned through SCIM and are reported as missing.
+func (a *App) GetScimUser(userID string) (*model.ScimUser, *model.AppError) {
+	user, appErr := a.getScimUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return user.ToScimUser(a.GetSiteURL()), nil
+}
+
+func (a *App) getScimUser(userID string) (*model.User, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if user.IsBot || user.IsRemote() {
+		return nil, model.NewAppError("getScimUser", MissingAccountError, nil, "user_id="+userID, http.StatusNotFound)
+	}
+
+	return user, nil
+}
+
+// SearchScimUsers returns the page of users matching a SCIM filter, starting at the
+// 1-based startIndex. An empty filter matches every user. The filter is evaluated by the
+// store, which rejects the attributes users can't be filtered by.
+func (a *App) SearchScimUsers(filter string, startIndex, count int) (*model.ScimListResponse, *model.AppError) {
+	var parsed *model.ScimFilter
+	if filter != "" {
+		var appErr *model.AppError
+		if parsed, appErr = model.ParseScimFilter(filter); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	siteURL := a.GetSiteURL()
+	resources := []any{}
+
+	// Identity providers look users up by userName before provisioning them, so that
+	// query is answered through the index of the usernames. Users renamed since they were
+	// provisioned are only found by the store.
+	if userName, ok := scimUserNameFilter(parsed); ok {
+		if username := model.ScimUsernameToUsername(userName); username != "" {
+			user, appErr := a.GetUserByUsername(username)
+			if appErr != nil && appErr.StatusCode != http.StatusNotFound {
+				return nil, appErr
+			}
+			if user != nil && !user.IsBot && !user.IsRemote() {
+				if su := user.ToScimUser(siteURL); parsed.Matches(su) {
+					if startIndex == 1 && count > 0 {
+						resources = append(resources, su)
+					}
+					return model.NewScimListResponse(resources, 1, startIndex), nil
+				}
+			}
+		}
+	}
+
+	users, total, err := a.Srv().Store().User().SearchScim(parsed, startIndex-1, count)
+	if err != nil {
+		var invErr *store.ErrInvalidInput
+		if errors.As(err, &invErr) {
+			return nil, model.NewScimFilterError(filter)
+		}
+		return nil, model.NewAppError("SearchScimUsers", "app.user.get_profiles.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	for _, user := range users {
+		resources = append(resources, user.ToScimUser(siteURL))
+	}
+
+	return model.NewScimListResponse(resources, int(total), startIndex), nil
+}
+
+func scimUserNameFilter(filter *model.ScimFilter) (string, bool) {
+	if filter == nil || filter.Operator != model.ScimFilterOperatorEqual || filter.Attribute != "username" {
+		return "", false
+	}
+	userName, ok := filter.Value.(string)
+	return userName, ok
+}
+
+// CreateScimUser provisions a user from its SCIM representation. Users are bound to the
+// configured authentication service when there is one, and get the given or a random
+// password otherwise.
+func (a *App) CreateScimUser(c request.CTX, su *model.ScimUser) (*model.ScimUser, *model.AppError) {
+	user := &model.User{EmailVerified: true}
+	if appErr := su.ApplyTo(user); appErr != nil {
+		return nil, appErr
+	}
+
+	if authService := *a.Config().ScimSettings.AuthService; authService != "" {
+		authData := su.ExternalId
+		if authData == "" {
+			authData = su.UserName
+		}
+		user.AuthService = authService
+		user.AuthData = model.NewPointer(authData)
+	} else if su.Password != "" {
+		user.Password = su.Password
+	} else {
+		password, err := generatePassword(*a.Config().PasswordSettings.MinimumLength)
+		if err != nil {
+			return nil, model.NewAppError("CreateScimUser", "app.scim.user.password.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		user.Password = password
+	}
+
+	ruser, appErr := a.CreateUser(c, user)
+	if appErr != nil {
+		return nil, scimConflictError(appErr)
+	}
+
+	if su.Active != nil && !*su.Active {
+		if ruser, appErr = a.UpdateActive(c, ruser, false); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return ruser.ToScimUser(a.GetSiteURL()), nil
+}
+
+// ReplaceScimUser replaces the attributes of a user with its SCIM representation.
+func (a *App) ReplaceScimUser(c request.CTX, userID string, su *model.ScimUser) (*model.ScimUser, *model.AppError) {
+	user, appErr := a.getScimUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return a.updateScimUser(c, user, su)
+}
+
+// PatchScimUser applies the operations of a SCIM PATCH request to a user.
+func (a *App) PatchScimUser(c request.CTX, userID string, operations []*model.ScimPatchOperation) (*model.ScimUser, *model.AppError) {
+	user, appErr := a.getScimUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	su := user.ToScimUser(a.GetSiteURL())
+	if appErr := su.Patch(operations); appErr != nil {
+		return nil, appErr
+	}
+
+	return a.updateScimUser(c, user, su)
+}
+
+func (a *App) updateScimUser(c request.CTX, user *model.User, su *model.ScimUser) (*model.ScimUser, *model.AppError) {
+	oldUsername := user.Username
+	oldEmail := user.Email
+	if appErr := su.ApplyTo(user); appErr != nil {
+		return nil, appErr
+	}
+
+	if user.Username != oldUsername {
+		if appErr := a.isUniqueToGroupNames(user.Username); appErr != nil {
+			return nil, scimConflictError(appErr)
+		}
+	}
+
+	// The identity provider is trusted with the email address, which is why the update
+	// bypasses the verification UpdateUser may require.
+	userUpdate, err := a.Srv().Store().User().Update(c, user, true)
+	if err != nil {
+		var appErr *model.AppError
+		var invErr *store.ErrInvalidInput
+		var conErr *store.ErrConflict
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &invErr):
+			return nil, model.NewAppError("updateScimUser", "app.user.update.find.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		case errors.As(err, &conErr):
+			if conErr.Resource == "Username" {
+				return nil, model.NewAppError("updateScimUser", "app.user.save.username_exists.app_error", nil, "", http.StatusConflict).Wrap(err)
+			}
+			return nil, model.NewAppError("updateScimUser", "app.user.save.email_exists.app_error", nil, "", http.StatusConflict).Wrap(err)
+		default:
+			return nil, model.NewAppError("updateScimUser", "app.user.update.finding.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	if user.Email != oldEmail {
+		if _, err := a.Srv().Store().User().VerifyEmail(user.Id, user.Email); err != nil {
+			return nil, model.NewAppError("updateScimUser", "app.user.verify_email.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	a.InvalidateCacheForUser(user.Id)
+	a.sendUpdatedUserEvent(*userUpdate.New)
+
+	// The update sanitized the user, while the password history needs its current hash.
+	user, appErr := a.GetUser(user.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if su.Password != "" && user.AuthService == "" {
+		if appErr := a.UpdatePassword(c, user, su.Password); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	if su.Active != nil && *su.Active != (user.DeleteAt == 0) {
+		if _, appErr := a.UpdateActive(c, user, *su.Active); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return a.GetScimUser(user.Id)
+}
+
+// DeleteScimUser deprovisions a user. The user is deactivated rather than deleted, so
+// their content is kept and they remain visible to the identity provider as inactive.
+func (a *App) DeleteScimUser(c request.CTX, userID string) *model.AppError {
+	user, appErr := a.getScimUser(userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if user.DeleteAt != 0 {
+		return nil
+	}
+
+	_, appErr = a.UpdateActive(c, user, false)
+	return appErr
+}
+
+// scimConflictError reports the uniqueness errors of user and group creation with the
+// status code the SCIM protocol expects.
+func scimConflictError(appErr *model.AppError) *model.AppError {
+	switch appErr.Id {
+	case "app.user.save.username_exists.app_error", "app.user.save.email_exists.app_error", "app.group.username_conflict":
+		appErr.StatusCode = http.StatusConflict
+	}
+	return appErr
+}
+
+// GetScimGroup returns the SCIM representation of a group provisioned through SCIM.
+func (a *App) GetScimGroup(groupID string, excludeMembers bool) (*model.ScimGroup, *model.AppError) {
+	group, appErr := a.getScimGroup(groupID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var members []*model.User
+	if !excludeMembers {
+		if members, appErr = a.GetGroupMemberUsers(group.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return group.ToScimGroup(members, a.GetSiteURL()), nil
+}
+
+func (a *App) getScimGroup(groupID string) (*model.Group, *model.AppError) {
+	group, appErr := a.GetGroup(groupID, nil, nil)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// Groups from other sources are managed by their own synchronization.
+	if group.Source != model.GroupSourceScim || group.DeleteAt != 0 {
+		return nil, model.NewAppError("getScimGroup", "app.group.no_rows", nil, "group_id="+groupID, http.StatusNotFound)
+	}
+
+	return group, nil
+}
+
+// SearchScimGroups returns the page of groups provisioned through SCIM matching a filter,
+// starting at the 1-based startIndex. An empty filter matches every group.
+func (a *App) SearchScimGroups(filter string, startIndex, count int, excludeMembers bool) (*model.ScimListResponse, *model.AppError) {
+	var parsed *model.ScimFilter
+	if filter != "" {
+		var appErr *model.AppError
+		if parsed, appErr = model.ParseScimFilter(filter); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	groups, appErr := a.GetGroupsBySource(model.GroupSourceScim)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	siteURL := a.GetSiteURL()
+	resources := []any{}
+	total := 0
+	for _, group := range groups {
+		// The members are needed to evaluate filters on them.
+		var members []*model.User
+		if !excludeMembers || parsed != nil {
+			if members, appErr = a.GetGroupMemberUsers(group.Id); appErr != nil {
+				return nil, appErr
+			}
+		}
+
+		sg := group.ToScimGroup(members, siteURL)
+		if parsed != nil && !parsed.Matches(sg) {
+			continue
+		}
+		total++
+		if total < startIndex || len(resources) >= count {
+			continue
+		}
+		if excludeMembers {
+			sg.Members = nil
+		}
+		resources = append(resources, sg)
+	}
+
+	return model.NewScimListResponse(resources, total, startIndex), nil
+}
+
+// CreateScimGroup provisions a group from its SCIM representation. A deleted group with
+// the same external id is restored rather than duplicated.
+func (a *App) CreateScimGroup(c request.CTX, sg *model.ScimGroup) (*model.ScimGroup, *model.AppError) {
+	if sg.ExternalId != "" {
+		existing, appErr := a.GetGroupByRemoteID(sg.ExternalId, model.GroupSourceScim)
+		if appErr != nil && appErr.StatusCode != http.StatusNotFound {
+			return nil, appErr
+		}
+		if existing != nil {
+			if existing.DeleteAt == 0 {
+				return nil, model.NewAppError("CreateScimGroup", "app.scim.group.external_id_exists.app_error", nil, "", http.StatusConflict)
+			}
+			if _, appErr := a.RestoreGroup(existing.Id); appErr != nil {
+				return nil, appErr
+			}
+			return a.ReplaceScimGroup(c, existing.Id, sg)
+		}
+	}
+
+	group := &model.Group{}
+	if appErr := sg.ApplyTo(group); appErr != nil {
+		return nil, appErr
+	}
+
+	memberIDs, appErr := a.checkScimGroupMemberIDs(sg.MemberIds())
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	group, appErr = a.CreateGroup(group)
+	if appErr != nil {
+		return nil, scimConflictError(appErr)
+	}
+
+	if len(memberIDs) > 0 {
+		if _, appErr := a.UpsertGroupMembers(group.Id, memberIDs); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return a.GetScimGroup(group.Id, false)
+}
+
+// ReplaceScimGroup replaces the attributes and members of a group with its SCIM
+// representation.
+func (a *App) ReplaceScimGroup(c request.CTX, groupID string, sg *model.ScimGroup) (*model.ScimGroup, *model.AppError) {
+	group, appErr := a.getScimGroup(groupID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return a.updateScimGroup(c, group, sg)
+}
+
+// PatchScimGroup applies the operations of a SCIM PATCH request to a group. The members
+// added or removed are applied as such rather than by replacing the members, so that
+// concurrent requests don't lose each other's changes.
+func (a *App) PatchScimGroup(c request.CTX, groupID string, operations []*model.ScimPatchOperation) (*model.ScimGroup, *model.AppError) {
+	group, appErr := a.getScimGroup(groupID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	attributeOps, memberOps, appErr := model.SplitScimGroupPatch(operations)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if len(attributeOps) > 0 {
+		sg := group.ToScimGroup(nil, a.GetSiteURL())
+		if appErr = sg.Patch(attributeOps); appErr != nil {
+			return nil, appErr
+		}
+		if appErr = a.updateScimGroupAttributes(group, sg); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	changed := false
+	for _, memberOp := range memberOps {
+		var opChanged bool
+		switch memberOp.Op {
+		case model.ScimPatchOpAdd:
+			opChanged, appErr = a.addScimGroupMembers(group.Id, memberOp.Ids)
+		case model.ScimPatchOpRemove:
+			opChanged, appErr = a.removeScimGroupMembers(group.Id, memberOp)
+		case model.ScimPatchOpReplace:
+			opChanged, appErr = a.setScimGroupMembers(group.Id, memberOp.Ids)
+		}
+		if appErr != nil {
+			return nil, appErr
+		}
+		changed = changed || opChanged
+	}
+
+	if changed {
+		if appErr := a.syncScimGroupMemberships(c, group.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return a.GetScimGroup(group.Id, false)
+}
+
+func (a *App) updateScimGroup(c request.CTX, group *model.Group, sg *model.ScimGroup) (*model.ScimGroup, *model.AppError) {
+	if appErr := a.updateScimGroupAttributes(group, sg); appErr != nil {
+		return nil, appErr
+	}
+
+	changed, appErr := a.setScimGroupMembers(group.Id, sg.MemberIds())
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if changed {
+		if appErr := a.syncScimGroupMemberships(c, group.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return a.GetScimGroup(group.Id, false)
+}
+
+// updateScimGroupAttributes updates the attributes of a group other than its members.
+func (a *App) updateScimGroupAttributes(group *model.Group, sg *model.ScimGroup) *model.AppError {
+	oldExternalID := group.GetRemoteId()
+	if appErr := sg.ApplyTo(group); appErr != nil {
+		return appErr
+	}
+
+	if sg.ExternalId != "" && sg.ExternalId != oldExternalID {
+		existing, appErr := a.GetGroupByRemoteID(sg.ExternalId, model.GroupSourceScim)
+		if appErr != nil && appErr.StatusCode != http.StatusNotFound {
+			return appErr
+		}
+		if existing != nil {
+			return model.NewAppError("updateScimGroupAttributes", "app.scim.group.external_id_exists.app_error", nil, "", http.StatusConflict)
+		}
+	}
+
+	if _, appErr := a.UpdateGroup(group); appErr != nil {
+		return scimConflictError(appErr)
+	}
+
+	return nil
+}
+
+// addScimGroupMembers adds users to a group, returning whether any was added.
+func (a *App) addScimGroupMembers(groupID string, userIDs []string) (bool, *model.AppError) {
+	if len(userIDs) == 0 {
+		return false, nil
+	}
+	userIDs, appErr := a.checkScimGroupMemberIDs(userIDs)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	if _, appErr := a.UpsertGroupMembers(groupID, userIDs); appErr != nil {
+		return false, appErr
+	}
+
+	return true, nil
+}
+
+// removeScimGroupMembers removes the members a removal selects from a group, returning
+// whether any was removed.
+func (a *App) removeScimGroupMembers(groupID string, memberOp *model.ScimGroupMemberOperation) (bool, *model.AppError) {
+	members, appErr := a.GetGroupMemberUsers(groupID)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	isMember := make(map[string]bool, len(members))
+	memberIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		isMember[member.Id] = true
+		memberIDs = append(memberIDs, member.Id)
+	}
+
+	var removedIDs []string
+	for _, userID := range memberOp.SelectMembers(memberIDs) {
+		if isMember[userID] {
+			removedIDs = append(removedIDs, userID)
+		}
+	}
+	if len(removedIDs) == 0 {
+		return false, nil
+	}
+
+	if _, appErr := a.DeleteGroupMembers(groupID, removedIDs); appErr != nil {
+		return false, appErr
+	}
+
+	return true, nil
+}
+
+// setScimGroupMembers replaces the members of a group, returning whether they changed.
+func (a *App) setScimGroupMembers(groupID string, userIDs []string) (bool, *model.AppError) {
+	userIDs, appErr := a.checkScimGroupMemberIDs(userIDs)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	oldMembers, appErr := a.GetGroupMemberUsers(groupID)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	isMember := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		isMember[userID] = true
+	}
+	var removedIDs []string
+	for _, member := range oldMembers {
+		if isMember[member.Id] {
+			delete(isMember, member.Id)
+			continue
+		}
+		removedIDs = append(removedIDs, member.Id)
+	}
+	addedIDs := make([]string, 0, len(isMember))
+	for _, userID := range userIDs {
+		if isMember[userID] {
+			addedIDs = append(addedIDs, userID)
+		}
+	}
+
+	if len(addedIDs) > 0 {
+		if _, appErr := a.UpsertGroupMembers(groupID, addedIDs); appErr != nil {
+			return false, appErr
+		}
+	}
+	if len(removedIDs) > 0 {
+		if _, appErr := a.DeleteGroupMembers(groupID, removedIDs); appErr != nil {
+			return false, appErr
+		}
+	}
+
+	return len(addedIDs) > 0 || len(removedIDs) > 0, nil
+}
+
+// DeleteScimGroup deletes a group provisioned through SCIM and removes its members from
+// the group-constrained teams and channels it granted access to.
+func (a *App) DeleteScimGroup(c request.CTX, groupID string) *model.AppError {
+	if _, appErr := a.getScimGroup(groupID); appErr != nil {
+		return appErr
+	}
+
+	if _, appErr := a.DeleteGroup(groupID); appErr != nil {
+		return appErr
+	}
+
+	return a.syncScimGroupMemberships(c, groupID)
+}
+
+// checkScimGroupMemberIDs checks that the members of a group are all existing users. It
+// returns their IDs without the duplicates identity providers may send.
+func (a *App) checkScimGroupMemberIDs(userIDs []string) ([]string, *model.AppError) {
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	for _, userID := range userIDs {
+		if !model.IsValidId(userID) {
+			return nil, model.NewAppError("checkScimGroupMemberIDs", "app.scim.group.member.app_error", nil, "user_id="+userID, http.StatusBadRequest)
+		}
+	}
+
+	userIDs = model.RemoveDuplicateStringsNonSort(userIDs)
+	users, appErr := a.GetUsers(userIDs)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if len(users) != len(userIDs) {
+		return nil, model.NewAppError("checkScimGroupMemberIDs", "app.scim.group.member.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return userIDs, nil
+}
+
+// syncScimGroupMemberships applies a change in the members of a group to the teams and
+// channels it is linked to, in the background.
+func (a *App) syncScimGroupMemberships(c request.CTX, groupID string) *model.AppError {
+	var syncables []*model.GroupSyncable
+	for _, syncableType := range []model.GroupSyncableType{model.GroupSyncableTypeTeam, model.GroupSyncableTypeChannel} {
+		groupSyncables, appErr := a.GetGroupSyncables(groupID, syncableType)
+		if appErr != nil {
+			return appErr
+		}
+		syncables = append(syncables, groupSyncables...)
+	}
+
+	if len(syncables) == 0 {
+		return nil
+	}
+
+	a.Srv().Go(func() {
+		for _, syncable := range syncables {
+			a.SyncRolesAndMembership(c, syncable.SyncableId, syncable.Type, false)
+		}
+	})
+
+	return nil
+}
@@ -39,16 +39,21 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/app/users"
 	"github.com/mattermost/mattermost/server/v8/channels/audit"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/access_control_sync"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/active_users"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/cleanup_desktop_tokens"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/cleanup_websocket_events"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/cold_storage"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_dms_preferences_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_empty_drafts_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_orphan_drafts_migration"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/email_digest"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/expirynotify"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_delete"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_process"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_users_to_csv"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/extract_content"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_encryption_key_rotation"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/hosted_purchase_screening"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_delete"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_process"
@@ -105,6 +110,8 @@ type Server struct {
 
 	localModeServer *http.Server
 
+	inboundEmailServer *mail.InboundServer
+
 	didFinishListen chan struct{}
 
 	EmailService email.ServiceInterface
@@ -121,6 +128,7 @@ type Server struct {
 
 	htmlTemplateWatcher     *templates.Container
 	seenPendingPostIdsCache cache.Cache
+	geoIP                   geoIPLookup
 	openGraphDataCache      cache.Cache
 	clusterLeaderListenerId string
 	loggerLicenseListenerId string
@@ -137,7 +145,8 @@ type Server struct {
 
 	phase2PermissionsMigrationComplete bool
 
-	Audit *audit.Audit
+	Audit                *audit.Audit
+	auditRecordPersister *auditRecordPersister
 
 	joinCluster  bool
 	skipPostInit bool
@@ -428,6 +437,9 @@ func NewServer(options ...Option) (*Server, error) {
 
 	if s.Audit == nil {
 		s.Audit = &audit.Audit{}
+		if metrics := s.GetMetrics(); metrics != nil {
+			s.Audit.MetricsCollector = metrics.GetLoggerMetricsCollector()
+		}
 		s.Audit.Init(audit.DefMaxQueueSize)
 		if err = s.configureAudit(s.Audit, allowAdvancedLogging); err != nil {
 			mlog.Error("Error configuring audit", mlog.Err(err))
@@ -698,6 +710,7 @@ func (s *Server) Shutdown() {
 
 	s.StopHTTPServer()
 	s.stopLocalModeServer()
+	s.stopInboundEmailServer()
 	// Push notification hub needs to be shutdown after HTTP server
 	// to prevent stray requests from generating a push notification after it's shut down.
 	s.StopPushNotificationsHubWorkers()
@@ -706,6 +719,9 @@ func (s *Server) Shutdown() {
 	s.platform.StopSearchEngine()
 
 	s.Audit.Shutdown()
+	if s.auditRecordPersister != nil {
+		s.auditRecordPersister.stop()
+	}
 
 	s.platform.StopFeatureFlagUpdateJob()
 
@@ -871,8 +887,11 @@ func (s *Server) Start() error {
 
 	err := s.FileBackend().TestConnection()
 	if err != nil {
-		if _, ok := err.(*filestore.S3FileBackendNoBucketError); ok {
-			err = s.FileBackend().(*filestore.S3FileBackend).MakeBucket()
+		switch err.(type) {
+		case *filestore.S3FileBackendNoBucketError, *filestore.AzureFileBackendNoContainerError, *filestore.GCSFileBackendNoBucketError:
+			if bucketMaker, ok := s.FileBackend().(interface{ MakeBucket() error }); ok {
+				err = bucketMaker.MakeBucket()
+			}
 		}
 		if err != nil {
 			mlog.Error("Problem with file storage settings", mlog.Err(err))
@@ -1083,6 +1102,12 @@ func (s *Server) Start() error {
 		}
 	}
 
+	if *s.platform.Config().EmailSettings.EnableReplyByEmail || *s.platform.Config().EmailSettings.EnablePostByEmail {
+		if err := s.startInboundEmailServer(); err != nil {
+			mlog.Error("Error starting the inbound email server", mlog.Err(err))
+		}
+	}
+
 	if err := s.startInterClusterServices(s.License()); err != nil {
 		mlog.Error("Error starting inter-cluster services", mlog.Err(err))
 	}
@@ -1123,6 +1148,38 @@ func (s *Server) stopLocalModeServer() {
 	}
 }
 
+func (s *Server) startInboundEmailServer() error {
+	appInstance := New(ServerConnector(s.Channels()))
+
+	s.inboundEmailServer = &mail.InboundServer{
+		Addr:            *s.platform.Config().EmailSettings.InboundSMTPListenAddress,
+		Hostname:        utils.GetHostnameFromSiteURL(*s.platform.Config().ServiceSettings.SiteURL),
+		MaxMessageSize:  *s.platform.Config().EmailSettings.InboundEmailMaxMessageSize,
+		AcceptRecipient: appInstance.isInboundEmailRecipient,
+		Handler: func(from string, to string, data []byte) error {
+			if _, appErr := appInstance.ReceiveInboundEmail(request.EmptyContext(s.Log()), to, data); appErr != nil {
+				return appErr
+			}
+			return nil
+		},
+		Logger: s.Log(),
+	}
+
+	if err := s.inboundEmailServer.Start(); err != nil {
+		s.inboundEmailServer = nil
+		return err
+	}
+
+	mlog.Info("Inbound email server is listening", mlog.String("address", s.inboundEmailServer.ListenAddr().String()))
+	return nil
+}
+
+func (s *Server) stopInboundEmailServer() {
+	if s.inboundEmailServer != nil {
+		s.inboundEmailServer.Close()
+	}
+}
+
 func (a *App) OriginChecker() func(*http.Request) bool {
 	if allowed := *a.Config().ServiceSettings.AllowCorsFrom; allowed != "" {
 		if allowed != "*" {
@@ -1270,6 +1327,10 @@ func doSessionCleanup(s *Server) {
 	if err != nil {
 		mlog.Warn("Error while cleaning up sessions", mlog.Err(err))
 	}
+
+	if err := s.Store().MfaTrustedDevice().Cleanup(model.GetMillis()); err != nil {
+		mlog.Warn("Error while cleaning up MFA trusted devices", mlog.Err(err))
+	}
 }
 
 func doJobsCleanup(s *Server) {
@@ -1488,6 +1549,16 @@ func (s *Server) initJobs() {
 	if jobsLdapSyncInterface != nil {
 		builder := jobsLdapSyncInterface(New(ServerConnector(s.Channels())))
 		s.Jobs.RegisterJobType(model.JobTypeLdapSync, builder.MakeWorker(), builder.MakeScheduler())
+
+		// The custom profile attributes mapped to LDAP attributes follow the users
+		// updated by the sync.
+		s.Jobs.RegisterSuccessHook(model.JobTypeLdapSync, func(job *model.Job) {
+			s.Go(func() {
+				if appErr := New(ServerConnector(s.Channels())).SyncLdapCustomProfileAttributes(request.EmptyContext(s.Log())); appErr != nil {
+					s.Log().Warn("Failed to synchronize the custom profile attributes from LDAP", mlog.String("job_id", job.Id), mlog.Err(appErr))
+				}
+			})
+		})
 	}
 
 	s.Jobs.RegisterJobType(
@@ -1553,6 +1624,18 @@ func (s *Server) initJobs() {
 		export_delete.MakeScheduler(s.Jobs),
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileEncryptionKeyRotation,
+		file_encryption_key_rotation.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		nil,
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeColdStorage,
+		cold_storage.MakeWorker(s.Jobs, New(ServerConnector(s.Channels())), s.Store()),
+		cold_storage.MakeScheduler(s.Jobs),
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeExportProcess,
 		export_process.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
@@ -1625,6 +1708,24 @@ func (s *Server) initJobs() {
 		cleanup_desktop_tokens.MakeScheduler(s.Jobs),
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeCleanupWebSocketEvents,
+		cleanup_websocket_events.MakeWorker(s.Jobs),
+		cleanup_websocket_events.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeEmailDigest,
+		email_digest.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		email_digest.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeAccessControlSync,
+		access_control_sync.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		access_control_sync.MakeScheduler(s.Jobs),
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeRefreshPostStats,
 		refresh_post_stats.MakeWorker(s.Jobs, *s.platform.Config().SqlSettings.DriverName),
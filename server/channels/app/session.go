@@ -5,6 +5,7 @@ package app
 
 import (
 	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"math"
 	"net/http"
@@ -190,6 +191,11 @@ func (a *App) GetLRUSessions(c request.CTX, userID string, limit uint64, offset
 }
 
 func (a *App) RevokeAllSessions(c request.CTX, userID string) *model.AppError {
+	// Revoking all the sessions of the user revokes the trust of all their devices.
+	if err := a.Srv().Store().MfaTrustedDevice().PermanentDeleteByUser(userID); err != nil {
+		return model.NewAppError("RevokeAllSessions", "app.mfa_trusted_device.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
 	if err := a.ch.srv.platform.RevokeAllSessions(c, userID); err != nil {
 		switch {
 		case errors.Is(err, platform.GetSessionError):
@@ -326,6 +332,11 @@ func (a *App) ExtendSessionExpiryIfNeeded(rctx request.CTX, session *model.Sessi
 		return false
 	}
 
+	// The sessions of user access tokens last as long as their token.
+	if session.IsUserAccessToken() {
+		return false
+	}
+
 	sessionLength := a.GetSessionLengthInMillis(session)
 
 	// Only extend the expiry if the lessor of 1% or 1 day has elapsed within the
@@ -408,6 +419,14 @@ func (a *App) CreateUserAccessToken(rctx request.CTX, token *model.UserAccessTok
 		return nil, model.NewAppError("CreateUserAccessToken", "app.user_access_token.disabled", nil, "", http.StatusNotImplemented)
 	}
 
+	if token.Scope != nil && token.Scope.IsEmpty() {
+		token.Scope = nil
+	}
+
+	if token.ExpiresAt != 0 && token.IsExpired() {
+		return nil, model.NewAppError("CreateUserAccessToken", "app.user_access_token.expires_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	token.Token = model.NewId()
 
 	token, nErr = a.Srv().Store().UserAccessToken().Save(token)
@@ -441,6 +460,10 @@ func (a *App) createSessionForUserAccessToken(c request.CTX, tokenString string)
 		return nil, model.NewAppError("createSessionForUserAccessToken", "app.user_access_token.invalid_or_missing", nil, "inactive_token", http.StatusUnauthorized)
 	}
 
+	if token.IsExpired() {
+		return nil, model.NewAppError("createSessionForUserAccessToken", "app.user_access_token.invalid_or_missing", nil, "expired_token", http.StatusUnauthorized)
+	}
+
 	user, nErr := a.Srv().Store().User().Get(c.Context(), token.UserId)
 	if nErr != nil {
 		var nfErr *store.ErrNotFound
@@ -481,7 +504,17 @@ func (a *App) createSessionForUserAccessToken(c request.CTX, tokenString string)
 	} else {
 		session.AddProp(model.SessionPropIsGuest, "false")
 	}
+	if token.Scope != nil {
+		scope, err := json.Marshal(token.Scope)
+		if err != nil {
+			return nil, model.NewAppError("createSessionForUserAccessToken", "app.user_access_token.invalid_or_missing", nil, "invalid_scope", http.StatusUnauthorized).Wrap(err)
+		}
+		session.AddProp(model.SessionPropUserAccessTokenScope, string(scope))
+	}
 	a.ch.srv.platform.SetSessionExpireInHours(session, model.SessionUserAccessTokenExpiryHours)
+	if token.ExpiresAt != 0 {
+		session.ExpiresAt = token.ExpiresAt
+	}
 
 	session, nErr = a.Srv().Store().Session().Save(c, session)
 	if nErr != nil {
@@ -284,6 +284,23 @@ func TestApp_ExtendExpiryIfNeeded(t *testing.T) {
 		require.False(t, session.IsExpired())
 	})
 
+	t.Run("user access token session should not be extended", func(t *testing.T) {
+		session := &model.Session{
+			UserId: model.NewId(),
+		}
+		session.AddProp(model.SessionPropType, model.SessionTypeUserAccessToken)
+		session, err := th.App.CreateSession(th.Context, session)
+		require.Nil(t, err)
+
+		expires := model.GetMillis() + hourMillis
+		session.ExpiresAt = expires
+
+		ok := th.App.ExtendSessionExpiryIfNeeded(th.Context, session)
+
+		require.False(t, ok)
+		require.Equal(t, expires, session.ExpiresAt)
+	})
+
 	var tests = []struct {
 		enabled bool
 		name    string
@@ -490,3 +507,69 @@ func TestSetExtraSessionProps(t *testing.T) {
 		assert.Equal(t, "true", storeSession.Props["testProp"])
 	})
 }
+
+func TestScopedUserAccessToken(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableUserAccessTokens = true })
+
+	t.Run("token expiring in the past cannot be created", func(t *testing.T) {
+		_, appErr := th.App.CreateUserAccessToken(th.Context, &model.UserAccessToken{
+			UserId:      th.BasicUser.Id,
+			Description: "expired",
+			ExpiresAt:   model.GetMillis() - hourMillis,
+		})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.user_access_token.expires_at.app_error", appErr.Id)
+	})
+
+	t.Run("empty scope is not stored", func(t *testing.T) {
+		token, appErr := th.App.CreateUserAccessToken(th.Context, &model.UserAccessToken{
+			UserId:      th.BasicUser.Id,
+			Description: "unscoped",
+			Scope:       &model.UserAccessTokenScope{},
+		})
+		require.Nil(t, appErr)
+		assert.Nil(t, token.Scope)
+
+		session, appErr := th.App.GetSession(token.Token)
+		require.Nil(t, appErr)
+		assert.Nil(t, session.GetUserAccessTokenScope())
+	})
+
+	t.Run("session carries the scope and expiry of the token", func(t *testing.T) {
+		expiresAt := model.GetMillis() + hourMillis
+		token, appErr := th.App.CreateUserAccessToken(th.Context, &model.UserAccessToken{
+			UserId:      th.BasicUser.Id,
+			Description: "scoped",
+			ExpiresAt:   expiresAt,
+			Scope: &model.UserAccessTokenScope{
+				Permissions: []string{model.PermissionCreatePost.Id},
+				ChannelIds:  []string{th.BasicChannel.Id},
+			},
+		})
+		require.Nil(t, appErr)
+
+		session, appErr := th.App.GetSession(token.Token)
+		require.Nil(t, appErr)
+		assert.Equal(t, expiresAt, session.ExpiresAt)
+		scope := session.GetUserAccessTokenScope()
+		require.NotNil(t, scope)
+		assert.Equal(t, []string{model.PermissionCreatePost.Id}, scope.Permissions)
+		assert.Equal(t, []string{th.BasicChannel.Id}, scope.ChannelIds)
+	})
+
+	t.Run("expired token cannot be used", func(t *testing.T) {
+		token, err := th.App.Srv().Store().UserAccessToken().Save(&model.UserAccessToken{
+			UserId:      th.BasicUser.Id,
+			Token:       model.NewId(),
+			Description: "expired",
+			ExpiresAt:   model.GetMillis() - hourMillis,
+		})
+		require.NoError(t, err)
+
+		_, appErr := th.App.GetSession(token.Token)
+		require.NotNil(t, appErr)
+	})
+}
@@ -30,6 +30,15 @@ func (a *App) GetStorageUsage() (int64, *model.AppError) {
 	return usage, nil
 }
 
+// GetStorageUsageByTier returns the number and total size of the files stored in each storage tier
+func (a *App) GetStorageUsageByTier() ([]*model.FileTierUsage, *model.AppError) {
+	usage, err := a.Srv().Store().FileInfo().GetStorageUsageByTier(false)
+	if err != nil {
+		return nil, model.NewAppError("GetStorageUsageByTier", "app.usage.get_storage_usage.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return usage, nil
+}
+
 func (a *App) GetTeamsUsage() (*model.TeamsUsage, *model.AppError) {
 	usage := &model.TeamsUsage{}
 	includeDeleted := false
@@ -379,7 +379,11 @@ func (a *App) CreateOAuthUser(c request.CTX, service string, userData io.Reader,
 		return nil, model.NewAppError("CreateOAuthUser", "api.user.create_oauth_user.already_attached.app_error", map[string]any{"Service": service, "Auth": userByEmail.AuthService}, "email="+user.Email+" authData="+*user.AuthData, http.StatusBadRequest)
 	}
 
-	user.EmailVerified = true
+	// The generic OpenID Connect providers report whether the email address is verified,
+	// the others only return verified email addresses.
+	if !model.IsOpenIdProviderService(service) {
+		user.EmailVerified = true
+	}
 
 	ruser, err := a.CreateUser(c, user)
 	if err != nil {
@@ -784,6 +788,19 @@ func (a *App) DeactivateMfa(userID string) *model.AppError {
 		return model.NewAppError("DeactivateMfa", "mfa.deactivate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	// Deactivating MFA revokes all the methods of the user.
+	if err := a.Srv().Store().WebAuthnCredential().PermanentDeleteByUser(userID); err != nil {
+		return model.NewAppError("DeactivateMfa", "mfa.deactivate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().MfaRecoveryCode().PermanentDeleteByUser(userID); err != nil {
+		return model.NewAppError("DeactivateMfa", "mfa.deactivate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().MfaTrustedDevice().PermanentDeleteByUser(userID); err != nil {
+		return model.NewAppError("DeactivateMfa", "mfa.deactivate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
 	// Make sure old MFA status is not cached locally or in cluster nodes.
 	a.InvalidateCacheForUser(userID)
 
@@ -1422,7 +1439,11 @@ func (a *App) UpdatePassword(rctx request.CTX, user *model.User, newPassword str
 		return model.NewAppError("UpdatePassword", "api.user.update_password.failed.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	hashedPassword, err := model.HashPassword(newPassword)
+	if err := a.checkPasswordHistory(user, newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := model.HashPasswordWithSettings(newPassword, &a.Config().PasswordSettings)
 	if err != nil {
 		// can't be password length (checked in IsPasswordValid)
 		return model.NewAppError("UpdatePassword", "api.user.update_password.password_hash.app_error", nil, "user_id="+user.Id, http.StatusInternalServerError).Wrap(err)
@@ -1432,6 +1453,7 @@ func (a *App) UpdatePassword(rctx request.CTX, user *model.User, newPassword str
 		return model.NewAppError("UpdatePassword", "api.user.update_password.failed.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	a.recordPasswordHistory(rctx, user)
 	a.InvalidateCacheForUser(user.Id)
 
 	if *a.Config().ServiceSettings.TerminateSessionsOnPasswordChange {
@@ -1892,6 +1914,34 @@ func (a *App) PermanentDeleteUser(c request.CTX, user *model.User) *model.AppErr
 		return model.NewAppError("PermanentDeleteUser", "app.user.permanent_delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	if err := a.Srv().Store().NotificationRule().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.notification_rule.permanent_delete_by_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().WebAuthnCredential().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.webauthn.delete_credential.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().MfaRecoveryCode().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.mfa_recovery_code.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().MfaTrustedDevice().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.mfa_trusted_device.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().PasswordHistory().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.password_history.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().LoginDevice().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.login_device.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().CustomProfileAttribute().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.custom_profile_attribute.delete_values.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
 	if err := a.Srv().Store().Audit().PermanentDeleteByUser(user.Id); err != nil {
 		return model.NewAppError("PermanentDeleteUser", "app.audit.permanent_delete_by_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -2230,11 +2280,19 @@ func (a *App) UpdateOAuthUserAttrs(c request.CTX, userData io.Reader, user *mode
 		userAttrsChanged = true
 	}
 
+	// The generic OpenID Connect providers report whether the email address is verified.
+	reportsEmailVerified := model.IsOpenIdProviderService(service)
 	if oauthUser.Email != user.Email {
 		if existingUser, _ := a.GetUserByEmail(oauthUser.Email); existingUser == nil {
 			user.Email = oauthUser.Email
+			if reportsEmailVerified {
+				user.EmailVerified = oauthUser.EmailVerified
+			}
 			userAttrsChanged = true
 		}
+	} else if reportsEmailVerified && oauthUser.EmailVerified && !user.EmailVerified {
+		user.EmailVerified = true
+		userAttrsChanged = true
 	}
 
 	if user.DeleteAt > 0 {
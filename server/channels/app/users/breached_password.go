@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package users
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const breachedPasswordPrefixLength = 5
+
+// isPasswordBreached looks the password up in a local hash-prefix database of breached
+// passwords. Only the bucket of the first characters of the SHA-1 hash of the password is
+// read, the way the k-anonymity range API of the database works, so the database can be
+// the one downloaded from it. A missing bucket holds no breached password.
+func isPasswordBreached(password, directory string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachedPasswordPrefixLength], hash[breachedPasswordPrefixLength:]
+
+	file, err := os.Open(filepath.Join(directory, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to open the breached password bucket %s", prefix)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		candidate, count, _ := strings.Cut(line, ":")
+		if !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+
+		// Padded entries of the database have a count of zero.
+		return strings.TrimLeft(count, "0") != "", nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, errors.Wrapf(err, "failed to read the breached password bucket %s", prefix)
+	}
+
+	return false, nil
+}
@@ -4,10 +4,9 @@
 package users
 
 import (
-	"errors"
 	"strings"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost/server/public/model"
 )
@@ -25,7 +24,7 @@ func ComparePassword(hash string, password string) error {
 		return errors.New("empty password or hash")
 	}
 
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return model.ComparePasswordHash(hash, password)
 }
 
 func (us *UserService) isPasswordValid(password string) error {
@@ -33,7 +32,8 @@ func (us *UserService) isPasswordValid(password string) error {
 }
 
 // IsPasswordValidWithSettings is a utility functions that checks if the given password
-// conforms to the password settings. It returns the error id as error value.
+// conforms to the password settings, and is not a known breached password. It returns the
+// error id as error value.
 func IsPasswordValidWithSettings(password string, settings *model.PasswordSettings) error {
 	id := "model.user.is_valid.pwd"
 	isError := false
@@ -84,5 +84,15 @@ func IsPasswordValidWithSettings(password string, settings *model.PasswordSettin
 		return NewErrInvalidPassword(id + ".app_error")
 	}
 
+	if *settings.BreachedPasswordDirectory != "" {
+		breached, err := isPasswordBreached(password, *settings.BreachedPasswordDirectory)
+		if err != nil {
+			return errors.Wrap(err, "failed to check the password against the breached password database")
+		}
+		if breached {
+			return NewErrInvalidPassword("model.user.is_valid.pwd_breached.app_error")
+		}
+	}
+
 	return nil
 }
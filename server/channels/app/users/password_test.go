@@ -4,6 +4,8 @@
 package users
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -131,3 +133,27 @@ func TestIsPasswordValidWithSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPasswordValidWithBreachedPasswords(t *testing.T) {
+	directory := t.TempDir()
+	// The SHA-1 hash of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "5BAA6.txt"), []byte(
+		"003D68EB55068C33ACE09247EE4C639306B:3\r\n"+
+			"1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n"+
+			"1F2B668E8AABEF1C59E9EC6F82E3F3CD786:0\r\n",
+	), 0600))
+
+	settings := &model.PasswordSettings{BreachedPasswordDirectory: model.NewPointer(directory)}
+	settings.SetDefaults()
+
+	err := IsPasswordValidWithSettings("password", settings)
+	var invErr *ErrInvalidPassword
+	require.ErrorAs(t, err, &invErr)
+	assert.Equal(t, "model.user.is_valid.pwd_breached.app_error", invErr.Id())
+
+	// A password of a missing bucket is not breached.
+	assert.NoError(t, IsPasswordValidWithSettings("not-a-breached-password", settings))
+
+	settings.BreachedPasswordDirectory = model.NewPointer("")
+	assert.NoError(t, IsPasswordValidWithSettings("password", settings))
+}
@@ -64,6 +64,10 @@ func (us *UserService) createUser(rctx request.CTX, user *model.User) (*model.Us
 		return nil, err
 	}
 
+	if appErr := user.HashPassword(&us.config().PasswordSettings); appErr != nil {
+		return nil, appErr
+	}
+
 	ruser, err := us.store.Save(rctx, user)
 	if err != nil {
 		return nil, err
@@ -17,6 +17,8 @@ const (
 	broadcastAddMentions  = "add_mentions"
 	broadcastAddFollowers = "add_followers"
 	broadcastPostedAck    = "posted_ack"
+
+	broadcastMuteNotifications = "mute_notifications"
 )
 
 func (s *Server) makeBroadcastHooks() map[string]platform.BroadcastHook {
@@ -24,6 +26,8 @@ func (s *Server) makeBroadcastHooks() map[string]platform.BroadcastHook {
 		broadcastAddMentions:  &addMentionsBroadcastHook{},
 		broadcastAddFollowers: &addFollowersBroadcastHook{},
 		broadcastPostedAck:    &postedAckBroadcastHook{},
+
+		broadcastMuteNotifications: &muteNotificationsBroadcastHook{},
 	}
 }
 
@@ -71,6 +75,29 @@ func useAddFollowersHook(message *model.WebSocketEvent, followers model.StringAr
 	})
 }
 
+type muteNotificationsBroadcastHook struct{}
+
+func (h *muteNotificationsBroadcastHook) Process(msg *platform.HookedWebSocketEvent, webConn *platform.WebConn, args map[string]any) error {
+	users, err := getTypedArg[model.StringArray](args, "users")
+	if err != nil {
+		return errors.Wrap(err, "Invalid users value passed to muteNotificationsBroadcastHook")
+	}
+
+	if slices.Contains(users, webConn.UserId) {
+		msg.Add("notifications_muted", true)
+	}
+
+	return nil
+}
+
+// useMuteNotificationsHook tells the clients of the given users not to notify them of the post,
+// since their notification rules muted it.
+func useMuteNotificationsHook(message *model.WebSocketEvent, users model.StringArray) {
+	message.GetBroadcast().AddHook(broadcastMuteNotifications, map[string]any{
+		"users": users,
+	})
+}
+
 type postedAckBroadcastHook struct{}
 
 func usePostedAckHook(message *model.WebSocketEvent, postedUserId string, channelType model.ChannelType, usersToNotify []string) {
@@ -83,6 +83,39 @@ func TestAddFollowersHook_Process(t *testing.T) {
 	})
 }
 
+func TestMuteNotificationsHook_Process(t *testing.T) {
+	hook := &muteNotificationsBroadcastHook{}
+
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	webConn := &platform.WebConn{
+		UserId: userID,
+	}
+
+	t.Run("should mute the notifications of the current user", func(t *testing.T) {
+		msg := platform.MakeHookedWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventPosted, "", "", "", nil, ""))
+
+		err := hook.Process(msg, webConn, map[string]any{
+			"users": model.StringArray{userID},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, true, msg.Event().GetData()["notifications_muted"])
+	})
+
+	t.Run("should not mute the notifications of another user", func(t *testing.T) {
+		msg := platform.MakeHookedWebSocketEvent(model.NewWebSocketEvent(model.WebsocketEventPosted, "", "", "", nil, ""))
+
+		err := hook.Process(msg, webConn, map[string]any{
+			"users": []any{otherUserID},
+		})
+		require.NoError(t, err)
+
+		assert.Nil(t, msg.Event().GetData()["notifications_muted"])
+	})
+}
+
 func TestPostedAckHook_Process(t *testing.T) {
 	hook := &postedAckBroadcastHook{}
 	userID := model.NewId()
@@ -240,6 +240,39 @@ func TestWebConnShouldSendEvent(t *testing.T) {
 		assert.True(t, adminUserWc.ShouldSendEvent(event), "expected admin")
 	})
 
+	t.Run("should only send the events of the channels of a scoped token", func(t *testing.T) {
+		scopedSession, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: th.BasicUser.Id, Roles: th.BasicUser.GetRawRoles(), TeamMembers: []*model.TeamMember{
+			{
+				UserId: th.BasicUser.Id,
+				TeamId: th.BasicTeam.Id,
+				Roles:  model.TeamUserRoleId,
+			},
+		}})
+		require.Nil(t, appErr)
+		scopedSession.AddProp(model.SessionPropUserAccessTokenScope, `{"channel_ids":["`+th.BasicChannel.Id+`"]}`)
+
+		scopedWc := &platform.WebConn{
+			Platform: th.Server.Platform(),
+			Suite:    th.App,
+			UserId:   th.BasicUser.Id,
+			T:        i18n.T,
+		}
+		scopedWc.SetConnectionID(model.NewId())
+		scopedWc.SetSession(scopedSession)
+		scopedWc.SetSessionToken(scopedSession.Token)
+		scopedWc.SetSessionExpiresAt(scopedSession.ExpiresAt)
+
+		event = event.SetBroadcast(&model.WebsocketBroadcast{ChannelId: th.BasicChannel.Id})
+		assert.True(t, scopedWc.ShouldSendEvent(event), "expected the channel of the token")
+
+		event = event.SetBroadcast(&model.WebsocketBroadcast{ChannelId: channel2.Id})
+		assert.False(t, scopedWc.ShouldSendEvent(event), "did not expect another channel")
+
+		userEvent := model.NewWebSocketEvent(model.WebsocketEventChannelViewed, "", "", th.BasicUser.Id, nil, "")
+		userEvent.Add("channel_id", channel2.Id)
+		assert.False(t, scopedWc.ShouldSendEvent(userEvent), "did not expect an event about another channel")
+	})
+
 	event2 := model.NewWebSocketEvent(model.WebsocketEventUpdateTeam, th.BasicTeam.Id, "", "", nil, "")
 	assert.True(t, basicUserWc.ShouldSendEvent(event2))
 	assert.True(t, basicUser2Wc.ShouldSendEvent(event2))
@@ -0,0 +1,473 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/webauthn"
+)
+
+// webAuthnChallenge is stored in the Extra field of the challenge tokens, and binds a
+// challenge to its ceremony and, when known, to the user.
+type webAuthnChallenge struct {
+	UserId   string `json:"user_id"`
+	Ceremony string `json:"ceremony"`
+}
+
+func (a *App) isWebAuthnEnabled() bool {
+	return *a.Config().ServiceSettings.EnableMultifactorAuthentication && *a.Config().ServiceSettings.EnableWebAuthn
+}
+
+// webAuthnRelyingParty returns the relying party of the server, identified by the host
+// of the site URL.
+func (a *App) webAuthnRelyingParty() (*webauthn.RelyingParty, *model.AppError) {
+	siteURL, err := url.Parse(a.GetSiteURL())
+	if err != nil || siteURL.Hostname() == "" {
+		return nil, model.NewAppError("webAuthnRelyingParty", "app.webauthn.site_url.app_error", nil, "", http.StatusNotImplemented).Wrap(err)
+	}
+
+	return &webauthn.RelyingParty{
+		ID:     siteURL.Hostname(),
+		Origin: siteURL.Scheme + "://" + siteURL.Host,
+	}, nil
+}
+
+func (a *App) createWebAuthnChallenge(userID, ceremony string) (*model.Token, *model.AppError) {
+	extra, err := json.Marshal(webAuthnChallenge{UserId: userID, Ceremony: ceremony})
+	if err != nil {
+		return nil, model.NewAppError("createWebAuthnChallenge", "app.webauthn.create_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	token := model.NewToken(model.TokenTypeWebAuthnChallenge, string(extra))
+	if err := a.Srv().Store().Token().Save(token); err != nil {
+		return nil, model.NewAppError("createWebAuthnChallenge", "app.webauthn.create_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return token, nil
+}
+
+// consumeWebAuthnChallenge deletes the challenge a client data was signed for, so that
+// it cannot be replayed, and returns it after checking its ceremony and expiry.
+func (a *App) consumeWebAuthnChallenge(clientDataJSON []byte, ceremony string) ([]byte, *webAuthnChallenge, *model.AppError) {
+	challenge, err := webauthn.ClientDataChallenge(clientDataJSON)
+	if err != nil {
+		return nil, nil, model.NewAppError("consumeWebAuthnChallenge", "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	token, err := a.Srv().Store().Token().GetByToken(string(challenge))
+	if err != nil {
+		return nil, nil, model.NewAppError("consumeWebAuthnChallenge", "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	if token.Type != model.TokenTypeWebAuthnChallenge {
+		return nil, nil, model.NewAppError("consumeWebAuthnChallenge", "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err = a.Srv().Store().Token().Delete(token.Token); err != nil {
+		return nil, nil, model.NewAppError("consumeWebAuthnChallenge", "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	var extra webAuthnChallenge
+	if json.Unmarshal([]byte(token.Extra), &extra) != nil || extra.Ceremony != ceremony {
+		return nil, nil, model.NewAppError("consumeWebAuthnChallenge", "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if model.GetMillis()-token.CreateAt > model.WebAuthnTimeout {
+		return nil, nil, model.NewAppError("consumeWebAuthnChallenge", "app.webauthn.expired_challenge.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return challenge, &extra, nil
+}
+
+func webAuthnCredentialDescriptors(credentials []*model.WebAuthnCredential) []model.WebAuthnCredentialDescriptor {
+	descriptors := make([]model.WebAuthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, model.WebAuthnCredentialDescriptor{
+			Type: model.WebAuthnCredentialTypePublicKey,
+			Id:   credential.CredentialId,
+		})
+	}
+	return descriptors
+}
+
+func (a *App) checkWebAuthnUser(user *model.User) *model.AppError {
+	if !a.isWebAuthnEnabled() {
+		return model.NewAppError("checkWebAuthnUser", "app.webauthn.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if user.AuthService != "" && user.AuthService != model.UserAuthServiceLdap {
+		return model.NewAppError("checkWebAuthnUser", "api.user.activate_mfa.email_and_ldap_only.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// GenerateWebAuthnRegistrationOptions starts the registration of a new authenticator
+// for the user.
+func (a *App) GenerateWebAuthnRegistrationOptions(rctx request.CTX, userID string) (*model.WebAuthnCreationOptions, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr = a.checkWebAuthnUser(user); appErr != nil {
+		return nil, appErr
+	}
+
+	rp, appErr := a.webAuthnRelyingParty()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	credentials, err := a.Srv().Store().WebAuthnCredential().GetForUser(user.Id)
+	if err != nil {
+		return nil, model.NewAppError("GenerateWebAuthnRegistrationOptions", "app.webauthn.get_credentials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if len(credentials) >= model.MaxWebAuthnCredentialsPerUser {
+		return nil, model.NewAppError("GenerateWebAuthnRegistrationOptions", "app.webauthn.too_many_credentials.app_error", map[string]any{"Max": model.MaxWebAuthnCredentialsPerUser}, "", http.StatusBadRequest)
+	}
+
+	token, appErr := a.createWebAuthnChallenge(user.Id, model.WebAuthnCeremonyRegistration)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	params := make([]model.WebAuthnCredentialParameters, 0, len(webauthn.SupportedAlgorithms))
+	for _, alg := range webauthn.SupportedAlgorithms {
+		params = append(params, model.WebAuthnCredentialParameters{Type: model.WebAuthnCredentialTypePublicKey, Alg: alg})
+	}
+
+	displayName := user.GetFullName()
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	return &model.WebAuthnCreationOptions{
+		Challenge: base64.RawURLEncoding.EncodeToString([]byte(token.Token)),
+		RP: model.WebAuthnRelyingPartyEntity{
+			Id:   rp.ID,
+			Name: *a.Config().TeamSettings.SiteName,
+		},
+		User: model.WebAuthnUserEntity{
+			Id:          base64.RawURLEncoding.EncodeToString([]byte(user.Id)),
+			Name:        user.Username,
+			DisplayName: displayName,
+		},
+		PubKeyCredParams:   params,
+		Timeout:            model.WebAuthnTimeout,
+		ExcludeCredentials: webAuthnCredentialDescriptors(credentials),
+		AuthenticatorSelection: model.WebAuthnAuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: "preferred",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// RegisterWebAuthnCredential completes the registration of an authenticator. Registering
+// the first MFA method of the user activates MFA, and generates their recovery codes.
+func (a *App) RegisterWebAuthnCredential(rctx request.CTX, userID string, registration *model.WebAuthnRegistrationRequest) (*model.WebAuthnRegistrationResult, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr = a.checkWebAuthnUser(user); appErr != nil {
+		return nil, appErr
+	}
+
+	rp, appErr := a.webAuthnRelyingParty()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if registration.Credential == nil {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.invalid_credential.app_error", nil, "", http.StatusBadRequest)
+	}
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(registration.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.invalid_credential.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	attestationObject, err := base64.RawURLEncoding.DecodeString(registration.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.invalid_credential.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	challenge, extra, appErr := a.consumeWebAuthnChallenge(clientDataJSON, model.WebAuthnCeremonyRegistration)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if extra.UserId != user.Id {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	created, err := rp.VerifyRegistration(challenge, clientDataJSON, attestationObject, false)
+	if err != nil {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.invalid_credential.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	credentials, err := a.Srv().Store().WebAuthnCredential().GetForUser(user.Id)
+	if err != nil {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.get_credentials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(credentials) >= model.MaxWebAuthnCredentialsPerUser {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.too_many_credentials.app_error", map[string]any{"Max": model.MaxWebAuthnCredentialsPerUser}, "", http.StatusBadRequest)
+	}
+
+	credential, err := a.Srv().Store().WebAuthnCredential().Save(&model.WebAuthnCredential{
+		UserId:       user.Id,
+		Name:         registration.Name,
+		CredentialId: base64.RawURLEncoding.EncodeToString(created.ID),
+		PublicKey:    created.PublicKey,
+		SignCount:    int64(created.SignCount),
+		AAGUID:       hex.EncodeToString(created.AAGUID),
+	})
+	if err != nil {
+		var appErr *model.AppError
+		var conflictErr *store.ErrConflict
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &conflictErr):
+			return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.credential_exists.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		default:
+			return nil, model.NewAppError("RegisterWebAuthnCredential", "app.webauthn.save_credential.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	result := &model.WebAuthnRegistrationResult{Credential: credential}
+	if user.MfaActive {
+		return result, nil
+	}
+
+	codes, appErr := a.replaceMfaRecoveryCodes(user.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	result.RecoveryCodes = codes
+
+	if err := a.Srv().Store().User().UpdateMfaActive(user.Id, true); err != nil {
+		return nil, model.NewAppError("RegisterWebAuthnCredential", "mfa.activate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	// Make sure old MFA status is not cached locally or in cluster nodes.
+	a.InvalidateCacheForUser(user.Id)
+
+	a.Srv().Go(func() {
+		if err := a.Srv().EmailService.SendMfaChangeEmail(user.Email, true, user.Locale, a.GetSiteURL()); err != nil {
+			rctx.Logger().Error("Failed to send mfa change email", mlog.Err(err))
+		}
+	})
+
+	return result, nil
+}
+
+func (a *App) GetWebAuthnCredentials(userID string) ([]*model.WebAuthnCredential, *model.AppError) {
+	credentials, err := a.Srv().Store().WebAuthnCredential().GetForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetWebAuthnCredentials", "app.webauthn.get_credentials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return credentials, nil
+}
+
+// DeleteWebAuthnCredential revokes an authenticator of the user. Revoking the last MFA
+// method of the user deactivates MFA.
+func (a *App) DeleteWebAuthnCredential(rctx request.CTX, userID, credentialID string) *model.AppError {
+	credential, err := a.Srv().Store().WebAuthnCredential().Get(credentialID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.credential_not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.get_credentials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if credential.UserId != userID {
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.credential_not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if err = a.Srv().Store().WebAuthnCredential().Delete(credential.Id); err != nil {
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.delete_credential.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return appErr
+	}
+	if !user.MfaActive || user.MfaSecret != "" {
+		return nil
+	}
+
+	remaining, appErr := a.GetWebAuthnCredentials(userID)
+	if appErr != nil {
+		return appErr
+	}
+	if len(remaining) > 0 {
+		return nil
+	}
+
+	return a.UpdateMfa(rctx, false, userID, "")
+}
+
+// GenerateWebAuthnLoginOptions starts an authentication ceremony, to log in without a
+// password or to complete a login as the second factor. When a login id is given, the
+// ceremony is restricted to the authenticators of that user.
+func (a *App) GenerateWebAuthnLoginOptions(rctx request.CTX, loginID string) (*model.WebAuthnRequestOptions, *model.AppError) {
+	if !a.isWebAuthnEnabled() {
+		return nil, model.NewAppError("GenerateWebAuthnLoginOptions", "app.webauthn.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	rp, appErr := a.webAuthnRelyingParty()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	userID := ""
+	credentials := []*model.WebAuthnCredential{}
+	userVerification := "required"
+	if loginID != "" {
+		userVerification = "preferred"
+		// Unknown users get a challenge all the same, so that the response does not
+		// disclose whether the account exists.
+		if user, err := a.GetUserForLogin(rctx, "", loginID); err == nil && user.Id != "" {
+			userID = user.Id
+			if credentials, appErr = a.GetWebAuthnCredentials(user.Id); appErr != nil {
+				return nil, appErr
+			}
+		}
+	}
+
+	token, appErr := a.createWebAuthnChallenge(userID, model.WebAuthnCeremonyLogin)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return &model.WebAuthnRequestOptions{
+		Challenge:        base64.RawURLEncoding.EncodeToString([]byte(token.Token)),
+		Timeout:          model.WebAuthnTimeout,
+		RPId:             rp.ID,
+		AllowCredentials: webAuthnCredentialDescriptors(credentials),
+		UserVerification: userVerification,
+	}, nil
+}
+
+// verifyWebAuthnAssertion verifies an assertion signed with one of the credentials of
+// the user, or with any credential when userID is empty, and records its use.
+func (a *App) verifyWebAuthnAssertion(assertion *model.WebAuthnAssertionCredential, userID string, requireUserVerification bool) (*model.WebAuthnCredential, *model.AppError) {
+	if !a.isWebAuthnEnabled() {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	rp, appErr := a.webAuthnRelyingParty()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(assertion.Response.ClientDataJSON)
+	if err != nil {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+	authData, err := base64.RawURLEncoding.DecodeString(assertion.Response.AuthenticatorData)
+	if err != nil {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(assertion.Response.Signature)
+	if err != nil {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+
+	challenge, extra, appErr := a.consumeWebAuthnChallenge(clientDataJSON, model.WebAuthnCeremonyLogin)
+	if appErr != nil {
+		appErr.StatusCode = http.StatusUnauthorized
+		return nil, appErr
+	}
+
+	credential, err := a.Srv().Store().WebAuthnCredential().GetByCredentialId(assertion.RawId)
+	if err != nil {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+	if (userID != "" && credential.UserId != userID) || (extra.UserId != "" && credential.UserId != extra.UserId) {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized)
+	}
+	if assertion.Response.UserHandle != "" && assertion.Response.UserHandle != base64.RawURLEncoding.EncodeToString([]byte(credential.UserId)) {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	signCount, err := rp.VerifyAssertion(challenge, credential.PublicKey, uint32(credential.SignCount), clientDataJSON, authData, signature, requireUserVerification)
+	if err != nil {
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+
+	if err := a.Srv().Store().WebAuthnCredential().UpdateSignCount(credential.Id, int64(signCount), model.GetMillis()); err != nil {
+		// The counter was updated concurrently, by an assertion replayed or signed by a
+		// cloned authenticator.
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.invalid_assertion.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+		}
+		return nil, model.NewAppError("verifyWebAuthnAssertion", "app.webauthn.save_credential.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return credential, nil
+}
+
+// checkWebAuthnMfa checks an MFA token holding the JSON serialization of an assertion.
+func (a *App) checkWebAuthnMfa(rctx request.CTX, user *model.User, token string) *model.AppError {
+	var assertion model.WebAuthnAssertionCredential
+	if err := json.Unmarshal([]byte(strings.TrimSpace(token)), &assertion); err != nil {
+		return model.NewAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+
+	if _, appErr := a.verifyWebAuthnAssertion(&assertion, user.Id, false); appErr != nil {
+		rctx.Logger().Debug("WebAuthn assertion rejected", mlog.String("user_id", user.Id), mlog.Err(appErr))
+		return model.NewAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized).Wrap(appErr)
+	}
+
+	return nil
+}
+
+// AuthenticateUserForWebAuthnLogin authenticates a user with a passkey, without password.
+// The authenticator must have verified the user, e.g. with a PIN or biometrics, which
+// makes it a multi-factor authentication by itself.
+func (a *App) AuthenticateUserForWebAuthnLogin(rctx request.CTX, assertion *model.WebAuthnAssertionCredential) (user *model.User, appErr *model.AppError) {
+	defer func() {
+		if a.Metrics() != nil {
+			if user == nil || appErr != nil {
+				a.Metrics().IncrementLoginFail()
+			} else {
+				a.Metrics().IncrementLogin()
+			}
+		}
+	}()
+
+	if !a.isWebAuthnEnabled() || !*a.Config().ServiceSettings.EnableWebAuthnPasswordlessLogin {
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.passwordless_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	credential, appErr := a.verifyWebAuthnAssertion(assertion, "", true)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if user, appErr = a.GetUser(credential.UserId); appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr = a.CheckUserAllAuthenticationCriteria(rctx, user, ""); appErr != nil {
+		return nil, appErr
+	}
+
+	return user, nil
+}
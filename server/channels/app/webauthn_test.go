@@ -0,0 +1,63 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestDeleteWebAuthnCredential(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableMultifactorAuthentication = true
+		*cfg.ServiceSettings.EnableWebAuthn = true
+	})
+
+	saveCredential := func(userID string) *model.WebAuthnCredential {
+		credential, err := th.App.Srv().Store().WebAuthnCredential().Save(&model.WebAuthnCredential{
+			UserId:       userID,
+			Name:         "Security key",
+			CredentialId: base64.RawURLEncoding.EncodeToString([]byte(model.NewId())),
+			PublicKey:    []byte{0xa0},
+		})
+		require.NoError(t, err)
+		return credential
+	}
+
+	require.NoError(t, th.App.Srv().Store().User().UpdateMfaActive(th.BasicUser.Id, true))
+	th.App.InvalidateCacheForUser(th.BasicUser.Id)
+	first := saveCredential(th.BasicUser.Id)
+	second := saveCredential(th.BasicUser.Id)
+
+	t.Run("credential of another user", func(t *testing.T) {
+		other := saveCredential(th.BasicUser2.Id)
+		appErr := th.App.DeleteWebAuthnCredential(th.Context, th.BasicUser.Id, other.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.webauthn.credential_not_found.app_error", appErr.Id)
+	})
+
+	t.Run("mfa stays active while a credential remains", func(t *testing.T) {
+		require.Nil(t, th.App.DeleteWebAuthnCredential(th.Context, th.BasicUser.Id, first.Id))
+
+		user, appErr := th.App.GetUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		assert.True(t, user.MfaActive)
+	})
+
+	t.Run("deleting the last credential deactivates mfa", func(t *testing.T) {
+		require.Nil(t, th.App.DeleteWebAuthnCredential(th.Context, th.BasicUser.Id, second.Id))
+
+		user, appErr := th.App.GetUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		assert.False(t, user.MfaActive)
+	})
+}
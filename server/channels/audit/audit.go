@@ -5,6 +5,7 @@ package audit
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -18,6 +19,16 @@ type Audit struct {
 
 	// OnError is called when an error occurs while writing an audit record.
 	OnError func(err error)
+
+	// MetricsCollector, when set, receives the metrics of the targets exporting audit records,
+	// such as the OTLP and Loki ones.
+	MetricsCollector mlog.MetricsCollector
+
+	// OnChainedRecord, when set, is called with each hash chained record once logged,
+	// e.g. to persist it.
+	OnChainedRecord func(rec *ChainedRecord)
+
+	chain atomic.Value // *Chain
 }
 
 func (a *Audit) Init(maxQueueSize int) {
@@ -40,12 +51,89 @@ func (a *Audit) LogRecord(level mlog.Level, rec Record) {
 		mlog.Any(KeyError, rec.Error),
 	}
 
-	a.logger.Log(level, "", flds...)
+	chain := a.getChain()
+	if chain == nil {
+		a.logger.Log(level, "", flds...)
+		return
+	}
+
+	// The records are logged while holding the lock of the chain so that they are written
+	// in the order of the chain.
+	chain.mux.Lock()
+	defer chain.mux.Unlock()
+	if chain.ended {
+		a.logger.Log(level, "", flds...)
+		return
+	}
+
+	chained, err := chain.Link(level, rec)
+	if err != nil {
+		a.onLoggerError(err)
+		a.logger.Log(level, "", flds...)
+		return
+	}
+	a.logChainedRecord(level, chained, flds)
+
+	if chain.IsCheckpointDue() {
+		a.logCheckpoint(chain)
+	}
+}
+
+// SetChain sets the hash chain linking the audit records, or disables hash chaining when
+// nil. The previous chain, if any, is ended with a checkpoint.
+func (a *Audit) SetChain(chain *Chain) {
+	previous, _ := a.chain.Swap(chain).(*Chain)
+	if previous == nil {
+		return
+	}
+
+	previous.mux.Lock()
+	defer previous.mux.Unlock()
+	if !previous.ended {
+		a.logCheckpoint(previous)
+		previous.ended = true
+	}
 }
 
-// Configure sets zero or more target to output audit logs to.
+func (a *Audit) getChain() *Chain {
+	chain, _ := a.chain.Load().(*Chain)
+	return chain
+}
+
+// logCheckpoint logs a checkpoint signing the chain. It must be called while holding the
+// lock of the chain.
+func (a *Audit) logCheckpoint(chain *Chain) {
+	checkpoint, level, err := chain.Checkpoint()
+	if err != nil {
+		a.onLoggerError(err)
+		return
+	}
+	if checkpoint == nil {
+		return
+	}
+
+	flds := []mlog.Field{
+		mlog.String(KeyEventName, checkpoint.EventName),
+		mlog.String(KeyStatus, checkpoint.Status),
+		mlog.Any(KeyActor, checkpoint.Actor),
+		mlog.Any(KeyEvent, checkpoint.EventData),
+		mlog.Any(KeyMeta, checkpoint.Meta),
+		mlog.Any(KeyError, checkpoint.Error),
+	}
+	a.logChainedRecord(level, checkpoint, flds)
+}
+
+func (a *Audit) logChainedRecord(level mlog.Level, rec *ChainedRecord, flds []mlog.Field) {
+	a.logger.Log(level, "", append(flds, rec.fields()...)...)
+	if a.OnChainedRecord != nil {
+		a.OnChainedRecord(rec)
+	}
+}
+
+// Configure sets zero or more target to output audit logs to. Besides the targets built
+// into logr, audit logs can be exported to the targets provided by mlog.NewFactories.
 func (a *Audit) Configure(cfg mlog.LoggerConfiguration) error {
-	return a.logger.ConfigureTargets(cfg, nil)
+	return a.logger.ConfigureTargets(cfg, mlog.NewAuditFactories(a.MetricsCollector))
 }
 
 // Flush attempts to write all queued audit records to all targets.
@@ -58,7 +146,10 @@ func (a *Audit) Flush() error {
 }
 
 // Shutdown cleanly stops the audit engine after making best efforts to flush all targets.
+// The hash chain, if any, is ended with a checkpoint.
 func (a *Audit) Shutdown() error {
+	a.SetChain(nil)
+
 	err := a.logger.Shutdown()
 	if err != nil {
 		a.onLoggerError(err)
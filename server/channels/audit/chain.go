@@ -0,0 +1,273 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// Keys of the fields added to the hash chained audit records.
+const (
+	KeyChainID   = "chain_id"
+	KeySequence  = "seq"
+	KeyCreateAt  = "create_at"
+	KeyPrevHash  = "prev_hash"
+	KeyHash      = "hash"
+	KeySignature = "signature"
+
+	// EventNameCheckpoint is the event name of the records signing the hash of the chain.
+	EventNameCheckpoint = "auditCheckpoint"
+)
+
+// hashedKeys are the fields of a chained record covered by its hash.
+var hashedKeys = []string{
+	KeyChainID,
+	KeySequence,
+	KeyCreateAt,
+	KeyPrevHash,
+	KeyEventName,
+	KeyStatus,
+	KeyActor,
+	KeyEvent,
+	KeyMeta,
+	KeyError,
+}
+
+// ChainedRecord is an audit record linked to the previous record of its chain.
+type ChainedRecord struct {
+	Record
+	ChainID  string
+	Sequence int64
+	CreateAt int64
+	PrevHash string
+	// Hash is the hex encoded SHA-256 of Payload.
+	Hash string
+	// Signature is the base64 encoded ECDSA signature of Hash. Only checkpoints are signed.
+	Signature string
+	// Payload is the canonical JSON encoding of the hashed fields of the record.
+	Payload []byte
+}
+
+// IsCheckpoint reports whether the record is a checkpoint.
+func (r *ChainedRecord) IsCheckpoint() bool {
+	return r.EventName == EventNameCheckpoint
+}
+
+func (r *ChainedRecord) fields() []mlog.Field {
+	flds := []mlog.Field{
+		mlog.String(KeyChainID, r.ChainID),
+		mlog.Int(KeySequence, r.Sequence),
+		mlog.Int(KeyCreateAt, r.CreateAt),
+		mlog.String(KeyPrevHash, r.PrevHash),
+		mlog.String(KeyHash, r.Hash),
+	}
+	if r.Signature != "" {
+		flds = append(flds, mlog.String(KeySignature, r.Signature))
+	}
+	return flds
+}
+
+// Chain links the audit records of a server into a hash chain: each record carries a
+// sequence number and the hash of the previous record, so removing or modifying a record
+// breaks the chain. Every CheckpointInterval records, a checkpoint record signing the
+// hash of the chain is added, so the chain cannot be rewritten without the signing key.
+//
+// A chain is started each time the server starts. Its methods must be called while
+// holding its lock, which Audit does.
+type Chain struct {
+	mux   sync.Mutex
+	ended bool
+
+	id                 string
+	checkpointInterval int64
+	signingKey         func() *ecdsa.PrivateKey
+
+	seq             int64
+	prevHash        string
+	sinceCheckpoint int64
+	lastLevel       mlog.Level
+}
+
+// NewChain creates a chain adding a checkpoint every checkpointInterval records. The
+// checkpoints are signed with the key returned by signingKey, or left unsigned if it
+// returns nil.
+func NewChain(checkpointInterval int, signingKey func() *ecdsa.PrivateKey) *Chain {
+	if checkpointInterval <= 0 {
+		checkpointInterval = model.ExperimentalAuditSettingsDefaultCheckpointInterval
+	}
+	return &Chain{
+		id:                 model.NewId(),
+		checkpointInterval: int64(checkpointInterval),
+		signingKey:         signingKey,
+	}
+}
+
+// LoadSigningKey reads the PEM encoded ECDSA private key signing the checkpoints, in the
+// SEC 1 ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY") format, from the given file.
+func LoadSigningKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	if block.Type == "EC PRIVATE KEY" {
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse signing key: %w", err)
+		}
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse signing key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key is not an ECDSA key")
+	}
+	return key, nil
+}
+
+// ID returns the identifier of the chain, logged with each of its records.
+func (c *Chain) ID() string {
+	return c.id
+}
+
+// Link adds the record to the chain.
+func (c *Chain) Link(level mlog.Level, rec Record) (*ChainedRecord, error) {
+	chained := &ChainedRecord{
+		Record:   rec,
+		ChainID:  c.id,
+		Sequence: c.seq + 1,
+		CreateAt: model.GetMillis(),
+		PrevHash: c.prevHash,
+	}
+
+	payload, err := chainedRecordPayload(chained)
+	if err != nil {
+		return nil, err
+	}
+	chained.Payload = payload
+	chained.Hash = hashPayload(payload)
+
+	if chained.IsCheckpoint() {
+		if c.signingKey != nil {
+			if key := c.signingKey(); key != nil {
+				if chained.Signature, err = signHash(key, chained.Hash); err != nil {
+					return nil, err
+				}
+			}
+		}
+		c.sinceCheckpoint = 0
+	} else {
+		c.sinceCheckpoint++
+	}
+
+	c.seq = chained.Sequence
+	c.prevHash = chained.Hash
+	c.lastLevel = level
+	return chained, nil
+}
+
+// IsCheckpointDue reports whether a checkpoint should be added to the chain.
+func (c *Chain) IsCheckpointDue() bool {
+	return c.sinceCheckpoint >= c.checkpointInterval
+}
+
+// Checkpoint adds a checkpoint to the chain, unless the last record is one already, and
+// returns it with the level of the last record.
+func (c *Chain) Checkpoint() (*ChainedRecord, mlog.Level, error) {
+	if c.sinceCheckpoint == 0 {
+		return nil, c.lastLevel, nil
+	}
+
+	rec := Record{
+		EventName: EventNameCheckpoint,
+		Status:    Success,
+		Meta: map[string]any{
+			"records": c.sinceCheckpoint,
+		},
+	}
+	level := c.lastLevel
+	chained, err := c.Link(level, rec)
+	return chained, level, err
+}
+
+// chainedRecordPayload returns the canonical JSON encoding of the hashed fields of the
+// record.
+func chainedRecordPayload(r *ChainedRecord) ([]byte, error) {
+	return canonicalPayload(map[string]any{
+		KeyChainID:   r.ChainID,
+		KeySequence:  r.Sequence,
+		KeyCreateAt:  r.CreateAt,
+		KeyPrevHash:  r.PrevHash,
+		KeyEventName: r.EventName,
+		KeyStatus:    r.Status,
+		KeyActor:     r.Actor,
+		KeyEvent:     r.EventData,
+		KeyMeta:      r.Meta,
+		KeyError:     r.Error,
+	})
+}
+
+// canonicalPayload encodes the hashed fields the same way whether they come from a
+// record being logged or from a line of an audit log: the values are decoded back to
+// generic JSON values, so that the keys are sorted and the encoding of strings and
+// numbers does not depend on the formatter of the target.
+func canonicalPayload(fields map[string]any) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode audit record: %w", err)
+	}
+
+	var generic any
+	if err = json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("cannot decode audit record: %w", err)
+	}
+
+	return json.Marshal(generic)
+}
+
+func hashPayload(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func signHash(key *ecdsa.PrivateKey, hash string) (string, error) {
+	digest := sha256.Sum256([]byte(hash))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("cannot sign audit checkpoint: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func verifyHashSignature(key *ecdsa.PublicKey, hash, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256([]byte(hash))
+	return ecdsa.VerifyASN1(key, digest[:], sig)
+}
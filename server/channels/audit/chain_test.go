@@ -0,0 +1,255 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func newTestSigningKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+// writeChainedAuditLog logs count records with a checkpoint every interval records to an
+// audit log file, and returns its lines and the records passed to OnChainedRecord.
+func writeChainedAuditLog(t *testing.T, key *ecdsa.PrivateKey, count, interval int) ([]string, []*ChainedRecord) {
+	filePath := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := mlog.NewLogger()
+	require.NoError(t, err)
+	err = logger.ConfigureTargets(mlog.LoggerConfiguration{
+		"file": {
+			Type:          "file",
+			Format:        "json",
+			FormatOptions: json.RawMessage(`{"disable_timestamp": false, "disable_msg": true, "disable_stacktrace": true, "disable_level": true}`),
+			Levels:        []mlog.Level{mlog.LvlAuditAPI, mlog.LvlAuditCLI},
+			Options:       json.RawMessage(fmt.Sprintf(`{"filename": %q}`, filePath)),
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	var chained []*ChainedRecord
+	audit := &Audit{logger: logger, OnChainedRecord: func(rec *ChainedRecord) { chained = append(chained, rec) }}
+	audit.SetChain(NewChain(interval, func() *ecdsa.PrivateKey { return key }))
+
+	for i := 0; i < count; i++ {
+		rec := Record{
+			EventName: "updateUser",
+			Status:    Success,
+			Actor:     EventActor{UserId: model.NewId(), IpAddress: "10.0.0.1"},
+			Meta:      map[string]any{"index": i, "ratio": 0.5, "html": "<b>&</b>"},
+		}
+		AddEventParameter(&rec, "ids", []string{"a", "b"})
+		audit.LogRecord(mlog.LvlAuditAPI, rec)
+	}
+	require.NoError(t, audit.Shutdown())
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n"), chained
+}
+
+func verifyLines(t *testing.T, lines []string, key *ecdsa.PublicKey) *VerifyResult {
+	result, err := Verify(strings.NewReader(strings.Join(lines, "\n")), key)
+	require.NoError(t, err)
+	return result
+}
+
+func TestChain(t *testing.T) {
+	key := newTestSigningKey(t)
+	lines, chained := writeChainedAuditLog(t, key, 5, 2)
+
+	// 5 records, a checkpoint after the second and fourth ones, and one on shutdown.
+	require.Len(t, lines, 8)
+	require.Len(t, chained, 8)
+	for i, rec := range chained {
+		assert.Equal(t, int64(i+1), rec.Sequence)
+		assert.Equal(t, chained[0].ChainID, rec.ChainID)
+		assert.Equal(t, hashPayload(rec.Payload), rec.Hash)
+		if i > 0 {
+			assert.Equal(t, chained[i-1].Hash, rec.PrevHash)
+		}
+		assert.Equal(t, rec.IsCheckpoint(), rec.Signature != "", "only checkpoints are signed")
+	}
+	assert.True(t, chained[2].IsCheckpoint())
+	assert.True(t, chained[5].IsCheckpoint())
+	assert.True(t, chained[7].IsCheckpoint())
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, chained[0].Hash, line[KeyHash])
+	assert.Equal(t, "", line[KeyPrevHash])
+}
+
+func TestVerify(t *testing.T) {
+	key := newTestSigningKey(t)
+	lines, _ := writeChainedAuditLog(t, key, 5, 2)
+
+	t.Run("valid log", func(t *testing.T) {
+		result := verifyLines(t, lines, &key.PublicKey)
+		require.True(t, result.IsValid(), result.Issues)
+		assert.Equal(t, 8, result.Records)
+		require.Len(t, result.Chains, 1)
+		assert.Equal(t, int64(1), result.Chains[0].FirstSeq)
+		assert.Equal(t, int64(8), result.Chains[0].LastSeq)
+		assert.Equal(t, 3, result.Chains[0].Checkpoints)
+		assert.Equal(t, 3, result.Chains[0].VerifiedCheckpoints)
+		assert.Equal(t, 0, result.Chains[0].UncheckpointedRecords)
+	})
+
+	t.Run("without public key", func(t *testing.T) {
+		result := verifyLines(t, lines, nil)
+		require.True(t, result.IsValid(), result.Issues)
+		assert.Equal(t, 0, result.Chains[0].VerifiedCheckpoints)
+	})
+
+	t.Run("modified record", func(t *testing.T) {
+		modified := append([]string{}, lines...)
+		modified[1] = strings.Replace(modified[1], `"updateUser"`, `"getUser"`, 1)
+		result := verifyLines(t, modified, &key.PublicKey)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, 2, result.Issues[0].Line)
+		assert.Equal(t, int64(2), result.Issues[0].Sequence)
+		assert.Contains(t, result.Issues[0].Message, "modified")
+	})
+
+	t.Run("removed record", func(t *testing.T) {
+		removed := append(append([]string{}, lines[:3]...), lines[4:]...)
+		result := verifyLines(t, removed, &key.PublicKey)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, "records 4 to 4 are missing", result.Issues[0].Message)
+	})
+
+	t.Run("reordered records", func(t *testing.T) {
+		reordered := append([]string{}, lines...)
+		reordered[3], reordered[4] = reordered[4], reordered[3]
+		result := verifyLines(t, reordered, &key.PublicKey)
+		assert.False(t, result.IsValid())
+	})
+
+	t.Run("rotated log", func(t *testing.T) {
+		result := verifyLines(t, lines[2:], &key.PublicKey)
+		require.True(t, result.IsValid(), result.Issues)
+		assert.Equal(t, int64(3), result.Chains[0].FirstSeq)
+	})
+
+	t.Run("removed head", func(t *testing.T) {
+		result := verifyLines(t, lines[3:], &key.PublicKey)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, int64(4), result.Issues[0].Sequence)
+		assert.Contains(t, result.Issues[0].Message, "without a signed checkpoint")
+	})
+
+	t.Run("chain without a verified checkpoint", func(t *testing.T) {
+		result := verifyLines(t, lines[:2], &key.PublicKey)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, 2, result.Issues[0].Line)
+		assert.Equal(t, "chain has no checkpoint with a valid signature", result.Issues[0].Message)
+
+		result = verifyLines(t, lines[:2], nil)
+		require.True(t, result.IsValid(), result.Issues)
+	})
+
+	t.Run("inserted unchained record", func(t *testing.T) {
+		inserted := append(append(append([]string{}, lines[:3]...), `{"event_name":"login"}`), lines[3:]...)
+		result := verifyLines(t, inserted, &key.PublicKey)
+		assert.Equal(t, 1, result.UnchainedRecords)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, 4, result.Issues[0].Line)
+	})
+
+	t.Run("removed tail", func(t *testing.T) {
+		result := verifyLines(t, lines[:5], &key.PublicKey)
+		require.True(t, result.IsValid(), result.Issues)
+		assert.Equal(t, 2, result.Chains[0].UncheckpointedRecords)
+	})
+
+	t.Run("wrong signing key", func(t *testing.T) {
+		result := verifyLines(t, lines, &newTestSigningKey(t).PublicKey)
+		require.Len(t, result.Issues, 4)
+		assert.Equal(t, "checkpoint signature is invalid", result.Issues[0].Message)
+		assert.Equal(t, "chain has no checkpoint with a valid signature", result.Issues[3].Message)
+	})
+
+	t.Run("unchained records and invalid lines", func(t *testing.T) {
+		result := verifyLines(t, append([]string{`{"event_name":"login"}`, "not json", ""}, lines...), nil)
+		assert.Equal(t, 1, result.UnchainedRecords)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, 2, result.Issues[0].Line)
+	})
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newTestSigningKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	parsed, err := ParsePublicKey(base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(parsed))
+
+	var pemKey bytes.Buffer
+	pemKey.WriteString("-----BEGIN PUBLIC KEY-----\n")
+	pemKey.WriteString(base64.StdEncoding.EncodeToString(der))
+	pemKey.WriteString("\n-----END PUBLIC KEY-----\n")
+	parsed, err = ParsePublicKey(pemKey.String())
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(parsed))
+
+	_, err = ParsePublicKey("not a key")
+	require.Error(t, err)
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	key := newTestSigningKey(t)
+	dir := t.TempDir()
+
+	writeKey := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+		return path
+	}
+
+	sec1, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	loaded, err := LoadSigningKey(writeKey("sec1.pem", "EC PRIVATE KEY", sec1))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	loaded, err = LoadSigningKey(writeKey("pkcs8.pem", "PRIVATE KEY", pkcs8))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	other, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	_, err = LoadSigningKey(writeKey("ed25519.pem", "PRIVATE KEY", other))
+	require.Error(t, err)
+
+	_, err = LoadSigningKey(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+}
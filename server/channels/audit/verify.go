@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VerifyIssue is a gap or modification found in the hash chains of an audit log.
+type VerifyIssue struct {
+	Line     int    `json:"line"`
+	ChainID  string `json:"chain_id,omitempty"`
+	Sequence int64  `json:"seq,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ChainSummary describes a hash chain found in an audit log.
+type ChainSummary struct {
+	ID       string `json:"id"`
+	FirstSeq int64  `json:"first_seq"`
+	LastSeq  int64  `json:"last_seq"`
+	Records  int    `json:"records"`
+	// Checkpoints is the number of checkpoints of the chain, of which VerifiedCheckpoints
+	// had their signature verified.
+	Checkpoints         int `json:"checkpoints"`
+	VerifiedCheckpoints int `json:"verified_checkpoints"`
+	// UncheckpointedRecords is the number of records after the last checkpoint. Unlike the
+	// previous ones, those records could be removed from the end of the chain undetected.
+	UncheckpointedRecords int `json:"uncheckpointed_records"`
+
+	lastHash string
+	lastLine int
+}
+
+// VerifyResult is the result of the verification of an audit log.
+type VerifyResult struct {
+	Records int `json:"records"`
+	// UnchainedRecords is the number of records without a hash, e.g. logged before hash
+	// chaining was enabled. Those found after the start of a chain are also reported as
+	// issues.
+	UnchainedRecords int             `json:"unchained_records"`
+	Chains           []*ChainSummary `json:"chains"`
+	Issues           []VerifyIssue   `json:"issues"`
+}
+
+// IsValid reports whether no gap or modification was found.
+func (r *VerifyResult) IsValid() bool {
+	return len(r.Issues) == 0
+}
+
+// Verify reads an audit log written by a JSON target, one record per line, and checks
+// the hash chains of its records: each record must follow the previous record of its
+// chain and match its hash, and the signature of the checkpoints must be valid. The
+// signatures are verified only if publicKey is not nil, in which case every chain must
+// have a checkpoint with a valid signature.
+//
+// A chain must start with its first record or with a signed checkpoint, e.g. the last
+// checkpoint of the log files rotated out, so that removing its first records is noticed.
+func Verify(r io.Reader, publicKey *ecdsa.PublicKey) (*VerifyResult, error) {
+	result := &VerifyResult{Chains: []*ChainSummary{}, Issues: []VerifyIssue{}}
+	chains := make(map[string]*ChainSummary)
+
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("cannot read audit log: %w", err)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			verifyLine(result, chains, publicKey, lineNumber, trimmed)
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if publicKey != nil {
+		for _, chain := range result.Chains {
+			if chain.VerifiedCheckpoints == 0 {
+				result.Issues = append(result.Issues, VerifyIssue{Line: chain.lastLine, ChainID: chain.ID, Sequence: chain.LastSeq, Message: "chain has no checkpoint with a valid signature"})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func verifyLine(result *VerifyResult, chains map[string]*ChainSummary, publicKey *ecdsa.PublicKey, lineNumber int, line []byte) {
+	addIssue := func(chainID string, seq int64, format string, args ...any) {
+		result.Issues = append(result.Issues, VerifyIssue{Line: lineNumber, ChainID: chainID, Sequence: seq, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(line, &fields); err != nil {
+		addIssue("", 0, "line is not a JSON audit record: %v", err)
+		return
+	}
+
+	result.Records++
+	hash, ok := fields[KeyHash].(string)
+	if !ok {
+		result.UnchainedRecords++
+		if len(chains) > 0 {
+			addIssue("", 0, "record has no hash although hash chaining has started: it may have been inserted")
+		}
+		return
+	}
+
+	chainID, _ := fields[KeyChainID].(string)
+	prevHash, _ := fields[KeyPrevHash].(string)
+	signature, _ := fields[KeySignature].(string)
+	eventName, _ := fields[KeyEventName].(string)
+	seqValue, _ := fields[KeySequence].(float64)
+	seq := int64(seqValue)
+	if chainID == "" || seq < 1 {
+		addIssue(chainID, seq, "record has no valid chain identifier or sequence number")
+		return
+	}
+
+	hashed := make(map[string]any, len(hashedKeys))
+	for _, key := range hashedKeys {
+		hashed[key] = fields[key]
+	}
+	payload, err := canonicalPayload(hashed)
+	if err != nil {
+		addIssue(chainID, seq, "%v", err)
+		return
+	}
+	if hashPayload(payload) != hash {
+		addIssue(chainID, seq, "record was modified: its hash does not match its content")
+	}
+
+	chain, ok := chains[chainID]
+	if !ok {
+		chain = &ChainSummary{ID: chainID, FirstSeq: seq}
+		chains[chainID] = chain
+		result.Chains = append(result.Chains, chain)
+		switch {
+		case seq == 1 && prevHash != "":
+			addIssue(chainID, seq, "first record of the chain has a previous hash")
+		case seq > 1 && (eventName != EventNameCheckpoint || signature == ""):
+			addIssue(chainID, seq, "chain starts at sequence %d without a signed checkpoint: its first records may have been removed", seq)
+		}
+	} else {
+		switch {
+		case seq <= chain.LastSeq:
+			addIssue(chainID, seq, "record is duplicated or out of order: expected sequence %d", chain.LastSeq+1)
+		case seq > chain.LastSeq+1:
+			addIssue(chainID, seq, "records %d to %d are missing", chain.LastSeq+1, seq-1)
+		case prevHash != chain.lastHash:
+			addIssue(chainID, seq, "previous record was modified: previous hash does not match")
+		}
+	}
+
+	chain.Records++
+	chain.LastSeq = max(chain.LastSeq, seq)
+	chain.lastHash = hash
+	chain.lastLine = lineNumber
+
+	if eventName != EventNameCheckpoint {
+		chain.UncheckpointedRecords++
+		return
+	}
+
+	chain.Checkpoints++
+	chain.UncheckpointedRecords = 0
+	if publicKey == nil {
+		return
+	}
+	switch {
+	case signature == "":
+		addIssue(chainID, seq, "checkpoint is not signed")
+	case !verifyHashSignature(publicKey, hash, signature):
+		addIssue(chainID, seq, "checkpoint signature is invalid")
+	default:
+		chain.VerifiedCheckpoints++
+	}
+}
+
+// ParsePublicKey parses the public key verifying the signature of the checkpoints, the
+// public key of ExperimentalAuditSettings.CheckpointSigningKeyFile, either PEM encoded or
+// base64 DER encoded.
+func ParsePublicKey(key string) (*ecdsa.PublicKey, error) {
+	key = strings.TrimSpace(key)
+
+	var der []byte
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		der = block.Bytes
+	} else {
+		var err error
+		if der, err = base64.StdEncoding.DecodeString(key); err != nil {
+			return nil, fmt.Errorf("public key is neither PEM nor base64 encoded: %w", err)
+		}
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse public key: %w", err)
+	}
+
+	publicKey, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an ECDSA key")
+	}
+	return publicKey, nil
+}
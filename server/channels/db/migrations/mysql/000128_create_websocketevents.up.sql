@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS WebSocketEvents (
+    Seq bigint(20) NOT NULL AUTO_INCREMENT,
+    CreateAt bigint(20) NOT NULL,
+    UserId varchar(26) NOT NULL DEFAULT '',
+    Event longtext NOT NULL,
+    PRIMARY KEY (Seq),
+    KEY idx_websocketevents_createat (CreateAt),
+    KEY idx_websocketevents_userid_seq (UserId, Seq)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS NotificationRules (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    Action varchar(16) NOT NULL,
+    Keywords text NOT NULL,
+    Patterns text NOT NULL,
+    TeamIds text NOT NULL,
+    ChannelIds text NOT NULL,
+    Schedule json,
+    BypassUrgent tinyint(1) NOT NULL DEFAULT 0,
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    PRIMARY KEY (Id),
+    KEY idx_notificationrules_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ChannelEmailAddresses (
+    ChannelId varchar(26) NOT NULL,
+    Token varchar(26) NOT NULL,
+    WebhookId varchar(26) NOT NULL,
+    AllowedSenders text NOT NULL,
+    CreatorId varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    PRIMARY KEY (ChannelId),
+    UNIQUE KEY idx_channelemailaddresses_token (Token)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS AuditRecords (
+    Id varchar(26) NOT NULL,
+    ChainId varchar(26) NOT NULL,
+    Sequence bigint(20) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    EventName varchar(256) NOT NULL,
+    Status varchar(32) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    SessionId varchar(26) NOT NULL,
+    IpAddress varchar(64) NOT NULL,
+    ObjectType varchar(64) NOT NULL,
+    PrevHash varchar(64) NOT NULL,
+    Hash varchar(64) NOT NULL,
+    Signature text NOT NULL,
+    Data longtext NOT NULL,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_auditrecords_chainid_sequence (ChainId, Sequence),
+    KEY idx_auditrecords_createat (CreateAt),
+    KEY idx_auditrecords_userid_createat (UserId, CreateAt),
+    KEY idx_auditrecords_eventname_createat (EventName, CreateAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS OAuthDeviceCodes (
+    DeviceCode varchar(128) NOT NULL,
+    UserCode varchar(16) NOT NULL,
+    ClientId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Scope varchar(128) NOT NULL,
+    Status varchar(16) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    ExpiresAt bigint(20) NOT NULL,
+    LastPollAt bigint(20) NOT NULL,
+    PRIMARY KEY (DeviceCode),
+    UNIQUE KEY idx_oauthdevicecodes_usercode (UserCode),
+    KEY idx_oauthdevicecodes_expiresat (ExpiresAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
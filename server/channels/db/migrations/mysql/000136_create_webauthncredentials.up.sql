@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS WebAuthnCredentials (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    CredentialId varchar(400) NOT NULL,
+    PublicKey blob NOT NULL,
+    SignCount bigint(20) NOT NULL,
+    AAGUID varchar(32) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    LastUsedAt bigint(20) NOT NULL,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_webauthncredentials_credentialid (CredentialId),
+    KEY idx_webauthncredentials_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
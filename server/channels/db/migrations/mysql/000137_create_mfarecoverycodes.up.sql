@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS MfaRecoveryCodes (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    CodeHash varchar(64) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    UsedAt bigint(20) NOT NULL,
+    PRIMARY KEY (Id),
+    KEY idx_mfarecoverycodes_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
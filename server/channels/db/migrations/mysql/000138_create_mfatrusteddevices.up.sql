@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS MfaTrustedDevices (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    ExpiresAt bigint(20) NOT NULL,
+    LastUsedAt bigint(20) NOT NULL,
+    PRIMARY KEY (Id),
+    KEY idx_mfatrusteddevices_userid (UserId),
+    KEY idx_mfatrusteddevices_expiresat (ExpiresAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS PasswordHistory (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    PasswordHash varchar(128) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    PRIMARY KEY (Id),
+    KEY idx_passwordhistory_userid_createat (UserId, CreateAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS LoginDevices (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Fingerprint varchar(64) NOT NULL,
+    Name varchar(512) NOT NULL,
+    Country varchar(2) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    LastSeenAt bigint(20) NOT NULL,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_logindevices_userid_fingerprint_country (UserId, Fingerprint, Country)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
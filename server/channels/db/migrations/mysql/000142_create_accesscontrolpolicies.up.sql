@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS AccessControlPolicies (
+    ChannelId varchar(26) NOT NULL,
+    Expression text NOT NULL,
+    CreatorId varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    PRIMARY KEY (ChannelId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS CustomProfileFields (
+    Id varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    DisplayName varchar(64) NOT NULL,
+    Type varchar(32) NOT NULL,
+    Options text NOT NULL,
+    Visibility varchar(32) NOT NULL,
+    AdminManaged tinyint(1) NOT NULL DEFAULT 0,
+    LdapAttribute varchar(128) NOT NULL DEFAULT '',
+    SamlAttribute varchar(128) NOT NULL DEFAULT '',
+    SortOrder int NOT NULL DEFAULT 0,
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    DeleteAt bigint(20) NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_customprofilefields_name_deleteat (Name, DeleteAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS CustomProfileValues (
+    FieldId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Value text NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    PRIMARY KEY (FieldId, UserId),
+    KEY idx_customprofilevalues_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
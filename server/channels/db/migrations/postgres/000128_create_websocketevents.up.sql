@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS websocketevents (
+    seq bigserial PRIMARY KEY,
+    createat bigint NOT NULL,
+    userid varchar(26) NOT NULL DEFAULT '',
+    event text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_websocketevents_createat ON websocketevents (createat);
+CREATE INDEX IF NOT EXISTS idx_websocketevents_userid_seq ON websocketevents (userid, seq);
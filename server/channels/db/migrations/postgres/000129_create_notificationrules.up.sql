@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS notificationrules (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    name varchar(64) NOT NULL,
+    action varchar(16) NOT NULL,
+    keywords text NOT NULL,
+    patterns text NOT NULL,
+    teamids text NOT NULL,
+    channelids text NOT NULL,
+    schedule jsonb,
+    bypassurgent boolean NOT NULL DEFAULT false,
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notificationrules_userid ON notificationrules (userid);
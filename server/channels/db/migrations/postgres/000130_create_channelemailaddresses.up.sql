@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS channelemailaddresses (
+    channelid varchar(26) PRIMARY KEY,
+    token varchar(26) NOT NULL,
+    webhookid varchar(26) NOT NULL,
+    allowedsenders text NOT NULL,
+    creatorid varchar(26) NOT NULL,
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_channelemailaddresses_token ON channelemailaddresses (token);
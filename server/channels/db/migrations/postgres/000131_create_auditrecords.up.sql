@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS auditrecords (
+    id varchar(26) PRIMARY KEY,
+    chainid varchar(26) NOT NULL,
+    sequence bigint NOT NULL,
+    createat bigint NOT NULL,
+    eventname varchar(256) NOT NULL,
+    status varchar(32) NOT NULL,
+    userid varchar(26) NOT NULL,
+    sessionid varchar(26) NOT NULL,
+    ipaddress varchar(64) NOT NULL,
+    objecttype varchar(64) NOT NULL,
+    prevhash varchar(64) NOT NULL,
+    hash varchar(64) NOT NULL,
+    signature text NOT NULL,
+    data text NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auditrecords_chainid_sequence ON auditrecords (chainid, sequence);
+CREATE INDEX IF NOT EXISTS idx_auditrecords_createat ON auditrecords (createat);
+CREATE INDEX IF NOT EXISTS idx_auditrecords_userid_createat ON auditrecords (userid, createat);
+CREATE INDEX IF NOT EXISTS idx_auditrecords_eventname_createat ON auditrecords (eventname, createat);
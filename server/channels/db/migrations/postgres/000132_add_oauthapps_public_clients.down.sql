@@ -0,0 +1,2 @@
+ALTER TABLE oauthapps DROP COLUMN IF EXISTS botuserid;
+ALTER TABLE oauthapps DROP COLUMN IF EXISTS ispublic;
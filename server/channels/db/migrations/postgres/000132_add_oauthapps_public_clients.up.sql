@@ -0,0 +1,2 @@
+ALTER TABLE oauthapps ADD COLUMN IF NOT EXISTS ispublic boolean NOT NULL DEFAULT false;
+ALTER TABLE oauthapps ADD COLUMN IF NOT EXISTS botuserid varchar(26) NOT NULL DEFAULT '';
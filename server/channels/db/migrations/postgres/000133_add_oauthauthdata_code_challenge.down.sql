@@ -0,0 +1,2 @@
+ALTER TABLE oauthauthdata DROP COLUMN IF EXISTS codechallengemethod;
+ALTER TABLE oauthauthdata DROP COLUMN IF EXISTS codechallenge;
@@ -0,0 +1,2 @@
+ALTER TABLE oauthauthdata ADD COLUMN IF NOT EXISTS codechallenge varchar(128) NOT NULL DEFAULT '';
+ALTER TABLE oauthauthdata ADD COLUMN IF NOT EXISTS codechallengemethod varchar(16) NOT NULL DEFAULT '';
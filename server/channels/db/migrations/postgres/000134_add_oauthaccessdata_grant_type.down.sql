@@ -0,0 +1 @@
+ALTER TABLE oauthaccessdata DROP COLUMN IF EXISTS granttype;
@@ -0,0 +1 @@
+ALTER TABLE oauthaccessdata ADD COLUMN IF NOT EXISTS granttype varchar(64) NOT NULL DEFAULT '';
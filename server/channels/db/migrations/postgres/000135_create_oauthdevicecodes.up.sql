@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS oauthdevicecodes (
+    devicecode varchar(128) PRIMARY KEY,
+    usercode varchar(16) NOT NULL,
+    clientid varchar(26) NOT NULL,
+    userid varchar(26) NOT NULL,
+    scope varchar(128) NOT NULL,
+    status varchar(16) NOT NULL,
+    createat bigint NOT NULL,
+    expiresat bigint NOT NULL,
+    lastpollat bigint NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauthdevicecodes_usercode ON oauthdevicecodes (usercode);
+CREATE INDEX IF NOT EXISTS idx_oauthdevicecodes_expiresat ON oauthdevicecodes (expiresat);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS mfarecoverycodes (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    codehash varchar(64) NOT NULL,
+    createat bigint NOT NULL,
+    usedat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfarecoverycodes_userid ON mfarecoverycodes (userid);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS mfatrusteddevices (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    createat bigint NOT NULL,
+    expiresat bigint NOT NULL,
+    lastusedat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfatrusteddevices_userid ON mfatrusteddevices (userid);
+CREATE INDEX IF NOT EXISTS idx_mfatrusteddevices_expiresat ON mfatrusteddevices (expiresat);
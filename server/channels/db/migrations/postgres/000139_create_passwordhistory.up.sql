@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS passwordhistory (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    passwordhash varchar(128) NOT NULL,
+    createat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_passwordhistory_userid_createat ON passwordhistory (userid, createat);
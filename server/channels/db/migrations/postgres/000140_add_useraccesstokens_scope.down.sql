@@ -0,0 +1,2 @@
+ALTER TABLE useraccesstokens DROP COLUMN IF EXISTS expiresat;
+ALTER TABLE useraccesstokens DROP COLUMN IF EXISTS scope;
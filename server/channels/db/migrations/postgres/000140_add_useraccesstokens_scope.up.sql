@@ -0,0 +1,2 @@
+ALTER TABLE useraccesstokens ADD COLUMN IF NOT EXISTS scope jsonb;
+ALTER TABLE useraccesstokens ADD COLUMN IF NOT EXISTS expiresat bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS logindevices (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    fingerprint varchar(64) NOT NULL,
+    name varchar(512) NOT NULL,
+    country varchar(2) NOT NULL,
+    createat bigint NOT NULL,
+    lastseenat bigint NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_logindevices_userid_fingerprint_country ON logindevices (userid, fingerprint, country);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS accesscontrolpolicies (
+    channelid varchar(26) PRIMARY KEY,
+    expression text NOT NULL,
+    creatorid varchar(26) NOT NULL,
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL
+);
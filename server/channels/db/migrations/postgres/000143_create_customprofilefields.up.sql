@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS customprofilefields (
+    id varchar(26) PRIMARY KEY,
+    name varchar(64) NOT NULL,
+    displayname varchar(64) NOT NULL,
+    type varchar(32) NOT NULL,
+    options text NOT NULL,
+    visibility varchar(32) NOT NULL,
+    adminmanaged boolean NOT NULL DEFAULT false,
+    ldapattribute varchar(128) NOT NULL DEFAULT '',
+    samlattribute varchar(128) NOT NULL DEFAULT '',
+    sortorder integer NOT NULL DEFAULT 0,
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL,
+    deleteat bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customprofilefields_name_deleteat ON customprofilefields (name, deleteat);
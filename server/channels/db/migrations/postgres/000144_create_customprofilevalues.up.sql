@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS customprofilevalues (
+    fieldid varchar(26) NOT NULL,
+    userid varchar(26) NOT NULL,
+    value text NOT NULL,
+    updateat bigint NOT NULL,
+    PRIMARY KEY (fieldid, userid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customprofilevalues_userid ON customprofilevalues (userid);
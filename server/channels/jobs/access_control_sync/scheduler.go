@@ -0,0 +1,22 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package access_control_sync
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+// schedFreq is how often the members who no longer match the policy of their channels
+// are removed, e.g. after their attributes were synchronized from LDAP.
+const schedFreq = 1 * time.Hour
+
+func MakeScheduler(jobServer *jobs.JobServer) *jobs.PeriodicScheduler {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.AccessControlSettings.EnableAttributeBasedAccessControl
+	}
+	return jobs.NewPeriodicScheduler(jobServer, model.JobTypeAccessControlSync, schedFreq, isEnabled)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cleanup_websocket_events
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const (
+	jobName   = "CleanupWebSocketEvents"
+	batchSize = 1000
+	// batchDelay leaves some room to the other queries between batches.
+	batchDelay = 100 * time.Millisecond
+)
+
+func MakeWorker(jobServer *jobs.JobServer) *jobs.SimpleWorker {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.ServiceSettings.EnableWebSocketEventLog
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		retention := time.Duration(*jobServer.Config().ServiceSettings.WebSocketEventLogRetentionMinutes) * time.Minute
+		olderThan := model.GetMillisForTime(time.Now().Add(-retention))
+
+		var total int64
+		for {
+			deleted, err := jobServer.Store.WebSocketEvent().DeleteOlderThan(olderThan, batchSize)
+			if err != nil {
+				return err
+			}
+			total += deleted
+			if deleted < batchSize {
+				break
+			}
+			time.Sleep(batchDelay)
+		}
+
+		logger.Debug("Deleted old WebSocket events", mlog.Int("count", total))
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+	return worker
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cold_storage
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/configservice"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const batchSize = 100
+
+type AppIface interface {
+	configservice.ConfigService
+	FileBackend() filestore.FileBackend
+}
+
+// MakeWorker creates a worker that moves the files older than the configured
+// threshold from the hot storage tier to the cold one, recording the new tier
+// of each file in its FileInfo.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface, s store.Store) *jobs.SimpleWorker {
+	const workerName = "ColdStorage"
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.FileSettings.EnableColdStorage
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		backend, ok := app.FileBackend().(*filestore.TieredFileBackend)
+		if !ok {
+			return errors.New("cold storage is not enabled")
+		}
+
+		rctx := request.EmptyContext(logger)
+		days := *app.Config().FileSettings.ColdStorageAfterDays
+		olderThan := model.GetMillisForTime(time.Now().AddDate(0, 0, -days))
+
+		errs := merror.New()
+		moved := 0
+		var startTime int64
+		startFileID := ""
+		for {
+			files, err := s.FileInfo().GetFilesBatchForColdStorage(olderThan, startTime, startFileID, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				break
+			}
+
+			for _, info := range files {
+				if err := moveToCold(backend, info); err != nil {
+					logger.Warn("Worker: Failed to move file to cold storage", mlog.Err(err), mlog.String("file_id", info.Id))
+					errs.Append(err)
+					continue
+				}
+				if err := s.FileInfo().SetTier(rctx, info.Id, model.FileTierCold); err != nil {
+					errs.Append(err)
+					continue
+				}
+				if info.PostId != "" {
+					s.FileInfo().InvalidateFileInfosForPostCache(info.PostId, info.DeleteAt != 0)
+				}
+				moved++
+			}
+
+			last := files[len(files)-1]
+			startTime, startFileID = last.CreateAt, last.Id
+		}
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["moved_count"] = strconv.Itoa(moved)
+		if appErr := jobServer.UpdateInProgressJobData(job); appErr != nil {
+			logger.Warn("Worker: Failed to update job data", mlog.Err(appErr))
+		}
+
+		return errs.ErrorOrNil()
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
+
+func moveToCold(backend *filestore.TieredFileBackend, info *model.FileInfo) error {
+	for _, path := range []string{info.Path, info.ThumbnailPath, info.PreviewPath} {
+		if path == "" {
+			continue
+		}
+		if err := backend.MoveToCold(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package email_digest
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+// schedFreq is the granularity of the time at which the digests are sent.
+const schedFreq = 15 * time.Minute
+
+func MakeScheduler(jobServer *jobs.JobServer) *jobs.PeriodicScheduler {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.EmailSettings.EnableEmailDigests
+	}
+	return jobs.NewPeriodicScheduler(jobServer, model.JobTypeEmailDigest, schedFreq, isEnabled)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_encryption_key_rotation
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/configservice"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+type AppIface interface {
+	configservice.ConfigService
+	FileBackend() filestore.FileBackend
+}
+
+// MakeWorker creates a worker that re-wraps the data keys of every encrypted
+// file with the active master key. File contents are not rewritten, so the
+// previous master key can be retired once the job has succeeded.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
+	const workerName = "FileEncryptionKeyRotation"
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.FileSettings.EnableEncryption
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		backends := encryptedBackends(app.FileBackend())
+		if len(backends) == 0 {
+			return errors.New("file storage encryption is not enabled")
+		}
+
+		errs := merror.New()
+		filesCount := 0
+		rewrapped := 0
+		for _, backend := range backends {
+			paths, err := backend.ListEncryptedFiles("")
+			if err != nil {
+				return err
+			}
+
+			filesCount += len(paths)
+			for _, path := range paths {
+				ok, err := backend.RewrapKey(path)
+				if err != nil {
+					logger.Warn("Worker: Failed to rewrap file key", mlog.Err(err), mlog.String("path", path))
+					errs.Append(err)
+					continue
+				}
+				if ok {
+					rewrapped++
+				}
+			}
+		}
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["files_count"] = strconv.Itoa(filesCount)
+		job.Data["rewrapped_count"] = strconv.Itoa(rewrapped)
+		if appErr := jobServer.UpdateInProgressJobData(job); appErr != nil {
+			logger.Warn("Worker: Failed to update job data", mlog.Err(appErr))
+		}
+
+		return errs.ErrorOrNil()
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
+
+// encryptedBackends returns the encrypted backends of every storage tier.
+func encryptedBackends(backend filestore.FileBackend) []*filestore.EncryptedFileBackend {
+	switch b := backend.(type) {
+	case *filestore.EncryptedFileBackend:
+		return []*filestore.EncryptedFileBackend{b}
+	case *filestore.EncryptedFileBackendWithLinkGenerator:
+		return []*filestore.EncryptedFileBackend{b.EncryptedFileBackend}
+	case *filestore.TieredFileBackend:
+		return append(encryptedBackends(b.Hot()), encryptedBackends(b.Cold())...)
+	}
+	return nil
+}
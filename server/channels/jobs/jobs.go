@@ -101,8 +101,11 @@ func (srv *JobServer) ClaimJob(job *model.Job) (bool, *model.AppError) {
 		return false, model.NewAppError("ClaimJob", "app.job.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	if updated && srv.metrics != nil {
-		srv.metrics.IncrementJobActive(job.Type)
+	if updated {
+		srv.startJobSpan(job)
+		if srv.metrics != nil {
+			srv.metrics.IncrementJobActive(job.Type)
+		}
 	}
 
 	return updated, nil
@@ -129,11 +132,14 @@ func (srv *JobServer) SetJobSuccess(job *model.Job) *model.AppError {
 	if _, err := srv.Store.Job().UpdateStatus(job.Id, model.JobStatusSuccess); err != nil {
 		return model.NewAppError("SetJobSuccess", "app.job.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
+	srv.endJobSpan(job, model.JobStatusSuccess, nil)
 
 	if srv.metrics != nil {
 		srv.metrics.DecrementJobActive(job.Type)
 	}
 
+	srv.runSuccessHooks(job)
+
 	return nil
 }
 
@@ -143,6 +149,7 @@ func (srv *JobServer) SetJobError(job *model.Job, jobError *model.AppError) *mod
 		if err != nil {
 			return model.NewAppError("SetJobError", "app.job.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
+		srv.endJobSpan(job, model.JobStatusError, nil)
 
 		if srv.metrics != nil {
 			srv.metrics.DecrementJobActive(job.Type)
@@ -180,6 +187,7 @@ func (srv *JobServer) SetJobError(job *model.Job, jobError *model.AppError) *mod
 			return model.NewAppError("SetJobError", "jobs.set_job_error.update.error", nil, "id="+job.Id, http.StatusInternalServerError)
 		}
 	}
+	srv.endJobSpan(job, model.JobStatusError, jobError)
 
 	return nil
 }
@@ -188,6 +196,7 @@ func (srv *JobServer) SetJobCanceled(job *model.Job) *model.AppError {
 	if _, err := srv.Store.Job().UpdateStatus(job.Id, model.JobStatusCanceled); err != nil {
 		return model.NewAppError("SetJobCanceled", "app.job.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
+	srv.endJobSpan(job, model.JobStatusCanceled, nil)
 
 	if srv.metrics != nil {
 		srv.metrics.DecrementJobActive(job.Type)
@@ -200,6 +209,7 @@ func (srv *JobServer) SetJobPending(job *model.Job) *model.AppError {
 	if _, err := srv.Store.Job().UpdateStatus(job.Id, model.JobStatusPending); err != nil {
 		return model.NewAppError("SetJobPending", "app.job.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
+	srv.endJobSpan(job, model.JobStatusPending, nil)
 
 	if srv.metrics != nil {
 		srv.metrics.DecrementJobActive(job.Type)
@@ -237,6 +237,33 @@ func TestSetJobSuccess(t *testing.T) {
 		err := jobServer.SetJobSuccess(job)
 		require.Nil(t, err)
 	})
+
+	t.Run("success hooks", func(t *testing.T) {
+		jobServer, mockStore := makeTeamEditionJobServer(t)
+
+		job := &model.Job{
+			Id:   "job_id",
+			Type: "job_type",
+		}
+
+		var succeeded []string
+		jobServer.RegisterSuccessHook("job_type", func(job *model.Job) {
+			succeeded = append(succeeded, job.Id)
+		})
+		jobServer.RegisterSuccessHook("other_job_type", func(job *model.Job) {
+			require.Fail(t, "hook of another job type called")
+		})
+
+		mockStore.JobStore.On("UpdateStatus", "job_id", model.JobStatusSuccess).Return(job, &model.AppError{Message: "message"}).Once()
+		err := jobServer.SetJobSuccess(job)
+		expectErrorId(t, "app.job.update.app_error", err)
+		require.Empty(t, succeeded)
+
+		mockStore.JobStore.On("UpdateStatus", "job_id", model.JobStatusSuccess).Return(job, nil).Once()
+		err = jobServer.SetJobSuccess(job)
+		require.Nil(t, err)
+		require.Equal(t, []string{"job_id"}, succeeded)
+	})
 }
 
 func TestSetJobError(t *testing.T) {
@@ -28,6 +28,9 @@ type S3PathMigrationWorker struct {
 	logger      mlog.LoggerIFace
 	store       store.Store
 	fileBackend *filestore.S3FileBackend
+	// encrypted is set when the files are encrypted, in which case their key
+	// files are migrated along with them.
+	encrypted bool
 
 	stop    chan struct{}
 	stopped chan bool
@@ -35,6 +38,12 @@ type S3PathMigrationWorker struct {
 }
 
 func MakeWorker(jobServer *jobs.JobServer, store store.Store, fileBackend filestore.FileBackend) *S3PathMigrationWorker {
+	// The files are migrated in the backend holding them.
+	encrypted := false
+	if b, ok := fileBackend.(interface{ Unwrap() filestore.FileBackend }); ok {
+		fileBackend = b.Unwrap()
+		encrypted = true
+	}
 	// If the type cast fails, it will be nil
 	// which is checked later.
 	s3Backend, _ := fileBackend.(*filestore.S3FileBackend)
@@ -45,6 +54,7 @@ func MakeWorker(jobServer *jobs.JobServer, store store.Store, fileBackend filest
 		logger:      jobServer.Logger().With(mlog.String("worker_name", workerName)),
 		store:       store,
 		fileBackend: s3Backend,
+		encrypted:   encrypted,
 		stop:        make(chan struct{}),
 		stopped:     make(chan bool, 1),
 		jobs:        make(chan model.Job),
@@ -199,17 +209,17 @@ func (worker *S3PathMigrationWorker) DoJob(job *model.Job) {
 				logger.Debug("Processing file ID", mlog.String("id", f.Id))
 				// We do not fail the job if a single image failed to encode.
 				if f.Path != "" {
-					if err := worker.fileBackend.DecodeFilePathIfNeeded(f.Path); err != nil {
+					if err := worker.decodeFilePathIfNeeded(f.Path); err != nil {
 						logger.Warn("Failed to encode S3 file path", mlog.String("path", f.Path), mlog.String("id", f.Id), mlog.Err(err))
 					}
 				}
 				if f.PreviewPath != "" {
-					if err := worker.fileBackend.DecodeFilePathIfNeeded(f.PreviewPath); err != nil {
+					if err := worker.decodeFilePathIfNeeded(f.PreviewPath); err != nil {
 						logger.Warn("Failed to encode S3 file path", mlog.String("path", f.PreviewPath), mlog.String("id", f.Id), mlog.Err(err))
 					}
 				}
 				if f.ThumbnailPath != "" {
-					if err := worker.fileBackend.DecodeFilePathIfNeeded(f.ThumbnailPath); err != nil {
+					if err := worker.decodeFilePathIfNeeded(f.ThumbnailPath); err != nil {
 						logger.Warn("Failed to encode S3 file path", mlog.String("path", f.ThumbnailPath), mlog.String("id", f.Id), mlog.Err(err))
 					}
 				}
@@ -263,3 +273,15 @@ func (worker *S3PathMigrationWorker) setJobError(logger mlog.LoggerIFace, job *m
 		logger.Error("S3PathMigrationWorker: Failed to set job error", mlog.Err(err))
 	}
 }
+
+// decodeFilePathIfNeeded decodes the path of a file, along with the path of its
+// key file when the files are encrypted.
+func (worker *S3PathMigrationWorker) decodeFilePathIfNeeded(path string) error {
+	if err := worker.fileBackend.DecodeFilePathIfNeeded(path); err != nil {
+		return err
+	}
+	if worker.encrypted {
+		return worker.fileBackend.DecodeFilePathIfNeeded(path + filestore.EncryptionKeyFileSuffix)
+	}
+	return nil
+}
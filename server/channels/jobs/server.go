@@ -24,6 +24,12 @@ type JobServer struct {
 	mut        sync.Mutex
 	workers    *Workers
 	schedulers *Schedulers
+
+	// jobSpans holds the spans of the jobs in progress, by job id.
+	jobSpans sync.Map
+
+	// successHooks holds the functions called after a job succeeds, by job type.
+	successHooks map[string][]func(job *model.Job)
 }
 
 func NewJobServer(configService configservice.ConfigService, store store.Store, metrics einterfaces.MetricsInterface, logger mlog.LoggerIFace) *JobServer {
@@ -76,6 +82,27 @@ func (srv *JobServer) RegisterJobType(name string, worker model.Worker, schedule
 	}
 }
 
+// RegisterSuccessHook registers a function called after each job of the given type
+// succeeds, e.g. to follow up on a job whose worker is implemented elsewhere.
+func (srv *JobServer) RegisterSuccessHook(name string, hook func(job *model.Job)) {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	if srv.successHooks == nil {
+		srv.successHooks = make(map[string][]func(job *model.Job))
+	}
+	srv.successHooks[name] = append(srv.successHooks[name], hook)
+}
+
+func (srv *JobServer) runSuccessHooks(job *model.Job) {
+	srv.mut.Lock()
+	hooks := srv.successHooks[job.Type]
+	srv.mut.Unlock()
+
+	for _, hook := range hooks {
+		hook(job)
+	}
+}
+
 func (srv *JobServer) StartWorkers() error {
 	srv.mut.Lock()
 	defer srv.mut.Unlock()
@@ -0,0 +1,45 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package jobs
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/platform/services/tracing"
+)
+
+// startJobSpan starts the span of a job claimed by a worker of this server. The span is
+// ended when the status of the job is set once the job is done. No span is started when
+// tracing is disabled.
+func (srv *JobServer) startJobSpan(job *model.Job) {
+	span, _ := tracing.StartRootSpanByContext(context.Background(), "job:"+job.Type, trace.SpanKindInternal)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("job_id", job.Id),
+		attribute.String("job_type", job.Type),
+	)
+	srv.jobSpans.Store(job.Id, span)
+}
+
+// endJobSpan ends the span of a job, if any, with the status of the job.
+func (srv *JobServer) endJobSpan(job *model.Job, status string, err error) {
+	value, ok := srv.jobSpans.LoadAndDelete(job.Id)
+	if !ok {
+		return
+	}
+
+	span := value.(trace.Span)
+	span.SetAttributes(attribute.String("job_status", status))
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+	span.End()
+}
@@ -13,21 +13,23 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/platform/services/tracing"
-	"github.com/opentracing/opentracing-go/ext"
-	spanlog "github.com/opentracing/opentracing-go/log"
 )
 
 type OpenTracingLayer struct {
 	store.Store
+	AccessControlPolicyStore        store.AccessControlPolicyStore
 	AuditStore                      store.AuditStore
+	AuditRecordStore                store.AuditRecordStore
 	BotStore                        store.BotStore
 	ChannelStore                    store.ChannelStore
 	ChannelBookmarkStore            store.ChannelBookmarkStore
+	ChannelEmailAddressStore        store.ChannelEmailAddressStore
 	ChannelMemberHistoryStore       store.ChannelMemberHistoryStore
 	ClusterDiscoveryStore           store.ClusterDiscoveryStore
 	CommandStore                    store.CommandStore
 	CommandWebhookStore             store.CommandWebhookStore
 	ComplianceStore                 store.ComplianceStore
+	CustomProfileAttributeStore     store.CustomProfileAttributeStore
 	DesktopTokensStore              store.DesktopTokensStore
 	DraftStore                      store.DraftStore
 	EmojiStore                      store.EmojiStore
@@ -36,9 +38,14 @@ type OpenTracingLayer struct {
 	JobStore                        store.JobStore
 	LicenseStore                    store.LicenseStore
 	LinkMetadataStore               store.LinkMetadataStore
+	LoginDeviceStore                store.LoginDeviceStore
+	MfaRecoveryCodeStore            store.MfaRecoveryCodeStore
+	MfaTrustedDeviceStore           store.MfaTrustedDeviceStore
+	NotificationRuleStore           store.NotificationRuleStore
 	NotifyAdminStore                store.NotifyAdminStore
 	OAuthStore                      store.OAuthStore
 	OutgoingOAuthConnectionStore    store.OutgoingOAuthConnectionStore
+	PasswordHistoryStore            store.PasswordHistoryStore
 	PluginStore                     store.PluginStore
 	PostStore                       store.PostStore
 	PostAcknowledgementStore        store.PostAcknowledgementStore
@@ -63,13 +70,23 @@ type OpenTracingLayer struct {
 	UserStore                       store.UserStore
 	UserAccessTokenStore            store.UserAccessTokenStore
 	UserTermsOfServiceStore         store.UserTermsOfServiceStore
+	WebAuthnCredentialStore         store.WebAuthnCredentialStore
+	WebSocketEventStore             store.WebSocketEventStore
 	WebhookStore                    store.WebhookStore
 }
 
+func (s *OpenTracingLayer) AccessControlPolicy() store.AccessControlPolicyStore {
+	return s.AccessControlPolicyStore
+}
+
 func (s *OpenTracingLayer) Audit() store.AuditStore {
 	return s.AuditStore
 }
 
+func (s *OpenTracingLayer) AuditRecord() store.AuditRecordStore {
+	return s.AuditRecordStore
+}
+
 func (s *OpenTracingLayer) Bot() store.BotStore {
 	return s.BotStore
 }
@@ -82,6 +99,10 @@ func (s *OpenTracingLayer) ChannelBookmark() store.ChannelBookmarkStore {
 	return s.ChannelBookmarkStore
 }
 
+func (s *OpenTracingLayer) ChannelEmailAddress() store.ChannelEmailAddressStore {
+	return s.ChannelEmailAddressStore
+}
+
 func (s *OpenTracingLayer) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	return s.ChannelMemberHistoryStore
 }
@@ -102,6 +123,10 @@ func (s *OpenTracingLayer) Compliance() store.ComplianceStore {
 	return s.ComplianceStore
 }
 
+func (s *OpenTracingLayer) CustomProfileAttribute() store.CustomProfileAttributeStore {
+	return s.CustomProfileAttributeStore
+}
+
 func (s *OpenTracingLayer) DesktopTokens() store.DesktopTokensStore {
 	return s.DesktopTokensStore
 }
@@ -134,6 +159,22 @@ func (s *OpenTracingLayer) LinkMetadata() store.LinkMetadataStore {
 	return s.LinkMetadataStore
 }
 
+func (s *OpenTracingLayer) LoginDevice() store.LoginDeviceStore {
+	return s.LoginDeviceStore
+}
+
+func (s *OpenTracingLayer) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	return s.MfaRecoveryCodeStore
+}
+
+func (s *OpenTracingLayer) MfaTrustedDevice() store.MfaTrustedDeviceStore {
+	return s.MfaTrustedDeviceStore
+}
+
+func (s *OpenTracingLayer) NotificationRule() store.NotificationRuleStore {
+	return s.NotificationRuleStore
+}
+
 func (s *OpenTracingLayer) NotifyAdmin() store.NotifyAdminStore {
 	return s.NotifyAdminStore
 }
@@ -146,6 +187,10 @@ func (s *OpenTracingLayer) OutgoingOAuthConnection() store.OutgoingOAuthConnecti
 	return s.OutgoingOAuthConnectionStore
 }
 
+func (s *OpenTracingLayer) PasswordHistory() store.PasswordHistoryStore {
+	return s.PasswordHistoryStore
+}
+
 func (s *OpenTracingLayer) Plugin() store.PluginStore {
 	return s.PluginStore
 }
@@ -242,15 +287,33 @@ func (s *OpenTracingLayer) UserTermsOfService() store.UserTermsOfServiceStore {
 	return s.UserTermsOfServiceStore
 }
 
+func (s *OpenTracingLayer) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return s.WebAuthnCredentialStore
+}
+
+func (s *OpenTracingLayer) WebSocketEvent() store.WebSocketEventStore {
+	return s.WebSocketEventStore
+}
+
 func (s *OpenTracingLayer) Webhook() store.WebhookStore {
 	return s.WebhookStore
 }
 
+type OpenTracingLayerAccessControlPolicyStore struct {
+	store.AccessControlPolicyStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerAuditStore struct {
 	store.AuditStore
 	Root *OpenTracingLayer
 }
 
+type OpenTracingLayerAuditRecordStore struct {
+	store.AuditRecordStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerBotStore struct {
 	store.BotStore
 	Root *OpenTracingLayer
@@ -266,6 +329,11 @@ type OpenTracingLayerChannelBookmarkStore struct {
 	Root *OpenTracingLayer
 }
 
+type OpenTracingLayerChannelEmailAddressStore struct {
+	store.ChannelEmailAddressStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerChannelMemberHistoryStore struct {
 	store.ChannelMemberHistoryStore
 	Root *OpenTracingLayer
@@ -291,6 +359,11 @@ type OpenTracingLayerComplianceStore struct {
 	Root *OpenTracingLayer
 }
 
+type OpenTracingLayerCustomProfileAttributeStore struct {
+	store.CustomProfileAttributeStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerDesktopTokensStore struct {
 	store.DesktopTokensStore
 	Root *OpenTracingLayer
@@ -331,6 +404,26 @@ type OpenTracingLayerLinkMetadataStore struct {
 	Root *OpenTracingLayer
 }
 
+type OpenTracingLayerLoginDeviceStore struct {
+	store.LoginDeviceStore
+	Root *OpenTracingLayer
+}
+
+type OpenTracingLayerMfaRecoveryCodeStore struct {
+	store.MfaRecoveryCodeStore
+	Root *OpenTracingLayer
+}
+
+type OpenTracingLayerMfaTrustedDeviceStore struct {
+	store.MfaTrustedDeviceStore
+	Root *OpenTracingLayer
+}
+
+type OpenTracingLayerNotificationRuleStore struct {
+	store.NotificationRuleStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerNotifyAdminStore struct {
 	store.NotifyAdminStore
 	Root *OpenTracingLayer
@@ -346,6 +439,11 @@ type OpenTracingLayerOutgoingOAuthConnectionStore struct {
 	Root *OpenTracingLayer
 }
 
+type OpenTracingLayerPasswordHistoryStore struct {
+	store.PasswordHistoryStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerPluginStore struct {
 	store.PluginStore
 	Root *OpenTracingLayer
@@ -466,11 +564,89 @@ type OpenTracingLayerUserTermsOfServiceStore struct {
 	Root *OpenTracingLayer
 }
 
+type OpenTracingLayerWebAuthnCredentialStore struct {
+	store.WebAuthnCredentialStore
+	Root *OpenTracingLayer
+}
+
+type OpenTracingLayerWebSocketEventStore struct {
+	store.WebSocketEventStore
+	Root *OpenTracingLayer
+}
+
 type OpenTracingLayerWebhookStore struct {
 	store.WebhookStore
 	Root *OpenTracingLayer
 }
 
+func (s *OpenTracingLayerAccessControlPolicyStore) Delete(channelID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AccessControlPolicyStore.Delete")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.AccessControlPolicyStore.Delete(channelID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerAccessControlPolicyStore) Get(channelID string) (*model.AccessControlPolicy, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AccessControlPolicyStore.Get")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.AccessControlPolicyStore.Get(channelID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerAccessControlPolicyStore) GetAll(afterChannelID string, limit int) ([]*model.AccessControlPolicy, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AccessControlPolicyStore.GetAll")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.AccessControlPolicyStore.GetAll(afterChannelID, limit)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerAccessControlPolicyStore) Save(policy *model.AccessControlPolicy) (*model.AccessControlPolicy, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AccessControlPolicyStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.AccessControlPolicyStore.Save(policy)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
 func (s *OpenTracingLayerAuditStore) Get(user_id string, offset int, limit int) (model.Audits, error) {
 	origCtx := s.Root.Store.Context()
 	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AuditStore.Get")
@@ -479,11 +655,10 @@ func (s *OpenTracingLayerAuditStore) Get(user_id string, offset int, limit int)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.AuditStore.Get(user_id, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -497,11 +672,10 @@ func (s *OpenTracingLayerAuditStore) PermanentDeleteByUser(userID string) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.AuditStore.PermanentDeleteByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -515,16 +689,49 @@ func (s *OpenTracingLayerAuditStore) Save(audit *model.Audit) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.AuditStore.Save(audit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
 }
 
+func (s *OpenTracingLayerAuditRecordStore) Save(record *model.AuditRecord) (*model.AuditRecord, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AuditRecordStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.AuditRecordStore.Save(record)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerAuditRecordStore) Search(opts model.AuditRecordSearchOptions) ([]*model.AuditRecord, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "AuditRecordStore.Search")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.AuditRecordStore.Search(opts)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
 func (s *OpenTracingLayerBotStore) Get(userID string, includeDeleted bool) (*model.Bot, error) {
 	origCtx := s.Root.Store.Context()
 	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "BotStore.Get")
@@ -533,11 +740,10 @@ func (s *OpenTracingLayerBotStore) Get(userID string, includeDeleted bool) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.BotStore.Get(userID, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -551,11 +757,10 @@ func (s *OpenTracingLayerBotStore) GetAll(options *model.BotGetOptions) ([]*mode
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.BotStore.GetAll(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -569,11 +774,10 @@ func (s *OpenTracingLayerBotStore) PermanentDelete(userID string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.BotStore.PermanentDelete(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -587,11 +791,10 @@ func (s *OpenTracingLayerBotStore) Save(bot *model.Bot) (*model.Bot, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.BotStore.Save(bot)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -605,11 +808,10 @@ func (s *OpenTracingLayerBotStore) Update(bot *model.Bot) (*model.Bot, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.BotStore.Update(bot)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -623,11 +825,10 @@ func (s *OpenTracingLayerChannelStore) AnalyticsDeletedTypeCount(teamID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.AnalyticsDeletedTypeCount(teamID, channelType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -641,11 +842,10 @@ func (s *OpenTracingLayerChannelStore) AnalyticsTypeCount(teamID string, channel
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.AnalyticsTypeCount(teamID, channelType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -659,11 +859,10 @@ func (s *OpenTracingLayerChannelStore) Autocomplete(rctx request.CTX, userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.Autocomplete(rctx, userID, term, includeDeleted, isGuest)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -677,11 +876,10 @@ func (s *OpenTracingLayerChannelStore) AutocompleteInTeam(rctx request.CTX, team
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.AutocompleteInTeam(rctx, teamID, userID, term, includeDeleted, isGuest)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -695,11 +893,10 @@ func (s *OpenTracingLayerChannelStore) AutocompleteInTeamForSearch(teamID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.AutocompleteInTeamForSearch(teamID, userID, term, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -713,11 +910,10 @@ func (s *OpenTracingLayerChannelStore) ClearAllCustomRoleAssignments() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.ClearAllCustomRoleAssignments()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -731,7 +927,7 @@ func (s *OpenTracingLayerChannelStore) ClearCaches() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.ClearCaches()
 
 }
@@ -744,7 +940,7 @@ func (s *OpenTracingLayerChannelStore) ClearMembersForUserCache() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.ClearMembersForUserCache()
 
 }
@@ -757,11 +953,10 @@ func (s *OpenTracingLayerChannelStore) ClearSidebarOnTeamLeave(userID string, te
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.ClearSidebarOnTeamLeave(userID, teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -775,11 +970,10 @@ func (s *OpenTracingLayerChannelStore) CountPostsAfter(channelID string, timesta
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ChannelStore.CountPostsAfter(channelID, timestamp, excludedUserID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -793,11 +987,10 @@ func (s *OpenTracingLayerChannelStore) CountUrgentPostsAfter(channelID string, t
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.CountUrgentPostsAfter(channelID, timestamp, excludedUserID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -811,11 +1004,10 @@ func (s *OpenTracingLayerChannelStore) CreateDirectChannel(ctx request.CTX, user
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.CreateDirectChannel(ctx, userID, otherUserID, channelOptions...)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -829,11 +1021,10 @@ func (s *OpenTracingLayerChannelStore) CreateInitialSidebarCategories(c request.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.CreateInitialSidebarCategories(c, userID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -847,11 +1038,10 @@ func (s *OpenTracingLayerChannelStore) CreateSidebarCategory(userID string, team
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.CreateSidebarCategory(userID, teamID, newCategory)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -865,11 +1055,10 @@ func (s *OpenTracingLayerChannelStore) Delete(channelID string, timestamp int64)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.Delete(channelID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -883,11 +1072,10 @@ func (s *OpenTracingLayerChannelStore) DeleteAllSidebarChannelForChannel(channel
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.DeleteAllSidebarChannelForChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -901,11 +1089,10 @@ func (s *OpenTracingLayerChannelStore) DeleteSidebarCategory(categoryID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.DeleteSidebarCategory(categoryID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -919,11 +1106,10 @@ func (s *OpenTracingLayerChannelStore) DeleteSidebarChannelsByPreferences(prefer
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.DeleteSidebarChannelsByPreferences(preferences)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -937,11 +1123,10 @@ func (s *OpenTracingLayerChannelStore) Get(id string, allowFromCache bool) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.Get(id, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -955,11 +1140,10 @@ func (s *OpenTracingLayerChannelStore) GetAll(teamID string) ([]*model.Channel,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAll(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -973,11 +1157,10 @@ func (s *OpenTracingLayerChannelStore) GetAllChannelMemberIdsByChannelId(id stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllChannelMemberIdsByChannelId(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -991,11 +1174,10 @@ func (s *OpenTracingLayerChannelStore) GetAllChannelMembersForUser(ctx request.C
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllChannelMembersForUser(ctx, userID, allowFromCache, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1009,11 +1191,10 @@ func (s *OpenTracingLayerChannelStore) GetAllChannelMembersNotifyPropsForChannel
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllChannelMembersNotifyPropsForChannel(channelID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1027,11 +1208,10 @@ func (s *OpenTracingLayerChannelStore) GetAllChannels(page int, perPage int, opt
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllChannels(page, perPage, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1045,11 +1225,10 @@ func (s *OpenTracingLayerChannelStore) GetAllChannelsCount(opts store.ChannelSea
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllChannelsCount(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1063,11 +1242,10 @@ func (s *OpenTracingLayerChannelStore) GetAllChannelsForExportAfter(limit int, a
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllChannelsForExportAfter(limit, afterID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1081,11 +1259,10 @@ func (s *OpenTracingLayerChannelStore) GetAllDirectChannelsForExportAfter(limit
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetAllDirectChannelsForExportAfter(limit, afterID, includeArchivedChannels)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1099,11 +1276,10 @@ func (s *OpenTracingLayerChannelStore) GetByName(team_id string, name string, al
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetByName(team_id, name, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1117,11 +1293,10 @@ func (s *OpenTracingLayerChannelStore) GetByNameIncludeDeleted(team_id string, n
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetByNameIncludeDeleted(team_id, name, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1135,11 +1310,10 @@ func (s *OpenTracingLayerChannelStore) GetByNames(team_id string, names []string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetByNames(team_id, names, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1153,11 +1327,10 @@ func (s *OpenTracingLayerChannelStore) GetByNamesIncludeDeleted(team_id string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetByNamesIncludeDeleted(team_id, names, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1171,11 +1344,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelCounts(teamID string, userID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelCounts(teamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1189,11 +1361,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelMembersForExport(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelMembersForExport(userID, teamID, includeArchivedChannel)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1207,11 +1378,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelMembersTimezones(channelID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelMembersTimezones(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1225,11 +1395,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelUnread(channelID string, userID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelUnread(channelID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1243,11 +1412,10 @@ func (s *OpenTracingLayerChannelStore) GetChannels(teamID string, userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannels(teamID, userID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1261,11 +1429,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsBatchForIndexing(startTime int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelsBatchForIndexing(startTime, startChannelID, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1279,11 +1446,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsByIds(channelIds []string, inc
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelsByIds(channelIds, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1297,11 +1463,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsByScheme(schemeID string, offs
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelsByScheme(schemeID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1315,11 +1480,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsByUser(userID string, includeD
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelsByUser(userID, includeDeleted, lastDeleteAt, pageSize, fromChannelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1333,11 +1497,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsMemberCount(channelIDs []strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelsMemberCount(channelIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1351,11 +1514,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsWithTeamDataByIds(channelIds [
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetChannelsWithTeamDataByIds(channelIds, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1369,11 +1531,10 @@ func (s *OpenTracingLayerChannelStore) GetChannelsWithUnreadsAndWithMentions(ctx
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, resultVar2, err := s.ChannelStore.GetChannelsWithUnreadsAndWithMentions(ctx, channelIDs, userID, userNotifyProps)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, resultVar2, err
@@ -1387,11 +1548,10 @@ func (s *OpenTracingLayerChannelStore) GetDeleted(team_id string, offset int, li
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetDeleted(team_id, offset, limit, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1405,11 +1565,10 @@ func (s *OpenTracingLayerChannelStore) GetDeletedByName(team_id string, name str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetDeletedByName(team_id, name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1423,11 +1582,10 @@ func (s *OpenTracingLayerChannelStore) GetFileCount(channelID string) (int64, er
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetFileCount(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1441,11 +1599,10 @@ func (s *OpenTracingLayerChannelStore) GetForPost(postID string) (*model.Channel
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetForPost(postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1459,11 +1616,10 @@ func (s *OpenTracingLayerChannelStore) GetGuestCount(channelID string, allowFrom
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetGuestCount(channelID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1477,11 +1633,10 @@ func (s *OpenTracingLayerChannelStore) GetMany(ids []string, allowFromCache bool
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMany(ids, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1495,11 +1650,10 @@ func (s *OpenTracingLayerChannelStore) GetMember(ctx context.Context, channelID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMember(ctx, channelID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1513,11 +1667,10 @@ func (s *OpenTracingLayerChannelStore) GetMemberCount(channelID string, allowFro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMemberCount(channelID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1531,7 +1684,7 @@ func (s *OpenTracingLayerChannelStore) GetMemberCountFromCache(channelID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result := s.ChannelStore.GetMemberCountFromCache(channelID)
 	return result
 }
@@ -1544,11 +1697,10 @@ func (s *OpenTracingLayerChannelStore) GetMemberCountsByGroup(ctx context.Contex
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMemberCountsByGroup(ctx, channelID, includeTimezones)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1562,11 +1714,10 @@ func (s *OpenTracingLayerChannelStore) GetMemberForPost(postID string, userID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMemberForPost(postID, userID, includeArchivedChannels)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1580,11 +1731,10 @@ func (s *OpenTracingLayerChannelStore) GetMemberLastViewedAt(ctx context.Context
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMemberLastViewedAt(ctx, channelID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1598,11 +1748,10 @@ func (s *OpenTracingLayerChannelStore) GetMembers(channelID string, offset int,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMembers(channelID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1616,11 +1765,10 @@ func (s *OpenTracingLayerChannelStore) GetMembersByChannelIds(channelIds []strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMembersByChannelIds(channelIds, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1634,11 +1782,10 @@ func (s *OpenTracingLayerChannelStore) GetMembersByIds(channelID string, userIds
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMembersByIds(channelID, userIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1652,11 +1799,10 @@ func (s *OpenTracingLayerChannelStore) GetMembersForUser(teamID string, userID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMembersForUser(teamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1670,11 +1816,10 @@ func (s *OpenTracingLayerChannelStore) GetMembersForUserWithPagination(userID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMembersForUserWithPagination(userID, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1688,11 +1833,10 @@ func (s *OpenTracingLayerChannelStore) GetMembersInfoByChannelIds(channelIDs []s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMembersInfoByChannelIds(channelIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1706,11 +1850,27 @@ func (s *OpenTracingLayerChannelStore) GetMoreChannels(teamID string, userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetMoreChannels(teamID, userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerChannelStore) GetNewPublicChannelsForUser(userID string, since int64, limit int) ([]*model.ChannelWithTeamData, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "ChannelStore.GetNewPublicChannelsForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.ChannelStore.GetNewPublicChannelsForUser(userID, since, limit)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1724,11 +1884,10 @@ func (s *OpenTracingLayerChannelStore) GetPinnedPostCount(channelID string, allo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetPinnedPostCount(channelID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1742,11 +1901,10 @@ func (s *OpenTracingLayerChannelStore) GetPinnedPosts(channelID string) (*model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetPinnedPosts(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1760,11 +1918,10 @@ func (s *OpenTracingLayerChannelStore) GetPrivateChannelsForTeam(teamID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetPrivateChannelsForTeam(teamID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1778,11 +1935,10 @@ func (s *OpenTracingLayerChannelStore) GetPublicChannelsByIdsForTeam(teamID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetPublicChannelsByIdsForTeam(teamID, channelIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1796,11 +1952,10 @@ func (s *OpenTracingLayerChannelStore) GetPublicChannelsForTeam(teamID string, o
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetPublicChannelsForTeam(teamID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1814,11 +1969,10 @@ func (s *OpenTracingLayerChannelStore) GetSidebarCategories(userID string, opts
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetSidebarCategories(userID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1832,11 +1986,10 @@ func (s *OpenTracingLayerChannelStore) GetSidebarCategoriesForTeamForUser(userID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetSidebarCategoriesForTeamForUser(userID, teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1850,11 +2003,10 @@ func (s *OpenTracingLayerChannelStore) GetSidebarCategory(categoryID string) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetSidebarCategory(categoryID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1868,11 +2020,10 @@ func (s *OpenTracingLayerChannelStore) GetSidebarCategoryOrder(userID string, te
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetSidebarCategoryOrder(userID, teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1886,11 +2037,10 @@ func (s *OpenTracingLayerChannelStore) GetTeamChannels(teamID string) (model.Cha
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetTeamChannels(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1904,11 +2054,10 @@ func (s *OpenTracingLayerChannelStore) GetTeamForChannel(channelID string) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetTeamForChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1922,11 +2071,10 @@ func (s *OpenTracingLayerChannelStore) GetTeamMembersForChannel(channelID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GetTeamMembersForChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1940,11 +2088,10 @@ func (s *OpenTracingLayerChannelStore) GroupSyncedChannelCount() (int64, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.GroupSyncedChannelCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -1958,11 +2105,10 @@ func (s *OpenTracingLayerChannelStore) IncrementMentionCount(channelID string, u
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.IncrementMentionCount(channelID, userIDs, isRoot, isUrgent)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -1976,7 +2122,7 @@ func (s *OpenTracingLayerChannelStore) InvalidateAllChannelMembersForUser(userID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidateAllChannelMembersForUser(userID)
 
 }
@@ -1989,7 +2135,7 @@ func (s *OpenTracingLayerChannelStore) InvalidateCacheForChannelMembersNotifyPro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidateCacheForChannelMembersNotifyProps(channelID)
 
 }
@@ -2002,7 +2148,7 @@ func (s *OpenTracingLayerChannelStore) InvalidateChannel(id string) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidateChannel(id)
 
 }
@@ -2015,7 +2161,7 @@ func (s *OpenTracingLayerChannelStore) InvalidateChannelByName(teamID string, na
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidateChannelByName(teamID, name)
 
 }
@@ -2028,7 +2174,7 @@ func (s *OpenTracingLayerChannelStore) InvalidateGuestCount(channelID string) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidateGuestCount(channelID)
 
 }
@@ -2041,7 +2187,7 @@ func (s *OpenTracingLayerChannelStore) InvalidateMemberCount(channelID string) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidateMemberCount(channelID)
 
 }
@@ -2054,7 +2200,7 @@ func (s *OpenTracingLayerChannelStore) InvalidatePinnedPostCount(channelID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.ChannelStore.InvalidatePinnedPostCount(channelID)
 
 }
@@ -2067,11 +2213,10 @@ func (s *OpenTracingLayerChannelStore) MigrateChannelMembers(fromChannelID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.MigrateChannelMembers(fromChannelID, fromUserID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2085,11 +2230,10 @@ func (s *OpenTracingLayerChannelStore) PatchMultipleMembersNotifyProps(members [
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.PatchMultipleMembersNotifyProps(members, notifyProps)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2103,11 +2247,10 @@ func (s *OpenTracingLayerChannelStore) PermanentDelete(ctx request.CTX, channelI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.PermanentDelete(ctx, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2121,11 +2264,10 @@ func (s *OpenTracingLayerChannelStore) PermanentDeleteByTeam(teamID string) erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.PermanentDeleteByTeam(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2139,11 +2281,10 @@ func (s *OpenTracingLayerChannelStore) PermanentDeleteMembersByChannel(ctx reque
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.PermanentDeleteMembersByChannel(ctx, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2157,11 +2298,10 @@ func (s *OpenTracingLayerChannelStore) PermanentDeleteMembersByUser(ctx request.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.PermanentDeleteMembersByUser(ctx, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2175,11 +2315,10 @@ func (s *OpenTracingLayerChannelStore) RemoveAllDeactivatedMembers(ctx request.C
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.RemoveAllDeactivatedMembers(ctx, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2193,11 +2332,10 @@ func (s *OpenTracingLayerChannelStore) RemoveMember(ctx request.CTX, channelID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.RemoveMember(ctx, channelID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2211,11 +2349,10 @@ func (s *OpenTracingLayerChannelStore) RemoveMembers(ctx request.CTX, channelID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.RemoveMembers(ctx, channelID, userIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2229,11 +2366,10 @@ func (s *OpenTracingLayerChannelStore) ResetAllChannelSchemes() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.ResetAllChannelSchemes()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2247,11 +2383,10 @@ func (s *OpenTracingLayerChannelStore) Restore(channelID string, timestamp int64
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.Restore(channelID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2265,11 +2400,10 @@ func (s *OpenTracingLayerChannelStore) Save(rctx request.CTX, channel *model.Cha
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.Save(rctx, channel, maxChannelsPerTeam)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2283,11 +2417,10 @@ func (s *OpenTracingLayerChannelStore) SaveDirectChannel(ctx request.CTX, channe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SaveDirectChannel(ctx, channel, member1, member2)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2301,11 +2434,10 @@ func (s *OpenTracingLayerChannelStore) SaveMember(rctx request.CTX, member *mode
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SaveMember(rctx, member)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2319,11 +2451,10 @@ func (s *OpenTracingLayerChannelStore) SaveMultipleMembers(members []*model.Chan
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SaveMultipleMembers(members)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2337,11 +2468,10 @@ func (s *OpenTracingLayerChannelStore) SearchAllChannels(term string, opts store
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ChannelStore.SearchAllChannels(term, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -2355,11 +2485,10 @@ func (s *OpenTracingLayerChannelStore) SearchArchivedInTeam(teamID string, term
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SearchArchivedInTeam(teamID, term, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2373,11 +2502,10 @@ func (s *OpenTracingLayerChannelStore) SearchForUserInTeam(userID string, teamID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SearchForUserInTeam(userID, teamID, term, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2391,11 +2519,10 @@ func (s *OpenTracingLayerChannelStore) SearchGroupChannels(userID string, term s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SearchGroupChannels(userID, term)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2409,11 +2536,10 @@ func (s *OpenTracingLayerChannelStore) SearchInTeam(teamID string, term string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SearchInTeam(teamID, term, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2427,11 +2553,10 @@ func (s *OpenTracingLayerChannelStore) SearchMore(userID string, teamID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.SearchMore(userID, teamID, term)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2445,11 +2570,10 @@ func (s *OpenTracingLayerChannelStore) SetDeleteAt(channelID string, deleteAt in
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.SetDeleteAt(channelID, deleteAt, updateAt)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2463,11 +2587,10 @@ func (s *OpenTracingLayerChannelStore) SetShared(channelId string, shared bool)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.SetShared(channelId, shared)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2481,11 +2604,10 @@ func (s *OpenTracingLayerChannelStore) Update(ctx request.CTX, channel *model.Ch
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.Update(ctx, channel)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2499,11 +2621,10 @@ func (s *OpenTracingLayerChannelStore) UpdateLastViewedAt(channelIds []string, u
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.UpdateLastViewedAt(channelIds, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2517,11 +2638,10 @@ func (s *OpenTracingLayerChannelStore) UpdateLastViewedAtPost(unreadPost *model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.UpdateLastViewedAtPost(unreadPost, userID, mentionCount, mentionCountRoot, urgentMentionCount, setUnreadCountRoot)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2535,11 +2655,10 @@ func (s *OpenTracingLayerChannelStore) UpdateMember(ctx request.CTX, member *mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.UpdateMember(ctx, member)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2553,11 +2672,10 @@ func (s *OpenTracingLayerChannelStore) UpdateMemberNotifyProps(channelID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.UpdateMemberNotifyProps(channelID, userID, props)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2571,11 +2689,10 @@ func (s *OpenTracingLayerChannelStore) UpdateMembersRole(channelID string, userI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.UpdateMembersRole(channelID, userIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2589,11 +2706,10 @@ func (s *OpenTracingLayerChannelStore) UpdateMultipleMembers(members []*model.Ch
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.UpdateMultipleMembers(members)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2607,11 +2723,10 @@ func (s *OpenTracingLayerChannelStore) UpdateSidebarCategories(userID string, te
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ChannelStore.UpdateSidebarCategories(userID, teamID, categories)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -2625,11 +2740,10 @@ func (s *OpenTracingLayerChannelStore) UpdateSidebarCategoryOrder(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.UpdateSidebarCategoryOrder(userID, teamID, categoryOrder)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2643,11 +2757,10 @@ func (s *OpenTracingLayerChannelStore) UpdateSidebarChannelCategoryOnMove(channe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.UpdateSidebarChannelCategoryOnMove(channel, newTeamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2661,11 +2774,10 @@ func (s *OpenTracingLayerChannelStore) UpdateSidebarChannelsByPreferences(prefer
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelStore.UpdateSidebarChannelsByPreferences(preferences)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2679,11 +2791,10 @@ func (s *OpenTracingLayerChannelStore) UserBelongsToChannels(userID string, chan
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelStore.UserBelongsToChannels(userID, channelIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2697,11 +2808,10 @@ func (s *OpenTracingLayerChannelBookmarkStore) Delete(bookmarkId string, deleteF
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelBookmarkStore.Delete(bookmarkId, deleteFile)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2715,11 +2825,10 @@ func (s *OpenTracingLayerChannelBookmarkStore) ErrorIfBookmarkFileInfoAlreadyAtt
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelBookmarkStore.ErrorIfBookmarkFileInfoAlreadyAttached(fileId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2733,11 +2842,10 @@ func (s *OpenTracingLayerChannelBookmarkStore) Get(Id string, includeDeleted boo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelBookmarkStore.Get(Id, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2751,11 +2859,10 @@ func (s *OpenTracingLayerChannelBookmarkStore) GetBookmarksForChannelSince(chann
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelBookmarkStore.GetBookmarksForChannelSince(channelId, since)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2769,11 +2876,10 @@ func (s *OpenTracingLayerChannelBookmarkStore) Save(bookmark *model.ChannelBookm
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelBookmarkStore.Save(bookmark, increaseSortOrder)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2787,11 +2893,10 @@ func (s *OpenTracingLayerChannelBookmarkStore) Update(bookmark *model.ChannelBoo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelBookmarkStore.Update(bookmark)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2805,11 +2910,95 @@ func (s *OpenTracingLayerChannelBookmarkStore) UpdateSortOrder(bookmarkId string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelBookmarkStore.UpdateSortOrder(bookmarkId, channelId, newIndex)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerChannelEmailAddressStore) Delete(channelID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "ChannelEmailAddressStore.Delete")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.ChannelEmailAddressStore.Delete(channelID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerChannelEmailAddressStore) Get(channelID string) (*model.ChannelEmailAddress, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "ChannelEmailAddressStore.Get")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.ChannelEmailAddressStore.Get(channelID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerChannelEmailAddressStore) GetByToken(token string) (*model.ChannelEmailAddress, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "ChannelEmailAddressStore.GetByToken")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.ChannelEmailAddressStore.GetByToken(token)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerChannelEmailAddressStore) Save(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "ChannelEmailAddressStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.ChannelEmailAddressStore.Save(address)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerChannelEmailAddressStore) Update(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "ChannelEmailAddressStore.Update")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.ChannelEmailAddressStore.Update(address)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2823,11 +3012,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) DeleteOrphanedRows(limit int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelMemberHistoryStore.DeleteOrphanedRows(limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2841,11 +3029,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) GetChannelsLeftSince(userID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelMemberHistoryStore.GetChannelsLeftSince(userID, since)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2859,11 +3046,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) GetUsersInChannelDuring(star
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelMemberHistoryStore.GetUsersInChannelDuring(startTime, endTime, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2877,11 +3063,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) LogJoinEvent(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelMemberHistoryStore.LogJoinEvent(userID, channelID, joinTime)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2895,11 +3080,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) LogLeaveEvent(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ChannelMemberHistoryStore.LogLeaveEvent(userID, channelID, leaveTime)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2913,11 +3097,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) PermanentDeleteBatch(endTime
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ChannelMemberHistoryStore.PermanentDeleteBatch(endTime, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2931,11 +3114,10 @@ func (s *OpenTracingLayerChannelMemberHistoryStore) PermanentDeleteBatchForReten
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ChannelMemberHistoryStore.PermanentDeleteBatchForRetentionPolicies(now, globalPolicyEndTime, limit, cursor)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -2949,11 +3131,10 @@ func (s *OpenTracingLayerClusterDiscoveryStore) Cleanup() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ClusterDiscoveryStore.Cleanup()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -2967,11 +3148,10 @@ func (s *OpenTracingLayerClusterDiscoveryStore) Delete(discovery *model.ClusterD
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ClusterDiscoveryStore.Delete(discovery)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -2985,11 +3165,10 @@ func (s *OpenTracingLayerClusterDiscoveryStore) Exists(discovery *model.ClusterD
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ClusterDiscoveryStore.Exists(discovery)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3003,11 +3182,10 @@ func (s *OpenTracingLayerClusterDiscoveryStore) GetAll(discoveryType string, clu
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ClusterDiscoveryStore.GetAll(discoveryType, clusterName)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3021,11 +3199,10 @@ func (s *OpenTracingLayerClusterDiscoveryStore) Save(discovery *model.ClusterDis
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ClusterDiscoveryStore.Save(discovery)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3039,11 +3216,10 @@ func (s *OpenTracingLayerClusterDiscoveryStore) SetLastPingAt(discovery *model.C
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ClusterDiscoveryStore.SetLastPingAt(discovery)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3057,11 +3233,10 @@ func (s *OpenTracingLayerCommandStore) AnalyticsCommandCount(teamID string) (int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandStore.AnalyticsCommandCount(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3075,11 +3250,10 @@ func (s *OpenTracingLayerCommandStore) Delete(commandID string, timestamp int64)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.CommandStore.Delete(commandID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3093,11 +3267,10 @@ func (s *OpenTracingLayerCommandStore) Get(id string) (*model.Command, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3111,11 +3284,10 @@ func (s *OpenTracingLayerCommandStore) GetByTeam(teamID string) ([]*model.Comman
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandStore.GetByTeam(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3129,11 +3301,10 @@ func (s *OpenTracingLayerCommandStore) GetByTrigger(teamID string, trigger strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandStore.GetByTrigger(teamID, trigger)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3147,11 +3318,10 @@ func (s *OpenTracingLayerCommandStore) PermanentDeleteByTeam(teamID string) erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.CommandStore.PermanentDeleteByTeam(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3165,11 +3335,10 @@ func (s *OpenTracingLayerCommandStore) PermanentDeleteByUser(userID string) erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.CommandStore.PermanentDeleteByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3183,11 +3352,10 @@ func (s *OpenTracingLayerCommandStore) Save(webhook *model.Command) (*model.Comm
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandStore.Save(webhook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3201,11 +3369,10 @@ func (s *OpenTracingLayerCommandStore) Update(hook *model.Command) (*model.Comma
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandStore.Update(hook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3219,7 +3386,7 @@ func (s *OpenTracingLayerCommandWebhookStore) Cleanup() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.CommandWebhookStore.Cleanup()
 
 }
@@ -3232,11 +3399,10 @@ func (s *OpenTracingLayerCommandWebhookStore) Get(id string) (*model.CommandWebh
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandWebhookStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3250,11 +3416,10 @@ func (s *OpenTracingLayerCommandWebhookStore) Save(webhook *model.CommandWebhook
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.CommandWebhookStore.Save(webhook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3268,11 +3433,10 @@ func (s *OpenTracingLayerCommandWebhookStore) TryUse(id string, limit int) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.CommandWebhookStore.TryUse(id, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3286,11 +3450,10 @@ func (s *OpenTracingLayerComplianceStore) ComplianceExport(compliance *model.Com
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ComplianceStore.ComplianceExport(compliance, cursor, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -3304,11 +3467,10 @@ func (s *OpenTracingLayerComplianceStore) Get(id string) (*model.Compliance, err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ComplianceStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3322,11 +3484,10 @@ func (s *OpenTracingLayerComplianceStore) GetAll(offset int, limit int) (model.C
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ComplianceStore.GetAll(offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3340,11 +3501,10 @@ func (s *OpenTracingLayerComplianceStore) MessageExport(c request.CTX, cursor mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ComplianceStore.MessageExport(c, cursor, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -3358,11 +3518,10 @@ func (s *OpenTracingLayerComplianceStore) Save(compliance *model.Compliance) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ComplianceStore.Save(compliance)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3376,227 +3535,367 @@ func (s *OpenTracingLayerComplianceStore) Update(compliance *model.Compliance) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ComplianceStore.Update(compliance)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
 }
 
-func (s *OpenTracingLayerDesktopTokensStore) Delete(token string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) DeleteField(rctx request.CTX, id string, deleteAt int64) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.Delete")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.DeleteField")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DesktopTokensStore.Delete(token)
+	defer span.End()
+	err := s.CustomProfileAttributeStore.DeleteField(rctx, id, deleteAt)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
 }
 
-func (s *OpenTracingLayerDesktopTokensStore) DeleteByUserId(userId string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) GetField(id string) (*model.CustomProfileField, error) {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.DeleteByUserId")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.GetField")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DesktopTokensStore.DeleteByUserId(userId)
+	defer span.End()
+	result, err := s.CustomProfileAttributeStore.GetField(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return err
+	return result, err
 }
 
-func (s *OpenTracingLayerDesktopTokensStore) DeleteOlderThan(minCreatedAt int64) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) GetFields(includeDeleted bool) ([]*model.CustomProfileField, error) {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.DeleteOlderThan")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.GetFields")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DesktopTokensStore.DeleteOlderThan(minCreatedAt)
+	defer span.End()
+	result, err := s.CustomProfileAttributeStore.GetFields(includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return err
+	return result, err
 }
 
-func (s *OpenTracingLayerDesktopTokensStore) GetUserId(token string, minCreatedAt int64) (*string, error) {
+func (s *OpenTracingLayerCustomProfileAttributeStore) GetUserIDsForField(fieldID string) ([]string, error) {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.GetUserId")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.GetUserIDsForField")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	result, err := s.DesktopTokensStore.GetUserId(token, minCreatedAt)
+	defer span.End()
+	result, err := s.CustomProfileAttributeStore.GetUserIDsForField(fieldID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
 }
 
-func (s *OpenTracingLayerDesktopTokensStore) Insert(token string, createAt int64, userId string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) GetValuesForUsers(userIDs []string) ([]*model.CustomProfileValue, error) {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.Insert")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.GetValuesForUsers")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DesktopTokensStore.Insert(token, createAt, userId)
+	defer span.End()
+	result, err := s.CustomProfileAttributeStore.GetValuesForUsers(userIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return err
+	return result, err
 }
 
-func (s *OpenTracingLayerDraftStore) Delete(userID string, channelID string, rootID string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) PermanentDeleteByUser(userID string) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.Delete")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.PermanentDeleteByUser")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DraftStore.Delete(userID, channelID, rootID)
+	defer span.End()
+	err := s.CustomProfileAttributeStore.PermanentDeleteByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
 }
 
-func (s *OpenTracingLayerDraftStore) DeleteDraftsAssociatedWithPost(channelID string, rootID string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) SaveField(field *model.CustomProfileField) (*model.CustomProfileField, error) {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.DeleteDraftsAssociatedWithPost")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.SaveField")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DraftStore.DeleteDraftsAssociatedWithPost(channelID, rootID)
+	defer span.End()
+	result, err := s.CustomProfileAttributeStore.SaveField(field)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return err
+	return result, err
 }
 
-func (s *OpenTracingLayerDraftStore) DeleteEmptyDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) SaveValues(rctx request.CTX, userID string, values []*model.CustomProfileValue) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.DeleteEmptyDraftsByCreateAtAndUserId")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.SaveValues")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DraftStore.DeleteEmptyDraftsByCreateAtAndUserId(createAt, userId)
+	defer span.End()
+	err := s.CustomProfileAttributeStore.SaveValues(rctx, userID, values)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
 }
 
-func (s *OpenTracingLayerDraftStore) DeleteOrphanDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+func (s *OpenTracingLayerCustomProfileAttributeStore) UpdateField(rctx request.CTX, field *model.CustomProfileField) (*model.CustomProfileField, error) {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.DeleteOrphanDraftsByCreateAtAndUserId")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "CustomProfileAttributeStore.UpdateField")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.DraftStore.DeleteOrphanDraftsByCreateAtAndUserId(createAt, userId)
+	defer span.End()
+	result, err := s.CustomProfileAttributeStore.UpdateField(rctx, field)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return err
+	return result, err
 }
 
-func (s *OpenTracingLayerDraftStore) Get(userID string, channelID string, rootID string, includeDeleted bool) (*model.Draft, error) {
+func (s *OpenTracingLayerDesktopTokensStore) Delete(token string) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.Get")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.Delete")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	result, err := s.DraftStore.Get(userID, channelID, rootID, includeDeleted)
+	defer span.End()
+	err := s.DesktopTokensStore.Delete(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return result, err
+	return err
 }
 
-func (s *OpenTracingLayerDraftStore) GetDraftsForUser(userID string, teamID string) ([]*model.Draft, error) {
+func (s *OpenTracingLayerDesktopTokensStore) DeleteByUserId(userId string) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.GetDraftsForUser")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.DeleteByUserId")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	result, err := s.DraftStore.GetDraftsForUser(userID, teamID)
+	defer span.End()
+	err := s.DesktopTokensStore.DeleteByUserId(userId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
-	return result, err
+	return err
 }
 
-func (s *OpenTracingLayerDraftStore) GetLastCreateAtAndUserIdValuesForEmptyDraftsMigration(createAt int64, userId string) (int64, string, error) {
+func (s *OpenTracingLayerDesktopTokensStore) DeleteOlderThan(minCreatedAt int64) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.GetLastCreateAtAndUserIdValuesForEmptyDraftsMigration")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.DeleteOlderThan")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
+	err := s.DesktopTokensStore.DeleteOlderThan(minCreatedAt)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerDesktopTokensStore) GetUserId(token string, minCreatedAt int64) (*string, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.GetUserId")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.DesktopTokensStore.GetUserId(token, minCreatedAt)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerDesktopTokensStore) Insert(token string, createAt int64, userId string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DesktopTokensStore.Insert")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.DesktopTokensStore.Insert(token, createAt, userId)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerDraftStore) Delete(userID string, channelID string, rootID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.Delete")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.DraftStore.Delete(userID, channelID, rootID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerDraftStore) DeleteDraftsAssociatedWithPost(channelID string, rootID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.DeleteDraftsAssociatedWithPost")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.DraftStore.DeleteDraftsAssociatedWithPost(channelID, rootID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerDraftStore) DeleteEmptyDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.DeleteEmptyDraftsByCreateAtAndUserId")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.DraftStore.DeleteEmptyDraftsByCreateAtAndUserId(createAt, userId)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerDraftStore) DeleteOrphanDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.DeleteOrphanDraftsByCreateAtAndUserId")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.DraftStore.DeleteOrphanDraftsByCreateAtAndUserId(createAt, userId)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerDraftStore) Get(userID string, channelID string, rootID string, includeDeleted bool) (*model.Draft, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.Get")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.DraftStore.Get(userID, channelID, rootID, includeDeleted)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerDraftStore) GetDraftsForUser(userID string, teamID string) ([]*model.Draft, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.GetDraftsForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.DraftStore.GetDraftsForUser(userID, teamID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerDraftStore) GetLastCreateAtAndUserIdValuesForEmptyDraftsMigration(createAt int64, userId string) (int64, string, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "DraftStore.GetLastCreateAtAndUserIdValuesForEmptyDraftsMigration")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
 	result, resultVar1, err := s.DraftStore.GetLastCreateAtAndUserIdValuesForEmptyDraftsMigration(createAt, userId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -3610,11 +3909,10 @@ func (s *OpenTracingLayerDraftStore) Upsert(d *model.Draft) (*model.Draft, error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.DraftStore.Upsert(d)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3628,11 +3926,10 @@ func (s *OpenTracingLayerEmojiStore) Delete(emoji *model.Emoji, timestamp int64)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.EmojiStore.Delete(emoji, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3646,11 +3943,10 @@ func (s *OpenTracingLayerEmojiStore) Get(c request.CTX, id string, allowFromCach
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.EmojiStore.Get(c, id, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3664,11 +3960,10 @@ func (s *OpenTracingLayerEmojiStore) GetByName(c request.CTX, name string, allow
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.EmojiStore.GetByName(c, name, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3682,11 +3977,10 @@ func (s *OpenTracingLayerEmojiStore) GetList(offset int, limit int, sort string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.EmojiStore.GetList(offset, limit, sort)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3700,11 +3994,10 @@ func (s *OpenTracingLayerEmojiStore) GetMultipleByName(c request.CTX, names []st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.EmojiStore.GetMultipleByName(c, names)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3718,11 +4011,10 @@ func (s *OpenTracingLayerEmojiStore) Save(emoji *model.Emoji) (*model.Emoji, err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.EmojiStore.Save(emoji)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3736,11 +4028,10 @@ func (s *OpenTracingLayerEmojiStore) Search(name string, prefixOnly bool, limit
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.EmojiStore.Search(name, prefixOnly, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3754,11 +4045,10 @@ func (s *OpenTracingLayerFileInfoStore) AttachToPost(c request.CTX, fileID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.FileInfoStore.AttachToPost(c, fileID, postID, channelID, creatorID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -3772,7 +4062,7 @@ func (s *OpenTracingLayerFileInfoStore) ClearCaches() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.FileInfoStore.ClearCaches()
 
 }
@@ -3785,11 +4075,10 @@ func (s *OpenTracingLayerFileInfoStore) CountAll() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.CountAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3803,11 +4092,10 @@ func (s *OpenTracingLayerFileInfoStore) DeleteForPost(c request.CTX, postID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.DeleteForPost(c, postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3821,11 +4109,10 @@ func (s *OpenTracingLayerFileInfoStore) Get(id string) (*model.FileInfo, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3839,11 +4126,10 @@ func (s *OpenTracingLayerFileInfoStore) GetByIds(ids []string) ([]*model.FileInf
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetByIds(ids)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3857,11 +4143,27 @@ func (s *OpenTracingLayerFileInfoStore) GetByPath(path string) (*model.FileInfo,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetByPath(path)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerFileInfoStore) GetFilesBatchForColdStorage(olderThan int64, startTime int64, startFileID string, limit int) ([]*model.FileInfo, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "FileInfoStore.GetFilesBatchForColdStorage")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.FileInfoStore.GetFilesBatchForColdStorage(olderThan, startTime, startFileID, limit)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3875,11 +4177,10 @@ func (s *OpenTracingLayerFileInfoStore) GetFilesBatchForIndexing(startTime int64
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetFilesBatchForIndexing(startTime, startFileID, includeDeleted, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3893,11 +4194,10 @@ func (s *OpenTracingLayerFileInfoStore) GetForPost(postID string, readFromMaster
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetForPost(postID, readFromMaster, includeDeleted, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3911,11 +4211,10 @@ func (s *OpenTracingLayerFileInfoStore) GetForUser(userID string) ([]*model.File
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetForUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3929,11 +4228,10 @@ func (s *OpenTracingLayerFileInfoStore) GetFromMaster(id string) (*model.FileInf
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetFromMaster(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3947,11 +4245,27 @@ func (s *OpenTracingLayerFileInfoStore) GetStorageUsage(allowFromCache bool, inc
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetStorageUsage(allowFromCache, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerFileInfoStore) GetStorageUsageByTier(includeDeleted bool) ([]*model.FileTierUsage, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "FileInfoStore.GetStorageUsageByTier")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.FileInfoStore.GetStorageUsageByTier(includeDeleted)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3965,11 +4279,10 @@ func (s *OpenTracingLayerFileInfoStore) GetUptoNSizeFileTime(n int64) (int64, er
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetUptoNSizeFileTime(n)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -3983,11 +4296,10 @@ func (s *OpenTracingLayerFileInfoStore) GetWithOptions(page int, perPage int, op
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.GetWithOptions(page, perPage, opt)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4001,7 +4313,7 @@ func (s *OpenTracingLayerFileInfoStore) InvalidateFileInfosForPostCache(postID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.FileInfoStore.InvalidateFileInfosForPostCache(postID, deleted)
 
 }
@@ -4014,11 +4326,10 @@ func (s *OpenTracingLayerFileInfoStore) PermanentDelete(c request.CTX, fileID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.FileInfoStore.PermanentDelete(c, fileID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -4032,11 +4343,10 @@ func (s *OpenTracingLayerFileInfoStore) PermanentDeleteBatch(ctx request.CTX, en
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.PermanentDeleteBatch(ctx, endTime, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4050,11 +4360,10 @@ func (s *OpenTracingLayerFileInfoStore) PermanentDeleteByUser(ctx request.CTX, u
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.PermanentDeleteByUser(ctx, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4068,11 +4377,10 @@ func (s *OpenTracingLayerFileInfoStore) Save(ctx request.CTX, info *model.FileIn
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.Save(ctx, info)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4086,11 +4394,10 @@ func (s *OpenTracingLayerFileInfoStore) Search(ctx request.CTX, paramsList []*mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.Search(ctx, paramsList, userID, teamID, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4104,11 +4411,27 @@ func (s *OpenTracingLayerFileInfoStore) SetContent(ctx request.CTX, fileID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.FileInfoStore.SetContent(ctx, fileID, content)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerFileInfoStore) SetTier(ctx request.CTX, fileID string, tier string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "FileInfoStore.SetTier")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.FileInfoStore.SetTier(ctx, fileID, tier)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -4122,11 +4445,10 @@ func (s *OpenTracingLayerFileInfoStore) Upsert(rctx request.CTX, info *model.Fil
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.FileInfoStore.Upsert(rctx, info)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4140,11 +4462,10 @@ func (s *OpenTracingLayerGroupStore) AdminRoleGroupsForSyncableMember(userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.AdminRoleGroupsForSyncableMember(userID, syncableID, syncableType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4158,11 +4479,10 @@ func (s *OpenTracingLayerGroupStore) ChannelMembersMinusGroupMembers(channelID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.ChannelMembersMinusGroupMembers(channelID, groupIDs, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4176,11 +4496,10 @@ func (s *OpenTracingLayerGroupStore) ChannelMembersToAdd(since int64, channelID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.ChannelMembersToAdd(since, channelID, includeRemovedMembers)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4194,11 +4513,10 @@ func (s *OpenTracingLayerGroupStore) ChannelMembersToRemove(channelID *string) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.ChannelMembersToRemove(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4212,11 +4530,10 @@ func (s *OpenTracingLayerGroupStore) CountChannelMembersMinusGroupMembers(channe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.CountChannelMembersMinusGroupMembers(channelID, groupIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4230,11 +4547,10 @@ func (s *OpenTracingLayerGroupStore) CountGroupsByChannel(channelID string, opts
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.CountGroupsByChannel(channelID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4248,11 +4564,10 @@ func (s *OpenTracingLayerGroupStore) CountGroupsByTeam(teamID string, opts model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.CountGroupsByTeam(teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4266,11 +4581,10 @@ func (s *OpenTracingLayerGroupStore) CountTeamMembersMinusGroupMembers(teamID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.CountTeamMembersMinusGroupMembers(teamID, groupIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4284,11 +4598,10 @@ func (s *OpenTracingLayerGroupStore) Create(group *model.Group) (*model.Group, e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.Create(group)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4302,11 +4615,10 @@ func (s *OpenTracingLayerGroupStore) CreateGroupSyncable(groupSyncable *model.Gr
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.CreateGroupSyncable(groupSyncable)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4320,11 +4632,10 @@ func (s *OpenTracingLayerGroupStore) CreateWithUserIds(group *model.GroupWithUse
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.CreateWithUserIds(group)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4338,11 +4649,10 @@ func (s *OpenTracingLayerGroupStore) Delete(groupID string) (*model.Group, error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.Delete(groupID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4356,11 +4666,10 @@ func (s *OpenTracingLayerGroupStore) DeleteGroupSyncable(groupID string, syncabl
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.DeleteGroupSyncable(groupID, syncableID, syncableType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4374,11 +4683,10 @@ func (s *OpenTracingLayerGroupStore) DeleteMember(groupID string, userID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.DeleteMember(groupID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4392,11 +4700,10 @@ func (s *OpenTracingLayerGroupStore) DeleteMembers(groupID string, userIDs []str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.DeleteMembers(groupID, userIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4410,11 +4717,10 @@ func (s *OpenTracingLayerGroupStore) DistinctGroupMemberCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.DistinctGroupMemberCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4428,11 +4734,10 @@ func (s *OpenTracingLayerGroupStore) DistinctGroupMemberCountForSource(source mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.DistinctGroupMemberCountForSource(source)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4446,11 +4751,10 @@ func (s *OpenTracingLayerGroupStore) Get(groupID string) (*model.Group, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.Get(groupID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4464,11 +4768,10 @@ func (s *OpenTracingLayerGroupStore) GetAllBySource(groupSource model.GroupSourc
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetAllBySource(groupSource)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4482,11 +4785,10 @@ func (s *OpenTracingLayerGroupStore) GetAllGroupSyncablesByGroupId(groupID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetAllGroupSyncablesByGroupId(groupID, syncableType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4500,11 +4802,10 @@ func (s *OpenTracingLayerGroupStore) GetByIDs(groupIDs []string) ([]*model.Group
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetByIDs(groupIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4518,11 +4819,10 @@ func (s *OpenTracingLayerGroupStore) GetByName(name string, opts model.GroupSear
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetByName(name, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4536,11 +4836,10 @@ func (s *OpenTracingLayerGroupStore) GetByRemoteID(remoteID string, groupSource
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetByRemoteID(remoteID, groupSource)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4554,11 +4853,27 @@ func (s *OpenTracingLayerGroupStore) GetByUser(userID string) ([]*model.Group, e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerGroupStore) GetByUsers(userIDs []string) (map[string][]*model.Group, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "GroupStore.GetByUsers")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.GroupStore.GetByUsers(userIDs)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4572,11 +4887,10 @@ func (s *OpenTracingLayerGroupStore) GetGroupSyncable(groupID string, syncableID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetGroupSyncable(groupID, syncableID, syncableType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4590,11 +4904,10 @@ func (s *OpenTracingLayerGroupStore) GetGroups(page int, perPage int, opts model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetGroups(page, perPage, opts, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4608,11 +4921,10 @@ func (s *OpenTracingLayerGroupStore) GetGroupsAssociatedToChannelsByTeam(teamID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetGroupsAssociatedToChannelsByTeam(teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4626,11 +4938,10 @@ func (s *OpenTracingLayerGroupStore) GetGroupsByChannel(channelID string, opts m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetGroupsByChannel(channelID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4644,11 +4955,10 @@ func (s *OpenTracingLayerGroupStore) GetGroupsByTeam(teamID string, opts model.G
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetGroupsByTeam(teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4662,11 +4972,10 @@ func (s *OpenTracingLayerGroupStore) GetMember(groupID string, userID string) (*
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMember(groupID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4680,11 +4989,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberCount(groupID string) (int64, erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberCount(groupID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4698,11 +5006,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberCountWithRestrictions(groupID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberCountWithRestrictions(groupID, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4716,11 +5023,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberUsers(groupID string) ([]*model.Us
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberUsers(groupID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4734,11 +5040,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberUsersInTeam(groupID string, teamID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberUsersInTeam(groupID, teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4752,11 +5057,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberUsersNotInChannel(groupID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberUsersNotInChannel(groupID, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4770,11 +5074,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberUsersPage(groupID string, page int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberUsersPage(groupID, page, perPage, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4788,11 +5091,10 @@ func (s *OpenTracingLayerGroupStore) GetMemberUsersSortedPage(groupID string, pa
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetMemberUsersSortedPage(groupID, page, perPage, viewRestrictions, teammateNameDisplay)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4806,11 +5108,10 @@ func (s *OpenTracingLayerGroupStore) GetNonMemberUsersPage(groupID string, page
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GetNonMemberUsersPage(groupID, page, perPage, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4824,11 +5125,10 @@ func (s *OpenTracingLayerGroupStore) GroupChannelCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GroupChannelCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4842,11 +5142,10 @@ func (s *OpenTracingLayerGroupStore) GroupCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GroupCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4860,11 +5159,10 @@ func (s *OpenTracingLayerGroupStore) GroupCountBySource(source model.GroupSource
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GroupCountBySource(source)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4878,11 +5176,10 @@ func (s *OpenTracingLayerGroupStore) GroupCountWithAllowReference() (int64, erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GroupCountWithAllowReference()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4896,11 +5193,10 @@ func (s *OpenTracingLayerGroupStore) GroupMemberCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GroupMemberCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4914,11 +5210,10 @@ func (s *OpenTracingLayerGroupStore) GroupTeamCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.GroupTeamCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4932,11 +5227,10 @@ func (s *OpenTracingLayerGroupStore) PermanentDeleteMembersByUser(userID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.GroupStore.PermanentDeleteMembersByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -4950,11 +5244,10 @@ func (s *OpenTracingLayerGroupStore) PermittedSyncableAdmins(syncableID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.PermittedSyncableAdmins(syncableID, syncableType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4968,11 +5261,10 @@ func (s *OpenTracingLayerGroupStore) Restore(groupID string) (*model.Group, erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.Restore(groupID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -4986,11 +5278,10 @@ func (s *OpenTracingLayerGroupStore) TeamMembersMinusGroupMembers(teamID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.TeamMembersMinusGroupMembers(teamID, groupIDs, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5004,11 +5295,10 @@ func (s *OpenTracingLayerGroupStore) TeamMembersToAdd(since int64, teamID *strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.TeamMembersToAdd(since, teamID, includeRemovedMembers)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5022,11 +5312,10 @@ func (s *OpenTracingLayerGroupStore) TeamMembersToRemove(teamID *string) ([]*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.TeamMembersToRemove(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5040,11 +5329,10 @@ func (s *OpenTracingLayerGroupStore) Update(group *model.Group) (*model.Group, e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.Update(group)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5058,11 +5346,10 @@ func (s *OpenTracingLayerGroupStore) UpdateGroupSyncable(groupSyncable *model.Gr
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.UpdateGroupSyncable(groupSyncable)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5076,11 +5363,10 @@ func (s *OpenTracingLayerGroupStore) UpsertMember(groupID string, userID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.UpsertMember(groupID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5094,11 +5380,10 @@ func (s *OpenTracingLayerGroupStore) UpsertMembers(groupID string, userIDs []str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.GroupStore.UpsertMembers(groupID, userIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5112,11 +5397,10 @@ func (s *OpenTracingLayerJobStore) Cleanup(expiryTime int64, batchSize int) erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.JobStore.Cleanup(expiryTime, batchSize)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5130,11 +5414,10 @@ func (s *OpenTracingLayerJobStore) Delete(id string) (string, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.Delete(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5148,11 +5431,10 @@ func (s *OpenTracingLayerJobStore) Get(c request.CTX, id string) (*model.Job, er
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.Get(c, id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5166,11 +5448,10 @@ func (s *OpenTracingLayerJobStore) GetAllByStatus(c request.CTX, status string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetAllByStatus(c, status)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5184,11 +5465,10 @@ func (s *OpenTracingLayerJobStore) GetAllByType(c request.CTX, jobType string) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetAllByType(c, jobType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5202,11 +5482,10 @@ func (s *OpenTracingLayerJobStore) GetAllByTypeAndStatus(c request.CTX, jobType
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetAllByTypeAndStatus(c, jobType, status)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5220,11 +5499,10 @@ func (s *OpenTracingLayerJobStore) GetAllByTypeAndStatusPage(c request.CTX, jobT
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetAllByTypeAndStatusPage(c, jobType, status, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5238,11 +5516,10 @@ func (s *OpenTracingLayerJobStore) GetAllByTypePage(c request.CTX, jobType strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetAllByTypePage(c, jobType, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5256,11 +5533,10 @@ func (s *OpenTracingLayerJobStore) GetAllByTypesPage(c request.CTX, jobTypes []s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetAllByTypesPage(c, jobTypes, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5274,11 +5550,10 @@ func (s *OpenTracingLayerJobStore) GetCountByStatusAndType(status string, jobTyp
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetCountByStatusAndType(status, jobType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5292,11 +5567,10 @@ func (s *OpenTracingLayerJobStore) GetNewestJobByStatusAndType(status string, jo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetNewestJobByStatusAndType(status, jobType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5310,11 +5584,10 @@ func (s *OpenTracingLayerJobStore) GetNewestJobByStatusesAndType(statuses []stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.GetNewestJobByStatusesAndType(statuses, jobType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5328,11 +5601,10 @@ func (s *OpenTracingLayerJobStore) Save(job *model.Job) (*model.Job, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.Save(job)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5346,11 +5618,10 @@ func (s *OpenTracingLayerJobStore) SaveOnce(job *model.Job) (*model.Job, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.SaveOnce(job)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5364,11 +5635,10 @@ func (s *OpenTracingLayerJobStore) UpdateOptimistically(job *model.Job, currentS
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.UpdateOptimistically(job, currentStatus)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5382,11 +5652,10 @@ func (s *OpenTracingLayerJobStore) UpdateStatus(id string, status string) (*mode
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.UpdateStatus(id, status)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5400,11 +5669,10 @@ func (s *OpenTracingLayerJobStore) UpdateStatusOptimistically(id string, current
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.JobStore.UpdateStatusOptimistically(id, currentStatus, newStatus)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5418,11 +5686,10 @@ func (s *OpenTracingLayerLicenseStore) Get(c request.CTX, id string) (*model.Lic
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.LicenseStore.Get(c, id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5436,11 +5703,10 @@ func (s *OpenTracingLayerLicenseStore) GetAll() ([]*model.LicenseRecord, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.LicenseStore.GetAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5454,11 +5720,10 @@ func (s *OpenTracingLayerLicenseStore) Save(license *model.LicenseRecord) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.LicenseStore.Save(license)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5472,11 +5737,10 @@ func (s *OpenTracingLayerLinkMetadataStore) Get(url string, timestamp int64) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.LinkMetadataStore.Get(url, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5490,11 +5754,384 @@ func (s *OpenTracingLayerLinkMetadataStore) Save(linkMetadata *model.LinkMetadat
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.LinkMetadataStore.Save(linkMetadata)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerLoginDeviceStore) GetForUser(userID string, limit int) ([]*model.LoginDevice, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "LoginDeviceStore.GetForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.LoginDeviceStore.GetForUser(userID, limit)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerLoginDeviceStore) PermanentDeleteByUser(userID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "LoginDeviceStore.PermanentDeleteByUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.LoginDeviceStore.PermanentDeleteByUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerLoginDeviceStore) PruneForUser(userID string, keep int) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "LoginDeviceStore.PruneForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.LoginDeviceStore.PruneForUser(userID, keep)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerLoginDeviceStore) Save(device *model.LoginDevice) (*model.LoginDevice, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "LoginDeviceStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.LoginDeviceStore.Save(device)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerLoginDeviceStore) UpdateLastSeenAt(id string, lastSeenAt int64) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "LoginDeviceStore.UpdateLastSeenAt")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.LoginDeviceStore.UpdateLastSeenAt(id, lastSeenAt)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaRecoveryCodeStore) GetUnusedForUser(userID string) ([]*model.MfaRecoveryCode, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaRecoveryCodeStore.GetUnusedForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.MfaRecoveryCodeStore.GetUnusedForUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerMfaRecoveryCodeStore) MarkUsed(id string, usedAt int64) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaRecoveryCodeStore.MarkUsed")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaRecoveryCodeStore.MarkUsed(id, usedAt)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaRecoveryCodeStore) PermanentDeleteByUser(userID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaRecoveryCodeStore.PermanentDeleteByUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaRecoveryCodeStore.PermanentDeleteByUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaRecoveryCodeStore) Replace(userID string, codes []*model.MfaRecoveryCode) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaRecoveryCodeStore.Replace")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaRecoveryCodeStore.Replace(userID, codes)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaTrustedDeviceStore) Cleanup(expiryTime int64) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaTrustedDeviceStore.Cleanup")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaTrustedDeviceStore.Cleanup(expiryTime)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaTrustedDeviceStore) Delete(id string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaTrustedDeviceStore.Delete")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaTrustedDeviceStore.Delete(id)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaTrustedDeviceStore) Get(id string) (*model.MfaTrustedDevice, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaTrustedDeviceStore.Get")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.MfaTrustedDeviceStore.Get(id)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerMfaTrustedDeviceStore) PermanentDeleteByUser(userID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaTrustedDeviceStore.PermanentDeleteByUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaTrustedDeviceStore.PermanentDeleteByUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerMfaTrustedDeviceStore) Save(device *model.MfaTrustedDevice) (*model.MfaTrustedDevice, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaTrustedDeviceStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.MfaTrustedDeviceStore.Save(device)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerMfaTrustedDeviceStore) UpdateLastUsedAt(id string, lastUsedAt int64) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "MfaTrustedDeviceStore.UpdateLastUsedAt")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.MfaTrustedDeviceStore.UpdateLastUsedAt(id, lastUsedAt)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) Delete(id string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.Delete")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.NotificationRuleStore.Delete(id)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) Get(id string) (*model.NotificationRule, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.Get")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.NotificationRuleStore.Get(id)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) GetForChannelMembers(channelID string) ([]*model.NotificationRule, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.GetForChannelMembers")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.NotificationRuleStore.GetForChannelMembers(channelID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) GetForUser(userID string) ([]*model.NotificationRule, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.GetForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.NotificationRuleStore.GetForUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) PermanentDeleteByUser(userID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.PermanentDeleteByUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.NotificationRuleStore.PermanentDeleteByUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) Save(rule *model.NotificationRule) (*model.NotificationRule, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.NotificationRuleStore.Save(rule)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerNotificationRuleStore) Update(rule *model.NotificationRule) (*model.NotificationRule, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "NotificationRuleStore.Update")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.NotificationRuleStore.Update(rule)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5508,11 +6145,10 @@ func (s *OpenTracingLayerNotifyAdminStore) DeleteBefore(trial bool, now int64) e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.NotifyAdminStore.DeleteBefore(trial, now)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5526,11 +6162,10 @@ func (s *OpenTracingLayerNotifyAdminStore) Get(trial bool) ([]*model.NotifyAdmin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.NotifyAdminStore.Get(trial)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5544,11 +6179,10 @@ func (s *OpenTracingLayerNotifyAdminStore) GetDataByUserIdAndFeature(userId stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.NotifyAdminStore.GetDataByUserIdAndFeature(userId, feature)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5562,11 +6196,10 @@ func (s *OpenTracingLayerNotifyAdminStore) Save(data *model.NotifyAdminData) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.NotifyAdminStore.Save(data)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5580,11 +6213,10 @@ func (s *OpenTracingLayerNotifyAdminStore) Update(userId string, requiredPlan st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.NotifyAdminStore.Update(userId, requiredPlan, requiredFeature, now)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5598,11 +6230,10 @@ func (s *OpenTracingLayerOAuthStore) DeleteApp(id string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.OAuthStore.DeleteApp(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5616,11 +6247,10 @@ func (s *OpenTracingLayerOAuthStore) GetAccessData(token string) (*model.AccessD
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetAccessData(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5634,11 +6264,10 @@ func (s *OpenTracingLayerOAuthStore) GetAccessDataByRefreshToken(token string) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetAccessDataByRefreshToken(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5652,11 +6281,10 @@ func (s *OpenTracingLayerOAuthStore) GetAccessDataByUserForApp(userID string, cl
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetAccessDataByUserForApp(userID, clientId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5670,11 +6298,10 @@ func (s *OpenTracingLayerOAuthStore) GetApp(id string) (*model.OAuthApp, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetApp(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5688,11 +6315,10 @@ func (s *OpenTracingLayerOAuthStore) GetAppByUser(userID string, offset int, lim
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetAppByUser(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5706,11 +6332,10 @@ func (s *OpenTracingLayerOAuthStore) GetApps(offset int, limit int) ([]*model.OA
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetApps(offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5724,11 +6349,10 @@ func (s *OpenTracingLayerOAuthStore) GetAuthData(code string) (*model.AuthData,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetAuthData(code)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5742,11 +6366,44 @@ func (s *OpenTracingLayerOAuthStore) GetAuthorizedApps(userID string, offset int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetAuthorizedApps(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerOAuthStore) GetDeviceCode(deviceCode string) (*model.OAuthDeviceCode, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.GetDeviceCode")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.OAuthStore.GetDeviceCode(deviceCode)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerOAuthStore) GetDeviceCodeByUserCode(userCode string) (*model.OAuthDeviceCode, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.GetDeviceCodeByUserCode")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.OAuthStore.GetDeviceCodeByUserCode(userCode)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5760,11 +6417,10 @@ func (s *OpenTracingLayerOAuthStore) GetPreviousAccessData(userID string, client
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.GetPreviousAccessData(userID, clientId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5778,11 +6434,10 @@ func (s *OpenTracingLayerOAuthStore) PermanentDeleteAuthDataByUser(userID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.OAuthStore.PermanentDeleteAuthDataByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5796,11 +6451,10 @@ func (s *OpenTracingLayerOAuthStore) RemoveAccessData(token string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.OAuthStore.RemoveAccessData(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5814,11 +6468,10 @@ func (s *OpenTracingLayerOAuthStore) RemoveAllAccessData() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.OAuthStore.RemoveAllAccessData()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5832,11 +6485,10 @@ func (s *OpenTracingLayerOAuthStore) RemoveAuthData(code string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.OAuthStore.RemoveAuthData(code)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5850,29 +6502,61 @@ func (s *OpenTracingLayerOAuthStore) RemoveAuthDataByClientId(clientId string, u
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.OAuthStore.RemoveAuthDataByClientId(clientId, userId)
+	defer span.End()
+	err := s.OAuthStore.RemoveAuthDataByClientId(clientId, userId)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerOAuthStore) RemoveAuthDataByUserId(userId string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.RemoveAuthDataByUserId")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.OAuthStore.RemoveAuthDataByUserId(userId)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerOAuthStore) RemoveDeviceCode(deviceCode string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.RemoveDeviceCode")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.OAuthStore.RemoveDeviceCode(deviceCode)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
 }
 
-func (s *OpenTracingLayerOAuthStore) RemoveAuthDataByUserId(userId string) error {
+func (s *OpenTracingLayerOAuthStore) RemoveExpiredDeviceCodes(expiredBefore int64) error {
 	origCtx := s.Root.Store.Context()
-	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.RemoveAuthDataByUserId")
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.RemoveExpiredDeviceCodes")
 	s.Root.Store.SetContext(newCtx)
 	defer func() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
-	err := s.OAuthStore.RemoveAuthDataByUserId(userId)
+	defer span.End()
+	err := s.OAuthStore.RemoveExpiredDeviceCodes(expiredBefore)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5886,11 +6570,10 @@ func (s *OpenTracingLayerOAuthStore) SaveAccessData(accessData *model.AccessData
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.SaveAccessData(accessData)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5904,11 +6587,10 @@ func (s *OpenTracingLayerOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthA
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.SaveApp(app)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5922,11 +6604,27 @@ func (s *OpenTracingLayerOAuthStore) SaveAuthData(authData *model.AuthData) (*mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.SaveAuthData(authData)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerOAuthStore) SaveDeviceCode(deviceCode *model.OAuthDeviceCode) (*model.OAuthDeviceCode, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.SaveDeviceCode")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.OAuthStore.SaveDeviceCode(deviceCode)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5940,11 +6638,10 @@ func (s *OpenTracingLayerOAuthStore) UpdateAccessData(accessData *model.AccessDa
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.UpdateAccessData(accessData)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5958,11 +6655,27 @@ func (s *OpenTracingLayerOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAut
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OAuthStore.UpdateApp(app)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerOAuthStore) UpdateDeviceCodeOptimistically(deviceCode *model.OAuthDeviceCode, currentStatus string) (bool, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "OAuthStore.UpdateDeviceCodeOptimistically")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.OAuthStore.UpdateDeviceCodeOptimistically(deviceCode, currentStatus)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -5976,11 +6689,10 @@ func (s *OpenTracingLayerOutgoingOAuthConnectionStore) DeleteConnection(c reques
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.OutgoingOAuthConnectionStore.DeleteConnection(c, id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -5994,11 +6706,10 @@ func (s *OpenTracingLayerOutgoingOAuthConnectionStore) GetConnection(c request.C
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OutgoingOAuthConnectionStore.GetConnection(c, id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6012,11 +6723,10 @@ func (s *OpenTracingLayerOutgoingOAuthConnectionStore) GetConnections(c request.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OutgoingOAuthConnectionStore.GetConnections(c, filters)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6030,11 +6740,10 @@ func (s *OpenTracingLayerOutgoingOAuthConnectionStore) SaveConnection(c request.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OutgoingOAuthConnectionStore.SaveConnection(c, conn)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6048,11 +6757,78 @@ func (s *OpenTracingLayerOutgoingOAuthConnectionStore) UpdateConnection(c reques
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.OutgoingOAuthConnectionStore.UpdateConnection(c, conn)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerPasswordHistoryStore) GetForUser(userID string, limit int) ([]*model.PasswordHistoryEntry, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "PasswordHistoryStore.GetForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.PasswordHistoryStore.GetForUser(userID, limit)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerPasswordHistoryStore) PermanentDeleteByUser(userID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "PasswordHistoryStore.PermanentDeleteByUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.PasswordHistoryStore.PermanentDeleteByUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerPasswordHistoryStore) PruneForUser(userID string, keep int) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "PasswordHistoryStore.PruneForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.PasswordHistoryStore.PruneForUser(userID, keep)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerPasswordHistoryStore) Save(entry *model.PasswordHistoryEntry) (*model.PasswordHistoryEntry, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "PasswordHistoryStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.PasswordHistoryStore.Save(entry)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6066,11 +6842,10 @@ func (s *OpenTracingLayerPluginStore) CompareAndDelete(keyVal *model.PluginKeyVa
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PluginStore.CompareAndDelete(keyVal, oldValue)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6084,11 +6859,10 @@ func (s *OpenTracingLayerPluginStore) CompareAndSet(keyVal *model.PluginKeyValue
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PluginStore.CompareAndSet(keyVal, oldValue)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6102,11 +6876,10 @@ func (s *OpenTracingLayerPluginStore) Delete(pluginID string, key string) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PluginStore.Delete(pluginID, key)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -6120,11 +6893,10 @@ func (s *OpenTracingLayerPluginStore) DeleteAllExpired() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PluginStore.DeleteAllExpired()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -6138,11 +6910,10 @@ func (s *OpenTracingLayerPluginStore) DeleteAllForPlugin(PluginID string) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PluginStore.DeleteAllForPlugin(PluginID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -6156,11 +6927,10 @@ func (s *OpenTracingLayerPluginStore) Get(pluginID string, key string) (*model.P
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PluginStore.Get(pluginID, key)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6174,11 +6944,10 @@ func (s *OpenTracingLayerPluginStore) List(pluginID string, page int, perPage in
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PluginStore.List(pluginID, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6192,11 +6961,10 @@ func (s *OpenTracingLayerPluginStore) SaveOrUpdate(keyVal *model.PluginKeyValue)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PluginStore.SaveOrUpdate(keyVal)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6210,11 +6978,10 @@ func (s *OpenTracingLayerPluginStore) SetWithOptions(pluginID string, key string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PluginStore.SetWithOptions(pluginID, key, value, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6228,11 +6995,10 @@ func (s *OpenTracingLayerPostStore) AnalyticsPostCount(options *model.PostCountO
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.AnalyticsPostCount(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6246,11 +7012,10 @@ func (s *OpenTracingLayerPostStore) AnalyticsPostCountsByDay(options *model.Anal
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.AnalyticsPostCountsByDay(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6264,11 +7029,10 @@ func (s *OpenTracingLayerPostStore) AnalyticsUserCountsWithPostsByDay(teamID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.AnalyticsUserCountsWithPostsByDay(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6282,7 +7046,7 @@ func (s *OpenTracingLayerPostStore) ClearCaches() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.PostStore.ClearCaches()
 
 }
@@ -6295,11 +7059,10 @@ func (s *OpenTracingLayerPostStore) Delete(rctx request.CTX, postID string, time
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostStore.Delete(rctx, postID, timestamp, deleteByID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -6313,11 +7076,10 @@ func (s *OpenTracingLayerPostStore) Get(ctx context.Context, id string, opts mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.Get(ctx, id, opts, userID, sanitizeOptions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6331,11 +7093,10 @@ func (s *OpenTracingLayerPostStore) GetDirectPostParentsForExportAfter(limit int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetDirectPostParentsForExportAfter(limit, afterID, includeArchivedChannels)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6349,11 +7110,10 @@ func (s *OpenTracingLayerPostStore) GetEditHistoryForPost(postId string) ([]*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetEditHistoryForPost(postId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6367,7 +7127,7 @@ func (s *OpenTracingLayerPostStore) GetEtag(channelID string, allowFromCache boo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result := s.PostStore.GetEtag(channelID, allowFromCache, collapsedThreads)
 	return result
 }
@@ -6380,11 +7140,10 @@ func (s *OpenTracingLayerPostStore) GetFlaggedPosts(userID string, offset int, l
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetFlaggedPosts(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6398,11 +7157,10 @@ func (s *OpenTracingLayerPostStore) GetFlaggedPostsForChannel(userID string, cha
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetFlaggedPostsForChannel(userID, channelID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6416,19 +7174,18 @@ func (s *OpenTracingLayerPostStore) GetFlaggedPostsForTeam(userID string, teamID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	span.SetTag("userID", userID)
+	span.SetAttributes(tracing.Attribute("userID", userID))
 
-	span.SetTag("teamID", teamID)
+	span.SetAttributes(tracing.Attribute("teamID", teamID))
 
-	span.SetTag("offset", offset)
+	span.SetAttributes(tracing.Attribute("offset", offset))
 
-	span.SetTag("limit", limit)
+	span.SetAttributes(tracing.Attribute("limit", limit))
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetFlaggedPostsForTeam(userID, teamID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6442,7 +7199,7 @@ func (s *OpenTracingLayerPostStore) GetMaxPostSize() int {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result := s.PostStore.GetMaxPostSize()
 	return result
 }
@@ -6455,11 +7212,10 @@ func (s *OpenTracingLayerPostStore) GetNthRecentPostTime(n int64) (int64, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetNthRecentPostTime(n)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6473,11 +7229,10 @@ func (s *OpenTracingLayerPostStore) GetOldest() (*model.Post, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetOldest()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6491,11 +7246,10 @@ func (s *OpenTracingLayerPostStore) GetOldestEntityCreationTime() (int64, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetOldestEntityCreationTime()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6509,11 +7263,10 @@ func (s *OpenTracingLayerPostStore) GetParentsForExportAfter(limit int, afterID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetParentsForExportAfter(limit, afterID, includeArchivedChannels)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6527,11 +7280,10 @@ func (s *OpenTracingLayerPostStore) GetPostAfterTime(channelID string, timestamp
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostAfterTime(channelID, timestamp, collapsedThreads)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6545,11 +7297,10 @@ func (s *OpenTracingLayerPostStore) GetPostIdAfterTime(channelID string, timesta
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostIdAfterTime(channelID, timestamp, collapsedThreads)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6563,11 +7314,10 @@ func (s *OpenTracingLayerPostStore) GetPostIdBeforeTime(channelID string, timest
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostIdBeforeTime(channelID, timestamp, collapsedThreads)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6581,11 +7331,10 @@ func (s *OpenTracingLayerPostStore) GetPostReminderMetadata(postID string) (*sto
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostReminderMetadata(postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6599,11 +7348,10 @@ func (s *OpenTracingLayerPostStore) GetPostReminders(now int64) ([]*model.PostRe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostReminders(now)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6617,11 +7365,10 @@ func (s *OpenTracingLayerPostStore) GetPosts(options model.GetPostsOptions, allo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPosts(options, allowFromCache, sanitizeOptions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6635,11 +7382,10 @@ func (s *OpenTracingLayerPostStore) GetPostsAfter(options model.GetPostsOptions,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsAfter(options, sanitizeOptions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6653,11 +7399,10 @@ func (s *OpenTracingLayerPostStore) GetPostsBatchForIndexing(startTime int64, st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsBatchForIndexing(startTime, startPostID, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6671,11 +7416,10 @@ func (s *OpenTracingLayerPostStore) GetPostsBefore(options model.GetPostsOptions
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsBefore(options, sanitizeOptions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6689,11 +7433,10 @@ func (s *OpenTracingLayerPostStore) GetPostsByIds(postIds []string) ([]*model.Po
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsByIds(postIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6707,11 +7450,10 @@ func (s *OpenTracingLayerPostStore) GetPostsByThread(threadID string, since int6
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsByThread(threadID, since)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6725,11 +7467,10 @@ func (s *OpenTracingLayerPostStore) GetPostsCreatedAt(channelID string, timestam
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsCreatedAt(channelID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6743,11 +7484,10 @@ func (s *OpenTracingLayerPostStore) GetPostsSince(options model.GetPostsSinceOpt
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetPostsSince(options, allowFromCache, sanitizeOptions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6761,11 +7501,10 @@ func (s *OpenTracingLayerPostStore) GetPostsSinceForSync(options model.GetPostsS
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.PostStore.GetPostsSinceForSync(options, cursor, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -6779,11 +7518,10 @@ func (s *OpenTracingLayerPostStore) GetRepliesForExport(parentID string) ([]*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetRepliesForExport(parentID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6797,11 +7535,27 @@ func (s *OpenTracingLayerPostStore) GetSingle(rctx request.CTX, id string, inclD
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.GetSingle(rctx, id, inclDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerPostStore) GetTopReactedForUserSince(userID string, since int64, limit int) ([]*model.ReactedPost, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "PostStore.GetTopReactedForUserSince")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.PostStore.GetTopReactedForUserSince(userID, since, limit)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6815,11 +7569,10 @@ func (s *OpenTracingLayerPostStore) HasAutoResponsePostByUserSince(options model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.HasAutoResponsePostByUserSince(options, userId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6833,7 +7586,7 @@ func (s *OpenTracingLayerPostStore) InvalidateLastPostTimeCache(channelID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.PostStore.InvalidateLastPostTimeCache(channelID)
 
 }
@@ -6846,11 +7599,10 @@ func (s *OpenTracingLayerPostStore) Overwrite(rctx request.CTX, post *model.Post
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.Overwrite(rctx, post)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6864,11 +7616,10 @@ func (s *OpenTracingLayerPostStore) OverwriteMultiple(posts []*model.Post) ([]*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.PostStore.OverwriteMultiple(posts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -6882,11 +7633,10 @@ func (s *OpenTracingLayerPostStore) PermanentDeleteBatch(endTime int64, limit in
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.PermanentDeleteBatch(endTime, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6900,11 +7650,10 @@ func (s *OpenTracingLayerPostStore) PermanentDeleteBatchForRetentionPolicies(now
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.PostStore.PermanentDeleteBatchForRetentionPolicies(now, globalPolicyEndTime, limit, cursor)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -6918,11 +7667,10 @@ func (s *OpenTracingLayerPostStore) PermanentDeleteByChannel(rctx request.CTX, c
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostStore.PermanentDeleteByChannel(rctx, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -6936,11 +7684,10 @@ func (s *OpenTracingLayerPostStore) PermanentDeleteByUser(rctx request.CTX, user
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostStore.PermanentDeleteByUser(rctx, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -6954,11 +7701,10 @@ func (s *OpenTracingLayerPostStore) Save(rctx request.CTX, post *model.Post) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.Save(rctx, post)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -6972,11 +7718,10 @@ func (s *OpenTracingLayerPostStore) SaveMultiple(posts []*model.Post) ([]*model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.PostStore.SaveMultiple(posts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -6990,11 +7735,10 @@ func (s *OpenTracingLayerPostStore) Search(teamID string, userID string, params
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.Search(teamID, userID, params)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7008,11 +7752,10 @@ func (s *OpenTracingLayerPostStore) SearchPostsForUser(rctx request.CTX, paramsL
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.SearchPostsForUser(rctx, paramsList, userID, teamID, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7026,11 +7769,10 @@ func (s *OpenTracingLayerPostStore) SetPostReminder(reminder *model.PostReminder
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostStore.SetPostReminder(reminder)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7044,11 +7786,10 @@ func (s *OpenTracingLayerPostStore) Update(rctx request.CTX, newPost *model.Post
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostStore.Update(rctx, newPost, oldPost)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7062,11 +7803,10 @@ func (s *OpenTracingLayerPostAcknowledgementStore) Delete(acknowledgement *model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostAcknowledgementStore.Delete(acknowledgement)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7080,11 +7820,10 @@ func (s *OpenTracingLayerPostAcknowledgementStore) Get(postID string, userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostAcknowledgementStore.Get(postID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7098,11 +7837,10 @@ func (s *OpenTracingLayerPostAcknowledgementStore) GetForPost(postID string) ([]
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostAcknowledgementStore.GetForPost(postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7116,11 +7854,10 @@ func (s *OpenTracingLayerPostAcknowledgementStore) GetForPosts(postIds []string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostAcknowledgementStore.GetForPosts(postIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7134,11 +7871,10 @@ func (s *OpenTracingLayerPostAcknowledgementStore) Save(postID string, userID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostAcknowledgementStore.Save(postID, userID, acknowledgedAt)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7152,11 +7888,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) Delete(postIds []strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostPersistentNotificationStore.Delete(postIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7170,11 +7905,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) DeleteByChannel(channe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostPersistentNotificationStore.DeleteByChannel(channelIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7188,11 +7922,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) DeleteByTeam(teamIds [
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostPersistentNotificationStore.DeleteByTeam(teamIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7206,11 +7939,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) DeleteExpired(maxSentC
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostPersistentNotificationStore.DeleteExpired(maxSentCount)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7224,11 +7956,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) Get(params model.GetPe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostPersistentNotificationStore.Get(params)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7242,11 +7973,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) GetSingle(postID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostPersistentNotificationStore.GetSingle(postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7260,11 +7990,10 @@ func (s *OpenTracingLayerPostPersistentNotificationStore) UpdateLastActivity(pos
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PostPersistentNotificationStore.UpdateLastActivity(postIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7278,11 +8007,10 @@ func (s *OpenTracingLayerPostPriorityStore) GetForPost(postId string) (*model.Po
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostPriorityStore.GetForPost(postId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7296,11 +8024,10 @@ func (s *OpenTracingLayerPostPriorityStore) GetForPosts(ids []string) ([]*model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PostPriorityStore.GetForPosts(ids)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7314,11 +8041,10 @@ func (s *OpenTracingLayerPreferenceStore) CleanupFlagsBatch(limit int64) (int64,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.CleanupFlagsBatch(limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7332,11 +8058,10 @@ func (s *OpenTracingLayerPreferenceStore) Delete(userID string, category string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PreferenceStore.Delete(userID, category, name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7350,11 +8075,10 @@ func (s *OpenTracingLayerPreferenceStore) DeleteCategory(userID string, category
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PreferenceStore.DeleteCategory(userID, category)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7368,11 +8092,10 @@ func (s *OpenTracingLayerPreferenceStore) DeleteCategoryAndName(category string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PreferenceStore.DeleteCategoryAndName(category, name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7386,11 +8109,10 @@ func (s *OpenTracingLayerPreferenceStore) DeleteInvalidVisibleDmsGms() (int64, e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.DeleteInvalidVisibleDmsGms()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7404,11 +8126,10 @@ func (s *OpenTracingLayerPreferenceStore) DeleteOrphanedRows(limit int) (int64,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.DeleteOrphanedRows(limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7422,11 +8143,10 @@ func (s *OpenTracingLayerPreferenceStore) Get(userID string, category string, na
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.Get(userID, category, name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7440,11 +8160,10 @@ func (s *OpenTracingLayerPreferenceStore) GetAll(userID string) (model.Preferenc
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.GetAll(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7458,11 +8177,10 @@ func (s *OpenTracingLayerPreferenceStore) GetCategory(userID string, category st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.GetCategory(userID, category)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7476,11 +8194,10 @@ func (s *OpenTracingLayerPreferenceStore) GetCategoryAndName(category string, na
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.PreferenceStore.GetCategoryAndName(category, nane)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7494,11 +8211,10 @@ func (s *OpenTracingLayerPreferenceStore) PermanentDeleteByUser(userID string) e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PreferenceStore.PermanentDeleteByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7512,11 +8228,10 @@ func (s *OpenTracingLayerPreferenceStore) Save(preferences model.Preferences) er
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.PreferenceStore.Save(preferences)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7530,11 +8245,10 @@ func (s *OpenTracingLayerProductNoticesStore) Clear(notices []string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ProductNoticesStore.Clear(notices)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7548,11 +8262,10 @@ func (s *OpenTracingLayerProductNoticesStore) ClearOldNotices(currentNotices mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ProductNoticesStore.ClearOldNotices(currentNotices)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7566,11 +8279,10 @@ func (s *OpenTracingLayerProductNoticesStore) GetViews(userID string) ([]model.P
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ProductNoticesStore.GetViews(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7584,11 +8296,10 @@ func (s *OpenTracingLayerProductNoticesStore) View(userID string, notices []stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ProductNoticesStore.View(userID, notices)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7602,11 +8313,10 @@ func (s *OpenTracingLayerReactionStore) BulkGetForPosts(postIds []string) ([]*mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.BulkGetForPosts(postIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7620,11 +8330,10 @@ func (s *OpenTracingLayerReactionStore) Delete(reaction *model.Reaction) (*model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.Delete(reaction)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7638,11 +8347,10 @@ func (s *OpenTracingLayerReactionStore) DeleteAllWithEmojiName(emojiName string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ReactionStore.DeleteAllWithEmojiName(emojiName)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7656,11 +8364,10 @@ func (s *OpenTracingLayerReactionStore) DeleteOrphanedRowsByIds(r *model.Retenti
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ReactionStore.DeleteOrphanedRowsByIds(r)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7674,11 +8381,10 @@ func (s *OpenTracingLayerReactionStore) ExistsOnPost(postId string, emojiName st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.ExistsOnPost(postId, emojiName)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7692,11 +8398,10 @@ func (s *OpenTracingLayerReactionStore) GetForPost(postID string, allowFromCache
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.GetForPost(postID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7710,11 +8415,10 @@ func (s *OpenTracingLayerReactionStore) GetForPostSince(postId string, since int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.GetForPostSince(postId, since, excludeRemoteId, inclDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7728,11 +8432,10 @@ func (s *OpenTracingLayerReactionStore) GetSingle(userID string, postID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.GetSingle(userID, postID, remoteID, emojiName)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7746,11 +8449,10 @@ func (s *OpenTracingLayerReactionStore) GetUniqueCountForPost(postId string) (in
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.GetUniqueCountForPost(postId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7764,11 +8466,10 @@ func (s *OpenTracingLayerReactionStore) PermanentDeleteBatch(endTime int64, limi
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.PermanentDeleteBatch(endTime, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7782,11 +8483,10 @@ func (s *OpenTracingLayerReactionStore) PermanentDeleteByUser(userID string) err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ReactionStore.PermanentDeleteByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7800,11 +8500,10 @@ func (s *OpenTracingLayerReactionStore) Save(reaction *model.Reaction) (*model.R
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ReactionStore.Save(reaction)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7818,11 +8517,10 @@ func (s *OpenTracingLayerRemoteClusterStore) Delete(remoteClusterId string) (boo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.Delete(remoteClusterId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7836,11 +8534,10 @@ func (s *OpenTracingLayerRemoteClusterStore) Get(remoteClusterId string) (*model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.Get(remoteClusterId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7854,11 +8551,10 @@ func (s *OpenTracingLayerRemoteClusterStore) GetAll(offset int, limit int, filte
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.GetAll(offset, limit, filter)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7872,11 +8568,10 @@ func (s *OpenTracingLayerRemoteClusterStore) GetByPluginID(pluginID string) (*mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.GetByPluginID(pluginID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7890,11 +8585,10 @@ func (s *OpenTracingLayerRemoteClusterStore) Save(rc *model.RemoteCluster) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.Save(rc)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7908,11 +8602,10 @@ func (s *OpenTracingLayerRemoteClusterStore) SetLastPingAt(remoteClusterId strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RemoteClusterStore.SetLastPingAt(remoteClusterId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7926,11 +8619,10 @@ func (s *OpenTracingLayerRemoteClusterStore) Update(rc *model.RemoteCluster) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.Update(rc)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7944,11 +8636,10 @@ func (s *OpenTracingLayerRemoteClusterStore) UpdateTopics(remoteClusterId string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RemoteClusterStore.UpdateTopics(remoteClusterId, topics)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -7962,11 +8653,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) AddChannels(policyId string, chan
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RetentionPolicyStore.AddChannels(policyId, channelIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7980,11 +8670,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) AddTeams(policyId string, teamIds
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RetentionPolicyStore.AddTeams(policyId, teamIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -7998,11 +8687,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) Delete(id string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RetentionPolicyStore.Delete(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8016,11 +8704,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) DeleteOrphanedRows(limit int) (in
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.DeleteOrphanedRows(limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8034,11 +8721,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) Get(id string) (*model.RetentionP
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8052,11 +8738,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetAll(offset int, limit int) ([]
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetAll(offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8070,11 +8755,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetChannelPoliciesCountForUser(us
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetChannelPoliciesCountForUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8088,11 +8772,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetChannelPoliciesForUser(userID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetChannelPoliciesForUser(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8106,11 +8789,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetChannels(policyId string, offs
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetChannels(policyId, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8124,11 +8806,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetChannelsCount(policyId string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetChannelsCount(policyId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8142,11 +8823,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8160,11 +8840,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetIdsForDeletionByTableName(tabl
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetIdsForDeletionByTableName(tableName, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8178,11 +8857,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetTeamPoliciesCountForUser(userI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetTeamPoliciesCountForUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8196,11 +8874,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetTeamPoliciesForUser(userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetTeamPoliciesForUser(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8214,11 +8891,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetTeams(policyId string, offset
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetTeams(policyId, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8232,11 +8908,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) GetTeamsCount(policyId string) (i
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.GetTeamsCount(policyId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8250,11 +8925,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) Patch(patch *model.RetentionPolic
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.Patch(patch)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8268,11 +8942,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) RemoveChannels(policyId string, c
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RetentionPolicyStore.RemoveChannels(policyId, channelIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8286,11 +8959,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) RemoveTeams(policyId string, team
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RetentionPolicyStore.RemoveTeams(policyId, teamIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8304,11 +8976,10 @@ func (s *OpenTracingLayerRetentionPolicyStore) Save(policy *model.RetentionPolic
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RetentionPolicyStore.Save(policy)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8322,11 +8993,10 @@ func (s *OpenTracingLayerRoleStore) AllChannelSchemeRoles() ([]*model.Role, erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.AllChannelSchemeRoles()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8340,11 +9010,10 @@ func (s *OpenTracingLayerRoleStore) ChannelHigherScopedPermissions(roleNames []s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.ChannelHigherScopedPermissions(roleNames)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8358,11 +9027,10 @@ func (s *OpenTracingLayerRoleStore) ChannelRolesUnderTeamRole(roleName string) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.ChannelRolesUnderTeamRole(roleName)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8376,11 +9044,10 @@ func (s *OpenTracingLayerRoleStore) Delete(roleID string) (*model.Role, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.Delete(roleID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8394,11 +9061,10 @@ func (s *OpenTracingLayerRoleStore) Get(roleID string) (*model.Role, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.Get(roleID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8412,11 +9078,10 @@ func (s *OpenTracingLayerRoleStore) GetAll() ([]*model.Role, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.GetAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8430,11 +9095,10 @@ func (s *OpenTracingLayerRoleStore) GetByName(ctx context.Context, name string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.GetByName(ctx, name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8448,11 +9112,10 @@ func (s *OpenTracingLayerRoleStore) GetByNames(names []string) ([]*model.Role, e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.GetByNames(names)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8466,11 +9129,10 @@ func (s *OpenTracingLayerRoleStore) PermanentDeleteAll() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.RoleStore.PermanentDeleteAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8484,11 +9146,10 @@ func (s *OpenTracingLayerRoleStore) Save(role *model.Role) (*model.Role, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.RoleStore.Save(role)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8502,11 +9163,10 @@ func (s *OpenTracingLayerSchemeStore) CountByScope(scope string) (int64, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.CountByScope(scope)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8520,11 +9180,10 @@ func (s *OpenTracingLayerSchemeStore) CountWithoutPermission(scope string, permi
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.CountWithoutPermission(scope, permissionID, roleScope, roleType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8538,11 +9197,10 @@ func (s *OpenTracingLayerSchemeStore) Delete(schemeID string) (*model.Scheme, er
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.Delete(schemeID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8556,11 +9214,10 @@ func (s *OpenTracingLayerSchemeStore) Get(schemeID string) (*model.Scheme, error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.Get(schemeID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8574,11 +9231,10 @@ func (s *OpenTracingLayerSchemeStore) GetAllPage(scope string, offset int, limit
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.GetAllPage(scope, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8592,11 +9248,10 @@ func (s *OpenTracingLayerSchemeStore) GetByName(schemeName string) (*model.Schem
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.GetByName(schemeName)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8610,11 +9265,10 @@ func (s *OpenTracingLayerSchemeStore) PermanentDeleteAll() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SchemeStore.PermanentDeleteAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8628,11 +9282,10 @@ func (s *OpenTracingLayerSchemeStore) Save(scheme *model.Scheme) (*model.Scheme,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SchemeStore.Save(scheme)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8646,11 +9299,10 @@ func (s *OpenTracingLayerSessionStore) AnalyticsSessionCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.AnalyticsSessionCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8664,11 +9316,10 @@ func (s *OpenTracingLayerSessionStore) Cleanup(expiryTime int64, batchSize int64
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.Cleanup(expiryTime, batchSize)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8682,11 +9333,10 @@ func (s *OpenTracingLayerSessionStore) Get(c request.CTX, sessionIDOrToken strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.Get(c, sessionIDOrToken)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8700,11 +9350,10 @@ func (s *OpenTracingLayerSessionStore) GetLRUSessions(c request.CTX, userID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.GetLRUSessions(c, userID, limit, offset)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8718,11 +9367,10 @@ func (s *OpenTracingLayerSessionStore) GetSessions(c request.CTX, userID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.GetSessions(c, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8736,11 +9384,10 @@ func (s *OpenTracingLayerSessionStore) GetSessionsExpired(thresholdMillis int64,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.GetSessionsExpired(thresholdMillis, mobileOnly, unnotifiedOnly)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8754,11 +9401,10 @@ func (s *OpenTracingLayerSessionStore) GetSessionsWithActiveDeviceIds(userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.GetSessionsWithActiveDeviceIds(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8772,11 +9418,10 @@ func (s *OpenTracingLayerSessionStore) PermanentDeleteSessionsByUser(teamID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.PermanentDeleteSessionsByUser(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8790,11 +9435,10 @@ func (s *OpenTracingLayerSessionStore) Remove(sessionIDOrToken string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.Remove(sessionIDOrToken)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8808,11 +9452,10 @@ func (s *OpenTracingLayerSessionStore) RemoveAllSessions() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.RemoveAllSessions()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8826,11 +9469,10 @@ func (s *OpenTracingLayerSessionStore) Save(c request.CTX, session *model.Sessio
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.Save(c, session)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8844,11 +9486,10 @@ func (s *OpenTracingLayerSessionStore) UpdateDeviceId(id string, deviceID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.UpdateDeviceId(id, deviceID, expiresAt)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8862,11 +9503,10 @@ func (s *OpenTracingLayerSessionStore) UpdateExpiredNotify(sessionid string, not
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.UpdateExpiredNotify(sessionid, notified)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8880,11 +9520,10 @@ func (s *OpenTracingLayerSessionStore) UpdateExpiresAt(sessionID string, timesta
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.UpdateExpiresAt(sessionID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8898,11 +9537,10 @@ func (s *OpenTracingLayerSessionStore) UpdateLastActivityAt(sessionID string, ti
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.UpdateLastActivityAt(sessionID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8916,11 +9554,10 @@ func (s *OpenTracingLayerSessionStore) UpdateProps(session *model.Session) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SessionStore.UpdateProps(session)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -8934,11 +9571,10 @@ func (s *OpenTracingLayerSessionStore) UpdateRoles(userID string, roles string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SessionStore.UpdateRoles(userID, roles)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8952,11 +9588,10 @@ func (s *OpenTracingLayerSharedChannelStore) Delete(channelId string) (bool, err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.Delete(channelId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8970,11 +9605,10 @@ func (s *OpenTracingLayerSharedChannelStore) DeleteRemote(remoteId string) (bool
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.DeleteRemote(remoteId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -8988,11 +9622,10 @@ func (s *OpenTracingLayerSharedChannelStore) Get(channelId string) (*model.Share
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.Get(channelId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9006,11 +9639,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetAll(offset int, limit int, opts
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetAll(offset, limit, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9024,11 +9656,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetAllCount(opts model.SharedChanne
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetAllCount(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9042,11 +9673,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetAttachment(fileId string, remote
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetAttachment(fileId, remoteId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9060,11 +9690,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetRemote(id string) (*model.Shared
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetRemote(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9078,11 +9707,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetRemoteByIds(channelId string, re
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetRemoteByIds(channelId, remoteId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9096,11 +9724,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetRemoteForUser(remoteId string, u
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetRemoteForUser(remoteId, userId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9114,11 +9741,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetRemotes(offset int, limit int, o
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetRemotes(offset, limit, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9132,11 +9758,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetRemotesStatus(channelId string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetRemotesStatus(channelId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9150,11 +9775,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetSingleUser(userID string, channe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetSingleUser(userID, channelID, remoteID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9168,11 +9792,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetUsersForSync(filter model.GetUse
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetUsersForSync(filter)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9186,11 +9809,10 @@ func (s *OpenTracingLayerSharedChannelStore) GetUsersForUser(userID string) ([]*
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.GetUsersForUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9204,11 +9826,10 @@ func (s *OpenTracingLayerSharedChannelStore) HasChannel(channelID string) (bool,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.HasChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9222,11 +9843,10 @@ func (s *OpenTracingLayerSharedChannelStore) HasRemote(channelID string, remoteI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.HasRemote(channelID, remoteId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9240,11 +9860,10 @@ func (s *OpenTracingLayerSharedChannelStore) Save(sc *model.SharedChannel) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.Save(sc)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9258,11 +9877,10 @@ func (s *OpenTracingLayerSharedChannelStore) SaveAttachment(remote *model.Shared
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.SaveAttachment(remote)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9276,11 +9894,10 @@ func (s *OpenTracingLayerSharedChannelStore) SaveRemote(remote *model.SharedChan
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.SaveRemote(remote)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9294,11 +9911,10 @@ func (s *OpenTracingLayerSharedChannelStore) SaveUser(remote *model.SharedChanne
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.SaveUser(remote)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9312,11 +9928,10 @@ func (s *OpenTracingLayerSharedChannelStore) Update(sc *model.SharedChannel) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.Update(sc)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9330,11 +9945,10 @@ func (s *OpenTracingLayerSharedChannelStore) UpdateAttachmentLastSyncAt(id strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SharedChannelStore.UpdateAttachmentLastSyncAt(id, syncTime)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9348,11 +9962,10 @@ func (s *OpenTracingLayerSharedChannelStore) UpdateRemote(remote *model.SharedCh
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.UpdateRemote(remote)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9366,11 +9979,10 @@ func (s *OpenTracingLayerSharedChannelStore) UpdateRemoteCursor(id string, curso
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SharedChannelStore.UpdateRemoteCursor(id, cursor)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9384,11 +9996,10 @@ func (s *OpenTracingLayerSharedChannelStore) UpdateUserLastSyncAt(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SharedChannelStore.UpdateUserLastSyncAt(userID, channelID, remoteID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9402,11 +10013,10 @@ func (s *OpenTracingLayerSharedChannelStore) UpsertAttachment(remote *model.Shar
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SharedChannelStore.UpsertAttachment(remote)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9420,11 +10030,10 @@ func (s *OpenTracingLayerStatusStore) Get(userID string) (*model.Status, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.StatusStore.Get(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9438,11 +10047,10 @@ func (s *OpenTracingLayerStatusStore) GetByIds(userIds []string) ([]*model.Statu
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.StatusStore.GetByIds(userIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9456,11 +10064,10 @@ func (s *OpenTracingLayerStatusStore) GetTotalActiveUsersCount() (int64, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.StatusStore.GetTotalActiveUsersCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9474,11 +10081,10 @@ func (s *OpenTracingLayerStatusStore) ResetAll() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.StatusStore.ResetAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9492,11 +10098,10 @@ func (s *OpenTracingLayerStatusStore) SaveOrUpdate(status *model.Status) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.StatusStore.SaveOrUpdate(status)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9510,11 +10115,10 @@ func (s *OpenTracingLayerStatusStore) UpdateExpiredDNDStatuses() ([]*model.Statu
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.StatusStore.UpdateExpiredDNDStatuses()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9528,11 +10132,10 @@ func (s *OpenTracingLayerStatusStore) UpdateLastActivityAt(userID string, lastAc
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.StatusStore.UpdateLastActivityAt(userID, lastActivityAt)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9546,11 +10149,10 @@ func (s *OpenTracingLayerSystemStore) Get() (model.StringMap, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SystemStore.Get()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9564,11 +10166,10 @@ func (s *OpenTracingLayerSystemStore) GetByName(name string) (*model.System, err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SystemStore.GetByName(name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9582,11 +10183,10 @@ func (s *OpenTracingLayerSystemStore) InsertIfExists(system *model.System) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SystemStore.InsertIfExists(system)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9600,11 +10200,10 @@ func (s *OpenTracingLayerSystemStore) PermanentDeleteByName(name string) (*model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.SystemStore.PermanentDeleteByName(name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9618,11 +10217,10 @@ func (s *OpenTracingLayerSystemStore) Save(system *model.System) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SystemStore.Save(system)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9636,11 +10234,10 @@ func (s *OpenTracingLayerSystemStore) SaveOrUpdate(system *model.System) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SystemStore.SaveOrUpdate(system)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9654,11 +10251,10 @@ func (s *OpenTracingLayerSystemStore) Update(system *model.System) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.SystemStore.Update(system)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9672,11 +10268,10 @@ func (s *OpenTracingLayerTeamStore) AnalyticsGetTeamCountForScheme(schemeID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.AnalyticsGetTeamCountForScheme(schemeID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9690,11 +10285,10 @@ func (s *OpenTracingLayerTeamStore) AnalyticsTeamCount(opts *model.TeamSearch) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.AnalyticsTeamCount(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9708,11 +10302,10 @@ func (s *OpenTracingLayerTeamStore) ClearAllCustomRoleAssignments() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.ClearAllCustomRoleAssignments()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -9726,7 +10319,7 @@ func (s *OpenTracingLayerTeamStore) ClearCaches() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.TeamStore.ClearCaches()
 
 }
@@ -9739,11 +10332,10 @@ func (s *OpenTracingLayerTeamStore) Get(id string) (*model.Team, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9757,11 +10349,10 @@ func (s *OpenTracingLayerTeamStore) GetActiveMemberCount(teamID string, restrict
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetActiveMemberCount(teamID, restrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9775,11 +10366,10 @@ func (s *OpenTracingLayerTeamStore) GetAll() ([]*model.Team, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9793,11 +10383,10 @@ func (s *OpenTracingLayerTeamStore) GetAllForExportAfter(limit int, afterID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetAllForExportAfter(limit, afterID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9811,11 +10400,10 @@ func (s *OpenTracingLayerTeamStore) GetAllPage(offset int, limit int, opts *mode
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetAllPage(offset, limit, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9829,11 +10417,10 @@ func (s *OpenTracingLayerTeamStore) GetAllPrivateTeamListing() ([]*model.Team, e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetAllPrivateTeamListing()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9847,11 +10434,10 @@ func (s *OpenTracingLayerTeamStore) GetAllTeamListing() ([]*model.Team, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetAllTeamListing()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9865,11 +10451,10 @@ func (s *OpenTracingLayerTeamStore) GetByEmptyInviteID() ([]*model.Team, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetByEmptyInviteID()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9883,11 +10468,10 @@ func (s *OpenTracingLayerTeamStore) GetByInviteId(inviteID string) (*model.Team,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetByInviteId(inviteID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9901,11 +10485,10 @@ func (s *OpenTracingLayerTeamStore) GetByName(name string) (*model.Team, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetByName(name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9919,11 +10502,10 @@ func (s *OpenTracingLayerTeamStore) GetByNames(name []string) ([]*model.Team, er
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetByNames(name)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9937,11 +10519,10 @@ func (s *OpenTracingLayerTeamStore) GetChannelUnreadsForAllTeams(excludeTeamID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetChannelUnreadsForAllTeams(excludeTeamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9955,11 +10536,10 @@ func (s *OpenTracingLayerTeamStore) GetChannelUnreadsForTeam(teamID string, user
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetChannelUnreadsForTeam(teamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9973,11 +10553,10 @@ func (s *OpenTracingLayerTeamStore) GetCommonTeamIDsForMultipleUsers(userIDs []s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetCommonTeamIDsForMultipleUsers(userIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -9991,11 +10570,10 @@ func (s *OpenTracingLayerTeamStore) GetCommonTeamIDsForTwoUsers(userID string, o
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetCommonTeamIDsForTwoUsers(userID, otherUserID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10009,11 +10587,10 @@ func (s *OpenTracingLayerTeamStore) GetMany(ids []string) ([]*model.Team, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetMany(ids)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10027,11 +10604,10 @@ func (s *OpenTracingLayerTeamStore) GetMember(c request.CTX, teamID string, user
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetMember(c, teamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10045,11 +10621,10 @@ func (s *OpenTracingLayerTeamStore) GetMembers(teamID string, offset int, limit
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetMembers(teamID, offset, limit, teamMembersGetOptions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10063,11 +10638,10 @@ func (s *OpenTracingLayerTeamStore) GetMembersByIds(teamID string, userIds []str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetMembersByIds(teamID, userIds, restrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10081,11 +10655,10 @@ func (s *OpenTracingLayerTeamStore) GetTeamMembersForExport(userID string) ([]*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetTeamMembersForExport(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10099,11 +10672,10 @@ func (s *OpenTracingLayerTeamStore) GetTeamsByScheme(schemeID string, offset int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetTeamsByScheme(schemeID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10117,11 +10689,10 @@ func (s *OpenTracingLayerTeamStore) GetTeamsByUserId(userID string) ([]*model.Te
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetTeamsByUserId(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10135,11 +10706,10 @@ func (s *OpenTracingLayerTeamStore) GetTeamsForUser(c request.CTX, userID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetTeamsForUser(c, userID, excludeTeamID, includeDeleted)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10153,11 +10723,10 @@ func (s *OpenTracingLayerTeamStore) GetTeamsForUserWithPagination(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetTeamsForUserWithPagination(userID, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10171,11 +10740,10 @@ func (s *OpenTracingLayerTeamStore) GetTotalMemberCount(teamID string, restricti
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetTotalMemberCount(teamID, restrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10189,11 +10757,10 @@ func (s *OpenTracingLayerTeamStore) GetUserTeamIds(userID string, allowFromCache
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GetUserTeamIds(userID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10207,11 +10774,10 @@ func (s *OpenTracingLayerTeamStore) GroupSyncedTeamCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.GroupSyncedTeamCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10225,7 +10791,7 @@ func (s *OpenTracingLayerTeamStore) InvalidateAllTeamIdsForUser(userID string) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.TeamStore.InvalidateAllTeamIdsForUser(userID)
 
 }
@@ -10238,11 +10804,10 @@ func (s *OpenTracingLayerTeamStore) MigrateTeamMembers(fromTeamID string, fromUs
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.MigrateTeamMembers(fromTeamID, fromUserID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10256,11 +10821,10 @@ func (s *OpenTracingLayerTeamStore) PermanentDelete(teamID string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.PermanentDelete(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10274,11 +10838,10 @@ func (s *OpenTracingLayerTeamStore) RemoveAllMembersByTeam(teamID string) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.RemoveAllMembersByTeam(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10292,11 +10855,10 @@ func (s *OpenTracingLayerTeamStore) RemoveAllMembersByUser(ctx request.CTX, user
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.RemoveAllMembersByUser(ctx, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10310,11 +10872,10 @@ func (s *OpenTracingLayerTeamStore) RemoveMember(rctx request.CTX, teamID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.RemoveMember(rctx, teamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10328,11 +10889,10 @@ func (s *OpenTracingLayerTeamStore) RemoveMembers(rctx request.CTX, teamID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.RemoveMembers(rctx, teamID, userIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10346,11 +10906,10 @@ func (s *OpenTracingLayerTeamStore) ResetAllTeamSchemes() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.ResetAllTeamSchemes()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10364,11 +10923,10 @@ func (s *OpenTracingLayerTeamStore) Save(team *model.Team) (*model.Team, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.Save(team)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10382,11 +10940,10 @@ func (s *OpenTracingLayerTeamStore) SaveMember(rctx request.CTX, member *model.T
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.SaveMember(rctx, member, maxUsersPerTeam)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10400,11 +10957,10 @@ func (s *OpenTracingLayerTeamStore) SaveMultipleMembers(members []*model.TeamMem
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.SaveMultipleMembers(members, maxUsersPerTeam)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10418,11 +10974,10 @@ func (s *OpenTracingLayerTeamStore) SearchAll(opts *model.TeamSearch) ([]*model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.SearchAll(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10436,11 +10991,10 @@ func (s *OpenTracingLayerTeamStore) SearchAllPaged(opts *model.TeamSearch) ([]*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.TeamStore.SearchAllPaged(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -10454,11 +11008,10 @@ func (s *OpenTracingLayerTeamStore) SearchOpen(opts *model.TeamSearch) ([]*model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.SearchOpen(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10472,11 +11025,10 @@ func (s *OpenTracingLayerTeamStore) SearchPrivate(opts *model.TeamSearch) ([]*mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.SearchPrivate(opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10490,11 +11042,10 @@ func (s *OpenTracingLayerTeamStore) Update(team *model.Team) (*model.Team, error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.Update(team)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10508,11 +11059,10 @@ func (s *OpenTracingLayerTeamStore) UpdateLastTeamIconUpdate(teamID string, curT
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.UpdateLastTeamIconUpdate(teamID, curTime)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10526,11 +11076,10 @@ func (s *OpenTracingLayerTeamStore) UpdateMember(rctx request.CTX, member *model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.UpdateMember(rctx, member)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10544,11 +11093,10 @@ func (s *OpenTracingLayerTeamStore) UpdateMembersRole(teamID string, userIDs []s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TeamStore.UpdateMembersRole(teamID, userIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10562,11 +11110,10 @@ func (s *OpenTracingLayerTeamStore) UpdateMultipleMembers(members []*model.TeamM
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.UpdateMultipleMembers(members)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10580,11 +11127,10 @@ func (s *OpenTracingLayerTeamStore) UserBelongsToTeams(userID string, teamIds []
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TeamStore.UserBelongsToTeams(userID, teamIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10598,11 +11144,10 @@ func (s *OpenTracingLayerTermsOfServiceStore) Get(id string, allowFromCache bool
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TermsOfServiceStore.Get(id, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10616,11 +11161,10 @@ func (s *OpenTracingLayerTermsOfServiceStore) GetLatest(allowFromCache bool) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TermsOfServiceStore.GetLatest(allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10634,11 +11178,10 @@ func (s *OpenTracingLayerTermsOfServiceStore) Save(termsOfService *model.TermsOf
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TermsOfServiceStore.Save(termsOfService)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10652,11 +11195,10 @@ func (s *OpenTracingLayerThreadStore) DeleteMembershipForUser(userId string, pos
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ThreadStore.DeleteMembershipForUser(userId, postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10670,11 +11212,10 @@ func (s *OpenTracingLayerThreadStore) DeleteMembershipsForChannel(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ThreadStore.DeleteMembershipsForChannel(userID, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10688,11 +11229,10 @@ func (s *OpenTracingLayerThreadStore) DeleteOrphanedRows(limit int) (int64, erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.DeleteOrphanedRows(limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10706,11 +11246,10 @@ func (s *OpenTracingLayerThreadStore) Get(id string) (*model.Thread, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.Get(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10724,11 +11263,10 @@ func (s *OpenTracingLayerThreadStore) GetMembershipForUser(userId string, postID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetMembershipForUser(userId, postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10742,11 +11280,10 @@ func (s *OpenTracingLayerThreadStore) GetMembershipsForUser(userId string, teamI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetMembershipsForUser(userId, teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10760,11 +11297,10 @@ func (s *OpenTracingLayerThreadStore) GetTeamsUnreadForUser(userID string, teamI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetTeamsUnreadForUser(userID, teamIDs, includeUrgentMentionCount)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10778,11 +11314,10 @@ func (s *OpenTracingLayerThreadStore) GetThreadFollowers(threadID string, fetchO
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetThreadFollowers(threadID, fetchOnlyActive)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10796,11 +11331,10 @@ func (s *OpenTracingLayerThreadStore) GetThreadForUser(threadMembership *model.T
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetThreadForUser(threadMembership, extended, postPriorityIsEnabled)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10814,11 +11348,10 @@ func (s *OpenTracingLayerThreadStore) GetThreadMembershipsForExport(postID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetThreadMembershipsForExport(postID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10832,11 +11365,10 @@ func (s *OpenTracingLayerThreadStore) GetThreadUnreadReplyCount(threadMembership
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetThreadUnreadReplyCount(threadMembership)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10850,11 +11382,10 @@ func (s *OpenTracingLayerThreadStore) GetThreadsForUser(userId string, teamID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetThreadsForUser(userId, teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10868,11 +11399,10 @@ func (s *OpenTracingLayerThreadStore) GetTotalThreads(userId string, teamID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetTotalThreads(userId, teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10886,11 +11416,10 @@ func (s *OpenTracingLayerThreadStore) GetTotalUnreadMentions(userId string, team
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetTotalUnreadMentions(userId, teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10904,11 +11433,10 @@ func (s *OpenTracingLayerThreadStore) GetTotalUnreadThreads(userId string, teamI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetTotalUnreadThreads(userId, teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10922,11 +11450,10 @@ func (s *OpenTracingLayerThreadStore) GetTotalUnreadUrgentMentions(userId string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.GetTotalUnreadUrgentMentions(userId, teamID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10940,11 +11467,10 @@ func (s *OpenTracingLayerThreadStore) MaintainMembership(userID string, postID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.MaintainMembership(userID, postID, opts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10958,11 +11484,10 @@ func (s *OpenTracingLayerThreadStore) MaintainMultipleFromImport(memberships []*
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.MaintainMultipleFromImport(memberships)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -10976,11 +11501,10 @@ func (s *OpenTracingLayerThreadStore) MarkAllAsRead(userID string, threadIds []s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ThreadStore.MarkAllAsRead(userID, threadIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -10994,11 +11518,10 @@ func (s *OpenTracingLayerThreadStore) MarkAllAsReadByChannels(userID string, cha
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ThreadStore.MarkAllAsReadByChannels(userID, channelIDs)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11012,11 +11535,10 @@ func (s *OpenTracingLayerThreadStore) MarkAllAsReadByTeam(userID string, teamID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ThreadStore.MarkAllAsReadByTeam(userID, teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11030,11 +11552,10 @@ func (s *OpenTracingLayerThreadStore) MarkAsRead(userID string, threadID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.ThreadStore.MarkAsRead(userID, threadID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11048,11 +11569,10 @@ func (s *OpenTracingLayerThreadStore) PermanentDeleteBatchForRetentionPolicies(n
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ThreadStore.PermanentDeleteBatchForRetentionPolicies(now, globalPolicyEndTime, limit, cursor)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -11066,11 +11586,10 @@ func (s *OpenTracingLayerThreadStore) PermanentDeleteBatchThreadMembershipsForRe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, resultVar1, err := s.ThreadStore.PermanentDeleteBatchThreadMembershipsForRetentionPolicies(now, globalPolicyEndTime, limit, cursor)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, resultVar1, err
@@ -11084,11 +11603,10 @@ func (s *OpenTracingLayerThreadStore) SaveMultipleMemberships(memberships []*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.SaveMultipleMemberships(memberships)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11102,11 +11620,10 @@ func (s *OpenTracingLayerThreadStore) UpdateMembership(membership *model.ThreadM
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.ThreadStore.UpdateMembership(membership)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11120,7 +11637,7 @@ func (s *OpenTracingLayerTokenStore) Cleanup(expiryTime int64) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.TokenStore.Cleanup(expiryTime)
 
 }
@@ -11133,11 +11650,10 @@ func (s *OpenTracingLayerTokenStore) Delete(token string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TokenStore.Delete(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11151,11 +11667,10 @@ func (s *OpenTracingLayerTokenStore) GetAllTokensByType(tokenType string) ([]*mo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TokenStore.GetAllTokensByType(tokenType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11169,11 +11684,10 @@ func (s *OpenTracingLayerTokenStore) GetByToken(token string) (*model.Token, err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.TokenStore.GetByToken(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11187,11 +11701,10 @@ func (s *OpenTracingLayerTokenStore) RemoveAllTokensByType(tokenType string) err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TokenStore.RemoveAllTokensByType(tokenType)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11205,11 +11718,10 @@ func (s *OpenTracingLayerTokenStore) Save(recovery *model.Token) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.TokenStore.Save(recovery)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11223,11 +11735,10 @@ func (s *OpenTracingLayerUploadSessionStore) Delete(id string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UploadSessionStore.Delete(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11241,11 +11752,10 @@ func (s *OpenTracingLayerUploadSessionStore) Get(c request.CTX, id string) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UploadSessionStore.Get(c, id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11259,11 +11769,10 @@ func (s *OpenTracingLayerUploadSessionStore) GetForUser(userID string) ([]*model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UploadSessionStore.GetForUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11277,11 +11786,10 @@ func (s *OpenTracingLayerUploadSessionStore) Save(session *model.UploadSession)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UploadSessionStore.Save(session)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11295,11 +11803,10 @@ func (s *OpenTracingLayerUploadSessionStore) Update(session *model.UploadSession
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UploadSessionStore.Update(session)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11313,11 +11820,10 @@ func (s *OpenTracingLayerUserStore) AnalyticsActiveCount(timestamp int64, option
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AnalyticsActiveCount(timestamp, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11331,11 +11837,10 @@ func (s *OpenTracingLayerUserStore) AnalyticsActiveCountForPeriod(startTime int6
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AnalyticsActiveCountForPeriod(startTime, endTime, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11349,11 +11854,10 @@ func (s *OpenTracingLayerUserStore) AnalyticsGetExternalUsers(hostDomain string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AnalyticsGetExternalUsers(hostDomain)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11367,11 +11871,10 @@ func (s *OpenTracingLayerUserStore) AnalyticsGetGuestCount() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AnalyticsGetGuestCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11385,11 +11888,10 @@ func (s *OpenTracingLayerUserStore) AnalyticsGetInactiveUsersCount() (int64, err
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AnalyticsGetInactiveUsersCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11403,11 +11905,10 @@ func (s *OpenTracingLayerUserStore) AnalyticsGetSystemAdminCount() (int64, error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AnalyticsGetSystemAdminCount()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11421,11 +11922,10 @@ func (s *OpenTracingLayerUserStore) AutocompleteUsersInChannel(rctx request.CTX,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.AutocompleteUsersInChannel(rctx, teamID, channelID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11439,11 +11939,10 @@ func (s *OpenTracingLayerUserStore) ClearAllCustomRoleAssignments() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.ClearAllCustomRoleAssignments()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -11457,7 +11956,7 @@ func (s *OpenTracingLayerUserStore) ClearCaches() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.UserStore.ClearCaches()
 
 }
@@ -11470,11 +11969,10 @@ func (s *OpenTracingLayerUserStore) Count(options model.UserCountOptions) (int64
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.Count(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11488,11 +11986,10 @@ func (s *OpenTracingLayerUserStore) DeactivateGuests() ([]string, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.DeactivateGuests()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11506,11 +12003,10 @@ func (s *OpenTracingLayerUserStore) DemoteUserToGuest(userID string) (*model.Use
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.DemoteUserToGuest(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11524,11 +12020,10 @@ func (s *OpenTracingLayerUserStore) Get(ctx context.Context, id string) (*model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.Get(ctx, id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11542,11 +12037,10 @@ func (s *OpenTracingLayerUserStore) GetAll() ([]*model.User, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAll()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11560,11 +12054,10 @@ func (s *OpenTracingLayerUserStore) GetAllAfter(limit int, afterID string) ([]*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAllAfter(limit, afterID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11578,11 +12071,10 @@ func (s *OpenTracingLayerUserStore) GetAllNotInAuthService(authServices []string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAllNotInAuthService(authServices)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11596,11 +12088,10 @@ func (s *OpenTracingLayerUserStore) GetAllProfiles(options *model.UserGetOptions
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAllProfiles(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11614,11 +12105,10 @@ func (s *OpenTracingLayerUserStore) GetAllProfilesInChannel(rctx context.Context
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAllProfilesInChannel(rctx, channelID, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11632,11 +12122,10 @@ func (s *OpenTracingLayerUserStore) GetAllUsingAuthService(authService string) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAllUsingAuthService(authService)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11650,11 +12139,10 @@ func (s *OpenTracingLayerUserStore) GetAnyUnreadPostCountForChannel(userID strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetAnyUnreadPostCountForChannel(userID, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11668,11 +12156,10 @@ func (s *OpenTracingLayerUserStore) GetByAuth(authData *string, authService stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetByAuth(authData, authService)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11686,11 +12173,10 @@ func (s *OpenTracingLayerUserStore) GetByEmail(email string) (*model.User, error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetByEmail(email)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11704,11 +12190,10 @@ func (s *OpenTracingLayerUserStore) GetByRemoteID(remoteID string) (*model.User,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetByRemoteID(remoteID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11722,11 +12207,10 @@ func (s *OpenTracingLayerUserStore) GetByUsername(username string) (*model.User,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetByUsername(username)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11740,11 +12224,10 @@ func (s *OpenTracingLayerUserStore) GetChannelGroupUsers(channelID string) ([]*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetChannelGroupUsers(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11758,7 +12241,7 @@ func (s *OpenTracingLayerUserStore) GetEtagForAllProfiles() string {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result := s.UserStore.GetEtagForAllProfiles()
 	return result
 }
@@ -11771,7 +12254,7 @@ func (s *OpenTracingLayerUserStore) GetEtagForProfiles(teamID string) string {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result := s.UserStore.GetEtagForProfiles(teamID)
 	return result
 }
@@ -11784,7 +12267,7 @@ func (s *OpenTracingLayerUserStore) GetEtagForProfilesNotInTeam(teamID string) s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result := s.UserStore.GetEtagForProfilesNotInTeam(teamID)
 	return result
 }
@@ -11797,11 +12280,10 @@ func (s *OpenTracingLayerUserStore) GetForLogin(loginID string, allowSignInWithU
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetForLogin(loginID, allowSignInWithUsername, allowSignInWithEmail)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11815,11 +12297,10 @@ func (s *OpenTracingLayerUserStore) GetKnownUsers(userID string) ([]string, erro
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetKnownUsers(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11833,11 +12314,10 @@ func (s *OpenTracingLayerUserStore) GetMany(ctx context.Context, ids []string) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetMany(ctx, ids)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11851,11 +12331,10 @@ func (s *OpenTracingLayerUserStore) GetNewUsersForTeam(teamID string, offset int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetNewUsersForTeam(teamID, offset, limit, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11869,11 +12348,10 @@ func (s *OpenTracingLayerUserStore) GetProfileByGroupChannelIdsForUser(userID st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfileByGroupChannelIdsForUser(userID, channelIds)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11887,11 +12365,10 @@ func (s *OpenTracingLayerUserStore) GetProfileByIds(ctx context.Context, userIds
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfileByIds(ctx, userIds, options, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11905,11 +12382,10 @@ func (s *OpenTracingLayerUserStore) GetProfiles(options *model.UserGetOptions) (
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfiles(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11923,11 +12399,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesByUsernames(usernames []string, v
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesByUsernames(usernames, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11941,11 +12416,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesInChannel(options *model.UserGetO
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesInChannel(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11959,11 +12433,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesInChannelByAdmin(options *model.U
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesInChannelByAdmin(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11977,11 +12450,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesInChannelByStatus(options *model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesInChannelByStatus(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -11995,11 +12467,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesNotInChannel(teamID string, chann
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesNotInChannel(teamID, channelId, groupConstrained, offset, limit, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12013,11 +12484,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesNotInTeam(teamID string, groupCon
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesNotInTeam(teamID, groupConstrained, offset, limit, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12031,11 +12501,10 @@ func (s *OpenTracingLayerUserStore) GetProfilesWithoutTeam(options *model.UserGe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetProfilesWithoutTeam(options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12049,11 +12518,10 @@ func (s *OpenTracingLayerUserStore) GetRecentlyActiveUsersForTeam(teamID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetRecentlyActiveUsersForTeam(teamID, offset, limit, viewRestrictions)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12067,11 +12535,10 @@ func (s *OpenTracingLayerUserStore) GetSystemAdminProfiles() (map[string]*model.
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetSystemAdminProfiles()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12085,11 +12552,10 @@ func (s *OpenTracingLayerUserStore) GetTeamGroupUsers(teamID string) ([]*model.U
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetTeamGroupUsers(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12103,11 +12569,10 @@ func (s *OpenTracingLayerUserStore) GetUnreadCount(userID string, isCRTEnabled b
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetUnreadCount(userID, isCRTEnabled)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12121,11 +12586,10 @@ func (s *OpenTracingLayerUserStore) GetUnreadCountForChannel(userID string, chan
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetUnreadCountForChannel(userID, channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12139,11 +12603,10 @@ func (s *OpenTracingLayerUserStore) GetUserCountForReport(filter *model.UserRepo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetUserCountForReport(filter)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12157,11 +12620,10 @@ func (s *OpenTracingLayerUserStore) GetUserReport(filter *model.UserReportOption
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetUserReport(filter)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12175,11 +12637,10 @@ func (s *OpenTracingLayerUserStore) GetUsersBatchForIndexing(startTime int64, st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetUsersBatchForIndexing(startTime, startFileID, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12193,11 +12654,10 @@ func (s *OpenTracingLayerUserStore) GetUsersWithInvalidEmails(page int, perPage
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.GetUsersWithInvalidEmails(page, perPage, restrictedDomains)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12211,11 +12671,10 @@ func (s *OpenTracingLayerUserStore) InferSystemInstallDate() (int64, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.InferSystemInstallDate()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12229,11 +12688,10 @@ func (s *OpenTracingLayerUserStore) InsertUsers(users []*model.User) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.InsertUsers(users)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12247,7 +12705,7 @@ func (s *OpenTracingLayerUserStore) InvalidateProfileCacheForUser(userID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.UserStore.InvalidateProfileCacheForUser(userID)
 
 }
@@ -12260,7 +12718,7 @@ func (s *OpenTracingLayerUserStore) InvalidateProfilesInChannelCache(channelID s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.UserStore.InvalidateProfilesInChannelCache(channelID)
 
 }
@@ -12273,7 +12731,7 @@ func (s *OpenTracingLayerUserStore) InvalidateProfilesInChannelCacheByUser(userI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.UserStore.InvalidateProfilesInChannelCacheByUser(userID)
 
 }
@@ -12286,11 +12744,10 @@ func (s *OpenTracingLayerUserStore) IsEmpty(excludeBots bool) (bool, error) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.IsEmpty(excludeBots)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12304,11 +12761,10 @@ func (s *OpenTracingLayerUserStore) PermanentDelete(rctx request.CTX, userID str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.PermanentDelete(rctx, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12322,11 +12778,10 @@ func (s *OpenTracingLayerUserStore) PromoteGuestToUser(userID string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.PromoteGuestToUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12340,11 +12795,10 @@ func (s *OpenTracingLayerUserStore) RefreshPostStatsForUsers() error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.RefreshPostStatsForUsers()
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12358,11 +12812,10 @@ func (s *OpenTracingLayerUserStore) ResetAuthDataToEmailForUsers(service string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.ResetAuthDataToEmailForUsers(service, userIDs, includeDeleted, dryRun)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12376,11 +12829,10 @@ func (s *OpenTracingLayerUserStore) ResetLastPictureUpdate(userID string) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.ResetLastPictureUpdate(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12394,11 +12846,10 @@ func (s *OpenTracingLayerUserStore) Save(rctx request.CTX, user *model.User) (*m
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.Save(rctx, user)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12412,11 +12863,10 @@ func (s *OpenTracingLayerUserStore) Search(rctx request.CTX, teamID string, term
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.Search(rctx, teamID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12430,11 +12880,10 @@ func (s *OpenTracingLayerUserStore) SearchInChannel(channelID string, term strin
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.SearchInChannel(channelID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12448,11 +12897,10 @@ func (s *OpenTracingLayerUserStore) SearchInGroup(groupID string, term string, o
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.SearchInGroup(groupID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12466,11 +12914,10 @@ func (s *OpenTracingLayerUserStore) SearchNotInChannel(teamID string, channelID
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.SearchNotInChannel(teamID, channelID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12484,11 +12931,10 @@ func (s *OpenTracingLayerUserStore) SearchNotInGroup(groupID string, term string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.SearchNotInGroup(groupID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12502,16 +12948,32 @@ func (s *OpenTracingLayerUserStore) SearchNotInTeam(notInTeamID string, term str
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.SearchNotInTeam(notInTeamID, term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
 }
 
+func (s *OpenTracingLayerUserStore) SearchScim(filter *model.ScimFilter, offset int, limit int) ([]*model.User, int64, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "UserStore.SearchScim")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, resultVar1, err := s.UserStore.SearchScim(filter, offset, limit)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, resultVar1, err
+}
+
 func (s *OpenTracingLayerUserStore) SearchWithoutTeam(term string, options *model.UserSearchOptions) ([]*model.User, error) {
 	origCtx := s.Root.Store.Context()
 	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "UserStore.SearchWithoutTeam")
@@ -12520,11 +12982,10 @@ func (s *OpenTracingLayerUserStore) SearchWithoutTeam(term string, options *mode
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.SearchWithoutTeam(term, options)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12538,11 +12999,10 @@ func (s *OpenTracingLayerUserStore) Update(rctx request.CTX, user *model.User, a
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.Update(rctx, user, allowRoleUpdate)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12556,11 +13016,10 @@ func (s *OpenTracingLayerUserStore) UpdateAuthData(userID string, service string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.UpdateAuthData(userID, service, authData, email, resetMfa)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12574,11 +13033,10 @@ func (s *OpenTracingLayerUserStore) UpdateFailedPasswordAttempts(userID string,
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdateFailedPasswordAttempts(userID, attempts)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12592,11 +13050,10 @@ func (s *OpenTracingLayerUserStore) UpdateLastLogin(userID string, lastLogin int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdateLastLogin(userID, lastLogin)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12610,11 +13067,10 @@ func (s *OpenTracingLayerUserStore) UpdateLastPictureUpdate(userID string) error
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdateLastPictureUpdate(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12628,11 +13084,10 @@ func (s *OpenTracingLayerUserStore) UpdateMfaActive(userID string, active bool)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdateMfaActive(userID, active)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12646,11 +13101,10 @@ func (s *OpenTracingLayerUserStore) UpdateMfaSecret(userID string, secret string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdateMfaSecret(userID, secret)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12664,11 +13118,10 @@ func (s *OpenTracingLayerUserStore) UpdateNotifyProps(userID string, props map[s
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdateNotifyProps(userID, props)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12682,11 +13135,27 @@ func (s *OpenTracingLayerUserStore) UpdatePassword(userID string, newPassword st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserStore.UpdatePassword(userID, newPassword)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerUserStore) UpdatePasswordHash(userID string, currentHash string, newHash string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "UserStore.UpdatePasswordHash")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.UserStore.UpdatePasswordHash(userID, currentHash, newHash)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12700,11 +13169,10 @@ func (s *OpenTracingLayerUserStore) UpdateUpdateAt(userID string) (int64, error)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.UpdateUpdateAt(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12718,11 +13186,10 @@ func (s *OpenTracingLayerUserStore) VerifyEmail(userID string, email string) (st
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserStore.VerifyEmail(userID, email)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12736,11 +13203,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) Delete(tokenID string) error {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserAccessTokenStore.Delete(tokenID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12754,11 +13220,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) DeleteAllForUser(userID string) e
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserAccessTokenStore.DeleteAllForUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12772,11 +13237,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) Get(tokenID string) (*model.UserA
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserAccessTokenStore.Get(tokenID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12790,11 +13254,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) GetAll(offset int, limit int) ([]
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserAccessTokenStore.GetAll(offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12808,11 +13271,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) GetByToken(tokenString string) (*
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserAccessTokenStore.GetByToken(tokenString)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12826,11 +13288,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) GetByUser(userID string, page int
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserAccessTokenStore.GetByUser(userID, page, perPage)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12844,11 +13305,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) Save(token *model.UserAccessToken
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserAccessTokenStore.Save(token)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12862,11 +13322,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) Search(term string) ([]*model.Use
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserAccessTokenStore.Search(term)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12880,11 +13339,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) UpdateTokenDisable(tokenID string
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserAccessTokenStore.UpdateTokenDisable(tokenID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12898,11 +13356,10 @@ func (s *OpenTracingLayerUserAccessTokenStore) UpdateTokenEnable(tokenID string)
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserAccessTokenStore.UpdateTokenEnable(tokenID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12916,11 +13373,10 @@ func (s *OpenTracingLayerUserTermsOfServiceStore) Delete(userID string, termsOfS
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.UserTermsOfServiceStore.Delete(userID, termsOfServiceId)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -12934,11 +13390,10 @@ func (s *OpenTracingLayerUserTermsOfServiceStore) GetByUser(userID string) (*mod
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserTermsOfServiceStore.GetByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12952,11 +13407,214 @@ func (s *OpenTracingLayerUserTermsOfServiceStore) Save(userTermsOfService *model
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.UserTermsOfServiceStore.Save(userTermsOfService)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) Delete(id string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.Delete")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.WebAuthnCredentialStore.Delete(id)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) Get(id string) (*model.WebAuthnCredential, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.Get")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebAuthnCredentialStore.Get(id)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.GetByCredentialId")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebAuthnCredentialStore.GetByCredentialId(credentialID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) GetForUser(userID string) ([]*model.WebAuthnCredential, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.GetForUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebAuthnCredentialStore.GetForUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) PermanentDeleteByUser(userID string) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.PermanentDeleteByUser")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.WebAuthnCredentialStore.PermanentDeleteByUser(userID)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebAuthnCredentialStore.Save(credential)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebAuthnCredentialStore) UpdateSignCount(id string, signCount int64, lastUsedAt int64) error {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebAuthnCredentialStore.UpdateSignCount")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	err := s.WebAuthnCredentialStore.UpdateSignCount(id, signCount, lastUsedAt)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return err
+}
+
+func (s *OpenTracingLayerWebSocketEventStore) DeleteOlderThan(createAt int64, limit int) (int64, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebSocketEventStore.DeleteOlderThan")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebSocketEventStore.DeleteOlderThan(createAt, limit)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebSocketEventStore) GetSeqRange() (int64, int64, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebSocketEventStore.GetSeqRange")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, resultVar1, err := s.WebSocketEventStore.GetSeqRange()
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, resultVar1, err
+}
+
+func (s *OpenTracingLayerWebSocketEventStore) GetSince(userID string, seq int64, overlap int64, limit int) ([]*model.WebSocketEventLogEntry, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebSocketEventStore.GetSince")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebSocketEventStore.GetSince(userID, seq, overlap, limit)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebSocketEventStore) Save(entry *model.WebSocketEventLogEntry) (*model.WebSocketEventLogEntry, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebSocketEventStore.Save")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebSocketEventStore.Save(entry)
+	if err != nil {
+		tracing.SetError(span, err)
+	}
+
+	return result, err
+}
+
+func (s *OpenTracingLayerWebSocketEventStore) SaveMultiple(entries []*model.WebSocketEventLogEntry) ([]*model.WebSocketEventLogEntry, error) {
+	origCtx := s.Root.Store.Context()
+	span, newCtx := tracing.StartSpanWithParentByContext(s.Root.Store.Context(), "WebSocketEventStore.SaveMultiple")
+	s.Root.Store.SetContext(newCtx)
+	defer func() {
+		s.Root.Store.SetContext(origCtx)
+	}()
+
+	defer span.End()
+	result, err := s.WebSocketEventStore.SaveMultiple(entries)
+	if err != nil {
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12970,11 +13628,10 @@ func (s *OpenTracingLayerWebhookStore) AnalyticsIncomingCount(teamID string, use
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.AnalyticsIncomingCount(teamID, userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -12988,11 +13645,10 @@ func (s *OpenTracingLayerWebhookStore) AnalyticsOutgoingCount(teamID string) (in
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.AnalyticsOutgoingCount(teamID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13006,7 +13662,7 @@ func (s *OpenTracingLayerWebhookStore) ClearCaches() {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.WebhookStore.ClearCaches()
 
 }
@@ -13019,11 +13675,10 @@ func (s *OpenTracingLayerWebhookStore) DeleteIncoming(webhookID string, timestam
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.WebhookStore.DeleteIncoming(webhookID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -13037,11 +13692,10 @@ func (s *OpenTracingLayerWebhookStore) DeleteOutgoing(webhookID string, timestam
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.WebhookStore.DeleteOutgoing(webhookID, timestamp)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -13055,11 +13709,10 @@ func (s *OpenTracingLayerWebhookStore) GetIncoming(id string, allowFromCache boo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetIncoming(id, allowFromCache)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13073,11 +13726,10 @@ func (s *OpenTracingLayerWebhookStore) GetIncomingByChannel(channelID string) ([
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetIncomingByChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13091,11 +13743,10 @@ func (s *OpenTracingLayerWebhookStore) GetIncomingByTeam(teamID string, offset i
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetIncomingByTeam(teamID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13109,11 +13760,10 @@ func (s *OpenTracingLayerWebhookStore) GetIncomingByTeamByUser(teamID string, us
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetIncomingByTeamByUser(teamID, userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13127,11 +13777,10 @@ func (s *OpenTracingLayerWebhookStore) GetIncomingList(offset int, limit int) ([
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetIncomingList(offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13145,11 +13794,10 @@ func (s *OpenTracingLayerWebhookStore) GetIncomingListByUser(userID string, offs
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetIncomingListByUser(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13163,11 +13811,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoing(id string) (*model.OutgoingWe
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoing(id)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13181,11 +13828,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoingByChannel(channelID string, of
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoingByChannel(channelID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13199,11 +13845,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoingByChannelByUser(channelID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoingByChannelByUser(channelID, userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13217,11 +13862,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoingByTeam(teamID string, offset i
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoingByTeam(teamID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13235,11 +13879,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoingByTeamByUser(teamID string, us
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoingByTeamByUser(teamID, userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13253,11 +13896,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoingList(offset int, limit int) ([
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoingList(offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13271,11 +13913,10 @@ func (s *OpenTracingLayerWebhookStore) GetOutgoingListByUser(userID string, offs
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.GetOutgoingListByUser(userID, offset, limit)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13289,7 +13930,7 @@ func (s *OpenTracingLayerWebhookStore) InvalidateWebhookCache(webhook string) {
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	s.WebhookStore.InvalidateWebhookCache(webhook)
 
 }
@@ -13302,11 +13943,10 @@ func (s *OpenTracingLayerWebhookStore) PermanentDeleteIncomingByChannel(channelI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.WebhookStore.PermanentDeleteIncomingByChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -13320,11 +13960,10 @@ func (s *OpenTracingLayerWebhookStore) PermanentDeleteIncomingByUser(userID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.WebhookStore.PermanentDeleteIncomingByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -13338,11 +13977,10 @@ func (s *OpenTracingLayerWebhookStore) PermanentDeleteOutgoingByChannel(channelI
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.WebhookStore.PermanentDeleteOutgoingByChannel(channelID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -13356,11 +13994,10 @@ func (s *OpenTracingLayerWebhookStore) PermanentDeleteOutgoingByUser(userID stri
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	err := s.WebhookStore.PermanentDeleteOutgoingByUser(userID)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return err
@@ -13374,11 +14011,10 @@ func (s *OpenTracingLayerWebhookStore) SaveIncoming(webhook *model.IncomingWebho
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.SaveIncoming(webhook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13392,11 +14028,10 @@ func (s *OpenTracingLayerWebhookStore) SaveOutgoing(webhook *model.OutgoingWebho
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.SaveOutgoing(webhook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13410,11 +14045,10 @@ func (s *OpenTracingLayerWebhookStore) UpdateIncoming(webhook *model.IncomingWeb
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.UpdateIncoming(webhook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13428,11 +14062,10 @@ func (s *OpenTracingLayerWebhookStore) UpdateOutgoing(hook *model.OutgoingWebhoo
 		s.Root.Store.SetContext(origCtx)
 	}()
 
-	defer span.Finish()
+	defer span.End()
 	result, err := s.WebhookStore.UpdateOutgoing(hook)
 	if err != nil {
-		span.LogFields(spanlog.Error(err))
-		ext.Error.Set(span, true)
+		tracing.SetError(span, err)
 	}
 
 	return result, err
@@ -13479,15 +14112,19 @@ func New(childStore store.Store, ctx context.Context) *OpenTracingLayer {
 		Store: childStore,
 	}
 
+	newStore.AccessControlPolicyStore = &OpenTracingLayerAccessControlPolicyStore{AccessControlPolicyStore: childStore.AccessControlPolicy(), Root: &newStore}
 	newStore.AuditStore = &OpenTracingLayerAuditStore{AuditStore: childStore.Audit(), Root: &newStore}
+	newStore.AuditRecordStore = &OpenTracingLayerAuditRecordStore{AuditRecordStore: childStore.AuditRecord(), Root: &newStore}
 	newStore.BotStore = &OpenTracingLayerBotStore{BotStore: childStore.Bot(), Root: &newStore}
 	newStore.ChannelStore = &OpenTracingLayerChannelStore{ChannelStore: childStore.Channel(), Root: &newStore}
 	newStore.ChannelBookmarkStore = &OpenTracingLayerChannelBookmarkStore{ChannelBookmarkStore: childStore.ChannelBookmark(), Root: &newStore}
+	newStore.ChannelEmailAddressStore = &OpenTracingLayerChannelEmailAddressStore{ChannelEmailAddressStore: childStore.ChannelEmailAddress(), Root: &newStore}
 	newStore.ChannelMemberHistoryStore = &OpenTracingLayerChannelMemberHistoryStore{ChannelMemberHistoryStore: childStore.ChannelMemberHistory(), Root: &newStore}
 	newStore.ClusterDiscoveryStore = &OpenTracingLayerClusterDiscoveryStore{ClusterDiscoveryStore: childStore.ClusterDiscovery(), Root: &newStore}
 	newStore.CommandStore = &OpenTracingLayerCommandStore{CommandStore: childStore.Command(), Root: &newStore}
 	newStore.CommandWebhookStore = &OpenTracingLayerCommandWebhookStore{CommandWebhookStore: childStore.CommandWebhook(), Root: &newStore}
 	newStore.ComplianceStore = &OpenTracingLayerComplianceStore{ComplianceStore: childStore.Compliance(), Root: &newStore}
+	newStore.CustomProfileAttributeStore = &OpenTracingLayerCustomProfileAttributeStore{CustomProfileAttributeStore: childStore.CustomProfileAttribute(), Root: &newStore}
 	newStore.DesktopTokensStore = &OpenTracingLayerDesktopTokensStore{DesktopTokensStore: childStore.DesktopTokens(), Root: &newStore}
 	newStore.DraftStore = &OpenTracingLayerDraftStore{DraftStore: childStore.Draft(), Root: &newStore}
 	newStore.EmojiStore = &OpenTracingLayerEmojiStore{EmojiStore: childStore.Emoji(), Root: &newStore}
@@ -13496,9 +14133,14 @@ func New(childStore store.Store, ctx context.Context) *OpenTracingLayer {
 	newStore.JobStore = &OpenTracingLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
 	newStore.LicenseStore = &OpenTracingLayerLicenseStore{LicenseStore: childStore.License(), Root: &newStore}
 	newStore.LinkMetadataStore = &OpenTracingLayerLinkMetadataStore{LinkMetadataStore: childStore.LinkMetadata(), Root: &newStore}
+	newStore.LoginDeviceStore = &OpenTracingLayerLoginDeviceStore{LoginDeviceStore: childStore.LoginDevice(), Root: &newStore}
+	newStore.MfaRecoveryCodeStore = &OpenTracingLayerMfaRecoveryCodeStore{MfaRecoveryCodeStore: childStore.MfaRecoveryCode(), Root: &newStore}
+	newStore.MfaTrustedDeviceStore = &OpenTracingLayerMfaTrustedDeviceStore{MfaTrustedDeviceStore: childStore.MfaTrustedDevice(), Root: &newStore}
+	newStore.NotificationRuleStore = &OpenTracingLayerNotificationRuleStore{NotificationRuleStore: childStore.NotificationRule(), Root: &newStore}
 	newStore.NotifyAdminStore = &OpenTracingLayerNotifyAdminStore{NotifyAdminStore: childStore.NotifyAdmin(), Root: &newStore}
 	newStore.OAuthStore = &OpenTracingLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
 	newStore.OutgoingOAuthConnectionStore = &OpenTracingLayerOutgoingOAuthConnectionStore{OutgoingOAuthConnectionStore: childStore.OutgoingOAuthConnection(), Root: &newStore}
+	newStore.PasswordHistoryStore = &OpenTracingLayerPasswordHistoryStore{PasswordHistoryStore: childStore.PasswordHistory(), Root: &newStore}
 	newStore.PluginStore = &OpenTracingLayerPluginStore{PluginStore: childStore.Plugin(), Root: &newStore}
 	newStore.PostStore = &OpenTracingLayerPostStore{PostStore: childStore.Post(), Root: &newStore}
 	newStore.PostAcknowledgementStore = &OpenTracingLayerPostAcknowledgementStore{PostAcknowledgementStore: childStore.PostAcknowledgement(), Root: &newStore}
@@ -13523,6 +14165,8 @@ func New(childStore store.Store, ctx context.Context) *OpenTracingLayer {
 	newStore.UserStore = &OpenTracingLayerUserStore{UserStore: childStore.User(), Root: &newStore}
 	newStore.UserAccessTokenStore = &OpenTracingLayerUserAccessTokenStore{UserAccessTokenStore: childStore.UserAccessToken(), Root: &newStore}
 	newStore.UserTermsOfServiceStore = &OpenTracingLayerUserTermsOfServiceStore{UserTermsOfServiceStore: childStore.UserTermsOfService(), Root: &newStore}
+	newStore.WebAuthnCredentialStore = &OpenTracingLayerWebAuthnCredentialStore{WebAuthnCredentialStore: childStore.WebAuthnCredential(), Root: &newStore}
+	newStore.WebSocketEventStore = &OpenTracingLayerWebSocketEventStore{WebSocketEventStore: childStore.WebSocketEvent(), Root: &newStore}
 	newStore.WebhookStore = &OpenTracingLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
 	return &newStore
 }
@@ -21,6 +21,17 @@ func genStore() *mocks.Store {
 	mock.On("Channel").Return(&mocks.ChannelStore{})
 	mock.On("ChannelMemberHistory").Return(&mocks.ChannelMemberHistoryStore{})
 	mock.On("ChannelBookmark").Return(&mocks.ChannelBookmarkStore{})
+	mock.On("WebSocketEvent").Return(&mocks.WebSocketEventStore{})
+	mock.On("NotificationRule").Return(&mocks.NotificationRuleStore{})
+	mock.On("WebAuthnCredential").Return(&mocks.WebAuthnCredentialStore{})
+	mock.On("MfaRecoveryCode").Return(&mocks.MfaRecoveryCodeStore{})
+	mock.On("MfaTrustedDevice").Return(&mocks.MfaTrustedDeviceStore{})
+	mock.On("PasswordHistory").Return(&mocks.PasswordHistoryStore{})
+	mock.On("LoginDevice").Return(&mocks.LoginDeviceStore{})
+	mock.On("AccessControlPolicy").Return(&mocks.AccessControlPolicyStore{})
+	mock.On("CustomProfileAttribute").Return(&mocks.CustomProfileAttributeStore{})
+	mock.On("ChannelEmailAddress").Return(&mocks.ChannelEmailAddressStore{})
+	mock.On("AuditRecord").Return(&mocks.AuditRecordStore{})
 	mock.On("ClusterDiscovery").Return(&mocks.ClusterDiscoveryStore{})
 	mock.On("RemoteCluster").Return(&mocks.RemoteClusterStore{})
 	mock.On("Command").Return(&mocks.CommandStore{})
@@ -62,6 +73,17 @@ func genStore() *mocks.Store {
 	mock.On("PostPersistentNotification").Return(&mocks.PostPersistentNotificationStore{})
 	mock.On("DesktopTokens").Return(&mocks.DesktopTokensStore{})
 	mock.On("ChannelBookmark").Return(&mocks.ChannelBookmarkStore{})
+	mock.On("WebSocketEvent").Return(&mocks.WebSocketEventStore{})
+	mock.On("NotificationRule").Return(&mocks.NotificationRuleStore{})
+	mock.On("WebAuthnCredential").Return(&mocks.WebAuthnCredentialStore{})
+	mock.On("MfaRecoveryCode").Return(&mocks.MfaRecoveryCodeStore{})
+	mock.On("MfaTrustedDevice").Return(&mocks.MfaTrustedDeviceStore{})
+	mock.On("PasswordHistory").Return(&mocks.PasswordHistoryStore{})
+	mock.On("LoginDevice").Return(&mocks.LoginDeviceStore{})
+	mock.On("AccessControlPolicy").Return(&mocks.AccessControlPolicyStore{})
+	mock.On("CustomProfileAttribute").Return(&mocks.CustomProfileAttributeStore{})
+	mock.On("ChannelEmailAddress").Return(&mocks.ChannelEmailAddressStore{})
+	mock.On("AuditRecord").Return(&mocks.AuditRecordStore{})
 	return mock
 }
 
@@ -0,0 +1,64 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package searchlayer
+
+import (
+	model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	store "github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SearchCustomProfileAttributeStore struct {
+	store.CustomProfileAttributeStore
+	rootStore *SearchStore
+}
+
+// indexUsersWithValues indexes again the users with a value for a field that changed,
+// since the visibility of a field decides whether its values are searchable.
+func (s SearchCustomProfileAttributeStore) indexUsersWithValues(rctx request.CTX, userIDs []string) {
+	for _, userID := range userIDs {
+		s.rootStore.indexUserFromID(rctx, userID)
+	}
+}
+
+func (s SearchCustomProfileAttributeStore) UpdateField(rctx request.CTX, field *model.CustomProfileField) (*model.CustomProfileField, error) {
+	old, err := s.CustomProfileAttributeStore.GetField(field.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.CustomProfileAttributeStore.UpdateField(rctx, field)
+	if err == nil && updated.Visibility != old.Visibility {
+		userIDs, uErr := s.CustomProfileAttributeStore.GetUserIDsForField(field.Id)
+		if uErr != nil {
+			rctx.Logger().Error("Failed to get the users to index after a custom profile field update", mlog.String("field_id", field.Id), mlog.Err(uErr))
+			return updated, nil
+		}
+		s.indexUsersWithValues(rctx, userIDs)
+	}
+	return updated, err
+}
+
+func (s SearchCustomProfileAttributeStore) DeleteField(rctx request.CTX, id string, deleteAt int64) error {
+	// The values are deleted along with the field, so the users are looked up first.
+	userIDs, err := s.CustomProfileAttributeStore.GetUserIDsForField(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.CustomProfileAttributeStore.DeleteField(rctx, id, deleteAt)
+	if err == nil {
+		s.indexUsersWithValues(rctx, userIDs)
+	}
+	return err
+}
+
+func (s SearchCustomProfileAttributeStore) SaveValues(rctx request.CTX, userID string, values []*model.CustomProfileValue) error {
+	err := s.CustomProfileAttributeStore.SaveValues(rctx, userID, values)
+	if err == nil {
+		s.rootStore.indexUserFromID(rctx, userID)
+	}
+	return err
+}
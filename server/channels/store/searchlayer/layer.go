@@ -15,13 +15,14 @@ import (
 
 type SearchStore struct {
 	store.Store
-	searchEngine *searchengine.Broker
-	user         *SearchUserStore
-	team         *SearchTeamStore
-	channel      *SearchChannelStore
-	post         *SearchPostStore
-	fileInfo     *SearchFileInfoStore
-	configValue  atomic.Pointer[model.Config]
+	searchEngine           *searchengine.Broker
+	user                   *SearchUserStore
+	team                   *SearchTeamStore
+	channel                *SearchChannelStore
+	post                   *SearchPostStore
+	fileInfo               *SearchFileInfoStore
+	customProfileAttribute *SearchCustomProfileAttributeStore
+	configValue            atomic.Pointer[model.Config]
 }
 
 func NewSearchLayer(baseStore store.Store, searchEngine *searchengine.Broker, cfg *model.Config) *SearchStore {
@@ -35,6 +36,7 @@ func NewSearchLayer(baseStore store.Store, searchEngine *searchengine.Broker, cf
 	searchStore.team = &SearchTeamStore{TeamStore: baseStore.Team(), rootStore: searchStore}
 	searchStore.user = &SearchUserStore{UserStore: baseStore.User(), rootStore: searchStore}
 	searchStore.fileInfo = &SearchFileInfoStore{FileInfoStore: baseStore.FileInfo(), rootStore: searchStore}
+	searchStore.customProfileAttribute = &SearchCustomProfileAttributeStore{CustomProfileAttributeStore: baseStore.CustomProfileAttribute(), rootStore: searchStore}
 
 	return searchStore
 }
@@ -67,6 +69,10 @@ func (s *SearchStore) User() store.UserStore {
 	return s.user
 }
 
+func (s *SearchStore) CustomProfileAttribute() store.CustomProfileAttributeStore {
+	return s.customProfileAttribute
+}
+
 func (s *SearchStore) indexUserFromID(rctx request.CTX, userId string) {
 	user, err := s.User().Get(rctx.Context(), userId)
 	if err != nil {
@@ -101,7 +107,18 @@ func (s *SearchStore) indexUser(rctx request.CTX, user *model.User) {
 					userChannelsIds = append(userChannelsIds, channelId)
 				}
 
-				if err := engineCopy.IndexUser(rctx, user, userTeamsIds, userChannelsIds); err != nil {
+				if indexer, ok := engineCopy.(searchengine.CustomAttributesUserIndexer); ok {
+					customAttributes, err := s.getCustomProfileSearchValues(user.Id)
+					if err != nil {
+						rctx.Logger().Error("Encountered error indexing user", mlog.String("user_id", user.Id), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
+						return
+					}
+
+					if err := indexer.IndexUserWithCustomAttributes(rctx, user, userTeamsIds, userChannelsIds, customAttributes); err != nil {
+						rctx.Logger().Error("Encountered error indexing user", mlog.String("user_id", user.Id), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
+						return
+					}
+				} else if err := engineCopy.IndexUser(rctx, user, userTeamsIds, userChannelsIds); err != nil {
 					rctx.Logger().Error("Encountered error indexing user", mlog.String("user_id", user.Id), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
 					return
 				}
@@ -111,6 +128,19 @@ func (s *SearchStore) indexUser(rctx request.CTX, user *model.User) {
 	}
 }
 
+func (s *SearchStore) getCustomProfileSearchValues(userID string) ([]string, error) {
+	fields, err := s.CustomProfileAttribute().GetFields(false)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.CustomProfileAttribute().GetValuesForUsers([]string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return model.CustomProfileSearchValues(fields, values)[userID], nil
+}
+
 // Runs an indexing function synchronously or asynchronously depending on the engine
 func runIndexFn(rctx request.CTX, engine searchengine.SearchEngineInterface, indexFn func(searchengine.SearchEngineInterface)) {
 	if engine.IsIndexingSync() {
@@ -158,6 +158,11 @@ var searchUserStoreTests = []searchTest{
 		Fn:   testSearchUserBySubstringInAnyName,
 		Tags: []string{EngineAll},
 	},
+	{
+		Name: "Should support search users by the custom profile attributes visible to everyone",
+		Fn:   testSearchUsersByCustomProfileAttribute,
+		Tags: []string{EngineAll},
+	},
 }
 
 func TestSearchUserStore(t *testing.T, s store.Store, testEngine *SearchTestEngine) {
@@ -950,3 +955,71 @@ func createDefaultOptions(allowFullName, allowEmails, allowInactive bool) *model
 		Limit:          model.UserSearchDefaultLimit,
 	}
 }
+
+func testSearchUsersByCustomProfileAttribute(t *testing.T, th *SearchTestHelper) {
+	public, err := th.Store.CustomProfileAttribute().SaveField(&model.CustomProfileField{Name: "location", DisplayName: "Location", Type: model.CustomProfileFieldTypeText})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, th.Store.CustomProfileAttribute().DeleteField(th.Context, public.Id, model.GetMillis()))
+	}()
+	private, err := th.Store.CustomProfileAttribute().SaveField(&model.CustomProfileField{Name: "phone", DisplayName: "Phone", Type: model.CustomProfileFieldTypeText, Visibility: model.CustomProfileFieldVisibilitySelf})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, th.Store.CustomProfileAttribute().DeleteField(th.Context, private.Id, model.GetMillis()))
+	}()
+
+	err = th.Store.CustomProfileAttribute().SaveValues(th.Context, th.User.Id, []*model.CustomProfileValue{
+		{FieldId: public.Id, Value: "Atlantis"},
+		{FieldId: private.Id, Value: "Lemuria"},
+	})
+	require.NoError(t, err)
+
+	t.Run("Should return the users matching a public attribute", func(t *testing.T) {
+		options := createDefaultOptions(false, false, false)
+		users, err := th.Store.User().Search(th.Context, th.Team.Id, "atlant", options)
+		require.NoError(t, err)
+		th.assertUsersMatchInAnyOrder(t, []*model.User{th.User}, users)
+	})
+	t.Run("Should not return the users matching a private attribute", func(t *testing.T) {
+		options := createDefaultOptions(false, false, false)
+		users, err := th.Store.User().Search(th.Context, th.Team.Id, "lemuria", options)
+		require.NoError(t, err)
+		th.assertUsersMatchInAnyOrder(t, []*model.User{}, users)
+	})
+	t.Run("Should follow the visibility changes of an attribute", func(t *testing.T) {
+		hidden := *public
+		hidden.Visibility = model.CustomProfileFieldVisibilityAdmin
+		_, err := th.Store.CustomProfileAttribute().UpdateField(th.Context, &hidden)
+		require.NoError(t, err)
+
+		options := createDefaultOptions(false, false, false)
+		users, err := th.Store.User().Search(th.Context, th.Team.Id, "atlant", options)
+		require.NoError(t, err)
+		th.assertUsersMatchInAnyOrder(t, []*model.User{}, users)
+
+		shown := *private
+		shown.Visibility = model.CustomProfileFieldVisibilityAll
+		_, err = th.Store.CustomProfileAttribute().UpdateField(th.Context, &shown)
+		require.NoError(t, err)
+
+		users, err = th.Store.User().Search(th.Context, th.Team.Id, "lemuria", options)
+		require.NoError(t, err)
+		th.assertUsersMatchInAnyOrder(t, []*model.User{th.User}, users)
+	})
+	t.Run("Should not return the users matching a deleted attribute", func(t *testing.T) {
+		deleted, err := th.Store.CustomProfileAttribute().SaveField(&model.CustomProfileField{Name: "planet", DisplayName: "Planet", Type: model.CustomProfileFieldTypeText})
+		require.NoError(t, err)
+		err = th.Store.CustomProfileAttribute().SaveValues(th.Context, th.User.Id, []*model.CustomProfileValue{{FieldId: deleted.Id, Value: "Tatooine"}})
+		require.NoError(t, err)
+
+		options := createDefaultOptions(false, false, false)
+		users, err := th.Store.User().Search(th.Context, th.Team.Id, "tatooine", options)
+		require.NoError(t, err)
+		th.assertUsersMatchInAnyOrder(t, []*model.User{th.User}, users)
+
+		require.NoError(t, th.Store.CustomProfileAttribute().DeleteField(th.Context, deleted.Id, model.GetMillis()))
+		users, err = th.Store.User().Search(th.Context, th.Team.Id, "tatooine", options)
+		require.NoError(t, err)
+		th.assertUsersMatchInAnyOrder(t, []*model.User{}, users)
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var accessControlPolicyColumns = []string{
+	"AccessControlPolicies.ChannelId",
+	"AccessControlPolicies.Expression",
+	"AccessControlPolicies.CreatorId",
+	"AccessControlPolicies.CreateAt",
+	"AccessControlPolicies.UpdateAt",
+}
+
+type SqlAccessControlPolicyStore struct {
+	*SqlStore
+}
+
+func newSqlAccessControlPolicyStore(sqlStore *SqlStore) store.AccessControlPolicyStore {
+	return &SqlAccessControlPolicyStore{sqlStore}
+}
+
+func (s *SqlAccessControlPolicyStore) Save(policy *model.AccessControlPolicy) (*model.AccessControlPolicy, error) {
+	policy.PreSave()
+	if err := policy.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("AccessControlPolicies").
+		Columns("ChannelId", "Expression", "CreatorId", "CreateAt", "UpdateAt").
+		Values(policy.ChannelId, policy.Expression, policy.CreatorId, policy.CreateAt, policy.UpdateAt)
+
+	if s.DriverName() == model.DatabaseDriverMysql {
+		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE Expression = ?, CreatorId = ?, UpdateAt = ?",
+			policy.Expression, policy.CreatorId, policy.UpdateAt))
+	} else {
+		query = query.SuffixExpr(sq.Expr("ON CONFLICT (channelid) DO UPDATE SET Expression = ?, CreatorId = ?, UpdateAt = ?",
+			policy.Expression, policy.CreatorId, policy.UpdateAt))
+	}
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save AccessControlPolicy with channelId=%s", policy.ChannelId)
+	}
+	return s.Get(policy.ChannelId)
+}
+
+func (s *SqlAccessControlPolicyStore) Get(channelID string) (*model.AccessControlPolicy, error) {
+	query := s.getQueryBuilder().
+		Select(accessControlPolicyColumns...).
+		From("AccessControlPolicies").
+		Where(sq.Eq{"ChannelId": channelID})
+
+	var policy model.AccessControlPolicy
+	if err := s.GetMasterX().GetBuilder(&policy, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("AccessControlPolicy", channelID)
+		}
+		return nil, errors.Wrapf(err, "failed to get AccessControlPolicy with channelId=%s", channelID)
+	}
+	return &policy, nil
+}
+
+func (s *SqlAccessControlPolicyStore) GetAll(afterChannelID string, limit int) ([]*model.AccessControlPolicy, error) {
+	query := s.getQueryBuilder().
+		Select(accessControlPolicyColumns...).
+		From("AccessControlPolicies").
+		Where(sq.Gt{"ChannelId": afterChannelID}).
+		OrderBy("ChannelId").
+		Limit(uint64(limit))
+
+	policies := []*model.AccessControlPolicy{}
+	if err := s.GetReplicaX().SelectBuilder(&policies, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get AccessControlPolicies")
+	}
+	return policies, nil
+}
+
+func (s *SqlAccessControlPolicyStore) Delete(channelID string) error {
+	query := s.getQueryBuilder().
+		Delete("AccessControlPolicies").
+		Where(sq.Eq{"ChannelId": channelID})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete AccessControlPolicy with channelId=%s", channelID)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("AccessControlPolicy", channelID)
+	}
+	return nil
+}
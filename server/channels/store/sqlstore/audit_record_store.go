@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"encoding/json"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var auditRecordColumns = []string{
+	"AuditRecords.Id",
+	"AuditRecords.ChainId",
+	"AuditRecords.Sequence",
+	"AuditRecords.CreateAt",
+	"AuditRecords.EventName",
+	"AuditRecords.Status",
+	"AuditRecords.UserId",
+	"AuditRecords.SessionId",
+	"AuditRecords.IpAddress",
+	"AuditRecords.ObjectType",
+	"AuditRecords.PrevHash",
+	"AuditRecords.Hash",
+	"AuditRecords.Signature",
+	"AuditRecords.Data",
+}
+
+// auditRecordRow scans the data of a record as a string, since scanning it directly into
+// a json.RawMessage would not copy the bytes owned by the driver.
+type auditRecordRow struct {
+	model.AuditRecord
+	Data string
+}
+
+type SqlAuditRecordStore struct {
+	*SqlStore
+}
+
+func newSqlAuditRecordStore(sqlStore *SqlStore) store.AuditRecordStore {
+	return &SqlAuditRecordStore{sqlStore}
+}
+
+func (s *SqlAuditRecordStore) Save(record *model.AuditRecord) (*model.AuditRecord, error) {
+	record.PreSave()
+	if err := record.IsValid(); err != nil {
+		return nil, err
+	}
+
+	row := auditRecordRow{AuditRecord: *record, Data: string(record.Data)}
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO AuditRecords
+	(Id, ChainId, Sequence, CreateAt, EventName, Status, UserId, SessionId, IpAddress, ObjectType, PrevHash, Hash, Signature, Data)
+	VALUES
+	(:Id, :ChainId, :Sequence, :CreateAt, :EventName, :Status, :UserId, :SessionId, :IpAddress, :ObjectType, :PrevHash, :Hash, :Signature, :Data)`, row); err != nil {
+		return nil, errors.Wrapf(err, "failed to save AuditRecord with chainId=%s and sequence=%d", record.ChainId, record.Sequence)
+	}
+	return record, nil
+}
+
+func (s *SqlAuditRecordStore) Search(opts model.AuditRecordSearchOptions) ([]*model.AuditRecord, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = model.AuditRecordSearchDefaultLimit
+	}
+	limit = min(limit, model.AuditRecordSearchMaxLimit)
+
+	query := s.getQueryBuilder().
+		Select(auditRecordColumns...).
+		From("AuditRecords").
+		OrderBy("CreateAt DESC", "Id DESC").
+		Limit(uint64(limit))
+
+	if opts.UserId != "" {
+		query = query.Where(sq.Eq{"UserId": opts.UserId})
+	}
+	if opts.EventName != "" {
+		query = query.Where(sq.Eq{"EventName": opts.EventName})
+	}
+	if opts.IpAddress != "" {
+		query = query.Where(sq.Eq{"IpAddress": opts.IpAddress})
+	}
+	if opts.ObjectType != "" {
+		query = query.Where(sq.Eq{"ObjectType": opts.ObjectType})
+	}
+	if opts.Status != "" {
+		query = query.Where(sq.Eq{"Status": opts.Status})
+	}
+	if !opts.Cursor.IsEmpty() {
+		query = query.Where(sq.Or{
+			sq.Lt{"CreateAt": opts.Cursor.CreateAt},
+			sq.And{
+				sq.Eq{"CreateAt": opts.Cursor.CreateAt},
+				sq.Lt{"Id": opts.Cursor.Id},
+			},
+		})
+	}
+	if opts.StartTime > 0 {
+		query = query.Where(sq.GtOrEq{"CreateAt": opts.StartTime})
+	}
+	if opts.EndTime > 0 {
+		query = query.Where(sq.LtOrEq{"CreateAt": opts.EndTime})
+	}
+
+	rows := []auditRecordRow{}
+	if err := s.GetReplicaX().SelectBuilder(&rows, query); err != nil {
+		return nil, errors.Wrap(err, "failed to search AuditRecords")
+	}
+
+	records := make([]*model.AuditRecord, 0, len(rows))
+	for i := range rows {
+		record := rows[i].AuditRecord
+		record.Data = json.RawMessage(rows[i].Data)
+		records = append(records, &record)
+	}
+	return records, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var channelEmailAddressColumns = []string{
+	"ChannelEmailAddresses.ChannelId",
+	"ChannelEmailAddresses.Token",
+	"ChannelEmailAddresses.WebhookId",
+	"ChannelEmailAddresses.AllowedSenders",
+	"ChannelEmailAddresses.CreatorId",
+	"ChannelEmailAddresses.CreateAt",
+	"ChannelEmailAddresses.UpdateAt",
+}
+
+type SqlChannelEmailAddressStore struct {
+	*SqlStore
+}
+
+func newSqlChannelEmailAddressStore(sqlStore *SqlStore) store.ChannelEmailAddressStore {
+	return &SqlChannelEmailAddressStore{sqlStore}
+}
+
+func (s *SqlChannelEmailAddressStore) Save(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error) {
+	address.PreSave()
+	if err := address.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO ChannelEmailAddresses
+	(ChannelId, Token, WebhookId, AllowedSenders, CreatorId, CreateAt, UpdateAt)
+	VALUES
+	(:ChannelId, :Token, :WebhookId, :AllowedSenders, :CreatorId, :CreateAt, :UpdateAt)`, address); err != nil {
+		if IsUniqueConstraintError(err, []string{"PRIMARY", "channelemailaddresses_pkey", "ChannelId", "channelid"}) {
+			return nil, store.NewErrConflict("ChannelEmailAddress", err, "channelId="+address.ChannelId)
+		}
+		return nil, errors.Wrap(err, "failed to save ChannelEmailAddress")
+	}
+	return address, nil
+}
+
+func (s *SqlChannelEmailAddressStore) Update(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error) {
+	address.PreUpdate()
+	if err := address.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("ChannelEmailAddresses").
+		SetMap(map[string]any{
+			"Token":          address.Token,
+			"WebhookId":      address.WebhookId,
+			"AllowedSenders": address.AllowedSenders,
+			"UpdateAt":       address.UpdateAt,
+		}).
+		Where(sq.Eq{"ChannelId": address.ChannelId})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update ChannelEmailAddress with channelId=%s", address.ChannelId)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return nil, store.NewErrNotFound("ChannelEmailAddress", address.ChannelId)
+	}
+	return address, nil
+}
+
+func (s *SqlChannelEmailAddressStore) Get(channelID string) (*model.ChannelEmailAddress, error) {
+	return s.getBy(sq.Eq{"ChannelId": channelID}, channelID)
+}
+
+func (s *SqlChannelEmailAddressStore) GetByToken(token string) (*model.ChannelEmailAddress, error) {
+	return s.getBy(sq.Eq{"Token": token}, token)
+}
+
+func (s *SqlChannelEmailAddressStore) getBy(where sq.Eq, id string) (*model.ChannelEmailAddress, error) {
+	query := s.getQueryBuilder().
+		Select(channelEmailAddressColumns...).
+		From("ChannelEmailAddresses").
+		Where(where)
+
+	address := &model.ChannelEmailAddress{}
+	if err := s.GetReplicaX().GetBuilder(address, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("ChannelEmailAddress", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get ChannelEmailAddress with id=%s", id)
+	}
+	return address, nil
+}
+
+func (s *SqlChannelEmailAddressStore) Delete(channelID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM ChannelEmailAddresses WHERE ChannelId=?`, channelID); err != nil {
+		return errors.Wrapf(err, "failed to delete ChannelEmailAddress with channelId=%s", channelID)
+	}
+	return nil
+}
@@ -2850,6 +2850,37 @@ func (s SqlChannelStore) GetChannelsWithTeamDataByIds(channelIDs []string, inclu
 	return channels, nil
 }
 
+func (s SqlChannelStore) GetNewPublicChannelsForUser(userID string, since int64, limit int) ([]*model.ChannelWithTeamData, error) {
+	query := s.getQueryBuilder().
+		Select("c.*",
+			"t.DisplayName AS TeamDisplayName",
+			"t.Name AS TeamName",
+			"t.UpdateAt AS TeamUpdateAt").
+		From("Channels c").
+		InnerJoin("Teams t ON t.Id = c.TeamId").
+		InnerJoin("TeamMembers tm ON tm.TeamId = c.TeamId AND tm.UserId = ? AND tm.DeleteAt = 0", userID).
+		Where(sq.Eq{
+			"c.Type":     model.ChannelTypeOpen,
+			"c.DeleteAt": 0,
+			"t.DeleteAt": 0,
+		}).
+		Where(sq.Gt{"c.CreateAt": since}).
+		Where("NOT EXISTS (SELECT 1 FROM ChannelMembers cm WHERE cm.ChannelId = c.Id AND cm.UserId = ?)", userID).
+		OrderBy("c.CreateAt DESC").
+		Limit(uint64(limit))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetNewPublicChannelsForUser_tosql")
+	}
+
+	channels := []*model.ChannelWithTeamData{}
+	if err := s.GetReplicaX().Select(&channels, sql, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find new public Channels for userId=%s", userID)
+	}
+	return channels, nil
+}
+
 func (s SqlChannelStore) GetForPost(postId string) (*model.Channel, error) {
 	channel := model.Channel{}
 	if err := s.GetReplicaX().Get(
@@ -0,0 +1,239 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var customProfileFieldColumns = []string{
+	"CustomProfileFields.Id",
+	"CustomProfileFields.Name",
+	"CustomProfileFields.DisplayName",
+	"CustomProfileFields.Type",
+	"CustomProfileFields.Options",
+	"CustomProfileFields.Visibility",
+	"CustomProfileFields.AdminManaged",
+	"CustomProfileFields.LdapAttribute",
+	"CustomProfileFields.SamlAttribute",
+	"CustomProfileFields.SortOrder",
+	"CustomProfileFields.CreateAt",
+	"CustomProfileFields.UpdateAt",
+	"CustomProfileFields.DeleteAt",
+}
+
+var customProfileValueColumns = []string{
+	"CustomProfileValues.FieldId",
+	"CustomProfileValues.UserId",
+	"CustomProfileValues.Value",
+	"CustomProfileValues.UpdateAt",
+}
+
+type SqlCustomProfileAttributeStore struct {
+	*SqlStore
+}
+
+func newSqlCustomProfileAttributeStore(sqlStore *SqlStore) store.CustomProfileAttributeStore {
+	return &SqlCustomProfileAttributeStore{sqlStore}
+}
+
+func (s *SqlCustomProfileAttributeStore) SaveField(field *model.CustomProfileField) (*model.CustomProfileField, error) {
+	if field.Id != "" {
+		return nil, store.NewErrInvalidInput("CustomProfileField", "Id", field.Id)
+	}
+
+	field.PreSave()
+	if err := field.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO CustomProfileFields
+	(Id, Name, DisplayName, Type, Options, Visibility, AdminManaged, LdapAttribute, SamlAttribute, SortOrder, CreateAt, UpdateAt, DeleteAt)
+	VALUES
+	(:Id, :Name, :DisplayName, :Type, :Options, :Visibility, :AdminManaged, :LdapAttribute, :SamlAttribute, :SortOrder, :CreateAt, :UpdateAt, :DeleteAt)`, field); err != nil {
+		if IsUniqueConstraintError(err, []string{"idx_customprofilefields_name_deleteat"}) {
+			return nil, store.NewErrConflict("CustomProfileField", err, "name="+field.Name)
+		}
+		return nil, errors.Wrap(err, "failed to save CustomProfileField")
+	}
+	return field, nil
+}
+
+func (s *SqlCustomProfileAttributeStore) UpdateField(rctx request.CTX, field *model.CustomProfileField) (*model.CustomProfileField, error) {
+	field.PreUpdate()
+	if err := field.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("CustomProfileFields").
+		SetMap(map[string]any{
+			"DisplayName":   field.DisplayName,
+			"Options":       field.Options,
+			"Visibility":    field.Visibility,
+			"AdminManaged":  field.AdminManaged,
+			"LdapAttribute": field.LdapAttribute,
+			"SamlAttribute": field.SamlAttribute,
+			"SortOrder":     field.SortOrder,
+			"UpdateAt":      field.UpdateAt,
+		}).
+		Where(sq.Eq{"Id": field.Id, "DeleteAt": 0})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update CustomProfileField with id=%s", field.Id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return nil, store.NewErrNotFound("CustomProfileField", field.Id)
+	}
+	return field, nil
+}
+
+func (s *SqlCustomProfileAttributeStore) GetField(id string) (*model.CustomProfileField, error) {
+	query := s.getQueryBuilder().
+		Select(customProfileFieldColumns...).
+		From("CustomProfileFields").
+		Where(sq.Eq{"Id": id})
+
+	var field model.CustomProfileField
+	if err := s.GetReplicaX().GetBuilder(&field, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("CustomProfileField", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get CustomProfileField with id=%s", id)
+	}
+	return &field, nil
+}
+
+func (s *SqlCustomProfileAttributeStore) GetFields(includeDeleted bool) ([]*model.CustomProfileField, error) {
+	query := s.getQueryBuilder().
+		Select(customProfileFieldColumns...).
+		From("CustomProfileFields").
+		OrderBy("SortOrder", "Name", "DeleteAt")
+	if !includeDeleted {
+		query = query.Where(sq.Eq{"DeleteAt": 0})
+	}
+
+	fields := []*model.CustomProfileField{}
+	if err := s.GetReplicaX().SelectBuilder(&fields, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get CustomProfileFields")
+	}
+	return fields, nil
+}
+
+func (s *SqlCustomProfileAttributeStore) DeleteField(rctx request.CTX, id string, deleteAt int64) (err error) {
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	result, err := transaction.ExecBuilder(s.getQueryBuilder().
+		Update("CustomProfileFields").
+		Set("DeleteAt", deleteAt).
+		Set("UpdateAt", deleteAt).
+		Where(sq.Eq{"Id": id, "DeleteAt": 0}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete CustomProfileField with id=%s", id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("CustomProfileField", id)
+	}
+
+	if _, err = transaction.ExecBuilder(s.getQueryBuilder().
+		Delete("CustomProfileValues").
+		Where(sq.Eq{"FieldId": id})); err != nil {
+		return errors.Wrapf(err, "failed to delete CustomProfileValues for fieldId=%s", id)
+	}
+
+	return transaction.Commit()
+}
+
+func (s *SqlCustomProfileAttributeStore) GetValuesForUsers(userIDs []string) ([]*model.CustomProfileValue, error) {
+	values := []*model.CustomProfileValue{}
+	if len(userIDs) == 0 {
+		return values, nil
+	}
+
+	query := s.getQueryBuilder().
+		Select(customProfileValueColumns...).
+		From("CustomProfileValues").
+		Join("CustomProfileFields ON CustomProfileFields.Id = CustomProfileValues.FieldId").
+		Where(sq.Eq{"CustomProfileValues.UserId": userIDs, "CustomProfileFields.DeleteAt": 0}).
+		OrderBy("CustomProfileValues.UserId", "CustomProfileFields.SortOrder", "CustomProfileFields.Name")
+
+	if err := s.GetReplicaX().SelectBuilder(&values, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get CustomProfileValues")
+	}
+	return values, nil
+}
+
+func (s *SqlCustomProfileAttributeStore) GetUserIDsForField(fieldID string) ([]string, error) {
+	query := s.getQueryBuilder().
+		Select("UserId").
+		From("CustomProfileValues").
+		Where(sq.Eq{"FieldId": fieldID}).
+		OrderBy("UserId")
+
+	userIDs := []string{}
+	if err := s.GetReplicaX().SelectBuilder(&userIDs, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the users with a CustomProfileValue for fieldId=%s", fieldID)
+	}
+	return userIDs, nil
+}
+
+func (s *SqlCustomProfileAttributeStore) SaveValues(rctx request.CTX, userID string, values []*model.CustomProfileValue) (err error) {
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	for _, value := range values {
+		value.UserId = userID
+		value.PreSave()
+		if appErr := value.IsValid(); appErr != nil {
+			return appErr
+		}
+
+		if value.Value == "" {
+			if _, err = transaction.ExecBuilder(s.getQueryBuilder().
+				Delete("CustomProfileValues").
+				Where(sq.Eq{"FieldId": value.FieldId, "UserId": userID})); err != nil {
+				return errors.Wrapf(err, "failed to delete CustomProfileValue with fieldId=%s", value.FieldId)
+			}
+			continue
+		}
+
+		query := s.getQueryBuilder().
+			Insert("CustomProfileValues").
+			Columns("FieldId", "UserId", "Value", "UpdateAt").
+			Values(value.FieldId, userID, value.Value, value.UpdateAt)
+		if s.DriverName() == model.DatabaseDriverMysql {
+			query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE Value = ?, UpdateAt = ?", value.Value, value.UpdateAt))
+		} else {
+			query = query.SuffixExpr(sq.Expr("ON CONFLICT (fieldid, userid) DO UPDATE SET Value = ?, UpdateAt = ?", value.Value, value.UpdateAt))
+		}
+
+		if _, err = transaction.ExecBuilder(query); err != nil {
+			return errors.Wrapf(err, "failed to save CustomProfileValue with fieldId=%s", value.FieldId)
+		}
+	}
+
+	return transaction.Commit()
+}
+
+func (s *SqlCustomProfileAttributeStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM CustomProfileValues WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete CustomProfileValues for userId=%s", userID)
+	}
+	return nil
+}
@@ -42,6 +42,7 @@ type fileInfoWithChannelID struct {
 	Content         string
 	RemoteId        *string
 	Archived        bool
+	Tier            string
 }
 
 func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
@@ -66,6 +67,7 @@ func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
 		MiniPreview:     fi.MiniPreview,
 		Content:         fi.Content,
 		RemoteId:        fi.RemoteId,
+		Tier:            fi.Tier,
 	}
 }
 
@@ -106,6 +108,7 @@ func newSqlFileInfoStore(sqlStore *SqlStore, metrics einterfaces.MetricsInterfac
 		"Coalesce(FileInfo.Content, '') AS Content",
 		"Coalesce(FileInfo.RemoteId, '') AS RemoteId",
 		"FileInfo.Archived",
+		"COALESCE(FileInfo.Tier, '') AS Tier",
 	}
 
 	return s
@@ -442,6 +445,25 @@ func (fs SqlFileInfoStore) SetContent(rctx request.CTX, fileId, content string)
 	return nil
 }
 
+func (fs SqlFileInfoStore) SetTier(rctx request.CTX, fileId, tier string) error {
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("Tier", tier).
+		Where(sq.Eq{"Id": fileId})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "file_info_tosql")
+	}
+
+	_, err = fs.GetMasterX().Exec(queryString, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update FileInfo tier with id=%s", fileId)
+	}
+
+	return nil
+}
+
 func (fs SqlFileInfoStore) DeleteForPost(rctx request.CTX, postId string) (string, error) {
 	if _, err := fs.GetMasterX().Exec(
 		`UPDATE
@@ -724,6 +746,34 @@ func (fs SqlFileInfoStore) GetFilesBatchForIndexing(startTime int64, startFileID
 	return files, nil
 }
 
+// GetFilesBatchForColdStorage returns the files created before olderThan that are still in the hot tier,
+// starting after the file identified by startTime and startFileID.
+func (fs SqlFileInfoStore) GetFilesBatchForColdStorage(olderThan, startTime int64, startFileID string, limit int) ([]*model.FileInfo, error) {
+	files := []*model.FileInfo{}
+
+	query := fs.getQueryBuilder().
+		Select(fs.queryFields...).
+		From("FileInfo").
+		Where(sq.Lt{"FileInfo.CreateAt": olderThan}).
+		Where(sq.Or{
+			sq.Gt{"FileInfo.CreateAt": startTime},
+			sq.And{
+				sq.Eq{"FileInfo.CreateAt": startTime},
+				sq.Gt{"FileInfo.Id": startFileID},
+			},
+		}).
+		Where(sq.NotEq{"COALESCE(FileInfo.Tier, '')": model.FileTierCold}).
+		Where(sq.NotEq{"FileInfo.Path": ""}).
+		OrderBy("FileInfo.CreateAt ASC, FileInfo.Id ASC").
+		Limit(uint64(limit))
+
+	if err := fs.GetReplicaX().SelectBuilder(&files, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find Files")
+	}
+
+	return files, nil
+}
+
 func (fs SqlFileInfoStore) GetStorageUsage(allowFromCache, includeDeleted bool) (int64, error) {
 	query := fs.getQueryBuilder().
 		Select("COALESCE(SUM(Size), 0)").
@@ -741,6 +791,28 @@ func (fs SqlFileInfoStore) GetStorageUsage(allowFromCache, includeDeleted bool)
 	return size, nil
 }
 
+func (fs SqlFileInfoStore) GetStorageUsageByTier(includeDeleted bool) ([]*model.FileTierUsage, error) {
+	query := fs.getQueryBuilder().
+		Select(
+			fmt.Sprintf("COALESCE(NULLIF(Tier, ''), '%s') AS Tier", model.FileTierHot),
+			"COUNT(*) AS FileCount",
+			"COALESCE(SUM(Size), 0) AS Size",
+		).
+		From("FileInfo").
+		GroupBy(fmt.Sprintf("COALESCE(NULLIF(Tier, ''), '%s')", model.FileTierHot)).
+		OrderBy("Tier")
+
+	if !includeDeleted {
+		query = query.Where("DeleteAt = 0")
+	}
+
+	usage := []*model.FileTierUsage{}
+	if err := fs.GetReplicaX().SelectBuilder(&usage, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get storage usage by tier")
+	}
+	return usage, nil
+}
+
 // GetUptoNSizeFileTime returns the CreateAt time of the last accessible file with a running-total size upto n bytes.
 func (fs *SqlFileInfoStore) GetUptoNSizeFileTime(n int64) (int64, error) {
 	if n <= 0 {
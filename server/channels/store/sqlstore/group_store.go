@@ -320,6 +320,36 @@ func (s *SqlGroupStore) GetByUser(userId string) ([]*model.Group, error) {
 	return groups, nil
 }
 
+func (s *SqlGroupStore) GetByUsers(userIDs []string) (map[string][]*model.Group, error) {
+	groupsByUser := make(map[string][]*model.Group, len(userIDs))
+	if len(userIDs) == 0 {
+		return groupsByUser, nil
+	}
+
+	builder := s.getQueryBuilder().
+		Select("UserGroups.*, GroupMembers.UserId AS MemberUserId").
+		From("GroupMembers").
+		Join("UserGroups ON UserGroups.Id = GroupMembers.GroupId").
+		Where(sq.Eq{
+			"GroupMembers.DeleteAt": 0,
+			"GroupMembers.UserId":   userIDs,
+		})
+
+	res := []*struct {
+		model.Group
+		MemberUserId string
+	}{}
+	if err := s.GetReplicaX().SelectBuilder(&res, builder); err != nil {
+		return nil, errors.Wrap(err, "failed to find Groups by user ids")
+	}
+
+	for _, item := range res {
+		groupsByUser[item.MemberUserId] = append(groupsByUser[item.MemberUserId], &item.Group)
+	}
+
+	return groupsByUser, nil
+}
+
 func (s *SqlGroupStore) Update(group *model.Group) (*model.Group, error) {
 	var retrievedGroup model.Group
 	builder := s.getQueryBuilder().
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var loginDeviceColumns = []string{
+	"LoginDevices.Id",
+	"LoginDevices.UserId",
+	"LoginDevices.Fingerprint",
+	"LoginDevices.Name",
+	"LoginDevices.Country",
+	"LoginDevices.CreateAt",
+	"LoginDevices.LastSeenAt",
+}
+
+type SqlLoginDeviceStore struct {
+	*SqlStore
+}
+
+func newSqlLoginDeviceStore(sqlStore *SqlStore) store.LoginDeviceStore {
+	return &SqlLoginDeviceStore{sqlStore}
+}
+
+func (s *SqlLoginDeviceStore) Save(device *model.LoginDevice) (*model.LoginDevice, error) {
+	if device.Id != "" {
+		return nil, store.NewErrInvalidInput("LoginDevice", "Id", device.Id)
+	}
+
+	device.PreSave()
+	if err := device.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO LoginDevices
+	(Id, UserId, Fingerprint, Name, Country, CreateAt, LastSeenAt)
+	VALUES
+	(:Id, :UserId, :Fingerprint, :Name, :Country, :CreateAt, :LastSeenAt)`, device); err != nil {
+		if IsUniqueConstraintError(err, []string{"idx_logindevices_userid_fingerprint_country"}) {
+			return nil, store.NewErrConflict("LoginDevice", err, "userId="+device.UserId)
+		}
+		return nil, errors.Wrap(err, "failed to save LoginDevice")
+	}
+	return device, nil
+}
+
+func (s *SqlLoginDeviceStore) GetForUser(userID string, limit int) ([]*model.LoginDevice, error) {
+	query := s.getQueryBuilder().
+		Select(loginDeviceColumns...).
+		From("LoginDevices").
+		Where(sq.Eq{"UserId": userID}).
+		OrderBy("LastSeenAt DESC", "Id DESC").
+		Limit(uint64(limit))
+
+	devices := []*model.LoginDevice{}
+	if err := s.GetMasterX().SelectBuilder(&devices, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get LoginDevices for userId=%s", userID)
+	}
+	return devices, nil
+}
+
+func (s *SqlLoginDeviceStore) UpdateLastSeenAt(id string, lastSeenAt int64) error {
+	query := s.getQueryBuilder().
+		Update("LoginDevices").
+		Set("LastSeenAt", lastSeenAt).
+		Where(sq.Eq{"Id": id})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update LoginDevice with id=%s", id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("LoginDevice", id)
+	}
+	return nil
+}
+
+func (s *SqlLoginDeviceStore) PruneForUser(userID string, keep int) error {
+	devices, err := s.GetForUser(userID, keep)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder().
+		Delete("LoginDevices").
+		Where(sq.Eq{"UserId": userID})
+	if len(devices) > 0 {
+		kept := make([]string, 0, len(devices))
+		for _, device := range devices {
+			kept = append(kept, device.Id)
+		}
+		query = query.Where(sq.NotEq{"Id": kept})
+	}
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to prune LoginDevices for userId=%s", userID)
+	}
+	return nil
+}
+
+func (s *SqlLoginDeviceStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM LoginDevices WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete LoginDevices for userId=%s", userID)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var mfaRecoveryCodeColumns = []string{
+	"MfaRecoveryCodes.Id",
+	"MfaRecoveryCodes.UserId",
+	"MfaRecoveryCodes.CodeHash",
+	"MfaRecoveryCodes.CreateAt",
+	"MfaRecoveryCodes.UsedAt",
+}
+
+type SqlMfaRecoveryCodeStore struct {
+	*SqlStore
+}
+
+func newSqlMfaRecoveryCodeStore(sqlStore *SqlStore) store.MfaRecoveryCodeStore {
+	return &SqlMfaRecoveryCodeStore{sqlStore}
+}
+
+func (s *SqlMfaRecoveryCodeStore) Replace(userID string, codes []*model.MfaRecoveryCode) (err error) {
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	if _, err = transaction.Exec(`DELETE FROM MfaRecoveryCodes WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete MfaRecoveryCodes for userId=%s", userID)
+	}
+
+	if len(codes) > 0 {
+		query := s.getQueryBuilder().
+			Insert("MfaRecoveryCodes").
+			Columns("Id", "UserId", "CodeHash", "CreateAt", "UsedAt")
+		for _, code := range codes {
+			if code.UserId != userID {
+				return store.NewErrInvalidInput("MfaRecoveryCode", "UserId", code.UserId)
+			}
+			query = query.Values(code.Id, code.UserId, code.CodeHash, code.CreateAt, code.UsedAt)
+		}
+		if _, err = transaction.ExecBuilder(query); err != nil {
+			return errors.Wrapf(err, "failed to save MfaRecoveryCodes for userId=%s", userID)
+		}
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return errors.Wrap(err, "commit_transaction")
+	}
+	return nil
+}
+
+func (s *SqlMfaRecoveryCodeStore) GetUnusedForUser(userID string) ([]*model.MfaRecoveryCode, error) {
+	query := s.getQueryBuilder().
+		Select(mfaRecoveryCodeColumns...).
+		From("MfaRecoveryCodes").
+		Where(sq.Eq{"UserId": userID, "UsedAt": 0}).
+		OrderBy("Id ASC")
+
+	// Read from the master, as a code is consumed right after having been checked.
+	codes := []*model.MfaRecoveryCode{}
+	if err := s.GetMasterX().SelectBuilder(&codes, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get MfaRecoveryCodes for userId=%s", userID)
+	}
+	return codes, nil
+}
+
+func (s *SqlMfaRecoveryCodeStore) MarkUsed(id string, usedAt int64) error {
+	query := s.getQueryBuilder().
+		Update("MfaRecoveryCodes").
+		Set("UsedAt", usedAt).
+		Where(sq.Eq{"Id": id, "UsedAt": 0})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update MfaRecoveryCode with id=%s", id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("MfaRecoveryCode", id)
+	}
+	return nil
+}
+
+func (s *SqlMfaRecoveryCodeStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM MfaRecoveryCodes WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete MfaRecoveryCodes for userId=%s", userID)
+	}
+	return nil
+}
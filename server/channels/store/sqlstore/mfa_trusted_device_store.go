@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var mfaTrustedDeviceColumns = []string{
+	"MfaTrustedDevices.Id",
+	"MfaTrustedDevices.UserId",
+	"MfaTrustedDevices.CreateAt",
+	"MfaTrustedDevices.ExpiresAt",
+	"MfaTrustedDevices.LastUsedAt",
+}
+
+type SqlMfaTrustedDeviceStore struct {
+	*SqlStore
+}
+
+func newSqlMfaTrustedDeviceStore(sqlStore *SqlStore) store.MfaTrustedDeviceStore {
+	return &SqlMfaTrustedDeviceStore{sqlStore}
+}
+
+func (s *SqlMfaTrustedDeviceStore) Save(device *model.MfaTrustedDevice) (*model.MfaTrustedDevice, error) {
+	if device.Id != "" {
+		return nil, store.NewErrInvalidInput("MfaTrustedDevice", "Id", device.Id)
+	}
+
+	device.PreSave()
+	if err := device.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO MfaTrustedDevices
+	(Id, UserId, CreateAt, ExpiresAt, LastUsedAt)
+	VALUES
+	(:Id, :UserId, :CreateAt, :ExpiresAt, :LastUsedAt)`, device); err != nil {
+		return nil, errors.Wrap(err, "failed to save MfaTrustedDevice")
+	}
+	return device, nil
+}
+
+func (s *SqlMfaTrustedDeviceStore) Get(id string) (*model.MfaTrustedDevice, error) {
+	query := s.getQueryBuilder().
+		Select(mfaTrustedDeviceColumns...).
+		From("MfaTrustedDevices").
+		Where(sq.Eq{"Id": id})
+
+	// Read from the master, as a revoked device must not be trusted anymore.
+	device := &model.MfaTrustedDevice{}
+	if err := s.GetMasterX().GetBuilder(device, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("MfaTrustedDevice", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get MfaTrustedDevice with id=%s", id)
+	}
+	return device, nil
+}
+
+func (s *SqlMfaTrustedDeviceStore) UpdateLastUsedAt(id string, lastUsedAt int64) error {
+	query := s.getQueryBuilder().
+		Update("MfaTrustedDevices").
+		Set("LastUsedAt", lastUsedAt).
+		Where(sq.Eq{"Id": id})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update MfaTrustedDevice with id=%s", id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("MfaTrustedDevice", id)
+	}
+	return nil
+}
+
+func (s *SqlMfaTrustedDeviceStore) Delete(id string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM MfaTrustedDevices WHERE Id=?`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete MfaTrustedDevice with id=%s", id)
+	}
+	return nil
+}
+
+func (s *SqlMfaTrustedDeviceStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM MfaTrustedDevices WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete MfaTrustedDevices for userId=%s", userID)
+	}
+	return nil
+}
+
+func (s *SqlMfaTrustedDeviceStore) Cleanup(expiryTime int64) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM MfaTrustedDevices WHERE ExpiresAt < ?`, expiryTime); err != nil {
+		return errors.Wrap(err, "failed to delete expired MfaTrustedDevices")
+	}
+	return nil
+}
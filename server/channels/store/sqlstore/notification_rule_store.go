@@ -0,0 +1,157 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var notificationRuleColumns = []string{
+	"NotificationRules.Id",
+	"NotificationRules.UserId",
+	"NotificationRules.Name",
+	"NotificationRules.Action",
+	"NotificationRules.Keywords",
+	"NotificationRules.Patterns",
+	"NotificationRules.TeamIds",
+	"NotificationRules.ChannelIds",
+	"NotificationRules.Schedule",
+	"NotificationRules.BypassUrgent",
+	"NotificationRules.CreateAt",
+	"NotificationRules.UpdateAt",
+}
+
+type SqlNotificationRuleStore struct {
+	*SqlStore
+}
+
+func newSqlNotificationRuleStore(sqlStore *SqlStore) store.NotificationRuleStore {
+	return &SqlNotificationRuleStore{sqlStore}
+}
+
+func (s *SqlNotificationRuleStore) Save(rule *model.NotificationRule) (*model.NotificationRule, error) {
+	if rule.Id != "" {
+		return nil, store.NewErrInvalidInput("NotificationRule", "Id", rule.Id)
+	}
+
+	rule.PreSave()
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO NotificationRules
+	(Id, UserId, Name, Action, Keywords, Patterns, TeamIds, ChannelIds, Schedule, BypassUrgent, CreateAt, UpdateAt)
+	VALUES
+	(:Id, :UserId, :Name, :Action, :Keywords, :Patterns, :TeamIds, :ChannelIds, :Schedule, :BypassUrgent, :CreateAt, :UpdateAt)`, rule); err != nil {
+		return nil, errors.Wrap(err, "failed to save NotificationRule")
+	}
+	return rule, nil
+}
+
+func (s *SqlNotificationRuleStore) Update(rule *model.NotificationRule) (*model.NotificationRule, error) {
+	rule.PreUpdate()
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("NotificationRules").
+		SetMap(map[string]any{
+			"Name":         rule.Name,
+			"Action":       rule.Action,
+			"Keywords":     rule.Keywords,
+			"Patterns":     rule.Patterns,
+			"TeamIds":      rule.TeamIds,
+			"ChannelIds":   rule.ChannelIds,
+			"Schedule":     rule.Schedule,
+			"BypassUrgent": rule.BypassUrgent,
+			"UpdateAt":     rule.UpdateAt,
+		}).
+		Where(sq.Eq{"Id": rule.Id, "UserId": rule.UserId})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update NotificationRule with id=%s", rule.Id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return nil, store.NewErrNotFound("NotificationRule", rule.Id)
+	}
+	return rule, nil
+}
+
+func (s *SqlNotificationRuleStore) Get(id string) (*model.NotificationRule, error) {
+	query := s.getQueryBuilder().
+		Select(notificationRuleColumns...).
+		From("NotificationRules").
+		Where(sq.Eq{"Id": id})
+
+	rule := &model.NotificationRule{}
+	if err := s.GetReplicaX().GetBuilder(rule, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("NotificationRule", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get NotificationRule with id=%s", id)
+	}
+	rule.CompilePatterns()
+	return rule, nil
+}
+
+func (s *SqlNotificationRuleStore) GetForUser(userID string) ([]*model.NotificationRule, error) {
+	query := s.getQueryBuilder().
+		Select(notificationRuleColumns...).
+		From("NotificationRules").
+		Where(sq.Eq{"UserId": userID}).
+		OrderBy("CreateAt ASC", "Id ASC")
+
+	rules := []*model.NotificationRule{}
+	if err := s.GetReplicaX().SelectBuilder(&rules, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get NotificationRules for userId=%s", userID)
+	}
+	compileNotificationRulePatterns(rules)
+	return rules, nil
+}
+
+func (s *SqlNotificationRuleStore) GetForChannelMembers(channelID string) ([]*model.NotificationRule, error) {
+	query := s.getQueryBuilder().
+		Select(notificationRuleColumns...).
+		From("NotificationRules").
+		InnerJoin("ChannelMembers ON ChannelMembers.UserId = NotificationRules.UserId").
+		Where(sq.Eq{"ChannelMembers.ChannelId": channelID}).
+		OrderBy("NotificationRules.CreateAt ASC", "NotificationRules.Id ASC")
+
+	rules := []*model.NotificationRule{}
+	if err := s.GetReplicaX().SelectBuilder(&rules, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get NotificationRules for channelId=%s", channelID)
+	}
+	compileNotificationRulePatterns(rules)
+	return rules, nil
+}
+
+func (s *SqlNotificationRuleStore) Delete(id string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM NotificationRules WHERE Id=?`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete NotificationRule with id=%s", id)
+	}
+	return nil
+}
+
+func (s *SqlNotificationRuleStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM NotificationRules WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete NotificationRules for userId=%s", userID)
+	}
+	return nil
+}
+
+// compileNotificationRulePatterns compiles the patterns of the loaded rules once,
+// rather than whenever they are matched.
+func compileNotificationRulePatterns(rules []*model.NotificationRule) {
+	for _, rule := range rules {
+		rule.CompilePatterns()
+	}
+}
@@ -32,9 +32,9 @@ func (as SqlOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, error) {
 	}
 
 	if _, err := as.GetMasterX().NamedExec(`INSERT INTO OAuthApps
-		(Id, CreatorId, CreateAt, UpdateAt, ClientSecret, Name, Description, IconURL, CallbackUrls, Homepage, IsTrusted, MattermostAppID)
+		(Id, CreatorId, CreateAt, UpdateAt, ClientSecret, Name, Description, IconURL, CallbackUrls, Homepage, IsTrusted, MattermostAppID, IsPublic, BotUserId)
 		VALUES
-		(:Id, :CreatorId, :CreateAt, :UpdateAt, :ClientSecret, :Name, :Description, :IconURL, :CallbackUrls, :Homepage, :IsTrusted, :MattermostAppID)`, app); err != nil {
+		(:Id, :CreatorId, :CreateAt, :UpdateAt, :ClientSecret, :Name, :Description, :IconURL, :CallbackUrls, :Homepage, :IsTrusted, :MattermostAppID, :IsPublic, :BotUserId)`, app); err != nil {
 		return nil, errors.Wrap(err, "failed to save OAuthApp")
 	}
 	return app, nil
@@ -63,7 +63,8 @@ func (as SqlOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, error)
 	res, err := as.GetMasterX().NamedExec(`UPDATE OAuthApps
 		SET UpdateAt=:UpdateAt, ClientSecret=:ClientSecret, Name=:Name,
 			Description=:Description, IconURL=:IconURL, CallbackUrls=:CallbackUrls,
-			Homepage=:Homepage, IsTrusted=:IsTrusted, MattermostAppID=:MattermostAppID
+			Homepage=:Homepage, IsTrusted=:IsTrusted, MattermostAppID=:MattermostAppID,
+			IsPublic=:IsPublic, BotUserId=:BotUserId
 		WHERE Id=:Id`, app)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to update OAuthApp with id=%s", app.Id)
@@ -149,9 +150,9 @@ func (as SqlOAuthStore) SaveAccessData(accessData *model.AccessData) (*model.Acc
 	}
 
 	if _, err := as.GetMasterX().NamedExec(`INSERT INTO OAuthAccessData
-		(ClientId, UserId, Token, RefreshToken, RedirectUri, ExpiresAt, Scope)
+		(ClientId, UserId, Token, RefreshToken, RedirectUri, ExpiresAt, Scope, GrantType)
 		VALUES
-		(:ClientId, :UserId, :Token, :RefreshToken, :RedirectUri, :ExpiresAt, :Scope)`, accessData); err != nil {
+		(:ClientId, :UserId, :Token, :RefreshToken, :RedirectUri, :ExpiresAt, :Scope, :GrantType)`, accessData); err != nil {
 		return nil, errors.Wrap(err, "failed to save AccessData")
 	}
 	return accessData, nil
@@ -230,9 +231,9 @@ func (as SqlOAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData,
 	}
 
 	if _, err := as.GetMasterX().NamedExec(`INSERT INTO OAuthAuthData
-		(ClientId, UserId, Code, ExpiresIn, CreateAt, RedirectUri, State, Scope)
+		(ClientId, UserId, Code, ExpiresIn, CreateAt, RedirectUri, State, Scope, CodeChallenge, CodeChallengeMethod)
 		VALUES
-		(:ClientId, :UserId, :Code, :ExpiresIn, :CreateAt, :RedirectUri, :State, :Scope)`, authData); err != nil {
+		(:ClientId, :UserId, :Code, :ExpiresIn, :CreateAt, :RedirectUri, :State, :Scope, :CodeChallenge, :CodeChallengeMethod)`, authData); err != nil {
 		return nil, errors.Wrap(err, "failed to save AuthData")
 	}
 	return authData, nil
@@ -285,6 +286,79 @@ func (as SqlOAuthStore) PermanentDeleteAuthDataByUser(userId string) error {
 	return nil
 }
 
+func (as SqlOAuthStore) SaveDeviceCode(deviceCode *model.OAuthDeviceCode) (*model.OAuthDeviceCode, error) {
+	deviceCode.PreSave()
+	if err := deviceCode.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := as.GetMasterX().NamedExec(`INSERT INTO OAuthDeviceCodes
+		(DeviceCode, UserCode, ClientId, UserId, Scope, Status, CreateAt, ExpiresAt, LastPollAt)
+		VALUES
+		(:DeviceCode, :UserCode, :ClientId, :UserId, :Scope, :Status, :CreateAt, :ExpiresAt, :LastPollAt)`, deviceCode); err != nil {
+		return nil, errors.Wrap(err, "failed to save OAuthDeviceCode")
+	}
+	return deviceCode, nil
+}
+
+func (as SqlOAuthStore) GetDeviceCode(deviceCode string) (*model.OAuthDeviceCode, error) {
+	var dc model.OAuthDeviceCode
+	if err := as.GetMasterX().Get(&dc, `SELECT * FROM OAuthDeviceCodes WHERE DeviceCode=?`, deviceCode); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("OAuthDeviceCode", "device_code")
+		}
+		return nil, errors.Wrap(err, "failed to get OAuthDeviceCode")
+	}
+	return &dc, nil
+}
+
+func (as SqlOAuthStore) GetDeviceCodeByUserCode(userCode string) (*model.OAuthDeviceCode, error) {
+	var dc model.OAuthDeviceCode
+	if err := as.GetMasterX().Get(&dc, `SELECT * FROM OAuthDeviceCodes WHERE UserCode=?`, userCode); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("OAuthDeviceCode", fmt.Sprintf("user_code=%s", userCode))
+		}
+		return nil, errors.Wrapf(err, "failed to get OAuthDeviceCode with user_code=%s", userCode)
+	}
+	return &dc, nil
+}
+
+// UpdateDeviceCodeOptimistically updates the user, the status and the last poll time of the
+// device code if its status is still currentStatus, and reports whether it was updated.
+func (as SqlOAuthStore) UpdateDeviceCodeOptimistically(deviceCode *model.OAuthDeviceCode, currentStatus string) (bool, error) {
+	if err := deviceCode.IsValid(); err != nil {
+		return false, err
+	}
+
+	res, err := as.GetMasterX().Exec(`UPDATE OAuthDeviceCodes
+		SET UserId=?, Status=?, LastPollAt=?
+		WHERE DeviceCode=? AND Status=?`,
+		deviceCode.UserId, deviceCode.Status, deviceCode.LastPollAt, deviceCode.DeviceCode, currentStatus)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to update OAuthDeviceCode")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+	return rows == 1, nil
+}
+
+func (as SqlOAuthStore) RemoveDeviceCode(deviceCode string) error {
+	if _, err := as.GetMasterX().Exec("DELETE FROM OAuthDeviceCodes WHERE DeviceCode = ?", deviceCode); err != nil {
+		return errors.Wrap(err, "failed to delete OAuthDeviceCode")
+	}
+	return nil
+}
+
+func (as SqlOAuthStore) RemoveExpiredDeviceCodes(expiredBefore int64) error {
+	if _, err := as.GetMasterX().Exec("DELETE FROM OAuthDeviceCodes WHERE ExpiresAt < ?", expiredBefore); err != nil {
+		return errors.Wrapf(err, "failed to delete OAuthDeviceCodes expired before %d", expiredBefore)
+	}
+	return nil
+}
+
 func (as SqlOAuthStore) deleteApp(transaction *sqlxTxWrapper, clientId string) error {
 	if _, err := transaction.Exec("DELETE FROM OAuthApps WHERE Id = ?", clientId); err != nil {
 		return errors.Wrapf(err, "failed to delete OAuthApp with id=%s", clientId)
@@ -326,5 +400,9 @@ func (as SqlOAuthStore) deleteAppExtras(transaction *sqlxTxWrapper, clientId str
 		return errors.Wrapf(err, "failed to delete Preferences with name=%s", clientId)
 	}
 
+	if _, err := transaction.Exec("DELETE FROM OAuthDeviceCodes WHERE ClientId = ?", clientId); err != nil {
+		return errors.Wrapf(err, "failed to delete OAuthDeviceCodes with clientId=%s", clientId)
+	}
+
 	return nil
 }
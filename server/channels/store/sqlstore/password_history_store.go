@@ -0,0 +1,91 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var passwordHistoryColumns = []string{
+	"PasswordHistory.Id",
+	"PasswordHistory.UserId",
+	"PasswordHistory.PasswordHash",
+	"PasswordHistory.CreateAt",
+}
+
+type SqlPasswordHistoryStore struct {
+	*SqlStore
+}
+
+func newSqlPasswordHistoryStore(sqlStore *SqlStore) store.PasswordHistoryStore {
+	return &SqlPasswordHistoryStore{sqlStore}
+}
+
+func (s *SqlPasswordHistoryStore) Save(entry *model.PasswordHistoryEntry) (*model.PasswordHistoryEntry, error) {
+	if entry.Id != "" {
+		return nil, store.NewErrInvalidInput("PasswordHistoryEntry", "Id", entry.Id)
+	}
+
+	entry.PreSave()
+	if err := entry.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO PasswordHistory
+	(Id, UserId, PasswordHash, CreateAt)
+	VALUES
+	(:Id, :UserId, :PasswordHash, :CreateAt)`, entry); err != nil {
+		return nil, errors.Wrap(err, "failed to save PasswordHistoryEntry")
+	}
+	return entry, nil
+}
+
+func (s *SqlPasswordHistoryStore) GetForUser(userID string, limit int) ([]*model.PasswordHistoryEntry, error) {
+	query := s.getQueryBuilder().
+		Select(passwordHistoryColumns...).
+		From("PasswordHistory").
+		Where(sq.Eq{"UserId": userID}).
+		OrderBy("CreateAt DESC", "Id DESC").
+		Limit(uint64(limit))
+
+	entries := []*model.PasswordHistoryEntry{}
+	if err := s.GetMasterX().SelectBuilder(&entries, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get PasswordHistory for userId=%s", userID)
+	}
+	return entries, nil
+}
+
+func (s *SqlPasswordHistoryStore) PruneForUser(userID string, keep int) error {
+	entries, err := s.GetForUser(userID, keep)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder().
+		Delete("PasswordHistory").
+		Where(sq.Eq{"UserId": userID})
+	if len(entries) > 0 {
+		kept := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			kept = append(kept, entry.Id)
+		}
+		query = query.Where(sq.NotEq{"Id": kept})
+	}
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to prune PasswordHistory for userId=%s", userID)
+	}
+	return nil
+}
+
+func (s *SqlPasswordHistoryStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM PasswordHistory WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete PasswordHistory for userId=%s", userID)
+	}
+	return nil
+}
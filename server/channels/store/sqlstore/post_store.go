@@ -2430,6 +2430,68 @@ func (s *SqlPostStore) GetPostsByIds(postIds []string) ([]*model.Post, error) {
 	return posts, nil
 }
 
+func (s *SqlPostStore) GetTopReactedForUserSince(userID string, since int64, limit int) ([]*model.ReactedPost, error) {
+	query, args, err := s.getQueryBuilder().
+		Select("Reactions.PostId", "COUNT(*) AS ReactionCount").
+		From("Reactions").
+		InnerJoin("ChannelMembers ON ChannelMembers.ChannelId = Reactions.ChannelId").
+		Where(sq.Eq{
+			"ChannelMembers.UserId": userID,
+			"Reactions.DeleteAt":    0,
+		}).
+		Where(sq.Gt{"Reactions.CreateAt": since}).
+		GroupBy("Reactions.PostId").
+		OrderBy("ReactionCount DESC", "Reactions.PostId").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetTopReactedForUserSince_tosql")
+	}
+
+	var counts []struct {
+		PostId        string
+		ReactionCount int64
+	}
+	if err = s.GetReplicaX().Select(&counts, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to count Reactions for userId=%s", userID)
+	}
+	if len(counts) == 0 {
+		return []*model.ReactedPost{}, nil
+	}
+
+	postIDs := make([]string, 0, len(counts))
+	for _, count := range counts {
+		postIDs = append(postIDs, count.PostId)
+	}
+
+	query, args, err = s.getQueryBuilder().
+		Select("*").
+		From("Posts").
+		Where(sq.Eq{"Id": postIDs, "DeleteAt": 0}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetTopReactedForUserSince_posts_tosql")
+	}
+
+	posts := []*model.Post{}
+	if err = s.GetReplicaX().Select(&posts, query, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to find Posts")
+	}
+
+	postsByID := make(map[string]*model.Post, len(posts))
+	for _, post := range posts {
+		postsByID[post.Id] = post
+	}
+
+	reacted := make([]*model.ReactedPost, 0, len(posts))
+	for _, count := range counts {
+		if post, ok := postsByID[count.PostId]; ok {
+			reacted = append(reacted, &model.ReactedPost{Post: post, ReactionCount: count.ReactionCount})
+		}
+	}
+	return reacted, nil
+}
+
 func (s *SqlPostStore) GetEditHistoryForPost(postId string) ([]*model.Post, error) {
 	builder := s.getQueryBuilder().
 		Select("*").
@@ -111,6 +111,17 @@ type SqlStoreStores struct {
 	postPersistentNotification store.PostPersistentNotificationStore
 	desktopTokens              store.DesktopTokensStore
 	channelBookmarks           store.ChannelBookmarkStore
+	webSocketEvents            store.WebSocketEventStore
+	notificationRules          store.NotificationRuleStore
+	channelEmailAddresses      store.ChannelEmailAddressStore
+	auditRecords               store.AuditRecordStore
+	webAuthnCredentials        store.WebAuthnCredentialStore
+	mfaRecoveryCodes           store.MfaRecoveryCodeStore
+	mfaTrustedDevices          store.MfaTrustedDeviceStore
+	passwordHistory            store.PasswordHistoryStore
+	loginDevices               store.LoginDeviceStore
+	accessControlPolicies      store.AccessControlPolicyStore
+	customProfileAttributes    store.CustomProfileAttributeStore
 }
 
 type SqlStore struct {
@@ -236,6 +247,17 @@ func New(settings model.SqlSettings, logger mlog.LoggerIFace, metrics einterface
 	store.stores.postPersistentNotification = newSqlPostPersistentNotificationStore(store)
 	store.stores.desktopTokens = newSqlDesktopTokensStore(store, metrics)
 	store.stores.channelBookmarks = newSqlChannelBookmarkStore(store)
+	store.stores.webSocketEvents = newSqlWebSocketEventStore(store)
+	store.stores.notificationRules = newSqlNotificationRuleStore(store)
+	store.stores.channelEmailAddresses = newSqlChannelEmailAddressStore(store)
+	store.stores.auditRecords = newSqlAuditRecordStore(store)
+	store.stores.webAuthnCredentials = newSqlWebAuthnCredentialStore(store)
+	store.stores.mfaRecoveryCodes = newSqlMfaRecoveryCodeStore(store)
+	store.stores.mfaTrustedDevices = newSqlMfaTrustedDeviceStore(store)
+	store.stores.passwordHistory = newSqlPasswordHistoryStore(store)
+	store.stores.loginDevices = newSqlLoginDeviceStore(store)
+	store.stores.accessControlPolicies = newSqlAccessControlPolicyStore(store)
+	store.stores.customProfileAttributes = newSqlCustomProfileAttributeStore(store)
 
 	store.stores.preference.(*SqlPreferenceStore).deleteUnusedFeatures()
 
@@ -1039,6 +1061,50 @@ func (ss *SqlStore) ChannelBookmark() store.ChannelBookmarkStore {
 	return ss.stores.channelBookmarks
 }
 
+func (ss *SqlStore) WebSocketEvent() store.WebSocketEventStore {
+	return ss.stores.webSocketEvents
+}
+
+func (ss *SqlStore) NotificationRule() store.NotificationRuleStore {
+	return ss.stores.notificationRules
+}
+
+func (ss *SqlStore) ChannelEmailAddress() store.ChannelEmailAddressStore {
+	return ss.stores.channelEmailAddresses
+}
+
+func (ss *SqlStore) AuditRecord() store.AuditRecordStore {
+	return ss.stores.auditRecords
+}
+
+func (ss *SqlStore) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return ss.stores.webAuthnCredentials
+}
+
+func (ss *SqlStore) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	return ss.stores.mfaRecoveryCodes
+}
+
+func (ss *SqlStore) MfaTrustedDevice() store.MfaTrustedDeviceStore {
+	return ss.stores.mfaTrustedDevices
+}
+
+func (ss *SqlStore) PasswordHistory() store.PasswordHistoryStore {
+	return ss.stores.passwordHistory
+}
+
+func (ss *SqlStore) LoginDevice() store.LoginDeviceStore {
+	return ss.stores.loginDevices
+}
+
+func (ss *SqlStore) AccessControlPolicy() store.AccessControlPolicyStore {
+	return ss.stores.accessControlPolicies
+}
+
+func (ss *SqlStore) CustomProfileAttribute() store.CustomProfileAttributeStore {
+	return ss.stores.customProfileAttributes
+}
+
 func (ss *SqlStore) DropAllTables() {
 	if ss.DriverName() == model.DatabaseDriverPostgres {
 		ss.masterX.Exec(`DO
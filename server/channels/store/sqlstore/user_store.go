@@ -317,6 +317,18 @@ func (us SqlUserStore) UpdatePassword(userId, hashedPassword string) error {
 	return nil
 }
 
+func (us SqlUserStore) UpdatePasswordHash(userId, currentHash, newHash string) error {
+	result, err := us.GetMasterX().Exec("UPDATE Users SET Password = ? WHERE Id = ? AND Password = ?", newHash, userId, currentHash)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the password hash of User with userId=%s", userId)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("User", userId)
+	}
+
+	return nil
+}
+
 func (us SqlUserStore) UpdateFailedPasswordAttempts(userId string, attempts int) error {
 	if _, err := us.GetMasterX().Exec("UPDATE Users SET FailedAttempts = ? WHERE Id = ?", attempts, userId); err != nil {
 		return errors.Wrapf(err, "failed to update User with userId=%s", userId)
@@ -1603,7 +1615,10 @@ func (us SqlUserStore) SearchNotInGroup(groupID string, term string, options *mo
 	return us.performSearch(query, term, options)
 }
 
-func generateSearchQuery(query sq.SelectBuilder, terms []string, fields []string, isPostgreSQL bool) sq.SelectBuilder {
+// generateSearchQuery requires every term to match one of the fields. When
+// customProfileAttributes is set, a term may also match the value of a custom profile
+// field visible to everyone.
+func generateSearchQuery(query sq.SelectBuilder, terms []string, fields []string, customProfileAttributes bool, isPostgreSQL bool) sq.SelectBuilder {
 	for _, term := range terms {
 		searchFields := []string{}
 		termArgs := []any{}
@@ -1615,6 +1630,16 @@ func generateSearchQuery(query sq.SelectBuilder, terms []string, fields []string
 			}
 			termArgs = append(termArgs, fmt.Sprintf("%%%s%%", strings.TrimLeft(term, "@")))
 		}
+		if customProfileAttributes {
+			valueClause := "cpv.Value LIKE ? escape '*'"
+			if isPostgreSQL {
+				valueClause = "lower(cpv.Value) LIKE lower(?) escape '*'"
+			}
+			searchFields = append(searchFields, fmt.Sprintf(`EXISTS (SELECT 1 FROM CustomProfileValues cpv
+				JOIN CustomProfileFields cpf ON cpf.Id = cpv.FieldId
+				WHERE cpv.UserId = u.Id AND cpf.DeleteAt = 0 AND cpf.Visibility = '%s' AND %s)`, model.CustomProfileFieldVisibilityAll, valueClause))
+			termArgs = append(termArgs, fmt.Sprintf("%%%s%%", strings.TrimLeft(term, "@")))
+		}
 		searchFields = append(searchFields, "Id = ?")
 		termArgs = append(termArgs, strings.TrimLeft(term, "@"))
 		query = query.Where(fmt.Sprintf("(%s)", strings.Join(searchFields, " OR ")), termArgs...)
@@ -1651,7 +1676,7 @@ func (us SqlUserStore) performSearch(query sq.SelectBuilder, term string, option
 	}
 
 	if strings.TrimSpace(term) != "" {
-		query = generateSearchQuery(query, strings.Fields(term), searchType, isPostgreSQL)
+		query = generateSearchQuery(query, strings.Fields(term), searchType, true, isPostgreSQL)
 	}
 
 	query = applyViewRestrictionsFilter(query, options.ViewRestrictions, true)
@@ -1901,19 +1926,53 @@ func (us SqlUserStore) GetUsersBatchForIndexing(startTime int64, startFileID str
 		return nil, errors.Wrap(err, "failed to find TeamMembers")
 	}
 
+	customProfileFields := []*model.CustomProfileField{}
+	customProfileFieldsQuery, args, err := us.getQueryBuilder().
+		Select(customProfileFieldColumns...).
+		From("CustomProfileFields").
+		Where(sq.Eq{"DeleteAt": 0, "Visibility": model.CustomProfileFieldVisibilityAll}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetUsersBatchForIndexing_ToSql4")
+	}
+
+	err = us.GetSearchReplicaX().Select(&customProfileFields, customProfileFieldsQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find CustomProfileFields")
+	}
+
+	customProfileValues := []*model.CustomProfileValue{}
+	if len(customProfileFields) > 0 {
+		customProfileValuesQuery, args, err := us.getQueryBuilder().
+			Select(customProfileValueColumns...).
+			From("CustomProfileValues").
+			Where(sq.Eq{"UserId": userIds}).
+			ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "GetUsersBatchForIndexing_ToSql5")
+		}
+
+		err = us.GetSearchReplicaX().Select(&customProfileValues, customProfileValuesQuery, args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find CustomProfileValues")
+		}
+	}
+	customAttributes := model.CustomProfileSearchValues(customProfileFields, customProfileValues)
+
 	userMap := map[string]*model.UserForIndexing{}
 	for _, user := range users {
 		userMap[user.Id] = &model.UserForIndexing{
-			Id:          user.Id,
-			Username:    user.Username,
-			Nickname:    user.Nickname,
-			FirstName:   user.FirstName,
-			LastName:    user.LastName,
-			Roles:       user.Roles,
-			CreateAt:    user.CreateAt,
-			DeleteAt:    user.DeleteAt,
-			TeamsIds:    []string{},
-			ChannelsIds: []string{},
+			Id:               user.Id,
+			Username:         user.Username,
+			Nickname:         user.Nickname,
+			FirstName:        user.FirstName,
+			LastName:         user.LastName,
+			Roles:            user.Roles,
+			CreateAt:         user.CreateAt,
+			DeleteAt:         user.DeleteAt,
+			TeamsIds:         []string{},
+			ChannelsIds:      []string{},
+			CustomAttributes: customAttributes[user.Id],
 		}
 	}
 
@@ -2288,7 +2347,7 @@ func applyUserReportFilter(query sq.SelectBuilder, filter *model.UserReportOptio
 	}
 
 	if strings.TrimSpace(filter.SearchTerm) != "" {
-		query = generateSearchQuery(query, strings.Fields(sanitizeSearchTerm(filter.SearchTerm, "*")), UserSearchTypeAll, isPostgres)
+		query = generateSearchQuery(query, strings.Fields(sanitizeSearchTerm(filter.SearchTerm, "*")), UserSearchTypeAll, false, isPostgres)
 	}
 
 	return query
@@ -2419,3 +2478,183 @@ func (us SqlUserStore) GetUserReport(filter *model.UserReportOptions) ([]*model.
 
 	return userResults, nil
 }
+
+func (us SqlUserStore) SearchScim(filter *model.ScimFilter, offset, limit int) ([]*model.User, int64, error) {
+	conditions := sq.And{
+		sq.Expr("b.UserId IS NULL"),
+		sq.Or{sq.Eq{"u.RemoteId": nil}, sq.Eq{"u.RemoteId": ""}},
+	}
+	if filter != nil {
+		condition, err := us.scimFilterCondition(filter, "")
+		if err != nil {
+			return nil, 0, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	countQuery := us.getQueryBuilder().
+		Select("COUNT(*)").
+		From("Users u").
+		LeftJoin("Bots b ON ( b.UserId = u.Id )").
+		Where(conditions)
+
+	var total int64
+	if err := us.GetReplicaX().GetBuilder(&total, countQuery); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to count Users matching the SCIM filter")
+	}
+
+	query := us.usersQuery.
+		Where(conditions).
+		OrderBy("u.CreateAt ASC", "u.Id ASC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit))
+
+	users := []*model.User{}
+	if err := us.GetReplicaX().SelectBuilder(&users, query); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to find Users matching the SCIM filter")
+	}
+
+	return users, total, nil
+}
+
+// scimFilterCondition translates a SCIM filter on users into a condition on their columns.
+// Filters on attributes which are not stored, such as displayName, are rejected.
+func (us SqlUserStore) scimFilterCondition(filter *model.ScimFilter, prefix string) (sq.Sqlizer, error) {
+	switch filter.Operator {
+	case model.ScimFilterOperatorAnd, model.ScimFilterOperatorOr:
+		conditions := make([]sq.Sqlizer, 0, len(filter.Filters))
+		for _, operand := range filter.Filters {
+			condition, err := us.scimFilterCondition(operand, prefix)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		if filter.Operator == model.ScimFilterOperatorAnd {
+			return sq.And(conditions), nil
+		}
+		return sq.Or(conditions), nil
+	case model.ScimFilterOperatorNot:
+		condition, err := us.scimFilterCondition(filter.Filters[0], prefix)
+		if err != nil {
+			return nil, err
+		}
+		sql, args, err := condition.ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "scim_filter_tosql")
+		}
+		return sq.Expr("NOT ("+sql+")", args...), nil
+	case model.ScimFilterOperatorValuePath:
+		return us.scimFilterCondition(filter.Filters[0], filter.Attribute+".")
+	}
+
+	attribute := prefix + filter.Attribute
+	switch attribute {
+	case "active":
+		return scimBoolCondition(filter, "u.DeleteAt = 0"), nil
+	case "emails.type":
+		// The email of a user is their only one, a primary work email.
+		return scimConstantCondition(filter, "work"), nil
+	case "emails.primary":
+		return scimBoolCondition(filter, "u.Email <> ''"), nil
+	}
+
+	column, ok := map[string]string{
+		"id":              "u.Id",
+		"username":        "COALESCE(NULLIF(" + us.propsValue(model.UserPropsKeyScimUserName) + ", ''), u.Username)",
+		"externalid":      "COALESCE(" + us.propsValue(model.UserPropsKeyScimExternalId) + ", '')",
+		"emails":          "u.Email",
+		"emails.value":    "u.Email",
+		"name.givenname":  "u.FirstName",
+		"name.familyname": "u.LastName",
+		"nickname":        "u.Nickname",
+		"title":           "u.Position",
+		"locale":          "u.Locale",
+	}[attribute]
+	if !ok {
+		return nil, store.NewErrInvalidInput("User", "filter", attribute)
+	}
+
+	if filter.Operator == model.ScimFilterOperatorPresent || (filter.Value == nil && filter.Operator == model.ScimFilterOperatorNotEqual) {
+		return sq.Expr(column + " <> ''"), nil
+	}
+	if filter.Value == nil {
+		if filter.Operator == model.ScimFilterOperatorEqual {
+			return sq.Expr(column + " = ''"), nil
+		}
+		return sq.Expr("1 = 0"), nil
+	}
+
+	value, ok := filter.Value.(string)
+	if !ok {
+		// Only strings compare to the attributes of users.
+		if filter.Operator == model.ScimFilterOperatorNotEqual {
+			return sq.Expr("1 = 1"), nil
+		}
+		return sq.Expr("1 = 0"), nil
+	}
+	value = strings.ToLower(value)
+
+	lowered := "LOWER(" + column + ")"
+	switch filter.Operator {
+	case model.ScimFilterOperatorEqual:
+		return sq.Expr(lowered+" = ?", value), nil
+	case model.ScimFilterOperatorNotEqual:
+		return sq.Expr(lowered+" <> ?", value), nil
+	case model.ScimFilterOperatorContains:
+		return sq.Expr(lowered+" LIKE ? ESCAPE '*'", "%"+sanitizeSearchTerm(value, "*")+"%"), nil
+	case model.ScimFilterOperatorStarts:
+		return sq.Expr(lowered+" LIKE ? ESCAPE '*'", sanitizeSearchTerm(value, "*")+"%"), nil
+	case model.ScimFilterOperatorEnds:
+		return sq.Expr(lowered+" LIKE ? ESCAPE '*'", "%"+sanitizeSearchTerm(value, "*")), nil
+	case model.ScimFilterOperatorGreater:
+		return sq.Expr(lowered+" > ?", value), nil
+	case model.ScimFilterOperatorGreaterEq:
+		return sq.Expr(lowered+" >= ?", value), nil
+	case model.ScimFilterOperatorLess:
+		return sq.Expr(lowered+" < ?", value), nil
+	case model.ScimFilterOperatorLessEq:
+		return sq.Expr(lowered+" <= ?", value), nil
+	}
+
+	return nil, store.NewErrInvalidInput("User", "filter", filter.Operator)
+}
+
+// propsValue returns the expression of a value of the props of a user.
+func (us SqlUserStore) propsValue(key string) string {
+	if us.DriverName() == model.DatabaseDriverPostgres {
+		return "u.Props->>'" + key + "'"
+	}
+	return "JSON_UNQUOTE(JSON_EXTRACT(u.Props, '$." + key + "'))"
+}
+
+// scimBoolCondition translates a filter on a boolean attribute, true when condition holds.
+func scimBoolCondition(filter *model.ScimFilter, condition string) sq.Sqlizer {
+	expected, ok := filter.Value.(bool)
+	switch {
+	case filter.Operator == model.ScimFilterOperatorPresent:
+		return sq.Expr("1 = 1")
+	case ok && filter.Operator == model.ScimFilterOperatorEqual:
+	case ok && filter.Operator == model.ScimFilterOperatorNotEqual:
+		expected = !expected
+	case filter.Operator == model.ScimFilterOperatorNotEqual:
+		return sq.Expr("1 = 1")
+	default:
+		return sq.Expr("1 = 0")
+	}
+
+	if expected {
+		return sq.Expr(condition)
+	}
+	return sq.Expr("NOT (" + condition + ")")
+}
+
+// scimConstantCondition translates a filter on an attribute with the same value for every
+// user.
+func scimConstantCondition(filter *model.ScimFilter, value string) sq.Sqlizer {
+	constant := &model.ScimFilter{Operator: filter.Operator, Attribute: "value", Value: filter.Value}
+	if constant.Matches(map[string]any{"value": value}) {
+		return sq.Expr("1 = 1")
+	}
+	return sq.Expr("1 = 0")
+}
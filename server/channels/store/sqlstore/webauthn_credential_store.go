@@ -0,0 +1,130 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+var webAuthnCredentialColumns = []string{
+	"WebAuthnCredentials.Id",
+	"WebAuthnCredentials.UserId",
+	"WebAuthnCredentials.Name",
+	"WebAuthnCredentials.CredentialId",
+	"WebAuthnCredentials.PublicKey",
+	"WebAuthnCredentials.SignCount",
+	"WebAuthnCredentials.AAGUID",
+	"WebAuthnCredentials.CreateAt",
+	"WebAuthnCredentials.LastUsedAt",
+}
+
+type SqlWebAuthnCredentialStore struct {
+	*SqlStore
+}
+
+func newSqlWebAuthnCredentialStore(sqlStore *SqlStore) store.WebAuthnCredentialStore {
+	return &SqlWebAuthnCredentialStore{sqlStore}
+}
+
+func (s *SqlWebAuthnCredentialStore) Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error) {
+	if credential.Id != "" {
+		return nil, store.NewErrInvalidInput("WebAuthnCredential", "Id", credential.Id)
+	}
+
+	credential.PreSave()
+	if err := credential.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO WebAuthnCredentials
+	(Id, UserId, Name, CredentialId, PublicKey, SignCount, AAGUID, CreateAt, LastUsedAt)
+	VALUES
+	(:Id, :UserId, :Name, :CredentialId, :PublicKey, :SignCount, :AAGUID, :CreateAt, :LastUsedAt)`, credential); err != nil {
+		if IsUniqueConstraintError(err, []string{"CredentialId", "idx_webauthncredentials_credentialid"}) {
+			return nil, store.NewErrConflict("WebAuthnCredential", err, "credentialId="+credential.CredentialId)
+		}
+		return nil, errors.Wrap(err, "failed to save WebAuthnCredential")
+	}
+	return credential, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) get(column, value string) (*model.WebAuthnCredential, error) {
+	query := s.getQueryBuilder().
+		Select(webAuthnCredentialColumns...).
+		From("WebAuthnCredentials").
+		Where(sq.Eq{column: value})
+
+	credential := &model.WebAuthnCredential{}
+	if err := s.GetReplicaX().GetBuilder(credential, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("WebAuthnCredential", value)
+		}
+		return nil, errors.Wrapf(err, "failed to get WebAuthnCredential with %s=%s", column, value)
+	}
+	return credential, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) Get(id string) (*model.WebAuthnCredential, error) {
+	return s.get("Id", id)
+}
+
+func (s *SqlWebAuthnCredentialStore) GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error) {
+	return s.get("CredentialId", credentialID)
+}
+
+func (s *SqlWebAuthnCredentialStore) GetForUser(userID string) ([]*model.WebAuthnCredential, error) {
+	query := s.getQueryBuilder().
+		Select(webAuthnCredentialColumns...).
+		From("WebAuthnCredentials").
+		Where(sq.Eq{"UserId": userID}).
+		OrderBy("CreateAt ASC", "Id ASC")
+
+	credentials := []*model.WebAuthnCredential{}
+	if err := s.GetReplicaX().SelectBuilder(&credentials, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get WebAuthnCredentials for userId=%s", userID)
+	}
+	return credentials, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) UpdateSignCount(id string, signCount int64, lastUsedAt int64) error {
+	query := s.getQueryBuilder().
+		Update("WebAuthnCredentials").
+		Set("SignCount", signCount).
+		Set("LastUsedAt", lastUsedAt).
+		Where(sq.Eq{"Id": id})
+	if signCount == 0 {
+		query = query.Where(sq.Eq{"SignCount": 0})
+	} else {
+		query = query.Where(sq.Lt{"SignCount": signCount})
+	}
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update WebAuthnCredential with id=%s", id)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return store.NewErrNotFound("WebAuthnCredential", id)
+	}
+	return nil
+}
+
+func (s *SqlWebAuthnCredentialStore) Delete(id string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM WebAuthnCredentials WHERE Id=?`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete WebAuthnCredential with id=%s", id)
+	}
+	return nil
+}
+
+func (s *SqlWebAuthnCredentialStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMasterX().Exec(`DELETE FROM WebAuthnCredentials WHERE UserId=?`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete WebAuthnCredentials for userId=%s", userID)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"slices"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlWebSocketEventStore struct {
+	*SqlStore
+}
+
+func newSqlWebSocketEventStore(sqlStore *SqlStore) store.WebSocketEventStore {
+	return &SqlWebSocketEventStore{
+		SqlStore: sqlStore,
+	}
+}
+
+type webSocketEventRow struct {
+	Seq      int64
+	CreateAt int64
+	UserId   string
+	Event    string
+}
+
+func (s *SqlWebSocketEventStore) Save(entry *model.WebSocketEventLogEntry) (*model.WebSocketEventLogEntry, error) {
+	saved, err := s.SaveMultiple([]*model.WebSocketEventLogEntry{entry})
+	if err != nil {
+		return nil, err
+	}
+	return saved[0], nil
+}
+
+func (s *SqlWebSocketEventStore) SaveMultiple(entries []*model.WebSocketEventLogEntry) ([]*model.WebSocketEventLogEntry, error) {
+	builder := s.getQueryBuilder().
+		Insert("WebSocketEvents").
+		Columns("CreateAt", "UserId", "Event")
+	for _, entry := range entries {
+		builder = builder.Values(entry.CreateAt, entry.UserId, string(entry.Event))
+	}
+
+	saved := make([]*model.WebSocketEventLogEntry, len(entries))
+	for i, entry := range entries {
+		copied := *entry
+		saved[i] = &copied
+	}
+
+	if s.DriverName() == model.DatabaseDriverPostgres {
+		query, args, err := builder.Suffix("RETURNING Seq").ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "websocketevent_savemultiple_tosql")
+		}
+		seqs := []int64{}
+		if err := s.GetMasterX().Select(&seqs, query, args...); err != nil {
+			return nil, errors.Wrap(err, "failed to save WebSocketEvents")
+		}
+		if len(seqs) != len(saved) {
+			return nil, errors.Errorf("saved %d WebSocketEvents out of %d", len(seqs), len(saved))
+		}
+		// The sequences are assigned in the order of the rows.
+		slices.Sort(seqs)
+		for i, seq := range seqs {
+			saved[i].Seq = seq
+		}
+		return saved, nil
+	}
+
+	result, err := s.GetMasterX().ExecBuilder(builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save WebSocketEvents")
+	}
+	// The sequences of the rows inserted by a single statement are consecutive,
+	// starting from the one of the first row.
+	first, err := result.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the sequence of the saved WebSocketEvents")
+	}
+	for i := range saved {
+		saved[i].Seq = first + int64(i)
+	}
+	return saved, nil
+}
+
+func (s *SqlWebSocketEventStore) GetSince(userID string, seq int64, overlap int64, limit int) ([]*model.WebSocketEventLogEntry, error) {
+	from := seq
+	if overlap > 0 {
+		query := s.getQueryBuilder().
+			Select("MIN(Seq)").
+			From("WebSocketEvents").
+			Where(sq.Expr("CreateAt >= (SELECT CreateAt FROM WebSocketEvents WHERE Seq = ?) - ?", seq, overlap))
+
+		var start sql.NullInt64
+		if err := s.GetMasterX().GetBuilder(&start, query); err != nil {
+			return nil, errors.Wrapf(err, "failed to get the WebSocketEvents overlapping seq=%d", seq)
+		}
+		if start.Valid && start.Int64 <= from {
+			from = start.Int64 - 1
+		}
+	}
+
+	query := s.getQueryBuilder().
+		Select("Seq", "CreateAt", "UserId", "Event").
+		From("WebSocketEvents").
+		Where(sq.And{
+			sq.Gt{"Seq": from},
+			sq.Eq{"UserId": []string{"", userID}},
+		}).
+		OrderBy("Seq ASC").
+		Limit(uint64(limit))
+
+	// Events are read right after being written, when clients reconnect.
+	rows := []webSocketEventRow{}
+	if err := s.GetMasterX().SelectBuilder(&rows, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get WebSocketEvents since seq=%d", seq)
+	}
+
+	entries := make([]*model.WebSocketEventLogEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, &model.WebSocketEventLogEntry{
+			Seq:      row.Seq,
+			CreateAt: row.CreateAt,
+			UserId:   row.UserId,
+			Event:    []byte(row.Event),
+		})
+	}
+	return entries, nil
+}
+
+func (s *SqlWebSocketEventStore) GetSeqRange() (int64, int64, error) {
+	query := s.getQueryBuilder().
+		Select("COALESCE(MIN(Seq), 0) AS Oldest", "COALESCE(MAX(Seq), 0) AS Latest").
+		From("WebSocketEvents")
+
+	var seqRange struct {
+		Oldest int64
+		Latest int64
+	}
+	if err := s.GetMasterX().GetBuilder(&seqRange, query); err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get the WebSocketEvents sequence range")
+	}
+	return seqRange.Oldest, seqRange.Latest, nil
+}
+
+func (s *SqlWebSocketEventStore) DeleteOlderThan(createAt int64, limit int) (int64, error) {
+	_, latest, err := s.GetSeqRange()
+	if err != nil {
+		return 0, err
+	}
+
+	condition := sq.And{
+		sq.Lt{"CreateAt": createAt},
+		sq.Lt{"Seq": latest},
+	}
+
+	var builder sq.DeleteBuilder
+	if s.DriverName() == model.DatabaseDriverPostgres {
+		// Postgres doesn't support LIMIT in a DELETE statement.
+		subQuery := s.getSubQueryBuilder().
+			Select("Seq").
+			From("WebSocketEvents").
+			Where(condition).
+			Limit(uint64(limit))
+		builder = s.getQueryBuilder().
+			Delete("WebSocketEvents").
+			Where(sq.Expr("Seq IN (?)", subQuery))
+	} else {
+		builder = s.getQueryBuilder().
+			Delete("WebSocketEvents").
+			Where(condition).
+			Limit(uint64(limit))
+	}
+
+	result, err := s.GetMasterX().ExecBuilder(builder)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete WebSocketEvents")
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get the number of deleted WebSocketEvents")
+	}
+	return deleted, nil
+}
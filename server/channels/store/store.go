@@ -91,6 +91,17 @@ type Store interface {
 	PostPersistentNotification() PostPersistentNotificationStore
 	DesktopTokens() DesktopTokensStore
 	ChannelBookmark() ChannelBookmarkStore
+	WebSocketEvent() WebSocketEventStore
+	NotificationRule() NotificationRuleStore
+	ChannelEmailAddress() ChannelEmailAddressStore
+	AuditRecord() AuditRecordStore
+	WebAuthnCredential() WebAuthnCredentialStore
+	MfaRecoveryCode() MfaRecoveryCodeStore
+	MfaTrustedDevice() MfaTrustedDeviceStore
+	PasswordHistory() PasswordHistoryStore
+	LoginDevice() LoginDeviceStore
+	AccessControlPolicy() AccessControlPolicyStore
+	CustomProfileAttribute() CustomProfileAttributeStore
 }
 
 type RetentionPolicyStore interface {
@@ -215,6 +226,9 @@ type ChannelStore interface {
 	GetTeamChannels(teamID string) (model.ChannelList, error)
 	GetAll(teamID string) ([]*model.Channel, error)
 	GetChannelsByIds(channelIds []string, includeDeleted bool) ([]*model.Channel, error)
+	// GetNewPublicChannelsForUser returns the public channels created since the given time
+	// in the teams of the user that the user hasn't joined, newest first.
+	GetNewPublicChannelsForUser(userID string, since int64, limit int) ([]*model.ChannelWithTeamData, error)
 	GetChannelsWithTeamDataByIds(channelIds []string, includeDeleted bool) ([]*model.ChannelWithTeamData, error)
 	GetForPost(postID string) (*model.Channel, error)
 	SaveMultipleMembers(members []*model.ChannelMember) ([]*model.ChannelMember, error)
@@ -384,6 +398,9 @@ type PostStore interface {
 	Overwrite(rctx request.CTX, post *model.Post) (*model.Post, error)
 	OverwriteMultiple(posts []*model.Post) ([]*model.Post, int, error)
 	GetPostsByIds(postIds []string) ([]*model.Post, error)
+	// GetTopReactedForUserSince returns the posts of the channels of the user that
+	// received the most reactions since the given time.
+	GetTopReactedForUserSince(userID string, since int64, limit int) ([]*model.ReactedPost, error)
 	GetEditHistoryForPost(postId string) ([]*model.Post, error)
 	GetPostsBatchForIndexing(startTime int64, startPostID string, limit int) ([]*model.PostForIndexing, error)
 	PermanentDeleteBatchForRetentionPolicies(now, globalPolicyEndTime, limit int64, cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error)
@@ -411,6 +428,10 @@ type UserStore interface {
 	UpdateLastPictureUpdate(userID string) error
 	ResetLastPictureUpdate(userID string) error
 	UpdatePassword(userID, newPassword string) error
+	// UpdatePasswordHash replaces the hash of the password of a user with a hash of the
+	// same password, provided the hash is still currentHash. It does not count as a
+	// password change.
+	UpdatePasswordHash(userID, currentHash, newHash string) error
 	UpdateUpdateAt(userID string) (int64, error)
 	UpdateAuthData(userID string, service string, authData *string, email string, resetMfa bool) (string, error)
 	UpdateLastLogin(userID string, lastLogin int64) error
@@ -486,6 +507,9 @@ type UserStore interface {
 	RefreshPostStatsForUsers() error
 	GetUserReport(filter *model.UserReportOptions) ([]*model.UserReportQuery, error)
 	GetUserCountForReport(filter *model.UserReportOptions) (int64, error)
+	// SearchScim returns the page of users, other than bots and remote users, matching a
+	// SCIM filter, and the number of users matching it. A nil filter matches every user.
+	SearchScim(filter *model.ScimFilter, offset, limit int) ([]*model.User, int64, error)
 }
 
 type BotStore interface {
@@ -573,6 +597,12 @@ type OAuthStore interface {
 	GetPreviousAccessData(userID, clientId string) (*model.AccessData, error)
 	RemoveAccessData(token string) error
 	RemoveAllAccessData() error
+	SaveDeviceCode(deviceCode *model.OAuthDeviceCode) (*model.OAuthDeviceCode, error)
+	GetDeviceCode(deviceCode string) (*model.OAuthDeviceCode, error)
+	GetDeviceCodeByUserCode(userCode string) (*model.OAuthDeviceCode, error)
+	UpdateDeviceCodeOptimistically(deviceCode *model.OAuthDeviceCode, currentStatus string) (bool, error)
+	RemoveDeviceCode(deviceCode string) error
+	RemoveExpiredDeviceCodes(expiredBefore int64) error
 }
 
 type OutgoingOAuthConnectionStore interface {
@@ -682,6 +712,128 @@ type DesktopTokensStore interface {
 	DeleteOlderThan(minCreatedAt int64) error
 }
 
+// WebSocketEventStore keeps the log of the WebSocket events clients resume from.
+type WebSocketEventStore interface {
+	Save(entry *model.WebSocketEventLogEntry) (*model.WebSocketEventLogEntry, error)
+	// SaveMultiple saves the events with consecutive sequence numbers, in order.
+	SaveMultiple(entries []*model.WebSocketEventLogEntry) ([]*model.WebSocketEventLogEntry, error)
+	// GetSince returns up to limit events following seq, broadcast either to the
+	// given user or to anyone. The events created up to overlap milliseconds
+	// before the one at seq are returned as well, since events may be committed
+	// in a different order than their sequence numbers.
+	GetSince(userID string, seq int64, overlap int64, limit int) ([]*model.WebSocketEventLogEntry, error)
+	// GetSeqRange returns the oldest and the latest sequence numbers of the log.
+	GetSeqRange() (int64, int64, error)
+	// DeleteOlderThan deletes up to limit events created before createAt. The
+	// latest event is always kept, to know the sequence numbers still valid.
+	DeleteOlderThan(createAt int64, limit int) (int64, error)
+}
+
+type NotificationRuleStore interface {
+	Save(rule *model.NotificationRule) (*model.NotificationRule, error)
+	Update(rule *model.NotificationRule) (*model.NotificationRule, error)
+	Get(id string) (*model.NotificationRule, error)
+	GetForUser(userID string) ([]*model.NotificationRule, error)
+	// GetForChannelMembers returns the rules of the members of the channel.
+	GetForChannelMembers(channelID string) ([]*model.NotificationRule, error)
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type ChannelEmailAddressStore interface {
+	Save(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error)
+	Update(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error)
+	Get(channelID string) (*model.ChannelEmailAddress, error)
+	GetByToken(token string) (*model.ChannelEmailAddress, error)
+	Delete(channelID string) error
+}
+
+// AuditRecordStore persists the hash chained audit records.
+type AuditRecordStore interface {
+	Save(record *model.AuditRecord) (*model.AuditRecord, error)
+	// Search returns the records matching the options, most recent first, i.e. sorted by
+	// descending CreateAt and Id.
+	Search(opts model.AuditRecordSearchOptions) ([]*model.AuditRecord, error)
+}
+
+type WebAuthnCredentialStore interface {
+	Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error)
+	Get(id string) (*model.WebAuthnCredential, error)
+	GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error)
+	GetForUser(userID string) ([]*model.WebAuthnCredential, error)
+	// UpdateSignCount records a use of the credential. It only succeeds while the stored
+	// signature counter is lower than the new one, or both are zero, so that concurrent
+	// assertions cannot replay the same counter.
+	UpdateSignCount(id string, signCount int64, lastUsedAt int64) error
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type MfaRecoveryCodeStore interface {
+	// Replace discards the recovery codes of the user and saves the given ones.
+	Replace(userID string, codes []*model.MfaRecoveryCode) error
+	GetUnusedForUser(userID string) ([]*model.MfaRecoveryCode, error)
+	// MarkUsed consumes a recovery code, and returns a not found error when the code
+	// was already used.
+	MarkUsed(id string, usedAt int64) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type MfaTrustedDeviceStore interface {
+	Save(device *model.MfaTrustedDevice) (*model.MfaTrustedDevice, error)
+	Get(id string) (*model.MfaTrustedDevice, error)
+	UpdateLastUsedAt(id string, lastUsedAt int64) error
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+	// Cleanup deletes the trusted devices which expired before the given time.
+	Cleanup(expiryTime int64) error
+}
+
+type PasswordHistoryStore interface {
+	Save(entry *model.PasswordHistoryEntry) (*model.PasswordHistoryEntry, error)
+	// GetForUser returns the most recent previous passwords of the user first.
+	GetForUser(userID string, limit int) ([]*model.PasswordHistoryEntry, error)
+	// PruneForUser deletes all but the keep most recent previous passwords of the user.
+	PruneForUser(userID string, keep int) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type LoginDeviceStore interface {
+	Save(device *model.LoginDevice) (*model.LoginDevice, error)
+	// GetForUser returns the most recently seen devices of the user first.
+	GetForUser(userID string, limit int) ([]*model.LoginDevice, error)
+	UpdateLastSeenAt(id string, lastSeenAt int64) error
+	// PruneForUser deletes all but the keep most recently seen devices of the user.
+	PruneForUser(userID string, keep int) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type AccessControlPolicyStore interface {
+	// Save creates the policy of the channel, or replaces its expression.
+	Save(policy *model.AccessControlPolicy) (*model.AccessControlPolicy, error)
+	Get(channelID string) (*model.AccessControlPolicy, error)
+	// GetAll returns the policies ordered by channel id, starting after afterChannelID.
+	GetAll(afterChannelID string, limit int) ([]*model.AccessControlPolicy, error)
+	Delete(channelID string) error
+}
+
+type CustomProfileAttributeStore interface {
+	SaveField(field *model.CustomProfileField) (*model.CustomProfileField, error)
+	UpdateField(rctx request.CTX, field *model.CustomProfileField) (*model.CustomProfileField, error)
+	GetField(id string) (*model.CustomProfileField, error)
+	// GetFields returns the fields ordered by sort order, then name.
+	GetFields(includeDeleted bool) ([]*model.CustomProfileField, error)
+	// DeleteField marks the field as deleted and deletes its values.
+	DeleteField(rctx request.CTX, id string, deleteAt int64) error
+	// GetValuesForUsers returns the values of the fields which are not deleted.
+	GetValuesForUsers(userIDs []string) ([]*model.CustomProfileValue, error)
+	// GetUserIDsForField returns the ids of the users who have a value for the field.
+	GetUserIDsForField(fieldID string) ([]string, error)
+	// SaveValues creates or replaces the values of the user, deleting the empty ones.
+	SaveValues(rctx request.CTX, userID string, values []*model.CustomProfileValue) error
+	PermanentDeleteByUser(userID string) error
+}
+
 type EmojiStore interface {
 	Save(emoji *model.Emoji) (*model.Emoji, error)
 	Get(c request.CTX, id string, allowFromCache bool) (*model.Emoji, error)
@@ -719,11 +871,15 @@ type FileInfoStore interface {
 	PermanentDeleteBatch(ctx request.CTX, endTime int64, limit int64) (int64, error)
 	PermanentDeleteByUser(ctx request.CTX, userID string) (int64, error)
 	SetContent(ctx request.CTX, fileID, content string) error
+	SetTier(ctx request.CTX, fileID, tier string) error
 	Search(ctx request.CTX, paramsList []*model.SearchParams, userID, teamID string, page, perPage int) (*model.FileInfoList, error)
 	CountAll() (int64, error)
 	GetFilesBatchForIndexing(startTime int64, startFileID string, includeDeleted bool, limit int) ([]*model.FileForIndexing, error)
 	ClearCaches()
 	GetStorageUsage(allowFromCache, includeDeleted bool) (int64, error)
+	GetStorageUsageByTier(includeDeleted bool) ([]*model.FileTierUsage, error)
+	// GetFilesBatchForColdStorage returns the files created before olderThan that are still in the hot tier.
+	GetFilesBatchForColdStorage(olderThan, startTime int64, startFileID string, limit int) ([]*model.FileInfo, error)
 	// GetUptoNSizeFileTime returns the CreateAt time of the last accessible file with a running-total size upto n bytes.
 	GetUptoNSizeFileTime(n int64) (int64, error)
 }
@@ -859,6 +1015,8 @@ type GroupStore interface {
 	GetByRemoteID(remoteID string, groupSource model.GroupSource) (*model.Group, error)
 	GetAllBySource(groupSource model.GroupSource) ([]*model.Group, error)
 	GetByUser(userID string) ([]*model.Group, error)
+	// GetByUsers returns the groups of several users, keyed by user ID.
+	GetByUsers(userIDs []string) (map[string][]*model.Group, error)
 	Update(group *model.Group) (*model.Group, error)
 	Delete(groupID string) (*model.Group, error)
 	Restore(groupID string) (*model.Group, error)
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestAccessControlPolicyStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGet", func(t *testing.T) { testAccessControlPolicySaveGet(t, rctx, ss) })
+	t.Run("GetAll", func(t *testing.T) { testAccessControlPolicyGetAll(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testAccessControlPolicyDelete(t, rctx, ss) })
+}
+
+func testAccessControlPolicySaveGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	channelID := model.NewId()
+
+	_, err := ss.AccessControlPolicy().Save(&model.AccessControlPolicy{ChannelId: channelID, CreatorId: model.NewId(), Expression: "user.attributes.department"})
+	require.Error(t, err)
+
+	_, err = ss.AccessControlPolicy().Get(channelID)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+
+	saved, err := ss.AccessControlPolicy().Save(&model.AccessControlPolicy{
+		ChannelId:  channelID,
+		CreatorId:  model.NewId(),
+		Expression: `user.attributes.department == "Engineering"`,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, saved.CreateAt)
+
+	policy, err := ss.AccessControlPolicy().Get(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, saved, policy)
+
+	updated, err := ss.AccessControlPolicy().Save(&model.AccessControlPolicy{
+		ChannelId:  channelID,
+		CreatorId:  model.NewId(),
+		Expression: `"secret" in user.groups`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, saved.CreateAt, updated.CreateAt)
+	assert.Equal(t, `"secret" in user.groups`, updated.Expression)
+	assert.NotEqual(t, saved.CreatorId, updated.CreatorId)
+}
+
+func testAccessControlPolicyGetAll(t *testing.T, rctx request.CTX, ss store.Store) {
+	existing, err := ss.AccessControlPolicy().GetAll("", 1000)
+	require.NoError(t, err)
+	for _, policy := range existing {
+		require.NoError(t, ss.AccessControlPolicy().Delete(policy.ChannelId))
+	}
+
+	channelIDs := []string{model.NewId(), model.NewId(), model.NewId()}
+	for _, channelID := range channelIDs {
+		_, err = ss.AccessControlPolicy().Save(&model.AccessControlPolicy{
+			ChannelId:  channelID,
+			CreatorId:  model.NewId(),
+			Expression: `user.auth_service == "saml"`,
+		})
+		require.NoError(t, err)
+	}
+	sort.Strings(channelIDs)
+
+	policies, err := ss.AccessControlPolicy().GetAll("", 2)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, channelIDs[0], policies[0].ChannelId)
+	assert.Equal(t, channelIDs[1], policies[1].ChannelId)
+
+	policies, err = ss.AccessControlPolicy().GetAll(policies[1].ChannelId, 2)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, channelIDs[2], policies[0].ChannelId)
+}
+
+func testAccessControlPolicyDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	policy, err := ss.AccessControlPolicy().Save(&model.AccessControlPolicy{
+		ChannelId:  model.NewId(),
+		CreatorId:  model.NewId(),
+		Expression: `user.auth_service == "saml"`,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.AccessControlPolicy().Delete(policy.ChannelId))
+
+	_, err = ss.AccessControlPolicy().Get(policy.ChannelId)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+
+	err = ss.AccessControlPolicy().Delete(policy.ChannelId)
+	require.True(t, errors.As(err, &nfErr))
+}
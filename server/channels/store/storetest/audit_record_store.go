@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestAuditRecordStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveSearch", func(t *testing.T) { testAuditRecordSaveSearch(t, rctx, ss) })
+	t.Run("SearchPages", func(t *testing.T) { testAuditRecordSearchPages(t, rctx, ss) })
+}
+
+func testAuditRecordSaveSearch(t *testing.T, rctx request.CTX, ss store.Store) {
+	chainID := model.NewId()
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	save := func(seq int64, createAt int64, eventName, userID, ipAddress string) *model.AuditRecord {
+		record, err := ss.AuditRecord().Save(&model.AuditRecord{
+			ChainId:    chainID,
+			Sequence:   seq,
+			CreateAt:   createAt,
+			EventName:  eventName,
+			Status:     "success",
+			UserId:     userID,
+			IpAddress:  ipAddress,
+			ObjectType: "user",
+			Hash:       strings.Repeat("a", 64),
+			Data:       json.RawMessage(`{"event_name":"` + eventName + `"}`),
+		})
+		require.NoError(t, err)
+		return record
+	}
+
+	login := save(1, 1000, "login", userID, "10.0.0.1")
+	update := save(2, 2000, "updateUser", userID, "10.0.0.1")
+	otherLogin := save(3, 3000, "login", otherUserID, "10.0.0.2")
+
+	t.Run("save invalid", func(t *testing.T) {
+		_, err := ss.AuditRecord().Save(&model.AuditRecord{ChainId: chainID})
+		require.Error(t, err)
+	})
+
+	t.Run("save duplicate sequence", func(t *testing.T) {
+		_, err := ss.AuditRecord().Save(&model.AuditRecord{ChainId: chainID, Sequence: 1, Hash: strings.Repeat("b", 64), Data: json.RawMessage(`{}`)})
+		require.Error(t, err)
+	})
+
+	for name, tc := range map[string]struct {
+		opts     model.AuditRecordSearchOptions
+		expected []*model.AuditRecord
+	}{
+		"by user":              {opts: model.AuditRecordSearchOptions{UserId: userID}, expected: []*model.AuditRecord{update, login}},
+		"by event":             {opts: model.AuditRecordSearchOptions{EventName: "login", StartTime: 1000, EndTime: 3000}, expected: []*model.AuditRecord{otherLogin, login}},
+		"by time range":        {opts: model.AuditRecordSearchOptions{StartTime: 1500, EndTime: 2500}, expected: []*model.AuditRecord{update}},
+		"with limit":           {opts: model.AuditRecordSearchOptions{StartTime: 1000, EndTime: 3000, Limit: 1}, expected: []*model.AuditRecord{otherLogin}},
+		"by ip address":        {opts: model.AuditRecordSearchOptions{IpAddress: "10.0.0.2", StartTime: 1000}, expected: []*model.AuditRecord{otherLogin}},
+		"by object and status": {opts: model.AuditRecordSearchOptions{ObjectType: "user", Status: "success", UserId: userID}, expected: []*model.AuditRecord{update, login}},
+		"after cursor":         {opts: model.AuditRecordSearchOptions{UserId: userID, Cursor: model.AuditRecordCursor{CreateAt: update.CreateAt, Id: update.Id}}, expected: []*model.AuditRecord{login}},
+		"no match":             {opts: model.AuditRecordSearchOptions{UserId: userID, EventName: "deleteUser"}, expected: []*model.AuditRecord{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			records, err := ss.AuditRecord().Search(tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, records)
+		})
+	}
+}
+
+func testAuditRecordSearchPages(t *testing.T, rctx request.CTX, ss store.Store) {
+	chainID := model.NewId()
+	userID := model.NewId()
+
+	// Records created at the same time are ordered by their id.
+	for i := 0; i < 5; i++ {
+		_, err := ss.AuditRecord().Save(&model.AuditRecord{
+			ChainId:   chainID,
+			Sequence:  int64(i + 1),
+			CreateAt:  1000 + int64(i/2),
+			EventName: "login",
+			UserId:    userID,
+			Hash:      strings.Repeat("a", 64),
+			Data:      json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+	}
+
+	var all []*model.AuditRecord
+	opts := model.AuditRecordSearchOptions{UserId: userID, Limit: 2}
+	for {
+		records, err := ss.AuditRecord().Search(opts)
+		require.NoError(t, err)
+		if len(records) == 0 {
+			break
+		}
+		all = append(all, records...)
+		last := records[len(records)-1]
+		opts.Cursor = model.AuditRecordCursor{CreateAt: last.CreateAt, Id: last.Id}
+	}
+
+	require.Len(t, all, 5)
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i-1].CreateAt > all[i].CreateAt || (all[i-1].CreateAt == all[i].CreateAt && all[i-1].Id > all[i].Id))
+	}
+}
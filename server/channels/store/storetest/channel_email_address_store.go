@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestChannelEmailAddressStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGetUpdateDelete", func(t *testing.T) { testChannelEmailAddressSaveGetUpdateDelete(t, rctx, ss) })
+}
+
+func testChannelEmailAddressSaveGetUpdateDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	channelID := model.NewId()
+
+	t.Run("save invalid", func(t *testing.T) {
+		_, err := ss.ChannelEmailAddress().Save(&model.ChannelEmailAddress{ChannelId: channelID})
+		require.Error(t, err)
+	})
+
+	address, err := ss.ChannelEmailAddress().Save(&model.ChannelEmailAddress{
+		ChannelId:      channelID,
+		CreatorId:      model.NewId(),
+		AllowedSenders: model.StringArray{"@example.com"},
+	})
+	require.NoError(t, err)
+	require.True(t, model.IsValidId(address.Token))
+
+	t.Run("save twice", func(t *testing.T) {
+		_, err := ss.ChannelEmailAddress().Save(&model.ChannelEmailAddress{ChannelId: channelID, CreatorId: model.NewId()})
+		var conflictErr *store.ErrConflict
+		require.ErrorAs(t, err, &conflictErr)
+	})
+
+	got, err := ss.ChannelEmailAddress().Get(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, address, got)
+
+	got, err = ss.ChannelEmailAddress().GetByToken(address.Token)
+	require.NoError(t, err)
+	assert.Equal(t, address, got)
+
+	oldToken := address.Token
+	address.Token = model.NewId()
+	address.WebhookId = model.NewId()
+	address.AllowedSenders = model.StringArray{}
+	_, err = ss.ChannelEmailAddress().Update(address)
+	require.NoError(t, err)
+
+	_, err = ss.ChannelEmailAddress().GetByToken(oldToken)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	got, err = ss.ChannelEmailAddress().GetByToken(address.Token)
+	require.NoError(t, err)
+	assert.Equal(t, address, got)
+
+	t.Run("update missing", func(t *testing.T) {
+		_, err := ss.ChannelEmailAddress().Update(&model.ChannelEmailAddress{ChannelId: model.NewId(), Token: model.NewId(), CreatorId: model.NewId(), CreateAt: 1})
+		require.ErrorAs(t, err, &nfErr)
+	})
+
+	require.NoError(t, ss.ChannelEmailAddress().Delete(channelID))
+	_, err = ss.ChannelEmailAddress().Get(channelID)
+	require.ErrorAs(t, err, &nfErr)
+}
@@ -76,6 +76,7 @@ func TestChannelStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore
 	t.Run("Get", func(t *testing.T) { testChannelStoreGet(t, rctx, ss, s) })
 	t.Run("GetMany", func(t *testing.T) { testChannelStoreGetMany(t, rctx, ss, s) })
 	t.Run("GetChannelsByIds", func(t *testing.T) { testChannelStoreGetChannelsByIds(t, rctx, ss) })
+	t.Run("GetNewPublicChannelsForUser", func(t *testing.T) { testChannelStoreGetNewPublicChannelsForUser(t, rctx, ss) })
 	t.Run("GetChannelsWithTeamDataByIds", func(t *testing.T) { testGetChannelsWithTeamDataByIds(t, rctx, ss) })
 	t.Run("GetForPost", func(t *testing.T) { testChannelStoreGetForPost(t, rctx, ss) })
 	t.Run("Restore", func(t *testing.T) { testChannelStoreRestore(t, rctx, ss) })
@@ -530,6 +531,54 @@ func testChannelStoreGetMany(t *testing.T, rctx request.CTX, ss store.Store, s S
 	s.GetMasterX().Exec("TRUNCATE Channels")
 }
 
+func testChannelStoreGetNewPublicChannelsForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	team, err := ss.Team().Save(&model.Team{
+		DisplayName: "DisplayName",
+		Name:        NewTestId(),
+		Email:       MakeEmail(),
+		Type:        model.TeamOpen,
+	})
+	require.NoError(t, err)
+
+	userID := model.NewId()
+	_, err = ss.Team().SaveMember(rctx, &model.TeamMember{TeamId: team.Id, UserId: userID}, -1)
+	require.NoError(t, err)
+
+	saveChannel := func(teamID string, channelType model.ChannelType) *model.Channel {
+		channel, err := ss.Channel().Save(rctx, &model.Channel{
+			TeamId:      teamID,
+			DisplayName: "DisplayName",
+			Name:        "channel" + model.NewId(),
+			Type:        channelType,
+		}, -1)
+		require.NoError(t, err)
+		return channel
+	}
+
+	older := saveChannel(team.Id, model.ChannelTypeOpen)
+	since := older.CreateAt
+	time.Sleep(time.Millisecond)
+
+	newChannel := saveChannel(team.Id, model.ChannelTypeOpen)
+	saveChannel(team.Id, model.ChannelTypePrivate)
+	saveChannel(model.NewId(), model.ChannelTypeOpen)
+	joined := saveChannel(team.Id, model.ChannelTypeOpen)
+	_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{ChannelId: joined.Id, UserId: userID, NotifyProps: model.GetDefaultChannelNotifyProps()})
+	require.NoError(t, err)
+	deleted := saveChannel(team.Id, model.ChannelTypeOpen)
+	require.NoError(t, ss.Channel().Delete(deleted.Id, model.GetMillis()))
+
+	channels, err := ss.Channel().GetNewPublicChannelsForUser(userID, since, 10)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assert.Equal(t, newChannel.Id, channels[0].Id)
+	assert.Equal(t, team.Name, channels[0].TeamName)
+
+	channels, err = ss.Channel().GetNewPublicChannelsForUser(model.NewId(), since, 10)
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+}
+
 func testChannelStoreGetChannelsByIds(t *testing.T, rctx request.CTX, ss store.Store) {
 	o1 := model.Channel{}
 	o1.TeamId = model.NewId()
@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestCustomProfileAttributeStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("Fields", func(t *testing.T) { testCustomProfileAttributeFields(t, rctx, ss) })
+	t.Run("Values", func(t *testing.T) { testCustomProfileAttributeValues(t, rctx, ss) })
+	t.Run("UserSearch", func(t *testing.T) { testCustomProfileAttributeUserSearch(t, rctx, ss) })
+}
+
+func saveCustomProfileField(t *testing.T, ss store.Store, name, fieldType string, options ...string) *model.CustomProfileField {
+	field, err := ss.CustomProfileAttribute().SaveField(&model.CustomProfileField{
+		Name:        name,
+		DisplayName: name,
+		Type:        fieldType,
+		Options:     options,
+	})
+	require.NoError(t, err)
+	return field
+}
+
+func testCustomProfileAttributeFields(t *testing.T, rctx request.CTX, ss store.Store) {
+	name := "field_" + model.NewId()
+	field := saveCustomProfileField(t, ss, name, model.CustomProfileFieldTypeSelect, "Sales", "Engineering")
+
+	_, err := ss.CustomProfileAttribute().SaveField(field)
+	var invErr *store.ErrInvalidInput
+	require.True(t, errors.As(err, &invErr))
+
+	_, err = ss.CustomProfileAttribute().SaveField(&model.CustomProfileField{Name: name, DisplayName: "Duplicate", Type: model.CustomProfileFieldTypeText})
+	var conflictErr *store.ErrConflict
+	require.True(t, errors.As(err, &conflictErr))
+
+	_, err = ss.CustomProfileAttribute().SaveField(&model.CustomProfileField{Name: "Invalid Name", DisplayName: "Invalid", Type: model.CustomProfileFieldTypeText})
+	require.Error(t, err)
+
+	fetched, err := ss.CustomProfileAttribute().GetField(field.Id)
+	require.NoError(t, err)
+	assert.Equal(t, field, fetched)
+
+	field.DisplayName = "Team"
+	field.Options = model.StringArray{"Sales", "Engineering", "Marketing"}
+	field.Visibility = model.CustomProfileFieldVisibilityAdmin
+	field.LdapAttribute = "department"
+	_, err = ss.CustomProfileAttribute().UpdateField(rctx, field)
+	require.NoError(t, err)
+
+	fetched, err = ss.CustomProfileAttribute().GetField(field.Id)
+	require.NoError(t, err)
+	assert.Equal(t, field, fetched)
+
+	fields, err := ss.CustomProfileAttribute().GetFields(false)
+	require.NoError(t, err)
+	assert.Contains(t, fields, field)
+
+	require.NoError(t, ss.CustomProfileAttribute().DeleteField(rctx, field.Id, model.GetMillis()))
+
+	err = ss.CustomProfileAttribute().DeleteField(rctx, field.Id, model.GetMillis())
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+
+	_, err = ss.CustomProfileAttribute().UpdateField(rctx, field)
+	require.True(t, errors.As(err, &nfErr))
+
+	fields, err = ss.CustomProfileAttribute().GetFields(false)
+	require.NoError(t, err)
+	for _, f := range fields {
+		assert.NotEqual(t, field.Id, f.Id)
+	}
+
+	fields, err = ss.CustomProfileAttribute().GetFields(true)
+	require.NoError(t, err)
+	var deleted *model.CustomProfileField
+	for _, f := range fields {
+		if f.Id == field.Id {
+			deleted = f
+		}
+	}
+	require.NotNil(t, deleted)
+	assert.NotZero(t, deleted.DeleteAt)
+
+	// The name of a deleted field can be reused.
+	saveCustomProfileField(t, ss, name, model.CustomProfileFieldTypeText)
+}
+
+func testCustomProfileAttributeValues(t *testing.T, rctx request.CTX, ss store.Store) {
+	location := saveCustomProfileField(t, ss, "field_"+model.NewId(), model.CustomProfileFieldTypeText)
+	skills := saveCustomProfileField(t, ss, "field_"+model.NewId(), model.CustomProfileFieldTypeMultiselect, "Go", "SQL")
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	require.NoError(t, ss.CustomProfileAttribute().SaveValues(rctx, userID, []*model.CustomProfileValue{
+		{FieldId: location.Id, Value: "Paris"},
+		{FieldId: skills.Id, Value: `["Go"]`},
+	}))
+	require.NoError(t, ss.CustomProfileAttribute().SaveValues(rctx, otherUserID, []*model.CustomProfileValue{
+		{FieldId: location.Id, Value: "Berlin"},
+	}))
+
+	values, err := ss.CustomProfileAttribute().GetValuesForUsers([]string{userID})
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+
+	require.NoError(t, ss.CustomProfileAttribute().SaveValues(rctx, userID, []*model.CustomProfileValue{
+		{FieldId: location.Id, Value: ""},
+		{FieldId: skills.Id, Value: `["Go","SQL"]`},
+	}))
+
+	values, err = ss.CustomProfileAttribute().GetValuesForUsers([]string{userID, otherUserID})
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	byUser := map[string]*model.CustomProfileValue{}
+	for _, value := range values {
+		byUser[value.UserId] = value
+	}
+	assert.Equal(t, skills.Id, byUser[userID].FieldId)
+	assert.Equal(t, `["Go","SQL"]`, byUser[userID].Value)
+	assert.Equal(t, "Berlin", byUser[otherUserID].Value)
+
+	values, err = ss.CustomProfileAttribute().GetValuesForUsers(nil)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	userIDs, err := ss.CustomProfileAttribute().GetUserIDsForField(skills.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{userID}, userIDs)
+	userIDs, err = ss.CustomProfileAttribute().GetUserIDsForField(location.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{otherUserID}, userIDs)
+
+	require.NoError(t, ss.CustomProfileAttribute().DeleteField(rctx, skills.Id, model.GetMillis()))
+	userIDs, err = ss.CustomProfileAttribute().GetUserIDsForField(skills.Id)
+	require.NoError(t, err)
+	assert.Empty(t, userIDs)
+	values, err = ss.CustomProfileAttribute().GetValuesForUsers([]string{userID})
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	require.NoError(t, ss.CustomProfileAttribute().PermanentDeleteByUser(otherUserID))
+	values, err = ss.CustomProfileAttribute().GetValuesForUsers([]string{otherUserID})
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func testCustomProfileAttributeUserSearch(t *testing.T, rctx request.CTX, ss store.Store) {
+	public := saveCustomProfileField(t, ss, "field_"+model.NewId(), model.CustomProfileFieldTypeText)
+	private, err := ss.CustomProfileAttribute().SaveField(&model.CustomProfileField{
+		Name:        "field_" + model.NewId(),
+		DisplayName: "Private",
+		Type:        model.CustomProfileFieldTypeText,
+		Visibility:  model.CustomProfileFieldVisibilityAdmin,
+	})
+	require.NoError(t, err)
+
+	u1, err := ss.User().Save(rctx, &model.User{Username: "u" + NewTestId(), Email: MakeEmail()})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u1.Id)) }()
+	u2, err := ss.User().Save(rctx, &model.User{Username: "u" + NewTestId(), Email: MakeEmail()})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u2.Id)) }()
+
+	location := "Atlantis" + model.NewId()
+	secret := "Lemuria" + model.NewId()
+	require.NoError(t, ss.CustomProfileAttribute().SaveValues(rctx, u1.Id, []*model.CustomProfileValue{{FieldId: public.Id, Value: location}}))
+	require.NoError(t, ss.CustomProfileAttribute().SaveValues(rctx, u2.Id, []*model.CustomProfileValue{{FieldId: private.Id, Value: secret}}))
+
+	options := &model.UserSearchOptions{AllowFullNames: true, Limit: model.UserSearchDefaultLimit}
+
+	users, err := ss.User().SearchWithoutTeam(location, options)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, u1.Id, users[0].Id)
+
+	users, err = ss.User().SearchWithoutTeam(u1.Username+" "+location[:8], options)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, u1.Id, users[0].Id)
+
+	users, err = ss.User().SearchWithoutTeam(secret, options)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
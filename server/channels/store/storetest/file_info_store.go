@@ -36,6 +36,8 @@ func TestFileInfoStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStor
 	t.Run("GetFilesBatchForIndexing", func(t *testing.T) { testFileInfoStoreGetFilesBatchForIndexing(t, rctx, ss) })
 	t.Run("CountAll", func(t *testing.T) { testFileInfoStoreCountAll(t, rctx, ss) })
 	t.Run("GetStorageUsage", func(t *testing.T) { testFileInfoGetStorageUsage(t, rctx, ss) })
+	t.Run("GetStorageUsageByTier", func(t *testing.T) { testFileInfoGetStorageUsageByTier(t, rctx, ss) })
+	t.Run("GetFilesBatchForColdStorage", func(t *testing.T) { testFileInfoGetFilesBatchForColdStorage(t, rctx, ss) })
 	t.Run("GetUptoNSizeFileTime", func(t *testing.T) { testGetUptoNSizeFileTime(t, rctx, ss, s) })
 }
 
@@ -868,6 +870,72 @@ func testFileInfoGetStorageUsage(t *testing.T, rctx request.CTX, ss store.Store)
 	require.Equal(t, int64(30), usage)
 }
 
+func testFileInfoGetStorageUsageByTier(t *testing.T, rctx request.CTX, ss store.Store) {
+	_, err := ss.FileInfo().PermanentDeleteBatch(rctx, model.GetMillis(), 100000)
+	require.NoError(t, err)
+
+	usage, err := ss.FileInfo().GetStorageUsageByTier(false)
+	require.NoError(t, err)
+	require.Empty(t, usage)
+
+	for i := 0; i < 3; i++ {
+		info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+			PostId:    model.NewId(),
+			CreatorId: model.NewId(),
+			Size:      10,
+			Path:      fmt.Sprintf("file%d.txt", i),
+		})
+		require.NoError(t, err)
+
+		if i == 0 {
+			err = ss.FileInfo().SetTier(rctx, info.Id, model.FileTierCold)
+			require.NoError(t, err)
+
+			info, err = ss.FileInfo().Get(info.Id)
+			require.NoError(t, err)
+			require.Equal(t, model.FileTierCold, info.Tier)
+		}
+	}
+
+	usage, err = ss.FileInfo().GetStorageUsageByTier(false)
+	require.NoError(t, err)
+	require.Equal(t, []*model.FileTierUsage{
+		{Tier: model.FileTierCold, FileCount: 1, Size: 10},
+		{Tier: model.FileTierHot, FileCount: 2, Size: 20},
+	}, usage)
+}
+
+func testFileInfoGetFilesBatchForColdStorage(t *testing.T, rctx request.CTX, ss store.Store) {
+	_, err := ss.FileInfo().PermanentDeleteBatch(rctx, model.GetMillis(), 100000)
+	require.NoError(t, err)
+
+	now := model.GetMillis()
+	var infos []*model.FileInfo
+	for i := 0; i < 4; i++ {
+		info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+			CreatorId: model.NewId(),
+			Path:      fmt.Sprintf("file%d.txt", i),
+			CreateAt:  now - int64(4-i)*1000,
+		})
+		require.NoError(t, err)
+		infos = append(infos, info)
+	}
+	err = ss.FileInfo().SetTier(rctx, infos[1].Id, model.FileTierCold)
+	require.NoError(t, err)
+
+	// Only the files older than the last one, and not in the cold tier already.
+	files, err := ss.FileInfo().GetFilesBatchForColdStorage(infos[3].CreateAt, 0, "", 10)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, infos[0].Id, files[0].Id)
+	assert.Equal(t, infos[2].Id, files[1].Id)
+
+	files, err = ss.FileInfo().GetFilesBatchForColdStorage(infos[3].CreateAt, files[0].CreateAt, files[0].Id, 10)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, infos[2].Id, files[0].Id)
+}
+
 func testGetUptoNSizeFileTime(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Skip("MM-53905")
 
@@ -31,6 +31,7 @@ func TestGroupStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("GetByRemoteID", func(t *testing.T) { testGroupStoreGetByRemoteID(t, rctx, ss) })
 	t.Run("GetAllBySource", func(t *testing.T) { testGroupStoreGetAllByType(t, rctx, ss) })
 	t.Run("GetByUser", func(t *testing.T) { testGroupStoreGetByUser(t, rctx, ss) })
+	t.Run("GetByUsers", func(t *testing.T) { testGroupStoreGetByUsers(t, rctx, ss) })
 	t.Run("Update", func(t *testing.T) { testGroupStoreUpdate(t, rctx, ss) })
 	t.Run("Delete", func(t *testing.T) { testGroupStoreDelete(t, rctx, ss) })
 	t.Run("Restore", func(t *testing.T) { testGroupStoreRestore(t, rctx, ss) })
@@ -602,6 +603,60 @@ func testGroupStoreGetByUser(t *testing.T, rctx request.CTX, ss store.Store) {
 	assert.Equal(t, 0, len(groups))
 }
 
+func testGroupStoreGetByUsers(t *testing.T, rctx request.CTX, ss store.Store) {
+	g1, err := ss.Group().Create(&model.Group{
+		Name:        model.NewPointer(model.NewId()),
+		DisplayName: model.NewId(),
+		Source:      model.GroupSourceLdap,
+		RemoteId:    model.NewPointer(model.NewId()),
+	})
+	require.NoError(t, err)
+
+	g2, err := ss.Group().Create(&model.Group{
+		Name:        model.NewPointer(model.NewId()),
+		DisplayName: model.NewId(),
+		Source:      model.GroupSourceLdap,
+		RemoteId:    model.NewPointer(model.NewId()),
+	})
+	require.NoError(t, err)
+
+	u1, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+	u2, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+	u3, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+
+	_, err = ss.Group().UpsertMember(g1.Id, u1.Id)
+	require.NoError(t, err)
+	_, err = ss.Group().UpsertMember(g2.Id, u1.Id)
+	require.NoError(t, err)
+	_, err = ss.Group().UpsertMember(g2.Id, u2.Id)
+	require.NoError(t, err)
+	_, err = ss.Group().UpsertMember(g1.Id, u3.Id)
+	require.NoError(t, err)
+	_, err = ss.Group().DeleteMember(g1.Id, u3.Id)
+	require.NoError(t, err)
+
+	groupIDs := func(groups []*model.Group) []string {
+		ids := []string{}
+		for _, group := range groups {
+			ids = append(ids, group.Id)
+		}
+		return ids
+	}
+
+	groupsByUser, err := ss.Group().GetByUsers([]string{u1.Id, u2.Id, u3.Id})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{g1.Id, g2.Id}, groupIDs(groupsByUser[u1.Id]))
+	assert.Equal(t, []string{g2.Id}, groupIDs(groupsByUser[u2.Id]))
+	assert.Empty(t, groupsByUser[u3.Id])
+
+	groupsByUser, err = ss.Group().GetByUsers([]string{})
+	require.NoError(t, err)
+	assert.Empty(t, groupsByUser)
+}
+
 func testGroupStoreUpdate(t *testing.T, rctx request.CTX, ss store.Store) {
 	// Save a new group
 	g1 := &model.Group{
@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestLoginDeviceStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGet", func(t *testing.T) { testLoginDeviceSaveGet(t, rctx, ss) })
+	t.Run("UpdateLastSeenAt", func(t *testing.T) { testLoginDeviceUpdateLastSeenAt(t, rctx, ss) })
+	t.Run("PruneForUser", func(t *testing.T) { testLoginDevicePruneForUser(t, rctx, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testLoginDevicePermanentDeleteByUser(t, rctx, ss) })
+}
+
+func saveLoginDevices(t *testing.T, ss store.Store, userID string, count int) []*model.LoginDevice {
+	devices := make([]*model.LoginDevice, 0, count)
+	for i := range count {
+		device, err := ss.LoginDevice().Save(&model.LoginDevice{
+			UserId:      userID,
+			Fingerprint: model.NewLoginDeviceFingerprint(&model.Session{DeviceId: model.NewId()}),
+			Name:        "Chrome on Linux",
+			Country:     "FR",
+			LastSeenAt:  int64(1000 + i),
+		})
+		require.NoError(t, err)
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+func testLoginDeviceSaveGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	devices := saveLoginDevices(t, ss, userID, 3)
+	saveLoginDevices(t, ss, model.NewId(), 1)
+
+	_, err := ss.LoginDevice().Save(devices[0])
+	var invErr *store.ErrInvalidInput
+	require.True(t, errors.As(err, &invErr))
+
+	_, err = ss.LoginDevice().Save(&model.LoginDevice{UserId: userID})
+	require.Error(t, err)
+
+	_, err = ss.LoginDevice().Save(&model.LoginDevice{UserId: userID, Fingerprint: devices[1].Fingerprint, Country: devices[1].Country})
+	var conflictErr *store.ErrConflict
+	require.True(t, errors.As(err, &conflictErr))
+
+	_, err = ss.LoginDevice().Save(&model.LoginDevice{UserId: userID, Fingerprint: devices[1].Fingerprint, Country: "DE", LastSeenAt: 1})
+	require.NoError(t, err)
+
+	got, err := ss.LoginDevice().GetForUser(userID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.LoginDevice{devices[2], devices[1]}, got)
+
+	got, err = ss.LoginDevice().GetForUser(model.NewId(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func testLoginDeviceUpdateLastSeenAt(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	devices := saveLoginDevices(t, ss, userID, 2)
+
+	require.NoError(t, ss.LoginDevice().UpdateLastSeenAt(devices[0].Id, 5000))
+
+	got, err := ss.LoginDevice().GetForUser(userID, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, devices[0].Id, got[0].Id)
+	assert.Equal(t, int64(5000), got[0].LastSeenAt)
+
+	err = ss.LoginDevice().UpdateLastSeenAt(model.NewId(), 5000)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+}
+
+func testLoginDevicePruneForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	devices := saveLoginDevices(t, ss, userID, 4)
+	saveLoginDevices(t, ss, otherUserID, 2)
+
+	require.NoError(t, ss.LoginDevice().PruneForUser(userID, 2))
+	got, err := ss.LoginDevice().GetForUser(userID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.LoginDevice{devices[3], devices[2]}, got)
+
+	got, err = ss.LoginDevice().GetForUser(otherUserID, 10)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func testLoginDevicePermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	saveLoginDevices(t, ss, userID, 2)
+	saveLoginDevices(t, ss, otherUserID, 1)
+
+	require.NoError(t, ss.LoginDevice().PermanentDeleteByUser(userID))
+
+	got, err := ss.LoginDevice().GetForUser(userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = ss.LoginDevice().GetForUser(otherUserID, 10)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestMfaRecoveryCodeStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("ReplaceMarkUsed", func(t *testing.T) { testMfaRecoveryCodeReplaceMarkUsed(t, rctx, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testMfaRecoveryCodePermanentDeleteByUser(t, rctx, ss) })
+}
+
+func testMfaRecoveryCodeReplaceMarkUsed(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	_, codes := model.NewMfaRecoveryCodes(userID)
+	require.NoError(t, ss.MfaRecoveryCode().Replace(userID, codes))
+
+	unused, err := ss.MfaRecoveryCode().GetUnusedForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, unused, model.MfaRecoveryCodeCount)
+
+	hashes := map[string]bool{}
+	for _, code := range codes {
+		hashes[code.CodeHash] = true
+	}
+	for _, code := range unused {
+		assert.True(t, hashes[code.CodeHash])
+		assert.Equal(t, userID, code.UserId)
+	}
+
+	t.Run("mark used", func(t *testing.T) {
+		require.NoError(t, ss.MfaRecoveryCode().MarkUsed(unused[0].Id, model.GetMillis()))
+
+		var nfErr *store.ErrNotFound
+		err := ss.MfaRecoveryCode().MarkUsed(unused[0].Id, model.GetMillis())
+		require.ErrorAs(t, err, &nfErr)
+
+		remaining, err := ss.MfaRecoveryCode().GetUnusedForUser(userID)
+		require.NoError(t, err)
+		require.Len(t, remaining, model.MfaRecoveryCodeCount-1)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		_, newCodes := model.NewMfaRecoveryCodes(userID)
+		require.NoError(t, ss.MfaRecoveryCode().Replace(userID, newCodes))
+
+		unused, err := ss.MfaRecoveryCode().GetUnusedForUser(userID)
+		require.NoError(t, err)
+		require.Len(t, unused, model.MfaRecoveryCodeCount)
+		for _, code := range unused {
+			assert.False(t, hashes[code.CodeHash])
+		}
+	})
+
+	t.Run("replace with codes of another user", func(t *testing.T) {
+		_, otherCodes := model.NewMfaRecoveryCodes(model.NewId())
+		err := ss.MfaRecoveryCode().Replace(userID, otherCodes)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+
+		unused, err := ss.MfaRecoveryCode().GetUnusedForUser(userID)
+		require.NoError(t, err)
+		require.Len(t, unused, model.MfaRecoveryCodeCount)
+	})
+}
+
+func testMfaRecoveryCodePermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	for _, id := range []string{userID, otherUserID} {
+		_, codes := model.NewMfaRecoveryCodes(id)
+		require.NoError(t, ss.MfaRecoveryCode().Replace(id, codes))
+	}
+
+	require.NoError(t, ss.MfaRecoveryCode().PermanentDeleteByUser(userID))
+
+	unused, err := ss.MfaRecoveryCode().GetUnusedForUser(userID)
+	require.NoError(t, err)
+	require.Empty(t, unused)
+
+	unused, err = ss.MfaRecoveryCode().GetUnusedForUser(otherUserID)
+	require.NoError(t, err)
+	require.Len(t, unused, model.MfaRecoveryCodeCount)
+}
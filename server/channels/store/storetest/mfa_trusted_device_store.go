@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestMfaTrustedDeviceStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGetDelete", func(t *testing.T) { testMfaTrustedDeviceSaveGetDelete(t, rctx, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testMfaTrustedDevicePermanentDeleteByUser(t, rctx, ss) })
+	t.Run("Cleanup", func(t *testing.T) { testMfaTrustedDeviceCleanup(t, rctx, ss) })
+}
+
+func newTestMfaTrustedDevice(userID string, expiresAt int64) *model.MfaTrustedDevice {
+	return &model.MfaTrustedDevice{UserId: userID, ExpiresAt: expiresAt}
+}
+
+func testMfaTrustedDeviceSaveGetDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	device, err := ss.MfaTrustedDevice().Save(newTestMfaTrustedDevice(userID, model.GetMillis()+60000))
+	require.NoError(t, err)
+	require.NotEmpty(t, device.Id)
+
+	_, err = ss.MfaTrustedDevice().Save(device)
+	var invErr *store.ErrInvalidInput
+	require.True(t, errors.As(err, &invErr))
+
+	_, err = ss.MfaTrustedDevice().Save(newTestMfaTrustedDevice(userID, 0))
+	require.Error(t, err)
+
+	got, err := ss.MfaTrustedDevice().Get(device.Id)
+	require.NoError(t, err)
+	assert.Equal(t, device, got)
+
+	require.NoError(t, ss.MfaTrustedDevice().UpdateLastUsedAt(device.Id, 1234))
+	got, err = ss.MfaTrustedDevice().Get(device.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), got.LastUsedAt)
+
+	require.NoError(t, ss.MfaTrustedDevice().Delete(device.Id))
+	_, err = ss.MfaTrustedDevice().Get(device.Id)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+
+	err = ss.MfaTrustedDevice().UpdateLastUsedAt(device.Id, 1234)
+	require.True(t, errors.As(err, &nfErr))
+}
+
+func testMfaTrustedDevicePermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+	expiresAt := model.GetMillis() + 60000
+
+	device, err := ss.MfaTrustedDevice().Save(newTestMfaTrustedDevice(userID, expiresAt))
+	require.NoError(t, err)
+	other, err := ss.MfaTrustedDevice().Save(newTestMfaTrustedDevice(otherUserID, expiresAt))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.MfaTrustedDevice().PermanentDeleteByUser(userID))
+
+	_, err = ss.MfaTrustedDevice().Get(device.Id)
+	require.Error(t, err)
+	_, err = ss.MfaTrustedDevice().Get(other.Id)
+	require.NoError(t, err)
+}
+
+func testMfaTrustedDeviceCleanup(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	now := model.GetMillis()
+
+	expired, err := ss.MfaTrustedDevice().Save(newTestMfaTrustedDevice(userID, now+1000))
+	require.NoError(t, err)
+	valid, err := ss.MfaTrustedDevice().Save(newTestMfaTrustedDevice(userID, now+60000))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.MfaTrustedDevice().Cleanup(now+2000))
+
+	_, err = ss.MfaTrustedDevice().Get(expired.Id)
+	require.Error(t, err)
+	_, err = ss.MfaTrustedDevice().Get(valid.Id)
+	require.NoError(t, err)
+}
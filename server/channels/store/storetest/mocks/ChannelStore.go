@@ -1760,6 +1760,36 @@ func (_m *ChannelStore) GetMoreChannels(teamID string, userID string, offset int
 	return r0, r1
 }
 
+// GetNewPublicChannelsForUser provides a mock function with given fields: userID, since, limit
+func (_m *ChannelStore) GetNewPublicChannelsForUser(userID string, since int64, limit int) ([]*model.ChannelWithTeamData, error) {
+	ret := _m.Called(userID, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNewPublicChannelsForUser")
+	}
+
+	var r0 []*model.ChannelWithTeamData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64, int) ([]*model.ChannelWithTeamData, error)); ok {
+		return rf(userID, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64, int) []*model.ChannelWithTeamData); ok {
+		r0 = rf(userID, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ChannelWithTeamData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64, int) error); ok {
+		r1 = rf(userID, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPinnedPostCount provides a mock function with given fields: channelID, allowFromCache
 func (_m *ChannelStore) GetPinnedPostCount(channelID string, allowFromCache bool) (int64, error) {
 	ret := _m.Called(channelID, allowFromCache)
@@ -184,6 +184,36 @@ func (_m *FileInfoStore) GetByPath(path string) (*model.FileInfo, error) {
 	return r0, r1
 }
 
+// GetFilesBatchForColdStorage provides a mock function with given fields: olderThan, startTime, startFileID, limit
+func (_m *FileInfoStore) GetFilesBatchForColdStorage(olderThan int64, startTime int64, startFileID string, limit int) ([]*model.FileInfo, error) {
+	ret := _m.Called(olderThan, startTime, startFileID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFilesBatchForColdStorage")
+	}
+
+	var r0 []*model.FileInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64, string, int) ([]*model.FileInfo, error)); ok {
+		return rf(olderThan, startTime, startFileID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, string, int) []*model.FileInfo); ok {
+		r0 = rf(olderThan, startTime, startFileID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, string, int) error); ok {
+		r1 = rf(olderThan, startTime, startFileID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFilesBatchForIndexing provides a mock function with given fields: startTime, startFileID, includeDeleted, limit
 func (_m *FileInfoStore) GetFilesBatchForIndexing(startTime int64, startFileID string, includeDeleted bool, limit int) ([]*model.FileForIndexing, error) {
 	ret := _m.Called(startTime, startFileID, includeDeleted, limit)
@@ -332,6 +362,36 @@ func (_m *FileInfoStore) GetStorageUsage(allowFromCache bool, includeDeleted boo
 	return r0, r1
 }
 
+// GetStorageUsageByTier provides a mock function with given fields: includeDeleted
+func (_m *FileInfoStore) GetStorageUsageByTier(includeDeleted bool) ([]*model.FileTierUsage, error) {
+	ret := _m.Called(includeDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStorageUsageByTier")
+	}
+
+	var r0 []*model.FileTierUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool) ([]*model.FileTierUsage, error)); ok {
+		return rf(includeDeleted)
+	}
+	if rf, ok := ret.Get(0).(func(bool) []*model.FileTierUsage); ok {
+		r0 = rf(includeDeleted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileTierUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(includeDeleted)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUptoNSizeFileTime provides a mock function with given fields: n
 func (_m *FileInfoStore) GetUptoNSizeFileTime(n int64) (int64, error) {
 	ret := _m.Called(n)
@@ -547,6 +607,24 @@ func (_m *FileInfoStore) SetContent(ctx request.CTX, fileID string, content stri
 	return r0
 }
 
+// SetTier provides a mock function with given fields: ctx, fileID, tier
+func (_m *FileInfoStore) SetTier(ctx request.CTX, fileID string, tier string) error {
+	ret := _m.Called(ctx, fileID, tier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTier")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string) error); ok {
+		r0 = rf(ctx, fileID, tier)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Upsert provides a mock function with given fields: rctx, info
 func (_m *FileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	ret := _m.Called(rctx, info)
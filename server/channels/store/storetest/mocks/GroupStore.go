@@ -722,6 +722,36 @@ func (_m *GroupStore) GetByUser(userID string) ([]*model.Group, error) {
 	return r0, r1
 }
 
+// GetByUsers provides a mock function with given fields: userIDs
+func (_m *GroupStore) GetByUsers(userIDs []string) (map[string][]*model.Group, error) {
+	ret := _m.Called(userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUsers")
+	}
+
+	var r0 map[string][]*model.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) (map[string][]*model.Group, error)); ok {
+		return rf(userIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]string) map[string][]*model.Group); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]*model.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGroupSyncable provides a mock function with given fields: groupID, syncableID, syncableType
 func (_m *GroupStore) GetGroupSyncable(groupID string, syncableID string, syncableType model.GroupSyncableType) (*model.GroupSyncable, error) {
 	ret := _m.Called(groupID, syncableID, syncableType)
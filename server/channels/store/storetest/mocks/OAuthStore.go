@@ -272,6 +272,66 @@ func (_m *OAuthStore) GetAuthorizedApps(userID string, offset int, limit int) ([
 	return r0, r1
 }
 
+// GetDeviceCode provides a mock function with given fields: deviceCode
+func (_m *OAuthStore) GetDeviceCode(deviceCode string) (*model.OAuthDeviceCode, error) {
+	ret := _m.Called(deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeviceCode")
+	}
+
+	var r0 *model.OAuthDeviceCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OAuthDeviceCode, error)); ok {
+		return rf(deviceCode)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OAuthDeviceCode); ok {
+		r0 = rf(deviceCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthDeviceCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(deviceCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeviceCodeByUserCode provides a mock function with given fields: userCode
+func (_m *OAuthStore) GetDeviceCodeByUserCode(userCode string) (*model.OAuthDeviceCode, error) {
+	ret := _m.Called(userCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeviceCodeByUserCode")
+	}
+
+	var r0 *model.OAuthDeviceCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OAuthDeviceCode, error)); ok {
+		return rf(userCode)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OAuthDeviceCode); ok {
+		r0 = rf(userCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthDeviceCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPreviousAccessData provides a mock function with given fields: userID, clientId
 func (_m *OAuthStore) GetPreviousAccessData(userID string, clientId string) (*model.AccessData, error) {
 	ret := _m.Called(userID, clientId)
@@ -410,6 +470,42 @@ func (_m *OAuthStore) RemoveAuthDataByUserId(userId string) error {
 	return r0
 }
 
+// RemoveDeviceCode provides a mock function with given fields: deviceCode
+func (_m *OAuthStore) RemoveDeviceCode(deviceCode string) error {
+	ret := _m.Called(deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDeviceCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(deviceCode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveExpiredDeviceCodes provides a mock function with given fields: expiredBefore
+func (_m *OAuthStore) RemoveExpiredDeviceCodes(expiredBefore int64) error {
+	ret := _m.Called(expiredBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveExpiredDeviceCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(expiredBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveAccessData provides a mock function with given fields: accessData
 func (_m *OAuthStore) SaveAccessData(accessData *model.AccessData) (*model.AccessData, error) {
 	ret := _m.Called(accessData)
@@ -500,6 +596,36 @@ func (_m *OAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData, e
 	return r0, r1
 }
 
+// SaveDeviceCode provides a mock function with given fields: deviceCode
+func (_m *OAuthStore) SaveDeviceCode(deviceCode *model.OAuthDeviceCode) (*model.OAuthDeviceCode, error) {
+	ret := _m.Called(deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDeviceCode")
+	}
+
+	var r0 *model.OAuthDeviceCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.OAuthDeviceCode) (*model.OAuthDeviceCode, error)); ok {
+		return rf(deviceCode)
+	}
+	if rf, ok := ret.Get(0).(func(*model.OAuthDeviceCode) *model.OAuthDeviceCode); ok {
+		r0 = rf(deviceCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthDeviceCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.OAuthDeviceCode) error); ok {
+		r1 = rf(deviceCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateAccessData provides a mock function with given fields: accessData
 func (_m *OAuthStore) UpdateAccessData(accessData *model.AccessData) (*model.AccessData, error) {
 	ret := _m.Called(accessData)
@@ -560,6 +686,34 @@ func (_m *OAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, error) {
 	return r0, r1
 }
 
+// UpdateDeviceCodeOptimistically provides a mock function with given fields: deviceCode, currentStatus
+func (_m *OAuthStore) UpdateDeviceCodeOptimistically(deviceCode *model.OAuthDeviceCode, currentStatus string) (bool, error) {
+	ret := _m.Called(deviceCode, currentStatus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeviceCodeOptimistically")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.OAuthDeviceCode, string) (bool, error)); ok {
+		return rf(deviceCode, currentStatus)
+	}
+	if rf, ok := ret.Get(0).(func(*model.OAuthDeviceCode, string) bool); ok {
+		r0 = rf(deviceCode, currentStatus)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.OAuthDeviceCode, string) error); ok {
+		r1 = rf(deviceCode, currentStatus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewOAuthStore creates a new instance of OAuthStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOAuthStore(t interface {
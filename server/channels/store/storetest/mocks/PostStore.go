@@ -946,6 +946,36 @@ func (_m *PostStore) GetSingle(rctx request.CTX, id string, inclDeleted bool) (*
 	return r0, r1
 }
 
+// GetTopReactedForUserSince provides a mock function with given fields: userID, since, limit
+func (_m *PostStore) GetTopReactedForUserSince(userID string, since int64, limit int) ([]*model.ReactedPost, error) {
+	ret := _m.Called(userID, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopReactedForUserSince")
+	}
+
+	var r0 []*model.ReactedPost
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64, int) ([]*model.ReactedPost, error)); ok {
+		return rf(userID, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64, int) []*model.ReactedPost); ok {
+		r0 = rf(userID, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ReactedPost)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64, int) error); ok {
+		r1 = rf(userID, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasAutoResponsePostByUserSince provides a mock function with given fields: options, userId
 func (_m *PostStore) HasAutoResponsePostByUserSince(options model.GetPostsSinceOptions, userId string) (bool, error) {
 	ret := _m.Called(options, userId)
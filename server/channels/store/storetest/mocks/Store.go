@@ -24,6 +24,26 @@ type Store struct {
 	mock.Mock
 }
 
+// AccessControlPolicy provides a mock function with given fields:
+func (_m *Store) AccessControlPolicy() store.AccessControlPolicyStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccessControlPolicy")
+	}
+
+	var r0 store.AccessControlPolicyStore
+	if rf, ok := ret.Get(0).(func() store.AccessControlPolicyStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.AccessControlPolicyStore)
+		}
+	}
+
+	return r0
+}
+
 // Audit provides a mock function with given fields:
 func (_m *Store) Audit() store.AuditStore {
 	ret := _m.Called()
@@ -44,6 +64,26 @@ func (_m *Store) Audit() store.AuditStore {
 	return r0
 }
 
+// AuditRecord provides a mock function with given fields:
+func (_m *Store) AuditRecord() store.AuditRecordStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuditRecord")
+	}
+
+	var r0 store.AuditRecordStore
+	if rf, ok := ret.Get(0).(func() store.AuditRecordStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.AuditRecordStore)
+		}
+	}
+
+	return r0
+}
+
 // Bot provides a mock function with given fields:
 func (_m *Store) Bot() store.BotStore {
 	ret := _m.Called()
@@ -104,6 +144,26 @@ func (_m *Store) ChannelBookmark() store.ChannelBookmarkStore {
 	return r0
 }
 
+// ChannelEmailAddress provides a mock function with given fields:
+func (_m *Store) ChannelEmailAddress() store.ChannelEmailAddressStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChannelEmailAddress")
+	}
+
+	var r0 store.ChannelEmailAddressStore
+	if rf, ok := ret.Get(0).(func() store.ChannelEmailAddressStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelEmailAddressStore)
+		}
+	}
+
+	return r0
+}
+
 // ChannelMemberHistory provides a mock function with given fields:
 func (_m *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	ret := _m.Called()
@@ -249,6 +309,26 @@ func (_m *Store) Context() context.Context {
 	return r0
 }
 
+// CustomProfileAttribute provides a mock function with given fields:
+func (_m *Store) CustomProfileAttribute() store.CustomProfileAttributeStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CustomProfileAttribute")
+	}
+
+	var r0 store.CustomProfileAttributeStore
+	if rf, ok := ret.Get(0).(func() store.CustomProfileAttributeStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.CustomProfileAttributeStore)
+		}
+	}
+
+	return r0
+}
+
 // DesktopTokens provides a mock function with given fields:
 func (_m *Store) DesktopTokens() store.DesktopTokensStore {
 	ret := _m.Called()
@@ -593,11 +673,91 @@ func (_m *Store) Logger() mlog.LoggerIFace {
 	return r0
 }
 
+// LoginDevice provides a mock function with given fields:
+func (_m *Store) LoginDevice() store.LoginDeviceStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginDevice")
+	}
+
+	var r0 store.LoginDeviceStore
+	if rf, ok := ret.Get(0).(func() store.LoginDeviceStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.LoginDeviceStore)
+		}
+	}
+
+	return r0
+}
+
 // MarkSystemRanUnitTests provides a mock function with given fields:
 func (_m *Store) MarkSystemRanUnitTests() {
 	_m.Called()
 }
 
+// MfaRecoveryCode provides a mock function with given fields:
+func (_m *Store) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MfaRecoveryCode")
+	}
+
+	var r0 store.MfaRecoveryCodeStore
+	if rf, ok := ret.Get(0).(func() store.MfaRecoveryCodeStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.MfaRecoveryCodeStore)
+		}
+	}
+
+	return r0
+}
+
+// MfaTrustedDevice provides a mock function with given fields:
+func (_m *Store) MfaTrustedDevice() store.MfaTrustedDeviceStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MfaTrustedDevice")
+	}
+
+	var r0 store.MfaTrustedDeviceStore
+	if rf, ok := ret.Get(0).(func() store.MfaTrustedDeviceStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.MfaTrustedDeviceStore)
+		}
+	}
+
+	return r0
+}
+
+// NotificationRule provides a mock function with given fields:
+func (_m *Store) NotificationRule() store.NotificationRuleStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotificationRule")
+	}
+
+	var r0 store.NotificationRuleStore
+	if rf, ok := ret.Get(0).(func() store.NotificationRuleStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.NotificationRuleStore)
+		}
+	}
+
+	return r0
+}
+
 // NotifyAdmin provides a mock function with given fields:
 func (_m *Store) NotifyAdmin() store.NotifyAdminStore {
 	ret := _m.Called()
@@ -658,6 +818,26 @@ func (_m *Store) OutgoingOAuthConnection() store.OutgoingOAuthConnectionStore {
 	return r0
 }
 
+// PasswordHistory provides a mock function with given fields:
+func (_m *Store) PasswordHistory() store.PasswordHistoryStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PasswordHistory")
+	}
+
+	var r0 store.PasswordHistoryStore
+	if rf, ok := ret.Get(0).(func() store.PasswordHistoryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PasswordHistoryStore)
+		}
+	}
+
+	return r0
+}
+
 // Plugin provides a mock function with given fields:
 func (_m *Store) Plugin() store.PluginStore {
 	ret := _m.Called()
@@ -1243,6 +1423,46 @@ func (_m *Store) UserTermsOfService() store.UserTermsOfServiceStore {
 	return r0
 }
 
+// WebAuthnCredential provides a mock function with given fields:
+func (_m *Store) WebAuthnCredential() store.WebAuthnCredentialStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebAuthnCredential")
+	}
+
+	var r0 store.WebAuthnCredentialStore
+	if rf, ok := ret.Get(0).(func() store.WebAuthnCredentialStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.WebAuthnCredentialStore)
+		}
+	}
+
+	return r0
+}
+
+// WebSocketEvent provides a mock function with given fields:
+func (_m *Store) WebSocketEvent() store.WebSocketEventStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebSocketEvent")
+	}
+
+	var r0 store.WebSocketEventStore
+	if rf, ok := ret.Get(0).(func() store.WebSocketEventStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.WebSocketEventStore)
+		}
+	}
+
+	return r0
+}
+
 // Webhook provides a mock function with given fields:
 func (_m *Store) Webhook() store.WebhookStore {
 	ret := _m.Called()
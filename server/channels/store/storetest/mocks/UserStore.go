@@ -1704,6 +1704,43 @@ func (_m *UserStore) Search(rctx request.CTX, teamID string, term string, option
 	return r0, r1
 }
 
+// SearchScim provides a mock function with given fields: filter, offset, limit
+func (_m *UserStore) SearchScim(filter *model.ScimFilter, offset int, limit int) ([]*model.User, int64, error) {
+	ret := _m.Called(filter, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchScim")
+	}
+
+	var r0 []*model.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*model.ScimFilter, int, int) ([]*model.User, int64, error)); ok {
+		return rf(filter, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(*model.ScimFilter, int, int) []*model.User); ok {
+		r0 = rf(filter, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.ScimFilter, int, int) int64); ok {
+		r1 = rf(filter, offset, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(*model.ScimFilter, int, int) error); ok {
+		r2 = rf(filter, offset, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SearchInChannel provides a mock function with given fields: channelID, term, options
 func (_m *UserStore) SearchInChannel(channelID string, term string, options *model.UserSearchOptions) ([]*model.User, error) {
 	ret := _m.Called(channelID, term, options)
@@ -2068,6 +2105,24 @@ func (_m *UserStore) UpdatePassword(userID string, newPassword string) error {
 	return r0
 }
 
+// UpdatePasswordHash provides a mock function with given fields: userID, currentHash, newHash
+func (_m *UserStore) UpdatePasswordHash(userID string, currentHash string, newHash string) error {
+	ret := _m.Called(userID, currentHash, newHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userID, currentHash, newHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUpdateAt provides a mock function with given fields: userID
 func (_m *UserStore) UpdateUpdateAt(userID string) (int64, error) {
 	ret := _m.Called(userID)
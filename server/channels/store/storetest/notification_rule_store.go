@@ -0,0 +1,150 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestNotificationRuleStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGetUpdateDelete", func(t *testing.T) { testNotificationRuleSaveGetUpdateDelete(t, rctx, ss) })
+	t.Run("GetForChannelMembers", func(t *testing.T) { testNotificationRuleGetForChannelMembers(t, rctx, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testNotificationRulePermanentDeleteByUser(t, rctx, ss) })
+}
+
+func newTestNotificationRule(userID string) *model.NotificationRule {
+	return &model.NotificationRule{
+		UserId:   userID,
+		Name:     "Deploys",
+		Action:   model.NotificationRuleActionNotify,
+		Keywords: model.StringArray{"deploy"},
+		Patterns: model.StringArray{`INC-\d+`},
+	}
+}
+
+func testNotificationRuleSaveGetUpdateDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	t.Run("save with id", func(t *testing.T) {
+		rule := newTestNotificationRule(userID)
+		rule.Id = model.NewId()
+		_, err := ss.NotificationRule().Save(rule)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("save invalid", func(t *testing.T) {
+		rule := newTestNotificationRule(userID)
+		rule.Patterns = model.StringArray{"a("}
+		_, err := ss.NotificationRule().Save(rule)
+		require.Error(t, err)
+	})
+
+	rule, err := ss.NotificationRule().Save(newTestNotificationRule(userID))
+	require.NoError(t, err)
+	require.NotEmpty(t, rule.Id)
+
+	got, err := ss.NotificationRule().Get(rule.Id)
+	require.NoError(t, err)
+	assert.Equal(t, rule, got)
+	assert.Nil(t, got.Schedule)
+
+	rule.Action = model.NotificationRuleActionMute
+	rule.BypassUrgent = true
+	rule.Keywords = model.StringArray{}
+	rule.ChannelIds = model.StringArray{model.NewId()}
+	rule.Schedule = &model.NotificationRuleSchedule{Days: []int{1, 2}, StartTime: "22:00", EndTime: "07:00"}
+	updated, err := ss.NotificationRule().Update(rule)
+	require.NoError(t, err)
+
+	got, err = ss.NotificationRule().Get(rule.Id)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+
+	second, err := ss.NotificationRule().Save(newTestNotificationRule(userID))
+	require.NoError(t, err)
+	_, err = ss.NotificationRule().Save(newTestNotificationRule(model.NewId()))
+	require.NoError(t, err)
+
+	rules, err := ss.NotificationRule().GetForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, rule.Id, rules[0].Id)
+	assert.Equal(t, second.Id, rules[1].Id)
+
+	t.Run("update another user's rule", func(t *testing.T) {
+		other := *second
+		other.UserId = model.NewId()
+		_, err := ss.NotificationRule().Update(&other)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+
+	require.NoError(t, ss.NotificationRule().Delete(rule.Id))
+	_, err = ss.NotificationRule().Get(rule.Id)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	rules, err = ss.NotificationRule().GetForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+}
+
+func testNotificationRuleGetForChannelMembers(t *testing.T, rctx request.CTX, ss store.Store) {
+	channel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      model.NewId(),
+		DisplayName: "Notification rules",
+		Name:        NewTestId(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+
+	memberID := model.NewId()
+	_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{
+		ChannelId:   channel.Id,
+		UserId:      memberID,
+		NotifyProps: model.GetDefaultChannelNotifyProps(),
+	})
+	require.NoError(t, err)
+
+	memberRule, err := ss.NotificationRule().Save(newTestNotificationRule(memberID))
+	require.NoError(t, err)
+	_, err = ss.NotificationRule().Save(newTestNotificationRule(model.NewId()))
+	require.NoError(t, err)
+
+	rules, err := ss.NotificationRule().GetForChannelMembers(channel.Id)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, memberRule.Id, rules[0].Id)
+	assert.Equal(t, memberID, rules[0].UserId)
+}
+
+func testNotificationRulePermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	for range 2 {
+		_, err := ss.NotificationRule().Save(newTestNotificationRule(userID))
+		require.NoError(t, err)
+	}
+	_, err := ss.NotificationRule().Save(newTestNotificationRule(otherUserID))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.NotificationRule().PermanentDeleteByUser(userID))
+
+	rules, err := ss.NotificationRule().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	rules, err = ss.NotificationRule().GetForUser(otherUserID)
+	require.NoError(t, err)
+	assert.Len(t, rules, 1)
+}
@@ -30,6 +30,10 @@ func TestOAuthStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("OAuthGetAuthorizedApps", func(t *testing.T) { testOAuthGetAuthorizedApps(t, rctx, ss) })
 	t.Run("OAuthGetAccessDataByUserForApp", func(t *testing.T) { testOAuthGetAccessDataByUserForApp(t, rctx, ss) })
 	t.Run("DeleteApp", func(t *testing.T) { testOAuthStoreDeleteApp(t, rctx, ss) })
+	t.Run("PublicApp", func(t *testing.T) { testOAuthStorePublicApp(t, rctx, ss) })
+	t.Run("SaveAuthDataWithCodeChallenge", func(t *testing.T) { testOAuthStoreSaveAuthDataWithCodeChallenge(t, rctx, ss) })
+	t.Run("DeviceCode", func(t *testing.T) { testOAuthStoreDeviceCode(t, rctx, ss) })
+	t.Run("RemoveExpiredDeviceCodes", func(t *testing.T) { testOAuthStoreRemoveExpiredDeviceCodes(t, rctx, ss) })
 }
 
 func testOAuthStoreSaveApp(t *testing.T, rctx request.CTX, ss store.Store) {
@@ -396,3 +400,112 @@ func testOAuthStoreDeleteApp(t *testing.T, rctx request.CTX, ss store.Store) {
 	_, err = ss.OAuth().GetAccessData(s1.Token)
 	require.Error(t, err, "should error - access data should be deleted")
 }
+
+func testOAuthStorePublicApp(t *testing.T, rctx request.CTX, ss store.Store) {
+	a1 := model.OAuthApp{}
+	a1.CreatorId = model.NewId()
+	a1.Name = "TestApp" + model.NewId()
+	a1.CallbackUrls = []string{"https://nowhere.com"}
+	a1.Homepage = "https://nowhere.com"
+	a1.IsPublic = true
+	_, err := ss.OAuth().SaveApp(&a1)
+	require.NoError(t, err)
+
+	app, err := ss.OAuth().GetApp(a1.Id)
+	require.NoError(t, err)
+	assert.True(t, app.IsPublic)
+	assert.Empty(t, app.ClientSecret)
+
+	app.IsPublic = false
+	app.ClientSecret = model.NewId()
+	app.BotUserId = model.NewId()
+	_, err = ss.OAuth().UpdateApp(app)
+	require.NoError(t, err)
+
+	app, err = ss.OAuth().GetApp(a1.Id)
+	require.NoError(t, err)
+	assert.False(t, app.IsPublic)
+	assert.Equal(t, a1.Id, app.Id)
+	assert.NotEmpty(t, app.BotUserId)
+}
+
+func testOAuthStoreSaveAuthDataWithCodeChallenge(t *testing.T, rctx request.CTX, ss store.Store) {
+	a1 := model.AuthData{}
+	a1.ClientId = model.NewId()
+	a1.UserId = model.NewId()
+	a1.Code = model.NewId()
+	a1.RedirectUri = "http://example.com"
+	a1.CodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	a1.CodeChallengeMethod = model.PKCEMethodS256
+	_, err := ss.OAuth().SaveAuthData(&a1)
+	require.NoError(t, err)
+
+	authData, err := ss.OAuth().GetAuthData(a1.Code)
+	require.NoError(t, err)
+	assert.Equal(t, a1.CodeChallenge, authData.CodeChallenge)
+	assert.Equal(t, a1.CodeChallengeMethod, authData.CodeChallengeMethod)
+}
+
+func testOAuthStoreDeviceCode(t *testing.T, rctx request.CTX, ss store.Store) {
+	dc := &model.OAuthDeviceCode{ClientId: model.NewId()}
+	_, err := ss.OAuth().SaveDeviceCode(dc)
+	require.NoError(t, err)
+	defer ss.OAuth().RemoveDeviceCode(dc.DeviceCode)
+
+	_, err = ss.OAuth().SaveDeviceCode(&model.OAuthDeviceCode{ClientId: model.NewId(), UserCode: dc.UserCode})
+	require.Error(t, err, "Should have failed, the user code is already used")
+
+	dc2, err := ss.OAuth().GetDeviceCode(dc.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, dc, dc2)
+
+	dc2, err = ss.OAuth().GetDeviceCodeByUserCode(dc.UserCode)
+	require.NoError(t, err)
+	assert.Equal(t, dc, dc2)
+
+	_, err = ss.OAuth().GetDeviceCodeByUserCode(model.NewOAuthUserCode())
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	dc2.UserId = model.NewId()
+	dc2.Status = model.OAuthDeviceCodeStatusApproved
+	updated, err := ss.OAuth().UpdateDeviceCodeOptimistically(dc2, model.OAuthDeviceCodeStatusDenied)
+	require.NoError(t, err)
+	assert.False(t, updated, "Should not have updated a device code with another status")
+
+	updated, err = ss.OAuth().UpdateDeviceCodeOptimistically(dc2, model.OAuthDeviceCodeStatusPending)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = ss.OAuth().UpdateDeviceCodeOptimistically(dc2, model.OAuthDeviceCodeStatusPending)
+	require.NoError(t, err)
+	assert.False(t, updated, "Should not have updated a device code twice")
+
+	dc3, err := ss.OAuth().GetDeviceCode(dc.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, dc2, dc3)
+
+	require.NoError(t, ss.OAuth().RemoveDeviceCode(dc.DeviceCode))
+	_, err = ss.OAuth().GetDeviceCode(dc.DeviceCode)
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testOAuthStoreRemoveExpiredDeviceCodes(t *testing.T, rctx request.CTX, ss store.Store) {
+	expired := &model.OAuthDeviceCode{ClientId: model.NewId(), CreateAt: 1000, ExpiresAt: 2000}
+	_, err := ss.OAuth().SaveDeviceCode(expired)
+	require.NoError(t, err)
+
+	active := &model.OAuthDeviceCode{ClientId: model.NewId()}
+	_, err = ss.OAuth().SaveDeviceCode(active)
+	require.NoError(t, err)
+	defer ss.OAuth().RemoveDeviceCode(active.DeviceCode)
+
+	require.NoError(t, ss.OAuth().RemoveExpiredDeviceCodes(model.GetMillis()))
+
+	var nfErr *store.ErrNotFound
+	_, err = ss.OAuth().GetDeviceCode(expired.DeviceCode)
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = ss.OAuth().GetDeviceCode(active.DeviceCode)
+	require.NoError(t, err)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestPasswordHistoryStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGet", func(t *testing.T) { testPasswordHistorySaveGet(t, rctx, ss) })
+	t.Run("PruneForUser", func(t *testing.T) { testPasswordHistoryPruneForUser(t, rctx, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testPasswordHistoryPermanentDeleteByUser(t, rctx, ss) })
+}
+
+func savePasswordHistory(t *testing.T, ss store.Store, userID string, count int) []*model.PasswordHistoryEntry {
+	entries := make([]*model.PasswordHistoryEntry, 0, count)
+	for i := range count {
+		entry, err := ss.PasswordHistory().Save(&model.PasswordHistoryEntry{
+			UserId:       userID,
+			PasswordHash: model.NewId(),
+			CreateAt:     int64(1000 + i),
+		})
+		require.NoError(t, err)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func testPasswordHistorySaveGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	entries := savePasswordHistory(t, ss, userID, 3)
+	savePasswordHistory(t, ss, model.NewId(), 1)
+
+	_, err := ss.PasswordHistory().Save(entries[0])
+	var invErr *store.ErrInvalidInput
+	require.True(t, errors.As(err, &invErr))
+
+	_, err = ss.PasswordHistory().Save(&model.PasswordHistoryEntry{UserId: userID})
+	require.Error(t, err)
+
+	got, err := ss.PasswordHistory().GetForUser(userID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.PasswordHistoryEntry{entries[2], entries[1]}, got)
+
+	got, err = ss.PasswordHistory().GetForUser(model.NewId(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func testPasswordHistoryPruneForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	entries := savePasswordHistory(t, ss, userID, 4)
+	savePasswordHistory(t, ss, otherUserID, 2)
+
+	require.NoError(t, ss.PasswordHistory().PruneForUser(userID, 2))
+	got, err := ss.PasswordHistory().GetForUser(userID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.PasswordHistoryEntry{entries[3], entries[2]}, got)
+
+	require.NoError(t, ss.PasswordHistory().PruneForUser(userID, 0))
+	got, err = ss.PasswordHistory().GetForUser(userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = ss.PasswordHistory().GetForUser(otherUserID, 10)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func testPasswordHistoryPermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	savePasswordHistory(t, ss, userID, 2)
+	savePasswordHistory(t, ss, otherUserID, 1)
+
+	require.NoError(t, ss.PasswordHistory().PermanentDeleteByUser(userID))
+
+	got, err := ss.PasswordHistory().GetForUser(userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = ss.PasswordHistory().GetForUser(otherUserID, 10)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
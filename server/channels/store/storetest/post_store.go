@@ -47,6 +47,7 @@ func TestPostStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("Overwrite", func(t *testing.T) { testPostStoreOverwrite(t, rctx, ss) })
 	t.Run("OverwriteMultiple", func(t *testing.T) { testPostStoreOverwriteMultiple(t, rctx, ss) })
 	t.Run("GetPostsByIds", func(t *testing.T) { testPostStoreGetPostsByIds(t, rctx, ss) })
+	t.Run("GetTopReactedForUserSince", func(t *testing.T) { testPostStoreGetTopReactedForUserSince(t, rctx, ss) })
 	t.Run("GetPostsBatchForIndexing", func(t *testing.T) { testPostStoreGetPostsBatchForIndexing(t, rctx, ss) })
 	t.Run("PermanentDeleteBatch", func(t *testing.T) { testPostStorePermanentDeleteBatch(t, rctx, ss) })
 	t.Run("GetOldest", func(t *testing.T) { testPostStoreGetOldest(t, rctx, ss) })
@@ -3836,6 +3837,76 @@ func testPostStoreOverwrite(t *testing.T, rctx request.CTX, ss store.Store) {
 	})
 }
 
+func testPostStoreGetTopReactedForUserSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	channel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      model.NewId(),
+		DisplayName: "DisplayName",
+		Name:        "channel" + model.NewId(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+	_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{ChannelId: channel.Id, UserId: userID, NotifyProps: model.GetDefaultChannelNotifyProps()})
+	require.NoError(t, err)
+
+	otherChannel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      model.NewId(),
+		DisplayName: "DisplayName",
+		Name:        "channel" + model.NewId(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+
+	savePost := func(channelID string) *model.Post {
+		post, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: model.NewId(), Message: NewTestId()})
+		require.NoError(t, err)
+		return post
+	}
+	react := func(post *model.Post, count int) {
+		for i := 0; i < count; i++ {
+			_, err := ss.Reaction().Save(&model.Reaction{UserId: model.NewId(), PostId: post.Id, ChannelId: post.ChannelId, EmojiName: "smile"})
+			require.NoError(t, err)
+		}
+	}
+
+	since := model.GetMillis() - 1
+	post1 := savePost(channel.Id)
+	react(post1, 1)
+	post2 := savePost(channel.Id)
+	react(post2, 3)
+	post3 := savePost(channel.Id)
+	react(post3, 2)
+	react(savePost(otherChannel.Id), 5)
+	savePost(channel.Id)
+
+	t.Run("orders by reaction count", func(t *testing.T) {
+		reacted, err := ss.Post().GetTopReactedForUserSince(userID, since, 2)
+		require.NoError(t, err)
+		require.Len(t, reacted, 2)
+		assert.Equal(t, post2.Id, reacted[0].Post.Id)
+		assert.Equal(t, int64(3), reacted[0].ReactionCount)
+		assert.Equal(t, post3.Id, reacted[1].Post.Id)
+		assert.Equal(t, int64(2), reacted[1].ReactionCount)
+	})
+
+	t.Run("ignores older reactions", func(t *testing.T) {
+		reacted, err := ss.Post().GetTopReactedForUserSince(userID, model.GetMillis()+1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, reacted)
+	})
+
+	t.Run("ignores deleted posts", func(t *testing.T) {
+		err := ss.Post().Delete(rctx, post2.Id, model.GetMillis(), userID)
+		require.NoError(t, err)
+
+		reacted, err := ss.Post().GetTopReactedForUserSince(userID, since, 10)
+		require.NoError(t, err)
+		require.Len(t, reacted, 2)
+		assert.Equal(t, post3.Id, reacted[0].Post.Id)
+		assert.Equal(t, post1.Id, reacted[1].Post.Id)
+	})
+}
+
 func testPostStoreGetPostsByIds(t *testing.T, rctx request.CTX, ss store.Store) {
 	teamId := model.NewId()
 	channel1, err := ss.Channel().Save(rctx, &model.Channel{
@@ -65,6 +65,17 @@ type Store struct {
 	PostPersistentNotificationStore mocks.PostPersistentNotificationStore
 	DesktopTokensStore              mocks.DesktopTokensStore
 	ChannelBookmarkStore            mocks.ChannelBookmarkStore
+	WebSocketEventStore             mocks.WebSocketEventStore
+	NotificationRuleStore           mocks.NotificationRuleStore
+	ChannelEmailAddressStore        mocks.ChannelEmailAddressStore
+	AuditRecordStore                mocks.AuditRecordStore
+	WebAuthnCredentialStore         mocks.WebAuthnCredentialStore
+	MfaRecoveryCodeStore            mocks.MfaRecoveryCodeStore
+	MfaTrustedDeviceStore           mocks.MfaTrustedDeviceStore
+	PasswordHistoryStore            mocks.PasswordHistoryStore
+	LoginDeviceStore                mocks.LoginDeviceStore
+	AccessControlPolicyStore        mocks.AccessControlPolicyStore
+	CustomProfileAttributeStore     mocks.CustomProfileAttributeStore
 }
 
 func (s *Store) SetContext(context context.Context)            { s.context = context }
@@ -112,6 +123,7 @@ func (s *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 }
 func (s *Store) ChannelBookmark() store.ChannelBookmarkStore { return &s.ChannelBookmarkStore }
 func (s *Store) DesktopTokens() store.DesktopTokensStore     { return &s.DesktopTokensStore }
+func (s *Store) WebSocketEvent() store.WebSocketEventStore   { return &s.WebSocketEventStore }
 func (s *Store) NotifyAdmin() store.NotifyAdminStore         { return &s.NotifyAdminStore }
 func (s *Store) Group() store.GroupStore                     { return &s.GroupStore }
 func (s *Store) LinkMetadata() store.LinkMetadataStore       { return &s.LinkMetadataStore }
@@ -123,6 +135,36 @@ func (s *Store) PostAcknowledgement() store.PostAcknowledgementStore {
 func (s *Store) PostPersistentNotification() store.PostPersistentNotificationStore {
 	return &s.PostPersistentNotificationStore
 }
+func (s *Store) NotificationRule() store.NotificationRuleStore {
+	return &s.NotificationRuleStore
+}
+func (s *Store) ChannelEmailAddress() store.ChannelEmailAddressStore {
+	return &s.ChannelEmailAddressStore
+}
+func (s *Store) AuditRecord() store.AuditRecordStore {
+	return &s.AuditRecordStore
+}
+func (s *Store) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return &s.WebAuthnCredentialStore
+}
+func (s *Store) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	return &s.MfaRecoveryCodeStore
+}
+func (s *Store) MfaTrustedDevice() store.MfaTrustedDeviceStore {
+	return &s.MfaTrustedDeviceStore
+}
+func (s *Store) PasswordHistory() store.PasswordHistoryStore {
+	return &s.PasswordHistoryStore
+}
+func (s *Store) LoginDevice() store.LoginDeviceStore {
+	return &s.LoginDeviceStore
+}
+func (s *Store) AccessControlPolicy() store.AccessControlPolicyStore {
+	return &s.AccessControlPolicyStore
+}
+func (s *Store) CustomProfileAttribute() store.CustomProfileAttributeStore {
+	return &s.CustomProfileAttributeStore
+}
 func (s *Store) MarkSystemRanUnitTests()             { /* do nothing */ }
 func (s *Store) Close()                              { /* do nothing */ }
 func (s *Store) LockToMaster()                       { /* do nothing */ }
@@ -188,5 +230,16 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.PostPersistentNotificationStore,
 		&s.DesktopTokensStore,
 		&s.ChannelBookmarkStore,
+		&s.WebSocketEventStore,
+		&s.NotificationRuleStore,
+		&s.ChannelEmailAddressStore,
+		&s.AuditRecordStore,
+		&s.WebAuthnCredentialStore,
+		&s.MfaRecoveryCodeStore,
+		&s.MfaTrustedDeviceStore,
+		&s.PasswordHistoryStore,
+		&s.LoginDeviceStore,
+		&s.AccessControlPolicyStore,
+		&s.CustomProfileAttributeStore,
 	)
 }
@@ -6,6 +6,7 @@ package storetest
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -17,6 +18,7 @@ func TestUserAccessTokenStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("UserAccessTokenSaveGetDelete", func(t *testing.T) { testUserAccessTokenSaveGetDelete(t, rctx, ss) })
 	t.Run("UserAccessTokenDisableEnable", func(t *testing.T) { testUserAccessTokenDisableEnable(t, rctx, ss) })
 	t.Run("UserAccessTokenSearch", func(t *testing.T) { testUserAccessTokenSearch(t, rctx, ss) })
+	t.Run("UserAccessTokenScope", func(t *testing.T) { testUserAccessTokenScope(t, rctx, ss) })
 }
 
 func testUserAccessTokenSaveGetDelete(t *testing.T, rctx request.CTX, ss store.Store) {
@@ -155,3 +157,39 @@ func testUserAccessTokenSearch(t *testing.T, rctx request.CTX, ss store.Store) {
 	require.NoError(t, nErr)
 	require.Equal(t, 1, len(received), "received incorrect number of tokens after search")
 }
+
+func testUserAccessTokenScope(t *testing.T, rctx request.CTX, ss store.Store) {
+	uat := &model.UserAccessToken{
+		Token:       model.NewId(),
+		UserId:      model.NewId(),
+		Description: "scopedtoken",
+		Scope: &model.UserAccessTokenScope{
+			Permissions: []string{model.PermissionCreatePost.Id},
+			ChannelIds:  []string{model.NewId(), model.NewId()},
+			AllowedIPs:  []string{"10.0.0.0/8"},
+		},
+		ExpiresAt: model.GetMillis() + 60000,
+	}
+	uat, err := ss.UserAccessToken().Save(uat)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.UserAccessToken().Delete(uat.Id)) }()
+
+	got, err := ss.UserAccessToken().GetByToken(uat.Token)
+	require.NoError(t, err)
+	assert.Equal(t, uat.Scope, got.Scope)
+	assert.Equal(t, uat.ExpiresAt, got.ExpiresAt)
+
+	unscoped := &model.UserAccessToken{
+		Token:       model.NewId(),
+		UserId:      uat.UserId,
+		Description: "unscopedtoken",
+	}
+	unscoped, err = ss.UserAccessToken().Save(unscoped)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.UserAccessToken().Delete(unscoped.Id)) }()
+
+	got, err = ss.UserAccessToken().Get(unscoped.Id)
+	require.NoError(t, err)
+	assert.Nil(t, got.Scope)
+	assert.Zero(t, got.ExpiresAt)
+}
@@ -72,6 +72,7 @@ func TestUserStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("GetByUsername", func(t *testing.T) { testUserStoreGetByUsername(t, rctx, ss) })
 	t.Run("GetForLogin", func(t *testing.T) { testUserStoreGetForLogin(t, rctx, ss) })
 	t.Run("UpdatePassword", func(t *testing.T) { testUserStoreUpdatePassword(t, rctx, ss) })
+	t.Run("UpdatePasswordHash", func(t *testing.T) { testUserStoreUpdatePasswordHash(t, rctx, ss) })
 	t.Run("Delete", func(t *testing.T) { testUserStoreDelete(t, rctx, ss) })
 	t.Run("UpdateAuthData", func(t *testing.T) { testUserStoreUpdateAuthData(t, rctx, ss) })
 	t.Run("ResetAuthDataToEmailForUsers", func(t *testing.T) { testUserStoreResetAuthDataToEmailForUsers(t, rctx, ss) })
@@ -101,6 +102,7 @@ func TestUserStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("GetUsersWithInvalidEmails", func(t *testing.T) { testGetUsersWithInvalidEmails(t, rctx, ss) })
 	t.Run("UpdateLastLogin", func(t *testing.T) { testUpdateLastLogin(t, rctx, ss) })
 	t.Run("GetUserReport", func(t *testing.T) { testGetUserReport(t, rctx, ss, s) })
+	t.Run("SearchScim", func(t *testing.T) { testUserStoreSearchScim(t, rctx, ss) })
 }
 
 func testUserStoreSave(t *testing.T, rctx request.CTX, ss store.Store) {
@@ -2310,6 +2312,30 @@ func testUserStoreUpdatePassword(t *testing.T, rctx request.CTX, ss store.Store)
 	require.Equal(t, user.Password, hashedPassword, "Password was not updated correctly")
 }
 
+func testUserStoreUpdatePasswordHash(t *testing.T, rctx request.CTX, ss store.Store) {
+	u1 := &model.User{}
+	u1.Email = MakeEmail()
+	u1.Password = "password"
+	u1, err := ss.User().Save(rctx, u1)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u1.Id)) }()
+
+	newHash, err := model.HashPasswordWithSettings("password", &model.PasswordSettings{HashAlgorithm: model.NewPointer(model.PasswordHashAlgorithmScrypt)})
+	require.NoError(t, err)
+
+	err = ss.User().UpdatePasswordHash(u1.Id, "stale", newHash)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	err = ss.User().UpdatePasswordHash(u1.Id, u1.Password, newHash)
+	require.NoError(t, err)
+
+	user, err := ss.User().GetByEmail(u1.Email)
+	require.NoError(t, err)
+	require.Equal(t, newHash, user.Password)
+	require.Equal(t, u1.LastPasswordUpdate, user.LastPasswordUpdate)
+}
+
 func testUserStoreDelete(t *testing.T, rctx request.CTX, ss store.Store) {
 	u1 := &model.User{}
 	u1.Email = MakeEmail()
@@ -6219,6 +6245,87 @@ func testUpdateLastLogin(t *testing.T, rctx request.CTX, ss store.Store) {
 	require.Equal(t, int64(1234567890), user.LastLogin)
 }
 
+func testUserStoreSearchScim(t *testing.T, rctx request.CTX, ss store.Store) {
+	lastName := "scim" + model.NewId()
+
+	u1, err := ss.User().Save(rctx, &model.User{
+		Email:     MakeEmail(),
+		Username:  "u1" + model.NewId(),
+		FirstName: "Barbara",
+		LastName:  lastName,
+		Props:     model.StringMap{model.UserPropsKeyScimExternalId: "bjensen", model.UserPropsKeyScimUserName: "BJensen@example.com"},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u1.Id)) }()
+
+	u2, err := ss.User().Save(rctx, &model.User{
+		Email:     MakeEmail(),
+		Username:  "u2" + model.NewId(),
+		FirstName: "Babs",
+		LastName:  lastName,
+		DeleteAt:  model.GetMillis(),
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u2.Id)) }()
+
+	bot, err := ss.User().Save(rctx, &model.User{
+		Email:    MakeEmail(),
+		Username: "bot" + model.NewId(),
+		LastName: lastName,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, bot.Id)) }()
+	_, err = ss.Bot().Save(&model.Bot{UserId: bot.Id, Username: bot.Username, OwnerId: u1.Id})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.Bot().PermanentDelete(bot.Id)) }()
+
+	search := func(t *testing.T, filter string, offset, limit int) ([]string, int64) {
+		t.Helper()
+		parsed, appErr := model.ParseScimFilter(`name.familyName eq "` + lastName + `" and (` + filter + `)`)
+		require.Nil(t, appErr)
+		users, total, err := ss.User().SearchScim(parsed, offset, limit)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, user := range users {
+			ids = append(ids, user.Id)
+		}
+		return ids, total
+	}
+
+	for filter, expected := range map[string][]string{
+		`name.givenName sw "ba"`:                               {u1.Id, u2.Id},
+		`userName eq "bjensen@example.com"`:                    {u1.Id},
+		`externalId eq "bjensen"`:                              {u1.Id},
+		`active eq false`:                                      {u2.Id},
+		`not (active eq false)`:                                {u1.Id},
+		`name.givenName co "bs" or externalId pr`:              {u1.Id, u2.Id},
+		`emails[type eq "work" and value co "@"]`:              {u1.Id, u2.Id},
+		`name.givenName eq "john"`:                             {},
+		`name.givenName co "%"`:                                {},
+		`userName eq "` + u2.Username + `" and active eq true`: {},
+	} {
+		t.Run(filter, func(t *testing.T) {
+			ids, total := search(t, filter, 0, 10)
+			assert.ElementsMatch(t, expected, ids)
+			assert.EqualValues(t, len(expected), total)
+		})
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		ids, total := search(t, `name.givenName sw "ba"`, 1, 10)
+		assert.Equal(t, []string{u2.Id}, ids)
+		assert.EqualValues(t, 2, total)
+	})
+
+	t.Run("unsupported attribute", func(t *testing.T) {
+		parsed, appErr := model.ParseScimFilter(`displayName eq "Barbara"`)
+		require.Nil(t, appErr)
+		_, _, err := ss.User().SearchScim(parsed, 0, 10)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+}
+
 func testGetUserReport(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	numRegularUsers := 90
 	numSysAdmins := 10
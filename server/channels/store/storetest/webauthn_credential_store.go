@@ -0,0 +1,157 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestWebAuthnCredentialStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveGetDelete", func(t *testing.T) { testWebAuthnCredentialSaveGetDelete(t, rctx, ss) })
+	t.Run("UpdateSignCount", func(t *testing.T) { testWebAuthnCredentialUpdateSignCount(t, rctx, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testWebAuthnCredentialPermanentDeleteByUser(t, rctx, ss) })
+}
+
+func newTestWebAuthnCredential(userID string) *model.WebAuthnCredential {
+	return &model.WebAuthnCredential{
+		UserId:       userID,
+		Name:         "Security key",
+		CredentialId: base64.RawURLEncoding.EncodeToString([]byte(model.NewId())),
+		PublicKey:    []byte{0xa5, 0x01, 0x02, 0x03, 0x26},
+		AAGUID:       "00000000000000000000000000000000",
+	}
+}
+
+func testWebAuthnCredentialSaveGetDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	t.Run("save with id", func(t *testing.T) {
+		credential := newTestWebAuthnCredential(userID)
+		credential.Id = model.NewId()
+		_, err := ss.WebAuthnCredential().Save(credential)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("save invalid", func(t *testing.T) {
+		credential := newTestWebAuthnCredential(userID)
+		credential.PublicKey = nil
+		_, err := ss.WebAuthnCredential().Save(credential)
+		require.Error(t, err)
+	})
+
+	credential, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(userID))
+	require.NoError(t, err)
+	require.NotEmpty(t, credential.Id)
+
+	t.Run("save duplicate credential id", func(t *testing.T) {
+		duplicate := newTestWebAuthnCredential(model.NewId())
+		duplicate.CredentialId = credential.CredentialId
+		_, err := ss.WebAuthnCredential().Save(duplicate)
+		var conflictErr *store.ErrConflict
+		require.ErrorAs(t, err, &conflictErr)
+	})
+
+	got, err := ss.WebAuthnCredential().Get(credential.Id)
+	require.NoError(t, err)
+	assert.Equal(t, credential, got)
+
+	got, err = ss.WebAuthnCredential().GetByCredentialId(credential.CredentialId)
+	require.NoError(t, err)
+	assert.Equal(t, credential, got)
+
+	other, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(userID))
+	require.NoError(t, err)
+
+	credentials, err := ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, credentials, 2)
+
+	credentials, err = ss.WebAuthnCredential().GetForUser(model.NewId())
+	require.NoError(t, err)
+	require.Empty(t, credentials)
+
+	require.NoError(t, ss.WebAuthnCredential().Delete(credential.Id))
+
+	_, err = ss.WebAuthnCredential().Get(credential.Id)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = ss.WebAuthnCredential().GetByCredentialId(credential.CredentialId)
+	require.ErrorAs(t, err, &nfErr)
+
+	credentials, err = ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	assert.Equal(t, other.Id, credentials[0].Id)
+}
+
+func testWebAuthnCredentialUpdateSignCount(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Run("counter", func(t *testing.T) {
+		credential, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(model.NewId()))
+		require.NoError(t, err)
+
+		require.NoError(t, ss.WebAuthnCredential().UpdateSignCount(credential.Id, 5, 1000))
+
+		got, err := ss.WebAuthnCredential().Get(credential.Id)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), got.SignCount)
+		assert.Equal(t, int64(1000), got.LastUsedAt)
+
+		var nfErr *store.ErrNotFound
+		err = ss.WebAuthnCredential().UpdateSignCount(credential.Id, 5, 2000)
+		require.ErrorAs(t, err, &nfErr)
+		err = ss.WebAuthnCredential().UpdateSignCount(credential.Id, 0, 2000)
+		require.ErrorAs(t, err, &nfErr)
+
+		require.NoError(t, ss.WebAuthnCredential().UpdateSignCount(credential.Id, 6, 3000))
+	})
+
+	t.Run("no counter", func(t *testing.T) {
+		credential, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(model.NewId()))
+		require.NoError(t, err)
+
+		require.NoError(t, ss.WebAuthnCredential().UpdateSignCount(credential.Id, 0, 1000))
+		require.NoError(t, ss.WebAuthnCredential().UpdateSignCount(credential.Id, 0, 2000))
+
+		got, err := ss.WebAuthnCredential().Get(credential.Id)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), got.SignCount)
+		assert.Equal(t, int64(2000), got.LastUsedAt)
+	})
+
+	t.Run("unknown credential", func(t *testing.T) {
+		err := ss.WebAuthnCredential().UpdateSignCount(model.NewId(), 1, 1000)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testWebAuthnCredentialPermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	for _, id := range []string{userID, userID, otherUserID} {
+		_, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(id))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, ss.WebAuthnCredential().PermanentDeleteByUser(userID))
+
+	credentials, err := ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	require.Empty(t, credentials)
+
+	credentials, err = ss.WebAuthnCredential().GetForUser(otherUserID)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestWebSocketEventStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveAndGetSince", func(t *testing.T) { testWebSocketEventSaveAndGetSince(t, rctx, ss) })
+	t.Run("SaveMultiple", func(t *testing.T) { testWebSocketEventSaveMultiple(t, rctx, ss) })
+	t.Run("GetSinceOverlap", func(t *testing.T) { testWebSocketEventGetSinceOverlap(t, rctx, ss) })
+	t.Run("DeleteOlderThan", func(t *testing.T) { testWebSocketEventDeleteOlderThan(t, rctx, ss) })
+}
+
+func saveWebSocketEvent(t *testing.T, ss store.Store, createAt int64, userID string) *model.WebSocketEventLogEntry {
+	t.Helper()
+	entry, err := ss.WebSocketEvent().Save(&model.WebSocketEventLogEntry{
+		CreateAt: createAt,
+		UserId:   userID,
+		Event:    []byte(`{"event":"posted"}`),
+	})
+	require.NoError(t, err)
+	return entry
+}
+
+func testWebSocketEventSaveAndGetSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+
+	first := saveWebSocketEvent(t, ss, model.GetMillis(), "")
+	second := saveWebSocketEvent(t, ss, model.GetMillis(), userID)
+	saveWebSocketEvent(t, ss, model.GetMillis(), otherUserID)
+	fourth := saveWebSocketEvent(t, ss, model.GetMillis(), "")
+	require.Greater(t, second.Seq, first.Seq)
+	require.Greater(t, fourth.Seq, second.Seq)
+
+	entries, err := ss.WebSocketEvent().GetSince(userID, first.Seq-1, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, first.Seq, entries[0].Seq)
+	assert.Equal(t, second.Seq, entries[1].Seq)
+	assert.Equal(t, userID, entries[1].UserId)
+	assert.Equal(t, fourth.Seq, entries[2].Seq)
+	assert.JSONEq(t, `{"event":"posted"}`, string(entries[2].Event))
+
+	entries, err = ss.WebSocketEvent().GetSince(userID, first.Seq, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, second.Seq, entries[0].Seq)
+
+	oldest, latest, err := ss.WebSocketEvent().GetSeqRange()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, oldest, first.Seq)
+	assert.Equal(t, fourth.Seq, latest)
+}
+
+func testWebSocketEventSaveMultiple(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	saved, err := ss.WebSocketEvent().SaveMultiple([]*model.WebSocketEventLogEntry{
+		{CreateAt: model.GetMillis(), UserId: userID, Event: []byte(`{"event":"first"}`)},
+		{CreateAt: model.GetMillis(), UserId: userID, Event: []byte(`{"event":"second"}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, saved[0].Seq+1, saved[1].Seq)
+
+	entries, err := ss.WebSocketEvent().GetSince(userID, saved[0].Seq-1, 0, 10)
+	require.NoError(t, err)
+	var events []string
+	for _, entry := range entries {
+		if entry.UserId == userID {
+			events = append(events, string(entry.Event))
+		}
+	}
+	assert.Equal(t, []string{`{"event":"first"}`, `{"event":"second"}`}, events)
+}
+
+func testWebSocketEventGetSinceOverlap(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	now := model.GetMillis()
+	recent := saveWebSocketEvent(t, ss, now-1000, userID)
+	last := saveWebSocketEvent(t, ss, now, userID)
+	next := saveWebSocketEvent(t, ss, now, userID)
+
+	userSeqs := func(entries []*model.WebSocketEventLogEntry) []int64 {
+		var seqs []int64
+		for _, entry := range entries {
+			if entry.UserId == userID {
+				seqs = append(seqs, entry.Seq)
+			}
+		}
+		return seqs
+	}
+
+	entries, err := ss.WebSocketEvent().GetSince(userID, last.Seq, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{next.Seq}, userSeqs(entries))
+
+	entries, err = ss.WebSocketEvent().GetSince(userID, last.Seq, 5000, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{recent.Seq, last.Seq, next.Seq}, userSeqs(entries))
+}
+
+func testWebSocketEventDeleteOlderThan(t *testing.T, rctx request.CTX, ss store.Store) {
+	_, err := ss.WebSocketEvent().DeleteOlderThan(model.GetMillis()+1, 10000)
+	require.NoError(t, err)
+
+	old := saveWebSocketEvent(t, ss, 1000, "")
+	recent := saveWebSocketEvent(t, ss, 2000, "")
+
+	// The latest event is kept even when expired.
+	deleted, err := ss.WebSocketEvent().DeleteOlderThan(3000, 100)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	oldest, latest, err := ss.WebSocketEvent().GetSeqRange()
+	require.NoError(t, err)
+	assert.Greater(t, oldest, old.Seq)
+	assert.Equal(t, recent.Seq, oldest)
+	assert.Equal(t, recent.Seq, latest)
+}
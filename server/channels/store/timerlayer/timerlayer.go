@@ -19,15 +19,19 @@ import (
 type TimerLayer struct {
 	store.Store
 	Metrics                         einterfaces.MetricsInterface
+	AccessControlPolicyStore        store.AccessControlPolicyStore
 	AuditStore                      store.AuditStore
+	AuditRecordStore                store.AuditRecordStore
 	BotStore                        store.BotStore
 	ChannelStore                    store.ChannelStore
 	ChannelBookmarkStore            store.ChannelBookmarkStore
+	ChannelEmailAddressStore        store.ChannelEmailAddressStore
 	ChannelMemberHistoryStore       store.ChannelMemberHistoryStore
 	ClusterDiscoveryStore           store.ClusterDiscoveryStore
 	CommandStore                    store.CommandStore
 	CommandWebhookStore             store.CommandWebhookStore
 	ComplianceStore                 store.ComplianceStore
+	CustomProfileAttributeStore     store.CustomProfileAttributeStore
 	DesktopTokensStore              store.DesktopTokensStore
 	DraftStore                      store.DraftStore
 	EmojiStore                      store.EmojiStore
@@ -36,9 +40,14 @@ type TimerLayer struct {
 	JobStore                        store.JobStore
 	LicenseStore                    store.LicenseStore
 	LinkMetadataStore               store.LinkMetadataStore
+	LoginDeviceStore                store.LoginDeviceStore
+	MfaRecoveryCodeStore            store.MfaRecoveryCodeStore
+	MfaTrustedDeviceStore           store.MfaTrustedDeviceStore
+	NotificationRuleStore           store.NotificationRuleStore
 	NotifyAdminStore                store.NotifyAdminStore
 	OAuthStore                      store.OAuthStore
 	OutgoingOAuthConnectionStore    store.OutgoingOAuthConnectionStore
+	PasswordHistoryStore            store.PasswordHistoryStore
 	PluginStore                     store.PluginStore
 	PostStore                       store.PostStore
 	PostAcknowledgementStore        store.PostAcknowledgementStore
@@ -63,13 +72,23 @@ type TimerLayer struct {
 	UserStore                       store.UserStore
 	UserAccessTokenStore            store.UserAccessTokenStore
 	UserTermsOfServiceStore         store.UserTermsOfServiceStore
+	WebAuthnCredentialStore         store.WebAuthnCredentialStore
+	WebSocketEventStore             store.WebSocketEventStore
 	WebhookStore                    store.WebhookStore
 }
 
+func (s *TimerLayer) AccessControlPolicy() store.AccessControlPolicyStore {
+	return s.AccessControlPolicyStore
+}
+
 func (s *TimerLayer) Audit() store.AuditStore {
 	return s.AuditStore
 }
 
+func (s *TimerLayer) AuditRecord() store.AuditRecordStore {
+	return s.AuditRecordStore
+}
+
 func (s *TimerLayer) Bot() store.BotStore {
 	return s.BotStore
 }
@@ -82,6 +101,10 @@ func (s *TimerLayer) ChannelBookmark() store.ChannelBookmarkStore {
 	return s.ChannelBookmarkStore
 }
 
+func (s *TimerLayer) ChannelEmailAddress() store.ChannelEmailAddressStore {
+	return s.ChannelEmailAddressStore
+}
+
 func (s *TimerLayer) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	return s.ChannelMemberHistoryStore
 }
@@ -102,6 +125,10 @@ func (s *TimerLayer) Compliance() store.ComplianceStore {
 	return s.ComplianceStore
 }
 
+func (s *TimerLayer) CustomProfileAttribute() store.CustomProfileAttributeStore {
+	return s.CustomProfileAttributeStore
+}
+
 func (s *TimerLayer) DesktopTokens() store.DesktopTokensStore {
 	return s.DesktopTokensStore
 }
@@ -134,6 +161,22 @@ func (s *TimerLayer) LinkMetadata() store.LinkMetadataStore {
 	return s.LinkMetadataStore
 }
 
+func (s *TimerLayer) LoginDevice() store.LoginDeviceStore {
+	return s.LoginDeviceStore
+}
+
+func (s *TimerLayer) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	return s.MfaRecoveryCodeStore
+}
+
+func (s *TimerLayer) MfaTrustedDevice() store.MfaTrustedDeviceStore {
+	return s.MfaTrustedDeviceStore
+}
+
+func (s *TimerLayer) NotificationRule() store.NotificationRuleStore {
+	return s.NotificationRuleStore
+}
+
 func (s *TimerLayer) NotifyAdmin() store.NotifyAdminStore {
 	return s.NotifyAdminStore
 }
@@ -146,6 +189,10 @@ func (s *TimerLayer) OutgoingOAuthConnection() store.OutgoingOAuthConnectionStor
 	return s.OutgoingOAuthConnectionStore
 }
 
+func (s *TimerLayer) PasswordHistory() store.PasswordHistoryStore {
+	return s.PasswordHistoryStore
+}
+
 func (s *TimerLayer) Plugin() store.PluginStore {
 	return s.PluginStore
 }
@@ -242,15 +289,33 @@ func (s *TimerLayer) UserTermsOfService() store.UserTermsOfServiceStore {
 	return s.UserTermsOfServiceStore
 }
 
+func (s *TimerLayer) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return s.WebAuthnCredentialStore
+}
+
+func (s *TimerLayer) WebSocketEvent() store.WebSocketEventStore {
+	return s.WebSocketEventStore
+}
+
 func (s *TimerLayer) Webhook() store.WebhookStore {
 	return s.WebhookStore
 }
 
+type TimerLayerAccessControlPolicyStore struct {
+	store.AccessControlPolicyStore
+	Root *TimerLayer
+}
+
 type TimerLayerAuditStore struct {
 	store.AuditStore
 	Root *TimerLayer
 }
 
+type TimerLayerAuditRecordStore struct {
+	store.AuditRecordStore
+	Root *TimerLayer
+}
+
 type TimerLayerBotStore struct {
 	store.BotStore
 	Root *TimerLayer
@@ -266,6 +331,11 @@ type TimerLayerChannelBookmarkStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerChannelEmailAddressStore struct {
+	store.ChannelEmailAddressStore
+	Root *TimerLayer
+}
+
 type TimerLayerChannelMemberHistoryStore struct {
 	store.ChannelMemberHistoryStore
 	Root *TimerLayer
@@ -291,6 +361,11 @@ type TimerLayerComplianceStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerCustomProfileAttributeStore struct {
+	store.CustomProfileAttributeStore
+	Root *TimerLayer
+}
+
 type TimerLayerDesktopTokensStore struct {
 	store.DesktopTokensStore
 	Root *TimerLayer
@@ -331,6 +406,26 @@ type TimerLayerLinkMetadataStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerLoginDeviceStore struct {
+	store.LoginDeviceStore
+	Root *TimerLayer
+}
+
+type TimerLayerMfaRecoveryCodeStore struct {
+	store.MfaRecoveryCodeStore
+	Root *TimerLayer
+}
+
+type TimerLayerMfaTrustedDeviceStore struct {
+	store.MfaTrustedDeviceStore
+	Root *TimerLayer
+}
+
+type TimerLayerNotificationRuleStore struct {
+	store.NotificationRuleStore
+	Root *TimerLayer
+}
+
 type TimerLayerNotifyAdminStore struct {
 	store.NotifyAdminStore
 	Root *TimerLayer
@@ -346,6 +441,11 @@ type TimerLayerOutgoingOAuthConnectionStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerPasswordHistoryStore struct {
+	store.PasswordHistoryStore
+	Root *TimerLayer
+}
+
 type TimerLayerPluginStore struct {
 	store.PluginStore
 	Root *TimerLayer
@@ -466,11 +566,85 @@ type TimerLayerUserTermsOfServiceStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerWebAuthnCredentialStore struct {
+	store.WebAuthnCredentialStore
+	Root *TimerLayer
+}
+
+type TimerLayerWebSocketEventStore struct {
+	store.WebSocketEventStore
+	Root *TimerLayer
+}
+
 type TimerLayerWebhookStore struct {
 	store.WebhookStore
 	Root *TimerLayer
 }
 
+func (s *TimerLayerAccessControlPolicyStore) Delete(channelID string) error {
+	start := time.Now()
+
+	err := s.AccessControlPolicyStore.Delete(channelID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AccessControlPolicyStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerAccessControlPolicyStore) Get(channelID string) (*model.AccessControlPolicy, error) {
+	start := time.Now()
+
+	result, err := s.AccessControlPolicyStore.Get(channelID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AccessControlPolicyStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerAccessControlPolicyStore) GetAll(afterChannelID string, limit int) ([]*model.AccessControlPolicy, error) {
+	start := time.Now()
+
+	result, err := s.AccessControlPolicyStore.GetAll(afterChannelID, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AccessControlPolicyStore.GetAll", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerAccessControlPolicyStore) Save(policy *model.AccessControlPolicy) (*model.AccessControlPolicy, error) {
+	start := time.Now()
+
+	result, err := s.AccessControlPolicyStore.Save(policy)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AccessControlPolicyStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerAuditStore) Get(user_id string, offset int, limit int) (model.Audits, error) {
 	start := time.Now()
 
@@ -519,6 +693,38 @@ func (s *TimerLayerAuditStore) Save(audit *model.Audit) error {
 	return err
 }
 
+func (s *TimerLayerAuditRecordStore) Save(record *model.AuditRecord) (*model.AuditRecord, error) {
+	start := time.Now()
+
+	result, err := s.AuditRecordStore.Save(record)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditRecordStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerAuditRecordStore) Search(opts model.AuditRecordSearchOptions) ([]*model.AuditRecord, error) {
+	start := time.Now()
+
+	result, err := s.AuditRecordStore.Search(opts)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditRecordStore.Search", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerBotStore) Get(userID string, includeDeleted bool) (*model.Bot, error) {
 	start := time.Now()
 
@@ -1589,6 +1795,22 @@ func (s *TimerLayerChannelStore) GetMoreChannels(teamID string, userID string, o
 	return result, err
 }
 
+func (s *TimerLayerChannelStore) GetNewPublicChannelsForUser(userID string, since int64, limit int) ([]*model.ChannelWithTeamData, error) {
+	start := time.Now()
+
+	result, err := s.ChannelStore.GetNewPublicChannelsForUser(userID, since, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelStore.GetNewPublicChannelsForUser", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerChannelStore) GetPinnedPostCount(channelID string, allowFromCache bool) (int64, error) {
 	start := time.Now()
 
@@ -2590,6 +2812,86 @@ func (s *TimerLayerChannelBookmarkStore) UpdateSortOrder(bookmarkId string, chan
 	return result, err
 }
 
+func (s *TimerLayerChannelEmailAddressStore) Delete(channelID string) error {
+	start := time.Now()
+
+	err := s.ChannelEmailAddressStore.Delete(channelID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelEmailAddressStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerChannelEmailAddressStore) Get(channelID string) (*model.ChannelEmailAddress, error) {
+	start := time.Now()
+
+	result, err := s.ChannelEmailAddressStore.Get(channelID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelEmailAddressStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerChannelEmailAddressStore) GetByToken(token string) (*model.ChannelEmailAddress, error) {
+	start := time.Now()
+
+	result, err := s.ChannelEmailAddressStore.GetByToken(token)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelEmailAddressStore.GetByToken", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerChannelEmailAddressStore) Save(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error) {
+	start := time.Now()
+
+	result, err := s.ChannelEmailAddressStore.Save(address)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelEmailAddressStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerChannelEmailAddressStore) Update(address *model.ChannelEmailAddress) (*model.ChannelEmailAddress, error) {
+	start := time.Now()
+
+	result, err := s.ChannelEmailAddressStore.Update(address)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelEmailAddressStore.Update", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerChannelMemberHistoryStore) DeleteOrphanedRows(limit int) (int64, error) {
 	start := time.Now()
 
@@ -3101,10 +3403,10 @@ func (s *TimerLayerComplianceStore) Update(compliance *model.Compliance) (*model
 	return result, err
 }
 
-func (s *TimerLayerDesktopTokensStore) Delete(token string) error {
+func (s *TimerLayerCustomProfileAttributeStore) DeleteField(rctx request.CTX, id string, deleteAt int64) error {
 	start := time.Now()
 
-	err := s.DesktopTokensStore.Delete(token)
+	err := s.CustomProfileAttributeStore.DeleteField(rctx, id, deleteAt)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3112,15 +3414,15 @@ func (s *TimerLayerDesktopTokensStore) Delete(token string) error {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.Delete", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.DeleteField", success, elapsed)
 	}
 	return err
 }
 
-func (s *TimerLayerDesktopTokensStore) DeleteByUserId(userId string) error {
+func (s *TimerLayerCustomProfileAttributeStore) GetField(id string) (*model.CustomProfileField, error) {
 	start := time.Now()
 
-	err := s.DesktopTokensStore.DeleteByUserId(userId)
+	result, err := s.CustomProfileAttributeStore.GetField(id)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3128,15 +3430,15 @@ func (s *TimerLayerDesktopTokensStore) DeleteByUserId(userId string) error {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.DeleteByUserId", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.GetField", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerDesktopTokensStore) DeleteOlderThan(minCreatedAt int64) error {
+func (s *TimerLayerCustomProfileAttributeStore) GetFields(includeDeleted bool) ([]*model.CustomProfileField, error) {
 	start := time.Now()
 
-	err := s.DesktopTokensStore.DeleteOlderThan(minCreatedAt)
+	result, err := s.CustomProfileAttributeStore.GetFields(includeDeleted)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3144,15 +3446,15 @@ func (s *TimerLayerDesktopTokensStore) DeleteOlderThan(minCreatedAt int64) error
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.DeleteOlderThan", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.GetFields", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerDesktopTokensStore) GetUserId(token string, minCreatedAt int64) (*string, error) {
+func (s *TimerLayerCustomProfileAttributeStore) GetUserIDsForField(fieldID string) ([]string, error) {
 	start := time.Now()
 
-	result, err := s.DesktopTokensStore.GetUserId(token, minCreatedAt)
+	result, err := s.CustomProfileAttributeStore.GetUserIDsForField(fieldID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3160,15 +3462,15 @@ func (s *TimerLayerDesktopTokensStore) GetUserId(token string, minCreatedAt int6
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.GetUserId", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.GetUserIDsForField", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerDesktopTokensStore) Insert(token string, createAt int64, userId string) error {
+func (s *TimerLayerCustomProfileAttributeStore) GetValuesForUsers(userIDs []string) ([]*model.CustomProfileValue, error) {
 	start := time.Now()
 
-	err := s.DesktopTokensStore.Insert(token, createAt, userId)
+	result, err := s.CustomProfileAttributeStore.GetValuesForUsers(userIDs)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3176,15 +3478,15 @@ func (s *TimerLayerDesktopTokensStore) Insert(token string, createAt int64, user
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.Insert", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.GetValuesForUsers", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerDraftStore) Delete(userID string, channelID string, rootID string) error {
+func (s *TimerLayerCustomProfileAttributeStore) PermanentDeleteByUser(userID string) error {
 	start := time.Now()
 
-	err := s.DraftStore.Delete(userID, channelID, rootID)
+	err := s.CustomProfileAttributeStore.PermanentDeleteByUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3192,15 +3494,15 @@ func (s *TimerLayerDraftStore) Delete(userID string, channelID string, rootID st
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.Delete", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.PermanentDeleteByUser", success, elapsed)
 	}
 	return err
 }
 
-func (s *TimerLayerDraftStore) DeleteDraftsAssociatedWithPost(channelID string, rootID string) error {
+func (s *TimerLayerCustomProfileAttributeStore) SaveField(field *model.CustomProfileField) (*model.CustomProfileField, error) {
 	start := time.Now()
 
-	err := s.DraftStore.DeleteDraftsAssociatedWithPost(channelID, rootID)
+	result, err := s.CustomProfileAttributeStore.SaveField(field)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3208,15 +3510,15 @@ func (s *TimerLayerDraftStore) DeleteDraftsAssociatedWithPost(channelID string,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.DeleteDraftsAssociatedWithPost", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.SaveField", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerDraftStore) DeleteEmptyDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+func (s *TimerLayerCustomProfileAttributeStore) SaveValues(rctx request.CTX, userID string, values []*model.CustomProfileValue) error {
 	start := time.Now()
 
-	err := s.DraftStore.DeleteEmptyDraftsByCreateAtAndUserId(createAt, userId)
+	err := s.CustomProfileAttributeStore.SaveValues(rctx, userID, values)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3224,15 +3526,15 @@ func (s *TimerLayerDraftStore) DeleteEmptyDraftsByCreateAtAndUserId(createAt int
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.DeleteEmptyDraftsByCreateAtAndUserId", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.SaveValues", success, elapsed)
 	}
 	return err
 }
 
-func (s *TimerLayerDraftStore) DeleteOrphanDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+func (s *TimerLayerCustomProfileAttributeStore) UpdateField(rctx request.CTX, field *model.CustomProfileField) (*model.CustomProfileField, error) {
 	start := time.Now()
 
-	err := s.DraftStore.DeleteOrphanDraftsByCreateAtAndUserId(createAt, userId)
+	result, err := s.CustomProfileAttributeStore.UpdateField(rctx, field)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3240,15 +3542,15 @@ func (s *TimerLayerDraftStore) DeleteOrphanDraftsByCreateAtAndUserId(createAt in
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.DeleteOrphanDraftsByCreateAtAndUserId", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("CustomProfileAttributeStore.UpdateField", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerDraftStore) Get(userID string, channelID string, rootID string, includeDeleted bool) (*model.Draft, error) {
+func (s *TimerLayerDesktopTokensStore) Delete(token string) error {
 	start := time.Now()
 
-	result, err := s.DraftStore.Get(userID, channelID, rootID, includeDeleted)
+	err := s.DesktopTokensStore.Delete(token)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3256,9 +3558,153 @@ func (s *TimerLayerDraftStore) Get(userID string, channelID string, rootID strin
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.Get", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.Delete", success, elapsed)
 	}
-	return result, err
+	return err
+}
+
+func (s *TimerLayerDesktopTokensStore) DeleteByUserId(userId string) error {
+	start := time.Now()
+
+	err := s.DesktopTokensStore.DeleteByUserId(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.DeleteByUserId", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDesktopTokensStore) DeleteOlderThan(minCreatedAt int64) error {
+	start := time.Now()
+
+	err := s.DesktopTokensStore.DeleteOlderThan(minCreatedAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.DeleteOlderThan", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDesktopTokensStore) GetUserId(token string, minCreatedAt int64) (*string, error) {
+	start := time.Now()
+
+	result, err := s.DesktopTokensStore.GetUserId(token, minCreatedAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.GetUserId", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerDesktopTokensStore) Insert(token string, createAt int64, userId string) error {
+	start := time.Now()
+
+	err := s.DesktopTokensStore.Insert(token, createAt, userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DesktopTokensStore.Insert", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDraftStore) Delete(userID string, channelID string, rootID string) error {
+	start := time.Now()
+
+	err := s.DraftStore.Delete(userID, channelID, rootID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDraftStore) DeleteDraftsAssociatedWithPost(channelID string, rootID string) error {
+	start := time.Now()
+
+	err := s.DraftStore.DeleteDraftsAssociatedWithPost(channelID, rootID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.DeleteDraftsAssociatedWithPost", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDraftStore) DeleteEmptyDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+	start := time.Now()
+
+	err := s.DraftStore.DeleteEmptyDraftsByCreateAtAndUserId(createAt, userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.DeleteEmptyDraftsByCreateAtAndUserId", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDraftStore) DeleteOrphanDraftsByCreateAtAndUserId(createAt int64, userId string) error {
+	start := time.Now()
+
+	err := s.DraftStore.DeleteOrphanDraftsByCreateAtAndUserId(createAt, userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.DeleteOrphanDraftsByCreateAtAndUserId", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerDraftStore) Get(userID string, channelID string, rootID string, includeDeleted bool) (*model.Draft, error) {
+	start := time.Now()
+
+	result, err := s.DraftStore.Get(userID, channelID, rootID, includeDeleted)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("DraftStore.Get", success, elapsed)
+	}
+	return result, err
 }
 
 func (s *TimerLayerDraftStore) GetDraftsForUser(userID string, teamID string) ([]*model.Draft, error) {
@@ -3532,6 +3978,22 @@ func (s *TimerLayerFileInfoStore) GetByPath(path string) (*model.FileInfo, error
 	return result, err
 }
 
+func (s *TimerLayerFileInfoStore) GetFilesBatchForColdStorage(olderThan int64, startTime int64, startFileID string, limit int) ([]*model.FileInfo, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.GetFilesBatchForColdStorage(olderThan, startTime, startFileID, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.GetFilesBatchForColdStorage", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) GetFilesBatchForIndexing(startTime int64, startFileID string, includeDeleted bool, limit int) ([]*model.FileForIndexing, error) {
 	start := time.Now()
 
@@ -3612,6 +4074,22 @@ func (s *TimerLayerFileInfoStore) GetStorageUsage(allowFromCache bool, includeDe
 	return result, err
 }
 
+func (s *TimerLayerFileInfoStore) GetStorageUsageByTier(includeDeleted bool) ([]*model.FileTierUsage, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.GetStorageUsageByTier(includeDeleted)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.GetStorageUsageByTier", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) GetUptoNSizeFileTime(n int64) (int64, error) {
 	start := time.Now()
 
@@ -3755,6 +4233,22 @@ func (s *TimerLayerFileInfoStore) SetContent(ctx request.CTX, fileID string, con
 	return err
 }
 
+func (s *TimerLayerFileInfoStore) SetTier(ctx request.CTX, fileID string, tier string) error {
+	start := time.Now()
+
+	err := s.FileInfoStore.SetTier(ctx, fileID, tier)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.SetTier", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	start := time.Now()
 
@@ -4155,6 +4649,22 @@ func (s *TimerLayerGroupStore) GetByUser(userID string) ([]*model.Group, error)
 	return result, err
 }
 
+func (s *TimerLayerGroupStore) GetByUsers(userIDs []string) (map[string][]*model.Group, error) {
+	start := time.Now()
+
+	result, err := s.GroupStore.GetByUsers(userIDs)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.GetByUsers", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerGroupStore) GetGroupSyncable(groupID string, syncableID string, syncableType model.GroupSyncableType) (*model.GroupSyncable, error) {
 	start := time.Now()
 
@@ -4987,10 +5497,10 @@ func (s *TimerLayerLinkMetadataStore) Save(linkMetadata *model.LinkMetadata) (*m
 	return result, err
 }
 
-func (s *TimerLayerNotifyAdminStore) DeleteBefore(trial bool, now int64) error {
+func (s *TimerLayerLoginDeviceStore) GetForUser(userID string, limit int) ([]*model.LoginDevice, error) {
 	start := time.Now()
 
-	err := s.NotifyAdminStore.DeleteBefore(trial, now)
+	result, err := s.LoginDeviceStore.GetForUser(userID, limit)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -4998,15 +5508,15 @@ func (s *TimerLayerNotifyAdminStore) DeleteBefore(trial bool, now int64) error {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.DeleteBefore", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginDeviceStore.GetForUser", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerNotifyAdminStore) Get(trial bool) ([]*model.NotifyAdminData, error) {
+func (s *TimerLayerLoginDeviceStore) PermanentDeleteByUser(userID string) error {
 	start := time.Now()
 
-	result, err := s.NotifyAdminStore.Get(trial)
+	err := s.LoginDeviceStore.PermanentDeleteByUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5014,15 +5524,15 @@ func (s *TimerLayerNotifyAdminStore) Get(trial bool) ([]*model.NotifyAdminData,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.Get", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginDeviceStore.PermanentDeleteByUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerNotifyAdminStore) GetDataByUserIdAndFeature(userId string, feature model.MattermostFeature) ([]*model.NotifyAdminData, error) {
+func (s *TimerLayerLoginDeviceStore) PruneForUser(userID string, keep int) error {
 	start := time.Now()
 
-	result, err := s.NotifyAdminStore.GetDataByUserIdAndFeature(userId, feature)
+	err := s.LoginDeviceStore.PruneForUser(userID, keep)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5030,15 +5540,15 @@ func (s *TimerLayerNotifyAdminStore) GetDataByUserIdAndFeature(userId string, fe
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.GetDataByUserIdAndFeature", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginDeviceStore.PruneForUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerNotifyAdminStore) Save(data *model.NotifyAdminData) (*model.NotifyAdminData, error) {
+func (s *TimerLayerLoginDeviceStore) Save(device *model.LoginDevice) (*model.LoginDevice, error) {
 	start := time.Now()
 
-	result, err := s.NotifyAdminStore.Save(data)
+	result, err := s.LoginDeviceStore.Save(device)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5046,15 +5556,15 @@ func (s *TimerLayerNotifyAdminStore) Save(data *model.NotifyAdminData) (*model.N
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.Save", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginDeviceStore.Save", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerNotifyAdminStore) Update(userId string, requiredPlan string, requiredFeature model.MattermostFeature, now int64) error {
+func (s *TimerLayerLoginDeviceStore) UpdateLastSeenAt(id string, lastSeenAt int64) error {
 	start := time.Now()
 
-	err := s.NotifyAdminStore.Update(userId, requiredPlan, requiredFeature, now)
+	err := s.LoginDeviceStore.UpdateLastSeenAt(id, lastSeenAt)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5062,15 +5572,15 @@ func (s *TimerLayerNotifyAdminStore) Update(userId string, requiredPlan string,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.Update", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginDeviceStore.UpdateLastSeenAt", success, elapsed)
 	}
 	return err
 }
 
-func (s *TimerLayerOAuthStore) DeleteApp(id string) error {
+func (s *TimerLayerMfaRecoveryCodeStore) GetUnusedForUser(userID string) ([]*model.MfaRecoveryCode, error) {
 	start := time.Now()
 
-	err := s.OAuthStore.DeleteApp(id)
+	result, err := s.MfaRecoveryCodeStore.GetUnusedForUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5078,15 +5588,15 @@ func (s *TimerLayerOAuthStore) DeleteApp(id string) error {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.DeleteApp", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaRecoveryCodeStore.GetUnusedForUser", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerOAuthStore) GetAccessData(token string) (*model.AccessData, error) {
+func (s *TimerLayerMfaRecoveryCodeStore) MarkUsed(id string, usedAt int64) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetAccessData(token)
+	err := s.MfaRecoveryCodeStore.MarkUsed(id, usedAt)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5094,15 +5604,15 @@ func (s *TimerLayerOAuthStore) GetAccessData(token string) (*model.AccessData, e
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessData", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaRecoveryCodeStore.MarkUsed", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) GetAccessDataByRefreshToken(token string) (*model.AccessData, error) {
+func (s *TimerLayerMfaRecoveryCodeStore) PermanentDeleteByUser(userID string) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetAccessDataByRefreshToken(token)
+	err := s.MfaRecoveryCodeStore.PermanentDeleteByUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5110,15 +5620,15 @@ func (s *TimerLayerOAuthStore) GetAccessDataByRefreshToken(token string) (*model
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessDataByRefreshToken", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaRecoveryCodeStore.PermanentDeleteByUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) GetAccessDataByUserForApp(userID string, clientId string) ([]*model.AccessData, error) {
+func (s *TimerLayerMfaRecoveryCodeStore) Replace(userID string, codes []*model.MfaRecoveryCode) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetAccessDataByUserForApp(userID, clientId)
+	err := s.MfaRecoveryCodeStore.Replace(userID, codes)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5126,15 +5636,15 @@ func (s *TimerLayerOAuthStore) GetAccessDataByUserForApp(userID string, clientId
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessDataByUserForApp", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaRecoveryCodeStore.Replace", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) GetApp(id string) (*model.OAuthApp, error) {
+func (s *TimerLayerMfaTrustedDeviceStore) Cleanup(expiryTime int64) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetApp(id)
+	err := s.MfaTrustedDeviceStore.Cleanup(expiryTime)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5142,15 +5652,15 @@ func (s *TimerLayerOAuthStore) GetApp(id string) (*model.OAuthApp, error) {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetApp", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaTrustedDeviceStore.Cleanup", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) GetAppByUser(userID string, offset int, limit int) ([]*model.OAuthApp, error) {
+func (s *TimerLayerMfaTrustedDeviceStore) Delete(id string) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetAppByUser(userID, offset, limit)
+	err := s.MfaTrustedDeviceStore.Delete(id)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5158,15 +5668,15 @@ func (s *TimerLayerOAuthStore) GetAppByUser(userID string, offset int, limit int
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAppByUser", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaTrustedDeviceStore.Delete", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) GetApps(offset int, limit int) ([]*model.OAuthApp, error) {
+func (s *TimerLayerMfaTrustedDeviceStore) Get(id string) (*model.MfaTrustedDevice, error) {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetApps(offset, limit)
+	result, err := s.MfaTrustedDeviceStore.Get(id)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5174,15 +5684,15 @@ func (s *TimerLayerOAuthStore) GetApps(offset int, limit int) ([]*model.OAuthApp
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetApps", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaTrustedDeviceStore.Get", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerOAuthStore) GetAuthData(code string) (*model.AuthData, error) {
+func (s *TimerLayerMfaTrustedDeviceStore) PermanentDeleteByUser(userID string) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.GetAuthData(code)
+	err := s.MfaTrustedDeviceStore.PermanentDeleteByUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5190,12 +5700,364 @@ func (s *TimerLayerOAuthStore) GetAuthData(code string) (*model.AuthData, error)
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAuthData", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaTrustedDeviceStore.PermanentDeleteByUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) GetAuthorizedApps(userID string, offset int, limit int) ([]*model.OAuthApp, error) {
+func (s *TimerLayerMfaTrustedDeviceStore) Save(device *model.MfaTrustedDevice) (*model.MfaTrustedDevice, error) {
+	start := time.Now()
+
+	result, err := s.MfaTrustedDeviceStore.Save(device)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaTrustedDeviceStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerMfaTrustedDeviceStore) UpdateLastUsedAt(id string, lastUsedAt int64) error {
+	start := time.Now()
+
+	err := s.MfaTrustedDeviceStore.UpdateLastUsedAt(id, lastUsedAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("MfaTrustedDeviceStore.UpdateLastUsedAt", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerNotificationRuleStore) Delete(id string) error {
+	start := time.Now()
+
+	err := s.NotificationRuleStore.Delete(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerNotificationRuleStore) Get(id string) (*model.NotificationRule, error) {
+	start := time.Now()
+
+	result, err := s.NotificationRuleStore.Get(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotificationRuleStore) GetForChannelMembers(channelID string) ([]*model.NotificationRule, error) {
+	start := time.Now()
+
+	result, err := s.NotificationRuleStore.GetForChannelMembers(channelID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.GetForChannelMembers", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotificationRuleStore) GetForUser(userID string) ([]*model.NotificationRule, error) {
+	start := time.Now()
+
+	result, err := s.NotificationRuleStore.GetForUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.GetForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotificationRuleStore) PermanentDeleteByUser(userID string) error {
+	start := time.Now()
+
+	err := s.NotificationRuleStore.PermanentDeleteByUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.PermanentDeleteByUser", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerNotificationRuleStore) Save(rule *model.NotificationRule) (*model.NotificationRule, error) {
+	start := time.Now()
+
+	result, err := s.NotificationRuleStore.Save(rule)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotificationRuleStore) Update(rule *model.NotificationRule) (*model.NotificationRule, error) {
+	start := time.Now()
+
+	result, err := s.NotificationRuleStore.Update(rule)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationRuleStore.Update", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotifyAdminStore) DeleteBefore(trial bool, now int64) error {
+	start := time.Now()
+
+	err := s.NotifyAdminStore.DeleteBefore(trial, now)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.DeleteBefore", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerNotifyAdminStore) Get(trial bool) ([]*model.NotifyAdminData, error) {
+	start := time.Now()
+
+	result, err := s.NotifyAdminStore.Get(trial)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotifyAdminStore) GetDataByUserIdAndFeature(userId string, feature model.MattermostFeature) ([]*model.NotifyAdminData, error) {
+	start := time.Now()
+
+	result, err := s.NotifyAdminStore.GetDataByUserIdAndFeature(userId, feature)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.GetDataByUserIdAndFeature", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotifyAdminStore) Save(data *model.NotifyAdminData) (*model.NotifyAdminData, error) {
+	start := time.Now()
+
+	result, err := s.NotifyAdminStore.Save(data)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerNotifyAdminStore) Update(userId string, requiredPlan string, requiredFeature model.MattermostFeature, now int64) error {
+	start := time.Now()
+
+	err := s.NotifyAdminStore.Update(userId, requiredPlan, requiredFeature, now)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("NotifyAdminStore.Update", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerOAuthStore) DeleteApp(id string) error {
+	start := time.Now()
+
+	err := s.OAuthStore.DeleteApp(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.DeleteApp", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerOAuthStore) GetAccessData(token string) (*model.AccessData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetAccessData(token)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessData", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetAccessDataByRefreshToken(token string) (*model.AccessData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetAccessDataByRefreshToken(token)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessDataByRefreshToken", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetAccessDataByUserForApp(userID string, clientId string) ([]*model.AccessData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetAccessDataByUserForApp(userID, clientId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessDataByUserForApp", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetApp(id string) (*model.OAuthApp, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetApp(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetApp", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetAppByUser(userID string, offset int, limit int) ([]*model.OAuthApp, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetAppByUser(userID, offset, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAppByUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetApps(offset int, limit int) ([]*model.OAuthApp, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetApps(offset, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetApps", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetAuthData(code string) (*model.AuthData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetAuthData(code)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAuthData", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetAuthorizedApps(userID string, offset int, limit int) ([]*model.OAuthApp, error) {
 	start := time.Now()
 
 	result, err := s.OAuthStore.GetAuthorizedApps(userID, offset, limit)
@@ -5211,6 +6073,38 @@ func (s *TimerLayerOAuthStore) GetAuthorizedApps(userID string, offset int, limi
 	return result, err
 }
 
+func (s *TimerLayerOAuthStore) GetDeviceCode(deviceCode string) (*model.OAuthDeviceCode, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetDeviceCode(deviceCode)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetDeviceCode", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) GetDeviceCodeByUserCode(userCode string) (*model.OAuthDeviceCode, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.GetDeviceCodeByUserCode(userCode)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetDeviceCodeByUserCode", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerOAuthStore) GetPreviousAccessData(userID string, clientId string) (*model.AccessData, error) {
 	start := time.Now()
 
@@ -5307,10 +6201,138 @@ func (s *TimerLayerOAuthStore) RemoveAuthDataByClientId(clientId string, userId
 	return err
 }
 
-func (s *TimerLayerOAuthStore) RemoveAuthDataByUserId(userId string) error {
+func (s *TimerLayerOAuthStore) RemoveAuthDataByUserId(userId string) error {
+	start := time.Now()
+
+	err := s.OAuthStore.RemoveAuthDataByUserId(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.RemoveAuthDataByUserId", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerOAuthStore) RemoveDeviceCode(deviceCode string) error {
+	start := time.Now()
+
+	err := s.OAuthStore.RemoveDeviceCode(deviceCode)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.RemoveDeviceCode", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerOAuthStore) RemoveExpiredDeviceCodes(expiredBefore int64) error {
+	start := time.Now()
+
+	err := s.OAuthStore.RemoveExpiredDeviceCodes(expiredBefore)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.RemoveExpiredDeviceCodes", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerOAuthStore) SaveAccessData(accessData *model.AccessData) (*model.AccessData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.SaveAccessData(accessData)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAccessData", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.SaveApp(app)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveApp", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.SaveAuthData(authData)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAuthData", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) SaveDeviceCode(deviceCode *model.OAuthDeviceCode) (*model.OAuthDeviceCode, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.SaveDeviceCode(deviceCode)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveDeviceCode", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) UpdateAccessData(accessData *model.AccessData) (*model.AccessData, error) {
+	start := time.Now()
+
+	result, err := s.OAuthStore.UpdateAccessData(accessData)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateAccessData", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, error) {
 	start := time.Now()
 
-	err := s.OAuthStore.RemoveAuthDataByUserId(userId)
+	result, err := s.OAuthStore.UpdateApp(app)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5318,15 +6340,15 @@ func (s *TimerLayerOAuthStore) RemoveAuthDataByUserId(userId string) error {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.RemoveAuthDataByUserId", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateApp", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerOAuthStore) SaveAccessData(accessData *model.AccessData) (*model.AccessData, error) {
+func (s *TimerLayerOAuthStore) UpdateDeviceCodeOptimistically(deviceCode *model.OAuthDeviceCode, currentStatus string) (bool, error) {
 	start := time.Now()
 
-	result, err := s.OAuthStore.SaveAccessData(accessData)
+	result, err := s.OAuthStore.UpdateDeviceCodeOptimistically(deviceCode, currentStatus)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5334,15 +6356,15 @@ func (s *TimerLayerOAuthStore) SaveAccessData(accessData *model.AccessData) (*mo
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAccessData", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateDeviceCodeOptimistically", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, error) {
+func (s *TimerLayerOutgoingOAuthConnectionStore) DeleteConnection(c request.CTX, id string) error {
 	start := time.Now()
 
-	result, err := s.OAuthStore.SaveApp(app)
+	err := s.OutgoingOAuthConnectionStore.DeleteConnection(c, id)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5350,15 +6372,15 @@ func (s *TimerLayerOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, er
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveApp", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.DeleteConnection", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData, error) {
+func (s *TimerLayerOutgoingOAuthConnectionStore) GetConnection(c request.CTX, id string) (*model.OutgoingOAuthConnection, error) {
 	start := time.Now()
 
-	result, err := s.OAuthStore.SaveAuthData(authData)
+	result, err := s.OutgoingOAuthConnectionStore.GetConnection(c, id)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5366,15 +6388,15 @@ func (s *TimerLayerOAuthStore) SaveAuthData(authData *model.AuthData) (*model.Au
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAuthData", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.GetConnection", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerOAuthStore) UpdateAccessData(accessData *model.AccessData) (*model.AccessData, error) {
+func (s *TimerLayerOutgoingOAuthConnectionStore) GetConnections(c request.CTX, filters model.OutgoingOAuthConnectionGetConnectionsFilter) ([]*model.OutgoingOAuthConnection, error) {
 	start := time.Now()
 
-	result, err := s.OAuthStore.UpdateAccessData(accessData)
+	result, err := s.OutgoingOAuthConnectionStore.GetConnections(c, filters)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5382,15 +6404,15 @@ func (s *TimerLayerOAuthStore) UpdateAccessData(accessData *model.AccessData) (*
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateAccessData", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.GetConnections", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, error) {
+func (s *TimerLayerOutgoingOAuthConnectionStore) SaveConnection(c request.CTX, conn *model.OutgoingOAuthConnection) (*model.OutgoingOAuthConnection, error) {
 	start := time.Now()
 
-	result, err := s.OAuthStore.UpdateApp(app)
+	result, err := s.OutgoingOAuthConnectionStore.SaveConnection(c, conn)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5398,15 +6420,15 @@ func (s *TimerLayerOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateApp", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.SaveConnection", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerOutgoingOAuthConnectionStore) DeleteConnection(c request.CTX, id string) error {
+func (s *TimerLayerOutgoingOAuthConnectionStore) UpdateConnection(c request.CTX, conn *model.OutgoingOAuthConnection) (*model.OutgoingOAuthConnection, error) {
 	start := time.Now()
 
-	err := s.OutgoingOAuthConnectionStore.DeleteConnection(c, id)
+	result, err := s.OutgoingOAuthConnectionStore.UpdateConnection(c, conn)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5414,15 +6436,15 @@ func (s *TimerLayerOutgoingOAuthConnectionStore) DeleteConnection(c request.CTX,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.DeleteConnection", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.UpdateConnection", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerOutgoingOAuthConnectionStore) GetConnection(c request.CTX, id string) (*model.OutgoingOAuthConnection, error) {
+func (s *TimerLayerPasswordHistoryStore) GetForUser(userID string, limit int) ([]*model.PasswordHistoryEntry, error) {
 	start := time.Now()
 
-	result, err := s.OutgoingOAuthConnectionStore.GetConnection(c, id)
+	result, err := s.PasswordHistoryStore.GetForUser(userID, limit)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5430,15 +6452,15 @@ func (s *TimerLayerOutgoingOAuthConnectionStore) GetConnection(c request.CTX, id
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.GetConnection", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("PasswordHistoryStore.GetForUser", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerOutgoingOAuthConnectionStore) GetConnections(c request.CTX, filters model.OutgoingOAuthConnectionGetConnectionsFilter) ([]*model.OutgoingOAuthConnection, error) {
+func (s *TimerLayerPasswordHistoryStore) PermanentDeleteByUser(userID string) error {
 	start := time.Now()
 
-	result, err := s.OutgoingOAuthConnectionStore.GetConnections(c, filters)
+	err := s.PasswordHistoryStore.PermanentDeleteByUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5446,15 +6468,15 @@ func (s *TimerLayerOutgoingOAuthConnectionStore) GetConnections(c request.CTX, f
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.GetConnections", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("PasswordHistoryStore.PermanentDeleteByUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOutgoingOAuthConnectionStore) SaveConnection(c request.CTX, conn *model.OutgoingOAuthConnection) (*model.OutgoingOAuthConnection, error) {
+func (s *TimerLayerPasswordHistoryStore) PruneForUser(userID string, keep int) error {
 	start := time.Now()
 
-	result, err := s.OutgoingOAuthConnectionStore.SaveConnection(c, conn)
+	err := s.PasswordHistoryStore.PruneForUser(userID, keep)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5462,15 +6484,15 @@ func (s *TimerLayerOutgoingOAuthConnectionStore) SaveConnection(c request.CTX, c
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.SaveConnection", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("PasswordHistoryStore.PruneForUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerOutgoingOAuthConnectionStore) UpdateConnection(c request.CTX, conn *model.OutgoingOAuthConnection) (*model.OutgoingOAuthConnection, error) {
+func (s *TimerLayerPasswordHistoryStore) Save(entry *model.PasswordHistoryEntry) (*model.PasswordHistoryEntry, error) {
 	start := time.Now()
 
-	result, err := s.OutgoingOAuthConnectionStore.UpdateConnection(c, conn)
+	result, err := s.PasswordHistoryStore.Save(entry)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -5478,7 +6500,7 @@ func (s *TimerLayerOutgoingOAuthConnectionStore) UpdateConnection(c request.CTX,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("OutgoingOAuthConnectionStore.UpdateConnection", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("PasswordHistoryStore.Save", success, elapsed)
 	}
 	return result, err
 }
@@ -6154,6 +7176,22 @@ func (s *TimerLayerPostStore) GetSingle(rctx request.CTX, id string, inclDeleted
 	return result, err
 }
 
+func (s *TimerLayerPostStore) GetTopReactedForUserSince(userID string, since int64, limit int) ([]*model.ReactedPost, error) {
+	start := time.Now()
+
+	result, err := s.PostStore.GetTopReactedForUserSince(userID, since, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetTopReactedForUserSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) HasAutoResponsePostByUserSince(options model.GetPostsSinceOptions, userId string) (bool, error) {
 	start := time.Now()
 
@@ -11266,6 +12304,22 @@ func (s *TimerLayerUserStore) SearchNotInTeam(notInTeamID string, term string, o
 	return result, err
 }
 
+func (s *TimerLayerUserStore) SearchScim(filter *model.ScimFilter, offset int, limit int) ([]*model.User, int64, error) {
+	start := time.Now()
+
+	result, resultVar1, err := s.UserStore.SearchScim(filter, offset, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.SearchScim", success, elapsed)
+	}
+	return result, resultVar1, err
+}
+
 func (s *TimerLayerUserStore) SearchWithoutTeam(term string, options *model.UserSearchOptions) ([]*model.User, error) {
 	start := time.Now()
 
@@ -11426,6 +12480,22 @@ func (s *TimerLayerUserStore) UpdatePassword(userID string, newPassword string)
 	return err
 }
 
+func (s *TimerLayerUserStore) UpdatePasswordHash(userID string, currentHash string, newHash string) error {
+	start := time.Now()
+
+	err := s.UserStore.UpdatePasswordHash(userID, currentHash, newHash)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.UpdatePasswordHash", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerUserStore) UpdateUpdateAt(userID string) (int64, error) {
 	start := time.Now()
 
@@ -11666,6 +12736,198 @@ func (s *TimerLayerUserTermsOfServiceStore) Save(userTermsOfService *model.UserT
 	return result, err
 }
 
+func (s *TimerLayerWebAuthnCredentialStore) Delete(id string) error {
+	start := time.Now()
+
+	err := s.WebAuthnCredentialStore.Delete(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) Get(id string) (*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.Get(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.GetByCredentialId(credentialID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.GetByCredentialId", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) GetForUser(userID string) ([]*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.GetForUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.GetForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) PermanentDeleteByUser(userID string) error {
+	start := time.Now()
+
+	err := s.WebAuthnCredentialStore.PermanentDeleteByUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.PermanentDeleteByUser", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.Save(credential)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) UpdateSignCount(id string, signCount int64, lastUsedAt int64) error {
+	start := time.Now()
+
+	err := s.WebAuthnCredentialStore.UpdateSignCount(id, signCount, lastUsedAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.UpdateSignCount", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWebSocketEventStore) DeleteOlderThan(createAt int64, limit int) (int64, error) {
+	start := time.Now()
+
+	result, err := s.WebSocketEventStore.DeleteOlderThan(createAt, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebSocketEventStore.DeleteOlderThan", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebSocketEventStore) GetSeqRange() (int64, int64, error) {
+	start := time.Now()
+
+	result, resultVar1, err := s.WebSocketEventStore.GetSeqRange()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebSocketEventStore.GetSeqRange", success, elapsed)
+	}
+	return result, resultVar1, err
+}
+
+func (s *TimerLayerWebSocketEventStore) GetSince(userID string, seq int64, overlap int64, limit int) ([]*model.WebSocketEventLogEntry, error) {
+	start := time.Now()
+
+	result, err := s.WebSocketEventStore.GetSince(userID, seq, overlap, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebSocketEventStore.GetSince", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebSocketEventStore) Save(entry *model.WebSocketEventLogEntry) (*model.WebSocketEventLogEntry, error) {
+	start := time.Now()
+
+	result, err := s.WebSocketEventStore.Save(entry)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebSocketEventStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebSocketEventStore) SaveMultiple(entries []*model.WebSocketEventLogEntry) ([]*model.WebSocketEventLogEntry, error) {
+	start := time.Now()
+
+	result, err := s.WebSocketEventStore.SaveMultiple(entries)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebSocketEventStore.SaveMultiple", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerWebhookStore) AnalyticsIncomingCount(teamID string, userID string) (int64, error) {
 	start := time.Now()
 
@@ -12138,15 +13400,19 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 		Metrics: metrics,
 	}
 
+	newStore.AccessControlPolicyStore = &TimerLayerAccessControlPolicyStore{AccessControlPolicyStore: childStore.AccessControlPolicy(), Root: &newStore}
 	newStore.AuditStore = &TimerLayerAuditStore{AuditStore: childStore.Audit(), Root: &newStore}
+	newStore.AuditRecordStore = &TimerLayerAuditRecordStore{AuditRecordStore: childStore.AuditRecord(), Root: &newStore}
 	newStore.BotStore = &TimerLayerBotStore{BotStore: childStore.Bot(), Root: &newStore}
 	newStore.ChannelStore = &TimerLayerChannelStore{ChannelStore: childStore.Channel(), Root: &newStore}
 	newStore.ChannelBookmarkStore = &TimerLayerChannelBookmarkStore{ChannelBookmarkStore: childStore.ChannelBookmark(), Root: &newStore}
+	newStore.ChannelEmailAddressStore = &TimerLayerChannelEmailAddressStore{ChannelEmailAddressStore: childStore.ChannelEmailAddress(), Root: &newStore}
 	newStore.ChannelMemberHistoryStore = &TimerLayerChannelMemberHistoryStore{ChannelMemberHistoryStore: childStore.ChannelMemberHistory(), Root: &newStore}
 	newStore.ClusterDiscoveryStore = &TimerLayerClusterDiscoveryStore{ClusterDiscoveryStore: childStore.ClusterDiscovery(), Root: &newStore}
 	newStore.CommandStore = &TimerLayerCommandStore{CommandStore: childStore.Command(), Root: &newStore}
 	newStore.CommandWebhookStore = &TimerLayerCommandWebhookStore{CommandWebhookStore: childStore.CommandWebhook(), Root: &newStore}
 	newStore.ComplianceStore = &TimerLayerComplianceStore{ComplianceStore: childStore.Compliance(), Root: &newStore}
+	newStore.CustomProfileAttributeStore = &TimerLayerCustomProfileAttributeStore{CustomProfileAttributeStore: childStore.CustomProfileAttribute(), Root: &newStore}
 	newStore.DesktopTokensStore = &TimerLayerDesktopTokensStore{DesktopTokensStore: childStore.DesktopTokens(), Root: &newStore}
 	newStore.DraftStore = &TimerLayerDraftStore{DraftStore: childStore.Draft(), Root: &newStore}
 	newStore.EmojiStore = &TimerLayerEmojiStore{EmojiStore: childStore.Emoji(), Root: &newStore}
@@ -12155,9 +13421,14 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.JobStore = &TimerLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
 	newStore.LicenseStore = &TimerLayerLicenseStore{LicenseStore: childStore.License(), Root: &newStore}
 	newStore.LinkMetadataStore = &TimerLayerLinkMetadataStore{LinkMetadataStore: childStore.LinkMetadata(), Root: &newStore}
+	newStore.LoginDeviceStore = &TimerLayerLoginDeviceStore{LoginDeviceStore: childStore.LoginDevice(), Root: &newStore}
+	newStore.MfaRecoveryCodeStore = &TimerLayerMfaRecoveryCodeStore{MfaRecoveryCodeStore: childStore.MfaRecoveryCode(), Root: &newStore}
+	newStore.MfaTrustedDeviceStore = &TimerLayerMfaTrustedDeviceStore{MfaTrustedDeviceStore: childStore.MfaTrustedDevice(), Root: &newStore}
+	newStore.NotificationRuleStore = &TimerLayerNotificationRuleStore{NotificationRuleStore: childStore.NotificationRule(), Root: &newStore}
 	newStore.NotifyAdminStore = &TimerLayerNotifyAdminStore{NotifyAdminStore: childStore.NotifyAdmin(), Root: &newStore}
 	newStore.OAuthStore = &TimerLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
 	newStore.OutgoingOAuthConnectionStore = &TimerLayerOutgoingOAuthConnectionStore{OutgoingOAuthConnectionStore: childStore.OutgoingOAuthConnection(), Root: &newStore}
+	newStore.PasswordHistoryStore = &TimerLayerPasswordHistoryStore{PasswordHistoryStore: childStore.PasswordHistory(), Root: &newStore}
 	newStore.PluginStore = &TimerLayerPluginStore{PluginStore: childStore.Plugin(), Root: &newStore}
 	newStore.PostStore = &TimerLayerPostStore{PostStore: childStore.Post(), Root: &newStore}
 	newStore.PostAcknowledgementStore = &TimerLayerPostAcknowledgementStore{PostAcknowledgementStore: childStore.PostAcknowledgement(), Root: &newStore}
@@ -12182,6 +13453,8 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.UserStore = &TimerLayerUserStore{UserStore: childStore.User(), Root: &newStore}
 	newStore.UserAccessTokenStore = &TimerLayerUserAccessTokenStore{UserAccessTokenStore: childStore.UserAccessToken(), Root: &newStore}
 	newStore.UserTermsOfServiceStore = &TimerLayerUserTermsOfServiceStore{UserTermsOfServiceStore: childStore.UserTermsOfService(), Root: &newStore}
+	newStore.WebAuthnCredentialStore = &TimerLayerWebAuthnCredentialStore{WebAuthnCredentialStore: childStore.WebAuthnCredential(), Root: &newStore}
+	newStore.WebSocketEventStore = &TimerLayerWebSocketEventStore{WebSocketEventStore: childStore.WebSocketEvent(), Root: &newStore}
 	newStore.WebhookStore = &TimerLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
 	return &newStore
 }
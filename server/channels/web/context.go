@@ -663,6 +663,17 @@ func (c *Context) RequireJobId() *Context {
 	return c
 }
 
+func (c *Context) RequireConfigVersionId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.ConfigVersionId) {
+		c.SetInvalidURLParam("config_version_id")
+	}
+	return c
+}
+
 func (c *Context) RequireJobType() *Context {
 	if c.Err != nil {
 		return c
@@ -775,6 +786,39 @@ func (c *Context) RequireInvoiceId() *Context {
 	return c
 }
 
+func (c *Context) RequireNotificationRuleId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.NotificationRuleId) {
+		c.SetInvalidURLParam("rule_id")
+	}
+	return c
+}
+
+func (c *Context) RequireWebAuthnCredentialId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.WebAuthnCredentialId) {
+		c.SetInvalidURLParam("credential_id")
+	}
+	return c
+}
+
+func (c *Context) RequireCustomProfileFieldId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.CustomProfileFieldId) {
+		c.SetInvalidURLParam("field_id")
+	}
+	return c
+}
+
 func (c *Context) GetRemoteID(r *http.Request) string {
 	return r.Header.Get(model.HeaderRemoteclusterId)
 }
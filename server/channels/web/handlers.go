@@ -6,6 +6,7 @@ package web
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,9 +17,9 @@ import (
 	"time"
 
 	"github.com/klauspost/compress/gzhttp"
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	spanlog "github.com/opentracing/opentracing-go/log"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
@@ -88,6 +89,7 @@ type Handler struct {
 	IsLocal                   bool
 	DisableWhenBusy           bool
 	FileAPI                   bool
+	ScimAPI                   bool
 
 	cspShaDirective string
 }
@@ -211,22 +213,22 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if *c.App.Config().ServiceSettings.EnableOpenTracing {
-		span, ctx := tracing.StartRootSpanByContext(context.Background(), "web:ServeHTTP")
-		carrier := opentracing.HTTPHeadersCarrier(r.Header)
-		_ = opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, carrier)
-		ext.HTTPMethod.Set(span, r.Method)
-		ext.HTTPUrl.Set(span, c.AppContext.Path())
-		ext.PeerAddress.Set(span, c.AppContext.IPAddress())
-		span.SetTag("request_id", c.AppContext.RequestId())
-		span.SetTag("user_agent", c.AppContext.UserAgent())
+		// The span continues the trace of the caller when the request propagates a W3C trace context.
+		span, ctx := tracing.StartRootSpanByContext(tracing.ExtractHTTPHeaders(context.Background(), r.Header), "web:"+h.HandlerName, trace.SpanKindServer)
+		span.SetAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(c.AppContext.Path()),
+			semconv.ClientAddress(c.AppContext.IPAddress()),
+			semconv.UserAgentOriginal(c.AppContext.UserAgent()),
+			attribute.String("request_id", c.AppContext.RequestId()),
+		)
 
 		defer func() {
 			if c.Err != nil {
-				span.LogFields(spanlog.Error(c.Err))
-				ext.HTTPStatusCode.Set(span, uint16(c.Err.StatusCode))
-				ext.Error.Set(span, true)
+				tracing.SetError(span, c.Err)
+				span.SetAttributes(semconv.HTTPResponseStatusCode(c.Err.StatusCode))
 			}
-			span.Finish()
+			span.End()
 		}()
 		c.AppContext = c.AppContext.WithContext(ctx)
 
@@ -304,6 +306,8 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		} else if !session.IsOAuth && tokenLocation == app.TokenLocationQueryString {
 			c.Err = model.NewAppError("ServeHTTP", "api.context.token_provided.app_error", nil, "token="+token, http.StatusUnauthorized)
+		} else if scope := session.GetUserAccessTokenScope(); scope != nil && !scope.AllowsIP(c.AppContext.IPAddress()) {
+			c.Err = model.NewAppError("ServeHTTP", "api.context.token_ip_not_allowed.app_error", nil, "ip_addr="+c.AppContext.IPAddress(), http.StatusUnauthorized)
 		} else {
 			c.AppContext = c.AppContext.WithSession(session)
 		}
@@ -398,7 +402,8 @@ func (h Handler) recordMetrics(c *Context, r *http.Request, now time.Time, statu
 	if c.App.Metrics() != nil {
 		c.App.Metrics().IncrementHTTPRequest()
 
-		if r.URL.Path != model.APIURLSuffix+"/websocket" {
+		// The duration of the long-lived event connections isn't meaningful.
+		if !strings.HasPrefix(r.URL.Path, model.APIURLSuffix+"/websocket") {
 			elapsed := float64(time.Since(now)) / float64(time.Second)
 
 			pageLoadContext := r.Header.Get("X-Page-Load-Context")
@@ -447,7 +452,13 @@ func (h Handler) handleContextError(c *Context, w http.ResponseWriter, r *http.R
 		c.Err.Where = ""
 	}
 
-	if IsAPICall(c.App, r) || IsWebhookCall(c.App, r) || IsOAuthAPICall(c.App, r) || r.Header.Get("X-Mobile-App") != "" {
+	if h.ScimAPI {
+		w.Header().Set("Content-Type", model.ScimContentType)
+		w.WriteHeader(c.Err.StatusCode)
+		if err := json.NewEncoder(w).Encode(model.NewScimError(c.Err)); err != nil {
+			c.Logger.Warn("Error while writing response", mlog.Err(err))
+		}
+	} else if IsAPICall(c.App, r) || IsWebhookCall(c.App, r) || IsOAuthAPICall(c.App, r) || r.Header.Get("X-Mobile-App") != "" {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(c.Err.StatusCode)
 		w.Write([]byte(c.Err.ToJSON()))
@@ -598,3 +609,23 @@ func (w *Web) APISessionRequired(h func(*Context, http.ResponseWriter, *http.Req
 	}
 	return handler
 }
+
+// ScimHandler provides a handler for the SCIM provisioning endpoints, which require a session
+// and report their errors in the format of the SCIM protocol.
+func (w *Web) ScimHandler(h func(*Context, http.ResponseWriter, *http.Request)) http.Handler {
+	handler := &Handler{
+		Srv:            w.srv,
+		HandleFunc:     h,
+		HandlerName:    GetHandlerName(h),
+		RequireSession: true,
+		TrustRequester: false,
+		RequireMfa:     true,
+		IsStatic:       false,
+		IsLocal:        false,
+		ScimAPI:        true,
+	}
+	if *w.srv.Config().ServiceSettings.WebserverMode == "gzip" {
+		return gzhttp.GzipHandler(handler)
+	}
+	return handler
+}
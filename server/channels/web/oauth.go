@@ -28,6 +28,9 @@ func (w *Web) InitOAuth() {
 	w.MainRouter.Handle("/oauth/authorize", w.APISessionRequired(authorizeOAuthApp)).Methods(http.MethodPost)
 	w.MainRouter.Handle("/oauth/deauthorize", w.APISessionRequired(deauthorizeOAuthApp)).Methods(http.MethodPost)
 	w.MainRouter.Handle("/oauth/access_token", w.APIHandlerTrustRequester(getAccessToken)).Methods(http.MethodPost)
+	w.MainRouter.Handle("/oauth/device_authorization", w.APIHandlerTrustRequester(authorizeOAuthDevice)).Methods(http.MethodPost)
+	w.MainRouter.Handle("/oauth/device", w.APIHandlerTrustRequester(verifyOAuthDevicePage)).Methods(http.MethodGet)
+	w.MainRouter.Handle("/oauth/device", w.APISessionRequired(verifyOAuthDevice)).Methods(http.MethodPost)
 
 	// API version independent OAuth as a client endpoints
 	w.MainRouter.Handle("/oauth/{service:[A-Za-z0-9]+}/complete", w.APIHandler(completeOAuth)).Methods(http.MethodGet)
@@ -119,6 +122,9 @@ func authorizeOAuthPage(c *Context, w http.ResponseWriter, r *http.Request) {
 		RedirectURI:  r.URL.Query().Get("redirect_uri"),
 		Scope:        r.URL.Query().Get("scope"),
 		State:        r.URL.Query().Get("state"),
+
+		CodeChallenge:       r.URL.Query().Get("code_challenge"),
+		CodeChallengeMethod: r.URL.Query().Get("code_challenge_method"),
 	}
 
 	loginHint := r.URL.Query().Get("login_hint")
@@ -205,6 +211,7 @@ func getAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	code := r.FormValue("code")
 	refreshToken := r.FormValue("refresh_token")
+	deviceCode := r.FormValue("device_code")
 
 	grantType := r.FormValue("grant_type")
 	switch grantType {
@@ -218,23 +225,23 @@ func getAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 			c.Err = model.NewAppError("getAccessToken", "api.oauth.get_access_token.missing_refresh_token.app_error", nil, "", http.StatusBadRequest)
 			return
 		}
+	case model.DeviceCodeGrantType:
+		if deviceCode == "" {
+			c.Err = model.NewAppError("getAccessToken", "api.oauth.get_access_token.missing_device_code.app_error", nil, "", http.StatusBadRequest)
+			return
+		}
+	case model.ClientCredentialsGrantType:
 	default:
 		c.Err = model.NewAppError("getAccessToken", "api.oauth.get_access_token.bad_grant.app_error", nil, "", http.StatusBadRequest)
 		return
 	}
 
-	clientId := r.FormValue("client_id")
+	clientId, secret := oauthClientCredentials(r)
 	if !model.IsValidId(clientId) {
 		c.Err = model.NewAppError("getAccessToken", "api.oauth.get_access_token.bad_client_id.app_error", nil, "", http.StatusBadRequest)
 		return
 	}
 
-	secret := r.FormValue("client_secret")
-	if secret == "" {
-		c.Err = model.NewAppError("getAccessToken", "api.oauth.get_access_token.bad_client_secret.app_error", nil, "", http.StatusBadRequest)
-		return
-	}
-
 	redirectURI := r.FormValue("redirect_uri")
 
 	auditRec := c.MakeAuditRecord("getAccessToken", audit.Fail)
@@ -243,7 +250,23 @@ func getAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("client_id", clientId)
 	c.LogAudit("attempt")
 
-	accessRsp, err := c.App.GetOAuthAccessTokenForCodeFlow(c.AppContext, clientId, grantType, redirectURI, code, secret, refreshToken)
+	var accessRsp *model.AccessResponse
+	var err *model.AppError
+	switch grantType {
+	case model.ClientCredentialsGrantType:
+		accessRsp, err = c.App.GetOAuthAccessTokenForClientCredentials(c.AppContext, clientId, secret, r.FormValue("scope"))
+	case model.DeviceCodeGrantType:
+		accessRsp, err = c.App.GetOAuthAccessTokenForDeviceCode(c.AppContext, clientId, secret, deviceCode)
+		if err != nil {
+			// The device polls until the user answers, so these errors are reported as defined by RFC 8628.
+			if errorCode := app.OAuthDeviceCodeErrorCode(err); errorCode != "" {
+				writeOAuthError(c, w, err.StatusCode, errorCode)
+				return
+			}
+		}
+	default:
+		accessRsp, err = c.App.GetOAuthAccessTokenForCodeFlow(c.AppContext, clientId, grantType, redirectURI, code, secret, refreshToken, r.FormValue("code_verifier"))
+	}
 	if err != nil {
 		c.Err = err
 		return
@@ -261,6 +284,113 @@ func getAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// oauthClientCredentials returns the client id and secret sent in the form or, as recommended
+// by RFC 6749, with HTTP basic authentication.
+func oauthClientCredentials(r *http.Request) (string, string) {
+	if clientId, secret, ok := r.BasicAuth(); ok {
+		return clientId, secret
+	}
+	return r.FormValue("client_id"), r.FormValue("client_secret")
+}
+
+func writeOAuthError(c *Context, w http.ResponseWriter, statusCode int, errorCode string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(model.OAuthErrorResponse{ErrorCode: errorCode}); err != nil {
+		c.Logger.Warn("Error writing response", mlog.Err(err))
+	}
+}
+
+func authorizeOAuthDevice(c *Context, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	clientId, secret := oauthClientCredentials(r)
+	if !model.IsValidId(clientId) {
+		c.Err = model.NewAppError("authorizeOAuthDevice", "api.oauth.get_access_token.bad_client_id.app_error", nil, "", http.StatusBadRequest)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("authorizeOAuthDevice", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("client_id", clientId)
+
+	deviceRsp, err := c.App.CreateOAuthDeviceCode(c.AppContext, clientId, secret, r.FormValue("scope"))
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(deviceRsp); err != nil {
+		c.Logger.Warn("Error writing response", mlog.Err(err))
+	}
+}
+
+func verifyOAuthDevicePage(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !*c.App.Config().ServiceSettings.EnableOAuthServiceProvider {
+		err := model.NewAppError("verifyOAuthDevicePage", "api.oauth.authorize_oauth.disabled.app_error", nil, "", http.StatusNotImplemented)
+		utils.RenderWebAppError(c.App.Config(), w, r, err, c.App.AsymmetricSigningKey())
+		return
+	}
+
+	if c.AppContext.Session().UserId == "" {
+		http.Redirect(w, r, c.GetSiteURLHeader()+"/login?redirect_to="+url.QueryEscape(r.RequestURI), http.StatusFound)
+		return
+	}
+
+	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	w.Header().Set("Content-Security-Policy", fmt.Sprintf("frame-ancestors %s", frameAncestors))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, max-age=31556926")
+
+	staticDir, _ := fileutils.FindDir(model.ClientDir)
+	http.ServeFile(w, r, filepath.Join(staticDir, "root.html"))
+}
+
+func verifyOAuthDevice(c *Context, w http.ResponseWriter, r *http.Request) {
+	var verifyRequest struct {
+		UserCode string `json:"user_code"`
+		Allow    bool   `json:"allow"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&verifyRequest); err != nil {
+		c.SetInvalidParamWithErr("user_code", err)
+		return
+	}
+
+	if verifyRequest.UserCode == "" {
+		c.SetInvalidParam("user_code")
+		return
+	}
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("verifyOAuthDevice", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("allow", verifyRequest.Allow)
+
+	if err := c.App.AuthorizeOAuthDeviceCode(c.AppContext, c.AppContext.Session().UserId, verifyRequest.UserCode, verifyRequest.Allow); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	c.LogAudit("success")
+
+	ReturnStatusOK(w)
+}
+
 func completeOAuth(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireService()
 	if c.Err != nil {
@@ -394,6 +394,70 @@ func TestOAuthAccessToken(t *testing.T) {
 	apiClient.ClearOAuthToken()
 }
 
+func TestOAuthDeviceAuthorization(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	oauthApp, appErr := th.App.CreateOAuthApp(&model.OAuthApp{
+		Name:         "TestApp" + model.NewId(),
+		Homepage:     "https://nowhere.com",
+		Description:  "test",
+		CallbackUrls: []string{"https://nowhere.com"},
+		CreatorId:    th.SystemAdminUser.Id,
+		IsPublic:     true,
+	})
+	require.Nil(t, appErr)
+
+	th.Logout(apiClient)
+
+	_, _, err := apiClient.AuthorizeOAuthDevice(context.Background(), model.NewId(), "")
+	require.Error(t, err, "should have failed - unknown client id")
+
+	deviceRsp, _, err := apiClient.AuthorizeOAuthDevice(context.Background(), oauthApp.Id, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, deviceRsp.DeviceCode)
+	require.NotEmpty(t, deviceRsp.UserCode)
+
+	_, _, err = apiClient.GetOAuthDeviceAccessToken(context.Background(), oauthApp.Id, deviceRsp.DeviceCode)
+	var oauthErr *model.OAuthErrorResponse
+	require.ErrorAs(t, err, &oauthErr)
+	require.Equal(t, model.OAuthErrorAuthorizationPending, oauthErr.ErrorCode)
+
+	resp, err := apiClient.VerifyOAuthDevice(context.Background(), deviceRsp.UserCode, true)
+	require.Error(t, err, "should have failed - not logged in")
+	CheckUnauthorizedStatus(t, resp)
+
+	th.Login(apiClient, th.BasicUser)
+
+	app, _, err := apiClient.GetOAuthAppForDeviceCode(context.Background(), deviceRsp.UserCode)
+	require.NoError(t, err)
+	require.Equal(t, oauthApp.Id, app.Id)
+
+	_, err = apiClient.VerifyOAuthDevice(context.Background(), deviceRsp.UserCode, true)
+	require.NoError(t, err)
+
+	th.Logout(apiClient)
+
+	rsp, _, err := apiClient.GetOAuthDeviceAccessToken(context.Background(), oauthApp.Id, deviceRsp.DeviceCode)
+	require.NoError(t, err)
+	require.NotEmpty(t, rsp.AccessToken)
+
+	apiClient.SetOAuthToken(rsp.AccessToken)
+	_, err = apiClient.DoAPIGet(context.Background(), "/oauth_test", "")
+	require.NoError(t, err)
+	apiClient.ClearOAuthToken()
+
+	_, _, err = apiClient.GetOAuthDeviceAccessToken(context.Background(), oauthApp.Id, deviceRsp.DeviceCode)
+	require.ErrorAs(t, err, &oauthErr)
+	require.Equal(t, model.OAuthErrorInvalidGrant, oauthErr.ErrorCode)
+}
+
 func TestMobileLoginWithOAuth(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
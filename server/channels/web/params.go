@@ -52,6 +52,7 @@ type Params struct {
 	Category                  string
 	Service                   string
 	JobId                     string
+	ConfigVersionId           string
 	JobType                   string
 	ActionId                  string
 	RoleId                    string
@@ -109,6 +110,12 @@ type Params struct {
 
 	// Cloud
 	InvoiceId string
+
+	NotificationRuleId string
+
+	WebAuthnCredentialId string
+
+	CustomProfileFieldId string
 }
 
 func ParamsFromRequest(r *http.Request) *Params {
@@ -154,6 +161,7 @@ func ParamsFromRequest(r *http.Request) *Params {
 	params.PreferenceName = props["preference_name"]
 	params.EmojiName = props["emoji_name"]
 	params.JobId = props["job_id"]
+	params.ConfigVersionId = props["config_version_id"]
 	params.JobType = props["job_type"]
 	params.ActionId = props["action_id"]
 	params.RoleId = props["role_id"]
@@ -174,6 +182,9 @@ func ParamsFromRequest(r *http.Request) *Params {
 	params.ExcludeHome, _ = strconv.ParseBool(query.Get("exclude_home"))
 	params.ExcludeRemote, _ = strconv.ParseBool(query.Get("exclude_remote"))
 	params.ChannelBookmarkId = props["bookmark_id"]
+	params.NotificationRuleId = props["rule_id"]
+	params.WebAuthnCredentialId = props["credential_id"]
+	params.CustomProfileFieldId = props["field_id"]
 	params.Scope = query.Get("scope")
 
 	if val, err := strconv.Atoi(query.Get("page")); err != nil || val < 0 {
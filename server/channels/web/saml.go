@@ -150,6 +150,10 @@ func completeSaml(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = c.App.SyncSamlCustomProfileAttributes(c.AppContext, user, encodedXML); err != nil {
+		c.Logger.Warn("Failed to synchronize the custom profile attributes from SAML", mlog.String("user_id", user.Id), mlog.Err(err))
+	}
+
 	switch action {
 	case model.OAuthActionSignup:
 		if teamId := relayProps["team_id"]; teamId != "" {
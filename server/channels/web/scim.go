@@ -0,0 +1,389 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+// InitScim registers the SCIM 2.0 endpoints identity providers use to provision users
+// and groups, as described by RFC 7644.
+func (w *Web) InitScim() {
+	scim := w.MainRouter.PathPrefix(model.ScimAPIURLSuffix).Subrouter()
+
+	scim.Handle("/ServiceProviderConfig", w.ScimHandler(getScimServiceProviderConfig)).Methods(http.MethodGet)
+	scim.Handle("/ResourceTypes", w.ScimHandler(getScimResourceTypes)).Methods(http.MethodGet)
+
+	scim.Handle("/Users", w.ScimHandler(getScimUsers)).Methods(http.MethodGet)
+	scim.Handle("/Users", w.ScimHandler(createScimUser)).Methods(http.MethodPost)
+	scim.Handle("/Users/{user_id}", w.ScimHandler(getScimUser)).Methods(http.MethodGet)
+	scim.Handle("/Users/{user_id}", w.ScimHandler(replaceScimUser)).Methods(http.MethodPut)
+	scim.Handle("/Users/{user_id}", w.ScimHandler(patchScimUser)).Methods(http.MethodPatch)
+	scim.Handle("/Users/{user_id}", w.ScimHandler(deleteScimUser)).Methods(http.MethodDelete)
+
+	scim.Handle("/Groups", w.ScimHandler(getScimGroups)).Methods(http.MethodGet)
+	scim.Handle("/Groups", w.ScimHandler(createScimGroup)).Methods(http.MethodPost)
+	scim.Handle("/Groups/{group_id}", w.ScimHandler(getScimGroup)).Methods(http.MethodGet)
+	scim.Handle("/Groups/{group_id}", w.ScimHandler(replaceScimGroup)).Methods(http.MethodPut)
+	scim.Handle("/Groups/{group_id}", w.ScimHandler(patchScimGroup)).Methods(http.MethodPatch)
+	scim.Handle("/Groups/{group_id}", w.ScimHandler(deleteScimGroup)).Methods(http.MethodDelete)
+}
+
+// hasScimAccess checks that provisioning is enabled and that the request is made on
+// behalf of a system admin, usually with a personal access token.
+func hasScimAccess(c *Context) bool {
+	if !*c.App.Config().ScimSettings.Enable {
+		c.Err = model.NewAppError("hasScimAccess", "api.scim.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return false
+	}
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return false
+	}
+
+	return true
+}
+
+func writeScimResponse(c *Context, w http.ResponseWriter, statusCode int, resource any) {
+	w.Header().Set("Content-Type", model.ScimContentType)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(resource); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func decodeScimBody(c *Context, r *http.Request, resource any) bool {
+	if err := json.NewDecoder(r.Body).Decode(resource); err != nil {
+		c.Err = model.NewAppError("decodeScimBody", "model.scim.invalid_syntax.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		return false
+	}
+	return true
+}
+
+// scimListParams returns the filter and the 1-based pagination of a SCIM query.
+func scimListParams(c *Context, r *http.Request) (filter string, startIndex, count int) {
+	query := r.URL.Query()
+
+	startIndex = 1
+	if value := query.Get("startIndex"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.SetInvalidURLParam("startIndex")
+			return
+		}
+		startIndex = max(parsed, 1)
+	}
+
+	count = model.ScimMaxResults
+	if value := query.Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.SetInvalidURLParam("count")
+			return
+		}
+		count = min(max(parsed, 0), model.ScimMaxResults)
+	}
+
+	return query.Get("filter"), startIndex, count
+}
+
+func scimExcludesMembers(r *http.Request) bool {
+	for _, attribute := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+func getScimServiceProviderConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, model.NewScimServiceProviderConfig(c.App.GetSiteURL()))
+}
+
+func getScimResourceTypes(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	resourceTypes := model.NewScimResourceTypes(c.App.GetSiteURL())
+	resources := make([]any, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		resources = append(resources, resourceType)
+	}
+	writeScimResponse(c, w, http.StatusOK, model.NewScimListResponse(resources, len(resources), 1))
+}
+
+func getScimUsers(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	filter, startIndex, count := scimListParams(c, r)
+	if c.Err != nil {
+		return
+	}
+
+	list, appErr := c.App.SearchScimUsers(filter, startIndex, count)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, list)
+}
+
+func getScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	su, appErr := c.App.GetScimUser(c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, su)
+}
+
+func createScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	var su model.ScimUser
+	if !decodeScimBody(c, r, &su) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("external_id", su.ExternalId)
+
+	created, appErr := c.App.CreateScimUser(c.AppContext, &su)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("user_id", created.Id)
+
+	writeScimResponse(c, w, http.StatusCreated, created)
+}
+
+func replaceScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	var su model.ScimUser
+	if !decodeScimBody(c, r, &su) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("replaceScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	updated, appErr := c.App.ReplaceScimUser(c.AppContext, c.Params.UserId, &su)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	writeScimResponse(c, w, http.StatusOK, updated)
+}
+
+func patchScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	var patch model.ScimPatchRequest
+	if !decodeScimBody(c, r, &patch) {
+		return
+	}
+	if c.Err = patch.IsValid(); c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	updated, appErr := c.App.PatchScimUser(c.AppContext, c.Params.UserId, patch.Operations)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	writeScimResponse(c, w, http.StatusOK, updated)
+}
+
+func deleteScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	if appErr := c.App.DeleteScimUser(c.AppContext, c.Params.UserId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getScimGroups(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	filter, startIndex, count := scimListParams(c, r)
+	if c.Err != nil {
+		return
+	}
+
+	list, appErr := c.App.SearchScimGroups(filter, startIndex, count, scimExcludesMembers(r))
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, list)
+}
+
+func getScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	sg, appErr := c.App.GetScimGroup(c.Params.GroupId, scimExcludesMembers(r))
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, sg)
+}
+
+func createScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	var sg model.ScimGroup
+	if !decodeScimBody(c, r, &sg) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("external_id", sg.ExternalId)
+
+	created, appErr := c.App.CreateScimGroup(c.AppContext, &sg)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("group_id", created.Id)
+
+	writeScimResponse(c, w, http.StatusCreated, created)
+}
+
+func replaceScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	var sg model.ScimGroup
+	if !decodeScimBody(c, r, &sg) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("replaceScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("group_id", c.Params.GroupId)
+
+	updated, appErr := c.App.ReplaceScimGroup(c.AppContext, c.Params.GroupId, &sg)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	writeScimResponse(c, w, http.StatusOK, updated)
+}
+
+func patchScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	var patch model.ScimPatchRequest
+	if !decodeScimBody(c, r, &patch) {
+		return
+	}
+	if c.Err = patch.IsValid(); c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("group_id", c.Params.GroupId)
+
+	updated, appErr := c.App.PatchScimGroup(c.AppContext, c.Params.GroupId, patch.Operations)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	writeScimResponse(c, w, http.StatusOK, updated)
+}
+
+func deleteScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !hasScimAccess(c) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("group_id", c.Params.GroupId)
+
+	if appErr := c.App.DeleteScimGroup(c.AppContext, c.Params.GroupId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,351 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// scimComplianceClient sends the requests of the compliance suite as an identity provider
+// would, and checks the protocol requirements every response must meet.
+type scimComplianceClient struct {
+	token string
+}
+
+func (c *scimComplianceClient) do(t *testing.T, method, path string, body any) (int, map[string]any) {
+	t.Helper()
+
+	var payload strings.Builder
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&payload).Encode(body))
+	}
+	req, err := http.NewRequest(method, URL+model.ScimAPIURLSuffix+path, strings.NewReader(payload.String()))
+	require.NoError(t, err)
+	req.Header.Set(model.HeaderAuth, model.HeaderBearer+" "+c.token)
+	req.Header.Set("Content-Type", model.ScimContentType)
+	req.Header.Set("Accept", model.ScimContentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+
+	// RFC 7644, section 3.1: responses use the SCIM media type and carry their schemas.
+	assert.Equal(t, model.ScimContentType, resp.Header.Get("Content-Type"))
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.NotEmpty(t, result["schemas"], "response without schemas")
+
+	// RFC 7644, section 3.12: errors have a string status matching the HTTP status.
+	if resp.StatusCode >= http.StatusBadRequest {
+		assert.Equal(t, []any{model.ScimSchemaError}, result["schemas"])
+		assert.Equal(t, strconv.Itoa(resp.StatusCode), result["status"])
+	}
+	return resp.StatusCode, result
+}
+
+// requireScimResource checks the common attributes of a resource (RFC 7643, section 3.1).
+func requireScimResource(t *testing.T, resource map[string]any, resourceType, schema string) string {
+	t.Helper()
+
+	id, _ := resource["id"].(string)
+	require.NotEmpty(t, id)
+	assert.Contains(t, resource["schemas"], schema)
+
+	meta, ok := resource["meta"].(map[string]any)
+	require.True(t, ok, "resource without meta")
+	assert.Equal(t, resourceType, meta["resourceType"])
+	assert.True(t, strings.HasSuffix(meta["location"].(string), model.ScimAPIURLSuffix+"/"+resourceType+"s/"+id))
+	assert.NotEmpty(t, meta["created"])
+	assert.NotEmpty(t, meta["lastModified"])
+	return id
+}
+
+// list queries resources and checks the list response (RFC 7644, section 3.4.2).
+func (c *scimComplianceClient) list(t *testing.T, path string, totalResults, startIndex int) []map[string]any {
+	t.Helper()
+
+	status, result := c.do(t, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []any{model.ScimSchemaListResponse}, result["schemas"])
+	assert.EqualValues(t, totalResults, result["totalResults"])
+	assert.EqualValues(t, startIndex, result["startIndex"])
+
+	raw, _ := result["Resources"].([]any)
+	assert.EqualValues(t, len(raw), result["itemsPerPage"])
+	resources := make([]map[string]any, 0, len(raw))
+	for _, resource := range raw {
+		resources = append(resources, resource.(map[string]any))
+	}
+	return resources
+}
+
+func scimMemberIDs(group map[string]any) []string {
+	members, _ := group["members"].([]any)
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.(map[string]any)["value"].(string))
+	}
+	return ids
+}
+
+func scimFilterQuery(filter string) string {
+	return "?filter=" + url.QueryEscape(filter)
+}
+
+// TestScimCompliance walks through the requirements of RFC 7643 and RFC 7644 that identity
+// providers rely on when provisioning users and groups. It runs in CI as the SCIM
+// compliance suite, see make test-scim-compliance.
+func TestScimCompliance(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ScimSettings.Enable = true })
+
+	session, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: th.SystemAdminUser.Id, Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId})
+	require.Nil(t, appErr)
+	client := &scimComplianceClient{token: session.Token}
+
+	newUser := func(userName, familyName string) map[string]any {
+		return map[string]any{
+			"schemas":    []string{model.ScimSchemaUser},
+			"userName":   userName,
+			"externalId": "ext-" + userName,
+			"name":       map[string]any{"givenName": "Compliance", "familyName": familyName},
+			"emails":     []map[string]any{{"value": userName, "type": "work", "primary": true}},
+			"active":     true,
+		}
+	}
+	createUser := func(t *testing.T, userName, familyName string) string {
+		t.Helper()
+		status, result := client.do(t, http.MethodPost, "/Users", newUser(userName, familyName))
+		require.Equal(t, http.StatusCreated, status)
+		return requireScimResource(t, result, model.ScimResourceTypeUser, model.ScimSchemaUser)
+	}
+	patchOp := func(operations ...map[string]any) map[string]any {
+		return map[string]any{"schemas": []string{model.ScimSchemaPatchOp}, "Operations": operations}
+	}
+
+	t.Run("discovery", func(t *testing.T) {
+		status, result := client.do(t, http.MethodGet, "/ServiceProviderConfig", nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []any{model.ScimSchemaServiceProviderConfig}, result["schemas"])
+		for _, feature := range []string{"patch", "bulk", "filter", "changePassword", "sort", "etag"} {
+			assert.Contains(t, result[feature], "supported", feature)
+		}
+		assert.NotEmpty(t, result["authenticationSchemes"])
+
+		resourceTypes := client.list(t, "/ResourceTypes", 2, 1)
+		endpoints := []any{}
+		for _, resourceType := range resourceTypes {
+			endpoints = append(endpoints, resourceType["endpoint"])
+		}
+		assert.ElementsMatch(t, []any{"/Users", "/Groups"}, endpoints)
+	})
+
+	familyName := "Compliance" + model.NewId()
+	userIDs := make([]string, 5)
+	for i := range userIDs {
+		userIDs[i] = createUser(t, "compliance"+model.NewId()+"@example.com", familyName)
+	}
+
+	t.Run("users", func(t *testing.T) {
+		userName := "Compliance" + model.NewId() + "@Example.com"
+		id := createUser(t, userName, "Users")
+		defer client.do(t, http.MethodDelete, "/Users/"+id, nil)
+
+		status, result := client.do(t, http.MethodGet, "/Users/"+id, nil)
+		require.Equal(t, http.StatusOK, status)
+		requireScimResource(t, result, model.ScimResourceTypeUser, model.ScimSchemaUser)
+		assert.Equal(t, userName, result["userName"])
+
+		// userName is case insensitive (RFC 7643, section 4.1.1).
+		status, result = client.do(t, http.MethodPost, "/Users", newUser(strings.ToLower(userName), "Users"))
+		require.Equal(t, http.StatusConflict, status)
+		assert.Equal(t, model.ScimErrorTypeUniqueness, result["scimType"])
+
+		resources := client.list(t, "/Users"+scimFilterQuery(`userName eq "`+strings.ToUpper(userName)+`"`), 1, 1)
+		require.Len(t, resources, 1)
+		assert.Equal(t, id, resources[0]["id"])
+
+		client.list(t, "/Users"+scimFilterQuery(`userName eq "missing`+model.NewId()+`"`), 0, 1)
+
+		status, result = client.do(t, http.MethodPatch, "/Users/"+id, patchOp(
+			map[string]any{"op": "replace", "value": map[string]any{"title": "Engineer", "name.givenName": "Patched"}},
+		))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Engineer", result["title"])
+		assert.Equal(t, "Patched", result["name"].(map[string]any)["givenName"])
+
+		status, result = client.do(t, http.MethodPatch, "/Users/"+id, patchOp(
+			map[string]any{"op": "replace", "path": "active", "value": "False"},
+		))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, result["active"])
+
+		status, result = client.do(t, http.MethodPut, "/Users/"+id, newUser(userName, "Replaced"))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Replaced", result["name"].(map[string]any)["familyName"])
+		assert.Equal(t, true, result["active"])
+
+		status, _ = client.do(t, http.MethodGet, "/Users/"+model.NewId(), nil)
+		assert.Equal(t, http.StatusNotFound, status)
+		status, _ = client.do(t, http.MethodPatch, "/Users/"+id, patchOp(map[string]any{"op": "move", "path": "title"}))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("filters and pagination", func(t *testing.T) {
+		filter := scimFilterQuery(`name.familyName eq "` + familyName + `"`)
+
+		all := client.list(t, "/Users"+filter, len(userIDs), 1)
+		ids := []string{}
+		for _, resource := range all {
+			ids = append(ids, resource["id"].(string))
+		}
+		assert.ElementsMatch(t, userIDs, ids)
+
+		// Pages don't overlap and follow the same order.
+		first := client.list(t, "/Users"+filter+"&startIndex=1&count=2", len(userIDs), 1)
+		second := client.list(t, "/Users"+filter+"&startIndex=3&count=2", len(userIDs), 3)
+		require.Len(t, first, 2)
+		require.Len(t, second, 2)
+		assert.Equal(t, []any{all[0]["id"], all[1]["id"], all[2]["id"], all[3]["id"]}, []any{first[0]["id"], first[1]["id"], second[0]["id"], second[1]["id"]})
+
+		// A startIndex below 1 is interpreted as 1 and a count of 0 only returns the total
+		// (RFC 7644, section 3.4.2.4).
+		client.list(t, "/Users"+filter+"&startIndex=0", len(userIDs), 1)
+		assert.Empty(t, client.list(t, "/Users"+filter+"&count=0", len(userIDs), 1))
+
+		for expr, expected := range map[string]int{
+			`name.familyName eq "` + familyName + `" and active eq true`:                                     len(userIDs),
+			`name.familyName eq "` + familyName + `" and not (active eq true)`:                               0,
+			`name.familyName eq "` + familyName + `" and (externalId sw "ext-" or title pr)`:                 len(userIDs),
+			`name.familyName eq "` + familyName + `" and emails[type eq "work" and value ew "@example.com"]`: len(userIDs),
+			`emails.value eq "` + all[0]["userName"].(string) + `"`:                                          1,
+		} {
+			client.list(t, "/Users"+scimFilterQuery(expr), expected, 1)
+		}
+
+		status, result := client.do(t, http.MethodGet, "/Users"+scimFilterQuery(`name.familyName eq`), nil)
+		require.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, model.ScimErrorTypeInvalidFilter, result["scimType"])
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		status, result := client.do(t, http.MethodPost, "/Groups", map[string]any{
+			"schemas":     []string{model.ScimSchemaGroup},
+			"displayName": "Compliance " + familyName,
+			"externalId":  "ext-" + familyName,
+			"members":     []map[string]any{{"value": userIDs[0]}},
+		})
+		require.Equal(t, http.StatusCreated, status)
+		groupID := requireScimResource(t, result, model.ScimResourceTypeGroup, model.ScimSchemaGroup)
+		assert.Equal(t, []string{userIDs[0]}, scimMemberIDs(result))
+
+		resources := client.list(t, "/Groups"+scimFilterQuery(`displayName eq "compliance `+strings.ToLower(familyName)+`"`), 1, 1)
+		require.Len(t, resources, 1)
+		assert.Equal(t, groupID, resources[0]["id"])
+
+		status, result = client.do(t, http.MethodPatch, "/Groups/"+groupID, patchOp(
+			map[string]any{"op": "add", "path": "members", "value": []map[string]any{{"value": userIDs[1]}, {"value": userIDs[2]}}},
+			map[string]any{"op": "remove", "path": `members[value eq "` + userIDs[0] + `"]`},
+		))
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, userIDs[1:3], scimMemberIDs(result))
+
+		// Some providers remove members with a value list instead of a filter.
+		status, result = client.do(t, http.MethodPatch, "/Groups/"+groupID, patchOp(
+			map[string]any{"op": "remove", "path": "members", "value": []map[string]any{{"value": userIDs[1]}}},
+			map[string]any{"op": "replace", "path": "displayName", "value": "Renamed " + familyName},
+		))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{userIDs[2]}, scimMemberIDs(result))
+		assert.Equal(t, "Renamed "+familyName, result["displayName"])
+
+		status, result = client.do(t, http.MethodPatch, "/Groups/"+groupID, patchOp(
+			map[string]any{"op": "replace", "path": "members", "value": []map[string]any{{"value": userIDs[3]}, {"value": userIDs[4]}}},
+		))
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, userIDs[3:], scimMemberIDs(result))
+
+		status, result = client.do(t, http.MethodGet, "/Groups/"+groupID+"?excludedAttributes=members", nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.NotContains(t, result, "members")
+
+		status, result = client.do(t, http.MethodPatch, "/Groups/"+groupID, patchOp(map[string]any{"op": "remove", "path": "members"}))
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, scimMemberIDs(result))
+
+		status, result = client.do(t, http.MethodPut, "/Groups/"+groupID, map[string]any{
+			"schemas":     []string{model.ScimSchemaGroup},
+			"displayName": "Compliance " + familyName,
+			"externalId":  "ext-" + familyName,
+			"members":     []map[string]any{{"value": userIDs[0]}},
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{userIDs[0]}, scimMemberIDs(result))
+
+		status, _ = client.do(t, http.MethodDelete, "/Groups/"+groupID, nil)
+		require.Equal(t, http.StatusNoContent, status)
+		status, _ = client.do(t, http.MethodGet, "/Groups/"+groupID, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("concurrent member changes", func(t *testing.T) {
+		status, result := client.do(t, http.MethodPost, "/Groups", map[string]any{
+			"schemas":     []string{model.ScimSchemaGroup},
+			"displayName": "Concurrent " + familyName,
+		})
+		require.Equal(t, http.StatusCreated, status)
+		groupID := requireScimResource(t, result, model.ScimResourceTypeGroup, model.ScimSchemaGroup)
+		defer client.do(t, http.MethodDelete, "/Groups/"+groupID, nil)
+
+		// Identity providers send a PATCH per assignment, often at the same time, and none
+		// of them may undo the others.
+		var wg sync.WaitGroup
+		for _, userID := range userIDs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				body, err := json.Marshal(patchOp(
+					map[string]any{"op": "add", "path": "members", "value": []map[string]any{{"value": userID}}},
+				))
+				if !assert.NoError(t, err) {
+					return
+				}
+				req, err := http.NewRequest(http.MethodPatch, URL+model.ScimAPIURLSuffix+"/Groups/"+groupID, strings.NewReader(string(body)))
+				if !assert.NoError(t, err) {
+					return
+				}
+				req.Header.Set(model.HeaderAuth, model.HeaderBearer+" "+client.token)
+				req.Header.Set("Content-Type", model.ScimContentType)
+				resp, err := http.DefaultClient.Do(req)
+				if !assert.NoError(t, err) {
+					return
+				}
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}()
+		}
+		wg.Wait()
+
+		status, result = client.do(t, http.MethodGet, "/Groups/"+groupID, nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, userIDs, scimMemberIDs(result))
+	})
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestScim(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ScimSettings.Enable = true })
+
+	adminSession, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: th.SystemAdminUser.Id, Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId})
+	require.Nil(t, appErr)
+	userSession, appErr := th.App.CreateSession(th.Context, &model.Session{UserId: th.BasicUser.Id, Roles: model.SystemUserRoleId})
+	require.Nil(t, appErr)
+
+	doScim := func(t *testing.T, token, method, path string, body any) (*http.Response, map[string]any) {
+		t.Helper()
+
+		var payload strings.Builder
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&payload).Encode(body))
+		}
+		req, err := http.NewRequest(method, URL+model.ScimAPIURLSuffix+path, strings.NewReader(payload.String()))
+		require.NoError(t, err)
+		req.Header.Set(model.HeaderAuth, model.HeaderBearer+" "+token)
+		req.Header.Set("Content-Type", model.ScimContentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result map[string]any
+		if resp.StatusCode != http.StatusNoContent {
+			assert.Equal(t, model.ScimContentType, resp.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		}
+		return resp, result
+	}
+
+	requireScimError := func(t *testing.T, resp *http.Response, result map[string]any, statusCode int, scimType string) {
+		t.Helper()
+
+		require.Equal(t, statusCode, resp.StatusCode)
+		assert.Equal(t, []any{model.ScimSchemaError}, result["schemas"])
+		assert.Equal(t, model.ScimContentType, resp.Header.Get("Content-Type"))
+		if scimType != "" {
+			assert.Equal(t, scimType, result["scimType"])
+		}
+	}
+
+	t.Run("access", func(t *testing.T) {
+		resp, result := doScim(t, "", http.MethodGet, "/Users", nil)
+		requireScimError(t, resp, result, http.StatusUnauthorized, "")
+
+		resp, result = doScim(t, userSession.Token, http.MethodGet, "/Users", nil)
+		requireScimError(t, resp, result, http.StatusForbidden, "")
+
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ScimSettings.Enable = false })
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, "/Users", nil)
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ScimSettings.Enable = true })
+		requireScimError(t, resp, result, http.StatusNotImplemented, "")
+	})
+
+	t.Run("service provider config", func(t *testing.T) {
+		resp, result := doScim(t, adminSession.Token, http.MethodGet, "/ServiceProviderConfig", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, map[string]any{"supported": true}, result["patch"])
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, "/ResourceTypes", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 2, result["totalResults"])
+	})
+
+	var userID string
+	t.Run("provision user", func(t *testing.T) {
+		user := map[string]any{
+			"schemas":    []string{model.ScimSchemaUser},
+			"userName":   "BJensen@example.com",
+			"externalId": "bjensen",
+			"name":       map[string]any{"givenName": "Barbara", "familyName": "Jensen"},
+			"emails":     []map[string]any{{"value": "bjensen@example.com", "type": "work", "primary": true}},
+			"active":     true,
+		}
+
+		resp, result := doScim(t, adminSession.Token, http.MethodPost, "/Users", user)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		userID, _ = result["id"].(string)
+		require.NotEmpty(t, userID)
+		assert.Equal(t, "BJensen@example.com", result["userName"])
+		assert.Equal(t, true, result["active"])
+
+		created, appErr := th.App.GetUser(userID)
+		require.Nil(t, appErr)
+		assert.Equal(t, "bjensen", created.Username)
+		assert.True(t, created.EmailVerified)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPost, "/Users", user)
+		requireScimError(t, resp, result, http.StatusConflict, model.ScimErrorTypeUniqueness)
+	})
+
+	t.Run("find user", func(t *testing.T) {
+		resp, result := doScim(t, adminSession.Token, http.MethodGet, `/Users?filter=userName+eq+"bjensen@example.com"`, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 1, result["totalResults"])
+		require.Len(t, result["Resources"], 1)
+		assert.Equal(t, userID, result["Resources"].([]any)[0].(map[string]any)["id"])
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, `/Users?filter=name.familyName+eq+"Jensen"&count=0`, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 1, result["totalResults"])
+		assert.Empty(t, result["Resources"])
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, `/Users?filter=userName+eq`, nil)
+		requireScimError(t, resp, result, http.StatusBadRequest, model.ScimErrorTypeInvalidFilter)
+
+		// Users renamed since they were provisioned are found by the userName they were provisioned with.
+		renamed, appErr := th.App.GetUser(userID)
+		require.Nil(t, appErr)
+		renamed.Username = "barbara"
+		_, appErr = th.App.UpdateUser(th.Context, renamed, false)
+		require.Nil(t, appErr)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, `/Users?filter=userName+eq+"bjensen@example.com"`, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 1, result["totalResults"])
+		require.Len(t, result["Resources"], 1)
+		assert.Equal(t, userID, result["Resources"].([]any)[0].(map[string]any)["id"])
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, "/Users/"+model.NewId(), nil)
+		requireScimError(t, resp, result, http.StatusNotFound, "")
+	})
+
+	t.Run("update user", func(t *testing.T) {
+		resp, result := doScim(t, adminSession.Token, http.MethodPatch, "/Users/"+userID, map[string]any{
+			"schemas": []string{model.ScimSchemaPatchOp},
+			"Operations": []map[string]any{
+				{"op": "replace", "path": "name.familyName", "value": "Smith"},
+				{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "bsmith@example.com"},
+			},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "Smith", result["name"].(map[string]any)["familyName"])
+
+		updated, appErr := th.App.GetUser(userID)
+		require.Nil(t, appErr)
+		assert.Equal(t, "bsmith@example.com", updated.Email)
+		assert.True(t, updated.EmailVerified)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPatch, "/Users/"+userID, map[string]any{
+			"Operations": []map[string]any{{"op": "replace", "path": "active", "value": false}},
+		})
+		requireScimError(t, resp, result, http.StatusBadRequest, model.ScimErrorTypeInvalidSyntax)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPut, "/Users/"+userID, map[string]any{
+			"schemas":  []string{model.ScimSchemaUser},
+			"userName": "bsmith@example.com",
+			"emails":   []map[string]any{{"value": "bsmith@example.com"}},
+			"title":    "Manager",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "Manager", result["title"])
+		assert.Nil(t, result["externalId"])
+
+		updated, appErr = th.App.GetUser(userID)
+		require.Nil(t, appErr)
+		assert.Equal(t, "bsmith", updated.Username)
+		assert.Empty(t, updated.LastName)
+	})
+
+	t.Run("deprovision user", func(t *testing.T) {
+		resp, _ := doScim(t, adminSession.Token, http.MethodDelete, "/Users/"+userID, nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, result := doScim(t, adminSession.Token, http.MethodGet, "/Users/"+userID, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, false, result["active"])
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPatch, "/Users/"+userID, map[string]any{
+			"schemas":    []string{model.ScimSchemaPatchOp},
+			"Operations": []map[string]any{{"op": "replace", "value": map[string]any{"active": true}}},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, true, result["active"])
+	})
+
+	t.Run("provision group", func(t *testing.T) {
+		resp, result := doScim(t, adminSession.Token, http.MethodPost, "/Groups", map[string]any{
+			"schemas":     []string{model.ScimSchemaGroup},
+			"displayName": "Engineering",
+			"externalId":  "engineering",
+			"members":     []map[string]any{{"value": userID}, {"value": userID}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		groupID, _ := result["id"].(string)
+		require.NotEmpty(t, groupID)
+		require.Len(t, result["members"], 1)
+
+		group, appErr := th.App.GetGroup(groupID, nil, nil)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.GroupSourceScim, group.Source)
+		assert.Equal(t, "engineering", group.GetRemoteId())
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPost, "/Groups", map[string]any{
+			"displayName": "Engineering",
+			"externalId":  "engineering",
+		})
+		requireScimError(t, resp, result, http.StatusConflict, model.ScimErrorTypeUniqueness)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPatch, "/Groups/"+groupID, map[string]any{
+			"schemas": []string{model.ScimSchemaPatchOp},
+			"Operations": []map[string]any{
+				{"op": "add", "path": "members", "value": []map[string]any{{"value": th.BasicUser.Id}, {"value": th.BasicUser.Id}}},
+				{"op": "remove", "path": "members[value eq \"" + userID + "\"]"},
+			},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, result["members"], 1)
+		assert.Equal(t, th.BasicUser.Id, result["members"].([]any)[0].(map[string]any)["value"])
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPatch, "/Groups/"+groupID, map[string]any{
+			"schemas":    []string{model.ScimSchemaPatchOp},
+			"Operations": []map[string]any{{"op": "add", "path": "members", "value": []map[string]any{{"value": model.NewId()}}}},
+		})
+		requireScimError(t, resp, result, http.StatusBadRequest, model.ScimErrorTypeInvalidValue)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, `/Groups?filter=displayName+eq+"engineering"&excludedAttributes=members`, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 1, result["totalResults"])
+		require.Len(t, result["Resources"], 1)
+		assert.NotContains(t, result["Resources"].([]any)[0], "members")
+
+		resp, _ = doScim(t, adminSession.Token, http.MethodDelete, "/Groups/"+groupID, nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, result = doScim(t, adminSession.Token, http.MethodGet, "/Groups/"+groupID, nil)
+		requireScimError(t, resp, result, http.StatusNotFound, "")
+
+		resp, result = doScim(t, adminSession.Token, http.MethodPost, "/Groups", map[string]any{
+			"displayName": "Engineering",
+			"externalId":  "engineering",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, groupID, result["id"])
+		assert.Empty(t, result["members"])
+	})
+
+	t.Run("team and channel membership follows the group", func(t *testing.T) {
+		member, appErr := th.App.CreateUser(th.Context, &model.User{Email: model.NewId() + "success+test@simulator.amazonses.com", Password: "passwd1", EmailVerified: true})
+		require.Nil(t, appErr)
+
+		resp, result := doScim(t, adminSession.Token, http.MethodPost, "/Groups", map[string]any{
+			"schemas":     []string{model.ScimSchemaGroup},
+			"displayName": "Support",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		groupID, _ := result["id"].(string)
+		require.NotEmpty(t, groupID)
+
+		team, appErr := th.App.CreateTeam(th.Context, &model.Team{DisplayName: "Support", Name: "z-z-" + model.NewId() + "a", Type: model.TeamOpen, GroupConstrained: model.NewPointer(true)})
+		require.Nil(t, appErr)
+		channel, appErr := th.App.CreateChannel(th.Context, &model.Channel{DisplayName: "Support", Name: "zz" + model.NewId() + "a", Type: model.ChannelTypePrivate, TeamId: team.Id, GroupConstrained: model.NewPointer(true)}, false)
+		require.Nil(t, appErr)
+		_, appErr = th.App.UpsertGroupSyncable(model.NewGroupTeam(groupID, team.Id, true))
+		require.Nil(t, appErr)
+		_, appErr = th.App.UpsertGroupSyncable(model.NewGroupChannel(groupID, channel.Id, true))
+		require.Nil(t, appErr)
+
+		isMember := func() (bool, bool) {
+			teamMember, teamErr := th.App.GetTeamMember(th.Context, team.Id, member.Id)
+			_, channelErr := th.App.GetChannelMember(th.Context, channel.Id, member.Id)
+			return teamErr == nil && teamMember.DeleteAt == 0, channelErr == nil
+		}
+
+		resp, _ = doScim(t, adminSession.Token, http.MethodPatch, "/Groups/"+groupID, map[string]any{
+			"schemas":    []string{model.ScimSchemaPatchOp},
+			"Operations": []map[string]any{{"op": "add", "path": "members", "value": []map[string]any{{"value": member.Id}}}},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Eventually(t, func() bool {
+			inTeam, inChannel := isMember()
+			return inTeam && inChannel
+		}, 5*time.Second, 50*time.Millisecond)
+
+		resp, _ = doScim(t, adminSession.Token, http.MethodPatch, "/Groups/"+groupID, map[string]any{
+			"schemas":    []string{model.ScimSchemaPatchOp},
+			"Operations": []map[string]any{{"op": "remove", "path": "members[value eq \"" + member.Id + "\"]"}},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Eventually(t, func() bool {
+			inTeam, inChannel := isMember()
+			return !inTeam && !inChannel
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
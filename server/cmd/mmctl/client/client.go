@@ -28,6 +28,13 @@ type Client interface {
 	GetChannelByName(ctx context.Context, channelName, teamID string, etag string) (*model.Channel, *model.Response, error)
 	GetChannelByNameIncludeDeleted(ctx context.Context, channelName, teamID string, etag string) (*model.Channel, *model.Response, error)
 	GetChannel(ctx context.Context, channelID, etag string) (*model.Channel, *model.Response, error)
+	GetChannelEmailAddress(ctx context.Context, channelId string) (*model.ChannelEmailAddress, *model.Response, error)
+	CreateChannelEmailAddress(ctx context.Context, channelId string, patch *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.Response, error)
+	PatchChannelEmailAddress(ctx context.Context, channelId string, patch *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.Response, error)
+	RegenerateChannelEmailAddress(ctx context.Context, channelId string) (*model.ChannelEmailAddress, *model.Response, error)
+	DeleteChannelEmailAddress(ctx context.Context, channelId string) (*model.Response, error)
+	SearchAuditRecords(ctx context.Context, opts model.AuditRecordSearchOptions) (*model.AuditRecordList, *model.Response, error)
+	ExportAuditRecords(ctx context.Context, opts model.AuditRecordSearchOptions, format string, wr io.Writer) (int64, *model.Response, error)
 	GetTeam(ctx context.Context, teamID, etag string) (*model.Team, *model.Response, error)
 	GetTeamByName(ctx context.Context, name, etag string) (*model.Team, *model.Response, error)
 	GetAllTeams(ctx context.Context, etag string, page int, perPage int) ([]*model.Team, *model.Response, error)
@@ -78,6 +85,7 @@ type Client interface {
 	UpdateUserPassword(ctx context.Context, userID, currentPassword, newPassword string) (*model.Response, error)
 	UpdateUserHashedPassword(ctx context.Context, userID, newHashedPassword string) (*model.Response, error)
 	CreateUserAccessToken(ctx context.Context, userID, description string) (*model.UserAccessToken, *model.Response, error)
+	CreateScopedUserAccessToken(ctx context.Context, userID string, token *model.UserAccessToken) (*model.UserAccessToken, *model.Response, error)
 	RevokeUserAccessToken(ctx context.Context, tokenID string) (*model.Response, error)
 	GetUserAccessTokensForUser(ctx context.Context, userID string, page, perPage int) ([]*model.UserAccessToken, *model.Response, error)
 	ConvertUserToBot(ctx context.Context, userID string) (*model.Bot, *model.Response, error)
@@ -96,6 +104,9 @@ type Client interface {
 	PatchConfig(context.Context, *model.Config) (*model.Config, *model.Response, error)
 	ReloadConfig(ctx context.Context) (*model.Response, error)
 	MigrateConfig(ctx context.Context, from, to string) (*model.Response, error)
+	GetConfigHistory(ctx context.Context, page, perPage int) ([]*model.ConfigVersion, *model.Response, error)
+	GetConfigVersionDiff(ctx context.Context, fromVersionID, toVersionID string) ([]*model.ConfigVersionDiff, *model.Response, error)
+	RollbackConfig(ctx context.Context, versionID string) (*model.Response, error)
 	SyncLdap(ctx context.Context, includeRemovedMembers bool) (*model.Response, error)
 	MigrateIdLdap(ctx context.Context, toAttribute string) (*model.Response, error)
 	GetUsers(ctx context.Context, page, perPage int, etag string) ([]*model.User, *model.Response, error)
@@ -113,6 +124,7 @@ type Client interface {
 	SetServerBusy(ctx context.Context, secs int) (*model.Response, error)
 	ClearServerBusy(ctx context.Context) (*model.Response, error)
 	GetServerBusy(ctx context.Context) (*model.ServerBusyState, *model.Response, error)
+	GetStorageUsageByTier(ctx context.Context) ([]*model.FileTierUsage, *model.Response, error)
 	CheckIntegrity(ctx context.Context) ([]model.IntegrityCheckResult, *model.Response, error)
 	InstallPluginFromURL(context.Context, string, bool) (*model.Manifest, *model.Response, error)
 	InstallMarketplacePlugin(context.Context, *model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response, error)
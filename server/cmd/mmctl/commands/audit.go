@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const auditVerifyTemplate = `{{range .Chains}}Chain {{.ID}}: records {{.FirstSeq}} to {{.LastSeq}}, {{.Checkpoints}} checkpoints ({{.VerifiedCheckpoints}} verified), {{.UncheckpointedRecords}} records after the last checkpoint
+{{end}}{{if .UnchainedRecords}}{{.UnchainedRecords}} records are not hash chained
+{{end}}{{range .Issues}}Line {{.Line}}: {{if .ChainID}}chain {{.ChainID}}, record {{.Sequence}}: {{end}}{{.Message}}
+{{end}}`
+
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Management of audit logs",
+}
+
+var AuditVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Verify the hash chains of an audit log",
+	Long: `Verify that the records of an audit log written by a JSON log target with hash chaining enabled were not modified, removed or reordered. Each hash chain must start with its first record or with a signed checkpoint, so a log rotated out must be verified along with the following ones from its last checkpoint. Use "-" to read the log from the standard input, e.g. to verify rotated log files together.
+
+The signatures of the checkpoints are verified with the public key of the private key configured in the ExperimentalAuditSettings.CheckpointSigningKeyFile setting of the server, e.g. extracted with "openssl ec -in key.pem -pubout". The public key should be obtained from whoever holds the signing key rather than from the server.`,
+	Example: `  audit verify audit.log --public-key-file audit-signing.pub
+  audit verify audit.log --public-key MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE...
+  cat audit.2024-01-01.log audit.log | mmctl audit verify -`,
+	Args: cobra.ExactArgs(1),
+	RunE: auditVerifyCmdF,
+}
+
+var AuditSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search the audit records",
+	Long:  "Search the audit records stored in the database, most recent first. The records are stored in the database when the ExperimentalAuditSettings.EnableDatabaseStorage setting is enabled.",
+	Example: `  audit search --user john --since 2024-01-01T00:00:00+00:00
+  audit search --event login --status fail --all
+  audit search --ip 10.0.0.1 --export csv --output audit.csv`,
+	Args: cobra.NoArgs,
+	RunE: withClient(auditSearchCmdF),
+}
+
+func init() {
+	AuditSearchCmd.Flags().String("user", "", "Username, email or ID of the user who triggered the events.")
+	AuditSearchCmd.Flags().String("event", "", "Name of the events, e.g. \"login\".")
+	AuditSearchCmd.Flags().String("ip", "", "IP address the events were triggered from.")
+	AuditSearchCmd.Flags().String("object-type", "", "Type of the objects modified by the events, e.g. \"user\".")
+	AuditSearchCmd.Flags().String("status", "", "Status of the events, e.g. \"success\" or \"fail\".")
+	AuditSearchCmd.Flags().String("since", "", "List the events triggered at or after this time (ISO 8601).")
+	AuditSearchCmd.Flags().String("until", "", "List the events triggered at or before this time (ISO 8601).")
+	AuditSearchCmd.Flags().Int("per-page", model.AuditRecordSearchDefaultLimit, "Number of records to retrieve per page.")
+	AuditSearchCmd.Flags().String("cursor", "", "Cursor of the page to retrieve, as printed after the previous page.")
+	AuditSearchCmd.Flags().Bool("all", false, "Retrieve all the pages of records.")
+	AuditSearchCmd.Flags().String("export", "", "Export all the records in the \"csv\" or \"jsonl\" format instead of printing them.")
+	AuditSearchCmd.Flags().String("output", "", "File to write the export to. The export is written to the standard output if omitted.")
+
+	AuditVerifyCmd.Flags().String("public-key", "", "Public key verifying the signature of the checkpoints, base64 DER or PEM encoded. The signatures are not verified if omitted.")
+	AuditVerifyCmd.Flags().String("public-key-file", "", "File containing the public key verifying the signature of the checkpoints, base64 DER or PEM encoded.")
+	AuditVerifyCmd.MarkFlagsMutuallyExclusive("public-key", "public-key-file")
+
+	AuditCmd.AddCommand(
+		AuditSearchCmd,
+		AuditVerifyCmd,
+	)
+
+	RootCmd.AddCommand(AuditCmd)
+}
+
+func auditSearchOptionsFromFlags(c client.Client, cmd *cobra.Command) (model.AuditRecordSearchOptions, error) {
+	opts := model.AuditRecordSearchOptions{}
+	opts.EventName, _ = cmd.Flags().GetString("event")
+	opts.IpAddress, _ = cmd.Flags().GetString("ip")
+	opts.ObjectType, _ = cmd.Flags().GetString("object-type")
+	opts.Status, _ = cmd.Flags().GetString("status")
+	opts.Limit, _ = cmd.Flags().GetInt("per-page")
+
+	if userArg, _ := cmd.Flags().GetString("user"); userArg != "" {
+		user := getUserFromUserArg(c, userArg)
+		if user == nil {
+			return opts, fmt.Errorf("unable to find user %q", userArg)
+		}
+		opts.UserId = user.Id
+	}
+
+	for flag, value := range map[string]*int64{"since": &opts.StartTime, "until": &opts.EndTime} {
+		if s, _ := cmd.Flags().GetString(flag); s != "" {
+			t, err := time.Parse(ISO8601Layout, s)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s time %q", flag, s)
+			}
+			*value = model.GetMillisForTime(t)
+		}
+	}
+
+	cursor, _ := cmd.Flags().GetString("cursor")
+	var err error
+	if opts.Cursor, err = model.DecodeAuditRecordCursor(cursor); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+func auditSearchCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	opts, err := auditSearchOptionsFromFlags(c, cmd)
+	if err != nil {
+		return err
+	}
+
+	if format, _ := cmd.Flags().GetString("export"); format != "" {
+		return exportAuditRecords(c, cmd, opts, format)
+	}
+
+	all, _ := cmd.Flags().GetBool("all")
+	for {
+		list, _, err := c.SearchAuditRecords(context.TODO(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to search audit records: %w", err)
+		}
+
+		for _, record := range list.Records {
+			printer.PrintT(fmt.Sprintf("{{.Id}}: {{.EventName}} ({{.Status}}) at %s by {{if .UserId}}{{.UserId}}{{else}}unknown{{end}}{{if .IpAddress}} from {{.IpAddress}}{{end}}{{if .ObjectType}} on {{.ObjectType}}{{end}}",
+				time.UnixMilli(record.CreateAt).Format(ISO8601Layout)), record)
+		}
+
+		if list.NextCursor == "" {
+			return nil
+		}
+		if !all {
+			printer.PrintWarning(fmt.Sprintf("There are more records, use --cursor %s to retrieve them.", list.NextCursor))
+			return nil
+		}
+		if opts.Cursor, err = model.DecodeAuditRecordCursor(list.NextCursor); err != nil {
+			return err
+		}
+	}
+}
+
+func exportAuditRecords(c client.Client, cmd *cobra.Command, opts model.AuditRecordSearchOptions, format string) error {
+	if format != model.AuditRecordExportFormatCSV && format != model.AuditRecordExportFormatJSONL {
+		return fmt.Errorf("invalid export format %q, expected %q or %q", format, model.AuditRecordExportFormatCSV, model.AuditRecordExportFormatJSONL)
+	}
+
+	var writer io.Writer = os.Stdout
+	if output, _ := cmd.Flags().GetString("output"); output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return errors.Wrap(err, "failed to create export file")
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if _, _, err := c.ExportAuditRecords(context.TODO(), opts, format, writer); err != nil {
+		return fmt.Errorf("failed to export audit records: %w", err)
+	}
+	return nil
+}
+
+func auditVerifyCmdF(cmd *cobra.Command, args []string) error {
+	key, _ := cmd.Flags().GetString("public-key")
+	if keyFile, _ := cmd.Flags().GetString("public-key-file"); keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read public key file")
+		}
+		key = string(data)
+	}
+
+	var publicKey *ecdsa.PublicKey
+	if key != "" {
+		var err error
+		if publicKey, err = audit.ParsePublicKey(key); err != nil {
+			return errors.Wrap(err, "invalid public key")
+		}
+	}
+
+	var reader io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to open audit log")
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	result, err := audit.Verify(reader, publicKey)
+	if err != nil {
+		return err
+	}
+
+	if publicKey == nil {
+		printer.PrintWarning("The signatures of the checkpoints were not verified, use --public-key or --public-key-file to verify them.")
+	}
+	printer.PrintT(auditVerifyTemplate, result)
+
+	if !result.IsValid() {
+		return fmt.Errorf("audit log verification failed: %d issues found", len(result.Issues))
+	}
+	return nil
+}
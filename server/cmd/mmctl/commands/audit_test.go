@@ -0,0 +1,261 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func (s *MmctlUnitTestSuite) TestAuditVerifyCmd() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	s.Require().NoError(err)
+	publicKey := base64.StdEncoding.EncodeToString(der)
+
+	filePath := filepath.Join(s.T().TempDir(), "audit.log")
+	adt := &audit.Audit{}
+	adt.Init(audit.DefMaxQueueSize)
+	s.Require().NoError(adt.Configure(mlog.LoggerConfiguration{
+		"file": {
+			Type:    "file",
+			Format:  "json",
+			Levels:  []mlog.Level{mlog.LvlAuditAPI},
+			Options: json.RawMessage(fmt.Sprintf(`{"filename": %q}`, filePath)),
+		},
+	}))
+	adt.SetChain(audit.NewChain(2, func() *ecdsa.PrivateKey { return key }))
+	for i := 0; i < 3; i++ {
+		adt.LogRecord(mlog.LvlAuditAPI, audit.Record{EventName: "login", Status: audit.Success})
+	}
+	s.Require().NoError(adt.Shutdown())
+
+	newCmd := func(publicKey string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("public-key", publicKey, "")
+		cmd.Flags().String("public-key-file", "", "")
+		return cmd
+	}
+
+	s.Run("valid audit log", func() {
+		printer.Clean()
+
+		err := auditVerifyCmdF(newCmd(publicKey), []string{filePath})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		result := printer.GetLines()[0].(*audit.VerifyResult)
+		s.Require().Len(result.Chains, 1)
+		s.Equal(5, result.Chains[0].Records)
+		s.Equal(2, result.Chains[0].VerifiedCheckpoints)
+		s.Empty(printer.GetErrorLines())
+	})
+
+	s.Run("public key file", func() {
+		printer.Clean()
+
+		keyFile := filepath.Join(s.T().TempDir(), "audit.pub")
+		s.Require().NoError(os.WriteFile(keyFile, []byte(publicKey), 0600))
+		cmd := newCmd("")
+		s.Require().NoError(cmd.Flags().Set("public-key-file", keyFile))
+
+		err := auditVerifyCmdF(cmd, []string{filePath})
+		s.Require().NoError(err)
+		result := printer.GetLines()[0].(*audit.VerifyResult)
+		s.Equal(2, result.Chains[0].VerifiedCheckpoints)
+	})
+
+	s.Run("without public key", func() {
+		printer.Clean()
+
+		err := auditVerifyCmdF(newCmd(""), []string{filePath})
+		s.Require().NoError(err)
+		result := printer.GetLines()[0].(*audit.VerifyResult)
+		s.Equal(0, result.Chains[0].VerifiedCheckpoints)
+	})
+
+	s.Run("modified audit log", func() {
+		printer.Clean()
+
+		data, err := os.ReadFile(filePath)
+		s.Require().NoError(err)
+		modifiedPath := filepath.Join(s.T().TempDir(), "modified.log")
+		s.Require().NoError(os.WriteFile(modifiedPath, []byte(strings.Replace(string(data), `"login"`, `"logout"`, 1)), 0600))
+
+		err = auditVerifyCmdF(newCmd(publicKey), []string{modifiedPath})
+		s.Require().EqualError(err, "audit log verification failed: 1 issues found")
+		result := printer.GetLines()[0].(*audit.VerifyResult)
+		s.Equal(1, result.Issues[0].Line)
+	})
+
+	s.Run("invalid public key", func() {
+		printer.Clean()
+
+		err := auditVerifyCmdF(newCmd("invalid"), []string{filePath})
+		s.Require().ErrorContains(err, "invalid public key")
+	})
+
+	s.Run("missing file", func() {
+		printer.Clean()
+
+		err := auditVerifyCmdF(newCmd(""), []string{filepath.Join(s.T().TempDir(), "missing.log")})
+		s.Require().ErrorContains(err, "failed to open audit log")
+	})
+}
+
+func newAuditSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	for _, flag := range []string{"user", "event", "ip", "object-type", "status", "since", "until", "cursor", "export", "output"} {
+		cmd.Flags().String(flag, "", "")
+	}
+	cmd.Flags().Int("per-page", model.AuditRecordSearchDefaultLimit, "")
+	cmd.Flags().Bool("all", false, "")
+	return cmd
+}
+
+func (s *MmctlUnitTestSuite) TestAuditSearchCmd() {
+	user := &model.User{Id: model.NewId(), Username: "john"}
+	records := []*model.AuditRecord{
+		{Id: model.NewId(), CreateAt: 2000, EventName: "login", Status: "success", UserId: user.Id, IpAddress: "10.0.0.1"},
+		{Id: model.NewId(), CreateAt: 1000, EventName: "login", Status: "fail", UserId: user.Id},
+	}
+	nextCursor := model.AuditRecordCursor{CreateAt: records[0].CreateAt, Id: records[0].Id}.Encode()
+
+	s.Run("search with filters", func() {
+		printer.Clean()
+		cmd := newAuditSearchCmd()
+		s.Require().NoError(cmd.Flags().Set("user", "john"))
+		s.Require().NoError(cmd.Flags().Set("event", "login"))
+		s.Require().NoError(cmd.Flags().Set("since", "2024-01-01T00:00:00+00:00"))
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), "john", "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), "john", "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), model.AuditRecordSearchOptions{
+				UserId:    user.Id,
+				EventName: "login",
+				StartTime: 1704067200000,
+				Limit:     model.AuditRecordSearchDefaultLimit,
+			}).
+			Return(&model.AuditRecordList{Records: records}, &model.Response{}, nil).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(records[0], printer.GetLines()[0])
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("search all the pages", func() {
+		printer.Clean()
+		cmd := newAuditSearchCmd()
+		s.Require().NoError(cmd.Flags().Set("all", "true"))
+		s.Require().NoError(cmd.Flags().Set("per-page", "1"))
+
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), model.AuditRecordSearchOptions{Limit: 1}).
+			Return(&model.AuditRecordList{Records: records[:1], NextCursor: nextCursor}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), model.AuditRecordSearchOptions{
+				Limit:  1,
+				Cursor: model.AuditRecordCursor{CreateAt: records[0].CreateAt, Id: records[0].Id},
+			}).
+			Return(&model.AuditRecordList{Records: records[1:]}, &model.Response{}, nil).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(records[1], printer.GetLines()[1])
+	})
+
+	s.Run("search fails", func() {
+		printer.Clean()
+		cmd := newAuditSearchCmd()
+
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), model.AuditRecordSearchOptions{Limit: model.AuditRecordSearchDefaultLimit}).
+			Return(nil, &model.Response{}, errors.New("storage disabled")).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "failed to search audit records: storage disabled")
+		s.Require().Len(printer.GetLines(), 0)
+	})
+
+	s.Run("invalid time", func() {
+		printer.Clean()
+		cmd := newAuditSearchCmd()
+		s.Require().NoError(cmd.Flags().Set("until", "yesterday"))
+
+		err := auditSearchCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, `invalid until time "yesterday"`)
+	})
+
+	s.Run("export to a file", func() {
+		printer.Clean()
+		output := filepath.Join(s.T().TempDir(), "audit.csv")
+		cmd := newAuditSearchCmd()
+		s.Require().NoError(cmd.Flags().Set("export", "csv"))
+		s.Require().NoError(cmd.Flags().Set("output", output))
+
+		s.client.
+			EXPECT().
+			ExportAuditRecords(context.TODO(), model.AuditRecordSearchOptions{Limit: model.AuditRecordSearchDefaultLimit}, model.AuditRecordExportFormatCSV, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ model.AuditRecordSearchOptions, _ string, wr io.Writer) (int64, *model.Response, error) {
+				n, err := io.Copy(wr, bytes.NewBufferString("id,create_at\n"))
+				return n, &model.Response{}, err
+			}).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		data, err := os.ReadFile(output)
+		s.Require().NoError(err)
+		s.Require().Equal("id,create_at\n", string(data))
+	})
+
+	s.Run("invalid export format", func() {
+		printer.Clean()
+		cmd := newAuditSearchCmd()
+		s.Require().NoError(cmd.Flags().Set("export", "xml"))
+
+		err := auditSearchCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, `invalid export format "xml", expected "csv" or "jsonl"`)
+	})
+}
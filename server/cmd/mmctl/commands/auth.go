@@ -34,7 +34,8 @@ var LoginCmd = &cobra.Command{
 	Example: `  auth login https://mattermost.example.com
   auth login https://mattermost.example.com --name local-server --username sysadmin --password-file mysupersecret.txt
   auth login https://mattermost.example.com --name local-server --username sysadmin --password-file mysupersecret.txt --mfa-token 123456
-  auth login https://mattermost.example.com --name local-server --access-token myaccesstoken`,
+  auth login https://mattermost.example.com --name local-server --access-token myaccesstoken
+  auth login https://mattermost.example.com --name local-server --oauth-client-id myoauthclientid`,
 	Args: cobra.ExactArgs(1),
 	RunE: loginCmdF,
 }
@@ -101,6 +102,7 @@ func init() {
 	_ = LoginCmd.Flags().MarkHidden("password")
 	LoginCmd.Flags().StringP("password-file", "f", "", "Password file to be read for the credentials")
 	LoginCmd.Flags().Bool("no-activate", false, "If present, it won't activate the credentials after login")
+	LoginCmd.Flags().String("oauth-client-id", "", "OAuth 2.0 app to log in with from a browser instead of username/password, for servers using single sign-on")
 
 	RenewCmd.Flags().StringP("password", "p", "", "Password for the credentials")
 	_ = RenewCmd.Flags().MarkHidden("password")
@@ -161,6 +163,11 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	oauthClientID, err := cmd.Flags().GetString("oauth-client-id")
+	if err != nil {
+		return err
+	}
+
 	allowInsecureSHA1 := viper.GetBool("insecure-sha1-intermediate")
 	allowInsecureTLS := viper.GetBool("insecure-tls-version")
 
@@ -183,7 +190,11 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		return errors.New("you must use --access-token or --username, but not both")
 	}
 
-	if accessToken == "" && username == "" {
+	if oauthClientID != "" && (accessToken != "" || username != "") {
+		return errors.New("you must use --oauth-client-id, --access-token or --username, but not several of them")
+	}
+
+	if oauthClientID == "" && accessToken == "" && username == "" {
 		reader := bufio.NewReader(os.Stdin)
 		fmt.Printf("Username: ")
 		username, err = reader.ReadString('\n')
@@ -202,7 +213,19 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		password = stdinPassword
 	}
 
-	if username != "" {
+	if oauthClientID != "" {
+		method = MethodDevice
+		c, _, err := InitClientWithDeviceAuthorization(ctx, oauthClientID, url, allowInsecureSHA1, allowInsecureTLS)
+		if err != nil {
+			return fmt.Errorf("could not initiate client: %w", err)
+		}
+		user, _, err := c.GetMe(ctx, "")
+		if err != nil {
+			return fmt.Errorf("could not get the logged in user: %w", err)
+		}
+		username = user.Username
+		accessToken = c.AuthToken
+	} else if username != "" {
 		var c *model.Client4
 		var err error
 		if mfaToken != "" {
@@ -234,6 +257,9 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		AuthToken:   accessToken,
 		AuthMethod:  method,
 	}
+	if method == MethodDevice {
+		credentials.OAuthClientID = oauthClientID
+	}
 
 	if err := SaveCredentials(credentials); err != nil {
 		return err
@@ -397,6 +423,13 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 		}
 		credentials.AuthToken = c.AuthToken
 
+	case MethodDevice:
+		c, _, err := InitClientWithDeviceAuthorization(ctx, credentials.OAuthClientID, credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
+		if err != nil {
+			return err
+		}
+		credentials.AuthToken = c.AuthToken
+
 	default:
 		return errors.Errorf("invalid auth method %q", credentials.AuthMethod)
 	}
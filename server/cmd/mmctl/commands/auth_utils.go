@@ -21,6 +21,7 @@ const (
 	MethodPassword = "P"
 	MethodToken    = "T"
 	MethodMFA      = "M"
+	MethodDevice   = "D"
 
 	userHomeVar      = "$HOME"
 	configFileName   = "config"
@@ -37,6 +38,8 @@ type Credentials struct {
 	AuthMethod  string `json:"authMethod"`
 	InstanceURL string `json:"instanceUrl"`
 	Active      bool   `json:"active"`
+	// OAuthClientID is the OAuth 2.0 client application used to log in with the device method.
+	OAuthClientID string `json:"oauthClientId,omitempty"`
 }
 
 type CredentialsList map[string]*Credentials
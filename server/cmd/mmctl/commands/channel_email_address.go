@@ -0,0 +1,179 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const channelEmailAddressTemplate = `Address: {{.Address}}
+Allowed senders: {{if .AllowedSenders}}{{range $i, $s := .AllowedSenders}}{{if $i}}, {{end}}{{$s}}{{end}}{{else}}all{{end}}`
+
+var ChannelEmailAddressCmd = &cobra.Command{
+	Use:   "email-address",
+	Short: "Management of channel email addresses",
+	Long:  "Management of the inbound email addresses used to post to channels by email",
+}
+
+var ChannelEmailAddressShowCmd = &cobra.Command{
+	Use:     "show [channel]",
+	Short:   "Show the email address of a channel",
+	Example: "  channel email-address show myteam:mychannel",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(channelEmailAddressShowCmdF),
+}
+
+var ChannelEmailAddressEnableCmd = &cobra.Command{
+	Use:   "enable [channel]",
+	Short: "Enable the email address of a channel",
+	Long:  "Give a channel an email address. The emails sent to it are posted to the channel.",
+	Example: `  channel email-address enable myteam:mychannel
+  channel email-address enable myteam:mychannel --allowed-senders @example.com,jane@example.org`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(channelEmailAddressEnableCmdF),
+}
+
+var ChannelEmailAddressModifyCmd = &cobra.Command{
+	Use:     "modify [channel]",
+	Short:   "Modify the email address of a channel",
+	Example: "  channel email-address modify myteam:mychannel --allowed-senders @example.com",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(channelEmailAddressModifyCmdF),
+}
+
+var ChannelEmailAddressRegenerateCmd = &cobra.Command{
+	Use:     "regenerate [channel]",
+	Short:   "Regenerate the email address of a channel",
+	Long:    "Replace the email address of a channel. The emails sent to the previous address are rejected.",
+	Example: "  channel email-address regenerate myteam:mychannel",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(channelEmailAddressRegenerateCmdF),
+}
+
+var ChannelEmailAddressDisableCmd = &cobra.Command{
+	Use:     "disable [channel]",
+	Short:   "Disable the email address of a channel",
+	Example: "  channel email-address disable myteam:mychannel",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(channelEmailAddressDisableCmdF),
+}
+
+func init() {
+	ChannelEmailAddressEnableCmd.Flags().StringSlice("allowed-senders", nil, "Comma-separated list of the email addresses and domains, e.g. @example.com, allowed to send emails to the channel. All senders are allowed when empty.")
+	ChannelEmailAddressModifyCmd.Flags().StringSlice("allowed-senders", nil, "Comma-separated list of the email addresses and domains, e.g. @example.com, allowed to send emails to the channel. All senders are allowed when empty.")
+	_ = ChannelEmailAddressModifyCmd.MarkFlagRequired("allowed-senders")
+
+	ChannelEmailAddressCmd.AddCommand(
+		ChannelEmailAddressShowCmd,
+		ChannelEmailAddressEnableCmd,
+		ChannelEmailAddressModifyCmd,
+		ChannelEmailAddressRegenerateCmd,
+		ChannelEmailAddressDisableCmd,
+	)
+
+	ChannelCmd.AddCommand(ChannelEmailAddressCmd)
+}
+
+func getChannelEmailAddressPatch(cmd *cobra.Command) (*model.ChannelEmailAddressPatch, error) {
+	patch := &model.ChannelEmailAddressPatch{}
+	if cmd.Flags().Changed("allowed-senders") {
+		allowedSenders, err := cmd.Flags().GetStringSlice("allowed-senders")
+		if err != nil {
+			return nil, err
+		}
+		patch.AllowedSenders = &allowedSenders
+	}
+	return patch, nil
+}
+
+func channelEmailAddressShowCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	address, _, err := c.GetChannelEmailAddress(context.TODO(), channel.Id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the email address of channel %q", args[0])
+	}
+
+	printer.PrintT(channelEmailAddressTemplate, address)
+	return nil
+}
+
+func channelEmailAddressEnableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	patch, err := getChannelEmailAddressPatch(cmd)
+	if err != nil {
+		return err
+	}
+
+	address, _, err := c.CreateChannelEmailAddress(context.TODO(), channel.Id, patch)
+	if err != nil {
+		return errors.Wrapf(err, "unable to enable the email address of channel %q", args[0])
+	}
+
+	printer.PrintT(channelEmailAddressTemplate, address)
+	return nil
+}
+
+func channelEmailAddressModifyCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	patch, err := getChannelEmailAddressPatch(cmd)
+	if err != nil {
+		return err
+	}
+
+	address, _, err := c.PatchChannelEmailAddress(context.TODO(), channel.Id, patch)
+	if err != nil {
+		return errors.Wrapf(err, "unable to modify the email address of channel %q", args[0])
+	}
+
+	printer.PrintT(channelEmailAddressTemplate, address)
+	return nil
+}
+
+func channelEmailAddressRegenerateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	address, _, err := c.RegenerateChannelEmailAddress(context.TODO(), channel.Id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to regenerate the email address of channel %q", args[0])
+	}
+
+	printer.PrintT(channelEmailAddressTemplate, address)
+	return nil
+}
+
+func channelEmailAddressDisableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	channel := getChannelFromChannelArg(c, args[0])
+	if channel == nil {
+		return errors.Errorf("unable to find channel %q", args[0])
+	}
+
+	if _, err := c.DeleteChannelEmailAddress(context.TODO(), channel.Id); err != nil {
+		return errors.Wrapf(err, "unable to disable the email address of channel %q", args[0])
+	}
+
+	printer.PrintT("Email address of channel {{.Name}} disabled", channel)
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/spf13/cobra"
+)
+
+func (s *MmctlUnitTestSuite) TestChannelEmailAddressCmds() {
+	mockChannel := &model.Channel{Id: channelID, Name: channelName}
+	mockAddress := &model.ChannelEmailAddress{
+		ChannelId:      channelID,
+		Address:        "channel+token@example.com",
+		AllowedSenders: model.StringArray{"@example.com", "jane@example.org"},
+	}
+
+	expectChannel := func() {
+		s.client.
+			EXPECT().
+			GetChannel(context.TODO(), channelID, "").
+			Return(mockChannel, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("show", func() {
+		printer.Clean()
+		expectChannel()
+		s.client.
+			EXPECT().
+			GetChannelEmailAddress(context.TODO(), channelID).
+			Return(mockAddress, &model.Response{}, nil).
+			Times(1)
+
+		err := channelEmailAddressShowCmdF(s.client, &cobra.Command{}, []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockAddress, printer.GetLines()[0])
+	})
+
+	s.Run("show unknown channel", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetChannel(context.TODO(), channelID, "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		err := channelEmailAddressShowCmdF(s.client, &cobra.Command{}, []string{channelID})
+		s.Require().EqualError(err, `unable to find channel "`+channelID+`"`)
+		s.Len(printer.GetLines(), 0)
+	})
+
+	s.Run("enable with allowed senders", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("allowed-senders", nil, "")
+		s.Require().NoError(cmd.Flags().Set("allowed-senders", "@example.com,jane@example.org"))
+
+		expectChannel()
+		s.client.
+			EXPECT().
+			CreateChannelEmailAddress(context.TODO(), channelID, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{"@example.com", "jane@example.org"}}).
+			Return(mockAddress, &model.Response{}, nil).
+			Times(1)
+
+		err := channelEmailAddressEnableCmdF(s.client, cmd, []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockAddress, printer.GetLines()[0])
+	})
+
+	s.Run("enable fails", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("allowed-senders", nil, "")
+
+		expectChannel()
+		s.client.
+			EXPECT().
+			CreateChannelEmailAddress(context.TODO(), channelID, &model.ChannelEmailAddressPatch{}).
+			Return(nil, &model.Response{}, errors.New("disabled")).
+			Times(1)
+
+		err := channelEmailAddressEnableCmdF(s.client, cmd, []string{channelID})
+		s.Require().EqualError(err, `unable to enable the email address of channel "`+channelID+`": disabled`)
+		s.Len(printer.GetLines(), 0)
+	})
+
+	s.Run("modify allows all senders", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("allowed-senders", nil, "")
+		s.Require().NoError(cmd.Flags().Set("allowed-senders", ""))
+
+		expectChannel()
+		s.client.
+			EXPECT().
+			PatchChannelEmailAddress(context.TODO(), channelID, &model.ChannelEmailAddressPatch{AllowedSenders: &[]string{}}).
+			Return(mockAddress, &model.Response{}, nil).
+			Times(1)
+
+		err := channelEmailAddressModifyCmdF(s.client, cmd, []string{channelID})
+		s.Require().NoError(err)
+		s.Len(printer.GetLines(), 1)
+	})
+
+	s.Run("regenerate", func() {
+		printer.Clean()
+		expectChannel()
+		s.client.
+			EXPECT().
+			RegenerateChannelEmailAddress(context.TODO(), channelID).
+			Return(mockAddress, &model.Response{}, nil).
+			Times(1)
+
+		err := channelEmailAddressRegenerateCmdF(s.client, &cobra.Command{}, []string{channelID})
+		s.Require().NoError(err)
+		s.Len(printer.GetLines(), 1)
+	})
+
+	s.Run("disable", func() {
+		printer.Clean()
+		expectChannel()
+		s.client.
+			EXPECT().
+			DeleteChannelEmailAddress(context.TODO(), channelID).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := channelEmailAddressDisableCmdF(s.client, &cobra.Command{}, []string{channelID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockChannel, printer.GetLines()[0])
+	})
+}
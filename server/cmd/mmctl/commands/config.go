@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
@@ -104,6 +105,33 @@ var ConfigMigrateCmd = &cobra.Command{
 	RunE:    withClient(configMigrateCmdF),
 }
 
+var ConfigHistoryCmd = &cobra.Command{
+	Use:     "history",
+	Short:   "List past configurations",
+	Long:    "Lists the configurations previously saved by the server, newest first, along with who saved them and the settings they changed. Only available when the configuration is stored in the database.",
+	Example: "config history --per-page 10",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(configHistoryCmdF),
+}
+
+var ConfigDiffCmd = &cobra.Command{
+	Use:     "diff [from_version] [to_version]",
+	Short:   "Show the differences between two configurations",
+	Long:    "Shows the settings that differ between two past configurations. If the second version is omitted, the first one is compared against the active configuration.",
+	Example: "config diff 4xp9fdt77pncbef59f4k1qe83o",
+	Args:    cobra.RangeArgs(1, 2),
+	RunE:    withClient(configDiffCmdF),
+}
+
+var ConfigRollbackCmd = &cobra.Command{
+	Use:     "rollback [version]",
+	Short:   "Restore a past configuration",
+	Long:    "Makes a past configuration the active one again.",
+	Example: "config rollback 4xp9fdt77pncbef59f4k1qe83o",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(configRollbackCmdF),
+}
+
 var ConfigSubpathCmd = &cobra.Command{
 	Use:   "subpath",
 	Short: "Update client asset loading to use the configured subpath",
@@ -123,6 +151,11 @@ var ConfigSubpathCmd = &cobra.Command{
 func init() {
 	ConfigResetCmd.Flags().Bool("confirm", false, "confirm you really want to reset all configuration settings to its default value")
 
+	ConfigHistoryCmd.Flags().Int("page", 0, "Page number to fetch for the list of configurations")
+	ConfigHistoryCmd.Flags().Int("per-page", DefaultPageSize, "Number of configurations to be fetched")
+
+	ConfigRollbackCmd.Flags().Bool("confirm", false, "confirm you really want to restore the configuration")
+
 	ConfigSubpathCmd.Flags().StringP("assets-dir", "a", "", "directory of the Mattermost assets in the local filesystem")
 	_ = ConfigSubpathCmd.MarkFlagRequired("assets-dir")
 	ConfigSubpathCmd.Flags().StringP("path", "p", "", "path to update the assets with")
@@ -137,6 +170,9 @@ func init() {
 		ConfigShowCmd,
 		ConfigReloadCmd,
 		ConfigMigrateCmd,
+		ConfigHistoryCmd,
+		ConfigDiffCmd,
+		ConfigRollbackCmd,
 		ConfigSubpathCmd,
 	)
 	RootCmd.AddCommand(ConfigCmd)
@@ -537,6 +573,75 @@ func configMigrateCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	return nil
 }
 
+func configHistoryCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	page, _ := cmd.Flags().GetInt("page")
+	perPage, _ := cmd.Flags().GetInt("per-page")
+
+	versions, _, err := c.GetConfigHistory(context.TODO(), page, perPage)
+	if err != nil {
+		return fmt.Errorf("failed to get configuration history: %w", err)
+	}
+
+	printer.SetTemplateFunc("join", strings.Join)
+	for _, version := range versions {
+		printer.PrintT(fmt.Sprintf(`{{.Id}}: saved at %s by {{if .CreatedBy}}{{.CreatedBy}}{{else}}unknown{{end}}{{if .Active}} (active){{end}}{{if .ChangedPaths}}
+  Changed: {{join .ChangedPaths ", "}}{{end}}`, time.Unix(version.CreateAt/1000, 0)), version)
+	}
+
+	return nil
+}
+
+func configDiffCmdF(c client.Client, _ *cobra.Command, args []string) error {
+	var toVersion string
+	if len(args) > 1 {
+		toVersion = args[1]
+	}
+
+	diffs, _, err := c.GetConfigVersionDiff(context.TODO(), args[0], toVersion)
+	if err != nil {
+		return fmt.Errorf("failed to diff configurations: %w", err)
+	}
+
+	if len(diffs) == 0 {
+		printer.Print("The configurations are identical")
+		return nil
+	}
+
+	printer.SetTemplateFunc("json", formatDiffValue)
+	for _, diff := range diffs {
+		printer.PrintT("{{.Path}}: {{json .BaseVal}} -> {{json .ActualVal}}", diff)
+	}
+
+	return nil
+}
+
+// formatDiffValue renders a setting value the way it appears in the
+// configuration file.
+func formatDiffValue(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
+
+func configRollbackCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		if err := getConfirmation(fmt.Sprintf("Are you sure you want to restore the configuration %s? (YES/NO): ", args[0]), false); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.RollbackConfig(context.TODO(), args[0]); err != nil {
+		return fmt.Errorf("failed to restore configuration %s: %w", args[0], err)
+	}
+
+	printer.Print(fmt.Sprintf("Configuration %s successfully restored", args[0]))
+
+	return nil
+}
+
 func configSubpathCmdF(cmd *cobra.Command, _ []string) error {
 	assetsDir, _ := cmd.Flags().GetString("assets-dir")
 	path, _ := cmd.Flags().GetString("path")
@@ -893,6 +893,138 @@ func (s *MmctlUnitTestSuite) TestConfigMigrateCmd() {
 	})
 }
 
+func (s *MmctlUnitTestSuite) TestConfigHistoryCmd() {
+	s.Run("Should list the configuration history", func() {
+		printer.Clean()
+
+		versions := []*model.ConfigVersion{
+			{Id: model.NewId(), CreateAt: 2000, CreatedBy: model.NewId(), Active: true, ChangedPaths: []string{"TeamSettings.SiteName", "ServiceSettings.SiteURL"}},
+			{Id: model.NewId(), CreateAt: 1000},
+		}
+
+		s.client.
+			EXPECT().
+			GetConfigHistory(context.TODO(), 0, DefaultPageSize).
+			Return(versions, &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("page", 0, "")
+		cmd.Flags().Int("per-page", DefaultPageSize, "")
+
+		err := configHistoryCmdF(s.client, cmd, []string{})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Equal(versions[0], printer.GetLines()[0])
+		s.Equal(versions[1], printer.GetLines()[1])
+		s.Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Should fail on error when getting the history", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfigHistory(context.TODO(), 0, DefaultPageSize).
+			Return(nil, &model.Response{StatusCode: http.StatusNotImplemented}, errors.New("some-error")).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("page", 0, "")
+		cmd.Flags().Int("per-page", DefaultPageSize, "")
+
+		err := configHistoryCmdF(s.client, cmd, []string{})
+		s.Require().NotNil(err)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestConfigDiffCmd() {
+	fromID := model.NewId()
+	toID := model.NewId()
+
+	s.Run("Should diff against the active configuration", func() {
+		printer.Clean()
+
+		diff := &model.ConfigVersionDiff{Path: "TeamSettings.SiteName", BaseVal: "Old", ActualVal: "New"}
+		s.client.
+			EXPECT().
+			GetConfigVersionDiff(context.TODO(), fromID, "").
+			Return([]*model.ConfigVersionDiff{diff}, &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := configDiffCmdF(s.client, &cobra.Command{}, []string{fromID})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(diff, printer.GetLines()[0])
+	})
+
+	s.Run("Should report identical configurations", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfigVersionDiff(context.TODO(), fromID, toID).
+			Return([]*model.ConfigVersionDiff{}, &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := configDiffCmdF(s.client, &cobra.Command{}, []string{fromID, toID})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal("The configurations are identical", printer.GetLines()[0])
+	})
+
+	s.Run("Should fail on error when diffing", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetConfigVersionDiff(context.TODO(), fromID, toID).
+			Return(nil, &model.Response{StatusCode: http.StatusNotFound}, errors.New("some-error")).
+			Times(1)
+
+		err := configDiffCmdF(s.client, &cobra.Command{}, []string{fromID, toID})
+		s.Require().NotNil(err)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestConfigRollbackCmd() {
+	versionID := model.NewId()
+
+	s.Run("Should rollback the configuration", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			RollbackConfig(context.TODO(), versionID).
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := configRollbackCmdF(s.client, cmd, []string{versionID})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Should fail on error when rolling back", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			RollbackConfig(context.TODO(), versionID).
+			Return(&model.Response{StatusCode: http.StatusNotFound}, errors.New("some-error")).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := configRollbackCmdF(s.client, cmd, []string{versionID})
+		s.Require().NotNil(err)
+	})
+}
+
 func TestCloudRestricted(t *testing.T) {
 	cfg := &model.Config{
 		ServiceSettings: model.ServiceSettings{
@@ -203,6 +203,45 @@ func InitClientWithMFA(ctx context.Context, username, password, mfaToken, instan
 	return client, resp.ServerVersion, nil
 }
 
+// InitClientWithDeviceAuthorization logs in with the OAuth 2.0 device authorization grant of
+// the client application, asking the user to allow the login from a browser.
+func InitClientWithDeviceAuthorization(ctx context.Context, clientID, instanceURL string, allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, string, error) {
+	client := NewAPIv4Client(instanceURL, allowInsecureSHA1, allowInsecureTLS)
+
+	deviceRsp, _, err := client.AuthorizeOAuthDevice(ctx, clientID, "")
+	if err != nil {
+		return nil, "", checkInsecureTLSError(err, allowInsecureTLS)
+	}
+
+	fmt.Printf("To log in, open %s in a browser and enter the code %s\n", deviceRsp.VerificationURI, deviceRsp.UserCode)
+
+	interval := time.Duration(deviceRsp.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(deviceRsp.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-time.After(interval):
+		}
+
+		accessRsp, resp, err := client.GetOAuthDeviceAccessToken(ctx, clientID, deviceRsp.DeviceCode)
+		var oauthErr *model.OAuthErrorResponse
+		switch {
+		case err == nil:
+			client.AuthType = model.HeaderBearer
+			client.AuthToken = accessRsp.AccessToken
+			return client, resp.ServerVersion, nil
+		case errors.As(err, &oauthErr) && oauthErr.ErrorCode == model.OAuthErrorAuthorizationPending:
+		case errors.As(err, &oauthErr) && oauthErr.ErrorCode == model.OAuthErrorSlowDown:
+			interval += 5 * time.Second
+		default:
+			return nil, "", checkInsecureTLSError(err, allowInsecureTLS)
+		}
+	}
+
+	return nil, "", errors.New("the login request expired before it was allowed")
+}
+
 func InitClientWithCredentials(ctx context.Context, credentials *Credentials, allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, string, error) {
 	client := NewAPIv4Client(credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
 
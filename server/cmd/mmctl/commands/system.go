@@ -77,6 +77,15 @@ var SystemSupportPacketCmd = &cobra.Command{
 	RunE:    withClient(systemSupportPacketCmdF),
 }
 
+var SystemStorageUsageCmd = &cobra.Command{
+	Use:     "storageusage",
+	Short:   "Prints the file storage usage per tier",
+	Long:    "Prints the number of files and the storage used by them in each storage tier (hot and cold)",
+	Example: `  system storageusage`,
+	Args:    cobra.NoArgs,
+	RunE:    withClient(systemStorageUsageCmdF),
+}
+
 func init() {
 	SystemSetBusyCmd.Flags().UintP("seconds", "s", 3600, "Number of seconds until server is automatically marked as not busy.")
 	_ = SystemSetBusyCmd.MarkFlagRequired("seconds")
@@ -90,6 +99,7 @@ func init() {
 		SystemVersionCmd,
 		SystemStatusCmd,
 		SystemSupportPacketCmd,
+		SystemStorageUsageCmd,
 	)
 	RootCmd.AddCommand(SystemCmd)
 }
@@ -204,3 +214,15 @@ func systemSupportPacketCmdF(c client.Client, cmd *cobra.Command, _ []string) er
 	printer.PrintT("Downloaded Support Packet to {{ .filename }}", map[string]string{"filename": filename})
 	return nil
 }
+
+func systemStorageUsageCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	usage, _, err := c.GetStorageUsageByTier(context.TODO())
+	if err != nil {
+		return fmt.Errorf("unable to fetch storage usage: %w", err)
+	}
+
+	for _, tierUsage := range usage {
+		printer.PrintT("{{.Tier}}: {{.FileCount}} files, {{.Size}} bytes", tierUsage)
+	}
+	return nil
+}
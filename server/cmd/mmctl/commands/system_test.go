@@ -221,6 +221,44 @@ func (s *MmctlUnitTestSuite) TestServerStatusCmd() {
 	})
 }
 
+func (s *MmctlUnitTestSuite) TestSystemStorageUsageCmd() {
+	s.Run("Print storage usage per tier", func() {
+		printer.Clean()
+
+		usage := []*model.FileTierUsage{
+			{Tier: model.FileTierCold, FileCount: 3, Size: 300},
+			{Tier: model.FileTierHot, FileCount: 5, Size: 500},
+		}
+		s.client.
+			EXPECT().
+			GetStorageUsageByTier(context.TODO()).
+			Return(usage, &model.Response{}, nil).
+			Times(1)
+
+		err := systemStorageUsageCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(usage[0], printer.GetLines()[0])
+		s.Require().Equal(usage[1], printer.GetLines()[1])
+	})
+
+	s.Run("Request to the server fails", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetStorageUsageByTier(context.TODO()).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := systemStorageUsageCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().Error(err)
+		s.Require().Len(printer.GetErrorLines(), 0)
+		s.Require().Len(printer.GetLines(), 0)
+	})
+}
+
 func cleanupSupportPacket(t *testing.T) func() {
 	return func() {
 		entries, err := os.ReadDir(".")
@@ -6,6 +6,9 @@ package commands
 import (
 	"context"
 	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
 
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
@@ -23,7 +26,7 @@ var GenerateUserTokenCmd = &cobra.Command{
 	Use:     "generate [user] [description]",
 	Short:   "Generate token for a user",
 	Long:    "Generate token for a user",
-	Example: "  generate testuser test-token",
+	Example: "  generate testuser test-token\n  generate testuser test-token --permission create_post --channel myteam:mychannel --allowed-ip 10.0.0.0/8 --expires-at 2030-01-01T00:00:00+00:00",
 	RunE:    withClient(generateTokenForAUserCmdF),
 	Args:    cobra.ExactArgs(2),
 }
@@ -47,6 +50,12 @@ var ListUserTokensCmd = &cobra.Command{
 }
 
 func init() {
+	GenerateUserTokenCmd.Flags().StringSlice("permission", []string{}, "Restrict the token to a permission. Can be repeated")
+	GenerateUserTokenCmd.Flags().StringSlice("team", []string{}, "Restrict the token to a team. Can be repeated")
+	GenerateUserTokenCmd.Flags().StringSlice("channel", []string{}, "Restrict the token to a channel, in the team:channel format. Can be repeated")
+	GenerateUserTokenCmd.Flags().StringSlice("allowed-ip", []string{}, "Restrict the token to an IP address or CIDR range. Can be repeated")
+	GenerateUserTokenCmd.Flags().String("expires-at", "", "Expire the token at the given time, in the "+ISO8601Layout+" format")
+
 	ListUserTokensCmd.Flags().Int("page", 0, "Page number to fetch for the list of users")
 	ListUserTokensCmd.Flags().Int("per-page", DefaultPageSize, "Number of users to be fetched")
 	ListUserTokensCmd.Flags().Bool("all", false, "Fetch all tokens. --page flag will be ignore if provided")
@@ -71,7 +80,21 @@ func generateTokenForAUserCmdF(c client.Client, command *cobra.Command, args []s
 		return errors.Errorf("could not retrieve user information of %q", userArg)
 	}
 
-	token, _, err := c.CreateUserAccessToken(context.TODO(), user.Id, args[1])
+	scope, expiresAt, err := getTokenScopeFromFlags(c, command)
+	if err != nil {
+		return err
+	}
+
+	var token *model.UserAccessToken
+	if scope.IsEmpty() && expiresAt == 0 {
+		token, _, err = c.CreateUserAccessToken(context.TODO(), user.Id, args[1])
+	} else {
+		token = &model.UserAccessToken{Description: args[1], ExpiresAt: expiresAt}
+		if !scope.IsEmpty() {
+			token.Scope = scope
+		}
+		token, _, err = c.CreateScopedUserAccessToken(context.TODO(), user.Id, token)
+	}
 	if err != nil {
 		return errors.Errorf("could not create token for %q: %s", userArg, err.Error())
 	}
@@ -80,6 +103,46 @@ func generateTokenForAUserCmdF(c client.Client, command *cobra.Command, args []s
 	return nil
 }
 
+func getTokenScopeFromFlags(c client.Client, command *cobra.Command) (*model.UserAccessTokenScope, int64, error) {
+	permissions, _ := command.Flags().GetStringSlice("permission")
+	teamArgs, _ := command.Flags().GetStringSlice("team")
+	channelArgs, _ := command.Flags().GetStringSlice("channel")
+	allowedIPs, _ := command.Flags().GetStringSlice("allowed-ip")
+	expiresAtArg, _ := command.Flags().GetString("expires-at")
+
+	scope := &model.UserAccessTokenScope{
+		Permissions: permissions,
+		AllowedIPs:  allowedIPs,
+	}
+
+	for _, teamArg := range teamArgs {
+		team := getTeamFromTeamArg(c, teamArg)
+		if team == nil {
+			return nil, 0, errors.Errorf("unable to find team %q", teamArg)
+		}
+		scope.TeamIds = append(scope.TeamIds, team.Id)
+	}
+
+	for _, channelArg := range channelArgs {
+		channel := getChannelFromChannelArg(c, channelArg)
+		if channel == nil {
+			return nil, 0, errors.Errorf("unable to find channel %q", channelArg)
+		}
+		scope.ChannelIds = append(scope.ChannelIds, channel.Id)
+	}
+
+	var expiresAt int64
+	if expiresAtArg != "" {
+		t, err := time.Parse(ISO8601Layout, expiresAtArg)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "invalid expiry time %q", expiresAtArg)
+		}
+		expiresAt = model.GetMillisForTime(t)
+	}
+
+	return scope, expiresAt, nil
+}
+
 func listTokensOfAUserCmdF(c client.Client, command *cobra.Command, args []string) error {
 	page, _ := command.Flags().GetInt("page")
 	perPage, _ := command.Flags().GetInt("per-page")
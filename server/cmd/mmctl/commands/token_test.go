@@ -109,6 +109,90 @@ func (s *MmctlUnitTestSuite) TestGenerateTokenForAUserCmd() {
 		s.Require().NotNil(err)
 		s.Require().Contains(err.Error(), fmt.Sprintf("could not create token for %q:", "user1"))
 	})
+
+	s.Run("Should generate a scoped token for a user", func() {
+		printer.Clean()
+
+		mockUser := model.User{Id: "userId1", Email: "user1@example.com", Username: "user1"}
+		mockTeam := model.Team{Id: "teamId1", Name: "team1"}
+		expectedToken := &model.UserAccessToken{
+			Description: "token-desc",
+			ExpiresAt:   1893456000000,
+			Scope: &model.UserAccessTokenScope{
+				Permissions: []string{"create_post"},
+				TeamIds:     []string{mockTeam.Id},
+				AllowedIPs:  []string{"10.0.0.0/8"},
+			},
+		}
+		mockToken := model.UserAccessToken{Token: "token-id", Description: "token-desc"}
+
+		command := cobra.Command{}
+		command.Flags().StringSlice("permission", []string{"create_post"}, "")
+		command.Flags().StringSlice("team", []string{mockTeam.Name}, "")
+		command.Flags().StringSlice("channel", []string{}, "")
+		command.Flags().StringSlice("allowed-ip", []string{"10.0.0.0/8"}, "")
+		command.Flags().String("expires-at", "2030-01-01T00:00:00+00:00", "")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), mockUser.Username, "").
+			Return(nil, &model.Response{}, errors.New("no user found with the given email")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(&mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(context.TODO(), mockTeam.Name, "").
+			Return(nil, &model.Response{}, errors.New("no team found with the given ID")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName(context.TODO(), mockTeam.Name, "").
+			Return(&mockTeam, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateScopedUserAccessToken(context.TODO(), mockUser.Id, expectedToken).
+			Return(&mockToken, &model.Response{}, nil).
+			Times(1)
+
+		err := generateTokenForAUserCmdF(s.client, &command, []string{mockUser.Username, mockToken.Description})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&mockToken, printer.GetLines()[0])
+	})
+
+	s.Run("Should fail on an invalid expiry time", func() {
+		printer.Clean()
+
+		mockUser := model.User{Id: "userId1", Email: "user1@example.com", Username: "user1"}
+
+		command := cobra.Command{}
+		command.Flags().String("expires-at", "tomorrow", "")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), mockUser.Username, "").
+			Return(nil, &model.Response{}, errors.New("no user found with the given email")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(&mockUser, &model.Response{}, nil).
+			Times(1)
+
+		err := generateTokenForAUserCmdF(s.client, &command, []string{mockUser.Username, "description"})
+		s.Require().NotNil(err)
+		s.Require().Contains(err.Error(), "invalid expiry time")
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestListTokensOfAUserCmdF() {
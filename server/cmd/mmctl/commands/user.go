@@ -132,7 +132,7 @@ var ChangePasswordUserCmd = &cobra.Command{
 var ResetUserMfaCmd = &cobra.Command{
 	Use:   "resetmfa [users]",
 	Short: "Turn off MFA",
-	Long: `Turn off multi-factor authentication for a user.
+	Long: `Turn off multi-factor authentication for a user, removing their authenticator app, security keys and recovery codes.
 If MFA enforcement is enabled, the user will be forced to re-enable MFA as soon as they log in.`,
 	Example: "  user resetmfa user@example.com",
 	RunE:    withClient(resetUserMfaCmdF),
@@ -198,6 +198,22 @@ func (mr *MockClientMockRecorder) CreateChannel(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannel", reflect.TypeOf((*MockClient)(nil).CreateChannel), arg0, arg1)
 }
 
+// CreateChannelEmailAddress mocks base method.
+func (m *MockClient) CreateChannelEmailAddress(arg0 context.Context, arg1 string, arg2 *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChannelEmailAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.ChannelEmailAddress)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateChannelEmailAddress indicates an expected call of CreateChannelEmailAddress.
+func (mr *MockClientMockRecorder) CreateChannelEmailAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannelEmailAddress", reflect.TypeOf((*MockClient)(nil).CreateChannelEmailAddress), arg0, arg1, arg2)
+}
+
 // CreateCommand mocks base method.
 func (m *MockClient) CreateCommand(arg0 context.Context, arg1 *model.Command) (*model.Command, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -278,6 +294,22 @@ func (mr *MockClientMockRecorder) CreatePost(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockClient)(nil).CreatePost), arg0, arg1)
 }
 
+// CreateScopedUserAccessToken mocks base method.
+func (m *MockClient) CreateScopedUserAccessToken(arg0 context.Context, arg1 string, arg2 *model.UserAccessToken) (*model.UserAccessToken, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScopedUserAccessToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.UserAccessToken)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateScopedUserAccessToken indicates an expected call of CreateScopedUserAccessToken.
+func (mr *MockClientMockRecorder) CreateScopedUserAccessToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScopedUserAccessToken", reflect.TypeOf((*MockClient)(nil).CreateScopedUserAccessToken), arg0, arg1, arg2)
+}
+
 // CreateTeam mocks base method.
 func (m *MockClient) CreateTeam(arg0 context.Context, arg1 *model.Team) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -357,6 +389,21 @@ func (mr *MockClientMockRecorder) DeleteChannel(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockClient)(nil).DeleteChannel), arg0, arg1)
 }
 
+// DeleteChannelEmailAddress mocks base method.
+func (m *MockClient) DeleteChannelEmailAddress(arg0 context.Context, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannelEmailAddress", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChannelEmailAddress indicates an expected call of DeleteChannelEmailAddress.
+func (mr *MockClientMockRecorder) DeleteChannelEmailAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannelEmailAddress", reflect.TypeOf((*MockClient)(nil).DeleteChannelEmailAddress), arg0, arg1)
+}
+
 // DeleteCommand mocks base method.
 func (m *MockClient) DeleteCommand(arg0 context.Context, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -540,6 +587,22 @@ func (mr *MockClientMockRecorder) EnablePlugin(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnablePlugin", reflect.TypeOf((*MockClient)(nil).EnablePlugin), arg0, arg1)
 }
 
+// ExportAuditRecords mocks base method.
+func (m *MockClient) ExportAuditRecords(arg0 context.Context, arg1 model.AuditRecordSearchOptions, arg2 string, arg3 io.Writer) (int64, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAuditRecords", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExportAuditRecords indicates an expected call of ExportAuditRecords.
+func (mr *MockClientMockRecorder) ExportAuditRecords(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAuditRecords", reflect.TypeOf((*MockClient)(nil).ExportAuditRecords), arg0, arg1, arg2, arg3)
+}
+
 // GeneratePresignedURL mocks base method.
 func (m *MockClient) GeneratePresignedURL(arg0 context.Context, arg1 string) (*model.PresignURLResponse, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -684,6 +747,22 @@ func (mr *MockClientMockRecorder) GetChannelByNameIncludeDeleted(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelByNameIncludeDeleted", reflect.TypeOf((*MockClient)(nil).GetChannelByNameIncludeDeleted), arg0, arg1, arg2, arg3)
 }
 
+// GetChannelEmailAddress mocks base method.
+func (m *MockClient) GetChannelEmailAddress(arg0 context.Context, arg1 string) (*model.ChannelEmailAddress, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelEmailAddress", arg0, arg1)
+	ret0, _ := ret[0].(*model.ChannelEmailAddress)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChannelEmailAddress indicates an expected call of GetChannelEmailAddress.
+func (mr *MockClientMockRecorder) GetChannelEmailAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelEmailAddress", reflect.TypeOf((*MockClient)(nil).GetChannelEmailAddress), arg0, arg1)
+}
+
 // GetChannelMembers mocks base method.
 func (m *MockClient) GetChannelMembers(arg0 context.Context, arg1 string, arg2, arg3 int, arg4 string) (model.ChannelMembers, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -748,6 +827,38 @@ func (mr *MockClientMockRecorder) GetConfig(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockClient)(nil).GetConfig), arg0)
 }
 
+// GetConfigHistory mocks base method.
+func (m *MockClient) GetConfigHistory(arg0 context.Context, arg1, arg2 int) ([]*model.ConfigVersion, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.ConfigVersion)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetConfigHistory indicates an expected call of GetConfigHistory.
+func (mr *MockClientMockRecorder) GetConfigHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigHistory", reflect.TypeOf((*MockClient)(nil).GetConfigHistory), arg0, arg1, arg2)
+}
+
+// GetConfigVersionDiff mocks base method.
+func (m *MockClient) GetConfigVersionDiff(arg0 context.Context, arg1, arg2 string) ([]*model.ConfigVersionDiff, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigVersionDiff", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.ConfigVersionDiff)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetConfigVersionDiff indicates an expected call of GetConfigVersionDiff.
+func (mr *MockClientMockRecorder) GetConfigVersionDiff(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigVersionDiff", reflect.TypeOf((*MockClient)(nil).GetConfigVersionDiff), arg0, arg1, arg2)
+}
+
 // GetDeletedChannelsForTeam mocks base method.
 func (m *MockClient) GetDeletedChannelsForTeam(arg0 context.Context, arg1 string, arg2, arg3 int, arg4 string) ([]*model.Channel, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1262,6 +1373,22 @@ func (mr *MockClientMockRecorder) GetServerBusy(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerBusy", reflect.TypeOf((*MockClient)(nil).GetServerBusy), arg0)
 }
 
+// GetStorageUsageByTier mocks base method.
+func (m *MockClient) GetStorageUsageByTier(arg0 context.Context) ([]*model.FileTierUsage, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStorageUsageByTier", arg0)
+	ret0, _ := ret[0].([]*model.FileTierUsage)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStorageUsageByTier indicates an expected call of GetStorageUsageByTier.
+func (mr *MockClientMockRecorder) GetStorageUsageByTier(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageUsageByTier", reflect.TypeOf((*MockClient)(nil).GetStorageUsageByTier), arg0)
+}
+
 // GetTeam mocks base method.
 func (m *MockClient) GetTeam(arg0 context.Context, arg1, arg2 string) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1672,6 +1799,22 @@ func (mr *MockClientMockRecorder) PatchChannel(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchChannel", reflect.TypeOf((*MockClient)(nil).PatchChannel), arg0, arg1, arg2)
 }
 
+// PatchChannelEmailAddress mocks base method.
+func (m *MockClient) PatchChannelEmailAddress(arg0 context.Context, arg1 string, arg2 *model.ChannelEmailAddressPatch) (*model.ChannelEmailAddress, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchChannelEmailAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.ChannelEmailAddress)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchChannelEmailAddress indicates an expected call of PatchChannelEmailAddress.
+func (mr *MockClientMockRecorder) PatchChannelEmailAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchChannelEmailAddress", reflect.TypeOf((*MockClient)(nil).PatchChannelEmailAddress), arg0, arg1, arg2)
+}
+
 // PatchConfig mocks base method.
 func (m *MockClient) PatchConfig(arg0 context.Context, arg1 *model.Config) (*model.Config, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1811,6 +1954,22 @@ func (mr *MockClientMockRecorder) RegenOutgoingHookToken(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenOutgoingHookToken", reflect.TypeOf((*MockClient)(nil).RegenOutgoingHookToken), arg0, arg1)
 }
 
+// RegenerateChannelEmailAddress mocks base method.
+func (m *MockClient) RegenerateChannelEmailAddress(arg0 context.Context, arg1 string) (*model.ChannelEmailAddress, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateChannelEmailAddress", arg0, arg1)
+	ret0, _ := ret[0].(*model.ChannelEmailAddress)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegenerateChannelEmailAddress indicates an expected call of RegenerateChannelEmailAddress.
+func (mr *MockClientMockRecorder) RegenerateChannelEmailAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateChannelEmailAddress", reflect.TypeOf((*MockClient)(nil).RegenerateChannelEmailAddress), arg0, arg1)
+}
+
 // ReloadConfig mocks base method.
 func (m *MockClient) ReloadConfig(arg0 context.Context) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1965,6 +2124,37 @@ func (mr *MockClientMockRecorder) RevokeUserAccessToken(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserAccessToken", reflect.TypeOf((*MockClient)(nil).RevokeUserAccessToken), arg0, arg1)
 }
 
+// RollbackConfig mocks base method.
+func (m *MockClient) RollbackConfig(arg0 context.Context, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackConfig", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackConfig indicates an expected call of RollbackConfig.
+func (mr *MockClientMockRecorder) RollbackConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackConfig", reflect.TypeOf((*MockClient)(nil).RollbackConfig), arg0, arg1)
+}
+
+// SearchAuditRecords mocks base method.
+func (m *MockClient) SearchAuditRecords(arg0 context.Context, arg1 model.AuditRecordSearchOptions) (*model.AuditRecordList, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAuditRecords", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuditRecordList)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchAuditRecords indicates an expected call of SearchAuditRecords.
+func (mr *MockClientMockRecorder) SearchAuditRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAuditRecords", reflect.TypeOf((*MockClient)(nil).SearchAuditRecords), arg0, arg1)
+}
+
 // SearchTeams mocks base method.
 func (m *MockClient) SearchTeams(arg0 context.Context, arg1 *model.TeamSearch) ([]*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -4,7 +4,7 @@
 
 # Enable services to be run in docker.
 #
-# Possible options: mysql, postgres, minio, inbucket, openldap, dejavu,
+# Possible options: mysql, postgres, minio, azurite, fakegcs, inbucket, openldap, dejavu,
 # keycloak, elasticsearch, prometheus, grafana, loki and promtail.
 #
 # Must be space separated names.
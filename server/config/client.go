@@ -4,6 +4,7 @@
 package config
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 
@@ -64,6 +65,7 @@ func GenerateClientConfig(c *model.Config, telemetryID string, license *model.Li
 	props["SendPushNotifications"] = strconv.FormatBool(*c.EmailSettings.SendPushNotifications)
 	props["RequireEmailVerification"] = strconv.FormatBool(*c.EmailSettings.RequireEmailVerification)
 	props["EnableEmailBatching"] = strconv.FormatBool(*c.EmailSettings.EnableEmailBatching)
+	props["EnableEmailDigests"] = strconv.FormatBool(*c.EmailSettings.EnableEmailDigests)
 	props["EnablePreviewModeBanner"] = strconv.FormatBool(*c.EmailSettings.EnablePreviewModeBanner)
 	props["EmailNotificationContentsType"] = *c.EmailSettings.EmailNotificationContentsType
 
@@ -136,6 +138,7 @@ func GenerateClientConfig(c *model.Config, telemetryID string, license *model.Li
 	props["EnableCustomGroups"] = "false"
 	props["PostPriority"] = strconv.FormatBool(*c.ServiceSettings.PostPriority)
 	props["AllowPersistentNotifications"] = strconv.FormatBool(*c.ServiceSettings.AllowPersistentNotifications)
+	props["EnableNotificationRules"] = strconv.FormatBool(*c.ServiceSettings.EnableNotificationRules)
 	props["AllowPersistentNotificationsForGuests"] = strconv.FormatBool(*c.ServiceSettings.AllowPersistentNotificationsForGuests)
 	props["PersistentNotificationMaxCount"] = strconv.FormatInt(int64(*c.ServiceSettings.PersistentNotificationMaxCount), 10)
 	props["PersistentNotificationIntervalMinutes"] = strconv.FormatInt(int64(*c.ServiceSettings.PersistentNotificationIntervalMinutes), 10)
@@ -330,12 +333,20 @@ func GenerateLimitedClientConfig(c *model.Config, telemetryID string, license *m
 	props["EnableSignUpWithOpenId"] = "false"
 	props["OpenIdButtonText"] = ""
 	props["OpenIdButtonColor"] = ""
+	props["OpenIdConnectProviders"] = openIdConnectProvidersJSON(c)
 	props["CWSURL"] = ""
 	props["EnableCustomBrand"] = strconv.FormatBool(*c.TeamSettings.EnableCustomBrand)
 	props["CustomBrandText"] = *c.TeamSettings.CustomBrandText
 	props["CustomDescriptionText"] = *c.TeamSettings.CustomDescriptionText
 	props["EnableMultifactorAuthentication"] = strconv.FormatBool(*c.ServiceSettings.EnableMultifactorAuthentication)
 	props["EnforceMultifactorAuthentication"] = "false"
+	props["EnableWebAuthn"] = strconv.FormatBool(*c.ServiceSettings.EnableMultifactorAuthentication && *c.ServiceSettings.EnableWebAuthn)
+	props["EnableWebAuthnPasswordlessLogin"] = strconv.FormatBool(*c.ServiceSettings.EnableMultifactorAuthentication && *c.ServiceSettings.EnableWebAuthn && *c.ServiceSettings.EnableWebAuthnPasswordlessLogin)
+	if *c.ServiceSettings.EnableMultifactorAuthentication {
+		props["MfaTrustedDeviceDays"] = strconv.Itoa(*c.ServiceSettings.MfaTrustedDeviceDays)
+	} else {
+		props["MfaTrustedDeviceDays"] = "0"
+	}
 	props["EnableGuestAccounts"] = strconv.FormatBool(*c.GuestAccountsSettings.Enable)
 	props["HideGuestTags"] = strconv.FormatBool(*c.GuestAccountsSettings.HideTags)
 	props["GuestAccountsEnforceMultifactorAuthentication"] = strconv.FormatBool(*c.GuestAccountsSettings.EnforceMultifactorAuthentication)
@@ -407,3 +418,35 @@ func getGiphySdkKey(ss model.ServiceSettings) string {
 
 	return ""
 }
+
+// openIdConnectProviderOption is what the login page needs to render the
+// button of a generic OpenID Connect provider.
+type openIdConnectProviderOption struct {
+	Service     string `json:"service"`
+	ButtonText  string `json:"button_text"`
+	ButtonColor string `json:"button_color"`
+}
+
+// openIdConnectProvidersJSON returns the enabled generic OpenID Connect
+// providers as a JSON list.
+func openIdConnectProvidersJSON(c *model.Config) string {
+	options := []openIdConnectProviderOption{}
+	for _, provider := range c.OpenIdConnectSettings.Providers {
+		if !*provider.Enable {
+			continue
+		}
+
+		buttonText := *provider.ButtonText
+		if buttonText == "" {
+			buttonText = *provider.Id
+		}
+		options = append(options, openIdConnectProviderOption{
+			Service:     provider.Service(),
+			ButtonText:  buttonText,
+			ButtonColor: *provider.ButtonColor,
+		})
+	}
+
+	b, _ := json.Marshal(options)
+	return string(b)
+}
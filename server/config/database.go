@@ -200,7 +200,12 @@ func parseDSN(dsn string) (string, string, error) {
 
 // Set replaces the current configuration in its entirety and updates the backing store.
 func (ds *DatabaseStore) Set(newCfg *model.Config) error {
-	return ds.persist(newCfg)
+	return ds.persist(newCfg, "")
+}
+
+// setWithAuthor behaves like Set, additionally recording the user who made the change.
+func (ds *DatabaseStore) setWithAuthor(newCfg *model.Config, authorID string) error {
+	return ds.persist(newCfg, authorID)
 }
 
 // maxLength identifies the maximum length of a configuration or configuration file
@@ -213,7 +218,7 @@ func (ds *DatabaseStore) checkLength(length int) error {
 }
 
 // persist writes the configuration to the configured database.
-func (ds *DatabaseStore) persist(cfg *model.Config) error {
+func (ds *DatabaseStore) persist(cfg *model.Config, createdBy string) error {
 	b, err := marshalConfig(cfg)
 	if err != nil {
 		return errors.Wrap(err, "failed to serialize")
@@ -286,14 +291,15 @@ func (ds *DatabaseStore) persist(cfg *model.Config) error {
 	}
 
 	params := map[string]any{
-		"id":        model.NewId(),
-		"value":     value,
-		"create_at": model.GetMillis(),
-		"key":       "ConfigurationId",
-		"sha":       hex.EncodeToString(sum[0:]),
+		"id":         model.NewId(),
+		"value":      value,
+		"create_at":  model.GetMillis(),
+		"key":        "ConfigurationId",
+		"sha":        hex.EncodeToString(sum[0:]),
+		"created_by": createdBy,
 	}
 
-	if _, err := tx.NamedExec("INSERT INTO Configurations (Id, Value, CreateAt, Active, SHA) VALUES (:id, :value, :create_at, TRUE, :sha)", params); err != nil {
+	if _, err := tx.NamedExec("INSERT INTO Configurations (Id, Value, CreateAt, Active, SHA, CreatedBy) VALUES (:id, :value, :create_at, TRUE, :sha, :created_by)", params); err != nil {
 		return errors.Wrap(err, "failed to record new configuration")
 	}
 
@@ -324,6 +330,65 @@ func (ds *DatabaseStore) Load() ([]byte, error) {
 	return configurationData, nil
 }
 
+// getRevisions returns up to limit persisted configurations, newest first.
+func (ds *DatabaseStore) getRevisions(offset, limit int) ([]*configRevision, error) {
+	rows, err := ds.db.Query(ds.db.Rebind("SELECT Id, Value, CreateAt, CreatedBy, Active FROM Configurations ORDER BY CreateAt DESC, Id LIMIT ? OFFSET ?"), limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query configurations")
+	}
+	defer rows.Close()
+
+	var revisions []*configRevision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate configurations")
+	}
+
+	return revisions, nil
+}
+
+// getRevision returns the persisted configuration with the given id. An empty
+// id refers to the active configuration.
+func (ds *DatabaseStore) getRevision(id string) (*configRevision, error) {
+	var row *sql.Row
+	if id == "" {
+		row = ds.db.QueryRow("SELECT Id, Value, CreateAt, CreatedBy, Active FROM Configurations WHERE Active")
+	} else {
+		row = ds.db.QueryRow(ds.db.Rebind("SELECT Id, Value, CreateAt, CreatedBy, Active FROM Configurations WHERE Id = ?"), id)
+	}
+
+	revision, err := scanRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrConfigVersionNotFound
+	}
+	return revision, err
+}
+
+func scanRevision(row interface{ Scan(dest ...any) error }) (*configRevision, error) {
+	var (
+		revision  configRevision
+		createdBy sql.NullString
+		active    sql.NullBool
+	)
+	revision.version = &model.ConfigVersion{}
+	if err := row.Scan(&revision.version.Id, &revision.value, &revision.version.CreateAt, &createdBy, &active); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "failed to read configuration")
+	}
+	revision.version.CreatedBy = createdBy.String
+	revision.version.Active = active.Valid && active.Bool
+
+	return &revision, nil
+}
+
 // GetFile fetches the contents of a previously persisted configuration file.
 func (ds *DatabaseStore) GetFile(name string) ([]byte, error) {
 	query, args, err := sqlx.Named("SELECT Data FROM ConfigurationFiles WHERE Name = :name", map[string]any{
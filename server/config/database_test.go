@@ -876,7 +876,7 @@ func TestDatabaseStoreLoad(t *testing.T) {
 		newCfg := minimalConfig.Clone()
 		dbStore, ok := ds.backingStore.(*DatabaseStore)
 		require.True(t, ok)
-		err = dbStore.persist(newCfg)
+		err = dbStore.persist(newCfg, "")
 		require.NoError(t, err)
 
 		err = ds.Load()
@@ -1159,3 +1159,62 @@ func TestCleanUp(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, count+3 == initialCount)
 }
+
+func TestDatabaseStoreHistory(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	_, tearDown := setupConfigDatabase(t, minimalConfig, nil)
+	defer tearDown()
+
+	ds, err := newTestDatabaseStore(nil)
+	require.NoError(t, err)
+	defer ds.Close()
+
+	oldSiteName := *ds.Get().TeamSettings.SiteName
+	authorID := model.NewId()
+	newCfg := ds.Get().Clone()
+	newCfg.TeamSettings.SiteName = model.NewPointer("changed")
+	_, _, err = ds.SetWithAuthor(newCfg, authorID)
+	require.NoError(t, err)
+
+	versions, err := ds.GetHistory(0, 10)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(versions), 2)
+
+	t.Run("lists the versions newest first", func(t *testing.T) {
+		assert.True(t, versions[0].Active)
+		assert.Equal(t, authorID, versions[0].CreatedBy)
+		assert.Equal(t, []string{"TeamSettings.SiteName"}, versions[0].ChangedPaths)
+		assert.False(t, versions[1].Active)
+		assert.Empty(t, versions[1].CreatedBy)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		page, err := ds.GetHistory(1, 1)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, versions[1].Id, page[0].Id)
+	})
+
+	t.Run("diffs against the active configuration", func(t *testing.T) {
+		diffs, err := ds.DiffVersions(versions[1].Id, "")
+		require.NoError(t, err)
+		require.Len(t, diffs, 1)
+		assert.Equal(t, "TeamSettings.SiteName", diffs[0].Path)
+
+		diffs, err = ds.DiffVersions(versions[0].Id, versions[0].Id)
+		require.NoError(t, err)
+		assert.Empty(t, diffs)
+	})
+
+	t.Run("gets a past version", func(t *testing.T) {
+		cfg, err := ds.GetVersion(versions[1].Id)
+		require.NoError(t, err)
+		assert.Equal(t, oldSiteName, *cfg.TeamSettings.SiteName)
+
+		_, err = ds.GetVersion(model.NewId())
+		assert.ErrorIs(t, err, ErrConfigVersionNotFound)
+	})
+}
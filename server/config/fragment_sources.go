@@ -0,0 +1,273 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// defaultPollInterval is how often an HTTP source checks for changes.
+	defaultPollInterval = 30 * time.Second
+
+	// watchDebounce groups the bursts of filesystem events caused by a
+	// single update, e.g. when Kubernetes swaps a ConfigMap volume.
+	watchDebounce = 500 * time.Millisecond
+
+	httpSourceTimeout = 10 * time.Second
+)
+
+// DirectorySource reads the fragments from the JSON and YAML files of a directory,
+// merged in lexical order of their names.
+type DirectorySource struct {
+	dir string
+}
+
+// NewDirectorySource creates a fragment source reading from the given directory.
+func NewDirectorySource(dir string) (*DirectorySource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open fragments directory %s", dir)
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("%s is not a directory", dir)
+	}
+
+	return &DirectorySource{dir: dir}, nil
+}
+
+// Fragments reads the fragments of the directory. Hidden files, such as the
+// metadata of Kubernetes volumes, and files of other types are ignored.
+func (ds *DirectorySource) Fragments() ([]*Fragment, error) {
+	entries, err := os.ReadDir(ds.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read fragments directory %s", ds.dir)
+	}
+
+	var fragments []*Fragment
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if strings.HasPrefix(name, ".") || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		// Symbolic links to directories are listed as files.
+		if info, err := os.Stat(filepath.Join(ds.dir, name)); err == nil && info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(ds.dir, name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read fragment %s", name)
+		}
+
+		fragment, err := parseFragment(name, data, ext != ".json")
+		if err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, fragment)
+	}
+
+	return fragments, nil
+}
+
+// Watch calls onChange after the content of the directory changed.
+func (ds *DirectorySource) Watch(onChange func()) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create fragments watcher")
+	}
+	if err := watcher.Add(ds.dir); err != nil {
+		watcher.Close()
+		return nil, errors.Wrapf(err, "failed to watch fragments directory %s", ds.dir)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		debounce := time.NewTimer(watchDebounce)
+		debounce.Stop()
+		defer debounce.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				debounce.Reset(watchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				mlog.Warn("Error while watching configuration fragments", mlog.String("dir", ds.dir), mlog.Err(err))
+			case <-debounce.C:
+				onChange()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+			wg.Wait()
+		})
+	}, nil
+}
+
+// String returns the DSN of the directory.
+func (ds *DirectorySource) String() string {
+	return fragmentsScheme + "://" + ds.dir
+}
+
+// HTTPSource reads a single fragment from an HTTP endpoint, such as the raw value
+// of a Consul key, and polls it for changes.
+type HTTPSource struct {
+	url          string
+	client       *http.Client
+	pollInterval time.Duration
+
+	lastSumLock sync.Mutex
+	lastSum     [sha256.Size]byte
+}
+
+// NewHTTPSource creates a fragment source reading from the given URL.
+func NewHTTPSource(rawURL string, pollInterval time.Duration) (*HTTPSource, error) {
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return nil, errors.Errorf("unsupported fragments URL %s", rawURL)
+	}
+
+	return &HTTPSource{
+		url:          rawURL,
+		client:       &http.Client{Timeout: httpSourceTimeout},
+		pollInterval: pollInterval,
+	}, nil
+}
+
+func (hs *HTTPSource) fetch() ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), httpSourceTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hs.url, nil)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to create fragment request")
+	}
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to fetch fragment")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, errors.Errorf("failed to fetch fragment: unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxWriteLength))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read fragment")
+	}
+
+	isYAML := strings.Contains(resp.Header.Get("Content-Type"), "yaml")
+	if ext := strings.ToLower(path.Ext(req.URL.Path)); ext == ".yaml" || ext == ".yml" {
+		isYAML = true
+	}
+	return data, isYAML, nil
+}
+
+// Fragments fetches the fragment served by the endpoint.
+func (hs *HTTPSource) Fragments() ([]*Fragment, error) {
+	data, isYAML, err := hs.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	hs.lastSumLock.Lock()
+	hs.lastSum = sha256.Sum256(data)
+	hs.lastSumLock.Unlock()
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	fragment, err := parseFragment(hs.url, data, isYAML)
+	if err != nil {
+		return nil, err
+	}
+	return []*Fragment{fragment}, nil
+}
+
+// Watch polls the endpoint and calls onChange when the fragment differs from the
+// last one read.
+func (hs *HTTPSource) Watch(onChange func()) (func(), error) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(hs.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				data, _, err := hs.fetch()
+				if err != nil {
+					mlog.Warn("Failed to poll configuration fragment", mlog.String("url", hs.String()), mlog.Err(err))
+					continue
+				}
+
+				hs.lastSumLock.Lock()
+				changed := hs.lastSum != sha256.Sum256(data)
+				hs.lastSumLock.Unlock()
+				if changed {
+					onChange()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}, nil
+}
+
+// String returns the DSN of the endpoint, without credentials.
+func (hs *HTTPSource) String() string {
+	u, err := url.Parse(hs.url)
+	if err != nil {
+		return fragmentsScheme + "+" + hs.url
+	}
+	u.User = nil
+	return fragmentsScheme + "+" + u.String()
+}
@@ -0,0 +1,521 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	fragmentsScheme = "fragments"
+
+	// fragmentsBaseParam and fragmentsPollIntervalParam are the DSN query parameters
+	// reserved to configure the fragment store itself.
+	fragmentsBaseParam         = "base"
+	fragmentsPollIntervalParam = "poll_interval"
+)
+
+// ManagedSettingError is returned when an attempt to change a setting defined by
+// configuration fragments is made. It is a read-only configuration error.
+type ManagedSettingError struct {
+	Path string
+}
+
+func (e *ManagedSettingError) Error() string {
+	return fmt.Sprintf("setting %s is managed by configuration fragments", e.Path)
+}
+
+func (e *ManagedSettingError) Is(target error) bool {
+	return target == ErrReadOnlyConfiguration
+}
+
+// Fragment is a partial configuration document, e.g. the settings of a single
+// section or plugin.
+type Fragment struct {
+	Name string
+	Data map[string]any
+}
+
+// FragmentSource provides the fragments a FragmentStore composes the configuration from.
+type FragmentSource interface {
+	// Fragments returns the current fragments, in the order they must be merged.
+	Fragments() ([]*Fragment, error)
+
+	// Watch calls onChange whenever the fragments may have changed, until stop is called.
+	Watch(onChange func()) (stop func(), err error)
+
+	// String describes the source of the fragments.
+	String() string
+}
+
+// parseFragment decodes a JSON or YAML fragment.
+func parseFragment(name string, data []byte, isYAML bool) (*Fragment, error) {
+	fragment := &Fragment{Name: name, Data: map[string]any{}}
+	var err error
+	if isYAML {
+		err = yaml.Unmarshal(data, &fragment.Data)
+	} else {
+		err = json.Unmarshal(data, &fragment.Data)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse fragment %s", name)
+	}
+
+	// Catch typos in setting names, which would be silently ignored otherwise.
+	b, err := json.Marshal(fragment.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to serialize fragment %s", name)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&model.Config{}); err != nil {
+		return nil, errors.Wrapf(err, "invalid fragment %s", name)
+	}
+
+	// Setting names are matched case-insensitively when decoding, but must be
+	// exact to be enforced as managed.
+	if err := checkFragmentKeys(nil, reflect.TypeOf(model.Config{}), fragment.Data); err != nil {
+		return nil, errors.Wrapf(err, "invalid fragment %s", name)
+	}
+
+	return fragment, nil
+}
+
+// checkFragmentKeys fails if a setting of the fragment data doesn't use the
+// canonical name of its field.
+func checkFragmentKeys(prefix []string, t reflect.Type, data map[string]any) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for key, value := range data {
+		path := append(append([]string{}, prefix...), key)
+		field, ok := t.FieldByName(key)
+		if !ok {
+			return errors.Errorf("unknown setting %s, setting names are case-sensitive", strings.Join(path, "."))
+		}
+		if object, isObject := value.(map[string]any); isObject {
+			if err := checkFragmentKeys(path, field.Type, object); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FragmentStore is a config store composing the configuration from fragments, merged
+// in order on top of an optional base store. The settings defined by the fragments
+// are read-only, any other setting is persisted to the base store.
+//
+// Not to be used directly. Only to be used as a backing store for config.Store
+type FragmentStore struct {
+	source FragmentSource
+	base   BackingStore
+
+	managedLock sync.RWMutex
+	// managed holds the paths of the settings defined by the fragments.
+	managed [][]string
+	// composed is the last loaded configuration, used to compare the managed settings.
+	composed *model.Config
+}
+
+// NewFragmentStore creates a new instance of a config store composed from the
+// fragments of the given source. Without a base store, the configuration is read-only.
+func NewFragmentStore(source FragmentSource, base BackingStore) *FragmentStore {
+	return &FragmentStore{
+		source: source,
+		base:   base,
+	}
+}
+
+// IsFragmentsDSN returns true if the DSN describes a configuration composed from fragments.
+func IsFragmentsDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, fragmentsScheme+"://") ||
+		strings.HasPrefix(dsn, fragmentsScheme+"+http://") ||
+		strings.HasPrefix(dsn, fragmentsScheme+"+https://")
+}
+
+// NewFragmentStoreFromDSN creates a fragment store from a DSN such as
+//
+//	fragments:///etc/mattermost/config.d?base=config.json
+//
+// reading the fragments from a directory, or
+//
+//	fragments+https://consul:8500/v1/kv/mattermost/config?raw&poll_interval=30s
+//
+// polling a single fragment from an HTTP endpoint. The optional base parameter is
+// the DSN of the store keeping the settings not defined by the fragments.
+func NewFragmentStoreFromDSN(dsn string, createFileIfNotExists bool) (*FragmentStore, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse fragments DSN")
+	}
+
+	query := u.Query()
+	baseDSN := query.Get(fragmentsBaseParam)
+	pollInterval := defaultPollInterval
+	if s := query.Get(fragmentsPollIntervalParam); s != "" {
+		if pollInterval, err = time.ParseDuration(s); err != nil || pollInterval <= 0 {
+			return nil, errors.Errorf("invalid %s %q", fragmentsPollIntervalParam, s)
+		}
+	}
+	query.Del(fragmentsBaseParam)
+	query.Del(fragmentsPollIntervalParam)
+	u.RawQuery = query.Encode()
+
+	var source FragmentSource
+	if u.Scheme == fragmentsScheme {
+		source, err = NewDirectorySource(u.Path)
+	} else {
+		u.Scheme = strings.TrimPrefix(u.Scheme, fragmentsScheme+"+")
+		source, err = NewHTTPSource(u.String(), pollInterval)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var base BackingStore
+	if baseDSN != "" {
+		if IsFragmentsDSN(baseDSN) {
+			return nil, errors.New("the base of a fragment store can't be another fragment store")
+		}
+		if base, err = newBackingStoreFromDSN(baseDSN, createFileIfNotExists); err != nil {
+			return nil, errors.Wrap(err, "failed to create base store")
+		}
+	}
+
+	return NewFragmentStore(source, base), nil
+}
+
+// Set replaces the current configuration in its entirety and updates the base store.
+// It fails if the configuration changes any setting managed by the fragments.
+func (fs *FragmentStore) Set(newCfg *model.Config) error {
+	fs.managedLock.RLock()
+	managed, composed := fs.managed, fs.composed
+	fs.managedLock.RUnlock()
+
+	if fs.base == nil {
+		return ErrReadOnlyConfiguration
+	}
+
+	if composed != nil {
+		for _, path := range managed {
+			current, _ := valueAtPath(reflect.ValueOf(composed), path)
+			updated, _ := valueAtPath(reflect.ValueOf(newCfg), path)
+			if !reflect.DeepEqual(valueInterface(current), valueInterface(updated)) {
+				return &ManagedSettingError{Path: strings.Join(path, ".")}
+			}
+		}
+	}
+
+	baseCfg, err := fs.withoutManagedSettings(newCfg, managed)
+	if err != nil {
+		return err
+	}
+
+	return fs.base.Set(baseCfg)
+}
+
+// withoutManagedSettings returns the configuration to persist to the base store.
+// The managed settings keep their value from the base store, if any, so that the
+// values defined by the fragments are never written to it.
+func (fs *FragmentStore) withoutManagedSettings(cfg *model.Config, managed [][]string) (*model.Config, error) {
+	if len(managed) == 0 {
+		return cfg, nil
+	}
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize configuration")
+	}
+	data := map[string]any{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrap(err, "failed to parse configuration")
+	}
+
+	baseData := map[string]any{}
+	baseBytes, err := fs.base.Load()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load base configuration")
+	}
+	if len(baseBytes) != 0 {
+		if err := json.Unmarshal(baseBytes, &baseData); err != nil {
+			return nil, errors.Wrap(err, "failed to parse base configuration")
+		}
+	}
+
+	for _, path := range managed {
+		if value, ok := lookupPath(baseData, path); ok {
+			setPath(data, path, value)
+		} else {
+			deletePath(data, path)
+		}
+	}
+
+	if b, err = json.Marshal(data); err != nil {
+		return nil, errors.Wrap(err, "failed to serialize configuration")
+	}
+	var baseCfg model.Config
+	if err := json.Unmarshal(b, &baseCfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse configuration")
+	}
+	return &baseCfg, nil
+}
+
+// Load composes the configuration from the base store and the fragments.
+func (fs *FragmentStore) Load() ([]byte, error) {
+	composed := map[string]any{}
+	if fs.base != nil {
+		baseBytes, err := fs.base.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load base configuration")
+		}
+		if len(baseBytes) != 0 {
+			if err := json.Unmarshal(baseBytes, &composed); err != nil {
+				return nil, errors.Wrap(err, "failed to parse base configuration")
+			}
+		}
+	}
+
+	fragments, err := fs.source.Fragments()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load configuration fragments")
+	}
+
+	var managed [][]string
+	for _, fragment := range fragments {
+		mergeFragment(composed, fragment.Data)
+		managed = appendManagedPaths(managed, nil, reflect.TypeOf(model.Config{}), fragment.Data)
+	}
+
+	b, err := json.Marshal(composed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize composed configuration")
+	}
+
+	// Keep the settings as config.Store sees them, so that
+	// normalized values aren't mistaken for changes.
+	var cfg model.Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse composed configuration")
+	}
+	if cfg.ServiceSettings.SiteURL == nil {
+		cfg.ServiceSettings.SiteURL = model.NewPointer("")
+	}
+	cfg.SetDefaults()
+	fixConfig(&cfg)
+
+	fs.managedLock.Lock()
+	fs.managed = managed
+	fs.composed = &cfg
+	fs.managedLock.Unlock()
+
+	return b, nil
+}
+
+// ManagedSettings returns the paths of the settings defined by the fragments.
+func (fs *FragmentStore) ManagedSettings() []string {
+	fs.managedLock.RLock()
+	defer fs.managedLock.RUnlock()
+
+	paths := make([]string, 0, len(fs.managed))
+	for _, path := range fs.managed {
+		paths = append(paths, strings.Join(path, "."))
+	}
+	return paths
+}
+
+// watch implements watchableBackingStore.
+func (fs *FragmentStore) watch(onChange func()) (func(), error) {
+	return fs.source.Watch(onChange)
+}
+
+// GetFile fetches the contents of a previously persisted configuration file.
+func (fs *FragmentStore) GetFile(name string) ([]byte, error) {
+	if fs.base == nil {
+		return nil, fmt.Errorf("file %s not stored", name)
+	}
+	return fs.base.GetFile(name)
+}
+
+// SetFile sets or replaces the contents of a configuration file.
+func (fs *FragmentStore) SetFile(name string, data []byte) error {
+	if fs.base == nil {
+		return ErrReadOnlyConfiguration
+	}
+	return fs.base.SetFile(name, data)
+}
+
+// HasFile returns true if the given file was previously persisted.
+func (fs *FragmentStore) HasFile(name string) (bool, error) {
+	if fs.base == nil {
+		return false, nil
+	}
+	return fs.base.HasFile(name)
+}
+
+// RemoveFile removes a previously persisted configuration file.
+func (fs *FragmentStore) RemoveFile(name string) error {
+	if fs.base == nil {
+		return ErrReadOnlyConfiguration
+	}
+	return fs.base.RemoveFile(name)
+}
+
+// String describes the fragments source and the base store.
+func (fs *FragmentStore) String() string {
+	if fs.base == nil {
+		return fs.source.String()
+	}
+	return fs.source.String() + " over " + fs.base.String()
+}
+
+// Close cleans up resources associated with the store.
+func (fs *FragmentStore) Close() error {
+	if fs.base == nil {
+		return nil
+	}
+	return fs.base.Close()
+}
+
+// mergeFragment deep merges the fragment into the configuration. Objects are merged
+// key by key, any other value replaces the existing one.
+func mergeFragment(dst, fragment map[string]any) {
+	for key, value := range fragment {
+		if fragmentObject, ok := value.(map[string]any); ok {
+			if dstObject, ok := dst[key].(map[string]any); ok {
+				mergeFragment(dstObject, fragmentObject)
+				continue
+			}
+			merged := map[string]any{}
+			mergeFragment(merged, fragmentObject)
+			dst[key] = merged
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// appendManagedPaths appends the paths of the settings defined by the fragment data.
+// Entries of maps, such as the settings of each plugin, are managed as a whole.
+func appendManagedPaths(paths [][]string, prefix []string, t reflect.Type, data map[string]any) [][]string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for key, value := range data {
+		path := append(append([]string{}, prefix...), key)
+
+		var fieldType reflect.Type
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := t.FieldByName(key)
+			if !ok {
+				continue
+			}
+			fieldType = field.Type
+		default:
+			paths = append(paths, path)
+			continue
+		}
+
+		object, isObject := value.(map[string]any)
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if isObject && (fieldType.Kind() == reflect.Struct || fieldType.Kind() == reflect.Map) {
+			paths = appendManagedPaths(paths, path, fieldType, object)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// lookupPath returns the value at the given path of a decoded configuration.
+func lookupPath(data map[string]any, path []string) (any, bool) {
+	var value any = data
+	for _, key := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setPath sets the value at the given path of a decoded configuration, creating
+// the missing objects along the way.
+func setPath(data map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		object, ok := data[key].(map[string]any)
+		if !ok {
+			object = map[string]any{}
+			data[key] = object
+		}
+		data = object
+	}
+	data[path[len(path)-1]] = value
+}
+
+// deletePath removes the value at the given path of a decoded configuration.
+func deletePath(data map[string]any, path []string) {
+	for _, key := range path[:len(path)-1] {
+		object, ok := data[key].(map[string]any)
+		if !ok {
+			return
+		}
+		data = object
+	}
+	delete(data, path[len(path)-1])
+}
+
+// valueAtPath walks the configuration down to the given setting.
+func valueAtPath(v reflect.Value, path []string) (reflect.Value, bool) {
+	for _, key := range path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(key)
+		case reflect.Map:
+			v = v.MapIndex(reflect.ValueOf(key))
+		default:
+			return reflect.Value{}, false
+		}
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+func valueInterface(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func writeFragment(t *testing.T, dir, name, data string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0600))
+}
+
+func setupFragmentsDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFragment(t, dir, "10-service.yaml", `
+ServiceSettings:
+  SiteURL: http://example.com/
+`)
+	writeFragment(t, dir, "20-plugins.json", `{"PluginSettings": {"Plugins": {"com.example.managed": {"key": "value"}}}}`)
+	writeFragment(t, dir, "README.md", "not a fragment")
+	return dir
+}
+
+func TestParseFragment(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		fragment, err := parseFragment("team.json", []byte(`{"TeamSettings": {"SiteName": "Fragmented"}}`), false)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"TeamSettings": map[string]any{"SiteName": "Fragmented"}}, fragment.Data)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		fragment, err := parseFragment("team.yaml", []byte("TeamSettings:\n  MaxUsersPerTeam: 10\n"), true)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"TeamSettings": map[string]any{"MaxUsersPerTeam": 10}}, fragment.Data)
+	})
+
+	t.Run("unknown setting", func(t *testing.T) {
+		_, err := parseFragment("team.yaml", []byte("TeamSettings:\n  SiteNam: typo\n"), true)
+		require.Error(t, err)
+	})
+
+	t.Run("non-canonical setting name", func(t *testing.T) {
+		_, err := parseFragment("service.json", []byte(`{"servicesettings": {"SiteURL": "http://example.com"}}`), false)
+		require.Error(t, err)
+
+		_, err = parseFragment("service.yaml", []byte("ServiceSettings:\n  siteurl: http://example.com\n"), true)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseFragment("team.json", []byte(`{"TeamSettings":`), false)
+		require.Error(t, err)
+	})
+}
+
+func TestMergeFragment(t *testing.T) {
+	dst := map[string]any{
+		"TeamSettings": map[string]any{"SiteName": "Base", "MaxUsersPerTeam": 50},
+	}
+	mergeFragment(dst, map[string]any{
+		"TeamSettings":    map[string]any{"SiteName": "Fragment"},
+		"ServiceSettings": map[string]any{"SiteURL": "http://example.com"},
+	})
+
+	assert.Equal(t, map[string]any{
+		"TeamSettings":    map[string]any{"SiteName": "Fragment", "MaxUsersPerTeam": 50},
+		"ServiceSettings": map[string]any{"SiteURL": "http://example.com"},
+	}, dst)
+}
+
+func TestFragmentStore(t *testing.T) {
+	t.Run("composes the fragments over the base store", func(t *testing.T) {
+		source, err := NewDirectorySource(setupFragmentsDir(t))
+		require.NoError(t, err)
+		base, err := NewMemoryStore()
+		require.NoError(t, err)
+
+		fs := NewFragmentStore(source, base)
+		store, err := NewStoreFromBacking(fs, nil, false)
+		require.NoError(t, err)
+		defer store.Close()
+
+		cfg := store.Get()
+		assert.Equal(t, "http://example.com", *cfg.ServiceSettings.SiteURL)
+		assert.Equal(t, map[string]any{"key": "value"}, cfg.PluginSettings.Plugins["com.example.managed"])
+		assert.ElementsMatch(t, []string{"ServiceSettings.SiteURL", "PluginSettings.Plugins.com.example.managed"}, fs.ManagedSettings())
+	})
+
+	t.Run("enforces the managed settings", func(t *testing.T) {
+		source, err := NewDirectorySource(setupFragmentsDir(t))
+		require.NoError(t, err)
+		base, err := NewMemoryStore()
+		require.NoError(t, err)
+
+		store, err := NewStoreFromBacking(NewFragmentStore(source, base), nil, false)
+		require.NoError(t, err)
+		defer store.Close()
+
+		newCfg := store.Get().Clone()
+		newCfg.TeamSettings.SiteName = model.NewPointer("Unmanaged")
+		newCfg.PluginSettings.Plugins["com.example.other"] = map[string]any{"key": "other"}
+		_, _, err = store.Set(newCfg)
+		require.NoError(t, err)
+		baseBytes, err := base.Load()
+		require.NoError(t, err)
+		assert.Contains(t, string(baseBytes), `"SiteName": "Unmanaged"`)
+		assert.NotContains(t, string(baseBytes), "http://example.com", "managed settings are not persisted")
+		assert.NotContains(t, string(baseBytes), "com.example.managed", "managed settings are not persisted")
+		assert.Contains(t, string(baseBytes), "com.example.other")
+
+		newCfg = store.Get().Clone()
+		newCfg.ServiceSettings.SiteURL = model.NewPointer("http://other.example.com")
+		_, _, err = store.Set(newCfg)
+		require.ErrorIs(t, err, ErrReadOnlyConfiguration)
+		var managedErr *ManagedSettingError
+		require.ErrorAs(t, err, &managedErr)
+		assert.Equal(t, "ServiceSettings.SiteURL", managedErr.Path)
+
+		newCfg = store.Get().Clone()
+		newCfg.PluginSettings.Plugins["com.example.managed"] = map[string]any{"key": "changed"}
+		_, _, err = store.Set(newCfg)
+		require.ErrorAs(t, err, &managedErr)
+		assert.Equal(t, "PluginSettings.Plugins.com.example.managed", managedErr.Path)
+	})
+
+	t.Run("read-only without a base store", func(t *testing.T) {
+		source, err := NewDirectorySource(setupFragmentsDir(t))
+		require.NoError(t, err)
+
+		store, err := NewStoreFromBacking(NewFragmentStore(source, nil), nil, false)
+		require.NoError(t, err)
+		defer store.Close()
+
+		newCfg := store.Get().Clone()
+		newCfg.TeamSettings.SiteName = model.NewPointer("Unmanaged")
+		_, _, err = store.Set(newCfg)
+		require.ErrorIs(t, err, ErrReadOnlyConfiguration)
+	})
+
+	t.Run("invalid fragments", func(t *testing.T) {
+		dir := setupFragmentsDir(t)
+		writeFragment(t, dir, "30-invalid.json", `{"TeamSettings": {"MaxUsersPerTeam": -1}}`)
+		source, err := NewDirectorySource(dir)
+		require.NoError(t, err)
+
+		_, err = NewStoreFromBacking(NewFragmentStore(source, nil), nil, false)
+		require.Error(t, err)
+	})
+
+	t.Run("reloads on change", func(t *testing.T) {
+		dir := setupFragmentsDir(t)
+		source, err := NewDirectorySource(dir)
+		require.NoError(t, err)
+
+		store, err := NewStoreFromBacking(NewFragmentStore(source, nil), nil, false)
+		require.NoError(t, err)
+		defer store.Close()
+
+		var notified atomic.Bool
+		store.AddListener(func(_, _ *model.Config) { notified.Store(true) })
+
+		writeFragment(t, dir, "10-service.yaml", "ServiceSettings:\n  SiteURL: http://changed.example.com\n")
+		require.Eventually(t, func() bool {
+			return *store.Get().ServiceSettings.SiteURL == "http://changed.example.com"
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.True(t, notified.Load())
+
+		// An invalid change keeps the last valid configuration.
+		writeFragment(t, dir, "10-service.yaml", "ServiceSettings:\n  SiteURL: [\n")
+		time.Sleep(2 * watchDebounce)
+		assert.Equal(t, "http://changed.example.com", *store.Get().ServiceSettings.SiteURL)
+	})
+}
+
+func TestHTTPSource(t *testing.T) {
+	var body atomic.Value
+	body.Store("TeamSettings:\n  SiteName: Remote\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.URL.Query().Get(fragmentsBaseParam))
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	baseFile := filepath.Join(t.TempDir(), "config.json")
+	dsn := "fragments+" + server.URL + "/v1/kv/mattermost?raw&poll_interval=50ms&base=" + url.QueryEscape(baseFile)
+	require.True(t, IsFragmentsDSN(dsn))
+
+	store, err := NewStoreFromDSN(dsn, false, nil, true)
+	require.NoError(t, err)
+	defer store.Close()
+
+	assert.Equal(t, "Remote", *store.Get().TeamSettings.SiteName)
+	assert.NotContains(t, store.String(), "poll_interval")
+
+	body.Store("TeamSettings:\n  SiteName: Updated\n")
+	require.Eventually(t, func() bool {
+		return *store.Get().TeamSettings.SiteName == "Updated"
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+var (
+	// ErrConfigHistoryUnsupported is returned when the backing store doesn't keep
+	// past configurations, e.g. when the configuration lives in a file.
+	ErrConfigHistoryUnsupported = errors.New("configuration store does not keep a history")
+
+	// ErrConfigVersionNotFound is returned when the requested configuration version doesn't exist.
+	ErrConfigVersionNotFound = errors.New("configuration version not found")
+)
+
+// configRevision is a configuration as persisted by a versioned backing store.
+type configRevision struct {
+	version *model.ConfigVersion
+	value   []byte
+}
+
+func (r *configRevision) config() (*model.Config, error) {
+	var cfg model.Config
+	if err := json.Unmarshal(r.value, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal configuration %s", r.version.Id)
+	}
+	cfg.SetDefaults()
+	return &cfg, nil
+}
+
+// versionedBackingStore is implemented by the backing stores keeping every
+// configuration they persisted.
+type versionedBackingStore interface {
+	setWithAuthor(cfg *model.Config, authorID string) error
+	getRevisions(offset, limit int) ([]*configRevision, error)
+	getRevision(id string) (*configRevision, error)
+}
+
+func (s *Store) versionedBackingStore() (versionedBackingStore, error) {
+	vs, ok := s.backingStore.(versionedBackingStore)
+	if !ok {
+		return nil, ErrConfigHistoryUnsupported
+	}
+	return vs, nil
+}
+
+// GetHistory returns up to limit past configurations, newest first, along with the
+// settings each of them changed compared to the configuration it replaced.
+func (s *Store) GetHistory(offset, limit int) ([]*model.ConfigVersion, error) {
+	vs, err := s.versionedBackingStore()
+	if err != nil {
+		return nil, err
+	}
+
+	// The revision preceding the page is needed to compute the changes of the last version.
+	revisions, err := vs.getRevisions(offset, limit+1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get configuration history")
+	}
+
+	versions := make([]*model.ConfigVersion, 0, len(revisions))
+	for i, revision := range revisions {
+		if i == limit {
+			break
+		}
+
+		if i+1 < len(revisions) {
+			diffs, err := diffRevisions(revisions[i+1], revision)
+			if err != nil {
+				return nil, err
+			}
+			revision.version.ChangedPaths = make([]string, 0, len(diffs))
+			for _, d := range diffs {
+				revision.version.ChangedPaths = append(revision.version.ChangedPaths, d.Path)
+			}
+		}
+		versions = append(versions, revision.version)
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns the configuration persisted with the given version id.
+func (s *Store) GetVersion(id string) (*model.Config, error) {
+	vs, err := s.versionedBackingStore()
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := vs.getRevision(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return revision.config()
+}
+
+// DiffVersions compares two persisted configurations. An empty toID compares the
+// first version against the active configuration.
+func (s *Store) DiffVersions(fromID, toID string) (ConfigDiffs, error) {
+	vs, err := s.versionedBackingStore()
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := vs.getRevision(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := vs.getRevision(toID)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffRevisions(from, to)
+}
+
+func diffRevisions(base, actual *configRevision) (ConfigDiffs, error) {
+	baseCfg, err := base.config()
+	if err != nil {
+		return nil, err
+	}
+	actualCfg, err := actual.config()
+	if err != nil {
+		return nil, err
+	}
+
+	diffs, err := Diff(baseCfg, actualCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to diff configurations")
+	}
+	return diffs, nil
+}
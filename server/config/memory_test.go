@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
 )
 
 func setupConfigMemory(t *testing.T) {
@@ -203,3 +205,14 @@ func TestMemoryStoreString(t *testing.T) {
 
 	assert.Equal(t, "memory://", ms.String())
 }
+
+func TestMemoryStoreHistory(t *testing.T) {
+	ms := NewTestMemoryStore()
+	defer ms.Close()
+
+	_, err := ms.GetHistory(0, 10)
+	assert.ErrorIs(t, err, ErrConfigHistoryUnsupported)
+
+	_, err = ms.DiffVersions(model.NewId(), "")
+	assert.ErrorIs(t, err, ErrConfigHistoryUnsupported)
+}
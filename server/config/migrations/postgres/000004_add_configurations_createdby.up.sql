@@ -0,0 +1 @@
+ALTER TABLE Configurations ADD COLUMN IF NOT EXISTS CreatedBy varchar(26) DEFAULT '';
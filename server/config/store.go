@@ -13,6 +13,7 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/utils"
 )
 
@@ -35,6 +36,8 @@ type Store struct {
 
 	readOnly   bool
 	readOnlyFF bool
+
+	stopWatching func()
 }
 
 // BackingStore defines the behaviour exposed by the underlying store
@@ -67,6 +70,12 @@ type BackingStore interface {
 	Close() error
 }
 
+// watchableBackingStore is implemented by the backing stores whose configuration
+// can change outside of the server, calling onChange until stop is called.
+type watchableBackingStore interface {
+	watch(onChange func()) (stop func(), err error)
+}
+
 // NewStoreFromBacking creates and returns a new config store given a backing store.
 func NewStoreFromBacking(backingStore BackingStore, customDefaults *model.Config, readOnly bool) (*Store, error) {
 	store := &Store{
@@ -80,19 +89,30 @@ func NewStoreFromBacking(backingStore BackingStore, customDefaults *model.Config
 		return nil, errors.Wrap(err, "unable to load on store creation")
 	}
 
+	if ws, ok := backingStore.(watchableBackingStore); ok {
+		stop, err := ws.watch(store.reloadOnChange)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to watch the backing store")
+		}
+		store.stopWatching = stop
+	}
+
 	return store, nil
 }
 
-// NewStoreFromDSN creates and returns a new config store backed by either a database or file store
-// depending on the value of the given data source name string.
-func NewStoreFromDSN(dsn string, readOnly bool, customDefaults *model.Config, createFileIfNotExist bool) (*Store, error) {
-	var err error
-	var backingStore BackingStore
-	if IsDatabaseDSN(dsn) {
-		backingStore, err = NewDatabaseStore(dsn)
-	} else {
-		backingStore, err = NewFileStore(dsn, createFileIfNotExist)
+// reloadOnChange reloads the configuration after the backing store reported a change.
+func (s *Store) reloadOnChange() {
+	if err := s.Load(); err != nil {
+		mlog.Error("Failed to reload the configuration after a change", mlog.String("store", s.String()), mlog.Err(err))
+		return
 	}
+	mlog.Info("Reloaded the configuration after a change", mlog.String("store", s.String()))
+}
+
+// NewStoreFromDSN creates and returns a new config store backed by either a database, fragments or
+// file store depending on the value of the given data source name string.
+func NewStoreFromDSN(dsn string, readOnly bool, customDefaults *model.Config, createFileIfNotExist bool) (*Store, error) {
+	backingStore, err := newBackingStoreFromDSN(dsn, createFileIfNotExist)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +126,17 @@ func NewStoreFromDSN(dsn string, readOnly bool, customDefaults *model.Config, cr
 	return store, nil
 }
 
+func newBackingStoreFromDSN(dsn string, createFileIfNotExist bool) (BackingStore, error) {
+	switch {
+	case IsDatabaseDSN(dsn):
+		return NewDatabaseStore(dsn)
+	case IsFragmentsDSN(dsn):
+		return NewFragmentStoreFromDSN(dsn, createFileIfNotExist)
+	default:
+		return NewFileStore(dsn, createFileIfNotExist)
+	}
+}
+
 // NewTestMemoryStore returns a new config store backed by a memory store
 // to be used for testing purposes.
 func NewTestMemoryStore() *Store {
@@ -166,6 +197,16 @@ func (s *Store) SetReadOnlyFF(readOnly bool) {
 // Set replaces the current configuration in its entirety and updates the backing store.
 // It returns both old and new versions of the config.
 func (s *Store) Set(newCfg *model.Config) (*model.Config, *model.Config, error) {
+	return s.set(newCfg, "")
+}
+
+// SetWithAuthor behaves like Set, additionally recording the user who made the
+// change when the backing store keeps a history of the configurations.
+func (s *Store) SetWithAuthor(newCfg *model.Config, authorID string) (*model.Config, *model.Config, error) {
+	return s.set(newCfg, authorID)
+}
+
+func (s *Store) set(newCfg *model.Config, authorID string) (*model.Config, *model.Config, error) {
 	s.configLock.Lock()
 	defer s.configLock.Unlock()
 
@@ -210,7 +251,11 @@ func (s *Store) Set(newCfg *model.Config) (*model.Config, *model.Config, error)
 		newCfgNoEnv.FeatureFlags = nil
 	}
 
-	if err := s.backingStore.Set(newCfgNoEnv); err != nil {
+	if vs, ok := s.backingStore.(versionedBackingStore); ok && authorID != "" {
+		if err := vs.setWithAuthor(newCfgNoEnv, authorID); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to persist")
+		}
+	} else if err := s.backingStore.Set(newCfgNoEnv); err != nil {
 		return nil, nil, errors.Wrap(err, "failed to persist")
 	}
 
@@ -387,6 +432,11 @@ func (s *Store) String() string {
 
 // Close cleans up resources associated with the store.
 func (s *Store) Close() error {
+	// Stop watching before locking, as a reload in progress needs the lock to complete.
+	if s.stopWatching != nil {
+		s.stopWatching()
+	}
+
 	s.configLock.Lock()
 	defer s.configLock.Unlock()
 	return s.backingStore.Close()
@@ -33,6 +33,15 @@ func desanitize(actual, target *model.Config) {
 	if *target.FileSettings.AmazonS3SecretAccessKey == model.FakeSetting {
 		target.FileSettings.AmazonS3SecretAccessKey = actual.FileSettings.AmazonS3SecretAccessKey
 	}
+	if target.FileSettings.ColdAmazonS3SecretAccessKey != nil && *target.FileSettings.ColdAmazonS3SecretAccessKey == model.FakeSetting {
+		target.FileSettings.ColdAmazonS3SecretAccessKey = actual.FileSettings.ColdAmazonS3SecretAccessKey
+	}
+	if target.FileSettings.AzureStorageAccountKey != nil && *target.FileSettings.AzureStorageAccountKey == model.FakeSetting {
+		target.FileSettings.AzureStorageAccountKey = actual.FileSettings.AzureStorageAccountKey
+	}
+	if target.FileSettings.EncryptionMasterKey != nil && *target.FileSettings.EncryptionMasterKey == model.FakeSetting {
+		target.FileSettings.EncryptionMasterKey = actual.FileSettings.EncryptionMasterKey
+	}
 
 	if *target.EmailSettings.SMTPPassword == model.FakeSetting {
 		target.EmailSettings.SMTPPassword = actual.EmailSettings.SMTPPassword
@@ -54,6 +63,15 @@ func desanitize(actual, target *model.Config) {
 		target.OpenIdSettings.Secret = actual.OpenIdSettings.Secret
 	}
 
+	for _, provider := range target.OpenIdConnectSettings.Providers {
+		if provider.ClientSecret == nil || *provider.ClientSecret != model.FakeSetting || provider.Id == nil {
+			continue
+		}
+		if actualProvider := actual.OpenIdConnectSettings.GetProvider(provider.Service()); actualProvider != nil {
+			provider.ClientSecret = actualProvider.ClientSecret
+		}
+	}
+
 	if *target.SqlSettings.DataSource == model.FakeSetting {
 		*target.SqlSettings.DataSource = *actual.SqlSettings.DataSource
 	}
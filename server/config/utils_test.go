@@ -35,6 +35,10 @@ func TestDesanitize(t *testing.T) {
 	actual.SqlSettings.DataSourceReplicas = append(actual.SqlSettings.DataSourceReplicas, "replica1")
 	actual.SqlSettings.DataSourceSearchReplicas = append(actual.SqlSettings.DataSourceSearchReplicas, "search_replica0")
 	actual.SqlSettings.DataSourceSearchReplicas = append(actual.SqlSettings.DataSourceSearchReplicas, "search_replica1")
+	actual.OpenIdConnectSettings.Providers = []*model.OpenIdProviderSettings{
+		{Id: model.NewPointer("okta"), ClientSecret: model.NewPointer("okta_secret")},
+		{Id: model.NewPointer("keycloak"), ClientSecret: model.NewPointer("keycloak_secret")},
+	}
 
 	target := &model.Config{}
 	target.SetDefaults()
@@ -55,6 +59,10 @@ func TestDesanitize(t *testing.T) {
 	target.ElasticsearchSettings.Password = model.NewPointer(model.FakeSetting)
 	target.SqlSettings.DataSourceReplicas = []string{model.FakeSetting, model.FakeSetting}
 	target.SqlSettings.DataSourceSearchReplicas = []string{model.FakeSetting, model.FakeSetting}
+	target.OpenIdConnectSettings.Providers = []*model.OpenIdProviderSettings{
+		{Id: model.NewPointer("keycloak"), ClientSecret: model.NewPointer(model.FakeSetting)},
+		{Id: model.NewPointer("okta"), ClientSecret: model.NewPointer("new_okta_secret")},
+	}
 
 	actualClone := actual.Clone()
 	desanitize(actual, target)
@@ -77,6 +85,8 @@ func TestDesanitize(t *testing.T) {
 	assert.Equal(t, actual.SqlSettings.DataSourceReplicas, target.SqlSettings.DataSourceReplicas)
 	assert.Equal(t, actual.SqlSettings.DataSourceSearchReplicas, target.SqlSettings.DataSourceSearchReplicas)
 	assert.Equal(t, actual.ServiceSettings.SplitKey, target.ServiceSettings.SplitKey)
+	assert.Equal(t, "keycloak_secret", *target.OpenIdConnectSettings.Providers[0].ClientSecret)
+	assert.Equal(t, "new_okta_secret", *target.OpenIdConnectSettings.Providers[1].ClientSecret)
 }
 
 func TestFixInvalidLocales(t *testing.T) {
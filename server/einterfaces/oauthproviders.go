@@ -17,6 +17,12 @@ type OAuthProvider interface {
 	IsSameUser(c request.CTX, dbUser, oAuthUser *model.User) bool
 }
 
+// OAuthGroupsProvider is implemented by OAuth providers able to report the
+// groups a user belongs to. Memberships are synced on every login.
+type OAuthGroupsProvider interface {
+	GetGroupsFromJSON(c request.CTX, data io.Reader, tokenUser *model.User) ([]string, error)
+}
+
 var oauthProviders = make(map[string]OAuthProvider)
 
 func RegisterOAuthProvider(name string, newProvider OAuthProvider) {
@@ -64,6 +64,10 @@ func BLVChannelFromChannel(channel *model.Channel, userIDs, teamMemberIDs []stri
 }
 
 func BLVUserFromUserAndTeams(user *model.User, teamsIds, channelsIds []string) *BLVUser {
+	return BLVUserFromUserAndTeamsWithCustomAttributes(user, teamsIds, channelsIds, nil)
+}
+
+func BLVUserFromUserAndTeamsWithCustomAttributes(user *model.User, teamsIds, channelsIds, customAttributes []string) *BLVUser {
 	usernameSuggestions := searchengine.GetSuggestionInputsSplitByMultiple(user.Username, []string{".", "-", "_"})
 
 	fullnameStrings := []string{}
@@ -86,6 +90,9 @@ func BLVUserFromUserAndTeams(user *model.User, teamsIds, channelsIds []string) *
 	}
 
 	usernameAndNicknameSuggestions := append(usernameSuggestions, nicknameSuggestions...)
+	for _, attribute := range customAttributes {
+		usernameAndNicknameSuggestions = append(usernameAndNicknameSuggestions, searchengine.GetSuggestionInputsSplitBy(attribute, " ")...)
+	}
 
 	return &BLVUser{
 		Id:                         user.Id,
@@ -107,7 +114,7 @@ func BLVUserFromUserForIndexing(userForIndexing *model.UserForIndexing) *BLVUser
 		DeleteAt:  userForIndexing.DeleteAt,
 	}
 
-	return BLVUserFromUserAndTeams(user, userForIndexing.TeamsIds, userForIndexing.ChannelsIds)
+	return BLVUserFromUserAndTeamsWithCustomAttributes(user, userForIndexing.TeamsIds, userForIndexing.ChannelsIds, userForIndexing.CustomAttributes)
 }
 
 func BLVPostFromPost(post *model.Post, teamId string) *BLVPost {
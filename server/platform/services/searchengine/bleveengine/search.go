@@ -403,11 +403,15 @@ func (b *BleveEngine) DeleteChannel(channel *model.Channel) *model.AppError {
 	return nil
 }
 
-func (b *BleveEngine) IndexUser(_ request.CTX, user *model.User, teamsIds, channelsIds []string) *model.AppError {
+func (b *BleveEngine) IndexUser(rctx request.CTX, user *model.User, teamsIds, channelsIds []string) *model.AppError {
+	return b.IndexUserWithCustomAttributes(rctx, user, teamsIds, channelsIds, nil)
+}
+
+func (b *BleveEngine) IndexUserWithCustomAttributes(_ request.CTX, user *model.User, teamsIds, channelsIds, customAttributes []string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	blvUser := BLVUserFromUserAndTeams(user, teamsIds, channelsIds)
+	blvUser := BLVUserFromUserAndTeamsWithCustomAttributes(user, teamsIds, channelsIds, customAttributes)
 	if err := b.UserIndex.Index(blvUser.Id, blvUser); err != nil {
 		return model.NewAppError("Bleveengine.IndexUser", "bleveengine.index_user.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -52,3 +52,11 @@ type SearchEngineInterface interface {
 	DataRetentionDeleteIndexes(rctx request.CTX, cutoff time.Time) *model.AppError
 	IsChannelsIndexVerified() bool
 }
+
+// CustomAttributesUserIndexer is implemented by the engines able to search the users by
+// their custom profile attributes.
+type CustomAttributesUserIndexer interface {
+	// IndexUserWithCustomAttributes indexes a given user like IndexUser. The
+	// customAttributes are the values of the custom profile fields visible to everyone.
+	IndexUserWithCustomAttributes(rctx request.CTX, user *model.User, teamsIds, channelsIds, customAttributes []string) *model.AppError
+}
@@ -78,6 +78,8 @@ const (
 	TrackConfigExport              = "config_export"
 	TrackConfigWrangler            = "config_wrangler"
 	TrackConfigConnectedWorkspaces = "config_connected_workspaces"
+	TrackConfigScim                = "config_scim"
+	TrackConfigAccessControl       = "config_access_control"
 	TrackFeatureFlags              = "config_feature_flags"
 	TrackPermissionsGeneral        = "permissions_general"
 	TrackPermissionsSystemScheme   = "permissions_system_scheme"
@@ -424,6 +426,9 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_client_performance_debugging":                     *cfg.ServiceSettings.EnableClientPerformanceDebugging,
 		"enable_multifactor_authentication":                       *cfg.ServiceSettings.EnableMultifactorAuthentication,
 		"enforce_multifactor_authentication":                      *cfg.ServiceSettings.EnforceMultifactorAuthentication,
+		"enable_webauthn":                                         *cfg.ServiceSettings.EnableWebAuthn,
+		"enable_webauthn_passwordless_login":                      *cfg.ServiceSettings.EnableWebAuthnPasswordlessLogin,
+		"mfa_trusted_device_days":                                 *cfg.ServiceSettings.MfaTrustedDeviceDays,
 		"enable_oauth_service_provider":                           cfg.ServiceSettings.EnableOAuthServiceProvider,
 		"connection_security":                                     *cfg.ServiceSettings.ConnectionSecurity,
 		"tls_strict_transport":                                    *cfg.ServiceSettings.TLSStrictTransport,
@@ -437,6 +442,11 @@ func (ts *TelemetryService) trackConfig() {
 		"session_length_sso_in_hours":                             *cfg.ServiceSettings.SessionLengthSSOInHours,
 		"session_cache_in_minutes":                                *cfg.ServiceSettings.SessionCacheInMinutes,
 		"session_idle_timeout_in_minutes":                         *cfg.ServiceSettings.SessionIdleTimeoutInMinutes,
+		"maximum_web_sessions_per_user":                           *cfg.ServiceSettings.MaximumWebSessionsPerUser,
+		"maximum_desktop_sessions_per_user":                       *cfg.ServiceSettings.MaximumDesktopSessionsPerUser,
+		"maximum_mobile_sessions_per_user":                        *cfg.ServiceSettings.MaximumMobileSessionsPerUser,
+		"enable_login_alerts":                                     *cfg.ServiceSettings.EnableLoginAlerts,
+		"isdefault_geoip_database_file":                           isDefault(*cfg.ServiceSettings.GeoIPDatabaseFile, ""),
 		"isdefault_site_url":                                      isDefault(*cfg.ServiceSettings.SiteURL, model.ServiceSettingsDefaultSiteURL),
 		"isdefault_tls_cert_file":                                 isDefault(*cfg.ServiceSettings.TLSCertFile, model.ServiceSettingsDefaultTLSCertFile),
 		"isdefault_tls_key_file":                                  isDefault(*cfg.ServiceSettings.TLSKeyFile, model.ServiceSettingsDefaultTLSKeyFile),
@@ -495,6 +505,9 @@ func (ts *TelemetryService) trackConfig() {
 		"refresh_post_stats_run_time":                             *cfg.ServiceSettings.RefreshPostStatsRunTime,
 		"maximum_payload_size":                                    *cfg.ServiceSettings.MaximumPayloadSizeBytes,
 		"maximum_url_length":                                      *cfg.ServiceSettings.MaximumURLLength,
+		"enable_websocket_event_log":                              *cfg.ServiceSettings.EnableWebSocketEventLog,
+		"websocket_event_log_retention_minutes":                   *cfg.ServiceSettings.WebSocketEventLogRetentionMinutes,
+		"enable_notification_rules":                               *cfg.ServiceSettings.EnableNotificationRules,
 	})
 
 	ts.SendTelemetry(TrackConfigTeam, map[string]any{
@@ -556,13 +569,17 @@ func (ts *TelemetryService) trackConfig() {
 	})
 
 	ts.SendTelemetry(TrackConfigAudit, map[string]any{
-		"file_enabled":          *cfg.ExperimentalAuditSettings.FileEnabled,
-		"file_max_size_mb":      *cfg.ExperimentalAuditSettings.FileMaxSizeMB,
-		"file_max_age_days":     *cfg.ExperimentalAuditSettings.FileMaxAgeDays,
-		"file_max_backups":      *cfg.ExperimentalAuditSettings.FileMaxBackups,
-		"file_compress":         *cfg.ExperimentalAuditSettings.FileCompress,
-		"file_max_queue_size":   *cfg.ExperimentalAuditSettings.FileMaxQueueSize,
-		"advanced_logging_json": len(cfg.ExperimentalAuditSettings.AdvancedLoggingJSON) != 0,
+		"file_enabled":            *cfg.ExperimentalAuditSettings.FileEnabled,
+		"file_max_size_mb":        *cfg.ExperimentalAuditSettings.FileMaxSizeMB,
+		"file_max_age_days":       *cfg.ExperimentalAuditSettings.FileMaxAgeDays,
+		"file_max_backups":        *cfg.ExperimentalAuditSettings.FileMaxBackups,
+		"file_compress":           *cfg.ExperimentalAuditSettings.FileCompress,
+		"file_max_queue_size":     *cfg.ExperimentalAuditSettings.FileMaxQueueSize,
+		"advanced_logging_json":   len(cfg.ExperimentalAuditSettings.AdvancedLoggingJSON) != 0,
+		"enable_hash_chain":       *cfg.ExperimentalAuditSettings.EnableHashChain,
+		"checkpoint_interval":     *cfg.ExperimentalAuditSettings.CheckpointInterval,
+		"signed_checkpoints":      *cfg.ExperimentalAuditSettings.CheckpointSigningKeyFile != "",
+		"enable_database_storage": *cfg.ExperimentalAuditSettings.EnableDatabaseStorage,
 	})
 
 	ts.SendTelemetry(TrackConfigNotificationLog, map[string]any{
@@ -577,11 +594,18 @@ func (ts *TelemetryService) trackConfig() {
 	})
 
 	ts.SendTelemetry(TrackConfigPassword, map[string]any{
-		"minimum_length": *cfg.PasswordSettings.MinimumLength,
-		"lowercase":      *cfg.PasswordSettings.Lowercase,
-		"number":         *cfg.PasswordSettings.Number,
-		"uppercase":      *cfg.PasswordSettings.Uppercase,
-		"symbol":         *cfg.PasswordSettings.Symbol,
+		"minimum_length":                        *cfg.PasswordSettings.MinimumLength,
+		"lowercase":                             *cfg.PasswordSettings.Lowercase,
+		"number":                                *cfg.PasswordSettings.Number,
+		"uppercase":                             *cfg.PasswordSettings.Uppercase,
+		"symbol":                                *cfg.PasswordSettings.Symbol,
+		"hash_algorithm":                        *cfg.PasswordSettings.HashAlgorithm,
+		"argon2id_memory_kib":                   *cfg.PasswordSettings.Argon2idMemoryKiB,
+		"argon2id_iterations":                   *cfg.PasswordSettings.Argon2idIterations,
+		"argon2id_parallelism":                  *cfg.PasswordSettings.Argon2idParallelism,
+		"isdefault_breached_password_directory": isDefault(*cfg.PasswordSettings.BreachedPasswordDirectory, ""),
+		"history_count":                         *cfg.PasswordSettings.HistoryCount,
+		"expiry_days":                           *cfg.PasswordSettings.ExpiryDays,
 	})
 
 	ts.SendTelemetry(TrackConfigFile, map[string]any{
@@ -595,6 +619,10 @@ func (ts *TelemetryService) trackConfig() {
 		"amazon_s3_sse":                 *cfg.FileSettings.AmazonS3SSE,
 		"amazon_s3_signv2":              *cfg.FileSettings.AmazonS3SignV2,
 		"amazon_s3_trace":               *cfg.FileSettings.AmazonS3Trace,
+		"enable_encryption":             *cfg.FileSettings.EnableEncryption,
+		"enable_cold_storage":           *cfg.FileSettings.EnableColdStorage,
+		"cold_storage_after_days":       *cfg.FileSettings.ColdStorageAfterDays,
+		"cold_driver_name":              *cfg.FileSettings.ColdDriverName,
 		"max_file_size":                 *cfg.FileSettings.MaxFileSize,
 		"max_image_resolution":          *cfg.FileSettings.MaxImageResolution,
 		"max_image_decoder_concurrency": *cfg.FileSettings.MaxImageDecoderConcurrency,
@@ -618,6 +646,12 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_email_batching":                *cfg.EmailSettings.EnableEmailBatching,
 		"email_batching_buffer_size":           *cfg.EmailSettings.EmailBatchingBufferSize,
 		"email_batching_interval":              *cfg.EmailSettings.EmailBatchingInterval,
+		"enable_email_digests":                 *cfg.EmailSettings.EnableEmailDigests,
+		"enable_reply_by_email":                *cfg.EmailSettings.EnableReplyByEmail,
+		"isdefault_reply_by_email_address":     isDefault(*cfg.EmailSettings.ReplyByEmailAddress, ""),
+		"enable_post_by_email":                 *cfg.EmailSettings.EnablePostByEmail,
+		"isdefault_post_by_email_address":      isDefault(*cfg.EmailSettings.PostByEmailAddress, ""),
+		"inbound_email_max_message_size":       *cfg.EmailSettings.InboundEmailMaxMessageSize,
 		"enable_preview_mode_banner":           *cfg.EmailSettings.EnablePreviewModeBanner,
 		"isdefault_feedback_name":              isDefault(cfg.EmailSettings.FeedbackName, ""),
 		"isdefault_feedback_email":             isDefault(cfg.EmailSettings.FeedbackEmail, ""),
@@ -896,6 +930,15 @@ func (ts *TelemetryService) trackConfig() {
 		"disable_shared_channels_status_sync": *cfg.ConnectedWorkspacesSettings.DisableSharedChannelsStatusSync,
 	})
 
+	ts.SendTelemetry(TrackConfigScim, map[string]any{
+		"enable":           *cfg.ScimSettings.Enable,
+		"use_auth_service": *cfg.ScimSettings.AuthService != "",
+	})
+
+	ts.SendTelemetry(TrackConfigAccessControl, map[string]any{
+		"enable_attribute_based_access_control": *cfg.AccessControlSettings.EnableAttributeBasedAccessControl,
+	})
+
 	// Convert feature flags to map[string]any for sending
 	flags := cfg.FeatureFlags.ToMap()
 	interfaceFlags := make(map[string]any)
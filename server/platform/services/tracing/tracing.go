@@ -5,83 +5,153 @@ package tracing
 
 import (
 	"context"
-	"io"
+	"fmt"
+	"net/http"
 	"time"
 
-	opentracing "github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	"github.com/uber/jaeger-client-go/zipkin"
-	"github.com/uber/jaeger-lib/metrics"
-
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
-// Tracer is a wrapper around Jaeger OpenTracing client, used to properly de-initialize jaeger on exit
-type Tracer struct {
-	closer io.Closer
-}
+const (
+	serviceName = "mattermost"
+	tracerName  = "github.com/mattermost/mattermost/server/v8"
 
-type LogrusAdapter struct {
-}
-
-// Error - logrus adapter for span errors
-func (LogrusAdapter) Error(msg string) {
-	mlog.Error(msg)
-}
+	shutdownTimeout = 5 * time.Second
+)
 
-// Infof - logrus adapter for span info logging
-func (LogrusAdapter) Infof(msg string, args ...any) {
-	// we ignore Info messages from opentracing
+// Tracer is a wrapper around the OpenTelemetry tracer provider, used to flush the pending spans on exit
+type Tracer struct {
+	provider *sdktrace.TracerProvider
 }
 
-// New instantiates Jaeger opentracing client with default options
-// To override the defaults use environment variables listed here: https://github.com/jaegertracing/jaeger-client-go/blob/master/config/config.go
+// New instantiates the OpenTelemetry tracer provider, exporting the spans with OTLP over HTTP to a
+// collector listening on localhost:4318 by default, and propagating the W3C trace context.
+// To override the defaults use the environment variables listed here: https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/
+// e.g. OTEL_EXPORTER_OTLP_ENDPOINT to point at another collector or OTEL_TRACES_SAMPLER to sample the traces.
 func New() (*Tracer, error) {
-	cfg := jaegercfg.Configuration{
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1,
-		},
-		Reporter: &jaegercfg.ReporterConfig{
-			LogSpans: true,
-		},
-	}
+	ctx := context.Background()
 
-	zipkinPropagator := zipkin.NewZipkinB3HTTPHeaderPropagator()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+	}
 
-	closer, err := cfg.InitGlobalTracer(
-		"mattermost",
-		jaegercfg.Logger(LogrusAdapter{}),
-		jaegercfg.Metrics(metrics.NullFactory),
-		jaegercfg.Tag("serverStartTime", time.Now().UTC().Format(time.RFC3339)),
-		jaegercfg.Injector(opentracing.HTTPHeaders, zipkinPropagator),
-		jaegercfg.Extractor(opentracing.HTTPHeaders, zipkinPropagator),
-		jaegercfg.ZipkinSharedRPCSpan(true),
+	// The attributes from the environment are detected last so that they can override the service name.
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(model.CurrentVersion),
+			attribute.String("server.start_time", time.Now().UTC().Format(time.RFC3339)),
+		),
+		resource.WithFromEnv(),
 	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create the tracing resource: %w", err)
 	}
-	mlog.Info("Opentracing initialized")
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		mlog.Error("OpenTelemetry error", mlog.Err(err))
+	}))
+
+	mlog.Info("OpenTelemetry tracing initialized")
 	return &Tracer{
-		closer: closer,
+		provider: provider,
 	}, nil
 }
 
+// Close exports the pending spans and stops the tracer provider.
 func (t *Tracer) Close() error {
-	return t.closer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return t.provider.Shutdown(ctx)
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
 }
 
-func StartRootSpanByContext(ctx context.Context, operationName string) (opentracing.Span, context.Context) {
-	return opentracing.StartSpanFromContext(ctx, operationName)
+// StartRootSpanByContext starts a span of the given kind, child of the remote span of the context if any.
+func StartRootSpanByContext(ctx context.Context, operationName string, kind trace.SpanKind, opts ...trace.SpanStartOption) (trace.Span, context.Context) {
+	ctx, span := tracer().Start(ctx, operationName, append(opts, trace.WithSpanKind(kind))...)
+	return span, ctx
 }
 
-func StartSpanWithParentByContext(ctx context.Context, operationName string) (opentracing.Span, context.Context) {
-	parentSpan := opentracing.SpanFromContext(ctx)
+// StartSpanWithParentByContext starts a span, child of the span of the context if any.
+func StartSpanWithParentByContext(ctx context.Context, operationName string) (trace.Span, context.Context) {
+	ctx, span := tracer().Start(ctx, operationName)
+	return span, ctx
+}
 
-	if parentSpan == nil {
-		return StartRootSpanByContext(ctx, operationName)
+// Attribute converts a value of any type to a span attribute.
+func Attribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	default:
+		return attribute.String(key, fmt.Sprintf("%v", v))
 	}
+}
+
+// SetError records the error on the span and marks the span as failed.
+func SetError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
 
-	return opentracing.StartSpanFromContext(ctx, operationName, opentracing.ChildOf(parentSpan.Context()))
+// ExtractHTTPHeaders returns a copy of the context with the trace context propagated in the headers.
+func ExtractHTTPHeaders(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectHTTPHeaders propagates the trace context of the context in the headers. It does nothing
+// when tracing is disabled.
+func InjectHTTPHeaders(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// TraceParent returns the W3C trace context of the span of the context, to propagate it
+// where headers are not available, e.g. to the plugins. It is empty when tracing is
+// disabled or the context has no span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// ContextWithTraceParent returns a copy of the context with the W3C trace context returned
+// by TraceParent.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
 }
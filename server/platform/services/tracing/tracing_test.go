@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	return recorder
+}
+
+func TestAttribute(t *testing.T) {
+	assert.Equal(t, attribute.String("key", "value"), Attribute("key", "value"))
+	assert.Equal(t, attribute.Bool("key", true), Attribute("key", true))
+	assert.Equal(t, attribute.Int("key", 3), Attribute("key", 3))
+	assert.Equal(t, attribute.Int64("key", 3), Attribute("key", int64(3)))
+	assert.Equal(t, attribute.StringSlice("key", []string{"a", "b"}), Attribute("key", []string{"a", "b"}))
+	assert.Equal(t, attribute.String("key", "{a 1}"), Attribute("key", struct {
+		A string
+		B int
+	}{"a", 1}))
+}
+
+func TestSpans(t *testing.T) {
+	recorder := setupTestTracing(t)
+
+	span, ctx := StartRootSpanByContext(context.Background(), "root", trace.SpanKindServer)
+	child, _ := StartSpanWithParentByContext(ctx, "child")
+	SetError(child, errors.New("failed"))
+	child.End()
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "failed", spans[0].Status().Description)
+	assert.Equal(t, "root", spans[1].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[1].SpanKind())
+	assert.False(t, spans[1].Parent().IsValid())
+}
+
+func TestHTTPHeadersPropagation(t *testing.T) {
+	recorder := setupTestTracing(t)
+
+	span, ctx := StartRootSpanByContext(context.Background(), "client", trace.SpanKindClient)
+	header := http.Header{}
+	InjectHTTPHeaders(ctx, header)
+	span.End()
+	require.NotEmpty(t, header.Get("traceparent"))
+
+	remote, _ := StartRootSpanByContext(ExtractHTTPHeaders(context.Background(), header), "server", trace.SpanKindServer)
+	remote.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, span.SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.True(t, spans[1].Parent().IsRemote())
+
+	t.Run("nothing is propagated without a span", func(t *testing.T) {
+		header := http.Header{}
+		InjectHTTPHeaders(context.Background(), header)
+		assert.Empty(t, header)
+	})
+}
+
+func TestTraceParentPropagation(t *testing.T) {
+	recorder := setupTestTracing(t)
+
+	span, ctx := StartRootSpanByContext(context.Background(), "request", trace.SpanKindServer)
+	traceParent := TraceParent(ctx)
+	span.End()
+	require.NotEmpty(t, traceParent)
+
+	hook, _ := StartRootSpanByContext(ContextWithTraceParent(context.Background(), traceParent), "hook", trace.SpanKindClient)
+	hook.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, span.SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[1].Parent().SpanID())
+
+	t.Run("nothing is propagated without a span", func(t *testing.T) {
+		assert.Empty(t, TraceParent(context.Background()))
+		assert.Equal(t, context.Background(), ContextWithTraceParent(context.Background(), ""))
+	})
+}
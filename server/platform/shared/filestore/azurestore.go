@@ -0,0 +1,457 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// azureBlockIDSize matches the size of the block IDs generated by the SDK,
+	// since all the blocks of a blob must have IDs of the same length.
+	azureBlockIDSize = 64
+	// azureAppendBlockSize is the size of the blocks staged when appending data.
+	azureAppendBlockSize = 8 * 1024 * 1024
+
+	defaultPresignExpires = 6 * time.Hour
+)
+
+// AzureFileBackend stores files as block blobs in an Azure Blob Storage container.
+type AzureFileBackend struct {
+	accountName    string
+	container      string
+	pathPrefix     string
+	timeout        time.Duration
+	presignExpires time.Duration
+	credential     *container.SharedKeyCredential
+	client         *container.Client
+}
+
+type AzureFileBackendAuthError struct {
+	DetailedError string
+}
+
+// AzureFileBackendNoContainerError is returned when testing a connection and no container is found
+type AzureFileBackendNoContainerError struct{}
+
+var _ FileBackendWithLinkGenerator = (*AzureFileBackend)(nil)
+
+func (s *AzureFileBackendAuthError) Error() string {
+	return s.DetailedError
+}
+
+func (s *AzureFileBackendNoContainerError) Error() string {
+	return "no such container"
+}
+
+// NewAzureFileBackend returns an instance of an AzureFileBackend authenticated with the storage account shared key.
+func NewAzureFileBackend(settings FileBackendSettings) (*AzureFileBackend, error) {
+	endpoint := settings.AzureStorageEndpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", settings.AzureStorageAccountName)
+	}
+	containerURL := strings.TrimRight(endpoint, "/") + "/" + settings.AzureStorageContainer
+
+	cred, err := container.NewSharedKeyCredential(settings.AzureStorageAccountName, settings.AzureStorageAccountKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid azure storage account credentials")
+	}
+	client, err := container.NewClientWithSharedKeyCredential(containerURL, cred, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the azure storage client")
+	}
+
+	presignExpires := time.Duration(settings.AzureStoragePresignExpiresSeconds) * time.Second
+	if presignExpires == 0 {
+		presignExpires = defaultPresignExpires
+	}
+
+	return &AzureFileBackend{
+		accountName:    settings.AzureStorageAccountName,
+		container:      settings.AzureStorageContainer,
+		pathPrefix:     settings.AzureStoragePathPrefix,
+		timeout:        time.Duration(settings.AzureStorageRequestTimeoutMilliseconds) * time.Millisecond,
+		presignExpires: presignExpires,
+		credential:     cred,
+		client:         client,
+	}, nil
+}
+
+func (b *AzureFileBackend) DriverName() string {
+	return driverAzure
+}
+
+func (b *AzureFileBackend) TestConnection() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.client.GetProperties(ctx, nil); err != nil {
+		if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+			return &AzureFileBackendNoContainerError{}
+		}
+		return &AzureFileBackendAuthError{DetailedError: "unable to check if the azure storage container exists"}
+	}
+	mlog.Debug("Connection to Azure Blob Storage is good. Container exists.")
+	return nil
+}
+
+// MakeBucket creates the container holding the files.
+func (b *AzureFileBackend) MakeBucket() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.client.Create(ctx, nil); err != nil {
+		return errors.Wrap(err, "unable to create the azure storage container")
+	}
+	return nil
+}
+
+func (b *AzureFileBackend) blobName(path string) string {
+	return filepath.Join(b.pathPrefix, path)
+}
+
+func (b *AzureFileBackend) blockBlob(path string) *blockblob.Client {
+	return b.client.NewBlockBlobClient(b.blobName(path))
+}
+
+// Caller must close the first return value
+func (b *AzureFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	size, err := b.FileSize(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	blobClient := b.blockBlob(path)
+	return newRangeReader(size, b.timeout, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		resp, err := blobClient.DownloadStream(ctx, &blob.DownloadStreamOptions{
+			Range: blob.HTTPRange{Offset: offset},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read file %s", path)
+		}
+		return resp.Body, nil
+	}), nil
+}
+
+func (b *AzureFileBackend) ReadFile(path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	resp, err := b.blockBlob(path).DownloadStream(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	defer resp.Body.Close()
+	f, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return f, nil
+}
+
+func (b *AzureFileBackend) getProperties(path string) (blob.GetPropertiesResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	return b.blockBlob(path).GetProperties(ctx, nil)
+}
+
+func (b *AzureFileBackend) FileExists(path string) (bool, error) {
+	_, err := b.getProperties(path)
+	if err == nil {
+		return true, nil
+	}
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "unable to know if file %s exists", path)
+}
+
+func (b *AzureFileBackend) FileSize(path string) (int64, error) {
+	props, err := b.getProperties(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+	}
+	if props.ContentLength == nil {
+		return 0, nil
+	}
+	return *props.ContentLength, nil
+}
+
+func (b *AzureFileBackend) FileModTime(path string) (time.Time, error) {
+	props, err := b.getProperties(path)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", path)
+	}
+	if props.LastModified == nil {
+		return time.Time{}, nil
+	}
+	return *props.LastModified, nil
+}
+
+func (b *AzureFileBackend) CopyFile(oldPath, newPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	dst := b.blockBlob(newPath)
+	resp, err := dst.StartCopyFromURL(ctx, b.blockBlob(oldPath).URL(), nil)
+	if err != nil {
+		return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+	}
+
+	// Copies within the same storage account usually complete synchronously,
+	// but the service is allowed to schedule them.
+	status := resp.CopyStatus
+	for status != nil && *status == blob.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "unable to copy file from %s to %s", oldPath, newPath)
+		case <-time.After(100 * time.Millisecond):
+		}
+		props, err := dst.GetProperties(ctx, nil)
+		if err != nil {
+			return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+		}
+		status = props.CopyStatus
+	}
+	if status != nil && *status != blob.CopyStatusTypeSuccess {
+		return errors.Errorf("unable to copy file from %s to %s: copy status %s", oldPath, newPath, *status)
+	}
+
+	return nil
+}
+
+func (b *AzureFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.CopyFile(oldPath, newPath); err != nil {
+		return errors.Wrapf(err, "unable to copy the file to %s to the new destination", newPath)
+	}
+	if err := b.RemoveFile(oldPath); err != nil {
+		return errors.Wrapf(err, "unable to remove the file old file %s", oldPath)
+	}
+	return nil
+}
+
+func (b *AzureFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	return b.WriteFileContext(ctx, fr, path)
+}
+
+func (b *AzureFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	contentType := contentTypeForPath(path)
+	counter := &countingReader{r: fr}
+	_, err := b.blockBlob(path).UploadStream(ctx, counter, &blockblob.UploadStreamOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable write the data in the file %s", path)
+	}
+	return counter.n, nil
+}
+
+// AppendFile stages the new data as additional blocks and commits them after
+// the existing ones, so that appending never downloads the blob.
+func (b *AzureFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	blockBlob := b.blockBlob(path)
+	props, err := blockBlob.GetProperties(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", path)
+	}
+	list, err := blockBlob.GetBlockList(ctx, blockblob.BlockListTypeCommitted, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+
+	var blockIDs []string
+	for _, block := range list.BlockList.CommittedBlocks {
+		blockIDs = append(blockIDs, *block.Name)
+	}
+
+	// Blobs uploaded in a single request have no blocks, so their content
+	// has to be staged again before anything can be added to it.
+	if len(blockIDs) == 0 && props.ContentLength != nil && *props.ContentLength > 0 {
+		resp, err2 := blockBlob.DownloadStream(ctx, nil)
+		if err2 != nil {
+			return 0, errors.Wrapf(err2, "unable append the data in the file %s", path)
+		}
+		ids, _, err2 := b.stageBlocks(ctx, blockBlob, resp.Body)
+		resp.Body.Close()
+		if err2 != nil {
+			return 0, errors.Wrapf(err2, "unable append the data in the file %s", path)
+		}
+		blockIDs = ids
+	}
+
+	ids, written, err := b.stageBlocks(ctx, blockBlob, fr)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+
+	contentType := contentTypeForPath(path)
+	if _, err := blockBlob.CommitBlockList(ctx, append(blockIDs, ids...), &blockblob.CommitBlockListOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	}); err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+
+	return written, nil
+}
+
+func (b *AzureFileBackend) stageBlocks(ctx context.Context, blockBlob *blockblob.Client, r io.Reader) ([]string, int64, error) {
+	var (
+		ids     []string
+		written int64
+	)
+	buf := make([]byte, azureAppendBlockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			id, err2 := newAzureBlockID()
+			if err2 != nil {
+				return nil, 0, err2
+			}
+			if _, err2 := blockBlob.StageBlock(ctx, id, streaming.NopCloser(bytes.NewReader(buf[:n])), nil); err2 != nil {
+				return nil, 0, errors.Wrap(err2, "unable to stage block")
+			}
+			ids = append(ids, id)
+			written += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ids, written, nil
+		}
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "unable to read the data")
+		}
+	}
+}
+
+func newAzureBlockID() (string, error) {
+	id := make([]byte, azureBlockIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", errors.Wrap(err, "unable to generate block id")
+	}
+	return base64.StdEncoding.EncodeToString(id), nil
+}
+
+func (b *AzureFileBackend) RemoveFile(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.blockBlob(path).Delete(ctx, nil); err != nil {
+		return errors.Wrapf(err, "unable to remove the file %s", path)
+	}
+	return nil
+}
+
+func (b *AzureFileBackend) listPrefix(path string) string {
+	prefix := b.blobName(path)
+	if prefix == "." {
+		prefix = ""
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// trimPrefix strips the path prefix, so that it remains transparent to the application.
+func (b *AzureFileBackend) trimPrefix(name string) string {
+	return strings.Trim(strings.TrimPrefix(name, b.pathPrefix), "/")
+}
+
+func (b *AzureFileBackend) ListDirectory(path string) ([]string, error) {
+	prefix := b.listPrefix(path)
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	var paths []string
+	pager := b.client.NewListBlobsHierarchyPager("/", &container.ListBlobsHierarchyOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the directory %s", path)
+		}
+		for _, p := range page.Segment.BlobPrefixes {
+			if trimmed := b.trimPrefix(*p.Name); trimmed != "" {
+				paths = append(paths, trimmed)
+			}
+		}
+		for _, item := range page.Segment.BlobItems {
+			if trimmed := b.trimPrefix(*item.Name); trimmed != "" {
+				paths = append(paths, trimmed)
+			}
+		}
+	}
+	return paths, nil
+}
+
+func (b *AzureFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	prefix := b.listPrefix(path)
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	var paths []string
+	pager := b.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the directory %s", path)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if trimmed := b.trimPrefix(*item.Name); trimmed != "" {
+				paths = append(paths, trimmed)
+			}
+		}
+	}
+	return paths, nil
+}
+
+func (b *AzureFileBackend) RemoveDirectory(path string) error {
+	paths, err := b.ListDirectoryRecursively(path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove the directory %s", path)
+	}
+	for _, p := range paths {
+		if err := b.RemoveFile(p); err != nil {
+			if bloberror.HasCode(err, bloberror.BlobNotFound) {
+				continue
+			}
+			return errors.Wrapf(err, "unable to remove the directory %s", path)
+		}
+	}
+	return nil
+}
+
+func (b *AzureFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	// The file is always downloaded rather than rendered by the browser, like
+	// with the other backends.
+	params, err := sas.BlobSignatureValues{
+		Version:            sas.Version,
+		ExpiryTime:         time.Now().Add(b.presignExpires).UTC(),
+		Permissions:        (&sas.BlobPermissions{Read: true}).String(),
+		ContainerName:      b.container,
+		BlobName:           b.blobName(path),
+		ContentDisposition: "attachment",
+	}.SignWithSharedKey(b.credential)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to generate public link for %s", path)
+	}
+	return b.blockBlob(path).URL() + "?" + params.Encode(), b.presignExpires, nil
+}
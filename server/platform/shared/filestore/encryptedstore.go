@@ -0,0 +1,727 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// EncryptionKeyFileSuffix is appended to the path of an encrypted file to
+	// get the path of the file holding its wrapped data key.
+	EncryptionKeyFileSuffix = ".mmenc"
+
+	encryptionHeaderVersion = 2
+	encryptionChunkSize     = 64 * 1024
+	encryptionNoncePrefix   = 4
+	encryptionTagSize       = 16
+)
+
+// encryptionHeader is stored next to every encrypted file. Keeping it apart
+// from the data allows re-wrapping the data key without rewriting the file.
+type encryptionHeader struct {
+	Version     int    `json:"version"`
+	KeyID       string `json:"key_id"`
+	WrappedKey  []byte `json:"wrapped_key"`
+	NoncePrefix []byte `json:"nonce_prefix"`
+	ChunkSize   int64  `json:"chunk_size"`
+
+	// Segments lists the separately sealed parts of the file in order. Files
+	// written with the first version of the header have a single segment
+	// spanning the whole file and no list.
+	Segments []encryptionSegment `json:"segments,omitempty"`
+	// SegmentsMAC authenticates Segments, so that the segments cannot be
+	// reordered or dropped from the end of the file.
+	SegmentsMAC []byte `json:"segments_mac,omitempty"`
+}
+
+// encryptionSegment locates a sealed segment in the encrypted file.
+type encryptionSegment struct {
+	Offset int64 `json:"offset"`
+	Size   int64 `json:"size"`
+}
+
+// EncryptedFileBackend wraps another FileBackend and transparently encrypts
+// file contents with AES-GCM. Every file gets its own random data key, which
+// is wrapped with the active master key of the keyring.
+//
+// Contents are sealed in fixed size chunks so that readers can seek without
+// decrypting the whole file. The index of every chunk, and whether it is the
+// last one, are authenticated so that chunks cannot be reordered and the file
+// cannot be truncated on a chunk boundary. Appended data is sealed as a new
+// segment of chunks, so that appending never rewrites the existing contents.
+// Files written before encryption was enabled have no key file and are read
+// as plaintext.
+type EncryptedFileBackend struct {
+	backend FileBackend
+	keyring *Keyring
+}
+
+func NewEncryptedFileBackend(backend FileBackend, keyring *Keyring) *EncryptedFileBackend {
+	return &EncryptedFileBackend{
+		backend: backend,
+		keyring: keyring,
+	}
+}
+
+// Unwrap returns the backend holding the encrypted files.
+func (b *EncryptedFileBackend) Unwrap() FileBackend {
+	return b.backend
+}
+
+func (b *EncryptedFileBackend) DriverName() string {
+	return b.backend.DriverName()
+}
+
+func (b *EncryptedFileBackend) TestConnection() error {
+	return b.backend.TestConnection()
+}
+
+// EncryptedFileBackendWithLinkGenerator is an EncryptedFileBackend wrapping a
+// backend able to generate public links.
+type EncryptedFileBackendWithLinkGenerator struct {
+	*EncryptedFileBackend
+}
+
+// GeneratePublicLink forwards to the wrapped backend. Links to encrypted files
+// are refused, since they would serve the ciphertext.
+func (b *EncryptedFileBackendWithLinkGenerator) GeneratePublicLink(path string) (string, time.Duration, error) {
+	h, err := b.header(path)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to generate a public link to %s", path)
+	}
+	if h != nil {
+		return "", 0, errors.Errorf("unable to generate a public link to the encrypted file %s", path)
+	}
+	return b.backend.(FileBackendWithLinkGenerator).GeneratePublicLink(path)
+}
+
+func (b *EncryptedFileBackend) header(path string) (*encryptionHeader, error) {
+	ok, err := b.backend.FileExists(path + EncryptionKeyFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := b.backend.ReadFile(path + EncryptionKeyFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+	var h encryptionHeader
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the encryption header of %s", path)
+	}
+	if h.Version < 1 || h.Version > encryptionHeaderVersion {
+		return nil, errors.Errorf("unsupported encryption header version %d for %s", h.Version, path)
+	}
+	return &h, nil
+}
+
+// writeHeader replaces the key file of path. The header is written under a
+// temporary name first, so that the current key file is never left partially
+// written.
+func (b *EncryptedFileBackend) writeHeader(h *encryptionHeader, path string) error {
+	tmpKeyPath, err := b.stageHeader(h, path)
+	if err != nil {
+		return err
+	}
+	return b.commitHeader(tmpKeyPath, path)
+}
+
+// stageHeader writes the key file of path under a temporary name, which is
+// returned.
+func (b *EncryptedFileBackend) stageHeader(h *encryptionHeader, path string) (string, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to marshal the encryption header of %s", path)
+	}
+	suffix, err := temporarySuffix()
+	if err != nil {
+		return "", err
+	}
+	tmpKeyPath := path + EncryptionKeyFileSuffix + suffix
+	if _, err := b.backend.WriteFile(bytes.NewReader(data), tmpKeyPath); err != nil {
+		b.backend.RemoveFile(tmpKeyPath)
+		return "", errors.Wrapf(err, "unable to write the encryption header of %s", path)
+	}
+	return tmpKeyPath, nil
+}
+
+// commitHeader puts the key file staged at tmpKeyPath in place. On failure the
+// staged file is kept, as it may hold the only key of the contents of path.
+func (b *EncryptedFileBackend) commitHeader(tmpKeyPath, path string) error {
+	if err := b.backend.MoveFile(tmpKeyPath, path+EncryptionKeyFileSuffix); err != nil {
+		return errors.Wrapf(err, "unable to put the encryption header of %s in place, it was left at %s", path, tmpKeyPath)
+	}
+	return nil
+}
+
+// newHeader generates the header of a new file, returning it along with its
+// data key.
+func (b *EncryptedFileBackend) newHeader() (*encryptionHeader, []byte, error) {
+	dataKey := make([]byte, masterKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to generate data key")
+	}
+	prefix := make([]byte, encryptionNoncePrefix)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to generate nonce prefix")
+	}
+	keyID, wrapped, err := b.keyring.WrapKey(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &encryptionHeader{
+		Version:     encryptionHeaderVersion,
+		KeyID:       keyID,
+		WrappedKey:  wrapped,
+		NoncePrefix: prefix,
+		ChunkSize:   encryptionChunkSize,
+	}, dataKey, nil
+}
+
+// segments returns the sealed segments of the file at path, after checking
+// that the list has not been tampered with.
+func (b *EncryptedFileBackend) segments(h *encryptionHeader, dataKey []byte, path string) ([]encryptionSegment, error) {
+	if h.Version == 1 {
+		size, err := b.backend.FileSize(path)
+		if err != nil {
+			return nil, err
+		}
+		return []encryptionSegment{{Size: size}}, nil
+	}
+	if !hmac.Equal(h.SegmentsMAC, segmentsMAC(dataKey, h.Segments)) {
+		return nil, errors.New("the list of encrypted segments has been tampered with")
+	}
+	return h.Segments, nil
+}
+
+func (b *EncryptedFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	h, err := b.header(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	if h == nil {
+		return b.backend.Reader(path)
+	}
+
+	dataKey, err := b.keyring.UnwrapKey(h.KeyID, h.WrappedKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	segments, err := b.segments(h, dataKey, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+
+	r := &decryptingReader{
+		aead:      aead,
+		prefix:    h.NoncePrefix,
+		chunkSize: h.ChunkSize,
+	}
+	for i, segment := range segments {
+		size, err := plaintextSize(segment.Size, h.ChunkSize)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to open file %s", path)
+		}
+		r.segments = append(r.segments, decryptingSegment{
+			encryptionSegment: segment,
+			idx:               uint64(i),
+			start:             r.size,
+			size:              size,
+		})
+		r.size += size
+	}
+	r.src, err = b.backend.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (b *EncryptedFileBackend) ReadFile(path string) ([]byte, error) {
+	r, err := b.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return data, nil
+}
+
+func (b *EncryptedFileBackend) FileExists(path string) (bool, error) {
+	return b.backend.FileExists(path)
+}
+
+func (b *EncryptedFileBackend) FileSize(path string) (int64, error) {
+	h, err := b.header(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+	}
+	if h == nil || h.Version == 1 {
+		cipherSize, err := b.backend.FileSize(path)
+		if err != nil || h == nil {
+			return cipherSize, err
+		}
+		return plaintextSize(cipherSize, h.ChunkSize)
+	}
+
+	var size int64
+	for _, segment := range h.Segments {
+		segmentSize, err := plaintextSize(segment.Size, h.ChunkSize)
+		if err != nil {
+			return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+		}
+		size += segmentSize
+	}
+	return size, nil
+}
+
+func (b *EncryptedFileBackend) FileModTime(path string) (time.Time, error) {
+	return b.backend.FileModTime(path)
+}
+
+func (b *EncryptedFileBackend) CopyFile(oldPath, newPath string) error {
+	if err := b.backend.CopyFile(oldPath, newPath); err != nil {
+		return err
+	}
+	return b.transferHeader(oldPath, newPath, b.backend.CopyFile)
+}
+
+func (b *EncryptedFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.backend.MoveFile(oldPath, newPath); err != nil {
+		return err
+	}
+	return b.transferHeader(oldPath, newPath, b.backend.MoveFile)
+}
+
+// transferHeader copies or moves the key file of oldPath along with its data,
+// making sure a stale key file is not left behind when oldPath is plaintext.
+func (b *EncryptedFileBackend) transferHeader(oldPath, newPath string, transfer func(string, string) error) error {
+	ok, err := b.backend.FileExists(oldPath + EncryptionKeyFileSuffix)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return transfer(oldPath+EncryptionKeyFileSuffix, newPath+EncryptionKeyFileSuffix)
+	}
+	return b.removeHeader(newPath)
+}
+
+func (b *EncryptedFileBackend) removeHeader(path string) error {
+	ok, err := b.backend.FileExists(path + EncryptionKeyFileSuffix)
+	if err != nil || !ok {
+		return err
+	}
+	return b.backend.RemoveFile(path + EncryptionKeyFileSuffix)
+}
+
+func (b *EncryptedFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	return b.WriteFileContext(context.Background(), fr, path)
+}
+
+func (b *EncryptedFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	h, dataKey, err := b.newHeader()
+	if err != nil {
+		return 0, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return 0, err
+	}
+	suffix, err := temporarySuffix()
+	if err != nil {
+		return 0, err
+	}
+
+	// The data is written to a temporary file first so that a failed write
+	// never leaves a key file next to missing or partial contents.
+	tmpPath := path + suffix
+	er := newEncryptingReader(ctx, fr, aead, h, 0)
+	if _, err := TryWriteFileContext(ctx, b.backend, er, tmpPath); err != nil {
+		b.backend.RemoveFile(tmpPath)
+		return er.read, err
+	}
+	if er.written > 0 {
+		h.Segments = []encryptionSegment{{Size: er.written}}
+	}
+	h.SegmentsMAC = segmentsMAC(dataKey, h.Segments)
+
+	// The new key file is only put in place once the new contents are, so
+	// that a failed move leaves the previous contents with their key.
+	tmpKeyPath, err := b.stageHeader(h, path)
+	if err != nil {
+		b.backend.RemoveFile(tmpPath)
+		return 0, err
+	}
+	if err := b.backend.MoveFile(tmpPath, path); err != nil {
+		b.backend.RemoveFile(tmpPath)
+		b.backend.RemoveFile(tmpKeyPath)
+		return 0, errors.Wrapf(err, "unable to write the file %s", path)
+	}
+	if err := b.commitHeader(tmpKeyPath, path); err != nil {
+		return 0, err
+	}
+	return er.read, nil
+}
+
+// AppendFile appends data to the file. The data appended to an encrypted file
+// is sealed as a new segment, so the existing contents are left untouched.
+func (b *EncryptedFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	h, err := b.header(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to append the data in the file %s", path)
+	}
+	if h == nil {
+		return b.backend.AppendFile(fr, path)
+	}
+
+	dataKey, err := b.keyring.UnwrapKey(h.KeyID, h.WrappedKey)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to append the data in the file %s", path)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to append the data in the file %s", path)
+	}
+	segments, err := b.segments(h, dataKey, path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to append the data in the file %s", path)
+	}
+
+	// The new segment starts at the end of the file rather than at the end of
+	// the last segment, skipping the leftovers of a failed append.
+	offset, err := b.backend.FileSize(path)
+	if err != nil {
+		return 0, err
+	}
+	er := newEncryptingReader(context.Background(), fr, aead, h, uint64(len(segments)))
+	if _, err := b.backend.AppendFile(er, path); err != nil {
+		return 0, err
+	}
+	if er.written == 0 {
+		return 0, nil
+	}
+
+	h.Version = encryptionHeaderVersion
+	h.Segments = append(segments, encryptionSegment{Offset: offset, Size: er.written})
+	h.SegmentsMAC = segmentsMAC(dataKey, h.Segments)
+	if err := b.writeHeader(h, path); err != nil {
+		return 0, err
+	}
+	return er.read, nil
+}
+
+func (b *EncryptedFileBackend) RemoveFile(path string) error {
+	if err := b.backend.RemoveFile(path); err != nil {
+		return err
+	}
+	return b.removeHeader(path)
+}
+
+func (b *EncryptedFileBackend) ListDirectory(path string) ([]string, error) {
+	paths, err := b.backend.ListDirectory(path)
+	return withoutKeyFiles(paths), err
+}
+
+func (b *EncryptedFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	paths, err := b.backend.ListDirectoryRecursively(path)
+	return withoutKeyFiles(paths), err
+}
+
+func (b *EncryptedFileBackend) RemoveDirectory(path string) error {
+	return b.backend.RemoveDirectory(path)
+}
+
+// ListEncryptedFiles returns the paths of all the encrypted files under path.
+func (b *EncryptedFileBackend) ListEncryptedFiles(path string) ([]string, error) {
+	paths, err := b.backend.ListDirectoryRecursively(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []string
+	for _, p := range paths {
+		if strings.HasSuffix(p, EncryptionKeyFileSuffix) {
+			results = append(results, strings.TrimSuffix(p, EncryptionKeyFileSuffix))
+		}
+	}
+	return results, nil
+}
+
+// RewrapKey re-wraps the data key of the file at path with the active master
+// key. The file contents are left untouched. It returns false if the file is
+// not encrypted or its data key is already wrapped with the active key.
+func (b *EncryptedFileBackend) RewrapKey(path string) (bool, error) {
+	h, err := b.header(path)
+	if err != nil {
+		return false, err
+	}
+	if h == nil || h.KeyID == b.keyring.ActiveKeyID() {
+		return false, nil
+	}
+
+	dataKey, err := b.keyring.UnwrapKey(h.KeyID, h.WrappedKey)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to rewrap the data key of %s", path)
+	}
+	h.KeyID, h.WrappedKey, err = b.keyring.WrapKey(dataKey)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to rewrap the data key of %s", path)
+	}
+	if err := b.writeHeader(h, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func withoutKeyFiles(paths []string) []string {
+	results := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !strings.HasSuffix(p, EncryptionKeyFileSuffix) {
+			results = append(results, p)
+		}
+	}
+	return results
+}
+
+// temporarySuffix returns a random suffix naming the temporary files of a
+// single write.
+func temporarySuffix() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", errors.Wrap(err, "unable to generate a temporary file name")
+	}
+	return ".tmp-" + hex.EncodeToString(suffix), nil
+}
+
+// segmentsMAC authenticates the list of segments of a file with a key derived
+// from its data key.
+func segmentsMAC(dataKey []byte, segments []encryptionSegment) []byte {
+	kdf := hmac.New(sha256.New, dataKey)
+	kdf.Write([]byte("filestore segments"))
+	mac := hmac.New(sha256.New, kdf.Sum(nil))
+
+	var buf [16]byte
+	for _, segment := range segments {
+		binary.BigEndian.PutUint64(buf[:8], uint64(segment.Offset))
+		binary.BigEndian.PutUint64(buf[8:], uint64(segment.Size))
+		mac.Write(buf[:])
+	}
+	return mac.Sum(nil)
+}
+
+// chunkIndex returns the index of a chunk within the file, made of the index
+// of its segment and its index in the segment, so that every chunk gets its
+// own nonce. The chunks of the first segment keep their index in the segment.
+func chunkIndex(segment, chunk uint64) uint64 {
+	return segment<<32 | chunk
+}
+
+func chunkNonce(prefix []byte, idx uint64) []byte {
+	nonce := make([]byte, encryptionNoncePrefix+8)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[encryptionNoncePrefix:], idx)
+	return nonce
+}
+
+// chunkAdditionalData binds the position of a chunk in the file, and whether it
+// ends its segment, to its ciphertext.
+func chunkAdditionalData(idx uint64, last bool) []byte {
+	ad := make([]byte, 9)
+	binary.BigEndian.PutUint64(ad, idx)
+	if last {
+		ad[8] = 1
+	}
+	return ad
+}
+
+func plaintextSize(cipherSize, chunkSize int64) (int64, error) {
+	sealedChunkSize := chunkSize + encryptionTagSize
+	size := (cipherSize / sealedChunkSize) * chunkSize
+	rem := cipherSize % sealedChunkSize
+	if rem == 0 {
+		return size, nil
+	}
+	if rem <= encryptionTagSize {
+		return 0, errors.New("encrypted file is truncated")
+	}
+	return size + rem - encryptionTagSize, nil
+}
+
+// encryptingReader seals the contents of src chunk by chunk. It stops with
+// the context error once ctx is done, so that writes to backends without
+// context support still honor the deadline.
+type encryptingReader struct {
+	ctx    context.Context
+	src    *bufio.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	idx    uint64
+	plain  []byte
+	sealed []byte
+	// read and written count the plaintext and sealed bytes.
+	read    int64
+	written int64
+	eof     bool
+}
+
+// newEncryptingReader returns a reader sealing src as the given segment of a
+// file.
+func newEncryptingReader(ctx context.Context, src io.Reader, aead cipher.AEAD, h *encryptionHeader, segment uint64) *encryptingReader {
+	return &encryptingReader{
+		ctx:    ctx,
+		src:    bufio.NewReader(src),
+		aead:   aead,
+		prefix: h.NoncePrefix,
+		idx:    chunkIndex(segment, 0),
+		plain:  make([]byte, h.ChunkSize),
+	}
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.sealed) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(r.src, r.plain)
+		if ctxErr := r.ctx.Err(); ctxErr != nil {
+			return 0, ctxErr
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			r.eof = true
+		} else if err != nil {
+			return 0, err
+		} else if _, err := r.src.Peek(1); err == io.EOF {
+			// A full chunk may still be the last one.
+			r.eof = true
+		} else if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			continue
+		}
+		r.sealed = r.aead.Seal(r.sealed[:0], chunkNonce(r.prefix, r.idx), r.plain[:n], chunkAdditionalData(r.idx, r.eof))
+		r.idx++
+		r.read += int64(n)
+		r.written += int64(len(r.sealed))
+	}
+
+	n := copy(p, r.sealed)
+	r.sealed = r.sealed[n:]
+	return n, nil
+}
+
+type decryptingReader struct {
+	src        ReadCloseSeeker
+	aead       cipher.AEAD
+	prefix     []byte
+	chunkSize  int64
+	segments   []decryptingSegment
+	size       int64
+	pos        int64
+	chunkStart int64
+	chunk      []byte
+}
+
+// decryptingSegment is a sealed segment along with the range of the plaintext
+// it holds.
+type decryptingSegment struct {
+	encryptionSegment
+	idx   uint64
+	start int64
+	size  int64
+}
+
+// loadChunk decrypts the chunk holding the plaintext at pos.
+func (r *decryptingReader) loadChunk(pos int64) error {
+	i := sort.Search(len(r.segments), func(i int) bool {
+		return r.segments[i].start+r.segments[i].size > pos
+	})
+	segment := r.segments[i]
+
+	sealedChunkSize := r.chunkSize + encryptionTagSize
+	chunk := (pos - segment.start) / r.chunkSize
+	offset := chunk * sealedChunkSize
+	if _, err := r.src.Seek(segment.Offset+offset, io.SeekStart); err != nil {
+		return err
+	}
+	sealed := make([]byte, min(sealedChunkSize, segment.Size-offset))
+	if _, err := io.ReadFull(r.src, sealed); err == io.EOF {
+		// The segment is missing from the file, which must not pass for its end.
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+	idx := chunkIndex(segment.idx, uint64(chunk))
+	last := offset+sealedChunkSize >= segment.Size
+	plain, err := r.aead.Open(r.chunk[:0], chunkNonce(r.prefix, idx), sealed, chunkAdditionalData(idx, last))
+	if err != nil {
+		return errors.Wrapf(err, "unable to decrypt chunk %d of segment %d", chunk, segment.idx)
+	}
+	r.chunk = plain
+	r.chunkStart = segment.start + chunk*r.chunkSize
+	return nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	if r.chunk == nil || r.pos < r.chunkStart || r.pos >= r.chunkStart+int64(len(r.chunk)) {
+		if err := r.loadChunk(r.pos); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.chunk[r.pos-r.chunkStart:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+func (r *decryptingReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.src.Close()
+}
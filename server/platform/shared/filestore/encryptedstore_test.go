@@ -0,0 +1,307 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T, active byte, others ...byte) *Keyring {
+	t.Helper()
+	keys := map[string][]byte{}
+	activeKey := bytes.Repeat([]byte{active}, masterKeySize)
+	keys[MasterKeyID(activeKey)] = activeKey
+	for _, o := range others {
+		key := bytes.Repeat([]byte{o}, masterKeySize)
+		keys[MasterKeyID(key)] = key
+	}
+	keyring, err := NewKeyring(MasterKeyID(activeKey), keys)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestEncryptedFileBackend(t *testing.T) {
+	dir := t.TempDir()
+	local := &LocalFileBackend{directory: dir}
+	backend := NewEncryptedFileBackend(local, newTestKeyring(t, 1))
+
+	data := make([]byte, 3*encryptionChunkSize+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	written, err := backend.WriteFile(bytes.NewReader(data), "files/data.bin")
+	require.NoError(t, err)
+	assert.EqualValues(t, len(data), written)
+
+	t.Run("contents are not stored in plaintext", func(t *testing.T) {
+		raw, err := os.ReadFile(filepath.Join(dir, "files/data.bin"))
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(raw, data[:encryptionChunkSize]))
+		assert.EqualValues(t, len(data)+4*encryptionTagSize, len(raw))
+	})
+
+	t.Run("size and listings hide the encryption", func(t *testing.T) {
+		size, err := backend.FileSize("files/data.bin")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), size)
+
+		paths, err := backend.ListDirectory("files")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"files/data.bin"}, paths)
+	})
+
+	t.Run("ranged reads", func(t *testing.T) {
+		r, err := backend.Reader("files/data.bin")
+		require.NoError(t, err)
+		defer r.Close()
+
+		offset := int64(2*encryptionChunkSize - 10)
+		_, err = r.Seek(offset, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, 20)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, data[offset:offset+20], buf)
+
+		end, err := r.Seek(-5, io.SeekEnd)
+		require.NoError(t, err)
+		rest, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data[end:], rest)
+	})
+
+	t.Run("tampered contents fail to decrypt", func(t *testing.T) {
+		err := backend.CopyFile("files/data.bin", "files/tampered.bin")
+		require.NoError(t, err)
+		path := filepath.Join(dir, "files/tampered.bin")
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		raw[10] ^= 0xff
+		require.NoError(t, os.WriteFile(path, raw, 0600))
+
+		_, err = backend.ReadFile("files/tampered.bin")
+		assert.Error(t, err)
+	})
+
+	t.Run("contents truncated on a chunk boundary fail to decrypt", func(t *testing.T) {
+		err := backend.CopyFile("files/data.bin", "files/truncated.bin")
+		require.NoError(t, err)
+		path := filepath.Join(dir, "files/truncated.bin")
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, raw[:2*(encryptionChunkSize+encryptionTagSize)], 0600))
+
+		_, err = backend.ReadFile("files/truncated.bin")
+		assert.Error(t, err)
+	})
+
+	t.Run("appending seals new segments", func(t *testing.T) {
+		_, err := backend.WriteFile(bytes.NewReader(data[:encryptionChunkSize+10]), "files/append.bin")
+		require.NoError(t, err)
+		raw, err := os.ReadFile(filepath.Join(dir, "files/append.bin"))
+		require.NoError(t, err)
+
+		appended, err := backend.AppendFile(bytes.NewReader(data[encryptionChunkSize+10:2*encryptionChunkSize]), "files/append.bin")
+		require.NoError(t, err)
+		assert.EqualValues(t, encryptionChunkSize-10, appended)
+		appended, err = backend.AppendFile(bytes.NewReader(data[2*encryptionChunkSize:]), "files/append.bin")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data)-2*encryptionChunkSize, appended)
+
+		// The existing contents must not have been rewritten.
+		rawAfter, err := os.ReadFile(filepath.Join(dir, "files/append.bin"))
+		require.NoError(t, err)
+		assert.Equal(t, raw, rawAfter[:len(raw)])
+
+		size, err := backend.FileSize("files/append.bin")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), size)
+
+		read, err := backend.ReadFile("files/append.bin")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+
+		r, err := backend.Reader("files/append.bin")
+		require.NoError(t, err)
+		defer r.Close()
+		offset := int64(2*encryptionChunkSize - 10)
+		_, err = r.Seek(offset, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, 20)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, data[offset:offset+20], buf)
+	})
+
+	t.Run("dropping the last segment fails to decrypt", func(t *testing.T) {
+		err := backend.CopyFile("files/append.bin", "files/dropped.bin")
+		require.NoError(t, err)
+		keyPath := filepath.Join(dir, "files/dropped.bin"+EncryptionKeyFileSuffix)
+		raw, err := os.ReadFile(keyPath)
+		require.NoError(t, err)
+		var h encryptionHeader
+		require.NoError(t, json.Unmarshal(raw, &h))
+		require.Len(t, h.Segments, 3)
+		h.Segments = h.Segments[:2]
+		raw, err = json.Marshal(h)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(keyPath, raw, 0600))
+
+		_, err = backend.ReadFile("files/dropped.bin")
+		assert.Error(t, err)
+	})
+
+	t.Run("plaintext files written before encryption are still readable", func(t *testing.T) {
+		_, err := local.WriteFile(bytes.NewReader([]byte("legacy")), "files/legacy.txt")
+		require.NoError(t, err)
+
+		read, err := backend.ReadFile("files/legacy.txt")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("legacy"), read)
+	})
+}
+
+// failingMoveFileBackend fails to move files once fail is set.
+type failingMoveFileBackend struct {
+	FileBackend
+	fail bool
+}
+
+func (b *failingMoveFileBackend) MoveFile(oldPath, newPath string) error {
+	if b.fail {
+		return errors.New("move failed")
+	}
+	return b.FileBackend.MoveFile(oldPath, newPath)
+}
+
+func TestEncryptedFileBackendFailedOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	local := &failingMoveFileBackend{FileBackend: &LocalFileBackend{directory: dir}}
+	backend := NewEncryptedFileBackend(local, newTestKeyring(t, 1))
+
+	_, err := backend.WriteFile(bytes.NewReader([]byte("old contents")), "files/a.txt")
+	require.NoError(t, err)
+
+	local.fail = true
+	_, err = backend.WriteFile(bytes.NewReader([]byte("new contents")), "files/a.txt")
+	require.Error(t, err)
+
+	read, err := backend.ReadFile("files/a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old contents"), read)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "files"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "temporary files must be removed")
+}
+
+type linkGeneratingFileBackend struct {
+	*LocalFileBackend
+}
+
+func (b *linkGeneratingFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	return "https://example.com/" + path, time.Hour, nil
+}
+
+func TestEncryptedFileBackendWithLinkGenerator(t *testing.T) {
+	local := &linkGeneratingFileBackend{&LocalFileBackend{directory: t.TempDir()}}
+	backend := &EncryptedFileBackendWithLinkGenerator{NewEncryptedFileBackend(local, newTestKeyring(t, 1))}
+	assert.Equal(t, local, backend.Unwrap())
+
+	_, err := local.WriteFile(bytes.NewReader([]byte("plain")), "exports/plain.zip")
+	require.NoError(t, err)
+	link, _, err := backend.GeneratePublicLink("exports/plain.zip")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/exports/plain.zip", link)
+
+	_, err = backend.WriteFile(bytes.NewReader([]byte("secret")), "exports/encrypted.zip")
+	require.NoError(t, err)
+	_, _, err = backend.GeneratePublicLink("exports/encrypted.zip")
+	assert.Error(t, err)
+}
+
+func TestEncryptedFileBackendRewrapKey(t *testing.T) {
+	dir := t.TempDir()
+	local := &LocalFileBackend{directory: dir}
+	oldBackend := NewEncryptedFileBackend(local, newTestKeyring(t, 1))
+
+	_, err := oldBackend.WriteFile(bytes.NewReader([]byte("secret")), "files/a.txt")
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(dir, "files/a.txt"))
+	require.NoError(t, err)
+
+	newBackend := NewEncryptedFileBackend(local, newTestKeyring(t, 2, 1))
+	paths, err := newBackend.ListEncryptedFiles("")
+	require.NoError(t, err)
+	require.Equal(t, []string{"files/a.txt"}, paths)
+
+	rewrapped, err := newBackend.RewrapKey("files/a.txt")
+	require.NoError(t, err)
+	assert.True(t, rewrapped)
+
+	rewrapped, err = newBackend.RewrapKey("files/a.txt")
+	require.NoError(t, err)
+	assert.False(t, rewrapped)
+
+	// The data itself must not have been rewritten.
+	rawAfter, err := os.ReadFile(filepath.Join(dir, "files/a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, raw, rawAfter)
+
+	// Only the new master key is needed from now on.
+	read, err := NewEncryptedFileBackend(local, newTestKeyring(t, 2)).ReadFile("files/a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), read)
+
+	_, err = oldBackend.ReadFile("files/a.txt")
+	assert.Error(t, err)
+}
+
+func TestNewKeyringFromSettings(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, masterKeySize)
+	newKey := bytes.Repeat([]byte{2}, masterKeySize)
+
+	keyFile := filepath.Join(t.TempDir(), "keys.json")
+	data, err := json.Marshal(keyringFile{
+		ActiveKeyID: "old",
+		Keys:        map[string]string{"old": base64.StdEncoding.EncodeToString(oldKey)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, data, 0600))
+
+	t.Run("key file only", func(t *testing.T) {
+		keyring, err := NewKeyringFromSettings("", keyFile)
+		require.NoError(t, err)
+		assert.Equal(t, "old", keyring.ActiveKeyID())
+	})
+
+	t.Run("master key takes precedence", func(t *testing.T) {
+		keyring, err := NewKeyringFromSettings(base64.StdEncoding.EncodeToString(newKey), keyFile)
+		require.NoError(t, err)
+		assert.Equal(t, MasterKeyID(newKey), keyring.ActiveKeyID())
+		assert.Len(t, keyring.keys, 2)
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		_, err := NewKeyringFromSettings(base64.StdEncoding.EncodeToString([]byte("short")), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		_, err := NewKeyringFromSettings("", "")
+		assert.Error(t, err)
+	})
+}
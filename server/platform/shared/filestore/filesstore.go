@@ -15,6 +15,8 @@ import (
 const (
 	driverS3    = "amazons3"
 	driverLocal = "local"
+	driverAzure = "azureblob"
+	driverGCS   = "gcs"
 )
 
 type ReadCloseSeeker interface {
@@ -47,46 +49,79 @@ type FileBackendWithLinkGenerator interface {
 }
 
 type FileBackendSettings struct {
-	DriverName                         string
-	Directory                          string
-	AmazonS3AccessKeyId                string
-	AmazonS3SecretAccessKey            string
-	AmazonS3Bucket                     string
-	AmazonS3PathPrefix                 string
-	AmazonS3Region                     string
-	AmazonS3Endpoint                   string
-	AmazonS3SSL                        bool
-	AmazonS3SignV2                     bool
-	AmazonS3SSE                        bool
-	AmazonS3Trace                      bool
-	SkipVerify                         bool
-	AmazonS3RequestTimeoutMilliseconds int64
-	AmazonS3PresignExpiresSeconds      int64
-	AmazonS3UploadPartSizeBytes        int64
+	DriverName                                   string
+	Directory                                    string
+	AmazonS3AccessKeyId                          string
+	AmazonS3SecretAccessKey                      string
+	AmazonS3Bucket                               string
+	AmazonS3PathPrefix                           string
+	AmazonS3Region                               string
+	AmazonS3Endpoint                             string
+	AmazonS3SSL                                  bool
+	AmazonS3SignV2                               bool
+	AmazonS3SSE                                  bool
+	AmazonS3Trace                                bool
+	SkipVerify                                   bool
+	AmazonS3RequestTimeoutMilliseconds           int64
+	AmazonS3PresignExpiresSeconds                int64
+	AmazonS3UploadPartSizeBytes                  int64
+	AzureStorageAccountName                      string
+	AzureStorageAccountKey                       string
+	AzureStorageContainer                        string
+	AzureStoragePathPrefix                       string
+	AzureStorageEndpoint                         string
+	AzureStorageRequestTimeoutMilliseconds       int64
+	AzureStoragePresignExpiresSeconds            int64
+	GoogleCloudStorageBucket                     string
+	GoogleCloudStoragePathPrefix                 string
+	GoogleCloudStorageCredentialsFile            string
+	GoogleCloudStorageEndpoint                   string
+	GoogleCloudStorageRequestTimeoutMilliseconds int64
+	GoogleCloudStoragePresignExpiresSeconds      int64
+	EncryptionEnabled                            bool
+	EncryptionMasterKey                          string
+	EncryptionKeyFile                            string
 }
 
 func NewFileBackendSettingsFromConfig(fileSettings *model.FileSettings, enableComplianceFeature bool, skipVerify bool) FileBackendSettings {
 	if *fileSettings.DriverName == model.ImageDriverLocal {
 		return FileBackendSettings{
-			DriverName: *fileSettings.DriverName,
-			Directory:  *fileSettings.Directory,
+			DriverName:          *fileSettings.DriverName,
+			Directory:           *fileSettings.Directory,
+			EncryptionEnabled:   fileSettings.EnableEncryption != nil && *fileSettings.EnableEncryption,
+			EncryptionMasterKey: model.SafeDereference(fileSettings.EncryptionMasterKey),
+			EncryptionKeyFile:   model.SafeDereference(fileSettings.EncryptionKeyFile),
 		}
 	}
 	return FileBackendSettings{
-		DriverName:                         *fileSettings.DriverName,
-		AmazonS3AccessKeyId:                *fileSettings.AmazonS3AccessKeyId,
-		AmazonS3SecretAccessKey:            *fileSettings.AmazonS3SecretAccessKey,
-		AmazonS3Bucket:                     *fileSettings.AmazonS3Bucket,
-		AmazonS3PathPrefix:                 *fileSettings.AmazonS3PathPrefix,
-		AmazonS3Region:                     *fileSettings.AmazonS3Region,
-		AmazonS3Endpoint:                   *fileSettings.AmazonS3Endpoint,
-		AmazonS3SSL:                        fileSettings.AmazonS3SSL == nil || *fileSettings.AmazonS3SSL,
-		AmazonS3SignV2:                     fileSettings.AmazonS3SignV2 != nil && *fileSettings.AmazonS3SignV2,
-		AmazonS3SSE:                        fileSettings.AmazonS3SSE != nil && *fileSettings.AmazonS3SSE && enableComplianceFeature,
-		AmazonS3Trace:                      fileSettings.AmazonS3Trace != nil && *fileSettings.AmazonS3Trace,
-		AmazonS3RequestTimeoutMilliseconds: *fileSettings.AmazonS3RequestTimeoutMilliseconds,
-		SkipVerify:                         skipVerify,
-		AmazonS3UploadPartSizeBytes:        *fileSettings.AmazonS3UploadPartSizeBytes,
+		DriverName:                                   *fileSettings.DriverName,
+		AmazonS3AccessKeyId:                          *fileSettings.AmazonS3AccessKeyId,
+		AmazonS3SecretAccessKey:                      *fileSettings.AmazonS3SecretAccessKey,
+		AmazonS3Bucket:                               *fileSettings.AmazonS3Bucket,
+		AmazonS3PathPrefix:                           *fileSettings.AmazonS3PathPrefix,
+		AmazonS3Region:                               *fileSettings.AmazonS3Region,
+		AmazonS3Endpoint:                             *fileSettings.AmazonS3Endpoint,
+		AmazonS3SSL:                                  fileSettings.AmazonS3SSL == nil || *fileSettings.AmazonS3SSL,
+		AmazonS3SignV2:                               fileSettings.AmazonS3SignV2 != nil && *fileSettings.AmazonS3SignV2,
+		AmazonS3SSE:                                  fileSettings.AmazonS3SSE != nil && *fileSettings.AmazonS3SSE && enableComplianceFeature,
+		AmazonS3Trace:                                fileSettings.AmazonS3Trace != nil && *fileSettings.AmazonS3Trace,
+		AmazonS3RequestTimeoutMilliseconds:           *fileSettings.AmazonS3RequestTimeoutMilliseconds,
+		SkipVerify:                                   skipVerify,
+		AmazonS3UploadPartSizeBytes:                  *fileSettings.AmazonS3UploadPartSizeBytes,
+		AzureStorageAccountName:                      model.SafeDereference(fileSettings.AzureStorageAccountName),
+		AzureStorageAccountKey:                       model.SafeDereference(fileSettings.AzureStorageAccountKey),
+		AzureStorageContainer:                        model.SafeDereference(fileSettings.AzureStorageContainer),
+		AzureStoragePathPrefix:                       model.SafeDereference(fileSettings.AzureStoragePathPrefix),
+		AzureStorageEndpoint:                         model.SafeDereference(fileSettings.AzureStorageEndpoint),
+		AzureStorageRequestTimeoutMilliseconds:       model.SafeDereference(fileSettings.AzureStorageRequestTimeoutMilliseconds),
+		GoogleCloudStorageBucket:                     model.SafeDereference(fileSettings.GoogleCloudStorageBucket),
+		GoogleCloudStoragePathPrefix:                 model.SafeDereference(fileSettings.GoogleCloudStoragePathPrefix),
+		GoogleCloudStorageCredentialsFile:            model.SafeDereference(fileSettings.GoogleCloudStorageCredentialsFile),
+		GoogleCloudStorageEndpoint:                   model.SafeDereference(fileSettings.GoogleCloudStorageEndpoint),
+		GoogleCloudStorageRequestTimeoutMilliseconds: model.SafeDereference(fileSettings.GoogleCloudStorageRequestTimeoutMilliseconds),
+		EncryptionEnabled:                            fileSettings.EnableEncryption != nil && *fileSettings.EnableEncryption,
+		EncryptionMasterKey:                          model.SafeDereference(fileSettings.EncryptionMasterKey),
+		EncryptionKeyFile:                            model.SafeDereference(fileSettings.EncryptionKeyFile),
 	}
 }
 
@@ -116,6 +151,36 @@ func NewExportFileBackendSettingsFromConfig(fileSettings *model.FileSettings, en
 	}
 }
 
+// NewColdFileBackendSettingsFromConfig returns the settings of the backend holding the cold storage tier.
+// Encryption applies to both tiers, so the encryption settings are shared with the primary backend.
+func NewColdFileBackendSettingsFromConfig(fileSettings *model.FileSettings, enableComplianceFeature bool, skipVerify bool) FileBackendSettings {
+	settings := FileBackendSettings{
+		DriverName:          *fileSettings.ColdDriverName,
+		EncryptionEnabled:   fileSettings.EnableEncryption != nil && *fileSettings.EnableEncryption,
+		EncryptionMasterKey: model.SafeDereference(fileSettings.EncryptionMasterKey),
+		EncryptionKeyFile:   model.SafeDereference(fileSettings.EncryptionKeyFile),
+	}
+	if *fileSettings.ColdDriverName == model.ImageDriverLocal {
+		settings.Directory = *fileSettings.ColdDirectory
+		return settings
+	}
+
+	settings.AmazonS3AccessKeyId = *fileSettings.ColdAmazonS3AccessKeyId
+	settings.AmazonS3SecretAccessKey = *fileSettings.ColdAmazonS3SecretAccessKey
+	settings.AmazonS3Bucket = *fileSettings.ColdAmazonS3Bucket
+	settings.AmazonS3PathPrefix = *fileSettings.ColdAmazonS3PathPrefix
+	settings.AmazonS3Region = *fileSettings.ColdAmazonS3Region
+	settings.AmazonS3Endpoint = *fileSettings.ColdAmazonS3Endpoint
+	settings.AmazonS3SSL = fileSettings.ColdAmazonS3SSL == nil || *fileSettings.ColdAmazonS3SSL
+	settings.AmazonS3SignV2 = fileSettings.ColdAmazonS3SignV2 != nil && *fileSettings.ColdAmazonS3SignV2
+	settings.AmazonS3SSE = fileSettings.ColdAmazonS3SSE != nil && *fileSettings.ColdAmazonS3SSE && enableComplianceFeature
+	settings.AmazonS3Trace = fileSettings.ColdAmazonS3Trace != nil && *fileSettings.ColdAmazonS3Trace
+	settings.AmazonS3RequestTimeoutMilliseconds = *fileSettings.ColdAmazonS3RequestTimeoutMilliseconds
+	settings.AmazonS3UploadPartSizeBytes = *fileSettings.AmazonS3UploadPartSizeBytes
+	settings.SkipVerify = skipVerify
+	return settings
+}
+
 func (settings *FileBackendSettings) CheckMandatoryS3Fields() error {
 	if settings.AmazonS3Bucket == "" {
 		return errors.New("missing s3 bucket settings")
@@ -140,23 +205,52 @@ func NewExportFileBackend(settings FileBackendSettings) (FileBackend, error) {
 }
 
 func newFileBackend(settings FileBackendSettings, canBeCloud bool) (FileBackend, error) {
+	var backend FileBackend
 	switch settings.DriverName {
 	case driverS3:
 		newBackendFn := NewS3FileBackend
 		if !canBeCloud {
 			newBackendFn = NewS3FileBackendWithoutBifrost
 		}
-		backend, err := newBackendFn(settings)
+		s3Backend, err := newBackendFn(settings)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to connect to the s3 backend")
 		}
-		return backend, nil
+		backend = s3Backend
+	case driverAzure:
+		azureBackend, err := NewAzureFileBackend(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to connect to the azure blob storage backend")
+		}
+		backend = azureBackend
+	case driverGCS:
+		gcsBackend, err := NewGCSFileBackend(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to connect to the google cloud storage backend")
+		}
+		backend = gcsBackend
 	case driverLocal:
-		return &LocalFileBackend{
+		backend = &LocalFileBackend{
 			directory: settings.Directory,
-		}, nil
+		}
+	default:
+		return nil, errors.New("no valid filestorage driver found")
+	}
+
+	if settings.EncryptionEnabled {
+		keyring, err := NewKeyringFromSettings(settings.EncryptionMasterKey, settings.EncryptionKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to load the file encryption keys")
+		}
+		encrypted := NewEncryptedFileBackend(backend, keyring)
+		if _, ok := backend.(FileBackendWithLinkGenerator); ok {
+			backend = &EncryptedFileBackendWithLinkGenerator{encrypted}
+		} else {
+			backend = encrypted
+		}
 	}
-	return nil, errors.New("no valid filestorage driver found")
+
+	return backend, nil
 }
 
 // TryWriteFileContext checks if the file backend supports context writes and passes the context in that case.
@@ -6,10 +6,12 @@ package filestore
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"math"
 	"math/rand"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -53,6 +55,25 @@ func TestLocalFileBackendTestSuite(t *testing.T) {
 	})
 }
 
+func TestEncryptedLocalFileBackendTestSuite(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	mlog.InitGlobalLogger(logger)
+
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:          driverLocal,
+			Directory:           dir,
+			EncryptionEnabled:   true,
+			EncryptionMasterKey: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, masterKeySize)),
+		},
+	})
+}
+
 func TestS3FileBackendTestSuite(t *testing.T) {
 	runBackendTest(t, false)
 }
@@ -90,6 +111,56 @@ func runBackendTest(t *testing.T, encrypt bool) {
 	})
 }
 
+// Azurite accepts the well known development storage account.
+const (
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+func TestAzureFileBackendTestSuite(t *testing.T) {
+	azuriteHost := os.Getenv("CI_AZURITE_HOST")
+	if azuriteHost == "" {
+		azuriteHost = "localhost"
+	}
+
+	azuritePort := os.Getenv("CI_AZURITE_PORT")
+	if azuritePort == "" {
+		azuritePort = "10000"
+	}
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:                             driverAzure,
+			AzureStorageAccountName:                azuriteAccountName,
+			AzureStorageAccountKey:                 azuriteAccountKey,
+			AzureStorageContainer:                  "mattermost-test",
+			AzureStorageEndpoint:                   fmt.Sprintf("http://%s:%s/%s", azuriteHost, azuritePort, azuriteAccountName),
+			AzureStorageRequestTimeoutMilliseconds: 5000,
+		},
+	})
+}
+
+func TestGCSFileBackendTestSuite(t *testing.T) {
+	gcsHost := os.Getenv("CI_FAKEGCS_HOST")
+	if gcsHost == "" {
+		gcsHost = "localhost"
+	}
+
+	gcsPort := os.Getenv("CI_FAKEGCS_PORT")
+	if gcsPort == "" {
+		gcsPort = "4443"
+	}
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:                                   driverGCS,
+			GoogleCloudStorageBucket:                     "mattermost-test",
+			GoogleCloudStorageEndpoint:                   fmt.Sprintf("http://%s:%s/storage/v1/", gcsHost, gcsPort),
+			GoogleCloudStorageRequestTimeoutMilliseconds: 5000,
+		},
+	})
+}
+
 func (s *FileBackendTestSuite) SetupTest() {
 	backend, err := NewFileBackend(s.settings)
 	require.NoError(s.T(), err)
@@ -97,10 +168,11 @@ func (s *FileBackendTestSuite) SetupTest() {
 
 	// This is needed to create the bucket if it doesn't exist.
 	err = s.backend.TestConnection()
-	if _, ok := err.(*S3FileBackendNoBucketError); ok {
-		s3Backend := s.backend.(*S3FileBackend)
-		s.NoError(s3Backend.MakeBucket())
-	} else {
+	switch err.(type) {
+	case *S3FileBackendNoBucketError, *AzureFileBackendNoContainerError, *GCSFileBackendNoBucketError:
+		bucketMaker := s.backend.(interface{ MakeBucket() error })
+		s.NoError(bucketMaker.MakeBucket())
+	default:
 		s.NoError(err)
 	}
 }
@@ -526,6 +598,55 @@ func (s *FileBackendTestSuite) TestAppendFile() {
 	})
 }
 
+func (s *FileBackendTestSuite) TestReaderSeek() {
+	data := []byte("0123456789")
+	path := "tests/" + randomString()
+
+	_, err := s.backend.WriteFile(bytes.NewReader(data), path)
+	s.Require().NoError(err)
+	defer s.backend.RemoveFile(path)
+
+	r, err := s.backend.Reader(path)
+	s.Require().NoError(err)
+	defer r.Close()
+
+	_, err = r.Seek(4, io.SeekStart)
+	s.Require().NoError(err)
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(r, buf)
+	s.Require().NoError(err)
+	s.Equal([]byte("456"), buf)
+
+	_, err = r.Seek(-2, io.SeekEnd)
+	s.Require().NoError(err)
+	rest, err := io.ReadAll(r)
+	s.Require().NoError(err)
+	s.Equal([]byte("89"), rest)
+}
+
+func (s *FileBackendTestSuite) TestGeneratePublicLink() {
+	// Only Azure can sign links in tests: S3 links are only enabled for the export
+	// store, and signing GCS links requires service account credentials.
+	if s.settings.DriverName != driverAzure {
+		s.T().Skip("backend can't generate public links")
+	}
+	linkGenerator := s.backend.(FileBackendWithLinkGenerator)
+
+	path := "tests/" + randomString()
+	_, err := s.backend.WriteFile(bytes.NewReader([]byte("test")), path)
+	s.Require().NoError(err)
+	defer s.backend.RemoveFile(path)
+
+	link, expires, err := linkGenerator.GeneratePublicLink(path)
+	s.Require().NoError(err)
+	s.NotEmpty(link)
+	s.Positive(expires)
+
+	u, err := url.Parse(link)
+	s.Require().NoError(err)
+	s.Equal("attachment", u.Query().Get("rscd"))
+}
+
 func (s *FileBackendTestSuite) TestFileSize() {
 	s.Run("nonexistent file", func() {
 		size, err := s.backend.FileSize("tests/nonexistentfile")
@@ -0,0 +1,331 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GCSFileBackend stores files as objects in a Google Cloud Storage bucket.
+type GCSFileBackend struct {
+	bucket         string
+	pathPrefix     string
+	timeout        time.Duration
+	presignExpires time.Duration
+	client         *storage.Client
+}
+
+type GCSFileBackendAuthError struct {
+	DetailedError string
+}
+
+// GCSFileBackendNoBucketError is returned when testing a connection and no bucket is found
+type GCSFileBackendNoBucketError struct{}
+
+var _ FileBackendWithLinkGenerator = (*GCSFileBackend)(nil)
+
+func (s *GCSFileBackendAuthError) Error() string {
+	return s.DetailedError
+}
+
+func (s *GCSFileBackendNoBucketError) Error() string {
+	return "no such bucket"
+}
+
+// NewGCSFileBackend returns an instance of a GCSFileBackend. Without a credentials file,
+// the application default credentials are used, unless a custom endpoint is set, in
+// which case the requests are not authenticated, as expected by storage emulators.
+func NewGCSFileBackend(settings FileBackendSettings) (*GCSFileBackend, error) {
+	var opts []option.ClientOption
+	if settings.GoogleCloudStorageCredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(settings.GoogleCloudStorageCredentialsFile))
+	}
+	if settings.GoogleCloudStorageEndpoint != "" {
+		// Custom endpoints only serve the JSON API reliably, the XML API
+		// used for reads by default is specific to Google's servers.
+		opts = append(opts, option.WithEndpoint(settings.GoogleCloudStorageEndpoint), storage.WithJSONReads())
+		if settings.GoogleCloudStorageCredentialsFile == "" {
+			opts = append(opts, option.WithoutAuthentication())
+		}
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the google cloud storage client")
+	}
+
+	presignExpires := time.Duration(settings.GoogleCloudStoragePresignExpiresSeconds) * time.Second
+	if presignExpires == 0 {
+		presignExpires = defaultPresignExpires
+	}
+
+	return &GCSFileBackend{
+		bucket:         settings.GoogleCloudStorageBucket,
+		pathPrefix:     settings.GoogleCloudStoragePathPrefix,
+		timeout:        time.Duration(settings.GoogleCloudStorageRequestTimeoutMilliseconds) * time.Millisecond,
+		presignExpires: presignExpires,
+		client:         client,
+	}, nil
+}
+
+func (b *GCSFileBackend) DriverName() string {
+	return driverGCS
+}
+
+func (b *GCSFileBackend) TestConnection() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.client.Bucket(b.bucket).Attrs(ctx); err != nil {
+		if errors.Is(err, storage.ErrBucketNotExist) {
+			return &GCSFileBackendNoBucketError{}
+		}
+		return &GCSFileBackendAuthError{DetailedError: "unable to check if the google cloud storage bucket exists"}
+	}
+	mlog.Debug("Connection to Google Cloud Storage is good. Bucket exists.")
+	return nil
+}
+
+// MakeBucket creates the bucket holding the files. It's mostly useful against
+// emulators, since creating a real bucket requires a project ID.
+func (b *GCSFileBackend) MakeBucket() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if err := b.client.Bucket(b.bucket).Create(ctx, "mattermost", nil); err != nil {
+		return errors.Wrap(err, "unable to create the google cloud storage bucket")
+	}
+	return nil
+}
+
+func (b *GCSFileBackend) objectName(path string) string {
+	return filepath.Join(b.pathPrefix, path)
+}
+
+func (b *GCSFileBackend) object(path string) *storage.ObjectHandle {
+	return b.client.Bucket(b.bucket).Object(b.objectName(path))
+}
+
+// Caller must close the first return value
+func (b *GCSFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	size, err := b.FileSize(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	obj := b.object(path)
+	return newRangeReader(size, b.timeout, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		r, err := obj.NewRangeReader(ctx, offset, -1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read file %s", path)
+		}
+		return r, nil
+	}), nil
+}
+
+func (b *GCSFileBackend) ReadFile(path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	r, err := b.object(path).NewReader(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	defer r.Close()
+	f, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return f, nil
+}
+
+func (b *GCSFileBackend) attrs(path string) (*storage.ObjectAttrs, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	return b.object(path).Attrs(ctx)
+}
+
+func (b *GCSFileBackend) FileExists(path string) (bool, error) {
+	_, err := b.attrs(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "unable to know if file %s exists", path)
+}
+
+func (b *GCSFileBackend) FileSize(path string) (int64, error) {
+	attrs, err := b.attrs(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+	}
+	return attrs.Size, nil
+}
+
+func (b *GCSFileBackend) FileModTime(path string) (time.Time, error) {
+	attrs, err := b.attrs(path)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", path)
+	}
+	return attrs.Updated, nil
+}
+
+func (b *GCSFileBackend) CopyFile(oldPath, newPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.object(newPath).CopierFrom(b.object(oldPath)).Run(ctx); err != nil {
+		return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+	}
+	return nil
+}
+
+func (b *GCSFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.CopyFile(oldPath, newPath); err != nil {
+		return errors.Wrapf(err, "unable to copy the file to %s to the new destination", newPath)
+	}
+	if err := b.RemoveFile(oldPath); err != nil {
+		return errors.Wrapf(err, "unable to remove the file old file %s", oldPath)
+	}
+	return nil
+}
+
+func (b *GCSFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	return b.WriteFileContext(ctx, fr, path)
+}
+
+func (b *GCSFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	w := b.object(path).NewWriter(ctx)
+	w.ContentType = contentTypeForPath(path)
+	written, err := io.Copy(w, fr)
+	if err != nil {
+		w.Close()
+		return 0, errors.Wrapf(err, "unable write the data in the file %s", path)
+	}
+	if err := w.Close(); err != nil {
+		return 0, errors.Wrapf(err, "unable write the data in the file %s", path)
+	}
+	return written, nil
+}
+
+// AppendFile uploads the new data to a temporary object and composes it with
+// the existing one. Unlike S3, composing has no minimum size for the parts.
+func (b *GCSFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	if _, err := b.attrs(path); err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", path)
+	}
+
+	partPath := path + ".part"
+	written, err := b.WriteFile(fr, partPath)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+	defer b.RemoveFile(partPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	dst := b.object(path)
+	composer := dst.ComposerFrom(dst, b.object(partPath))
+	composer.ContentType = contentTypeForPath(path)
+	if _, err := composer.Run(ctx); err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+	return written, nil
+}
+
+func (b *GCSFileBackend) RemoveFile(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if err := b.object(path).Delete(ctx); err != nil {
+		return errors.Wrapf(err, "unable to remove the file %s", path)
+	}
+	return nil
+}
+
+func (b *GCSFileBackend) listDirectory(path string, recursion bool) ([]string, error) {
+	prefix := b.objectName(path)
+	if prefix == "." {
+		prefix = ""
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	query := &storage.Query{Prefix: prefix}
+	if !recursion {
+		query.Delimiter = "/"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	var paths []string
+	it := b.client.Bucket(b.bucket).Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the directory %s", path)
+		}
+		name := attrs.Name
+		if name == "" {
+			name = attrs.Prefix
+		}
+		// We strip the path prefix that gets applied,
+		// so that it remains transparent to the application.
+		if trimmed := strings.Trim(strings.TrimPrefix(name, b.pathPrefix), "/"); trimmed != "" {
+			paths = append(paths, trimmed)
+		}
+	}
+	return paths, nil
+}
+
+func (b *GCSFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.listDirectory(path, false)
+}
+
+func (b *GCSFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.listDirectory(path, true)
+}
+
+func (b *GCSFileBackend) RemoveDirectory(path string) error {
+	paths, err := b.ListDirectoryRecursively(path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove the directory %s", path)
+	}
+	for _, p := range paths {
+		if err := b.RemoveFile(p); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return errors.Wrapf(err, "unable to remove the directory %s", path)
+		}
+	}
+	return nil
+}
+
+// GeneratePublicLink returns a V4 signed URL, which requires the credentials
+// file to belong to a service account.
+func (b *GCSFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	link, err := b.client.Bucket(b.bucket).SignedURL(b.objectName(path), &storage.SignedURLOptions{
+		Method:          "GET",
+		Expires:         time.Now().Add(b.presignExpires),
+		Scheme:          storage.SigningSchemeV4,
+		QueryParameters: url.Values{"response-content-disposition": []string{"attachment"}},
+	})
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to generate public link for %s", path)
+	}
+	return link, b.presignExpires, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const masterKeySize = 32
+
+// Keyring holds the master keys used to wrap and unwrap per-file data keys.
+// New data keys are always wrapped with the active key, while any key in the
+// ring can be used to unwrap, which allows rotating the master key without
+// losing access to files wrapped with a previous one.
+type Keyring struct {
+	activeID string
+	keys     map[string][]byte
+}
+
+type keyringFile struct {
+	ActiveKeyID string            `json:"active_key_id"`
+	Keys        map[string]string `json:"keys"`
+}
+
+// MasterKeyID returns the identifier under which a master key is stored in a
+// keyring: the hex encoded prefix of its SHA-256 digest.
+func MasterKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewKeyring creates a keyring from the given keys, using activeID to wrap new data keys.
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring has no keys")
+	}
+	for id, key := range keys {
+		if len(key) != masterKeySize {
+			return nil, errors.Errorf("master key %s must be %d bytes long", id, masterKeySize)
+		}
+	}
+	if _, ok := keys[activeID]; !ok {
+		return nil, errors.Errorf("active master key %s not found in keyring", activeID)
+	}
+	return &Keyring{activeID: activeID, keys: keys}, nil
+}
+
+// NewKeyringFromSettings builds the keyring from a base64 encoded master key
+// and/or a JSON key file. When both are given, the master key becomes the
+// active key and the key file only provides the keys to unwrap older files.
+func NewKeyringFromSettings(masterKey, keyFile string) (*Keyring, error) {
+	keys := map[string][]byte{}
+	activeID := ""
+
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the encryption key file %s", keyFile)
+		}
+		var kf keyringFile
+		if err := json.Unmarshal(data, &kf); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse the encryption key file %s", keyFile)
+		}
+		for id, encoded := range kf.Keys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to decode master key %s", id)
+			}
+			keys[id] = key
+		}
+		activeID = kf.ActiveKeyID
+	}
+
+	if masterKey != "" {
+		key, err := base64.StdEncoding.DecodeString(masterKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to decode the encryption master key")
+		}
+		activeID = MasterKeyID(key)
+		keys[activeID] = key
+	}
+
+	return NewKeyring(activeID, keys)
+}
+
+// ActiveKeyID returns the identifier of the key used to wrap new data keys.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// WrapKey encrypts a data key with the active master key.
+func (k *Keyring) WrapKey(dataKey []byte) (string, []byte, error) {
+	gcm, err := newGCM(k.keys[k.activeID])
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, errors.Wrap(err, "unable to generate nonce")
+	}
+	return k.activeID, gcm.Seal(nonce, nonce, dataKey, []byte(k.activeID)), nil
+}
+
+// UnwrapKey decrypts a data key previously wrapped with the master key keyID.
+func (k *Keyring) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, errors.Errorf("master key %s not found in keyring", keyID)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	dataKey, err := gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to unwrap data key with master key %s", keyID)
+	}
+	return dataKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create GCM")
+	}
+	return gcm, nil
+}
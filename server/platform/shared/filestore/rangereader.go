@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// openRangeFunc opens a stream on an object starting at the given offset.
+type openRangeFunc func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+// rangeReader turns ranged object downloads into a ReadCloseSeeker. The
+// underlying stream is opened lazily and reopened at the new offset after
+// each seek, so seeking itself never performs a request.
+type rangeReader struct {
+	open   openRangeFunc
+	size   int64
+	offset int64
+	body   io.ReadCloser
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	timer  *time.Timer
+}
+
+func newRangeReader(size int64, timeout time.Duration, open openRangeFunc) *rangeReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &rangeReader{
+		open:   open,
+		size:   size,
+		ctx:    ctx,
+		cancel: cancel,
+		timer:  time.AfterFunc(timeout, cancel),
+	}
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.open(r.ctx, r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+	if abs != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *rangeReader) Close() error {
+	r.timer.Stop()
+	defer r.cancel()
+	if r.body != nil {
+		return r.body.Close()
+	}
+	return nil
+}
+
+// CancelTimeout attempts to cancel the timeout for this reader. It allows calling
+// code to ignore the timeout in case of longer running operations. The methods returns
+// false if the timeout has already fired.
+func (r *rangeReader) CancelTimeout() bool {
+	return r.timer.Stop()
+}
+
+// countingReader counts the bytes read through it, for backends whose upload
+// calls don't report the size of the written object.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeReader(t *testing.T) {
+	data := []byte("0123456789")
+	var opened []int64
+	r := newRangeReader(int64(len(data)), time.Minute, func(_ context.Context, offset int64) (io.ReadCloser, error) {
+		opened = append(opened, offset)
+		return io.NopCloser(bytes.NewReader(data[offset:])), nil
+	})
+	defer r.Close()
+
+	buf := make([]byte, 3)
+	_, err := io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("012"), buf)
+
+	// Seeking to the current position keeps the open stream.
+	pos, err := r.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, pos)
+
+	pos, err = r.Seek(-4, io.SeekEnd)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, pos)
+
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("6789"), rest)
+	assert.Equal(t, []int64{0, 6}, opened)
+
+	_, err = r.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+}
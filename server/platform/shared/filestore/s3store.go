@@ -83,6 +83,13 @@ func getImageMimeType(ext string) string {
 	return imageMimeTypes[ext]
 }
 
+func contentTypeForPath(path string) string {
+	if ext := filepath.Ext(path); isFileExtImage(ext) {
+		return getImageMimeType(ext)
+	}
+	return "binary/octet-stream"
+}
+
 func (s *S3FileBackendAuthError) Error() string {
 	return s.DetailedError
 }
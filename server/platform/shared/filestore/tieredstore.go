@@ -0,0 +1,229 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TieredFileBackend combines a primary (hot) backend with a secondary (cold)
+// one used to archive rarely accessed files. New files are always written to
+// the hot tier, and reads transparently fall back to the cold tier when a
+// file is not found in the hot one.
+type TieredFileBackend struct {
+	hot  FileBackend
+	cold FileBackend
+}
+
+func NewTieredFileBackend(hot, cold FileBackend) *TieredFileBackend {
+	return &TieredFileBackend{
+		hot:  hot,
+		cold: cold,
+	}
+}
+
+// Hot returns the backend of the hot tier.
+func (b *TieredFileBackend) Hot() FileBackend {
+	return b.hot
+}
+
+// Cold returns the backend of the cold tier.
+func (b *TieredFileBackend) Cold() FileBackend {
+	return b.cold
+}
+
+// backendFor returns the tier holding path, preferring the hot one. If the
+// file doesn't exist in any of them, the hot tier is returned so that errors
+// are reported by the primary backend.
+func (b *TieredFileBackend) backendFor(path string) (FileBackend, error) {
+	ok, err := b.hot.FileExists(path)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return b.hot, nil
+	}
+	ok, err = b.cold.FileExists(path)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return b.cold, nil
+	}
+	return b.hot, nil
+}
+
+func (b *TieredFileBackend) DriverName() string {
+	return b.hot.DriverName()
+}
+
+func (b *TieredFileBackend) TestConnection() error {
+	if err := b.hot.TestConnection(); err != nil {
+		return err
+	}
+	if err := b.cold.TestConnection(); err != nil {
+		return errors.Wrap(err, "unable to connect to the cold storage backend")
+	}
+	return nil
+}
+
+func (b *TieredFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Reader(path)
+}
+
+func (b *TieredFileBackend) ReadFile(path string) ([]byte, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.ReadFile(path)
+}
+
+func (b *TieredFileBackend) FileExists(path string) (bool, error) {
+	ok, err := b.hot.FileExists(path)
+	if err != nil || ok {
+		return ok, err
+	}
+	return b.cold.FileExists(path)
+}
+
+func (b *TieredFileBackend) FileSize(path string) (int64, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return 0, err
+	}
+	return backend.FileSize(path)
+}
+
+func (b *TieredFileBackend) FileModTime(path string) (time.Time, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return backend.FileModTime(path)
+}
+
+func (b *TieredFileBackend) CopyFile(oldPath, newPath string) error {
+	backend, err := b.backendFor(oldPath)
+	if err != nil {
+		return err
+	}
+	if backend == b.hot {
+		return b.hot.CopyFile(oldPath, newPath)
+	}
+	// Copies are new files, so they are brought back to the hot tier.
+	return b.transfer(b.cold, b.hot, oldPath, newPath)
+}
+
+func (b *TieredFileBackend) MoveFile(oldPath, newPath string) error {
+	backend, err := b.backendFor(oldPath)
+	if err != nil {
+		return err
+	}
+	return backend.MoveFile(oldPath, newPath)
+}
+
+func (b *TieredFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	return b.hot.WriteFile(fr, path)
+}
+
+func (b *TieredFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	return TryWriteFileContext(ctx, b.hot, fr, path)
+}
+
+func (b *TieredFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return 0, err
+	}
+	return backend.AppendFile(fr, path)
+}
+
+func (b *TieredFileBackend) RemoveFile(path string) error {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return err
+	}
+	return backend.RemoveFile(path)
+}
+
+func (b *TieredFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.listBoth(path, FileBackend.ListDirectory)
+}
+
+func (b *TieredFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.listBoth(path, FileBackend.ListDirectoryRecursively)
+}
+
+func (b *TieredFileBackend) listBoth(path string, list func(FileBackend, string) ([]string, error)) ([]string, error) {
+	hotPaths, err := list(b.hot, path)
+	if err != nil {
+		return hotPaths, err
+	}
+	coldPaths, err := list(b.cold, path)
+	if err != nil {
+		return hotPaths, errors.Wrap(err, "unable to list the cold storage backend")
+	}
+
+	seen := make(map[string]bool, len(hotPaths))
+	for _, p := range hotPaths {
+		seen[p] = true
+	}
+	for _, p := range coldPaths {
+		if !seen[p] {
+			hotPaths = append(hotPaths, p)
+		}
+	}
+	return hotPaths, nil
+}
+
+func (b *TieredFileBackend) RemoveDirectory(path string) error {
+	if err := b.hot.RemoveDirectory(path); err != nil {
+		return err
+	}
+	return b.cold.RemoveDirectory(path)
+}
+
+// MoveToCold moves the file at path from the hot tier to the cold one.
+// It's a no-op if the file is already in the cold tier.
+func (b *TieredFileBackend) MoveToCold(path string) error {
+	ok, err := b.hot.FileExists(path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		ok, err = b.cold.FileExists(path)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		return errors.Errorf("unable to find the file %s to move to cold storage", path)
+	}
+
+	if err := b.transfer(b.hot, b.cold, path, path); err != nil {
+		return errors.Wrapf(err, "unable to move the file %s to cold storage", path)
+	}
+	return b.hot.RemoveFile(path)
+}
+
+func (b *TieredFileBackend) transfer(from, to FileBackend, fromPath, toPath string) error {
+	r, err := from.Reader(fromPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, err = TryWriteFileContext(context.Background(), to, r, toPath)
+	return err
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieredFileBackend(t *testing.T) {
+	hot := &LocalFileBackend{directory: t.TempDir()}
+	cold := &LocalFileBackend{directory: t.TempDir()}
+	backend := NewTieredFileBackend(hot, cold)
+
+	_, err := backend.WriteFile(bytes.NewReader([]byte("old")), "files/old.txt")
+	require.NoError(t, err)
+	_, err = backend.WriteFile(bytes.NewReader([]byte("new")), "files/new.txt")
+	require.NoError(t, err)
+
+	require.NoError(t, backend.MoveToCold("files/old.txt"))
+
+	t.Run("file is moved between tiers", func(t *testing.T) {
+		ok, err := hot.FileExists("files/old.txt")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = cold.FileExists("files/old.txt")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// Moving it again is a no-op.
+		require.NoError(t, backend.MoveToCold("files/old.txt"))
+		assert.Error(t, backend.MoveToCold("files/missing.txt"))
+	})
+
+	t.Run("reads fall back to the cold tier", func(t *testing.T) {
+		data, err := backend.ReadFile("files/old.txt")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("old"), data)
+
+		r, err := backend.Reader("files/old.txt")
+		require.NoError(t, err)
+		defer r.Close()
+
+		size, err := backend.FileSize("files/old.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, size)
+
+		ok, err := backend.FileExists("files/old.txt")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("listings include both tiers", func(t *testing.T) {
+		paths, err := backend.ListDirectory("files")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"files/new.txt", "files/old.txt"}, paths)
+	})
+
+	t.Run("copies of cold files are written to the hot tier", func(t *testing.T) {
+		require.NoError(t, backend.CopyFile("files/old.txt", "files/copy.txt"))
+
+		data, err := hot.ReadFile("files/copy.txt")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("old"), data)
+	})
+
+	t.Run("files are removed from the tier holding them", func(t *testing.T) {
+		require.NoError(t, backend.RemoveFile("files/old.txt"))
+
+		ok, err := backend.FileExists("files/old.txt")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// dkimMaxSignatures caps the signatures verified per email, each one costs a DNS lookup.
+	dkimMaxSignatures = 5
+	dkimMinRSAKeyBits = 1024
+)
+
+// DKIMLookupTXT looks up the TXT records of a domain, e.g. net.Resolver.LookupTXT.
+type DKIMLookupTXT func(ctx context.Context, name string) ([]string, error)
+
+// VerifyDKIM verifies the DKIM signatures of a raw email, see RFC 6376, and returns the
+// domains of the valid ones. The rsa-sha256 and ed25519-sha256 algorithms are supported.
+// The signatures limited to a part of the body are ignored, as content could be appended
+// to it. The returned error describes the signatures which failed to verify.
+func VerifyDKIM(ctx context.Context, data []byte, lookupTXT DKIMLookupTXT) ([]string, error) {
+	// The line endings are normalized when the email is received.
+	data = toCRLF(data)
+
+	headerEnd := bytes.Index(data, []byte("\r\n\r\n"))
+	var body []byte
+	if headerEnd < 0 {
+		headerEnd = len(data)
+	} else {
+		body = data[headerEnd+4:]
+	}
+	headers := splitHeaderFields(data[:headerEnd])
+
+	var domains []string
+	var errs []error
+	verified := 0
+	for _, field := range headers {
+		name, _, _ := strings.Cut(field, ":")
+		if !strings.EqualFold(strings.TrimSpace(name), "DKIM-Signature") {
+			continue
+		}
+		if verified == dkimMaxSignatures {
+			break
+		}
+		verified++
+
+		domain, err := verifyDKIMSignature(ctx, field, headers, body, lookupTXT)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		domains = append(domains, domain)
+	}
+
+	if len(errs) > 0 {
+		return domains, errors.Errorf("invalid DKIM signatures: %v", errs)
+	}
+	return domains, nil
+}
+
+// IsDKIMAligned returns whether a DKIM signature of the domain authenticates the
+// address, i.e. the domain of the address is the signing domain or one of its subdomains.
+func IsDKIMAligned(address, domain string) bool {
+	at := strings.LastIndex(address, "@")
+	if at < 0 || domain == "" {
+		return false
+	}
+	addressDomain := strings.ToLower(strings.TrimSuffix(address[at+1:], "."))
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	return addressDomain == domain || strings.HasSuffix(addressDomain, "."+domain)
+}
+
+func verifyDKIMSignature(ctx context.Context, signature string, headers []string, body []byte, lookupTXT DKIMLookupTXT) (string, error) {
+	_, value, _ := strings.Cut(signature, ":")
+	tags, err := parseDKIMTags(value)
+	if err != nil {
+		return "", err
+	}
+
+	domain := tags["d"]
+	selector := tags["s"]
+	if tags["v"] != "1" || domain == "" || selector == "" || tags["b"] == "" || tags["bh"] == "" || tags["h"] == "" {
+		return "", errors.New("missing DKIM signature tags")
+	}
+	if _, ok := tags["l"]; ok {
+		return "", errors.Errorf("DKIM signature of %s limited to a part of the body", domain)
+	}
+	if expiration, ok := tags["x"]; ok {
+		x, err := strconv.ParseInt(expiration, 10, 64)
+		if err != nil || time.Unix(x, 0).Before(time.Now()) {
+			return "", errors.Errorf("DKIM signature of %s expired", domain)
+		}
+	}
+
+	var keyType string
+	switch strings.ToLower(tags["a"]) {
+	case "rsa-sha256":
+		keyType = "rsa"
+	case "ed25519-sha256":
+		keyType = "ed25519"
+	default:
+		return "", errors.Errorf("unsupported DKIM algorithm %q", tags["a"])
+	}
+
+	headerCanon, bodyCanon, _ := strings.Cut(strings.ToLower(tags["c"]), "/")
+	if headerCanon == "" {
+		headerCanon = "simple"
+	}
+	if bodyCanon == "" {
+		bodyCanon = "simple"
+	}
+	if (headerCanon != "simple" && headerCanon != "relaxed") || (bodyCanon != "simple" && bodyCanon != "relaxed") {
+		return "", errors.Errorf("unsupported DKIM canonicalization %q", tags["c"])
+	}
+
+	bodyHash := sha256.Sum256(canonicalizeDKIMBody(body, bodyCanon == "relaxed"))
+	if expected, err := base64.StdEncoding.DecodeString(stripDKIMSpaces(tags["bh"])); err != nil || !bytes.Equal(expected, bodyHash[:]) {
+		return "", errors.Errorf("DKIM body hash of %s does not match", domain)
+	}
+
+	signedHeaders := strings.Split(tags["h"], ":")
+	hash := sha256.New()
+	signsFrom := false
+	used := make(map[int]bool, len(signedHeaders))
+	for _, name := range signedHeaders {
+		name = strings.TrimSpace(name)
+		if strings.EqualFold(name, "From") {
+			signsFrom = true
+		}
+		// The occurrences of a header are signed from the last one up.
+		for i := len(headers) - 1; i >= 0; i-- {
+			fieldName, _, _ := strings.Cut(headers[i], ":")
+			if used[i] || !strings.EqualFold(strings.TrimSpace(fieldName), name) {
+				continue
+			}
+			used[i] = true
+			hash.Write([]byte(canonicalizeDKIMHeader(headers[i], headerCanon == "relaxed")))
+			break
+		}
+	}
+	if !signsFrom {
+		return "", errors.Errorf("DKIM signature of %s does not sign the From header", domain)
+	}
+	hash.Write([]byte(strings.TrimSuffix(canonicalizeDKIMHeader(removeDKIMSignatureValue(signature), headerCanon == "relaxed"), "\r\n")))
+
+	sig, err := base64.StdEncoding.DecodeString(stripDKIMSpaces(tags["b"]))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid DKIM signature of %s", domain)
+	}
+
+	key, err := lookupDKIMKey(ctx, selector+"._domainkey."+domain, keyType, lookupTXT)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the DKIM key of %s", domain)
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash.Sum(nil), sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, hash.Sum(nil), sig) {
+			err = errors.New("ed25519 verification failed")
+		}
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid DKIM signature of %s", domain)
+	}
+
+	return domain, nil
+}
+
+func lookupDKIMKey(ctx context.Context, name, keyType string, lookupTXT DKIMLookupTXT) (crypto.PublicKey, error) {
+	records, err := lookupTXT(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no DKIM key record")
+	}
+
+	tags, err := parseDKIMTags(strings.Join(records, ""))
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := tags["v"]; ok && v != "DKIM1" {
+		return nil, errors.Errorf("unsupported DKIM key version %q", v)
+	}
+	if k := strings.ToLower(tags["k"]); k != keyType && (k != "" || keyType != "rsa") {
+		return nil, errors.Errorf("DKIM key type %q does not match the signature", k)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(stripDKIMSpaces(tags["p"]))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid DKIM key")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("DKIM key revoked")
+	}
+
+	if keyType == "ed25519" {
+		if len(data) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 DKIM key")
+		}
+		return ed25519.PublicKey(data), nil
+	}
+
+	var key *rsa.PublicKey
+	if parsed, err := x509.ParsePKIXPublicKey(data); err == nil {
+		var ok bool
+		if key, ok = parsed.(*rsa.PublicKey); !ok {
+			return nil, errors.New("DKIM key is not an RSA key")
+		}
+	} else if key, err = x509.ParsePKCS1PublicKey(data); err != nil {
+		return nil, errors.Wrap(err, "invalid RSA DKIM key")
+	}
+	if key.N.BitLen() < dkimMinRSAKeyBits {
+		return nil, errors.New("RSA DKIM key too short")
+	}
+	return key, nil
+}
+
+// parseDKIMTags parses a tag list, e.g. "v=1; a=rsa-sha256; d=example.com".
+func parseDKIMTags(value string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, tag := range strings.Split(value, ";") {
+		name, tagValue, found := strings.Cut(tag, "=")
+		name = strings.TrimSpace(name)
+		if !found {
+			if name == "" {
+				continue
+			}
+			return nil, errors.Errorf("invalid DKIM tag %q", name)
+		}
+		if _, ok := tags[name]; ok {
+			return nil, errors.Errorf("duplicate DKIM tag %q", name)
+		}
+		tags[name] = strings.TrimSpace(unfoldDKIM(tagValue))
+	}
+	return tags, nil
+}
+
+// removeDKIMSignatureValue empties the b= tag of a DKIM-Signature header, which
+// is signed without its own value.
+func removeDKIMSignatureValue(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	tags := strings.Split(value, ";")
+	for i, tag := range tags {
+		tagName, _, found := strings.Cut(tag, "=")
+		if found && strings.TrimSpace(tagName) == "b" {
+			tags[i] = tag[:strings.Index(tag, "=")+1]
+		}
+	}
+	return name + ":" + strings.Join(tags, ";")
+}
+
+// canonicalizeDKIMHeader canonicalizes a header field, ending with CRLF.
+func canonicalizeDKIMHeader(field string, relaxed bool) string {
+	if !relaxed {
+		return field
+	}
+
+	name, value, _ := strings.Cut(field, ":")
+	value = strings.Join(strings.FieldsFunc(unfoldDKIM(value), isDKIMSpace), " ")
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + value + "\r\n"
+}
+
+func canonicalizeDKIMBody(body []byte, relaxed bool) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	if relaxed {
+		for i, line := range lines {
+			line = strings.TrimRightFunc(line, isDKIMSpace)
+			var b strings.Builder
+			space := false
+			for _, r := range line {
+				if isDKIMSpace(r) {
+					space = true
+					continue
+				}
+				if space {
+					b.WriteByte(' ')
+					space = false
+				}
+				b.WriteRune(r)
+			}
+			lines[i] = b.String()
+		}
+	}
+
+	// The empty lines at the end of the body are ignored.
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		if relaxed {
+			return nil
+		}
+		return []byte("\r\n")
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// splitHeaderFields splits the header section of an email into its fields, keeping
+// their folding and ending CRLF.
+func splitHeaderFields(header []byte) []string {
+	var fields []string
+	for _, line := range strings.SplitAfter(string(header), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	if n := len(fields); n > 0 && !strings.HasSuffix(fields[n-1], "\r\n") {
+		fields[n-1] += "\r\n"
+	}
+	return fields
+}
+
+func toCRLF(data []byte) []byte {
+	if !bytes.Contains(data, []byte("\n")) {
+		return data
+	}
+	return bytes.ReplaceAll(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+}
+
+func unfoldDKIM(value string) string {
+	return strings.NewReplacer("\r\n", "", "\n", "").Replace(value)
+}
+
+func stripDKIMSpaces(value string) string {
+	return strings.Join(strings.FieldsFunc(value, isDKIMSpace), "")
+}
+
+func isDKIMSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r' || r == '\n'
+}
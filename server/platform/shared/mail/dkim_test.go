@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mail
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signDKIM signs the email the way a sending server would.
+func signDKIM(t *testing.T, email, domain string, key crypto.Signer, canonicalization string) string {
+	t.Helper()
+
+	algorithm := "rsa-sha256"
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		algorithm = "ed25519-sha256"
+	}
+	headerCanon, bodyCanon, _ := strings.Cut(canonicalization, "/")
+
+	data := toCRLF([]byte(email))
+	header, body, _ := strings.Cut(string(data), "\r\n\r\n")
+	bodyHash := sha256.Sum256(canonicalizeDKIMBody([]byte(body), bodyCanon == "relaxed"))
+
+	signature := fmt.Sprintf("DKIM-Signature: v=1; a=%s; c=%s; d=%s; s=mail;\r\n\th=From:Subject; bh=%s; b=",
+		algorithm, canonicalization, domain, base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	hash := sha256.New()
+	fields := splitHeaderFields([]byte(header + "\r\n"))
+	for _, name := range []string{"From", "Subject"} {
+		for _, field := range fields {
+			if strings.HasPrefix(field, name+":") {
+				hash.Write([]byte(canonicalizeDKIMHeader(field, headerCanon == "relaxed")))
+			}
+		}
+	}
+	hash.Write([]byte(strings.TrimSuffix(canonicalizeDKIMHeader(signature+"\r\n", headerCanon == "relaxed"), "\r\n")))
+
+	var opts crypto.SignerOpts = crypto.SHA256
+	if algorithm == "ed25519-sha256" {
+		opts = crypto.Hash(0)
+	}
+	sig, err := key.Sign(rand.Reader, hash.Sum(nil), opts)
+	require.NoError(t, err)
+
+	return signature + base64.StdEncoding.EncodeToString(sig) + "\r\n" + string(data)
+}
+
+func TestVerifyDKIM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPublicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	lookupTXT := func(_ context.Context, name string) ([]string, error) {
+		switch name {
+		case "mail._domainkey.example.com":
+			return []string{"v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(rsaPublicKey)}, nil
+		case "mail._domainkey.example.org":
+			return []string{"v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(edPublicKey)}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	email := "From: John <john@example.com>\nSubject:  Hello \n\tthere\n\nHello  world \n\n\n"
+
+	t.Run("rsa-sha256 relaxed", func(t *testing.T) {
+		domains, err := VerifyDKIM(context.Background(), []byte(signDKIM(t, email, "example.com", rsaKey, "relaxed/relaxed")), lookupTXT)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com"}, domains)
+	})
+
+	t.Run("ed25519-sha256 simple", func(t *testing.T) {
+		domains, err := VerifyDKIM(context.Background(), []byte(signDKIM(t, email, "example.org", edKey, "simple/simple")), lookupTXT)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.org"}, domains)
+	})
+
+	t.Run("modified body", func(t *testing.T) {
+		signed := signDKIM(t, email, "example.com", rsaKey, "relaxed/relaxed")
+		domains, err := VerifyDKIM(context.Background(), []byte(strings.Replace(signed, "Hello  world", "Goodbye world", 1)), lookupTXT)
+		require.Error(t, err)
+		assert.Empty(t, domains)
+	})
+
+	t.Run("modified sender", func(t *testing.T) {
+		signed := signDKIM(t, email, "example.com", rsaKey, "relaxed/relaxed")
+		domains, err := VerifyDKIM(context.Background(), []byte(strings.Replace(signed, "john@example.com", "jane@example.com", 1)), lookupTXT)
+		require.Error(t, err)
+		assert.Empty(t, domains)
+	})
+
+	t.Run("signed with another key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		domains, err := VerifyDKIM(context.Background(), []byte(signDKIM(t, email, "example.com", otherKey, "relaxed/relaxed")), lookupTXT)
+		require.Error(t, err)
+		assert.Empty(t, domains)
+	})
+
+	t.Run("unknown domain", func(t *testing.T) {
+		domains, err := VerifyDKIM(context.Background(), []byte(signDKIM(t, email, "example.net", rsaKey, "relaxed/relaxed")), lookupTXT)
+		require.Error(t, err)
+		assert.Empty(t, domains)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		domains, err := VerifyDKIM(context.Background(), []byte(email), lookupTXT)
+		require.NoError(t, err)
+		assert.Empty(t, domains)
+	})
+}
+
+func TestDKIMCanonicalization(t *testing.T) {
+	// The examples of RFC 6376, section 3.4.5.
+	fields := splitHeaderFields([]byte("A: X\r\nB : Y\t\r\n\tZ  \r\n"))
+	require.Len(t, fields, 2)
+	assert.Equal(t, "a:X\r\n", canonicalizeDKIMHeader(fields[0], true))
+	assert.Equal(t, "b:Y Z\r\n", canonicalizeDKIMHeader(fields[1], true))
+	assert.Equal(t, "B : Y\t\r\n\tZ  \r\n", canonicalizeDKIMHeader(fields[1], false))
+
+	body := []byte(" C \r\nD \t E\r\n\r\n\r\n")
+	assert.Equal(t, " C\r\nD E\r\n", string(canonicalizeDKIMBody(body, true)))
+	assert.Equal(t, " C \r\nD \t E\r\n", string(canonicalizeDKIMBody(body, false)))
+
+	assert.Equal(t, "\r\n", string(canonicalizeDKIMBody(nil, false)))
+	assert.Empty(t, canonicalizeDKIMBody(nil, true))
+}
+
+func TestIsDKIMAligned(t *testing.T) {
+	assert.True(t, IsDKIMAligned("john@example.com", "example.com"))
+	assert.True(t, IsDKIMAligned("john@Mail.Example.com", "example.com"))
+	assert.False(t, IsDKIMAligned("john@example.com", "mail.example.com"))
+	assert.False(t, IsDKIMAligned("john@badexample.com", "example.com"))
+	assert.False(t, IsDKIMAligned("john", "example.com"))
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+
+	"github.com/jaytaylor/html2text"
+	"github.com/pkg/errors"
+)
+
+// InboundEmail is an email received by the inbound server.
+type InboundEmail struct {
+	From        *mail.Address
+	Subject     string
+	MessageID   string
+	Text        string
+	Attachments []*InboundAttachment
+
+	// AutoSubmitted is set for automatic emails, e.g. out of office replies.
+	AutoSubmitted bool
+}
+
+// InboundAttachment is a file attached to an inbound email.
+type InboundAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ParseInboundEmail parses a raw email, keeping its plain text body, or the text
+// of its HTML body when there is none, and its attachments.
+func ParseInboundEmail(data []byte) (*InboundEmail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the email")
+	}
+
+	decoder := &mime.WordDecoder{}
+	autoSubmitted := strings.ToLower(msg.Header.Get("Auto-Submitted"))
+	precedence := strings.ToLower(msg.Header.Get("Precedence"))
+	email := &InboundEmail{
+		MessageID:     msg.Header.Get("Message-Id"),
+		AutoSubmitted: (autoSubmitted != "" && autoSubmitted != "no") || precedence == "bulk" || precedence == "junk" || precedence == "auto_reply",
+	}
+
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the sender")
+	}
+	email.From = from
+
+	if email.Subject, err = decoder.DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		email.Subject = msg.Header.Get("Subject")
+	}
+
+	var html string
+	if err := walkInboundPart(textproto.MIMEHeader(msg.Header), msg.Body, email, &html); err != nil {
+		return nil, err
+	}
+
+	if email.Text == "" && html != "" {
+		if email.Text, err = html2text.FromString(html); err != nil {
+			return nil, errors.Wrap(err, "failed to convert the HTML body")
+		}
+	}
+
+	return email, nil
+}
+
+func walkInboundPart(header textproto.MIMEHeader, body io.Reader, email *InboundEmail, html *string) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return errors.Wrap(err, "failed to read the email part")
+			}
+			if err := walkInboundPart(part.Header, part, email, html); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode the email part")
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if decoded, err := (&mime.WordDecoder{}).DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+
+	switch {
+	case disposition == "attachment" || filename != "":
+		if filename == "" {
+			filename = "attachment"
+		}
+		email.Attachments = append(email.Attachments, &InboundAttachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Data:        data,
+		})
+	case mediaType == "text/plain" && email.Text == "":
+		email.Text = strings.ToValidUTF8(string(data), "")
+	case mediaType == "text/html" && *html == "":
+		*html = strings.ToValidUTF8(string(data), "")
+	}
+
+	return nil
+}
+
+var (
+	replyHeaderPattern      = regexp.MustCompile(`(?i)^on\s.+\swrote:$`)
+	originalMessagePattern  = regexp.MustCompile(`(?i)^-{2,}\s*original message\s*-{2,}$`)
+	outlookSeparatorPattern = regexp.MustCompile(`^_{20,}$`)
+	mobileSignaturePattern  = regexp.MustCompile(`(?i)^(sent from my .+|get outlook for (ios|android))$`)
+)
+
+// StripReplyQuote returns the text of a reply without the quoted message it
+// replies to and without the signature of the sender.
+func StripReplyQuote(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	for i := range lines {
+		if isQuoteStart(lines, i) {
+			lines = lines[:i]
+			break
+		}
+	}
+
+	for i, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed == "--" || mobileSignaturePattern.MatchString(trimmed) {
+			lines = lines[:i]
+			break
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func isQuoteStart(lines []string, i int) bool {
+	line := strings.TrimSpace(lines[i])
+
+	switch {
+	case originalMessagePattern.MatchString(line), outlookSeparatorPattern.MatchString(line):
+		return true
+	case strings.HasPrefix(strings.ToLower(line), "on "):
+		// The header of the quote may be wrapped over a few lines.
+		header := line
+		for j := i + 1; j < len(lines) && j <= i+2 && !replyHeaderPattern.MatchString(header); j++ {
+			header += " " + strings.TrimSpace(lines[j])
+		}
+		return replyHeaderPattern.MatchString(header)
+	case strings.HasPrefix(line, "From:"):
+		// The header of the quote in Outlook.
+		for j := i + 1; j < len(lines); j++ {
+			if next := strings.TrimSpace(lines[j]); next != "" {
+				return strings.HasPrefix(next, "Sent:") || strings.HasPrefix(next, "Date:")
+			}
+		}
+	case strings.HasPrefix(line, ">"):
+		// A quote is only stripped when nothing but the quote follows.
+		for _, next := range lines[i:] {
+			if next = strings.TrimSpace(next); next != "" && !strings.HasPrefix(next, ">") {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
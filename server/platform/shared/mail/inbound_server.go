@@ -0,0 +1,371 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	inboundDefaultMaxMessageSize = 25 * 1024 * 1024
+	inboundDefaultMaxConnections = 50
+	inboundMaxRecipients         = 100
+	inboundCommandTimeout        = 5 * time.Minute
+)
+
+// InboundServer is a minimal SMTP server receiving the emails sent to the server,
+// e.g. the replies to the email notifications. It does not relay any email.
+type InboundServer struct {
+	Addr           string
+	Hostname       string
+	MaxMessageSize int64
+	// MaxConnections caps the concurrent connections, the others are refused
+	// with a temporary error.
+	MaxConnections int
+
+	// AcceptRecipient reports whether emails to the given address are accepted.
+	AcceptRecipient func(address string) bool
+
+	// Handler is called for every recipient of a received email. The email is
+	// accepted when it is handled for one of its recipients at least, and rejected
+	// when the handler returns an error for all of them.
+	Handler func(from string, to string, data []byte) error
+
+	Logger *mlog.Logger
+
+	mut      sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// Start starts listening for connections in the background.
+func (s *InboundServer) Start() error {
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.Addr, err)
+	}
+
+	s.mut.Lock()
+	s.listener = listener
+	s.conns = map[net.Conn]struct{}{}
+	s.mut.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serve(listener)
+	}()
+
+	return nil
+}
+
+// ListenAddr returns the address the server listens on, once started.
+func (s *InboundServer) ListenAddr() net.Addr {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close stops the server, closing the open connections.
+func (s *InboundServer) Close() error {
+	s.mut.Lock()
+	if s.listener == nil {
+		s.mut.Unlock()
+		return nil
+	}
+	err := s.listener.Close()
+	s.listener = nil
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mut.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *InboundServer) serve(listener net.Listener) {
+	sem := make(chan struct{}, s.maxConnections())
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.log(mlog.LvlWarn, "Failed to accept an inbound email connection", mlog.Err(err))
+			}
+			return
+		}
+
+		select {
+		case sem <- struct{}{}:
+		default:
+			s.log(mlog.LvlDebug, "Refused an inbound email connection, too many connections", mlog.String("remote_addr", conn.RemoteAddr().String()))
+			conn.SetWriteDeadline(time.Now().Add(time.Second))
+			fmt.Fprintf(conn, "421 4.3.2 %s Too many connections, try again later\r\n", s.hostname())
+			conn.Close()
+			continue
+		}
+
+		s.mut.Lock()
+		if s.listener == nil {
+			s.mut.Unlock()
+			conn.Close()
+			<-sem
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mut.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mut.Lock()
+				delete(s.conns, conn)
+				s.mut.Unlock()
+				conn.Close()
+				<-sem
+			}()
+
+			if err := s.handleConn(conn); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.log(mlog.LvlDebug, "Inbound email connection failed", mlog.String("remote_addr", conn.RemoteAddr().String()), mlog.Err(err))
+			}
+		}()
+	}
+}
+
+func (s *InboundServer) handleConn(conn net.Conn) error {
+	tc := textproto.NewConn(conn)
+	hostname := s.hostname()
+	maxMessageSize := s.maxMessageSize()
+
+	var greeted bool
+	var from string
+	var to []string
+	reset := func() {
+		from = ""
+		to = nil
+	}
+
+	conn.SetDeadline(time.Now().Add(inboundCommandTimeout))
+	if err := tc.PrintfLine("220 %s ESMTP ready", hostname); err != nil {
+		return err
+	}
+
+	for {
+		conn.SetDeadline(time.Now().Add(inboundCommandTimeout))
+
+		line, err := tc.ReadLine()
+		if err != nil {
+			return err
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			greeted = true
+			reset()
+			err = tc.PrintfLine("250 %s", hostname)
+		case "EHLO":
+			greeted = true
+			reset()
+			err = tc.PrintfLine("250-%s\r\n250-SIZE %d\r\n250 8BITMIME", hostname, maxMessageSize)
+		case "MAIL":
+			address, params, ok := parseInboundPath(arg, "FROM:")
+			switch {
+			case !greeted:
+				err = tc.PrintfLine("503 5.5.1 Send HELO or EHLO first")
+			case from != "":
+				err = tc.PrintfLine("503 5.5.1 Sender already specified")
+			case !ok:
+				err = tc.PrintfLine("501 5.5.4 Syntax: MAIL FROM:<address>")
+			case inboundDeclaredSize(params) > maxMessageSize:
+				err = tc.PrintfLine("552 5.3.4 Message size exceeds the limit")
+			default:
+				// The null reverse path of bounces is kept as "<>" to tell it apart.
+				from = address
+				if from == "" {
+					from = "<>"
+				}
+				err = tc.PrintfLine("250 2.1.0 OK")
+			}
+		case "RCPT":
+			address, _, ok := parseInboundPath(arg, "TO:")
+			switch {
+			case from == "":
+				err = tc.PrintfLine("503 5.5.1 Send MAIL first")
+			case !ok || address == "":
+				err = tc.PrintfLine("501 5.5.4 Syntax: RCPT TO:<address>")
+			case len(to) >= inboundMaxRecipients:
+				err = tc.PrintfLine("452 4.5.3 Too many recipients")
+			case s.AcceptRecipient != nil && !s.AcceptRecipient(address):
+				err = tc.PrintfLine("550 5.1.1 Mailbox unavailable")
+			default:
+				to = append(to, address)
+				err = tc.PrintfLine("250 2.1.5 OK")
+			}
+		case "DATA":
+			if len(to) == 0 {
+				err = tc.PrintfLine("503 5.5.1 Send RCPT first")
+				break
+			}
+			if err = tc.PrintfLine("354 End data with <CR><LF>.<CR><LF>"); err != nil {
+				return err
+			}
+			err = s.receiveData(tc, from, to, maxMessageSize)
+			reset()
+		case "RSET":
+			reset()
+			err = tc.PrintfLine("250 2.0.0 OK")
+		case "NOOP":
+			err = tc.PrintfLine("250 2.0.0 OK")
+		case "VRFY":
+			err = tc.PrintfLine("252 2.5.2 Cannot verify the user")
+		case "QUIT":
+			tc.PrintfLine("221 2.0.0 Bye")
+			return nil
+		default:
+			err = tc.PrintfLine("502 5.5.2 Command not implemented")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *InboundServer) receiveData(tc *textproto.Conn, from string, to []string, maxMessageSize int64) error {
+	// The email is spooled to a file while it is received, rather than held in
+	// memory for as long as the client takes to send it.
+	spool, err := os.CreateTemp("", "inbound-email-")
+	if err != nil {
+		s.log(mlog.LvlWarn, "Failed to create the inbound email spool file", mlog.Err(err))
+		if _, err := io.Copy(io.Discard, tc.DotReader()); err != nil {
+			return err
+		}
+		return tc.PrintfLine("451 4.3.0 Local error in processing")
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	reader := tc.DotReader()
+	n, err := io.Copy(spool, io.LimitReader(reader, maxMessageSize+1))
+	if err != nil {
+		return err
+	}
+
+	if n > maxMessageSize {
+		if _, err := io.Copy(io.Discard, reader); err != nil {
+			return err
+		}
+		return tc.PrintfLine("552 5.3.4 Message size exceeds the limit")
+	}
+
+	if s.Handler == nil {
+		return tc.PrintfLine("250 2.0.0 OK")
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		s.log(mlog.LvlWarn, "Failed to read the inbound email spool file", mlog.Err(err))
+		return tc.PrintfLine("451 4.3.0 Local error in processing")
+	}
+	data, err := io.ReadAll(spool)
+	if err != nil {
+		s.log(mlog.LvlWarn, "Failed to read the inbound email spool file", mlog.Err(err))
+		return tc.PrintfLine("451 4.3.0 Local error in processing")
+	}
+
+	// A failure for one recipient doesn't undo the delivery to the others, so the
+	// email is accepted as soon as one recipient got it.
+	var delivered bool
+	for _, recipient := range to {
+		if err := s.Handler(from, recipient, data); err != nil {
+			s.log(mlog.LvlDebug, "Rejected an inbound email", mlog.String("from", from), mlog.String("recipient", recipient), mlog.Err(err))
+			continue
+		}
+		delivered = true
+	}
+
+	if !delivered {
+		return tc.PrintfLine("554 5.6.0 Message rejected")
+	}
+	return tc.PrintfLine("250 2.0.0 OK")
+}
+
+func (s *InboundServer) hostname() string {
+	if s.Hostname != "" {
+		return s.Hostname
+	}
+	return "localhost"
+}
+
+func (s *InboundServer) maxMessageSize() int64 {
+	if s.MaxMessageSize > 0 {
+		return s.MaxMessageSize
+	}
+	return inboundDefaultMaxMessageSize
+}
+
+func (s *InboundServer) maxConnections() int {
+	if s.MaxConnections > 0 {
+		return s.MaxConnections
+	}
+	return inboundDefaultMaxConnections
+}
+
+func (s *InboundServer) log(level mlog.Level, msg string, fields ...mlog.Field) {
+	if s.Logger != nil {
+		s.Logger.Log(level, msg, fields...)
+		return
+	}
+	mlog.Log(level, msg, fields...)
+}
+
+// parseInboundPath parses the argument of the MAIL and RCPT commands, e.g.
+// "FROM:<user@example.com> SIZE=1024".
+func parseInboundPath(arg, prefix string) (address string, params []string, ok bool) {
+	arg = strings.TrimSpace(arg)
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	arg = strings.TrimSpace(arg[len(prefix):])
+
+	if !strings.HasPrefix(arg, "<") {
+		return "", nil, false
+	}
+	end := strings.Index(arg, ">")
+	if end < 0 {
+		return "", nil, false
+	}
+
+	return arg[1:end], strings.Fields(arg[end+1:]), true
+}
+
+func inboundDeclaredSize(params []string) int64 {
+	for _, param := range params {
+		if key, value, found := strings.Cut(param, "="); found && strings.EqualFold(key, "SIZE") {
+			size, _ := strconv.ParseInt(value, 10, 64)
+			return size
+		}
+	}
+	return 0
+}
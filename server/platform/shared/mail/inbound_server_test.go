@@ -0,0 +1,161 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mail
+
+import (
+	"errors"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundServer(t *testing.T) {
+	type received struct {
+		from string
+		to   string
+		data []byte
+	}
+
+	var mut sync.Mutex
+	var emails []received
+
+	server := &InboundServer{
+		Addr:           "127.0.0.1:0",
+		Hostname:       "mail.example.com",
+		MaxMessageSize: 1024,
+		AcceptRecipient: func(address string) bool {
+			return strings.HasSuffix(address, "@example.com")
+		},
+		Handler: func(from string, to string, data []byte) error {
+			if strings.Contains(string(data), "reject me") || strings.HasPrefix(to, "reply+invalid@") {
+				return errors.New("rejected")
+			}
+			mut.Lock()
+			defer mut.Unlock()
+			emails = append(emails, received{from: from, to: to, data: data})
+			return nil
+		},
+	}
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	addr := server.ListenAddr().String()
+
+	t.Run("receives an email", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "john@example.org", []string{"reply+token@example.com"}, []byte("Subject: Hello\r\n\r\nHello\r\n.dot\r\n"))
+		require.NoError(t, err)
+
+		mut.Lock()
+		defer mut.Unlock()
+		require.Len(t, emails, 1)
+		assert.Equal(t, "john@example.org", emails[0].from)
+		assert.Equal(t, "reply+token@example.com", emails[0].to)
+		// The line endings are normalized by the dot reader.
+		assert.Equal(t, "Subject: Hello\n\nHello\n.dot\n", string(emails[0].data))
+	})
+
+	t.Run("rejects unknown recipients", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "john@example.org", []string{"someone@example.org"}, []byte("Subject: Hello\r\n\r\nHello\r\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "550")
+	})
+
+	t.Run("rejects emails refused by the handler", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "john@example.org", []string{"reply+token@example.com"}, []byte("Subject: Hello\r\n\r\nreject me\r\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "554")
+	})
+
+	t.Run("accepts emails handled for one of their recipients", func(t *testing.T) {
+		mut.Lock()
+		emails = nil
+		mut.Unlock()
+
+		err := smtp.SendMail(addr, nil, "john@example.org", []string{"reply+invalid@example.com", "reply+token@example.com"}, []byte("Subject: Hello\r\n\r\nHello\r\n"))
+		require.NoError(t, err)
+
+		mut.Lock()
+		defer mut.Unlock()
+		require.Len(t, emails, 1)
+		assert.Equal(t, "reply+token@example.com", emails[0].to)
+	})
+
+	t.Run("rejects emails not handled for any recipient", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "john@example.org", []string{"reply+invalid@example.com"}, []byte("Subject: Hello\r\n\r\nHello\r\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "554")
+	})
+
+	t.Run("rejects emails over the size limit", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "john@example.org", []string{"reply+token@example.com"}, []byte("Subject: Hello\r\n\r\n"+strings.Repeat("a", 2048)+"\r\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "552")
+	})
+
+	t.Run("keeps the connection usable after a rejected email", func(t *testing.T) {
+		client, err := smtp.Dial(addr)
+		require.NoError(t, err)
+		defer client.Close()
+
+		require.NoError(t, client.Mail("john@example.org"))
+		require.Error(t, client.Rcpt("someone@example.org"))
+		require.NoError(t, client.Rcpt("reply+other@example.com"))
+		require.NoError(t, client.Noop())
+		require.NoError(t, client.Reset())
+		require.Error(t, client.Rcpt("reply+other@example.com"))
+		require.NoError(t, client.Quit())
+	})
+
+	t.Run("stops on close", func(t *testing.T) {
+		require.NoError(t, server.Close())
+		_, err := smtp.Dial(addr)
+		require.Error(t, err)
+	})
+}
+
+func TestInboundServerMaxConnections(t *testing.T) {
+	server := &InboundServer{
+		Addr:           "127.0.0.1:0",
+		MaxConnections: 1,
+	}
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	addr := server.ListenAddr().String()
+
+	dial := func() *textproto.Conn {
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		return textproto.NewConn(conn)
+	}
+
+	first := dial()
+	defer first.Close()
+	_, _, err := first.ReadResponse(220)
+	require.NoError(t, err)
+
+	second := dial()
+	defer second.Close()
+	code, _, err := second.ReadResponse(220)
+	require.Error(t, err)
+	assert.Equal(t, 421, code)
+
+	// The connections are accepted again once the first one is closed.
+	require.NoError(t, first.PrintfLine("QUIT"))
+	_, _, err = first.ReadResponse(221)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		conn := dial()
+		defer conn.Close()
+		code, _, _ := conn.ReadResponse(220)
+		return code == 220
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInboundEmail(t *testing.T) {
+	t.Run("plain text", func(t *testing.T) {
+		email, err := ParseInboundEmail([]byte(strings.Join([]string{
+			"From: John Doe <john@example.com>",
+			"To: reply@example.com",
+			"Subject: =?UTF-8?Q?Re:_Caf=C3=A9?=",
+			"Message-ID: <1234@example.com>",
+			"Content-Type: text/plain; charset=UTF-8",
+			"Content-Transfer-Encoding: quoted-printable",
+			"",
+			"See you at the caf=C3=A9.",
+			"",
+		}, "\r\n")))
+		require.NoError(t, err)
+
+		assert.Equal(t, "john@example.com", email.From.Address)
+		assert.Equal(t, "John Doe", email.From.Name)
+		assert.Equal(t, "Re: Café", email.Subject)
+		assert.Equal(t, "<1234@example.com>", email.MessageID)
+		assert.Equal(t, "See you at the café.\r\n", email.Text)
+		assert.Empty(t, email.Attachments)
+		assert.False(t, email.AutoSubmitted)
+	})
+
+	t.Run("automatic reply", func(t *testing.T) {
+		email, err := ParseInboundEmail([]byte("From: john@example.com\r\nAuto-Submitted: auto-replied\r\n\r\nI am out of office.\r\n"))
+		require.NoError(t, err)
+
+		assert.True(t, email.AutoSubmitted)
+	})
+
+	t.Run("multipart with attachment", func(t *testing.T) {
+		email, err := ParseInboundEmail([]byte(strings.Join([]string{
+			"From: john@example.com",
+			"Subject: Re: Report",
+			"MIME-Version: 1.0",
+			`Content-Type: multipart/mixed; boundary="outer"`,
+			"",
+			"--outer",
+			`Content-Type: multipart/alternative; boundary="inner"`,
+			"",
+			"--inner",
+			"Content-Type: text/plain; charset=UTF-8",
+			"",
+			"Here is the report.",
+			"--inner",
+			"Content-Type: text/html; charset=UTF-8",
+			"",
+			"<p>Here is the <b>report</b>.</p>",
+			"--inner--",
+			"--outer",
+			`Content-Type: text/csv; name="report.csv"`,
+			`Content-Disposition: attachment; filename="report.csv"`,
+			"Content-Transfer-Encoding: base64",
+			"",
+			"YSxiCjEsMgo=",
+			"--outer--",
+			"",
+		}, "\r\n")))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Here is the report.", email.Text)
+		require.Len(t, email.Attachments, 1)
+		assert.Equal(t, "report.csv", email.Attachments[0].Filename)
+		assert.Equal(t, "text/csv", email.Attachments[0].ContentType)
+		assert.Equal(t, []byte("a,b\n1,2\n"), email.Attachments[0].Data)
+	})
+
+	t.Run("html only", func(t *testing.T) {
+		email, err := ParseInboundEmail([]byte(strings.Join([]string{
+			"From: john@example.com",
+			"Content-Type: text/html; charset=UTF-8",
+			"",
+			"<p>Sounds <b>good</b></p>",
+			"",
+		}, "\r\n")))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Sounds *good*", email.Text)
+	})
+
+	t.Run("missing sender", func(t *testing.T) {
+		_, err := ParseInboundEmail([]byte("Subject: Hello\r\n\r\nHello\r\n"))
+		require.Error(t, err)
+	})
+}
+
+func TestStripReplyQuote(t *testing.T) {
+	for name, tc := range map[string]struct {
+		text     string
+		expected string
+	}{
+		"no quote": {
+			text:     "Sounds good.\n\nSee you there.",
+			expected: "Sounds good.\n\nSee you there.",
+		},
+		"gmail": {
+			text:     "Sounds good.\r\n\r\nOn Mon, Jan 1, 2024 at 8:00 AM John Doe <john@example.com> wrote:\r\n> Are you coming?\r\n",
+			expected: "Sounds good.",
+		},
+		"wrapped header": {
+			text:     "Sounds good.\n\nOn Mon, Jan 1, 2024 at 8:00 AM John Doe <\njohn@example.com> wrote:\n\n> Are you coming?",
+			expected: "Sounds good.",
+		},
+		"original message": {
+			text:     "Sounds good.\n\n-----Original Message-----\nFrom: John Doe\nAre you coming?",
+			expected: "Sounds good.",
+		},
+		"outlook": {
+			text:     "Sounds good.\n\n________________________________\nFrom: John Doe <john@example.com>\nSent: Monday, January 1, 2024 8:00 AM\n\nAre you coming?",
+			expected: "Sounds good.",
+		},
+		"outlook without separator": {
+			text:     "Sounds good.\n\nFrom: John Doe <john@example.com>\nDate: Monday, January 1, 2024 8:00 AM\n\nAre you coming?",
+			expected: "Sounds good.",
+		},
+		"inline quote is kept": {
+			text:     "> Are you coming?\nYes.\n> At 8?\nSure.",
+			expected: "> Are you coming?\nYes.\n> At 8?\nSure.",
+		},
+		"trailing quote": {
+			text:     "Yes.\n\n> Are you coming?\n>\n> John",
+			expected: "Yes.",
+		},
+		"signature": {
+			text:     "Yes.\n\n-- \nJane Doe\nACME Inc.",
+			expected: "Yes.",
+		},
+		"mobile signature": {
+			text:     "Yes.\n\nSent from my iPhone",
+			expected: "Yes.",
+		},
+		"only a quote": {
+			text:     "On Mon, Jan 1, 2024 John Doe wrote:\n> Are you coming?",
+			expected: "",
+		},
+		"sentence starting with on": {
+			text:     "On second thought, no.\nSorry.",
+			expected: "On second thought, no.\nSorry.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, StripReplyQuote(tc.text))
+		})
+	}
+}
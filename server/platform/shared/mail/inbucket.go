@@ -118,6 +118,24 @@ func GetMessageFromMailbox(email, id string) (JSONMessageInbucket, error) {
 	return record, err
 }
 
+// GetMessageSourceFromMailbox returns the raw message, as it was received by inbucket.
+func GetMessageSourceFromMailbox(email, id string) ([]byte, error) {
+	parsedEmail := ParseEmail(email)
+
+	url := fmt.Sprintf("%s%s%s/%s/source", getInbucketHost(), InbucketAPI, parsedEmail, id)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
 func downloadAttachment(url string) ([]byte, error) {
 	attachmentResponse, err := http.Get(url)
 	if err != nil {
@@ -261,6 +261,26 @@ func SendMailUsingConfig(to, subject, htmlBody string, config *SMTPConfig, enabl
 	return SendMailWithEmbeddedFilesUsingConfig(to, subject, htmlBody, nil, config, enableComplianceFeatures, messageID, inReplyTo, references, ccMail, category)
 }
 
+// SendMailWithHeadersUsingConfig sends an email with additional MIME headers, e.g. the
+// List-Unsubscribe headers of bulk emails.
+func SendMailWithHeadersUsingConfig(to, subject, htmlBody string, mimeHeaders map[string]string, config *SMTPConfig, category string) error {
+	fromMail := mail.Address{Name: config.FeedbackName, Address: config.FeedbackEmail}
+	replyTo := mail.Address{Name: config.FeedbackName, Address: config.ReplyToAddress}
+
+	mail := mailData{
+		mimeTo:      to,
+		smtpTo:      to,
+		from:        fromMail,
+		replyTo:     replyTo,
+		subject:     subject,
+		htmlBody:    htmlBody,
+		mimeHeaders: mimeHeaders,
+		category:    category,
+	}
+
+	return sendMailUsingConfigAdvanced(mail, config)
+}
+
 // allows for sending an email with differing MIME/SMTP recipients
 func sendMailUsingConfigAdvanced(mail mailData, config *SMTPConfig) error {
 	if config.Server == "" {
@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthn
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// maxCBORDepth bounds the nesting of the decoded items, authenticator data never
+// goes deeper than a few levels.
+const maxCBORDepth = 16
+
+var errCBORTruncated = errors.New("truncated cbor data")
+
+// decodeCBOR decodes the first CBOR data item of data, as used by the attestation
+// objects and COSE keys, and returns it along with the number of bytes it spans.
+//
+// Only the subset of RFC 8949 needed by WebAuthn is supported: integers are
+// returned as int64, byte strings as []byte, text strings as string, arrays as
+// []any and maps as map[any]any. Indefinite lengths and tags are rejected.
+func decodeCBOR(data []byte) (any, int, error) {
+	d := &cborDecoder{data: data}
+	item, err := d.decode(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return item, d.offset, nil
+}
+
+type cborDecoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *cborDecoder) readHeader() (byte, uint64, error) {
+	if d.offset >= len(d.data) {
+		return 0, 0, errCBORTruncated
+	}
+
+	initial := d.data[d.offset]
+	d.offset++
+	major := initial >> 5
+	info := initial & 0x1f
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, errors.Errorf("unsupported cbor additional information %d", info)
+	}
+
+	if len(d.data)-d.offset < size {
+		return 0, 0, errCBORTruncated
+	}
+	buf := make([]byte, 8)
+	copy(buf[8-size:], d.data[d.offset:d.offset+size])
+	d.offset += size
+
+	return major, binary.BigEndian.Uint64(buf), nil
+}
+
+func (d *cborDecoder) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.offset) {
+		return nil, errCBORTruncated
+	}
+	b := d.data[d.offset : d.offset+int(n)]
+	d.offset += int(n)
+	return b, nil
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("cbor data is nested too deeply")
+	}
+
+	major, arg, err := d.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor integer overflow")
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor integer overflow")
+		}
+		return -1 - int64(arg), nil
+	case 2:
+		b, err := d.readBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 3:
+		b, err := d.readBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case 4:
+		// Every item takes at least one byte, which bounds the allocation.
+		if arg > uint64(len(d.data)-d.offset) {
+			return nil, errCBORTruncated
+		}
+		items := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case 5:
+		if arg > uint64(len(d.data)-d.offset)/2 {
+			return nil, errCBORTruncated
+		}
+		m := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("unsupported cbor map key type")
+			}
+			if _, ok := m[key]; ok {
+				return nil, errors.New("duplicate cbor map key")
+			}
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case 7:
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		}
+		return nil, errors.Errorf("unsupported cbor simple value %d", arg)
+	}
+
+	return nil, errors.Errorf("unsupported cbor major type %d", major)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// COSE algorithm identifiers, from the IANA COSE Algorithms registry.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms lists the signature algorithms accepted for the credentials,
+// by order of preference.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+const (
+	coseKeyType      int64 = 1
+	coseKeyAlgorithm int64 = 3
+
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256    int64 = 1
+	coseCurveEd25519 int64 = 6
+
+	// Type specific parameters, the curve and coordinates of the OKP and EC2 keys,
+	// the modulus and exponent of the RSA keys.
+	coseKeyParam1 int64 = -1
+	coseKeyParam2 int64 = -2
+	coseKeyParam3 int64 = -3
+)
+
+// publicKey is a credential public key, decoded from its COSE_Key encoding.
+type publicKey struct {
+	algorithm int64
+	key       crypto.PublicKey
+}
+
+func parsePublicKey(data []byte) (*publicKey, error) {
+	item, n, err := decodeCBOR(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key encoding")
+	}
+	if n != len(data) {
+		return nil, errors.New("trailing data after the public key")
+	}
+	return publicKeyFromCOSE(item)
+}
+
+func publicKeyFromCOSE(item any) (*publicKey, error) {
+	m, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("public key is not a map")
+	}
+
+	kty, _ := m[coseKeyType].(int64)
+	alg, _ := m[coseKeyAlgorithm].(int64)
+
+	switch {
+	case kty == coseKeyTypeEC2 && alg == AlgES256:
+		crv, _ := m[coseKeyParam1].(int64)
+		x, _ := m[coseKeyParam2].([]byte)
+		y, _ := m[coseKeyParam3].([]byte)
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 public key")
+		}
+
+		// crypto/ecdh validates that the point is on the curve.
+		point := append([]byte{4}, append(append([]byte{}, x...), y...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, errors.Wrap(err, "invalid P-256 public key")
+		}
+
+		return &publicKey{
+			algorithm: alg,
+			key: &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(x),
+				Y:     new(big.Int).SetBytes(y),
+			},
+		}, nil
+	case kty == coseKeyTypeOKP && alg == AlgEdDSA:
+		crv, _ := m[coseKeyParam1].(int64)
+		x, _ := m[coseKeyParam2].([]byte)
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return &publicKey{algorithm: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == coseKeyTypeRSA && alg == AlgRS256:
+		n, _ := m[coseKeyParam1].([]byte)
+		e, _ := m[coseKeyParam2].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA public key")
+		}
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		if exponent < 3 || exponent%2 == 0 {
+			return nil, errors.New("invalid RSA public key exponent")
+		}
+		return &publicKey{
+			algorithm: alg,
+			key:       &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent},
+		}, nil
+	}
+
+	return nil, errors.Errorf("unsupported public key type %d with algorithm %d", kty, alg)
+}
+
+// verify checks the signature of the given data.
+func (k *publicKey) verify(data, signature []byte) error {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return ErrInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return ErrInvalidSignature
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+	default:
+		return errors.New("unsupported public key")
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package webauthn implements the relying party side of the Web Authentication
+// ceremonies: the verification of the attestations sent when registering an
+// authenticator, and of the assertions sent when authenticating with it.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidSignature indicates that the signature of an attestation or an
+	// assertion does not match the authenticator data.
+	ErrInvalidSignature = errors.New("invalid webauthn signature")
+	// ErrSignCountRegression indicates that the signature counter of an authenticator
+	// did not increase, which may denote a cloned authenticator.
+	ErrSignCountRegression = errors.New("webauthn signature counter did not increase")
+	// ErrUserVerificationRequired indicates that the authenticator did not verify
+	// the user, e.g. with a PIN or biometrics, while it was required.
+	ErrUserVerificationRequired = errors.New("webauthn user verification required")
+)
+
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	attestationFormatNone   = "none"
+	attestationFormatPacked = "packed"
+
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+	flagExtensionData          byte = 0x80
+
+	authenticatorDataMinLength = 37
+	aaguidLength               = 16
+	maxCredentialIdLength      = 1023
+)
+
+// oidAAGUID is the certificate extension holding the AAGUID of the authenticator
+// in packed attestations.
+var oidAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+// RelyingParty identifies the server to the authenticators.
+type RelyingParty struct {
+	// ID is the relying party identifier, the host name of the server.
+	ID string
+	// Origin is the web origin the ceremonies must run on, e.g. https://chat.example.com.
+	Origin string
+}
+
+// Credential is a public key credential created by an authenticator.
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE_Key encoding of the credential public key.
+	PublicKey []byte
+	SignCount uint32
+	AAGUID    []byte
+}
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+type authenticatorData struct {
+	rpIdHash  []byte
+	flags     byte
+	signCount uint32
+
+	aaguid       []byte
+	credentialId []byte
+	publicKey    []byte
+}
+
+// ClientDataChallenge returns the challenge a client data JSON was signed for,
+// which identifies the ceremony before verifying it.
+func ClientDataChallenge(clientDataJSON []byte) ([]byte, error) {
+	var cd clientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return nil, errors.Wrap(err, "invalid client data")
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(cd.Challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid client data challenge")
+	}
+
+	return challenge, nil
+}
+
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, ceremony string, challenge []byte) error {
+	var cd clientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return errors.Wrap(err, "invalid client data")
+	}
+
+	if cd.Type != ceremony {
+		return errors.Errorf("unexpected client data type %q", cd.Type)
+	}
+
+	received, err := base64.RawURLEncoding.DecodeString(cd.Challenge)
+	if err != nil {
+		return errors.Wrap(err, "invalid client data challenge")
+	}
+	if len(challenge) == 0 || subtle.ConstantTimeCompare(received, challenge) != 1 {
+		return errors.New("client data challenge mismatch")
+	}
+
+	if cd.Origin != rp.Origin {
+		return errors.Errorf("unexpected client data origin %q", cd.Origin)
+	}
+
+	if cd.CrossOrigin {
+		return errors.New("cross origin ceremonies are not allowed")
+	}
+
+	return nil
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(authData *authenticatorData, requireUserVerification bool) error {
+	rpIdHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(authData.rpIdHash, rpIdHash[:]) != 1 {
+		return errors.New("relying party id hash mismatch")
+	}
+
+	if authData.flags&flagUserPresent == 0 {
+		return errors.New("user presence required")
+	}
+
+	if requireUserVerification && authData.flags&flagUserVerified == 0 {
+		return ErrUserVerificationRequired
+	}
+
+	return nil
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < authenticatorDataMinLength {
+		return nil, errors.New("authenticator data is too short")
+	}
+
+	authData := &authenticatorData{
+		rpIdHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	rest := data[authenticatorDataMinLength:]
+	if authData.flags&flagAttestedCredentialData != 0 {
+		if len(rest) < aaguidLength+2 {
+			return nil, errors.New("attested credential data is too short")
+		}
+		authData.aaguid = rest[:aaguidLength]
+		idLength := int(binary.BigEndian.Uint16(rest[aaguidLength : aaguidLength+2]))
+		rest = rest[aaguidLength+2:]
+		if idLength > maxCredentialIdLength || len(rest) < idLength {
+			return nil, errors.New("invalid credential id length")
+		}
+		authData.credentialId = rest[:idLength]
+		rest = rest[idLength:]
+
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid credential public key")
+		}
+		authData.publicKey = rest[:n]
+		rest = rest[n:]
+	}
+
+	if authData.flags&flagExtensionData != 0 {
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid extension data")
+		}
+		rest = rest[n:]
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after the authenticator data")
+	}
+
+	return authData, nil
+}
+
+// VerifyRegistration verifies the response of an authenticator to a credential
+// creation request for the given challenge, and returns the created credential.
+//
+// The "none" and "packed" attestation formats are accepted. The attestation
+// certificates are not chained to a trust anchor: the attestation only proves
+// that the authenticator holds the credential private key.
+func (rp *RelyingParty) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte, requireUserVerification bool) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	item, n, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid attestation object")
+	}
+	if n != len(attestationObject) {
+		return nil, errors.New("trailing data after the attestation object")
+	}
+	object, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("attestation object is not a map")
+	}
+	format, _ := object["fmt"].(string)
+	statement, _ := object["attStmt"].(map[any]any)
+	rawAuthData, _ := object["authData"].([]byte)
+	if statement == nil || rawAuthData == nil {
+		return nil, errors.New("incomplete attestation object")
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err = rp.verifyAuthenticatorData(authData, requireUserVerification); err != nil {
+		return nil, err
+	}
+	if authData.publicKey == nil {
+		return nil, errors.New("attested credential data missing")
+	}
+
+	key, err := parsePublicKey(authData.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+
+	switch format {
+	case attestationFormatNone:
+		if len(statement) != 0 {
+			return nil, errors.New("unexpected attestation statement")
+		}
+	case attestationFormatPacked:
+		if err := verifyPackedAttestation(statement, key, authData.aaguid, signedData); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported attestation format %q", format)
+	}
+
+	return &Credential{
+		ID:        append([]byte{}, authData.credentialId...),
+		PublicKey: append([]byte{}, authData.publicKey...),
+		SignCount: authData.signCount,
+		AAGUID:    append([]byte{}, authData.aaguid...),
+	}, nil
+}
+
+func verifyPackedAttestation(statement map[any]any, key *publicKey, aaguid, signedData []byte) error {
+	alg, _ := statement["alg"].(int64)
+	signature, _ := statement["sig"].([]byte)
+	if signature == nil {
+		return errors.New("attestation signature missing")
+	}
+
+	x5c, hasCertificates := statement["x5c"].([]any)
+	if !hasCertificates {
+		// Self attestation, signed with the credential private key.
+		if alg != key.algorithm {
+			return errors.New("attestation algorithm mismatch")
+		}
+		return key.verify(signedData, signature)
+	}
+
+	if len(x5c) == 0 {
+		return errors.New("attestation certificate missing")
+	}
+	der, _ := x5c[0].([]byte)
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return errors.Wrap(err, "invalid attestation certificate")
+	}
+	if certificate.IsCA {
+		return errors.New("attestation certificate must not be a CA")
+	}
+
+	for _, extension := range certificate.Extensions {
+		if !extension.Id.Equal(oidAAGUID) {
+			continue
+		}
+		var value []byte
+		if _, err := asn1.Unmarshal(extension.Value, &value); err != nil || !bytes.Equal(value, aaguid) {
+			return errors.New("attestation certificate aaguid mismatch")
+		}
+	}
+
+	var signatureAlgorithm x509.SignatureAlgorithm
+	switch alg {
+	case AlgES256:
+		signatureAlgorithm = x509.ECDSAWithSHA256
+	case AlgRS256:
+		signatureAlgorithm = x509.SHA256WithRSA
+	case AlgEdDSA:
+		signatureAlgorithm = x509.PureEd25519
+	default:
+		return errors.Errorf("unsupported attestation algorithm %d", alg)
+	}
+
+	if err := certificate.CheckSignature(signatureAlgorithm, signedData, signature); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyAssertion verifies the response of an authenticator to an authentication
+// request for the given challenge, with the public key and signature counter of
+// the stored credential. It returns the new signature counter of the credential.
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, credentialPublicKey []byte, signCount uint32, clientDataJSON, rawAuthData, signature []byte, requireUserVerification bool) (uint32, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+	if err = rp.verifyAuthenticatorData(authData, requireUserVerification); err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(credentialPublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if err := key.verify(signedData, signature); err != nil {
+		return 0, err
+	}
+
+	// Authenticators without a counter, e.g. synced passkeys, always return zero.
+	if (authData.signCount != 0 || signCount != 0) && authData.signCount <= signCount {
+		return 0, ErrSignCountRegression
+	}
+
+	return authData.signCount, nil
+}
@@ -0,0 +1,424 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeCBOR is a minimal encoder for the values handled by decodeCBOR.
+func encodeCBOR(t *testing.T, v any) []byte {
+	header := func(major byte, arg uint64) []byte {
+		switch {
+		case arg < 24:
+			return []byte{major<<5 | byte(arg)}
+		case arg <= 0xff:
+			return []byte{major<<5 | 24, byte(arg)}
+		case arg <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+		case arg <= 0xffffffff:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+		}
+		return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, arg)
+	}
+
+	switch v := v.(type) {
+	case int:
+		return encodeCBOR(t, int64(v))
+	case int64:
+		if v < 0 {
+			return header(1, uint64(-1-v))
+		}
+		return header(0, uint64(v))
+	case []byte:
+		return append(header(2, uint64(len(v))), v...)
+	case string:
+		return append(header(3, uint64(len(v))), v...)
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	case []any:
+		out := header(4, uint64(len(v)))
+		for _, item := range v {
+			out = append(out, encodeCBOR(t, item)...)
+		}
+		return out
+	case map[any]any:
+		keys := make([]any, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return string(encodeCBOR(t, keys[i])) < string(encodeCBOR(t, keys[j]))
+		})
+		out := header(5, uint64(len(v)))
+		for _, key := range keys {
+			out = append(out, encodeCBOR(t, key)...)
+			out = append(out, encodeCBOR(t, v[key])...)
+		}
+		return out
+	}
+
+	t.Fatalf("unsupported cbor value %T", v)
+	return nil
+}
+
+// authenticator is a software authenticator holding a single credential.
+type authenticator struct {
+	t            *testing.T
+	rpID         string
+	credentialId []byte
+	aaguid       []byte
+	signer       crypto.Signer
+	signCount    uint32
+}
+
+func newAuthenticator(t *testing.T, rpID string, signer crypto.Signer) *authenticator {
+	a := &authenticator{
+		t:            t,
+		rpID:         rpID,
+		credentialId: make([]byte, 32),
+		aaguid:       make([]byte, 16),
+		signer:       signer,
+	}
+	rand.Read(a.credentialId)
+	rand.Read(a.aaguid)
+	return a
+}
+
+func (a *authenticator) coseKey() []byte {
+	switch key := a.signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+		return encodeCBOR(a.t, map[any]any{
+			coseKeyType: coseKeyTypeEC2, coseKeyAlgorithm: AlgES256,
+			coseKeyParam1: coseCurveP256, coseKeyParam2: x, coseKeyParam3: y,
+		})
+	case ed25519.PublicKey:
+		return encodeCBOR(a.t, map[any]any{
+			coseKeyType: coseKeyTypeOKP, coseKeyAlgorithm: AlgEdDSA,
+			coseKeyParam1: coseCurveEd25519, coseKeyParam2: []byte(key),
+		})
+	}
+	a.t.Fatal("unsupported key")
+	return nil
+}
+
+func (a *authenticator) algorithm() int64 {
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		return AlgEdDSA
+	}
+	return AlgES256
+}
+
+func (a *authenticator) sign(data []byte) []byte {
+	var signature []byte
+	var err error
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		signature, err = a.signer.Sign(rand.Reader, data, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(data)
+		signature, err = a.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	require.NoError(a.t, err)
+	return signature
+}
+
+func (a *authenticator) authData(flags byte, attested bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(a.rpID))
+	data := append(rpIdHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, a.aaguid...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialId)))
+		data = append(data, a.credentialId...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+func clientDataJSON(t *testing.T, ceremony string, challenge []byte, origin string) []byte {
+	data, err := json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    origin,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// create returns the client data and attestation object of a registration.
+func (a *authenticator) create(challenge []byte, origin, format string, flags byte) ([]byte, []byte) {
+	clientData := clientDataJSON(a.t, ceremonyCreate, challenge, origin)
+	authData := a.authData(flags|flagAttestedCredentialData, true)
+
+	statement := map[any]any{}
+	if format == attestationFormatPacked {
+		clientDataHash := sha256.Sum256(clientData)
+		statement["alg"] = a.algorithm()
+		statement["sig"] = a.sign(append(append([]byte{}, authData...), clientDataHash[:]...))
+	}
+
+	return clientData, encodeCBOR(a.t, map[any]any{
+		"fmt":      format,
+		"attStmt":  statement,
+		"authData": authData,
+	})
+}
+
+// get returns the client data, authenticator data and signature of an assertion.
+func (a *authenticator) get(challenge []byte, origin string, flags byte) ([]byte, []byte, []byte) {
+	a.signCount++
+	clientData := clientDataJSON(a.t, ceremonyGet, challenge, origin)
+	authData := a.authData(flags, false)
+	clientDataHash := sha256.Sum256(clientData)
+	return clientData, authData, a.sign(append(append([]byte{}, authData...), clientDataHash[:]...))
+}
+
+func newChallenge() []byte {
+	challenge := make([]byte, 32)
+	rand.Read(challenge)
+	return challenge
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	data := encodeCBOR(t, map[any]any{
+		"a": int64(-300),
+		1:   []any{"x", []byte{1, 2}, true},
+	})
+	item, n, err := decodeCBOR(append(data, 0xff))
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, map[any]any{"a": int64(-300), int64(1): []any{"x", []byte{1, 2}, true}}, item)
+
+	for name, data := range map[string][]byte{
+		"truncated":         data[:len(data)-1],
+		"huge length":       {0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"indefinite length": {0x5f, 0x41, 0x00, 0xff},
+		"tag":               {0xc1, 0x00},
+		"duplicate key":     {0xa2, 0x01, 0x01, 0x01, 0x02},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := decodeCBOR(data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	rp := &RelyingParty{ID: "chat.example.com", Origin: "https://chat.example.com"}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, format := range []string{attestationFormatNone, attestationFormatPacked} {
+		for name, signer := range map[string]crypto.Signer{"ES256": ecKey, "EdDSA": edKey} {
+			t.Run(format+" "+name, func(t *testing.T) {
+				a := newAuthenticator(t, rp.ID, signer)
+				challenge := newChallenge()
+				clientData, attestation := a.create(challenge, rp.Origin, format, flagUserPresent)
+
+				credential, err := rp.VerifyRegistration(challenge, clientData, attestation, false)
+				require.NoError(t, err)
+				assert.Equal(t, a.credentialId, credential.ID)
+				assert.Equal(t, a.aaguid, credential.AAGUID)
+				assert.Equal(t, a.coseKey(), credential.PublicKey)
+			})
+		}
+	}
+
+	t.Run("packed with attestation certificate", func(t *testing.T) {
+		attestationKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "Authenticator Attestation"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &attestationKey.PublicKey, attestationKey)
+		require.NoError(t, err)
+
+		a := newAuthenticator(t, rp.ID, ecKey)
+		challenge := newChallenge()
+		clientData := clientDataJSON(t, ceremonyCreate, challenge, rp.Origin)
+		authData := a.authData(flagUserPresent|flagAttestedCredentialData, true)
+		clientDataHash := sha256.Sum256(clientData)
+		digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+		signature, err := ecdsa.SignASN1(rand.Reader, attestationKey, digest[:])
+		require.NoError(t, err)
+
+		attestation := func(sig []byte) []byte {
+			return encodeCBOR(t, map[any]any{
+				"fmt":      attestationFormatPacked,
+				"attStmt":  map[any]any{"alg": AlgES256, "sig": sig, "x5c": []any{der}},
+				"authData": authData,
+			})
+		}
+
+		_, err = rp.VerifyRegistration(challenge, clientData, attestation(signature), false)
+		require.NoError(t, err)
+
+		_, err = rp.VerifyRegistration(challenge, clientData, attestation(a.sign(authData)), false)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	a := newAuthenticator(t, rp.ID, ecKey)
+
+	t.Run("challenge mismatch", func(t *testing.T) {
+		clientData, attestation := a.create(newChallenge(), rp.Origin, attestationFormatNone, flagUserPresent)
+		_, err := rp.VerifyRegistration(newChallenge(), clientData, attestation, false)
+		require.Error(t, err)
+	})
+
+	t.Run("origin mismatch", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, attestation := a.create(challenge, "https://evil.example.com", attestationFormatNone, flagUserPresent)
+		_, err := rp.VerifyRegistration(challenge, clientData, attestation, false)
+		require.Error(t, err)
+	})
+
+	t.Run("relying party mismatch", func(t *testing.T) {
+		other := newAuthenticator(t, "evil.example.com", ecKey)
+		challenge := newChallenge()
+		clientData, attestation := other.create(challenge, rp.Origin, attestationFormatNone, flagUserPresent)
+		_, err := rp.VerifyRegistration(challenge, clientData, attestation, false)
+		require.Error(t, err)
+	})
+
+	t.Run("user verification", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, attestation := a.create(challenge, rp.Origin, attestationFormatNone, flagUserPresent)
+		_, err := rp.VerifyRegistration(challenge, clientData, attestation, true)
+		require.ErrorIs(t, err, ErrUserVerificationRequired)
+
+		clientData, attestation = a.create(challenge, rp.Origin, attestationFormatNone, flagUserPresent|flagUserVerified)
+		_, err = rp.VerifyRegistration(challenge, clientData, attestation, true)
+		require.NoError(t, err)
+	})
+
+	t.Run("forged self attestation", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, attestation := a.create(challenge, rp.Origin, attestationFormatPacked, flagUserPresent)
+		item, _, err := decodeCBOR(attestation)
+		require.NoError(t, err)
+		item.(map[any]any)["attStmt"].(map[any]any)["sig"] = a.sign([]byte("something else"))
+
+		_, err = rp.VerifyRegistration(challenge, clientData, encodeCBOR(t, item), false)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, attestation := a.create(challenge, rp.Origin, "fido-u2f", flagUserPresent)
+		_, err := rp.VerifyRegistration(challenge, clientData, attestation, false)
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	rp := &RelyingParty{ID: "chat.example.com", Origin: "https://chat.example.com"}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	a := newAuthenticator(t, rp.ID, ecKey)
+	publicKey := a.coseKey()
+
+	t.Run("valid", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, authData, signature := a.get(challenge, rp.Origin, flagUserPresent)
+		signCount, err := rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, false)
+		require.NoError(t, err)
+		assert.Equal(t, a.signCount, signCount)
+	})
+
+	t.Run("sign count regression", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, authData, signature := a.get(challenge, rp.Origin, flagUserPresent)
+		_, err := rp.VerifyAssertion(challenge, publicKey, a.signCount, clientData, authData, signature, false)
+		require.ErrorIs(t, err, ErrSignCountRegression)
+	})
+
+	t.Run("authenticator without counter", func(t *testing.T) {
+		signCount := a.signCount
+		defer func() { a.signCount = signCount }()
+
+		// The counter wraps around to zero on the next assertion.
+		a.signCount = ^uint32(0)
+		challenge := newChallenge()
+		clientData, authData, signature := a.get(challenge, rp.Origin, flagUserPresent)
+		_, err := rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("user verification", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, authData, signature := a.get(challenge, rp.Origin, flagUserPresent)
+		_, err := rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, true)
+		require.ErrorIs(t, err, ErrUserVerificationRequired)
+
+		clientData, authData, signature = a.get(challenge, rp.Origin, flagUserPresent|flagUserVerified)
+		_, err = rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, true)
+		require.NoError(t, err)
+	})
+
+	t.Run("user presence", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, authData, signature := a.get(challenge, rp.Origin, 0)
+		_, err := rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, false)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong ceremony", func(t *testing.T) {
+		challenge := newChallenge()
+		_, authData, _ := a.get(challenge, rp.Origin, flagUserPresent)
+		clientData := clientDataJSON(t, ceremonyCreate, challenge, rp.Origin)
+		clientDataHash := sha256.Sum256(clientData)
+		signature := a.sign(append(append([]byte{}, authData...), clientDataHash[:]...))
+		_, err := rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, false)
+		require.Error(t, err)
+	})
+
+	t.Run("other credential", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		other := newAuthenticator(t, rp.ID, otherKey)
+		challenge := newChallenge()
+		clientData, authData, signature := other.get(challenge, rp.Origin, flagUserPresent)
+		_, err = rp.VerifyAssertion(challenge, publicKey, 0, clientData, authData, signature, false)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("challenge", func(t *testing.T) {
+		challenge := newChallenge()
+		clientData, _, _ := a.get(challenge, rp.Origin, flagUserPresent)
+		received, err := ClientDataChallenge(clientData)
+		require.NoError(t, err)
+		assert.Equal(t, challenge, received)
+	})
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Operators of the access control policy expressions.
+const (
+	AccessControlOperatorAnd      = "&&"
+	AccessControlOperatorOr       = "||"
+	AccessControlOperatorNot      = "!"
+	AccessControlOperatorEqual    = "=="
+	AccessControlOperatorNotEqual = "!="
+	AccessControlOperatorIn       = "in"
+)
+
+// AccessControlExpression is a parsed access control policy expression, such as
+// `user.attributes.department == "Engineering" && ("secret" in user.groups || user.auth_service in ["ldap", "saml"])`.
+//
+// Every operand of a comparison is a list of values: the values of an attribute, a single
+// string literal or a list literal. Comparisons are case insensitive, and hold when any
+// value of the left operand is equal to a value of the right one, so that missing
+// attributes match nothing.
+type AccessControlExpression struct {
+	Operator string
+	// Operands holds the operands of the logical operators and of the comparisons.
+	Operands []*AccessControlExpression
+	// Attribute is set for attribute operands.
+	Attribute string
+	// Values is set for literal operands.
+	Values []string
+}
+
+// ParseAccessControlExpression parses an access control policy expression, checking that
+// every attribute it refers to exists.
+func ParseAccessControlExpression(expression string) (*AccessControlExpression, *AppError) {
+	if utf8.RuneCountInString(expression) > AccessControlPolicyExpressionMaxRunes {
+		return nil, NewAccessControlExpressionError(expression, fmt.Sprintf("longer than %d characters", AccessControlPolicyExpressionMaxRunes))
+	}
+
+	tokens, err := tokenizeAccessControlExpression(expression)
+	if err != "" {
+		return nil, NewAccessControlExpressionError(expression, err)
+	}
+	if len(tokens) == 0 {
+		return nil, NewAccessControlExpressionError(expression, "empty expression")
+	}
+
+	p := &accessControlParser{tokens: tokens}
+	parsed, err := p.parseOr()
+	if err == "" && p.pos != len(p.tokens) {
+		err = fmt.Sprintf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != "" {
+		return nil, NewAccessControlExpressionError(expression, err)
+	}
+	return parsed, nil
+}
+
+// Attributes returns the names of the attributes the expression refers to.
+func (e *AccessControlExpression) Attributes() []string {
+	var attributes []string
+	seen := map[string]bool{}
+	var walk func(*AccessControlExpression)
+	walk = func(e *AccessControlExpression) {
+		if e.Attribute != "" && !seen[e.Attribute] {
+			seen[e.Attribute] = true
+			attributes = append(attributes, e.Attribute)
+		}
+		for _, operand := range e.Operands {
+			walk(operand)
+		}
+	}
+	walk(e)
+	return attributes
+}
+
+// Evaluate returns whether the attributes of the subject satisfy the expression.
+func (e *AccessControlExpression) Evaluate(subject *AccessControlSubject) bool {
+	switch e.Operator {
+	case AccessControlOperatorAnd:
+		return e.Operands[0].Evaluate(subject) && e.Operands[1].Evaluate(subject)
+	case AccessControlOperatorOr:
+		return e.Operands[0].Evaluate(subject) || e.Operands[1].Evaluate(subject)
+	case AccessControlOperatorNot:
+		return !e.Operands[0].Evaluate(subject)
+	case AccessControlOperatorEqual, AccessControlOperatorIn:
+		return accessControlValuesIntersect(e.Operands[0].values(subject), e.Operands[1].values(subject))
+	case AccessControlOperatorNotEqual:
+		return !accessControlValuesIntersect(e.Operands[0].values(subject), e.Operands[1].values(subject))
+	}
+	return false
+}
+
+func (e *AccessControlExpression) values(subject *AccessControlSubject) []string {
+	if e.Attribute != "" {
+		return subject.Attributes[e.Attribute]
+	}
+	return e.Values
+}
+
+func accessControlValuesIntersect(left, right []string) bool {
+	for _, l := range left {
+		for _, r := range right {
+			if strings.EqualFold(l, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsValidAccessControlAttribute returns whether policy expressions can refer to an
+// attribute. Custom profile fields may be referred to before they are created.
+func IsValidAccessControlAttribute(name string) bool {
+	if customName, ok := strings.CutPrefix(name, AccessControlAttributeCustomPrefix); ok {
+		return IsValidCustomProfileFieldName(customName)
+	}
+	return accessControlAttributes[name]
+}
+
+var accessControlTwoRuneOperators = map[string]bool{
+	AccessControlOperatorAnd:      true,
+	AccessControlOperatorOr:       true,
+	AccessControlOperatorEqual:    true,
+	AccessControlOperatorNotEqual: true,
+}
+
+type accessControlToken struct {
+	text string
+	// quoted is set for string literals, whose text holds the unquoted value.
+	quoted bool
+}
+
+func tokenizeAccessControlExpression(expression string) ([]accessControlToken, string) {
+	var tokens []accessControlToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, accessControlToken{text: string(r)})
+			i++
+		case i+1 < len(runes) && accessControlTwoRuneOperators[string(runes[i:i+2])]:
+			tokens = append(tokens, accessControlToken{text: string(runes[i : i+2])})
+			i += 2
+		case r == '!':
+			tokens = append(tokens, accessControlToken{text: AccessControlOperatorNot})
+			i++
+		case r == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, "unterminated string"
+			}
+			var value string
+			if err := json.Unmarshal([]byte(string(runes[i:end+1])), &value); err != nil {
+				return nil, "invalid string " + string(runes[i:end+1])
+			}
+			tokens = append(tokens, accessControlToken{text: value, quoted: true})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for ; end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.'); end++ {
+			}
+			tokens = append(tokens, accessControlToken{text: string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Sprintf("unexpected %q", r)
+		}
+	}
+	return tokens, ""
+}
+
+type accessControlParser struct {
+	tokens []accessControlToken
+	pos    int
+}
+
+func (p *accessControlParser) accept(text string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && p.tokens[p.pos].text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *accessControlParser) parseOr() (*AccessControlExpression, string) {
+	left, err := p.parseAnd()
+	for err == "" && p.accept(AccessControlOperatorOr) {
+		var right *AccessControlExpression
+		if right, err = p.parseAnd(); err == "" {
+			left = &AccessControlExpression{Operator: AccessControlOperatorOr, Operands: []*AccessControlExpression{left, right}}
+		}
+	}
+	return left, err
+}
+
+func (p *accessControlParser) parseAnd() (*AccessControlExpression, string) {
+	left, err := p.parseUnary()
+	for err == "" && p.accept(AccessControlOperatorAnd) {
+		var right *AccessControlExpression
+		if right, err = p.parseUnary(); err == "" {
+			left = &AccessControlExpression{Operator: AccessControlOperatorAnd, Operands: []*AccessControlExpression{left, right}}
+		}
+	}
+	return left, err
+}
+
+func (p *accessControlParser) parseUnary() (*AccessControlExpression, string) {
+	if p.accept(AccessControlOperatorNot) {
+		operand, err := p.parseUnary()
+		if err != "" {
+			return nil, err
+		}
+		return &AccessControlExpression{Operator: AccessControlOperatorNot, Operands: []*AccessControlExpression{operand}}, ""
+	}
+
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != "" {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, "missing )"
+		}
+		return inner, ""
+	}
+
+	return p.parseComparison()
+}
+
+func (p *accessControlParser) parseComparison() (*AccessControlExpression, string) {
+	left, err := p.parseOperand()
+	if err != "" {
+		return nil, err
+	}
+
+	for _, operator := range []string{AccessControlOperatorEqual, AccessControlOperatorNotEqual, AccessControlOperatorIn} {
+		if p.accept(operator) {
+			right, err := p.parseOperand()
+			if err != "" {
+				return nil, err
+			}
+			return &AccessControlExpression{Operator: operator, Operands: []*AccessControlExpression{left, right}}, ""
+		}
+	}
+	return nil, "expected a comparison"
+}
+
+func (p *accessControlParser) parseOperand() (*AccessControlExpression, string) {
+	if p.pos >= len(p.tokens) {
+		return nil, "unexpected end of expression"
+	}
+
+	token := p.tokens[p.pos]
+	switch {
+	case token.quoted:
+		p.pos++
+		return &AccessControlExpression{Values: []string{token.text}}, ""
+	case token.text == "[":
+		p.pos++
+		values := []string{}
+		for !p.accept("]") {
+			if len(values) > 0 && !p.accept(",") {
+				return nil, "expected , or ]"
+			}
+			if p.pos >= len(p.tokens) || !p.tokens[p.pos].quoted {
+				return nil, "lists may only hold strings"
+			}
+			values = append(values, p.tokens[p.pos].text)
+			p.pos++
+		}
+		return &AccessControlExpression{Values: values}, ""
+	case IsValidAccessControlAttribute(token.text):
+		p.pos++
+		return &AccessControlExpression{Attribute: token.text}, ""
+	}
+
+	return nil, fmt.Sprintf("unknown attribute %q", token.text)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccessControlExpression(t *testing.T) {
+	t.Run("valid expressions", func(t *testing.T) {
+		for _, expression := range []string{
+			`user.attributes.department == "Engineering"`,
+			`user.attributes.department != "Sales"`,
+			`"secret" in user.groups`,
+			`user.auth_service in ["saml", "ldap"]`,
+			`user.auth_service in []`,
+			`user.attributes.department == "Engineering" && !(user.auth_service == "")`,
+			`(user.auth_service == "saml" || user.auth_service == "ldap") && "system_user" in user.roles`,
+			`!!(user.attributes.department == "a \"quoted\" name")`,
+		} {
+			_, appErr := ParseAccessControlExpression(expression)
+			assert.Nil(t, appErr, expression)
+		}
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		for _, expression := range []string{
+			``,
+			`user.attributes.department`,
+			`user.attributes.department ==`,
+			`user.department == "Sales"`,
+			`user.position == "Engineer"`,
+			`user.username == "jdoe"`,
+			`user.email_domain in ["example.com"]`,
+			`user.attributes.department = "Engineering"`,
+			`user.attributes.department == "Engineering`,
+			`user.attributes.department == Engineering`,
+			`(user.attributes.department == "Engineering"`,
+			`user.attributes.department == "Engineering" &&`,
+			`user.attributes.department == "Engineering" & user.auth_service == "saml"`,
+			`user.auth_service in ["saml" "ldap"]`,
+			`user.auth_service in [user.attributes.department]`,
+			`user.attributes.department == "Engineering" user.auth_service == "saml"`,
+			`user.attributes.department == "` + strings.Repeat("a", AccessControlPolicyExpressionMaxRunes) + `"`,
+		} {
+			_, appErr := ParseAccessControlExpression(expression)
+			require.NotNil(t, appErr, expression)
+			assert.Equal(t, "model.access_control_policy.expression.app_error", appErr.Id)
+		}
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		parsed, appErr := ParseAccessControlExpression(`user.auth_service == "saml" || user.auth_service == "ldap" && !user.attributes.department == "Sales"`)
+		require.Nil(t, appErr)
+		assert.Equal(t, AccessControlOperatorOr, parsed.Operator)
+		assert.Equal(t, AccessControlOperatorAnd, parsed.Operands[1].Operator)
+		assert.Equal(t, AccessControlOperatorNot, parsed.Operands[1].Operands[1].Operator)
+		assert.Equal(t, []string{AccessControlAttributeAuthService, AccessControlAttributeCustomPrefix + "department"}, parsed.Attributes())
+	})
+}
+
+func TestAccessControlExpressionEvaluate(t *testing.T) {
+	subject := NewAccessControlSubject(&User{
+		Username:    "jdoe",
+		Email:       "jdoe@Example.com",
+		AuthService: UserAuthServiceSaml,
+		Roles:       SystemUserRoleId,
+	}, []*Group{{DisplayName: "Secret Clearance", Name: NewPointer("secret"), Source: GroupSourceScim}})
+	department := &CustomProfileField{Id: NewId(), Name: "department", Type: CustomProfileFieldTypeText, AdminManaged: true}
+	subject.AddCustomProfileAttributes([]*CustomProfileField{department}, []*CustomProfileValue{{FieldId: department.Id, Value: "Engineering"}})
+
+	for expression, expected := range map[string]bool{
+		`user.attributes.department == "engineering"`:                                                true,
+		`user.attributes.department != "Engineering"`:                                                false,
+		`user.attributes.department == "Sales"`:                                                      false,
+		`"secret" in user.groups`:                                                                    true,
+		`"Secret Clearance" in user.groups`:                                                          true,
+		`"top-secret" in user.groups`:                                                                false,
+		`user.auth_service in ["saml", "ldap"]`:                                                      true,
+		`user.auth_service in []`:                                                                    false,
+		`"system_user" in user.roles`:                                                                true,
+		`user.attributes.clearance == ""`:                                                            false,
+		`user.attributes.clearance != "anything"`:                                                    true,
+		`user.auth_service == ""`:                                                                    false,
+		`user.attributes.department == "Engineering" && user.auth_service == "ldap"`:                 false,
+		`user.attributes.department == "Engineering" && !(user.auth_service == "ldap")`:              true,
+		`user.auth_service == "ldap" || "secret" in user.groups`:                                     true,
+		`(user.auth_service == "ldap" || user.auth_service == "saml") && !("secret" in user.groups)`: false,
+	} {
+		parsed, appErr := ParseAccessControlExpression(expression)
+		require.Nil(t, appErr, expression)
+		assert.Equal(t, expected, parsed.Evaluate(subject), expression)
+	}
+}
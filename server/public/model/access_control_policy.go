@@ -0,0 +1,161 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const AccessControlPolicyExpressionMaxRunes = 4096
+
+// The attributes of a user access control policy expressions are evaluated against.
+// Multi-valued attributes hold every value, e.g. the names of all the user's groups.
+// Attributes users can change themselves, such as their position, username or email, are
+// left out so that nobody can grant themselves access to a channel.
+const (
+	AccessControlAttributeAuthService = "user.auth_service"
+	AccessControlAttributeRoles       = "user.roles"
+	// AccessControlAttributeGroups holds the groups synchronized from an identity
+	// provider.
+	AccessControlAttributeGroups = "user.groups"
+
+	// AccessControlAttributeCustomPrefix prefixes the names of the admin managed custom
+	// profile fields, e.g. user.attributes.department.
+	AccessControlAttributeCustomPrefix = "user.attributes."
+)
+
+var accessControlAttributes = map[string]bool{
+	AccessControlAttributeAuthService: true,
+	AccessControlAttributeRoles:       true,
+	AccessControlAttributeGroups:      true,
+}
+
+// accessControlGroupSources are the sources of the groups whose members are set by an
+// identity provider. Custom groups are left out since any user can create and join them.
+var accessControlGroupSources = map[GroupSource]bool{
+	GroupSourceLdap:   true,
+	GroupSourceOpenId: true,
+	GroupSourceScim:   true,
+}
+
+// AccessControlPolicy restricts the members of a channel to the users whose attributes
+// match its expression. Users who stop matching are removed by the access control job.
+type AccessControlPolicy struct {
+	ChannelId  string `json:"channel_id"`
+	Expression string `json:"expression"`
+	CreatorId  string `json:"creator_id"`
+	CreateAt   int64  `json:"create_at"`
+	UpdateAt   int64  `json:"update_at"`
+}
+
+func (p *AccessControlPolicy) Auditable() map[string]any {
+	return map[string]any{
+		"channel_id": p.ChannelId,
+		"expression": p.Expression,
+		"creator_id": p.CreatorId,
+		"create_at":  p.CreateAt,
+		"update_at":  p.UpdateAt,
+	}
+}
+
+func (p *AccessControlPolicy) PreSave() {
+	if p.CreateAt == 0 {
+		p.CreateAt = GetMillis()
+	}
+	p.UpdateAt = GetMillis()
+}
+
+func (p *AccessControlPolicy) IsValid() *AppError {
+	if !IsValidId(p.ChannelId) {
+		return NewAppError("AccessControlPolicy.IsValid", "model.access_control_policy.is_valid.channel_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(p.CreatorId) {
+		return NewAppError("AccessControlPolicy.IsValid", "model.access_control_policy.is_valid.creator_id.app_error", nil, "channel_id="+p.ChannelId, http.StatusBadRequest)
+	}
+
+	if p.CreateAt == 0 || p.UpdateAt == 0 {
+		return NewAppError("AccessControlPolicy.IsValid", "model.access_control_policy.is_valid.create_at.app_error", nil, "channel_id="+p.ChannelId, http.StatusBadRequest)
+	}
+
+	if _, appErr := ParseAccessControlExpression(p.Expression); appErr != nil {
+		return appErr
+	}
+
+	return nil
+}
+
+// AccessControlPolicySimulation previews the effect of an expression on the current
+// members of a channel.
+type AccessControlPolicySimulation struct {
+	MemberCount    int      `json:"member_count"`
+	MatchingCount  int      `json:"matching_count"`
+	RemovedUserIds []string `json:"removed_user_ids"`
+}
+
+// AccessControlSubject holds the attributes of a user, keyed by their name in policy
+// expressions.
+type AccessControlSubject struct {
+	Attributes map[string][]string
+}
+
+// NewAccessControlSubject returns the attributes of a user, given the groups they are a
+// member of. Only the groups synchronized from an identity provider are kept.
+func NewAccessControlSubject(user *User, groups []*Group) *AccessControlSubject {
+	attributes := map[string][]string{
+		AccessControlAttributeAuthService: {user.AuthService},
+		AccessControlAttributeRoles:       strings.Fields(user.Roles),
+	}
+
+	groupNames := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if group.DeleteAt != 0 || !accessControlGroupSources[group.Source] {
+			continue
+		}
+		groupNames = append(groupNames, group.DisplayName)
+		if name := group.GetName(); name != "" {
+			groupNames = append(groupNames, name)
+		}
+	}
+	attributes[AccessControlAttributeGroups] = groupNames
+
+	// Empty values are missing rather than matching the empty string.
+	for name, values := range attributes {
+		nonEmpty := values[:0]
+		for _, value := range values {
+			if value != "" {
+				nonEmpty = append(nonEmpty, value)
+			}
+		}
+		attributes[name] = nonEmpty
+	}
+
+	return &AccessControlSubject{Attributes: attributes}
+}
+
+// AddCustomProfileAttributes adds the values of the admin managed custom profile fields of
+// the user. The values of the other fields are set by the user themselves.
+func (s *AccessControlSubject) AddCustomProfileAttributes(fields []*CustomProfileField, values []*CustomProfileValue) {
+	fieldsByID := make(map[string]*CustomProfileField, len(fields))
+	for _, field := range fields {
+		if field.DeleteAt == 0 && field.AdminManaged {
+			fieldsByID[field.Id] = field
+		}
+	}
+
+	for _, value := range values {
+		if field, ok := fieldsByID[value.FieldId]; ok {
+			s.Attributes[AccessControlAttributeCustomPrefix+field.Name] = field.Values(value.Value)
+		}
+	}
+}
+
+func NewAccessControlExpressionError(expression, details string) *AppError {
+	if utf8.RuneCountInString(expression) > 64 {
+		expression = string([]rune(expression)[:64]) + "..."
+	}
+	return NewAppError("ParseAccessControlExpression", "model.access_control_policy.expression.app_error", map[string]any{"Details": details}, "expression="+expression, http.StatusBadRequest)
+}
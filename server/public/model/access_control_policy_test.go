@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessControlPolicyIsValid(t *testing.T) {
+	policy := &AccessControlPolicy{
+		ChannelId:  NewId(),
+		CreatorId:  NewId(),
+		Expression: `user.attributes.department == "Engineering"`,
+	}
+	policy.PreSave()
+	require.Nil(t, policy.IsValid())
+
+	for name, invalidate := range map[string]func(*AccessControlPolicy){
+		"model.access_control_policy.is_valid.channel_id.app_error": func(p *AccessControlPolicy) { p.ChannelId = "invalid" },
+		"model.access_control_policy.is_valid.creator_id.app_error": func(p *AccessControlPolicy) { p.CreatorId = "" },
+		"model.access_control_policy.is_valid.create_at.app_error":  func(p *AccessControlPolicy) { p.CreateAt = 0 },
+		"model.access_control_policy.expression.app_error":          func(p *AccessControlPolicy) { p.Expression = `user.position == "Engineer"` },
+	} {
+		invalid := *policy
+		invalidate(&invalid)
+		appErr := invalid.IsValid()
+		require.NotNil(t, appErr, name)
+		assert.Equal(t, name, appErr.Id)
+	}
+}
+
+func TestNewAccessControlSubject(t *testing.T) {
+	subject := NewAccessControlSubject(&User{
+		Username: "jdoe",
+		Email:    "jdoe@example.com",
+		Position: "Engineer",
+		Nickname: "Top Secret",
+		Roles:    "system_user system_admin",
+	}, []*Group{
+		{DisplayName: "Engineering", Name: NewPointer("engineering"), Source: GroupSourceScim},
+		{DisplayName: "LDAP Group", Source: GroupSourceLdap},
+		{DisplayName: "Custom", Name: NewPointer("custom"), Source: GroupSourceCustom},
+		{DisplayName: "Deleted", Source: GroupSourceLdap, DeleteAt: GetMillis()},
+	})
+
+	assert.Equal(t, []string{"system_user", "system_admin"}, subject.Attributes[AccessControlAttributeRoles])
+	assert.Equal(t, []string{"Engineering", "engineering", "LDAP Group"}, subject.Attributes[AccessControlAttributeGroups])
+	for _, values := range subject.Attributes {
+		assert.NotContains(t, values, "Engineer")
+		assert.NotContains(t, values, "Top Secret")
+		assert.NotContains(t, values, "jdoe")
+		assert.NotContains(t, values, "example.com")
+	}
+}
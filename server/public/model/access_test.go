@@ -53,3 +53,26 @@ func TestAccessIsValid(t *testing.T) {
 	ad.RedirectUri = "http://example.com"
 	require.Nil(t, ad.IsValid())
 }
+
+func TestAccessIsValidGrantType(t *testing.T) {
+	ad := AccessData{
+		ClientId:    NewId(),
+		UserId:      NewId(),
+		Token:       NewId(),
+		RedirectUri: "http://example.com",
+		GrantType:   AccessTokenGrantType,
+	}
+	require.Nil(t, ad.IsValid())
+
+	for _, grantType := range []string{ClientCredentialsGrantType, DeviceCodeGrantType} {
+		ad.GrantType = grantType
+		ad.RedirectUri = "http://example.com"
+		require.NotNil(t, ad.IsValid(), "Should have failed redirect URI set for %s", grantType)
+
+		ad.RedirectUri = ""
+		require.Nil(t, ad.IsValid())
+	}
+
+	ad.GrantType = "password"
+	require.NotNil(t, ad.IsValid(), "Should have failed unsupported grant type")
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	AuditRecordSearchDefaultLimit = 100
+	AuditRecordSearchMaxLimit     = 1000
+
+	AuditRecordExportFormatCSV   = "csv"
+	AuditRecordExportFormatJSONL = "jsonl"
+
+	auditRecordEventNameMaxLength  = 256
+	auditRecordStatusMaxLength     = 32
+	auditRecordIpAddressMaxLength  = 64
+	auditRecordObjectTypeMaxLength = 64
+	auditRecordHashLength          = 64 // hex encoded SHA-256
+)
+
+// AuditRecord is an audit record persisted to the database, as logged by the audit log.
+// The record is part of the hash chain identified by ChainId: Hash is the SHA-256 of
+// Data, the canonical JSON encoding of the record, which includes the hash of the
+// previous record of the chain.
+type AuditRecord struct {
+	Id         string          `json:"id"`
+	ChainId    string          `json:"chain_id"`
+	Sequence   int64           `json:"seq"`
+	CreateAt   int64           `json:"create_at"`
+	EventName  string          `json:"event_name"`
+	Status     string          `json:"status"`
+	UserId     string          `json:"user_id"`
+	SessionId  string          `json:"session_id"`
+	IpAddress  string          `json:"ip_address"`
+	ObjectType string          `json:"object_type"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	Signature  string          `json:"signature,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// AuditRecordSearchOptions filters the audit records persisted to the database. The time
+// range is inclusive and in milliseconds.
+type AuditRecordSearchOptions struct {
+	UserId     string
+	EventName  string
+	IpAddress  string
+	ObjectType string
+	Status     string
+	StartTime  int64
+	EndTime    int64
+	// Cursor is the position, in the list of the records from the most recent, after which
+	// the records are returned. The records are returned from the most recent when empty.
+	Cursor AuditRecordCursor
+	Limit  int
+}
+
+// AuditRecordCursor is the position of an audit record in the list of the records sorted
+// from the most recent.
+type AuditRecordCursor struct {
+	CreateAt int64
+	Id       string
+}
+
+// AuditRecordList is a page of audit records. NextCursor is empty on the last page.
+type AuditRecordList struct {
+	Records    []*AuditRecord `json:"records"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+// AuditRecordCSVHeader is the header of the CSV export of audit records, matching
+// AuditRecord.CSVRow.
+var AuditRecordCSVHeader = []string{"id", "create_at", "event_name", "status", "user_id", "session_id", "ip_address", "object_type", "chain_id", "seq", "prev_hash", "hash", "signature", "data"}
+
+func (c AuditRecordCursor) IsEmpty() bool {
+	return c.CreateAt == 0 && c.Id == ""
+}
+
+// Encode returns the cursor as an opaque string, as passed to the API.
+func (c AuditRecordCursor) Encode() string {
+	if c.IsEmpty() {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreateAt, 10) + ":" + c.Id))
+}
+
+// DecodeAuditRecordCursor parses a cursor returned by AuditRecordCursor.Encode.
+func DecodeAuditRecordCursor(s string) (AuditRecordCursor, error) {
+	if s == "" {
+		return AuditRecordCursor{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return AuditRecordCursor{}, fmt.Errorf("invalid audit record cursor: %w", err)
+	}
+
+	createAt, id, found := strings.Cut(string(data), ":")
+	if !found || !IsValidId(id) {
+		return AuditRecordCursor{}, errors.New("invalid audit record cursor")
+	}
+
+	c := AuditRecordCursor{Id: id}
+	if c.CreateAt, err = strconv.ParseInt(createAt, 10, 64); err != nil || c.CreateAt <= 0 {
+		return AuditRecordCursor{}, errors.New("invalid audit record cursor")
+	}
+	return c, nil
+}
+
+func (r *AuditRecord) PreSave() {
+	if r.Id == "" {
+		r.Id = NewId()
+	}
+
+	if r.CreateAt == 0 {
+		r.CreateAt = GetMillis()
+	}
+}
+
+func (r *AuditRecord) IsValid() *AppError {
+	if !IsValidId(r.Id) {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(r.ChainId) {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.chain_id.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.Sequence < 1 {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.sequence.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.CreateAt == 0 {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.create_at.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if len(r.EventName) > auditRecordEventNameMaxLength || len(r.Status) > auditRecordStatusMaxLength {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.event.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if len(r.UserId) > 26 || len(r.SessionId) > 26 || len(r.IpAddress) > auditRecordIpAddressMaxLength || len(r.ObjectType) > auditRecordObjectTypeMaxLength {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.actor.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if (r.PrevHash != "" && len(r.PrevHash) != auditRecordHashLength) || len(r.Hash) != auditRecordHashLength {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.hash.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if !json.Valid(r.Data) {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.data.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// CSVRow returns the fields of the record in the order of AuditRecordCSVHeader.
+func (r *AuditRecord) CSVRow() []string {
+	return []string{
+		r.Id,
+		strconv.FormatInt(r.CreateAt, 10),
+		r.EventName,
+		r.Status,
+		r.UserId,
+		r.SessionId,
+		r.IpAddress,
+		r.ObjectType,
+		r.ChainId,
+		strconv.FormatInt(r.Sequence, 10),
+		r.PrevHash,
+		r.Hash,
+		r.Signature,
+		string(r.Data),
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRecordIsValid(t *testing.T) {
+	newRecord := func() *AuditRecord {
+		record := &AuditRecord{
+			ChainId:   NewId(),
+			Sequence:  2,
+			EventName: "login",
+			Status:    "success",
+			PrevHash:  strings.Repeat("a", 64),
+			Hash:      strings.Repeat("b", 64),
+			Data:      json.RawMessage(`{"event_name":"login"}`),
+		}
+		record.PreSave()
+		return record
+	}
+
+	require.Nil(t, newRecord().IsValid())
+
+	for name, tc := range map[string]struct {
+		update func(r *AuditRecord)
+		errID  string
+	}{
+		"invalid id":         {update: func(r *AuditRecord) { r.Id = "invalid" }, errID: "model.audit_record.is_valid.id.app_error"},
+		"invalid chain":      {update: func(r *AuditRecord) { r.ChainId = "" }, errID: "model.audit_record.is_valid.chain_id.app_error"},
+		"invalid sequence":   {update: func(r *AuditRecord) { r.Sequence = 0 }, errID: "model.audit_record.is_valid.sequence.app_error"},
+		"missing create at":  {update: func(r *AuditRecord) { r.CreateAt = 0 }, errID: "model.audit_record.is_valid.create_at.app_error"},
+		"long event name":    {update: func(r *AuditRecord) { r.EventName = strings.Repeat("a", 257) }, errID: "model.audit_record.is_valid.event.app_error"},
+		"long ip address":    {update: func(r *AuditRecord) { r.IpAddress = strings.Repeat("1", 65) }, errID: "model.audit_record.is_valid.actor.app_error"},
+		"invalid hash":       {update: func(r *AuditRecord) { r.Hash = "abc" }, errID: "model.audit_record.is_valid.hash.app_error"},
+		"invalid prev hash":  {update: func(r *AuditRecord) { r.PrevHash = "abc" }, errID: "model.audit_record.is_valid.hash.app_error"},
+		"invalid data":       {update: func(r *AuditRecord) { r.Data = json.RawMessage(`{`) }, errID: "model.audit_record.is_valid.data.app_error"},
+		"first of the chain": {update: func(r *AuditRecord) { r.Sequence = 1; r.PrevHash = "" }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			record := newRecord()
+			tc.update(record)
+			err := record.IsValid()
+			if tc.errID == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tc.errID, err.Id)
+		})
+	}
+}
+
+func TestAuditRecordCursor(t *testing.T) {
+	assert.Equal(t, "", AuditRecordCursor{}.Encode())
+
+	empty, err := DecodeAuditRecordCursor("")
+	require.NoError(t, err)
+	assert.True(t, empty.IsEmpty())
+
+	cursor := AuditRecordCursor{CreateAt: 1700000000000, Id: NewId()}
+	decoded, err := DecodeAuditRecordCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	for _, invalid := range []string{"!", "MTIz", AuditRecordCursor{CreateAt: 1, Id: "invalid"}.Encode(), AuditRecordCursor{CreateAt: -1, Id: NewId()}.Encode()} {
+		_, err := DecodeAuditRecordCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAuditRecordCSVRow(t *testing.T) {
+	record := &AuditRecord{Id: NewId(), CreateAt: 1000, EventName: "login", Sequence: 3, Data: json.RawMessage(`{"a":1}`)}
+	row := record.CSVRow()
+	require.Len(t, row, len(AuditRecordCSVHeader))
+	assert.Equal(t, record.Id, row[0])
+	assert.Equal(t, "1000", row[1])
+	assert.Equal(t, "3", row[9])
+	assert.Equal(t, `{"a":1}`, row[13])
+}
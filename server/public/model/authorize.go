@@ -4,7 +4,11 @@
 package model
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"net/http"
+	"regexp"
 )
 
 const (
@@ -12,8 +16,14 @@ const (
 	AuthCodeResponseType = "code"
 	ImplicitResponseType = "token"
 	DefaultScope         = "user"
+
+	PKCEMethodS256  = "S256"
+	PKCEMethodPlain = "plain"
 )
 
+// pkceValueRegexp matches a code verifier or code challenge as defined by RFC 7636.
+var pkceValueRegexp = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
 type AuthData struct {
 	ClientId    string `json:"client_id"`
 	UserId      string `json:"user_id"`
@@ -23,6 +33,9 @@ type AuthData struct {
 	RedirectUri string `json:"redirect_uri"`
 	State       string `json:"state"`
 	Scope       string `json:"scope"`
+
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
 }
 
 type AuthorizeRequest struct {
@@ -31,6 +44,9 @@ type AuthorizeRequest struct {
 	RedirectURI  string `json:"redirect_uri"`
 	Scope        string `json:"scope"`
 	State        string `json:"state"`
+
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
 }
 
 // IsValid validates the AuthData and returns an error if it isn't configured
@@ -68,6 +84,10 @@ func (ad *AuthData) IsValid() *AppError {
 		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.scope.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
 	}
 
+	if !isValidCodeChallenge(ad.CodeChallenge, ad.CodeChallengeMethod) {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -94,9 +114,26 @@ func (ar *AuthorizeRequest) IsValid() *AppError {
 		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.scope.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
 	}
 
+	if !isValidCodeChallenge(ar.CodeChallenge, ar.CodeChallengeMethod) {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
+// isValidCodeChallenge validates an optional PKCE code challenge and its method.
+func isValidCodeChallenge(challenge, method string) bool {
+	if challenge == "" {
+		return method == ""
+	}
+
+	if !pkceValueRegexp.MatchString(challenge) {
+		return false
+	}
+
+	return method == "" || method == PKCEMethodS256 || method == PKCEMethodPlain
+}
+
 func (ad *AuthData) PreSave() {
 	if ad.ExpiresIn == 0 {
 		ad.ExpiresIn = AuthCodeExpireTime
@@ -109,8 +146,34 @@ func (ad *AuthData) PreSave() {
 	if ad.Scope == "" {
 		ad.Scope = DefaultScope
 	}
+
+	// The method defaults to plain when it's omitted, as per RFC 7636.
+	if ad.CodeChallenge != "" && ad.CodeChallengeMethod == "" {
+		ad.CodeChallengeMethod = PKCEMethodPlain
+	}
 }
 
 func (ad *AuthData) IsExpired() bool {
 	return GetMillis() > ad.CreateAt+int64(ad.ExpiresIn*1000)
 }
+
+// VerifyCodeVerifier checks the PKCE code verifier sent with the token request
+// against the code challenge sent with the authorization request. When no code
+// challenge was sent, no code verifier may be sent either.
+func (ad *AuthData) VerifyCodeVerifier(verifier string) bool {
+	if ad.CodeChallenge == "" {
+		return verifier == ""
+	}
+
+	if !pkceValueRegexp.MatchString(verifier) {
+		return false
+	}
+
+	expected := verifier
+	if ad.CodeChallengeMethod == PKCEMethodS256 {
+		sum := sha256.Sum256([]byte(verifier))
+		expected = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(ad.CodeChallenge)) == 1
+}
@@ -80,3 +80,64 @@ func TestAuthIsValid(t *testing.T) {
 	ad.RedirectUri = "http://example.com"
 	require.Nil(t, ad.IsValid())
 }
+
+func TestAuthCodeChallengeIsValid(t *testing.T) {
+	ad := AuthData{
+		ClientId:    NewId(),
+		UserId:      NewId(),
+		Code:        NewId(),
+		ExpiresIn:   1,
+		CreateAt:    1,
+		RedirectUri: "http://example.com",
+	}
+	require.Nil(t, ad.IsValid())
+
+	ad.CodeChallengeMethod = PKCEMethodS256
+	require.NotNil(t, ad.IsValid(), "Should have failed method without challenge")
+
+	ad.CodeChallenge = NewRandomString(42)
+	require.NotNil(t, ad.IsValid(), "Should have failed challenge too short")
+
+	ad.CodeChallenge = NewRandomString(129)
+	require.NotNil(t, ad.IsValid(), "Should have failed challenge too long")
+
+	ad.CodeChallenge = NewRandomString(43)
+	require.Nil(t, ad.IsValid())
+
+	ad.CodeChallengeMethod = "S512"
+	require.NotNil(t, ad.IsValid(), "Should have failed unknown method")
+
+	ad.CodeChallengeMethod = ""
+	require.Nil(t, ad.IsValid())
+	ad.PreSave()
+	require.Equal(t, PKCEMethodPlain, ad.CodeChallengeMethod)
+}
+
+func TestAuthVerifyCodeVerifier(t *testing.T) {
+	t.Run("no challenge", func(t *testing.T) {
+		ad := AuthData{}
+		require.True(t, ad.VerifyCodeVerifier(""))
+		require.False(t, ad.VerifyCodeVerifier(NewRandomString(43)))
+	})
+
+	t.Run("S256", func(t *testing.T) {
+		// Example from RFC 7636, appendix B.
+		ad := AuthData{
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: PKCEMethodS256,
+		}
+		require.True(t, ad.VerifyCodeVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+		require.False(t, ad.VerifyCodeVerifier("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"))
+		require.False(t, ad.VerifyCodeVerifier(""))
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		verifier := NewRandomString(64)
+		ad := AuthData{
+			CodeChallenge:       verifier,
+			CodeChallengeMethod: PKCEMethodPlain,
+		}
+		require.True(t, ad.VerifyCodeVerifier(verifier))
+		require.False(t, ad.VerifyCodeVerifier(NewRandomString(64)))
+	})
+}
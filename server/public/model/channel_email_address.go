@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	ChannelEmailAddressMaxAllowedSenders   = 100
+	ChannelEmailAddressAllowedSenderMaxLen = 254
+)
+
+// ChannelEmailAddress is the inbound email address of a channel. The emails sent to it are
+// posted to the channel, as the sender when they are a user of the server, or through the
+// incoming webhook of the address otherwise.
+type ChannelEmailAddress struct {
+	ChannelId string `json:"channel_id"`
+	// Token is the unique part of the address. It is replaced when the address is regenerated.
+	Token string `json:"token"`
+	// Address is the full address, computed from the token and the configuration.
+	Address string `json:"address" db:"-"`
+	// WebhookId is the incoming webhook posting the emails of the senders that are not users
+	// of the server. Those emails are rejected when it is empty.
+	WebhookId string `json:"webhook_id"`
+	// AllowedSenders are the email addresses and domains, e.g. "@example.com", allowed to
+	// send emails to the address. Only the emails authenticated by a DKIM signature of the
+	// domain of the sender are accepted then. All senders are allowed when it is empty.
+	AllowedSenders StringArray `json:"allowed_senders"`
+	CreatorId      string      `json:"creator_id"`
+	CreateAt       int64       `json:"create_at"`
+	UpdateAt       int64       `json:"update_at"`
+}
+
+type ChannelEmailAddressPatch struct {
+	AllowedSenders *[]string `json:"allowed_senders"`
+}
+
+func (a *ChannelEmailAddress) Auditable() map[string]any {
+	return map[string]any{
+		"channel_id":      a.ChannelId,
+		"webhook_id":      a.WebhookId,
+		"allowed_senders": a.AllowedSenders,
+		"creator_id":      a.CreatorId,
+		"create_at":       a.CreateAt,
+		"update_at":       a.UpdateAt,
+	}
+}
+
+func (a *ChannelEmailAddress) PreSave() {
+	if a.Token == "" {
+		a.Token = NewId()
+	}
+
+	if a.AllowedSenders == nil {
+		a.AllowedSenders = StringArray{}
+	}
+
+	a.CreateAt = GetMillis()
+	a.UpdateAt = a.CreateAt
+}
+
+func (a *ChannelEmailAddress) PreUpdate() {
+	if a.AllowedSenders == nil {
+		a.AllowedSenders = StringArray{}
+	}
+
+	a.UpdateAt = GetMillis()
+}
+
+func (a *ChannelEmailAddress) Patch(patch *ChannelEmailAddressPatch) {
+	if patch.AllowedSenders != nil {
+		a.AllowedSenders = normalizeAllowedSenders(*patch.AllowedSenders)
+	}
+}
+
+func (a *ChannelEmailAddress) IsValid() *AppError {
+	if !IsValidId(a.ChannelId) {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.channel_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(a.Token) {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.token.app_error", nil, "channel_id="+a.ChannelId, http.StatusBadRequest)
+	}
+
+	if a.WebhookId != "" && !IsValidId(a.WebhookId) {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.webhook_id.app_error", nil, "channel_id="+a.ChannelId, http.StatusBadRequest)
+	}
+
+	if !IsValidId(a.CreatorId) {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.creator_id.app_error", nil, "channel_id="+a.ChannelId, http.StatusBadRequest)
+	}
+
+	if len(a.AllowedSenders) > ChannelEmailAddressMaxAllowedSenders {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.allowed_senders.app_error", map[string]any{"Max": ChannelEmailAddressMaxAllowedSenders}, "channel_id="+a.ChannelId, http.StatusBadRequest)
+	}
+
+	for _, sender := range a.AllowedSenders {
+		if !isValidAllowedSender(sender) {
+			return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.allowed_sender.app_error", map[string]any{"Sender": sender}, "channel_id="+a.ChannelId, http.StatusBadRequest)
+		}
+	}
+
+	if a.CreateAt == 0 {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.create_at.app_error", nil, "channel_id="+a.ChannelId, http.StatusBadRequest)
+	}
+
+	if a.UpdateAt == 0 {
+		return NewAppError("ChannelEmailAddress.IsValid", "model.channel_email_address.is_valid.update_at.app_error", nil, "channel_id="+a.ChannelId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsSenderAllowed reports whether the emails of the sender are accepted.
+func (a *ChannelEmailAddress) IsSenderAllowed(sender string) bool {
+	if len(a.AllowedSenders) == 0 {
+		return true
+	}
+
+	sender = strings.ToLower(sender)
+	at := strings.LastIndex(sender, "@")
+	if at < 0 {
+		return false
+	}
+
+	for _, allowed := range a.AllowedSenders {
+		if allowed == sender || allowed == sender[at:] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeAllowedSenders(senders []string) StringArray {
+	normalized := StringArray{}
+	for _, sender := range senders {
+		sender = strings.ToLower(strings.TrimSpace(sender))
+		if sender == "" || normalized.Contains(sender) {
+			continue
+		}
+		normalized = append(normalized, sender)
+	}
+	return normalized
+}
+
+func isValidAllowedSender(sender string) bool {
+	if len(sender) > ChannelEmailAddressAllowedSenderMaxLen {
+		return false
+	}
+
+	if domain, found := strings.CutPrefix(sender, "@"); found {
+		return domain != "" && !strings.ContainsAny(domain, "@ ") && strings.Contains(domain, ".")
+	}
+
+	return IsValidEmail(sender)
+}
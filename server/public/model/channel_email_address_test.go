@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelEmailAddressIsValid(t *testing.T) {
+	newAddress := func() *ChannelEmailAddress {
+		address := &ChannelEmailAddress{
+			ChannelId: NewId(),
+			WebhookId: NewId(),
+			CreatorId: NewId(),
+		}
+		address.PreSave()
+		return address
+	}
+
+	require.Nil(t, newAddress().IsValid())
+
+	for name, tc := range map[string]struct {
+		update func(a *ChannelEmailAddress)
+		errID  string
+	}{
+		"invalid channel": {update: func(a *ChannelEmailAddress) { a.ChannelId = "invalid" }, errID: "model.channel_email_address.is_valid.channel_id.app_error"},
+		"invalid token":   {update: func(a *ChannelEmailAddress) { a.Token = "invalid" }, errID: "model.channel_email_address.is_valid.token.app_error"},
+		"invalid webhook": {update: func(a *ChannelEmailAddress) { a.WebhookId = "invalid" }, errID: "model.channel_email_address.is_valid.webhook_id.app_error"},
+		"invalid creator": {update: func(a *ChannelEmailAddress) { a.CreatorId = "" }, errID: "model.channel_email_address.is_valid.creator_id.app_error"},
+		"invalid sender":  {update: func(a *ChannelEmailAddress) { a.AllowedSenders = StringArray{"not an email"} }, errID: "model.channel_email_address.is_valid.allowed_sender.app_error"},
+		"invalid domain":  {update: func(a *ChannelEmailAddress) { a.AllowedSenders = StringArray{"@localhost"} }, errID: "model.channel_email_address.is_valid.allowed_sender.app_error"},
+		"too many senders": {update: func(a *ChannelEmailAddress) {
+			a.AllowedSenders = make(StringArray, ChannelEmailAddressMaxAllowedSenders+1)
+		}, errID: "model.channel_email_address.is_valid.allowed_senders.app_error"},
+		"missing create at":    {update: func(a *ChannelEmailAddress) { a.CreateAt = 0 }, errID: "model.channel_email_address.is_valid.create_at.app_error"},
+		"without webhook":      {update: func(a *ChannelEmailAddress) { a.WebhookId = "" }},
+		"with allowed senders": {update: func(a *ChannelEmailAddress) { a.AllowedSenders = StringArray{"john@example.com", "@example.org"} }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			address := newAddress()
+			tc.update(address)
+
+			appErr := address.IsValid()
+			if tc.errID == "" {
+				require.Nil(t, appErr)
+				return
+			}
+			require.NotNil(t, appErr)
+			assert.Equal(t, tc.errID, appErr.Id)
+		})
+	}
+}
+
+func TestChannelEmailAddressPatch(t *testing.T) {
+	address := &ChannelEmailAddress{AllowedSenders: StringArray{"john@example.com"}}
+
+	address.Patch(&ChannelEmailAddressPatch{})
+	assert.Equal(t, StringArray{"john@example.com"}, address.AllowedSenders)
+
+	address.Patch(&ChannelEmailAddressPatch{AllowedSenders: &[]string{" Jane@Example.com ", "", "@example.org", "jane@example.com"}})
+	assert.Equal(t, StringArray{"jane@example.com", "@example.org"}, address.AllowedSenders)
+}
+
+func TestChannelEmailAddressIsSenderAllowed(t *testing.T) {
+	address := &ChannelEmailAddress{}
+	assert.True(t, address.IsSenderAllowed("anyone@example.net"))
+
+	address.AllowedSenders = StringArray{"john@example.com", "@example.org"}
+	for sender, allowed := range map[string]bool{
+		"john@example.com":      true,
+		"John@Example.com":      true,
+		"jane@example.com":      false,
+		"jane@example.org":      true,
+		"jane@sub.example.org":  false,
+		"jane@example.org.evil": false,
+		"not an email":          false,
+	} {
+		assert.Equal(t, allowed, address.IsSenderAllowed(sender), sender)
+	}
+}
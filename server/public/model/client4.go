@@ -27,6 +27,7 @@ const (
 	HeaderRealIP                    = "X-Real-IP"
 	HeaderForwardedProto            = "X-Forwarded-Proto"
 	HeaderToken                     = "token"
+	HeaderMfaTrustedDevice          = "X-MFA-Trusted-Device"
 	HeaderCsrfToken                 = "X-CSRF-Token"
 	HeaderBearer                    = "BEARER"
 	HeaderAuth                      = "Authorization"
@@ -263,6 +264,22 @@ func (c *Client4) channelRoute(channelId string) string {
 	return fmt.Sprintf(c.channelsRoute()+"/%v", channelId)
 }
 
+func (c *Client4) channelEmailAddressRoute(channelId string) string {
+	return c.channelRoute(channelId) + "/email_address"
+}
+
+func (c *Client4) accessControlPolicyRoute(channelId string) string {
+	return c.channelRoute(channelId) + "/access_control_policy"
+}
+
+func (c *Client4) customProfileFieldsRoute() string {
+	return "/custom_profile_attributes/fields"
+}
+
+func (c *Client4) customProfileFieldRoute(fieldId string) string {
+	return fmt.Sprintf(c.customProfileFieldsRoute()+"/%v", fieldId)
+}
+
 func (c *Client4) channelByNameRoute(channelName, teamId string) string {
 	return fmt.Sprintf(c.teamRoute(teamId)+"/channels/name/%v", channelName)
 }
@@ -404,6 +421,14 @@ func (c *Client4) userStatusRoute(userId string) string {
 	return fmt.Sprintf(c.userRoute(userId) + "/status")
 }
 
+func (c *Client4) notificationRulesRoute(userId string) string {
+	return c.userRoute(userId) + "/notification_rules"
+}
+
+func (c *Client4) notificationRuleRoute(userId, ruleId string) string {
+	return fmt.Sprintf(c.notificationRulesRoute(userId)+"/%v", ruleId)
+}
+
 func (c *Client4) userStatusesRoute() string {
 	return fmt.Sprintf(c.usersRoute() + "/status")
 }
@@ -840,6 +865,19 @@ func (c *Client4) LoginWithMFA(ctx context.Context, loginId, password, mfaToken
 	return c.login(ctx, m)
 }
 
+// LoginWithMFATrustingDevice authenticates a user with MFA, and trusts the device for the
+// configured number of days. The token of the trusted device is returned in the
+// X-MFA-Trusted-Device header of the response, and can be passed as the MFA token of the
+// next logins.
+func (c *Client4) LoginWithMFATrustingDevice(ctx context.Context, loginId, password, mfaToken string) (*User, *Response, error) {
+	m := make(map[string]string)
+	m["login_id"] = loginId
+	m["password"] = password
+	m["token"] = mfaToken
+	m["trust_device"] = c.boolString(true)
+	return c.login(ctx, m)
+}
+
 func (c *Client4) login(ctx context.Context, m map[string]string) (*User, *Response, error) {
 	r, err := c.DoAPIPost(ctx, "/users/login", MapToJSON(m))
 	if err != nil {
@@ -856,6 +894,47 @@ func (c *Client4) login(ctx context.Context, m map[string]string) (*User, *Respo
 	return &user, BuildResponse(r), nil
 }
 
+// GenerateWebAuthnLoginOptions starts a WebAuthn authentication ceremony. The login id
+// is optional, and restricts the ceremony to the authenticators of that user.
+func (c *Client4) GenerateWebAuthnLoginOptions(ctx context.Context, loginId string) (*WebAuthnRequestOptions, *Response, error) {
+	buf, err := json.Marshal(WebAuthnLoginOptionsRequest{LoginId: loginId})
+	if err != nil {
+		return nil, nil, NewAppError("GenerateWebAuthnLoginOptions", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, "/users/login/webauthn/options", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var options WebAuthnRequestOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		return nil, nil, NewAppError("GenerateWebAuthnLoginOptions", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &options, BuildResponse(r), nil
+}
+
+// LoginWithWebAuthn authenticates a user without password, with an assertion of a
+// WebAuthn authenticator which verified the user.
+func (c *Client4) LoginWithWebAuthn(ctx context.Context, credential *WebAuthnAssertionCredential, deviceId string) (*User, *Response, error) {
+	buf, err := json.Marshal(WebAuthnLoginRequest{DeviceId: deviceId, Credential: credential})
+	if err != nil {
+		return nil, nil, NewAppError("LoginWithWebAuthn", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, "/users/login/webauthn", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	c.AuthToken = r.Header.Get(HeaderToken)
+	c.AuthType = HeaderBearer
+
+	var user User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		return nil, nil, NewAppError("LoginWithWebAuthn", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &user, BuildResponse(r), nil
+}
+
 func (c *Client4) LoginWithDesktopToken(ctx context.Context, token, deviceId string) (*User, *Response, error) {
 	m := make(map[string]string)
 	m["token"] = token
@@ -1526,6 +1605,94 @@ func (c *Client4) GenerateMfaSecret(ctx context.Context, userId string) (*MfaSec
 	return &secret, BuildResponse(r), nil
 }
 
+// GetMfaRecoveryCodesStatus returns the number of unused MFA recovery codes of a user.
+func (c *Client4) GetMfaRecoveryCodesStatus(ctx context.Context, userId string) (*MfaRecoveryCodes, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userId)+"/mfa/recovery_codes", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var status MfaRecoveryCodes
+	if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+		return nil, nil, NewAppError("GetMfaRecoveryCodesStatus", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &status, BuildResponse(r), nil
+}
+
+// RegenerateMfaRecoveryCodes generates the MFA recovery codes of a user, e.g. once MFA is
+// activated, replacing the previous ones, given an MFA token of the user, and returns the
+// new codes.
+func (c *Client4) RegenerateMfaRecoveryCodes(ctx context.Context, userId, code string) (*MfaRecoveryCodes, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.userRoute(userId)+"/mfa/recovery_codes", MapToJSON(map[string]string{"code": code}))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var codes MfaRecoveryCodes
+	if err := json.NewDecoder(r.Body).Decode(&codes); err != nil {
+		return nil, nil, NewAppError("RegenerateMfaRecoveryCodes", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &codes, BuildResponse(r), nil
+}
+
+// GenerateWebAuthnRegistrationOptions starts the registration of a WebAuthn authenticator
+// for the user, and returns the options to pass to the authenticator.
+func (c *Client4) GenerateWebAuthnRegistrationOptions(ctx context.Context, userId string) (*WebAuthnCreationOptions, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.userRoute(userId)+"/webauthn/registration/options", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var options WebAuthnCreationOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		return nil, nil, NewAppError("GenerateWebAuthnRegistrationOptions", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &options, BuildResponse(r), nil
+}
+
+// RegisterWebAuthnCredential completes the registration of a WebAuthn authenticator with
+// the credential it created.
+func (c *Client4) RegisterWebAuthnCredential(ctx context.Context, userId string, registration *WebAuthnRegistrationRequest) (*WebAuthnRegistrationResult, *Response, error) {
+	buf, err := json.Marshal(registration)
+	if err != nil {
+		return nil, nil, NewAppError("RegisterWebAuthnCredential", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.userRoute(userId)+"/webauthn/registration", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var result WebAuthnRegistrationResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, nil, NewAppError("RegisterWebAuthnCredential", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &result, BuildResponse(r), nil
+}
+
+// GetWebAuthnCredentials returns the WebAuthn authenticators registered by a user.
+func (c *Client4) GetWebAuthnCredentials(ctx context.Context, userId string) ([]*WebAuthnCredential, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userId)+"/webauthn/credentials", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var credentials []*WebAuthnCredential
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+		return nil, nil, NewAppError("GetWebAuthnCredentials", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return credentials, BuildResponse(r), nil
+}
+
+// DeleteWebAuthnCredential revokes a WebAuthn authenticator of a user.
+func (c *Client4) DeleteWebAuthnCredential(ctx context.Context, userId, credentialId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.userRoute(userId)+"/webauthn/credentials/"+credentialId)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
 // UpdateUserPassword updates a user's password. Must be logged in as the user or be a system administrator.
 func (c *Client4) UpdateUserPassword(ctx context.Context, userId, currentPassword, newPassword string) (*Response, error) {
 	requestBody := map[string]string{"current_password": currentPassword, "new_password": newPassword}
@@ -1696,6 +1863,21 @@ func (c *Client4) GetSessions(ctx context.Context, userId, etag string) ([]*Sess
 	return list, BuildResponse(r), nil
 }
 
+// GetActiveSessions returns the sessions of a user described by the device they were
+// created on, where they came from and when they were last active.
+func (c *Client4) GetActiveSessions(ctx context.Context, userId string) ([]*ActiveSession, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userId)+"/sessions/active", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var list []*ActiveSession
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		return nil, nil, NewAppError("GetActiveSessions", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return list, BuildResponse(r), nil
+}
+
 // RevokeSession revokes a user session based on the provided user id and session id strings.
 func (c *Client4) RevokeSession(ctx context.Context, userId, sessionId string) (*Response, error) {
 	requestBody := map[string]string{"session_id": sessionId}
@@ -1888,6 +2070,27 @@ func (c *Client4) CreateUserAccessToken(ctx context.Context, userId, description
 	return &uat, BuildResponse(r), nil
 }
 
+// CreateScopedUserAccessToken will generate a user access token restricted by the scope
+// and the expiry time of the given token. The token can only be granted the permissions,
+// teams, channels and IP addresses of its scope, and stops working once it has expired.
+// A non-blank description is required.
+func (c *Client4) CreateScopedUserAccessToken(ctx context.Context, userId string, token *UserAccessToken) (*UserAccessToken, *Response, error) {
+	buf, err := json.Marshal(token)
+	if err != nil {
+		return nil, nil, NewAppError("CreateScopedUserAccessToken", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.userRoute(userId)+"/tokens", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var uat UserAccessToken
+	if err := json.NewDecoder(r.Body).Decode(&uat); err != nil {
+		return nil, nil, NewAppError("CreateScopedUserAccessToken", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &uat, BuildResponse(r), nil
+}
+
 // GetUserAccessTokens will get a page of access tokens' id, description, is_active
 // and the user_id in the system. The actual token will not be returned. Must have
 // the 'manage_system' permission.
@@ -4879,6 +5082,52 @@ func (c *Client4) MigrateConfig(ctx context.Context, from, to string) (*Response
 	return BuildResponse(r), nil
 }
 
+// GetConfigHistory returns a page of the past configurations, newest first.
+func (c *Client4) GetConfigHistory(ctx context.Context, page, perPage int) ([]*ConfigVersion, *Response, error) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	r, err := c.DoAPIGet(ctx, c.configRoute()+"/history"+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var versions []*ConfigVersion
+	if err := json.NewDecoder(r.Body).Decode(&versions); err != nil {
+		return nil, nil, NewAppError("GetConfigHistory", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return versions, BuildResponse(r), nil
+}
+
+// GetConfigVersionDiff returns the settings that differ between two configuration
+// versions. An empty toVersionId compares against the active configuration.
+func (c *Client4) GetConfigVersionDiff(ctx context.Context, fromVersionId, toVersionId string) ([]*ConfigVersionDiff, *Response, error) {
+	query := ""
+	if toVersionId != "" {
+		query = "?to=" + url.QueryEscape(toVersionId)
+	}
+	r, err := c.DoAPIGet(ctx, c.configRoute()+"/history/"+fromVersionId+"/diff"+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var diffs []*ConfigVersionDiff
+	if err := json.NewDecoder(r.Body).Decode(&diffs); err != nil {
+		return nil, nil, NewAppError("GetConfigVersionDiff", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return diffs, BuildResponse(r), nil
+}
+
+// RollbackConfig makes a past configuration version the active one again.
+func (c *Client4) RollbackConfig(ctx context.Context, versionId string) (*Response, error) {
+	r, err := c.DoAPIPost(ctx, c.configRoute()+"/history/"+versionId+"/rollback", "")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
 // UploadLicenseFile will add a license file to the system.
 func (c *Client4) UploadLicenseFile(ctx context.Context, data []byte) (*Response, error) {
 	body := &bytes.Buffer{}
@@ -6131,6 +6380,103 @@ func (c *Client4) GetOAuthAccessToken(ctx context.Context, data url.Values) (*Ac
 	return ar, BuildResponse(rp), nil
 }
 
+// AuthorizeOAuthDevice starts the device authorization grant for the OAuth 2.0 client application,
+// returning the user code to show to the user and the device code to poll the access token with.
+func (c *Client4) AuthorizeOAuthDevice(ctx context.Context, clientId, scope string) (*OAuthDeviceAuthorizationResponse, *Response, error) {
+	data := url.Values{"client_id": {clientId}, "scope": {scope}}
+	r, err := c.doOAuthFormRequest(ctx, "/oauth/device_authorization", data)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var dar OAuthDeviceAuthorizationResponse
+	if err := json.NewDecoder(r.Body).Decode(&dar); err != nil {
+		return nil, BuildResponse(r), NewAppError("AuthorizeOAuthDevice", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &dar, BuildResponse(r), nil
+}
+
+// GetOAuthDeviceAccessToken polls the access token of a device authorization. Until the user
+// allows or denies the device, it returns an *OAuthErrorResponse error such as authorization_pending.
+func (c *Client4) GetOAuthDeviceAccessToken(ctx context.Context, clientId, deviceCode string) (*AccessResponse, *Response, error) {
+	data := url.Values{"grant_type": {DeviceCodeGrantType}, "client_id": {clientId}, "device_code": {deviceCode}}
+	r, err := c.doOAuthFormRequest(ctx, "/oauth/access_token", data)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var ar AccessResponse
+	if err := json.NewDecoder(r.Body).Decode(&ar); err != nil {
+		return nil, BuildResponse(r), NewAppError("GetOAuthDeviceAccessToken", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &ar, BuildResponse(r), nil
+}
+
+// VerifyOAuthDevice allows or denies the device authorization identified by the user code.
+func (c *Client4) VerifyOAuthDevice(ctx context.Context, userCode string, allow bool) (*Response, error) {
+	buf, err := json.Marshal(map[string]any{"user_code": userCode, "allow": allow})
+	if err != nil {
+		return nil, NewAppError("VerifyOAuthDevice", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIRequestBytes(ctx, http.MethodPost, c.URL+"/oauth/device", buf, "")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// GetOAuthAppForDeviceCode gets a sanitized version of the OAuth 2.0 client application requesting access with the user code.
+func (c *Client4) GetOAuthAppForDeviceCode(ctx context.Context, userCode string) (*OAuthApp, *Response, error) {
+	r, err := c.DoAPIGet(ctx, "/oauth/device/app?user_code="+url.QueryEscape(userCode), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var oapp OAuthApp
+	if err := json.NewDecoder(r.Body).Decode(&oapp); err != nil {
+		return nil, nil, NewAppError("GetOAuthAppForDeviceCode", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &oapp, BuildResponse(r), nil
+}
+
+// doOAuthFormRequest posts the form to an OAuth 2.0 endpoint, returning the errors defined
+// by RFC 6749 as an *OAuthErrorResponse and the other ones as an *AppError.
+func (c *Client4) doOAuthFormRequest(ctx context.Context, path string, data url.Values) (*http.Response, error) {
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+path, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	for k, v := range c.HTTPHeader {
+		rq.Header.Set(k, v)
+	}
+
+	rp, err := c.HTTPClient.Do(rq)
+	if err != nil {
+		return rp, err
+	}
+
+	if rp.StatusCode >= 300 {
+		defer closeBody(rp)
+		body, err := io.ReadAll(rp.Body)
+		if err != nil {
+			return rp, NewAppError(path, "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		var oauthErr OAuthErrorResponse
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.ErrorCode != "" {
+			return rp, &oauthErr
+		}
+		return rp, AppErrorFromJSON(bytes.NewReader(body))
+	}
+
+	return rp, nil
+}
+
 // OutgoingOAuthConnection section
 
 // GetOutgoingOAuthConnections retrieves the outgoing OAuth connections.
@@ -9026,6 +9372,19 @@ func (c *Client4) GetStorageUsage(ctx context.Context) (*StorageUsage, *Response
 	return usage, BuildResponse(r), err
 }
 
+// GetStorageUsageByTier returns the number and total size of the files stored in each storage tier.
+func (c *Client4) GetStorageUsageByTier(ctx context.Context) ([]*FileTierUsage, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.usageRoute()+"/storage/tiers", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var usage []*FileTierUsage
+	err = json.NewDecoder(r.Body).Decode(&usage)
+	return usage, BuildResponse(r), err
+}
+
 // GetTeamsUsage returns total usage of teams for the instance
 func (c *Client4) GetTeamsUsage(ctx context.Context) (*TeamsUsage, *Response, error) {
 	r, err := c.DoAPIGet(ctx, c.usageRoute()+"/teams", "")
@@ -9189,3 +9548,347 @@ func (c *Client4) SubmitClientMetrics(ctx context.Context, report *PerformanceRe
 
 	return BuildResponse(res), nil
 }
+
+// GetNotificationRules returns the notification rules of a user.
+func (c *Client4) GetNotificationRules(ctx context.Context, userId string) ([]*NotificationRule, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.notificationRulesRoute(userId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var rules []*NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		return nil, nil, NewAppError("GetNotificationRules", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return rules, BuildResponse(r), nil
+}
+
+// CreateNotificationRule creates a notification rule for a user.
+func (c *Client4) CreateNotificationRule(ctx context.Context, userId string, rule *NotificationRule) (*NotificationRule, *Response, error) {
+	buf, err := json.Marshal(rule)
+	if err != nil {
+		return nil, nil, NewAppError("CreateNotificationRule", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.notificationRulesRoute(userId), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var created *NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		return nil, nil, NewAppError("CreateNotificationRule", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return created, BuildResponse(r), nil
+}
+
+// UpdateNotificationRule replaces a notification rule of a user.
+func (c *Client4) UpdateNotificationRule(ctx context.Context, userId string, rule *NotificationRule) (*NotificationRule, *Response, error) {
+	buf, err := json.Marshal(rule)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateNotificationRule", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPutBytes(ctx, c.notificationRuleRoute(userId, rule.Id), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var updated *NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+		return nil, nil, NewAppError("UpdateNotificationRule", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return updated, BuildResponse(r), nil
+}
+
+// DeleteNotificationRule deletes a notification rule of a user.
+func (c *Client4) DeleteNotificationRule(ctx context.Context, userId, ruleId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.notificationRuleRoute(userId, ruleId))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// GetChannelEmailAddress returns the inbound email address of a channel.
+func (c *Client4) GetChannelEmailAddress(ctx context.Context, channelId string) (*ChannelEmailAddress, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.channelEmailAddressRoute(channelId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var address *ChannelEmailAddress
+	if err := json.NewDecoder(r.Body).Decode(&address); err != nil {
+		return nil, nil, NewAppError("GetChannelEmailAddress", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return address, BuildResponse(r), nil
+}
+
+// CreateChannelEmailAddress gives an inbound email address to a channel, accepting the emails
+// of the given senders.
+func (c *Client4) CreateChannelEmailAddress(ctx context.Context, channelId string, patch *ChannelEmailAddressPatch) (*ChannelEmailAddress, *Response, error) {
+	buf, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("CreateChannelEmailAddress", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.channelEmailAddressRoute(channelId), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var address *ChannelEmailAddress
+	if err := json.NewDecoder(r.Body).Decode(&address); err != nil {
+		return nil, nil, NewAppError("CreateChannelEmailAddress", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return address, BuildResponse(r), nil
+}
+
+// PatchChannelEmailAddress updates the senders allowed to email a channel.
+func (c *Client4) PatchChannelEmailAddress(ctx context.Context, channelId string, patch *ChannelEmailAddressPatch) (*ChannelEmailAddress, *Response, error) {
+	buf, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchChannelEmailAddress", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPutBytes(ctx, c.channelEmailAddressRoute(channelId)+"/patch", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var address *ChannelEmailAddress
+	if err := json.NewDecoder(r.Body).Decode(&address); err != nil {
+		return nil, nil, NewAppError("PatchChannelEmailAddress", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return address, BuildResponse(r), nil
+}
+
+// RegenerateChannelEmailAddress replaces the inbound email address of a channel with a new one.
+func (c *Client4) RegenerateChannelEmailAddress(ctx context.Context, channelId string) (*ChannelEmailAddress, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.channelEmailAddressRoute(channelId)+"/regenerate", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var address *ChannelEmailAddress
+	if err := json.NewDecoder(r.Body).Decode(&address); err != nil {
+		return nil, nil, NewAppError("RegenerateChannelEmailAddress", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return address, BuildResponse(r), nil
+}
+
+// DeleteChannelEmailAddress removes the inbound email address of a channel.
+func (c *Client4) DeleteChannelEmailAddress(ctx context.Context, channelId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.channelEmailAddressRoute(channelId))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+func auditRecordSearchQuery(opts AuditRecordSearchOptions) url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		"user_id":     opts.UserId,
+		"event_name":  opts.EventName,
+		"ip_address":  opts.IpAddress,
+		"object_type": opts.ObjectType,
+		"status":      opts.Status,
+		"cursor":      opts.Cursor.Encode(),
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if opts.StartTime > 0 {
+		values.Set("since", strconv.FormatInt(opts.StartTime, 10))
+	}
+	if opts.EndTime > 0 {
+		values.Set("until", strconv.FormatInt(opts.EndTime, 10))
+	}
+	if opts.Limit > 0 {
+		values.Set("per_page", strconv.Itoa(opts.Limit))
+	}
+	return values
+}
+
+// SearchAuditRecords returns a page of the audit records persisted to the database matching
+// the options. The next page is requested with the NextCursor of the list as the cursor
+// of the options.
+func (c *Client4) SearchAuditRecords(ctx context.Context, opts AuditRecordSearchOptions) (*AuditRecordList, *Response, error) {
+	r, err := c.DoAPIGet(ctx, "/audits/search?"+auditRecordSearchQuery(opts).Encode(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var list *AuditRecordList
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		return nil, nil, NewAppError("SearchAuditRecords", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return list, BuildResponse(r), nil
+}
+
+// ExportAuditRecords writes all the audit records persisted to the database matching the
+// options to wr, in the format AuditRecordExportFormatCSV or AuditRecordExportFormatJSONL.
+func (c *Client4) ExportAuditRecords(ctx context.Context, opts AuditRecordSearchOptions, format string, wr io.Writer) (int64, *Response, error) {
+	query := auditRecordSearchQuery(opts)
+	query.Del("cursor")
+	query.Del("per_page")
+	query.Set("format", format)
+	r, err := c.DoAPIGet(ctx, "/audits/export?"+query.Encode(), "")
+	if err != nil {
+		return 0, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	n, err := io.Copy(wr, r.Body)
+	if err != nil {
+		return n, BuildResponse(r), NewAppError("ExportAuditRecords", "model.client.copy.app_error", nil, "", r.StatusCode).Wrap(err)
+	}
+	return n, BuildResponse(r), nil
+}
+
+func (c *Client4) GetAccessControlPolicy(ctx context.Context, channelId string) (*AccessControlPolicy, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.accessControlPolicyRoute(channelId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var policy *AccessControlPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return nil, nil, NewAppError("GetAccessControlPolicy", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return policy, BuildResponse(r), nil
+}
+
+// SaveAccessControlPolicy restricts the members of a channel to the users matching the
+// expression, replacing its previous policy.
+func (c *Client4) SaveAccessControlPolicy(ctx context.Context, channelId, expression string) (*AccessControlPolicy, *Response, error) {
+	buf, err := json.Marshal(&AccessControlPolicy{Expression: expression})
+	if err != nil {
+		return nil, nil, NewAppError("SaveAccessControlPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPutBytes(ctx, c.accessControlPolicyRoute(channelId), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var policy *AccessControlPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return nil, nil, NewAppError("SaveAccessControlPolicy", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return policy, BuildResponse(r), nil
+}
+
+func (c *Client4) DeleteAccessControlPolicy(ctx context.Context, channelId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.accessControlPolicyRoute(channelId))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// SimulateAccessControlPolicy previews which members of a channel would be removed if
+// the expression was its policy.
+func (c *Client4) SimulateAccessControlPolicy(ctx context.Context, channelId, expression string) (*AccessControlPolicySimulation, *Response, error) {
+	buf, err := json.Marshal(&AccessControlPolicy{Expression: expression})
+	if err != nil {
+		return nil, nil, NewAppError("SimulateAccessControlPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.accessControlPolicyRoute(channelId)+"/simulate", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var simulation *AccessControlPolicySimulation
+	if err := json.NewDecoder(r.Body).Decode(&simulation); err != nil {
+		return nil, nil, NewAppError("SimulateAccessControlPolicy", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return simulation, BuildResponse(r), nil
+}
+
+func (c *Client4) GetCustomProfileFields(ctx context.Context) ([]*CustomProfileField, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.customProfileFieldsRoute(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var fields []*CustomProfileField
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		return nil, nil, NewAppError("GetCustomProfileFields", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return fields, BuildResponse(r), nil
+}
+
+func (c *Client4) CreateCustomProfileField(ctx context.Context, field *CustomProfileField) (*CustomProfileField, *Response, error) {
+	buf, err := json.Marshal(field)
+	if err != nil {
+		return nil, nil, NewAppError("CreateCustomProfileField", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.customProfileFieldsRoute(), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var created *CustomProfileField
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		return nil, nil, NewAppError("CreateCustomProfileField", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return created, BuildResponse(r), nil
+}
+
+func (c *Client4) PatchCustomProfileField(ctx context.Context, fieldId string, patch *CustomProfileFieldPatch) (*CustomProfileField, *Response, error) {
+	buf, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchCustomProfileField", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPatchBytes(ctx, c.customProfileFieldRoute(fieldId), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var patched *CustomProfileField
+	if err := json.NewDecoder(r.Body).Decode(&patched); err != nil {
+		return nil, nil, NewAppError("PatchCustomProfileField", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return patched, BuildResponse(r), nil
+}
+
+func (c *Client4) DeleteCustomProfileField(ctx context.Context, fieldId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.customProfileFieldRoute(fieldId))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+func (c *Client4) GetCustomProfileAttributes(ctx context.Context, userId string) ([]*CustomProfileValue, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userId)+"/custom_profile_attributes", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var values []*CustomProfileValue
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		return nil, nil, NewAppError("GetCustomProfileAttributes", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return values, BuildResponse(r), nil
+}
+
+// PatchCustomProfileAttributes sets the values of a user by field id. Empty values unset
+// the fields.
+func (c *Client4) PatchCustomProfileAttributes(ctx context.Context, userId string, values map[string]string) ([]*CustomProfileValue, *Response, error) {
+	buf, err := json.Marshal(values)
+	if err != nil {
+		return nil, nil, NewAppError("PatchCustomProfileAttributes", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPutBytes(ctx, c.userRoute(userId)+"/custom_profile_attributes", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var saved []*CustomProfileValue
+	if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+		return nil, nil, NewAppError("PatchCustomProfileAttributes", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return saved, BuildResponse(r), nil
+}
@@ -1379,6 +1379,10 @@ func TestConfigSanitize(t *testing.T) {
 	*c.EmailSettings.SMTPPassword = "baz"
 	*c.GitLabSettings.Secret = "bingo"
 	*c.OpenIdSettings.Secret = "secret"
+	c.OpenIdConnectSettings.Providers = []*OpenIdProviderSettings{{
+		Id:           NewPointer("okta"),
+		ClientSecret: NewPointer("secret"),
+	}}
 	c.SqlSettings.DataSourceReplicas = []string{"stuff"}
 	c.SqlSettings.DataSourceSearchReplicas = []string{"stuff"}
 	c.SqlSettings.ReplicaLagSettings = []*ReplicaLagSettings{{
@@ -1395,6 +1399,7 @@ func TestConfigSanitize(t *testing.T) {
 	assert.Equal(t, FakeSetting, *c.EmailSettings.SMTPPassword)
 	assert.Equal(t, FakeSetting, *c.GitLabSettings.Secret)
 	assert.Equal(t, FakeSetting, *c.OpenIdSettings.Secret)
+	assert.Equal(t, FakeSetting, *c.OpenIdConnectSettings.Providers[0].ClientSecret)
 	assert.Equal(t, FakeSetting, *c.SqlSettings.DataSource)
 	assert.Equal(t, FakeSetting, *c.SqlSettings.AtRestEncryptKey)
 	assert.Equal(t, FakeSetting, *c.ElasticsearchSettings.Password)
@@ -1539,6 +1544,134 @@ func TestConfigImportSettingsIsValid(t *testing.T) {
 	require.Equal(t, "model.config.is_valid.import.retention_days_too_low.app_error", appErr.Id)
 }
 
+func TestConfigOpenIdConnectSettingsIsValid(t *testing.T) {
+	newProvider := func(id string) *OpenIdProviderSettings {
+		provider := &OpenIdProviderSettings{
+			Id:                NewPointer(id),
+			Enable:            NewPointer(true),
+			ClientId:          NewPointer("client"),
+			DiscoveryEndpoint: NewPointer("https://idp.example.com/.well-known/openid-configuration"),
+		}
+		provider.SetDefaults()
+		return provider
+	}
+
+	for name, tc := range map[string]struct {
+		Providers     []*OpenIdProviderSettings
+		Modify        func(*OpenIdProviderSettings)
+		ExpectedError string
+	}{
+		"no providers": {},
+		"valid": {
+			Providers: []*OpenIdProviderSettings{newProvider("okta"), newProvider("keycloak")},
+		},
+		"duplicate id": {
+			Providers:     []*OpenIdProviderSettings{newProvider("okta"), newProvider("okta")},
+			ExpectedError: "model.config.is_valid.openid_connect.duplicate_id.app_error",
+		},
+		"invalid id": {
+			Providers:     []*OpenIdProviderSettings{newProvider("my-idp")},
+			ExpectedError: "model.config.is_valid.openid_connect.id.app_error",
+		},
+		"missing client id": {
+			Providers:     []*OpenIdProviderSettings{newProvider("okta")},
+			Modify:        func(p *OpenIdProviderSettings) { *p.ClientId = "" },
+			ExpectedError: "model.config.is_valid.openid_connect.client_id.app_error",
+		},
+		"invalid discovery endpoint": {
+			Providers:     []*OpenIdProviderSettings{newProvider("okta")},
+			Modify:        func(p *OpenIdProviderSettings) { *p.DiscoveryEndpoint = "idp.example.com" },
+			ExpectedError: "model.config.is_valid.openid_connect.discovery_endpoint.app_error",
+		},
+		"missing email claim": {
+			Providers:     []*OpenIdProviderSettings{newProvider("okta")},
+			Modify:        func(p *OpenIdProviderSettings) { *p.EmailClaim = "" },
+			ExpectedError: "model.config.is_valid.openid_connect.claims.app_error",
+		},
+		"disabled provider is not validated further": {
+			Providers: []*OpenIdProviderSettings{newProvider("okta")},
+			Modify: func(p *OpenIdProviderSettings) {
+				*p.Enable = false
+				*p.ClientId = ""
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := Config{}
+			cfg.SetDefaults()
+			cfg.OpenIdConnectSettings.Providers = tc.Providers
+			if tc.Modify != nil {
+				tc.Modify(tc.Providers[0])
+			}
+
+			appErr := cfg.OpenIdConnectSettings.isValid()
+			if tc.ExpectedError == "" {
+				require.Nil(t, appErr)
+			} else {
+				require.NotNil(t, appErr)
+				require.Equal(t, tc.ExpectedError, appErr.Id)
+			}
+		})
+	}
+}
+
+func TestConfigGetSSOServiceOpenIdConnect(t *testing.T) {
+	cfg := Config{}
+	cfg.OpenIdConnectSettings.Providers = []*OpenIdProviderSettings{{
+		Id:           NewPointer("okta"),
+		Enable:       NewPointer(true),
+		ClientId:     NewPointer("client"),
+		ClientSecret: NewPointer("secret"),
+	}}
+	cfg.SetDefaults()
+
+	sso := cfg.GetSSOService("oidcokta")
+	require.NotNil(t, sso)
+	assert.True(t, *sso.Enable)
+	assert.Equal(t, "client", *sso.Id)
+	assert.Equal(t, "secret", *sso.Secret)
+	assert.Equal(t, OpenidSettingsDefaultScope, *sso.Scope)
+
+	assert.Nil(t, cfg.GetSSOService("oidcother"))
+	assert.True(t, IsOpenIdProviderService("oidcokta"))
+	assert.False(t, IsOpenIdProviderService(ServiceOpenid))
+	assert.False(t, IsOpenIdProviderService("oidc"))
+}
+
+func TestConfigEmailSettingsInboundEmailIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	*cfg.EmailSettings.EnableReplyByEmail = true
+	appErr := cfg.EmailSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.reply_by_email_address.app_error", appErr.Id)
+
+	*cfg.EmailSettings.ReplyByEmailAddress = "reply@example.com"
+	appErr = cfg.EmailSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.inbound_smtp_listen_address.app_error", appErr.Id)
+
+	*cfg.EmailSettings.InboundSMTPListenAddress = ":2525"
+	appErr = cfg.EmailSettings.isValid()
+	require.Nil(t, appErr)
+
+	*cfg.EmailSettings.EnablePostByEmail = true
+	appErr = cfg.EmailSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.post_by_email_address.app_error", appErr.Id)
+
+	*cfg.EmailSettings.PostByEmailAddress = "channel@example.com"
+	*cfg.EmailSettings.InboundEmailMaxMessageSize = 0
+	appErr = cfg.EmailSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.inbound_email_max_message_size.app_error", appErr.Id)
+
+	*cfg.EmailSettings.InboundEmailMaxMessageSize = 1024
+	appErr = cfg.EmailSettings.isValid()
+	require.Nil(t, appErr)
+}
+
 func TestConfigExportSettingsDefaults(t *testing.T) {
 	cfg := Config{}
 	cfg.SetDefaults()
@@ -0,0 +1,21 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// ConfigVersion describes a configuration persisted by a database backed config store.
+type ConfigVersion struct {
+	Id        string `json:"id"`
+	CreateAt  int64  `json:"create_at"`
+	CreatedBy string `json:"created_by"`
+	Active    bool   `json:"active"`
+	// ChangedPaths lists the settings changed compared to the previous version.
+	ChangedPaths []string `json:"changed_paths"`
+}
+
+// ConfigVersionDiff is a setting that differs between two configuration versions.
+type ConfigVersionDiff struct {
+	Path      string `json:"path"`
+	BaseVal   any    `json:"base_val"`
+	ActualVal any    `json:"actual_val"`
+}
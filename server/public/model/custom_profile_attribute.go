@@ -0,0 +1,306 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	CustomProfileFieldTypeText        = "text"
+	CustomProfileFieldTypeSelect      = "select"
+	CustomProfileFieldTypeMultiselect = "multiselect"
+	CustomProfileFieldTypeDate        = "date"
+	CustomProfileFieldTypeURL         = "url"
+	CustomProfileFieldTypeUser        = "user"
+
+	// CustomProfileFieldVisibilityAll shows the values of a field to every user who can
+	// see the user.
+	CustomProfileFieldVisibilityAll = "all"
+	// CustomProfileFieldVisibilitySelf shows the values of a field to the user only.
+	CustomProfileFieldVisibilitySelf = "self"
+	// CustomProfileFieldVisibilityAdmin hides the values of a field from everyone but
+	// the system admins, who see every value.
+	CustomProfileFieldVisibilityAdmin = "admin"
+
+	// CustomProfileFieldDateFormat is the format of the values of the date fields.
+	CustomProfileFieldDateFormat = "2006-01-02"
+
+	CustomProfileFieldsMax                = 50
+	CustomProfileFieldNameMaxRunes        = 64
+	CustomProfileFieldDisplayNameMaxRunes = 64
+	CustomProfileFieldOptionsMax          = 100
+	CustomProfileFieldOptionMaxRunes      = 64
+	CustomProfileFieldAttributeMaxRunes   = 128
+	CustomProfileValueMaxRunes            = 256
+)
+
+var customProfileFieldNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomProfileField is an admin-defined field of the user profiles.
+type CustomProfileField struct {
+	Id string `json:"id"`
+	// Name identifies the field in access control policies and bulk imports, e.g. department.
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	// Options are the allowed values of the select and multiselect fields.
+	Options    StringArray `json:"options"`
+	Visibility string      `json:"visibility"`
+	// AdminManaged prevents users from editing their own value. Fields synchronized
+	// from LDAP or SAML are always admin managed.
+	AdminManaged  bool   `json:"admin_managed"`
+	LdapAttribute string `json:"ldap_attribute"`
+	SamlAttribute string `json:"saml_attribute"`
+	SortOrder     int    `json:"sort_order"`
+	CreateAt      int64  `json:"create_at"`
+	UpdateAt      int64  `json:"update_at"`
+	DeleteAt      int64  `json:"delete_at"`
+}
+
+type CustomProfileFieldPatch struct {
+	DisplayName   *string   `json:"display_name"`
+	Options       *[]string `json:"options"`
+	Visibility    *string   `json:"visibility"`
+	AdminManaged  *bool     `json:"admin_managed"`
+	LdapAttribute *string   `json:"ldap_attribute"`
+	SamlAttribute *string   `json:"saml_attribute"`
+	SortOrder     *int      `json:"sort_order"`
+}
+
+// CustomProfileValue is the value of a custom profile field for a user. The value of
+// multiselect fields is a JSON array of options.
+type CustomProfileValue struct {
+	FieldId  string `json:"field_id"`
+	UserId   string `json:"user_id"`
+	Value    string `json:"value"`
+	UpdateAt int64  `json:"update_at"`
+}
+
+func IsValidCustomProfileFieldName(name string) bool {
+	return utf8.RuneCountInString(name) <= CustomProfileFieldNameMaxRunes && customProfileFieldNameRegex.MatchString(name)
+}
+
+func (f *CustomProfileField) Auditable() map[string]any {
+	return map[string]any{
+		"id":             f.Id,
+		"name":           f.Name,
+		"type":           f.Type,
+		"options":        f.Options,
+		"visibility":     f.Visibility,
+		"admin_managed":  f.AdminManaged,
+		"ldap_attribute": f.LdapAttribute,
+		"saml_attribute": f.SamlAttribute,
+		"delete_at":      f.DeleteAt,
+	}
+}
+
+func (f *CustomProfileField) PreSave() {
+	if f.Id == "" {
+		f.Id = NewId()
+	}
+	if f.Options == nil {
+		f.Options = StringArray{}
+	}
+	if f.Visibility == "" {
+		f.Visibility = CustomProfileFieldVisibilityAll
+	}
+
+	f.CreateAt = GetMillis()
+	f.UpdateAt = f.CreateAt
+}
+
+func (f *CustomProfileField) PreUpdate() {
+	if f.Options == nil {
+		f.Options = StringArray{}
+	}
+	f.UpdateAt = GetMillis()
+}
+
+func (f *CustomProfileField) Patch(patch *CustomProfileFieldPatch) {
+	if patch.DisplayName != nil {
+		f.DisplayName = *patch.DisplayName
+	}
+	if patch.Options != nil {
+		f.Options = *patch.Options
+	}
+	if patch.Visibility != nil {
+		f.Visibility = *patch.Visibility
+	}
+	if patch.AdminManaged != nil {
+		f.AdminManaged = *patch.AdminManaged
+	}
+	if patch.LdapAttribute != nil {
+		f.LdapAttribute = *patch.LdapAttribute
+	}
+	if patch.SamlAttribute != nil {
+		f.SamlAttribute = *patch.SamlAttribute
+	}
+	if patch.SortOrder != nil {
+		f.SortOrder = *patch.SortOrder
+	}
+}
+
+// IsSynced returns whether the values of the field are synchronized from LDAP or SAML.
+func (f *CustomProfileField) IsSynced() bool {
+	return f.LdapAttribute != "" || f.SamlAttribute != ""
+}
+
+func (f *CustomProfileField) IsValid() *AppError {
+	if !IsValidId(f.Id) {
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidCustomProfileFieldName(f.Name) {
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.name.app_error", map[string]any{"MaxLength": CustomProfileFieldNameMaxRunes}, "id="+f.Id, http.StatusBadRequest)
+	}
+
+	if f.DisplayName == "" || utf8.RuneCountInString(f.DisplayName) > CustomProfileFieldDisplayNameMaxRunes {
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.display_name.app_error", map[string]any{"MaxLength": CustomProfileFieldDisplayNameMaxRunes}, "id="+f.Id, http.StatusBadRequest)
+	}
+
+	switch f.Type {
+	case CustomProfileFieldTypeSelect, CustomProfileFieldTypeMultiselect:
+		if len(f.Options) == 0 || len(f.Options) > CustomProfileFieldOptionsMax {
+			return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.options.app_error", map[string]any{"Max": CustomProfileFieldOptionsMax}, "id="+f.Id, http.StatusBadRequest)
+		}
+		seen := make(map[string]bool, len(f.Options))
+		for _, option := range f.Options {
+			if option == "" || utf8.RuneCountInString(option) > CustomProfileFieldOptionMaxRunes || seen[option] {
+				return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.option.app_error", map[string]any{"MaxLength": CustomProfileFieldOptionMaxRunes}, "id="+f.Id, http.StatusBadRequest)
+			}
+			seen[option] = true
+		}
+	case CustomProfileFieldTypeText, CustomProfileFieldTypeDate, CustomProfileFieldTypeURL, CustomProfileFieldTypeUser:
+		if len(f.Options) != 0 {
+			return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.options.app_error", map[string]any{"Max": CustomProfileFieldOptionsMax}, "id="+f.Id, http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.type.app_error", nil, "id="+f.Id, http.StatusBadRequest)
+	}
+
+	switch f.Visibility {
+	case CustomProfileFieldVisibilityAll, CustomProfileFieldVisibilitySelf, CustomProfileFieldVisibilityAdmin:
+	default:
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.visibility.app_error", nil, "id="+f.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(f.LdapAttribute) > CustomProfileFieldAttributeMaxRunes || utf8.RuneCountInString(f.SamlAttribute) > CustomProfileFieldAttributeMaxRunes {
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.attribute.app_error", map[string]any{"MaxLength": CustomProfileFieldAttributeMaxRunes}, "id="+f.Id, http.StatusBadRequest)
+	}
+
+	if f.CreateAt == 0 || f.UpdateAt == 0 {
+		return NewAppError("CustomProfileField.IsValid", "model.custom_profile_field.is_valid.create_at.app_error", nil, "id="+f.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsValidValue checks that a value is valid for the type of the field. Empty values,
+// which unset the field, are always valid.
+func (f *CustomProfileField) IsValidValue(value string) *AppError {
+	if value == "" {
+		return nil
+	}
+
+	valid := false
+	switch f.Type {
+	case CustomProfileFieldTypeText:
+		valid = utf8.RuneCountInString(value) <= CustomProfileValueMaxRunes
+	case CustomProfileFieldTypeSelect:
+		valid = f.hasOption(value)
+	case CustomProfileFieldTypeMultiselect:
+		var options []string
+		if err := json.Unmarshal([]byte(value), &options); err == nil {
+			valid = true
+			seen := make(map[string]bool, len(options))
+			for _, option := range options {
+				valid = valid && f.hasOption(option) && !seen[option]
+				seen[option] = true
+			}
+		}
+	case CustomProfileFieldTypeDate:
+		_, err := time.Parse(CustomProfileFieldDateFormat, value)
+		valid = err == nil
+	case CustomProfileFieldTypeURL:
+		valid = len(value) <= CustomProfileValueMaxRunes && IsValidHTTPURL(value)
+	case CustomProfileFieldTypeUser:
+		valid = IsValidId(value)
+	}
+
+	if !valid {
+		return NewAppError("CustomProfileField.IsValidValue", "model.custom_profile_field.is_valid_value.app_error", map[string]any{"DisplayName": f.DisplayName}, "id="+f.Id, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// Values returns the individual values of a valid value of the field: the selected
+// options of a multiselect field, or the value itself otherwise.
+func (f *CustomProfileField) Values(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	if f.Type == CustomProfileFieldTypeMultiselect {
+		var options []string
+		if err := json.Unmarshal([]byte(value), &options); err != nil {
+			return nil
+		}
+		return options
+	}
+
+	return []string{value}
+}
+
+// CustomProfileSearchValues returns, by user id, the individual values of the fields
+// visible to everyone, which are the values users can be searched by.
+func CustomProfileSearchValues(fields []*CustomProfileField, values []*CustomProfileValue) map[string][]string {
+	publicFields := make(map[string]*CustomProfileField, len(fields))
+	for _, field := range fields {
+		if field.DeleteAt == 0 && field.Visibility == CustomProfileFieldVisibilityAll {
+			publicFields[field.Id] = field
+		}
+	}
+
+	searchValues := map[string][]string{}
+	for _, value := range values {
+		if field, ok := publicFields[value.FieldId]; ok {
+			searchValues[value.UserId] = append(searchValues[value.UserId], field.Values(value.Value)...)
+		}
+	}
+	return searchValues
+}
+
+func (f *CustomProfileField) hasOption(value string) bool {
+	for _, option := range f.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *CustomProfileValue) PreSave() {
+	v.UpdateAt = GetMillis()
+}
+
+func (v *CustomProfileValue) IsValid() *AppError {
+	if !IsValidId(v.FieldId) {
+		return NewAppError("CustomProfileValue.IsValid", "model.custom_profile_value.is_valid.field_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(v.UserId) {
+		return NewAppError("CustomProfileValue.IsValid", "model.custom_profile_value.is_valid.user_id.app_error", nil, "field_id="+v.FieldId, http.StatusBadRequest)
+	}
+
+	if v.UpdateAt == 0 {
+		return NewAppError("CustomProfileValue.IsValid", "model.custom_profile_value.is_valid.update_at.app_error", nil, "field_id="+v.FieldId, http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomProfileFieldIsValid(t *testing.T) {
+	newField := func() *CustomProfileField {
+		field := &CustomProfileField{Name: "department", DisplayName: "Department", Type: CustomProfileFieldTypeSelect, Options: StringArray{"Sales", "Engineering"}}
+		field.PreSave()
+		return field
+	}
+	require.Nil(t, newField().IsValid())
+	assert.Equal(t, CustomProfileFieldVisibilityAll, newField().Visibility)
+
+	for id, invalidate := range map[string]func(*CustomProfileField){
+		"model.custom_profile_field.is_valid.id.app_error":           func(f *CustomProfileField) { f.Id = "invalid" },
+		"model.custom_profile_field.is_valid.name.app_error":         func(f *CustomProfileField) { f.Name = "Department" },
+		"model.custom_profile_field.is_valid.display_name.app_error": func(f *CustomProfileField) { f.DisplayName = "" },
+		"model.custom_profile_field.is_valid.type.app_error":         func(f *CustomProfileField) { f.Type = "number" },
+		"model.custom_profile_field.is_valid.options.app_error":      func(f *CustomProfileField) { f.Options = StringArray{} },
+		"model.custom_profile_field.is_valid.option.app_error":       func(f *CustomProfileField) { f.Options = StringArray{"Sales", "Sales"} },
+		"model.custom_profile_field.is_valid.visibility.app_error":   func(f *CustomProfileField) { f.Visibility = "team" },
+		"model.custom_profile_field.is_valid.attribute.app_error":    func(f *CustomProfileField) { f.LdapAttribute = strings.Repeat("a", 129) },
+		"model.custom_profile_field.is_valid.create_at.app_error":    func(f *CustomProfileField) { f.CreateAt = 0 },
+	} {
+		field := newField()
+		invalidate(field)
+		appErr := field.IsValid()
+		require.NotNil(t, appErr, id)
+		assert.Equal(t, id, appErr.Id)
+	}
+
+	field := newField()
+	field.Type = CustomProfileFieldTypeText
+	require.NotNil(t, field.IsValid())
+	field.Options = nil
+	field.PreUpdate()
+	require.Nil(t, field.IsValid())
+}
+
+func TestCustomProfileFieldIsValidValue(t *testing.T) {
+	for _, tc := range []struct {
+		fieldType string
+		valid     []string
+		invalid   []string
+	}{
+		{CustomProfileFieldTypeText, []string{"", "Paris", strings.Repeat("a", CustomProfileValueMaxRunes)}, []string{strings.Repeat("a", CustomProfileValueMaxRunes+1)}},
+		{CustomProfileFieldTypeSelect, []string{"Sales"}, []string{"sales", `["Sales"]`}},
+		{CustomProfileFieldTypeMultiselect, []string{`[]`, `["Sales","Engineering"]`}, []string{"Sales", `["Sales","Sales"]`, `["Marketing"]`}},
+		{CustomProfileFieldTypeDate, []string{"2024-02-29"}, []string{"2023-02-29", "29/02/2024"}},
+		{CustomProfileFieldTypeURL, []string{"https://example.com/profile"}, []string{"example.com", "javascript:alert(1)"}},
+		{CustomProfileFieldTypeUser, []string{NewId()}, []string{"someone"}},
+	} {
+		field := &CustomProfileField{Type: tc.fieldType, Options: StringArray{"Sales", "Engineering"}}
+		for _, value := range tc.valid {
+			assert.Nil(t, field.IsValidValue(value), "%s %q", tc.fieldType, value)
+		}
+		for _, value := range tc.invalid {
+			assert.NotNil(t, field.IsValidValue(value), "%s %q", tc.fieldType, value)
+		}
+	}
+}
+
+func TestCustomProfileFieldValues(t *testing.T) {
+	multiselect := &CustomProfileField{Type: CustomProfileFieldTypeMultiselect}
+	assert.Equal(t, []string{"Sales", "Engineering"}, multiselect.Values(`["Sales","Engineering"]`))
+	assert.Empty(t, multiselect.Values(""))
+
+	text := &CustomProfileField{Type: CustomProfileFieldTypeText}
+	assert.Equal(t, []string{`["Sales"]`}, text.Values(`["Sales"]`))
+}
+
+func TestCustomProfileSearchValues(t *testing.T) {
+	skills := &CustomProfileField{Id: NewId(), Type: CustomProfileFieldTypeMultiselect, Visibility: CustomProfileFieldVisibilityAll}
+	phone := &CustomProfileField{Id: NewId(), Type: CustomProfileFieldTypeText, Visibility: CustomProfileFieldVisibilitySelf}
+	userID := NewId()
+
+	searchValues := CustomProfileSearchValues([]*CustomProfileField{skills, phone}, []*CustomProfileValue{
+		{FieldId: skills.Id, UserId: userID, Value: `["Go","SQL"]`},
+		{FieldId: phone.Id, UserId: userID, Value: "555-0100"},
+		{FieldId: NewId(), UserId: userID, Value: "unknown"},
+	})
+	assert.Equal(t, map[string][]string{userID: {"Go", "SQL"}}, searchValues)
+}
+
+func TestAccessControlSubjectCustomProfileAttributes(t *testing.T) {
+	department := &CustomProfileField{Id: NewId(), Name: "department", Type: CustomProfileFieldTypeMultiselect, AdminManaged: true}
+	deleted := &CustomProfileField{Id: NewId(), Name: "location", Type: CustomProfileFieldTypeText, AdminManaged: true, DeleteAt: GetMillis()}
+	clearance := &CustomProfileField{Id: NewId(), Name: "clearance", Type: CustomProfileFieldTypeText}
+
+	subject := NewAccessControlSubject(&User{Username: "jdoe"}, nil)
+	subject.AddCustomProfileAttributes([]*CustomProfileField{department, deleted, clearance}, []*CustomProfileValue{
+		{FieldId: department.Id, Value: `["Sales","Engineering"]`},
+		{FieldId: deleted.Id, Value: "Paris"},
+		{FieldId: clearance.Id, Value: "secret"},
+	})
+
+	expression, appErr := ParseAccessControlExpression(`"engineering" in user.attributes.department`)
+	require.Nil(t, appErr)
+	assert.True(t, expression.Evaluate(subject))
+
+	expression, appErr = ParseAccessControlExpression(`user.attributes.location == "Paris"`)
+	require.Nil(t, appErr)
+	assert.False(t, expression.Evaluate(subject))
+
+	// Users set the values of the fields that aren't admin managed themselves.
+	expression, appErr = ParseAccessControlExpression(`user.attributes.clearance == "secret"`)
+	require.Nil(t, appErr)
+	assert.False(t, expression.Evaluate(subject))
+
+	_, appErr = ParseAccessControlExpression(`user.attributes.Location == "Paris"`)
+	require.NotNil(t, appErr)
+}
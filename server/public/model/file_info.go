@@ -13,6 +13,9 @@ import (
 const (
 	FileinfoSortByCreated = "CreateAt"
 	FileinfoSortBySize    = "Size"
+
+	FileTierHot  = "hot"
+	FileTierCold = "cold"
 )
 
 // GetFileInfosOptions contains options for getting FileInfos
@@ -57,6 +60,15 @@ type FileInfo struct {
 	Content         string  `json:"-"`
 	RemoteId        *string `json:"remote_id"`
 	Archived        bool    `json:"archived"`
+	// Tier is the storage tier holding the file contents. An empty value means the hot tier.
+	Tier string `json:"tier,omitempty"`
+}
+
+// FileTierUsage is the number and total size of the files stored in a storage tier.
+type FileTierUsage struct {
+	Tier      string `json:"tier"`
+	FileCount int64  `json:"file_count"`
+	Size      int64  `json:"size"`
 }
 
 func (fi *FileInfo) Auditable() map[string]interface{} {
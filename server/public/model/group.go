@@ -11,6 +11,8 @@ import (
 const (
 	GroupSourceLdap   GroupSource = "ldap"
 	GroupSourceCustom GroupSource = "custom"
+	GroupSourceOpenId GroupSource = "openid"
+	GroupSourceScim   GroupSource = "scim"
 
 	GroupNameMaxLength        = 64
 	GroupSourceMaxLength      = 64
@@ -24,10 +26,13 @@ type GroupSource string
 var allGroupSources = []GroupSource{
 	GroupSourceLdap,
 	GroupSourceCustom,
+	GroupSourceOpenId,
+	GroupSourceScim,
 }
 
 var groupSourcesRequiringRemoteID = []GroupSource{
 	GroupSourceLdap,
+	GroupSourceOpenId,
 }
 
 type Group struct {
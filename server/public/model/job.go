@@ -40,6 +40,11 @@ const (
 	JobTypeDeleteOrphanDraftsMigration   = "delete_orphan_drafts_migration"
 	JobTypeExportUsersToCSV              = "export_users_to_csv"
 	JobTypeDeleteDmsPreferencesMigration = "delete_dms_preferences_migration"
+	JobTypeFileEncryptionKeyRotation     = "file_encryption_key_rotation"
+	JobTypeColdStorage                   = "cold_storage"
+	JobTypeCleanupWebSocketEvents        = "cleanup_websocket_events"
+	JobTypeEmailDigest                   = "email_digest"
+	JobTypeAccessControlSync             = "access_control_sync"
 
 	JobStatusPending         = "pending"
 	JobStatusInProgress      = "in_progress"
@@ -72,6 +77,11 @@ var AllJobTypes = [...]string{
 	JobTypeLastAccessibleFile,
 	JobTypeCleanupDesktopTokens,
 	JobTypeRefreshPostStats,
+	JobTypeFileEncryptionKeyRotation,
+	JobTypeColdStorage,
+	JobTypeCleanupWebSocketEvents,
+	JobTypeEmailDigest,
+	JobTypeAccessControlSync,
 }
 
 type Job struct {
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// LoginDeviceMaxPerUser bounds the number of devices remembered for a user. The least
+	// recently seen ones are forgotten first.
+	LoginDeviceMaxPerUser = 50
+
+	LoginDeviceNameMaxRunes = 128
+)
+
+// LoginDevice is a device a user logged in with, along with the country the login came
+// from. A login from a device or a country which is not known yet for the user is
+// reported to them.
+type LoginDevice struct {
+	Id          string `json:"id"`
+	UserId      string `json:"user_id"`
+	Fingerprint string `json:"-"`
+	Name        string `json:"name"`
+	Country     string `json:"country"`
+	CreateAt    int64  `json:"create_at"`
+	LastSeenAt  int64  `json:"last_seen_at"`
+}
+
+func (d *LoginDevice) PreSave() {
+	if d.Id == "" {
+		d.Id = NewId()
+	}
+
+	d.CreateAt = GetMillis()
+	if d.LastSeenAt == 0 {
+		d.LastSeenAt = d.CreateAt
+	}
+
+	if utf8.RuneCountInString(d.Name) > LoginDeviceNameMaxRunes {
+		d.Name = string([]rune(d.Name)[:LoginDeviceNameMaxRunes])
+	}
+}
+
+func (d *LoginDevice) IsValid() *AppError {
+	if !IsValidId(d.Id) {
+		return NewAppError("LoginDevice.IsValid", "model.login_device.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(d.UserId) {
+		return NewAppError("LoginDevice.IsValid", "model.login_device.is_valid.user_id.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if len(d.Fingerprint) != sha256.Size*2 {
+		return NewAppError("LoginDevice.IsValid", "model.login_device.is_valid.fingerprint.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if len(d.Country) > 2 {
+		return NewAppError("LoginDevice.IsValid", "model.login_device.is_valid.country.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if d.CreateAt == 0 {
+		return NewAppError("LoginDevice.IsValid", "model.login_device.is_valid.create_at.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// NewLoginDeviceFingerprint returns the fingerprint of the device a session was created
+// on. It is made of the device id of mobile apps and of the platform, operating system
+// and browser the session was created with, without their versions so that upgrading a
+// browser does not make a new device.
+func NewLoginDeviceFingerprint(session *Session) string {
+	browser, _, _ := strings.Cut(session.Props[SessionPropBrowser], "/")
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		session.DeviceId,
+		session.Props[SessionPropPlatform],
+		session.Props[SessionPropOs],
+		browser,
+	}, "\n")))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginDeviceIsValid(t *testing.T) {
+	device := LoginDevice{}
+
+	appErr := device.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.login_device.is_valid.id.app_error", appErr.Id)
+
+	device.Id = NewId()
+	appErr = device.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.login_device.is_valid.user_id.app_error", appErr.Id)
+
+	device.UserId = NewId()
+	appErr = device.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.login_device.is_valid.fingerprint.app_error", appErr.Id)
+
+	device.Fingerprint = NewLoginDeviceFingerprint(&Session{})
+	device.Country = "FRA"
+	appErr = device.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.login_device.is_valid.country.app_error", appErr.Id)
+
+	device.Country = "FR"
+	appErr = device.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.login_device.is_valid.create_at.app_error", appErr.Id)
+
+	device.Name = strings.Repeat("é", LoginDeviceNameMaxRunes+1)
+	device.PreSave()
+	require.Nil(t, device.IsValid())
+	assert.Equal(t, device.CreateAt, device.LastSeenAt)
+	assert.Equal(t, strings.Repeat("é", LoginDeviceNameMaxRunes), device.Name)
+}
+
+func TestNewLoginDeviceFingerprint(t *testing.T) {
+	newSession := func(deviceID, browser string) *Session {
+		return &Session{DeviceId: deviceID, Props: StringMap{
+			SessionPropPlatform: "Windows",
+			SessionPropOs:       "Windows 10",
+			SessionPropBrowser:  browser,
+		}}
+	}
+
+	fingerprint := NewLoginDeviceFingerprint(newSession("", "Chrome/120.0"))
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, NewLoginDeviceFingerprint(newSession("", "Chrome/121.1")))
+	assert.NotEqual(t, fingerprint, NewLoginDeviceFingerprint(newSession("", "Firefox/120.0")))
+	assert.NotEqual(t, fingerprint, NewLoginDeviceFingerprint(newSession(NewId(), "Chrome/120.0")))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const (
+	MfaRecoveryCodeCount = 10
+	// mfaRecoveryCodeLength is the number of base32 characters of a recovery code,
+	// 50 bits of entropy.
+	mfaRecoveryCodeLength = 10
+)
+
+var (
+	mfaRecoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+	validMfaRecoveryCode    = regexp.MustCompile(`^[a-z2-7]{10}$`)
+)
+
+// MfaRecoveryCode is a single use code letting a user log in without their MFA device.
+// Only the hash of the code is stored.
+type MfaRecoveryCode struct {
+	Id       string `json:"id"`
+	UserId   string `json:"user_id"`
+	CodeHash string `json:"-"`
+	CreateAt int64  `json:"create_at"`
+	UsedAt   int64  `json:"used_at"`
+}
+
+// MfaRecoveryCodes describes the recovery codes of a user. The codes themselves are
+// only returned when they are generated.
+type MfaRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	Remaining     int      `json:"remaining"`
+}
+
+// NewMfaRecoveryCodes generates a set of recovery codes for the user. It returns the
+// codes to show to the user, and their hashed version to store.
+func NewMfaRecoveryCodes(userID string) ([]string, []*MfaRecoveryCode) {
+	codes := make([]string, 0, MfaRecoveryCodeCount)
+	hashed := make([]*MfaRecoveryCode, 0, MfaRecoveryCodeCount)
+	now := GetMillis()
+
+	for range MfaRecoveryCodeCount {
+		data := make([]byte, 7)
+		rand.Read(data)
+		code := mfaRecoveryCodeEncoding.EncodeToString(data)[:mfaRecoveryCodeLength]
+		codes = append(codes, code[:mfaRecoveryCodeLength/2]+"-"+code[mfaRecoveryCodeLength/2:])
+		hashed = append(hashed, &MfaRecoveryCode{
+			Id:       NewId(),
+			UserId:   userID,
+			CodeHash: HashMfaRecoveryCode(code),
+			CreateAt: now,
+		})
+	}
+
+	return codes, hashed
+}
+
+func normalizeMfaRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// IsMfaRecoveryCode returns whether an MFA token has the format of a recovery code.
+func IsMfaRecoveryCode(token string) bool {
+	return validMfaRecoveryCode.MatchString(normalizeMfaRecoveryCode(token))
+}
+
+// HashMfaRecoveryCode returns the hash stored for a recovery code. The codes are
+// random enough for a plain hash to resist brute forcing.
+func HashMfaRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeMfaRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMfaRecoveryCodes(t *testing.T) {
+	userID := NewId()
+	codes, hashed := NewMfaRecoveryCodes(userID)
+	require.Len(t, codes, MfaRecoveryCodeCount)
+	require.Len(t, hashed, MfaRecoveryCodeCount)
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		assert.True(t, IsMfaRecoveryCode(code))
+		assert.False(t, seen[code])
+		seen[code] = true
+
+		assert.True(t, IsValidId(hashed[i].Id))
+		assert.Equal(t, userID, hashed[i].UserId)
+		assert.Equal(t, HashMfaRecoveryCode(code), hashed[i].CodeHash)
+		assert.NotContains(t, hashed[i].CodeHash, strings.ReplaceAll(code, "-", ""))
+		assert.NotZero(t, hashed[i].CreateAt)
+		assert.Zero(t, hashed[i].UsedAt)
+	}
+}
+
+func TestMfaRecoveryCodeFormat(t *testing.T) {
+	assert.True(t, IsMfaRecoveryCode("abcde-fgh23"))
+	assert.True(t, IsMfaRecoveryCode(" ABCDE FGH23 "))
+	assert.True(t, IsMfaRecoveryCode("abcdefgh23"))
+	assert.False(t, IsMfaRecoveryCode("123456"))
+	assert.False(t, IsMfaRecoveryCode("abcde-fgh18"))
+	assert.False(t, IsMfaRecoveryCode(`{"id": "abcdefghij"}`))
+
+	assert.Equal(t, HashMfaRecoveryCode("abcde-fgh23"), HashMfaRecoveryCode(" ABCDE FGH23"))
+	assert.NotEqual(t, HashMfaRecoveryCode("abcde-fgh23"), HashMfaRecoveryCode("abcde-fgh24"))
+}
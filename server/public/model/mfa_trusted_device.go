@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	MfaTrustedDeviceCookie = "MMMFATRUST"
+	// SessionPropMfaTrustedDeviceId links the sessions created on a trusted device to
+	// it. Revoking one of them revokes the trust of the device.
+	SessionPropMfaTrustedDeviceId = "mfa_trusted_device_id"
+
+	MfaTrustedDeviceMaxDays = 365
+
+	mfaTrustedDeviceTokenPrefix = "mfatrust."
+)
+
+// MfaTrustedDevice is a device on which a user chose to skip MFA for a while after
+// logging in with it.
+type MfaTrustedDevice struct {
+	Id         string `json:"id"`
+	UserId     string `json:"user_id"`
+	CreateAt   int64  `json:"create_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+}
+
+func (d *MfaTrustedDevice) Auditable() map[string]any {
+	return map[string]any{
+		"id":           d.Id,
+		"user_id":      d.UserId,
+		"create_at":    d.CreateAt,
+		"expires_at":   d.ExpiresAt,
+		"last_used_at": d.LastUsedAt,
+	}
+}
+
+func (d *MfaTrustedDevice) PreSave() {
+	if d.Id == "" {
+		d.Id = NewId()
+	}
+
+	d.CreateAt = GetMillis()
+}
+
+func (d *MfaTrustedDevice) IsValid() *AppError {
+	if !IsValidId(d.Id) {
+		return NewAppError("MfaTrustedDevice.IsValid", "model.mfa_trusted_device.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(d.UserId) {
+		return NewAppError("MfaTrustedDevice.IsValid", "model.mfa_trusted_device.is_valid.user_id.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if d.CreateAt == 0 {
+		return NewAppError("MfaTrustedDevice.IsValid", "model.mfa_trusted_device.is_valid.create_at.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if d.ExpiresAt <= d.CreateAt {
+		return NewAppError("MfaTrustedDevice.IsValid", "model.mfa_trusted_device.is_valid.expires_at.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// NewMfaTrustedDeviceToken returns the token stored in the cookie of a trusted device,
+// given the signature of the device id.
+func NewMfaTrustedDeviceToken(deviceID, signature string) string {
+	return mfaTrustedDeviceTokenPrefix + deviceID + "." + signature
+}
+
+// ParseMfaTrustedDeviceToken returns the device id and the signature held by the token
+// of a trusted device.
+func ParseMfaTrustedDeviceToken(token string) (deviceID, signature string, ok bool) {
+	rest, found := strings.CutPrefix(token, mfaTrustedDeviceTokenPrefix)
+	if !found {
+		return "", "", false
+	}
+
+	deviceID, signature, found = strings.Cut(rest, ".")
+	if !found || !IsValidId(deviceID) || signature == "" {
+		return "", "", false
+	}
+
+	return deviceID, signature, true
+}
+
+// IsMfaTrustedDeviceToken returns whether an MFA token holds the token of a trusted
+// device rather than a one-time password.
+func IsMfaTrustedDeviceToken(token string) bool {
+	return strings.HasPrefix(token, mfaTrustedDeviceTokenPrefix)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMfaTrustedDeviceIsValid(t *testing.T) {
+	device := &MfaTrustedDevice{UserId: NewId()}
+	device.PreSave()
+	require.True(t, IsValidId(device.Id))
+
+	require.NotNil(t, device.IsValid())
+
+	device.ExpiresAt = device.CreateAt + 1000
+	require.Nil(t, device.IsValid())
+
+	device.UserId = "junk"
+	require.NotNil(t, device.IsValid())
+}
+
+func TestMfaTrustedDeviceToken(t *testing.T) {
+	deviceID := NewId()
+	token := NewMfaTrustedDeviceToken(deviceID, "c2lnbmF0dXJl")
+	assert.True(t, IsMfaTrustedDeviceToken(token))
+
+	parsedID, signature, ok := ParseMfaTrustedDeviceToken(token)
+	require.True(t, ok)
+	assert.Equal(t, deviceID, parsedID)
+	assert.Equal(t, "c2lnbmF0dXJl", signature)
+
+	for _, token := range []string{
+		"",
+		"123456",
+		"abcde-fghij",
+		"mfatrust.",
+		"mfatrust." + deviceID,
+		"mfatrust." + deviceID + ".",
+		"mfatrust.junk.c2lnbmF0dXJl",
+	} {
+		_, _, ok := ParseMfaTrustedDeviceToken(token)
+		assert.False(t, ok, token)
+	}
+	assert.False(t, IsMfaTrustedDeviceToken("123456"))
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// NotificationRuleActionNotify notifies the user of the matching posts, as if they were mentioned.
+	NotificationRuleActionNotify = "notify"
+	// NotificationRuleActionMute suppresses the push, email and desktop notifications of the matching posts.
+	NotificationRuleActionMute = "mute"
+
+	MaxNotificationRulesPerUser      = 50
+	NotificationRuleNameMaxRunes     = 64
+	NotificationRuleMaxMatchers      = 20
+	NotificationRuleMatcherMaxLength = 256
+	NotificationRuleMaxScopeIds      = 100
+
+	notificationRuleTimeLayout = "15:04"
+)
+
+// NotificationRule is a user defined rule applied when sending the notifications of a post.
+// A rule matches the posts of its scope containing one of its keywords or patterns, while its
+// schedule is active. A rule without keywords nor patterns matches all the posts of its scope.
+type NotificationRule struct {
+	Id     string `json:"id"`
+	UserId string `json:"user_id"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	// Keywords are phrases matched case-insensitively on word boundaries.
+	Keywords StringArray `json:"keywords"`
+	// Patterns are regular expressions, using the RE2 syntax.
+	Patterns StringArray `json:"patterns"`
+	// TeamIds and ChannelIds restrict the rule to the given teams and channels. The rule
+	// applies everywhere when both are empty.
+	TeamIds    StringArray               `json:"team_ids"`
+	ChannelIds StringArray               `json:"channel_ids"`
+	Schedule   *NotificationRuleSchedule `json:"schedule,omitempty"`
+	// BypassUrgent lets the urgent priority posts through a mute rule.
+	BypassUrgent bool  `json:"bypass_urgent"`
+	CreateAt     int64 `json:"create_at"`
+	UpdateAt     int64 `json:"update_at"`
+
+	// compiled caches the compiled Patterns, see CompilePatterns.
+	compiled *compiledPatterns
+}
+
+type compiledPatterns struct {
+	// source are the patterns the regexps were compiled from.
+	source  []string
+	regexps []*regexp.Regexp
+}
+
+// NotificationRuleSchedule restricts a rule to a time window, in the timezone of the user.
+// The window ends the next day when EndTime is before StartTime, e.g. for quiet hours.
+type NotificationRuleSchedule struct {
+	// Days are the days the window starts on, from Sunday (0) to Saturday (6). The window
+	// starts every day when empty.
+	Days      []int  `json:"days,omitempty"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+func (r *NotificationRule) Auditable() map[string]any {
+	return map[string]any{
+		"id":            r.Id,
+		"user_id":       r.UserId,
+		"name":          r.Name,
+		"action":        r.Action,
+		"team_ids":      r.TeamIds,
+		"channel_ids":   r.ChannelIds,
+		"bypass_urgent": r.BypassUrgent,
+		"create_at":     r.CreateAt,
+		"update_at":     r.UpdateAt,
+	}
+}
+
+func (r *NotificationRule) PreSave() {
+	if r.Id == "" {
+		r.Id = NewId()
+	}
+
+	r.CreateAt = GetMillis()
+	r.UpdateAt = r.CreateAt
+	r.normalize()
+}
+
+func (r *NotificationRule) PreUpdate() {
+	r.UpdateAt = GetMillis()
+	r.normalize()
+}
+
+func (r *NotificationRule) normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Keywords == nil {
+		r.Keywords = StringArray{}
+	}
+	if r.Patterns == nil {
+		r.Patterns = StringArray{}
+	}
+	if r.TeamIds == nil {
+		r.TeamIds = StringArray{}
+	}
+	if r.ChannelIds == nil {
+		r.ChannelIds = StringArray{}
+	}
+}
+
+func (r *NotificationRule) IsValid() *AppError {
+	if !IsValidId(r.Id) {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(r.UserId) {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.user_id.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.CreateAt == 0 {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.create_at.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.UpdateAt == 0 {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.update_at.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.Name == "" || utf8.RuneCountInString(r.Name) > NotificationRuleNameMaxRunes {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.name.app_error", map[string]any{"MaxLength": NotificationRuleNameMaxRunes}, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.Action != NotificationRuleActionNotify && r.Action != NotificationRuleActionMute {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.action.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.BypassUrgent && r.Action != NotificationRuleActionMute {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.bypass_urgent.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if len(r.Keywords)+len(r.Patterns) > NotificationRuleMaxMatchers {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.matchers.app_error", map[string]any{"Max": NotificationRuleMaxMatchers}, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	for _, keyword := range r.Keywords {
+		if strings.TrimSpace(keyword) == "" || len(keyword) > NotificationRuleMatcherMaxLength {
+			return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.keyword.app_error", map[string]any{"MaxLength": NotificationRuleMatcherMaxLength}, "id="+r.Id, http.StatusBadRequest)
+		}
+	}
+
+	regexps := make([]*regexp.Regexp, 0, len(r.Patterns))
+	for _, pattern := range r.Patterns {
+		if pattern == "" || len(pattern) > NotificationRuleMatcherMaxLength {
+			return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.pattern.app_error", map[string]any{"Pattern": pattern}, "id="+r.Id, http.StatusBadRequest)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.pattern.app_error", map[string]any{"Pattern": pattern}, "id="+r.Id, http.StatusBadRequest).Wrap(err)
+		}
+		regexps = append(regexps, re)
+	}
+	r.compiled = &compiledPatterns{source: slices.Clone(r.Patterns), regexps: regexps}
+
+	if len(r.TeamIds)+len(r.ChannelIds) > NotificationRuleMaxScopeIds {
+		return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.scope.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	for _, id := range append(slices.Clone(r.TeamIds), r.ChannelIds...) {
+		if !IsValidId(id) {
+			return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.scope.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+		}
+	}
+
+	if r.Schedule != nil {
+		if err := r.Schedule.isValid(); err != nil {
+			return NewAppError("NotificationRule.IsValid", "model.notification_rule.is_valid.schedule.app_error", nil, "id="+r.Id, http.StatusBadRequest).Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// AppliesToChannel returns whether the channel is in the scope of the rule.
+func (r *NotificationRule) AppliesToChannel(channel *Channel) bool {
+	if len(r.TeamIds) == 0 && len(r.ChannelIds) == 0 {
+		return true
+	}
+
+	return slices.Contains(r.ChannelIds, channel.Id) || (channel.TeamId != "" && slices.Contains(r.TeamIds, channel.TeamId))
+}
+
+// IsActiveAt returns whether the schedule of the rule, if any, is active at the given time.
+func (r *NotificationRule) IsActiveAt(t time.Time) bool {
+	if r.Schedule == nil {
+		return true
+	}
+
+	return r.Schedule.IsActiveAt(t)
+}
+
+// MatchesText returns whether the text contains one of the keywords or patterns of the rule.
+func (r *NotificationRule) MatchesText(text string) bool {
+	if len(r.Keywords) == 0 && len(r.Patterns) == 0 {
+		return true
+	}
+
+	lowerText := strings.ToLower(text)
+	for _, keyword := range r.Keywords {
+		if containsPhrase(lowerText, strings.ToLower(strings.TrimSpace(keyword))) {
+			return true
+		}
+	}
+
+	for _, re := range r.CompilePatterns() {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CompilePatterns returns the compiled patterns of the rule, skipping the invalid ones. They
+// are only compiled again once the patterns change, the store compiles them when loading the
+// rules so that they aren't compiled for every post.
+func (r *NotificationRule) CompilePatterns() []*regexp.Regexp {
+	if r.compiled != nil && slices.Equal(r.compiled.source, r.Patterns) {
+		return r.compiled.regexps
+	}
+
+	regexps := make([]*regexp.Regexp, 0, len(r.Patterns))
+	for _, pattern := range r.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		regexps = append(regexps, re)
+	}
+	r.compiled = &compiledPatterns{source: slices.Clone(r.Patterns), regexps: regexps}
+	return regexps
+}
+
+// containsPhrase returns whether the phrase appears in the text, not as part of a longer word.
+func containsPhrase(text, phrase string) bool {
+	if phrase == "" {
+		return false
+	}
+
+	isWordRune := func(s string, last bool) bool {
+		var r rune
+		if last {
+			r, _ = utf8.DecodeLastRuneInString(s)
+		} else {
+			r, _ = utf8.DecodeRuneInString(s)
+		}
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], phrase)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(phrase)
+
+		startsWord := start == 0 || !isWordRune(text[:start], true) || !isWordRune(phrase, false)
+		endsWord := end == len(text) || !isWordRune(text[end:], false) || !isWordRune(phrase, true)
+		if startsWord && endsWord {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+
+	return false
+}
+
+// IsActiveAt returns whether the window is active at the given time, using its location.
+func (s *NotificationRuleSchedule) IsActiveAt(t time.Time) bool {
+	start, startErr := time.Parse(notificationRuleTimeLayout, s.StartTime)
+	end, endErr := time.Parse(notificationRuleTimeLayout, s.EndTime)
+	if startErr != nil || endErr != nil {
+		return false
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+
+	startsOn := func(day time.Weekday) bool {
+		return len(s.Days) == 0 || slices.Contains(s.Days, int(day))
+	}
+
+	if startMinutes <= endMinutes {
+		return startsOn(t.Weekday()) && minutes >= startMinutes && minutes < endMinutes
+	}
+
+	// The window spans midnight, so it may have started the day before.
+	if minutes >= startMinutes {
+		return startsOn(t.Weekday())
+	}
+	return minutes < endMinutes && startsOn((t.Weekday()+6)%7)
+}
+
+func (s *NotificationRuleSchedule) isValid() error {
+	if _, err := time.Parse(notificationRuleTimeLayout, s.StartTime); err != nil {
+		return err
+	}
+	if _, err := time.Parse(notificationRuleTimeLayout, s.EndTime); err != nil {
+		return err
+	}
+	if s.StartTime == s.EndTime {
+		return errors.New("the schedule start and end times must differ")
+	}
+	for _, day := range s.Days {
+		if day < int(time.Sunday) || day > int(time.Saturday) {
+			return errors.New("invalid schedule day")
+		}
+	}
+	return nil
+}
+
+func (s NotificationRuleSchedule) Value() (driver.Value, error) {
+	j, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(j), nil
+}
+
+func (s *NotificationRuleSchedule) Scan(value any) error {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return errors.New("received value is neither a byte slice nor string")
+}
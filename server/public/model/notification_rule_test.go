@@ -0,0 +1,173 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidNotificationRule() *NotificationRule {
+	rule := &NotificationRule{
+		UserId:   NewId(),
+		Name:     "Deploys",
+		Action:   NotificationRuleActionNotify,
+		Keywords: StringArray{"deploy"},
+		Patterns: StringArray{`INC-\d+`},
+	}
+	rule.PreSave()
+	return rule
+}
+
+func TestNotificationRuleIsValid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		update func(rule *NotificationRule)
+		valid  bool
+	}{
+		"valid":                {update: func(rule *NotificationRule) {}, valid: true},
+		"invalid id":           {update: func(rule *NotificationRule) { rule.Id = "" }},
+		"invalid user id":      {update: func(rule *NotificationRule) { rule.UserId = "user" }},
+		"empty name":           {update: func(rule *NotificationRule) { rule.Name = "" }},
+		"long name":            {update: func(rule *NotificationRule) { rule.Name = strings.Repeat("a", NotificationRuleNameMaxRunes+1) }},
+		"invalid action":       {update: func(rule *NotificationRule) { rule.Action = "ignore" }},
+		"bypass urgent notify": {update: func(rule *NotificationRule) { rule.BypassUrgent = true }},
+		"empty keyword":        {update: func(rule *NotificationRule) { rule.Keywords = StringArray{" "} }},
+		"invalid pattern":      {update: func(rule *NotificationRule) { rule.Patterns = StringArray{"a("} }},
+		"invalid channel id":   {update: func(rule *NotificationRule) { rule.ChannelIds = StringArray{"channel"} }},
+		"no matchers":          {update: func(rule *NotificationRule) { rule.Keywords, rule.Patterns = nil, nil }, valid: true},
+		"bypass urgent mute":   {update: func(rule *NotificationRule) { rule.Action, rule.BypassUrgent = NotificationRuleActionMute, true }, valid: true},
+		"valid schedule": {update: func(rule *NotificationRule) {
+			rule.Schedule = &NotificationRuleSchedule{StartTime: "22:00", EndTime: "07:00"}
+		}, valid: true},
+		"invalid schedule time": {update: func(rule *NotificationRule) {
+			rule.Schedule = &NotificationRuleSchedule{StartTime: "25:00", EndTime: "07:00"}
+		}},
+		"empty schedule window": {update: func(rule *NotificationRule) {
+			rule.Schedule = &NotificationRuleSchedule{StartTime: "07:00", EndTime: "07:00"}
+		}},
+		"invalid schedule day": {update: func(rule *NotificationRule) {
+			rule.Schedule = &NotificationRuleSchedule{Days: []int{7}, StartTime: "09:00", EndTime: "17:00"}
+		}},
+		"too many matchers": {update: func(rule *NotificationRule) { rule.Keywords = make(StringArray, NotificationRuleMaxMatchers) }},
+		"too long keyword": {update: func(rule *NotificationRule) {
+			rule.Keywords = StringArray{strings.Repeat("a", NotificationRuleMatcherMaxLength+1)}
+		}},
+		"scoped to team": {update: func(rule *NotificationRule) { rule.TeamIds = StringArray{NewId()} }, valid: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := newValidNotificationRule()
+			tc.update(rule)
+			if tc.valid {
+				assert.Nil(t, rule.IsValid())
+			} else {
+				assert.NotNil(t, rule.IsValid())
+			}
+		})
+	}
+}
+
+func TestNotificationRuleMatchesText(t *testing.T) {
+	rule := newValidNotificationRule()
+	rule.Keywords = StringArray{"deploy", "on call", "c++"}
+
+	assert.True(t, rule.MatchesText("Starting the Deploy now"))
+	assert.True(t, rule.MatchesText("deploy!"))
+	assert.True(t, rule.MatchesText("who is ON CALL today?"))
+	assert.True(t, rule.MatchesText("I like c++."))
+	assert.True(t, rule.MatchesText("see INC-1234"))
+	assert.False(t, rule.MatchesText("redeploying"))
+	assert.False(t, rule.MatchesText("deploy_bot ran"))
+	assert.False(t, rule.MatchesText("see INC-"))
+
+	rule.Keywords, rule.Patterns = nil, nil
+	assert.True(t, rule.MatchesText("anything"))
+}
+
+func TestNotificationRuleCompilePatterns(t *testing.T) {
+	rule := newValidNotificationRule()
+	require.Nil(t, rule.IsValid())
+
+	regexps := rule.CompilePatterns()
+	require.Len(t, regexps, 1)
+	assert.Same(t, regexps[0], rule.CompilePatterns()[0], "the compiled patterns are cached")
+
+	rule.Patterns = StringArray{`PR-\d+`, "a("}
+	regexps = rule.CompilePatterns()
+	require.Len(t, regexps, 1, "the invalid patterns are skipped")
+	assert.True(t, rule.MatchesText("see PR-42"))
+	assert.False(t, rule.MatchesText("see INC-1234"))
+}
+
+func TestNotificationRuleAppliesToChannel(t *testing.T) {
+	rule := newValidNotificationRule()
+	channel := &Channel{Id: NewId(), TeamId: NewId()}
+	dm := &Channel{Id: NewId(), Type: ChannelTypeDirect}
+
+	assert.True(t, rule.AppliesToChannel(channel))
+	assert.True(t, rule.AppliesToChannel(dm))
+
+	rule.TeamIds = StringArray{channel.TeamId}
+	assert.True(t, rule.AppliesToChannel(channel))
+	assert.False(t, rule.AppliesToChannel(dm))
+
+	rule.TeamIds = StringArray{}
+	rule.ChannelIds = StringArray{dm.Id}
+	assert.False(t, rule.AppliesToChannel(channel))
+	assert.True(t, rule.AppliesToChannel(dm))
+}
+
+func TestNotificationRuleScheduleIsActiveAt(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	at := func(day int, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("same day window", func(t *testing.T) {
+		schedule := &NotificationRuleSchedule{Days: []int{1, 2, 3, 4, 5}, StartTime: "09:00", EndTime: "17:30"}
+		assert.True(t, schedule.IsActiveAt(at(1, 9, 0)))
+		assert.True(t, schedule.IsActiveAt(at(5, 17, 29)))
+		assert.False(t, schedule.IsActiveAt(at(1, 17, 30)))
+		assert.False(t, schedule.IsActiveAt(at(1, 8, 59)))
+		assert.False(t, schedule.IsActiveAt(at(6, 12, 0)))
+	})
+
+	t.Run("window spanning midnight", func(t *testing.T) {
+		schedule := &NotificationRuleSchedule{Days: []int{5}, StartTime: "22:00", EndTime: "07:00"}
+		assert.True(t, schedule.IsActiveAt(at(5, 23, 0)))
+		assert.True(t, schedule.IsActiveAt(at(6, 6, 59)))
+		assert.False(t, schedule.IsActiveAt(at(6, 7, 0)))
+		assert.False(t, schedule.IsActiveAt(at(6, 23, 0)))
+		assert.False(t, schedule.IsActiveAt(at(5, 6, 0)))
+	})
+
+	t.Run("every day", func(t *testing.T) {
+		schedule := &NotificationRuleSchedule{StartTime: "22:00", EndTime: "07:00"}
+		for day := 1; day <= 7; day++ {
+			assert.True(t, schedule.IsActiveAt(at(day, 3, 0)))
+			assert.False(t, schedule.IsActiveAt(at(day, 12, 0)))
+		}
+	})
+
+	t.Run("rule without schedule", func(t *testing.T) {
+		assert.True(t, newValidNotificationRule().IsActiveAt(at(1, 3, 0)))
+	})
+}
+
+func TestNotificationRuleScheduleValueScan(t *testing.T) {
+	schedule := NotificationRuleSchedule{Days: []int{0, 6}, StartTime: "08:00", EndTime: "12:00"}
+	value, err := schedule.Value()
+	require.NoError(t, err)
+
+	var scanned NotificationRuleSchedule
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, schedule, scanned)
+
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, schedule, scanned)
+	assert.Error(t, scanned.Scan(42))
+}
@@ -30,6 +30,11 @@ type OAuthApp struct {
 	Homepage        string      `json:"homepage"`
 	IsTrusted       bool        `json:"is_trusted"`
 	MattermostAppID string      `json:"mattermost_app_id"`
+	// IsPublic marks an app that can't keep a secret, such as a mobile or command line app. Public
+	// apps have no client secret and must use PKCE with the authorization code grant.
+	IsPublic bool `json:"is_public"`
+	// BotUserId is the bot the client credentials grant issues tokens for, if any.
+	BotUserId string `json:"bot_user_id"`
 }
 
 func (a *OAuthApp) Auditable() map[string]interface{} {
@@ -45,6 +50,8 @@ func (a *OAuthApp) Auditable() map[string]interface{} {
 		"homepage":          a.Homepage,
 		"is_trusted":        a.IsTrusted,
 		"mattermost_app_id": a.MattermostAppID,
+		"is_public":         a.IsPublic,
+		"bot_user_id":       a.BotUserId,
 	}
 }
 
@@ -67,7 +74,11 @@ func (a *OAuthApp) IsValid() *AppError {
 		return NewAppError("OAuthApp.IsValid", "model.oauth.is_valid.creator_id.app_error", nil, "app_id="+a.Id, http.StatusBadRequest)
 	}
 
-	if a.ClientSecret == "" || len(a.ClientSecret) > 128 {
+	if a.IsPublic {
+		if a.ClientSecret != "" {
+			return NewAppError("OAuthApp.IsValid", "model.oauth.is_valid.client_secret.app_error", nil, "app_id="+a.Id, http.StatusBadRequest)
+		}
+	} else if a.ClientSecret == "" || len(a.ClientSecret) > 128 {
 		return NewAppError("OAuthApp.IsValid", "model.oauth.is_valid.client_secret.app_error", nil, "app_id="+a.Id, http.StatusBadRequest)
 	}
 
@@ -103,17 +114,23 @@ func (a *OAuthApp) IsValid() *AppError {
 		return NewAppError("OAuthApp.IsValid", "model.oauth.is_valid.mattermost_app_id.app_error", nil, "app_id="+a.Id, http.StatusBadRequest)
 	}
 
+	if a.BotUserId != "" && (a.IsPublic || !IsValidId(a.BotUserId)) {
+		return NewAppError("OAuthApp.IsValid", "model.oauth.is_valid.bot_user_id.app_error", nil, "app_id="+a.Id, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
-// PreSave will set the Id and ClientSecret if missing.  It will also fill
+// PreSave will set the Id and, for confidential apps, the ClientSecret if missing.  It will also fill
 // in the CreateAt, UpdateAt times. It should be run before saving the app to the db.
 func (a *OAuthApp) PreSave() {
 	if a.Id == "" {
 		a.Id = NewId()
 	}
 
-	if a.ClientSecret == "" {
+	if a.IsPublic {
+		a.ClientSecret = ""
+	} else if a.ClientSecret == "" {
 		a.ClientSecret = NewId()
 	}
 
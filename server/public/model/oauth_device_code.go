@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+const (
+	OAuthDeviceCodeStatusPending  = "pending"
+	OAuthDeviceCodeStatusApproved = "approved"
+	OAuthDeviceCodeStatusDenied   = "denied"
+	OAuthDeviceCodeStatusUsed     = "used"
+
+	OAuthDeviceCodeExpireTime   = 60 * 10 // 10 minutes
+	OAuthDeviceCodePollInterval = 5       // 5 seconds
+
+	// The user code alphabet has no vowels to avoid spelling words and no look-alike characters,
+	// as recommended by RFC 8628.
+	oauthUserCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	oauthUserCodeLength   = 8
+
+	// Error codes of the device authorization grant, see RFC 8628 section 3.5 and RFC 6749 section 5.2.
+	OAuthErrorAuthorizationPending = "authorization_pending"
+	OAuthErrorSlowDown             = "slow_down"
+	OAuthErrorAccessDenied         = "access_denied"
+	OAuthErrorExpiredToken         = "expired_token"
+	OAuthErrorInvalidGrant         = "invalid_grant"
+)
+
+// OAuthDeviceCode is a pending device authorization, polled by the device until the user
+// approves or denies it from another browser.
+type OAuthDeviceCode struct {
+	DeviceCode string `json:"device_code"`
+	UserCode   string `json:"user_code"`
+	ClientId   string `json:"client_id"`
+	UserId     string `json:"user_id"`
+	Scope      string `json:"scope"`
+	Status     string `json:"status"`
+	CreateAt   int64  `json:"create_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+	LastPollAt int64  `json:"last_poll_at"`
+}
+
+// OAuthDeviceAuthorizationResponse is the response of the device authorization endpoint.
+type OAuthDeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// OAuthErrorResponse is the error response of the token endpoint, see RFC 6749 section 5.2.
+type OAuthErrorResponse struct {
+	ErrorCode        string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthErrorResponse) Error() string {
+	if e.ErrorDescription == "" {
+		return e.ErrorCode
+	}
+	return e.ErrorCode + ": " + e.ErrorDescription
+}
+
+// PreSave will generate the device and user codes if missing, and fill in the
+// status and the expiry. It should be run before saving the device code to the db.
+func (dc *OAuthDeviceCode) PreSave() {
+	if dc.DeviceCode == "" {
+		dc.DeviceCode = NewId() + NewId()
+	}
+
+	if dc.UserCode == "" {
+		dc.UserCode = NewOAuthUserCode()
+	}
+
+	if dc.Status == "" {
+		dc.Status = OAuthDeviceCodeStatusPending
+	}
+
+	if dc.Scope == "" {
+		dc.Scope = DefaultScope
+	}
+
+	if dc.CreateAt == 0 {
+		dc.CreateAt = GetMillis()
+	}
+
+	if dc.ExpiresAt == 0 {
+		dc.ExpiresAt = dc.CreateAt + OAuthDeviceCodeExpireTime*1000
+	}
+}
+
+// IsValid validates the OAuthDeviceCode and returns an error if it isn't configured
+// correctly.
+func (dc *OAuthDeviceCode) IsValid() *AppError {
+	if dc.DeviceCode == "" || len(dc.DeviceCode) > 128 {
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.device_code.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if dc.UserCode == "" || NormalizeOAuthUserCode(dc.UserCode) != dc.UserCode {
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.user_code.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(dc.ClientId) {
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.client_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if dc.UserId != "" && !IsValidId(dc.UserId) {
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.user_id.app_error", nil, "client_id="+dc.ClientId, http.StatusBadRequest)
+	}
+
+	if len(dc.Scope) > 128 {
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.scope.app_error", nil, "client_id="+dc.ClientId, http.StatusBadRequest)
+	}
+
+	switch dc.Status {
+	case OAuthDeviceCodeStatusPending, OAuthDeviceCodeStatusApproved, OAuthDeviceCodeStatusDenied, OAuthDeviceCodeStatusUsed:
+	default:
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.status.app_error", nil, "client_id="+dc.ClientId, http.StatusBadRequest)
+	}
+
+	if dc.CreateAt <= 0 || dc.ExpiresAt <= dc.CreateAt {
+		return NewAppError("OAuthDeviceCode.IsValid", "model.oauth_device_code.is_valid.expires_at.app_error", nil, "client_id="+dc.ClientId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (dc *OAuthDeviceCode) IsExpired() bool {
+	return GetMillis() > dc.ExpiresAt
+}
+
+// NewOAuthUserCode returns a random user code formatted as XXXX-XXXX.
+func NewOAuthUserCode() string {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(oauthUserCodeAlphabet)))
+	for i := 0; i < oauthUserCodeLength; i++ {
+		if i == oauthUserCodeLength/2 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		sb.WriteByte(oauthUserCodeAlphabet[n.Int64()])
+	}
+	return sb.String()
+}
+
+// NormalizeOAuthUserCode returns the user code as typed by the user in the format it's stored,
+// ignoring the case and the separators. It returns an empty string if the code isn't valid.
+func NormalizeOAuthUserCode(userCode string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(userCode) {
+		if r == '-' || r == ' ' {
+			continue
+		}
+		if !strings.ContainsRune(oauthUserCodeAlphabet, r) {
+			return ""
+		}
+		if sb.Len() == oauthUserCodeLength/2 {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(r)
+	}
+
+	if sb.Len() != oauthUserCodeLength+1 {
+		return ""
+	}
+	return sb.String()
+}
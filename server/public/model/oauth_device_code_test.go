@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthDeviceCodePreSave(t *testing.T) {
+	dc := OAuthDeviceCode{ClientId: NewId()}
+	dc.PreSave()
+
+	require.Len(t, dc.DeviceCode, 52)
+	require.Equal(t, dc.UserCode, NormalizeOAuthUserCode(dc.UserCode))
+	require.Equal(t, OAuthDeviceCodeStatusPending, dc.Status)
+	require.Equal(t, DefaultScope, dc.Scope)
+	require.Equal(t, dc.CreateAt+OAuthDeviceCodeExpireTime*1000, dc.ExpiresAt)
+	require.False(t, dc.IsExpired())
+	require.Nil(t, dc.IsValid())
+}
+
+func TestOAuthDeviceCodeIsValid(t *testing.T) {
+	dc := OAuthDeviceCode{}
+	require.NotNil(t, dc.IsValid())
+
+	dc.DeviceCode = NewRandomString(129)
+	require.NotNil(t, dc.IsValid(), "Should have failed device code too long")
+
+	dc.DeviceCode = NewId()
+	require.NotNil(t, dc.IsValid())
+
+	dc.UserCode = "ABCD-EFGH"
+	require.NotNil(t, dc.IsValid(), "Should have failed user code with vowels")
+
+	dc.UserCode = "BCDF-GHJK"
+	require.NotNil(t, dc.IsValid())
+
+	dc.ClientId = NewId()
+	require.NotNil(t, dc.IsValid())
+
+	dc.UserId = "junk"
+	require.NotNil(t, dc.IsValid(), "Should have failed invalid user id")
+
+	dc.UserId = ""
+	require.NotNil(t, dc.IsValid())
+
+	dc.Status = "junk"
+	require.NotNil(t, dc.IsValid(), "Should have failed invalid status")
+
+	dc.Status = OAuthDeviceCodeStatusPending
+	require.NotNil(t, dc.IsValid())
+
+	dc.CreateAt = 2
+	dc.ExpiresAt = 1
+	require.NotNil(t, dc.IsValid(), "Should have failed expiry before creation")
+
+	dc.ExpiresAt = 3
+	require.Nil(t, dc.IsValid())
+	require.True(t, dc.IsExpired())
+}
+
+func TestNormalizeOAuthUserCode(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{"BCDF-GHJK", "BCDF-GHJK"},
+		{"bcdfghjk", "BCDF-GHJK"},
+		{" bcdf ghjk", "BCDF-GHJK"},
+		{"BCDF-GHJ", ""},
+		{"BCDF-GHJKL", ""},
+		{"BCDF-GHJ1", ""},
+		{"", ""},
+	} {
+		require.Equal(t, tc.expected, NormalizeOAuthUserCode(tc.input), tc.input)
+	}
+
+	for i := 0; i < 10; i++ {
+		code := NewOAuthUserCode()
+		require.Equal(t, code, NormalizeOAuthUserCode(code))
+	}
+}
@@ -68,3 +68,30 @@ func TestOAuthAppIsValid(t *testing.T) {
 	app.IconURL = "https://nowhere.com/icon_image.png"
 	require.Nil(t, app.IsValid())
 }
+
+func TestOAuthAppIsValidPublic(t *testing.T) {
+	app := OAuthApp{
+		Name:         "TestOAuthApp",
+		CreatorId:    NewId(),
+		CallbackUrls: []string{"https://nowhere.com"},
+		Homepage:     "https://nowhere.com",
+		IsPublic:     true,
+	}
+	app.PreSave()
+	require.Empty(t, app.ClientSecret)
+	require.Nil(t, app.IsValid())
+
+	app.ClientSecret = NewId()
+	require.NotNil(t, app.IsValid(), "Should have failed public app with a secret")
+
+	app.ClientSecret = ""
+	app.BotUserId = NewId()
+	require.NotNil(t, app.IsValid(), "Should have failed public app with a bot user")
+
+	app.IsPublic = false
+	app.ClientSecret = NewId()
+	require.Nil(t, app.IsValid())
+
+	app.BotUserId = "junk"
+	require.NotNil(t, app.IsValid(), "Should have failed invalid bot user id")
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	PasswordHashAlgorithmBcrypt   = "bcrypt"
+	PasswordHashAlgorithmArgon2id = "argon2id"
+	PasswordHashAlgorithmScrypt   = "scrypt"
+
+	PasswordHashBcryptCost = 10
+
+	PasswordHashArgon2idDefaultMemoryKiB   = 64 * 1024
+	PasswordHashArgon2idDefaultIterations  = 3
+	PasswordHashArgon2idDefaultParallelism = 2
+	PasswordHashArgon2idMinMemoryKiB       = 8 * 1024
+	PasswordHashArgon2idMaxMemoryKiB       = 4 * 1024 * 1024
+	PasswordHashArgon2idMaxIterations      = 100
+	PasswordHashArgon2idMaxParallelism     = 255
+
+	// The scrypt parameters are the ones recommended for interactive logins, with
+	// N = 2^passwordHashScryptLogN.
+	passwordHashScryptLogN = 15
+	passwordHashScryptR    = 8
+	passwordHashScryptP    = 1
+
+	passwordHashSaltLength = 16
+	passwordHashKeyLength  = 32
+
+	// Hashes with a shorter salt or key are rejected rather than compared.
+	passwordHashMinSaltLength = 8
+	passwordHashMinKeyLength  = 16
+)
+
+// ErrPasswordHashMismatch is returned when a password does not match a hash.
+var ErrPasswordHashMismatch = errors.New("password does not match the hash")
+
+// passwordHash holds a hash in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>, in which the algorithm and its parameters
+// are stored along with the hash. Bcrypt hashes keep their own format.
+type passwordHash struct {
+	algorithm string
+	params    map[string]int
+	salt      []byte
+	key       []byte
+}
+
+func parsePasswordHash(hash string) (*passwordHash, error) {
+	if strings.HasPrefix(hash, "$2") {
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return nil, err
+		}
+		return &passwordHash{algorithm: PasswordHashAlgorithmBcrypt, params: map[string]int{"cost": cost}}, nil
+	}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) < 5 || parts[0] != "" {
+		return nil, errors.New("unknown password hash format")
+	}
+
+	h := &passwordHash{algorithm: parts[1], params: map[string]int{}}
+	switch h.algorithm {
+	case PasswordHashAlgorithmArgon2id:
+		if len(parts) != 6 || parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+			return nil, errors.New("unsupported argon2id hash version")
+		}
+		parts = append(parts[:2], parts[3:]...)
+	case PasswordHashAlgorithmScrypt:
+		if len(parts) != 5 {
+			return nil, errors.New("invalid scrypt hash")
+		}
+	default:
+		return nil, errors.Errorf("unknown password hash algorithm %q", h.algorithm)
+	}
+
+	for _, param := range strings.Split(parts[2], ",") {
+		var value int
+		name, rawValue, found := strings.Cut(param, "=")
+		if !found {
+			return nil, errors.Errorf("invalid password hash parameter %q", param)
+		}
+		if _, err := fmt.Sscanf(rawValue, "%d", &value); err != nil || value <= 0 {
+			return nil, errors.Errorf("invalid password hash parameter %q", param)
+		}
+		h.params[name] = value
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil {
+		return nil, errors.Wrap(err, "invalid password hash salt")
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, errors.Wrap(err, "invalid password hash key")
+	}
+	if len(h.salt) < passwordHashMinSaltLength || len(h.key) < passwordHashMinKeyLength {
+		return nil, errors.New("invalid password hash length")
+	}
+
+	return h, nil
+}
+
+func (h *passwordHash) deriveKey(password string) ([]byte, error) {
+	switch h.algorithm {
+	case PasswordHashAlgorithmArgon2id:
+		m, t, p := h.params["m"], h.params["t"], h.params["p"]
+		if m == 0 || t == 0 || p == 0 || m > PasswordHashArgon2idMaxMemoryKiB || t > PasswordHashArgon2idMaxIterations || p > PasswordHashArgon2idMaxParallelism {
+			return nil, errors.New("invalid argon2id parameters")
+		}
+		return argon2.IDKey([]byte(password), h.salt, uint32(t), uint32(m), uint8(p), uint32(len(h.key))), nil
+	case PasswordHashAlgorithmScrypt:
+		ln, r, p := h.params["ln"], h.params["r"], h.params["p"]
+		if ln == 0 || ln > 20 || r == 0 || p == 0 {
+			return nil, errors.New("invalid scrypt parameters")
+		}
+		return scrypt.Key([]byte(password), h.salt, 1<<ln, r, p, len(h.key))
+	}
+
+	return nil, errors.Errorf("cannot derive a key with %q", h.algorithm)
+}
+
+func (h *passwordHash) String() string {
+	salt := base64.RawStdEncoding.EncodeToString(h.salt)
+	key := base64.RawStdEncoding.EncodeToString(h.key)
+	switch h.algorithm {
+	case PasswordHashAlgorithmArgon2id:
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.params["m"], h.params["t"], h.params["p"], salt, key)
+	default:
+		return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", h.params["ln"], h.params["r"], h.params["p"], salt, key)
+	}
+}
+
+// passwordHashParams returns the parameters the settings require for their algorithm.
+func passwordHashParams(settings *PasswordSettings) (string, map[string]int) {
+	switch *settings.HashAlgorithm {
+	case PasswordHashAlgorithmArgon2id:
+		return PasswordHashAlgorithmArgon2id, map[string]int{
+			"m": *settings.Argon2idMemoryKiB,
+			"t": *settings.Argon2idIterations,
+			"p": *settings.Argon2idParallelism,
+		}
+	case PasswordHashAlgorithmScrypt:
+		return PasswordHashAlgorithmScrypt, map[string]int{
+			"ln": passwordHashScryptLogN,
+			"r":  passwordHashScryptR,
+			"p":  passwordHashScryptP,
+		}
+	default:
+		return PasswordHashAlgorithmBcrypt, map[string]int{"cost": PasswordHashBcryptCost}
+	}
+}
+
+// HashPasswordWithSettings hashes a password with the algorithm and parameters of the
+// password settings.
+func HashPasswordWithSettings(password string, settings *PasswordSettings) (string, error) {
+	algorithm, params := passwordHashParams(settings)
+	if algorithm == PasswordHashAlgorithmBcrypt {
+		return HashPassword(password)
+	}
+
+	if len(password) > PasswordMaximumLength {
+		return "", bcrypt.ErrPasswordTooLong
+	}
+
+	h := &passwordHash{
+		algorithm: algorithm,
+		params:    params,
+		salt:      make([]byte, passwordHashSaltLength),
+		key:       make([]byte, passwordHashKeyLength),
+	}
+	if _, err := rand.Read(h.salt); err != nil {
+		return "", errors.Wrap(err, "failed to generate a salt")
+	}
+
+	key, err := h.deriveKey(password)
+	if err != nil {
+		return "", err
+	}
+	h.key = key
+
+	return h.String(), nil
+}
+
+// ComparePasswordHash checks a password against a hash of any of the supported
+// algorithms.
+func ComparePasswordHash(hash, password string) error {
+	if strings.HasPrefix(hash, "$2") {
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return ErrPasswordHashMismatch
+			}
+			return err
+		}
+		return nil
+	}
+
+	h, err := parsePasswordHash(hash)
+	if err != nil {
+		return err
+	}
+
+	key, err := h.deriveKey(password)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(key, h.key) != 1 {
+		return ErrPasswordHashMismatch
+	}
+
+	return nil
+}
+
+// PasswordHashNeedsUpgrade returns whether a hash was computed with another algorithm or
+// other parameters than the ones the password settings require, in which case the
+// password should be hashed again the next time it is known.
+func PasswordHashNeedsUpgrade(hash string, settings *PasswordSettings) bool {
+	h, err := parsePasswordHash(hash)
+	if err != nil {
+		return false
+	}
+
+	algorithm, params := passwordHashParams(settings)
+	if h.algorithm != algorithm {
+		return true
+	}
+
+	if algorithm == PasswordHashAlgorithmBcrypt {
+		return h.params["cost"] < params["cost"]
+	}
+
+	for name, value := range params {
+		if h.params[name] != value {
+			return true
+		}
+	}
+
+	return false
+}
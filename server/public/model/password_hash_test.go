@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestPasswordSettings(algorithm string) *PasswordSettings {
+	settings := &PasswordSettings{
+		HashAlgorithm:     NewPointer(algorithm),
+		Argon2idMemoryKiB: NewPointer(PasswordHashArgon2idMinMemoryKiB),
+	}
+	settings.SetDefaults()
+	return settings
+}
+
+func TestHashPasswordWithSettings(t *testing.T) {
+	for algorithm, prefix := range map[string]string{
+		PasswordHashAlgorithmBcrypt:   "$2a$10$",
+		PasswordHashAlgorithmArgon2id: "$argon2id$v=19$m=8192,t=3,p=2$",
+		PasswordHashAlgorithmScrypt:   "$scrypt$ln=15,r=8,p=1$",
+	} {
+		t.Run(algorithm, func(t *testing.T) {
+			settings := newTestPasswordSettings(algorithm)
+
+			hash, err := HashPasswordWithSettings("Pa$$word", settings)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, prefix), hash)
+			assert.LessOrEqual(t, len(hash), 128)
+
+			other, err := HashPasswordWithSettings("Pa$$word", settings)
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other, "hashes must be salted")
+
+			assert.NoError(t, ComparePasswordHash(hash, "Pa$$word"))
+			assert.ErrorIs(t, ComparePasswordHash(hash, "Password"), ErrPasswordHashMismatch)
+			assert.False(t, PasswordHashNeedsUpgrade(hash, settings))
+
+			_, err = HashPasswordWithSettings(strings.Repeat("x", PasswordMaximumLength+1), settings)
+			assert.ErrorIs(t, err, bcrypt.ErrPasswordTooLong)
+		})
+	}
+}
+
+func TestComparePasswordHashInvalid(t *testing.T) {
+	hash, err := HashPasswordWithSettings("password", newTestPasswordSettings(PasswordHashAlgorithmArgon2id))
+	require.NoError(t, err)
+	parts := strings.Split(hash, "$")
+
+	for name, invalid := range map[string]string{
+		"empty":             "",
+		"plain":             "password",
+		"unknown algorithm": "$md5$salt$key",
+		"unknown version":   strings.Replace(hash, "v=19", "v=16", 1),
+		"missing params":    strings.Join(append(parts[:3:3], parts[4:]...), "$"),
+		"invalid param":     strings.Replace(hash, "t=3", "t=x", 1),
+		"huge memory":       strings.Replace(hash, "m=8192", "m=99999999", 1),
+		"empty key":         strings.Join(append(parts[:5:5], ""), "$"),
+		"short salt":        strings.Join(append(append(parts[:4:4], "c2FsdA"), parts[5]), "$"),
+		"short key":         strings.Join(append(parts[:5:5], "a2V5"), "$"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, ComparePasswordHash(invalid, "password"))
+		})
+	}
+}
+
+func TestPasswordHashNeedsUpgrade(t *testing.T) {
+	bcryptHash, err := HashPassword("password")
+	require.NoError(t, err)
+	weakBcryptHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	argon2idHash, err := HashPasswordWithSettings("password", newTestPasswordSettings(PasswordHashAlgorithmArgon2id))
+	require.NoError(t, err)
+
+	bcryptSettings := newTestPasswordSettings(PasswordHashAlgorithmBcrypt)
+	argon2idSettings := newTestPasswordSettings(PasswordHashAlgorithmArgon2id)
+	tunedSettings := newTestPasswordSettings(PasswordHashAlgorithmArgon2id)
+	tunedSettings.Argon2idIterations = NewPointer(4)
+
+	assert.False(t, PasswordHashNeedsUpgrade(bcryptHash, bcryptSettings))
+	assert.True(t, PasswordHashNeedsUpgrade(string(weakBcryptHash), bcryptSettings))
+	assert.True(t, PasswordHashNeedsUpgrade(bcryptHash, argon2idSettings))
+	assert.False(t, PasswordHashNeedsUpgrade(argon2idHash, argon2idSettings))
+	assert.True(t, PasswordHashNeedsUpgrade(argon2idHash, tunedSettings))
+	assert.True(t, PasswordHashNeedsUpgrade(argon2idHash, bcryptSettings))
+	assert.False(t, PasswordHashNeedsUpgrade("password", argon2idSettings), "unknown hashes cannot be upgraded")
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+// PasswordHistoryEntry is the hash of a previous password of a user, kept to prevent the
+// user from reusing it.
+type PasswordHistoryEntry struct {
+	Id           string `json:"id"`
+	UserId       string `json:"user_id"`
+	PasswordHash string `json:"-"`
+	CreateAt     int64  `json:"create_at"`
+}
+
+func (e *PasswordHistoryEntry) PreSave() {
+	if e.Id == "" {
+		e.Id = NewId()
+	}
+
+	if e.CreateAt == 0 {
+		e.CreateAt = GetMillis()
+	}
+}
+
+func (e *PasswordHistoryEntry) IsValid() *AppError {
+	if !IsValidId(e.Id) {
+		return NewAppError("PasswordHistoryEntry.IsValid", "model.password_history.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(e.UserId) {
+		return NewAppError("PasswordHistoryEntry.IsValid", "model.password_history.is_valid.user_id.app_error", nil, "id="+e.Id, http.StatusBadRequest)
+	}
+
+	if e.PasswordHash == "" {
+		return NewAppError("PasswordHistoryEntry.IsValid", "model.password_history.is_valid.password_hash.app_error", nil, "id="+e.Id, http.StatusBadRequest)
+	}
+
+	if e.CreateAt == 0 {
+		return NewAppError("PasswordHistoryEntry.IsValid", "model.password_history.is_valid.create_at.app_error", nil, "id="+e.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
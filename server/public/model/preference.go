@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -62,6 +63,16 @@ const (
 	PreferenceEmailIntervalHourAsSeconds     = "3600"
 	PreferenceCloudUserEphemeralInfo         = "cloud_user_ephemeral_info"
 
+	PreferenceNameEmailDigest         = "email_digest"
+	PreferenceNameEmailDigestTime     = "email_digest_time"
+	PreferenceNameEmailDigestDay      = "email_digest_day"
+	PreferenceNameEmailDigestLastSent = "email_digest_last_sent"
+	// the time of the digest is local to the user and the day of a weekly digest goes from 0 (Sunday) to 6
+
+	PreferenceEmailDigestOff    = "off"
+	PreferenceEmailDigestDaily  = "daily"
+	PreferenceEmailDigestWeekly = "weekly"
+
 	PreferenceLimitVisibleDmsGms         = "limit_visible_dms_gms"
 	PreferenceMaxLimitVisibleDmsGmsValue = 40
 	MaxPreferenceValueLength             = 20000
@@ -107,6 +118,23 @@ func (o *Preference) IsValid() *AppError {
 		}
 	}
 
+	if o.Category == PreferenceCategoryNotifications {
+		switch o.Name {
+		case PreferenceNameEmailDigest:
+			if o.Value != PreferenceEmailDigestOff && o.Value != PreferenceEmailDigestDaily && o.Value != PreferenceEmailDigestWeekly {
+				return NewAppError("Preference.IsValid", "model.preference.is_valid.email_digest.app_error", nil, "value="+o.Value, http.StatusBadRequest)
+			}
+		case PreferenceNameEmailDigestTime:
+			if _, err := time.Parse("15:04", o.Value); err != nil {
+				return NewAppError("Preference.IsValid", "model.preference.is_valid.email_digest_time.app_error", nil, "value="+o.Value, http.StatusBadRequest).Wrap(err)
+			}
+		case PreferenceNameEmailDigestDay:
+			if day, err := strconv.Atoi(o.Value); err != nil || day < int(time.Sunday) || day > int(time.Saturday) {
+				return NewAppError("Preference.IsValid", "model.preference.is_valid.email_digest_day.app_error", nil, "value="+o.Value, http.StatusBadRequest)
+			}
+		}
+	}
+
 	return nil
 }
 
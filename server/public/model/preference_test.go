@@ -98,6 +98,25 @@ func TestPreferenceIsValid(t *testing.T) {
 		preference.Value = "-10"
 		require.NotNil(t, preference.IsValid())
 	})
+
+	t.Run("email digest preferences", func(t *testing.T) {
+		preference.Category = PreferenceCategoryNotifications
+		for name, values := range map[string]struct{ valid, invalid []string }{
+			PreferenceNameEmailDigest:     {[]string{PreferenceEmailDigestOff, PreferenceEmailDigestDaily, PreferenceEmailDigestWeekly}, []string{"", "monthly"}},
+			PreferenceNameEmailDigestTime: {[]string{"00:00", "08:30", "23:59"}, []string{"", "8", "24:00", "8:30pm"}},
+			PreferenceNameEmailDigestDay:  {[]string{"0", "6"}, []string{"", "-1", "7", "monday"}},
+		} {
+			preference.Name = name
+			for _, value := range values.valid {
+				preference.Value = value
+				require.Nil(t, preference.IsValid(), "%s=%q", name, value)
+			}
+			for _, value := range values.invalid {
+				preference.Value = value
+				require.NotNil(t, preference.IsValid(), "%s=%q", name, value)
+			}
+		}
+	})
 }
 
 func TestPreferencePreUpdate(t *testing.T) {
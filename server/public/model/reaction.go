@@ -19,6 +19,12 @@ type Reaction struct {
 	ChannelId string  `json:"channel_id"`
 }
 
+// ReactedPost is a post along with the number of reactions it received.
+type ReactedPost struct {
+	Post          *Post `json:"post"`
+	ReactionCount int64 `json:"reaction_count"`
+}
+
 func (o *Reaction) IsValid() *AppError {
 	if !IsValidId(o.UserId) {
 		return NewAppError("Reaction.IsValid", "model.reaction.is_valid.user_id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
@@ -0,0 +1,630 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ScimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ScimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ScimSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	ScimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	ScimContentType  = "application/scim+json"
+	ScimAPIURLSuffix = "/scim/v2"
+
+	ScimResourceTypeUser  = "User"
+	ScimResourceTypeGroup = "Group"
+
+	ScimPatchOpAdd     = "add"
+	ScimPatchOpReplace = "replace"
+	ScimPatchOpRemove  = "remove"
+
+	// ScimMaxResults bounds the number of resources returned by a single query.
+	ScimMaxResults = 200
+
+	// UserPropsKeyScimExternalId holds the identifier a SCIM client, i.e. the identity
+	// provider, knows a user by.
+	UserPropsKeyScimExternalId = "scim_external_id"
+	// UserPropsKeyScimUserName holds the userName a user was provisioned with, when it
+	// is not a valid username and a username was derived from it.
+	UserPropsKeyScimUserName = "scim_user_name"
+)
+
+// SCIM error types, reported along with a status code of 400 or 409.
+const (
+	ScimErrorTypeInvalidFilter = "invalidFilter"
+	ScimErrorTypeUniqueness    = "uniqueness"
+	ScimErrorTypeMutability    = "mutability"
+	ScimErrorTypeInvalidSyntax = "invalidSyntax"
+	ScimErrorTypeInvalidPath   = "invalidPath"
+	ScimErrorTypeNoTarget      = "noTarget"
+	ScimErrorTypeInvalidValue  = "invalidValue"
+)
+
+var scimErrorTypes = map[string]string{
+	"model.scim.filter.app_error":                 ScimErrorTypeInvalidFilter,
+	"model.scim.path.app_error":                   ScimErrorTypeInvalidPath,
+	"model.scim.invalid_syntax.app_error":         ScimErrorTypeInvalidSyntax,
+	"model.scim.patch.invalid_op.app_error":       ScimErrorTypeInvalidSyntax,
+	"model.scim.patch.invalid_value.app_error":    ScimErrorTypeInvalidValue,
+	"model.scim.patch.no_target.app_error":        ScimErrorTypeNoTarget,
+	"model.scim.patch.mutability.app_error":       ScimErrorTypeMutability,
+	"model.scim.user.user_name.app_error":         ScimErrorTypeInvalidValue,
+	"model.scim.user.email.app_error":             ScimErrorTypeInvalidValue,
+	"model.scim.group.display_name.app_error":     ScimErrorTypeInvalidValue,
+	"model.scim.group.external_id.app_error":      ScimErrorTypeInvalidValue,
+	"app.scim.group.member.app_error":             ScimErrorTypeInvalidValue,
+	"app.user.save.username_exists.app_error":     ScimErrorTypeUniqueness,
+	"app.user.save.email_exists.app_error":        ScimErrorTypeUniqueness,
+	"app.scim.group.external_id_exists.app_error": ScimErrorTypeUniqueness,
+}
+
+type ScimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type ScimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// ScimMultiValue is a value of a multi-valued attribute, such as the emails of a user or
+// the members of a group.
+type ScimMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// ScimUser is the SCIM representation of a user, as defined in RFC 7643 section 4.1.
+type ScimUser struct {
+	Schemas     []string          `json:"schemas"`
+	Id          string            `json:"id,omitempty"`
+	ExternalId  string            `json:"externalId,omitempty"`
+	UserName    string            `json:"userName"`
+	Name        *ScimName         `json:"name,omitempty"`
+	DisplayName string            `json:"displayName,omitempty"`
+	NickName    string            `json:"nickName,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Locale      string            `json:"locale,omitempty"`
+	Active      *bool             `json:"active,omitempty"`
+	Password    string            `json:"password,omitempty"`
+	Emails      []*ScimMultiValue `json:"emails,omitempty"`
+	Meta        *ScimMeta         `json:"meta,omitempty"`
+}
+
+// ScimGroup is the SCIM representation of a group, as defined in RFC 7643 section 4.2.
+type ScimGroup struct {
+	Schemas     []string          `json:"schemas"`
+	Id          string            `json:"id,omitempty"`
+	ExternalId  string            `json:"externalId,omitempty"`
+	DisplayName string            `json:"displayName"`
+	Members     []*ScimMultiValue `json:"members,omitempty"`
+	Meta        *ScimMeta         `json:"meta,omitempty"`
+}
+
+type ScimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type ScimPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string              `json:"schemas"`
+	Operations []*ScimPatchOperation `json:"Operations"`
+}
+
+type ScimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type ScimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type ScimBulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type ScimFilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type ScimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+type ScimServiceProviderConfig struct {
+	Schemas               []string                    `json:"schemas"`
+	Patch                 ScimSupported               `json:"patch"`
+	Bulk                  ScimBulkSupport             `json:"bulk"`
+	Filter                ScimFilterSupport           `json:"filter"`
+	ChangePassword        ScimSupported               `json:"changePassword"`
+	Sort                  ScimSupported               `json:"sort"`
+	Etag                  ScimSupported               `json:"etag"`
+	AuthenticationSchemes []*ScimAuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *ScimMeta                   `json:"meta"`
+}
+
+type ScimResourceType struct {
+	Schemas     []string  `json:"schemas"`
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Endpoint    string    `json:"endpoint"`
+	Description string    `json:"description"`
+	Schema      string    `json:"schema"`
+	Meta        *ScimMeta `json:"meta"`
+}
+
+// ScimLocation returns the URL of a resource served by the SCIM API.
+func ScimLocation(siteURL, resourceType, id string) string {
+	return siteURL + ScimAPIURLSuffix + "/" + resourceType + "s/" + id
+}
+
+func scimTime(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return GetTimeForMillis(millis).UTC().Format(time.RFC3339)
+}
+
+// NewScimError describes an error in the format of the SCIM protocol.
+func NewScimError(appErr *AppError) *ScimError {
+	scimErr := &ScimError{
+		Schemas: []string{ScimSchemaError},
+		Status:  strconv.Itoa(appErr.StatusCode),
+		Detail:  appErr.Message,
+	}
+	if appErr.StatusCode == http.StatusBadRequest || appErr.StatusCode == http.StatusConflict {
+		scimErr.ScimType = scimErrorTypes[appErr.Id]
+	}
+	return scimErr
+}
+
+func NewScimListResponse(resources []any, totalResults, startIndex int) *ScimListResponse {
+	if resources == nil {
+		resources = []any{}
+	}
+	return &ScimListResponse{
+		Schemas:      []string{ScimSchemaListResponse},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+func NewScimServiceProviderConfig(siteURL string) *ScimServiceProviderConfig {
+	return &ScimServiceProviderConfig{
+		Schemas:        []string{ScimSchemaServiceProviderConfig},
+		Patch:          ScimSupported{Supported: true},
+		Filter:         ScimFilterSupport{Supported: true, MaxResults: ScimMaxResults},
+		ChangePassword: ScimSupported{Supported: true},
+		AuthenticationSchemes: []*ScimAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with a personal access token of a system administrator",
+			Primary:     true,
+		}},
+		Meta: &ScimMeta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     siteURL + ScimAPIURLSuffix + "/ServiceProviderConfig",
+		},
+	}
+}
+
+func NewScimResourceTypes(siteURL string) []*ScimResourceType {
+	resourceTypes := []*ScimResourceType{
+		{Id: ScimResourceTypeUser, Name: ScimResourceTypeUser, Endpoint: "/Users", Description: "User Account", Schema: ScimSchemaUser},
+		{Id: ScimResourceTypeGroup, Name: ScimResourceTypeGroup, Endpoint: "/Groups", Description: "Group", Schema: ScimSchemaGroup},
+	}
+	for _, resourceType := range resourceTypes {
+		resourceType.Schemas = []string{ScimSchemaResourceType}
+		resourceType.Meta = &ScimMeta{
+			ResourceType: "ResourceType",
+			Location:     siteURL + ScimAPIURLSuffix + "/ResourceTypes/" + resourceType.Id,
+		}
+	}
+	return resourceTypes
+}
+
+// ScimUsernameToUsername derives a valid username from the userName of a SCIM user, which
+// identity providers often set to an email address. It returns an empty string when no
+// username can be derived from it.
+func ScimUsernameToUsername(userName string) string {
+	username := NormalizeUsername(strings.TrimSpace(userName))
+	if at := strings.LastIndex(username, "@"); at > 0 && IsValidEmail(username) {
+		username = username[:at]
+	}
+
+	username = strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf && validUsernameChars.MatchString(string(r)) {
+			return r
+		}
+		return '-'
+	}, username)
+	username = strings.Trim(username, "-")
+
+	if !IsValidUsername(username) {
+		return ""
+	}
+	return username
+}
+
+// ToScimUser returns the SCIM representation of a user.
+func (u *User) ToScimUser(siteURL string) *ScimUser {
+	userName := u.Username
+	if u.Props[UserPropsKeyScimUserName] != "" {
+		userName = u.Props[UserPropsKeyScimUserName]
+	}
+
+	displayName := u.GetFullName()
+	if displayName == "" {
+		displayName = u.Username
+	}
+
+	su := &ScimUser{
+		Schemas:     []string{ScimSchemaUser},
+		Id:          u.Id,
+		ExternalId:  u.Props[UserPropsKeyScimExternalId],
+		UserName:    userName,
+		DisplayName: displayName,
+		NickName:    u.Nickname,
+		Title:       u.Position,
+		Locale:      u.Locale,
+		Active:      NewPointer(u.DeleteAt == 0),
+		Meta: &ScimMeta{
+			ResourceType: ScimResourceTypeUser,
+			Created:      scimTime(u.CreateAt),
+			LastModified: scimTime(u.UpdateAt),
+			Location:     ScimLocation(siteURL, ScimResourceTypeUser, u.Id),
+		},
+	}
+	if u.FirstName != "" || u.LastName != "" {
+		su.Name = &ScimName{
+			Formatted:  u.GetFullName(),
+			GivenName:  u.FirstName,
+			FamilyName: u.LastName,
+		}
+	}
+	if u.Email != "" {
+		su.Emails = []*ScimMultiValue{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	return su
+}
+
+// ApplyTo sets the attributes of a user from its SCIM representation. The active and
+// password attributes are left to the caller, since changing them has side effects.
+func (su *ScimUser) ApplyTo(user *User) *AppError {
+	username := ScimUsernameToUsername(su.UserName)
+	if username == "" {
+		return NewAppError("ScimUser.ApplyTo", "model.scim.user.user_name.app_error", nil, "user_name="+su.UserName, http.StatusBadRequest)
+	}
+
+	email := NormalizeEmail(strings.TrimSpace(su.primaryEmail()))
+	if email == "" {
+		email = NormalizeEmail(strings.TrimSpace(su.UserName))
+	}
+	if !IsValidEmail(email) {
+		return NewAppError("ScimUser.ApplyTo", "model.scim.user.email.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	user.Username = username
+	user.Email = email
+	user.Nickname = su.NickName
+	user.Position = su.Title
+	user.FirstName = ""
+	user.LastName = ""
+	if su.Name != nil {
+		user.FirstName = su.Name.GivenName
+		user.LastName = su.Name.FamilyName
+	}
+
+	user.MakeNonNil()
+	setOrDeleteProp(user.Props, UserPropsKeyScimExternalId, su.ExternalId)
+	if NormalizeUsername(su.UserName) == username {
+		delete(user.Props, UserPropsKeyScimUserName)
+	} else {
+		user.Props[UserPropsKeyScimUserName] = su.UserName
+	}
+	return nil
+}
+
+func setOrDeleteProp(props StringMap, key, value string) {
+	if value == "" {
+		delete(props, key)
+	} else {
+		props[key] = value
+	}
+}
+
+func (su *ScimUser) primaryEmail() string {
+	var email string
+	for _, value := range su.Emails {
+		if value == nil {
+			continue
+		}
+		if value.Primary {
+			return value.Value
+		}
+		if email == "" {
+			email = value.Value
+		}
+	}
+	return email
+}
+
+// Patch applies the operations of a SCIM PATCH request to the user.
+func (su *ScimUser) Patch(operations []*ScimPatchOperation) *AppError {
+	attributes, err := scimResourceToMap(su)
+	if err != nil {
+		return NewAppError("ScimUser.Patch", "model.scim.invalid_syntax.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	if appErr := applyScimPatch(attributes, operations); appErr != nil {
+		return appErr
+	}
+
+	// Some clients send booleans as strings, e.g. "False".
+	if active, ok := attributes["active"].(string); ok {
+		value, err := strconv.ParseBool(active)
+		if err != nil {
+			return NewAppError("ScimUser.Patch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+		attributes["active"] = value
+	}
+
+	var patched ScimUser
+	if err := scimMapToResource(attributes, &patched); err != nil {
+		return NewAppError("ScimUser.Patch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	*su = patched
+	return nil
+}
+
+// ToScimGroup returns the SCIM representation of a group. The members are left out when
+// nil, for queries excluding them.
+func (group *Group) ToScimGroup(members []*User, siteURL string) *ScimGroup {
+	sg := &ScimGroup{
+		Schemas:     []string{ScimSchemaGroup},
+		Id:          group.Id,
+		ExternalId:  group.GetRemoteId(),
+		DisplayName: group.DisplayName,
+		Meta: &ScimMeta{
+			ResourceType: ScimResourceTypeGroup,
+			Created:      scimTime(group.CreateAt),
+			LastModified: scimTime(group.UpdateAt),
+			Location:     ScimLocation(siteURL, ScimResourceTypeGroup, group.Id),
+		},
+	}
+	for _, member := range members {
+		sg.Members = append(sg.Members, &ScimMultiValue{
+			Value:   member.Id,
+			Display: member.Username,
+			Ref:     ScimLocation(siteURL, ScimResourceTypeUser, member.Id),
+		})
+	}
+	return sg
+}
+
+// ApplyTo sets the attributes of a group from its SCIM representation. The members are
+// left to the caller.
+func (sg *ScimGroup) ApplyTo(group *Group) *AppError {
+	if l := utf8.RuneCountInString(sg.DisplayName); l == 0 || l > GroupDisplayNameMaxLength {
+		return NewAppError("ScimGroup.ApplyTo", "model.scim.group.display_name.app_error", map[string]any{"GroupDisplayNameMaxLength": GroupDisplayNameMaxLength}, "", http.StatusBadRequest)
+	}
+	if len(sg.ExternalId) > GroupRemoteIDMaxLength {
+		return NewAppError("ScimGroup.ApplyTo", "model.scim.group.external_id.app_error", map[string]any{"GroupRemoteIDMaxLength": GroupRemoteIDMaxLength}, "", http.StatusBadRequest)
+	}
+
+	group.DisplayName = sg.DisplayName
+	group.Source = GroupSourceScim
+	group.RemoteId = nil
+	if sg.ExternalId != "" {
+		group.RemoteId = NewPointer(sg.ExternalId)
+	}
+	return nil
+}
+
+// MemberIds returns the ids of the users the group is made of.
+func (sg *ScimGroup) MemberIds() []string {
+	ids := make([]string, 0, len(sg.Members))
+	seen := make(map[string]bool, len(sg.Members))
+	for _, member := range sg.Members {
+		if member == nil || seen[member.Value] {
+			continue
+		}
+		seen[member.Value] = true
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
+
+// Patch applies the operations of a SCIM PATCH request to the group.
+func (sg *ScimGroup) Patch(operations []*ScimPatchOperation) *AppError {
+	attributes, err := scimResourceToMap(sg)
+	if err != nil {
+		return NewAppError("ScimGroup.Patch", "model.scim.invalid_syntax.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	if appErr := applyScimPatch(attributes, operations); appErr != nil {
+		return appErr
+	}
+
+	var patched ScimGroup
+	if err := scimMapToResource(attributes, &patched); err != nil {
+		return NewAppError("ScimGroup.Patch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	*sg = patched
+	return nil
+}
+
+// ScimGroupMemberOperation is an operation of a SCIM PATCH request on the members of a
+// group. The members are added or removed one by one, so that concurrent requests don't
+// undo each other, except when they are replaced as a whole.
+type ScimGroupMemberOperation struct {
+	Op string
+	// Ids are the users the operation adds, removes or replaces the members with. Every
+	// member selected by the filter, or every member without a filter, is removed when it
+	// is nil.
+	Ids    []string
+	Filter *ScimFilter
+}
+
+// SelectMembers returns the members of a group a removal applies to.
+func (op *ScimGroupMemberOperation) SelectMembers(memberIDs []string) []string {
+	if op.Ids != nil {
+		return op.Ids
+	}
+	if op.Filter == nil {
+		return memberIDs
+	}
+
+	var selected []string
+	for _, memberID := range memberIDs {
+		if op.Filter.matches(map[string]any{"value": memberID}) {
+			selected = append(selected, memberID)
+		}
+	}
+	return selected
+}
+
+// SplitScimGroupPatch separates the operations of a SCIM PATCH request on the members of
+// a group from the ones on its other attributes, which apply to the group as usual.
+func SplitScimGroupPatch(operations []*ScimPatchOperation) ([]*ScimPatchOperation, []*ScimGroupMemberOperation, *AppError) {
+	var attributeOps []*ScimPatchOperation
+	var memberOps []*ScimGroupMemberOperation
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		value := lowerScimKeys(operation.Value)
+
+		if operation.Path == "" {
+			values, ok := value.(map[string]any)
+			if !ok {
+				attributeOps = append(attributeOps, operation)
+				continue
+			}
+			if members, ok := values["members"]; ok && op != ScimPatchOpRemove {
+				memberOp, appErr := newScimGroupMemberOperation(op, members)
+				if appErr != nil {
+					return nil, nil, appErr
+				}
+				memberOps = append(memberOps, memberOp)
+				delete(values, "members")
+				if len(values) == 0 {
+					continue
+				}
+			}
+			attributeOps = append(attributeOps, &ScimPatchOperation{Op: operation.Op, Value: values})
+			continue
+		}
+
+		path, appErr := ParseScimPath(operation.Path)
+		if appErr != nil {
+			return nil, nil, appErr
+		}
+		if path.Attribute != "members" {
+			attributeOps = append(attributeOps, operation)
+			continue
+		}
+		if path.SubAttribute != "" {
+			return nil, nil, NewAppError("SplitScimGroupPatch", "model.scim.path.app_error", nil, "path="+operation.Path, http.StatusBadRequest)
+		}
+
+		switch {
+		case op == ScimPatchOpRemove && path.Filter != nil:
+			memberOps = append(memberOps, &ScimGroupMemberOperation{Op: op, Filter: path.Filter})
+		case op == ScimPatchOpRemove && value == nil:
+			memberOps = append(memberOps, &ScimGroupMemberOperation{Op: op})
+		case path.Filter != nil:
+			// Members only have an id, so there is nothing to change in them.
+			return nil, nil, NewAppError("SplitScimGroupPatch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest)
+		default:
+			memberOp, appErr := newScimGroupMemberOperation(op, value)
+			if appErr != nil {
+				return nil, nil, appErr
+			}
+			memberOps = append(memberOps, memberOp)
+		}
+	}
+
+	return attributeOps, memberOps, nil
+}
+
+func newScimGroupMemberOperation(op string, value any) (*ScimGroupMemberOperation, *AppError) {
+	switch op {
+	case ScimPatchOpAdd, ScimPatchOpReplace, ScimPatchOpRemove:
+	default:
+		return nil, NewAppError("SplitScimGroupPatch", "model.scim.patch.invalid_op.app_error", map[string]any{"Op": op}, "", http.StatusBadRequest)
+	}
+
+	if value == nil && op != ScimPatchOpRemove {
+		return nil, NewAppError("SplitScimGroupPatch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, member := range scimValuesOf(value) {
+		if m, ok := member.(map[string]any); ok {
+			member = m["value"]
+		}
+		id, ok := member.(string)
+		if !ok || id == "" {
+			return nil, NewAppError("SplitScimGroupPatch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return &ScimGroupMemberOperation{Op: op, Ids: ids}, nil
+}
+
+func (r *ScimPatchRequest) IsValid() *AppError {
+	hasSchema := false
+	for _, schema := range r.Schemas {
+		hasSchema = hasSchema || schema == ScimSchemaPatchOp
+	}
+	if !hasSchema || len(r.Operations) == 0 {
+		return NewAppError("ScimPatchRequest.IsValid", "model.scim.invalid_syntax.app_error", nil, "", http.StatusBadRequest)
+	}
+	for _, operation := range r.Operations {
+		if operation == nil {
+			return NewAppError("ScimPatchRequest.IsValid", "model.scim.invalid_syntax.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,704 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Operators of the SCIM filter expressions, as defined in RFC 7644 section 3.4.2.2.
+const (
+	ScimFilterOperatorAnd       = "and"
+	ScimFilterOperatorOr        = "or"
+	ScimFilterOperatorNot       = "not"
+	ScimFilterOperatorPresent   = "pr"
+	ScimFilterOperatorEqual     = "eq"
+	ScimFilterOperatorNotEqual  = "ne"
+	ScimFilterOperatorContains  = "co"
+	ScimFilterOperatorStarts    = "sw"
+	ScimFilterOperatorEnds      = "ew"
+	ScimFilterOperatorGreater   = "gt"
+	ScimFilterOperatorGreaterEq = "ge"
+	ScimFilterOperatorLess      = "lt"
+	ScimFilterOperatorLessEq    = "le"
+
+	// ScimFilterOperatorValuePath applies the filter to each value of a multi-valued
+	// complex attribute, e.g. emails[type eq "work"].
+	ScimFilterOperatorValuePath = "[]"
+)
+
+var scimComparisonOperators = map[string]bool{
+	ScimFilterOperatorEqual:     true,
+	ScimFilterOperatorNotEqual:  true,
+	ScimFilterOperatorContains:  true,
+	ScimFilterOperatorStarts:    true,
+	ScimFilterOperatorEnds:      true,
+	ScimFilterOperatorGreater:   true,
+	ScimFilterOperatorGreaterEq: true,
+	ScimFilterOperatorLess:      true,
+	ScimFilterOperatorLessEq:    true,
+}
+
+// ScimFilter is a parsed SCIM filter expression. Attribute names are lower case, since
+// they are case insensitive, and stripped of the URN of the core schemas.
+type ScimFilter struct {
+	Operator  string
+	Attribute string
+	// Value is the string, number, boolean or null a comparison is made against.
+	Value any
+	// Filters holds the operands of the logical operators, and the filter of a value path.
+	Filters []*ScimFilter
+}
+
+// ParseScimFilter parses a SCIM filter expression such as `userName eq "jdoe"` or
+// `emails[type eq "work" and value co "@example.com"] or not (active eq false)`.
+func ParseScimFilter(filter string) (*ScimFilter, *AppError) {
+	tokens, ok := tokenizeScimFilter(filter)
+	if !ok || len(tokens) == 0 {
+		return nil, NewScimFilterError(filter)
+	}
+
+	p := &scimFilterParser{tokens: tokens}
+	parsed, ok := p.parseOr()
+	if !ok || p.pos != len(p.tokens) {
+		return nil, NewScimFilterError(filter)
+	}
+	return parsed, nil
+}
+
+func NewScimFilterError(filter string) *AppError {
+	return NewAppError("ParseScimFilter", "model.scim.filter.app_error", nil, "filter="+filter, http.StatusBadRequest)
+}
+
+type scimFilterToken struct {
+	text string
+	// quoted is set for string literals, whose text holds the unquoted value.
+	quoted bool
+}
+
+func tokenizeScimFilter(filter string) ([]scimFilterToken, bool) {
+	var tokens []scimFilterToken
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '[' || r == ']':
+			tokens = append(tokens, scimFilterToken{text: string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, false
+			}
+			var value string
+			if err := json.Unmarshal([]byte(string(runes[i:end+1])), &value); err != nil {
+				return nil, false
+			}
+			tokens = append(tokens, scimFilterToken{text: value, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()[]"`, runes[end]); end++ {
+			}
+			tokens = append(tokens, scimFilterToken{text: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, true
+}
+
+type scimFilterParser struct {
+	tokens []scimFilterToken
+	pos    int
+}
+
+func (p *scimFilterParser) peek() (scimFilterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return scimFilterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *scimFilterParser) peekKeyword(keyword string) bool {
+	token, ok := p.peek()
+	return ok && !token.quoted && strings.EqualFold(token.text, keyword)
+}
+
+func (p *scimFilterParser) expect(text string) bool {
+	if !p.peekKeyword(text) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *scimFilterParser) parseOr() (*ScimFilter, bool) {
+	left, ok := p.parseAnd()
+	for ok && p.expect(ScimFilterOperatorOr) {
+		var right *ScimFilter
+		if right, ok = p.parseAnd(); ok {
+			left = &ScimFilter{Operator: ScimFilterOperatorOr, Filters: []*ScimFilter{left, right}}
+		}
+	}
+	return left, ok
+}
+
+func (p *scimFilterParser) parseAnd() (*ScimFilter, bool) {
+	left, ok := p.parseUnary()
+	for ok && p.expect(ScimFilterOperatorAnd) {
+		var right *ScimFilter
+		if right, ok = p.parseUnary(); ok {
+			left = &ScimFilter{Operator: ScimFilterOperatorAnd, Filters: []*ScimFilter{left, right}}
+		}
+	}
+	return left, ok
+}
+
+func (p *scimFilterParser) parseUnary() (*ScimFilter, bool) {
+	if p.expect(ScimFilterOperatorNot) {
+		if !p.expect("(") {
+			return nil, false
+		}
+		operand, ok := p.parseOr()
+		if !ok || !p.expect(")") {
+			return nil, false
+		}
+		return &ScimFilter{Operator: ScimFilterOperatorNot, Filters: []*ScimFilter{operand}}, true
+	}
+
+	if p.expect("(") {
+		filter, ok := p.parseOr()
+		if !ok || !p.expect(")") {
+			return nil, false
+		}
+		return filter, true
+	}
+
+	return p.parseAttributeExpression()
+}
+
+func (p *scimFilterParser) parseAttributeExpression() (*ScimFilter, bool) {
+	token, ok := p.peek()
+	if !ok || token.quoted || !isValidScimAttributePath(token.text) {
+		return nil, false
+	}
+	p.pos++
+	attribute := normalizeScimAttributePath(token.text)
+
+	if p.expect("[") {
+		filter, ok := p.parseOr()
+		if !ok || !p.expect("]") {
+			return nil, false
+		}
+		return &ScimFilter{Operator: ScimFilterOperatorValuePath, Attribute: attribute, Filters: []*ScimFilter{filter}}, true
+	}
+
+	operator, ok := p.peek()
+	if !ok || operator.quoted {
+		return nil, false
+	}
+	p.pos++
+	op := strings.ToLower(operator.text)
+
+	if op == ScimFilterOperatorPresent {
+		return &ScimFilter{Operator: op, Attribute: attribute}, true
+	}
+	if !scimComparisonOperators[op] {
+		return nil, false
+	}
+
+	value, ok := p.peek()
+	if !ok {
+		return nil, false
+	}
+	p.pos++
+
+	filter := &ScimFilter{Operator: op, Attribute: attribute}
+	switch {
+	case value.quoted:
+		filter.Value = value.text
+	case value.text == "true":
+		filter.Value = true
+	case value.text == "false":
+		filter.Value = false
+	case value.text == "null":
+		filter.Value = nil
+	default:
+		number, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, false
+		}
+		filter.Value = number
+	}
+	return filter, true
+}
+
+func isValidScimAttributePath(path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(".:$-_", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeScimAttributePath lower cases an attribute path and strips the URN of the core
+// User and Group schemas from it. Attributes of extension schemas keep their URN.
+func normalizeScimAttributePath(path string) string {
+	path = strings.ToLower(path)
+	for _, schema := range []string{ScimSchemaUser, ScimSchemaGroup} {
+		if prefix := strings.ToLower(schema) + ":"; strings.HasPrefix(path, prefix) {
+			return strings.TrimPrefix(path, prefix)
+		}
+	}
+	return path
+}
+
+// Matches reports whether a SCIM resource, such as a ScimUser or a ScimGroup, is selected
+// by the filter.
+func (f *ScimFilter) Matches(resource any) bool {
+	attributes, err := scimResourceToMap(resource)
+	if err != nil {
+		return false
+	}
+	return f.matches(attributes)
+}
+
+func (f *ScimFilter) matches(attributes map[string]any) bool {
+	switch f.Operator {
+	case ScimFilterOperatorAnd:
+		return f.Filters[0].matches(attributes) && f.Filters[1].matches(attributes)
+	case ScimFilterOperatorOr:
+		return f.Filters[0].matches(attributes) || f.Filters[1].matches(attributes)
+	case ScimFilterOperatorNot:
+		return !f.Filters[0].matches(attributes)
+	case ScimFilterOperatorValuePath:
+		for _, element := range scimValuesOf(attributes[f.Attribute]) {
+			if m, ok := element.(map[string]any); ok && f.Filters[0].matches(m) {
+				return true
+			}
+		}
+		return false
+	}
+
+	values := scimAttributeValues(attributes, f.Attribute)
+	if f.Operator == ScimFilterOperatorPresent {
+		for _, value := range values {
+			if value != nil && value != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if f.Operator == ScimFilterOperatorNotEqual {
+		return !(&ScimFilter{Operator: ScimFilterOperatorEqual, Attribute: f.Attribute, Value: f.Value}).matches(attributes)
+	}
+	if f.Value == nil {
+		// Comparing against null is equivalent to testing that the attribute is absent.
+		return f.Operator == ScimFilterOperatorEqual && !(&ScimFilter{Operator: ScimFilterOperatorPresent, Attribute: f.Attribute}).matches(attributes)
+	}
+	for _, value := range values {
+		if compareScimValue(f.Operator, value, f.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareScimValue(operator string, value, expected any) bool {
+	switch expected := expected.(type) {
+	case bool:
+		actual, ok := value.(bool)
+		return ok && operator == ScimFilterOperatorEqual && actual == expected
+	case float64:
+		actual, ok := value.(float64)
+		if !ok {
+			return false
+		}
+		switch operator {
+		case ScimFilterOperatorEqual:
+			return actual == expected
+		case ScimFilterOperatorGreater:
+			return actual > expected
+		case ScimFilterOperatorGreaterEq:
+			return actual >= expected
+		case ScimFilterOperatorLess:
+			return actual < expected
+		case ScimFilterOperatorLessEq:
+			return actual <= expected
+		}
+	case string:
+		actual, ok := value.(string)
+		if !ok {
+			return false
+		}
+		actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+		switch operator {
+		case ScimFilterOperatorEqual:
+			return actual == expected
+		case ScimFilterOperatorContains:
+			return strings.Contains(actual, expected)
+		case ScimFilterOperatorStarts:
+			return strings.HasPrefix(actual, expected)
+		case ScimFilterOperatorEnds:
+			return strings.HasSuffix(actual, expected)
+		case ScimFilterOperatorGreater:
+			return actual > expected
+		case ScimFilterOperatorGreaterEq:
+			return actual >= expected
+		case ScimFilterOperatorLess:
+			return actual < expected
+		case ScimFilterOperatorLessEq:
+			return actual <= expected
+		}
+	}
+	return false
+}
+
+// scimAttributeValues returns the values found at an attribute path of a resource. The
+// values of a multi-valued attribute are returned individually, and a multi-valued
+// complex attribute stands for the "value" sub-attribute of its values.
+func scimAttributeValues(attributes map[string]any, path string) []any {
+	name, subPath, hasSubPath := strings.Cut(path, ".")
+	if strings.HasPrefix(path, "urn:") {
+		// Extension attributes are nested under the URN of their schema.
+		if i := strings.LastIndex(path, ":"); i > 0 {
+			name, subPath, hasSubPath = path[:i], path[i+1:], true
+		}
+	}
+
+	var values []any
+	for _, value := range scimValuesOf(attributes[name]) {
+		m, isComplex := value.(map[string]any)
+		switch {
+		case hasSubPath && isComplex:
+			values = append(values, scimAttributeValues(m, subPath)...)
+		case hasSubPath:
+		case isComplex:
+			if v, ok := m["value"]; ok {
+				values = append(values, v)
+			}
+		default:
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func scimValuesOf(value any) []any {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return value
+	default:
+		return []any{value}
+	}
+}
+
+// scimResourceToMap converts a resource into its JSON representation, with the keys lower
+// cased so that attributes can be matched case insensitively. Since JSON decoding matches
+// keys to fields case insensitively, the map converts back into the resource.
+func scimResourceToMap(resource any) (map[string]any, error) {
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var attributes map[string]any
+	if err := json.Unmarshal(b, &attributes); err != nil {
+		return nil, err
+	}
+	return lowerScimKeys(attributes).(map[string]any), nil
+}
+
+func lowerScimKeys(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		lowered := make(map[string]any, len(value))
+		for key, v := range value {
+			lowered[strings.ToLower(key)] = lowerScimKeys(v)
+		}
+		return lowered
+	case []any:
+		for i, v := range value {
+			value[i] = lowerScimKeys(v)
+		}
+	}
+	return value
+}
+
+func scimMapToResource(attributes map[string]any, resource any) error {
+	b, err := json.Marshal(attributes)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, resource)
+}
+
+// ScimPath is the parsed path of a SCIM PATCH operation, e.g.
+// `emails[type eq "work"].value` or `members[value eq "2819c223"]`.
+type ScimPath struct {
+	Attribute    string
+	Filter       *ScimFilter
+	SubAttribute string
+}
+
+func ParseScimPath(path string) (*ScimPath, *AppError) {
+	invalidPath := NewAppError("ParseScimPath", "model.scim.path.app_error", nil, "path="+path, http.StatusBadRequest)
+
+	parsed := &ScimPath{}
+	attribute := strings.TrimSpace(path)
+	if open := strings.Index(attribute, "["); open >= 0 {
+		end := strings.LastIndex(attribute, "]")
+		if end < open {
+			return nil, invalidPath
+		}
+		filter, appErr := ParseScimFilter(attribute[open+1 : end])
+		if appErr != nil {
+			return nil, invalidPath
+		}
+		parsed.Filter = filter
+
+		rest := attribute[end+1:]
+		if rest != "" {
+			if !strings.HasPrefix(rest, ".") || !isValidScimAttributePath(rest[1:]) || strings.Contains(rest[1:], ".") {
+				return nil, invalidPath
+			}
+			parsed.SubAttribute = strings.ToLower(rest[1:])
+		}
+		attribute = attribute[:open]
+	}
+
+	if !isValidScimAttributePath(attribute) {
+		return nil, invalidPath
+	}
+	attribute = normalizeScimAttributePath(attribute)
+	if strings.HasPrefix(attribute, "urn:") {
+		i := strings.LastIndex(attribute, ":")
+		parsed.Attribute = attribute[:i]
+		parsed.SubAttribute = attribute[i+1:]
+		if parsed.Filter != nil || parsed.SubAttribute == "" {
+			return nil, invalidPath
+		}
+		return parsed, nil
+	}
+
+	name, subAttribute, hasSubAttribute := strings.Cut(attribute, ".")
+	if hasSubAttribute && (parsed.Filter != nil || subAttribute == "" || strings.Contains(subAttribute, ".")) {
+		return nil, invalidPath
+	}
+	parsed.Attribute = name
+	if hasSubAttribute {
+		parsed.SubAttribute = subAttribute
+	}
+	return parsed, nil
+}
+
+// applyScimPatch applies the operations of a SCIM PATCH request, as defined in RFC 7644
+// section 3.5.2, to the JSON representation of a resource.
+func applyScimPatch(attributes map[string]any, operations []*ScimPatchOperation) *AppError {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		value := lowerScimKeys(operation.Value)
+
+		if operation.Path == "" {
+			if op == ScimPatchOpRemove {
+				return NewAppError("applyScimPatch", "model.scim.patch.no_target.app_error", nil, "", http.StatusBadRequest)
+			}
+			values, ok := value.(map[string]any)
+			if !ok {
+				return NewAppError("applyScimPatch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest)
+			}
+			for key, v := range values {
+				path, appErr := ParseScimPath(key)
+				if appErr != nil {
+					return appErr
+				}
+				if appErr := applyScimPatchOperation(attributes, op, path, v); appErr != nil {
+					return appErr
+				}
+			}
+			continue
+		}
+
+		path, appErr := ParseScimPath(operation.Path)
+		if appErr != nil {
+			return appErr
+		}
+		if appErr := applyScimPatchOperation(attributes, op, path, value); appErr != nil {
+			return appErr
+		}
+	}
+	return nil
+}
+
+func applyScimPatchOperation(attributes map[string]any, op string, path *ScimPath, value any) *AppError {
+	switch path.Attribute {
+	case "id", "meta", "schemas":
+		return NewAppError("applyScimPatch", "model.scim.patch.mutability.app_error", map[string]any{"Attribute": path.Attribute}, "", http.StatusBadRequest)
+	}
+
+	switch op {
+	case ScimPatchOpAdd, ScimPatchOpReplace:
+		if value == nil {
+			return NewAppError("applyScimPatch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest)
+		}
+	case ScimPatchOpRemove:
+	default:
+		return NewAppError("applyScimPatch", "model.scim.patch.invalid_op.app_error", map[string]any{"Op": op}, "", http.StatusBadRequest)
+	}
+
+	if path.Filter != nil {
+		return applyScimPatchToFilteredValues(attributes, op, path, value)
+	}
+
+	if path.SubAttribute != "" {
+		switch current := attributes[path.Attribute].(type) {
+		case []any:
+			// The sub-attribute of every value of a multi-valued attribute is targeted.
+			for _, element := range current {
+				if m, ok := element.(map[string]any); ok {
+					setScimSubAttribute(m, op, path.SubAttribute, value)
+				}
+			}
+			if len(current) == 0 && op != ScimPatchOpRemove {
+				attributes[path.Attribute] = []any{map[string]any{path.SubAttribute: value}}
+			}
+		case map[string]any:
+			setScimSubAttribute(current, op, path.SubAttribute, value)
+		default:
+			if op != ScimPatchOpRemove {
+				attributes[path.Attribute] = map[string]any{path.SubAttribute: value}
+			}
+		}
+		return nil
+	}
+
+	current, exists := attributes[path.Attribute]
+	switch op {
+	case ScimPatchOpAdd:
+		currentValues, isMultiValued := current.([]any)
+		switch {
+		case isMultiValued:
+			attributes[path.Attribute] = append(currentValues, scimValuesOf(value)...)
+		case exists && isScimComplexValue(current) && isScimComplexValue(value):
+			mergeScimComplexValue(current.(map[string]any), value.(map[string]any))
+		default:
+			attributes[path.Attribute] = value
+		}
+	case ScimPatchOpReplace:
+		if exists && isScimComplexValue(current) && isScimComplexValue(value) {
+			mergeScimComplexValue(current.(map[string]any), value.(map[string]any))
+		} else {
+			attributes[path.Attribute] = value
+		}
+	case ScimPatchOpRemove:
+		currentValues, isMultiValued := current.([]any)
+		if isMultiValued && value != nil {
+			// Some clients list the values to remove rather than using a filter.
+			attributes[path.Attribute] = removeScimValues(currentValues, scimValuesOf(value))
+		} else {
+			delete(attributes, path.Attribute)
+		}
+	}
+	return nil
+}
+
+func applyScimPatchToFilteredValues(attributes map[string]any, op string, path *ScimPath, value any) *AppError {
+	current, _ := attributes[path.Attribute].([]any)
+
+	var matched bool
+	kept := make([]any, 0, len(current))
+	for _, element := range current {
+		m, ok := element.(map[string]any)
+		if !ok || !path.Filter.matches(m) {
+			kept = append(kept, element)
+			continue
+		}
+		matched = true
+
+		switch {
+		case op == ScimPatchOpRemove && path.SubAttribute == "":
+			continue
+		case path.SubAttribute != "":
+			setScimSubAttribute(m, op, path.SubAttribute, value)
+		case isScimComplexValue(value):
+			mergeScimComplexValue(m, value.(map[string]any))
+		default:
+			return NewAppError("applyScimPatch", "model.scim.patch.invalid_value.app_error", nil, "", http.StatusBadRequest)
+		}
+		kept = append(kept, element)
+	}
+
+	if !matched {
+		if op == ScimPatchOpRemove {
+			// Removing a value which is not there leaves the resource as requested.
+			return nil
+		}
+		return NewAppError("applyScimPatch", "model.scim.patch.no_target.app_error", nil, "", http.StatusBadRequest)
+	}
+	attributes[path.Attribute] = kept
+	return nil
+}
+
+func setScimSubAttribute(attributes map[string]any, op, subAttribute string, value any) {
+	if op == ScimPatchOpRemove {
+		delete(attributes, subAttribute)
+		return
+	}
+	attributes[subAttribute] = value
+}
+
+func isScimComplexValue(value any) bool {
+	_, ok := value.(map[string]any)
+	return ok
+}
+
+func mergeScimComplexValue(current, value map[string]any) {
+	for key, v := range value {
+		current[key] = v
+	}
+}
+
+// removeScimValues removes values from a multi-valued attribute, matching complex values
+// by their "value" sub-attribute.
+func removeScimValues(values []any, removed []any) []any {
+	keyOf := func(value any) string {
+		if m, ok := value.(map[string]any); ok {
+			value = m["value"]
+		}
+		key, _ := value.(string)
+		return key
+	}
+
+	removedKeys := make(map[string]bool, len(removed))
+	for _, value := range removed {
+		if key := keyOf(value); key != "" {
+			removedKeys[key] = true
+		}
+	}
+
+	kept := make([]any, 0, len(values))
+	for _, value := range values {
+		if !removedKeys[keyOf(value)] {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScimFilter(t *testing.T) {
+	t.Run("valid filters", func(t *testing.T) {
+		for _, filter := range []string{
+			`userName eq "bjensen"`,
+			`name.familyName co "O'Malley"`,
+			`urn:ietf:params:scim:schemas:core:2.0:User:userName sw "J"`,
+			`title pr`,
+			`meta.lastModified gt "2011-05-13T04:42:34Z"`,
+			`title pr and userType eq "Employee"`,
+			`title pr or userType eq "Intern"`,
+			`userType eq "Employee" and (emails co "example.com" or emails.value co "example.org")`,
+			`userType ne "Employee" and not (emails co "example.com" or emails.value co "example.org")`,
+			`emails[type eq "work" and value co "@example.com"] or ims[type eq "xmpp" and value co "@foo.com"]`,
+			`active eq false`,
+			`displayName eq "a \"quoted\" name"`,
+			`userName EQ "bjensen" AND active Eq true`,
+		} {
+			_, appErr := ParseScimFilter(filter)
+			assert.Nil(t, appErr, filter)
+		}
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, filter := range []string{
+			``,
+			`userName`,
+			`userName eq`,
+			`userName equals "bjensen"`,
+			`userName eq "bjensen`,
+			`userName eq bjensen`,
+			`(userName eq "bjensen"`,
+			`userName eq "bjensen" and`,
+			`not userName eq "bjensen"`,
+			`emails[type eq "work"`,
+			`"userName" eq "bjensen"`,
+		} {
+			_, appErr := ParseScimFilter(filter)
+			require.NotNil(t, appErr, filter)
+			assert.Equal(t, "model.scim.filter.app_error", appErr.Id)
+		}
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		filter, appErr := ParseScimFilter(`a eq "1" or b eq "2" and c eq "3"`)
+		require.Nil(t, appErr)
+		assert.Equal(t, ScimFilterOperatorOr, filter.Operator)
+		assert.Equal(t, "a", filter.Filters[0].Attribute)
+		assert.Equal(t, ScimFilterOperatorAnd, filter.Filters[1].Operator)
+	})
+
+	t.Run("schema URN is stripped", func(t *testing.T) {
+		filter, appErr := ParseScimFilter(`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bjensen"`)
+		require.Nil(t, appErr)
+		assert.Equal(t, "username", filter.Attribute)
+		assert.Equal(t, "bjensen", filter.Value)
+	})
+}
+
+func TestScimFilterMatches(t *testing.T) {
+	user := &ScimUser{
+		Schemas:    []string{ScimSchemaUser},
+		Id:         "2819c223",
+		ExternalId: "bjensen-ext",
+		UserName:   "bjensen@example.com",
+		Name:       &ScimName{GivenName: "Barbara", FamilyName: "Jensen"},
+		Title:      "Tour Guide",
+		Active:     NewPointer(true),
+		Emails: []*ScimMultiValue{
+			{Value: "bjensen@example.com", Type: "work", Primary: true},
+			{Value: "babs@jensen.org", Type: "home"},
+		},
+		Meta: &ScimMeta{LastModified: "2024-05-13T04:42:34Z"},
+	}
+
+	for filter, expected := range map[string]bool{
+		`userName eq "BJensen@example.com"`:                           true,
+		`userName eq "jsmith@example.com"`:                            false,
+		`userName ne "jsmith@example.com"`:                            true,
+		`externalId eq "bjensen-ext"`:                                 true,
+		`name.familyName co "ens"`:                                    true,
+		`name.givenName sw "bar"`:                                     true,
+		`name.givenName ew "bar"`:                                     false,
+		`title pr`:                                                    true,
+		`nickName pr`:                                                 false,
+		`nickName eq null`:                                            true,
+		`active eq true`:                                              true,
+		`active eq false`:                                             false,
+		`emails co "jensen.org"`:                                      true,
+		`emails.value eq "babs@jensen.org"`:                           true,
+		`emails[type eq "work" and value co "jensen.org"]`:            false,
+		`emails[type eq "home" and value co "jensen.org"]`:            true,
+		`meta.lastModified gt "2024-01-01T00:00:00Z"`:                 true,
+		`meta.lastModified lt "2024-01-01T00:00:00Z"`:                 false,
+		`title eq "Tour Guide" and not (active eq false)`:             true,
+		`title eq "Manager" or userName sw "bjensen"`:                 true,
+		`urn:ietf:params:scim:schemas:core:2.0:User:id eq "2819c223"`: true,
+	} {
+		parsed, appErr := ParseScimFilter(filter)
+		require.Nil(t, appErr, filter)
+		assert.Equal(t, expected, parsed.Matches(user), filter)
+	}
+}
+
+func TestParseScimPath(t *testing.T) {
+	for path, expected := range map[string]ScimPath{
+		"active":         {Attribute: "active"},
+		"name.givenName": {Attribute: "name", SubAttribute: "givenname"},
+		"urn:ietf:params:scim:schemas:core:2.0:User:name.familyName": {Attribute: "name", SubAttribute: "familyname"},
+		"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department": {
+			Attribute:    "urn:ietf:params:scim:schemas:extension:enterprise:2.0:user",
+			SubAttribute: "department",
+		},
+	} {
+		parsed, appErr := ParseScimPath(path)
+		require.Nil(t, appErr, path)
+		assert.Equal(t, expected, *parsed, path)
+	}
+
+	parsed, appErr := ParseScimPath(`emails[type eq "work"].value`)
+	require.Nil(t, appErr)
+	assert.Equal(t, "emails", parsed.Attribute)
+	assert.Equal(t, "value", parsed.SubAttribute)
+	require.NotNil(t, parsed.Filter)
+	assert.Equal(t, "type", parsed.Filter.Attribute)
+
+	for _, path := range []string{"", "members[value eq", `members[value eq "1"]value`, "name.givenName.first", `emails[bogus].value`} {
+		_, appErr := ParseScimPath(path)
+		require.NotNil(t, appErr, path)
+		assert.Equal(t, "model.scim.path.app_error", appErr.Id)
+	}
+}
+
+func TestApplyScimPatch(t *testing.T) {
+	decode := func(t *testing.T, operations string) []*ScimPatchOperation {
+		var request ScimPatchRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"Operations": `+operations+`}`), &request))
+		return request.Operations
+	}
+
+	newGroup := func() *ScimGroup {
+		return &ScimGroup{
+			Schemas:     []string{ScimSchemaGroup},
+			Id:          "group",
+			DisplayName: "Engineering",
+			Members:     []*ScimMultiValue{{Value: "user1"}, {Value: "user2"}},
+		}
+	}
+
+	t.Run("add members", func(t *testing.T) {
+		group := newGroup()
+		appErr := group.Patch(decode(t, `[{"op": "add", "path": "members", "value": [{"value": "user3"}]}]`))
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{"user1", "user2", "user3"}, group.MemberIds())
+	})
+
+	t.Run("remove member by filter", func(t *testing.T) {
+		group := newGroup()
+		appErr := group.Patch(decode(t, `[{"op": "remove", "path": "members[value eq \"user1\"]"}]`))
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{"user2"}, group.MemberIds())
+	})
+
+	t.Run("remove members by value", func(t *testing.T) {
+		group := newGroup()
+		appErr := group.Patch(decode(t, `[{"op": "Remove", "path": "members", "value": [{"value": "user2"}]}]`))
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{"user1"}, group.MemberIds())
+	})
+
+	t.Run("remove all members", func(t *testing.T) {
+		group := newGroup()
+		appErr := group.Patch(decode(t, `[{"op": "remove", "path": "members"}]`))
+		require.Nil(t, appErr)
+		assert.Empty(t, group.MemberIds())
+	})
+
+	t.Run("replace members and display name without a path", func(t *testing.T) {
+		group := newGroup()
+		appErr := group.Patch(decode(t, `[{"op": "replace", "value": {"displayName": "Platform", "members": [{"value": "user3"}]}}]`))
+		require.Nil(t, appErr)
+		assert.Equal(t, "Platform", group.DisplayName)
+		assert.Equal(t, []string{"user3"}, group.MemberIds())
+	})
+
+	t.Run("deactivate user", func(t *testing.T) {
+		for _, operations := range []string{
+			`[{"op": "replace", "path": "active", "value": false}]`,
+			`[{"op": "Replace", "path": "active", "value": "False"}]`,
+			`[{"op": "replace", "value": {"active": false}}]`,
+		} {
+			user := &ScimUser{UserName: "bjensen", Active: NewPointer(true)}
+			appErr := user.Patch(decode(t, operations))
+			require.Nil(t, appErr, operations)
+			require.NotNil(t, user.Active)
+			assert.False(t, *user.Active, operations)
+		}
+	})
+
+	t.Run("replace user attributes", func(t *testing.T) {
+		user := &ScimUser{
+			UserName: "bjensen",
+			Name:     &ScimName{GivenName: "Barbara", FamilyName: "Jensen"},
+			Emails:   []*ScimMultiValue{{Value: "bjensen@example.com", Type: "work", Primary: true}},
+		}
+		appErr := user.Patch(decode(t, `[
+			{"op": "replace", "path": "name.familyName", "value": "Smith"},
+			{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "bsmith@example.com"},
+			{"op": "add", "path": "title", "value": "Manager"},
+			{"op": "add", "path": "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "value": "Sales"}
+		]`))
+		require.Nil(t, appErr)
+		assert.Equal(t, "Barbara", user.Name.GivenName)
+		assert.Equal(t, "Smith", user.Name.FamilyName)
+		assert.Equal(t, "bsmith@example.com", user.Emails[0].Value)
+		assert.Equal(t, "Manager", user.Title)
+	})
+
+	t.Run("remove user attribute", func(t *testing.T) {
+		user := &ScimUser{UserName: "bjensen", Title: "Manager"}
+		appErr := user.Patch(decode(t, `[{"op": "remove", "path": "title"}]`))
+		require.Nil(t, appErr)
+		assert.Empty(t, user.Title)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for operations, expected := range map[string]string{
+			`[{"op": "remove"}]`: "model.scim.patch.no_target.app_error",
+			`[{"op": "replace", "path": "emails[type eq \"home\"].value", "value": "x"}]`: "model.scim.patch.no_target.app_error",
+			`[{"op": "replace", "path": "id", "value": "x"}]`:                             "model.scim.patch.mutability.app_error",
+			`[{"op": "move", "path": "title", "value": "x"}]`:                             "model.scim.patch.invalid_op.app_error",
+			`[{"op": "replace", "path": "title"}]`:                                        "model.scim.patch.invalid_value.app_error",
+			`[{"op": "replace", "path": "active", "value": "maybe"}]`:                     "model.scim.patch.invalid_value.app_error",
+			`[{"op": "replace", "path": "title[", "value": "x"}]`:                         "model.scim.path.app_error",
+		} {
+			user := &ScimUser{UserName: "bjensen", Emails: []*ScimMultiValue{{Value: "bjensen@example.com", Type: "work"}}}
+			appErr := user.Patch(decode(t, operations))
+			require.NotNil(t, appErr, operations)
+			assert.Equal(t, expected, appErr.Id, operations)
+		}
+	})
+}
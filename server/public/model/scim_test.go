@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimUsernameToUsername(t *testing.T) {
+	for userName, expected := range map[string]string{
+		"bjensen":                    "bjensen",
+		"BJensen":                    "bjensen",
+		"barbara.jensen@example.com": "barbara.jensen",
+		"Barbara Jensen":             "barbara-jensen",
+		"b+jensen@example.com":       "b-jensen",
+		"1234":                       "",
+		"all":                        "",
+		"":                           "",
+	} {
+		assert.Equal(t, expected, ScimUsernameToUsername(userName), userName)
+	}
+}
+
+func TestScimUserApplyTo(t *testing.T) {
+	t.Run("user name is an email address", func(t *testing.T) {
+		user := &User{}
+		appErr := (&ScimUser{
+			UserName:   "Barbara.Jensen@Example.com",
+			ExternalId: "ext-1",
+			Name:       &ScimName{GivenName: "Barbara", FamilyName: "Jensen"},
+			Title:      "Tour Guide",
+		}).ApplyTo(user)
+		require.Nil(t, appErr)
+		assert.Equal(t, "barbara.jensen", user.Username)
+		assert.Equal(t, "barbara.jensen@example.com", user.Email)
+		assert.Equal(t, "Barbara", user.FirstName)
+		assert.Equal(t, "Jensen", user.LastName)
+		assert.Equal(t, "Tour Guide", user.Position)
+		assert.Equal(t, "ext-1", user.Props[UserPropsKeyScimExternalId])
+		assert.Equal(t, "Barbara.Jensen@Example.com", user.Props[UserPropsKeyScimUserName])
+
+		su := user.ToScimUser("http://localhost:8065")
+		assert.Equal(t, "Barbara.Jensen@Example.com", su.UserName)
+		assert.Equal(t, "ext-1", su.ExternalId)
+	})
+
+	t.Run("primary email is used", func(t *testing.T) {
+		user := &User{Props: StringMap{UserPropsKeyScimUserName: "old", UserPropsKeyScimExternalId: "old"}}
+		appErr := (&ScimUser{
+			UserName: "bjensen",
+			Emails: []*ScimMultiValue{
+				{Value: "babs@jensen.org", Type: "home"},
+				{Value: "bjensen@example.com", Type: "work", Primary: true},
+			},
+		}).ApplyTo(user)
+		require.Nil(t, appErr)
+		assert.Equal(t, "bjensen", user.Username)
+		assert.Equal(t, "bjensen@example.com", user.Email)
+		assert.NotContains(t, user.Props, UserPropsKeyScimUserName)
+		assert.NotContains(t, user.Props, UserPropsKeyScimExternalId)
+	})
+
+	t.Run("invalid user name", func(t *testing.T) {
+		appErr := (&ScimUser{UserName: "1234", Emails: []*ScimMultiValue{{Value: "bjensen@example.com"}}}).ApplyTo(&User{})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.scim.user.user_name.app_error", appErr.Id)
+	})
+
+	t.Run("missing email", func(t *testing.T) {
+		appErr := (&ScimUser{UserName: "bjensen"}).ApplyTo(&User{})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.scim.user.email.app_error", appErr.Id)
+	})
+}
+
+func TestUserToScimUser(t *testing.T) {
+	user := &User{
+		Id:        NewId(),
+		Username:  "bjensen",
+		Email:     "bjensen@example.com",
+		FirstName: "Barbara",
+		LastName:  "Jensen",
+		CreateAt:  1715575354000,
+		UpdateAt:  1715575354000,
+		DeleteAt:  1715575354000,
+	}
+
+	su := user.ToScimUser("http://localhost:8065")
+	assert.Equal(t, []string{ScimSchemaUser}, su.Schemas)
+	assert.Equal(t, "bjensen", su.UserName)
+	assert.Equal(t, "Barbara Jensen", su.DisplayName)
+	assert.Equal(t, "Barbara Jensen", su.Name.Formatted)
+	require.NotNil(t, su.Active)
+	assert.False(t, *su.Active)
+	require.Len(t, su.Emails, 1)
+	assert.Equal(t, "bjensen@example.com", su.Emails[0].Value)
+	assert.True(t, su.Emails[0].Primary)
+	assert.Equal(t, ScimResourceTypeUser, su.Meta.ResourceType)
+	assert.Equal(t, "2024-05-13T04:42:34Z", su.Meta.Created)
+	assert.Equal(t, "http://localhost:8065/scim/v2/Users/"+user.Id, su.Meta.Location)
+}
+
+func TestScimGroupApplyTo(t *testing.T) {
+	group := &Group{}
+	require.Nil(t, (&ScimGroup{DisplayName: "Engineering", ExternalId: "ext-1"}).ApplyTo(group))
+	assert.Equal(t, "Engineering", group.DisplayName)
+	assert.Equal(t, GroupSourceScim, group.Source)
+	assert.Equal(t, "ext-1", group.GetRemoteId())
+	assert.Nil(t, group.IsValidForCreate())
+
+	appErr := (&ScimGroup{}).ApplyTo(group)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.scim.group.display_name.app_error", appErr.Id)
+
+	appErr = (&ScimGroup{DisplayName: "Engineering", ExternalId: NewRandomString(GroupRemoteIDMaxLength + 1)}).ApplyTo(group)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.scim.group.external_id.app_error", appErr.Id)
+}
+
+func TestNewScimError(t *testing.T) {
+	scimErr := NewScimError(NewAppError("", "model.scim.filter.app_error", nil, "", http.StatusBadRequest))
+	assert.Equal(t, []string{ScimSchemaError}, scimErr.Schemas)
+	assert.Equal(t, "400", scimErr.Status)
+	assert.Equal(t, ScimErrorTypeInvalidFilter, scimErr.ScimType)
+
+	scimErr = NewScimError(NewAppError("", "app.user.save.username_exists.app_error", nil, "", http.StatusConflict))
+	assert.Equal(t, "409", scimErr.Status)
+	assert.Equal(t, ScimErrorTypeUniqueness, scimErr.ScimType)
+
+	scimErr = NewScimError(NewAppError("", "app.user.missing_account.const", nil, "", http.StatusNotFound))
+	assert.Equal(t, "404", scimErr.Status)
+	assert.Empty(t, scimErr.ScimType)
+}
+
+func TestScimPatchRequestIsValid(t *testing.T) {
+	operations := []*ScimPatchOperation{{Op: ScimPatchOpReplace, Path: "active", Value: false}}
+	assert.Nil(t, (&ScimPatchRequest{Schemas: []string{ScimSchemaPatchOp}, Operations: operations}).IsValid())
+	assert.NotNil(t, (&ScimPatchRequest{Operations: operations}).IsValid())
+	assert.NotNil(t, (&ScimPatchRequest{Schemas: []string{ScimSchemaPatchOp}}).IsValid())
+}
+
+func TestSplitScimGroupPatch(t *testing.T) {
+	userID1, userID2 := NewId(), NewId()
+
+	t.Run("separates the member operations", func(t *testing.T) {
+		attributeOps, memberOps, appErr := SplitScimGroupPatch([]*ScimPatchOperation{
+			{Op: "Add", Path: "members", Value: []any{map[string]any{"value": userID1}, map[string]any{"Value": userID2}}},
+			{Op: "replace", Path: "displayName", Value: "Developers"},
+			{Op: "remove", Path: `members[value eq "` + userID1 + `"]`},
+			{Op: "replace", Value: map[string]any{"displayName": "Engineers", "members": []any{map[string]any{"value": userID2}}}},
+			{Op: "remove", Path: "members", Value: []any{map[string]any{"value": userID2}}},
+			{Op: "remove", Path: "members"},
+		})
+		require.Nil(t, appErr)
+
+		require.Len(t, attributeOps, 2)
+		assert.Equal(t, "displayName", attributeOps[0].Path)
+		assert.Equal(t, map[string]any{"displayname": "Engineers"}, attributeOps[1].Value)
+
+		require.Len(t, memberOps, 5)
+		assert.Equal(t, &ScimGroupMemberOperation{Op: ScimPatchOpAdd, Ids: []string{userID1, userID2}}, memberOps[0])
+		assert.Equal(t, ScimPatchOpRemove, memberOps[1].Op)
+		assert.Equal(t, []string{userID1}, memberOps[1].SelectMembers([]string{userID1, userID2}))
+		assert.Equal(t, &ScimGroupMemberOperation{Op: ScimPatchOpReplace, Ids: []string{userID2}}, memberOps[2])
+		assert.Equal(t, []string{userID2}, memberOps[3].SelectMembers([]string{userID1, userID2}))
+		assert.Equal(t, []string{userID1, userID2}, memberOps[4].SelectMembers([]string{userID1, userID2}))
+	})
+
+	t.Run("invalid member operations", func(t *testing.T) {
+		for name, operation := range map[string]*ScimPatchOperation{
+			"missing value":   {Op: "add", Path: "members"},
+			"invalid member":  {Op: "add", Path: "members", Value: []any{map[string]any{"display": "john"}}},
+			"sub-attribute":   {Op: "replace", Path: "members.display", Value: "john"},
+			"filtered change": {Op: "replace", Path: `members[value eq "` + userID1 + `"]`, Value: map[string]any{"display": "john"}},
+			"invalid op":      {Op: "move", Path: "members", Value: []any{map[string]any{"value": userID1}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, _, appErr := SplitScimGroupPatch([]*ScimPatchOperation{operation})
+				assert.NotNil(t, appErr)
+			})
+		}
+	})
+}
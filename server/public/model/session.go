@@ -4,6 +4,7 @@
 package model
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"strings"
@@ -22,6 +23,7 @@ const (
 	SessionPropBrowser                    = "browser"
 	SessionPropType                       = "type"
 	SessionPropUserAccessTokenId          = "user_access_token_id"
+	SessionPropUserAccessTokenScope       = "user_access_token_scope"
 	SessionPropIsBot                      = "is_bot"
 	SessionPropIsBotValue                 = "true"
 	SessionPropOAuthAppID                 = "oauth_app_id"
@@ -32,6 +34,12 @@ const (
 	SessionTypeCloudKey                   = "CloudKey"
 	SessionTypeRemoteclusterToken         = "RemoteClusterToken"
 	SessionPropIsGuest                    = "is_guest"
+	SessionPropIPAddress                  = "ip_address"
+	SessionPropCountry                    = "country"
+	SessionPropDeviceFingerprint          = "device_fingerprint"
+	SessionClientTypeWeb                  = "web"
+	SessionClientTypeDesktop              = "desktop"
+	SessionClientTypeMobile               = "mobile"
 	SessionActivityTimeout                = 1000 * 60 * 5  // 5 minutes
 	SessionUserAccessTokenExpiryHours     = 100 * 365 * 24 // 100 years
 )
@@ -188,6 +196,43 @@ func (s *Session) IsMobile() bool {
 	return isMobile
 }
 
+// GetClientType returns whether the session was created by logging in from a web
+// browser, the desktop app or a mobile app, or an empty string for the sessions of
+// integrations.
+func (s *Session) GetClientType() string {
+	switch {
+	case s.IsIntegration():
+		return ""
+	case s.IsMobileApp():
+		return SessionClientTypeMobile
+	case strings.HasPrefix(s.Props[SessionPropBrowser], "Desktop App"):
+		return SessionClientTypeDesktop
+	default:
+		return SessionClientTypeWeb
+	}
+}
+
+// GetDeviceName returns a name for the device the session was created on, e.g. "Chrome
+// on Windows 10", for the user to recognize it.
+func (s *Session) GetDeviceName() string {
+	browser, _, _ := strings.Cut(s.Props[SessionPropBrowser], "/")
+	os := s.Props[SessionPropOs]
+	if os == "" {
+		os = s.Props[SessionPropPlatform]
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Unknown device"
+	}
+}
+
 func (s *Session) IsSaml() bool {
 	val, ok := s.Props[UserAuthServiceIsSaml]
 	if !ok {
@@ -236,6 +281,23 @@ func (s *Session) IsUserAccessToken() bool {
 	return false
 }
 
+// GetUserAccessTokenScope returns the scope of the user access token the session was
+// created for, or nil when the session is not restricted by a scope. A scope which cannot
+// be read restricts the session to nothing.
+func (s *Session) GetUserAccessTokenScope() *UserAccessTokenScope {
+	val, ok := s.Props[SessionPropUserAccessTokenScope]
+	if !ok || val == "" {
+		return nil
+	}
+
+	var scope UserAccessTokenScope
+	if err := json.Unmarshal([]byte(val), &scope); err != nil {
+		mlog.Warn("Error parsing the user access token scope of a session", mlog.String("session_id", s.Id), mlog.Err(err))
+		return &UserAccessTokenScope{Permissions: []string{""}, ChannelIds: []string{""}, AllowedIPs: []string{""}}
+	}
+	return &scope
+}
+
 // Returns true when session is authenticated as a bot, by personal access token, or is an OAuth app.
 // Does not indicate other forms of integrations e.g. webhooks, slash commands, etc.
 func (s *Session) IsIntegration() bool {
@@ -275,3 +337,33 @@ func (s *Session) ExpiresAt_() float64 {
 func (s *Session) LastActivityAt_() float64 {
 	return float64(s.LastActivityAt)
 }
+
+// ActiveSession describes a session of a user to the user, without its secrets, for them
+// to recognize the devices they are logged in on.
+type ActiveSession struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	ClientType     string `json:"client_type"`
+	IPAddress      string `json:"ip_address,omitempty"`
+	Country        string `json:"country,omitempty"`
+	CreateAt       int64  `json:"create_at"`
+	LastActivityAt int64  `json:"last_activity_at"`
+	ExpiresAt      int64  `json:"expires_at"`
+	IsCurrent      bool   `json:"is_current"`
+}
+
+// ToActiveSession returns the description of the session given to its user. The current
+// session is the one the description is requested with.
+func (s *Session) ToActiveSession(currentSessionID string) *ActiveSession {
+	return &ActiveSession{
+		Id:             s.Id,
+		Name:           s.GetDeviceName(),
+		ClientType:     s.GetClientType(),
+		IPAddress:      s.Props[SessionPropIPAddress],
+		Country:        s.Props[SessionPropCountry],
+		CreateAt:       s.CreateAt,
+		LastActivityAt: s.LastActivityAt,
+		ExpiresAt:      s.ExpiresAt,
+		IsCurrent:      s.Id == currentSessionID,
+	}
+}
@@ -153,3 +153,76 @@ func TestIsIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionGetUserAccessTokenScope(t *testing.T) {
+	session := Session{}
+	require.Nil(t, session.GetUserAccessTokenScope())
+
+	session.AddProp(SessionPropUserAccessTokenScope, `{"permissions":["create_post"],"allowed_ips":["10.0.0.1"]}`)
+	scope := session.GetUserAccessTokenScope()
+	require.NotNil(t, scope)
+	require.Equal(t, []string{PermissionCreatePost.Id}, scope.Permissions)
+	require.Equal(t, []string{"10.0.0.1"}, scope.AllowedIPs)
+
+	session.AddProp(SessionPropUserAccessTokenScope, "{")
+	scope = session.GetUserAccessTokenScope()
+	require.NotNil(t, scope)
+	require.False(t, scope.AllowsPermission(PermissionCreatePost.Id))
+	require.False(t, scope.AllowsChannel(NewId(), NewId()))
+	require.False(t, scope.AllowsIP("10.0.0.1"))
+}
+
+func TestSessionGetClientType(t *testing.T) {
+	session := Session{Props: StringMap{SessionPropBrowser: "Chrome/120.0"}}
+	assert.Equal(t, SessionClientTypeWeb, session.GetClientType())
+
+	session.Props[SessionPropBrowser] = "Desktop App/5.6.0"
+	assert.Equal(t, SessionClientTypeDesktop, session.GetClientType())
+
+	session.DeviceId = NewId()
+	assert.Equal(t, SessionClientTypeMobile, session.GetClientType())
+
+	session.AddProp(SessionPropType, SessionTypeUserAccessToken)
+	assert.Equal(t, "", session.GetClientType())
+}
+
+func TestSessionGetDeviceName(t *testing.T) {
+	session := Session{Props: StringMap{}}
+	assert.Equal(t, "Unknown device", session.GetDeviceName())
+
+	session.Props[SessionPropPlatform] = "Macintosh"
+	assert.Equal(t, "Macintosh", session.GetDeviceName())
+
+	session.Props[SessionPropOs] = "Mac OS"
+	session.Props[SessionPropBrowser] = "Safari/17.1"
+	assert.Equal(t, "Safari on Mac OS", session.GetDeviceName())
+}
+
+func TestSessionToActiveSession(t *testing.T) {
+	session := Session{
+		Id:             NewId(),
+		Token:          NewId(),
+		CreateAt:       1,
+		LastActivityAt: 2,
+		ExpiresAt:      3,
+		Props: StringMap{
+			SessionPropBrowser:   "Firefox/120.0",
+			SessionPropOs:        "Linux",
+			SessionPropIPAddress: "192.0.2.1",
+			SessionPropCountry:   "FR",
+		},
+	}
+
+	assert.Equal(t, &ActiveSession{
+		Id:             session.Id,
+		Name:           "Firefox on Linux",
+		ClientType:     SessionClientTypeWeb,
+		IPAddress:      "192.0.2.1",
+		Country:        "FR",
+		CreateAt:       1,
+		LastActivityAt: 2,
+		ExpiresAt:      3,
+		IsCurrent:      true,
+	}, session.ToActiveSession(session.Id))
+	assert.False(t, session.ToActiveSession(NewId()).IsCurrent)
+}
@@ -12,6 +12,8 @@ const (
 	MaxTokenExipryTime = 1000 * 60 * 60 * 48 // 48 hour
 	TokenTypeOAuth     = "oauth"
 	TokenTypeSaml      = "saml"
+
+	TokenTypeWebAuthnChallenge = "webauthn_challenge"
 )
 
 type Token struct {
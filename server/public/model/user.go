@@ -109,6 +109,10 @@ type User struct {
 	TermsOfServiceCreateAt int64     `json:"terms_of_service_create_at,omitempty"`
 	DisableWelcomeEmail    bool      `json:"disable_welcome_email"`
 	LastLogin              int64     `json:"last_login,omitempty"`
+
+	// passwordHashed is set once the password of a user not saved yet was hashed with
+	// the password settings, so that PreSave doesn't hash it again.
+	passwordHashed bool
 }
 
 func (u *User) Auditable() map[string]interface{} {
@@ -234,6 +238,8 @@ type UserForIndexing struct {
 	DeleteAt    int64    `json:"delete_at"`
 	TeamsIds    []string `json:"team_id"`
 	ChannelsIds []string `json:"channel_id"`
+	// CustomAttributes are the values of the custom profile fields visible to everyone.
+	CustomAttributes []string `json:"custom_attributes"`
 }
 
 //msgp:ignore ViewUsersRestrictions
@@ -493,17 +499,14 @@ func (u *User) PreSave() *AppError {
 		u.Timezone = timezones.DefaultUserTimezone()
 	}
 
-	if u.Password != "" {
+	if u.Password != "" && !u.passwordHashed {
 		hashed, err := HashPassword(u.Password)
-		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
-			return NewAppError("User.PreSave", "model.user.pre_save.password_too_long.app_error",
-				nil, "user_id="+u.Id, http.StatusBadRequest).Wrap(err)
-		} else if err != nil {
-			return NewAppError("User.PreSave", "model.user.pre_save.password_hash.app_error",
-				nil, "user_id="+u.Id, http.StatusBadRequest).Wrap(err)
+		if err != nil {
+			return passwordHashError("User.PreSave", u.Id, err)
 		}
 		u.Password = hashed
 	}
+	u.passwordHashed = false
 
 	cs := u.GetCustomStatus()
 	if cs != nil {
@@ -514,6 +517,32 @@ func (u *User) PreSave() *AppError {
 	return nil
 }
 
+// HashPassword replaces the password of a user not saved yet with its hash, computed
+// with the algorithm of the password settings rather than the default one of PreSave.
+func (u *User) HashPassword(settings *PasswordSettings) *AppError {
+	if u.Password == "" || u.passwordHashed {
+		return nil
+	}
+
+	hashed, err := HashPasswordWithSettings(u.Password, settings)
+	if err != nil {
+		return passwordHashError("User.HashPassword", u.Id, err)
+	}
+	u.Password = hashed
+	u.passwordHashed = true
+
+	return nil
+}
+
+func passwordHashError(where, userID string, err error) *AppError {
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return NewAppError(where, "model.user.pre_save.password_too_long.app_error",
+			nil, "user_id="+userID, http.StatusBadRequest).Wrap(err)
+	}
+	return NewAppError(where, "model.user.pre_save.password_hash.app_error",
+		nil, "user_id="+userID, http.StatusBadRequest).Wrap(err)
+}
+
 // PreUpdate should be run before updating the user in the db.
 func (u *User) PreUpdate() {
 	u.Username = SanitizeUnicode(u.Username)
@@ -882,7 +911,8 @@ func (u *User) IsOAuthUser() bool {
 	return u.AuthService == ServiceGitlab ||
 		u.AuthService == ServiceGoogle ||
 		u.AuthService == ServiceOffice365 ||
-		u.AuthService == ServiceOpenid
+		u.AuthService == ServiceOpenid ||
+		IsOpenIdProviderService(u.AuthService)
 }
 
 func (u *User) IsLDAPUser() bool {
@@ -961,7 +991,8 @@ func (u *UserPatch) SetField(fieldName string, fieldValue string) {
 	}
 }
 
-// HashPassword generates a hash using the bcrypt.GenerateFromPassword
+// HashPassword generates a hash using the bcrypt.GenerateFromPassword. The hashes of the
+// other algorithms are generated by HashPasswordWithSettings.
 func HashPassword(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), 10)
 	if err != nil {
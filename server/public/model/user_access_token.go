@@ -4,15 +4,37 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"net"
 	"net/http"
+	"slices"
+
+	"github.com/pkg/errors"
 )
 
+const UserAccessTokenScopeMaxEntries = 100
+
 type UserAccessToken struct {
-	Id          string `json:"id"`
-	Token       string `json:"token,omitempty"`
-	UserId      string `json:"user_id"`
-	Description string `json:"description"`
-	IsActive    bool   `json:"is_active"`
+	Id          string                `json:"id"`
+	Token       string                `json:"token,omitempty"`
+	UserId      string                `json:"user_id"`
+	Description string                `json:"description"`
+	IsActive    bool                  `json:"is_active"`
+	Scope       *UserAccessTokenScope `json:"scope,omitempty"`
+	ExpiresAt   int64                 `json:"expires_at,omitempty"`
+}
+
+// UserAccessTokenScope restricts a user access token to a part of the power of its user.
+// Each list that is not empty restricts the token to its entries: the permissions the
+// token can exercise, the teams and channels it can act in, and the IP addresses or CIDR
+// ranges it can be used from. A token restricted to some permissions, teams or channels
+// cannot act on the account of its user either.
+type UserAccessTokenScope struct {
+	Permissions []string `json:"permissions,omitempty"`
+	TeamIds     []string `json:"team_ids,omitempty"`
+	ChannelIds  []string `json:"channel_ids,omitempty"`
+	AllowedIPs  []string `json:"allowed_ips,omitempty"`
 }
 
 func (t *UserAccessToken) IsValid() *AppError {
@@ -32,10 +54,148 @@ func (t *UserAccessToken) IsValid() *AppError {
 		return NewAppError("UserAccessToken.IsValid", "model.user_access_token.is_valid.description.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if t.ExpiresAt < 0 {
+		return NewAppError("UserAccessToken.IsValid", "model.user_access_token.is_valid.expires_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if t.Scope != nil {
+		if appErr := t.Scope.IsValid(); appErr != nil {
+			return appErr
+		}
+	}
+
 	return nil
 }
 
+// IsExpired returns whether the token has an expiry date which has passed.
+func (t *UserAccessToken) IsExpired() bool {
+	return t.ExpiresAt != 0 && t.ExpiresAt <= GetMillis()
+}
+
 func (t *UserAccessToken) PreSave() {
 	t.Id = NewId()
 	t.IsActive = true
 }
+
+func (s *UserAccessTokenScope) IsValid() *AppError {
+	if len(s.Permissions) > UserAccessTokenScopeMaxEntries || len(s.TeamIds) > UserAccessTokenScopeMaxEntries ||
+		len(s.ChannelIds) > UserAccessTokenScopeMaxEntries || len(s.AllowedIPs) > UserAccessTokenScopeMaxEntries {
+		return NewAppError("UserAccessTokenScope.IsValid", "model.user_access_token.is_valid.scope_too_large.app_error", map[string]any{"Max": UserAccessTokenScopeMaxEntries}, "", http.StatusBadRequest)
+	}
+
+	for _, permissionID := range s.Permissions {
+		if !slices.ContainsFunc(AllPermissions, func(p *Permission) bool { return p.Id == permissionID }) {
+			return NewAppError("UserAccessTokenScope.IsValid", "model.user_access_token.is_valid.scope_permission.app_error", map[string]any{"Permission": permissionID}, "", http.StatusBadRequest)
+		}
+	}
+
+	for _, teamID := range s.TeamIds {
+		if !IsValidId(teamID) {
+			return NewAppError("UserAccessTokenScope.IsValid", "model.user_access_token.is_valid.scope_team_id.app_error", nil, "team_id="+teamID, http.StatusBadRequest)
+		}
+	}
+
+	for _, channelID := range s.ChannelIds {
+		if !IsValidId(channelID) {
+			return NewAppError("UserAccessTokenScope.IsValid", "model.user_access_token.is_valid.scope_channel_id.app_error", nil, "channel_id="+channelID, http.StatusBadRequest)
+		}
+	}
+
+	for _, allowedIP := range s.AllowedIPs {
+		if _, _, err := net.ParseCIDR(allowedIP); err != nil && net.ParseIP(allowedIP) == nil {
+			return NewAppError("UserAccessTokenScope.IsValid", "model.user_access_token.is_valid.scope_allowed_ip.app_error", map[string]any{"IP": allowedIP}, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// IsEmpty returns whether the scope does not restrict anything.
+func (s *UserAccessTokenScope) IsEmpty() bool {
+	return len(s.Permissions) == 0 && len(s.TeamIds) == 0 && len(s.ChannelIds) == 0 && len(s.AllowedIPs) == 0
+}
+
+func (s *UserAccessTokenScope) AllowsPermission(permissionID string) bool {
+	return len(s.Permissions) == 0 || slices.Contains(s.Permissions, permissionID)
+}
+
+// AllowsSystemPermission returns whether the token can use a permission outside of a team
+// or channel, e.g. to manage the system. A token restricted to teams or channels only can
+// when the permission is listed explicitly.
+func (s *UserAccessTokenScope) AllowsSystemPermission(permissionID string) bool {
+	if s.RestrictsChannels() {
+		return slices.Contains(s.Permissions, permissionID)
+	}
+	return s.AllowsPermission(permissionID)
+}
+
+// AllowsUserAccount returns whether the token can act on the account of its user, e.g.
+// update its profile or preferences, or list its flagged posts across channels.
+func (s *UserAccessTokenScope) AllowsUserAccount() bool {
+	return len(s.Permissions) == 0 && !s.RestrictsChannels()
+}
+
+// RestrictsChannels returns whether the token is restricted to some teams or channels,
+// in which case the results spanning channels must be filtered with AllowsChannel.
+func (s *UserAccessTokenScope) RestrictsChannels() bool {
+	return len(s.TeamIds) > 0 || len(s.ChannelIds) > 0
+}
+
+func (s *UserAccessTokenScope) AllowsTeam(teamID string) bool {
+	return len(s.TeamIds) == 0 || slices.Contains(s.TeamIds, teamID)
+}
+
+// AllowsChannel returns whether the token can act in a channel of a team, or in a direct
+// or group message channel when teamID is empty. A token restricted to channels is only
+// allowed in these, and one restricted to teams in the channels of these.
+func (s *UserAccessTokenScope) AllowsChannel(channelID, teamID string) bool {
+	if len(s.ChannelIds) > 0 {
+		return slices.Contains(s.ChannelIds, channelID)
+	}
+	return len(s.TeamIds) == 0 || slices.Contains(s.TeamIds, teamID)
+}
+
+func (s *UserAccessTokenScope) AllowsIP(ipAddress string) bool {
+	if len(s.AllowedIPs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+
+	for _, allowedIP := range s.AllowedIPs {
+		if _, ipNet, err := net.ParseCIDR(allowedIP); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(allowedIP); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s UserAccessTokenScope) Value() (driver.Value, error) {
+	j, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(j), nil
+}
+
+func (s *UserAccessTokenScope) Scan(value any) error {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return errors.New("received value is neither a byte slice nor string")
+}
@@ -30,3 +30,127 @@ func TestUserAccessTokenIsValid(t *testing.T) {
 	appErr = ad.IsValid()
 	require.False(t, appErr == nil || appErr.Id != "model.user_access_token.is_valid.description.app_error")
 }
+
+func TestUserAccessTokenScopeIsValid(t *testing.T) {
+	token := UserAccessToken{Id: NewId(), Token: NewId(), UserId: NewId()}
+
+	token.ExpiresAt = -1
+	appErr := token.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.user_access_token.is_valid.expires_at.app_error", appErr.Id)
+	token.ExpiresAt = 0
+
+	for name, tc := range map[string]struct {
+		Scope         UserAccessTokenScope
+		ExpectedError string
+	}{
+		"valid": {
+			Scope: UserAccessTokenScope{
+				Permissions: []string{PermissionCreatePost.Id},
+				TeamIds:     []string{NewId()},
+				ChannelIds:  []string{NewId()},
+				AllowedIPs:  []string{"10.0.0.1", "192.168.0.0/16", "::1"},
+			},
+		},
+		"unknown permission": {
+			Scope:         UserAccessTokenScope{Permissions: []string{"fly"}},
+			ExpectedError: "model.user_access_token.is_valid.scope_permission.app_error",
+		},
+		"invalid team id": {
+			Scope:         UserAccessTokenScope{TeamIds: []string{"team"}},
+			ExpectedError: "model.user_access_token.is_valid.scope_team_id.app_error",
+		},
+		"invalid channel id": {
+			Scope:         UserAccessTokenScope{ChannelIds: []string{"channel"}},
+			ExpectedError: "model.user_access_token.is_valid.scope_channel_id.app_error",
+		},
+		"invalid ip": {
+			Scope:         UserAccessTokenScope{AllowedIPs: []string{"10.0.0.300"}},
+			ExpectedError: "model.user_access_token.is_valid.scope_allowed_ip.app_error",
+		},
+		"too many entries": {
+			Scope:         UserAccessTokenScope{AllowedIPs: make([]string, UserAccessTokenScopeMaxEntries+1)},
+			ExpectedError: "model.user_access_token.is_valid.scope_too_large.app_error",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			token.Scope = &tc.Scope
+			appErr := token.IsValid()
+			if tc.ExpectedError == "" {
+				require.Nil(t, appErr)
+			} else {
+				require.NotNil(t, appErr)
+				require.Equal(t, tc.ExpectedError, appErr.Id)
+			}
+		})
+	}
+}
+
+func TestUserAccessTokenIsExpired(t *testing.T) {
+	token := UserAccessToken{}
+	require.False(t, token.IsExpired())
+
+	token.ExpiresAt = GetMillis() + 60*1000
+	require.False(t, token.IsExpired())
+
+	token.ExpiresAt = GetMillis() - 1
+	require.True(t, token.IsExpired())
+}
+
+func TestUserAccessTokenScopeAllows(t *testing.T) {
+	teamID := NewId()
+	channelID := NewId()
+
+	t.Run("empty scope", func(t *testing.T) {
+		scope := UserAccessTokenScope{}
+		require.True(t, scope.IsEmpty())
+		require.True(t, scope.AllowsPermission(PermissionManageSystem.Id))
+		require.True(t, scope.AllowsSystemPermission(PermissionManageSystem.Id))
+		require.True(t, scope.AllowsUserAccount())
+		require.False(t, scope.RestrictsChannels())
+		require.True(t, scope.AllowsTeam(NewId()))
+		require.True(t, scope.AllowsChannel(NewId(), ""))
+		require.True(t, scope.AllowsIP("1.2.3.4"))
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		scope := UserAccessTokenScope{Permissions: []string{PermissionCreatePost.Id}}
+		require.True(t, scope.AllowsPermission(PermissionCreatePost.Id))
+		require.False(t, scope.AllowsPermission(PermissionManageSystem.Id))
+		require.True(t, scope.AllowsSystemPermission(PermissionCreatePost.Id))
+		require.False(t, scope.AllowsSystemPermission(PermissionManageSystem.Id))
+		require.False(t, scope.AllowsUserAccount())
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		scope := UserAccessTokenScope{TeamIds: []string{teamID}}
+		require.True(t, scope.AllowsTeam(teamID))
+		require.False(t, scope.AllowsTeam(NewId()))
+		require.True(t, scope.AllowsChannel(NewId(), teamID))
+		require.False(t, scope.AllowsChannel(NewId(), NewId()))
+		require.False(t, scope.AllowsChannel(NewId(), ""))
+		require.False(t, scope.AllowsSystemPermission(PermissionManageSystem.Id))
+		require.False(t, scope.AllowsUserAccount())
+		require.True(t, scope.RestrictsChannels())
+	})
+
+	t.Run("channels", func(t *testing.T) {
+		scope := UserAccessTokenScope{TeamIds: []string{teamID}, ChannelIds: []string{channelID}}
+		require.True(t, scope.AllowsChannel(channelID, teamID))
+		require.False(t, scope.AllowsChannel(NewId(), teamID))
+		require.False(t, scope.AllowsSystemPermission(PermissionManageSystem.Id))
+		require.False(t, scope.AllowsUserAccount())
+		require.True(t, scope.RestrictsChannels())
+
+		scope.Permissions = []string{PermissionManageSystem.Id}
+		require.True(t, scope.AllowsSystemPermission(PermissionManageSystem.Id))
+	})
+
+	t.Run("allowed ips", func(t *testing.T) {
+		scope := UserAccessTokenScope{AllowedIPs: []string{"10.0.0.1", "192.168.0.0/16"}}
+		require.True(t, scope.AllowsIP("10.0.0.1"))
+		require.True(t, scope.AllowsIP("192.168.1.20"))
+		require.False(t, scope.AllowsIP("10.0.0.2"))
+		require.False(t, scope.AllowsIP(""))
+	})
+}
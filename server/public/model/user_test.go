@@ -212,6 +212,17 @@ func TestUserPreSavePwdTooLong(t *testing.T) {
 	assert.ErrorIs(t, err, bcrypt.ErrPasswordTooLong)
 }
 
+func TestUserHashPassword(t *testing.T) {
+	user := User{Password: "password"}
+	require.Nil(t, user.HashPassword(newTestPasswordSettings(PasswordHashAlgorithmArgon2id)))
+	assert.True(t, strings.HasPrefix(user.Password, "$argon2id$"))
+
+	hash := user.Password
+	require.Nil(t, user.PreSave())
+	assert.Equal(t, hash, user.Password, "PreSave must not hash the password again")
+	require.NoError(t, ComparePasswordHash(user.Password, "password"))
+}
+
 func TestUserPreUpdate(t *testing.T) {
 	user := User{Password: "test"}
 	user.PreUpdate()
@@ -0,0 +1,205 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxWebAuthnCredentialsPerUser  = 20
+	WebAuthnCredentialNameMaxRunes = 64
+	// WebAuthnCredentialIdMaxLength bounds the size of the credential ids, in bytes. The
+	// specification allows up to 1023 bytes, authenticators use much shorter ids.
+	WebAuthnCredentialIdMaxLength = 255
+	WebAuthnTimeout               = 1000 * 60 * 5 // 5 minutes
+
+	WebAuthnCeremonyRegistration = "registration"
+	WebAuthnCeremonyLogin        = "login"
+
+	WebAuthnCredentialTypePublicKey = "public-key"
+)
+
+// WebAuthnCredential is a public key credential registered by a user with a WebAuthn
+// authenticator, such as a security key or a passkey.
+type WebAuthnCredential struct {
+	Id     string `json:"id"`
+	UserId string `json:"user_id"`
+	Name   string `json:"name"`
+	// CredentialId is the identifier the authenticator assigned to the credential,
+	// base64url encoded without padding.
+	CredentialId string `json:"credential_id"`
+	// PublicKey is the COSE_Key encoding of the credential public key.
+	PublicKey []byte `json:"-"`
+	SignCount int64  `json:"-"`
+	// AAGUID identifies the model of the authenticator, hex encoded.
+	AAGUID     string `json:"aaguid"`
+	CreateAt   int64  `json:"create_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+}
+
+func (c *WebAuthnCredential) Auditable() map[string]any {
+	return map[string]any{
+		"id":            c.Id,
+		"user_id":       c.UserId,
+		"name":          c.Name,
+		"credential_id": c.CredentialId,
+		"aaguid":        c.AAGUID,
+		"create_at":     c.CreateAt,
+		"last_used_at":  c.LastUsedAt,
+	}
+}
+
+func (c *WebAuthnCredential) PreSave() {
+	if c.Id == "" {
+		c.Id = NewId()
+	}
+
+	c.Name = strings.TrimSpace(c.Name)
+	c.CreateAt = GetMillis()
+}
+
+func (c *WebAuthnCredential) IsValid() *AppError {
+	if !IsValidId(c.Id) {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(c.UserId) {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.user_id.app_error", nil, "id="+c.Id, http.StatusBadRequest)
+	}
+
+	if c.CreateAt == 0 {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.create_at.app_error", nil, "id="+c.Id, http.StatusBadRequest)
+	}
+
+	if c.Name == "" || utf8.RuneCountInString(c.Name) > WebAuthnCredentialNameMaxRunes {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.name.app_error", map[string]any{"MaxLength": WebAuthnCredentialNameMaxRunes}, "id="+c.Id, http.StatusBadRequest)
+	}
+
+	if id, err := base64.RawURLEncoding.DecodeString(c.CredentialId); err != nil || len(id) == 0 || len(id) > WebAuthnCredentialIdMaxLength {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.credential_id.app_error", nil, "id="+c.Id, http.StatusBadRequest)
+	}
+
+	if len(c.PublicKey) == 0 {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.public_key.app_error", nil, "id="+c.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// WebAuthnRelyingPartyEntity, WebAuthnUserEntity and the other types below follow the
+// JSON serialization of the Web Authentication API options and responses, so that they
+// can be passed as is to PublicKeyCredential.parseCreationOptionsFromJSON and
+// PublicKeyCredential.parseRequestOptionsFromJSON, and read from PublicKeyCredential.toJSON.
+
+type WebAuthnRelyingPartyEntity struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebAuthnUserEntity struct {
+	// Id is the user handle, the base64url encoded user id.
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type WebAuthnCredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type WebAuthnCredentialDescriptor struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// WebAuthnCreationOptions are the options of a credential registration ceremony.
+type WebAuthnCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     WebAuthnRelyingPartyEntity     `json:"rp"`
+	User                   WebAuthnUserEntity             `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+// WebAuthnRequestOptions are the options of an authentication ceremony.
+type WebAuthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	Timeout          int64                          `json:"timeout"`
+	RPId             string                         `json:"rpId"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+type WebAuthnAttestationResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+}
+
+// WebAuthnRegistrationCredential is the credential returned by the authenticator at
+// the end of a registration ceremony.
+type WebAuthnRegistrationCredential struct {
+	Id       string                      `json:"id"`
+	RawId    string                      `json:"rawId"`
+	Type     string                      `json:"type"`
+	Response WebAuthnAttestationResponse `json:"response"`
+}
+
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// WebAuthnAssertionCredential is the credential returned by the authenticator at the
+// end of an authentication ceremony. Its JSON serialization is also accepted as the
+// MFA token when logging in.
+type WebAuthnAssertionCredential struct {
+	Id       string                    `json:"id"`
+	RawId    string                    `json:"rawId"`
+	Type     string                    `json:"type"`
+	Response WebAuthnAssertionResponse `json:"response"`
+}
+
+// IsWebAuthnAssertion returns whether an MFA token holds a WebAuthn assertion rather
+// than a one-time password or a recovery code.
+func IsWebAuthnAssertion(token string) bool {
+	return strings.HasPrefix(strings.TrimSpace(token), "{")
+}
+
+// WebAuthnRegistrationRequest completes the registration of an authenticator.
+type WebAuthnRegistrationRequest struct {
+	Name       string                          `json:"name"`
+	Credential *WebAuthnRegistrationCredential `json:"credential"`
+}
+
+// WebAuthnRegistrationResult is returned when an authenticator is registered. The
+// recovery codes are only set when the authenticator is the first MFA method of the
+// user, they cannot be retrieved afterwards.
+type WebAuthnRegistrationResult struct {
+	Credential    *WebAuthnCredential `json:"credential"`
+	RecoveryCodes []string            `json:"recovery_codes,omitempty"`
+}
+
+type WebAuthnLoginOptionsRequest struct {
+	LoginId string `json:"login_id"`
+}
+
+type WebAuthnLoginRequest struct {
+	DeviceId   string                       `json:"device_id"`
+	Credential *WebAuthnAssertionCredential `json:"credential"`
+}
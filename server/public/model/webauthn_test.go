@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAuthnCredentialIsValid(t *testing.T) {
+	newCredential := func() *WebAuthnCredential {
+		credential := &WebAuthnCredential{
+			UserId:       NewId(),
+			Name:         " Security key ",
+			CredentialId: base64.RawURLEncoding.EncodeToString([]byte("credential")),
+			PublicKey:    []byte{0xa1, 0x01, 0x02},
+		}
+		credential.PreSave()
+		return credential
+	}
+
+	credential := newCredential()
+	assert.Equal(t, "Security key", credential.Name)
+	require.Nil(t, credential.IsValid())
+
+	for name, update := range map[string]func(c *WebAuthnCredential){
+		"invalid id":           func(c *WebAuthnCredential) { c.Id = "" },
+		"invalid user id":      func(c *WebAuthnCredential) { c.UserId = "user" },
+		"missing create at":    func(c *WebAuthnCredential) { c.CreateAt = 0 },
+		"empty name":           func(c *WebAuthnCredential) { c.Name = "" },
+		"long name":            func(c *WebAuthnCredential) { c.Name = strings.Repeat("a", WebAuthnCredentialNameMaxRunes+1) },
+		"empty credential id":  func(c *WebAuthnCredential) { c.CredentialId = "" },
+		"padded credential id": func(c *WebAuthnCredential) { c.CredentialId = "YQ==" },
+		"missing public key":   func(c *WebAuthnCredential) { c.PublicKey = nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			credential := newCredential()
+			update(credential)
+			require.NotNil(t, credential.IsValid())
+		})
+	}
+}
+
+func TestIsWebAuthnAssertion(t *testing.T) {
+	assert.True(t, IsWebAuthnAssertion(` {"id": "abc"}`))
+	assert.False(t, IsWebAuthnAssertion("123456"))
+	assert.False(t, IsWebAuthnAssertion("abcde-fghij"))
+}
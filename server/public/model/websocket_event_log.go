@@ -0,0 +1,16 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// WebSocketEventLogEntry is a WebSocket event kept in the event log, so that
+// clients can resume from it after reconnecting to any node.
+type WebSocketEventLogEntry struct {
+	Seq      int64
+	CreateAt int64
+	// UserId is the user the event is broadcast to, or empty when the event
+	// is broadcast to a channel, a team or everyone.
+	UserId string
+	// Event is the JSON of the event, including its broadcast hooks.
+	Event []byte
+}
@@ -45,6 +45,7 @@ const (
 	WebsocketEventEphemeralMessage                    WebsocketEventType = "ephemeral_message"
 	WebsocketEventStatusChange                        WebsocketEventType = "status_change"
 	WebsocketEventHello                               WebsocketEventType = "hello"
+	WebsocketEventResyncRequired                      WebsocketEventType = "resync_required"
 	WebsocketAuthenticationChallenge                  WebsocketEventType = "authentication_challenge"
 	WebsocketEventReactionAdded                       WebsocketEventType = "reaction_added"
 	WebsocketEventReactionRemoved                     WebsocketEventType = "reaction_removed"
@@ -190,6 +191,8 @@ type webSocketEventJSON struct {
 	Data      map[string]any      `json:"data"`
 	Broadcast *WebsocketBroadcast `json:"broadcast"`
 	Sequence  int64               `json:"seq"`
+	// LogSequence is the position of the event in the WebSocket event log, when enabled.
+	LogSequence int64 `json:"log_seq,omitempty"`
 }
 
 type WebSocketEvent struct {
@@ -197,6 +200,7 @@ type WebSocketEvent struct {
 	data            map[string]any
 	broadcast       *WebsocketBroadcast
 	sequence        int64
+	logSequence     int64
 	precomputedJSON *precomputedWebSocketEventJSON
 }
 
@@ -264,6 +268,7 @@ func (ev *WebSocketEvent) Copy() *WebSocketEvent {
 		data:            ev.data,
 		broadcast:       ev.broadcast,
 		sequence:        ev.sequence,
+		logSequence:     ev.logSequence,
 		precomputedJSON: ev.precomputedJSON,
 	}
 	return evCopy
@@ -275,6 +280,7 @@ func (ev *WebSocketEvent) DeepCopy() *WebSocketEvent {
 		data:            maps.Clone(ev.data),
 		broadcast:       ev.broadcast.copy(),
 		sequence:        ev.sequence,
+		logSequence:     ev.logSequence,
 		precomputedJSON: ev.precomputedJSON.copy(),
 	}
 	return evCopy
@@ -316,6 +322,18 @@ func (ev *WebSocketEvent) SetSequence(seq int64) *WebSocketEvent {
 	return evCopy
 }
 
+// GetLogSequence returns the position of the event in the WebSocket event log,
+// or zero if the event wasn't logged.
+func (ev *WebSocketEvent) GetLogSequence() int64 {
+	return ev.logSequence
+}
+
+func (ev *WebSocketEvent) SetLogSequence(seq int64) *WebSocketEvent {
+	evCopy := ev.Copy()
+	evCopy.logSequence = seq
+	return evCopy
+}
+
 func (ev *WebSocketEvent) IsValid() bool {
 	return ev.event != ""
 }
@@ -333,6 +351,7 @@ func (ev *WebSocketEvent) ToJSON() ([]byte, error) {
 		ev.data,
 		ev.broadcast,
 		ev.sequence,
+		ev.logSequence,
 	})
 }
 
@@ -348,12 +367,17 @@ func (ev *WebSocketEvent) Encode(enc *json.Encoder, buf io.Writer) error {
 		ev.data,
 		ev.broadcast,
 		ev.sequence,
+		ev.logSequence,
 	})
 }
 
 // We write optimal code here sacrificing readability for
 // performance.
 func (ev *WebSocketEvent) precomputedJSONBuf() []byte {
+	var logSeq string
+	if ev.logSequence != 0 {
+		logSeq = `, "log_seq": ` + strconv.FormatInt(ev.logSequence, 10)
+	}
 	return []byte(`{"event": ` +
 		string(ev.precomputedJSON.Event) +
 		`, "data": ` +
@@ -362,6 +386,7 @@ func (ev *WebSocketEvent) precomputedJSONBuf() []byte {
 		string(ev.precomputedJSON.Broadcast) +
 		`, "seq": ` +
 		strconv.Itoa(int(ev.sequence)) +
+		logSeq +
 		`}`)
 }
 
@@ -389,6 +414,7 @@ func WebSocketEventFromJSON(data io.Reader) (*WebSocketEvent, error) {
 	ev.data = o.Data
 	ev.broadcast = o.Broadcast
 	ev.sequence = o.Sequence
+	ev.logSequence = o.LogSequence
 	return &ev, nil
 }
 
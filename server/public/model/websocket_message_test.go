@@ -91,6 +91,22 @@ func TestWebSocketEventFromJSON(t *testing.T) {
 	require.Equal(t, ev.GetBroadcast(), &WebsocketBroadcast{UserId: "userid"})
 }
 
+func TestWebSocketEventLogSequence(t *testing.T) {
+	event := NewWebSocketEvent(WebsocketEventPosted, "foo", "bar", "baz", nil, "")
+	data, err := event.ToJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "log_seq")
+
+	event = event.SetLogSequence(42)
+	for _, ev := range []*WebSocketEvent{event, event.PrecomputeJSON()} {
+		data, err = ev.ToJSON()
+		require.NoError(t, err)
+		parsed, err := WebSocketEventFromJSON(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), parsed.GetLogSequence())
+	}
+}
+
 func TestWebSocketResponse(t *testing.T) {
 	m := NewWebSocketResponse("OK", 1, map[string]any{})
 	e := NewWebSocketError(1, &AppError{})
@@ -12,4 +12,7 @@ type Context struct {
 	IPAddress      string
 	AcceptLanguage string
 	UserAgent      string
+	// TraceParent is the W3C trace context of the request span, if the request is traced,
+	// so that the spans of the hook continue the trace of the request.
+	TraceParent string
 }
@@ -181,6 +181,21 @@ func FieldListToRecordSuccess(structPrefix string, fieldList *ast.FieldList) str
 	return fmt.Sprintf("%s == nil", result)
 }
 
+// FieldListToContextName returns the name of the *Context parameter, or nil if there is none.
+func FieldListToContextName(fieldList *ast.FieldList) string {
+	if fieldList == nil {
+		return "nil"
+	}
+	for _, field := range fieldList.List {
+		if star, ok := field.Type.(*ast.StarExpr); ok {
+			if ident, ok := star.X.(*ast.Ident); ok && ident.Name == "Context" && len(field.Names) > 0 {
+				return field.Names[0].Name
+			}
+		}
+	}
+	return "nil"
+}
+
 func FieldListToStructList(fieldList *ast.FieldList, fileset *token.FileSet) string {
 	result := []string{}
 	if fieldList == nil || len(fieldList.List) == 0 {
@@ -441,9 +456,13 @@ type hooksTimerLayer struct {
 	metrics   metricsInterface
 }
 
-func (hooks *hooksTimerLayer) recordTime(startTime timePkg.Time, name string, success bool) {
+func (hooks *hooksTimerLayer) recordTime(c *Context, startTime timePkg.Time, name string, success bool) {
 	if hooks.metrics != nil {
 		elapsedTime := float64(timePkg.Since(startTime)) / float64(timePkg.Second)
+		if metrics, ok := hooks.metrics.(contextMetricsInterface); ok && c != nil {
+			metrics.ObservePluginHookDurationWithContext(c, hooks.pluginID, name, success, elapsedTime)
+			return
+		}
 		hooks.metrics.ObservePluginHookDuration(hooks.pluginID, name, success, elapsedTime)
 	}
 }
@@ -453,7 +472,7 @@ func (hooks *hooksTimerLayer) recordTime(startTime timePkg.Time, name string, su
 func (hooks *hooksTimerLayer) {{.Name}}{{funcStyle .Params}} {{funcStyle .Return}} {
 	startTime := timePkg.Now()
 	{{ if .Return }} {{destruct "_returns" .Return}} := {{ end }} hooks.hooksImpl.{{.Name}}({{valuesOnly .Params}})
-	hooks.recordTime(startTime, "{{.Name}}", {{ shouldRecordSuccess "_returns" .Return }})
+	hooks.recordTime({{contextName .Params}}, startTime, "{{.Name}}", {{ shouldRecordSuccess "_returns" .Return }})
 	{{ if .Return }} return {{destruct "_returns" .Return}} {{end -}}
 }
 
@@ -537,6 +556,7 @@ func generatePluginTimerLayer(info *PluginInterfaceInfo) {
 		"shouldRecordSuccess": func(structPrefix string, fields *ast.FieldList) string {
 			return FieldListToRecordSuccess(structPrefix, fields)
 		},
+		"contextName": func(fields *ast.FieldList) string { return FieldListToContextName(fields) },
 	}
 
 	// Prepare template params
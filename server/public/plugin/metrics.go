@@ -9,3 +9,9 @@ type metricsInterface interface {
 	ObservePluginMultiHookDuration(elapsed float64)
 	ObservePluginAPIDuration(pluginID, apiName string, success bool, elapsed float64)
 }
+
+// contextMetricsInterface is optionally implemented by the metrics to observe the hooks
+// called with a context along with it, e.g. to continue the trace of the request.
+type contextMetricsInterface interface {
+	ObservePluginHookDurationWithContext(c *Context, pluginID, hookName string, success bool, elapsed float64)
+}
@@ -0,0 +1,421 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mlog
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/logr/v2"
+	"github.com/mattermost/logr/v2/targets"
+)
+
+const (
+	DefaultExporterBatchSize           = 100
+	DefaultExporterFlushIntervalMillis = 1000
+	DefaultExporterMaxBufferSize       = 10000
+	DefaultExporterMaxRetries          = 5
+	DefaultExporterTimeoutMillis       = 10000
+
+	exporterRetryBackoffMillis    = 100
+	exporterMaxRetryBackoffMillis = 30 * 1000
+	exporterShutdownTimeout       = 10 * time.Second
+)
+
+// ExporterOptions are the options of the log targets that export log records in batches
+// to an HTTP endpoint.
+type ExporterOptions struct {
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	// Cert is the path to, or the base64 encoding of, the certificate of the authority
+	// that signed the certificate of the endpoint.
+	Cert     string `json:"cert,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+
+	TimeoutMillis       int64 `json:"timeout_millis,omitempty"`
+	BatchSize           int   `json:"batch_size,omitempty"`
+	FlushIntervalMillis int64 `json:"flush_interval_millis,omitempty"`
+	// MaxBufferSize is the number of records waiting to be exported above which new
+	// records are dropped, e.g. while the endpoint is unavailable.
+	MaxBufferSize int `json:"max_buffer_size,omitempty"`
+	// MaxRetries is the number of times a batch is sent again, with an exponential
+	// backoff, before it is dropped. The batches rejected by the endpoint are not sent
+	// again, unless the endpoint timed out or limited the rate of the requests.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// BlockWhenFull makes the writes wait for room in the buffer rather than drop the
+	// records, e.g. for the audit records.
+	BlockWhenFull bool `json:"block_when_full,omitempty"`
+}
+
+func (eo *ExporterOptions) SetDefaults() {
+	if eo.TimeoutMillis == 0 {
+		eo.TimeoutMillis = DefaultExporterTimeoutMillis
+	}
+	if eo.BatchSize == 0 {
+		eo.BatchSize = DefaultExporterBatchSize
+	}
+	if eo.FlushIntervalMillis == 0 {
+		eo.FlushIntervalMillis = DefaultExporterFlushIntervalMillis
+	}
+	if eo.MaxBufferSize == 0 {
+		eo.MaxBufferSize = DefaultExporterMaxBufferSize
+	}
+	if eo.MaxRetries == 0 {
+		eo.MaxRetries = DefaultExporterMaxRetries
+	}
+}
+
+func (eo ExporterOptions) CheckValid() error {
+	u, err := url.Parse(eo.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q", eo.URL)
+	}
+	if eo.TimeoutMillis < 0 || eo.BatchSize < 0 || eo.FlushIntervalMillis < 0 || eo.MaxBufferSize < 0 || eo.MaxRetries < 0 {
+		return errors.New("timeout, batch size, flush interval, max buffer size and max retries cannot be negative")
+	}
+	if eo.MaxBufferSize != 0 && eo.MaxBufferSize < eo.BatchSize {
+		return errors.New("max buffer size cannot be less than the batch size")
+	}
+	return nil
+}
+
+// exportRecord is a log record waiting to be exported. It holds a copy of the record
+// since the record and its formatted bytes are reused once written.
+type exportRecord struct {
+	time   time.Time
+	level  Level
+	msg    string
+	fields []exportField
+	// line is the record as formatted by the formatter of the target.
+	line string
+}
+
+type exportField struct {
+	key   string
+	value any // string, bool, int64 or float64
+}
+
+func newExportRecord(p []byte, rec *LogRec) exportRecord {
+	fields := rec.Fields()
+	r := exportRecord{
+		time:   rec.Time(),
+		level:  rec.Level(),
+		msg:    rec.Msg(),
+		fields: make([]exportField, 0, len(fields)),
+		line:   strings.TrimRight(string(p), "\n"),
+	}
+	for _, f := range fields {
+		r.fields = append(r.fields, exportField{key: f.Key, value: exportFieldValue(f)})
+	}
+	return r
+}
+
+func exportFieldValue(f Field) any {
+	switch f.Type {
+	case logr.BoolType:
+		return f.Integer != 0
+	case logr.Int64Type, logr.Int32Type, logr.IntType, logr.Uint64Type, logr.Uint32Type, logr.UintType:
+		return f.Integer
+	case logr.Float64Type, logr.Float32Type:
+		return f.Float
+	default:
+		var sb strings.Builder
+		if err := f.ValueString(&sb, nil); err != nil {
+			return fmt.Sprintf("<error: %v>", err)
+		}
+		return sb.String()
+	}
+}
+
+// exportEncoder encodes a batch of records into the body of a request to the endpoint.
+type exportEncoder func(records []exportRecord) (body []byte, contentType string, err error)
+
+// exporter is a log target that buffers the records and sends them in batches to an HTTP
+// endpoint, retrying with an exponential backoff while the endpoint is unavailable.
+type exporter struct {
+	name    string
+	options ExporterOptions
+	client  *http.Client
+	encode  exportEncoder
+
+	mux     sync.Mutex
+	buffer  []exportRecord
+	space   *sync.Cond // signaled when records leave the buffer
+	closed  bool
+	flush   chan struct{}
+	quit    chan struct{}
+	done    chan struct{}
+	lastErr error
+
+	droppedCounter Counter
+	errorCounter   Counter
+	queueSizeGauge Gauge
+}
+
+func newExporter(name string, options ExporterOptions, encode exportEncoder, collector MetricsCollector) (*exporter, error) {
+	options.SetDefaults()
+	if err := options.CheckValid(); err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: options.Insecure} //nolint:gosec
+	if options.Cert != "" {
+		pool, err := targets.GetCertPool(options.Cert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	e := &exporter{
+		name:    name,
+		options: options,
+		client: &http.Client{
+			Timeout:   time.Duration(options.TimeoutMillis) * time.Millisecond,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+		encode: encode,
+		flush:  make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	e.space = sync.NewCond(&e.mux)
+
+	if collector != nil {
+		var err error
+		if e.droppedCounter, err = collector.DroppedCounter(name); err != nil {
+			return nil, err
+		}
+		if e.errorCounter, err = collector.ErrorCounter(name); err != nil {
+			return nil, err
+		}
+		if e.queueSizeGauge, err = collector.QueueSizeGauge(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Init is called once to initialize the target.
+func (e *exporter) Init() error {
+	go e.start()
+	return nil
+}
+
+// Write buffers the record until the next batch is sent. When the buffer is full, the
+// record is dropped, or the write waits for room in the buffer with BlockWhenFull.
+func (e *exporter) Write(p []byte, rec *LogRec) (int, error) {
+	record := newExportRecord(p, rec)
+
+	e.mux.Lock()
+	for len(e.buffer) >= e.options.MaxBufferSize {
+		if e.closed {
+			e.mux.Unlock()
+			return 0, fmt.Errorf("log target %s is shut down", e.name)
+		}
+		if e.options.BlockWhenFull {
+			// The queue of the target fills up in the meantime, leaving the records to the
+			// queue full handling of the logger.
+			e.space.Wait()
+			continue
+		}
+
+		e.mux.Unlock()
+		// Not returning an error since it would be reported as a log record for each
+		// dropped record while the endpoint is unavailable.
+		if e.droppedCounter != nil {
+			e.droppedCounter.Inc()
+		}
+		return len(p), nil
+	}
+	e.buffer = append(e.buffer, record)
+	size := len(e.buffer)
+	e.mux.Unlock()
+
+	if e.queueSizeGauge != nil {
+		e.queueSizeGauge.Set(float64(size))
+	}
+
+	if size >= e.options.BatchSize {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Shutdown sends the buffered records and stops the target.
+func (e *exporter) Shutdown() error {
+	close(e.quit)
+
+	defer func() {
+		e.mux.Lock()
+		e.closed = true
+		e.mux.Unlock()
+		e.space.Broadcast()
+	}()
+
+	select {
+	case <-e.done:
+	case <-time.After(exporterShutdownTimeout):
+		return fmt.Errorf("log target %s shutdown timed out", e.name)
+	}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if len(e.buffer) > 0 {
+		return fmt.Errorf("log target %s dropped %d records on shutdown: %w", e.name, len(e.buffer), e.lastErr)
+	}
+	return nil
+}
+
+func (e *exporter) String() string {
+	return e.name
+}
+
+func (e *exporter) start() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(time.Duration(e.options.FlushIntervalMillis) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.flush:
+		case <-ticker.C:
+		case <-e.quit:
+			for e.sendBatch() {
+			}
+			return
+		}
+
+		for e.sendBatch() {
+			if !e.isBatchFull() {
+				break
+			}
+		}
+	}
+}
+
+func (e *exporter) isBatchFull() bool {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return len(e.buffer) >= e.options.BatchSize
+}
+
+// sendBatch sends the next batch of records and reports whether one was sent.
+func (e *exporter) sendBatch() bool {
+	e.mux.Lock()
+	n := min(len(e.buffer), e.options.BatchSize)
+	batch := e.buffer[:n:n]
+	e.mux.Unlock()
+
+	if n == 0 {
+		return false
+	}
+
+	err := e.sendWithRetries(batch)
+
+	e.mux.Lock()
+	e.buffer = e.buffer[n:]
+	size := len(e.buffer)
+	e.lastErr = err
+	e.mux.Unlock()
+	e.space.Broadcast()
+
+	if e.queueSizeGauge != nil {
+		e.queueSizeGauge.Set(float64(size))
+	}
+	if err != nil {
+		if e.errorCounter != nil {
+			e.errorCounter.Inc()
+		}
+		if e.droppedCounter != nil {
+			e.droppedCounter.Add(float64(n))
+		}
+		return false
+	}
+	return true
+}
+
+func (e *exporter) sendWithRetries(batch []exportRecord) error {
+	body, contentType, err := e.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	backoff := time.Duration(exporterRetryBackoffMillis) * time.Millisecond
+	for retries := 0; ; retries++ {
+		err = e.send(body, contentType)
+		var statusErr *exportStatusError
+		if err == nil || retries >= e.options.MaxRetries || (errors.As(err, &statusErr) && !statusErr.retryable()) {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-e.quit:
+			// Still try once more while shutting down, without waiting.
+			return e.send(body, contentType)
+		}
+		backoff = min(backoff*2, time.Duration(exporterMaxRetryBackoffMillis)*time.Millisecond)
+	}
+}
+
+func (e *exporter) send(body []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, e.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range e.options.Headers {
+		req.Header.Set(k, v)
+	}
+	if e.options.Username != "" || e.options.Password != "" {
+		req.SetBasicAuth(e.options.Username, e.options.Password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &exportStatusError{target: e.name, status: resp.StatusCode, msg: strings.TrimSpace(string(msg))}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// exportStatusError is returned when the endpoint doesn't accept a batch.
+type exportStatusError struct {
+	target string
+	status int
+	msg    string
+}
+
+func (e *exportStatusError) Error() string {
+	return fmt.Sprintf("log target %s received status %d: %s", e.target, e.status, e.msg)
+}
+
+// retryable reports whether the batch may be accepted when sent again. The other client
+// errors, e.g. a malformed batch or invalid credentials, fail again.
+func (e *exportStatusError) retryable() bool {
+	return e.status >= 500 || e.status == http.StatusRequestTimeout || e.status == http.StatusTooManyRequests
+}
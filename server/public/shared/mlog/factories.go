@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Log target types provided by NewFactories, in addition to the ones built into logr.
+// Syslog over TLS is provided by the built-in "syslog" target with the "tls" option.
+const (
+	TargetTypeOTLP = "otlp"
+	TargetTypeLoki = "loki"
+)
+
+// NewFactories returns the factories creating the log targets that are not built into logr.
+// The dropped records and export errors of those targets are reported to the collector,
+// which can be nil.
+func NewFactories(collector MetricsCollector) *Factories {
+	return &Factories{
+		TargetFactory: NewTargetFactory(collector),
+	}
+}
+
+// NewAuditFactories returns the factories of NewFactories for an audit logger. Rather than
+// dropping the audit records, their targets wait for room in their buffer once full, so
+// that the queue full handling of the audit logger applies.
+func NewAuditFactories(collector MetricsCollector) *Factories {
+	return &Factories{
+		TargetFactory: newTargetFactory(collector, true),
+	}
+}
+
+// NewTargetFactory returns a factory creating the log targets of the types TargetTypeOTLP
+// and TargetTypeLoki.
+func NewTargetFactory(collector MetricsCollector) TargetFactory {
+	return newTargetFactory(collector, false)
+}
+
+func newTargetFactory(collector MetricsCollector, blockWhenFull bool) TargetFactory {
+	return func(targetType string, options json.RawMessage) (Target, error) {
+		switch strings.ToLower(targetType) {
+		case TargetTypeOTLP:
+			var oo OTLPOptions
+			if err := decodeTargetOptions(targetType, options, &oo); err != nil {
+				return nil, err
+			}
+			oo.BlockWhenFull = oo.BlockWhenFull || blockWhenFull
+			return NewOTLPTarget(exporterMetricsName(TargetTypeOTLP, oo.URL), oo, collector)
+		case TargetTypeLoki:
+			var lo LokiOptions
+			if err := decodeTargetOptions(targetType, options, &lo); err != nil {
+				return nil, err
+			}
+			lo.BlockWhenFull = lo.BlockWhenFull || blockWhenFull
+			return NewLokiTarget(exporterMetricsName(TargetTypeLoki, lo.URL), lo, collector)
+		}
+		return nil, fmt.Errorf("target type '%s' is unrecognized", targetType)
+	}
+}
+
+func decodeTargetOptions(targetType string, options json.RawMessage, v any) error {
+	if len(options) == 0 {
+		return fmt.Errorf("missing %s target options", targetType)
+	}
+	if err := json.Unmarshal(options, v); err != nil {
+		return fmt.Errorf("error decoding %s target options: %w", targetType, err)
+	}
+	return nil
+}
+
+// exporterMetricsName names the metrics of an exporter after its type and endpoint,
+// since the factory does not know the name of the target.
+func exporterMetricsName(targetType, endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return targetType
+	}
+	return targetType + ":" + u.Host
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mlog_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type testCounter struct {
+	value atomic.Int64
+}
+
+func (c *testCounter) Inc()          { c.value.Add(1) }
+func (c *testCounter) Add(v float64) { c.value.Add(int64(v)) }
+
+type testGauge struct{}
+
+func (testGauge) Set(float64) {}
+func (testGauge) Add(float64) {}
+func (testGauge) Sub(float64) {}
+
+type testCollector struct {
+	mux     sync.Mutex
+	dropped map[string]*testCounter
+}
+
+func (c *testCollector) counter(target string) *testCounter {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.dropped == nil {
+		c.dropped = make(map[string]*testCounter)
+	}
+	if c.dropped[target] == nil {
+		c.dropped[target] = &testCounter{}
+	}
+	return c.dropped[target]
+}
+
+func (c *testCollector) QueueSizeGauge(string) (mlog.Gauge, error)     { return testGauge{}, nil }
+func (c *testCollector) LoggedCounter(string) (mlog.Counter, error)    { return &testCounter{}, nil }
+func (c *testCollector) ErrorCounter(string) (mlog.Counter, error)     { return &testCounter{}, nil }
+func (c *testCollector) BlockedCounter(string) (mlog.Counter, error)   { return &testCounter{}, nil }
+func (c *testCollector) DroppedCounter(t string) (mlog.Counter, error) { return c.counter(t), nil }
+
+type testEndpoint struct {
+	mux      sync.Mutex
+	bodies   [][]byte
+	header   http.Header
+	status   atomic.Int32
+	received atomic.Int32
+}
+
+func newTestEndpoint(t *testing.T) (*testEndpoint, *httptest.Server) {
+	endpoint := &testEndpoint{}
+	endpoint.status.Store(http.StatusNoContent)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		endpoint.received.Add(1)
+
+		status := int(endpoint.status.Load())
+		if status < 300 {
+			endpoint.mux.Lock()
+			endpoint.bodies = append(endpoint.bodies, body)
+			endpoint.header = r.Header.Clone()
+			endpoint.mux.Unlock()
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return endpoint, server
+}
+
+func (e *testEndpoint) requests() ([][]byte, http.Header) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return e.bodies, e.header
+}
+
+func configureExporter(t *testing.T, targetType string, options map[string]any, collector mlog.MetricsCollector) *mlog.Logger {
+	opts, err := json.Marshal(options)
+	require.NoError(t, err)
+
+	logger, err := mlog.NewLogger()
+	require.NoError(t, err)
+
+	err = logger.ConfigureTargets(mlog.LoggerConfiguration{
+		"exporter": {
+			Type:    targetType,
+			Format:  "json",
+			Levels:  []mlog.Level{mlog.LvlInfo, mlog.LvlError, mlog.LvlAuditAPI},
+			Options: opts,
+		},
+	}, mlog.NewFactories(collector))
+	require.NoError(t, err)
+	return logger
+}
+
+func TestOTLPTarget(t *testing.T) {
+	endpoint, server := newTestEndpoint(t)
+
+	logger := configureExporter(t, mlog.TargetTypeOTLP, map[string]any{
+		"url":                 server.URL + "/v1/logs",
+		"headers":             map[string]string{"Authorization": "Bearer token"},
+		"service_name":        "chat",
+		"resource_attributes": map[string]string{"deployment.environment": "test"},
+	}, nil)
+
+	logger.Info("user logged in", mlog.String("user_id", "abc"), mlog.Int("attempts", 2), mlog.Bool("mfa", true))
+	logger.Error("failed")
+	logger.Debug("filtered out")
+	require.NoError(t, logger.Shutdown())
+
+	bodies, header := endpoint.requests()
+	require.Len(t, bodies, 1)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+
+	var data struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []map[string]any `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []struct {
+					SeverityNumber int               `json:"severityNumber"`
+					SeverityText   string            `json:"severityText"`
+					Body           map[string]any    `json:"body"`
+					Attributes     []json.RawMessage `json:"attributes"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	require.NoError(t, json.Unmarshal(bodies[0], &data))
+	require.Len(t, data.ResourceLogs, 1)
+	assert.Len(t, data.ResourceLogs[0].Resource.Attributes, 2)
+
+	records := data.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 2)
+	assert.Equal(t, 9, records[0].SeverityNumber)
+	assert.Equal(t, "info", records[0].SeverityText)
+	assert.Equal(t, "user logged in", records[0].Body["stringValue"])
+	require.Len(t, records[0].Attributes, 3)
+	assert.JSONEq(t, `{"key":"user_id","value":{"stringValue":"abc"}}`, string(records[0].Attributes[0]))
+	assert.JSONEq(t, `{"key":"attempts","value":{"intValue":"2"}}`, string(records[0].Attributes[1]))
+	assert.JSONEq(t, `{"key":"mfa","value":{"boolValue":true}}`, string(records[0].Attributes[2]))
+	assert.Equal(t, 17, records[1].SeverityNumber)
+}
+
+func TestLokiTarget(t *testing.T) {
+	endpoint, server := newTestEndpoint(t)
+
+	logger := configureExporter(t, mlog.TargetTypeLoki, map[string]any{
+		"url":        server.URL + "/loki/api/v1/push",
+		"labels":     map[string]string{"app": "mattermost"},
+		"tenant_id":  "tenant",
+		"username":   "user",
+		"password":   "secret",
+		"batch_size": 2,
+	}, nil)
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Log(mlog.LvlAuditAPI, "audit", mlog.String("event", "login"))
+	require.NoError(t, logger.Shutdown())
+
+	bodies, header := endpoint.requests()
+	require.Len(t, bodies, 2)
+	assert.Equal(t, "tenant", header.Get("X-Scope-OrgID"))
+	username, password, ok := (&http.Request{Header: header}).BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "secret", password)
+
+	type push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+
+	var first push
+	require.NoError(t, json.Unmarshal(bodies[0], &first))
+	require.Len(t, first.Streams, 1)
+	assert.Equal(t, map[string]string{"app": "mattermost", "level": "info"}, first.Streams[0].Stream)
+	require.Len(t, first.Streams[0].Values, 2)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(first.Streams[0].Values[0][1]), &line))
+	assert.Equal(t, "one", line["msg"])
+
+	var second push
+	require.NoError(t, json.Unmarshal(bodies[1], &second))
+	require.Len(t, second.Streams, 1)
+	assert.Equal(t, "audit-api", second.Streams[0].Stream["level"])
+	assert.Contains(t, second.Streams[0].Values[0][1], `"event":"login"`)
+}
+
+func TestExporterDropsRecords(t *testing.T) {
+	endpoint, server := newTestEndpoint(t)
+	endpoint.status.Store(http.StatusServiceUnavailable)
+
+	collector := &testCollector{}
+	logger := configureExporter(t, mlog.TargetTypeLoki, map[string]any{
+		"url":                   server.URL,
+		"batch_size":            2,
+		"max_buffer_size":       2,
+		"max_retries":           1,
+		"flush_interval_millis": 60000,
+	}, collector)
+
+	for i := 0; i < 5; i++ {
+		logger.Info("message")
+	}
+	require.NoError(t, logger.Flush())
+	_ = logger.Shutdown()
+
+	bodies, _ := endpoint.requests()
+	assert.Empty(t, bodies)
+	assert.Equal(t, int64(5), collector.counter("loki:"+server.Listener.Addr().String()).value.Load())
+}
+
+func TestExporterDoesNotRetryRejectedBatches(t *testing.T) {
+	endpoint, server := newTestEndpoint(t)
+	endpoint.status.Store(http.StatusBadRequest)
+
+	logger := configureExporter(t, mlog.TargetTypeLoki, map[string]any{
+		"url":                   server.URL,
+		"batch_size":            1,
+		"max_retries":           3,
+		"flush_interval_millis": 60000,
+	}, nil)
+
+	logger.Info("message")
+	require.NoError(t, logger.Flush())
+	_ = logger.Shutdown()
+
+	assert.Equal(t, int32(1), endpoint.received.Load())
+}
+
+func TestAuditExporterBlocksWhenFull(t *testing.T) {
+	var mux sync.Mutex
+	var received int
+	first := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		// The first batch is held until the buffer filled up behind it.
+		once.Do(func() {
+			close(first)
+			<-release
+		})
+
+		mux.Lock()
+		received += strings.Count(string(body), "audit record")
+		mux.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	opts, err := json.Marshal(map[string]any{
+		"url":                   server.URL,
+		"batch_size":            2,
+		"max_buffer_size":       2,
+		"flush_interval_millis": 60000,
+	})
+	require.NoError(t, err)
+
+	logger, err := mlog.NewLogger()
+	require.NoError(t, err)
+	collector := &testCollector{}
+	err = logger.ConfigureTargets(mlog.LoggerConfiguration{
+		"exporter": {
+			Type:    mlog.TargetTypeLoki,
+			Format:  "json",
+			Levels:  []mlog.Level{mlog.LvlAuditAPI},
+			Options: opts,
+		},
+	}, mlog.NewAuditFactories(collector))
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		logger.Log(mlog.LvlAuditAPI, "audit record")
+	}
+
+	<-first
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	require.NoError(t, logger.Flush())
+	_ = logger.Shutdown()
+
+	mux.Lock()
+	defer mux.Unlock()
+	assert.Equal(t, 5, received)
+	assert.Zero(t, collector.counter("loki:"+server.Listener.Addr().String()).value.Load())
+}
+
+func TestNewTargetFactory(t *testing.T) {
+	factory := mlog.NewTargetFactory(nil)
+
+	for name, tc := range map[string]struct {
+		targetType string
+		options    string
+	}{
+		"unknown type":     {targetType: "kafka", options: `{"url":"http://localhost"}`},
+		"missing options":  {targetType: mlog.TargetTypeOTLP},
+		"invalid options":  {targetType: mlog.TargetTypeLoki, options: `{"url":1}`},
+		"invalid url":      {targetType: mlog.TargetTypeLoki, options: `{"url":"localhost:3100"}`},
+		"negative retries": {targetType: mlog.TargetTypeOTLP, options: `{"url":"http://localhost","max_retries":-1}`},
+		"small buffer":     {targetType: mlog.TargetTypeOTLP, options: `{"url":"http://localhost","batch_size":10,"max_buffer_size":5}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := factory(tc.targetType, json.RawMessage(tc.options))
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("valid configuration", func(t *testing.T) {
+		cfg := mlog.LoggerConfiguration{
+			"loki": {Type: mlog.TargetTypeLoki, Format: "json", Levels: []mlog.Level{mlog.LvlInfo}, Options: json.RawMessage(`{"url":"http://localhost:3100/loki/api/v1/push"}`)},
+		}
+		require.NoError(t, cfg.IsValid())
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mlog
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// LokiOptions are the options of the log target pushing log records to Loki, e.g. to
+// http://localhost:3100/loki/api/v1/push.
+type LokiOptions struct {
+	ExporterOptions
+	// Labels are the static labels of the streams. The level of the records is added as
+	// the "level" label.
+	Labels map[string]string `json:"labels,omitempty"`
+	// TenantID is sent as the X-Scope-OrgID header to multi-tenant Loki deployments.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// NewLokiTarget creates a target pushing log records to Loki. The records are pushed as
+// formatted by the formatter of the target.
+func NewLokiTarget(name string, options LokiOptions, collector MetricsCollector) (Target, error) {
+	if options.TenantID != "" {
+		headers := make(map[string]string, len(options.Headers)+1)
+		for k, v := range options.Headers {
+			headers[k] = v
+		}
+		headers["X-Scope-OrgID"] = options.TenantID
+		options.Headers = headers
+	}
+	return newExporter(name, options.ExporterOptions, newLokiEncoder(options), collector)
+}
+
+// See https://grafana.com/docs/loki/latest/reference/loki-http-api/#ingest-logs.
+type lokiPushRequest struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func newLokiEncoder(options LokiOptions) exportEncoder {
+	return func(records []exportRecord) ([]byte, string, error) {
+		req := lokiPushRequest{}
+		streams := make(map[string]*lokiStream)
+		for _, r := range records {
+			stream, ok := streams[r.level.Name]
+			if !ok {
+				labels := make(map[string]string, len(options.Labels)+1)
+				for k, v := range options.Labels {
+					labels[k] = v
+				}
+				labels["level"] = r.level.Name
+				stream = &lokiStream{Stream: labels}
+				streams[r.level.Name] = stream
+				req.Streams = append(req.Streams, stream)
+			}
+
+			line := r.line
+			if line == "" {
+				line = r.msg
+			}
+			stream.Values = append(stream.Values, [2]string{strconv.FormatInt(r.time.UnixNano(), 10), line})
+		}
+
+		body, err := json.Marshal(req)
+		return body, "application/json", err
+	}
+}
@@ -77,7 +77,7 @@ func (lc LoggerConfiguration) IsValid() error {
 	}
 	defer logger.Shutdown()
 
-	err = logrcfg.ConfigureTargets(logger, lc, nil)
+	err = logrcfg.ConfigureTargets(logger, lc, NewFactories(nil))
 	if err != nil {
 		return errors.Wrap(err, "logger configuration is invalid")
 	}
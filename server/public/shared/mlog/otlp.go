@@ -0,0 +1,153 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mlog
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+const DefaultOTLPServiceName = "mattermost"
+
+// OTLPOptions are the options of the log target exporting log records to an OpenTelemetry
+// collector with OTLP/HTTP, e.g. to http://localhost:4318/v1/logs.
+type OTLPOptions struct {
+	ExporterOptions
+	ServiceName        string            `json:"service_name,omitempty"`
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty"`
+}
+
+// NewOTLPTarget creates a target exporting log records to an OpenTelemetry collector.
+func NewOTLPTarget(name string, options OTLPOptions, collector MetricsCollector) (Target, error) {
+	if options.ServiceName == "" {
+		options.ServiceName = DefaultOTLPServiceName
+	}
+	return newExporter(name, options.ExporterOptions, newOTLPEncoder(options), collector)
+}
+
+// The types below are the subset of the OTLP/JSON encoding of logs used by the target.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+type otlpLogsData struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"` // int64 values are encoded as strings
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newOTLPAnyValue(value any) otlpAnyValue {
+	switch v := value.(type) {
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	default:
+		s := ""
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+// otlpSeverityNumber maps the level to an OpenTelemetry severity number. The levels
+// not defined by OpenTelemetry, such as the audit ones, are informational.
+func otlpSeverityNumber(level Level) int {
+	switch level.ID {
+	case LvlTrace.ID:
+		return 1
+	case LvlDebug.ID:
+		return 5
+	case LvlWarn.ID:
+		return 13
+	case LvlError.ID, LvlLogError.ID:
+		return 17
+	case LvlCritical.ID:
+		return 18
+	case LvlFatal.ID:
+		return 21
+	case LvlPanic.ID:
+		return 24
+	default:
+		return 9
+	}
+}
+
+func newOTLPEncoder(options OTLPOptions) exportEncoder {
+	resource := otlpResource{
+		Attributes: []otlpKeyValue{{Key: "service.name", Value: newOTLPAnyValue(options.ServiceName)}},
+	}
+	keys := make([]string, 0, len(options.ResourceAttributes))
+	for k := range options.ResourceAttributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		resource.Attributes = append(resource.Attributes, otlpKeyValue{Key: k, Value: newOTLPAnyValue(options.ResourceAttributes[k])})
+	}
+
+	return func(records []exportRecord) ([]byte, string, error) {
+		logRecords := make([]otlpLogRecord, 0, len(records))
+		for _, r := range records {
+			ts := strconv.FormatInt(r.time.UnixNano(), 10)
+			logRecord := otlpLogRecord{
+				TimeUnixNano:         ts,
+				ObservedTimeUnixNano: ts,
+				SeverityNumber:       otlpSeverityNumber(r.level),
+				SeverityText:         r.level.Name,
+				Body:                 newOTLPAnyValue(r.msg),
+			}
+			for _, f := range r.fields {
+				logRecord.Attributes = append(logRecord.Attributes, otlpKeyValue{Key: f.key, Value: newOTLPAnyValue(f.value)})
+			}
+			logRecords = append(logRecords, logRecord)
+		}
+
+		body, err := json.Marshal(otlpLogsData{
+			ResourceLogs: []otlpResourceLogs{{
+				Resource:  resource,
+				ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: options.ServiceName}, LogRecords: logRecords}},
+			}},
+		})
+		return body, "application/json", err
+	}
+}
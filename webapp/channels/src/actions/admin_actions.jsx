@@ -133,10 +133,33 @@ export function getOAuthAppInfo(clientId) {
  * @param {*}
  * @returns {ActionResult<{redirect: string}>}
  */
-export function allowOAuth2({responseType, clientId, redirectUri, state, scope}) {
+export function allowOAuth2({responseType, clientId, redirectUri, state, scope, codeChallenge, codeChallengeMethod}) {
     return bindClientFunc({
         clientFunc: Client4.authorizeOAuthApp,
-        params: [responseType, clientId, redirectUri, state, scope],
+        params: [responseType, clientId, redirectUri, state, scope, codeChallenge, codeChallengeMethod],
+    });
+}
+
+/**
+ * @param {string} userCode
+ * @returns {ActionResult<OAuthApp>}
+ */
+export function getOAuthAppForDeviceCode(userCode) {
+    return bindClientFunc({
+        clientFunc: Client4.getOAuthAppForDeviceCode,
+        params: [userCode],
+    });
+}
+
+/**
+ * @param {string} userCode
+ * @param {boolean} allow
+ * @returns {ActionResult<StatusOK>}
+ */
+export function verifyOAuthDevice(userCode, allow) {
+    return bindClientFunc({
+        clientFunc: Client4.verifyOAuthDevice,
+        params: [userCode, allow],
     });
 }
 
@@ -37,6 +37,7 @@ export type NewPostMessageProps = {
     mentions: string[];
     team_id: string;
     should_ack: boolean;
+    notifications_muted?: boolean;
 }
 
 export function completePostReceive(post: Post, websocketMessageProps: NewPostMessageProps, fetchedChannelMember?: boolean): ActionFuncAsync<boolean, GlobalState> {
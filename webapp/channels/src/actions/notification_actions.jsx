@@ -90,6 +90,10 @@ export function sendDesktopNotification(post, msgProps) {
             return {status: 'not_sent', reason: 'channel_muted'};
         }
 
+        if (msgProps.notifications_muted) {
+            return {status: 'not_sent', reason: 'muted_by_rule'};
+        }
+
         if (userStatus === UserStatuses.DND || userStatus === UserStatuses.OUT_OF_OFFICE) {
             return {status: 'not_sent', reason: 'user_status', data: userStatus};
         }
@@ -321,6 +321,13 @@ describe('notification_actions', () => {
             });
         });
 
+        test('should not notify user when muted by a notification rule', () => {
+            const store = testConfigureStore(baseState);
+            return store.dispatch(sendDesktopNotification(post, {...msgProps, notifications_muted: true})).then(() => {
+                expect(spy).not.toHaveBeenCalled();
+            });
+        });
+
         test.each([
             UserStatuses.DND,
             UserStatuses.OUT_OF_OFFICE,
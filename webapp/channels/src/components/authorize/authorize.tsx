@@ -21,6 +21,8 @@ export type Params = {
     redirectUri: string | null;
     state: string | null;
     scope: string | null;
+    codeChallenge: string | null;
+    codeChallengeMethod: string | null;
 };
 
 type Props = {
@@ -77,6 +79,8 @@ export default class Authorize extends React.PureComponent<Props, State> {
             redirectUri: searchParams.get("redirect_uri"),
             state: searchParams.get("state"),
             scope: searchParams.get("store"),
+            codeChallenge: searchParams.get("code_challenge"),
+            codeChallengeMethod: searchParams.get("code_challenge_method"),
         };
 
         this.props.actions.allowOAuth2(params).then(({ data, error }) => {
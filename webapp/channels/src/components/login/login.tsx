@@ -56,6 +56,7 @@ import Constants from 'utils/constants';
 import DesktopApp from 'utils/desktop_api';
 import {t} from 'utils/i18n';
 import {showNotification} from 'utils/notifications';
+import {parseOpenIdConnectProviders} from 'utils/openid_connect';
 import {isDesktopApp} from 'utils/user_agent';
 import {setCSRFFromCookie} from 'utils/utils';
 
@@ -99,6 +100,7 @@ const Login = ({onCustomizeHeader}: LoginProps) => {
         GitLabButtonColor,
         OpenIdButtonText,
         OpenIdButtonColor,
+        OpenIdConnectProviders,
         SamlLoginButtonText,
         EnableCustomBrand,
         CustomBrandText,
@@ -141,15 +143,17 @@ const Login = ({onCustomizeHeader}: LoginProps) => {
     const enableSignUpWithGoogle = EnableSignUpWithGoogle === 'true';
     const enableSignUpWithOffice365 = EnableSignUpWithOffice365 === 'true';
     const enableSignUpWithOpenId = EnableSignUpWithOpenId === 'true';
+    const openIdConnectProviders = parseOpenIdConnectProviders(OpenIdConnectProviders);
+    const enableSignUpWithOpenIdConnect = openIdConnectProviders.length > 0;
     const isLicensed = IsLicensed === 'true';
     const ldapEnabled = isLicensed && enableLdap;
     const enableSignUpWithSaml = isLicensed && enableSaml;
     const siteName = SiteName ?? '';
 
     const enableBaseLogin = enableSignInWithEmail || enableSignInWithUsername || ldapEnabled;
-    const enableExternalSignup = enableSignUpWithGitLab || enableSignUpWithOffice365 || enableSignUpWithGoogle || enableSignUpWithOpenId || enableSignUpWithSaml;
+    const enableExternalSignup = enableSignUpWithGitLab || enableSignUpWithOffice365 || enableSignUpWithGoogle || enableSignUpWithOpenId || enableSignUpWithOpenIdConnect || enableSignUpWithSaml;
     const showSignup = enableOpenServer && (enableExternalSignup || enableSignUpWithEmail || enableLdap);
-    const onlyLdapEnabled = enableLdap && !(enableSaml || enableSignInWithEmail || enableSignInWithUsername || enableSignUpWithEmail || enableSignUpWithGitLab || enableSignUpWithGoogle || enableSignUpWithOffice365 || enableSignUpWithOpenId);
+    const onlyLdapEnabled = enableLdap && !(enableSaml || enableSignInWithEmail || enableSignInWithUsername || enableSignUpWithEmail || enableSignUpWithGitLab || enableSignUpWithGoogle || enableSignUpWithOffice365 || enableSignUpWithOpenId || enableSignUpWithOpenIdConnect);
 
     const query = new URLSearchParams(search);
     const redirectTo = query.get('redirect_to');
@@ -259,6 +263,18 @@ const Login = ({onCustomizeHeader}: LoginProps) => {
             });
         }
 
+        for (const provider of openIdConnectProviders) {
+            const url = `${Client4.getOAuthRoute()}/${provider.service}/login${search}`;
+            externalLoginOptions.push({
+                id: provider.service,
+                url,
+                icon: <LoginOpenIDIcon/>,
+                label: provider.button_text,
+                style: {color: provider.button_color, borderColor: provider.button_color},
+                onClick: desktopExternalAuth(url),
+            });
+        }
+
         if (enableSignUpWithSaml) {
             const url = `${Client4.getUrl()}/login/sso/saml${search}`;
             externalLoginOptions.push({
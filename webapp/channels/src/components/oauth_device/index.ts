@@ -0,0 +1,21 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+import {connect} from 'react-redux';
+import {bindActionCreators} from 'redux';
+import type {Dispatch} from 'redux';
+
+import {getOAuthAppForDeviceCode, verifyOAuthDevice} from 'actions/admin_actions.jsx';
+
+import OAuthDevice from './oauth_device';
+
+function mapDispatchToProps(dispatch: Dispatch) {
+    return {
+        actions: bindActionCreators({
+            getOAuthAppForDeviceCode,
+            verifyOAuthDevice,
+        }, dispatch),
+    };
+}
+
+export default connect(null, mapDispatchToProps)(OAuthDevice);
@@ -0,0 +1,204 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+import React from 'react';
+import type {ChangeEvent, FormEvent, ReactNode} from 'react';
+import {FormattedMessage} from 'react-intl';
+
+import type {OAuthApp} from '@mattermost/types/integrations';
+
+import type {ActionResult} from 'mattermost-redux/types/actions';
+
+import FormError from 'components/form_error';
+import FormattedMarkdownMessage from 'components/formatted_markdown_message';
+
+type Props = {
+    location: {
+        search: string;
+    };
+    actions: {
+        getOAuthAppForDeviceCode: (userCode: string) => Promise<ActionResult<OAuthApp>>;
+        verifyOAuthDevice: (userCode: string, allow: boolean) => Promise<ActionResult>;
+    };
+};
+
+type State = {
+    userCode: string;
+    app?: OAuthApp;
+    allowed?: boolean;
+    error?: string;
+};
+
+export default class OAuthDevice extends React.PureComponent<Props, State> {
+    public constructor(props: Props) {
+        super(props);
+
+        this.state = {
+            userCode: new URLSearchParams(props.location.search).get('user_code') || '',
+        };
+    }
+
+    public componentDidMount(): void {
+        // if we get to this point remove the antiClickjack blocker
+        const blocker = document.getElementById('antiClickjack');
+        if (blocker && blocker.parentNode) {
+            blocker.parentNode.removeChild(blocker);
+        }
+    }
+
+    handleUserCodeChange = (e: ChangeEvent<HTMLInputElement>): void => {
+        this.setState({userCode: e.target.value});
+    };
+
+    handleSubmit = async (e: FormEvent): Promise<void> => {
+        e.preventDefault();
+
+        const {data, error} = await this.props.actions.getOAuthAppForDeviceCode(this.state.userCode.trim());
+        if (data) {
+            this.setState({app: data, error: undefined});
+        } else if (error) {
+            this.setState({error: error.message});
+        }
+    };
+
+    handleVerify = async (allow: boolean): Promise<void> => {
+        const {error} = await this.props.actions.verifyOAuthDevice(this.state.userCode.trim(), allow);
+        if (error) {
+            this.setState({error: error.message});
+            return;
+        }
+
+        this.setState({allowed: allow, error: undefined});
+    };
+
+    handleAllow = (): Promise<void> => this.handleVerify(true);
+
+    handleDeny = (): Promise<void> => this.handleVerify(false);
+
+    renderContent(): ReactNode {
+        const {app, allowed} = this.state;
+
+        if (app && allowed !== undefined) {
+            return (
+                <p>
+                    {allowed ? (
+                        <FormattedMarkdownMessage
+                            id='oauth_device.allowed'
+                            defaultMessage='**{appName}** is now connected to your account. You can close this page and return to your device.'
+                            values={{appName: app.name}}
+                        />
+                    ) : (
+                        <FormattedMarkdownMessage
+                            id='oauth_device.denied'
+                            defaultMessage='**{appName}** was denied access to your account.'
+                            values={{appName: app.name}}
+                        />
+                    )}
+                </p>
+            );
+        }
+
+        if (app) {
+            return (
+                <>
+                    <p>
+                        <FormattedMarkdownMessage
+                            id='authorize.app'
+                            defaultMessage='The app **{appName}** would like the ability to access and modify your basic information.'
+                            values={{appName: app.name}}
+                        />
+                    </p>
+                    <h2 className='prompt__allow'>
+                        <FormattedMarkdownMessage
+                            id='authorize.access'
+                            defaultMessage='Allow **{appName}** access?'
+                            values={{appName: app.name}}
+                        />
+                    </h2>
+                    <div className='prompt__buttons'>
+                        <button
+                            type='button'
+                            className='btn btn-tertiary authorize-btn'
+                            onClick={this.handleDeny}
+                        >
+                            <FormattedMessage
+                                id='authorize.deny'
+                                defaultMessage='Deny'
+                            />
+                        </button>
+                        <button
+                            type='button'
+                            className='btn btn-primary authorize-btn'
+                            onClick={this.handleAllow}
+                        >
+                            <FormattedMessage
+                                id='authorize.allow'
+                                defaultMessage='Allow'
+                            />
+                        </button>
+                    </div>
+                </>
+            );
+        }
+
+        return (
+            <form onSubmit={this.handleSubmit}>
+                <p>
+                    <FormattedMessage
+                        id='oauth_device.enter_code'
+                        defaultMessage='Enter the code shown on your device.'
+                    />
+                </p>
+                <div className='form-group'>
+                    <input
+                        type='text'
+                        className='form-control'
+                        autoFocus={true}
+                        autoComplete='off'
+                        spellCheck={false}
+                        value={this.state.userCode}
+                        onChange={this.handleUserCodeChange}
+                    />
+                </div>
+                <button
+                    type='submit'
+                    className='btn btn-primary'
+                    disabled={!this.state.userCode.trim()}
+                >
+                    <FormattedMessage
+                        id='oauth_device.continue'
+                        defaultMessage='Continue'
+                    />
+                </button>
+            </form>
+        );
+    }
+
+    public render(): ReactNode {
+        let error;
+        if (this.state.error) {
+            error = (
+                <div className='prompt__error form-group'>
+                    <FormError error={this.state.error}/>
+                </div>
+            );
+        }
+
+        return (
+            <div className='container-fluid'>
+                <div className='prompt'>
+                    <div className='prompt__heading'>
+                        <div className='text'>
+                            <FormattedMessage
+                                id='oauth_device.title'
+                                defaultMessage='Connect a Device to Your Mattermost Account'
+                            />
+                        </div>
+                    </div>
+                    {this.renderContent()}
+                    {error}
+                </div>
+            </div>
+        );
+    }
+}
@@ -141,6 +141,10 @@ const Authorize = makeAsyncComponent(
     'Authorize',
     lazy(() => import('components/authorize')),
 );
+const OAuthDevice = makeAsyncComponent(
+    'OAuthDevice',
+    lazy(() => import('components/oauth_device')),
+);
 const CreateTeam = makeAsyncComponent(
     'CreateTeam',
     lazy(() => import('components/create_team')),
@@ -622,6 +626,10 @@ class Root extends React.PureComponent<Props, State> {
                         path={'/oauth/authorize'}
                         component={Authorize}
                     />
+                    <LoggedInHFTRoute
+                        path={'/oauth/device'}
+                        component={OAuthDevice}
+                    />
                     <LoggedInHFTRoute
                         path={'/create_team'}
                         component={CreateTeam}
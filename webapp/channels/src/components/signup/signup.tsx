@@ -58,6 +58,7 @@ import Google from 'images/icons/google.png';
 import linkedin from 'images/icons/linkedin.png';
 import macImage from 'images/icons/mac.png';
 import {Constants, ItemStatus, ValidationErrors} from 'utils/constants';
+import {parseOpenIdConnectProviders} from 'utils/openid_connect';
 import {isValidPassword} from 'utils/password';
 import {isDesktopApp} from 'utils/user_agent';
 import {isValidUsername, getRoleFromTrackFlow, getMediumFromTrackFlow} from 'utils/utils';
@@ -106,6 +107,7 @@ const Signup = ({onCustomizeHeader}: SignupProps) => {
         GitLabButtonColor,
         OpenIdButtonText,
         OpenIdButtonColor,
+        OpenIdConnectProviders,
         EnableCustomBrand,
         CustomBrandText,
         TermsOfServiceLink,
@@ -129,6 +131,7 @@ const Signup = ({onCustomizeHeader}: SignupProps) => {
     const enableSignUpWithGoogle = enableUserCreation && EnableSignUpWithGoogle === 'true';
     const enableSignUpWithOffice365 = enableUserCreation && EnableSignUpWithOffice365 === 'true';
     const enableSignUpWithOpenId = enableUserCreation && EnableSignUpWithOpenId === 'true';
+    const openIdConnectProviders = enableUserCreation ? parseOpenIdConnectProviders(OpenIdConnectProviders) : [];
     const enableLDAP = EnableLdap === 'true';
     const enableSAML = EnableSaml === 'true';
     const enableCustomBrand = EnableCustomBrand === 'true';
@@ -154,7 +157,7 @@ const Signup = ({onCustomizeHeader}: SignupProps) => {
         setIsChecked(!isChecked);
     };
 
-    const enableExternalSignup = enableSignUpWithGitLab || enableSignUpWithOffice365 || enableSignUpWithGoogle || enableSignUpWithOpenId || enableLDAP || enableSAML;
+    const enableExternalSignup = enableSignUpWithGitLab || enableSignUpWithOffice365 || enableSignUpWithGoogle || enableSignUpWithOpenId || openIdConnectProviders.length > 0 || enableLDAP || enableSAML;
     const hasError = Boolean(emailError || nameError || passwordError || serverError || alertBanner);
     const canSubmit = Boolean(email && name && password) && !hasError && !loading;
     const passwordConfig = useSelector(getPasswordConfig);
@@ -263,6 +266,18 @@ const Signup = ({onCustomizeHeader}: SignupProps) => {
             });
         }
 
+        for (const provider of openIdConnectProviders) {
+            const url = `${Client4.getOAuthRoute()}/${provider.service}/signup${search}`;
+            externalLoginOptions.push({
+                id: provider.service,
+                url,
+                icon: <LoginOpenIDIcon/>,
+                label: provider.button_text,
+                style: {color: provider.button_color, borderColor: provider.button_color},
+                onClick: desktopExternalAuth(url),
+            });
+        }
+
         if (isLicensed && enableLDAP) {
             const newSearchParam = new URLSearchParams(search);
             newSearchParam.set('extra', Constants.CREATE_LDAP);
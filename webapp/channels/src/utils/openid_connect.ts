@@ -0,0 +1,22 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+export type OpenIdConnectProvider = {
+    service: string;
+    button_text: string;
+    button_color: string;
+};
+
+// parseOpenIdConnectProviders reads the OpenIdConnectProviders client config,
+// a JSON list of the enabled generic OpenID Connect providers.
+export function parseOpenIdConnectProviders(value?: string): OpenIdConnectProvider[] {
+    if (!value) {
+        return [];
+    }
+
+    try {
+        return JSON.parse(value);
+    } catch {
+        return [];
+    }
+}
@@ -1152,10 +1152,17 @@ export default class Client4 {
         );
     };
 
-    authorizeOAuthApp = (responseType: string, clientId: string, redirectUri: string, state: string, scope: string) => {
+    authorizeOAuthApp = (responseType: string, clientId: string, redirectUri: string, state: string, scope: string, codeChallenge = '', codeChallengeMethod = '') => {
         return this.doFetch<void>(
             `${this.url}/oauth/authorize`,
-            {method: 'post', body: JSON.stringify({client_id: clientId, response_type: responseType, redirect_uri: redirectUri, state, scope})},
+            {method: 'post', body: JSON.stringify({client_id: clientId, response_type: responseType, redirect_uri: redirectUri, state, scope, code_challenge: codeChallenge, code_challenge_method: codeChallengeMethod})},
+        );
+    };
+
+    verifyOAuthDevice = (userCode: string, allow: boolean) => {
+        return this.doFetch<StatusOK>(
+            `${this.url}/oauth/device`,
+            {method: 'post', body: JSON.stringify({user_code: userCode, allow})},
         );
     };
 
@@ -2890,6 +2897,13 @@ export default class Client4 {
         );
     };
 
+    getOAuthAppForDeviceCode = (userCode: string) => {
+        return this.doFetch<OAuthApp>(
+            `${this.getBaseRoute()}/oauth/device/app${buildQueryString({user_code: userCode})}`,
+            {method: 'get'},
+        );
+    };
+
     deleteOAuthApp = (appId: string) => {
         this.trackEvent('api', 'api_apps_delete');
 
@@ -65,6 +65,7 @@ export type ClientConfig = {
     EnableDeveloper: string;
     EnableDiagnostics: string;
     EnableEmailBatching: string;
+    EnableEmailDigests: string;
     EnableEmailInvitations: string;
     EnableEmojiPicker: string;
     EnableFileAttachments: string;
@@ -108,6 +109,9 @@ export type ClientConfig = {
     EnableUserCreation: string;
     EnableUserDeactivation: string;
     EnableUserTypingMessages: string;
+    EnableWebAuthn: string;
+    EnableWebAuthnPasswordlessLogin: string;
+    MfaTrustedDeviceDays: string;
     EnforceMultifactorAuthentication: string;
     ExperimentalClientSideCertCheck: string;
     ExperimentalClientSideCertEnable: string;
@@ -154,6 +158,7 @@ export type ClientConfig = {
     GitLabButtonColor: string;
     OpenIdButtonText: string;
     OpenIdButtonColor: string;
+    OpenIdConnectProviders: string;
     PasswordEnableForgotLink: string;
     PasswordMinimumLength: string;
     PasswordRequireLowercase: string;
@@ -198,6 +203,7 @@ export type ClientConfig = {
     PostPriority: string;
     PostAcknowledgements: string;
     AllowPersistentNotifications: string;
+    EnableNotificationRules: string;
     PersistentNotificationMaxRecipients: string;
     PersistentNotificationIntervalMinutes: string;
     AllowPersistentNotificationsForGuests: string;
@@ -325,6 +331,9 @@ export type ServiceSettings = {
     AllowedUntrustedInternalConnections: string;
     EnableMultifactorAuthentication: boolean;
     EnforceMultifactorAuthentication: boolean;
+    EnableWebAuthn: boolean;
+    EnableWebAuthnPasswordlessLogin: boolean;
+    MfaTrustedDeviceDays: number;
     EnableUserAccessTokens: boolean;
     AllowCorsFrom: string;
     CorsExposedHeaders: string;
@@ -341,6 +350,11 @@ export type ServiceSettings = {
     SessionLengthSSOInHours: number;
     SessionCacheInMinutes: number;
     SessionIdleTimeoutInMinutes: number;
+    MaximumWebSessionsPerUser: number;
+    MaximumDesktopSessionsPerUser: number;
+    MaximumMobileSessionsPerUser: number;
+    EnableLoginAlerts: boolean;
+    GeoIPDatabaseFile: string;
     WebsocketSecurePort: number;
     WebsocketPort: number;
     WebserverMode: string;
@@ -395,6 +409,9 @@ export type ServiceSettings = {
     RefreshPostStatsRunTime: string;
     MaximumPayloadSizeBytes: number;
     MaximumURLLength: number;
+    EnableWebSocketEventLog: boolean;
+    WebSocketEventLogRetentionMinutes: number;
+    EnableNotificationRules: boolean;
 };
 
 export type TeamSettings = {
@@ -474,6 +491,10 @@ export type ExperimentalAuditSettings = {
     FileCompress: boolean;
     FileMaxQueueSize: number;
     AdvancedLoggingJSON: Record<string, any>;
+    EnableHashChain: boolean;
+    CheckpointInterval: number;
+    CheckpointSigningKeyFile: string;
+    EnableDatabaseStorage: boolean;
 };
 
 export type NotificationLogSettings = {
@@ -495,6 +516,13 @@ export type PasswordSettings = {
     Uppercase: boolean;
     Symbol: boolean;
     EnableForgotLink: boolean;
+    HashAlgorithm: string;
+    Argon2idMemoryKiB: number;
+    Argon2idIterations: number;
+    Argon2idParallelism: number;
+    BreachedPasswordDirectory: string;
+    HistoryCount: number;
+    ExpiryDays: number;
 };
 
 export type WranglerSettings = {
@@ -533,6 +561,20 @@ export type FileSettings = {
     AmazonS3Trace: boolean;
     AmazonS3RequestTimeoutMilliseconds: number;
     AmazonS3UploadPartSizeBytes: number;
+    AzureStorageAccountName: string;
+    AzureStorageAccountKey: string;
+    AzureStorageContainer: string;
+    AzureStoragePathPrefix: string;
+    AzureStorageEndpoint: string;
+    AzureStorageRequestTimeoutMilliseconds: number;
+    GoogleCloudStorageBucket: string;
+    GoogleCloudStoragePathPrefix: string;
+    GoogleCloudStorageCredentialsFile: string;
+    GoogleCloudStorageEndpoint: string;
+    GoogleCloudStorageRequestTimeoutMilliseconds: number;
+    EnableEncryption: boolean;
+    EncryptionMasterKey: string;
+    EncryptionKeyFile: string;
     DedicatedExportStore: boolean;
     ExportDriverName: string;
     ExportDirectory: string;
@@ -549,6 +591,21 @@ export type FileSettings = {
     ExportAmazonS3RequestTimeoutMilliseconds: number;
     ExportAmazonS3PresignExpiresSeconds: number;
     ExportAmazonS3UploadPartSizeBytes: number;
+    EnableColdStorage: boolean;
+    ColdStorageAfterDays: number;
+    ColdDriverName: string;
+    ColdDirectory: string;
+    ColdAmazonS3AccessKeyId: string;
+    ColdAmazonS3SecretAccessKey: string;
+    ColdAmazonS3Bucket: string;
+    ColdAmazonS3PathPrefix: string;
+    ColdAmazonS3Region: string;
+    ColdAmazonS3Endpoint: string;
+    ColdAmazonS3SSL: boolean;
+    ColdAmazonS3SignV2: boolean;
+    ColdAmazonS3SSE: boolean;
+    ColdAmazonS3Trace: boolean;
+    ColdAmazonS3RequestTimeoutMilliseconds: number;
 };
 
 export type EmailSettings = {
@@ -576,6 +633,13 @@ export type EmailSettings = {
     PushNotificationContents: string;
     PushNotificationBuffer: number;
     EnableEmailBatching: boolean;
+    EnableEmailDigests: boolean;
+    EnableReplyByEmail: boolean;
+    ReplyByEmailAddress: string;
+    InboundSMTPListenAddress: string;
+    EnablePostByEmail: boolean;
+    PostByEmailAddress: string;
+    InboundEmailMaxMessageSize: number;
     EmailBatchingBufferSize: number;
     EmailBatchingInterval: number;
     EnablePreviewModeBanner: boolean;
@@ -659,6 +723,27 @@ export type Office365Settings = {
     DirectoryId: string;
 };
 
+export type OpenIdProviderSettings = {
+    Id: string;
+    Enable: boolean;
+    ButtonText: string;
+    ButtonColor: string;
+    ClientId: string;
+    ClientSecret: string;
+    Scope: string;
+    DiscoveryEndpoint: string;
+    UsernameClaim: string;
+    EmailClaim: string;
+    FirstNameClaim: string;
+    LastNameClaim: string;
+    GroupsClaim: string;
+    AssumeEmailVerified: boolean;
+};
+
+export type OpenIdConnectSettings = {
+    Providers: OpenIdProviderSettings[];
+};
+
 export type LdapSettings = {
     Enable: boolean;
     EnableSync: boolean;
@@ -935,6 +1020,15 @@ export type ExportSettings = {
     RetentionDays: number;
 };
 
+export type ScimSettings = {
+    Enable: boolean;
+    AuthService: string;
+};
+
+export type AccessControlSettings = {
+    EnableAttributeBasedAccessControl: boolean;
+};
+
 export type AdminConfig = {
     ServiceSettings: ServiceSettings;
     TeamSettings: TeamSettings;
@@ -955,6 +1049,7 @@ export type AdminConfig = {
     GoogleSettings: SSOSettings;
     Office365Settings: Office365Settings;
     OpenIdSettings: SSOSettings;
+    OpenIdConnectSettings: OpenIdConnectSettings;
     LdapSettings: LdapSettings;
     ComplianceSettings: ComplianceSettings;
     LocalizationSettings: LocalizationSettings;
@@ -979,6 +1074,8 @@ export type AdminConfig = {
     ImportSettings: ImportSettings;
     ExportSettings: ExportSettings;
     WranglerSettings: WranglerSettings;
+    ScimSettings: ScimSettings;
+    AccessControlSettings: AccessControlSettings;
 };
 
 export type ReplicaLagSetting = {
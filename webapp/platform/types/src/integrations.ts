@@ -107,6 +107,8 @@ export type OAuthApp = {
     'callback_urls': string[];
     'homepage': string;
     'is_trusted': boolean;
+    'is_public'?: boolean;
+    'bot_user_id'?: string;
 };
 
 export type OutgoingOAuthConnection = {
@@ -126,6 +126,15 @@ export type UserAccessToken = {
     user_id: string;
     description: string;
     is_active: boolean;
+    scope?: UserAccessTokenScope;
+    expires_at?: number;
+};
+
+export type UserAccessTokenScope = {
+    permissions?: string[];
+    team_ids?: string[];
+    channel_ids?: string[];
+    allowed_ips?: string[];
 };
 
 export type UsersStats = {